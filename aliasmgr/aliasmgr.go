@@ -0,0 +1,362 @@
+package aliasmgr
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// aliasBucket stores all the aliases we've handed out for our channels
+	// as keys, mapped to the base ShortChannelID of the channel they
+	// belong to.
+	aliasBucket = []byte("alias-bucket")
+
+	// peerAliasBucket stores the alias our peer sent us for a channel in
+	// its funding_locked message, keyed by the ChannelID of the channel.
+	peerAliasBucket = []byte("peer-alias-bucket")
+
+	// aliasAllocBucket stores the last alias that was allocated by
+	// RequestAlias so that we never hand out the same alias twice.
+	aliasAllocBucket = []byte("alias-alloc-bucket")
+
+	// lastAliasKey is the key under which the last allocated alias is
+	// stored within the aliasAllocBucket.
+	lastAliasKey = []byte("last-alias-key")
+
+	// byteOrder is the byte order used to serialize the short channel IDs.
+	byteOrder = binary.BigEndian
+
+	// ErrAliasNotFound is returned when no base ShortChannelID is known for
+	// an alias.
+	ErrAliasNotFound = errors.New("alias not found")
+
+	// ErrNoPeerAlias is returned when the peer hasn't sent us an alias for
+	// the channel.
+	ErrNoPeerAlias = errors.New("no peer alias found")
+
+	// ErrAliasRangeExhausted is returned when all aliases of the alias
+	// range have been handed out.
+	ErrAliasRangeExhausted = errors.New("alias range exhausted")
+)
+
+const (
+	// startingBlockHeight is the block height of the first alias that we
+	// hand out. Aliases are taken from a block height range that is far in
+	// the future, so they can't collide with confirmed ShortChannelIDs.
+	startingBlockHeight = 16_000_000
+
+	// endBlockHeight is the first block height that is no longer part of
+	// the alias range.
+	endBlockHeight = 16_250_000
+)
+
+// StartingAlias is the first alias ShortChannelID that is handed out.
+var StartingAlias = lnwire.ShortChannelID{
+	BlockHeight: startingBlockHeight,
+	TxIndex:     0,
+	TxPosition:  0,
+}
+
+// IsAlias returns true if the passed ShortChannelID lies within the alias
+// range.
+func IsAlias(scid lnwire.ShortChannelID) bool {
+	return scid.BlockHeight >= startingBlockHeight &&
+		scid.BlockHeight < endBlockHeight
+}
+
+// Manager is a persistent store of the alias ShortChannelIDs of our channels.
+// It keeps track of the aliases we've handed out for a channel, which map to
+// the channel's base ShortChannelID, as well as of the alias our peer wants us
+// to use when referring to the channel.
+//
+// The base ShortChannelID of a channel is the identifier the switch and the
+// forwarding packages use for the channel. For zero-conf and
+// option_scid_alias channels, the base is itself an alias, for all other
+// channels it is the confirmed ShortChannelID.
+type Manager struct {
+	backend kvdb.Backend
+
+	// baseToSet maps a base ShortChannelID to all of its aliases.
+	baseToSet map[lnwire.ShortChannelID][]lnwire.ShortChannelID
+
+	// aliasToBase maps an alias to its base ShortChannelID.
+	aliasToBase map[lnwire.ShortChannelID]lnwire.ShortChannelID
+
+	// peerAlias maps a ChannelID to the alias our peer sent us for it.
+	peerAlias map[lnwire.ChannelID]lnwire.ShortChannelID
+
+	sync.RWMutex
+}
+
+// NewManager creates a new Manager backed by the passed database and loads
+// all known aliases into memory.
+func NewManager(db kvdb.Backend) (*Manager, error) {
+	m := &Manager{
+		backend:     db,
+		baseToSet:   make(map[lnwire.ShortChannelID][]lnwire.ShortChannelID),
+		aliasToBase: make(map[lnwire.ShortChannelID]lnwire.ShortChannelID),
+		peerAlias:   make(map[lnwire.ChannelID]lnwire.ShortChannelID),
+	}
+
+	if err := m.populateMaps(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// populateMaps creates the top level buckets if they don't exist yet and reads
+// all stored aliases into memory.
+func (m *Manager) populateMaps() error {
+	return kvdb.Update(m.backend, func(tx kvdb.RwTx) error {
+		aliases, err := tx.CreateTopLevelBucket(aliasBucket)
+		if err != nil {
+			return err
+		}
+
+		peerAliases, err := tx.CreateTopLevelBucket(peerAliasBucket)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.CreateTopLevelBucket(aliasAllocBucket)
+		if err != nil {
+			return err
+		}
+
+		err = aliases.ForEach(func(k, v []byte) error {
+			alias := lnwire.NewShortChanIDFromInt(byteOrder.Uint64(k))
+			base := lnwire.NewShortChanIDFromInt(byteOrder.Uint64(v))
+
+			m.baseToSet[base] = append(m.baseToSet[base], alias)
+			m.aliasToBase[alias] = base
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return peerAliases.ForEach(func(k, v []byte) error {
+			var chanID lnwire.ChannelID
+			copy(chanID[:], k)
+
+			m.peerAlias[chanID] = lnwire.NewShortChanIDFromInt(
+				byteOrder.Uint64(v),
+			)
+
+			return nil
+		})
+	}, func() {
+		m.baseToSet = make(
+			map[lnwire.ShortChannelID][]lnwire.ShortChannelID,
+		)
+		m.aliasToBase = make(
+			map[lnwire.ShortChannelID]lnwire.ShortChannelID,
+		)
+		m.peerAlias = make(map[lnwire.ChannelID]lnwire.ShortChannelID)
+	})
+}
+
+// AddLocalAlias persists an alias for the channel identified by the passed
+// base ShortChannelID. Adding an alias that is already known for the same
+// base is a no-op.
+func (m *Manager) AddLocalAlias(alias, baseScid lnwire.ShortChannelID) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if base, ok := m.aliasToBase[alias]; ok {
+		if base != baseScid {
+			return fmt.Errorf("alias %v already in use by %v",
+				alias, base)
+		}
+
+		return nil
+	}
+
+	err := kvdb.Update(m.backend, func(tx kvdb.RwTx) error {
+		aliases, err := tx.CreateTopLevelBucket(aliasBucket)
+		if err != nil {
+			return err
+		}
+
+		var aliasBytes, baseBytes [8]byte
+		byteOrder.PutUint64(aliasBytes[:], alias.ToUint64())
+		byteOrder.PutUint64(baseBytes[:], baseScid.ToUint64())
+
+		return aliases.Put(aliasBytes[:], baseBytes[:])
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	m.baseToSet[baseScid] = append(m.baseToSet[baseScid], alias)
+	m.aliasToBase[alias] = baseScid
+
+	return nil
+}
+
+// GetAliases returns all the aliases of the channel identified by the passed
+// base ShortChannelID.
+func (m *Manager) GetAliases(
+	baseScid lnwire.ShortChannelID) []lnwire.ShortChannelID {
+
+	m.RLock()
+	defer m.RUnlock()
+
+	aliases := m.baseToSet[baseScid]
+
+	aliasCopy := make([]lnwire.ShortChannelID, len(aliases))
+	copy(aliasCopy, aliases)
+
+	return aliasCopy
+}
+
+// FindBaseSCID returns the base ShortChannelID of the passed alias. If the
+// alias is unknown, ErrAliasNotFound is returned.
+func (m *Manager) FindBaseSCID(
+	alias lnwire.ShortChannelID) (lnwire.ShortChannelID, error) {
+
+	m.RLock()
+	defer m.RUnlock()
+
+	base, ok := m.aliasToBase[alias]
+	if !ok {
+		return lnwire.ShortChannelID{}, ErrAliasNotFound
+	}
+
+	return base, nil
+}
+
+// DeleteLocalAlias removes all aliases of the channel identified by the passed
+// base ShortChannelID, as well as the alias our peer sent us for it. This
+// should be called once the channel is closed.
+func (m *Manager) DeleteLocalAlias(chanID lnwire.ChannelID,
+	baseScid lnwire.ShortChannelID) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	aliases := m.baseToSet[baseScid]
+
+	err := kvdb.Update(m.backend, func(tx kvdb.RwTx) error {
+		aliasStore, err := tx.CreateTopLevelBucket(aliasBucket)
+		if err != nil {
+			return err
+		}
+
+		for _, alias := range aliases {
+			var aliasBytes [8]byte
+			byteOrder.PutUint64(aliasBytes[:], alias.ToUint64())
+
+			if err := aliasStore.Delete(aliasBytes[:]); err != nil {
+				return err
+			}
+		}
+
+		peerAliases, err := tx.CreateTopLevelBucket(peerAliasBucket)
+		if err != nil {
+			return err
+		}
+
+		return peerAliases.Delete(chanID[:])
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	for _, alias := range aliases {
+		delete(m.aliasToBase, alias)
+	}
+	delete(m.baseToSet, baseScid)
+	delete(m.peerAlias, chanID)
+
+	return nil
+}
+
+// PutPeerAlias persists the alias our peer sent us for the passed channel.
+func (m *Manager) PutPeerAlias(chanID lnwire.ChannelID,
+	alias lnwire.ShortChannelID) error {
+
+	m.Lock()
+	defer m.Unlock()
+
+	err := kvdb.Update(m.backend, func(tx kvdb.RwTx) error {
+		peerAliases, err := tx.CreateTopLevelBucket(peerAliasBucket)
+		if err != nil {
+			return err
+		}
+
+		var aliasBytes [8]byte
+		byteOrder.PutUint64(aliasBytes[:], alias.ToUint64())
+
+		return peerAliases.Put(chanID[:], aliasBytes[:])
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	m.peerAlias[chanID] = alias
+
+	return nil
+}
+
+// GetPeerAlias returns the alias our peer sent us for the passed channel. If
+// the peer hasn't sent an alias, ErrNoPeerAlias is returned.
+func (m *Manager) GetPeerAlias(
+	chanID lnwire.ChannelID) (lnwire.ShortChannelID, error) {
+
+	m.RLock()
+	defer m.RUnlock()
+
+	alias, ok := m.peerAlias[chanID]
+	if !ok {
+		return lnwire.ShortChannelID{}, ErrNoPeerAlias
+	}
+
+	return alias, nil
+}
+
+// RequestAlias allocates a new alias that hasn't been handed out before. The
+// alias isn't associated with any channel until AddLocalAlias is called.
+func (m *Manager) RequestAlias() (lnwire.ShortChannelID, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	var nextAlias lnwire.ShortChannelID
+	err := kvdb.Update(m.backend, func(tx kvdb.RwTx) error {
+		bucket, err := tx.CreateTopLevelBucket(aliasAllocBucket)
+		if err != nil {
+			return err
+		}
+
+		lastBytes := bucket.Get(lastAliasKey)
+		if lastBytes == nil {
+			nextAlias = StartingAlias
+		} else {
+			lastAlias := byteOrder.Uint64(lastBytes)
+			nextAlias = lnwire.NewShortChanIDFromInt(lastAlias + 1)
+		}
+
+		if !IsAlias(nextAlias) {
+			return ErrAliasRangeExhausted
+		}
+
+		var aliasBytes [8]byte
+		byteOrder.PutUint64(aliasBytes[:], nextAlias.ToUint64())
+
+		return bucket.Put(lastAliasKey, aliasBytes[:])
+	}, func() {
+		nextAlias = lnwire.ShortChannelID{}
+	})
+	if err != nil {
+		return lnwire.ShortChannelID{}, err
+	}
+
+	return nextAlias, nil
+}
@@ -0,0 +1,138 @@
+package aliasmgr
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// makeTestBackend creates a new test database backend.
+func makeTestBackend(t *testing.T) (kvdb.Backend, func()) {
+	t.Helper()
+
+	path, err := ioutil.TempDir("", "aliasmgr")
+	require.NoError(t, err)
+
+	backend, cleanup, err := kvdb.GetTestBackend(path, "aliasmgr")
+	require.NoError(t, err)
+
+	return backend, func() {
+		cleanup()
+		os.RemoveAll(path)
+	}
+}
+
+// TestAliasStorePeerAlias tests that the aliases our peer sends us are
+// persisted across restarts.
+func TestAliasStorePeerAlias(t *testing.T) {
+	t.Parallel()
+
+	backend, cleanup := makeTestBackend(t)
+	defer cleanup()
+
+	aliasStore, err := NewManager(backend)
+	require.NoError(t, err)
+
+	var chanID1 [32]byte
+	chanID1[0] = 1
+	chanID2 := chanID1
+	chanID2[0] = 2
+
+	_, err = aliasStore.GetPeerAlias(chanID1)
+	require.ErrorIs(t, err, ErrNoPeerAlias)
+
+	peerAlias := lnwire.NewShortChanIDFromInt(16_000_123)
+	require.NoError(t, aliasStore.PutPeerAlias(chanID1, peerAlias))
+
+	// The alias should also be found after restarting the store.
+	aliasStore, err = NewManager(backend)
+	require.NoError(t, err)
+
+	storedAlias, err := aliasStore.GetPeerAlias(chanID1)
+	require.NoError(t, err)
+	require.Equal(t, peerAlias, storedAlias)
+
+	_, err = aliasStore.GetPeerAlias(chanID2)
+	require.ErrorIs(t, err, ErrNoPeerAlias)
+}
+
+// TestAliasStoreLocalAlias tests that the mapping between our aliases and the
+// base short channel ID of a channel is kept correctly.
+func TestAliasStoreLocalAlias(t *testing.T) {
+	t.Parallel()
+
+	backend, cleanup := makeTestBackend(t)
+	defer cleanup()
+
+	aliasStore, err := NewManager(backend)
+	require.NoError(t, err)
+
+	// Request two aliases, the first one being used as the base short
+	// channel ID of a zero-conf channel.
+	alias1, err := aliasStore.RequestAlias()
+	require.NoError(t, err)
+	require.Equal(t, StartingAlias, alias1)
+	require.True(t, IsAlias(alias1))
+
+	alias2, err := aliasStore.RequestAlias()
+	require.NoError(t, err)
+	require.NotEqual(t, alias1, alias2)
+	require.True(t, IsAlias(alias2))
+
+	require.NoError(t, aliasStore.AddLocalAlias(alias1, alias1))
+	require.NoError(t, aliasStore.AddLocalAlias(alias2, alias1))
+
+	// Adding the same alias twice is fine, but not for another base.
+	require.NoError(t, aliasStore.AddLocalAlias(alias2, alias1))
+	require.Error(t, aliasStore.AddLocalAlias(alias2, alias2))
+
+	// The confirmed short channel ID is added as another alias.
+	confirmedScid := lnwire.ShortChannelID{
+		BlockHeight: 700_000,
+		TxIndex:     12,
+		TxPosition:  1,
+	}
+	require.False(t, IsAlias(confirmedScid))
+	require.NoError(t, aliasStore.AddLocalAlias(confirmedScid, alias1))
+
+	// After a restart, all aliases should be found and no alias should be
+	// handed out twice.
+	aliasStore, err = NewManager(backend)
+	require.NoError(t, err)
+
+	require.ElementsMatch(
+		t, []lnwire.ShortChannelID{alias1, alias2, confirmedScid},
+		aliasStore.GetAliases(alias1),
+	)
+
+	for _, alias := range []lnwire.ShortChannelID{
+		alias1, alias2, confirmedScid,
+	} {
+		base, err := aliasStore.FindBaseSCID(alias)
+		require.NoError(t, err)
+		require.Equal(t, alias1, base)
+	}
+
+	alias3, err := aliasStore.RequestAlias()
+	require.NoError(t, err)
+	require.NotEqual(t, alias1, alias3)
+	require.NotEqual(t, alias2, alias3)
+
+	_, err = aliasStore.FindBaseSCID(alias3)
+	require.ErrorIs(t, err, ErrAliasNotFound)
+
+	// Finally, delete the channel's aliases.
+	var chanID [32]byte
+	require.NoError(t, aliasStore.PutPeerAlias(chanID, alias3))
+	require.NoError(t, aliasStore.DeleteLocalAlias(chanID, alias1))
+
+	require.Empty(t, aliasStore.GetAliases(alias1))
+	_, err = aliasStore.FindBaseSCID(alias2)
+	require.ErrorIs(t, err, ErrAliasNotFound)
+	_, err = aliasStore.GetPeerAlias(chanID)
+	require.ErrorIs(t, err, ErrNoPeerAlias)
+}
@@ -6,9 +6,9 @@ import (
 	"sync"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightninglabs/neutrino/cache"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
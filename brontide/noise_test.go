@@ -10,7 +10,7 @@ import (
 	"testing"
 	"testing/iotest"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/tor"
@@ -23,7 +23,7 @@ type maybeNetConn struct {
 
 func makeListener() (*Listener, *lnwire.NetAddress, error) {
 	// First, generate the long-term private keys for the brontide listener.
-	localPriv, err := btcec.NewPrivateKey(btcec.S256())
+	localPriv, err := btcec.NewPrivateKey()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -56,7 +56,7 @@ func establishTestConnection() (net.Conn, net.Conn, func(), error) {
 
 	// Nos, generate the long-term private keys remote end of the connection
 	// within our test.
-	remotePriv, err := btcec.NewPrivateKey(btcec.S256())
+	remotePriv, err := btcec.NewPrivateKey()
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -193,7 +193,7 @@ func TestConcurrentHandshakes(t *testing.T) {
 
 	// Now, construct a new private key and use the brontide dialer to
 	// connect to the listener.
-	remotePriv, err := btcec.NewPrivateKey(btcec.S256())
+	remotePriv, err := btcec.NewPrivateKey()
 	if err != nil {
 		t.Fatalf("unable to generate private key: %v", err)
 	}
@@ -325,9 +325,7 @@ func TestBolt0008TestVectors(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unable to decode hex: %v", err)
 	}
-	initiatorPriv, _ := btcec.PrivKeyFromBytes(
-		btcec.S256(), initiatorKeyBytes,
-	)
+	initiatorPriv, _ := btcec.PrivKeyFromBytes(initiatorKeyBytes)
 	initiatorKeyECDH := &keychain.PrivKeyECDH{PrivKey: initiatorPriv}
 
 	// We'll then do the same for the responder.
@@ -336,9 +334,7 @@ func TestBolt0008TestVectors(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unable to decode hex: %v", err)
 	}
-	responderPriv, responderPub := btcec.PrivKeyFromBytes(
-		btcec.S256(), responderKeyBytes,
-	)
+	responderPriv, responderPub := btcec.PrivKeyFromBytes(responderKeyBytes)
 	responderKeyECDH := &keychain.PrivKeyECDH{PrivKey: responderPriv}
 
 	// With the initiator's key data parsed, we'll now define a custom
@@ -353,7 +349,7 @@ func TestBolt0008TestVectors(t *testing.T) {
 			return nil, err
 		}
 
-		priv, _ := btcec.PrivKeyFromBytes(btcec.S256(), eBytes)
+		priv, _ := btcec.PrivKeyFromBytes(eBytes)
 		return priv, nil
 	})
 	responderEphemeral := EphemeralGenerator(func() (*btcec.PrivateKey, error) {
@@ -364,7 +360,7 @@ func TestBolt0008TestVectors(t *testing.T) {
 			return nil, err
 		}
 
-		priv, _ := btcec.PrivKeyFromBytes(btcec.S256(), eBytes)
+		priv, _ := btcec.PrivKeyFromBytes(eBytes)
 		return priv, nil
 	})
 
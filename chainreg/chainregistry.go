@@ -13,9 +13,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/rpcclient"
-	"github.com/btcsuite/btcutil"
 	"github.com/btcsuite/btcwallet/chain"
 	"github.com/btcsuite/btcwallet/wallet"
 	"github.com/lightninglabs/neutrino"
@@ -33,6 +33,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwallet/rpcwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing/chainview"
 )
@@ -109,6 +110,11 @@ type Config struct {
 	// optional.
 	FeeURL string
 
+	// FeeEstimator holds the configuration of the composite fee estimator
+	// that combines the estimates of the chain backend and all fee URLs,
+	// if it is active.
+	FeeEstimator *lncfg.FeeEstimator
+
 	// Dialer is a function closure that will be used to establish outbound
 	// TCP connections to Bitcoin peers in the event of a pruned block being
 	// requested.
@@ -120,6 +126,11 @@ type Config struct {
 	// CoinSelectionStrategy is the strategy that is used for selecting
 	// coins when funding a transaction.
 	CoinSelectionStrategy wallet.CoinSelectionStrategy
+
+	// RemoteSigner holds the configuration of the remote signer that all
+	// private key operations of the node's key ring are delegated to, if
+	// it is enabled.
+	RemoteSigner *lncfg.RemoteSigner
 }
 
 const (
@@ -294,6 +305,8 @@ func NewChainControl(cfg *Config, blockCache *blockcache.BlockCache) (
 		Wallet:                cfg.Wallet,
 		LoaderOptions:         cfg.LoaderOptions,
 		CoinSelectionStrategy: cfg.CoinSelectionStrategy,
+		WatchOnly: cfg.RemoteSigner != nil &&
+			cfg.RemoteSigner.Enable,
 	}
 
 	var err error
@@ -407,13 +420,15 @@ func NewChainControl(cfg *Config, blockCache *blockcache.BlockCache) (
 		// Establish the connection to bitcoind and create the clients
 		// required for our relevant subsystems.
 		bitcoindConn, err := chain.NewBitcoindConn(&chain.BitcoindConfig{
-			ChainParams:        cfg.ActiveNetParams.Params,
-			Host:               bitcoindHost,
-			User:               bitcoindMode.RPCUser,
-			Pass:               bitcoindMode.RPCPass,
-			ZMQBlockHost:       bitcoindMode.ZMQPubRawBlock,
-			ZMQTxHost:          bitcoindMode.ZMQPubRawTx,
-			ZMQReadDeadline:    5 * time.Second,
+			ChainParams: cfg.ActiveNetParams.Params,
+			Host:        bitcoindHost,
+			User:        bitcoindMode.RPCUser,
+			Pass:        bitcoindMode.RPCPass,
+			ZMQConfig: &chain.ZMQConfig{
+				ZMQBlockHost:    bitcoindMode.ZMQPubRawBlock,
+				ZMQTxHost:       bitcoindMode.ZMQPubRawTx,
+				ZMQReadDeadline: 5 * time.Second,
+			},
 			Dialer:             cfg.Dialer,
 			PrunedModeMaxPeers: bitcoindMode.PrunedNodeMaxPeers,
 		})
@@ -616,22 +631,33 @@ func NewChainControl(cfg *Config, blockCache *blockcache.BlockCache) (
 			homeChainConfig.Node)
 	}
 
+	// Do not cache fees on regtest to make it easier to execute manual or
+	// automated test cases.
+	cacheFees := !cfg.Bitcoin.RegTest
+
 	switch {
 	// If the fee URL isn't set, and the user is running mainnet, then
 	// we'll return an error to instruct them to set a proper fee
 	// estimator.
 	case cfg.FeeURL == "" && cfg.Bitcoin.MainNet &&
-		homeChainConfig.Node == "neutrino":
+		homeChainConfig.Node == "neutrino" &&
+		!(cfg.FeeEstimator.Active && len(cfg.FeeEstimator.FeeURLs) > 0):
 
 		return nil, nil, fmt.Errorf("--feeurl parameter required when " +
 			"running neutrino on mainnet")
 
+	// If the composite fee estimator is active, we'll combine the
+	// estimates of the chain backend and all fee URLs.
+	case cfg.FeeEstimator.Active:
+		cc.FeeEstimator, err = newCompositeEstimator(
+			cfg, cc.FeeEstimator, homeChainConfig.Node, cacheFees,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
 	// Override default fee estimator if an external service is specified.
 	case cfg.FeeURL != "":
-		// Do not cache fees on regtest to make it easier to execute
-		// manual or automated test cases.
-		cacheFees := !cfg.Bitcoin.RegTest
-
 		log.Infof("Using external fee estimator %v: cached=%v",
 			cfg.FeeURL, cacheFees)
 
@@ -643,7 +669,15 @@ func NewChainControl(cfg *Config, blockCache *blockcache.BlockCache) (
 		)
 	}
 
+	var rpcKeyRing *rpcwallet.RPCKeyRing
 	ccCleanup := func() {
+		if rpcKeyRing != nil {
+			if err := rpcKeyRing.Close(); err != nil {
+				log.Errorf("Failed to stop remote signer "+
+					"connection: %v", err)
+			}
+		}
+
 		if cc.Wallet != nil {
 			if err := cc.Wallet.Shutdown(); err != nil {
 				log.Errorf("Failed to shutdown wallet: %v", err)
@@ -679,9 +713,32 @@ func NewChainControl(cfg *Config, blockCache *blockcache.BlockCache) (
 		channelConstraints = DefaultLtcChannelConstraints
 	}
 
-	keyRing := keychain.NewBtcWalletKeyRing(
+	var keyRing keychain.SecretKeyRing = keychain.NewBtcWalletKeyRing(
 		wc.InternalWallet(), cfg.ActiveNetParams.CoinType,
 	)
+
+	// If remote signing is enabled, the local wallet is a watch-only
+	// wallet that only holds the account xpubs of the remote signer. Keys
+	// are still derived locally but all signing requests, including
+	// those for inputs owned by the on-chain wallet, are forwarded to the
+	// remote signer.
+	var walletController lnwallet.WalletController = wc
+	if cfg.RemoteSigner != nil && cfg.RemoteSigner.Enable {
+		rpcKeyRing, err = rpcwallet.NewRPCKeyRing(
+			keyRing, wc, cfg.RemoteSigner,
+		)
+		if err != nil {
+			fmt.Printf("unable to create RPC remote signing "+
+				"wallet: %v\n", err)
+			return nil, ccCleanup, err
+		}
+
+		cc.MsgSigner = rpcKeyRing
+		cc.Signer = rpcKeyRing
+		cc.Wc = rpcKeyRing
+		keyRing = rpcKeyRing
+		walletController = rpcKeyRing
+	}
 	cc.KeyRing = keyRing
 
 	// Create, and start the lnwallet, which handles the core payment
@@ -689,7 +746,7 @@ func NewChainControl(cfg *Config, blockCache *blockcache.BlockCache) (
 	walletCfg := lnwallet.Config{
 		Database:           cfg.ChanStateDB,
 		Notifier:           cc.ChainNotifier,
-		WalletController:   wc,
+		WalletController:   walletController,
 		Signer:             cc.Signer,
 		FeeEstimator:       cc.FeeEstimator,
 		SecretKeyRing:      keyRing,
@@ -713,6 +770,60 @@ func NewChainControl(cfg *Config, blockCache *blockcache.BlockCache) (
 	return cc, ccCleanup, nil
 }
 
+// newCompositeEstimator creates a fee estimator that combines the estimates of
+// the passed chain backend estimator and all configured fee URLs. The static
+// estimator used on networks without live fee estimates isn't a source of its
+// own.
+func newCompositeEstimator(cfg *Config, backend chainfee.Estimator,
+	node string, cacheFees bool) (chainfee.Estimator, error) {
+
+	policy, err := cfg.FeeEstimator.AggregationPolicy()
+	if err != nil {
+		return nil, err
+	}
+	bounds, err := cfg.FeeEstimator.Bounds()
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []chainfee.FeeSource
+	if _, ok := backend.(*chainfee.StaticEstimator); !ok {
+		sources = append(sources, chainfee.FeeSource{
+			Name:      node,
+			Estimator: backend,
+		})
+	}
+
+	feeURLs := cfg.FeeEstimator.FeeURLs
+	if cfg.FeeURL != "" {
+		feeURLs = append([]string{cfg.FeeURL}, feeURLs...)
+	}
+	for _, feeURL := range feeURLs {
+		sources = append(sources, chainfee.FeeSource{
+			Name: feeURL,
+			Estimator: chainfee.NewWebAPIEstimator(
+				chainfee.SparseConfFeeSource{
+					URL: feeURL,
+				},
+				!cacheFees,
+			),
+		})
+	}
+
+	log.Infof("Using composite fee estimator with %v sources and %v "+
+		"policy: cached=%v", len(sources), policy, cacheFees)
+
+	return chainfee.NewCompositeEstimator(chainfee.CompositeEstimatorConfig{
+		Sources: sources,
+		Policy:  policy,
+		Bounds:  bounds,
+		Fallback: chainfee.NewStaticEstimator(
+			cfg.FeeEstimator.FallbackFeePerKW(),
+			backend.RelayFeePerKW(),
+		),
+	})
+}
+
 // getBitcoindHealthCheckCmd queries bitcoind for its version to decide which
 // api we should use for our health check. We prefer to use the uptime
 // command, because it has no locking and is an inexpensive call, which was
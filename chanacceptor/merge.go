@@ -4,7 +4,7 @@ import (
 	"bytes"
 	"fmt"
 
-	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
@@ -148,5 +148,9 @@ func mergeResponse(current, new ChannelAcceptResponse) (ChannelAcceptResponse,
 		return current, err
 	}
 
+	// A zero-conf channel is requested if any of the acceptors asks for
+	// it, the min depth check happens once the responses are merged.
+	current.ZeroConf = current.ZeroConf || new.ZeroConf
+
 	return current, nil
 }
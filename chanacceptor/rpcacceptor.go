@@ -7,8 +7,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet/chancloser"
@@ -38,6 +38,11 @@ var (
 	errAcceptWithError = errors.New("channel acceptor response accepts " +
 		"channel, but also includes custom error")
 
+	// errZeroConfMinDepth is returned when a zero-conf channel is
+	// accepted with a non-zero min accept depth.
+	errZeroConfMinDepth = errors.New("zero-conf channels require a min " +
+		"accept depth of zero")
+
 	// errMaxHtlcTooHigh is returned if our htlc count exceeds the number
 	// hard-set by BOLT 2.
 	errMaxHtlcTooHigh = fmt.Errorf("htlc limit exceeds spec limit of: %v",
@@ -108,6 +113,7 @@ func (r *RPCAcceptor) Accept(req *ChannelAcceptRequest) *ChannelAcceptResponse {
 	// reject the channel.
 	rejectChannel := NewChannelAcceptResponse(
 		false, errChannelRejected, nil, 0, 0, 0, 0, 0, 0,
+		false,
 	)
 
 	// Send the request to the newRequests channel.
@@ -216,6 +222,7 @@ func (r *RPCAcceptor) receiveResponses(errChan chan error,
 			MaxHtlcCount:    resp.MaxHtlcCount,
 			MinHtlcIn:       resp.MinHtlcIn,
 			MinAcceptDepth:  resp.MinAcceptDepth,
+			ZeroConf:        resp.ZeroConf,
 		}
 
 		// We have received a decision for one of our channel
@@ -275,6 +282,21 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 				ChannelFlags:     uint32(req.OpenChanMsg.ChannelFlags),
 			}
 
+			// If the initiator explicitly negotiated a channel type,
+			// let the acceptor know whether it wants a zero-conf or
+			// option-scid-alias channel.
+			if req.OpenChanMsg.ChannelType != nil {
+				channelFeatures := lnwire.RawFeatureVector(
+					*req.OpenChanMsg.ChannelType,
+				)
+				chanAcceptReq.WantsZeroConf = channelFeatures.IsSet(
+					lnwire.ZeroConfRequired,
+				)
+				chanAcceptReq.WantsScidAlias = channelFeatures.IsSet(
+					lnwire.ScidAliasRequired,
+				)
+			}
+
 			if err := r.send(chanAcceptReq); err != nil {
 				return err
 			}
@@ -311,6 +333,7 @@ func (r *RPCAcceptor) sendAcceptRequests(errChan chan error,
 				btcutil.Amount(resp.ReserveSat),
 				lnwire.MilliSatoshi(resp.InFlightMaxMsat),
 				lnwire.MilliSatoshi(resp.MinHtlcIn),
+				resp.ZeroConf,
 			)
 
 			// Delete the channel from the acceptRequests map.
@@ -360,6 +383,14 @@ func (r *RPCAcceptor) validateAcceptorResponse(dustLimit btcutil.Amount,
 		return false, errChannelRejected, nil, errInsufficientReserve
 	}
 
+	// A zero-conf channel can't require any confirmations.
+	if req.ZeroConf && req.MinAcceptDepth != 0 {
+		log.Errorf("Zero-conf channel: %v requires min accept depth "+
+			"of zero, got: %v", channelStr, req.MinAcceptDepth)
+
+		return false, errChannelRejected, nil, errZeroConfMinDepth
+	}
+
 	// Attempt to parse the upfront shutdown address provided.
 	upfront, err := chancloser.ParseUpfrontShutdownAddress(
 		req.UpfrontShutdown, r.params,
@@ -0,0 +1,75 @@
+package chanbackup
+
+import (
+	"sync"
+
+	"github.com/lightningnetwork/lnd/keychain"
+)
+
+// PeerSwapper is a Swapper that, in addition to swapping out the multi backup
+// at its main location, hands every new backup to the channel peers that store
+// it on our behalf. The latest backup is kept in memory, so it can also be
+// sent to peers once they connect.
+type PeerSwapper struct {
+	// swapper is the Swapper that maintains the main backup location.
+	swapper Swapper
+
+	// distribute is called with every new backup once it has been swapped
+	// out at the main location.
+	distribute func(PackedMulti)
+
+	mu     sync.Mutex
+	latest PackedMulti
+}
+
+// A compile-time check to ensure PeerSwapper implements the Swapper
+// interface.
+var _ Swapper = (*PeerSwapper)(nil)
+
+// NewPeerSwapper creates a new PeerSwapper that maintains the main backup
+// location with the given swapper, and hands all new backups to the
+// distribute closure.
+func NewPeerSwapper(swapper Swapper,
+	distribute func(PackedMulti)) *PeerSwapper {
+
+	return &PeerSwapper{
+		swapper:    swapper,
+		distribute: distribute,
+	}
+}
+
+// UpdateAndSwap swaps out the backup at the main location with the new one,
+// and then hands it to the channel peers.
+//
+// NOTE: Part of the Swapper interface.
+func (p *PeerSwapper) UpdateAndSwap(newBackup PackedMulti) error {
+	if err := p.swapper.UpdateAndSwap(newBackup); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	p.latest = newBackup
+	p.mu.Unlock()
+
+	p.distribute(newBackup)
+
+	return nil
+}
+
+// ExtractMulti obtains and decodes the backup stored at the main location.
+//
+// NOTE: Part of the Swapper interface.
+func (p *PeerSwapper) ExtractMulti(keyChain keychain.KeyRing) (*Multi,
+	error) {
+
+	return p.swapper.ExtractMulti(keyChain)
+}
+
+// LatestBackup returns the latest backup that was swapped out, or nil if no
+// backup was created yet.
+func (p *PeerSwapper) LatestBackup() PackedMulti {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.latest
+}
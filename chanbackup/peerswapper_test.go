@@ -0,0 +1,41 @@
+package chanbackup
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestPeerSwapper tests that the PeerSwapper hands every backup that was
+// swapped out at the main location to the channel peers, and keeps the latest
+// one around.
+func TestPeerSwapper(t *testing.T) {
+	t.Parallel()
+
+	keyRing := &mockKeyRing{}
+	swapper := newMockSwapper(keyRing)
+
+	var distributed []PackedMulti
+	peerSwapper := NewPeerSwapper(swapper, func(backup PackedMulti) {
+		distributed = append(distributed, backup)
+	})
+	require.Nil(t, peerSwapper.LatestBackup())
+
+	var b bytes.Buffer
+	multi := Multi{StaticBackups: []Single{}}
+	require.NoError(t, multi.PackToWriter(&b, keyRing))
+	backup := PackedMulti(b.Bytes())
+
+	require.NoError(t, peerSwapper.UpdateAndSwap(backup))
+	require.Equal(t, backup, <-swapper.swaps)
+	require.Equal(t, []PackedMulti{backup}, distributed)
+	require.Equal(t, backup, peerSwapper.LatestBackup())
+
+	// If the backup can't be swapped out at the main location, it isn't
+	// handed to the peers either.
+	swapper.fail = true
+	require.Error(t, peerSwapper.UpdateAndSwap(PackedMulti{1}))
+	require.Len(t, distributed, 1)
+	require.Equal(t, backup, peerSwapper.LatestBackup())
+}
@@ -6,10 +6,10 @@ import (
 	"io"
 	"net"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -143,7 +143,7 @@ func NewSingle(channel *channeldb.OpenChannel,
 		// the backups plaintext don't carry any private information. When
 		// we go to recover, we'll present this in order to derive the
 		// private key.
-		_, shaChainPoint := btcec.PrivKeyFromBytes(btcec.S256(), b.Bytes())
+		_, shaChainPoint := btcec.PrivKeyFromBytes(b.Bytes())
 
 		shaChainRootDesc = keychain.KeyDescriptor{
 			PubKey: shaChainPoint,
@@ -341,13 +341,11 @@ func readRemoteKeyDesc(r io.Reader) (keychain.KeyDescriptor, error) {
 		return keychain.KeyDescriptor{}, err
 	}
 
-	keyDesc.PubKey, err = btcec.ParsePubKey(pub[:], btcec.S256())
+	keyDesc.PubKey, err = btcec.ParsePubKey(pub[:])
 	if err != nil {
 		return keychain.KeyDescriptor{}, err
 	}
 
-	keyDesc.PubKey.Curve = nil
-
 	return keyDesc, nil
 }
 
@@ -450,9 +448,7 @@ func (s *Single) Deserialize(r io.Reader) error {
 	// Since this field is optional, we'll check to see if the pubkey has
 	// been specified or not.
 	if !bytes.Equal(shaChainPub[:], zeroPub[:]) {
-		s.ShaChainRootDesc.PubKey, err = btcec.ParsePubKey(
-			shaChainPub[:], btcec.S256(),
-		)
+		s.ShaChainRootDesc.PubKey, err = btcec.ParsePubKey(shaChainPub[:])
 		if err != nil {
 			return err
 		}
@@ -12,10 +12,10 @@ import (
 	"strings"
 	"sync"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/kvdb"
@@ -129,6 +129,11 @@ var (
 	// bucket for a given channel.
 	frozenChanKey = []byte("frozen-chans")
 
+	// leaseTermsKey is the key where we store the terms of the lease of
+	// inbound liquidity a channel was funded with. This key is present
+	// only in the leaf bucket for a given channel.
+	leaseTermsKey = []byte("lease-terms")
+
 	// lastWasRevokeKey is a key that stores true when the last update we sent
 	// was a revocation and false when it was a commitment signature. This is
 	// nil in the case of new channels with no updates exchanged.
@@ -198,6 +203,20 @@ const (
 	// A tlv type definition used to serialize and deserialize a KeyLocator
 	// from the database.
 	keyLocType tlv.Type = 1
+
+	// A tlv type definition used to serialize and deserialize the
+	// confirmed ShortChannelID for a channel whose ShortChannelID is an
+	// alias.
+	confirmedScidType tlv.Type = 2
+
+	// A tlv type definition used to serialize and deserialize the funding
+	// outpoint of a channel whose funding output has been replaced by a
+	// splice.
+	spliceOutpointType tlv.Type = 3
+
+	// A tlv type definition used to serialize and deserialize the
+	// in-place upgrades of the commitment type of a channel.
+	commitTypeUpgradesType tlv.Type = 4
 )
 
 // indexStatus is an enum-like type that describes what state the
@@ -257,6 +276,16 @@ const (
 	// ZeroHtlcTxFeeBit indicates that the channel should use zero-fee
 	// second-level HTLC transactions.
 	ZeroHtlcTxFeeBit ChannelType = 1 << 5
+
+	// ZeroConfBit indicates that the channel is a zero-conf channel, which
+	// is usable before its funding transaction confirms. Until then, the
+	// channel is only known by an alias ShortChannelID.
+	ZeroConfBit ChannelType = 1 << 6
+
+	// ScidAliasChanBit indicates that the channel has negotiated the
+	// option_scid_alias channel type. Such a channel is only ever referred
+	// to by its alias ShortChannelIDs, never by the confirmed one.
+	ScidAliasChanBit ChannelType = 1 << 7
 )
 
 // IsSingleFunder returns true if the channel type if one of the known single
@@ -301,6 +330,33 @@ func (c ChannelType) IsFrozen() bool {
 	return c&FrozenBit == FrozenBit
 }
 
+// IsZeroConf returns true if the channel is a zero-conf channel.
+func (c ChannelType) IsZeroConf() bool {
+	return c&ZeroConfBit == ZeroConfBit
+}
+
+// HasScidAliasChan returns true if the option_scid_alias channel type was
+// negotiated.
+func (c ChannelType) HasScidAliasChan() bool {
+	return c&ScidAliasChanBit == ScidAliasChanBit
+}
+
+// LeaseTerms are the terms under which the responder of a dual funded channel
+// leased out its contribution to the initiator. The responder's own balance
+// within the channel is delayed by at least the lease duration, so it can't
+// withdraw the leased funds early by force closing the channel.
+type LeaseTerms struct {
+	// Duration is the lease duration in blocks.
+	Duration uint32
+
+	// Amount is the amount the responder leased out.
+	Amount btcutil.Amount
+
+	// Fee is the lease fee the initiator paid to the responder within the
+	// initial balances of the channel.
+	Fee btcutil.Amount
+}
+
 // ChannelConstraints represents a set of constraints meant to allow a node to
 // limit their exposure, enact flow control and ensure that all HTLCs are
 // economically relevant. This struct will be mirrored for both sides of the
@@ -719,6 +775,10 @@ type OpenChannel struct {
 	// interpreted as a relative height, or an absolute height otherwise.
 	ThawHeight uint32
 
+	// Lease is set if the responder's contribution to this dual funded
+	// channel was leased by the initiator.
+	Lease *LeaseTerms
+
 	// LastWasRevoke is a boolean that determines if the last update we sent
 	// was a revocation (true) or a commitment signature (false).
 	LastWasRevoke bool
@@ -728,6 +788,21 @@ type OpenChannel struct {
 	// have private key isolation from lnd.
 	RevocationKeyLocator keychain.KeyLocator
 
+	// confirmedScid is the confirmed ShortChannelID of a channel whose
+	// ShortChannelID is an alias, i.e. a zero-conf or option_scid_alias
+	// channel. It is zero until the funding transaction confirms.
+	confirmedScid lnwire.ShortChannelID
+
+	// spliceOutpoint is the outpoint of the output that funds the channel
+	// after it has been spliced. The FundingOutpoint remains the
+	// identifier of the channel. It is zero if the channel has never been
+	// spliced.
+	spliceOutpoint wire.OutPoint
+
+	// commitTypeUpgrades are the in-place upgrades of the commitment type
+	// of the channel, oldest first.
+	commitTypeUpgrades []CommitTypeUpgrade
+
 	// TODO(roasbeef): eww
 	Db *DB
 
@@ -744,6 +819,52 @@ func (c *OpenChannel) ShortChanID() lnwire.ShortChannelID {
 	return c.ShortChannelID
 }
 
+// IsZeroConf returns whether the channel is a zero-conf channel.
+func (c *OpenChannel) IsZeroConf() bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.ChanType.IsZeroConf()
+}
+
+// IsOptionScidAlias returns whether the option_scid_alias channel type was
+// negotiated for this channel.
+func (c *OpenChannel) IsOptionScidAlias() bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.ChanType.HasScidAliasChan()
+}
+
+// HasAliasScid returns true if the ShortChannelID of this channel is an alias
+// rather than the location of the funding transaction within the chain. This
+// is the case for zero-conf and option_scid_alias channels.
+func (c *OpenChannel) HasAliasScid() bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.ChanType.IsZeroConf() || c.ChanType.HasScidAliasChan()
+}
+
+// ConfirmedScid returns the confirmed ShortChannelID of a channel whose
+// ShortChannelID is an alias. The returned value is zero if the funding
+// transaction hasn't confirmed yet.
+func (c *OpenChannel) ConfirmedScid() lnwire.ShortChannelID {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.confirmedScid
+}
+
+// IsConfirmed returns true if the funding transaction of a channel whose
+// ShortChannelID is an alias has confirmed.
+func (c *OpenChannel) IsConfirmed() bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.confirmedScid != lnwire.ShortChannelID{}
+}
+
 // ChanStatus returns the current ChannelStatus of this channel.
 func (c *OpenChannel) ChanStatus() ChannelStatus {
 	c.RLock()
@@ -992,6 +1113,39 @@ func (c *OpenChannel) MarkAsOpen(openLoc lnwire.ShortChannelID) error {
 	return nil
 }
 
+// MarkConfirmedScid persists the confirmed ShortChannelID of a channel whose
+// ShortChannelID is an alias. The alias itself is left untouched, as it
+// remains the identifier of the channel within the forwarding packages and
+// the switch.
+func (c *OpenChannel) MarkConfirmedScid(scid lnwire.ShortChannelID) error {
+	c.Lock()
+	defer c.Unlock()
+
+	if err := kvdb.Update(c.Db, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel, err := fetchOpenChannel(chanBucket, &c.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+
+		channel.confirmedScid = scid
+
+		return putOpenChannel(chanBucket.(kvdb.RwBucket), channel)
+	}, func() {}); err != nil {
+		return err
+	}
+
+	c.confirmedScid = scid
+
+	return nil
+}
+
 // MarkDataLoss marks sets the channel status to LocalDataLoss and stores the
 // passed commitPoint for use to retrieve funds in case the remote force closes
 // the channel.
@@ -1129,7 +1283,7 @@ func (c *OpenChannel) ChanSyncMsg() (*lnwire.ChannelReestablish, error) {
 		}
 	}
 
-	return &lnwire.ChannelReestablish{
+	msg := &lnwire.ChannelReestablish{
 		ChanID: lnwire.NewChanIDFromOutPoint(
 			&c.FundingOutpoint,
 		),
@@ -1139,7 +1293,21 @@ func (c *OpenChannel) ChanSyncMsg() (*lnwire.ChannelReestablish, error) {
 		LocalUnrevokedCommitPoint: input.ComputeCommitmentPoint(
 			currentCommitSecret[:],
 		),
-	}, nil
+	}
+
+	// If the commitment type of the channel has been upgraded, we'll also
+	// let the remote party know how many upgrades we applied, so that an
+	// upgrade only one of us applied can be undone.
+	if len(c.commitTypeUpgrades) > 0 {
+		err := msg.SetCommitTypeUpgrades(
+			uint64(len(c.commitTypeUpgrades)),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return msg, nil
 }
 
 // isBorked returns true if the channel has been marked as borked in the
@@ -1190,9 +1358,11 @@ func (c *OpenChannel) MarkCoopBroadcasted(closeTx *wire.MsgTx,
 // markBroadcasted is a helper function which modifies the channel status of the
 // receiving channel and inserts a close transaction under the requested key,
 // which should specify either a coop or force close. It adds a status which
-// indicates the party that initiated the channel close.
+// indicates the party that initiated the channel close. Any extra closures
+// passed are applied to the chanBucket atomically with the new status.
 func (c *OpenChannel) markBroadcasted(status ChannelStatus, key []byte,
-	closeTx *wire.MsgTx, locallyInitiated bool) error {
+	closeTx *wire.MsgTx, locallyInitiated bool,
+	fs ...func(kvdb.RwBucket) error) error {
 
 	c.Lock()
 	defer c.Unlock()
@@ -1220,7 +1390,7 @@ func (c *OpenChannel) markBroadcasted(status ChannelStatus, key []byte,
 		status |= ChanStatusRemoteCloseInitiator
 	}
 
-	return c.putChanStatus(status, putClosingTx)
+	return c.putChanStatus(status, append(fs, putClosingTx)...)
 }
 
 // BroadcastedCommitment retrieves the stored unilateral closing tx set during
@@ -1372,6 +1542,16 @@ func putOpenChannel(chanBucket kvdb.RwBucket, channel *OpenChannel) error {
 		}
 	}
 
+	// If the channel was funded with a lease, we'll store its terms as
+	// well.
+	if channel.Lease != nil {
+		err := storeLeaseTerms(chanBucket, channel.Lease)
+		if err != nil {
+			return fmt.Errorf("unable to store lease terms: %v",
+				err)
+		}
+	}
+
 	// Finally, we'll write out the revocation state for both parties
 	// within a distinct key space.
 	if err := putChanRevocationState(chanBucket, channel); err != nil {
@@ -1414,6 +1594,12 @@ func fetchOpenChannel(chanBucket kvdb.RBucket,
 		channel.ThawHeight = thawHeight
 	}
 
+	lease, err := fetchLeaseTerms(chanBucket)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch lease terms: %v", err)
+	}
+	channel.Lease = lease
+
 	// Finally, we'll retrieve the current revocation state so we can
 	// properly
 	if err := fetchChanRevocationState(chanBucket, channel); err != nil {
@@ -2878,6 +3064,12 @@ func (c *OpenChannel) CloseChannel(summary *ChannelCloseSummary,
 			}
 		}
 
+		// The same goes for the terms of the lease the channel may
+		// have been funded with.
+		if err := chanBucket.Delete(leaseTermsKey); err != nil {
+			return err
+		}
+
 		// With the base channel data deleted, attempt to delete the
 		// information stored within the revocation log.
 		logBucket := chanBucket.NestedReadWriteBucket(revocationLogBucket)
@@ -3067,6 +3259,20 @@ func (c *OpenChannel) RemoteRevocationStore() (shachain.Store, error) {
 	return c.RevocationStore, nil
 }
 
+// LeaseExpiry returns the height at which the lease the channel was funded
+// with expires, or zero if the channel wasn't funded with a lease or isn't
+// confirmed yet.
+func (c *OpenChannel) LeaseExpiry() uint32 {
+	c.RLock()
+	defer c.RUnlock()
+
+	if c.Lease == nil || c.ShortChannelID.BlockHeight == 0 {
+		return 0
+	}
+
+	return c.ShortChannelID.BlockHeight + c.Lease.Duration
+}
+
 // AbsoluteThawHeight determines a frozen channel's absolute thaw height. If the
 // channel is not frozen, then 0 is returned.
 func (c *OpenChannel) AbsoluteThawHeight() (uint32, error) {
@@ -3263,8 +3469,10 @@ func writeChanConfig(b io.Writer, c *ChannelConfig) error {
 func fundingTxPresent(channel *OpenChannel) bool {
 	chanType := channel.ChanType
 
-	return chanType.IsSingleFunder() && chanType.HasFundingTx() &&
-		channel.IsInitiator &&
+	// Both parties of a dual funded channel know the funding transaction,
+	// as it's constructed interactively.
+	return chanType.HasFundingTx() &&
+		(channel.IsInitiator || chanType.IsDualFunder()) &&
 		!channel.hasChanStatus(ChanStatusRestored)
 }
 
@@ -3296,12 +3504,23 @@ func putChanInfo(chanBucket kvdb.RwBucket, channel *OpenChannel) error {
 		return err
 	}
 
-	// Write the RevocationKeyLocator as the first entry in a tlv stream.
+	// Write the RevocationKeyLocator as the first entry in a tlv stream,
+	// followed by the confirmed ShortChannelID of alias channels, the
+	// funding outpoint of spliced channels and the commitment type
+	// upgrades of the channel.
 	keyLocRecord := MakeKeyLocRecord(
 		keyLocType, &channel.RevocationKeyLocator,
 	)
+	confirmedScidRecord := tlv.MakeStaticRecord(
+		confirmedScidType, &channel.confirmedScid, 8,
+		lnwire.EShortChannelID, lnwire.DShortChannelID,
+	)
 
-	tlvStream, err := tlv.NewStream(keyLocRecord)
+	tlvStream, err := tlv.NewStream(
+		keyLocRecord, confirmedScidRecord,
+		makeSpliceOutpointRecord(&channel.spliceOutpoint),
+		makeCommitTypeUpgradesRecord(&channel.commitTypeUpgrades),
+	)
 	if err != nil {
 		return err
 	}
@@ -3500,7 +3719,15 @@ func fetchChanInfo(chanBucket kvdb.RBucket, channel *OpenChannel) error {
 	}
 
 	keyLocRecord := MakeKeyLocRecord(keyLocType, &channel.RevocationKeyLocator)
-	tlvStream, err := tlv.NewStream(keyLocRecord)
+	confirmedScidRecord := tlv.MakeStaticRecord(
+		confirmedScidType, &channel.confirmedScid, 8,
+		lnwire.EShortChannelID, lnwire.DShortChannelID,
+	)
+	tlvStream, err := tlv.NewStream(
+		keyLocRecord, confirmedScidRecord,
+		makeSpliceOutpointRecord(&channel.spliceOutpoint),
+		makeCommitTypeUpgradesRecord(&channel.commitTypeUpgrades),
+	)
 	if err != nil {
 		return err
 	}
@@ -3692,6 +3919,38 @@ func deleteThawHeight(chanBucket kvdb.RwBucket) error {
 	return chanBucket.Delete(frozenChanKey)
 }
 
+// fetchLeaseTerms returns the terms of the lease the channel was funded with,
+// or nil if it wasn't funded with a lease.
+func fetchLeaseTerms(chanBucket kvdb.RBucket) (*LeaseTerms, error) {
+	leaseBytes := chanBucket.Get(leaseTermsKey)
+	if leaseBytes == nil {
+		return nil, nil
+	}
+
+	var lease LeaseTerms
+	err := ReadElements(
+		bytes.NewReader(leaseBytes), &lease.Duration, &lease.Amount,
+		&lease.Fee,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lease, nil
+}
+
+func storeLeaseTerms(chanBucket kvdb.RwBucket, lease *LeaseTerms) error {
+	var leaseBuf bytes.Buffer
+	err := WriteElements(
+		&leaseBuf, lease.Duration, lease.Amount, lease.Fee,
+	)
+	if err != nil {
+		return err
+	}
+
+	return chanBucket.Put(leaseTermsKey, leaseBuf.Bytes())
+}
+
 // EKeyLocator is an encoder for keychain.KeyLocator.
 func EKeyLocator(w io.Writer, val interface{}, buf *[8]byte) error {
 	if v, ok := val.(*keychain.KeyLocator); ok {
@@ -10,10 +10,10 @@ import (
 
 	"github.com/stretchr/testify/require"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/clock"
@@ -36,7 +36,7 @@ var (
 		0x48, 0x59, 0xe6, 0x96, 0x31, 0x13, 0xa1, 0x17,
 		0x2d, 0xe7, 0x93, 0xe4,
 	}
-	privKey, pubKey = btcec.PrivKeyFromBytes(btcec.S256(), key[:])
+	privKey, pubKey = btcec.PrivKeyFromBytes(key[:])
 
 	wireSig, _ = lnwire.NewSigFromSignature(testSig)
 
@@ -392,7 +392,7 @@ func TestOpenChannelPutGetDelete(t *testing.T) {
 	// We'll also test that the channel is properly able to hot swap the
 	// next revocation for the state machine. This tests the initial
 	// post-funding revocation exchange.
-	nextRevKey, err := btcec.NewPrivateKey(btcec.S256())
+	nextRevKey, err := btcec.NewPrivateKey()
 	if err != nil {
 		t.Fatalf("unable to create new private key: %v", err)
 	}
@@ -731,7 +731,7 @@ func TestChannelStateTransition(t *testing.T) {
 	// current uncollapsed revocation state to simulate a state transition
 	// by the remote party.
 	channel.RemoteCurrentRevocation = channel.RemoteNextRevocation
-	newPriv, err := btcec.NewPrivateKey(btcec.S256())
+	newPriv, err := btcec.NewPrivateKey()
 	if err != nil {
 		t.Fatalf("unable to generate key: %v", err)
 	}
@@ -1230,6 +1230,99 @@ func TestRefreshShortChanID(t *testing.T) {
 	}
 }
 
+// TestMarkConfirmedScid tests that the confirmed short channel ID of a
+// zero-conf channel is persisted alongside its alias.
+func TestMarkConfirmedScid(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := MakeTestDB()
+	require.NoError(t, err, "unable to make test database")
+	defer cleanUp()
+
+	// Create a pending zero-conf channel and mark it open using an alias,
+	// as the funding transaction hasn't confirmed yet.
+	state := createTestChannel(t, cdb, func(params *testChannelParams) {
+		params.channel.ChanType |= ZeroConfBit
+	})
+	require.True(t, state.IsZeroConf())
+	require.True(t, state.HasAliasScid())
+	require.False(t, state.IsConfirmed())
+
+	alias := lnwire.ShortChannelID{
+		BlockHeight: 16_000_000,
+		TxIndex:     1,
+	}
+	require.NoError(t, state.MarkAsOpen(alias))
+
+	// Now simulate the confirmation of the funding transaction.
+	confirmedScid := lnwire.ShortChannelID{
+		BlockHeight: 105,
+		TxIndex:     10,
+		TxPosition:  15,
+	}
+	require.NoError(t, state.MarkConfirmedScid(confirmedScid))
+	require.True(t, state.IsConfirmed())
+	require.Equal(t, confirmedScid, state.ConfirmedScid())
+
+	// Fetching the channel from disk should return the alias as the short
+	// channel ID and the confirmed short channel ID separately.
+	channels, err := cdb.FetchOpenChannels(state.IdentityPub)
+	require.NoError(t, err, "unable to fetch open channels")
+	require.Len(t, channels, 1)
+
+	dbChannel := channels[0]
+	require.True(t, dbChannel.IsZeroConf())
+	require.Equal(t, alias, dbChannel.ShortChanID())
+	require.Equal(t, confirmedScid, dbChannel.ConfirmedScid())
+}
+
+// TestLeaseTerms tests that the terms of the lease a channel was funded with
+// are persisted along with the channel.
+func TestLeaseTerms(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := MakeTestDB()
+	require.NoError(t, err, "unable to make test database")
+	defer cleanUp()
+
+	lease := &LeaseTerms{
+		Duration: 4032,
+		Amount:   500000,
+		Fee:      3500,
+	}
+	state := createTestChannel(
+		t, cdb, openChannelOption(),
+		func(params *testChannelParams) {
+			params.channel.Lease = lease
+		},
+	)
+
+	channels, err := cdb.FetchOpenChannels(state.IdentityPub)
+	require.NoError(t, err, "unable to fetch open channels")
+	require.Len(t, channels, 1)
+
+	dbChannel := channels[0]
+	require.Equal(t, lease, dbChannel.Lease)
+	require.Equal(
+		t, state.ShortChannelID.BlockHeight+lease.Duration,
+		dbChannel.LeaseExpiry(),
+	)
+
+	// A channel funded without a lease doesn't have any terms.
+	state = createTestChannel(t, cdb, openChannelOption())
+	channels, err = cdb.FetchOpenChannels(state.IdentityPub)
+	require.NoError(t, err, "unable to fetch open channels")
+	require.Len(t, channels, 2)
+	for _, channel := range channels {
+		if channel.FundingOutpoint != state.FundingOutpoint {
+			continue
+		}
+
+		require.Nil(t, channel.Lease)
+		require.Zero(t, channel.LeaseExpiry())
+	}
+}
+
 // TestCloseInitiator tests the setting of close initiator statuses for
 // cooperative closes and local force closes.
 func TestCloseInitiator(t *testing.T) {
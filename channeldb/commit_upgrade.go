@@ -0,0 +1,254 @@
+package channeldb
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// commitTypeUpgradeLen is the length of a serialized commitment type
+	// upgrade.
+	commitTypeUpgradeLen = 17
+)
+
+// CommitTypeUpgrade records an in-place upgrade of the commitment type of a
+// channel. Commitments signed before the upgrade keep using the previous
+// commitment type, which needs to be known to sweep their outputs if they
+// ever confirm.
+type CommitTypeUpgrade struct {
+	// PrevType is the channel type that was in effect before the upgrade.
+	PrevType ChannelType
+
+	// LocalHeight is the height of the first local commitment that uses
+	// the upgraded commitment type.
+	LocalHeight uint64
+
+	// RemoteHeight is the height of the first remote commitment that uses
+	// the upgraded commitment type.
+	RemoteHeight uint64
+}
+
+// CommitTypeUpgrades returns all upgrades of the commitment type of the
+// channel, oldest first.
+func (c *OpenChannel) CommitTypeUpgrades() []CommitTypeUpgrade {
+	c.RLock()
+	defer c.RUnlock()
+
+	upgrades := make([]CommitTypeUpgrade, len(c.commitTypeUpgrades))
+	copy(upgrades, c.commitTypeUpgrades)
+
+	return upgrades
+}
+
+// CommitChanType returns the channel type of the local or remote commitment
+// at the given height. This differs from the ChanType of the channel for
+// commitments that were signed before the commitment type was upgraded.
+func (c *OpenChannel) CommitChanType(local bool, height uint64) ChannelType {
+	c.RLock()
+	defer c.RUnlock()
+
+	chanType := c.ChanType
+	for i := len(c.commitTypeUpgrades) - 1; i >= 0; i-- {
+		upgrade := c.commitTypeUpgrades[i]
+
+		firstHeight := upgrade.RemoteHeight
+		if local {
+			firstHeight = upgrade.LocalHeight
+		}
+		if height >= firstHeight {
+			break
+		}
+
+		chanType = upgrade.PrevType
+	}
+
+	return chanType
+}
+
+// UpgradeCommitType changes the channel type of the channel to the new type.
+// The new type applies to all commitments signed after the current local and
+// remote commitments, so the caller MUST ensure that no newer commitments
+// have been signed yet.
+func (c *OpenChannel) UpgradeCommitType(newType ChannelType) error {
+	c.Lock()
+	defer c.Unlock()
+
+	upgrade := CommitTypeUpgrade{
+		PrevType:     c.ChanType,
+		LocalHeight:  c.LocalCommitment.CommitHeight + 1,
+		RemoteHeight: c.RemoteCommitment.CommitHeight + 1,
+	}
+
+	// The upgrades are ordered by height, so a second upgrade can only be
+	// recorded once the previous one has been committed to.
+	if n := len(c.commitTypeUpgrades); n > 0 {
+		last := c.commitTypeUpgrades[n-1]
+		if upgrade.LocalHeight <= last.LocalHeight ||
+			upgrade.RemoteHeight <= last.RemoteHeight {
+
+			return fmt.Errorf("commitment type upgrade already " +
+				"pending")
+		}
+	}
+
+	err := kvdb.Update(c.Db, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel, err := fetchOpenChannel(chanBucket, &c.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+
+		channel.ChanType = newType
+		channel.commitTypeUpgrades = append(
+			channel.commitTypeUpgrades, upgrade,
+		)
+
+		return putChanInfo(chanBucket, channel)
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	c.ChanType = newType
+	c.commitTypeUpgrades = append(c.commitTypeUpgrades, upgrade)
+
+	return nil
+}
+
+// RevertCommitTypeUpgrade undoes the latest upgrade of the commitment type of
+// the channel. This is only possible as long as no commitment has been signed
+// with the upgraded commitment type, which the caller MUST ensure.
+func (c *OpenChannel) RevertCommitTypeUpgrade() error {
+	c.Lock()
+	defer c.Unlock()
+
+	n := len(c.commitTypeUpgrades)
+	if n == 0 {
+		return fmt.Errorf("commitment type was never upgraded")
+	}
+
+	upgrade := c.commitTypeUpgrades[n-1]
+	if c.LocalCommitment.CommitHeight >= upgrade.LocalHeight ||
+		c.RemoteCommitment.CommitHeight >= upgrade.RemoteHeight {
+
+		return fmt.Errorf("commitment type upgrade already committed")
+	}
+
+	err := kvdb.Update(c.Db, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		channel, err := fetchOpenChannel(chanBucket, &c.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+
+		channel.ChanType = upgrade.PrevType
+		channel.commitTypeUpgrades = c.commitTypeUpgrades[:n-1]
+
+		return putChanInfo(chanBucket, channel)
+	}, func() {})
+	if err != nil {
+		return err
+	}
+
+	c.ChanType = upgrade.PrevType
+	c.commitTypeUpgrades = c.commitTypeUpgrades[:n-1]
+
+	return nil
+}
+
+// makeCommitTypeUpgradesRecord creates a TLV record for the commitment type
+// upgrades of a channel.
+func makeCommitTypeUpgradesRecord(upgrades *[]CommitTypeUpgrade) tlv.Record {
+	sizeFunc := func() uint64 {
+		return uint64(len(*upgrades) * commitTypeUpgradeLen)
+	}
+
+	return tlv.MakeDynamicRecord(
+		commitTypeUpgradesType, upgrades, sizeFunc,
+		encodeCommitTypeUpgrades, decodeCommitTypeUpgrades,
+	)
+}
+
+// encodeCommitTypeUpgrades is a TLV encoder for a list of commitment type
+// upgrades.
+func encodeCommitTypeUpgrades(w io.Writer, val interface{},
+	buf *[8]byte) error {
+
+	if v, ok := val.(*[]CommitTypeUpgrade); ok {
+		for _, upgrade := range *v {
+			buf[0] = uint8(upgrade.PrevType)
+			if _, err := w.Write(buf[:1]); err != nil {
+				return err
+			}
+
+			binary.BigEndian.PutUint64(buf[:], upgrade.LocalHeight)
+			if _, err := w.Write(buf[:]); err != nil {
+				return err
+			}
+
+			binary.BigEndian.PutUint64(buf[:], upgrade.RemoteHeight)
+			if _, err := w.Write(buf[:]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "[]CommitTypeUpgrade")
+}
+
+// decodeCommitTypeUpgrades is a TLV decoder for a list of commitment type
+// upgrades.
+func decodeCommitTypeUpgrades(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	v, ok := val.(*[]CommitTypeUpgrade)
+	if !ok || l%commitTypeUpgradeLen != 0 {
+		return tlv.NewTypeForDecodingErr(
+			val, "[]CommitTypeUpgrade", l, commitTypeUpgradeLen,
+		)
+	}
+
+	if l == 0 {
+		*v = nil
+		return nil
+	}
+
+	upgrades := make([]CommitTypeUpgrade, l/commitTypeUpgradeLen)
+	for i := range upgrades {
+		if _, err := io.ReadFull(r, buf[:1]); err != nil {
+			return err
+		}
+		upgrades[i].PrevType = ChannelType(buf[0])
+
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return err
+		}
+		upgrades[i].LocalHeight = binary.BigEndian.Uint64(buf[:])
+
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return err
+		}
+		upgrades[i].RemoteHeight = binary.BigEndian.Uint64(buf[:])
+	}
+	*v = upgrades
+
+	return nil
+}
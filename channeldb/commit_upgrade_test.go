@@ -0,0 +1,72 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpgradeCommitType tests that the commitment type upgrades of a channel
+// are persisted, and that the channel type of older commitments is still
+// known after an upgrade.
+func TestUpgradeCommitType(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := MakeTestDB()
+	require.NoError(t, err, "unable to make test database")
+	defer cleanUp()
+
+	state := createTestChannel(
+		t, cdb, openChannelOption(),
+		channelCommitmentOption(10, 1000, 2000, true),
+		channelCommitmentOption(20, 2000, 1000, false),
+	)
+	state.ChanType = SingleFunderBit
+	require.Empty(t, state.CommitTypeUpgrades())
+
+	// Upgrade the channel to static remote key commitments. The new type
+	// applies to all commitments after the current ones.
+	tweakless := SingleFunderTweaklessBit
+	require.NoError(t, state.UpgradeCommitType(tweakless))
+	require.Equal(t, tweakless, state.ChanType)
+	require.Equal(t, SingleFunderBit, state.CommitChanType(true, 10))
+	require.Equal(t, tweakless, state.CommitChanType(true, 11))
+	require.Equal(t, SingleFunderBit, state.CommitChanType(false, 20))
+	require.Equal(t, tweakless, state.CommitChanType(false, 21))
+
+	// A second upgrade can't be made before the first one is committed
+	// to.
+	anchors := tweakless | AnchorOutputsBit | ZeroHtlcTxFeeBit
+	require.Error(t, state.UpgradeCommitType(anchors))
+
+	// As no commitment has been signed with the new type yet, the upgrade
+	// can still be reverted.
+	require.NoError(t, state.RevertCommitTypeUpgrade())
+	require.Equal(t, SingleFunderBit, state.ChanType)
+	require.Empty(t, state.CommitTypeUpgrades())
+	require.Error(t, state.RevertCommitTypeUpgrade())
+	require.NoError(t, state.UpgradeCommitType(tweakless))
+
+	// Once both commitments advanced, the channel can be upgraded again.
+	state.LocalCommitment.CommitHeight = 15
+	state.RemoteCommitment.CommitHeight = 25
+	require.Error(t, state.RevertCommitTypeUpgrade())
+	require.NoError(t, state.UpgradeCommitType(anchors))
+	require.Equal(t, SingleFunderBit, state.CommitChanType(true, 10))
+	require.Equal(t, tweakless, state.CommitChanType(true, 15))
+	require.Equal(t, anchors, state.CommitChanType(true, 16))
+	require.Equal(t, tweakless, state.CommitChanType(false, 25))
+	require.Equal(t, anchors, state.CommitChanType(false, 26))
+
+	// The upgrades must be persisted along with the new channel type.
+	channels, err := cdb.FetchOpenChannels(state.IdentityPub)
+	require.NoError(t, err, "unable to fetch open channels")
+	require.Len(t, channels, 1)
+
+	dbChannel := channels[0]
+	require.Equal(t, anchors, dbChannel.ChanType)
+	require.Equal(t, state.CommitTypeUpgrades(),
+		dbChannel.CommitTypeUpgrades())
+	require.Equal(t, SingleFunderBit, dbChannel.CommitChanType(false, 20))
+	require.Equal(t, tweakless, dbChannel.CommitChanType(false, 21))
+}
@@ -0,0 +1,133 @@
+package channeldb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+)
+
+var (
+	// rbfCoopCloseKey stores the state of a cooperative close whose
+	// closing transaction can be replaced with rbf.
+	rbfCoopCloseKey = []byte("rbf-coop-close-key")
+
+	// ErrNoRbfCoopClose is returned when no rbf cooperative close is found
+	// for a channel.
+	ErrNoRbfCoopClose = fmt.Errorf("no rbf coop close found")
+)
+
+// RbfCoopClose houses the state of a cooperative close whose closing
+// transaction can be replaced with rbf. Either party can replace the latest
+// closing transaction with one paying a higher fee out of its own balance
+// until one of them confirms.
+type RbfCoopClose struct {
+	// LocalDeliveryScript is the script that our funds are paid out to.
+	LocalDeliveryScript []byte
+
+	// RemoteDeliveryScript is the script that the funds of the remote
+	// party are paid out to.
+	RemoteDeliveryScript []byte
+
+	// CloseTx is the latest fully signed closing transaction.
+	CloseTx *wire.MsgTx
+
+	// Fee is the fee paid by the latest closing transaction.
+	Fee btcutil.Amount
+
+	// LocalPays is true if the fee of the latest closing transaction is
+	// paid out of our balance.
+	LocalPays bool
+
+	// BroadcastHeight is the height at which the first closing transaction
+	// was broadcast.
+	BroadcastHeight uint32
+}
+
+// MarkRbfCoopBroadcasted marks the channel as cooperatively closed, and
+// persists the state of the close along with its latest closing transaction.
+// This is called each time a closing transaction is replaced, such that the
+// latest one is republished, and can be replaced again after a restart.
+func (c *OpenChannel) MarkRbfCoopBroadcasted(coopClose *RbfCoopClose,
+	locallyInitiated bool) error {
+
+	var b bytes.Buffer
+	if err := serializeRbfCoopClose(&b, coopClose); err != nil {
+		return err
+	}
+
+	return c.markBroadcasted(
+		ChanStatusCoopBroadcasted, coopCloseTxKey, coopClose.CloseTx,
+		locallyInitiated, func(chanBucket kvdb.RwBucket) error {
+			return chanBucket.Put(rbfCoopCloseKey, b.Bytes())
+		},
+	)
+}
+
+// RbfCoopClose returns the state of the rbf cooperative close of the channel.
+// If the channel isn't being closed that way, ErrNoRbfCoopClose is returned.
+func (c *OpenChannel) RbfCoopClose() (*RbfCoopClose, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	var coopClose *RbfCoopClose
+	err := kvdb.View(c.Db, func(tx kvdb.RTx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		switch err {
+		case nil:
+		case ErrNoChanDBExists, ErrNoActiveChannels, ErrChannelNotFound:
+			return ErrNoRbfCoopClose
+		default:
+			return err
+		}
+
+		closeBytes := chanBucket.Get(rbfCoopCloseKey)
+		if closeBytes == nil {
+			return ErrNoRbfCoopClose
+		}
+
+		coopClose, err = deserializeRbfCoopClose(
+			bytes.NewReader(closeBytes),
+		)
+
+		return err
+	}, func() {
+		coopClose = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return coopClose, nil
+}
+
+// serializeRbfCoopClose serializes an rbf cooperative close to the passed
+// writer.
+func serializeRbfCoopClose(w io.Writer, coopClose *RbfCoopClose) error {
+	return WriteElements(w,
+		coopClose.LocalDeliveryScript, coopClose.RemoteDeliveryScript,
+		coopClose.CloseTx, coopClose.Fee, coopClose.LocalPays,
+		coopClose.BroadcastHeight,
+	)
+}
+
+// deserializeRbfCoopClose deserializes an rbf cooperative close from the
+// passed reader.
+func deserializeRbfCoopClose(r io.Reader) (*RbfCoopClose, error) {
+	coopClose := &RbfCoopClose{}
+	err := ReadElements(r,
+		&coopClose.LocalDeliveryScript, &coopClose.RemoteDeliveryScript,
+		&coopClose.CloseTx, &coopClose.Fee, &coopClose.LocalPays,
+		&coopClose.BroadcastHeight,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return coopClose, nil
+}
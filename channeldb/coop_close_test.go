@@ -0,0 +1,66 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRbfCoopClose tests that the state of an rbf cooperative close is
+// persisted, and that replacing the closing transaction updates both the
+// state and the closing transaction that is republished.
+func TestRbfCoopClose(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := MakeTestDB()
+	require.NoError(t, err, "unable to make test database")
+	defer cleanUp()
+
+	state := createTestChannel(t, cdb, openChannelOption())
+
+	_, err = state.RbfCoopClose()
+	require.ErrorIs(t, err, ErrNoRbfCoopClose)
+
+	newCloseTx := func(value int64) *wire.MsgTx {
+		closeTx := wire.NewMsgTx(2)
+		closeTx.AddTxIn(
+			wire.NewTxIn(&state.FundingOutpoint, []byte{}, nil),
+		)
+		closeTx.AddTxOut(wire.NewTxOut(value, []byte{0x00}))
+
+		return closeTx
+	}
+
+	coopClose := &RbfCoopClose{
+		LocalDeliveryScript:  []byte{0x01, 0x02},
+		RemoteDeliveryScript: []byte{0x03, 0x04},
+		CloseTx:              newCloseTx(1000),
+		Fee:                  100,
+		LocalPays:            true,
+		BroadcastHeight:      100,
+	}
+	require.NoError(t, state.MarkRbfCoopBroadcasted(coopClose, true))
+	require.True(t, state.HasChanStatus(ChanStatusCoopBroadcasted))
+	require.True(t, state.HasChanStatus(ChanStatusLocalCloseInitiator))
+
+	dbClose, err := state.RbfCoopClose()
+	require.NoError(t, err)
+	require.Equal(t, coopClose, dbClose)
+
+	// Replace the closing transaction with one paid for by the remote
+	// party.
+	coopClose.CloseTx = newCloseTx(900)
+	coopClose.Fee = 200
+	coopClose.LocalPays = false
+	require.NoError(t, state.MarkRbfCoopBroadcasted(coopClose, true))
+	require.False(t, state.HasChanStatus(ChanStatusRemoteCloseInitiator))
+
+	dbClose, err = state.RbfCoopClose()
+	require.NoError(t, err)
+	require.Equal(t, coopClose, dbClose)
+
+	closeTx, err := state.BroadcastedCooperative()
+	require.NoError(t, err)
+	require.Equal(t, coopClose.CloseTx.TxHash(), closeTx.TxHash())
+}
@@ -14,11 +14,12 @@ import (
 	"sync"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/batch"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -110,6 +111,21 @@ var (
 	// maps: outPoint -> chanID
 	channelPointBucket = []byte("chan-index")
 
+	// splicedChanBucket is a sub-bucket of the main edgeBucket that maps
+	// the channel point of a channel whose funding output has been
+	// replaced by a splice to the outpoint that currently funds it.
+	//
+	// maps: chanPoint -> fundingOutPoint
+	splicedChanBucket = []byte("spliced-chan-index")
+
+	// spliceOutpointBucket is a sub-bucket of the main edgeBucket that
+	// is the reverse of the splicedChanBucket. It's used to find the
+	// channel that was closed if the funding output of a spliced channel
+	// is spent.
+	//
+	// maps: fundingOutPoint -> chanPoint
+	spliceOutpointBucket = []byte("splice-outpoint-index")
+
 	// zombieBucket is a sub-bucket of the main edgeBucket bucket
 	// responsible for maintaining an index of zombie channels. Each entry
 	// exists within the bucket as follows:
@@ -934,9 +950,20 @@ func (c *ChannelGraph) PruneGraph(spentOutputs []*wire.OutPoint,
 				return err
 			}
 
+			// If the outpoint used to fund a channel that has since
+			// been spliced, the channel lives on. Otherwise, if it
+			// is the output that currently funds a spliced channel,
+			// we'll look up the channel by its channel point.
+			chanPointBytes, spliced := lookupSplicedChanPoint(
+				edges, opBytes.Bytes(),
+			)
+			if spliced {
+				continue
+			}
+
 			// First attempt to see if the channel exists within
 			// the database, if not, then we can exit early.
-			chanID := chanIndex.Get(opBytes.Bytes())
+			chanID := chanIndex.Get(chanPointBytes)
 			if chanID == nil {
 				continue
 			}
@@ -1994,6 +2021,9 @@ func delChannelEdge(edges, edgeIndex, chanIndex, zombieIndex,
 	if err := chanIndex.Delete(b.Bytes()); err != nil {
 		return err
 	}
+	if err := delSpliceIndexEntry(edges, b.Bytes()); err != nil {
+		return err
+	}
 
 	// Finally, we'll mark the edge as a zombie within our index if it's
 	// being removed due to the channel becoming a zombie. We do this to
@@ -2250,7 +2280,7 @@ func (l *LightningNode) PubKey() (*btcec.PublicKey, error) {
 		return l.pubKey, nil
 	}
 
-	key, err := btcec.ParsePubKey(l.PubKeyBytes[:], btcec.S256())
+	key, err := btcec.ParsePubKey(l.PubKeyBytes[:])
 	if err != nil {
 		return nil, err
 	}
@@ -2264,8 +2294,8 @@ func (l *LightningNode) PubKey() (*btcec.PublicKey, error) {
 //
 // NOTE: By having this method to access an attribute, we ensure we only need
 // to fully deserialize the signature if absolutely necessary.
-func (l *LightningNode) AuthSig() (*btcec.Signature, error) {
-	return btcec.ParseSignature(l.AuthSigBytes, btcec.S256())
+func (l *LightningNode) AuthSig() (*ecdsa.Signature, error) {
+	return ecdsa.ParseSignature(l.AuthSigBytes)
 }
 
 // AddPubKey is a setter-link method that can be used to swap out the public
@@ -2660,7 +2690,7 @@ func (c *ChannelEdgeInfo) NodeKey1() (*btcec.PublicKey, error) {
 		return c.nodeKey1, nil
 	}
 
-	key, err := btcec.ParsePubKey(c.NodeKey1Bytes[:], btcec.S256())
+	key, err := btcec.ParsePubKey(c.NodeKey1Bytes[:])
 	if err != nil {
 		return nil, err
 	}
@@ -2682,7 +2712,7 @@ func (c *ChannelEdgeInfo) NodeKey2() (*btcec.PublicKey, error) {
 		return c.nodeKey2, nil
 	}
 
-	key, err := btcec.ParsePubKey(c.NodeKey2Bytes[:], btcec.S256())
+	key, err := btcec.ParsePubKey(c.NodeKey2Bytes[:])
 	if err != nil {
 		return nil, err
 	}
@@ -2702,7 +2732,7 @@ func (c *ChannelEdgeInfo) BitcoinKey1() (*btcec.PublicKey, error) {
 		return c.bitcoinKey1, nil
 	}
 
-	key, err := btcec.ParsePubKey(c.BitcoinKey1Bytes[:], btcec.S256())
+	key, err := btcec.ParsePubKey(c.BitcoinKey1Bytes[:])
 	if err != nil {
 		return nil, err
 	}
@@ -2722,7 +2752,7 @@ func (c *ChannelEdgeInfo) BitcoinKey2() (*btcec.PublicKey, error) {
 		return c.bitcoinKey2, nil
 	}
 
-	key, err := btcec.ParsePubKey(c.BitcoinKey2Bytes[:], btcec.S256())
+	key, err := btcec.ParsePubKey(c.BitcoinKey2Bytes[:])
 	if err != nil {
 		return nil, err
 	}
@@ -2804,28 +2834,28 @@ func (c *ChannelEdgeInfo) FetchOtherNode(tx kvdb.RTx, thisNodeKey []byte) (*Ligh
 // features.
 type ChannelAuthProof struct {
 	// nodeSig1 is a cached instance of the first node signature.
-	nodeSig1 *btcec.Signature
+	nodeSig1 *ecdsa.Signature
 
 	// NodeSig1Bytes are the raw bytes of the first node signature encoded
 	// in DER format.
 	NodeSig1Bytes []byte
 
 	// nodeSig2 is a cached instance of the second node signature.
-	nodeSig2 *btcec.Signature
+	nodeSig2 *ecdsa.Signature
 
 	// NodeSig2Bytes are the raw bytes of the second node signature
 	// encoded in DER format.
 	NodeSig2Bytes []byte
 
 	// bitcoinSig1 is a cached instance of the first bitcoin signature.
-	bitcoinSig1 *btcec.Signature
+	bitcoinSig1 *ecdsa.Signature
 
 	// BitcoinSig1Bytes are the raw bytes of the first bitcoin signature
 	// encoded in DER format.
 	BitcoinSig1Bytes []byte
 
 	// bitcoinSig2 is a cached instance of the second bitcoin signature.
-	bitcoinSig2 *btcec.Signature
+	bitcoinSig2 *ecdsa.Signature
 
 	// BitcoinSig2Bytes are the raw bytes of the second bitcoin signature
 	// encoded in DER format.
@@ -2838,12 +2868,12 @@ type ChannelAuthProof struct {
 //
 // NOTE: By having this method to access an attribute, we ensure we only need
 // to fully deserialize the signature if absolutely necessary.
-func (c *ChannelAuthProof) Node1Sig() (*btcec.Signature, error) {
+func (c *ChannelAuthProof) Node1Sig() (*ecdsa.Signature, error) {
 	if c.nodeSig1 != nil {
 		return c.nodeSig1, nil
 	}
 
-	sig, err := btcec.ParseSignature(c.NodeSig1Bytes, btcec.S256())
+	sig, err := ecdsa.ParseSignature(c.NodeSig1Bytes)
 	if err != nil {
 		return nil, err
 	}
@@ -2859,12 +2889,12 @@ func (c *ChannelAuthProof) Node1Sig() (*btcec.Signature, error) {
 //
 // NOTE: By having this method to access an attribute, we ensure we only need
 // to fully deserialize the signature if absolutely necessary.
-func (c *ChannelAuthProof) Node2Sig() (*btcec.Signature, error) {
+func (c *ChannelAuthProof) Node2Sig() (*ecdsa.Signature, error) {
 	if c.nodeSig2 != nil {
 		return c.nodeSig2, nil
 	}
 
-	sig, err := btcec.ParseSignature(c.NodeSig2Bytes, btcec.S256())
+	sig, err := ecdsa.ParseSignature(c.NodeSig2Bytes)
 	if err != nil {
 		return nil, err
 	}
@@ -2879,12 +2909,12 @@ func (c *ChannelAuthProof) Node2Sig() (*btcec.Signature, error) {
 //
 // NOTE: By having this method to access an attribute, we ensure we only need
 // to fully deserialize the signature if absolutely necessary.
-func (c *ChannelAuthProof) BitcoinSig1() (*btcec.Signature, error) {
+func (c *ChannelAuthProof) BitcoinSig1() (*ecdsa.Signature, error) {
 	if c.bitcoinSig1 != nil {
 		return c.bitcoinSig1, nil
 	}
 
-	sig, err := btcec.ParseSignature(c.BitcoinSig1Bytes, btcec.S256())
+	sig, err := ecdsa.ParseSignature(c.BitcoinSig1Bytes)
 	if err != nil {
 		return nil, err
 	}
@@ -2899,12 +2929,12 @@ func (c *ChannelAuthProof) BitcoinSig1() (*btcec.Signature, error) {
 //
 // NOTE: By having this method to access an attribute, we ensure we only need
 // to fully deserialize the signature if absolutely necessary.
-func (c *ChannelAuthProof) BitcoinSig2() (*btcec.Signature, error) {
+func (c *ChannelAuthProof) BitcoinSig2() (*ecdsa.Signature, error) {
 	if c.bitcoinSig2 != nil {
 		return c.bitcoinSig2, nil
 	}
 
-	sig, err := btcec.ParseSignature(c.BitcoinSig2Bytes, btcec.S256())
+	sig, err := ecdsa.ParseSignature(c.BitcoinSig2Bytes)
 	if err != nil {
 		return nil, err
 	}
@@ -2936,7 +2966,7 @@ type ChannelEdgePolicy struct {
 	SigBytes []byte
 
 	// sig is a cached fully parsed signature.
-	sig *btcec.Signature
+	sig *ecdsa.Signature
 
 	// ChannelID is the unique channel ID for the channel. The first 3
 	// bytes are the block height, the next 3 the index within the block,
@@ -2996,12 +3026,12 @@ type ChannelEdgePolicy struct {
 //
 // NOTE: By having this method to access an attribute, we ensure we only need
 // to fully deserialize the signature if absolutely necessary.
-func (c *ChannelEdgePolicy) Signature() (*btcec.Signature, error) {
+func (c *ChannelEdgePolicy) Signature() (*ecdsa.Signature, error) {
 	if c.sig != nil {
 		return c.sig, nil
 	}
 
-	sig, err := btcec.ParseSignature(c.SigBytes, btcec.S256())
+	sig, err := ecdsa.ParseSignature(c.SigBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -3349,10 +3379,21 @@ func (c *ChannelGraph) ChannelView() ([]EdgePoint, error) {
 			return ErrGraphNoEdgesFound
 		}
 
+		// If a channel has been spliced, we need to watch the output
+		// that currently funds it instead of its channel point.
+		splicedChans := edges.NestedReadBucket(splicedChanBucket)
+
 		// Once we have the proper bucket, we'll range over each key
 		// (which is the channel point for the channel) and decode it,
 		// accumulating each entry.
 		return chanIndex.ForEach(func(chanPointBytes, chanID []byte) error {
+			if splicedChans != nil {
+				fundingPoint := splicedChans.Get(chanPointBytes)
+				if fundingPoint != nil {
+					chanPointBytes = fundingPoint
+				}
+			}
+
 			chanPointReader := bytes.NewReader(chanPointBytes)
 
 			var chanPoint wire.OutPoint
@@ -3392,6 +3433,155 @@ func (c *ChannelGraph) ChannelView() ([]EdgePoint, error) {
 	return edgePoints, nil
 }
 
+// MarkChannelSpliced records that the funding output of the channel with the
+// given channel point has been replaced by a splice. The channel keeps its
+// channel point and short channel ID, but is from then on closed by a spend of
+// the new funding output rather than the original one. Passing the channel
+// point as funding outpoint reverts the channel to its original funding
+// output.
+func (c *ChannelGraph) MarkChannelSpliced(chanPoint,
+	fundingPoint *wire.OutPoint, capacity btcutil.Amount) error {
+
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	var chanPointBytes, fundingPointBytes bytes.Buffer
+	if err := writeOutpoint(&chanPointBytes, chanPoint); err != nil {
+		return err
+	}
+	if err := writeOutpoint(&fundingPointBytes, fundingPoint); err != nil {
+		return err
+	}
+
+	var chanID uint64
+	err := kvdb.Update(c.db, func(tx kvdb.RwTx) error {
+		edges := tx.ReadWriteBucket(edgeBucket)
+		if edges == nil {
+			return ErrGraphNoEdgesFound
+		}
+		edgeIndex := edges.NestedReadWriteBucket(edgeIndexBucket)
+		if edgeIndex == nil {
+			return ErrGraphNoEdgesFound
+		}
+		chanIndex := edges.NestedReadWriteBucket(channelPointBucket)
+		if chanIndex == nil {
+			return ErrGraphNoEdgesFound
+		}
+
+		chanIDBytes := chanIndex.Get(chanPointBytes.Bytes())
+		if chanIDBytes == nil {
+			return ErrEdgeNotFound
+		}
+		chanID = byteOrder.Uint64(chanIDBytes)
+
+		// The capacity of the channel changes with the splice.
+		edgeInfo, err := fetchChanEdgeInfo(edgeIndex, chanIDBytes)
+		if err != nil {
+			return err
+		}
+		edgeInfo.Capacity = capacity
+
+		var chanKey [8]byte
+		copy(chanKey[:], chanIDBytes)
+		err = putChanEdgeInfo(edgeIndex, &edgeInfo, chanKey)
+		if err != nil {
+			return err
+		}
+
+		// Remove the entry of a previous splice before we add the new
+		// one.
+		err = delSpliceIndexEntry(edges, chanPointBytes.Bytes())
+		if err != nil {
+			return err
+		}
+
+		// A channel funded by its channel point has no entry in the
+		// splice index.
+		if *fundingPoint == *chanPoint {
+			return nil
+		}
+
+		splicedChans, err := edges.CreateBucketIfNotExists(
+			splicedChanBucket,
+		)
+		if err != nil {
+			return err
+		}
+		spliceOutpoints, err := edges.CreateBucketIfNotExists(
+			spliceOutpointBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = splicedChans.Put(
+			chanPointBytes.Bytes(), fundingPointBytes.Bytes(),
+		)
+		if err != nil {
+			return err
+		}
+
+		return spliceOutpoints.Put(
+			fundingPointBytes.Bytes(), chanPointBytes.Bytes(),
+		)
+	}, func() {
+		chanID = 0
+	})
+	if err != nil {
+		return err
+	}
+
+	c.rejectCache.remove(chanID)
+	c.chanCache.remove(chanID)
+
+	return nil
+}
+
+// lookupSplicedChanPoint returns the channel point of the channel that is
+// funded by the given outpoint. The second return value is true if the
+// outpoint used to fund a channel that has since been spliced, in which case
+// the spend of the outpoint doesn't close the channel.
+func lookupSplicedChanPoint(edges kvdb.RBucket, op []byte) ([]byte, bool) {
+	splicedChans := edges.NestedReadBucket(splicedChanBucket)
+	if splicedChans != nil && splicedChans.Get(op) != nil {
+		return nil, true
+	}
+
+	spliceOutpoints := edges.NestedReadBucket(spliceOutpointBucket)
+	if spliceOutpoints == nil {
+		return op, false
+	}
+
+	if chanPoint := spliceOutpoints.Get(op); chanPoint != nil {
+		return chanPoint, false
+	}
+
+	return op, false
+}
+
+// delSpliceIndexEntry removes the splice index entries of the channel with
+// the given channel point, if any.
+func delSpliceIndexEntry(edges kvdb.RwBucket, chanPoint []byte) error {
+	splicedChans := edges.NestedReadWriteBucket(splicedChanBucket)
+	if splicedChans == nil {
+		return nil
+	}
+
+	fundingPoint := splicedChans.Get(chanPoint)
+	if fundingPoint == nil {
+		return nil
+	}
+
+	spliceOutpoints := edges.NestedReadWriteBucket(spliceOutpointBucket)
+	if spliceOutpoints != nil {
+		if err := spliceOutpoints.Delete(fundingPoint); err != nil {
+			return err
+		}
+	}
+
+	return splicedChans.Delete(chanPoint)
+}
+
 // NewChannelEdgePolicy returns a new blank ChannelEdgePolicy.
 func (c *ChannelGraph) NewChannelEdgePolicy() *ChannelEdgePolicy {
 	return &ChannelEdgePolicy{db: c.db}
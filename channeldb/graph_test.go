@@ -3,11 +3,11 @@ package channeldb
 import (
 	"bytes"
 	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"image/color"
 	"math"
-	"math/big"
 	prand "math/rand"
 	"net"
 	"reflect"
@@ -16,7 +16,9 @@ import (
 	"testing"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/davecgh/go-spew/spew"
@@ -33,12 +35,13 @@ var (
 		"[2001:db8:85a3:0:0:8a2e:370:7334]:80")
 	testAddrs = []net.Addr{testAddr, anotherAddr}
 
-	testSig = &btcec.Signature{
-		R: new(big.Int),
-		S: new(big.Int),
-	}
-	_, _ = testSig.R.SetString("63724406601629180062774974542967536251589935445068131219452686511677818569431", 10)
-	_, _ = testSig.S.SetString("18801056069249825825291287104931333862866033135609736119018462340006816851118", 10)
+	testRBytes, _ = hex.DecodeString("8ce2bc69281ce27da07e6683571319d18e949ddfa2965fb6caa1bf0314f882d7")
+	testSBytes, _ = hex.DecodeString("299105481d63e0f4bc2a88121167221b6700d72a0ead154c03be696a292d24ae")
+	testRScalar   = new(btcec.ModNScalar)
+	testSScalar   = new(btcec.ModNScalar)
+	_             = testRScalar.SetByteSlice(testRBytes)
+	_             = testSScalar.SetByteSlice(testSBytes)
+	testSig       = ecdsa.NewSignature(testRScalar, testSScalar)
 
 	testFeatures = lnwire.NewFeatureVector(nil, lnwire.Features)
 
@@ -65,7 +68,7 @@ func createLightningNode(db *DB, priv *btcec.PrivateKey) (*LightningNode, error)
 }
 
 func createTestVertex(db *DB) (*LightningNode, error) {
-	priv, err := btcec.NewPrivateKey(btcec.S256())
+	priv, err := btcec.NewPrivateKey()
 	if err != nil {
 		return nil, err
 	}
@@ -1315,6 +1318,99 @@ func TestGraphPruning(t *testing.T) {
 	}
 }
 
+// TestGraphPruningSplicedChannel tests that the spend of the original funding
+// output of a spliced channel doesn't prune the channel, while the spend of
+// its new funding output does.
+func TestGraphPruningSplicedChannel(t *testing.T) {
+	t.Parallel()
+
+	db, cleanUp, err := MakeTestDB()
+	defer cleanUp()
+	require.NoError(t, err, "unable to make test database")
+
+	graph := db.ChannelGraph()
+	sourceNode, err := createTestVertex(db)
+	require.NoError(t, err, "unable to create source node")
+	require.NoError(t, graph.SetSourceNode(sourceNode))
+
+	node1, err := createTestVertex(db)
+	require.NoError(t, err, "unable to create node")
+	require.NoError(t, graph.AddLightningNode(node1))
+	node2, err := createTestVertex(db)
+	require.NoError(t, err, "unable to create node")
+	require.NoError(t, graph.AddLightningNode(node2))
+
+	edgeInfo, _, _ := createChannelEdge(db, node1, node2)
+	require.NoError(t, graph.AddChannelEdge(edgeInfo))
+
+	chanPoint := edgeInfo.ChannelPoint
+	fundingPoint := wire.OutPoint{
+		Hash:  sha256.Sum256([]byte("splice")),
+		Index: 1,
+	}
+
+	// Splicing an unknown channel should fail.
+	unknownPoint := wire.OutPoint{Index: 1}
+	err = graph.MarkChannelSpliced(&unknownPoint, &fundingPoint, 2000)
+	require.ErrorIs(t, err, ErrEdgeNotFound)
+
+	require.NoError(t, graph.MarkChannelSpliced(
+		&chanPoint, &fundingPoint, 2000,
+	))
+
+	// The channel keeps its channel point, but the capacity is updated
+	// and the new funding output is watched instead.
+	dbEdgeInfo, _, _, err := graph.FetchChannelEdgesByOutpoint(&chanPoint)
+	require.NoError(t, err)
+	require.Equal(t, btcutil.Amount(2000), dbEdgeInfo.Capacity)
+
+	channelView, err := graph.ChannelView()
+	require.NoError(t, err)
+	assertChanViewEqualChanPoints(
+		t, channelView, []*wire.OutPoint{&fundingPoint},
+	)
+
+	// An aborted splice reverts the channel to its original funding
+	// output, after which the splice is marked again.
+	require.NoError(t, graph.MarkChannelSpliced(
+		&chanPoint, &chanPoint, edgeInfo.Capacity,
+	))
+	channelView, err = graph.ChannelView()
+	require.NoError(t, err)
+	assertChanViewEqualChanPoints(
+		t, channelView, []*wire.OutPoint{&chanPoint},
+	)
+
+	require.NoError(t, graph.MarkChannelSpliced(
+		&chanPoint, &fundingPoint, 2000,
+	))
+
+	// The spend of the original funding output by the splice transaction
+	// must not prune the channel.
+	var blockHash chainhash.Hash
+	copy(blockHash[:], bytes.Repeat([]byte{1}, 32))
+	prunedChans, err := graph.PruneGraph(
+		[]*wire.OutPoint{&chanPoint}, &blockHash, 1,
+	)
+	require.NoError(t, err)
+	require.Empty(t, prunedChans)
+	assertNumChans(t, graph, 1)
+
+	// The spend of the new funding output closes the channel.
+	blockHash = sha256.Sum256(blockHash[:])
+	prunedChans, err = graph.PruneGraph(
+		[]*wire.OutPoint{&fundingPoint}, &blockHash, 2,
+	)
+	require.NoError(t, err)
+	require.Len(t, prunedChans, 1)
+	require.Equal(t, edgeInfo.ChannelID, prunedChans[0].ChannelID)
+	assertNumChans(t, graph, 0)
+
+	channelView, err = graph.ChannelView()
+	require.NoError(t, err)
+	require.Empty(t, channelView)
+}
+
 // TestHighestChanID tests that we're able to properly retrieve the highest
 // known channel ID in the database.
 func TestHighestChanID(t *testing.T) {
@@ -3167,15 +3263,12 @@ func TestLightningNodeSigVerification(t *testing.T) {
 	}
 
 	// Create private key and sign the data with it.
-	priv, err := btcec.NewPrivateKey(btcec.S256())
+	priv, err := btcec.NewPrivateKey()
 	if err != nil {
 		t.Fatalf("unable to crete priv key: %v", err)
 	}
 
-	sign, err := priv.Sign(data[:])
-	if err != nil {
-		t.Fatalf("unable to sign: %v", err)
-	}
+	sign := ecdsa.Sign(priv, data[:])
 
 	// Sanity check that the signature checks out.
 	if !sign.Verify(data[:], priv.PubKey()) {
@@ -6,7 +6,8 @@ import (
 	"testing"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
 	bitcoinCfg "github.com/btcsuite/btcd/chaincfg"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/zpay32"
@@ -150,12 +151,10 @@ func signDigestCompact(hash []byte) ([]byte, error) {
 	// Should the signature reference a compressed public key or not.
 	isCompressedKey := true
 
-	privKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), testPrivKeyBytes)
+	privKey, _ := btcec.PrivKeyFromBytes(testPrivKeyBytes)
 
 	// btcec.SignCompact returns a pubkey-recoverable signature
-	sig, err := btcec.SignCompact(
-		btcec.S256(), privKey, hash, isCompressedKey,
-	)
+	sig, err := ecdsa.SignCompact(privKey, hash, isCompressedKey)
 	if err != nil {
 		return nil, fmt.Errorf("can't sign the hash: %v", err)
 	}
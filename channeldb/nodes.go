@@ -6,7 +6,7 @@ import (
 	"net"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/kvdb"
 )
@@ -292,7 +292,7 @@ func deserializeLinkNode(r io.Reader) (*LinkNode, error) {
 	if _, err := io.ReadFull(r, pub[:]); err != nil {
 		return nil, err
 	}
-	node.IdentityPub, err = btcec.ParsePubKey(pub[:], btcec.S256())
+	node.IdentityPub, err = btcec.ParsePubKey(pub[:])
 	if err != nil {
 		return nil, err
 	}
@@ -20,6 +20,7 @@ var (
 	//      |
 	//      |-- <peer-pubkey>
 	//      |        |--flap-count-key: <ts><flap count>
+	//      |        |--peer-storage-key: <blob>
 	//      |
 	//      |-- <peer-pubkey>
 	//      |        |--flap-count-key: <ts><flap count>
@@ -29,6 +30,11 @@ var (
 	// the timestamp of a peer's last flap count and its all time flap
 	// count.
 	flapCountKey = []byte("flap-count")
+
+	// peerStorageKey is a key used in the peer pubkey sub-bucket that
+	// stores the opaque blob the peer asked us to hold on to, which we
+	// return to the peer whenever it reconnects.
+	peerStorageKey = []byte("peer-storage")
 )
 
 var (
@@ -121,3 +127,47 @@ func (d *DB) ReadFlapCount(pubkey route.Vertex) (*FlapCount, error) {
 
 	return &flapCount, nil
 }
+
+// PutPeerStorage stores the opaque blob a peer asked us to hold on to,
+// replacing any blob previously stored for the peer.
+func (d *DB) PutPeerStorage(pubkey route.Vertex, blob []byte) error {
+	return kvdb.Update(d, func(tx kvdb.RwTx) error {
+		peers := tx.ReadWriteBucket(peersBucket)
+
+		peerBucket, err := peers.CreateBucketIfNotExists(pubkey[:])
+		if err != nil {
+			return err
+		}
+
+		return peerBucket.Put(peerStorageKey, blob)
+	}, func() {})
+}
+
+// FetchPeerStorage returns the opaque blob a peer asked us to hold on to. If
+// we don't hold a blob for the peer, nil is returned.
+func (d *DB) FetchPeerStorage(pubkey route.Vertex) ([]byte, error) {
+	var blob []byte
+	err := kvdb.View(d, func(tx kvdb.RTx) error {
+		peers := tx.ReadBucket(peersBucket)
+
+		peerBucket := peers.NestedReadBucket(pubkey[:])
+		if peerBucket == nil {
+			return nil
+		}
+
+		storedBlob := peerBucket.Get(peerStorageKey)
+		if storedBlob != nil {
+			blob = make([]byte, len(storedBlob))
+			copy(blob, storedBlob)
+		}
+
+		return nil
+	}, func() {
+		blob = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return blob, nil
+}
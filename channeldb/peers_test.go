@@ -48,3 +48,32 @@ func TestFlapCount(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, peer2FlapCount, count)
 }
+
+// TestPeerStorage tests that the blobs peers ask us to hold on to are stored
+// and replaced.
+func TestPeerStorage(t *testing.T) {
+	db, cleanup, err := MakeTestDB()
+	require.NoError(t, err)
+	defer cleanup()
+
+	// We don't hold a blob for a peer we have no records for.
+	blob, err := db.FetchPeerStorage(testPub)
+	require.NoError(t, err)
+	require.Nil(t, blob)
+
+	require.NoError(t, db.PutPeerStorage(testPub, []byte{1, 2, 3}))
+	blob, err = db.FetchPeerStorage(testPub)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, blob)
+
+	// A new blob replaces the previous one.
+	require.NoError(t, db.PutPeerStorage(testPub, []byte{4, 5}))
+	blob, err = db.FetchPeerStorage(testPub)
+	require.NoError(t, err)
+	require.Equal(t, []byte{4, 5}, blob)
+
+	// The blobs of other peers are kept apart.
+	blob, err = db.FetchPeerStorage(route.Vertex{2, 2, 2})
+	require.NoError(t, err)
+	require.Nil(t, blob)
+}
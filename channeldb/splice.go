@@ -0,0 +1,584 @@
+package channeldb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/kvdb"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// pendingSpliceKey stores the state of a splice that has been signed
+	// but whose splice transaction hasn't been locked in yet.
+	pendingSpliceKey = []byte("pending-splice-key")
+
+	// spliceCommitmentsBucket is a sub-bucket of a channel's bucket that
+	// stores the variants of the commitments of both parties that spend
+	// the funding output of a pending splice, keyed by party and
+	// commitment height.
+	spliceCommitmentsBucket = []byte("splice-commitments")
+
+	// ErrNoPendingSplice is returned when no pending splice is found for
+	// a channel.
+	ErrNoPendingSplice = fmt.Errorf("no pending splice found")
+
+	// ErrNoSpliceCommitment is returned when the variant of a commitment
+	// that spends the funding output of a pending splice isn't found.
+	ErrNoSpliceCommitment = fmt.Errorf("no splice commitment found")
+)
+
+const (
+	// outPointLen is the length of a serialized outpoint.
+	outPointLen = 36
+)
+
+// PendingSplice houses the state of a splice whose splice transaction has
+// been signed, but not yet locked in by both parties. Until the splice is
+// completed, the channel continues to use the commitments that spend its
+// current funding output, but every new commitment is signed for the new
+// funding output as well.
+type PendingSplice struct {
+	// SpliceTx is the splice transaction. It spends the current funding
+	// output of the channel and creates the new one. The witnesses of the
+	// transaction are only populated if we're the initiator of the
+	// splice.
+	SpliceTx *wire.MsgTx
+
+	// FundingOutpoint is the outpoint of the new funding output created
+	// by the splice transaction.
+	FundingOutpoint wire.OutPoint
+
+	// Capacity is the capacity of the channel after the splice.
+	Capacity btcutil.Amount
+
+	// BroadcastHeight is the height at which the splice transaction was
+	// signed. It's used as a height hint when waiting for the splice
+	// transaction to confirm.
+	BroadcastHeight uint32
+
+	// LocalCommitment is our commitment that spends the new funding
+	// output, at the height at which the splice was signed.
+	LocalCommitment ChannelCommitment
+
+	// RemoteCommitment is the remote party's commitment that spends the
+	// new funding output, at the height at which the splice was signed.
+	RemoteCommitment ChannelCommitment
+
+	// LocalDelta and RemoteDelta are the amounts added to or removed from
+	// the balance of the local and remote party respectively by the
+	// splice.
+	LocalDelta  btcutil.Amount
+	RemoteDelta btcutil.Amount
+
+	// Locked is true once the splice transaction has confirmed, and we've
+	// sent our splice_locked message to the remote party.
+	Locked bool
+}
+
+// ActiveFundingOutpoint returns the outpoint of the output that currently
+// funds the channel. This differs from the FundingOutpoint, which remains the
+// identifier of the channel, once the channel has been spliced.
+func (c *OpenChannel) ActiveFundingOutpoint() wire.OutPoint {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.activeFundingOutpoint()
+}
+
+// activeFundingOutpoint returns the outpoint of the output that currently
+// funds the channel.
+//
+// NOTE: The channel's mutex MUST be held when calling this method.
+func (c *OpenChannel) activeFundingOutpoint() wire.OutPoint {
+	if c.spliceOutpoint == (wire.OutPoint{}) {
+		return c.FundingOutpoint
+	}
+
+	return c.spliceOutpoint
+}
+
+// IsSpliced returns true if the funding output of the channel has been
+// replaced by a splice.
+func (c *OpenChannel) IsSpliced() bool {
+	c.RLock()
+	defer c.RUnlock()
+
+	return c.spliceOutpoint != wire.OutPoint{}
+}
+
+// MarkSplicePending persists the state of a splice whose splice transaction
+// has been signed. This MUST be called before our signature for the current
+// funding output is released, as the splice transaction can confirm from
+// then on.
+func (c *OpenChannel) MarkSplicePending(splice *PendingSplice) error {
+	c.Lock()
+	defer c.Unlock()
+
+	var b bytes.Buffer
+	if err := serializePendingSplice(&b, splice); err != nil {
+		return err
+	}
+
+	return kvdb.Update(c.Db, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		// Any commitments of a previous splice are removed, as the
+		// commitments of the new splice start at the current heights.
+		err = chanBucket.DeleteNestedBucket(spliceCommitmentsBucket)
+		if err != nil && err != kvdb.ErrBucketNotFound {
+			return err
+		}
+		commitBucket, err := chanBucket.CreateBucket(
+			spliceCommitmentsBucket,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = putSpliceCommitment(
+			commitBucket, &splice.LocalCommitment, true,
+		)
+		if err != nil {
+			return err
+		}
+		err = putSpliceCommitment(
+			commitBucket, &splice.RemoteCommitment, false,
+		)
+		if err != nil {
+			return err
+		}
+
+		return chanBucket.Put(pendingSpliceKey, b.Bytes())
+	}, func() {})
+}
+
+// MarkSpliceLocked records that the splice transaction of the pending splice
+// has confirmed, and that we're about to send our splice_locked message.
+func (c *OpenChannel) MarkSpliceLocked() error {
+	c.Lock()
+	defer c.Unlock()
+
+	return kvdb.Update(c.Db, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		spliceBytes := chanBucket.Get(pendingSpliceKey)
+		if spliceBytes == nil {
+			return ErrNoPendingSplice
+		}
+
+		splice, err := deserializePendingSplice(
+			bytes.NewReader(spliceBytes),
+		)
+		if err != nil {
+			return err
+		}
+		splice.Locked = true
+
+		var b bytes.Buffer
+		if err := serializePendingSplice(&b, splice); err != nil {
+			return err
+		}
+
+		return chanBucket.Put(pendingSpliceKey, b.Bytes())
+	}, func() {})
+}
+
+// AddSpliceCommitment persists the variant of a new commitment that spends
+// the funding output of the pending splice. The local flag indicates whether
+// it's our commitment or the remote party's. Our own commitments must carry
+// the signatures of the remote party.
+func (c *OpenChannel) AddSpliceCommitment(commit *ChannelCommitment,
+	local bool) error {
+
+	c.Lock()
+	defer c.Unlock()
+
+	return kvdb.Update(c.Db, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		commitBucket := chanBucket.NestedReadWriteBucket(
+			spliceCommitmentsBucket,
+		)
+		if commitBucket == nil {
+			return ErrNoPendingSplice
+		}
+
+		return putSpliceCommitment(commitBucket, commit, local)
+	}, func() {})
+}
+
+// PendingSplice returns the pending splice of the channel. If the channel has
+// no pending splice, ErrNoPendingSplice is returned.
+func (c *OpenChannel) PendingSplice() (*PendingSplice, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	var splice *PendingSplice
+	err := kvdb.View(c.Db, func(tx kvdb.RTx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		switch err {
+		case nil:
+		case ErrNoChanDBExists, ErrNoActiveChannels, ErrChannelNotFound:
+			return ErrNoPendingSplice
+		default:
+			return err
+		}
+
+		spliceBytes := chanBucket.Get(pendingSpliceKey)
+		if spliceBytes == nil {
+			return ErrNoPendingSplice
+		}
+
+		splice, err = deserializePendingSplice(
+			bytes.NewReader(spliceBytes),
+		)
+
+		return err
+	}, func() {
+		splice = nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return splice, nil
+}
+
+// FindSpliceCommitment returns the variant of the commitment at the given
+// height that spends the funding output of the pending splice. The local flag
+// indicates whether it's our commitment or the remote party's. If there's no
+// such variant, ErrNoSpliceCommitment is returned.
+func (c *OpenChannel) FindSpliceCommitment(height uint64,
+	local bool) (*ChannelCommitment, error) {
+
+	c.RLock()
+	defer c.RUnlock()
+
+	var commit ChannelCommitment
+	err := kvdb.View(c.Db, func(tx kvdb.RTx) error {
+		chanBucket, err := fetchChanBucket(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		commitBucket := chanBucket.NestedReadBucket(
+			spliceCommitmentsBucket,
+		)
+		if commitBucket == nil {
+			return ErrNoSpliceCommitment
+		}
+
+		commit, err = fetchSpliceCommitment(commitBucket, height, local)
+
+		return err
+	}, func() {
+		commit = ChannelCommitment{}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &commit, nil
+}
+
+// CompleteSplice applies the pending splice of the channel. From then on, the
+// channel is funded by the output created by the splice transaction, and the
+// variants of the current commitments that spend it become the current
+// commitments of the channel. The revoked commitments of the remote party
+// that have been signed since the splice are replaced in the revocation log
+// as well, as only their variants can still be broadcast.
+func (c *OpenChannel) CompleteSplice() error {
+	c.Lock()
+	defer c.Unlock()
+
+	var (
+		splice       *PendingSplice
+		localCommit  ChannelCommitment
+		remoteCommit ChannelCommitment
+	)
+	err := kvdb.Update(c.Db, func(tx kvdb.RwTx) error {
+		chanBucket, err := fetchChanBucketRw(
+			tx, c.IdentityPub, &c.FundingOutpoint, c.ChainHash,
+		)
+		if err != nil {
+			return err
+		}
+
+		spliceBytes := chanBucket.Get(pendingSpliceKey)
+		if spliceBytes == nil {
+			return ErrNoPendingSplice
+		}
+
+		splice, err = deserializePendingSplice(
+			bytes.NewReader(spliceBytes),
+		)
+		if err != nil {
+			return err
+		}
+
+		channel, err := fetchOpenChannel(chanBucket, &c.FundingOutpoint)
+		if err != nil {
+			return err
+		}
+
+		commitBucket := chanBucket.NestedReadWriteBucket(
+			spliceCommitmentsBucket,
+		)
+		if commitBucket == nil {
+			return ErrNoSpliceCommitment
+		}
+
+		localCommit, err = fetchSpliceCommitment(
+			commitBucket, channel.LocalCommitment.CommitHeight,
+			true,
+		)
+		if err != nil {
+			return err
+		}
+		remoteHeight := channel.RemoteCommitment.CommitHeight
+		remoteCommit, err = fetchSpliceCommitment(
+			commitBucket, remoteHeight, false,
+		)
+		if err != nil {
+			return err
+		}
+
+		// If we've signed a new commitment for the remote party that
+		// hasn't been revoked yet, we'll swap it for its variant so
+		// it's the one that becomes the remote commitment once the
+		// remote party revokes its current one.
+		if diffBytes := chanBucket.Get(commitDiffKey); diffBytes != nil {
+			diff, err := deserializeCommitDiff(
+				bytes.NewReader(diffBytes),
+			)
+			if err != nil {
+				return err
+			}
+
+			diff.Commitment, err = fetchSpliceCommitment(
+				commitBucket, diff.Commitment.CommitHeight,
+				false,
+			)
+			if err != nil {
+				return err
+			}
+
+			var b bytes.Buffer
+			if err := serializeCommitDiff(&b, diff); err != nil {
+				return err
+			}
+			err = chanBucket.Put(commitDiffKey, b.Bytes())
+			if err != nil {
+				return err
+			}
+		}
+
+		// The remote commitments revoked since the splice was signed
+		// have variants that can be broadcast once the splice
+		// transaction confirms, so they replace the revoked
+		// commitments in the revocation log.
+		logBucket, err := chanBucket.CreateBucketIfNotExists(
+			revocationLogBucket,
+		)
+		if err != nil {
+			return err
+		}
+		startHeight := splice.RemoteCommitment.CommitHeight
+		for height := startHeight; height < remoteHeight; height++ {
+			commit, err := fetchSpliceCommitment(
+				commitBucket, height, false,
+			)
+			if err != nil {
+				return err
+			}
+
+			err = appendChannelLogEntry(logBucket, &commit)
+			if err != nil {
+				return err
+			}
+		}
+
+		channel.Capacity = splice.Capacity
+		channel.spliceOutpoint = splice.FundingOutpoint
+		if err := putChanInfo(chanBucket, channel); err != nil {
+			return err
+		}
+
+		err = putChanCommitment(chanBucket, &localCommit, true)
+		if err != nil {
+			return err
+		}
+		err = putChanCommitment(chanBucket, &remoteCommit, false)
+		if err != nil {
+			return err
+		}
+
+		err = chanBucket.DeleteNestedBucket(spliceCommitmentsBucket)
+		if err != nil {
+			return err
+		}
+
+		return chanBucket.Delete(pendingSpliceKey)
+	}, func() {
+		splice = nil
+		localCommit = ChannelCommitment{}
+		remoteCommit = ChannelCommitment{}
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Capacity = splice.Capacity
+	c.spliceOutpoint = splice.FundingOutpoint
+	c.LocalCommitment = localCommit
+	c.RemoteCommitment = remoteCommit
+
+	return nil
+}
+
+// putSpliceCommitment stores the passed splice commitment in the splice
+// commitments bucket of a channel.
+func putSpliceCommitment(commitBucket kvdb.RwBucket, commit *ChannelCommitment,
+	local bool) error {
+
+	var b bytes.Buffer
+	if err := serializeChanCommit(&b, commit); err != nil {
+		return err
+	}
+
+	key := spliceCommitmentKey(commit.CommitHeight, local)
+	return commitBucket.Put(key[:], b.Bytes())
+}
+
+// fetchSpliceCommitment fetches the splice commitment of the given party at
+// the given height from the splice commitments bucket of a channel.
+func fetchSpliceCommitment(commitBucket kvdb.RBucket, height uint64,
+	local bool) (ChannelCommitment, error) {
+
+	key := spliceCommitmentKey(height, local)
+	commitBytes := commitBucket.Get(key[:])
+	if commitBytes == nil {
+		return ChannelCommitment{}, ErrNoSpliceCommitment
+	}
+
+	return deserializeChanCommit(bytes.NewReader(commitBytes))
+}
+
+// spliceCommitmentKey returns the key of the splice commitment of the given
+// party at the given height.
+func spliceCommitmentKey(height uint64, local bool) [9]byte {
+	var key [9]byte
+	if local {
+		key[0] = 0x01
+	}
+	byteOrder.PutUint64(key[1:], height)
+
+	return key
+}
+
+// serializePendingSplice serializes a pending splice to the passed writer.
+func serializePendingSplice(w io.Writer, splice *PendingSplice) error {
+	err := WriteElements(w,
+		splice.SpliceTx, splice.FundingOutpoint, splice.Capacity,
+		splice.BroadcastHeight,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := serializeChanCommit(w, &splice.LocalCommitment); err != nil {
+		return err
+	}
+
+	if err := serializeChanCommit(w, &splice.RemoteCommitment); err != nil {
+		return err
+	}
+
+	return WriteElements(w,
+		splice.LocalDelta, splice.RemoteDelta, splice.Locked,
+	)
+}
+
+// deserializePendingSplice deserializes a pending splice from the passed
+// reader.
+func deserializePendingSplice(r io.Reader) (*PendingSplice, error) {
+	splice := &PendingSplice{}
+	err := ReadElements(r,
+		&splice.SpliceTx, &splice.FundingOutpoint, &splice.Capacity,
+		&splice.BroadcastHeight,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	splice.LocalCommitment, err = deserializeChanCommit(r)
+	if err != nil {
+		return nil, err
+	}
+
+	splice.RemoteCommitment, err = deserializeChanCommit(r)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ReadElements(r,
+		&splice.LocalDelta, &splice.RemoteDelta, &splice.Locked,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return splice, nil
+}
+
+// makeSpliceOutpointRecord creates a TLV record for the funding outpoint of
+// a spliced channel.
+func makeSpliceOutpointRecord(op *wire.OutPoint) tlv.Record {
+	return tlv.MakeStaticRecord(
+		spliceOutpointType, op, outPointLen, encodeOutpoint,
+		decodeOutpoint,
+	)
+}
+
+// encodeOutpoint is a TLV encoder for wire.OutPoint.
+func encodeOutpoint(w io.Writer, val interface{}, _ *[8]byte) error {
+	if op, ok := val.(*wire.OutPoint); ok {
+		return writeOutpoint(w, op)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "wire.OutPoint")
+}
+
+// decodeOutpoint is a TLV decoder for wire.OutPoint.
+func decodeOutpoint(r io.Reader, val interface{}, _ *[8]byte,
+	l uint64) error {
+
+	if op, ok := val.(*wire.OutPoint); ok && l == outPointLen {
+		return readOutpoint(r, op)
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "wire.OutPoint", l, outPointLen)
+}
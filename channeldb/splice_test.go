@@ -0,0 +1,150 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChannelSplice tests that a pending splice is persisted and that the
+// channel uses the new funding output once the splice has been completed,
+// along with the variants of the commitments signed in the meantime.
+func TestChannelSplice(t *testing.T) {
+	t.Parallel()
+
+	cdb, cleanUp, err := MakeTestDB()
+	require.NoError(t, err, "unable to make test database")
+	defer cleanUp()
+
+	state := createTestChannel(t, cdb, openChannelOption())
+	chanPoint := state.FundingOutpoint
+	require.False(t, state.IsSpliced())
+	require.Equal(t, state.FundingOutpoint, state.ActiveFundingOutpoint())
+
+	_, err = state.PendingSplice()
+	require.ErrorIs(t, err, ErrNoPendingSplice)
+
+	// Create a splice that adds funds to the channel, spending the
+	// current funding output.
+	spliceTx := wire.NewMsgTx(2)
+	spliceTx.AddTxIn(wire.NewTxIn(&state.FundingOutpoint, []byte{}, nil))
+	spliceTx.AddTxOut(wire.NewTxOut(
+		int64(state.Capacity+btcutil.SatoshiPerBitcoin), []byte{0x00},
+	))
+
+	localCommit := state.LocalCommitment
+	localCommit.LocalBalance += btcutil.SatoshiPerBitcoin * 1000
+	localCommit.CommitTx = spliceTx
+	remoteCommit := state.RemoteCommitment
+	remoteCommit.RemoteBalance += btcutil.SatoshiPerBitcoin * 1000
+	remoteCommit.CommitTx = spliceTx
+
+	splice := &PendingSplice{
+		SpliceTx: spliceTx,
+		FundingOutpoint: wire.OutPoint{
+			Hash:  spliceTx.TxHash(),
+			Index: 0,
+		},
+		Capacity:         state.Capacity + btcutil.SatoshiPerBitcoin,
+		BroadcastHeight:  100,
+		LocalCommitment:  localCommit,
+		RemoteCommitment: remoteCommit,
+		LocalDelta:       btcutil.SatoshiPerBitcoin,
+	}
+	require.NoError(t, state.MarkSplicePending(splice))
+	require.NoError(t, state.MarkSpliceLocked())
+
+	dbSplice, err := state.PendingSplice()
+	require.NoError(t, err)
+	require.Equal(t, splice.SpliceTx.TxHash(), dbSplice.SpliceTx.TxHash())
+	require.Equal(t, splice.FundingOutpoint, dbSplice.FundingOutpoint)
+	require.Equal(t, splice.Capacity, dbSplice.Capacity)
+	require.Equal(t, splice.BroadcastHeight, dbSplice.BroadcastHeight)
+	require.Equal(t, splice.LocalDelta, dbSplice.LocalDelta)
+	require.Zero(t, dbSplice.RemoteDelta)
+	require.True(t, dbSplice.Locked)
+	assertCommitmentEqual(
+		t, &splice.LocalCommitment, &dbSplice.LocalCommitment,
+	)
+	assertCommitmentEqual(
+		t, &splice.RemoteCommitment, &dbSplice.RemoteCommitment,
+	)
+
+	// Advance the remote commitment chain while the splice is pending.
+	// The variant of the new remote commitment is persisted before the
+	// commitment is signed.
+	nextRemoteCommit := state.RemoteCommitment
+	nextRemoteCommit.CommitHeight++
+	commitDiff := &CommitDiff{
+		Commitment: nextRemoteCommit,
+		CommitSig: &lnwire.CommitSig{
+			ChanID:    lnwire.NewChanIDFromOutPoint(&chanPoint),
+			CommitSig: wireSig,
+			ExtraData: make([]byte, 0),
+		},
+		LogUpdates:        []LogUpdate{},
+		OpenedCircuitKeys: []CircuitKey{},
+		ClosedCircuitKeys: []CircuitKey{},
+	}
+	nextSpliceCommit := remoteCommit
+	nextSpliceCommit.CommitHeight++
+	nextSpliceCommit.RemoteBalance += btcutil.SatoshiPerBitcoin
+	require.NoError(t, state.AddSpliceCommitment(&nextSpliceCommit, false))
+	require.NoError(t, state.AppendRemoteCommitChain(commitDiff))
+
+	// Completing the splice now must pick the variant of the pending
+	// remote commitment once it's locked in.
+	spliceDiff, err := state.RemoteCommitChainTip()
+	require.NoError(t, err)
+	assertCommitmentEqual(t, &nextRemoteCommit, &spliceDiff.Commitment)
+
+	state.RemoteCurrentRevocation = state.RemoteNextRevocation
+	newPriv, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	state.RemoteNextRevocation = newPriv.PubKey()
+
+	fwdPkg := NewFwdPkg(
+		state.ShortChanID(), remoteCommit.CommitHeight, nil, nil,
+	)
+	require.NoError(t, state.AdvanceCommitChainTail(fwdPkg, nil))
+
+	// Completing the splice should switch the channel over to the new
+	// funding output, while the channel point remains unchanged. The
+	// current remote commitment and the revoked one are replaced by their
+	// variants.
+	require.NoError(t, state.CompleteSplice())
+	require.True(t, state.IsSpliced())
+	require.Equal(t, splice.FundingOutpoint, state.ActiveFundingOutpoint())
+
+	_, err = state.PendingSplice()
+	require.ErrorIs(t, err, ErrNoPendingSplice)
+
+	channels, err := cdb.FetchOpenChannels(state.IdentityPub)
+	require.NoError(t, err, "unable to fetch open channels")
+	require.Len(t, channels, 1)
+
+	dbChannel := channels[0]
+	require.Equal(t, chanPoint, dbChannel.FundingOutpoint)
+	require.Equal(t, splice.FundingOutpoint,
+		dbChannel.ActiveFundingOutpoint())
+	require.Equal(t, splice.Capacity, dbChannel.Capacity)
+	assertCommitmentEqual(
+		t, &splice.LocalCommitment, &dbChannel.LocalCommitment,
+	)
+	assertCommitmentEqual(
+		t, &nextSpliceCommit, &dbChannel.RemoteCommitment,
+	)
+
+	revokedCommit, err := dbChannel.FindPreviousState(
+		remoteCommit.CommitHeight,
+	)
+	require.NoError(t, err)
+	assertCommitmentEqual(t, &splice.RemoteCommitment, revokedCommit)
+
+	// A second attempt to complete the splice must fail.
+	require.ErrorIs(t, state.CompleteSplice(), ErrNoPendingSplice)
+}
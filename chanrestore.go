@@ -5,7 +5,7 @@ import (
 	"math"
 	"net"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/lightningnetwork/lnd/chanbackup"
@@ -335,3 +335,73 @@ func (s *server) ConnectPeer(nodePub *btcec.PublicKey, addrs []net.Addr) error {
 	return fmt.Errorf("unable to connect to peer %x for SCB restore",
 		nodePub.SerializeCompressed())
 }
+
+// distributePeerBackup hands the new multi-channel backup to all connected
+// peers that store it on our behalf.
+func (s *server) distributePeerBackup(backup chanbackup.PackedMulti) {
+	// The backup is the same for all peers, so if it is too large to be
+	// stored, none of them will get it. As that leaves our channels
+	// without a peer backup, we warn about it once.
+	if len(backup) > lnwire.MaxPeerStorageBytes {
+		srvrLog.Warnf("Not sending channel backup to peers: %v byte "+
+			"backup exceeds peer storage maximum of %v bytes",
+			len(backup), lnwire.MaxPeerStorageBytes)
+		return
+	}
+
+	for _, p := range s.Peers() {
+		if err := p.SendPeerBackup(backup); err != nil {
+			srvrLog.Tracef("Not sending backup to %x: %v",
+				p.PubKey(), err)
+		}
+	}
+}
+
+// handlePeerStorageRetrieval restores our channels from the multi-channel
+// backup a peer returned to us, if we were asked to do so when the wallet was
+// restored. Only the first backup that can be decrypted is restored, as the
+// backup of any peer covers all of our channels at the time it was sent.
+func (s *server) handlePeerStorageRetrieval(peer [33]byte, blob []byte) error {
+	if !s.chansToRestore.RestoreFromPeers {
+		return nil
+	}
+
+	s.peerRestoreMtx.Lock()
+	if s.peerRestored {
+		s.peerRestoreMtx.Unlock()
+		return nil
+	}
+	s.peerRestored = true
+	s.peerRestoreMtx.Unlock()
+
+	// Restoring the channels reconnects to the peers we have channels
+	// with, which disconnects this peer first, so we can't restore from
+	// within its read handler.
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		srvrLog.Infof("Restoring channels from backup of peer %x", peer)
+
+		chanRestorer := &chanDBRestorer{
+			db:         s.chanStateDB,
+			secretKeys: s.cc.KeyRing,
+			chainArb:   s.chainArb,
+		}
+		err := chanbackup.UnpackAndRecoverMulti(
+			chanbackup.PackedMulti(blob), s.cc.KeyRing,
+			chanRestorer, s,
+		)
+		if err != nil {
+			srvrLog.Errorf("Unable to restore channels from "+
+				"backup of peer %x: %v", peer, err)
+
+			// We'll try the backup of the next peer instead.
+			s.peerRestoreMtx.Lock()
+			s.peerRestored = false
+			s.peerRestoreMtx.Unlock()
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var sendCustomCommand = cli.Command{
+	Name:     "sendcustom",
+	Category: "Peers",
+	Usage:    "Send a custom p2p wire message to a peer",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "peer",
+			Usage: "the hex-encoded public key of the peer",
+		},
+		cli.Uint64Flag{
+			Name:  "type",
+			Usage: "the message type, must be >= 32768",
+		},
+		cli.StringFlag{
+			Name:  "data",
+			Usage: "the hex-encoded message payload",
+		},
+	},
+	Action: actionDecorator(sendCustom),
+}
+
+func sendCustom(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	peer, err := hex.DecodeString(ctx.String("peer"))
+	if err != nil {
+		return fmt.Errorf("unable to decode peer: %v", err)
+	}
+
+	msgType := ctx.Uint64("type")
+
+	data, err := hex.DecodeString(ctx.String("data"))
+	if err != nil {
+		return fmt.Errorf("unable to decode data: %v", err)
+	}
+
+	_, err = client.SendCustomMessage(
+		ctxc,
+		&lnrpc.SendCustomMessageRequest{
+			Peer: peer,
+			Type: uint32(msgType),
+			Data: data,
+		},
+	)
+
+	return err
+}
+
+var subscribeCustomCommand = cli.Command{
+	Name:     "subscribecustom",
+	Category: "Peers",
+	Usage: "Subscribe to incoming custom p2p wire messages from all " +
+		"peers",
+	Action: actionDecorator(subscribeCustom),
+}
+
+func subscribeCustom(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	stream, err := client.SubscribeCustomMessages(
+		ctxc,
+		&lnrpc.SubscribeCustomMessagesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		fmt.Printf("Received from peer %x: type=%d, data=%x\n",
+			msg.Peer, msg.Type, msg.Data)
+	}
+}
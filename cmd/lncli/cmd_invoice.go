@@ -71,6 +71,13 @@ var addInvoiceCommand = cli.Command{
 			Usage: "creates an AMP invoice. If true, preimage " +
 				"should not be set.",
 		},
+		cli.BoolFlag{
+			Name: "blind",
+			Usage: "[experimental] hide this node behind blinded " +
+				"paths introduced by its channel peers " +
+				"instead of revealing it to the payer; " +
+				"disables routing hints",
+		},
 	},
 	Action: actionDecorator(addInvoice),
 }
@@ -123,8 +130,9 @@ func addInvoice(ctx *cli.Context) error {
 		DescriptionHash: descHash,
 		FallbackAddr:    ctx.String("fallback_addr"),
 		Expiry:          ctx.Int64("expiry"),
-		Private:         ctx.Bool("private"),
+		Private:         ctx.Bool("private") && !ctx.Bool("blind"),
 		IsAmp:           ctx.Bool("amp"),
+		Blind:           ctx.Bool("blind"),
 	}
 
 	resp, err := client.AddInvoice(ctxc, invoice)
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/routing"
 	"github.com/urfave/cli"
 )
 
@@ -13,6 +16,11 @@ var setCfgCommand = cli.Command{
 	the probability that payment routes will succeed.
 	`,
 	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "estimator",
+			Usage: "the probability estimator to use, either " +
+				"'apriori' or 'bimodal'",
+		},
 		cli.DurationFlag{
 			Name: "halflife",
 			Usage: "the amount of time taken to restore a node " +
@@ -38,6 +46,24 @@ var setCfgCommand = cli.Command{
 			Usage: "the amount of time to wait after a failure " +
 				"before raising failure amount",
 		},
+		cli.Float64Flag{
+			Name: "bimodalweight",
+			Usage: "the degree to which the bimodal estimator " +
+				"takes the results of the other channels of " +
+				"a node into account, expressed as value in " +
+				"[0;1]",
+		},
+		cli.Uint64Flag{
+			Name: "bimodalscale",
+			Usage: "the liquidity in msat that is typically " +
+				"left on the sides of a channel, used by " +
+				"the bimodal estimator",
+		},
+		cli.DurationFlag{
+			Name: "bimodaldecaytime",
+			Usage: "the time after which the bimodal estimator " +
+				"has mostly forgotten previous results",
+		},
 	},
 	Action: actionDecorator(setCfg),
 }
@@ -58,6 +84,14 @@ func setCfg(ctx *cli.Context) error {
 
 	var haveValue bool
 
+	if ctx.IsSet("estimator") {
+		haveValue = true
+		err := setEstimator(resp.Config, ctx.String("estimator"))
+		if err != nil {
+			return err
+		}
+	}
+
 	if ctx.IsSet("halflife") {
 		haveValue = true
 		resp.Config.HalfLifeSeconds = uint64(ctx.Duration(
@@ -87,6 +121,33 @@ func setCfg(ctx *cli.Context) error {
 		).Seconds())
 	}
 
+	if ctx.IsSet("bimodalweight") || ctx.IsSet("bimodalscale") ||
+		ctx.IsSet("bimodaldecaytime") {
+
+		bimodal := routerrpc.MissionControlConfig_BIMODAL
+		if resp.Config.Model != bimodal {
+			return fmt.Errorf("bimodal parameters can only be " +
+				"set for the bimodal estimator")
+		}
+	}
+
+	if ctx.IsSet("bimodalweight") {
+		haveValue = true
+		resp.Config.Bimodal.NodeWeight = ctx.Float64("bimodalweight")
+	}
+
+	if ctx.IsSet("bimodalscale") {
+		haveValue = true
+		resp.Config.Bimodal.ScaleMsat = ctx.Uint64("bimodalscale")
+	}
+
+	if ctx.IsSet("bimodaldecaytime") {
+		haveValue = true
+		resp.Config.Bimodal.DecayTime = uint64(ctx.Duration(
+			"bimodaldecaytime",
+		).Seconds())
+	}
+
 	if !haveValue {
 		return cli.ShowCommandHelp(ctx, "setmccfg")
 	}
@@ -98,3 +159,40 @@ func setCfg(ctx *cli.Context) error {
 	)
 	return err
 }
+
+// setEstimator switches the config to the estimator of the given name. The
+// parameters of an estimator we switch to start out with their defaults.
+func setEstimator(cfg *routerrpc.MissionControlConfig, name string) error {
+	switch name {
+	case routing.AprioriEstimatorName:
+		if cfg.Model == routerrpc.MissionControlConfig_APRIORI {
+			return nil
+		}
+
+		halfLife := routing.DefaultPenaltyHalfLife
+		cfg.Model = routerrpc.MissionControlConfig_APRIORI
+		cfg.HalfLifeSeconds = uint64(halfLife.Seconds())
+		cfg.HopProbability = float32(
+			routing.DefaultAprioriHopProbability,
+		)
+		cfg.Weight = float32(routing.DefaultAprioriWeight)
+
+	case routing.BimodalEstimatorName:
+		if cfg.Model == routerrpc.MissionControlConfig_BIMODAL {
+			return nil
+		}
+
+		decayTime := routing.DefaultBimodalDecayTime
+		cfg.Model = routerrpc.MissionControlConfig_BIMODAL
+		cfg.Bimodal = &routerrpc.BimodalParameters{
+			NodeWeight: routing.DefaultBimodalNodeWeight,
+			ScaleMsat:  uint64(routing.DefaultBimodalScaleMsat),
+			DecayTime:  uint64(decayTime.Seconds()),
+		}
+
+	default:
+		return fmt.Errorf("unknown estimator %v", name)
+	}
+
+	return nil
+}
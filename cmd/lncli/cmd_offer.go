@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/urfave/cli"
+)
+
+var addOfferCommand = cli.Command{
+	Name:     "addoffer",
+	Category: "Invoices",
+	Usage:    "Create a reusable offer.",
+	Description: `
+	Create a static offer that can be paid repeatedly, for example from a
+	printed QR code. For each payment, the payer requests a fresh invoice
+	for the offer from this node over onion messages, which requires onion
+	messages to be enabled.
+	`,
+	ArgsUsage: "description [amt_msat]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "description",
+			Usage: "a description of what payments are for",
+		},
+		cli.Uint64Flag{
+			Name: "amt_msat",
+			Usage: "the amount to pay in millisatoshis, if not " +
+				"set the payer chooses the amount",
+		},
+		cli.StringFlag{
+			Name:  "issuer",
+			Usage: "(optional) a name of the issuer shown to payers",
+		},
+		cli.Uint64Flag{
+			Name: "expiry",
+			Usage: "(optional) the number of seconds after which " +
+				"the offer expires, if not set the offer " +
+				"doesn't expire",
+		},
+	},
+	Action: actionDecorator(addOffer),
+}
+
+func addOffer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+
+	var description string
+	switch {
+	case ctx.IsSet("description"):
+		description = ctx.String("description")
+	case args.Present():
+		description = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("description argument missing")
+	}
+
+	amtMsat := ctx.Uint64("amt_msat")
+	if !ctx.IsSet("amt_msat") && args.Present() {
+		var err error
+		amtMsat, err = strconv.ParseUint(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode amt_msat: %v", err)
+		}
+	}
+
+	resp, err := client.AddOffer(ctxc, &lnrpc.AddOfferRequest{
+		Description: description,
+		AmountMsat:  amtMsat,
+		Issuer:      ctx.String("issuer"),
+		Expiry:      ctx.Uint64("expiry"),
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var decodeOfferCommand = cli.Command{
+	Name:        "decodeoffer",
+	Category:    "Invoices",
+	Usage:       "Decode an offer.",
+	Description: "Decode the passed offer and verify its signature.",
+	ArgsUsage:   "offer",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "offer",
+			Usage: "the bech32 encoded offer",
+		},
+	},
+	Action: actionDecorator(decodeOffer),
+}
+
+func decodeOffer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var offer string
+	switch {
+	case ctx.IsSet("offer"):
+		offer = ctx.String("offer")
+	case ctx.Args().Present():
+		offer = ctx.Args().First()
+	default:
+		return fmt.Errorf("offer argument missing")
+	}
+
+	resp, err := client.DecodeOffer(ctxc, &lnrpc.DecodeOfferRequest{
+		Offer: offer,
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var payOfferCommand = cli.Command{
+	Name:     "payoffer",
+	Category: "Payments",
+	Usage:    "Pay an offer over lightning.",
+	Description: `
+	Request a fresh invoice for the offer from its issuer over onion
+	messages and pay it.
+	`,
+	ArgsUsage: "offer",
+	Flags: append(paymentFlags(),
+		cli.StringFlag{
+			Name:  "offer",
+			Usage: "the bech32 encoded offer",
+		},
+		cli.Int64Flag{
+			Name: "amt",
+			Usage: "(optional) number of satoshis to pay, required " +
+				"for offers without amount",
+		},
+	),
+	Action: actionDecorator(payOffer),
+}
+
+func payOffer(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+
+	var offer string
+	switch {
+	case ctx.IsSet("offer"):
+		offer = ctx.String("offer")
+	case args.Present():
+		offer = args.First()
+	default:
+		return fmt.Errorf("offer argument missing")
+	}
+
+	req := &routerrpc.SendPaymentRequest{
+		Offer:             offer,
+		Amt:               ctx.Int64("amt"),
+		DestCustomRecords: make(map[uint64][]byte),
+	}
+
+	// Without an explicit amount we pay the amount of the offer, which we
+	// also base the fee limit on.
+	if !ctx.IsSet("amt") {
+		decoded, err := client.DecodeOffer(
+			ctxc, &lnrpc.DecodeOfferRequest{Offer: offer},
+		)
+		if err != nil {
+			return err
+		}
+
+		amtMsat := lnwire.MilliSatoshi(decoded.AmountMsat)
+		if amtMsat%1000 == 0 {
+			req.Amt = int64(amtMsat.ToSatoshis())
+		} else {
+			req.AmtMsat = int64(amtMsat)
+		}
+	}
+
+	return sendPaymentRequest(ctx, req)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/urfave/cli"
+)
+
+var sendOnionMessageCommand = cli.Command{
+	Name:     "sendonionmessage",
+	Category: "Peers",
+	Usage:    "Send an onion message along a path of nodes",
+	Description: `
+	Send an onion message along the given path of nodes. The first node of
+	the path must be a connected peer, while the last node of the path is
+	the recipient of the message. Only the recipient learns the records of
+	the message.
+	`,
+	Flags: []cli.Flag{
+		cli.StringSliceFlag{
+			Name: "path",
+			Usage: "the hex-encoded public key of a node of " +
+				"the path, can be specified multiple times " +
+				"in the order of the path",
+		},
+		cli.StringFlag{
+			Name: "data",
+			Usage: "the records to deliver to the recipient. The " +
+				"required format is: <record_id>=<hex_value>," +
+				"<record_id>=<hex_value>,.. Record ids start " +
+				"from 64.",
+		},
+	},
+	Action: actionDecorator(sendOnionMessage),
+}
+
+func sendOnionMessage(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if !ctx.IsSet("path") {
+		return errors.New("path must be specified")
+	}
+
+	var path [][]byte
+	for _, node := range ctx.StringSlice("path") {
+		nodeBytes, err := hex.DecodeString(node)
+		if err != nil {
+			return fmt.Errorf("unable to decode node: %v", err)
+		}
+		path = append(path, nodeBytes)
+	}
+
+	records := make(map[uint64][]byte)
+	if data := ctx.String("data"); data != "" {
+		for _, r := range strings.Split(data, ",") {
+			kv := strings.Split(r, "=")
+			if len(kv) != 2 {
+				return errors.New("invalid data format: " +
+					"multiple equal signs in record")
+			}
+
+			recordID, err := strconv.ParseUint(kv[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid data format: %v",
+					err)
+			}
+
+			hexValue, err := hex.DecodeString(kv[1])
+			if err != nil {
+				return fmt.Errorf("invalid data format: %v",
+					err)
+			}
+
+			records[recordID] = hexValue
+		}
+	}
+
+	_, err := client.SendOnionMessage(
+		ctxc,
+		&lnrpc.SendOnionMessageRequest{
+			Path:    path,
+			Records: records,
+		},
+	)
+
+	return err
+}
+
+var subscribeOnionMessagesCommand = cli.Command{
+	Name:     "subscribeonionmessages",
+	Category: "Peers",
+	Usage:    "Subscribe to incoming onion messages destined for us",
+	Action:   actionDecorator(subscribeOnionMessages),
+}
+
+func subscribeOnionMessages(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	stream, err := client.SubscribeOnionMessages(
+		ctxc,
+		&lnrpc.SubscribeOnionMessagesRequest{},
+	)
+	if err != nil {
+		return err
+	}
+
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		printRespJSON(msg)
+	}
+}
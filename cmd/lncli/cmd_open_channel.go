@@ -14,9 +14,9 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
 	"github.com/urfave/cli"
@@ -112,6 +112,21 @@ var openChannelCommand = cli.Command{
 				"channel and sending the remote party funds, " +
 				"but done all in one step",
 		},
+		cli.Int64Flag{
+			Name: "remote_funding_amt",
+			Usage: "the number of satoshis to request the " +
+				"remote side to contribute to the channel, " +
+				"which opens a dual funded channel; the " +
+				"remote side may contribute less, or " +
+				"nothing at all",
+		},
+		cli.Uint64Flag{
+			Name: "lease_duration",
+			Usage: "the number of blocks for which to lease the " +
+				"funds the remote side contributes, at the " +
+				"lease rates it advertises; requires " +
+				"remote_funding_amt to be set",
+		},
 		cli.BoolFlag{
 			Name:  "block",
 			Usage: "block and wait until the channel is fully open",
@@ -210,6 +225,19 @@ var openChannelCommand = cli.Command{
 				"propose to the remote peer (%q, %q)",
 				channelTypeTweakless, channelTypeAnchors),
 		},
+		cli.BoolFlag{
+			Name: "zero_conf",
+			Usage: "(optional) whether a zero-conf channel open " +
+				"should be attempted, requires the anchors " +
+				"channel type",
+		},
+		cli.BoolFlag{
+			Name: "scid_alias",
+			Usage: "(optional) whether an option-scid-alias " +
+				"channel type open should be attempted, " +
+				"requires a private channel and an explicit " +
+				"channel type",
+		},
 	},
 	Action: actionDecorator(openChannel),
 }
@@ -315,6 +343,8 @@ func openChannel(ctx *cli.Context) error {
 		}
 	}
 
+	req.RemoteFundingAmt = ctx.Int64("remote_funding_amt")
+	req.LeaseDuration = uint32(ctx.Uint64("lease_duration"))
 	req.Private = ctx.Bool("private")
 
 	// Parse the channel type and map it to its RPC representation.
@@ -330,6 +360,9 @@ func openChannel(ctx *cli.Context) error {
 		return fmt.Errorf("unsupported channel type %v", channelType)
 	}
 
+	req.ZeroConf = ctx.Bool("zero_conf")
+	req.ScidAlias = ctx.Bool("scid_alias")
+
 	// PSBT funding is a more involved, interactive process that is too
 	// large to also fit into this already long function.
 	if ctx.Bool("psbt") {
@@ -14,7 +14,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/jedib0t/go-pretty/table"
 	"github.com/jedib0t/go-pretty/text"
 	"github.com/lightninglabs/protobuf-hex-display/jsonpb"
@@ -99,6 +99,27 @@ var (
 		Usage: "if set to true, then a random payment address will " +
 			"be generated to enable re-use of an AMP invoice",
 	}
+
+	trampolineNodeFlag = cli.StringFlag{
+		Name: "trampoline_node",
+		Usage: "pubkey of the trampoline node to route the payment " +
+			"over, which finds the route to the destination on " +
+			"our behalf",
+	}
+
+	trampolineFeeFlag = cli.Int64Flag{
+		Name: "trampoline_fee_msat",
+		Usage: "the fee in milli-satoshis the trampoline node may " +
+			"spend on forwarding the payment, counting towards " +
+			"the fee limit",
+	}
+
+	trampolineCltvDeltaFlag = cli.UintFlag{
+		Name: "trampoline_cltv_delta",
+		Usage: "the time-lock delta the trampoline node may spend on " +
+			"forwarding the payment",
+		Value: routerrpc.DefaultTrampolineCltvDelta,
+	}
 )
 
 // paymentFlags returns common flags for sendpayment and payinvoice.
@@ -144,7 +165,8 @@ func paymentFlags() []cli.Flag {
 		},
 		dataFlag, inflightUpdatesFlag, maxPartsFlag, jsonFlag,
 		maxShardSizeSatFlag, maxShardSizeMsatFlag, ampFlag,
-		ampReuseFlag,
+		ampReuseFlag, trampolineNodeFlag, trampolineFeeFlag,
+		trampolineCltvDeltaFlag,
 	}
 }
 
@@ -437,6 +459,19 @@ func sendPaymentRequest(ctx *cli.Context,
 		}
 		req.LastHopPubkey = lastHop[:]
 	}
+	if ctx.IsSet(trampolineNodeFlag.Name) {
+		trampolineNode, err := route.NewVertexFromStr(
+			ctx.String(trampolineNodeFlag.Name),
+		)
+		if err != nil {
+			return err
+		}
+		req.TrampolineNode = trampolineNode[:]
+		req.TrampolineFeeMsat = ctx.Int64(trampolineFeeFlag.Name)
+		req.TrampolineCltvDelta = uint32(
+			ctx.Uint(trampolineCltvDeltaFlag.Name),
+		)
+	}
 
 	req.CltvLimit = int32(ctx.Int(cltvLimitFlag.Name))
 
@@ -7,7 +7,7 @@ import (
 	"path"
 	"strings"
 
-	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/urfave/cli"
 	"gopkg.in/macaroon.v2"
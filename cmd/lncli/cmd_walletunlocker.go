@@ -10,8 +10,10 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/lightninglabs/protobuf-hex-display/jsonpb"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
 	"github.com/lightningnetwork/lnd/walletunlocker"
 	"github.com/urfave/cli"
 )
@@ -64,6 +66,10 @@ var createCommand = cli.Command{
 	Channel Backups. Only one of the three parameters will be accepted. See
 	the restorechanbackup command for further details w.r.t the format
 	accepted.
+
+	If the --restore_from_peers flag is set instead, the channels are
+	restored from the encrypted backup that is returned by the first
+	connected peer that stored it on our behalf.
 	`,
 	Flags: []cli.Flag{
 		cli.StringFlag{
@@ -80,6 +86,12 @@ var createCommand = cli.Command{
 			Name:  "multi_file",
 			Usage: "the path to a multi-channel back up file",
 		},
+		cli.BoolFlag{
+			Name: "restore_from_peers",
+			Usage: "restore the channels from the backup " +
+				"returned by the peers that store it on " +
+				"our behalf",
+		},
 		statelessInitFlag,
 		saveToFlag,
 	},
@@ -197,6 +209,12 @@ func create(ctx *cli.Context) error {
 		}
 	}
 
+	restoreFromPeers := ctx.Bool("restore_from_peers")
+	if restoreFromPeers && chanBackups != nil {
+		return fmt.Errorf("cannot restore from peers and from static " +
+			"channel backups at the same time")
+	}
+
 	// Should the daemon be initialized stateless? Then we expect an answer
 	// with the admin macaroon later. Because the --save_to is related to
 	// stateless init, it doesn't make sense to be set on its own.
@@ -379,6 +397,7 @@ mnemonicCheck:
 		RecoveryWindow:                     recoveryWindow,
 		ChannelBackups:                     chanBackups,
 		StatelessInit:                      statelessInit,
+		RestoreFromPeers:                   restoreFromPeers,
 	}
 	response, err := client.InitWallet(ctxc, req)
 	if err != nil {
@@ -541,6 +560,189 @@ func unlock(ctx *cli.Context) error {
 	return nil
 }
 
+var createWatchOnlyCommand = cli.Command{
+	Name:      "createwatchonly",
+	Category:  "Startup",
+	ArgsUsage: "accounts-json-file",
+	Usage: "Initialize a watch-only wallet after starting lnd for the " +
+		"first time.",
+	Description: `
+	The createwatchonly command is used to initialize an lnd wallet from
+	scratch for the very first time, in watch-only mode. Watch-only means,
+	there will be no private keys in lnd's wallet. This is only useful in
+	combination with a remote signer and requires lnd to be started with
+	--remotesigner.enable.
+
+	This is an interactive command that takes a JSON file as its first and
+	only argument. The JSON is in the same format as the output of the
+	'lncli wallet accounts list' command of the remote signer. This makes
+	it easy to initialize the remote signer with the seed, then export the
+	extended public account keys (xpubs) to import the watch-only wallet.
+
+	Example JSON (non-mandatory or ignored fields are omitted):
+	{
+	    "accounts": [
+	        {
+	            "extended_public_key": "tpubDDXEYWvG...",
+	            "derivation_path": "m/84'/1'/0'"
+	        },
+	        {
+	            "extended_public_key": "tpubDCN5FdVF...",
+	            "derivation_path": "m/1017'/1'/0'"
+	        }
+	    ]
+	}
+	`,
+	Flags: []cli.Flag{
+		statelessInitFlag,
+		saveToFlag,
+	},
+	Action: actionDecorator(createWatchOnly),
+}
+
+func createWatchOnly(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getWalletUnlockerClient(ctx)
+	defer cleanUp()
+
+	if ctx.NArg() != 1 {
+		return cli.ShowCommandHelp(ctx, "createwatchonly")
+	}
+
+	// Should the daemon be initialized stateless? Then we expect an answer
+	// with the admin macaroon later. Because the --save_to is related to
+	// stateless init, it doesn't make sense to be set on its own.
+	statelessInit := ctx.Bool(statelessInitFlag.Name)
+	if !statelessInit && ctx.IsSet(saveToFlag.Name) {
+		return fmt.Errorf("cannot set save_to parameter without " +
+			"stateless_init")
+	}
+
+	jsonFile := lncfg.CleanAndExpandPath(ctx.Args().First())
+	jsonBytes, err := ioutil.ReadFile(jsonFile)
+	if err != nil {
+		return fmt.Errorf("error reading JSON from file %v: %v",
+			jsonFile, err)
+	}
+
+	jsonAccts := &walletrpc.ListAccountsResponse{}
+	err = jsonpb.UnmarshalString(string(jsonBytes), jsonAccts)
+	if err != nil {
+		return fmt.Errorf("error parsing JSON: %v", err)
+	}
+	if len(jsonAccts.Accounts) == 0 {
+		return fmt.Errorf("cannot import empty account list")
+	}
+
+	rpcAccounts := make(
+		[]*lnrpc.WatchOnlyAccount, 0, len(jsonAccts.Accounts),
+	)
+	var mkfp []byte
+	for _, acct := range jsonAccts.Accounts {
+		// The default imported account of each key scope has no xpub
+		// and can't be imported.
+		if acct.ExtendedPublicKey == "" {
+			continue
+		}
+
+		purpose, coinType, index, err := parseDerivationPath(
+			acct.DerivationPath,
+		)
+		if err != nil {
+			return fmt.Errorf("unable to parse derivation path "+
+				"of account %v: %v", acct.Name, err)
+		}
+
+		// All accounts must be derived from the same master key.
+		if len(acct.MasterKeyFingerprint) > 0 {
+			if mkfp != nil &&
+				!bytes.Equal(mkfp, acct.MasterKeyFingerprint) {
+
+				return fmt.Errorf("accounts have different " +
+					"master key fingerprints")
+			}
+			mkfp = acct.MasterKeyFingerprint
+		}
+
+		rpcAccounts = append(rpcAccounts, &lnrpc.WatchOnlyAccount{
+			Purpose:  purpose,
+			CoinType: coinType,
+			Account:  index,
+			Xpub:     acct.ExtendedPublicKey,
+		})
+	}
+
+	walletPassword, err := capturePassword(
+		"Input wallet password: ", false, walletunlocker.ValidatePassword,
+	)
+	if err != nil {
+		return err
+	}
+
+	// We can't recover any on-chain funds in watch-only mode, so we only
+	// ask for the birthday to know where to start scanning from.
+	birthday, err := askBirthdayTimestamp()
+	if err != nil {
+		return err
+	}
+
+	initResp, err := client.InitWallet(ctxc, &lnrpc.InitWalletRequest{
+		WalletPassword: walletPassword,
+		WatchOnly: &lnrpc.WatchOnly{
+			MasterKeyBirthdayTimestamp: birthday,
+			MasterKeyFingerprint:       mkfp,
+			Accounts:                   rpcAccounts,
+		},
+		StatelessInit: statelessInit,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\nlnd successfully initialized!")
+
+	if statelessInit {
+		return storeOrPrintAdminMac(ctx, initResp.AdminMacaroon)
+	}
+
+	return nil
+}
+
+// parseDerivationPath parses a path in the form of m/purpose'/coin_type'/
+// account' and returns the three hardened path elements as non-hardened
+// numbers.
+func parseDerivationPath(path string) (uint32, uint32, uint32, error) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "m/") {
+		return 0, 0, 0, fmt.Errorf("path must start with m/")
+	}
+
+	parts := strings.Split(strings.TrimPrefix(path, "m/"), "/")
+	if len(parts) != 3 {
+		return 0, 0, 0, fmt.Errorf("path must have exactly three " +
+			"elements")
+	}
+
+	var elements [3]uint32
+	for idx, part := range parts {
+		if !strings.HasSuffix(part, "'") {
+			return 0, 0, 0, fmt.Errorf("element %q is not "+
+				"hardened", part)
+		}
+
+		value, err := strconv.ParseUint(
+			strings.TrimSuffix(part, "'"), 10, 31,
+		)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid element %q: %v",
+				part, err)
+		}
+		elements[idx] = uint32(value)
+	}
+
+	return elements[0], elements[1], elements[2], nil
+}
+
 var changePasswordCommand = cli.Command{
 	Name:     "changepassword",
 	Category: "Startup",
@@ -137,7 +137,8 @@ var newAddressCommand = cli.Command{
 	Description: `
 	Generate a wallet new address. Address-types has to be one of:
 	    - p2wkh:  Pay to witness key hash
-	    - np2wkh: Pay to nested witness key hash`,
+	    - np2wkh: Pay to nested witness key hash
+	    - p2tr:   Pay to taproot pubkey`,
 	Action: actionDecorator(newAddress),
 }
 
@@ -159,9 +160,11 @@ func newAddress(ctx *cli.Context) error {
 		addrType = lnrpc.AddressType_WITNESS_PUBKEY_HASH
 	case "np2wkh":
 		addrType = lnrpc.AddressType_NESTED_PUBKEY_HASH
+	case "p2tr":
+		addrType = lnrpc.AddressType_TAPROOT_PUBKEY
 	default:
 		return fmt.Errorf("invalid address type %v, support address type "+
-			"are: p2wkh and np2wkh", stringAddrType)
+			"are: p2wkh, np2wkh and p2tr", stringAddrType)
 	}
 
 	client, cleanUp := getClient(ctx)
@@ -706,6 +709,12 @@ var closeChannelCommand = cli.Command{
 	if an upfront shutdown address has not already been set. If neither are
 	set the funds will be delivered to a new wallet address.
 
+	If both peers support rbf coop closes, the closing transaction of a
+	channel that is being closed cooperatively can be replaced with one
+	paying a higher fee (--bump). The fee rate of the replacement is set via
+	either the --conf_target or --sat_per_vbyte arguments, and its fee is
+	paid out of the local balance of the channel.
+
 	To view which funding_txids/output_indexes can be used for a channel close,
 	see the channel_point values within the listchannels command output.
 	The format for a channel_point is 'funding_txid:output_index'.`,
@@ -754,6 +763,12 @@ var closeChannelCommand = cli.Command{
 				"be used if an upfront shutdown address is not " +
 				"already set",
 		},
+		cli.BoolFlag{
+			Name: "bump",
+			Usage: "replace the closing transaction of a " +
+				"cooperative close in progress with one " +
+				"paying a higher fee out of the local balance",
+		},
 	},
 	Action: actionDecorator(closeChannel),
 }
@@ -790,6 +805,7 @@ func closeChannel(ctx *cli.Context) error {
 		TargetConf:      int32(ctx.Int64("conf_target")),
 		SatPerVbyte:     ctx.Uint64(feeRateFlag),
 		DeliveryAddress: ctx.String("delivery_addr"),
+		Bump:            ctx.Bool("bump"),
 	}
 
 	// After parsing the request, we'll spin up a goroutine that will
@@ -1180,6 +1196,290 @@ func abandonChannel(ctx *cli.Context) error {
 	return nil
 }
 
+var spliceChannelCommand = cli.Command{
+	Name:     "splicechannel",
+	Category: "Channels",
+	Usage:    "Add funds to or remove funds from an existing channel.",
+	Description: `
+	Splice funds into or out of an existing channel without closing it. Funds spliced into the channel are taken from the wallet. Funds
+	spliced out of the channel (--out) are sent to the passed delivery
+	address, or to a new wallet address if none is set. The fees of a
+	splice out are paid from the local balance of the channel.
+
+	The channel keeps forwarding payments once both peers signed the
+	splice transaction. Only private channels can be spliced. Splicing is
+	experimental and requires both peers to run with --protocol.splicing.
+
+	To view which funding_txids/output_indexes can be used for a splice,
+	see the channel_point values within the listchannels command output.
+	The format for a channel_point is 'funding_txid:output_index'.`,
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the number of satoshis to splice in or out",
+		},
+		cli.BoolFlag{
+			Name:  "out",
+			Usage: "splice the amount out of the channel",
+		},
+		cli.StringFlag{
+			Name: "delivery_addr",
+			Usage: "(optional) an address to deliver the funds " +
+				"spliced out of the channel to",
+		},
+		cli.Int64Flag{
+			Name: "conf_target",
+			Usage: "(optional) the number of blocks that the " +
+				"transaction *should* confirm in, will be " +
+				"used for fee estimation",
+		},
+		cli.Int64Flag{
+			Name: "sat_per_vbyte",
+			Usage: "(optional) a manual fee expressed in " +
+				"sat/vbyte that should be used when crafting " +
+				"the transaction",
+		},
+	},
+	Action: actionDecorator(spliceChannel),
+}
+
+func spliceChannel(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	// Show command help if no arguments and flags were provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "splicechannel")
+		return nil
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	amt := ctx.Int64("amt")
+	if amt <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+	if ctx.Bool("out") {
+		amt = -amt
+	} else if ctx.IsSet("delivery_addr") {
+		return fmt.Errorf("delivery address can only be set when " +
+			"splicing out")
+	}
+
+	req := &lnrpc.SpliceChannelRequest{
+		ChannelPoint:    channelPoint,
+		Amount:          amt,
+		DeliveryAddress: ctx.String("delivery_addr"),
+		TargetConf:      int32(ctx.Int64("conf_target")),
+		SatPerVbyte:     ctx.Uint64("sat_per_vbyte"),
+	}
+
+	resp, err := client.SpliceChannel(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var quiesceChannelCommand = cli.Command{
+	Name:     "quiescechannel",
+	Category: "Channels",
+	Usage:    "Pause all updates of an existing channel.",
+	Description: `
+	Quiesce an existing channel, which pauses all updates of it. Pending
+	updates are committed to first, after which neither peer adds any htlcs
+	or fee updates to the channel until it is resumed with unquiescechannel
+	or the peers reconnect. If the channel stays quiescent for longer than a
+	minute, the peer is disconnected. The command returns once the channel
+	is quiescent, and requires both peers to run with --protocol.quiescence.
+
+	The format for a channel_point is 'funding_txid:output_index'.`,
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+	},
+	Action: actionDecorator(quiesceChannel),
+}
+
+func quiesceChannel(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	// Show command help if no arguments and flags were provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "quiescechannel")
+		return nil
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.QuiesceChannelRequest{
+		ChannelPoint: channelPoint,
+	}
+
+	resp, err := client.QuiesceChannel(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var unquiesceChannelCommand = cli.Command{
+	Name:     "unquiescechannel",
+	Category: "Channels",
+	Usage:    "Resume updates of a quiesced channel.",
+	Description: `
+	Resume normal operation of a channel that was quiesced with
+	quiescechannel. The command fails if the channel isn't quiescent, if the
+	remote peer initiated the quiescence, or if a commitment type upgrade is
+	in progress.
+
+	The format for a channel_point is 'funding_txid:output_index'.`,
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+	},
+	Action: actionDecorator(unquiesceChannel),
+}
+
+func unquiesceChannel(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	// Show command help if no arguments and flags were provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "unquiescechannel")
+		return nil
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.UnquiesceChannelRequest{
+		ChannelPoint: channelPoint,
+	}
+
+	resp, err := client.UnquiesceChannel(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var upgradeChannelCommand = cli.Command{
+	Name:     "upgradechannel",
+	Category: "Channels",
+	Usage:    "Upgrade the commitment type of an existing channel.",
+	Description: `
+	Upgrade the commitment type of an existing channel in place, without
+	closing it. The channel is quiesced first, and resumes normal operation
+	once both peers committed to the new commitment type. The command
+	requires both peers to run with --protocol.dynamic-commitments.
+
+	The format for a channel_point is 'funding_txid:output_index'.`,
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+		cli.StringFlag{
+			Name: "channel_type",
+			Usage: fmt.Sprintf("the commitment type to "+
+				"upgrade the channel to (%q, %q)",
+				channelTypeTweakless, channelTypeAnchors),
+		},
+	},
+	Action: actionDecorator(upgradeChannel),
+}
+
+func upgradeChannel(ctx *cli.Context) error {
+	ctxc := getContext()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	// Show command help if no arguments and flags were provided.
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "upgradechannel")
+		return nil
+	}
+
+	channelPoint, err := parseChannelPoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.UpgradeChannelRequest{
+		ChannelPoint: channelPoint,
+	}
+
+	channelType := ctx.String("channel_type")
+	switch channelType {
+	case channelTypeTweakless:
+		req.CommitmentType = lnrpc.CommitmentType_STATIC_REMOTE_KEY
+	case channelTypeAnchors:
+		req.CommitmentType = lnrpc.CommitmentType_ANCHORS
+	default:
+		return fmt.Errorf("unsupported channel type %v", channelType)
+	}
+
+	resp, err := client.UpgradeChannel(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
 // parseChannelPoint parses a funding txid and output index from the command
 // line. Both named options as well as unnamed parameters are supported.
 func parseChannelPoint(ctx *cli.Context) (*lnrpc.ChannelPoint, error) {
@@ -2175,6 +2475,21 @@ var updateChannelPolicyCommand = cli.Command{
 				"to all forwarded HTLCs. If unset, the max HTLC " +
 				"is left unchanged.",
 		},
+		cli.Int64Flag{
+			Name: "inbound_base_fee_msat",
+			Usage: "the base fee in milli-satoshis that will be " +
+				"charged for each HTLC that arrives over the " +
+				"channel. A negative value is a discount, a " +
+				"positive value a surcharge. If neither inbound " +
+				"flag is set, the inbound fee is left unchanged.",
+		},
+		cli.Int64Flag{
+			Name: "inbound_fee_rate_ppm",
+			Usage: "the fee rate in parts per million that will " +
+				"be charged proportionally based on the value " +
+				"of each HTLC that arrives over the channel. " +
+				"May be negative to give a discount.",
+		},
 		cli.StringFlag{
 			Name: "chan_point",
 			Usage: "The channel whose fee policy should be " +
@@ -2296,6 +2611,29 @@ func updateChannelPolicy(ctx *cli.Context) error {
 		req.MinHtlcMsatSpecified = true
 	}
 
+	if ctx.IsSet("inbound_base_fee_msat") ||
+		ctx.IsSet("inbound_fee_rate_ppm") {
+
+		inboundBaseFee := ctx.Int64("inbound_base_fee_msat")
+		if inboundBaseFee < math.MinInt32 ||
+			inboundBaseFee > math.MaxInt32 {
+
+			return errors.New("inbound_base_fee_msat out of range")
+		}
+
+		inboundFeeRate := ctx.Int64("inbound_fee_rate_ppm")
+		if inboundFeeRate < math.MinInt32 ||
+			inboundFeeRate > math.MaxInt32 {
+
+			return errors.New("inbound_fee_rate_ppm out of range")
+		}
+
+		req.InboundFee = &lnrpc.InboundFee{
+			BaseFeeMsat: int32(inboundBaseFee),
+			FeeRatePpm:  int32(inboundFeeRate),
+		}
+	}
+
 	if chanPoint != nil {
 		req.Scope = &lnrpc.PolicyUpdateRequest_ChanPoint{
 			ChanPoint: chanPoint,
@@ -190,6 +190,13 @@ var addHoldInvoiceCommand = cli.Command{
 				"private channels in order to assist the " +
 				"payer in reaching you",
 		},
+		cli.BoolFlag{
+			Name: "blind",
+			Usage: "[experimental] hide this node behind blinded " +
+				"paths introduced by its channel peers " +
+				"instead of revealing it to the payer; " +
+				"disables routing hints",
+		},
 	},
 	Action: actionDecorator(addHoldInvoice),
 }
@@ -240,7 +247,8 @@ func addHoldInvoice(ctx *cli.Context) error {
 		DescriptionHash: descHash,
 		FallbackAddr:    ctx.String("fallback_addr"),
 		Expiry:          ctx.Int64("expiry"),
-		Private:         ctx.Bool("private"),
+		Private:         ctx.Bool("private") && !ctx.Bool("blind"),
+		Blind:           ctx.Bool("blind"),
 	}
 
 	resp, err := client.AddHoldInvoice(ctxc, invoice)
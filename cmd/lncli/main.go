@@ -12,7 +12,7 @@ import (
 	"strings"
 	"syscall"
 
-	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
@@ -324,6 +324,7 @@ func main() {
 	}
 	app.Commands = []cli.Command{
 		createCommand,
+		createWatchOnlyCommand,
 		unlockCommand,
 		changePasswordCommand,
 		newAddressCommand,
@@ -338,6 +339,10 @@ func main() {
 		closeChannelCommand,
 		closeAllChannelsCommand,
 		abandonChannelCommand,
+		spliceChannelCommand,
+		quiesceChannelCommand,
+		unquiesceChannelCommand,
+		upgradeChannelCommand,
 		listPeersCommand,
 		walletBalanceCommand,
 		channelBalanceCommand,
@@ -380,6 +385,13 @@ func main() {
 		versionCommand,
 		profileSubCommand,
 		getStateCommand,
+		sendCustomCommand,
+		subscribeCustomCommand,
+		sendOnionMessageCommand,
+		subscribeOnionMessagesCommand,
+		addOfferCommand,
+		decodeOfferCommand,
+		payOfferCommand,
 	}
 
 	// Add any extra commands determined by build flags.
@@ -24,6 +24,8 @@ func wtclientCommands() []cli.Command {
 				getTowerCommand,
 				statsCommand,
 				policyCommand,
+				backlogCommand,
+				rebalanceCommand,
 			},
 		},
 	}
@@ -307,3 +309,80 @@ func policy(ctx *cli.Context) error {
 	printRespJSON(resp)
 	return nil
 }
+
+var backlogCommand = cli.Command{
+	Name: "backlog",
+	Usage: "Display the backups that have yet to be delivered to each " +
+		"registered watchtower, along with the watchtower's health.",
+	Action: actionDecorator(backlog),
+}
+
+func backlog(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if the number of arguments/flags
+	// is not what we expect.
+	if ctx.NArg() > 0 || ctx.NumFlags() > 0 {
+		return cli.ShowCommandHelp(ctx, "backlog")
+	}
+
+	client, cleanUp := getWtclient(ctx)
+	defer cleanUp()
+
+	req := &wtclientrpc.ListTowerBacklogsRequest{}
+	resp, err := client.ListTowerBacklogs(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var rebalanceCommand = cli.Command{
+	Name: "rebalance",
+	Usage: "Move the pending backups of a watchtower to a session with " +
+		"a different watchtower.",
+	Description: "Backups that have not been committed to the sessions " +
+		"of the given watchtower yet are moved to a session with a " +
+		"different watchtower, which is also used for all subsequent " +
+		"backups. If no watchtower is given, the watchtower of the " +
+		"sessions currently in use is rebalanced.",
+	ArgsUsage: "[pubkey]",
+	Action:    actionDecorator(rebalance),
+}
+
+func rebalance(ctx *cli.Context) error {
+	ctxc := getContext()
+
+	// Display the command's help message if the number of arguments/flags
+	// is not what we expect.
+	if ctx.NArg() > 1 || ctx.NumFlags() > 0 {
+		return cli.ShowCommandHelp(ctx, "rebalance")
+	}
+
+	// The optional argument is the hex-encoded public key of the
+	// watchtower to move backups away from.
+	var pubKey []byte
+	if ctx.NArg() == 1 {
+		var err error
+		pubKey, err = hex.DecodeString(ctx.Args().Get(0))
+		if err != nil {
+			return fmt.Errorf("invalid public key: %v", err)
+		}
+	}
+
+	client, cleanUp := getWtclient(ctx)
+	defer cleanUp()
+
+	req := &wtclientrpc.RebalanceTowersRequest{
+		Pubkey: pubKey,
+	}
+	resp, err := client.RebalanceTowers(ctxc, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net"
 	"os"
 	"os/user"
@@ -18,8 +19,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
-	"github.com/btcsuite/btcutil"
 	flags "github.com/jessevdk/go-flags"
 	"github.com/lightninglabs/neutrino"
 	"github.com/lightningnetwork/lnd/autopilot"
@@ -314,6 +315,10 @@ type Config struct {
 	Color                         string        `long:"color" description:"The color of the node in hex format (i.e. '#3399FF'). Used to customize node appearance in intelligence services"`
 	MinChanSize                   int64         `long:"minchansize" description:"The smallest channel size (in satoshis) that we should accept. Incoming channels smaller than this will be rejected"`
 	MaxChanSize                   int64         `long:"maxchansize" description:"The largest channel size (in satoshis) that we should accept. Incoming channels larger than this will be rejected"`
+	MaxDualFundContribution       int64         `long:"maxdualfundcontribution" description:"The largest amount (in satoshis) that we'll contribute to channels opened by peers that request dual funding. If unset, we won't contribute to any incoming channels"`
+	LeaseFeeBase                  uint32        `long:"leasefeebase" description:"The fixed fee (in satoshis) we charge for leasing out our contribution to a dual funded channel"`
+	LeaseFeeBasis                 uint16        `long:"leasefeebasis" description:"The proportional fee (in basis points of the leased amount) we charge for leasing out our contribution to a dual funded channel"`
+	MaxLeaseDuration              uint32        `long:"maxleaseduration" description:"The longest duration (in blocks) we lease out our contribution to dual funded channels for. If set, our lease rates are advertised in our node announcement. If unset, we don't lease out any funds"`
 	CoopCloseTargetConfs          uint32        `long:"coop-close-target-confs" description:"The target number of blocks that a cooperative channel close transaction should confirm in. This is used to estimate the fee to use as the lower bound during fee negotiation for the channel closure."`
 
 	ChannelCommitInterval  time.Duration `long:"channel-commit-interval" description:"The maximum time that is allowed to pass between receiving a channel state update and signing the next commitment. Setting this to a longer duration allows for more efficient channel operations at the cost of latency."`
@@ -330,6 +335,10 @@ type Config struct {
 
 	RejectHTLC bool `long:"rejecthtlc" description:"If true, lnd will not forward any HTLCs that are meant as onward payments. This option will still allow lnd to send HTLCs and receive HTLCs but lnd won't be used as a hop."`
 
+	RequireInterceptor bool `long:"requireinterceptor" description:"Whether to always intercept HTLCs, even if no stream is attached. Forwards are then held until an interceptor connects, or until they are failed back because their incoming expiry is too close."`
+
+	InterceptorCltvRejectDelta uint32 `long:"interceptorcltvrejectdelta" description:"The number of blocks before the expiry of an incoming HTLC at which a held forward is failed back automatically to prevent the incoming channel from being force closed."`
+
 	StaggerInitialReconnect bool `long:"stagger-initial-reconnect" description:"If true, will apply a randomized staggering between 0s and 30s when reconnecting to persistent peers on startup. The first 10 reconnections will be attempted instantly, regardless of the flag's value"`
 
 	MaxOutgoingCltvExpiry uint32 `long:"max-cltv-expiry" description:"The maximum number of blocks funds could be locked up for when forwarding payments."`
@@ -380,6 +389,14 @@ type Config struct {
 
 	Cluster *lncfg.Cluster `group:"cluster" namespace:"cluster"`
 
+	RemoteSigner *lncfg.RemoteSigner `group:"remotesigner" namespace:"remotesigner"`
+
+	Trampoline *lncfg.Trampoline `group:"trampoline" namespace:"trampoline"`
+
+	Sweeper *lncfg.Sweeper `group:"sweeper" namespace:"sweeper"`
+
+	FeeEstimator *lncfg.FeeEstimator `group:"feeestimator" namespace:"feeestimator"`
+
 	// LogWriter is the root logger that all of the daemon's subloggers are
 	// hooked up to.
 	LogWriter *build.RotatingLogWriter
@@ -514,6 +531,9 @@ func DefaultConfig() Config {
 		Watchtower: &lncfg.Watchtower{
 			TowerDir: defaultTowerDir,
 		},
+		WtClient: &lncfg.WtClient{
+			TowerFailoverTimeout: lncfg.DefaultTowerFailoverTimeout,
+		},
 		HealthChecks: &lncfg.HealthCheckConfig{
 			ChainCheck: &lncfg.CheckConfig{
 				Interval: defaultChainInterval,
@@ -550,11 +570,17 @@ func DefaultConfig() Config {
 		LogWriter:               build.NewRotatingLogWriter(),
 		DB:                      lncfg.DefaultDB(),
 		Cluster:                 lncfg.DefaultCluster(),
+		RemoteSigner:            lncfg.DefaultRemoteSigner(),
+		Trampoline:              lncfg.DefaultTrampoline(),
+		Sweeper:                 lncfg.DefaultSweeper(),
+		FeeEstimator:            lncfg.DefaultFeeEstimator(),
 		registeredChains:        chainreg.NewChainRegistry(),
 		ActiveNetParams:         chainreg.BitcoinTestNetParams,
 		ChannelCommitInterval:   defaultChannelCommitInterval,
 		ChannelCommitBatchSize:  defaultChannelCommitBatchSize,
 		CoinSelectionStrategy:   defaultCoinSelectionStrategy,
+
+		InterceptorCltvRejectDelta: htlcswitch.DefaultInterceptorCltvRejectDelta,
 	}
 }
 
@@ -706,6 +732,12 @@ func ValidateConfig(cfg Config, usageMessage string,
 	cfg.WalletUnlockPasswordFile = CleanAndExpandPath(
 		cfg.WalletUnlockPasswordFile,
 	)
+	cfg.RemoteSigner.MacaroonPath = CleanAndExpandPath(
+		cfg.RemoteSigner.MacaroonPath,
+	)
+	cfg.RemoteSigner.TLSCertPath = CleanAndExpandPath(
+		cfg.RemoteSigner.TLSCertPath,
+	)
 
 	// Create the lnd directory and all other sub directories if they don't
 	// already exist. This makes sure that directory trees are also created
@@ -799,6 +831,26 @@ func ValidateConfig(cfg Config, usageMessage string,
 		)
 	}
 
+	if cfg.MaxDualFundContribution < 0 {
+		return nil, fmt.Errorf("invalid dual funding contribution %v, "+
+			"must not be negative", cfg.MaxDualFundContribution)
+	}
+
+	// Leased funds are delayed by a CSV delay of at least the lease
+	// duration, and are contributed to dual funded channels.
+	switch {
+	case cfg.MaxLeaseDuration > math.MaxUint16:
+		return nil, fmt.Errorf("invalid max lease duration %v, must "+
+			"not exceed %v", cfg.MaxLeaseDuration, math.MaxUint16)
+
+	case cfg.MaxLeaseDuration > 0 && (cfg.MaxDualFundContribution == 0 ||
+		!cfg.ProtocolOptions.DualFunding()):
+
+		return nil, fmt.Errorf("leasing out funds requires " +
+			"maxdualfundcontribution and protocol.dual-funding " +
+			"to be set")
+	}
+
 	// Don't allow superflous --maxchansize greater than
 	// BOLT 02 soft-limit for non-wumbo channel
 	if !cfg.ProtocolOptions.Wumbo() && cfg.MaxChanSize > int64(MaxFundingAmount) {
@@ -809,6 +861,14 @@ func ValidateConfig(cfg Config, usageMessage string,
 		)
 	}
 
+	// Zero-conf channels are only addressable through their alias short
+	// channel IDs before they confirm, so we require aliases to be enabled
+	// as well.
+	if cfg.ProtocolOptions.ZeroConf() && !cfg.ProtocolOptions.ScidAlias() {
+		return nil, fmt.Errorf("protocol.zero-conf requires " +
+			"protocol.option-scid-alias to be set")
+	}
+
 	// Ensure a valid max channel fee allocation was set.
 	if cfg.MaxChannelFeeAllocation <= 0 || cfg.MaxChannelFeeAllocation > 1 {
 		return nil, fmt.Errorf("invalid max channel fee allocation: "+
@@ -1353,6 +1413,17 @@ func ValidateConfig(cfg Config, usageMessage string,
 		return nil, fmt.Errorf("cannot set noseedbackup and " +
 			"wallet-unlock-password-file at the same time")
 
+	// A remote signing node runs a watch-only wallet that can't be
+	// created from a fresh seed.
+	case cfg.RemoteSigner.Enable && cfg.NoSeedBackup:
+		return nil, fmt.Errorf("cannot set noseedbackup and " +
+			"remotesigner.enable at the same time")
+
+	case cfg.RemoteSigner.Enable && cfg.WalletUnlockAllowCreate:
+		return nil, fmt.Errorf("cannot set " +
+			"wallet-unlock-allow-create and remotesigner.enable " +
+			"at the same time")
+
 	// The "allow-create" flag cannot be set without the auto unlock file.
 	case cfg.WalletUnlockAllowCreate && cfg.WalletUnlockPasswordFile == "":
 		return nil, fmt.Errorf("cannot set wallet-unlock-allow-create " +
@@ -1477,6 +1548,10 @@ func ValidateConfig(cfg Config, usageMessage string,
 		cfg.DB,
 		cfg.Cluster,
 		cfg.HealthChecks,
+		cfg.RemoteSigner,
+		cfg.Trampoline,
+		cfg.Sweeper,
+		cfg.FeeEstimator,
 	)
 	if err != nil {
 		return nil, err
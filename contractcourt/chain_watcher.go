@@ -7,11 +7,12 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/mempool"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -240,8 +241,9 @@ func (c *chainWatcher) Start() error {
 		chanState.FundingOutpoint)
 
 	// First, we'll register for a notification to be dispatched if the
-	// funding output is spent.
-	fundingOut := &chanState.FundingOutpoint
+	// funding output is spent. If the channel has been spliced, this is
+	// the output created by the latest splice transaction.
+	fundingOut := chanState.ActiveFundingOutpoint()
 
 	// As a height hint, we'll try to use the opening height, but if the
 	// channel isn't yet open, then we'll use the height it was broadcast
@@ -251,21 +253,13 @@ func (c *chainWatcher) Start() error {
 		heightHint = chanState.FundingBroadcastHeight
 	}
 
-	localKey := chanState.LocalChanCfg.MultiSigKey.PubKey.SerializeCompressed()
-	remoteKey := chanState.RemoteChanCfg.MultiSigKey.PubKey.SerializeCompressed()
-	multiSigScript, err := input.GenMultiSigScript(
-		localKey, remoteKey,
-	)
-	if err != nil {
-		return err
-	}
-	pkScript, err := input.WitnessScriptHash(multiSigScript)
+	pkScript, err := lnwallet.FundingPkScript(chanState)
 	if err != nil {
 		return err
 	}
 
 	spendNtfn, err := c.cfg.notifier.RegisterSpendNtfn(
-		fundingOut, pkScript, heightHint,
+		&fundingOut, pkScript, heightHint,
 	)
 	if err != nil {
 		return err
@@ -279,6 +273,76 @@ func (c *chainWatcher) Start() error {
 	return nil
 }
 
+// handleSpliceSpend checks whether the funding output of the channel has been
+// spent by its pending splice transaction. If so, the channel remains open,
+// and a new close observer is launched that watches the funding output
+// created by the splice transaction.
+func (c *chainWatcher) handleSpliceSpend(
+	spend *chainntnfs.SpendDetail) (bool, error) {
+
+	chanState := c.cfg.chanState
+	splice, err := chanState.PendingSplice()
+	switch {
+	case err == channeldb.ErrNoPendingSplice:
+		return false, nil
+
+	case err != nil:
+		return false, err
+	}
+
+	if *spend.SpenderTxHash != splice.SpliceTx.TxHash() {
+		return false, nil
+	}
+
+	log.Infof("ChannelPoint(%v) spliced by txid=%v, watching new "+
+		"funding output %v", chanState.FundingOutpoint,
+		spend.SpenderTxHash, splice.FundingOutpoint)
+
+	pkScript, err := lnwallet.FundingPkScript(chanState)
+	if err != nil {
+		return false, err
+	}
+
+	spendNtfn, err := c.cfg.notifier.RegisterSpendNtfn(
+		&splice.FundingOutpoint, pkScript,
+		uint32(spend.SpendingHeight),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	c.wg.Add(1)
+	go c.closeObserver(spendNtfn)
+
+	return true, nil
+}
+
+// refreshSplicedState ensures our view of the channel reflects the splice
+// whose funding output has been spent by the passed spend. The splice may not
+// have been completed yet if the channel was closed before both parties
+// locked it in.
+func (c *chainWatcher) refreshSplicedState(
+	spend *chainntnfs.SpendDetail) error {
+
+	chanState := c.cfg.chanState
+	if spend.SpentOutPoint == nil ||
+		*spend.SpentOutPoint == chanState.FundingOutpoint {
+
+		return nil
+	}
+
+	err := chanState.CompleteSplice()
+	switch {
+	// The splice was already completed through another instance of the
+	// channel, so we'll only need to reload the channel info from disk.
+	case err == channeldb.ErrNoPendingSplice:
+		return chanState.RefreshShortChanID()
+
+	default:
+		return err
+	}
+}
+
 // Stop signals the close observer to gracefully exit.
 func (c *chainWatcher) Stop() error {
 	if !atomic.CompareAndSwapInt32(&c.stopped, 0, 1) {
@@ -352,18 +416,22 @@ func (c *chainWatcher) handleUnknownLocalState(
 	}
 	commitPoint := input.ComputeCommitmentPoint(commitSecret[:])
 
+	// A past state may have been signed before the commitment type of the
+	// channel was upgraded, so we'll use the type of that state.
+	chanType := c.cfg.chanState.CommitChanType(true, broadcastStateNum)
+
 	// Now that we have the commit point, we'll derive the tweaked local
 	// and remote keys for this state. We use our point as only we can
 	// revoke our own commitment.
 	commitKeyRing := lnwallet.DeriveCommitmentKeys(
-		commitPoint, true, c.cfg.chanState.ChanType,
+		commitPoint, true, chanType,
 		&c.cfg.chanState.LocalChanCfg, &c.cfg.chanState.RemoteChanCfg,
 	)
 
 	// With the keys derived, we'll construct the remote script that'll be
 	// present if they have a non-dust balance on the commitment.
 	remoteScript, _, err := lnwallet.CommitScriptToRemote(
-		c.cfg.chanState.ChanType, commitKeyRing.ToRemoteKey,
+		chanType, commitKeyRing.ToRemoteKey,
 	)
 	if err != nil {
 		return false, err
@@ -545,6 +613,26 @@ func (c *chainWatcher) closeObserver(spendNtfn *chainntnfs.SpendEvent) {
 			return
 		}
 
+		// If the funding output was spent by a splice transaction,
+		// then the channel remains open, and we'll watch the funding
+		// output it created instead.
+		spliced, err := c.handleSpliceSpend(commitSpend)
+		if err != nil {
+			log.Errorf("Unable to handle splice spend: %v", err)
+			return
+		}
+		if spliced {
+			return
+		}
+
+		// If the funding output created by a splice was spent, we'll
+		// make sure our view of the channel reflects the splice.
+		if err := c.refreshSplicedState(commitSpend); err != nil {
+			log.Errorf("Unable to refresh spliced channel "+
+				"state: %v", err)
+			return
+		}
+
 		// Otherwise, the remote party might have broadcast a prior
 		// revoked state...!!!
 		commitTxBroadcast := commitSpend.SpendingTx
@@ -598,10 +686,14 @@ func (c *chainWatcher) closeObserver(spendNtfn *chainntnfs.SpendEvent) {
 
 		// Next, we'll check to see if this is a cooperative channel
 		// closure or not. This is characterized by having an input
-		// sequence number that's finalized. This won't happen with
-		// regular commitment transactions due to the state hint
-		// encoding scheme.
-		if commitTxBroadcast.TxIn[0].Sequence == wire.MaxTxInSequenceNum {
+		// sequence number that's finalized, or that signals
+		// replaceability in case of a replaceable cooperative close.
+		// This won't happen with regular commitment transactions due
+		// to the state hint encoding scheme.
+		sequence := commitTxBroadcast.TxIn[0].Sequence
+		if sequence == wire.MaxTxInSequenceNum ||
+			sequence == mempool.MaxRBFSequence {
+
 			// TODO(roasbeef): rare but possible, need itest case
 			// for
 			err := c.dispatchCooperativeClose(commitSpend)
@@ -1095,27 +1187,8 @@ func (c *chainWatcher) dispatchContractBreach(spendEvent *chainntnfs.SpendDetail
 
 	spendHeight := uint32(spendEvent.SpendingHeight)
 
-	// Nil the curve before printing.
-	if retribution.RemoteOutputSignDesc != nil &&
-		retribution.RemoteOutputSignDesc.DoubleTweak != nil {
-		retribution.RemoteOutputSignDesc.DoubleTweak.Curve = nil
-	}
-	if retribution.RemoteOutputSignDesc != nil &&
-		retribution.RemoteOutputSignDesc.KeyDesc.PubKey != nil {
-		retribution.RemoteOutputSignDesc.KeyDesc.PubKey.Curve = nil
-	}
-	if retribution.LocalOutputSignDesc != nil &&
-		retribution.LocalOutputSignDesc.DoubleTweak != nil {
-		retribution.LocalOutputSignDesc.DoubleTweak.Curve = nil
-	}
-	if retribution.LocalOutputSignDesc != nil &&
-		retribution.LocalOutputSignDesc.KeyDesc.PubKey != nil {
-		retribution.LocalOutputSignDesc.KeyDesc.PubKey.Curve = nil
-	}
-
 	log.Debugf("Punishment breach retribution created: %v",
 		newLogClosure(func() string {
-			retribution.KeyRing.CommitPoint.Curve = nil
 			retribution.KeyRing.LocalHtlcKey = nil
 			retribution.KeyRing.RemoteHtlcKey = nil
 			retribution.KeyRing.ToLocalKey = nil
@@ -9,9 +9,9 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/input"
@@ -1108,6 +1108,18 @@ func (c *ChannelArbitrator) sweepAnchors(anchors *lnwallet.AnchorResolutions,
 			return err
 		}
 
+		// The budget for the cpfp is a share of the HTLCs we may lose
+		// if the commitment doesn't confirm before the deadline.
+		deadlineHTLCs, err := c.deadlineHTLCs(htlcs)
+		if err != nil {
+			return err
+		}
+
+		var valueAtStake lnwire.MilliSatoshi
+		for _, htlc := range deadlineHTLCs {
+			valueAtStake += htlc.Amt
+		}
+
 		log.Debugf("ChannelArbitrator(%v): pre-confirmation sweep of "+
 			"anchor of %s commit tx %v", c.cfg.ChanPoint,
 			anchorPath, anchor.CommitAnchor)
@@ -1132,16 +1144,22 @@ func (c *ChannelArbitrator) sweepAnchors(anchors *lnwallet.AnchorResolutions,
 		// Also signal that this is a force sweep, so that the anchor
 		// will be swept even if it isn't economical purely based on the
 		// anchor value.
-		_, err = c.cfg.Sweeper.SweepInput(
-			&anchorInput,
-			sweep.Params{
-				Fee: sweep.FeePreference{
-					ConfTarget: deadline,
-				},
-				Force:          true,
-				ExclusiveGroup: &exclusiveGroup,
+		params := sweep.Params{
+			Fee: sweep.FeePreference{
+				ConfTarget: deadline,
 			},
-		)
+			Force:          true,
+			ExclusiveGroup: &exclusiveGroup,
+		}
+
+		// If there are HTLCs at stake, raise the fee rate as the
+		// deadline approaches so the commitment confirms in time.
+		if valueAtStake > 0 {
+			params.DeadlineHeight = int32(heightHint + deadline)
+			params.Budget = sweepBudget(valueAtStake)
+		}
+
+		_, err = c.cfg.Sweeper.SweepInput(&anchorInput, params)
 		if err != nil {
 			return err
 		}
@@ -1182,23 +1200,16 @@ func (c *ChannelArbitrator) sweepAnchors(anchors *lnwallet.AnchorResolutions,
 	return nil
 }
 
-// findCommitmentDeadline finds the deadline (relative block height) for a
-// commitment transaction by extracting the minimum CLTV from its HTLCs. From
-// our PoV, the deadline is defined to be the smaller of,
-//   - the least CLTV from outgoing HTLCs,  or,
-//   - the least CLTV from incoming HTLCs if the preimage is available.
-//
-// Note: when the deadline turns out to be 0 blocks, we will replace it with 1
-// block because our fee estimator doesn't allow a 0 conf target. This also
-// means we've left behind and should increase our fee to make the transaction
-// confirmed asap.
-func (c *ChannelArbitrator) findCommitmentDeadline(heightHint uint32,
-	htlcs htlcSet) (uint32, error) {
+// deadlineHTLCs returns the HTLCs of the given set that we may lose if the
+// commitment transaction doesn't confirm before they expire. These are the
+// non-dust outgoing HTLCs and the non-dust incoming HTLCs we have the preimage
+// for.
+func (c *ChannelArbitrator) deadlineHTLCs(
+	htlcs htlcSet) ([]channeldb.HTLC, error) {
 
-	deadlineMinHeight := uint32(math.MaxUint32)
+	var deadlineHTLCs []channeldb.HTLC
 
-	// First, iterate through the outgoingHTLCs to find the lowest CLTV
-	// value.
+	// First, iterate through the outgoingHTLCs.
 	for _, htlc := range htlcs.outgoingHTLCs {
 		// Skip if the HTLC is dust.
 		if htlc.OutputIndex < 0 {
@@ -1209,13 +1220,11 @@ func (c *ChannelArbitrator) findCommitmentDeadline(heightHint uint32,
 			continue
 		}
 
-		if htlc.RefundTimeout < deadlineMinHeight {
-			deadlineMinHeight = htlc.RefundTimeout
-		}
+		deadlineHTLCs = append(deadlineHTLCs, htlc)
 	}
 
-	// Then going through the incomingHTLCs, and update the minHeight when
-	// conditions met.
+	// Then going through the incomingHTLCs, and add them when conditions
+	// met.
 	for _, htlc := range htlcs.incomingHTLCs {
 		// Skip if the HTLC is dust.
 		if htlc.OutputIndex < 0 {
@@ -1230,13 +1239,41 @@ func (c *ChannelArbitrator) findCommitmentDeadline(heightHint uint32,
 		// this HTLC.
 		preimageAvailable, err := c.isPreimageAvailable(htlc.RHash)
 		if err != nil {
-			return 0, err
+			return nil, err
 		}
 
 		if !preimageAvailable {
 			continue
 		}
 
+		deadlineHTLCs = append(deadlineHTLCs, htlc)
+	}
+
+	return deadlineHTLCs, nil
+}
+
+// findCommitmentDeadline finds the deadline (relative block height) for a
+// commitment transaction by extracting the minimum CLTV from its HTLCs. From
+// our PoV, the deadline is defined to be the smaller of,
+//   - the least CLTV from outgoing HTLCs,  or,
+//   - the least CLTV from incoming HTLCs if the preimage is available.
+//
+// Note: when the deadline turns out to be 0 blocks, we will replace it with 1
+// block because our fee estimator doesn't allow a 0 conf target. This also
+// means we've left behind and should increase our fee to make the transaction
+// confirmed asap.
+func (c *ChannelArbitrator) findCommitmentDeadline(heightHint uint32,
+	htlcs htlcSet) (uint32, error) {
+
+	deadlineHTLCs, err := c.deadlineHTLCs(htlcs)
+	if err != nil {
+		return 0, err
+	}
+
+	// Find the lowest CLTV value among the HTLCs we may lose if the
+	// commitment doesn't confirm in time.
+	deadlineMinHeight := uint32(math.MaxUint32)
+	for _, htlc := range deadlineHTLCs {
 		if htlc.RefundTimeout < deadlineMinHeight {
 			deadlineMinHeight = htlc.RefundTimeout
 		}
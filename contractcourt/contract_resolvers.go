@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btclog"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
 )
 
 var (
@@ -24,8 +26,26 @@ const (
 	// secondLevelConfTarget is the confirmation target we'll use when
 	// adding fees to our second-level HTLC transactions.
 	secondLevelConfTarget = 6
+
+	// sweepBudgetPercent is the percentage of the value at stake that
+	// we're willing to spend on fees to get a time-sensitive sweep
+	// confirmed before its deadline.
+	sweepBudgetPercent = 50
+
+	// timeoutSweepDeadlineDelta is the number of blocks after the expiry
+	// of an outgoing HTLC by which our second-level timeout transaction
+	// must confirm. Forwarded HTLCs expire at least 18 blocks before the
+	// incoming HTLC, which goes to chain 10 blocks before its expiry if it
+	// hasn't been failed back by then.
+	timeoutSweepDeadlineDelta = 8
 )
 
+// sweepBudget returns the budget for the fees of a time-sensitive sweep that
+// protects the given value.
+func sweepBudget(valueAtStake lnwire.MilliSatoshi) btcutil.Amount {
+	return valueAtStake.ToSatoshis() * sweepBudgetPercent / 100
+}
+
 // ContractResolver is an interface which packages a state machine which is
 // able to carry out the necessary steps required to fully resolve a Bitcoin
 // contract on-chain. Resolvers are fully encodable to ensure callers are able
@@ -5,9 +5,9 @@ import (
 	"io"
 	"sync"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -240,12 +240,16 @@ func (h *htlcSuccessResolver) broadcastReSignedSuccessTx() (
 			h.htlcResolution.SignDetails, h.htlcResolution.Preimage,
 			h.broadcastHeight,
 		)
+		// The success transaction must confirm before the HTLC
+		// expires, as the remote party can time it out afterwards.
 		_, err := h.Sweeper.SweepInput(
 			&secondLevelInput,
 			sweep.Params{
 				Fee: sweep.FeePreference{
 					ConfTarget: secondLevelConfTarget,
 				},
+				DeadlineHeight: int32(h.htlc.RefundTimeout),
+				Budget:         sweepBudget(h.htlc.Amt),
 			},
 		)
 		if err != nil {
@@ -6,8 +6,8 @@ import (
 	"io"
 	"sync"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -330,12 +330,18 @@ func (h *htlcTimeoutResolver) spendHtlcOutput() (*chainntnfs.SpendDetail, error)
 			h.htlcResolution.SignDetails,
 			h.broadcastHeight,
 		)
+		// The timeout transaction must confirm in time to fail back
+		// the incoming HTLC before it goes to chain, and before the
+		// remote party sweeps the HTLC with the preimage.
+		deadline := h.htlc.RefundTimeout + timeoutSweepDeadlineDelta
 		_, err := h.Sweeper.SweepInput(
 			&inp,
 			sweep.Params{
 				Fee: sweep.FeePreference{
 					ConfTarget: secondLevelConfTarget,
 				},
+				DeadlineHeight: int32(deadline),
+				Budget:         sweepBudget(h.htlc.Amt),
 			},
 		)
 		if err != nil {
@@ -7,14 +7,15 @@ import (
 	"sync"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/batch"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lnwallet"
@@ -265,6 +266,20 @@ type Config struct {
 	// how often we should allow a new update for a specific channel and
 	// direction.
 	ChannelUpdateInterval time.Duration
+
+	// FindBaseByAlias finds the base short channel ID of the channel the
+	// given alias belongs to. This is used to process channel updates
+	// our peers send us for channels that use an alias. If nil, or if the
+	// short channel ID isn't an alias, channel updates are processed using
+	// the short channel ID they carry.
+	FindBaseByAlias func(alias lnwire.ShortChannelID) (
+		lnwire.ShortChannelID, error)
+
+	// GetAlias returns the alias our peer sent us for the channel with
+	// the given channel ID. Our own channel updates for private channels
+	// are sent to the peer using this alias. If nil, or if no alias is
+	// found, the update is sent unchanged.
+	GetAlias func(chanID lnwire.ChannelID) (lnwire.ShortChannelID, error)
 }
 
 // AuthenticatedGossiper is a subsystem which is responsible for receiving
@@ -316,6 +331,10 @@ type AuthenticatedGossiper struct {
 	// selfKey is the identity public key of the backing Lightning node.
 	selfKey *btcec.PublicKey
 
+	// selfKeyLoc is the locator for the identity public key of the backing
+	// Lightning node.
+	selfKeyLoc keychain.KeyLocator
+
 	// channelMtx is used to restrict the database access to one
 	// goroutine per channel ID. This is done to ensure that when
 	// the gossiper is handling an announcement, the db state stays
@@ -355,9 +374,11 @@ type AuthenticatedGossiper struct {
 
 // New creates a new AuthenticatedGossiper instance, initialized with the
 // passed configuration parameters.
-func New(cfg Config, selfKey *btcec.PublicKey) *AuthenticatedGossiper {
+func New(cfg Config,
+	selfKeyDesc *keychain.KeyDescriptor) *AuthenticatedGossiper {
 	gossiper := &AuthenticatedGossiper{
-		selfKey:                 selfKey,
+		selfKey:                 selfKeyDesc.PubKey,
+		selfKeyLoc:              selfKeyDesc.KeyLocator,
 		cfg:                     &cfg,
 		networkMsgs:             make(chan *networkMsg),
 		quit:                    make(chan struct{}),
@@ -1365,8 +1386,16 @@ func (d *AuthenticatedGossiper) processChanPolicyUpdate(
 			remotePubKey := remotePubFromChanInfo(
 				edgeInfo.Info, chanUpdate.ChannelFlags,
 			)
-			err := d.reliableSender.sendMessage(
-				chanUpdate, remotePubKey,
+
+			peerUpdate, err := d.peerAliasUpdate(
+				edgeInfo.Info, chanUpdate,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			err = d.reliableSender.sendMessage(
+				peerUpdate, remotePubKey,
 			)
 			if err != nil {
 				log.Errorf("Unable to reliably send %v for "+
@@ -1389,6 +1418,37 @@ func (d *AuthenticatedGossiper) processChanPolicyUpdate(
 	return chanUpdates, nil
 }
 
+// peerAliasUpdate returns the channel update that should be sent directly to
+// our peer for the given private channel. If our peer sent us an alias for
+// the channel, we'll use that alias in a re-signed copy of the update, as the
+// peer may not know the channel by any other short channel ID.
+func (d *AuthenticatedGossiper) peerAliasUpdate(
+	chanInfo *channeldb.ChannelEdgeInfo,
+	update *lnwire.ChannelUpdate) (*lnwire.ChannelUpdate, error) {
+
+	if d.cfg.GetAlias == nil {
+		return update, nil
+	}
+
+	chanID := lnwire.NewChanIDFromOutPoint(&chanInfo.ChannelPoint)
+	peerAlias, err := d.cfg.GetAlias(chanID)
+	if err != nil || peerAlias == update.ShortChannelID {
+		return update, nil
+	}
+
+	aliasUpdate := *update
+	aliasUpdate.ShortChannelID = peerAlias
+	err = netann.SignChannelUpdate(
+		d.cfg.AnnSigner, d.selfKeyLoc, &aliasUpdate,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign alias update for "+
+			"channel %v: %v", chanInfo.ChannelPoint, err)
+	}
+
+	return &aliasUpdate, nil
+}
+
 // remotePubFromChanInfo returns the public key of the remote peer given a
 // ChannelEdgeInfo that describe a channel we have with them.
 func remotePubFromChanInfo(chanInfo *channeldb.ChannelEdgeInfo,
@@ -1842,14 +1902,30 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 			return nil, false
 		}
 
-		blockHeight := msg.ShortChannelID.BlockHeight
-		shortChanID := msg.ShortChannelID.ToUint64()
+		// If the update refers to the channel by one of its aliases,
+		// we'll process it using the channel's base short channel ID
+		// instead.
+		var (
+			scid    = msg.ShortChannelID
+			isAlias bool
+		)
+		if d.cfg.FindBaseByAlias != nil {
+			base, err := d.cfg.FindBaseByAlias(scid)
+			if err == nil {
+				scid = base
+				isAlias = true
+			}
+		}
+
+		blockHeight := scid.BlockHeight
+		shortChanID := scid.ToUint64()
 
 		// If the advertised inclusionary block is beyond our knowledge
 		// of the chain tip, then we'll put the announcement in limbo
 		// to be fully verified once we advance forward in the chain.
+		// Updates for alias channels are never premature.
 		d.Lock()
-		if nMsg.isRemote && isPremature(msg.ShortChannelID, 0) {
+		if nMsg.isRemote && !isAlias && isPremature(scid, 0) {
 			log.Infof("Update announcement for "+
 				"short_chan_id(%v), is premature: advertises "+
 				"height %v, only height %v is known",
@@ -1866,7 +1942,7 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 		// channel in order to quickly reject it.
 		timestamp := time.Unix(int64(msg.Timestamp), 0)
 		if d.cfg.Router.IsStaleEdgePolicy(
-			msg.ShortChannelID, timestamp, msg.ChannelFlags,
+			scid, timestamp, msg.ChannelFlags,
 		) {
 			nMsg.err <- nil
 			return nil, true
@@ -1880,9 +1956,9 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 		// before we access the database. This ensures the state
 		// we read from the database has not changed between this
 		// point and when we call UpdateEdge() later.
-		d.channelMtx.Lock(msg.ShortChannelID.ToUint64())
-		defer d.channelMtx.Unlock(msg.ShortChannelID.ToUint64())
-		chanInfo, edge1, edge2, err := d.cfg.Router.GetChannelByID(msg.ShortChannelID)
+		d.channelMtx.Lock(shortChanID)
+		defer d.channelMtx.Unlock(shortChanID)
+		chanInfo, edge1, edge2, err := d.cfg.Router.GetChannelByID(scid)
 		switch err {
 		// No error, break.
 		case nil:
@@ -2067,11 +2143,20 @@ func (d *AuthenticatedGossiper) processNetworkAnnouncement(
 				chanInfo, msg.ChannelFlags,
 			)
 
+			// If our peer sent us an alias for this channel, the
+			// update it receives must use that alias.
+			peerUpdate, err := d.peerAliasUpdate(chanInfo, msg)
+			if err != nil {
+				log.Error(err)
+				nMsg.err <- err
+				return nil, false
+			}
+
 			// Now, we'll attempt to send the channel update message
 			// reliably to the remote peer in the background, so
 			// that we don't block if the peer happens to be offline
 			// at the moment.
-			err := d.reliableSender.sendMessage(msg, remotePubKey)
+			err = d.reliableSender.sendMessage(peerUpdate, remotePubKey)
 			if err != nil {
 				err := fmt.Errorf("unable to reliably send %v "+
 					"for channel=%v to peer=%x: %v",
@@ -2564,7 +2649,7 @@ func (d *AuthenticatedGossiper) updateChannel(info *channeldb.ChannelEdgeInfo,
 	// We'll generate a new signature over a digest of the channel
 	// announcement itself and update the timestamp to ensure it propagate.
 	err := netann.SignChannelUpdate(
-		d.cfg.AnnSigner, d.selfKey, chanUpdate,
+		d.cfg.AnnSigner, d.selfKeyLoc, chanUpdate,
 		netann.ChanUpdSetTimestamp,
 	)
 	if err != nil {
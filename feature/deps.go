@@ -65,6 +65,25 @@ var deps = depDesc{
 		lnwire.PaymentAddrOptional: {},
 	},
 	lnwire.ExplicitChannelTypeOptional: {},
+	lnwire.ScidAliasOptional: {
+		lnwire.ExplicitChannelTypeOptional: {},
+	},
+	lnwire.ZeroConfOptional: {
+		lnwire.ScidAliasOptional: {},
+	},
+	lnwire.SplicingOptional: {
+		lnwire.QuiescenceOptional: {},
+	},
+	lnwire.DynamicCommitmentsOptional: {
+		lnwire.QuiescenceOptional: {},
+	},
+	lnwire.RouteBlindingOptional: {
+		lnwire.TLVOnionPayloadOptional: {},
+	},
+	lnwire.TrampolineRoutingOptional: {
+		lnwire.TLVOnionPayloadOptional: {},
+		lnwire.PaymentAddrOptional:     {},
+	},
 }
 
 // ValidateDeps asserts that a feature vector sets all features and their
@@ -23,6 +23,47 @@ type Config struct {
 
 	// NoWumbo unsets any bits signalling support for wumbo channels.
 	NoWumbo bool
+
+	// NoScidAlias unsets any bits signalling support for the
+	// option_scid_alias channel type and alias short channel IDs. As
+	// zero-conf channels depend on aliases, this also unsets the zero-conf
+	// bits.
+	NoScidAlias bool
+
+	// NoZeroConf unsets any bits signalling support for zero-conf
+	// channels.
+	NoZeroConf bool
+
+	// NoSplicing unsets any bits signalling support for splicing.
+	NoSplicing bool
+
+	// NoQuiescence unsets any bits signalling support for quiescing
+	// channels with the stfu message.
+	NoQuiescence bool
+
+	// NoDynamicCommitments unsets any bits signalling support for
+	// upgrading the commitment type of existing channels.
+	NoDynamicCommitments bool
+
+	// NoPeerStorage unsets any bits signalling that we store backups on
+	// behalf of our channel peers.
+	NoPeerStorage bool
+
+	// NoRbfCoopClose unsets any bits signalling support for cooperative
+	// closes whose closing transaction can be fee bumped with rbf.
+	NoRbfCoopClose bool
+
+	// NoDualFunding unsets any bits signalling support for dual funded
+	// channels.
+	NoDualFunding bool
+
+	// NoOnionMessages unsets any bits signalling support for forwarding
+	// onion messages.
+	NoOnionMessages bool
+
+	// NoTrampolineRouting unsets any bits signalling that we forward
+	// payments as trampoline node.
+	NoTrampolineRouting bool
 }
 
 // Manager is responsible for generating feature vectors for different requested
@@ -79,6 +120,8 @@ func newManager(cfg Config, desc setDesc) (*Manager, error) {
 			raw.Unset(lnwire.MPPRequired)
 			raw.Unset(lnwire.AMPOptional)
 			raw.Unset(lnwire.AMPRequired)
+			raw.Unset(lnwire.TrampolineRoutingOptional)
+			raw.Unset(lnwire.TrampolineRoutingRequired)
 		}
 		if cfg.NoStaticRemoteKey {
 			raw.Unset(lnwire.StaticRemoteKeyOptional)
@@ -92,6 +135,48 @@ func newManager(cfg Config, desc setDesc) (*Manager, error) {
 			raw.Unset(lnwire.WumboChannelsOptional)
 			raw.Unset(lnwire.WumboChannelsRequired)
 		}
+		if cfg.NoScidAlias {
+			raw.Unset(lnwire.ScidAliasOptional)
+			raw.Unset(lnwire.ScidAliasRequired)
+			raw.Unset(lnwire.ZeroConfOptional)
+			raw.Unset(lnwire.ZeroConfRequired)
+		}
+		if cfg.NoZeroConf {
+			raw.Unset(lnwire.ZeroConfOptional)
+			raw.Unset(lnwire.ZeroConfRequired)
+		}
+		if cfg.NoSplicing {
+			raw.Unset(lnwire.SplicingOptional)
+			raw.Unset(lnwire.SplicingRequired)
+		}
+		if cfg.NoQuiescence {
+			raw.Unset(lnwire.QuiescenceOptional)
+			raw.Unset(lnwire.QuiescenceRequired)
+		}
+		if cfg.NoDynamicCommitments {
+			raw.Unset(lnwire.DynamicCommitmentsOptional)
+			raw.Unset(lnwire.DynamicCommitmentsRequired)
+		}
+		if cfg.NoPeerStorage {
+			raw.Unset(lnwire.ProvideStorageOptional)
+			raw.Unset(lnwire.ProvideStorageRequired)
+		}
+		if cfg.NoRbfCoopClose {
+			raw.Unset(lnwire.RbfCoopCloseOptional)
+			raw.Unset(lnwire.RbfCoopCloseRequired)
+		}
+		if cfg.NoDualFunding {
+			raw.Unset(lnwire.DualFundOptional)
+			raw.Unset(lnwire.DualFundRequired)
+		}
+		if cfg.NoOnionMessages {
+			raw.Unset(lnwire.OnionMessagesOptional)
+			raw.Unset(lnwire.OnionMessagesRequired)
+		}
+		if cfg.NoTrampolineRouting {
+			raw.Unset(lnwire.TrampolineRoutingOptional)
+			raw.Unset(lnwire.TrampolineRoutingRequired)
+		}
 
 		// Ensure that all of our feature sets properly set any
 		// dependent features.
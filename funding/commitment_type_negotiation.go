@@ -49,6 +49,37 @@ func explicitNegotiateCommitmentType(channelType lnwire.ChannelType,
 
 	channelFeatures := lnwire.RawFeatureVector(channelType)
 
+	// The zero-conf and scid-alias bits don't influence the commitment
+	// format, they can only be set on top of a non-legacy channel type.
+	// We'll make sure both peers support them and strip them before
+	// matching the base channel type.
+	channelFeatures = *channelFeatures.Clone()
+	if channelFeatures.IsSet(lnwire.ZeroConfRequired) ||
+		channelFeatures.IsSet(lnwire.ScidAliasRequired) {
+
+		if channelFeatures.IsSet(lnwire.ZeroConfRequired) {
+			if !hasFeatures(
+				local, remote, lnwire.ZeroConfOptional,
+			) {
+				return 0, errUnsupportedChannelType
+			}
+			channelFeatures.Unset(lnwire.ZeroConfRequired)
+		}
+
+		if channelFeatures.IsSet(lnwire.ScidAliasRequired) {
+			if !hasFeatures(
+				local, remote, lnwire.ScidAliasOptional,
+			) {
+				return 0, errUnsupportedChannelType
+			}
+			channelFeatures.Unset(lnwire.ScidAliasRequired)
+		}
+
+		if channelFeatures.IsEmpty() {
+			return 0, errUnsupportedChannelType
+		}
+	}
+
 	switch {
 	// Anchors zero fee + static remote key features only.
 	case channelFeatures.OnlyContains(
@@ -106,6 +137,20 @@ func implicitNegotiateCommitmentType(local,
 	return lnwallet.CommitmentTypeLegacy
 }
 
+// zeroConfAndScidAlias returns whether the zero-conf and the scid-alias bits
+// are set in the passed explicit channel type. A nil channel type has neither
+// of them set.
+func zeroConfAndScidAlias(channelType *lnwire.ChannelType) (bool, bool) {
+	if channelType == nil {
+		return false, false
+	}
+
+	channelFeatures := lnwire.RawFeatureVector(*channelType)
+
+	return channelFeatures.IsSet(lnwire.ZeroConfRequired),
+		channelFeatures.IsSet(lnwire.ScidAliasRequired)
+}
+
 // hasFeatures determines whether a set of features is supported by both the set
 // of local and remote features.
 func hasFeatures(local, remote *lnwire.FeatureVector,
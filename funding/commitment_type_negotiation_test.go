@@ -92,6 +92,68 @@ func TestCommitmentTypeNegotiation(t *testing.T) {
 			expectsRes: lnwallet.CommitmentTypeTweakless,
 			expectsErr: nil,
 		},
+		{
+			name: "explicit zero-conf scid-alias anchors",
+			channelFeatures: lnwire.NewRawFeatureVector(
+				lnwire.StaticRemoteKeyRequired,
+				lnwire.AnchorsZeroFeeHtlcTxRequired,
+				lnwire.ZeroConfRequired,
+				lnwire.ScidAliasRequired,
+			),
+			localFeatures: lnwire.NewRawFeatureVector(
+				lnwire.StaticRemoteKeyOptional,
+				lnwire.AnchorsZeroFeeHtlcTxOptional,
+				lnwire.ExplicitChannelTypeOptional,
+				lnwire.ZeroConfOptional,
+				lnwire.ScidAliasOptional,
+			),
+			remoteFeatures: lnwire.NewRawFeatureVector(
+				lnwire.StaticRemoteKeyOptional,
+				lnwire.AnchorsZeroFeeHtlcTxOptional,
+				lnwire.ExplicitChannelTypeOptional,
+				lnwire.ZeroConfOptional,
+				lnwire.ScidAliasOptional,
+			),
+			expectsRes: lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx,
+			expectsErr: nil,
+		},
+		{
+			name: "explicit zero-conf missing remote feature",
+			channelFeatures: lnwire.NewRawFeatureVector(
+				lnwire.StaticRemoteKeyRequired,
+				lnwire.AnchorsZeroFeeHtlcTxRequired,
+				lnwire.ZeroConfRequired,
+			),
+			localFeatures: lnwire.NewRawFeatureVector(
+				lnwire.StaticRemoteKeyOptional,
+				lnwire.AnchorsZeroFeeHtlcTxOptional,
+				lnwire.ExplicitChannelTypeOptional,
+				lnwire.ZeroConfOptional,
+				lnwire.ScidAliasOptional,
+			),
+			remoteFeatures: lnwire.NewRawFeatureVector(
+				lnwire.StaticRemoteKeyOptional,
+				lnwire.AnchorsZeroFeeHtlcTxOptional,
+				lnwire.ExplicitChannelTypeOptional,
+				lnwire.ScidAliasOptional,
+			),
+			expectsErr: errUnsupportedChannelType,
+		},
+		{
+			name: "explicit scid-alias legacy",
+			channelFeatures: lnwire.NewRawFeatureVector(
+				lnwire.ScidAliasRequired,
+			),
+			localFeatures: lnwire.NewRawFeatureVector(
+				lnwire.ExplicitChannelTypeOptional,
+				lnwire.ScidAliasOptional,
+			),
+			remoteFeatures: lnwire.NewRawFeatureVector(
+				lnwire.ExplicitChannelTypeOptional,
+				lnwire.ScidAliasOptional,
+			),
+			expectsErr: errUnsupportedChannelType,
+		},
 		{
 			name:            "explicit legacy",
 			channelFeatures: lnwire.NewRawFeatureVector(),
@@ -0,0 +1,579 @@
+package funding
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// maxInteractiveTxAdds is the maximum number of inputs and outputs
+	// we accept the remote party to add to an interactively constructed
+	// funding transaction, each.
+	maxInteractiveTxAdds = 4096
+
+	// maxInteractiveTxMsgs is the maximum number of messages we accept
+	// from the remote party while constructing a funding transaction
+	// interactively.
+	maxInteractiveTxMsgs = 4 * maxInteractiveTxAdds
+
+	// maxInteractiveTxSequence is the maximum sequence number an input of
+	// an interactively constructed funding transaction may use.
+	maxInteractiveTxSequence = wire.MaxTxInSequenceNum - 2
+)
+
+// interactiveTxInput is an input of an interactively constructed funding
+// transaction.
+type interactiveTxInput struct {
+	serialID uint64
+	outPoint wire.OutPoint
+	prevOut  *wire.TxOut
+	sequence uint32
+	local    bool
+}
+
+// interactiveTxOutput is an output of an interactively constructed funding
+// transaction.
+type interactiveTxOutput struct {
+	serialID uint64
+	txOut    *wire.TxOut
+	local    bool
+}
+
+// interactiveTx tracks the construction of the funding transaction of a dual
+// funded channel. Both parties take turns adding or removing inputs and
+// outputs, until both of them sent a tx_complete message in a row. The
+// initiator of the channel uses even serial IDs, the responder odd ones.
+type interactiveTx struct {
+	chanID    lnwire.ChannelID
+	initiator bool
+	lockTime  uint32
+	feeRate   chainfee.SatPerKWeight
+
+	// localAmt and remoteAmt are the amounts each party contributes to the
+	// funding output.
+	localAmt  btcutil.Amount
+	remoteAmt btcutil.Amount
+
+	// fundingOutput is the funding output the final transaction must
+	// contain exactly once.
+	fundingOutput *wire.TxOut
+
+	nextSerialID uint64
+	inputs       map[uint64]*interactiveTxInput
+	outputs      map[uint64]*interactiveTxOutput
+
+	// pending holds the messages we still need to send to the remote
+	// party.
+	pending []lnwire.Message
+
+	sentComplete bool
+	recvComplete bool
+	numRecvMsgs  int
+	numRemoteIn  int
+	numRemoteOut int
+
+	// signedTx is the funding transaction with our inputs signed.
+	signedTx *wire.MsgTx
+
+	// sentSigs is true once we sent our tx_signatures for the final
+	// transaction.
+	sentSigs bool
+}
+
+// newInteractiveTx creates a new interactiveTx for the channel with the given
+// ID.
+func newInteractiveTx(chanID lnwire.ChannelID, initiator bool,
+	lockTime uint32, feeRate chainfee.SatPerKWeight, localAmt,
+	remoteAmt btcutil.Amount, fundingOutput *wire.TxOut) *interactiveTx {
+
+	nextSerialID := uint64(1)
+	if initiator {
+		nextSerialID = 0
+	}
+
+	return &interactiveTx{
+		chanID:        chanID,
+		initiator:     initiator,
+		lockTime:      lockTime,
+		feeRate:       feeRate,
+		localAmt:      localAmt,
+		remoteAmt:     remoteAmt,
+		fundingOutput: fundingOutput,
+		nextSerialID:  nextSerialID,
+		inputs:        make(map[uint64]*interactiveTxInput),
+		outputs:       make(map[uint64]*interactiveTxOutput),
+	}
+}
+
+// isLocalSerialID returns true if the serial ID belongs to our side.
+func (i *interactiveTx) isLocalSerialID(serialID uint64) bool {
+	return (serialID%2 == 0) == i.initiator
+}
+
+// newSerialID returns a fresh serial ID for our side.
+func (i *interactiveTx) newSerialID() uint64 {
+	serialID := i.nextSerialID
+	i.nextSerialID += 2
+
+	return serialID
+}
+
+// hasOutPoint returns true if the outpoint was already added as an input.
+func (i *interactiveTx) hasOutPoint(op wire.OutPoint) bool {
+	for _, txIn := range i.inputs {
+		if txIn.outPoint == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addLocalInput queues an input spending the given output of prevTx to be
+// added to the transaction.
+func (i *interactiveTx) addLocalInput(prevTx *wire.MsgTx, index uint32,
+	sequence uint32) error {
+
+	if int(index) >= len(prevTx.TxOut) {
+		return fmt.Errorf("output index %v out of range", index)
+	}
+
+	prevOut := prevTx.TxOut[index]
+	if !txscript.IsWitnessProgram(prevOut.PkScript) {
+		return fmt.Errorf("input %v:%v isn't native segwit",
+			prevTx.TxHash(), index)
+	}
+
+	outPoint := wire.OutPoint{Hash: prevTx.TxHash(), Index: index}
+	if i.hasOutPoint(outPoint) {
+		return fmt.Errorf("input %v already added", outPoint)
+	}
+
+	serialID := i.newSerialID()
+	i.inputs[serialID] = &interactiveTxInput{
+		serialID: serialID,
+		outPoint: outPoint,
+		prevOut:  prevOut,
+		sequence: sequence,
+		local:    true,
+	}
+	i.pending = append(i.pending, &lnwire.TxAddInput{
+		ChannelID: i.chanID,
+		SerialID:  serialID,
+		PrevTx:    prevTx,
+		PrevTxOut: index,
+		Sequence:  sequence,
+	})
+
+	return nil
+}
+
+// addLocalOutput queues the output to be added to the transaction.
+func (i *interactiveTx) addLocalOutput(txOut *wire.TxOut) {
+	serialID := i.newSerialID()
+	i.outputs[serialID] = &interactiveTxOutput{
+		serialID: serialID,
+		txOut:    txOut,
+		local:    true,
+	}
+	i.pending = append(i.pending, &lnwire.TxAddOutput{
+		ChannelID: i.chanID,
+		SerialID:  serialID,
+		Amount:    btcutil.Amount(txOut.Value),
+		PkScript:  txOut.PkScript,
+	})
+}
+
+// processMsg validates and applies a message received from the remote party.
+func (i *interactiveTx) processMsg(msg lnwire.Message) error {
+	i.numRecvMsgs++
+	if i.numRecvMsgs > maxInteractiveTxMsgs {
+		return fmt.Errorf("too many interactive tx messages")
+	}
+
+	switch msg := msg.(type) {
+	case *lnwire.TxAddInput:
+		err := i.checkRemoteSerialID(msg.SerialID, true)
+		if err != nil {
+			return err
+		}
+		if msg.PrevTx == nil ||
+			int(msg.PrevTxOut) >= len(msg.PrevTx.TxOut) {
+
+			return fmt.Errorf("invalid previous output for "+
+				"serial_id=%v", msg.SerialID)
+		}
+
+		prevOut := msg.PrevTx.TxOut[msg.PrevTxOut]
+		if !txscript.IsWitnessProgram(prevOut.PkScript) {
+			return fmt.Errorf("input with serial_id=%v isn't "+
+				"native segwit", msg.SerialID)
+		}
+		if msg.Sequence > maxInteractiveTxSequence {
+			return fmt.Errorf("invalid sequence %v for "+
+				"serial_id=%v", msg.Sequence, msg.SerialID)
+		}
+
+		outPoint := wire.OutPoint{
+			Hash:  msg.PrevTx.TxHash(),
+			Index: msg.PrevTxOut,
+		}
+		if i.hasOutPoint(outPoint) {
+			return fmt.Errorf("input %v already added", outPoint)
+		}
+
+		i.numRemoteIn++
+		if i.numRemoteIn > maxInteractiveTxAdds {
+			return fmt.Errorf("too many inputs added")
+		}
+
+		i.inputs[msg.SerialID] = &interactiveTxInput{
+			serialID: msg.SerialID,
+			outPoint: outPoint,
+			prevOut:  prevOut,
+			sequence: msg.Sequence,
+		}
+
+	case *lnwire.TxAddOutput:
+		err := i.checkRemoteSerialID(msg.SerialID, false)
+		if err != nil {
+			return err
+		}
+
+		txOut := wire.NewTxOut(int64(msg.Amount), msg.PkScript)
+		if msg.Amount < lnwallet.DefaultDustLimit() ||
+			msg.Amount > btcutil.MaxSatoshi {
+
+			return fmt.Errorf("invalid amount %v for "+
+				"serial_id=%v", msg.Amount, msg.SerialID)
+		}
+		class := txscript.GetScriptClass(msg.PkScript)
+		if class == txscript.NonStandardTy ||
+			class == txscript.NullDataTy {
+
+			return fmt.Errorf("non-standard script for "+
+				"serial_id=%v", msg.SerialID)
+		}
+
+		i.numRemoteOut++
+		if i.numRemoteOut > maxInteractiveTxAdds {
+			return fmt.Errorf("too many outputs added")
+		}
+
+		i.outputs[msg.SerialID] = &interactiveTxOutput{
+			serialID: msg.SerialID,
+			txOut:    txOut,
+		}
+
+	case *lnwire.TxRemoveInput:
+		txIn, ok := i.inputs[msg.SerialID]
+		if !ok || txIn.local {
+			return fmt.Errorf("cannot remove input with "+
+				"serial_id=%v", msg.SerialID)
+		}
+		delete(i.inputs, msg.SerialID)
+
+	case *lnwire.TxRemoveOutput:
+		txOut, ok := i.outputs[msg.SerialID]
+		if !ok || txOut.local {
+			return fmt.Errorf("cannot remove output with "+
+				"serial_id=%v", msg.SerialID)
+		}
+		delete(i.outputs, msg.SerialID)
+
+	case *lnwire.TxComplete:
+		i.recvComplete = true
+		return nil
+
+	default:
+		return fmt.Errorf("unexpected interactive tx message %T", msg)
+	}
+
+	// Any change to the transaction means we need to confirm it again.
+	i.recvComplete = false
+	i.sentComplete = false
+
+	return nil
+}
+
+// checkRemoteSerialID ensures the serial ID of an input or output added by
+// the remote party has the correct parity and is unique.
+func (i *interactiveTx) checkRemoteSerialID(serialID uint64,
+	isInput bool) error {
+
+	if i.isLocalSerialID(serialID) {
+		return fmt.Errorf("serial_id=%v has wrong parity", serialID)
+	}
+
+	_, inputExists := i.inputs[serialID]
+	_, outputExists := i.outputs[serialID]
+	if (isInput && inputExists) || (!isInput && outputExists) {
+		return fmt.Errorf("duplicate serial_id=%v", serialID)
+	}
+
+	return nil
+}
+
+// nextMsg returns the next message we need to send to the remote party. If
+// there's nothing left for us to add, a tx_complete is returned. Once both
+// parties sent a tx_complete in a row, nil is returned.
+func (i *interactiveTx) nextMsg() lnwire.Message {
+	if i.isComplete() {
+		return nil
+	}
+
+	if len(i.pending) > 0 {
+		msg := i.pending[0]
+		i.pending = i.pending[1:]
+
+		// The remote party needs to confirm the transaction again
+		// after our change.
+		i.recvComplete = false
+		i.sentComplete = false
+
+		return msg
+	}
+
+	i.sentComplete = true
+
+	return &lnwire.TxComplete{
+		ChannelID: i.chanID,
+	}
+}
+
+// isComplete returns true once both parties sent a tx_complete in a row.
+func (i *interactiveTx) isComplete() bool {
+	return i.sentComplete && i.recvComplete
+}
+
+// sortedInputs returns all inputs sorted by their serial ID.
+func (i *interactiveTx) sortedInputs() []*interactiveTxInput {
+	inputs := make([]*interactiveTxInput, 0, len(i.inputs))
+	for _, txIn := range i.inputs {
+		inputs = append(inputs, txIn)
+	}
+	sort.Slice(inputs, func(a, b int) bool {
+		return inputs[a].serialID < inputs[b].serialID
+	})
+
+	return inputs
+}
+
+// sortedOutputs returns all outputs sorted by their serial ID.
+func (i *interactiveTx) sortedOutputs() []*interactiveTxOutput {
+	outputs := make([]*interactiveTxOutput, 0, len(i.outputs))
+	for _, txOut := range i.outputs {
+		outputs = append(outputs, txOut)
+	}
+	sort.Slice(outputs, func(a, b int) bool {
+		return outputs[a].serialID < outputs[b].serialID
+	})
+
+	return outputs
+}
+
+// isFundingOutput returns true if the output is the channel's funding
+// output.
+func (i *interactiveTx) isFundingOutput(txOut *wire.TxOut) bool {
+	return txOut.Value == i.fundingOutput.Value &&
+		string(txOut.PkScript) == string(i.fundingOutput.PkScript)
+}
+
+// inputTotal returns the total amount of the inputs added by the given side.
+func (i *interactiveTx) inputTotal(local bool) btcutil.Amount {
+	var total btcutil.Amount
+	for _, txIn := range i.inputs {
+		if txIn.local == local {
+			total += btcutil.Amount(txIn.prevOut.Value)
+		}
+	}
+
+	return total
+}
+
+// sendSigsFirst returns true if we need to send our tx_signatures first. This
+// is the party that contributed the lower input amount, or the initiator if
+// both contributed the same amount.
+func (i *interactiveTx) sendSigsFirst() bool {
+	localTotal := i.inputTotal(true)
+	remoteTotal := i.inputTotal(false)
+	if localTotal == remoteTotal {
+		return i.initiator
+	}
+
+	return localTotal < remoteTotal
+}
+
+// buildTx assembles the unsigned funding transaction once the construction is
+// complete. The remote party's contribution is checked to cover both its
+// share of the funding output and the fees for the inputs and outputs it
+// added.
+func (i *interactiveTx) buildTx() (*wire.MsgTx, error) {
+	if !i.isComplete() {
+		return nil, fmt.Errorf("transaction construction not complete")
+	}
+
+	tx := wire.NewMsgTx(2)
+	tx.LockTime = i.lockTime
+
+	var (
+		numFundingOutputs int
+		remoteOutputs     btcutil.Amount
+		remoteWeight      input.TxWeightEstimator
+	)
+	for _, txIn := range i.sortedInputs() {
+		tx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: txIn.outPoint,
+			Sequence:         txIn.sequence,
+		})
+
+		if !txIn.local {
+			remoteWeight.AddP2WKHInput()
+		}
+	}
+	for _, txOut := range i.sortedOutputs() {
+		tx.AddTxOut(txOut.txOut)
+
+		// The funding output is paid for by the initiator, but it is
+		// funded by both parties, so it doesn't count as a regular
+		// output of the remote party.
+		if i.isFundingOutput(txOut.txOut) {
+			numFundingOutputs++
+			if !i.initiator {
+				remoteWeight.AddTxOutput(txOut.txOut)
+			}
+
+			continue
+		}
+
+		if !txOut.local {
+			remoteOutputs += btcutil.Amount(txOut.txOut.Value)
+			remoteWeight.AddTxOutput(txOut.txOut)
+		}
+	}
+
+	if numFundingOutputs != 1 {
+		return nil, fmt.Errorf("funding tx has %v funding outputs",
+			numFundingOutputs)
+	}
+
+	// The initiator also pays for the common fields of the transaction,
+	// which are included in the estimator's weight. If the remote party
+	// is the responder, we only count the weight of its inputs and
+	// outputs.
+	weight := remoteWeight.Weight()
+	if i.initiator {
+		var empty input.TxWeightEstimator
+		weight -= empty.Weight()
+	}
+
+	requiredFee := i.feeRate.FeeForWeight(int64(weight))
+	remoteInputs := i.inputTotal(false)
+	if remoteInputs < remoteOutputs+i.remoteAmt+requiredFee {
+		return nil, fmt.Errorf("remote contribution insufficient: "+
+			"inputs=%v, outputs=%v, funding=%v, fee=%v",
+			remoteInputs, remoteOutputs, i.remoteAmt, requiredFee)
+	}
+
+	return tx, nil
+}
+
+// localSigs returns the tx_signatures message with the witnesses of our
+// inputs of the signed funding transaction, ordered by serial ID.
+func (i *interactiveTx) localSigs(
+	signedTx *wire.MsgTx) (*lnwire.TxSignatures, error) {
+
+	msg := &lnwire.TxSignatures{
+		ChannelID: i.chanID,
+		TxHash:    signedTx.TxHash(),
+	}
+	for idx, txIn := range i.sortedInputs() {
+		if !txIn.local {
+			continue
+		}
+
+		witness := signedTx.TxIn[idx].Witness
+		if len(witness) == 0 {
+			return nil, fmt.Errorf("input %v not signed",
+				txIn.outPoint)
+		}
+		msg.Witnesses = append(msg.Witnesses, witness)
+	}
+
+	return msg, nil
+}
+
+// applyRemoteSigs adds the witnesses of the remote party to its inputs of the
+// signed funding transaction and verifies them. The fully signed transaction
+// is returned.
+func (i *interactiveTx) applyRemoteSigs(signedTx *wire.MsgTx,
+	msg *lnwire.TxSignatures) (*wire.MsgTx, error) {
+
+	if msg.TxHash != signedTx.TxHash() {
+		return nil, fmt.Errorf("tx_signatures for unknown tx %v",
+			msg.TxHash)
+	}
+
+	inputs := i.sortedInputs()
+	if len(msg.Witnesses) != i.numInputs(false) {
+		return nil, fmt.Errorf("expected %v witnesses, got %v",
+			i.numInputs(false), len(msg.Witnesses))
+	}
+
+	tx := signedTx.Copy()
+	sigIndex := 0
+	for idx, txIn := range inputs {
+		if txIn.local {
+			continue
+		}
+
+		tx.TxIn[idx].Witness = msg.Witnesses[sigIndex]
+		sigIndex++
+	}
+
+	hashCache := input.NewTxSigHashesV0Only(tx)
+	for idx, txIn := range inputs {
+		if txIn.local {
+			continue
+		}
+
+		vm, err := txscript.NewEngine(
+			txIn.prevOut.PkScript, tx, idx,
+			txscript.StandardVerifyFlags, nil, hashCache,
+			txIn.prevOut.Value,
+			txscript.NewCannedPrevOutputFetcher(
+				txIn.prevOut.PkScript, txIn.prevOut.Value,
+			),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create script "+
+				"engine: %v", err)
+		}
+		if err := vm.Execute(); err != nil {
+			return nil, fmt.Errorf("invalid witness for input "+
+				"%v: %v", txIn.outPoint, err)
+		}
+	}
+
+	return tx, nil
+}
+
+// numInputs returns the number of inputs added by the given side.
+func (i *interactiveTx) numInputs(local bool) int {
+	var num int
+	for _, txIn := range i.inputs {
+		if txIn.local == local {
+			num++
+		}
+	}
+
+	return num
+}
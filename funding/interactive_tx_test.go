@@ -0,0 +1,281 @@
+package funding
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// interactiveTxParty bundles an interactiveTx with the key that owns the
+// inputs of that party.
+type interactiveTxParty struct {
+	tx       *interactiveTx
+	privKey  *btcec.PrivateKey
+	pkScript []byte
+}
+
+// newInteractiveTxParty creates a party that owns a single P2WKH output of
+// the given value it can spend.
+func newInteractiveTxParty(t *testing.T, initiator bool,
+	value btcutil.Amount, localAmt, remoteAmt btcutil.Amount,
+	fundingOutput *wire.TxOut) (*interactiveTxParty, *wire.MsgTx) {
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(
+		btcutil.Hash160(privKey.PubKey().SerializeCompressed()),
+		&chaincfg.RegressionNetParams,
+	)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(addr)
+	require.NoError(t, err)
+
+	prevTx := wire.NewMsgTx(2)
+	prevTx.AddTxIn(&wire.TxIn{})
+	prevTx.AddTxOut(wire.NewTxOut(int64(value), pkScript))
+	if initiator {
+		prevTx.LockTime = 1
+	}
+
+	var chanID lnwire.ChannelID
+	return &interactiveTxParty{
+		tx: newInteractiveTx(
+			chanID, initiator, 100, chainfee.FeePerKwFloor,
+			localAmt, remoteAmt, fundingOutput,
+		),
+		privKey:  privKey,
+		pkScript: pkScript,
+	}, prevTx
+}
+
+// testFundingOutput returns a P2WSH output of the given value to be used as
+// funding output.
+func testFundingOutput(value btcutil.Amount) *wire.TxOut {
+	pkScript := make([]byte, 34)
+	pkScript[0] = txscript.OP_0
+	pkScript[1] = txscript.OP_DATA_32
+
+	return wire.NewTxOut(int64(value), pkScript)
+}
+
+// sign signs all inputs of the party within the passed transaction.
+func (p *interactiveTxParty) sign(t *testing.T, tx *wire.MsgTx) *wire.MsgTx {
+	signedTx := tx.Copy()
+	hashCache := input.NewTxSigHashesV0Only(signedTx)
+	for idx, txIn := range p.tx.sortedInputs() {
+		if !txIn.local {
+			continue
+		}
+
+		witness, err := txscript.WitnessSignature(
+			signedTx, hashCache, idx, txIn.prevOut.Value,
+			txIn.prevOut.PkScript, txscript.SigHashAll, p.privKey,
+			true,
+		)
+		require.NoError(t, err)
+		signedTx.TxIn[idx].Witness = witness
+	}
+
+	return signedTx
+}
+
+// exchangeInteractiveTxMsgs lets both parties take turns until the
+// construction of the transaction is complete.
+func exchangeInteractiveTxMsgs(t *testing.T, initiator,
+	responder *interactiveTx) {
+
+	sender, receiver := initiator, responder
+	msg := sender.nextMsg()
+	for msg != nil {
+		require.NoError(t, receiver.processMsg(msg))
+
+		sender, receiver = receiver, sender
+		msg = sender.nextMsg()
+	}
+
+	require.True(t, initiator.isComplete())
+	require.True(t, responder.isComplete())
+}
+
+// TestInteractiveTxConstruction tests that both parties of a dual funded
+// channel arrive at the same funding transaction, and are able to exchange
+// and verify the signatures for it.
+func TestInteractiveTxConstruction(t *testing.T) {
+	t.Parallel()
+
+	const (
+		aliceAmt = btcutil.Amount(500_000)
+		bobAmt   = btcutil.Amount(300_000)
+	)
+	fundingOutput := testFundingOutput(aliceAmt + bobAmt)
+
+	alice, alicePrevTx := newInteractiveTxParty(
+		t, true, 600_000, aliceAmt, bobAmt, fundingOutput,
+	)
+	bob, bobPrevTx := newInteractiveTxParty(
+		t, false, 400_000, bobAmt, aliceAmt, fundingOutput,
+	)
+
+	// Alice adds her input, her change and the funding output, Bob only
+	// adds his input and change.
+	require.NoError(t, alice.tx.addLocalInput(alicePrevTx, 0, 0))
+	alice.tx.addLocalOutput(wire.NewTxOut(90_000, alice.pkScript))
+	alice.tx.addLocalOutput(fundingOutput)
+	require.NoError(t, bob.tx.addLocalInput(bobPrevTx, 0, 0))
+	bob.tx.addLocalOutput(wire.NewTxOut(90_000, bob.pkScript))
+
+	exchangeInteractiveTxMsgs(t, alice.tx, bob.tx)
+
+	aliceTx, err := alice.tx.buildTx()
+	require.NoError(t, err)
+	bobTx, err := bob.tx.buildTx()
+	require.NoError(t, err)
+	require.Equal(t, aliceTx.TxHash(), bobTx.TxHash())
+	require.Len(t, aliceTx.TxIn, 2)
+	require.Len(t, aliceTx.TxOut, 3)
+	require.EqualValues(t, 100, aliceTx.LockTime)
+
+	// Alice contributed the higher input amount, so Bob needs to send his
+	// signatures first.
+	require.False(t, alice.tx.sendSigsFirst())
+	require.True(t, bob.tx.sendSigsFirst())
+
+	bobSignedTx := bob.sign(t, bobTx)
+	bobSigs, err := bob.tx.localSigs(bobSignedTx)
+	require.NoError(t, err)
+	require.Len(t, bobSigs.Witnesses, 1)
+
+	aliceSignedTx := alice.sign(t, aliceTx)
+	aliceFinalTx, err := alice.tx.applyRemoteSigs(aliceSignedTx, bobSigs)
+	require.NoError(t, err)
+
+	aliceSigs, err := alice.tx.localSigs(aliceSignedTx)
+	require.NoError(t, err)
+	bobFinalTx, err := bob.tx.applyRemoteSigs(bobSignedTx, aliceSigs)
+	require.NoError(t, err)
+	require.Equal(t, aliceFinalTx.WitnessHash(), bobFinalTx.WitnessHash())
+
+	// Invalid signatures must be rejected.
+	aliceSigs.Witnesses[0] = bobSigs.Witnesses[0]
+	_, err = bob.tx.applyRemoteSigs(bobSignedTx, aliceSigs)
+	require.Error(t, err)
+}
+
+// TestInteractiveTxInsufficientContribution tests that we refuse a funding
+// transaction to which the remote party doesn't contribute enough funds.
+func TestInteractiveTxInsufficientContribution(t *testing.T) {
+	t.Parallel()
+
+	fundingOutput := testFundingOutput(800_000)
+	alice, alicePrevTx := newInteractiveTxParty(
+		t, true, 600_000, 500_000, 300_000, fundingOutput,
+	)
+	bob, bobPrevTx := newInteractiveTxParty(
+		t, false, 400_000, 300_000, 500_000, fundingOutput,
+	)
+
+	require.NoError(t, alice.tx.addLocalInput(alicePrevTx, 0, 0))
+	alice.tx.addLocalOutput(fundingOutput)
+
+	// Bob takes too much change, leaving less than his share of the
+	// funding output.
+	require.NoError(t, bob.tx.addLocalInput(bobPrevTx, 0, 0))
+	bob.tx.addLocalOutput(wire.NewTxOut(150_000, bob.pkScript))
+
+	exchangeInteractiveTxMsgs(t, alice.tx, bob.tx)
+
+	_, err := alice.tx.buildTx()
+	require.Error(t, err)
+}
+
+// TestInteractiveTxValidation tests that invalid messages of the remote party
+// are rejected.
+func TestInteractiveTxValidation(t *testing.T) {
+	t.Parallel()
+
+	fundingOutput := testFundingOutput(800_000)
+	alice, alicePrevTx := newInteractiveTxParty(
+		t, true, 600_000, 500_000, 300_000, fundingOutput,
+	)
+	require.NoError(t, alice.tx.addLocalInput(alicePrevTx, 0, 0))
+
+	_, bobPrevTx := newInteractiveTxParty(
+		t, false, 400_000, 300_000, 500_000, fundingOutput,
+	)
+	p2pkhTx := bobPrevTx.Copy()
+	p2pkhTx.TxOut[0].PkScript = append(
+		[]byte{txscript.OP_DUP, txscript.OP_HASH160,
+			txscript.OP_DATA_20}, make([]byte, 22)...,
+	)
+
+	testCases := []struct {
+		name string
+		msg  lnwire.Message
+	}{{
+		name: "wrong parity",
+		msg: &lnwire.TxAddInput{
+			SerialID: 2,
+			PrevTx:   bobPrevTx,
+		},
+	}, {
+		name: "out of range",
+		msg: &lnwire.TxAddInput{
+			SerialID:  1,
+			PrevTx:    bobPrevTx,
+			PrevTxOut: 1,
+		},
+	}, {
+		name: "non segwit input",
+		msg: &lnwire.TxAddInput{
+			SerialID: 1,
+			PrevTx:   p2pkhTx,
+		},
+	}, {
+		name: "duplicate input",
+		msg: &lnwire.TxAddInput{
+			SerialID: 1,
+			PrevTx:   alicePrevTx,
+		},
+	}, {
+		name: "invalid sequence",
+		msg: &lnwire.TxAddInput{
+			SerialID: 1,
+			PrevTx:   bobPrevTx,
+			Sequence: wire.MaxTxInSequenceNum,
+		},
+	}, {
+		name: "dust output",
+		msg: &lnwire.TxAddOutput{
+			SerialID: 1,
+			Amount:   1,
+			PkScript: fundingOutput.PkScript,
+		},
+	}, {
+		name: "remove local input",
+		msg: &lnwire.TxRemoveInput{
+			SerialID: 0,
+		},
+	}, {
+		name: "remove unknown output",
+		msg: &lnwire.TxRemoveOutput{
+			SerialID: 3,
+		},
+	}}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			require.Error(t, alice.tx.processMsg(tc.msg))
+		})
+	}
+}
@@ -19,3 +19,23 @@ type Controller interface {
 	// represents a pending channel in the Controller implementation.
 	IsPendingChannel([32]byte, lnpeer.Peer) bool
 }
+
+// aliasHandler is an interface that abstracts the managing of the alias short
+// channel IDs of zero-conf and option-scid-alias channels.
+type aliasHandler interface {
+	// RequestAlias returns a new alias that hasn't been handed out yet.
+	RequestAlias() (lnwire.ShortChannelID, error)
+
+	// AddLocalAlias persists an alias for the channel identified by the
+	// passed base short channel ID.
+	AddLocalAlias(alias, base lnwire.ShortChannelID) error
+
+	// GetAliases returns all the aliases of the channel identified by the
+	// passed base short channel ID.
+	GetAliases(base lnwire.ShortChannelID) []lnwire.ShortChannelID
+
+	// PutPeerAlias stores the alias our peer sent us in its
+	// funding_locked message.
+	PutPeerAlias(chanID lnwire.ChannelID,
+		alias lnwire.ShortChannelID) error
+}
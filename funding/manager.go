@@ -5,16 +5,19 @@ import (
 	"encoding/binary"
 	"fmt"
 	"io"
+	"math"
 	"sync"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/aliasmgr"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/chainreg"
 	"github.com/lightningnetwork/lnd/chanacceptor"
@@ -151,6 +154,18 @@ type reservationWithCtx struct {
 	// the channel.
 	channelType *lnwire.ChannelType
 
+	// dualFundReq is the request for the remote party to contribute funds
+	// to a channel we initiated.
+	dualFundReq *lnwire.DualFundingRequest
+
+	// leaseReq is the request to lease the funds the remote party
+	// contributes to a channel we initiated.
+	leaseReq *lnwire.LeaseRequest
+
+	// interactiveTx tracks the interactive construction of the funding
+	// transaction of a dual funded channel.
+	interactiveTx *interactiveTx
+
 	updateMtx   sync.RWMutex
 	lastUpdated time.Time
 
@@ -197,6 +212,18 @@ type InitFundingMsg struct {
 	// LocalFundingAmt is the size of the channel.
 	LocalFundingAmt btcutil.Amount
 
+	// RemoteFundingAmt is the amount we request the remote party to
+	// contribute to the channel. If it is non-zero, a dual funded channel
+	// is requested, which the remote party may partially or fully
+	// decline.
+	RemoteFundingAmt btcutil.Amount
+
+	// LeaseRequest is set if we lease the funds the remote party
+	// contributes to the channel at the rates it advertised. The remote
+	// party's own balance within the channel is then delayed by at least
+	// the lease duration. It requires RemoteFundingAmt to be set.
+	LeaseRequest *lnwire.LeaseRequest
+
 	// PushAmt is the amount pushed to the counterparty.
 	PushAmt lnwire.MilliSatoshi
 
@@ -295,6 +322,10 @@ type Config struct {
 	// Lightning Network.
 	IDKey *btcec.PublicKey
 
+	// IDKeyLoc is the locator for the key that is used to identify this
+	// node within the LightningNetwork.
+	IDKeyLoc keychain.KeyLocator
+
 	// Wallet handles the parts of the funding process that involves moving
 	// funds from on-chain transaction outputs into Lightning channels.
 	Wallet *lnwallet.LightningWallet
@@ -316,15 +347,15 @@ type Config struct {
 	// so that the channel creation process can be completed.
 	Notifier chainntnfs.ChainNotifier
 
-	// SignMessage signs an arbitrary message with a given public key. The
+	// SignMessage signs an arbitrary message with a given key locator. The
 	// actual digest signed is the double sha-256 of the message. In the
-	// case that the private key corresponding to the passed public key
+	// case that the private key corresponding to the passed key locator
 	// cannot be located, then an error is returned.
 	//
 	// TODO(roasbeef): should instead pass on this responsibility to a
 	// distinct sub-system?
-	SignMessage func(pubKey *btcec.PublicKey,
-		msg []byte) (input.Signature, error)
+	SignMessage func(keyLoc keychain.KeyLocator,
+		msg []byte) (*ecdsa.Signature, error)
 
 	// CurrentNodeAnnouncement should return the latest, fully signed node
 	// announcement from the backing Lightning Network node.
@@ -459,6 +490,26 @@ type Config struct {
 	// MaxAnchorsCommitFeeRate is the max commitment fee rate we'll use as
 	// the initiator for channels of the anchor type.
 	MaxAnchorsCommitFeeRate chainfee.SatPerKWeight
+
+	// AliasManager keeps track of the alias short channel IDs of our
+	// zero-conf and option-scid-alias channels, as well as of the aliases
+	// our peers send us.
+	AliasManager aliasHandler
+
+	// DeleteAliasEdge removes the edge of a public zero-conf channel that
+	// was added to the graph under its alias once the channel is announced
+	// under its confirmed short channel ID.
+	DeleteAliasEdge func(scid lnwire.ShortChannelID) error
+
+	// MaxDualFundContribution is the maximum amount we contribute to a
+	// dual funded channel opened by a remote peer. If zero, we never
+	// contribute funds to remotely initiated channels.
+	MaxDualFundContribution btcutil.Amount
+
+	// LeaseRates are the rates we advertise for leasing out our
+	// contribution to dual funded channels. If nil, we don't lease out
+	// any funds.
+	LeaseRates *lnwire.LeaseRates
 }
 
 // Manager acts as an orchestrator/bridge between the wallet's
@@ -498,8 +549,13 @@ type Manager struct {
 	// signed by both parties.
 	signedReservations map[lnwire.ChannelID][32]byte
 
-	// resMtx guards both of the maps above to ensure that all access is
-	// goroutine safe.
+	// pendingTxSigs maps the channel ID of dual funded channels to the
+	// interactively constructed funding transaction for which we still
+	// need to exchange the signatures of the inputs.
+	pendingTxSigs map[lnwire.ChannelID]*interactiveTx
+
+	// resMtx guards the maps above to ensure that all access is goroutine
+	// safe.
 	resMtx sync.RWMutex
 
 	// fundingMsgs is a channel that relays fundingMsg structs from
@@ -571,6 +627,7 @@ func NewFundingManager(cfg Config) (*Manager, error) {
 		chanIDKey:                   cfg.TempChanIDSeed,
 		activeReservations:          make(map[serializedPubKey]pendingChannels),
 		signedReservations:          make(map[lnwire.ChannelID][32]byte),
+		pendingTxSigs:               make(map[lnwire.ChannelID]*interactiveTx),
 		newChanBarriers:             make(map[lnwire.ChannelID]chan struct{}),
 		fundingMsgs:                 make(chan *fundingMsg, msgBufferSize),
 		fundingRequests:             make(chan *InitFundingMsg, msgBufferSize),
@@ -830,6 +887,13 @@ func (f *Manager) reservationCoordinator() {
 				f.handleFundingCreated(fmsg.peer, msg)
 			case *lnwire.FundingSigned:
 				f.handleFundingSigned(fmsg.peer, msg)
+			case *lnwire.TxAddInput, *lnwire.TxAddOutput,
+				*lnwire.TxRemoveInput, *lnwire.TxRemoveOutput,
+				*lnwire.TxComplete:
+
+				f.handleInteractiveTxMsg(fmsg.peer, msg)
+			case *lnwire.TxSignatures:
+				f.handleTxSignatures(fmsg.peer, msg)
 			case *lnwire.FundingLocked:
 				f.wg.Add(1)
 				go f.handleFundingLocked(fmsg.peer, msg)
@@ -862,9 +926,17 @@ func (f *Manager) advanceFundingState(channel *channeldb.OpenChannel,
 	defer f.wg.Done()
 
 	// If the channel is still pending we must wait for the funding
-	// transaction to confirm.
+	// transaction to confirm, unless this is a zero-conf channel which we
+	// can mark as open right away.
 	if channel.IsPending {
-		err := f.advancePendingChannelState(channel, pendingChanID)
+		var err error
+		if channel.IsZeroConf() {
+			err = f.handleZeroConfOpen(channel)
+		} else {
+			err = f.advancePendingChannelState(
+				channel, pendingChanID,
+			)
+		}
 		if err != nil {
 			log.Errorf("Unable to advance pending state of "+
 				"ChannelPoint(%v): %v",
@@ -1131,6 +1203,154 @@ func (f *Manager) advancePendingChannelState(
 	return nil
 }
 
+// handleZeroConfOpen marks a zero-conf channel as open without waiting for its
+// funding transaction to confirm. Until the funding transaction confirms, the
+// channel is identified by an alias short channel ID, which also remains the
+// base short channel ID of the channel afterwards.
+func (f *Manager) handleZeroConfOpen(channel *channeldb.OpenChannel) error {
+	fundingPoint := channel.FundingOutpoint
+	chanID := lnwire.NewChanIDFromOutPoint(&fundingPoint)
+
+	// If we already assigned an alias to the channel before a restart,
+	// we'll re-use it. Otherwise we request a fresh one.
+	_, alias, err := f.getChannelOpeningState(&fundingPoint)
+	switch {
+	case err == ErrChannelNotFound:
+		newAlias, err := f.cfg.AliasManager.RequestAlias()
+		if err != nil {
+			return fmt.Errorf("unable to request alias: %v", err)
+		}
+		alias = &newAlias
+
+	case err != nil:
+		return fmt.Errorf("unable to query channel opening state: %v",
+			err)
+	}
+
+	// The alias is the base short channel ID of the channel, so it maps
+	// onto itself.
+	if err := f.cfg.AliasManager.AddLocalAlias(*alias, *alias); err != nil {
+		return fmt.Errorf("unable to add local alias: %v", err)
+	}
+
+	log.Infof("Zero-conf ChannelPoint(%v) is now active with alias %v",
+		fundingPoint, alias)
+
+	// We'll use the alias as the short channel ID of the channel, and
+	// continue the funding flow just like we would for a confirmed
+	// channel.
+	err = f.saveChannelOpeningState(&fundingPoint, markedOpen, alias)
+	if err != nil {
+		return fmt.Errorf("error setting channel state to markedOpen: %v",
+			err)
+	}
+
+	if err := channel.MarkAsOpen(*alias); err != nil {
+		return fmt.Errorf("error setting channel pending flag to false: "+
+			"%v", err)
+	}
+
+	// Inform the ChannelNotifier that the channel has transitioned from
+	// pending open to open.
+	f.cfg.NotifyOpenChannelEvent(fundingPoint)
+
+	// Close the discoverySignal channel, indicating that it is now
+	// acceptable to process funding locked messages from the peer.
+	f.localDiscoveryMtx.Lock()
+	if discoverySignal, ok := f.localDiscoverySignals[chanID]; ok {
+		close(discoverySignal)
+	}
+	f.localDiscoveryMtx.Unlock()
+
+	return nil
+}
+
+// waitForZeroConfChannel waits for the funding transaction of a zero-conf
+// channel to confirm, validates it and persists the confirmed short channel
+// ID of the channel. If the channel already confirmed before a restart, the
+// stored confirmed short channel ID is returned.
+func (f *Manager) waitForZeroConfChannel(
+	c *channeldb.OpenChannel) (lnwire.ShortChannelID, error) {
+
+	if c.IsConfirmed() {
+		return c.ConfirmedScid(), nil
+	}
+
+	confChan := make(chan *confirmedChannel)
+	cancelChan := make(chan struct{})
+	defer close(cancelChan)
+
+	f.wg.Add(1)
+	go f.waitForFundingConfirmation(c, cancelChan, confChan)
+
+	var (
+		confChannel *confirmedChannel
+		ok          bool
+	)
+	select {
+	case confChannel, ok = <-confChan:
+		if !ok {
+			return lnwire.ShortChannelID{}, fmt.Errorf("waiting "+
+				"for zero-conf funding confirmation of "+
+				"ChannelPoint(%v) failed", c.FundingOutpoint)
+		}
+
+	case <-f.quit:
+		return lnwire.ShortChannelID{}, ErrFundingManagerShuttingDown
+	}
+
+	err := f.cfg.Wallet.ValidateChannel(c, confChannel.fundingTx)
+	if err != nil {
+		return lnwire.ShortChannelID{}, fmt.Errorf("unable to "+
+			"validate channel: %v", err)
+	}
+
+	confirmedScid := confChannel.shortChanID
+	if err := c.MarkConfirmedScid(confirmedScid); err != nil {
+		return lnwire.ShortChannelID{}, fmt.Errorf("unable to store "+
+			"confirmed scid: %v", err)
+	}
+
+	// Unless the option-scid-alias channel type was negotiated, the
+	// channel can now also be referred to by its confirmed short channel
+	// ID.
+	if !c.IsOptionScidAlias() {
+		err := f.cfg.AliasManager.AddLocalAlias(
+			confirmedScid, c.ShortChanID(),
+		)
+		if err != nil {
+			return lnwire.ShortChannelID{}, fmt.Errorf("unable to "+
+				"add confirmed scid as alias: %v", err)
+		}
+	}
+
+	log.Infof("Zero-conf ChannelPoint(%v) with alias %v confirmed at %v",
+		c.FundingOutpoint, c.ShortChanID(), confirmedScid)
+
+	f.updateFundingLabel(c, confirmedScid)
+
+	return confirmedScid, nil
+}
+
+// updateFundingLabel updates the label of the funding transaction with the
+// confirmed short channel ID, if we opened the channel and lnd's wallet
+// published the funding transaction (which is not the case for some
+// channels). We do not label transactions we did not publish, because our
+// wallet has no knowledge of them.
+func (f *Manager) updateFundingLabel(c *channeldb.OpenChannel,
+	shortChanID lnwire.ShortChannelID) {
+
+	if !c.IsInitiator || !c.ChanType.HasFundingTx() {
+		return
+	}
+
+	label := labels.MakeLabel(labels.LabelTypeChannelOpen, &shortChanID)
+	err := f.cfg.UpdateLabel(c.FundingOutpoint.Hash, label)
+	if err != nil {
+		log.Errorf("unable to update label: %v", err)
+	}
+}
+
 // ProcessFundingMsg sends a message to the internal fundingManager goroutine,
 // allowing it to handle the lnwire.Message.
 func (f *Manager) ProcessFundingMsg(msg lnwire.Message, peer lnpeer.Peer) {
@@ -1294,6 +1514,35 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		return
 	}
 
+	// A zero-conf channel must both be requested through the channel type
+	// and be accepted by our channel acceptor, since we're trusting the
+	// initiator not to double spend the funding transaction.
+	zeroConf, scidAlias := zeroConfAndScidAlias(msg.ChannelType)
+	switch {
+	case zeroConf && !acceptorResp.ZeroConf:
+		err = errors.New("zero-conf channel not accepted")
+		log.Errorf("Rejecting zero-conf channel from peer(%x): %v",
+			peer.IdentityKey().SerializeCompressed(), err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+
+	case acceptorResp.ZeroConf && !zeroConf:
+		err = errors.New("zero-conf channel type not requested")
+		log.Errorf("Channel acceptor requires zero-conf for "+
+			"pendingId=%x: %v", msg.PendingChannelID, err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
+	// Channels using the option-scid-alias channel type are never
+	// announced, as their confirmed short channel ID is never revealed.
+	public := msg.ChannelFlags&lnwire.FFAnnounceChannel != 0
+	if scidAlias && public {
+		err = errors.New("option-scid-alias channel must be private")
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
 	chainHash := chainhash.Hash(msg.ChainHash)
 	req := &lnwallet.InitFundingReserveMsg{
 		ChainHash:        &chainHash,
@@ -1308,13 +1557,52 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		Flags:            msg.ChannelFlags,
 		MinConfs:         1,
 		CommitType:       commitType,
+		ZeroConf:         zeroConf,
+		OptionScidAlias:  scidAlias,
+	}
+
+	// If the initiator requested us to contribute funds to the channel,
+	// we'll first attempt to reserve our contribution. Should the wallet
+	// be unable to provide it, we fall back to not contributing at all.
+	var reservation *lnwallet.ChannelReservation
+	dualFundAmt := f.dualFundContribution(peer, msg, zeroConf)
+	if dualFundAmt > 0 {
+		dualReq := *req
+		dualReq.LocalFundingAmt = dualFundAmt
+		dualReq.FundingFeePerKw = chainfee.SatPerKWeight(
+			msg.DualFundingRequest.FundingFeePerKw,
+		)
+		dualReq.DualFundResponder = true
+
+		// If the initiator leases our contribution, it pays us the
+		// lease fee.
+		if lease := msg.LeaseRequest; lease != nil {
+			dualReq.Lease = &channeldb.LeaseTerms{
+				Duration: lease.Duration,
+				Amount:   dualFundAmt,
+				Fee:      lease.Fee(dualFundAmt),
+			}
+		}
+
+		reservation, err = f.cfg.Wallet.InitChannelReservation(
+			&dualReq,
+		)
+		if err != nil {
+			log.Warnf("Unable to contribute %v to pendingId=%x, "+
+				"continuing without contribution: %v",
+				dualFundAmt, msg.PendingChannelID, err)
+
+			dualFundAmt = 0
+		}
 	}
 
-	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
-	if err != nil {
-		log.Errorf("Unable to initialize reservation: %v", err)
-		f.failFundingFlow(peer, msg.PendingChannelID, err)
-		return
+	if dualFundAmt == 0 {
+		reservation, err = f.cfg.Wallet.InitChannelReservation(req)
+		if err != nil {
+			log.Errorf("Unable to initialize reservation: %v", err)
+			f.failFundingFlow(peer, msg.PendingChannelID, err)
+			return
+		}
 	}
 
 	// As we're the responder, we get to specify the number of confirmations
@@ -1327,6 +1615,12 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 	if acceptorResp.MinAcceptDepth != 0 {
 		numConfsReq = acceptorResp.MinAcceptDepth
 	}
+
+	// A zero-conf channel can be used right away, so we don't require any
+	// confirmations.
+	if zeroConf {
+		numConfsReq = 0
+	}
 	reservation.SetNumConfsRequired(numConfsReq)
 
 	// We'll also validate and apply all the constraints the initiating
@@ -1339,9 +1633,17 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		MaxAcceptedHtlcs: msg.MaxAcceptedHTLCs,
 		CsvDelay:         msg.CsvDelay,
 	}
-	err = reservation.CommitConstraints(
-		channelConstraints, f.cfg.MaxLocalCSVDelay,
-	)
+
+	// If we lease out our contribution, our balance is delayed for at
+	// least the lease duration, which may exceed our usual maximum.
+	maxLocalCsv := f.cfg.MaxLocalCSVDelay
+	lease := msg.LeaseRequest
+	if dualFundAmt > 0 && lease != nil &&
+		uint32(maxLocalCsv) < lease.Duration {
+
+		maxLocalCsv = uint16(lease.Duration)
+	}
+	err = reservation.CommitConstraints(channelConstraints, maxLocalCsv)
 	if err != nil {
 		log.Errorf("Unacceptable channel constraints: %v", err)
 		f.failFundingFlow(peer, msg.PendingChannelID, err)
@@ -1380,23 +1682,26 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		commitType, msg.UpfrontShutdownScript)
 
 	// Generate our required constraints for the remote party, using the
-	// values provided by the channel acceptor if they are non-zero.
-	remoteCsvDelay := f.cfg.RequiredRemoteDelay(amt)
+	// values provided by the channel acceptor if they are non-zero. They
+	// are based on the full capacity of the channel, which includes our
+	// contribution for dual funded channels.
+	capacity := reservation.Capacity()
+	remoteCsvDelay := f.cfg.RequiredRemoteDelay(capacity)
 	if acceptorResp.CSVDelay != 0 {
 		remoteCsvDelay = acceptorResp.CSVDelay
 	}
 
-	chanReserve := f.cfg.RequiredRemoteChanReserve(amt, msg.DustLimit)
+	chanReserve := f.cfg.RequiredRemoteChanReserve(capacity, msg.DustLimit)
 	if acceptorResp.Reserve != 0 {
 		chanReserve = acceptorResp.Reserve
 	}
 
-	remoteMaxValue := f.cfg.RequiredRemoteMaxValue(amt)
+	remoteMaxValue := f.cfg.RequiredRemoteMaxValue(capacity)
 	if acceptorResp.InFlightTotal != 0 {
 		remoteMaxValue = acceptorResp.InFlightTotal
 	}
 
-	maxHtlcs := f.cfg.RequiredRemoteMaxHTLCs(amt)
+	maxHtlcs := f.cfg.RequiredRemoteMaxHTLCs(capacity)
 	if acceptorResp.HtlcLimit != 0 {
 		maxHtlcs = acceptorResp.HtlcLimit
 	}
@@ -1417,7 +1722,7 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 	}
 	resCtx := &reservationWithCtx{
 		reservation:    reservation,
-		chanAmt:        amt,
+		chanAmt:        capacity,
 		remoteCsvDelay: remoteCsvDelay,
 		remoteMinHtlc:  minHtlc,
 		remoteMaxValue: remoteMaxValue,
@@ -1465,7 +1770,14 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		},
 		UpfrontShutdown: msg.UpfrontShutdownScript,
 	}
-	err = reservation.ProcessSingleContribution(remoteContribution)
+	if dualFundAmt > 0 {
+		err = f.startInteractiveTx(
+			resCtx, msg.PendingChannelID, remoteContribution,
+			msg.DualFundingRequest, dualFundAmt, amt,
+		)
+	} else {
+		err = reservation.ProcessSingleContribution(remoteContribution)
+	}
 	if err != nil {
 		log.Errorf("unable to add contribution reservation: %v", err)
 		f.failFundingFlow(peer, msg.PendingChannelID, err)
@@ -1498,6 +1810,10 @@ func (f *Manager) handleFundingOpen(peer lnpeer.Peer,
 		UpfrontShutdownScript: ourContribution.UpfrontShutdown,
 		ChannelType:           msg.ChannelType,
 	}
+	if dualFundAmt > 0 {
+		contribution := lnwire.DualFundingAmount(dualFundAmt)
+		fundingAccept.DualFundingAmount = &contribution
+	}
 
 	if err := peer.SendMessage(true, &fundingAccept); err != nil {
 		log.Errorf("unable to send funding response to peer: %v", err)
@@ -1551,6 +1867,17 @@ func (f *Manager) handleFundingAccept(peer lnpeer.Peer,
 		return
 	}
 
+	// If we requested a zero-conf channel, the responder must not require
+	// any confirmations.
+	zeroConf, _ := zeroConfAndScidAlias(resCtx.channelType)
+	if zeroConf && msg.MinAcceptDepth != 0 {
+		err := fmt.Errorf("zero-conf channel requires min accept "+
+			"depth of zero, got %v", msg.MinAcceptDepth)
+		log.Warnf("Unacceptable channel constraints: %v", err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+
 	// The required number of confirmations should not be greater than the
 	// maximum number of confirmations required by the ChainNotifier to
 	// properly dispatch confirmations.
@@ -1563,6 +1890,51 @@ func (f *Manager) handleFundingAccept(peer lnpeer.Peer,
 		return
 	}
 
+	// If the responder agreed to contribute funds to the channel, we'll
+	// add them to our reservation, so the constraints below are checked
+	// against the full capacity of the channel.
+	var remoteFundingAmt btcutil.Amount
+	if msg.DualFundingAmount != nil {
+		remoteFundingAmt = btcutil.Amount(*msg.DualFundingAmount)
+	}
+	if remoteFundingAmt == 0 && resCtx.leaseReq != nil {
+		err := errors.New("remote party declined lease")
+		log.Warnf("Unacceptable channel constraints: %v", err)
+		f.failFundingFlow(peer, msg.PendingChannelID, err)
+		return
+	}
+	if remoteFundingAmt > 0 {
+		if resCtx.dualFundReq == nil ||
+			remoteFundingAmt > resCtx.dualFundReq.RequestedAmount {
+
+			err := fmt.Errorf("unexpected remote contribution "+
+				"of %v", remoteFundingAmt)
+			log.Warnf("Unacceptable channel constraints: %v", err)
+			f.failFundingFlow(peer, msg.PendingChannelID, err)
+			return
+		}
+
+		// If we lease the remote party's contribution, we pay the
+		// lease fee out of our balance.
+		var lease *channeldb.LeaseTerms
+		if resCtx.leaseReq != nil {
+			lease = &channeldb.LeaseTerms{
+				Duration: resCtx.leaseReq.Duration,
+				Amount:   remoteFundingAmt,
+				Fee:      resCtx.leaseReq.Fee(remoteFundingAmt),
+			}
+		}
+
+		err := resCtx.reservation.AddRemoteFunding(
+			remoteFundingAmt, lease,
+		)
+		if err != nil {
+			log.Errorf("Unable to add remote contribution: %v", err)
+			f.failFundingFlow(peer, msg.PendingChannelID, err)
+			return
+		}
+	}
+
 	// We'll also specify the responder's preference for the number of
 	// required confirmations, and also the set of channel constraints
 	// they've specified for commitment states we can create.
@@ -1622,6 +1994,23 @@ func (f *Manager) handleFundingAccept(peer lnpeer.Peer,
 		},
 		UpfrontShutdown: msg.UpfrontShutdownScript,
 	}
+
+	// For a dual funded channel, the funding transaction is constructed
+	// interactively before we can continue with the funding flow.
+	if remoteFundingAmt > 0 {
+		err = f.startInteractiveTx(
+			resCtx, pendingChanID, remoteContribution,
+			resCtx.dualFundReq, resCtx.chanAmt, remoteFundingAmt,
+		)
+		if err != nil {
+			log.Errorf("Unable to construct funding tx with %v: %v",
+				peerKey, err)
+			f.failFundingFlow(peer, msg.PendingChannelID, err)
+		}
+
+		return
+	}
+
 	err = resCtx.reservation.ProcessContribution(remoteContribution)
 
 	// The wallet has detected that a PSBT funding process was requested by
@@ -1814,6 +2203,303 @@ func (f *Manager) continueFundingAccept(resCtx *reservationWithCtx,
 	}
 }
 
+// dualFundContribution returns the amount we contribute to a channel opened
+// by the remote party. We only contribute if the initiator requested it, we
+// support dual funded channels and are configured to contribute funds. Our
+// contribution is capped so the channel stays within our maximum channel
+// size.
+func (f *Manager) dualFundContribution(peer lnpeer.Peer,
+	msg *lnwire.OpenChannel, zeroConf bool) btcutil.Amount {
+
+	req := msg.DualFundingRequest
+	switch {
+	case req == nil || req.RequestedAmount == 0:
+		return 0
+
+	case f.cfg.MaxDualFundContribution == 0:
+		return 0
+
+	case !peer.LocalFeatures().HasFeature(lnwire.DualFundOptional):
+		return 0
+
+	// We won't contribute funds to a zero-conf channel, as the initiator
+	// could double spend the funding transaction, and there's nothing to
+	// contribute to if funds are pushed to us.
+	case zeroConf || msg.PushAmount != 0:
+		return 0
+
+	// If the initiator leases our contribution, we only contribute if it
+	// pays at least the rates we advertise for a duration we offer.
+	case msg.LeaseRequest != nil && !f.acceptLease(msg.LeaseRequest):
+		return 0
+	}
+
+	amt := req.RequestedAmount
+	if amt > f.cfg.MaxDualFundContribution {
+		amt = f.cfg.MaxDualFundContribution
+	}
+	if amt > f.cfg.MaxChanSize-msg.FundingAmount {
+		amt = f.cfg.MaxChanSize - msg.FundingAmount
+	}
+
+	return amt
+}
+
+// acceptLease returns true if the initiator of a dual funded channel leases
+// our contribution at no less than the rates we advertise, and for a
+// duration we offer.
+func (f *Manager) acceptLease(req *lnwire.LeaseRequest) bool {
+	rates := f.cfg.LeaseRates
+	switch {
+	case rates == nil:
+		log.Debugf("Declining lease, we don't lease out funds")
+		return false
+
+	case req.Duration == 0 || req.Duration > rates.MaxDuration:
+		log.Debugf("Declining lease of %v blocks, maximum is %v",
+			req.Duration, rates.MaxDuration)
+		return false
+
+	case req.FeeBase < rates.FeeBase || req.FeeBasis < rates.FeeBasis:
+		log.Debugf("Declining lease with fee base %v and basis %v, "+
+			"below our rates", req.FeeBase, req.FeeBasis)
+		return false
+	}
+
+	return true
+}
+
+// startInteractiveTx starts the interactive construction of the funding
+// transaction of a dual funded channel. We add our own inputs and change
+// outputs, and if we're the initiator also the funding output and send the
+// first message to the remote party.
+func (f *Manager) startInteractiveTx(resCtx *reservationWithCtx,
+	pendingChanID [32]byte,
+	remoteContribution *lnwallet.ChannelContribution,
+	req *lnwire.DualFundingRequest, localAmt,
+	remoteAmt btcutil.Amount) error {
+
+	reservation := resCtx.reservation
+	err := reservation.ProcessInteractiveContribution(remoteContribution)
+	if err != nil {
+		return err
+	}
+
+	fundingOutput, err := reservation.FundingOutput()
+	if err != nil {
+		return err
+	}
+
+	initiator := resCtx.dualFundReq != nil
+	itx := newInteractiveTx(
+		lnwire.ChannelID(pendingChanID), initiator, req.LockTime,
+		chainfee.SatPerKWeight(req.FundingFeePerKw), localAmt,
+		remoteAmt, fundingOutput,
+	)
+
+	coins, changeOutputs := reservation.FundingInputs()
+	for _, coin := range coins {
+		prevTx, err := f.cfg.Wallet.FetchTx(coin.OutPoint.Hash)
+		if err != nil {
+			return err
+		}
+
+		err = itx.addLocalInput(
+			prevTx, coin.OutPoint.Index, maxInteractiveTxSequence,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	for _, changeOutput := range changeOutputs {
+		itx.addLocalOutput(changeOutput)
+	}
+	resCtx.interactiveTx = itx
+
+	log.Infof("Constructing funding tx for pending_id(%x) interactively, "+
+		"local_amt=%v, remote_amt=%v", pendingChanID[:], localAmt,
+		remoteAmt)
+
+	// The initiator adds the funding output and kicks off the
+	// construction of the transaction.
+	if !initiator {
+		return nil
+	}
+
+	itx.addLocalOutput(fundingOutput)
+
+	return resCtx.peer.SendMessage(true, itx.nextMsg())
+}
+
+// handleInteractiveTxMsg processes a message of the remote party that adds
+// to, removes from, or completes the funding transaction of a dual funded
+// channel. We respond with our next message until both parties completed the
+// transaction.
+func (f *Manager) handleInteractiveTxMsg(peer lnpeer.Peer,
+	msg lnwire.Message) {
+
+	var chanID lnwire.ChannelID
+	switch msg := msg.(type) {
+	case *lnwire.TxAddInput:
+		chanID = msg.ChannelID
+	case *lnwire.TxAddOutput:
+		chanID = msg.ChannelID
+	case *lnwire.TxRemoveInput:
+		chanID = msg.ChannelID
+	case *lnwire.TxRemoveOutput:
+		chanID = msg.ChannelID
+	case *lnwire.TxComplete:
+		chanID = msg.ChannelID
+	}
+
+	pendingChanID := [32]byte(chanID)
+	peerKey := peer.IdentityKey()
+	resCtx, err := f.getReservationCtx(peerKey, pendingChanID)
+	if err != nil {
+		log.Warnf("Can't find reservation (peerKey:%v, chan_id:%v)",
+			peerKey, chanID)
+		return
+	}
+
+	// Update the timestamp once the message has been handled.
+	defer resCtx.updateTimestamp()
+
+	itx := resCtx.interactiveTx
+	if itx == nil {
+		err := fmt.Errorf("pending_id(%x) isn't dual funded",
+			pendingChanID[:])
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+
+	if err := itx.processMsg(msg); err != nil {
+		log.Errorf("Invalid %v for pending_id(%x): %v", msg.MsgType(),
+			pendingChanID[:], err)
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+
+	// Unless the transaction is already complete, it's our turn to send
+	// the next message.
+	if !itx.isComplete() {
+		if err := peer.SendMessage(true, itx.nextMsg()); err != nil {
+			log.Errorf("Unable to send interactive tx message: %v",
+				err)
+			f.failFundingFlow(peer, pendingChanID, err)
+			return
+		}
+	}
+	if !itx.isComplete() {
+		return
+	}
+
+	// Both parties agreed on the funding transaction, so we can sign our
+	// inputs and create the commitment transactions.
+	fundingTx, err := itx.buildTx()
+	if err != nil {
+		log.Errorf("Invalid funding tx for pending_id(%x): %v",
+			pendingChanID[:], err)
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+
+	err = resCtx.reservation.ProcessInteractiveTx(fundingTx)
+	if err != nil {
+		log.Errorf("Unable to process funding tx for pending_id(%x): "+
+			"%v", pendingChanID[:], err)
+		f.failFundingFlow(peer, pendingChanID, err)
+		return
+	}
+	itx.signedTx = resCtx.reservation.FinalFundingTx()
+
+	log.Infof("Constructed funding tx %v for pending_id(%x)",
+		fundingTx.TxHash(), pendingChanID[:])
+
+	// As the initiator, we continue the funding flow by sending our
+	// signature for the responder's commitment transaction.
+	if itx.initiator {
+		f.continueFundingAccept(resCtx, pendingChanID)
+	}
+}
+
+// startTxSigsExchange registers the funding transaction of a dual funded
+// channel, for which the signatures of the inputs are exchanged once the
+// commitment signatures were exchanged. If we need to send our signatures
+// first, we'll do so now.
+func (f *Manager) startTxSigsExchange(peer lnpeer.Peer,
+	chanID lnwire.ChannelID, resCtx *reservationWithCtx) error {
+
+	itx := resCtx.interactiveTx
+	itx.chanID = chanID
+
+	f.resMtx.Lock()
+	f.pendingTxSigs[chanID] = itx
+	f.resMtx.Unlock()
+
+	if !itx.sendSigsFirst() {
+		return nil
+	}
+
+	return f.sendTxSigs(peer, itx)
+}
+
+// sendTxSigs sends the signatures of our inputs to the funding transaction
+// of a dual funded channel to the remote party.
+func (f *Manager) sendTxSigs(peer lnpeer.Peer, itx *interactiveTx) error {
+	msg, err := itx.localSigs(itx.signedTx)
+	if err != nil {
+		return err
+	}
+	itx.sentSigs = true
+
+	return peer.SendMessage(true, msg)
+}
+
+// handleTxSignatures processes the signatures of the remote party's inputs to
+// the funding transaction of a dual funded channel. Once they're verified, we
+// send our own signatures if we haven't already and broadcast the funding
+// transaction.
+func (f *Manager) handleTxSignatures(peer lnpeer.Peer,
+	msg *lnwire.TxSignatures) {
+
+	f.resMtx.Lock()
+	itx, ok := f.pendingTxSigs[msg.ChannelID]
+	delete(f.pendingTxSigs, msg.ChannelID)
+	f.resMtx.Unlock()
+	if !ok {
+		log.Warnf("Received unexpected tx_signatures for "+
+			"ChannelID(%v)", msg.ChannelID)
+		return
+	}
+
+	fundingTx, err := itx.applyRemoteSigs(itx.signedTx, msg)
+	if err != nil {
+		log.Errorf("Invalid tx_signatures for ChannelID(%v): %v",
+			msg.ChannelID, err)
+		f.failFundingFlow(peer, msg.ChannelID, err)
+		return
+	}
+
+	if !itx.sentSigs {
+		if err := f.sendTxSigs(peer, itx); err != nil {
+			log.Errorf("Unable to send tx_signatures for "+
+				"ChannelID(%v): %v", msg.ChannelID, err)
+		}
+	}
+
+	log.Infof("Broadcasting dual funded funding tx %v for ChannelID(%v)",
+		fundingTx.TxHash(), msg.ChannelID)
+
+	// Set a nil short channel ID at this stage because we do not know it
+	// until our funding tx confirms.
+	label := labels.MakeLabel(labels.LabelTypeChannelOpen, nil)
+	if err := f.cfg.PublishTransaction(fundingTx, label); err != nil {
+		log.Errorf("Unable to broadcast funding tx %v for "+
+			"ChannelID(%v): %v", fundingTx.TxHash(), msg.ChannelID,
+			err)
+	}
+}
+
 // handleFundingCreated progresses the funding workflow when the daemon is on
 // the responding side of a single funder workflow. Once this message has been
 // processed, a signature is sent to the remote peer allowing it to broadcast
@@ -1851,10 +2537,28 @@ func (f *Manager) handleFundingCreated(peer lnpeer.Peer,
 	// funding workflow to the next stage. If this succeeds then the
 	// funding transaction will broadcast after our next message.
 	// CompleteReservationSingle will also mark the channel as 'IsPending'
-	// in the database.
-	completeChan, err := resCtx.reservation.CompleteReservationSingle(
-		&fundingOut, commitSig,
-	)
+	// in the database. If the channel is dual funded, we already know the
+	// funding transaction, so we only need to verify their signature.
+	var completeChan *channeldb.OpenChannel
+	if resCtx.reservation.IsInteractive() {
+		if *resCtx.reservation.FundingOutpoint() != fundingOut {
+			err := fmt.Errorf("funding outpoint %v doesn't match "+
+				"interactively constructed funding tx",
+				fundingOut)
+			log.Errorf("Unable to complete reservation: %v", err)
+			f.failFundingFlow(peer, pendingChanID, err)
+			return
+		}
+
+		completeChan, err = resCtx.reservation.CompleteReservation(
+			nil, commitSig,
+		)
+	} else {
+		reservation := resCtx.reservation
+		completeChan, err = reservation.CompleteReservationSingle(
+			&fundingOut, commitSig,
+		)
+	}
 	if err != nil {
 		// TODO(roasbeef): better error logging: peerID, channelID, etc.
 		log.Errorf("unable to complete single reservation: %v", err)
@@ -1928,6 +2632,16 @@ func (f *Manager) handleFundingCreated(peer lnpeer.Peer,
 		return
 	}
 
+	// For a dual funded channel, both parties now exchange the signatures
+	// for their inputs to the funding transaction.
+	if resCtx.interactiveTx != nil {
+		err := f.startTxSigsExchange(peer, channelID, resCtx)
+		if err != nil {
+			log.Errorf("Unable to send tx_signatures for "+
+				"ChannelPoint(%v): %v", fundingOut, err)
+		}
+	}
+
 	// Now that we've sent over our final signature for this channel, we'll
 	// send it to the ChainArbitrator so it can watch for any on-chain
 	// actions during this final confirmation stage.
@@ -2034,8 +2748,18 @@ func (f *Manager) handleFundingSigned(peer lnpeer.Peer,
 	f.deleteReservationCtx(peerKey, pendingChanID)
 
 	// Broadcast the finalized funding transaction to the network, but only
-	// if we actually have the funding transaction.
-	if completeChan.ChanType.HasFundingTx() {
+	// if we actually have the funding transaction. The funding transaction
+	// of a dual funded channel can only be broadcast once both parties
+	// exchanged the signatures for their inputs.
+	switch {
+	case resCtx.interactiveTx != nil:
+		err := f.startTxSigsExchange(peer, permChanID, resCtx)
+		if err != nil {
+			log.Errorf("Unable to send tx_signatures for "+
+				"ChannelPoint(%v): %v", fundingPoint, err)
+		}
+
+	case completeChan.ChanType.HasFundingTx():
 		fundingTx := completeChan.FundingTxn
 		var fundingTxBuf bytes.Buffer
 		if err := fundingTx.Serialize(&fundingTxBuf); err != nil {
@@ -2215,6 +2939,14 @@ func (f *Manager) waitForFundingConfirmation(
 		return
 	}
 	numConfs := uint32(completeChan.NumConfsRequired)
+
+	// A zero-conf channel doesn't require any confirmations to be used,
+	// but we still wait for the first one to learn its position within
+	// the chain.
+	if numConfs == 0 {
+		numConfs = 1
+	}
+
 	confNtfn, err := f.cfg.Notifier.RegisterConfirmationsNtfn(
 		&txid, fundingScript, numConfs,
 		completeChan.FundingBroadcastHeight,
@@ -2363,6 +3095,40 @@ func (f *Manager) handleFundingConfirmation(
 		return fmt.Errorf("unable to validate channel: %v", err)
 	}
 
+	// Channels of the option-scid-alias channel type never use their
+	// confirmed short channel ID, instead they're identified by an alias.
+	// If we already assigned an alias before a restart, we'll re-use it.
+	shortChanID := confChannel.shortChanID
+	if completeChan.IsOptionScidAlias() {
+		_, alias, err := f.getChannelOpeningState(&fundingPoint)
+		switch {
+		case err == ErrChannelNotFound:
+			newAlias, err := f.cfg.AliasManager.RequestAlias()
+			if err != nil {
+				return fmt.Errorf("unable to request alias: %v",
+					err)
+			}
+			alias = &newAlias
+
+		case err != nil:
+			return fmt.Errorf("unable to query channel opening "+
+				"state: %v", err)
+		}
+
+		err = f.cfg.AliasManager.AddLocalAlias(*alias, *alias)
+		if err != nil {
+			return fmt.Errorf("unable to add local alias: %v", err)
+		}
+
+		err = completeChan.MarkConfirmedScid(confChannel.shortChanID)
+		if err != nil {
+			return fmt.Errorf("unable to store confirmed scid: %v",
+				err)
+		}
+
+		shortChanID = *alias
+	}
+
 	// The funding transaction now being confirmed, we add this channel to
 	// the fundingManager's internal persistent state machine that we use
 	// to track the remaining process of the channel opening. This is
@@ -2370,7 +3136,7 @@ func (f *Manager) handleFundingConfirmation(
 	// opening state before we mark the channel opened in the database,
 	// such that we can receover from one of the db writes failing.
 	err = f.saveChannelOpeningState(
-		&fundingPoint, markedOpen, &confChannel.shortChanID,
+		&fundingPoint, markedOpen, &shortChanID,
 	)
 	if err != nil {
 		return fmt.Errorf("error setting channel state to markedOpen: %v",
@@ -2379,7 +3145,7 @@ func (f *Manager) handleFundingConfirmation(
 
 	// Now that the channel has been fully confirmed and we successfully
 	// saved the opening state, we'll mark it as open within the database.
-	err = completeChan.MarkAsOpen(confChannel.shortChanID)
+	err = completeChan.MarkAsOpen(shortChanID)
 	if err != nil {
 		return fmt.Errorf("error setting channel pending flag to false: "+
 			"%v", err)
@@ -2397,24 +3163,9 @@ func (f *Manager) handleFundingConfirmation(
 		log.Errorf("unable to report short chan id: %v", err)
 	}
 
-	// If we opened the channel, and lnd's wallet published our funding tx
-	// (which is not the case for some channels) then we update our
-	// transaction label with our short channel ID, which is known now that
-	// our funding transaction has confirmed. We do not label transactions
-	// we did not publish, because our wallet has no knowledge of them.
-	if completeChan.IsInitiator && completeChan.ChanType.HasFundingTx() {
-		shortChanID := completeChan.ShortChanID()
-		label := labels.MakeLabel(
-			labels.LabelTypeChannelOpen, &shortChanID,
-		)
-
-		err = f.cfg.UpdateLabel(
-			completeChan.FundingOutpoint.Hash, label,
-		)
-		if err != nil {
-			log.Errorf("unable to update label: %v", err)
-		}
-	}
+	// Now that our funding transaction has confirmed, we update its label
+	// with the confirmed short channel ID.
+	f.updateFundingLabel(completeChan, confChannel.shortChanID)
 
 	// Close the discoverySignal channel, indicating to a separate
 	// goroutine that the channel now is marked as open in the database
@@ -2473,6 +3224,21 @@ func (f *Manager) sendFundingLocked(
 			return ErrFundingManagerShuttingDown
 		}
 
+		// If the channel is identified by an alias, or both of us
+		// support the scid-alias feature, we'll send an alias along
+		// which our peer can use in route hints for the channel.
+		if completeChan.HasAliasScid() || hasFeatures(
+			peer.LocalFeatures(), peer.RemoteFeatures(),
+			lnwire.ScidAliasOptional,
+		) {
+
+			alias, err := f.fundingLockedAlias(*shortChanID)
+			if err != nil {
+				return err
+			}
+			fundingLockedMsg.AliasScid = &alias
+		}
+
 		log.Infof("Peer(%x) is online, sending FundingLocked "+
 			"for ChannelID(%v)", peerKey, chanID)
 
@@ -2489,6 +3255,33 @@ func (f *Manager) sendFundingLocked(
 	return nil
 }
 
+// fundingLockedAlias returns the alias we send to our peer in the
+// fundingLocked message of the channel identified by the passed base short
+// channel ID. If the channel doesn't have an alias yet, a new one is created.
+func (f *Manager) fundingLockedAlias(
+	baseScid lnwire.ShortChannelID) (lnwire.ShortChannelID, error) {
+
+	for _, alias := range f.cfg.AliasManager.GetAliases(baseScid) {
+		if aliasmgr.IsAlias(alias) {
+			return alias, nil
+		}
+	}
+
+	alias, err := f.cfg.AliasManager.RequestAlias()
+	if err != nil {
+		return lnwire.ShortChannelID{}, fmt.Errorf("unable to request "+
+			"alias: %v", err)
+	}
+
+	err = f.cfg.AliasManager.AddLocalAlias(alias, baseScid)
+	if err != nil {
+		return lnwire.ShortChannelID{}, fmt.Errorf("unable to add "+
+			"local alias: %v", err)
+	}
+
+	return alias, nil
+}
+
 // addToRouterGraph sends a ChannelAnnouncement and a ChannelUpdate to the
 // gossiper so that the channel is added to the Router's internal graph.
 // These announcement messages are NOT broadcasted to the greater network,
@@ -2522,7 +3315,7 @@ func (f *Manager) addToRouterGraph(completeChan *channeldb.OpenChannel,
 
 	ann, err := f.newChanAnnouncement(
 		f.cfg.IDKey, completeChan.IdentityPub,
-		completeChan.LocalChanCfg.MultiSigKey.PubKey,
+		&completeChan.LocalChanCfg.MultiSigKey,
 		completeChan.RemoteChanCfg.MultiSigKey.PubKey, *shortChanID,
 		chanID, fwdMinHTLC, fwdMaxHTLC,
 	)
@@ -2582,10 +3375,40 @@ func (f *Manager) addToRouterGraph(completeChan *channeldb.OpenChannel,
 func (f *Manager) annAfterSixConfs(completeChan *channeldb.OpenChannel,
 	shortChanID *lnwire.ShortChannelID) error {
 
+	announceChan := completeChan.ChannelFlags&lnwire.FFAnnounceChannel != 0
+
+	// A zero-conf channel was added to the router graph under its alias
+	// before its funding transaction confirmed. We'll wait for the
+	// confirmation now, and if the channel is public, replace the alias
+	// edge with one using the confirmed short channel ID which is the one
+	// that gets announced.
+	if completeChan.IsZeroConf() {
+		confirmedScid, err := f.waitForZeroConfChannel(completeChan)
+		if err != nil {
+			return err
+		}
+
+		if announceChan {
+			err := f.cfg.DeleteAliasEdge(*shortChanID)
+			if err != nil && err != channeldb.ErrEdgeNotFound {
+				return fmt.Errorf("unable to delete alias "+
+					"edge: %v", err)
+			}
+
+			err = f.addToRouterGraph(completeChan, &confirmedScid)
+			if err != nil {
+				return fmt.Errorf("failed adding confirmed "+
+					"zero-conf channel to router graph: %v",
+					err)
+			}
+		}
+
+		shortChanID = &confirmedScid
+	}
+
 	// If this channel is not meant to be announced to the greater network,
 	// we'll only send our NodeAnnouncement to our counterparty to ensure we
 	// don't leak any of our information.
-	announceChan := completeChan.ChannelFlags&lnwire.FFAnnounceChannel != 0
 	if !announceChan {
 		log.Debugf("Will not announce private channel %v.",
 			shortChanID.ToUint64())
@@ -2685,7 +3508,7 @@ func (f *Manager) annAfterSixConfs(completeChan *channeldb.OpenChannel,
 		// public and usable for other nodes for routing.
 		err = f.announceChannel(
 			f.cfg.IDKey, completeChan.IdentityPub,
-			completeChan.LocalChanCfg.MultiSigKey.PubKey,
+			&completeChan.LocalChanCfg.MultiSigKey,
 			completeChan.RemoteChanCfg.MultiSigKey.PubKey,
 			*shortChanID, chanID,
 		)
@@ -2767,6 +3590,18 @@ func (f *Manager) handleFundingLocked(peer lnpeer.Peer,
 		return
 	}
 
+	// If our peer sent us an alias for the channel, we'll store it so we
+	// can use it in route hints. We do this before checking for duplicate
+	// messages, as the peer may resend the message with a new alias.
+	if msg.AliasScid != nil {
+		err := f.cfg.AliasManager.PutPeerAlias(chanID, *msg.AliasScid)
+		if err != nil {
+			log.Errorf("Unable to store peer alias for "+
+				"ChannelID(%v): %v", chanID, err)
+			return
+		}
+	}
+
 	// If the RemoteNextRevocation is non-nil, it means that we have
 	// already processed fundingLocked for this channel, so ignore.
 	if channel.RemoteNextRevocation != nil {
@@ -2825,8 +3660,9 @@ type chanAnnouncement struct {
 // identity pub keys of both parties to the channel, and the second segment is
 // authenticated only by us and contains our directional routing policy for the
 // channel.
-func (f *Manager) newChanAnnouncement(localPubKey, remotePubKey,
-	localFundingKey, remoteFundingKey *btcec.PublicKey,
+func (f *Manager) newChanAnnouncement(localPubKey,
+	remotePubKey *btcec.PublicKey, localFundingKey *keychain.KeyDescriptor,
+	remoteFundingKey *btcec.PublicKey,
 	shortChanID lnwire.ShortChannelID, chanID lnwire.ChannelID,
 	fwdMinHTLC, fwdMaxHTLC lnwire.MilliSatoshi) (*chanAnnouncement, error) {
 
@@ -2856,7 +3692,10 @@ func (f *Manager) newChanAnnouncement(localPubKey, remotePubKey,
 	if bytes.Compare(selfBytes, remoteBytes) == -1 {
 		copy(chanAnn.NodeID1[:], localPubKey.SerializeCompressed())
 		copy(chanAnn.NodeID2[:], remotePubKey.SerializeCompressed())
-		copy(chanAnn.BitcoinKey1[:], localFundingKey.SerializeCompressed())
+		copy(
+			chanAnn.BitcoinKey1[:],
+			localFundingKey.PubKey.SerializeCompressed(),
+		)
 		copy(chanAnn.BitcoinKey2[:], remoteFundingKey.SerializeCompressed())
 
 		// If we're the first node then update the chanFlags to
@@ -2866,7 +3705,10 @@ func (f *Manager) newChanAnnouncement(localPubKey, remotePubKey,
 		copy(chanAnn.NodeID1[:], remotePubKey.SerializeCompressed())
 		copy(chanAnn.NodeID2[:], localPubKey.SerializeCompressed())
 		copy(chanAnn.BitcoinKey1[:], remoteFundingKey.SerializeCompressed())
-		copy(chanAnn.BitcoinKey2[:], localFundingKey.SerializeCompressed())
+		copy(
+			chanAnn.BitcoinKey2[:],
+			localFundingKey.PubKey.SerializeCompressed(),
+		)
 
 		// If we're the second node then update the chanFlags to
 		// indicate the "direction" of the update.
@@ -2905,7 +3747,7 @@ func (f *Manager) newChanAnnouncement(localPubKey, remotePubKey,
 	if err != nil {
 		return nil, err
 	}
-	sig, err := f.cfg.SignMessage(f.cfg.IDKey, chanUpdateMsg)
+	sig, err := f.cfg.SignMessage(f.cfg.IDKeyLoc, chanUpdateMsg)
 	if err != nil {
 		return nil, errors.Errorf("unable to generate channel "+
 			"update announcement signature: %v", err)
@@ -2927,12 +3769,14 @@ func (f *Manager) newChanAnnouncement(localPubKey, remotePubKey,
 	if err != nil {
 		return nil, err
 	}
-	nodeSig, err := f.cfg.SignMessage(f.cfg.IDKey, chanAnnMsg)
+	nodeSig, err := f.cfg.SignMessage(f.cfg.IDKeyLoc, chanAnnMsg)
 	if err != nil {
 		return nil, errors.Errorf("unable to generate node "+
 			"signature for channel announcement: %v", err)
 	}
-	bitcoinSig, err := f.cfg.SignMessage(localFundingKey, chanAnnMsg)
+	bitcoinSig, err := f.cfg.SignMessage(
+		localFundingKey.KeyLocator, chanAnnMsg,
+	)
 	if err != nil {
 		return nil, errors.Errorf("unable to generate bitcoin "+
 			"signature for node public key: %v", err)
@@ -2968,7 +3812,8 @@ func (f *Manager) newChanAnnouncement(localPubKey, remotePubKey,
 // the network during its next trickle.
 // This method is synchronous and will return when all the network requests
 // finish, either successfully or with an error.
-func (f *Manager) announceChannel(localIDKey, remoteIDKey, localFundingKey,
+func (f *Manager) announceChannel(localIDKey, remoteIDKey *btcec.PublicKey,
+	localFundingKey *keychain.KeyDescriptor,
 	remoteFundingKey *btcec.PublicKey, shortChanID lnwire.ShortChannelID,
 	chanID lnwire.ChannelID) error {
 
@@ -3194,6 +4039,45 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		return
 	}
 
+	// Channels using the option-scid-alias channel type must be private.
+	zeroConf, scidAlias := zeroConfAndScidAlias(msg.ChannelType)
+	if scidAlias && !msg.Private {
+		msg.Err <- errors.New("option-scid-alias channel must be " +
+			"private")
+		return
+	}
+
+	// If we request the remote party to contribute funds to the channel,
+	// we'll make sure it's able to, and that we can construct the funding
+	// transaction interactively.
+	var dualFundReq *lnwire.DualFundingRequest
+	if msg.RemoteFundingAmt > 0 {
+		dualFundReq, err = f.newDualFundingRequest(msg, zeroConf)
+		if err != nil {
+			msg.Err <- err
+			return
+		}
+	}
+
+	// A lease can only be requested for the funds the remote party
+	// contributes, and its duration must be expressible as a CSV delay.
+	leaseReq := msg.LeaseRequest
+	if leaseReq != nil {
+		switch {
+		case dualFundReq == nil:
+			msg.Err <- errors.New("lease requires a remote " +
+				"funding amount")
+			return
+
+		case leaseReq.Duration == 0 ||
+			leaseReq.Duration > math.MaxUint16:
+
+			msg.Err <- fmt.Errorf("invalid lease duration %v",
+				leaseReq.Duration)
+			return
+		}
+	}
+
 	// First, we'll query the fee estimator for a fee that should get the
 	// commitment transaction confirmed by the next few blocks (conf target
 	// of 3). We target the near blocks here to ensure that we'll be able
@@ -3227,6 +4111,8 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		MinConfs:         msg.MinConfs,
 		CommitType:       commitType,
 		ChanFunder:       msg.ChanFunder,
+		ZeroConf:         zeroConf,
+		OptionScidAlias:  scidAlias,
 	}
 
 	reservation, err := f.cfg.Wallet.InitChannelReservation(req)
@@ -3254,6 +4140,13 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		remoteCsvDelay = f.cfg.RequiredRemoteDelay(capacity)
 	}
 
+	// If we lease the remote party's contribution, its own balance is
+	// delayed for at least the lease duration, so it can't withdraw the
+	// leased funds early by force closing the channel.
+	if leaseReq != nil && uint32(remoteCsvDelay) < leaseReq.Duration {
+		remoteCsvDelay = uint16(leaseReq.Duration)
+	}
+
 	// If no minimum HTLC value was specified, use the default one.
 	if minHtlcIn == 0 {
 		minHtlcIn = f.cfg.DefaultMinHtlcIn
@@ -3285,6 +4178,8 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		remoteMaxHtlcs: maxHtlcs,
 		maxLocalCsv:    maxCSV,
 		channelType:    msg.ChannelType,
+		dualFundReq:    dualFundReq,
+		leaseReq:       leaseReq,
 		reservation:    reservation,
 		peer:           msg.Peer,
 		updates:        msg.Updates,
@@ -3329,6 +4224,8 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 		ChannelFlags:          channelFlags,
 		UpfrontShutdownScript: shutdown,
 		ChannelType:           msg.ChannelType,
+		DualFundingRequest:    dualFundReq,
+		LeaseRequest:          leaseReq,
 	}
 	if err := msg.Peer.SendMessage(true, &fundingOpen); err != nil {
 		e := fmt.Errorf("unable to send funding request message: %v",
@@ -3347,6 +4244,48 @@ func (f *Manager) handleInitFundingMsg(msg *InitFundingMsg) {
 	}
 }
 
+// newDualFundingRequest creates the request for the remote party to
+// contribute funds to the channel we're about to open. Dual funded channels
+// require both parties to support them, and the funding transaction to be
+// constructed by our wallet.
+func (f *Manager) newDualFundingRequest(msg *InitFundingMsg,
+	zeroConf bool) (*lnwire.DualFundingRequest, error) {
+
+	switch {
+	case !msg.Peer.LocalFeatures().HasFeature(lnwire.DualFundOptional):
+		return nil, errors.New("dual funded channels not enabled")
+
+	case !msg.Peer.RemoteFeatures().HasFeature(lnwire.DualFundOptional):
+		return nil, errors.New("peer doesn't support dual funded " +
+			"channels")
+
+	case msg.ChanFunder != nil:
+		return nil, errors.New("dual funded channels must be funded " +
+			"by the internal wallet")
+
+	case msg.PushAmt != 0:
+		return nil, errors.New("dual funded channels can't push " +
+			"funds to the remote party")
+
+	case zeroConf:
+		return nil, errors.New("dual funded channels can't be " +
+			"zero-conf")
+	}
+
+	// We use the current height as lock time of the funding transaction
+	// to discourage fee sniping.
+	_, bestHeight, err := f.cfg.Wallet.Cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnwire.DualFundingRequest{
+		RequestedAmount: msg.RemoteFundingAmt,
+		FundingFeePerKw: uint32(msg.FundingFeePerKw),
+		LockTime:        uint32(bestHeight),
+	}, nil
+}
+
 // handleErrorMsg processes the error which was received from remote peer,
 // depending on the type of error we should do different clean up steps and
 // inform the user about it.
@@ -3413,7 +4352,8 @@ func (f *Manager) pruneZombieReservations() {
 
 	for pendingChanID, resCtx := range zombieReservations {
 		err := fmt.Errorf("reservation timed out waiting for peer "+
-			"(peer_id:%x, chan_id:%x)", resCtx.peer.IdentityKey(),
+			"(peer_id:%x, chan_id:%x)",
+			resCtx.peer.IdentityKey().SerializeCompressed(),
 			pendingChanID[:])
 		log.Warnf(err.Error())
 		f.failFundingFlow(resCtx.peer, pendingChanID, err)
@@ -3528,11 +4468,10 @@ func (f *Manager) IsPendingChannel(pendingChanID [32]byte,
 }
 
 func copyPubKey(pub *btcec.PublicKey) *btcec.PublicKey {
-	return &btcec.PublicKey{
-		Curve: btcec.S256(),
-		X:     pub.X,
-		Y:     pub.Y,
-	}
+	var tmp btcec.JacobianPoint
+	pub.AsJacobian(&tmp)
+	tmp.ToAffine()
+	return btcec.NewPublicKey(&tmp.X, &tmp.Y)
 }
 
 // saveChannelOpeningState saves the channelOpeningState for the provided
@@ -4,10 +4,10 @@ package funding
 
 import (
 	"bytes"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"math/big"
 	"net"
 	"os"
 	"path/filepath"
@@ -16,11 +16,13 @@ import (
 	"testing"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
+	"github.com/lightningnetwork/lnd/aliasmgr"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/chainreg"
 	"github.com/lightningnetwork/lnd/chanacceptor"
@@ -75,8 +77,7 @@ var (
 		0x6a, 0x49, 0x18, 0x83, 0x31, 0x98, 0x47, 0x53,
 	}
 
-	alicePrivKey, alicePubKey = btcec.PrivKeyFromBytes(btcec.S256(),
-		alicePrivKeyBytes[:])
+	alicePrivKey, alicePubKey = btcec.PrivKeyFromBytes(alicePrivKeyBytes[:])
 
 	aliceTCPAddr, _ = net.ResolveTCPAddr("tcp", "10.0.0.2:9001")
 
@@ -92,8 +93,7 @@ var (
 		0x1e, 0xb, 0x4c, 0xfd, 0x9e, 0xc5, 0x8c, 0xe9,
 	}
 
-	bobPrivKey, bobPubKey = btcec.PrivKeyFromBytes(btcec.S256(),
-		bobPrivKeyBytes[:])
+	bobPrivKey, bobPubKey = btcec.PrivKeyFromBytes(bobPrivKeyBytes[:])
 
 	bobTCPAddr, _ = net.ResolveTCPAddr("tcp", "10.0.0.2:9000")
 
@@ -102,14 +102,17 @@ var (
 		Address:     bobTCPAddr,
 	}
 
-	testSig = &btcec.Signature{
-		R: new(big.Int),
-		S: new(big.Int),
-	}
-	_, _ = testSig.R.SetString("63724406601629180062774974542967536251589935445068131219452686511677818569431", 10)
-	_, _ = testSig.S.SetString("18801056069249825825291287104931333862866033135609736119018462340006816851118", 10)
+	testRBytes, _ = hex.DecodeString("8ce2bc69281ce27da07e6683571319d18e949ddfa2965fb6caa1bf0314f882d7")
+	testSBytes, _ = hex.DecodeString("299105481d63e0f4bc2a88121167221b6700d72a0ead154c03be696a292d24ae")
+	testRScalar   = new(btcec.ModNScalar)
+	testSScalar   = new(btcec.ModNScalar)
+	_             = testRScalar.SetByteSlice(testRBytes)
+	_             = testSScalar.SetByteSlice(testSBytes)
+	testSig       = ecdsa.NewSignature(testRScalar, testSScalar)
 
 	fundingNetParams = chainreg.BitcoinTestNetParams
+
+	testKeyLoc = keychain.KeyLocator{Family: keychain.KeyFamilyNodeKey}
 )
 
 type mockNotifier struct {
@@ -194,6 +197,7 @@ type testNode struct {
 	mockChanEvent   *mockChanEvent
 	testDir         string
 	shutdownChannel chan struct{}
+	localFeatures   []lnwire.FeatureBit
 	remoteFeatures  []lnwire.FeatureBit
 
 	remotePeer  *testNode
@@ -229,7 +233,9 @@ func (n *testNode) QuitSignal() <-chan struct{} {
 }
 
 func (n *testNode) LocalFeatures() *lnwire.FeatureVector {
-	return lnwire.NewFeatureVector(nil, nil)
+	return lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(n.localFeatures...), nil,
+	)
 }
 
 func (n *testNode) RemoteFeatures() *lnwire.FeatureVector {
@@ -350,13 +356,19 @@ func createTestFundingManager(t *testing.T, privKey *btcec.PrivateKey,
 
 	chainedAcceptor := chanacceptor.NewChainedAcceptor()
 
+	aliasMgr, err := aliasmgr.NewManager(cdb)
+	if err != nil {
+		return nil, err
+	}
+
 	fundingCfg := Config{
 		IDKey:        privKey.PubKey(),
+		IDKeyLoc:     testKeyLoc,
 		Wallet:       lnw,
 		Notifier:     chainNotifier,
 		FeeEstimator: estimator,
-		SignMessage: func(pubKey *btcec.PublicKey,
-			msg []byte) (input.Signature, error) {
+		SignMessage: func(_ keychain.KeyLocator,
+			_ []byte) (*ecdsa.Signature, error) {
 
 			return testSig, nil
 		},
@@ -444,6 +456,10 @@ func createTestFundingManager(t *testing.T, privKey *btcec.PrivateKey,
 		OpenChannelPredicate:          chainedAcceptor,
 		NotifyPendingOpenChannelEvent: evt.NotifyPendingOpenChannelEvent,
 		RegisteredChains:              chainreg.NewChainRegistry(),
+		AliasManager:                  aliasMgr,
+		DeleteAliasEdge: func(lnwire.ShortChannelID) error {
+			return nil
+		},
 	}
 
 	for _, op := range options {
@@ -499,11 +515,12 @@ func recreateAliceFundingManager(t *testing.T, alice *testNode) {
 
 	f, err := NewFundingManager(Config{
 		IDKey:        oldCfg.IDKey,
+		IDKeyLoc:     oldCfg.IDKeyLoc,
 		Wallet:       oldCfg.Wallet,
 		Notifier:     oldCfg.Notifier,
 		FeeEstimator: oldCfg.FeeEstimator,
-		SignMessage: func(pubKey *btcec.PublicKey,
-			msg []byte) (input.Signature, error) {
+		SignMessage: func(_ keychain.KeyLocator,
+			_ []byte) (*ecdsa.Signature, error) {
 			return testSig, nil
 		},
 		SendAnnouncement: func(msg lnwire.Message,
@@ -546,6 +563,8 @@ func recreateAliceFundingManager(t *testing.T, alice *testNode) {
 		ZombieSweeperInterval: oldCfg.ZombieSweeperInterval,
 		ReservationTimeout:    oldCfg.ReservationTimeout,
 		OpenChannelPredicate:  chainedAcceptor,
+		AliasManager:          oldCfg.AliasManager,
+		DeleteAliasEdge:       oldCfg.DeleteAliasEdge,
 	})
 	if err != nil {
 		t.Fatalf("failed recreating aliceFundingManager: %v", err)
@@ -3674,3 +3693,281 @@ func testUpfrontFailure(t *testing.T, pkscript []byte, expectErr bool) {
 		require.True(t, ok, "did not receive AcceptChannel")
 	}
 }
+
+// mockZeroConfAcceptor is a channel acceptor that accepts all channels as
+// zero-conf channels.
+type mockZeroConfAcceptor struct{}
+
+// Accept accepts the channel as a zero-conf channel.
+func (m *mockZeroConfAcceptor) Accept(
+	*chanacceptor.ChannelAcceptRequest) *chanacceptor.ChannelAcceptResponse {
+
+	return &chanacceptor.ChannelAcceptResponse{
+		ZeroConf: true,
+	}
+}
+
+// TestFundingManagerZeroConf tests that a public zero-conf channel is usable
+// before its funding transaction confirms, and that it is announced using its
+// confirmed short channel ID afterwards.
+func TestFundingManagerZeroConf(t *testing.T) {
+	t.Parallel()
+
+	var deletedAliases []lnwire.ShortChannelID
+	alice, bob := setupFundingManagers(t, func(cfg *Config) {
+		cfg.OpenChannelPredicate = &mockZeroConfAcceptor{}
+	})
+	defer tearDownFundingManagers(t, alice, bob)
+
+	aliceDeletedAlias := make(chan lnwire.ShortChannelID, 1)
+	alice.fundingMgr.cfg.DeleteAliasEdge = func(
+		alias lnwire.ShortChannelID) error {
+
+		aliceDeletedAlias <- alias
+		return nil
+	}
+
+	// Both nodes need to support zero-conf channels and explicit channel
+	// type negotiation.
+	features := []lnwire.FeatureBit{
+		lnwire.StaticRemoteKeyOptional,
+		lnwire.ExplicitChannelTypeOptional,
+		lnwire.ScidAliasOptional,
+		lnwire.ZeroConfOptional,
+	}
+	alice.localFeatures = features
+	alice.remoteFeatures = features
+	bob.localFeatures = features
+	bob.remoteFeatures = features
+
+	channelType := lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+		lnwire.StaticRemoteKeyRequired, lnwire.ZeroConfRequired,
+	))
+
+	// Alice initiates the zero-conf channel.
+	updateChan := make(chan *lnrpc.OpenStatusUpdate)
+	errChan := make(chan error, 1)
+	localAmt := btcutil.Amount(500000)
+	initReq := &InitFundingMsg{
+		Peer:            bob,
+		TargetPubkey:    bob.privKey.PubKey(),
+		ChainHash:       *fundingNetParams.GenesisHash,
+		LocalFundingAmt: localAmt,
+		FundingFeePerKw: 1000,
+		ChannelType:     &channelType,
+		Updates:         updateChan,
+		Err:             errChan,
+	}
+	alice.fundingMgr.InitFundingWorkflow(initReq)
+
+	openChannelReq := expectOpenChannelMsg(t, alice.msgChan)
+	bob.fundingMgr.ProcessFundingMsg(openChannelReq, alice)
+
+	// Bob doesn't require any confirmations for the zero-conf channel.
+	acceptChannelResponse := assertFundingMsgSent(
+		t, bob.msgChan, "AcceptChannel",
+	).(*lnwire.AcceptChannel)
+	require.Zero(t, acceptChannelResponse.MinAcceptDepth)
+
+	alice.fundingMgr.ProcessFundingMsg(acceptChannelResponse, bob)
+	fundingCreated := assertFundingMsgSent(
+		t, alice.msgChan, "FundingCreated",
+	).(*lnwire.FundingCreated)
+
+	bob.fundingMgr.ProcessFundingMsg(fundingCreated, alice)
+	fundingSigned := assertFundingMsgSent(
+		t, bob.msgChan, "FundingSigned",
+	).(*lnwire.FundingSigned)
+
+	alice.fundingMgr.ProcessFundingMsg(fundingSigned, bob)
+
+	var pendingUpdate *lnrpc.OpenStatusUpdate
+	select {
+	case pendingUpdate = <-updateChan:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not send OpenStatusUpdate_ChanPending")
+	}
+	_, ok := pendingUpdate.Update.(*lnrpc.OpenStatusUpdate_ChanPending)
+	require.True(t, ok)
+
+	var fundingTx *wire.MsgTx
+	select {
+	case fundingTx = <-alice.publTxChan:
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not publish funding tx")
+	}
+	fundingOutPoint := &wire.OutPoint{
+		Hash:  fundingTx.TxHash(),
+		Index: 0,
+	}
+
+	// Without the funding transaction confirming, both nodes should mark
+	// the channel as open and send their fundingLocked messages, each
+	// including an alias.
+	assertMarkedOpen(t, alice, bob, fundingOutPoint)
+
+	fundingLockedAlice := assertFundingMsgSent(
+		t, alice.msgChan, "FundingLocked",
+	).(*lnwire.FundingLocked)
+	fundingLockedBob := assertFundingMsgSent(
+		t, bob.msgChan, "FundingLocked",
+	).(*lnwire.FundingLocked)
+
+	require.NotNil(t, fundingLockedAlice.AliasScid)
+	require.NotNil(t, fundingLockedBob.AliasScid)
+	require.True(t, aliasmgr.IsAlias(*fundingLockedAlice.AliasScid))
+	require.True(t, aliasmgr.IsAlias(*fundingLockedBob.AliasScid))
+
+	// The channel is added to the router graph under its alias.
+	assertFundingLockedSent(t, alice, bob, fundingOutPoint)
+	assertChannelAnnouncements(t, alice, bob, localAmt, nil, nil)
+	assertAddedToRouterGraph(t, alice, bob, fundingOutPoint)
+	waitForOpenUpdate(t, updateChan)
+
+	alice.fundingMgr.ProcessFundingMsg(fundingLockedBob, bob)
+	bob.fundingMgr.ProcessFundingMsg(fundingLockedAlice, alice)
+	assertHandleFundingLocked(t, alice, bob)
+
+	// Both nodes should have stored the alias their peer sent them.
+	chanID := lnwire.NewChanIDFromOutPoint(fundingOutPoint)
+	peerAlias, err := alice.fundingMgr.cfg.AliasManager.(*aliasmgr.Manager).
+		GetPeerAlias(chanID)
+	require.NoError(t, err)
+	require.Equal(t, *fundingLockedBob.AliasScid, peerAlias)
+
+	// Now the funding transaction confirms.
+	confirmedScid := lnwire.ShortChannelID{
+		BlockHeight: fundingBroadcastHeight + 1,
+		TxIndex:     1,
+	}
+	conf := &chainntnfs.TxConfirmation{
+		Tx:          fundingTx,
+		BlockHeight: confirmedScid.BlockHeight,
+		TxIndex:     confirmedScid.TxIndex,
+	}
+	alice.mockNotifier.oneConfChannel <- conf
+	bob.mockNotifier.oneConfChannel <- conf
+
+	// The alias edge is replaced by an edge using the confirmed short
+	// channel ID.
+	select {
+	case alias := <-aliceDeletedAlias:
+		deletedAliases = append(deletedAliases, alias)
+	case <-time.After(time.Second * 5):
+		t.Fatalf("alice did not delete alias edge")
+	}
+	require.Equal(
+		t, []lnwire.ShortChannelID{*fundingLockedAlice.AliasScid},
+		deletedAliases,
+	)
+	assertChannelAnnouncements(t, alice, bob, localAmt, nil, nil)
+
+	// After six confirmations, the channel is announced.
+	alice.mockNotifier.sixConfChannel <- conf
+	bob.mockNotifier.sixConfChannel <- conf
+	assertAnnouncementSignatures(t, alice, bob)
+	assertNoChannelState(t, alice, bob, fundingOutPoint)
+
+	// The confirmed short channel ID was persisted, and can also be used
+	// to refer to the channel.
+	channel, err := alice.fundingMgr.cfg.FindChannel(chanID)
+	require.NoError(t, err)
+	require.True(t, channel.IsZeroConf())
+	require.Equal(t, confirmedScid, channel.ConfirmedScid())
+	require.Equal(t, *fundingLockedAlice.AliasScid, channel.ShortChanID())
+	require.Contains(
+		t, alice.fundingMgr.cfg.AliasManager.GetAliases(
+			channel.ShortChanID(),
+		), confirmedScid,
+	)
+}
+
+// TestAcceptLease tests that we only lease out our contribution to a dual
+// funded channel at no less than the rates we advertise.
+func TestAcceptLease(t *testing.T) {
+	t.Parallel()
+
+	rates := &lnwire.LeaseRates{
+		FeeBase:     1000,
+		FeeBasis:    50,
+		MaxDuration: 4032,
+	}
+
+	tests := []struct {
+		name   string
+		rates  *lnwire.LeaseRates
+		req    lnwire.LeaseRequest
+		accept bool
+	}{
+		{
+			name:  "no lease rates",
+			rates: nil,
+			req: lnwire.LeaseRequest{
+				Duration: 2016,
+				FeeBase:  1000,
+				FeeBasis: 50,
+			},
+			accept: false,
+		},
+		{
+			name:  "advertised rates",
+			rates: rates,
+			req: lnwire.LeaseRequest{
+				Duration: 2016,
+				FeeBase:  1000,
+				FeeBasis: 50,
+			},
+			accept: true,
+		},
+		{
+			name:  "duration too long",
+			rates: rates,
+			req: lnwire.LeaseRequest{
+				Duration: 4033,
+				FeeBase:  1000,
+				FeeBasis: 50,
+			},
+			accept: false,
+		},
+		{
+			name:  "zero duration",
+			rates: rates,
+			req: lnwire.LeaseRequest{
+				FeeBase:  1000,
+				FeeBasis: 50,
+			},
+			accept: false,
+		},
+		{
+			name:  "base fee too low",
+			rates: rates,
+			req: lnwire.LeaseRequest{
+				Duration: 2016,
+				FeeBase:  999,
+				FeeBasis: 50,
+			},
+			accept: false,
+		},
+		{
+			name:  "proportional fee too low",
+			rates: rates,
+			req: lnwire.LeaseRequest{
+				Duration: 2016,
+				FeeBase:  1000,
+				FeeBasis: 49,
+			},
+			accept: false,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			f := &Manager{
+				cfg: &Config{LeaseRates: test.rates},
+			}
+			require.Equal(t, test.accept, f.acceptLease(&test.req))
+		})
+	}
+}
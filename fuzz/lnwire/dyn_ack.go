@@ -0,0 +1,16 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Fuzz_dyn_ack is used by go-fuzz.
+func Fuzz_dyn_ack(data []byte) int {
+	// Prefix with MsgDynAck.
+	data = prefixWithMsgType(data, lnwire.MsgDynAck)
+
+	// Pass the message into our general fuzz harness for wire messages!
+	return harness(data)
+}
@@ -0,0 +1,16 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Fuzz_dyn_propose is used by go-fuzz.
+func Fuzz_dyn_propose(data []byte) int {
+	// Prefix with MsgDynPropose.
+	data = prefixWithMsgType(data, lnwire.MsgDynPropose)
+
+	// Pass the message into our general fuzz harness for wire messages!
+	return harness(data)
+}
@@ -0,0 +1,16 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Fuzz_dyn_reject is used by go-fuzz.
+func Fuzz_dyn_reject(data []byte) int {
+	// Prefix with MsgDynReject.
+	data = prefixWithMsgType(data, lnwire.MsgDynReject)
+
+	// Pass the message into our general fuzz harness for wire messages!
+	return harness(data)
+}
@@ -0,0 +1,16 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Fuzz_onion_message is used by go-fuzz.
+func Fuzz_onion_message(data []byte) int {
+	// Prefix with MsgOnionMessage.
+	data = prefixWithMsgType(data, lnwire.MsgOnionMessage)
+
+	// Pass the message into our general fuzz harness for wire messages!
+	return harness(data)
+}
@@ -0,0 +1,16 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Fuzz_peer_storage is used by go-fuzz.
+func Fuzz_peer_storage(data []byte) int {
+	// Prefix with MsgPeerStorage.
+	data = prefixWithMsgType(data, lnwire.MsgPeerStorage)
+
+	// Pass the message into our general fuzz harness for wire messages!
+	return harness(data)
+}
@@ -0,0 +1,16 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Fuzz_peer_storage_retrieval is used by go-fuzz.
+func Fuzz_peer_storage_retrieval(data []byte) int {
+	// Prefix with MsgPeerStorageRetrieval.
+	data = prefixWithMsgType(data, lnwire.MsgPeerStorageRetrieval)
+
+	// Pass the message into our general fuzz harness for wire messages!
+	return harness(data)
+}
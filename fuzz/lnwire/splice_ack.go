@@ -0,0 +1,16 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Fuzz_splice_ack is used by go-fuzz.
+func Fuzz_splice_ack(data []byte) int {
+	// Prefix with MsgSpliceAck.
+	data = prefixWithMsgType(data, lnwire.MsgSpliceAck)
+
+	// Pass the message into our general fuzz harness for wire messages!
+	return harness(data)
+}
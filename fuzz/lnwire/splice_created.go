@@ -0,0 +1,16 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Fuzz_splice_created is used by go-fuzz.
+func Fuzz_splice_created(data []byte) int {
+	// Prefix with MsgSpliceCreated.
+	data = prefixWithMsgType(data, lnwire.MsgSpliceCreated)
+
+	// Pass the message into our general fuzz harness for wire messages!
+	return harness(data)
+}
@@ -0,0 +1,16 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Fuzz_splice_init is used by go-fuzz.
+func Fuzz_splice_init(data []byte) int {
+	// Prefix with MsgSpliceInit.
+	data = prefixWithMsgType(data, lnwire.MsgSpliceInit)
+
+	// Pass the message into our general fuzz harness for wire messages!
+	return harness(data)
+}
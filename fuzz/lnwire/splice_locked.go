@@ -0,0 +1,16 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Fuzz_splice_locked is used by go-fuzz.
+func Fuzz_splice_locked(data []byte) int {
+	// Prefix with MsgSpliceLocked.
+	data = prefixWithMsgType(data, lnwire.MsgSpliceLocked)
+
+	// Pass the message into our general fuzz harness for wire messages!
+	return harness(data)
+}
@@ -0,0 +1,16 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Fuzz_splice_signed is used by go-fuzz.
+func Fuzz_splice_signed(data []byte) int {
+	// Prefix with MsgSpliceSigned.
+	data = prefixWithMsgType(data, lnwire.MsgSpliceSigned)
+
+	// Pass the message into our general fuzz harness for wire messages!
+	return harness(data)
+}
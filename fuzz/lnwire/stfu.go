@@ -0,0 +1,16 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Fuzz_stfu is used by go-fuzz.
+func Fuzz_stfu(data []byte) int {
+	// Prefix with MsgStfu.
+	data = prefixWithMsgType(data, lnwire.MsgStfu)
+
+	// Pass the message into our general fuzz harness for wire messages!
+	return harness(data)
+}
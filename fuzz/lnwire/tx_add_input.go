@@ -0,0 +1,16 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Fuzz_tx_add_input is used by go-fuzz.
+func Fuzz_tx_add_input(data []byte) int {
+	// Prefix with MsgTxAddInput.
+	data = prefixWithMsgType(data, lnwire.MsgTxAddInput)
+
+	// Pass the message into our general fuzz harness for wire messages!
+	return harness(data)
+}
@@ -0,0 +1,16 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Fuzz_tx_complete is used by go-fuzz.
+func Fuzz_tx_complete(data []byte) int {
+	// Prefix with MsgTxComplete.
+	data = prefixWithMsgType(data, lnwire.MsgTxComplete)
+
+	// Pass the message into our general fuzz harness for wire messages!
+	return harness(data)
+}
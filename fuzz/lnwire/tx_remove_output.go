@@ -0,0 +1,16 @@
+// +build gofuzz
+
+package lnwirefuzz
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// Fuzz_tx_remove_output is used by go-fuzz.
+func Fuzz_tx_remove_output(data []byte) int {
+	// Prefix with MsgTxRemoveOutput.
+	data = prefixWithMsgType(data, lnwire.MsgTxRemoveOutput)
+
+	// Pass the message into our general fuzz harness for wire messages!
+	return harness(data)
+}
@@ -0,0 +1,343 @@
+package htlcswitch
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrDynamicCommitmentsNotSupported is returned when the commitment
+	// type of a channel with a peer that doesn't support dynamic
+	// commitments is to be upgraded.
+	ErrDynamicCommitmentsNotSupported = errors.New("peer doesn't " +
+		"support dynamic commitments")
+
+	// ErrNotQuiescenceInitiator is returned when the commitment type of a
+	// channel is to be upgraded, but the remote party won the race to
+	// initiate the quiescence of the channel.
+	ErrNotQuiescenceInitiator = errors.New("remote party initiated " +
+		"quiescence")
+
+	// ErrCommitTypeUpgradeRejected is returned when the remote party
+	// rejected the proposed commitment type upgrade.
+	ErrCommitTypeUpgradeRejected = errors.New("commitment type upgrade " +
+		"rejected by remote party")
+
+	// errUpgradeInProgress is returned when a commitment type upgrade is
+	// requested while another one is still in progress.
+	errUpgradeInProgress = errors.New("commitment type upgrade already " +
+		"in progress")
+)
+
+// upgradeReq is a local request to upgrade the commitment type of a quiescent
+// channel for which we're the initiator of the quiescence. The outcome of the
+// upgrade is sent to the err channel, which must be buffered.
+type upgradeReq struct {
+	commitType lnwallet.CommitmentType
+	err        chan error
+}
+
+// dynamicCommitmentsSupported returns true if both parties signal support for
+// upgrading the commitment type of existing channels.
+func (l *channelLink) dynamicCommitmentsSupported() bool {
+	local := l.cfg.Peer.LocalFeatures()
+	remote := l.cfg.Peer.RemoteFeatures()
+	feature := lnwire.DynamicCommitmentsOptional
+
+	return local != nil && local.HasFeature(feature) &&
+		remote != nil && remote.HasFeature(feature)
+}
+
+// commitTypeSupported returns true if both parties support the commitment
+// type.
+func (l *channelLink) commitTypeSupported(
+	commitType lnwallet.CommitmentType) bool {
+
+	var feature lnwire.FeatureBit
+	switch commitType {
+	case lnwallet.CommitmentTypeTweakless:
+		feature = lnwire.StaticRemoteKeyOptional
+
+	case lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx:
+		feature = lnwire.AnchorsZeroFeeHtlcTxOptional
+
+	default:
+		return false
+	}
+
+	local := l.cfg.Peer.LocalFeatures()
+	remote := l.cfg.Peer.RemoteFeatures()
+
+	return local.HasFeature(feature) && remote.HasFeature(feature)
+}
+
+// applyUpgrade validates the commitment type upgrade and applies it to the
+// channel. All commitments signed from then on use the new commitment type.
+func (l *channelLink) applyUpgrade(commitType lnwallet.CommitmentType) error {
+	if !l.commitTypeSupported(commitType) {
+		return fmt.Errorf("commitment type %v not supported",
+			commitType)
+	}
+
+	newType, err := lnwallet.UpgradedChanType(
+		l.channel.State().ChanType, commitType,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := l.channel.UpgradeCommitType(newType); err != nil {
+		return err
+	}
+
+	l.log.Infof("upgraded commitment type to %v", commitType)
+	l.upgrading = true
+
+	return nil
+}
+
+// proposeUpgrade handles a local request to upgrade the commitment type of
+// the channel by proposing it to the remote party.
+func (l *channelLink) proposeUpgrade(req *upgradeReq) {
+	switch {
+	case l.pendingUpgrade != nil || l.upgrading:
+		req.err <- errUpgradeInProgress
+		return
+
+	case !l.quiescer.quiescent():
+		req.err <- ErrChannelNotQuiescent
+		return
+
+	case !l.quiescer.initiator():
+		req.err <- ErrNotQuiescenceInitiator
+		return
+	}
+
+	// If the upgrade can't be proposed, the channel was quiesced for
+	// nothing, so it resumes normal operation.
+	fail := func(err error) {
+		req.err <- err
+		l.resumeQuiescence()
+	}
+
+	if !l.commitTypeSupported(req.commitType) {
+		fail(fmt.Errorf("commitment type %v not supported",
+			req.commitType))
+		return
+	}
+
+	// Make sure the upgrade is valid for our channel before proposing it,
+	// it is only applied once the remote party accepted it.
+	_, err := lnwallet.UpgradedChanType(
+		l.channel.State().ChanType, req.commitType,
+	)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	chanType := req.commitType.ChannelType()
+	propose := &lnwire.DynPropose{
+		ChanID:      l.ChanID(),
+		ChannelType: &chanType,
+	}
+	if err := l.cfg.Peer.SendMessage(false, propose); err != nil {
+		fail(err)
+		return
+	}
+
+	l.log.Infof("proposed commitment type upgrade to %v",
+		req.commitType)
+	l.pendingUpgrade = req
+}
+
+// handleDynPropose handles the remote party's proposal to upgrade the
+// commitment type of the quiescent channel. If the upgrade is valid, it is
+// applied and accepted. Otherwise it is rejected and the channel resumes
+// normal operation.
+func (l *channelLink) handleDynPropose(msg *lnwire.DynPropose) {
+	if !l.quiescer.quiescent() || l.quiescer.initiator() ||
+		l.upgrading {
+
+		l.fail(LinkFailureError{code: ErrInvalidUpdate},
+			"received unexpected dyn_propose")
+		return
+	}
+
+	err := ErrCommitTypeUpgradeRejected
+	if msg.ChannelType != nil && l.dynamicCommitmentsSupported() {
+		var commitType lnwallet.CommitmentType
+		commitType, err = lnwallet.CommitmentTypeFromChannelType(
+			*msg.ChannelType,
+		)
+		if err == nil {
+			err = l.applyUpgrade(commitType)
+		}
+	}
+	if err != nil {
+		l.log.Warnf("rejecting commitment type upgrade: %v", err)
+
+		reject := &lnwire.DynReject{ChanID: l.ChanID()}
+		if err := l.cfg.Peer.SendMessage(false, reject); err != nil {
+			l.log.Errorf("unable to send dyn_reject: %v", err)
+		}
+		l.resumeQuiescence()
+
+		return
+	}
+
+	// The upgrade is applied, so we'll accept it. The initiator then
+	// signs the first commitment of the new type.
+	ack := &lnwire.DynAck{ChanID: l.ChanID()}
+	if err := l.cfg.Peer.SendMessage(false, ack); err != nil {
+		l.log.Errorf("unable to send dyn_ack: %v", err)
+	}
+}
+
+// handleDynAck handles the remote party's acceptance of our proposal to
+// upgrade the commitment type. The upgrade is applied and the first
+// commitment of the new type is signed.
+func (l *channelLink) handleDynAck() {
+	req := l.pendingUpgrade
+	if req == nil || l.upgrading {
+		l.fail(LinkFailureError{code: ErrInvalidUpdate},
+			"received unexpected dyn_ack")
+		return
+	}
+
+	// The remote party already applied the upgrade, so if we fail to do
+	// the same, we'll fail the link. The remote party reverts the upgrade
+	// once we reconnect.
+	if err := l.applyUpgrade(req.commitType); err != nil {
+		l.pendingUpgrade = nil
+		req.err <- err
+
+		l.fail(LinkFailureError{code: ErrInternalError},
+			"unable to apply commitment type upgrade: %v", err)
+		return
+	}
+
+	l.updateCommitTxOrFail()
+}
+
+// handleDynReject handles the remote party's rejection of our proposal to
+// upgrade the commitment type. The channel resumes normal operation.
+func (l *channelLink) handleDynReject() {
+	req := l.pendingUpgrade
+	if req == nil || l.upgrading {
+		l.fail(LinkFailureError{code: ErrInvalidUpdate},
+			"received unexpected dyn_reject")
+		return
+	}
+
+	l.pendingUpgrade = nil
+	req.err <- ErrCommitTypeUpgradeRejected
+
+	l.resumeQuiescence()
+}
+
+// checkUpgradeComplete resumes normal operation of the channel once both
+// parties irrevocably committed to a commitment of the upgraded type.
+func (l *channelLink) checkUpgradeComplete() {
+	if !l.upgrading || l.channel.CommitTypeUpgradePending() {
+		return
+	}
+
+	l.log.Infof("commitment type upgrade complete")
+
+	l.upgrading = false
+	if l.pendingUpgrade != nil {
+		l.pendingUpgrade.err <- nil
+		l.pendingUpgrade = nil
+	}
+
+	l.resumeQuiescence()
+}
+
+// resumeQuiescence ends the quiescence of the channel once the operation that
+// required it has completed, and replays any remote adds that weren't
+// processed while the channel was quiescent.
+func (l *channelLink) resumeQuiescence() {
+	l.quiescer = newQuiescer(l.channel.IsInitiator())
+	l.cfg.QuiescenceTicker.Pause()
+	atomic.StoreInt32(&l.quiescent, 0)
+	atomic.StoreInt32(&l.initiator, 0)
+	atomic.StoreInt32(&l.quiescing, 0)
+
+	l.log.Infof("channel resumed from quiescence")
+
+	if err := l.resolveFwdPkgs(); err != nil {
+		l.fail(LinkFailureError{code: ErrInternalError},
+			"unable to resolve fwd pkgs: %v", err)
+	}
+}
+
+// UpgradeCommitType upgrades the commitment type of the channel in place. The
+// channel is quiesced first, and resumes normal operation once both parties
+// irrevocably committed to a commitment of the new type. If the request is
+// canceled before the upgrade was proposed, or the remote party initiated the
+// quiescence, the channel resumes normal operation right away. The cancel
+// channel is only checked once the channel is quiescent, so that a canceled
+// request never leaves behind a quiescent channel without an operation.
+//
+// NOTE: Part of the ChannelUpdateHandler interface.
+func (l *channelLink) UpgradeCommitType(commitType lnwallet.CommitmentType,
+	cancel <-chan struct{}) error {
+
+	if !l.dynamicCommitmentsSupported() {
+		return ErrDynamicCommitmentsNotSupported
+	}
+
+	initiator, err := l.Quiesce(nil)
+	if err != nil {
+		return err
+	}
+
+	// Only the initiator of the quiescence may propose the upgrade. If
+	// the remote party won the race, we'll resume normal operation. The
+	// resume request is ignored if the remote party already proposed an
+	// upgrade itself.
+	if !initiator {
+		l.ResumeQuiescence()
+		return ErrNotQuiescenceInitiator
+	}
+
+	canceled := func() error {
+		l.ResumeQuiescence()
+		return errors.New("upgrade request canceled")
+	}
+
+	// The upgrade wasn't proposed yet, so the channel can resume normal
+	// operation if the request was canceled in the meantime.
+	select {
+	case <-cancel:
+		return canceled()
+	default:
+	}
+
+	req := &upgradeReq{
+		commitType: commitType,
+		err:        make(chan error, 1),
+	}
+
+	select {
+	case l.upgradeReqs <- req:
+	case <-cancel:
+		return canceled()
+	case <-l.quit:
+		return ErrLinkShuttingDown
+	}
+
+	select {
+	case err := <-req.err:
+		return err
+	case <-cancel:
+		return errors.New("upgrade request canceled")
+	case <-l.quit:
+		return ErrLinkShuttingDown
+	}
+}
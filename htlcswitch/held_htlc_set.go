@@ -0,0 +1,101 @@
+package htlcswitch
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+// heldHtlcSet keeps track of outstanding intercepted forwards. It exposes
+// several methods to manipulate the underlying map structure in a consistent
+// way.
+type heldHtlcSet struct {
+	set map[channeldb.CircuitKey]InterceptedForward
+}
+
+func newHeldHtlcSet() *heldHtlcSet {
+	return &heldHtlcSet{
+		set: make(map[channeldb.CircuitKey]InterceptedForward),
+	}
+}
+
+// forEach iterates over all held forwards and calls the given callback for
+// each of them.
+func (h *heldHtlcSet) forEach(cb func(InterceptedForward)) {
+	for _, fwd := range h.set {
+		cb(fwd)
+	}
+}
+
+// popAutoFails calls the callback for each forward that has an auto-fail
+// height equal or less than the specified height and removes them from the
+// set.
+func (h *heldHtlcSet) popAutoFails(height int32,
+	cb func(InterceptedForward)) {
+
+	for key, fwd := range h.set {
+		if fwd.Packet().AutoFailHeight > height {
+			continue
+		}
+
+		cb(fwd)
+
+		delete(h.set, key)
+	}
+}
+
+// pop returns the specified forward and removes it from the set.
+func (h *heldHtlcSet) pop(key channeldb.CircuitKey) (InterceptedForward,
+	error) {
+
+	intercepted, ok := h.set[key]
+	if !ok {
+		return nil, fmt.Errorf("fwd %v: %w", key, ErrFwdNotExists)
+	}
+
+	delete(h.set, key)
+
+	return intercepted, nil
+}
+
+// exists tests whether the specified forward is part of the set.
+func (h *heldHtlcSet) exists(key channeldb.CircuitKey) bool {
+	_, ok := h.set[key]
+
+	return ok
+}
+
+// add adds the specified forward to the set and returns an error if the
+// forward already exists.
+func (h *heldHtlcSet) add(key channeldb.CircuitKey,
+	fwd InterceptedForward) error {
+
+	if _, ok := h.set[key]; ok {
+		return errors.New("htlc already exists in set")
+	}
+
+	h.set[key] = fwd
+
+	return nil
+}
+
+// replace swaps the held forward with the same key for the given one. This is
+// used when a link replays a held forward after it was restarted, as the new
+// forward references the current instance of the link.
+func (h *heldHtlcSet) replace(key channeldb.CircuitKey,
+	fwd InterceptedForward) error {
+
+	if _, ok := h.set[key]; !ok {
+		return fmt.Errorf("fwd %v: %w", key, ErrFwdNotExists)
+	}
+
+	h.set[key] = fwd
+
+	return nil
+}
+
+// len returns the number of held forwards.
+func (h *heldHtlcSet) len() int {
+	return len(h.set)
+}
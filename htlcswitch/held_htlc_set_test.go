@@ -0,0 +1,98 @@
+package htlcswitch
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// mockHeldForward is an InterceptedForward that records how it was resolved.
+type mockHeldForward struct {
+	packet InterceptedPacket
+	failed bool
+}
+
+func (m *mockHeldForward) Packet() InterceptedPacket {
+	return m.packet
+}
+
+func (m *mockHeldForward) Resume() error {
+	return nil
+}
+
+func (m *mockHeldForward) Settle(lntypes.Preimage) error {
+	return nil
+}
+
+func (m *mockHeldForward) Fail() error {
+	m.failed = true
+
+	return nil
+}
+
+// TestHeldHtlcSet tests the bookkeeping of held forwards.
+func TestHeldHtlcSet(t *testing.T) {
+	t.Parallel()
+
+	set := newHeldHtlcSet()
+
+	key := func(id uint64) channeldb.CircuitKey {
+		return channeldb.CircuitKey{
+			ChanID: lnwire.NewShortChanIDFromInt(1),
+			HtlcID: id,
+		}
+	}
+	newFwd := func(id uint64, autoFailHeight int32) *mockHeldForward {
+		return &mockHeldForward{
+			packet: InterceptedPacket{
+				IncomingCircuit: key(id),
+				AutoFailHeight:  autoFailHeight,
+			},
+		}
+	}
+
+	fwd1 := newFwd(1, 100)
+	fwd2 := newFwd(2, 110)
+	require.NoError(t, set.add(key(1), fwd1))
+	require.NoError(t, set.add(key(2), fwd2))
+	require.Error(t, set.add(key(1), fwd1))
+	require.True(t, set.exists(key(1)))
+	require.False(t, set.exists(key(3)))
+	require.Equal(t, 2, set.len())
+
+	// Replacing a forward keeps it held, but only if it is held already.
+	fwd1Replayed := newFwd(1, 100)
+	require.NoError(t, set.replace(key(1), fwd1Replayed))
+	require.ErrorIs(t, set.replace(key(3), fwd1), ErrFwdNotExists)
+
+	var offered int
+	set.forEach(func(InterceptedForward) {
+		offered++
+	})
+	require.Equal(t, 2, offered)
+
+	// Only forwards that reached their auto-fail height are popped.
+	set.popAutoFails(99, func(fwd InterceptedForward) {
+		require.NoError(t, fwd.Fail())
+	})
+	require.Equal(t, 2, set.len())
+
+	set.popAutoFails(100, func(fwd InterceptedForward) {
+		require.NoError(t, fwd.Fail())
+	})
+	require.True(t, fwd1Replayed.failed)
+	require.False(t, fwd1.failed)
+	require.False(t, set.exists(key(1)))
+
+	// Popping returns the forward and removes it from the set.
+	fwd, err := set.pop(key(2))
+	require.NoError(t, err)
+	require.Equal(t, fwd2, fwd)
+	require.Zero(t, set.len())
+
+	_, err = set.pop(key(2))
+	require.ErrorIs(t, err, ErrFwdNotExists)
+}
@@ -0,0 +1,216 @@
+package hop
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// ErrBlindedPathID is returned when the final hop of a blinded route
+	// receives a path ID that we could not have created.
+	ErrBlindedPathID = errors.New("invalid blinded path id")
+
+	// ErrBlindedRelayInfo is returned when an intermediate hop of a
+	// blinded route doesn't receive the forwarding parameters.
+	ErrBlindedRelayInfo = errors.New("blinded hop is missing relay info")
+)
+
+// decodeBlindedData decrypts the encrypted data of a hop within a blinded
+// route and populates the forwarding instructions of the payload from it.
+func (h *Payload) decodeBlindedData(kit *blindingKit) error {
+	if h.encryptedData == nil {
+		return ErrInvalidPayload{
+			Type:      record.EncryptedDataOnionType,
+			Violation: OmittedViolation,
+		}
+	}
+
+	// The introduction node of the route receives the blinding point in
+	// its payload, all other nodes within the update_add_htlc message. It
+	// must never be sent in both.
+	blindingPoint := kit.updateAddBlinding
+	switch {
+	case blindingPoint != nil && h.blindingPoint != nil:
+		return ErrInvalidPayload{
+			Type:      record.BlindingPointOnionType,
+			Violation: IncludedViolation,
+		}
+
+	case blindingPoint == nil && h.blindingPoint == nil:
+		return ErrInvalidPayload{
+			Type:      record.BlindingPointOnionType,
+			Violation: OmittedViolation,
+		}
+
+	case blindingPoint == nil:
+		blindingPoint = h.blindingPoint
+	}
+
+	plainText, err := blindedpath.DecryptBlindedData(
+		kit.nodeKey, blindingPoint, h.encryptedData,
+	)
+	if err != nil {
+		return err
+	}
+
+	routeData, err := record.DecodeBlindedRouteData(
+		bytes.NewReader(plainText),
+	)
+	if err != nil {
+		return err
+	}
+
+	// The recipient may restrict the HTLCs that are forwarded over its
+	// route.
+	if c := routeData.Constraints; c != nil {
+		if kit.incomingCltv > c.MaxCltvExpiry {
+			return fmt.Errorf("htlc expiry %v exceeds blinded "+
+				"route maximum %v", kit.incomingCltv,
+				c.MaxCltvExpiry)
+		}
+
+		if kit.incomingAmount != 0 &&
+			kit.incomingAmount < c.HtlcMinimumMsat {
+
+			return fmt.Errorf("htlc amount %v below blinded "+
+				"route minimum %v", kit.incomingAmount,
+				c.HtlcMinimumMsat)
+		}
+	}
+
+	// Without an outgoing channel, we are the recipient of the payment.
+	if routeData.ShortChannelID == nil {
+		return h.applyBlindedFinalHop(kit, routeData)
+	}
+
+	return h.applyBlindedForward(kit, blindingPoint, routeData)
+}
+
+// applyBlindedFinalHop validates the payload of the final hop of a blinded
+// route. The path ID takes the role of the payment address of the invoice,
+// so it is surfaced as MPP record to the invoice registry.
+//
+// The sender only knows the aggregated parameters of the blinded route, so
+// the amount and expiry of its payload are lower bounds for the HTLC rather
+// than exact values.
+func (h *Payload) applyBlindedFinalHop(kit *blindingKit,
+	routeData *record.BlindedRouteData) error {
+
+	for _, t := range []tlv.Type{
+		record.AmtOnionType, record.LockTimeOnionType,
+		record.TotalAmtMsatOnionType,
+	} {
+		if _, ok := h.parsedTypes[t]; !ok {
+			return ErrInvalidPayload{
+				Type:      t,
+				Violation: OmittedViolation,
+				FinalHop:  true,
+			}
+		}
+	}
+
+	if len(routeData.PathID) != 32 {
+		return ErrBlindedPathID
+	}
+
+	if kit.incomingAmount < h.FwdInfo.AmountToForward {
+		return fmt.Errorf("htlc amount %v below blinded payload "+
+			"amount %v", kit.incomingAmount,
+			h.FwdInfo.AmountToForward)
+	}
+	if kit.incomingCltv < h.FwdInfo.OutgoingCTLV {
+		return fmt.Errorf("htlc expiry %v below blinded payload "+
+			"expiry %v", kit.incomingCltv, h.FwdInfo.OutgoingCTLV)
+	}
+
+	var paymentAddr [32]byte
+	copy(paymentAddr[:], routeData.PathID)
+	h.MPP = record.NewMPP(h.totalAmtMsat, paymentAddr)
+	h.FwdInfo.NextHop = Exit
+	h.FwdInfo.AmountToForward = kit.incomingAmount
+	h.FwdInfo.OutgoingCTLV = kit.incomingCltv
+
+	return nil
+}
+
+// applyBlindedForward derives the forwarding instructions of an intermediate
+// hop of a blinded route from the parameters the recipient chose for us.
+func (h *Payload) applyBlindedForward(kit *blindingKit,
+	blindingPoint *btcec.PublicKey,
+	routeData *record.BlindedRouteData) error {
+
+	for _, t := range []tlv.Type{
+		record.AmtOnionType, record.LockTimeOnionType,
+		record.TotalAmtMsatOnionType,
+	} {
+		if _, ok := h.parsedTypes[t]; ok {
+			return ErrInvalidPayload{
+				Type:      t,
+				Violation: IncludedViolation,
+			}
+		}
+	}
+
+	relayInfo := routeData.RelayInfo
+	if relayInfo == nil {
+		return ErrBlindedRelayInfo
+	}
+
+	amtToForward, err := blindedForwardAmount(
+		kit.incomingAmount, relayInfo,
+	)
+	if err != nil {
+		return err
+	}
+
+	if kit.incomingCltv < uint32(relayInfo.CltvExpiryDelta) {
+		return fmt.Errorf("htlc expiry %v below blinded cltv delta "+
+			"%v", kit.incomingCltv, relayInfo.CltvExpiryDelta)
+	}
+
+	nextBlinding, err := blindedpath.NextBlindingPoint(
+		kit.nodeKey, blindingPoint,
+	)
+	if err != nil {
+		return err
+	}
+
+	h.FwdInfo = ForwardingInfo{
+		Network:         BitcoinNetwork,
+		NextHop:         *routeData.ShortChannelID,
+		AmountToForward: amtToForward,
+		OutgoingCTLV: kit.incomingCltv -
+			uint32(relayInfo.CltvExpiryDelta),
+		NextBlinding: nextBlinding,
+	}
+
+	return nil
+}
+
+// blindedForwardAmount calculates the amount to forward over a hop of a
+// blinded route, given the incoming amount and the fees the recipient chose
+// for the hop. The result is rounded up, which ensures that the amount the
+// sender derived from the fees of the route is forwarded unchanged.
+func blindedForwardAmount(incomingAmt lnwire.MilliSatoshi,
+	relayInfo *record.PaymentRelayInfo) (lnwire.MilliSatoshi, error) {
+
+	baseFee := lnwire.MilliSatoshi(relayInfo.BaseFee)
+	if incomingAmt < baseFee {
+		return 0, fmt.Errorf("htlc amount %v below blinded base fee %v",
+			incomingAmt, baseFee)
+	}
+
+	numerator := uint64(incomingAmt-baseFee) * 1_000_000
+	denominator := 1_000_000 + uint64(relayInfo.FeeRate)
+
+	return lnwire.MilliSatoshi(
+		(numerator + denominator - 1) / denominator,
+	), nil
+}
@@ -1,6 +1,7 @@
 package hop
 
 import (
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
@@ -26,4 +27,9 @@ type ForwardingInfo struct {
 	// OutgoingCTLV is the specified value of the CTLV timelock to be used
 	// in the outgoing HTLC.
 	OutgoingCTLV uint32
+
+	// NextBlinding is the blinding point that needs to be handed to the
+	// next hop in the update_add_htlc message, if the HTLC is forwarded
+	// within a blinded route.
+	NextBlinding *btcec.PublicKey
 }
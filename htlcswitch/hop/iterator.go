@@ -2,13 +2,18 @@ package hop
 
 import (
 	"bytes"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"sync"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
 	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
 )
 
 // Iterator is an interface that abstracts away the routing information
@@ -47,16 +52,40 @@ type sphinxHopIterator struct {
 	// includes the information required to properly forward the packet to
 	// the next hop.
 	processedPacket *sphinx.ProcessedPacket
+
+	// blindingKit holds the information required to decode the payload
+	// of a hop within a blinded route.
+	blindingKit blindingKit
+}
+
+// blindingKit contains the information that is needed to process the payload
+// of a hop that is part of a blinded route.
+type blindingKit struct {
+	// nodeKey is our node key, which is used to decrypt the data the
+	// recipient of the blinded route encrypted for us.
+	nodeKey keychain.SingleKeyECDH
+
+	// updateAddBlinding is the blinding point that was handed to us in
+	// the update_add_htlc message. It is only set if we aren't the
+	// introduction node of the blinded route.
+	updateAddBlinding *btcec.PublicKey
+
+	// incomingAmount is the amount of the incoming HTLC.
+	incomingAmount lnwire.MilliSatoshi
+
+	// incomingCltv is the expiry of the incoming HTLC.
+	incomingCltv uint32
 }
 
 // makeSphinxHopIterator converts a processed packet returned from a sphinx
 // router and converts it into an hop iterator for usage in the link.
 func makeSphinxHopIterator(ogPacket *sphinx.OnionPacket,
-	packet *sphinx.ProcessedPacket) *sphinxHopIterator {
+	packet *sphinx.ProcessedPacket, kit blindingKit) *sphinxHopIterator {
 
 	return &sphinxHopIterator{
 		ogPacket:        ogPacket,
 		processedPacket: packet,
+		blindingKit:     kit,
 	}
 }
 
@@ -82,17 +111,42 @@ func (r *sphinxHopIterator) HopPayload() (*Payload, error) {
 	switch r.processedPacket.Payload.Type {
 
 	// If this is the legacy payload, then we'll extract the information
-	// directly from the pre-populated ForwardingInstructions field.
+	// directly from the pre-populated ForwardingInstructions field. Hops
+	// within a blinded route always use TLV payloads.
 	case sphinx.PayloadLegacy:
+		if r.blindingKit.updateAddBlinding != nil {
+			return nil, ErrInvalidPayload{
+				Type:      record.EncryptedDataOnionType,
+				Violation: OmittedViolation,
+			}
+		}
+
 		fwdInst := r.processedPacket.ForwardingInstructions
 		return NewLegacyPayload(fwdInst), nil
 
 	// Otherwise, if this is the TLV payload, then we'll make a new stream
 	// to decode only what we need to make routing decisions.
 	case sphinx.PayloadTLV:
-		return NewPayloadFromReader(bytes.NewReader(
+		payload, err := NewPayloadFromReader(bytes.NewReader(
 			r.processedPacket.Payload.Payload,
 		))
+		if err != nil {
+			return nil, err
+		}
+
+		// If the payload carries encrypted data, we are part of a
+		// blinded route and need to decrypt our forwarding
+		// instructions.
+		if payload.encryptedData != nil ||
+			r.blindingKit.updateAddBlinding != nil {
+
+			err := payload.decodeBlindedData(&r.blindingKit)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return payload, nil
 
 	default:
 		return nil, fmt.Errorf("unknown sphinx payload type: %v",
@@ -123,11 +177,52 @@ func (r *sphinxHopIterator) ExtractErrorEncrypter(
 // tests dependent from the sphinx internal parts.
 type OnionProcessor struct {
 	router *sphinx.Router
+
+	// nodeKey is the key the onions destined to us are encrypted to.
+	nodeKey keychain.SingleKeyECDH
+
+	// netParams are the parameters of the network our router operates
+	// on.
+	netParams *chaincfg.Params
+
+	// replayLog is the replay log of our router, which is shared with the
+	// routers we create to process the onions of blinded routes.
+	replayLog sphinx.ReplayLog
 }
 
-// NewOnionProcessor creates new instance of decoder.
-func NewOnionProcessor(router *sphinx.Router) *OnionProcessor {
-	return &OnionProcessor{router}
+// NewOnionProcessor creates new instance of decoder. The node key, network
+// parameters and replay log need to match the ones of the passed router, they
+// are used to process onions that were constructed for our blinded node key.
+func NewOnionProcessor(router *sphinx.Router, nodeKey keychain.SingleKeyECDH,
+	netParams *chaincfg.Params,
+	replayLog sphinx.ReplayLog) *OnionProcessor {
+
+	return &OnionProcessor{
+		router:    router,
+		nodeKey:   nodeKey,
+		netParams: netParams,
+		replayLog: replayLog,
+	}
+}
+
+// blindedRouter returns a sphinx router for our blinded node key within the
+// blinded route that handed us the passed blinding point. If the blinding
+// point is nil, our regular router is returned.
+func (p *OnionProcessor) blindedRouter(
+	blindingPoint *btcec.PublicKey) (*sphinx.Router, error) {
+
+	if blindingPoint == nil {
+		return p.router, nil
+	}
+
+	blindedKey, err := blindedpath.NewBlindedNodeKey(
+		p.nodeKey, blindingPoint,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return sphinx.NewRouter(blindedKey, p.netParams, p.replayLog), nil
 }
 
 // Start spins up the onion processor's sphinx router.
@@ -182,7 +277,13 @@ func (p *OnionProcessor) DecodeHopIterator(r io.Reader, rHash []byte,
 		}
 	}
 
-	return makeSphinxHopIterator(onionPkt, sphinxPacket), lnwire.CodeNone
+	kit := blindingKit{
+		nodeKey:      p.nodeKey,
+		incomingCltv: incomingCltv,
+	}
+
+	return makeSphinxHopIterator(onionPkt, sphinxPacket, kit),
+		lnwire.CodeNone
 }
 
 // ReconstructHopIterator attempts to decode a valid sphinx packet from the passed io.Reader
@@ -206,16 +307,26 @@ func (p *OnionProcessor) ReconstructHopIterator(r io.Reader, rHash []byte) (
 		return nil, err
 	}
 
-	return makeSphinxHopIterator(onionPkt, sphinxPacket), nil
+	kit := blindingKit{
+		nodeKey: p.nodeKey,
+	}
+
+	return makeSphinxHopIterator(onionPkt, sphinxPacket, kit), nil
 }
 
 // DecodeHopIteratorRequest encapsulates all date necessary to process an onion
 // packet, perform sphinx replay detection, and schedule the entry for garbage
 // collection.
 type DecodeHopIteratorRequest struct {
-	OnionReader  io.Reader
-	RHash        []byte
-	IncomingCltv uint32
+	OnionReader    io.Reader
+	RHash          []byte
+	IncomingCltv   uint32
+	IncomingAmount lnwire.MilliSatoshi
+
+	// BlindingPoint is the blinding point that was handed to us in the
+	// update_add_htlc message, if the HTLC is forwarded within a blinded
+	// route.
+	BlindingPoint *btcec.PublicKey
 }
 
 // DecodeHopIteratorResponse encapsulates the outcome of a batched sphinx onion
@@ -252,9 +363,37 @@ func (p *OnionProcessor) DecodeHopIterators(id []byte,
 
 	tx := p.router.BeginTxn(id, batchSize)
 
+	// The onions of HTLCs that are forwarded within a blinded route are
+	// encrypted to our blinded node key. Each of them is therefore
+	// processed within a transaction of its own, using a router for the
+	// blinded key.
+	blindedTxns := make(map[uint16]*sphinx.Tx)
+	for i, req := range reqs {
+		if req.BlindingPoint == nil {
+			continue
+		}
+
+		router, err := p.blindedRouter(req.BlindingPoint)
+		if err != nil {
+			log.Errorf("unable to create blinded router: %v", err)
+			resps[i].FailCode = lnwire.CodeInvalidOnionKey
+			continue
+		}
+
+		blindedTxns[uint16(i)] = router.BeginTxn(
+			blindedBatchID(id, uint16(i)), 1,
+		)
+	}
+
 	decode := func(seqNum uint16, onionPkt *sphinx.OnionPacket,
 		req DecodeHopIteratorRequest) lnwire.FailCode {
 
+		tx := tx
+		if blindedTx, ok := blindedTxns[seqNum]; ok {
+			tx = blindedTx
+			seqNum = 0
+		}
+
 		err := onionPkt.Decode(req.OnionReader)
 		switch err {
 		case nil:
@@ -301,6 +440,12 @@ func (p *OnionProcessor) DecodeHopIterators(id []byte,
 		go func(seqNum uint16) {
 			defer wg.Done()
 
+			// Skip any indexes that already failed to set up
+			// their blinded router.
+			if resps[seqNum].FailCode != lnwire.CodeNone {
+				return
+			}
+
 			onionPkt := &onionPkts[seqNum]
 
 			resps[seqNum].FailCode = decode(
@@ -341,6 +486,33 @@ func (p *OnionProcessor) DecodeHopIterators(id []byte,
 		return resps, err
 	}
 
+	// Commit the transactions of the blinded onions as well. As their
+	// shared secrets are stored in the same replay log, a failure here is
+	// treated the same way as above.
+	type blindedResult struct {
+		packet  *sphinx.ProcessedPacket
+		replays *sphinx.ReplaySet
+	}
+	blindedResults := make(map[uint16]blindedResult, len(blindedTxns))
+	for seqNum, blindedTx := range blindedTxns {
+		blindedPackets, blindedReplays, err := blindedTx.Commit()
+		if err != nil {
+			log.Errorf("unable to process blinded onion packet "+
+				"%x-%v: %v", id, seqNum, err)
+
+			if resps[seqNum].FailCode == lnwire.CodeNone {
+				resps[seqNum].FailCode =
+					lnwire.CodeTemporaryChannelFailure
+			}
+			continue
+		}
+
+		blindedResults[seqNum] = blindedResult{
+			packet:  &blindedPackets[0],
+			replays: blindedReplays,
+		}
+	}
+
 	// Otherwise, the commit was successful. Now we will post process any
 	// remaining packets, additionally failing any that were included in the
 	// replay set.
@@ -352,11 +524,19 @@ func (p *OnionProcessor) DecodeHopIterators(id []byte,
 			continue
 		}
 
+		// Blinded onions were processed within their own
+		// transaction, so we'll need to consult its results instead.
+		packet, replayed := &packets[i], replays.Contains(uint16(i))
+		if result, ok := blindedResults[uint16(i)]; ok {
+			packet = result.packet
+			replayed = result.replays.Contains(0)
+		}
+
 		// If this index is contained in the replay set, mark it with a
 		// temporary channel failure error code. We infer that the
 		// offending error was due to a replayed packet because this
 		// index was found in the replay set.
-		if replays.Contains(uint16(i)) {
+		if replayed {
 			log.Errorf("unable to process onion packet: %v",
 				sphinx.ErrReplayedPacket)
 			resp.FailCode = lnwire.CodeTemporaryChannelFailure
@@ -365,12 +545,30 @@ func (p *OnionProcessor) DecodeHopIterators(id []byte,
 
 		// Finally, construct a hop iterator from our processed sphinx
 		// packet, simultaneously caching the original onion packet.
-		resp.HopIterator = makeSphinxHopIterator(&onionPkts[i], &packets[i])
+		kit := blindingKit{
+			nodeKey:           p.nodeKey,
+			updateAddBlinding: reqs[i].BlindingPoint,
+			incomingAmount:    reqs[i].IncomingAmount,
+			incomingCltv:      reqs[i].IncomingCltv,
+		}
+		resp.HopIterator = makeSphinxHopIterator(
+			&onionPkts[i], packet, kit,
+		)
 	}
 
 	return resps, nil
 }
 
+// blindedBatchID returns the ID of the replay log batch of the blinded onion
+// at the given index within the batch with the passed ID.
+func blindedBatchID(id []byte, seqNum uint16) []byte {
+	batchID := make([]byte, len(id)+2)
+	copy(batchID, id)
+	binary.BigEndian.PutUint16(batchID[len(id):], seqNum)
+
+	return batchID
+}
+
 // ExtractErrorEncrypter takes an io.Reader which should contain the onion
 // packet as original received by a forwarding node and creates an
 // ErrorEncrypter instance using the derived shared secret. In the case that en
@@ -5,11 +5,15 @@ import (
 	"encoding/binary"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/davecgh/go-spew/spew"
 	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
 	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/stretchr/testify/require"
 )
 
 // TestSphinxHopIteratorForwardingInstructions tests that we're able to
@@ -99,3 +103,124 @@ func TestSphinxHopIteratorForwardingInstructions(t *testing.T) {
 		}
 	}
 }
+
+// TestSphinxHopIteratorBlindedRoute tests that the nodes of a blinded route
+// are able to decrypt their forwarding instructions from the payload.
+func TestSphinxHopIteratorBlindedRoute(t *testing.T) {
+	t.Parallel()
+
+	introKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	recipientKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	// The introduction node forwards over the channel to the recipient,
+	// which identifies the payment by its path ID.
+	scid := lnwire.NewShortChanIDFromInt(1234)
+	introData, err := record.EncodeBlindedRouteData(
+		&record.BlindedRouteData{
+			ShortChannelID: &scid,
+			RelayInfo: &record.PaymentRelayInfo{
+				CltvExpiryDelta: 40,
+				FeeRate:         1000,
+				BaseFee:         1000,
+			},
+			Constraints: &record.PaymentConstraints{
+				MaxCltvExpiry:   1000,
+				HtlcMinimumMsat: 1000,
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	var pathID [32]byte
+	copy(pathID[:], bytes.Repeat([]byte{1}, 32))
+	recipientData, err := record.EncodeBlindedRouteData(
+		&record.BlindedRouteData{
+			PathID: pathID[:],
+		},
+	)
+	require.NoError(t, err)
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	path, err := blindedpath.BuildBlindedPath(
+		sessionKey, []*blindedpath.HopInfo{{
+			NodePub:   introKey.PubKey(),
+			PlainText: introData,
+		}, {
+			NodePub:   recipientKey.PubKey(),
+			PlainText: recipientData,
+		}},
+	)
+	require.NoError(t, err)
+
+	encodePayload := func(records ...tlv.Record) *sphinx.ProcessedPacket {
+		var b bytes.Buffer
+		require.NoError(t, tlv.MustNewStream(records...).Encode(&b))
+
+		return &sphinx.ProcessedPacket{
+			Payload: sphinx.HopPayload{
+				Type:    sphinx.PayloadTLV,
+				Payload: b.Bytes(),
+			},
+		}
+	}
+
+	// The introduction node receives the blinding point in its payload.
+	blindingPoint := path.BlindingPoint
+	iterator := makeSphinxHopIterator(
+		nil, encodePayload(
+			record.NewEncryptedDataRecord(
+				&path.BlindedHops[0].CipherText,
+			),
+			record.NewBlindingPointRecord(&blindingPoint),
+		), blindingKit{
+			nodeKey: &keychain.PrivKeyECDH{
+				PrivKey: introKey,
+			},
+			incomingAmount: 101_100,
+			incomingCltv:   540,
+		},
+	)
+	pld, err := iterator.HopPayload()
+	require.NoError(t, err)
+
+	fwdInfo := pld.ForwardingInfo()
+	require.Equal(t, scid, fwdInfo.NextHop)
+	require.EqualValues(t, 100_000, fwdInfo.AmountToForward)
+	require.EqualValues(t, 500, fwdInfo.OutgoingCTLV)
+	require.NotNil(t, fwdInfo.NextBlinding)
+
+	// An HTLC that exceeds the constraints of the route is rejected.
+	iterator.blindingKit.incomingCltv = 1001
+	_, err = iterator.HopPayload()
+	require.Error(t, err)
+
+	// The recipient receives the blinding point within the HTLC and the
+	// amounts within its payload.
+	amt, cltv := uint64(100_000), uint32(500)
+	iterator = makeSphinxHopIterator(
+		nil, encodePayload(
+			record.NewAmtToFwdRecord(&amt),
+			record.NewLockTimeRecord(&cltv),
+			record.NewEncryptedDataRecord(
+				&path.BlindedHops[1].CipherText,
+			),
+			record.NewTotalAmtMsatRecord(&amt),
+		), blindingKit{
+			nodeKey: &keychain.PrivKeyECDH{
+				PrivKey: recipientKey,
+			},
+			updateAddBlinding: fwdInfo.NextBlinding,
+			incomingAmount:    100_000,
+			incomingCltv:      500,
+		},
+	)
+	pld, err = iterator.HopPayload()
+	require.NoError(t, err)
+	require.Equal(t, Exit, pld.ForwardingInfo().NextHop)
+	require.NotNil(t, pld.MultiPath())
+	require.Equal(t, pathID, pld.MultiPath().PaymentAddr())
+	require.EqualValues(t, amt, pld.MultiPath().TotalMsat())
+}
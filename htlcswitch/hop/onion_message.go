@@ -0,0 +1,278 @@
+package hop
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+var (
+	// ErrOnionMessageNoNextNode is returned when an onion message that
+	// needs to be forwarded doesn't tell us the node to forward it to.
+	ErrOnionMessageNoNextNode = errors.New("onion message is missing " +
+		"next node")
+
+	// ErrOnionMessageForwardRecords is returned when an onion message we
+	// need to forward carries records for the final recipient in our
+	// payload.
+	ErrOnionMessageForwardRecords = errors.New("onion message to " +
+		"forward carries recipient records")
+)
+
+// OnionMessagePayload is the outcome of processing an onion message. It
+// either instructs us to forward the message to the next node of its path,
+// or carries the content of the message if we are its recipient.
+type OnionMessagePayload struct {
+	// NextNodeID is the node the message needs to be forwarded to. It is
+	// nil if we are the recipient of the message.
+	NextNodeID *btcec.PublicKey
+
+	// NextMessage is the message that needs to be sent to the next node.
+	NextMessage *lnwire.OnionMessage
+
+	// PathID is the path ID the creator of the blinded path left for us
+	// as its recipient, if any.
+	PathID []byte
+
+	// CustomRecords are the records the sender included for us as the
+	// recipient of the message.
+	CustomRecords record.CustomSet
+}
+
+// BuildOnionMessage creates an onion message that travels along the given
+// path of nodes and delivers the passed records to the last of them. The
+// message is sent to the first node of the path, which must be a peer of
+// ours.
+func BuildOnionMessage(path []*btcec.PublicKey,
+	records record.CustomSet) (*lnwire.OnionMessage, error) {
+
+	if len(path) == 0 {
+		return nil, fmt.Errorf("onion message path must not be empty")
+	}
+
+	// Each node of the path learns the node it needs to forward the
+	// message to from the data we encrypt for it.
+	hops := make([]*blindedpath.HopInfo, len(path))
+	for i, nodePub := range path {
+		var routeData record.BlindedRouteData
+		if i < len(path)-1 {
+			routeData.NextNodeID = path[i+1]
+		}
+
+		plainText, err := record.EncodeBlindedRouteData(&routeData)
+		if err != nil {
+			return nil, err
+		}
+
+		hops[i] = &blindedpath.HopInfo{
+			NodePub:   nodePub,
+			PlainText: plainText,
+		}
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	blindedPath, err := blindedpath.BuildBlindedPath(sessionKey, hops)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildBlindedOnionMessage(blindedPath, records)
+}
+
+// BuildBlindedOnionMessage creates an onion message that travels along the
+// given blinded path and delivers the passed records to its recipient. The
+// message is sent to the introduction point of the path, which must be a
+// peer of ours.
+func BuildBlindedOnionMessage(path *blindedpath.BlindedPath,
+	records record.CustomSet) (*lnwire.OnionMessage, error) {
+
+	if len(path.BlindedHops) > sphinx.NumMaxHops {
+		return nil, fmt.Errorf("onion message path of %d hops exceeds "+
+			"maximum of %d", len(path.BlindedHops),
+			sphinx.NumMaxHops)
+	}
+
+	if err := record.ValidateOnionMessageRecords(records); err != nil {
+		return nil, err
+	}
+
+	// The onion is constructed for the blinded keys of the nodes, each of
+	// which receives the data the creator of the path encrypted for it.
+	// The records are only delivered to the recipient.
+	var sphinxPath sphinx.PaymentPath
+	for i, blindedHop := range path.BlindedHops {
+		cipherText := blindedHop.CipherText
+		tlvRecords := []tlv.Record{
+			record.NewOnionMessageEncryptedDataRecord(&cipherText),
+		}
+		if i == len(path.BlindedHops)-1 {
+			tlvRecords = append(
+				tlvRecords, tlv.MapToRecords(records)...,
+			)
+		}
+
+		tlv.SortRecords(tlvRecords)
+		tlvStream, err := tlv.NewStream(tlvRecords...)
+		if err != nil {
+			return nil, err
+		}
+
+		var b bytes.Buffer
+		if err := tlvStream.Encode(&b); err != nil {
+			return nil, err
+		}
+
+		payload, err := sphinx.NewHopPayload(nil, b.Bytes())
+		if err != nil {
+			return nil, err
+		}
+
+		sphinxPath[i] = sphinx.OnionHop{
+			NodePub:    *blindedHop.BlindedNodePub,
+			HopPayload: payload,
+		}
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	onionPkt, err := sphinx.NewOnionPacket(
+		&sphinxPath, sessionKey, nil, sphinx.DeterministicPacketFiller,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var onionBlob bytes.Buffer
+	if err := onionPkt.Encode(&onionBlob); err != nil {
+		return nil, err
+	}
+
+	return lnwire.NewOnionMessage(path.BlindingPoint, onionBlob.Bytes()),
+		nil
+}
+
+// ProcessOnionMessage peels our layer off the passed onion message. The
+// returned payload either holds the message that needs to be forwarded to
+// the next node, or the content of the message if we are its recipient.
+//
+// NOTE: Onion messages are not checked against our replay log, as they
+// don't move any funds. Peers are expected to limit the rate at which they
+// accept onion messages instead.
+func (p *OnionProcessor) ProcessOnionMessage(
+	msg *lnwire.OnionMessage) (*OnionMessagePayload, error) {
+
+	if msg.BlindingPoint == nil {
+		return nil, fmt.Errorf("onion message is missing blinding " +
+			"point")
+	}
+
+	// Onion messages are always constructed for our blinded node key.
+	router, err := p.blindedRouter(msg.BlindingPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var onionPkt sphinx.OnionPacket
+	err = onionPkt.Decode(bytes.NewReader(msg.OnionBlob))
+	if err != nil {
+		return nil, err
+	}
+
+	processed, err := router.ReconstructOnionPacket(&onionPkt, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var encryptedData []byte
+	tlvStream, err := tlv.NewStream(
+		record.NewOnionMessageEncryptedDataRecord(&encryptedData),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := tlvStream.DecodeWithParsedTypes(
+		bytes.NewReader(processed.Payload.Payload),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := parsedTypes[record.OnionMessageEncryptedDataType]; !ok {
+		return nil, ErrInvalidPayload{
+			Type:      record.OnionMessageEncryptedDataType,
+			Violation: OmittedViolation,
+		}
+	}
+
+	plainText, err := blindedpath.DecryptBlindedData(
+		p.nodeKey, msg.BlindingPoint, encryptedData,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	routeData, err := record.DecodeBlindedRouteData(
+		bytes.NewReader(plainText),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	customRecords := make(record.CustomSet)
+	for t, value := range parsedTypes {
+		if value == nil || t < record.OnionMessageAppTypeStart {
+			continue
+		}
+		customRecords[uint64(t)] = value
+	}
+
+	// If we are the recipient of the message, we hand its content to the
+	// caller.
+	if processed.Action == sphinx.ExitNode {
+		return &OnionMessagePayload{
+			PathID:        routeData.PathID,
+			CustomRecords: customRecords,
+		}, nil
+	}
+
+	if routeData.NextNodeID == nil {
+		return nil, ErrOnionMessageNoNextNode
+	}
+	if len(customRecords) > 0 {
+		return nil, ErrOnionMessageForwardRecords
+	}
+
+	nextBlinding, err := blindedpath.NextBlindingPoint(
+		p.nodeKey, msg.BlindingPoint,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var nextOnion bytes.Buffer
+	if err := processed.NextPacket.Encode(&nextOnion); err != nil {
+		return nil, err
+	}
+
+	return &OnionMessagePayload{
+		NextNodeID: routeData.NextNodeID,
+		NextMessage: lnwire.NewOnionMessage(
+			nextBlinding, nextOnion.Bytes(),
+		),
+	}, nil
+}
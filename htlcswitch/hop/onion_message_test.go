@@ -0,0 +1,75 @@
+package hop
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOnionMessageRoundTrip tests that an onion message is forwarded along
+// its path and that only its recipient learns the records of the message.
+func TestOnionMessageRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	const numHops = 3
+
+	var (
+		path       []*btcec.PublicKey
+		processors []*OnionProcessor
+	)
+	for i := 0; i < numHops; i++ {
+		privKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		nodeKey := &keychain.PrivKeyECDH{PrivKey: privKey}
+		router := sphinx.NewRouter(
+			nodeKey, &chaincfg.RegressionNetParams, nil,
+		)
+
+		path = append(path, privKey.PubKey())
+		processors = append(processors, NewOnionProcessor(
+			router, nodeKey, &chaincfg.RegressionNetParams, nil,
+		))
+	}
+
+	records := record.CustomSet{
+		64:     []byte("invoice request"),
+		100001: []byte{1, 2, 3},
+	}
+
+	// Records outside of the range of the recipient can't be sent.
+	_, err := BuildOnionMessage(path, record.CustomSet{2: nil})
+	require.Error(t, err)
+
+	msg, err := BuildOnionMessage(path, records)
+	require.NoError(t, err)
+
+	// Each intermediate node learns the next node of the path, but none
+	// of the records.
+	for i := 0; i < numHops-1; i++ {
+		payload, err := processors[i].ProcessOnionMessage(msg)
+		require.NoError(t, err)
+
+		require.Equal(t, path[i+1], payload.NextNodeID)
+		require.Empty(t, payload.CustomRecords)
+		require.NotNil(t, payload.NextMessage)
+
+		// The message can't be processed by a node other than the
+		// one it is destined for.
+		_, err = processors[numHops-1].ProcessOnionMessage(msg)
+		require.Error(t, err)
+
+		msg = payload.NextMessage
+	}
+
+	payload, err := processors[numHops-1].ProcessOnionMessage(msg)
+	require.NoError(t, err)
+	require.Nil(t, payload.NextNodeID)
+	require.Nil(t, payload.NextMessage)
+	require.Equal(t, records, payload.CustomRecords)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	sphinx "github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/record"
@@ -93,6 +94,28 @@ type Payload struct {
 	// customRecords are user-defined records in the custom type range that
 	// were included in the payload.
 	customRecords record.CustomSet
+
+	// encryptedData is the encrypted_recipient_data of a hop within a
+	// blinded route. It is nil for regular hops.
+	encryptedData []byte
+
+	// blindingPoint is the blinding point the sender hands to the
+	// introduction node of a blinded route.
+	blindingPoint *btcec.PublicKey
+
+	// totalAmtMsat is the total amount of a payment to a blinded route,
+	// which is only set for the final hop of the route.
+	totalAmtMsat lnwire.MilliSatoshi
+
+	// trampolineOnion is the trampoline onion the sender included for us
+	// as trampoline node. It is nil if we are asked to forward the htlc
+	// to a channel or if we are the recipient of the payment.
+	trampolineOnion []byte
+
+	// parsedTypes are the types that were parsed from the payload of a
+	// hop within a blinded route. Whether they are valid can only be
+	// decided after the encrypted data has been decrypted.
+	parsedTypes tlv.TypeMap
 }
 
 // NewLegacyPayload builds a Payload from the amount, cltv, and next hop
@@ -120,6 +143,11 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 		cltv uint32
 		mpp  = &record.MPP{}
 		amp  = &record.AMP{}
+
+		encryptedData   []byte
+		blindingPoint   *btcec.PublicKey
+		totalAmtMsat    uint64
+		trampolineOnion []byte
 	)
 
 	tlvStream, err := tlv.NewStream(
@@ -127,7 +155,11 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 		record.NewLockTimeRecord(&cltv),
 		record.NewNextHopIDRecord(&cid),
 		mpp.Record(),
+		record.NewEncryptedDataRecord(&encryptedData),
+		record.NewBlindingPointRecord(&blindingPoint),
 		amp.Record(),
+		record.NewTotalAmtMsatRecord(&totalAmtMsat),
+		record.NewTrampolineOnionRecord(&trampolineOnion),
 	)
 	if err != nil {
 		return nil, err
@@ -139,9 +171,15 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 	}
 
 	// Validate whether the sender properly included or omitted tlv records
-	// in accordance with BOLT 04.
+	// in accordance with BOLT 04. The payload of a hop within a blinded
+	// route follows different rules.
 	nextHop := lnwire.NewShortChanIDFromInt(cid)
-	err = ValidateParsedPayloadTypes(parsedTypes, nextHop)
+	_, isBlinded := parsedTypes[record.EncryptedDataOnionType]
+	if isBlinded {
+		err = validateBlindedPayloadTypes(parsedTypes)
+	} else {
+		err = ValidateParsedPayloadTypes(parsedTypes, nextHop)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -171,7 +209,7 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 	// Filter out the custom records.
 	customRecords := NewCustomRecords(parsedTypes)
 
-	return &Payload{
+	payload := &Payload{
 		FwdInfo: ForwardingInfo{
 			Network:         BitcoinNetwork,
 			NextHop:         nextHop,
@@ -181,7 +219,20 @@ func NewPayloadFromReader(r io.Reader) (*Payload, error) {
 		MPP:           mpp,
 		AMP:           amp,
 		customRecords: customRecords,
-	}, nil
+	}
+
+	if _, ok := parsedTypes[record.TrampolineOnionType]; ok {
+		payload.trampolineOnion = trampolineOnion
+	}
+
+	if isBlinded {
+		payload.encryptedData = encryptedData
+		payload.blindingPoint = blindingPoint
+		payload.totalAmtMsat = lnwire.MilliSatoshi(totalAmtMsat)
+		payload.parsedTypes = parsedTypes
+	}
+
+	return payload, nil
 }
 
 // ForwardingInfo returns the basic parameters required for HTLC forwarding,
@@ -217,6 +268,9 @@ func ValidateParsedPayloadTypes(parsedTypes tlv.TypeMap,
 	_, hasNextHop := parsedTypes[record.NextHopOnionType]
 	_, hasMPP := parsedTypes[record.MPPOnionType]
 	_, hasAMP := parsedTypes[record.AMPOnionType]
+	_, hasBlinding := parsedTypes[record.BlindingPointOnionType]
+	_, hasTotalAmt := parsedTypes[record.TotalAmtMsatOnionType]
+	_, hasTrampoline := parsedTypes[record.TrampolineOnionType]
 
 	switch {
 
@@ -261,6 +315,64 @@ func ValidateParsedPayloadTypes(parsedTypes tlv.TypeMap,
 			Violation: IncludedViolation,
 			FinalHop:  isFinalHop,
 		}
+
+	// A blinding point is only handed to the introduction node of a
+	// blinded route, which must also receive encrypted data.
+	case hasBlinding:
+		return ErrInvalidPayload{
+			Type:      record.EncryptedDataOnionType,
+			Violation: OmittedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// The total amount is only used for payments to blinded routes.
+	case hasTotalAmt:
+		return ErrInvalidPayload{
+			Type:      record.TotalAmtMsatOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+
+	// A trampoline onion is only handed to the trampoline node at the end
+	// of a route.
+	case !isFinalHop && hasTrampoline:
+		return ErrInvalidPayload{
+			Type:      record.TrampolineOnionType,
+			Violation: IncludedViolation,
+			FinalHop:  isFinalHop,
+		}
+	}
+
+	return nil
+}
+
+// validateBlindedPayloadTypes checks the types parsed from the payload of a
+// hop within a blinded route. The forwarding instructions of such a hop are
+// part of the encrypted data, so it must not receive the ones of a regular
+// hop.
+func validateBlindedPayloadTypes(parsedTypes tlv.TypeMap) error {
+	_, hasNextHop := parsedTypes[record.NextHopOnionType]
+	_, hasMPP := parsedTypes[record.MPPOnionType]
+	_, hasAMP := parsedTypes[record.AMPOnionType]
+
+	switch {
+	case hasNextHop:
+		return ErrInvalidPayload{
+			Type:      record.NextHopOnionType,
+			Violation: IncludedViolation,
+		}
+
+	case hasMPP:
+		return ErrInvalidPayload{
+			Type:      record.MPPOnionType,
+			Violation: IncludedViolation,
+		}
+
+	case hasAMP:
+		return ErrInvalidPayload{
+			Type:      record.AMPOnionType,
+			Violation: IncludedViolation,
+		}
 	}
 
 	return nil
@@ -284,6 +396,12 @@ func (h *Payload) CustomRecords() record.CustomSet {
 	return h.customRecords
 }
 
+// TrampolineOnion returns the trampoline onion that was parsed from the
+// payload, or nil if there is none.
+func (h *Payload) TrampolineOnion() []byte {
+	return h.trampolineOnion
+}
+
 // getMinRequiredViolation checks for unrecognized required (even) fields in the
 // standard range and returns the lowest required type. Always returning the
 // lowest required type allows a failure message to be deterministic.
@@ -0,0 +1,432 @@
+package hop
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/tlv"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	// trampolineOnionVersion is the version of the trampoline onion
+	// layers we create and understand.
+	trampolineOnionVersion byte = 0
+
+	// trampolineTagSize is the size of the authentication tag of a
+	// trampoline onion layer.
+	trampolineTagSize = 16
+
+	// trampolineLayerOverhead is the number of bytes every layer of a
+	// trampoline onion adds to the payload it encrypts: the version, the
+	// ephemeral public key and the authentication tag.
+	trampolineLayerOverhead = 1 + btcec.PubKeyBytesLenCompressed +
+		trampolineTagSize
+
+	// maxRouteHintLength is the maximum number of hops a single route hint
+	// within a trampoline payload can have.
+	maxRouteHintLength = 255
+)
+
+var (
+	// trampolineKeyType is the key type used to derive the encryption key
+	// of a trampoline onion layer from the shared secret with its node.
+	trampolineKeyType = []byte("trampoline")
+
+	// ErrInvalidTrampolineOnion is returned when a trampoline onion can't
+	// be decrypted with our node key.
+	ErrInvalidTrampolineOnion = errors.New("invalid trampoline onion")
+)
+
+// TrampolinePayload holds the forwarding instructions the sender of a payment
+// left for a trampoline node within the trampoline onion. The trampoline node
+// finds a route to the outgoing node by itself.
+type TrampolinePayload struct {
+	// OutgoingNodeID is the node the payment needs to be forwarded to,
+	// which is either the recipient or the next trampoline node.
+	OutgoingNodeID *btcec.PublicKey
+
+	// AmtToForward is the amount the outgoing node needs to receive.
+	AmtToForward lnwire.MilliSatoshi
+
+	// OutgoingCltv is the expiry of the HTLCs the outgoing node needs to
+	// receive.
+	OutgoingCltv uint32
+
+	// MPP carries the payment address and total amount of the invoice
+	// that is paid. It is only set if the outgoing node is the recipient.
+	MPP *record.MPP
+
+	// InvoiceFeatures are the features of the invoice that is paid. It is
+	// only set if the outgoing node is the recipient.
+	InvoiceFeatures *lnwire.FeatureVector
+
+	// RouteHints are the route hints of the invoice that is paid. They
+	// are only set if the outgoing node is the recipient.
+	RouteHints [][]zpay32.HopHint
+
+	// NextOnion is the trampoline onion for the outgoing node. It is only
+	// set if the outgoing node is a trampoline node itself.
+	NextOnion []byte
+}
+
+// Encode serializes the trampoline payload as a TLV stream.
+func (t *TrampolinePayload) Encode(w io.Writer) error {
+	if t.OutgoingNodeID == nil {
+		return errors.New("trampoline payload is missing outgoing node")
+	}
+
+	amt := uint64(t.AmtToForward)
+	records := []tlv.Record{
+		record.NewAmtToFwdRecord(&amt),
+		record.NewLockTimeRecord(&t.OutgoingCltv),
+		record.NewOutgoingNodeIDRecord(&t.OutgoingNodeID),
+	}
+
+	if t.MPP != nil {
+		records = append(records, t.MPP.Record())
+	}
+
+	if t.NextOnion != nil {
+		records = append(records,
+			record.NewTrampolineOnionRecord(&t.NextOnion),
+		)
+	}
+
+	if t.InvoiceFeatures != nil {
+		var b bytes.Buffer
+		err := t.InvoiceFeatures.RawFeatureVector.EncodeBase256(&b)
+		if err != nil {
+			return err
+		}
+
+		features := b.Bytes()
+		records = append(records,
+			record.NewInvoiceFeaturesRecord(&features),
+		)
+	}
+
+	if len(t.RouteHints) > 0 {
+		routingInfo, err := encodeRouteHints(t.RouteHints)
+		if err != nil {
+			return err
+		}
+
+		records = append(records,
+			record.NewInvoiceRoutingInfoRecord(&routingInfo),
+		)
+	}
+
+	tlv.SortRecords(records)
+
+	tlvStream, err := tlv.NewStream(records...)
+	if err != nil {
+		return err
+	}
+
+	return tlvStream.Encode(w)
+}
+
+// decodeTrampolinePayload parses the payload of a trampoline onion layer.
+func decodeTrampolinePayload(r io.Reader) (*TrampolinePayload, error) {
+	var (
+		t           TrampolinePayload
+		amt         uint64
+		mpp         = &record.MPP{}
+		features    []byte
+		routingInfo []byte
+	)
+
+	tlvStream, err := tlv.NewStream(
+		record.NewAmtToFwdRecord(&amt),
+		record.NewLockTimeRecord(&t.OutgoingCltv),
+		mpp.Record(),
+		record.NewTrampolineOnionRecord(&t.NextOnion),
+		record.NewOutgoingNodeIDRecord(&t.OutgoingNodeID),
+		record.NewInvoiceFeaturesRecord(&features),
+		record.NewInvoiceRoutingInfoRecord(&routingInfo),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := tlvStream.DecodeWithParsedTypes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, required := range []tlv.Type{
+		record.AmtOnionType, record.LockTimeOnionType,
+		record.OutgoingNodeIDOnionType,
+	} {
+		if _, ok := parsedTypes[required]; !ok {
+			return nil, ErrInvalidPayload{
+				Type:      required,
+				Violation: OmittedViolation,
+			}
+		}
+	}
+
+	// Just like a regular payload, we must understand all even records
+	// the sender included.
+	violatingType := getMinRequiredViolation(parsedTypes)
+	if violatingType != nil {
+		return nil, ErrInvalidPayload{
+			Type:      *violatingType,
+			Violation: RequiredViolation,
+		}
+	}
+
+	t.AmtToForward = lnwire.MilliSatoshi(amt)
+
+	if _, ok := parsedTypes[record.MPPOnionType]; ok {
+		t.MPP = mpp
+	}
+
+	if _, ok := parsedTypes[record.InvoiceFeaturesOnionType]; ok {
+		raw := lnwire.NewRawFeatureVector()
+		err := raw.DecodeBase256(
+			bytes.NewReader(features), len(features),
+		)
+		if err != nil {
+			return nil, err
+		}
+		t.InvoiceFeatures = lnwire.NewFeatureVector(
+			raw, lnwire.Features,
+		)
+	}
+
+	if _, ok := parsedTypes[record.InvoiceRoutingInfoOnionType]; ok {
+		t.RouteHints, err = decodeRouteHints(routingInfo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &t, nil
+}
+
+// encodeRouteHints serializes the route hints of an invoice. Every route hint
+// is prefixed with its number of hops.
+func encodeRouteHints(routeHints [][]zpay32.HopHint) ([]byte, error) {
+	var b bytes.Buffer
+	for _, routeHint := range routeHints {
+		if len(routeHint) == 0 || len(routeHint) > maxRouteHintLength {
+			return nil, fmt.Errorf("invalid route hint length: %d",
+				len(routeHint))
+		}
+
+		b.WriteByte(byte(len(routeHint)))
+		for _, hopHint := range routeHint {
+			b.Write(hopHint.NodeID.SerializeCompressed())
+
+			for _, v := range []interface{}{
+				hopHint.ChannelID, hopHint.FeeBaseMSat,
+				hopHint.FeeProportionalMillionths,
+				hopHint.CLTVExpiryDelta,
+			} {
+				err := binary.Write(&b, binary.BigEndian, v)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+// decodeRouteHints parses the route hints serialized by encodeRouteHints.
+func decodeRouteHints(b []byte) ([][]zpay32.HopHint, error) {
+	var (
+		r          = bytes.NewReader(b)
+		routeHints [][]zpay32.HopHint
+	)
+
+	for r.Len() > 0 {
+		numHops, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if numHops == 0 {
+			return nil, errors.New("empty route hint")
+		}
+
+		routeHint := make([]zpay32.HopHint, numHops)
+		for i := range routeHint {
+			var pubKey [btcec.PubKeyBytesLenCompressed]byte
+			if _, err := io.ReadFull(r, pubKey[:]); err != nil {
+				return nil, err
+			}
+
+			routeHint[i].NodeID, err = btcec.ParsePubKey(pubKey[:])
+			if err != nil {
+				return nil, err
+			}
+
+			for _, v := range []interface{}{
+				&routeHint[i].ChannelID,
+				&routeHint[i].FeeBaseMSat,
+				&routeHint[i].FeeProportionalMillionths,
+				&routeHint[i].CLTVExpiryDelta,
+			} {
+				err := binary.Read(r, binary.BigEndian, v)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		routeHints = append(routeHints, routeHint)
+	}
+
+	return routeHints, nil
+}
+
+// TrampolineHop is a trampoline node of a payment along with the payload the
+// sender leaves for it.
+type TrampolineHop struct {
+	// NodeID is the public key of the trampoline node.
+	NodeID *btcec.PublicKey
+
+	// Payload holds the forwarding instructions for the trampoline node.
+	// The NextOnion of all but the last hop is filled in when the onion
+	// is built.
+	Payload *TrampolinePayload
+}
+
+// BuildTrampolineOnion creates the trampoline onion that the first of the
+// passed trampoline nodes receives in the payload of the final hop of the
+// route to it. Every layer of the onion is encrypted to its trampoline node
+// with a fresh ephemeral key and contains the onion for the next trampoline
+// node.
+//
+// NOTE: Unlike the sphinx onion, the layers aren't padded to a fixed size, so
+// a trampoline node is able to learn its distance from the last trampoline
+// node.
+func BuildTrampolineOnion(hops []*TrampolineHop) ([]byte, error) {
+	if len(hops) == 0 {
+		return nil, errors.New("trampoline route must not be empty")
+	}
+
+	var onion []byte
+	for i := len(hops) - 1; i >= 0; i-- {
+		payload := *hops[i].Payload
+		if i < len(hops)-1 {
+			payload.NextOnion = onion
+		}
+
+		var b bytes.Buffer
+		if err := payload.Encode(&b); err != nil {
+			return nil, err
+		}
+
+		var err error
+		onion, err = encryptTrampolineLayer(hops[i].NodeID, b.Bytes())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return onion, nil
+}
+
+// encryptTrampolineLayer encrypts the payload of a trampoline node to its
+// public key.
+func encryptTrampolineLayer(nodeID *btcec.PublicKey,
+	plainText []byte) ([]byte, error) {
+
+	ephemeralKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	ephemeralECDH := &keychain.PrivKeyECDH{PrivKey: ephemeralKey}
+	sharedSecret, err := ephemeralECDH.ECDH(nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.New(trampolineKey(sharedSecret))
+	if err != nil {
+		return nil, err
+	}
+
+	onion := make([]byte, 0, len(plainText)+trampolineLayerOverhead)
+	onion = append(onion, trampolineOnionVersion)
+	onion = append(onion, ephemeralKey.PubKey().SerializeCompressed()...)
+
+	// Every layer is encrypted with a fresh key, so a zero nonce is safe
+	// to use.
+	var nonce [chacha20poly1305.NonceSize]byte
+	return aead.Seal(onion, nonce[:], plainText, nil), nil
+}
+
+// DecodeTrampolineOnion decrypts the layer of the trampoline onion that the
+// sender encrypted to our node key and returns our forwarding instructions.
+func (p *OnionProcessor) DecodeTrampolineOnion(
+	onion []byte) (*TrampolinePayload, error) {
+
+	return decodeTrampolineOnion(p.nodeKey, onion)
+}
+
+// decodeTrampolineOnion decrypts a trampoline onion layer with the passed
+// node key.
+func decodeTrampolineOnion(nodeKey keychain.SingleKeyECDH,
+	onion []byte) (*TrampolinePayload, error) {
+
+	if len(onion) < trampolineLayerOverhead {
+		return nil, ErrInvalidTrampolineOnion
+	}
+
+	if onion[0] != trampolineOnionVersion {
+		return nil, fmt.Errorf("%w: unknown version %d",
+			ErrInvalidTrampolineOnion, onion[0])
+	}
+
+	ephemeralKey, err := btcec.ParsePubKey(onion[1 : 1+btcec.PubKeyBytesLenCompressed])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrampolineOnion,
+			err)
+	}
+
+	sharedSecret, err := nodeKey.ECDH(ephemeralKey)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.New(trampolineKey(sharedSecret))
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [chacha20poly1305.NonceSize]byte
+	plainText, err := aead.Open(
+		nil, nonce[:], onion[1+btcec.PubKeyBytesLenCompressed:], nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTrampolineOnion,
+			err)
+	}
+
+	return decodeTrampolinePayload(bytes.NewReader(plainText))
+}
+
+// trampolineKey derives the encryption key of a trampoline onion layer from
+// the shared secret with its node.
+func trampolineKey(sharedSecret [32]byte) []byte {
+	mac := hmac.New(sha256.New, trampolineKeyType)
+	mac.Write(sharedSecret[:])
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,147 @@
+package hop
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTrampolineOnionRoundTrip tests that every trampoline node of a route is
+// only able to decrypt its own layer of the trampoline onion, and learns the
+// onion of the next trampoline node from it.
+func TestTrampolineOnionRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	newKey := func() *btcec.PrivateKey {
+		privKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		return privKey
+	}
+
+	var (
+		trampoline1 = newKey()
+		trampoline2 = newKey()
+		recipient   = newKey()
+		hintNode    = newKey()
+		paymentAddr = [32]byte{1, 2, 3}
+	)
+
+	finalPayload := &TrampolinePayload{
+		OutgoingNodeID: recipient.PubKey(),
+		AmtToForward:   100_000,
+		OutgoingCltv:   600,
+		MPP:            record.NewMPP(100_000, paymentAddr),
+		InvoiceFeatures: lnwire.NewFeatureVector(
+			lnwire.NewRawFeatureVector(
+				lnwire.TLVOnionPayloadOptional,
+				lnwire.PaymentAddrRequired,
+			), lnwire.Features,
+		),
+		RouteHints: [][]zpay32.HopHint{{{
+			NodeID:                    hintNode.PubKey(),
+			ChannelID:                 12345,
+			FeeBaseMSat:               1000,
+			FeeProportionalMillionths: 10,
+			CLTVExpiryDelta:           40,
+		}}},
+	}
+
+	onion, err := BuildTrampolineOnion([]*TrampolineHop{
+		{
+			NodeID: trampoline1.PubKey(),
+			Payload: &TrampolinePayload{
+				OutgoingNodeID: trampoline2.PubKey(),
+				AmtToForward:   100_500,
+				OutgoingCltv:   700,
+			},
+		},
+		{
+			NodeID:  trampoline2.PubKey(),
+			Payload: finalPayload,
+		},
+	})
+	require.NoError(t, err)
+
+	// Only the first trampoline node is able to decrypt the onion.
+	_, err = decodeTrampolineOnion(
+		&keychain.PrivKeyECDH{PrivKey: trampoline2}, onion,
+	)
+	require.ErrorIs(t, err, ErrInvalidTrampolineOnion)
+
+	payload1, err := decodeTrampolineOnion(
+		&keychain.PrivKeyECDH{PrivKey: trampoline1}, onion,
+	)
+	require.NoError(t, err)
+	require.Equal(t, trampoline2.PubKey(), payload1.OutgoingNodeID)
+	require.Equal(t, lnwire.MilliSatoshi(100_500), payload1.AmtToForward)
+	require.Equal(t, uint32(700), payload1.OutgoingCltv)
+	require.Nil(t, payload1.MPP)
+	require.NotEmpty(t, payload1.NextOnion)
+
+	payload2, err := decodeTrampolineOnion(
+		&keychain.PrivKeyECDH{PrivKey: trampoline2}, payload1.NextOnion,
+	)
+	require.NoError(t, err)
+	require.Equal(t, recipient.PubKey(), payload2.OutgoingNodeID)
+	require.Equal(t, finalPayload.AmtToForward, payload2.AmtToForward)
+	require.Equal(t, finalPayload.OutgoingCltv, payload2.OutgoingCltv)
+	require.Equal(t, finalPayload.MPP, payload2.MPP)
+	require.Equal(t, finalPayload.RouteHints, payload2.RouteHints)
+	require.True(t, payload2.InvoiceFeatures.HasFeature(
+		lnwire.PaymentAddrRequired,
+	))
+	require.Nil(t, payload2.NextOnion)
+
+	// A tampered onion must be rejected.
+	tampered := append([]byte(nil), onion...)
+	tampered[len(tampered)-1] ^= 1
+	_, err = decodeTrampolineOnion(
+		&keychain.PrivKeyECDH{PrivKey: trampoline1}, tampered,
+	)
+	require.ErrorIs(t, err, ErrInvalidTrampolineOnion)
+}
+
+// TestTrampolineOnionPayload tests that the trampoline onion is only accepted
+// in the payload of the final hop of a route.
+func TestTrampolineOnionPayload(t *testing.T) {
+	t.Parallel()
+
+	onion := []byte{0, 1, 2, 3}
+	finalPayload := []byte{
+		// amount
+		0x02, 0x00,
+		// cltv
+		0x04, 0x00,
+		// trampoline onion
+		0x14, 0x04, 0x00, 0x01, 0x02, 0x03,
+	}
+
+	payload, err := NewPayloadFromReader(bytes.NewReader(finalPayload))
+	require.NoError(t, err)
+	require.Equal(t, onion, payload.TrampolineOnion())
+	require.Empty(t, payload.CustomRecords())
+
+	intermediatePayload := []byte{
+		// amount
+		0x02, 0x00,
+		// cltv
+		0x04, 0x00,
+		// next hop id
+		0x06, 0x08, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		// trampoline onion
+		0x14, 0x04, 0x00, 0x01, 0x02, 0x03,
+	}
+
+	_, err = NewPayloadFromReader(bytes.NewReader(intermediatePayload))
+	require.Equal(t, ErrInvalidPayload{
+		Type:      record.TrampolineOnionType,
+		Violation: IncludedViolation,
+	}, err)
+}
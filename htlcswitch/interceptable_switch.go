@@ -3,20 +3,61 @@ package htlcswitch
 import (
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"github.com/go-errors/errors"
+	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
+const (
+	// DefaultInterceptorCltvRejectDelta is the default number of blocks
+	// before the expiry of the incoming htlc at which a held forward is
+	// failed back automatically.
+	DefaultInterceptorCltvRejectDelta = 10
+)
+
 var (
 	// ErrFwdNotExists is an error returned when the caller tries to resolve
 	// a forward that doesn't exist anymore.
 	ErrFwdNotExists = errors.New("forward does not exist")
+
+	// errBlockStreamStopped is returned when the block epoch stream of the
+	// interceptable switch is closed unexpectedly.
+	errBlockStreamStopped = errors.New("block epoch stream stopped")
+
+	// errInterceptableSwitchStopped is returned when the interceptable
+	// switch is shutting down.
+	errInterceptableSwitchStopped = errors.New("interceptable switch " +
+		"stopped")
 )
 
+// InterceptableSwitchConfig contains the configuration of the
+// InterceptableSwitch.
+type InterceptableSwitchConfig struct {
+	// Switch is a reference to the actual switch implementation that
+	// packets get sent to on resume.
+	Switch *Switch
+
+	// Notifier is an instance of a chain notifier that we'll use to
+	// signal the switch when a new block has arrived.
+	Notifier chainntnfs.ChainNotifier
+
+	// CltvRejectDelta is the number of blocks before the expiry of the
+	// incoming htlc at which a held forward is failed back automatically.
+	// This prevents the incoming channel from being force closed because
+	// the interceptor didn't resolve the htlc in time.
+	CltvRejectDelta uint32
+
+	// RequireInterceptor indicates whether forwards are held even if no
+	// interceptor is registered. They are then offered to the interceptor
+	// once it connects.
+	RequireInterceptor bool
+}
+
 // InterceptableSwitch is an implementation of ForwardingSwitch interface.
 // This implementation is used like a proxy that wraps the switch and
 // intercepts forward requests. A reference to the Switch is held in order
@@ -24,30 +65,306 @@ var (
 // Resume - forwards the original request to the switch as is.
 // Settle - routes UpdateFulfillHTLC to the originating link.
 // Fail - routes UpdateFailHTLC to the originating link.
+//
+// Intercepted forwards are held until they are resolved, even if the
+// interceptor disconnects in the meantime, and are offered again to the next
+// interceptor that connects. Forwards that aren't resolved before their
+// incoming expiry gets too close are failed back automatically. As held
+// forwards aren't committed to the circuit map, the incoming links replay
+// them after a restart, so that they are held again.
 type InterceptableSwitch struct {
-	sync.RWMutex
+	started int32 // To be used atomically.
+	stopped int32 // To be used atomically.
 
 	// htlcSwitch is the underline switch
 	htlcSwitch *Switch
 
-	// fwdInterceptor is the callback that is called for each forward of
-	// an incoming htlc. It should return true if it is interested in handling
-	// it.
-	fwdInterceptor ForwardInterceptor
+	// intercepted is where we stream all intercepted packets coming from
+	// the switch.
+	intercepted chan *interceptedPackets
+
+	// resolutionChan is where we stream all responses coming from the
+	// interceptor client.
+	resolutionChan chan *fwdResolution
+
+	// interceptorRegistration is a channel that we use to synchronize
+	// client connect and disconnect.
+	interceptorRegistration chan ForwardInterceptor
+
+	// requireInterceptor indicates whether processing should block if no
+	// interceptor is connected.
+	requireInterceptor bool
+
+	// interceptor is the handler for intercepted packets.
+	interceptor ForwardInterceptor
+
+	// heldHtlcSet keeps track of outstanding intercepted forwards.
+	heldHtlcSet *heldHtlcSet
+
+	// cltvRejectDelta defines the number of blocks before the expiry of
+	// the htlc where we auto-fail an intercepted htlc to prevent channel
+	// force-closure.
+	cltvRejectDelta uint32
+
+	// notifier is an instance of a chain notifier that we'll use to
+	// signal the switch when a new block has arrived.
+	notifier chainntnfs.ChainNotifier
+
+	// blockEpochStream is an active block epoch event stream backed by an
+	// active ChainNotifier instance. This will be used to retrieve the
+	// latest height of the chain.
+	blockEpochStream *chainntnfs.BlockEpochEvent
+
+	// currentHeight is the currently best known height.
+	currentHeight int32
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// interceptedPackets is a batch of packets that is handed to the main loop
+// of the InterceptableSwitch. The main loop replies with the packets that it
+// didn't intercept, so that they can be forwarded to the switch.
+type interceptedPackets struct {
+	packets        []*htlcPacket
+	linkQuit       chan struct{}
+	notIntercepted chan []*htlcPacket
+}
+
+// fwdResolution wraps a resolution of an intercepted forward together with a
+// channel to report back the result.
+type fwdResolution struct {
+	resolution *FwdResolution
+	errChan    chan error
 }
 
 // NewInterceptableSwitch returns an instance of InterceptableSwitch.
-func NewInterceptableSwitch(s *Switch) *InterceptableSwitch {
-	return &InterceptableSwitch{htlcSwitch: s}
+func NewInterceptableSwitch(
+	cfg *InterceptableSwitchConfig) *InterceptableSwitch {
+
+	return &InterceptableSwitch{
+		htlcSwitch:              cfg.Switch,
+		intercepted:             make(chan *interceptedPackets),
+		interceptorRegistration: make(chan ForwardInterceptor),
+		heldHtlcSet:             newHeldHtlcSet(),
+		resolutionChan:          make(chan *fwdResolution),
+		requireInterceptor:      cfg.RequireInterceptor,
+		cltvRejectDelta:         cfg.CltvRejectDelta,
+		notifier:                cfg.Notifier,
+		quit:                    make(chan struct{}),
+	}
+}
+
+// Start starts the main loop of the InterceptableSwitch.
+func (s *InterceptableSwitch) Start() error {
+	if !atomic.CompareAndSwapInt32(&s.started, 0, 1) {
+		return errors.New("interceptable switch already started")
+	}
+
+	blockEpochStream, err := s.notifier.RegisterBlockEpochNtfn(nil)
+	if err != nil {
+		return err
+	}
+	s.blockEpochStream = blockEpochStream
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		err := s.run()
+		if err != nil {
+			log.Errorf("InterceptableSwitch stopped: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops the main loop of the InterceptableSwitch.
+func (s *InterceptableSwitch) Stop() error {
+	if !atomic.CompareAndSwapInt32(&s.stopped, 0, 1) {
+		return errors.New("interceptable switch already stopped")
+	}
+
+	close(s.quit)
+	s.wg.Wait()
+
+	if s.blockEpochStream != nil {
+		s.blockEpochStream.Cancel()
+	}
+
+	return nil
+}
+
+// run is the main loop of the InterceptableSwitch. All access to the held
+// forwards and the registered interceptor is serialized through it.
+func (s *InterceptableSwitch) run() error {
+	// The block epoch stream will immediately stream the current height.
+	// Read it out here.
+	select {
+	case currentBlock, ok := <-s.blockEpochStream.Epochs:
+		if !ok {
+			return errBlockStreamStopped
+		}
+		s.currentHeight = currentBlock.Height
+
+	case <-s.quit:
+		return nil
+	}
+
+	log.Debugf("InterceptableSwitch running: height=%v, "+
+		"requireInterceptor=%v", s.currentHeight, s.requireInterceptor)
+
+	for {
+		select {
+		// An interceptor registration or de-registration came in.
+		case interceptor := <-s.interceptorRegistration:
+			s.setInterceptor(interceptor)
+
+		case packets := <-s.intercepted:
+			var notIntercepted []*htlcPacket
+			for _, p := range packets.packets {
+				if !s.interceptForward(p, packets.linkQuit) {
+					notIntercepted = append(
+						notIntercepted, p,
+					)
+				}
+			}
+			packets.notIntercepted <- notIntercepted
+
+		case res := <-s.resolutionChan:
+			res.errChan <- s.resolve(res.resolution)
+
+		case currentBlock, ok := <-s.blockEpochStream.Epochs:
+			if !ok {
+				return errBlockStreamStopped
+			}
+			s.currentHeight = currentBlock.Height
+
+			// A new block is appended. Fail any held htlcs that
+			// expire at this height to prevent channel force-close.
+			s.failExpiredHtlcs()
+
+		case <-s.quit:
+			return nil
+		}
+	}
+}
+
+// failExpiredHtlcs fails all held forwards that reached their auto-fail
+// height.
+func (s *InterceptableSwitch) failExpiredHtlcs() {
+	s.heldHtlcSet.popAutoFails(
+		s.currentHeight,
+		func(fwd InterceptedForward) {
+			log.Debugf("Auto-failing held htlc %v at height %v",
+				fwd.Packet().IncomingCircuit, s.currentHeight)
+
+			if err := fwd.Fail(); err != nil {
+				log.Errorf("Cannot fail packet: %v", err)
+			}
+		},
+	)
+}
+
+// sendForward offers the held forward to the registered interceptor.
+func (s *InterceptableSwitch) sendForward(fwd InterceptedForward) {
+	err := s.interceptor(fwd.Packet())
+	if err != nil {
+		// Only log the error. If the interceptor is going to exit
+		// because of this error, the forward is offered again when it
+		// reconnects.
+		log.Errorf("Interceptor cannot handle forward %v: %v",
+			fwd.Packet().IncomingCircuit, err)
+	}
+}
+
+// setInterceptor registers the given interceptor. All held forwards are
+// offered to a new interceptor. If the interceptor disconnects, the held
+// forwards remain held until the next interceptor connects or until they are
+// auto-failed.
+func (s *InterceptableSwitch) setInterceptor(interceptor ForwardInterceptor) {
+	s.interceptor = interceptor
+
+	if interceptor == nil {
+		log.Infof("Interceptor disconnected, keeping %d held htlcs",
+			s.heldHtlcSet.len())
+
+		return
+	}
+
+	log.Debugf("Interceptor connected, offering %d held htlcs",
+		s.heldHtlcSet.len())
+
+	s.heldHtlcSet.forEach(s.sendForward)
+}
+
+// resolve applies the resolution to the held forward it refers to.
+func (s *InterceptableSwitch) resolve(res *FwdResolution) error {
+	// Verify the preimage before we release the forward, so that a wrong
+	// preimage doesn't leave the htlc unresolved.
+	if res.Action == FwdActionSettle && s.heldHtlcSet.exists(res.Key) {
+		fwd := s.heldHtlcSet.set[res.Key]
+		if !res.Preimage.Matches(fwd.Packet().Hash) {
+			return errors.New("preimage does not match hash")
+		}
+	}
+
+	intercepted, err := s.heldHtlcSet.pop(res.Key)
+	if err != nil {
+		return err
+	}
+
+	switch res.Action {
+	case FwdActionResume:
+		return intercepted.Resume()
+
+	case FwdActionSettle:
+		return intercepted.Settle(res.Preimage)
+
+	case FwdActionFail:
+		return intercepted.Fail()
+
+	default:
+		return fmt.Errorf("unrecognized action %v", res.Action)
+	}
 }
 
-// SetInterceptor sets the ForwardInterceptor to be used.
+// Resolve resolves an intercepted packet.
+func (s *InterceptableSwitch) Resolve(res *FwdResolution) error {
+	internalRes := &fwdResolution{
+		resolution: res,
+		errChan:    make(chan error, 1),
+	}
+
+	select {
+	case s.resolutionChan <- internalRes:
+
+	case <-s.quit:
+		return errInterceptableSwitchStopped
+	}
+
+	select {
+	case err := <-internalRes.errChan:
+		return err
+
+	case <-s.quit:
+		return errInterceptableSwitchStopped
+	}
+}
+
+// SetInterceptor sets the ForwardInterceptor to be used. A nil argument
+// unregisters the current interceptor.
 func (s *InterceptableSwitch) SetInterceptor(
 	interceptor ForwardInterceptor) {
 
-	s.Lock()
-	defer s.Unlock()
-	s.fwdInterceptor = interceptor
+	// Synchronize setting the handler with the main loop to prevent race
+	// conditions.
+	select {
+	case s.interceptorRegistration <- interceptor:
+
+	case <-s.quit:
+	}
 }
 
 // ForwardPackets attempts to forward the batch of htlcs through the
@@ -57,23 +374,50 @@ func (s *InterceptableSwitch) SetInterceptor(
 func (s *InterceptableSwitch) ForwardPackets(linkQuit chan struct{},
 	packets ...*htlcPacket) error {
 
-	var interceptor ForwardInterceptor
-	s.Lock()
-	interceptor = s.fwdInterceptor
-	s.Unlock()
-
-	// Optimize for the case we don't have an interceptor.
-	if interceptor == nil {
+	// Optimize for the case that there is nothing to intercept.
+	if !hasInterceptableAdd(packets) {
 		return s.htlcSwitch.ForwardPackets(linkQuit, packets...)
 	}
 
+	req := &interceptedPackets{
+		packets:        packets,
+		linkQuit:       linkQuit,
+		notIntercepted: make(chan []*htlcPacket, 1),
+	}
+
+	select {
+	case s.intercepted <- req:
+
+	case <-linkQuit:
+		log.Debugf("Forward cancelled because link quit")
+		return nil
+
+	case <-s.quit:
+		return errInterceptableSwitchStopped
+	}
+
 	var notIntercepted []*htlcPacket
+	select {
+	case notIntercepted = <-req.notIntercepted:
+
+	case <-s.quit:
+		return errInterceptableSwitchStopped
+	}
+
+	return s.htlcSwitch.ForwardPackets(linkQuit, notIntercepted...)
+}
+
+// hasInterceptableAdd returns true if any of the packets is a forwarded add
+// that may be intercepted.
+func hasInterceptableAdd(packets []*htlcPacket) bool {
 	for _, p := range packets {
-		if !s.interceptForward(p, interceptor, linkQuit) {
-			notIntercepted = append(notIntercepted, p)
+		_, isAdd := p.htlc.(*lnwire.UpdateAddHTLC)
+		if isAdd && p.incomingChanID != hop.Source {
+			return true
 		}
 	}
-	return s.htlcSwitch.ForwardPackets(linkQuit, notIntercepted...)
+
+	return false
 }
 
 // interceptForward checks if there is any external interceptor interested in
@@ -81,37 +425,89 @@ func (s *InterceptableSwitch) ForwardPackets(linkQuit chan struct{},
 // are being checked for interception. It can be extended in the future given
 // the right use case.
 func (s *InterceptableSwitch) interceptForward(packet *htlcPacket,
-	interceptor ForwardInterceptor, linkQuit chan struct{}) bool {
+	linkQuit chan struct{}) bool {
+
+	htlc, ok := packet.htlc.(*lnwire.UpdateAddHTLC)
+	if !ok {
+		return false
+	}
+
+	// We are not interested in intercepting initiated payments.
+	if packet.incomingChanID == hop.Source {
+		return false
+	}
+
+	// Held htlcs are failed back a number of blocks before they expire,
+	// so that the incoming channel doesn't need to be force closed.
+	autoFailHeight := int32(packet.incomingTimeout) -
+		int32(s.cltvRejectDelta)
 
-	switch htlc := packet.htlc.(type) {
-	case *lnwire.UpdateAddHTLC:
-		// We are not interested in intercepting initated payments.
-		if packet.incomingChanID == hop.Source {
-			return false
+	intercepted := &interceptedForward{
+		linkQuit:       linkQuit,
+		htlc:           htlc,
+		packet:         packet,
+		htlcSwitch:     s.htlcSwitch,
+		autoFailHeight: autoFailHeight,
+	}
+
+	// If the htlc is already held, the incoming link replayed it after
+	// it was restarted. We keep holding it, but make sure to use the
+	// current link from now on. The interceptor already knows about it,
+	// so we don't offer it again.
+	inKey := packet.inKey()
+	if s.heldHtlcSet.exists(inKey) {
+		log.Debugf("Replayed htlc %v is already held", inKey)
+
+		err := s.heldHtlcSet.replace(inKey, intercepted)
+		if err != nil {
+			log.Errorf("Cannot replace held htlc: %v", err)
 		}
 
-		intercepted := &interceptedForward{
-			linkQuit:   linkQuit,
-			htlc:       htlc,
-			packet:     packet,
-			htlcSwitch: s.htlcSwitch,
+		return true
+	}
+
+	// Without an interceptor, we let the switch forward the htlc unless
+	// we are required to hold it for an interceptor to connect.
+	if s.interceptor == nil && !s.requireInterceptor {
+		return false
+	}
+
+	// Fail the htlc right away if it would be auto-failed anyway, rather
+	// than holding it without any chance of being resolved.
+	if s.currentHeight >= intercepted.autoFailHeight {
+		log.Debugf("Htlc %v expires too soon to be held, failing: "+
+			"incoming_expiry=%v, height=%v", inKey,
+			packet.incomingTimeout, s.currentHeight)
+
+		if err := intercepted.Fail(); err != nil {
+			log.Errorf("Cannot fail packet: %v", err)
 		}
 
-		// If this htlc was intercepted, don't handle the forward.
-		return interceptor(intercepted)
-	default:
+		return true
+	}
+
+	if err := s.heldHtlcSet.add(inKey, intercepted); err != nil {
+		log.Errorf("Cannot hold htlc %v: %v", inKey, err)
+
 		return false
 	}
+
+	if s.interceptor != nil {
+		s.sendForward(intercepted)
+	}
+
+	return true
 }
 
 // interceptedForward implements the InterceptedForward interface.
 // It is passed from the switch to external interceptors that are interested
 // in holding forwards and resolve them manually.
 type interceptedForward struct {
-	linkQuit   chan struct{}
-	htlc       *lnwire.UpdateAddHTLC
-	packet     *htlcPacket
-	htlcSwitch *Switch
+	linkQuit       chan struct{}
+	htlc           *lnwire.UpdateAddHTLC
+	packet         *htlcPacket
+	htlcSwitch     *Switch
+	autoFailHeight int32
 }
 
 // Packet returns the intercepted htlc packet.
@@ -129,6 +525,7 @@ func (f *interceptedForward) Packet() InterceptedPacket {
 		IncomingExpiry: f.packet.incomingTimeout,
 		CustomRecords:  f.packet.customRecords,
 		OnionBlob:      f.htlc.OnionBlob,
+		AutoFailHeight: f.autoFailHeight,
 	}
 }
 
@@ -88,6 +88,35 @@ type ChannelUpdateHandler interface {
 	// MayAddOutgoingHtlc returns an error if we may not add an outgoing
 	// htlc to the channel.
 	MayAddOutgoingHtlc() error
+
+	// Quiesce requests to pause all updates of the channel. It blocks
+	// until both parties sent stfu, or the cancel channel is closed, and
+	// returns whether we're the initiator of the quiescence.
+	Quiesce(cancel <-chan struct{}) (bool, error)
+
+	// IsQuiescent returns true if both parties sent stfu, and no updates
+	// may be added to the channel until it resumes normal operation.
+	IsQuiescent() bool
+
+	// IsQuiescenceInitiator returns true if the channel is quiescent and
+	// we're the initiator of the quiescence.
+	IsQuiescenceInitiator() bool
+
+	// ResumeQuiescence ends the quiescence of the channel once the
+	// operation that required it has been signed by both parties. It has
+	// no effect if the channel isn't quiescent, or is quiescent for a
+	// commitment type upgrade.
+	ResumeQuiescence()
+
+	// Unquiesce ends a quiescence that we initiated through Quiesce
+	// without starting an operation that requires it.
+	Unquiesce() error
+
+	// UpgradeCommitType upgrades the commitment type of the channel in
+	// place. It blocks until both parties committed to the new type, or
+	// the cancel channel is closed.
+	UpgradeCommitType(commitType lnwallet.CommitmentType,
+		cancel <-chan struct{}) error
 }
 
 // ChannelLink is an interface which represents the subsystem for managing the
@@ -140,11 +169,12 @@ type ChannelLink interface {
 	// satisfy the current forwarding policy fo the target link. Otherwise,
 	// a LinkError with a valid protocol failure message should be returned
 	// in order to signal to the source of the HTLC, the policy consistency
-	// issue.
+	// issue. The inbound fee is the fee charged by the incoming link and
+	// is added to the outbound fee of the target link.
 	CheckHtlcForward(payHash [32]byte, incomingAmt lnwire.MilliSatoshi,
 		amtToForward lnwire.MilliSatoshi,
 		incomingTimeout, outgoingTimeout uint32,
-		heightNow uint32) *LinkError
+		inboundFee lnwire.Fee, heightNow uint32) *LinkError
 
 	// CheckHtlcTransit should return a nil error if the passed HTLC details
 	// satisfy the current channel policy.  Otherwise, a LinkError with a
@@ -202,22 +232,57 @@ type TowerClient interface {
 	// isTweakless should be true.
 	BackupState(*lnwire.ChannelID, *lnwallet.BreachRetribution,
 		channeldb.ChannelType) error
+
+	// BackupSpliceState initiates a request to back up the variant of a
+	// revoked state that spends the funding output of a pending splice.
+	BackupSpliceState(*lnwire.ChannelID, *lnwallet.BreachRetribution,
+		channeldb.ChannelType) error
 }
 
 // InterceptableHtlcForwarder is the interface to set the interceptor
 // implementation that intercepts htlc forwards.
 type InterceptableHtlcForwarder interface {
-	// SetInterceptor sets a ForwardInterceptor.
+	// SetInterceptor sets a ForwardInterceptor. All forwards that are
+	// currently held are offered to the new interceptor.
 	SetInterceptor(interceptor ForwardInterceptor)
+
+	// Resolve resolves an intercepted packet.
+	Resolve(res *FwdResolution) error
 }
 
 // ForwardInterceptor is a function that is invoked from the switch for every
-// incoming htlc that is intended to be forwarded. It is passed with the
-// InterceptedForward that contains the information about the packet and a way
-// to resolve it manually later in case it is held.
-// The return value indicates if this handler will take control of this forward
-// and resolve it later or let the switch execute its default behavior.
-type ForwardInterceptor func(InterceptedForward) bool
+// incoming htlc that is intended to be forwarded and held by the switch. The
+// switch keeps holding the htlc until it is resolved through the Resolve
+// method of the InterceptableHtlcForwarder, or until it is auto-failed
+// because its incoming expiry is too close.
+type ForwardInterceptor func(InterceptedPacket) error
+
+// FwdAction defines the various resolution types.
+type FwdAction int
+
+const (
+	// FwdActionResume forwards the intercepted packet to the switch.
+	FwdActionResume FwdAction = iota
+
+	// FwdActionSettle settles the intercepted packet with a preimage.
+	FwdActionSettle
+
+	// FwdActionFail fails the intercepted packet back to the sender.
+	FwdActionFail
+)
+
+// FwdResolution defines the action to be taken on an intercepted packet.
+type FwdResolution struct {
+	// Key is the incoming circuit key of the htlc.
+	Key channeldb.CircuitKey
+
+	// Action is the action to take on the intercepted htlc.
+	Action FwdAction
+
+	// Preimage is the preimage that is to be used for settling if Action
+	// is FwdActionSettle.
+	Preimage lntypes.Preimage
+}
 
 // InterceptedPacket contains the relevant information for the interceptor about
 // an htlc.
@@ -252,6 +317,11 @@ type InterceptedPacket struct {
 
 	// OnionBlob is the onion packet for the next hop
 	OnionBlob [lnwire.OnionPacketSize]byte
+
+	// AutoFailHeight is the block height at which this intercept will be
+	// failed back automatically, to prevent the incoming channel from
+	// being force closed.
+	AutoFailHeight int32
 }
 
 // InterceptedForward is passed to the ForwardInterceptor for every forwarded
@@ -56,6 +56,10 @@ const (
 	// which a link should propose to update its commitment fee rate.
 	DefaultMaxLinkFeeUpdateTimeout = 60 * time.Minute
 
+	// DefaultQuiescenceTimeout is the maximum time a channel may stay
+	// quiescent before we disconnect the peer.
+	DefaultQuiescenceTimeout = time.Minute
+
 	// DefaultMaxLinkFeeAllocation is the highest allocation we'll allow
 	// a channel's commitment fee to be of its balance. This only applies to
 	// the initiator of the channel.
@@ -97,6 +101,11 @@ type ForwardingPolicy struct {
 	//    per-hop payload of the incoming HTLC's onion packet.
 	TimeLockDelta uint32
 
+	// InboundFee is the fee that is charged for htlcs that arrive over
+	// this link. It is added to the outbound fee of the link that the htlc
+	// is forwarded over and may be negative to express a discount.
+	InboundFee lnwire.Fee
+
 	// TODO(roasbeef): add fee module inside of switch
 }
 
@@ -231,6 +240,12 @@ type ChannelLinkConfig struct {
 	// remote party to revoke.
 	PendingCommitTicker ticker.Ticker
 
+	// QuiescenceTicker is a ticker that allows the link to determine if
+	// the channel stays quiescent for too long. It runs from the moment
+	// either party requested quiescence until the channel resumes normal
+	// operation.
+	QuiescenceTicker ticker.Ticker
+
 	// BatchSize is the max size of a batch of updates done to the link
 	// before we do a state update.
 	BatchSize uint32
@@ -301,6 +316,13 @@ type localUpdateAddMsg struct {
 	err chan error
 }
 
+// quiesceReq is a local request to quiesce the channel. The link replies with
+// a channel that receives whether we're the initiator of the quiescence once
+// the channel is quiescent.
+type quiesceReq struct {
+	resp chan (<-chan bool)
+}
+
 // channelLink is the service which drives a channel's commitment update
 // state-machine. In the event that an HTLC needs to be propagated to another
 // link, the forward handler from config is used which sends HTLC to the
@@ -311,6 +333,9 @@ type channelLink struct {
 	started       int32
 	reestablished int32
 	shutdown      int32
+	quiescing     int32
+	quiescent     int32
+	initiator     int32
 
 	// failed should be set to true in case a link error happens, making
 	// sure we don't process any more updates.
@@ -365,6 +390,35 @@ type channelLink struct {
 	// sent across.
 	localUpdateAdd chan *localUpdateAddMsg
 
+	// quiesceReqs is a channel to which local requests to quiesce the
+	// channel are sent across.
+	quiesceReqs chan *quiesceReq
+
+	// resumeReqs is a channel to which local requests to resume from
+	// quiescence are sent across.
+	resumeReqs chan struct{}
+
+	// unquiesceReqs is a channel to which explicit local requests to end
+	// a quiescence without an operation are sent across. The outcome is
+	// sent to the passed error channel, which must be buffered.
+	unquiesceReqs chan chan error
+
+	// quiescer tracks the exchange of stfu messages with the remote
+	// party.
+	quiescer *quiescer
+
+	// upgradeReqs is a channel to which local requests to upgrade the
+	// commitment type of the quiescent channel are sent across.
+	upgradeReqs chan *upgradeReq
+
+	// pendingUpgrade is our commitment type upgrade proposal that awaits
+	// the remote party's response, or is being committed to.
+	pendingUpgrade *upgradeReq
+
+	// upgrading is true once the commitment type of the quiescent channel
+	// was upgraded, until both parties committed to the new type.
+	upgrading bool
+
 	// htlcUpdates is a channel that we'll use to update outside
 	// sub-systems with the latest set of active HTLC's on our channel.
 	htlcUpdates chan *contractcourt.ContractUpdate
@@ -420,6 +474,11 @@ func NewChannelLink(cfg ChannelLinkConfig,
 		log:            build.NewPrefixLog(logPrefix, log),
 		quit:           make(chan struct{}),
 		localUpdateAdd: make(chan *localUpdateAddMsg),
+		quiesceReqs:    make(chan *quiesceReq),
+		resumeReqs:     make(chan struct{}),
+		unquiesceReqs:  make(chan chan error),
+		quiescer:       newQuiescer(channel.IsInitiator()),
+		upgradeReqs:    make(chan *upgradeReq),
 	}
 }
 
@@ -570,7 +629,7 @@ func (l *channelLink) WaitForShutdown() {
 func (l *channelLink) EligibleToForward() bool {
 	return l.channel.RemoteNextRevocation() != nil &&
 		l.ShortChanID() != hop.Source &&
-		l.isReestablished()
+		l.isReestablished() && !l.isQuiescing()
 }
 
 // isReestablished returns true if the link has successfully completed the
@@ -586,6 +645,135 @@ func (l *channelLink) markReestablished() {
 	atomic.StoreInt32(&l.reestablished, 1)
 }
 
+// isQuiescing returns true if either party requested to quiesce the channel,
+// in which case no new updates may be added to it.
+func (l *channelLink) isQuiescing() bool {
+	return atomic.LoadInt32(&l.quiescing) == 1
+}
+
+// IsQuiescent returns true if both parties sent stfu, and no updates may be
+// added to the channel until the peers reconnect.
+//
+// NOTE: Part of the ChannelUpdateHandler interface.
+func (l *channelLink) IsQuiescent() bool {
+	return atomic.LoadInt32(&l.quiescent) == 1
+}
+
+// IsQuiescenceInitiator returns true if the channel is quiescent and we're
+// the initiator of the quiescence. Only the initiator may start an operation
+// that requires quiescence, such as a splice.
+//
+// NOTE: Part of the ChannelUpdateHandler interface.
+func (l *channelLink) IsQuiescenceInitiator() bool {
+	return atomic.LoadInt32(&l.initiator) == 1
+}
+
+// ResumeQuiescence ends the quiescence of the channel once the operation that
+// required it, such as a splice, has been signed by both parties.
+//
+// NOTE: Part of the ChannelUpdateHandler interface.
+func (l *channelLink) ResumeQuiescence() {
+	select {
+	case l.resumeReqs <- struct{}{}:
+	case <-l.quit:
+	}
+}
+
+// Unquiesce ends a quiescence that we initiated through Quiesce without
+// starting an operation that requires it. It fails if the channel isn't
+// quiescent, the remote party initiated the quiescence, or a commitment type
+// upgrade is in progress.
+//
+// NOTE: Part of the ChannelUpdateHandler interface.
+func (l *channelLink) Unquiesce() error {
+	errChan := make(chan error, 1)
+
+	select {
+	case l.unquiesceReqs <- errChan:
+	case <-l.quit:
+		return ErrLinkShuttingDown
+	}
+
+	select {
+	case err := <-errChan:
+		return err
+	case <-l.quit:
+		return ErrLinkShuttingDown
+	}
+}
+
+// unquiesce handles an explicit local request to end the quiescence of the
+// channel.
+func (l *channelLink) unquiesce() error {
+	switch {
+	case !l.quiescer.quiescent():
+		return ErrChannelNotQuiescent
+
+	case !l.quiescer.initiator():
+		return ErrNotQuiescenceInitiator
+
+	case l.pendingUpgrade != nil || l.upgrading:
+		return errUpgradeInProgress
+	}
+
+	l.resumeQuiescence()
+
+	return nil
+}
+
+// quiescenceSupported returns true if both parties signal support for
+// quiescence.
+func (l *channelLink) quiescenceSupported() bool {
+	local := l.cfg.Peer.LocalFeatures()
+	remote := l.cfg.Peer.RemoteFeatures()
+
+	return local != nil && local.HasFeature(lnwire.QuiescenceOptional) &&
+		remote != nil && remote.HasFeature(lnwire.QuiescenceOptional)
+}
+
+// updateQuiescence exposes the state of the quiescer, which is owned by the
+// htlcManager, to other goroutines.
+func (l *channelLink) updateQuiescence() {
+	if l.quiescer.active() {
+		atomic.StoreInt32(&l.quiescing, 1)
+		l.cfg.QuiescenceTicker.Resume()
+	}
+
+	if l.quiescer.quiescent() &&
+		atomic.CompareAndSwapInt32(&l.quiescent, 0, 1) {
+
+		initiator := l.quiescer.initiator()
+		if initiator {
+			atomic.StoreInt32(&l.initiator, 1)
+		}
+
+		l.log.Infof("channel is quiescent, initiator=%v", initiator)
+	}
+}
+
+// sendStfu sends our stfu message once quiescence was requested by either
+// party and all of our updates are irrevocably committed.
+func (l *channelLink) sendStfu() error {
+	if !l.quiescer.needStfu() || l.channel.HasPendingUpdates(true) {
+		return nil
+	}
+
+	// The channel is quiescent once we sent our stfu in response to the
+	// remote party's, and the remote party may follow up with a message
+	// that requires quiescence right away. So we'll expose the state
+	// before sending the message.
+	stfu := l.quiescer.sendStfu(l.ChanID())
+	l.updateQuiescence()
+
+	if err := l.cfg.Peer.SendMessage(false, stfu); err != nil {
+		return err
+	}
+
+	l.log.Debugf("sent stfu, initiator=%v", stfu.Initiator)
+
+	return nil
+}
+
 // sampleNetworkFee samples the current fee rate on the network to get into the
 // chain in a timely manner. The returned value is expressed in fee-per-kw, as
 // this is the native rate used when computing the fee for commitment
@@ -910,6 +1098,7 @@ func (l *channelLink) loadAndRemove() error {
 func (l *channelLink) htlcManager() {
 	defer func() {
 		l.cfg.BatchTicker.Stop()
+		l.cfg.QuiescenceTicker.Stop()
 		l.wg.Done()
 		l.log.Infof("exited")
 	}()
@@ -1061,11 +1250,37 @@ func (l *channelLink) htlcManager() {
 			return
 		}
 
+		// If quiescence was requested, we'll send our stfu once all
+		// of our updates are irrevocably committed. Until the peers
+		// reconnect, we then no longer read any packets from the
+		// switch or resolutions of exit hop htlcs, which would add
+		// new updates to the channel.
+		if err := l.sendStfu(); err != nil {
+			l.log.Errorf("unable to send stfu: %v", err)
+		}
+
+		var (
+			downstream = l.downstream
+			hodlChan   = l.hodlQueue.ChanOut()
+		)
+		if l.quiescer.active() {
+			downstream = nil
+			hodlChan = nil
+		}
+
+		// Once both parties committed to the upgraded commitment type,
+		// the channel resumes normal operation.
+		l.checkUpgradeComplete()
+
 		// If the previous event resulted in a non-empty batch, resume
 		// the batch ticker so that it can be cleared. Otherwise pause
 		// the ticker to prevent waking up the htlcManager while the
-		// batch is empty.
-		if l.channel.PendingLocalUpdateCount() > 0 {
+		// batch is empty. The same applies if we still owe the remote
+		// party a commitment of the upgraded type, which is the case
+		// if the connection was lost during the upgrade.
+		upgradeOwed := l.channel.CommitTypeUpgradePending() &&
+			l.channel.OweCommitment(true)
+		if l.channel.PendingLocalUpdateCount() > 0 || upgradeOwed {
 			l.cfg.BatchTicker.Resume()
 		} else {
 			l.cfg.BatchTicker.Pause()
@@ -1083,6 +1298,12 @@ func (l *channelLink) htlcManager() {
 				continue
 			}
 
+			// The fee can't be updated while the channel is
+			// quiescent.
+			if l.quiescer.active() {
+				continue
+			}
+
 			// If we are the initiator, then we'll sample the
 			// current fee rate to get into the chain within 3
 			// blocks.
@@ -1147,16 +1368,58 @@ func (l *channelLink) htlcManager() {
 				"unable to complete dance")
 			return
 
+		// The channel stayed quiescent for too long, so we'll fail the
+		// link, which disconnects the peer. The channel resumes normal
+		// operation once the peer reconnects.
+		case <-l.cfg.QuiescenceTicker.Ticks():
+			l.fail(LinkFailureError{code: ErrQuiescenceTimeout},
+				"channel quiescent for too long")
+			return
+
 		// A message from the switch was just received. This indicates
 		// that the link is an intermediate hop in a multi-hop HTLC
 		// circuit.
-		case pkt := <-l.downstream:
+		case pkt := <-downstream:
 			l.handleDownstreamPkt(pkt)
 
 		// A message containing a locally initiated add was received.
+		// It is rejected if the channel is quiescent.
 		case msg := <-l.localUpdateAdd:
+			if l.quiescer.active() {
+				l.mailBox.FailAdd(msg.pkt)
+				msg.err <- NewDetailedLinkError(
+					lnwire.NewTemporaryChannelFailure(nil),
+					OutgoingFailureDownstreamHtlcAdd,
+				)
+				continue
+			}
+
 			msg.err <- l.handleDownstreamUpdateAdd(msg.pkt)
 
+		// A local request to quiesce the channel was received.
+		case req := <-l.quiesceReqs:
+			req.resp <- l.quiescer.request()
+			l.updateQuiescence()
+
+		// A local request to resume from quiescence was received. A
+		// commitment type upgrade resumes by itself once it's
+		// complete, so the request is ignored while one is pending.
+		case <-l.resumeReqs:
+			if l.quiescer.quiescent() && l.pendingUpgrade == nil &&
+				!l.upgrading {
+
+				l.resumeQuiescence()
+			}
+
+		// An explicit local request to end the quiescence was
+		// received.
+		case errChan := <-l.unquiesceReqs:
+			errChan <- l.unquiesce()
+
+		// A local request to upgrade the commitment type was received.
+		case req := <-l.upgradeReqs:
+			l.proposeUpgrade(req)
+
 		// A message from the connected peer was just received. This
 		// indicates that we have a new incoming HTLC, either directly
 		// for us, or part of a multi-hop HTLC circuit.
@@ -1165,7 +1428,7 @@ func (l *channelLink) htlcManager() {
 
 		// A htlc resolution is received. This means that we now have a
 		// resolution for a previously accepted htlc.
-		case hodlItem := <-l.hodlQueue.ChanOut():
+		case hodlItem := <-hodlChan:
 			htlcResolution := hodlItem.(invoices.HtlcResolution)
 			err := l.processHodlQueue(htlcResolution)
 			if err != nil {
@@ -1271,6 +1534,14 @@ func (l *channelLink) processHtlcResolution(resolution invoices.HtlcResolution,
 func getResolutionFailure(resolution *invoices.HtlcFailResolution,
 	amount lnwire.MilliSatoshi) *LinkError {
 
+	// If the resolution carries its own failure message, we use it as is.
+	// This is the case when the htlc acceptor chose the failure.
+	if resolution.FailureMessage != nil {
+		return NewDetailedLinkError(
+			resolution.FailureMessage, resolution.Outcome,
+		)
+	}
+
 	// If the resolution has been resolved as part of a MPP timeout,
 	// we need to fail the htlc with lnwire.FailMppTimeout.
 	if resolution.Outcome == invoices.ResultMppTimeout {
@@ -1617,6 +1888,20 @@ func (l *channelLink) cleanupSpuriousResponse(pkt *htlcPacket) {
 // updates from the upstream peer. The upstream peer is the peer whom we have a
 // direct channel with, updating our respective commitment chains.
 func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
+	// Once the remote party sent stfu, it may no longer add any updates
+	// to the channel.
+	if l.quiescer.received != nil {
+		switch msg.(type) {
+		case *lnwire.UpdateAddHTLC, *lnwire.UpdateFulfillHTLC,
+			*lnwire.UpdateFailHTLC, *lnwire.UpdateFailMalformedHTLC,
+			*lnwire.UpdateFee:
+
+			l.fail(LinkFailureError{code: ErrInvalidUpdate},
+				"received %T after stfu", msg)
+			return
+		}
+	}
+
 	switch msg := msg.(type) {
 
 	case *lnwire.UpdateAddHTLC:
@@ -1760,7 +2045,7 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 		// We just received a new updates to our local commitment
 		// chain, validate this new commitment, closing the link if
 		// invalid.
-		err = l.channel.ReceiveNewCommitment(msg.CommitSig, msg.HtlcSigs)
+		err = l.channel.ReceiveCommitSig(msg)
 		if err != nil {
 			// If we were unable to reconstruct their proposed
 			// commitment, then we'll examine the type of error. If
@@ -1861,18 +2146,38 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 			}
 
 			chanID := l.ChanID()
+			chanType := state.CommitChanType(
+				false, state.RemoteCommitment.CommitHeight-1,
+			)
 			err = l.cfg.TowerClient.BackupState(
-				&chanID, breachInfo, state.ChanType,
+				&chanID, breachInfo, chanType,
 			)
 			if err != nil {
 				l.fail(LinkFailureError{code: ErrInternalError},
 					"unable to queue breach backup: %v", err)
 				return
 			}
+
+			// While a splice is pending, the revoked state has a
+			// variant that spends the funding output of the splice,
+			// which needs a backup of its own.
+			if err := l.backupSpliceState(); err != nil {
+				l.fail(LinkFailureError{code: ErrInternalError},
+					"unable to queue splice breach "+
+						"backup: %v", err)
+				return
+			}
 		}
 
 		l.processRemoteSettleFails(fwdPkg, settleFails)
-		l.processRemoteAdds(fwdPkg, adds)
+
+		// Processing the adds may add settles or fails to the
+		// channel, so we leave them to be replayed from the
+		// forwarding package once the link restarts if the channel
+		// is quiescent.
+		if !l.quiescer.active() {
+			l.processRemoteAdds(fwdPkg, adds)
+		}
 
 		// If the link failed during processing the adds, we must
 		// return to ensure we won't attempted to update the state
@@ -1903,6 +2208,39 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 				"error receiving fee update: %v", err)
 			return
 		}
+	case *lnwire.Stfu:
+		if !l.quiescenceSupported() {
+			l.log.Warnf("received stfu without quiescence " +
+				"support, ignoring")
+			return
+		}
+
+		// The remote party may only send stfu once all of its updates
+		// are irrevocably committed.
+		if l.channel.HasPendingUpdates(false) {
+			l.fail(LinkFailureError{code: ErrInvalidUpdate},
+				"%v", errStfuPendingUpdates)
+			return
+		}
+
+		if err := l.quiescer.receiveStfu(msg); err != nil {
+			l.fail(LinkFailureError{code: ErrInvalidUpdate},
+				"%v", err)
+			return
+		}
+
+		l.log.Debugf("received stfu, initiator=%v", msg.Initiator)
+		l.updateQuiescence()
+
+	case *lnwire.DynPropose:
+		l.handleDynPropose(msg)
+
+	case *lnwire.DynAck:
+		l.handleDynAck()
+
+	case *lnwire.DynReject:
+		l.handleDynReject()
+
 	case *lnwire.Error:
 		// Error received from remote, MUST fail channel, but should
 		// only print the contents of the error message if all
@@ -1928,6 +2266,36 @@ func (l *channelLink) handleUpstreamMsg(msg lnwire.Message) {
 
 }
 
+// backupSpliceState backs up the variant of the latest revoked state of the
+// remote party that spends the funding output of the pending splice, if the
+// state was signed while the splice was pending.
+func (l *channelLink) backupSpliceState() error {
+	splice := l.channel.PendingSplice()
+	if splice == nil {
+		return nil
+	}
+
+	state := l.channel.State()
+	revokedHeight := state.RemoteCommitment.CommitHeight - 1
+	if revokedHeight < splice.RemoteCommitment.CommitHeight {
+		return nil
+	}
+
+	breachInfo, err := lnwallet.NewSpliceBreachRetribution(
+		state, revokedHeight, 0,
+	)
+	if err != nil {
+		return err
+	}
+
+	chanID := l.ChanID()
+	chanType := state.CommitChanType(false, revokedHeight)
+
+	return l.cfg.TowerClient.BackupSpliceState(
+		&chanID, breachInfo, chanType,
+	)
+}
+
 // ackDownStreamPackets is responsible for removing htlcs from a link's mailbox
 // for packets delivered from server, and cleaning up any circuits closed by
 // signing a previous commitment txn. This method ensures that the circuits are
@@ -2062,6 +2430,7 @@ func (l *channelLink) updateCommitTx() error {
 		ChanID:    l.ChanID(),
 		CommitSig: theirCommitSig,
 		HtlcSigs:  htlcSigs,
+		SpliceSig: l.channel.RemoteSpliceCommitSig(),
 	}
 	l.cfg.Peer.SendMessage(false, commitSig)
 
@@ -2163,6 +2532,10 @@ func (l *channelLink) Bandwidth() lnwire.MilliSatoshi {
 // MayAddOutgoingHtlc indicates whether we may add any more outgoing htlcs to
 // this channel.
 func (l *channelLink) MayAddOutgoingHtlc() error {
+	if l.isQuiescing() {
+		return ErrChannelQuiescent
+	}
+
 	return l.channel.MayAddOutgoingHtlc()
 }
 
@@ -2200,7 +2573,7 @@ func (l *channelLink) UpdateForwardingPolicy(newPolicy ForwardingPolicy) {
 // NOTE: Part of the ChannelLink interface.
 func (l *channelLink) CheckHtlcForward(payHash [32]byte,
 	incomingHtlcAmt, amtToForward lnwire.MilliSatoshi,
-	incomingTimeout, outgoingTimeout uint32,
+	incomingTimeout, outgoingTimeout uint32, inboundFee lnwire.Fee,
 	heightNow uint32) *LinkError {
 
 	l.RLock()
@@ -2218,18 +2591,31 @@ func (l *channelLink) CheckHtlcForward(payHash [32]byte,
 	// Next, using the amount of the incoming HTLC, we'll calculate the
 	// expected fee this incoming HTLC must carry in order to satisfy the
 	// constraints of the outgoing link.
-	expectedFee := ExpectedFee(policy, amtToForward)
+	outFee := ExpectedFee(policy, amtToForward)
+
+	// Then calculate the inbound fee of the incoming link, which is based
+	// on the sum of the outgoing amount and the outgoing fee. Both fee
+	// components are rounded separately, so that senders that compute
+	// them in the same way never end up paying too little.
+	inFee := inboundFee.CalcFee(amtToForward + outFee)
+
+	// The inbound fee may be a discount, but the total fee can never drop
+	// below zero.
+	expectedFee := int64(outFee) + inFee
+	if expectedFee < 0 {
+		expectedFee = 0
+	}
 
 	// If the actual fee is less than our expected fee, then we'll reject
 	// this HTLC as it didn't provide a sufficient amount of fees, or the
 	// values have been tampered with, or the send used incorrect/dated
 	// information to construct the forwarding information for this hop. In
 	// any case, we'll cancel this HTLC.
-	actualFee := incomingHtlcAmt - amtToForward
+	actualFee := int64(incomingHtlcAmt) - int64(amtToForward)
 	if incomingHtlcAmt < amtToForward || actualFee < expectedFee {
 		l.log.Warnf("outgoing htlc(%x) has insufficient fee: "+
 			"expected %v, got %v",
-			payHash[:], int64(expectedFee), int64(actualFee))
+			payHash[:], expectedFee, actualFee)
 
 		// As part of the returned error, we'll send our latest routing
 		// policy so the sending node obtains the most up to date data.
@@ -2434,6 +2820,44 @@ func (l *channelLink) HandleChannelUpdate(message lnwire.Message) {
 	l.mailBox.AddMessage(message)
 }
 
+// Quiesce requests to quiesce the channel. It blocks until both parties sent
+// stfu and returns whether we're the initiator of the quiescence.
+//
+// NOTE: Part of the ChannelUpdateHandler interface.
+func (l *channelLink) Quiesce(cancel <-chan struct{}) (bool, error) {
+	if !l.quiescenceSupported() {
+		return false, ErrQuiescenceNotSupported
+	}
+
+	req := &quiesceReq{
+		resp: make(chan (<-chan bool), 1),
+	}
+
+	select {
+	case l.quiesceReqs <- req:
+	case <-cancel:
+		return false, errors.New("quiescence request canceled")
+	case <-l.quit:
+		return false, ErrLinkShuttingDown
+	}
+
+	var waiter <-chan bool
+	select {
+	case waiter = <-req.resp:
+	case <-l.quit:
+		return false, ErrLinkShuttingDown
+	}
+
+	select {
+	case initiator := <-waiter:
+		return initiator, nil
+	case <-cancel:
+		return false, errors.New("quiescence request canceled")
+	case <-l.quit:
+		return false, ErrLinkShuttingDown
+	}
+}
+
 // updateChannelFee updates the commitment fee-per-kw on this channel by
 // committing to an update_fee message.
 func (l *channelLink) updateChannelFee(feePerKw chainfee.SatPerKWeight) error {
@@ -2582,6 +3006,13 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 	l.log.Tracef("processing %d remote adds for height %d",
 		len(lockedInHtlcs), fwdPkg.Height)
 
+	// Grab the inbound fee of this link, so that the switch can take it
+	// into account when checking the forwarding policy of the outgoing
+	// link.
+	l.RLock()
+	inboundFee := l.cfg.FwrdingPolicy.InboundFee
+	l.RUnlock()
+
 	decodeReqs := make(
 		[]hop.DecodeHopIteratorRequest, 0, len(lockedInHtlcs),
 	)
@@ -2596,10 +3027,26 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 			// which process the Sphinx packet.
 			onionReader := bytes.NewReader(pd.OnionBlob)
 
+			// If the HTLC is forwarded within a blinded route,
+			// our predecessor handed us the blinding point we
+			// need to process the onion. If it can't be parsed,
+			// the onion is processed with our regular node key,
+			// which fails for onions constructed for our blinded
+			// key.
+			blindingPoint, err := lnwire.ExtractBlindingPoint(
+				pd.ExtraData,
+			)
+			if err != nil {
+				l.log.Warnf("unable to parse blinding point "+
+					"of htlc %v: %v", pd.HtlcIndex, err)
+			}
+
 			req := hop.DecodeHopIteratorRequest{
-				OnionReader:  onionReader,
-				RHash:        pd.RHash[:],
-				IncomingCltv: pd.Timeout,
+				OnionReader:    onionReader,
+				RHash:          pd.RHash[:],
+				IncomingCltv:   pd.Timeout,
+				IncomingAmount: pd.Amount,
+				BlindingPoint:  blindingPoint,
 			}
 
 			decodeReqs = append(decodeReqs, req)
@@ -2765,6 +3212,15 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 				// round of processing.
 				chanIterator.EncodeNextHop(buf)
 
+				// The same holds for the blinding point
+				// of an HTLC forwarded within a blinded
+				// route.
+				if fwdInfo.NextBlinding != nil {
+					_ = addMsg.SetBlindingPoint(
+						fwdInfo.NextBlinding,
+					)
+				}
+
 				updatePacket := &htlcPacket{
 					incomingChanID:  l.ShortChanID(),
 					incomingHTLCID:  pd.HtlcIndex,
@@ -2777,6 +3233,7 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 					incomingTimeout: pd.Timeout,
 					outgoingTimeout: fwdInfo.OutgoingCTLV,
 					customRecords:   pld.CustomRecords(),
+					inboundFee:      inboundFee,
 				}
 				switchPackets = append(
 					switchPackets, updatePacket,
@@ -2802,6 +3259,14 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 			// current hop.
 			buf := bytes.NewBuffer(addMsg.OnionBlob[0:0])
 			err := chanIterator.EncodeNextHop(buf)
+
+			// If the HTLC is forwarded within a blinded route,
+			// we'll also hand the next hop its blinding point.
+			if err == nil && fwdInfo.NextBlinding != nil {
+				err = addMsg.SetBlindingPoint(
+					fwdInfo.NextBlinding,
+				)
+			}
 			if err != nil {
 				l.log.Errorf("unable to encode the "+
 					"remaining route %v", err)
@@ -2841,6 +3306,7 @@ func (l *channelLink) processRemoteAdds(fwdPkg *channeldb.FwdPkg,
 					incomingTimeout: pd.Timeout,
 					outgoingTimeout: fwdInfo.OutgoingCTLV,
 					customRecords:   pld.CustomRecords(),
+					inboundFee:      inboundFee,
 				}
 
 				fwdPkg.FwdFilter.Set(idx)
@@ -255,6 +255,38 @@ func (l *linkTestContext) receiveFailAliceToBob() {
 	}
 }
 
+// sendStfuBobToAlice sends a stfu message from Bob to Alice.
+func (l *linkTestContext) sendStfuBobToAlice(initiator bool) {
+	l.t.Helper()
+
+	l.aliceLink.HandleChannelUpdate(
+		lnwire.NewStfu(l.aliceLink.ChanID(), initiator),
+	)
+}
+
+// receiveStfuAliceToBob waits for Alice to send a stfu message to Bob, and
+// asserts the initiator flag it carries.
+func (l *linkTestContext) receiveStfuAliceToBob(initiator bool) {
+	l.t.Helper()
+
+	var msg lnwire.Message
+	select {
+	case msg = <-l.aliceMsgs:
+	case <-time.After(15 * time.Second):
+		l.t.Fatalf("did not receive message")
+	}
+
+	stfu, ok := msg.(*lnwire.Stfu)
+	if !ok {
+		l.t.Fatalf("expected Stfu, got %T", msg)
+	}
+
+	if stfu.Initiator != initiator {
+		l.t.Fatalf("expected initiator=%v, got %v", initiator,
+			stfu.Initiator)
+	}
+}
+
 // assertNoMsgFromAlice asserts that Alice hasn't sent a message. Before
 // calling, make sure that Alice has had the opportunity to send the message.
 func (l *linkTestContext) assertNoMsgFromAlice(timeout time.Duration) {
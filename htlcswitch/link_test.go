@@ -14,10 +14,10 @@ import (
 	"testing"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
 	sphinx "github.com/lightningnetwork/lightning-onion"
@@ -66,29 +66,9 @@ func (c *concurrentTester) Fatalf(format string, args ...interface{}) {
 	c.T.Fatalf(format, args...)
 }
 
-// messageToString is used to produce less spammy log messages in trace mode by
-// setting the 'Curve" parameter to nil. Doing this avoids printing out each of
-// the field elements in the curve parameters for secp256k1.
+// messageToString returns a spewed representation of the message for trace
+// logs.
 func messageToString(msg lnwire.Message) string {
-	switch m := msg.(type) {
-	case *lnwire.RevokeAndAck:
-		m.NextRevocationKey.Curve = nil
-	case *lnwire.AcceptChannel:
-		m.FundingKey.Curve = nil
-		m.RevocationPoint.Curve = nil
-		m.PaymentPoint.Curve = nil
-		m.DelayedPaymentPoint.Curve = nil
-		m.FirstCommitmentPoint.Curve = nil
-	case *lnwire.OpenChannel:
-		m.FundingKey.Curve = nil
-		m.RevocationPoint.Curve = nil
-		m.PaymentPoint.Curve = nil
-		m.DelayedPaymentPoint.Curve = nil
-		m.FirstCommitmentPoint.Curve = nil
-	case *lnwire.FundingLocked:
-		m.NextPerCommitmentPoint.Curve = nil
-	}
-
 	return spew.Sdump(msg)
 }
 
@@ -1856,6 +1836,7 @@ type mockPeer struct {
 	disconnected bool
 	sentMsgs     chan lnwire.Message
 	quit         chan struct{}
+	features     *lnwire.FeatureVector
 }
 
 func (m *mockPeer) QuitSignal() <-chan struct{} {
@@ -1894,10 +1875,10 @@ func (m *mockPeer) Address() net.Addr {
 	return nil
 }
 func (m *mockPeer) LocalFeatures() *lnwire.FeatureVector {
-	return nil
+	return m.features
 }
 func (m *mockPeer) RemoteFeatures() *lnwire.FeatureVector {
-	return nil
+	return m.features
 }
 
 func newSingleLinkTestHarness(chanAmt, chanReserve btcutil.Amount) (
@@ -1973,6 +1954,7 @@ func newSingleLinkTestHarness(chanAmt, chanReserve btcutil.Amount) (
 		BatchTicker:         bticker,
 		FwdPkgGCTicker:      ticker.NewForce(15 * time.Second),
 		PendingCommitTicker: ticker.New(time.Minute),
+		QuiescenceTicker:    ticker.New(time.Minute),
 		// Make the BatchSize and Min/MaxFeeUpdateTimeout large enough
 		// to not trigger commit updates automatically during tests.
 		BatchSize:             10000,
@@ -4477,6 +4459,7 @@ func (h *persistentLinkHarness) restartLink(
 		BatchTicker:         bticker,
 		FwdPkgGCTicker:      ticker.New(5 * time.Second),
 		PendingCommitTicker: ticker.New(time.Minute),
+		QuiescenceTicker:    ticker.New(time.Minute),
 		// Make the BatchSize and Min/MaxFeeUpdateTimeout large enough
 		// to not trigger commit updates automatically during tests.
 		BatchSize:           10000,
@@ -4667,6 +4650,391 @@ func TestChannelLinkNoMoreUpdates(t *testing.T) {
 	}
 }
 
+// TestChannelLinkQuiescence tests that a link drains its pending updates
+// before it sends stfu, and refuses new updates once quiescence was requested.
+func TestChannelLinkQuiescence(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin * 5
+	const chanReserve = btcutil.SatoshiPerBitcoin * 1
+	aliceLink, bobChannel, _, start, cleanUp, _, err :=
+		newSingleLinkTestHarness(chanAmt, chanReserve)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	var (
+		coreLink  = aliceLink.(*channelLink)
+		alicePeer = coreLink.cfg.Peer.(*mockPeer)
+	)
+
+	// Without support for quiescence, it can't be requested.
+	_, err = aliceLink.Quiesce(nil)
+	require.Equal(t, ErrQuiescenceNotSupported, err)
+
+	alicePeer.features = lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(lnwire.QuiescenceOptional),
+		lnwire.Features,
+	)
+	require.NoError(t, start())
+
+	ctx := linkTestContext{
+		t:          t,
+		aliceLink:  aliceLink,
+		aliceMsgs:  alicePeer.sentMsgs,
+		bobChannel: bobChannel,
+	}
+
+	// Bob pays an htlc to Alice, which Alice settles once it is locked
+	// in.
+	htlc := generateHtlc(t, coreLink, 0)
+	ctx.sendHtlcBobToAlice(htlc)
+	ctx.sendCommitSigBobToAlice(1)
+	ctx.receiveRevAndAckAliceToBob()
+	ctx.receiveCommitSigAliceToBob(1)
+	ctx.sendRevAndAckBobToAlice()
+	ctx.receiveSettleAliceToBob()
+
+	// Bob requests quiescence while Alice's settle is still pending.
+	// Alice must not send stfu before the settle is irrevocably
+	// committed, and refuses new updates from now on.
+	ctx.sendStfuBobToAlice(true)
+	ctx.receiveCommitSigAliceToBob(0)
+	ctx.sendRevAndAckBobToAlice()
+	ctx.sendCommitSigBobToAlice(0)
+	ctx.receiveRevAndAckAliceToBob()
+	ctx.receiveStfuAliceToBob(false)
+
+	// Both parties sent stfu, so the channel is quiescent. As Bob
+	// requested the quiescence, Alice isn't the initiator.
+	initiator, err := aliceLink.Quiesce(nil)
+	require.NoError(t, err)
+	require.False(t, initiator)
+	require.True(t, aliceLink.IsQuiescent())
+	require.False(t, aliceLink.IsQuiescenceInitiator())
+	require.False(t, aliceLink.EligibleToForward())
+	require.Equal(t, ErrChannelQuiescent, aliceLink.MayAddOutgoingHtlc())
+
+	// No new updates are sent while the channel is quiescent.
+	ctx.assertNoMsgFromAlice(100 * time.Millisecond)
+}
+
+// newQuiescenceTestContext creates a started single link test harness whose
+// peer supports quiescence. The link's quiescence ticker fires at the given
+// interval while it is active, and its failures are delivered on the returned
+// channel.
+func newQuiescenceTestContext(t *testing.T,
+	timeout time.Duration) (*linkTestContext, chan LinkFailureError,
+	func()) {
+
+	t.Helper()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin * 5
+	const chanReserve = btcutil.SatoshiPerBitcoin * 1
+	aliceLink, bobChannel, _, start, cleanUp, _, err :=
+		newSingleLinkTestHarness(chanAmt, chanReserve)
+	require.NoError(t, err)
+
+	coreLink := aliceLink.(*channelLink)
+	coreLink.cfg.QuiescenceTicker = ticker.NewForce(timeout)
+
+	linkErrs := make(chan LinkFailureError, 1)
+	coreLink.cfg.OnChannelFailure = func(_ lnwire.ChannelID,
+		_ lnwire.ShortChannelID, linkErr LinkFailureError) {
+
+		linkErrs <- linkErr
+	}
+
+	alicePeer := coreLink.cfg.Peer.(*mockPeer)
+	alicePeer.features = lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(lnwire.QuiescenceOptional),
+		lnwire.Features,
+	)
+	if err := start(); err != nil {
+		cleanUp()
+		t.Fatalf("unable to start test harness: %v", err)
+	}
+
+	return &linkTestContext{
+		t:          t,
+		aliceLink:  aliceLink,
+		aliceMsgs:  alicePeer.sentMsgs,
+		bobChannel: bobChannel,
+	}, linkErrs, cleanUp
+}
+
+// TestChannelLinkQuiescenceTimeout tests that a link fails with a quiescence
+// timeout, which disconnects the peer, if the channel stays quiescent for too
+// long.
+func TestChannelLinkQuiescenceTimeout(t *testing.T) {
+	t.Parallel()
+
+	ctx, linkErrs, cleanUp := newQuiescenceTestContext(t, time.Millisecond)
+	defer cleanUp()
+
+	// The timeout isn't armed as long as the channel isn't quiescent.
+	select {
+	case linkErr := <-linkErrs:
+		t.Fatalf("link failed unexpectedly: %v", linkErr)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Bob quiesces the channel and never resumes it.
+	ctx.sendStfuBobToAlice(true)
+	ctx.receiveStfuAliceToBob(false)
+
+	select {
+	case linkErr := <-linkErrs:
+		require.Equal(t, ErrQuiescenceTimeout, linkErr.code)
+		require.True(t, linkErr.ShouldDisconnect())
+		require.False(t, linkErr.ShouldSendToPeer())
+	case <-time.After(5 * time.Second):
+		t.Fatalf("link did not time out")
+	}
+}
+
+// TestChannelLinkUnquiesce tests that the initiator of the quiescence can
+// resume normal operation of the channel.
+func TestChannelLinkUnquiesce(t *testing.T) {
+	t.Parallel()
+
+	ctx, _, cleanUp := newQuiescenceTestContext(t, time.Hour)
+	defer cleanUp()
+
+	require.Equal(t, ErrChannelNotQuiescent, ctx.aliceLink.Unquiesce())
+
+	// Alice quiesces the channel, which makes her the initiator.
+	type quiesceResult struct {
+		initiator bool
+		err       error
+	}
+	results := make(chan quiesceResult, 1)
+	go func() {
+		initiator, err := ctx.aliceLink.Quiesce(nil)
+		results <- quiesceResult{initiator, err}
+	}()
+
+	ctx.receiveStfuAliceToBob(true)
+	ctx.sendStfuBobToAlice(false)
+
+	select {
+	case res := <-results:
+		require.NoError(t, res.err)
+		require.True(t, res.initiator)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("channel not quiesced")
+	}
+
+	// Once resumed, the channel accepts new updates again.
+	require.NoError(t, ctx.aliceLink.Unquiesce())
+	require.False(t, ctx.aliceLink.IsQuiescent())
+	require.NoError(t, ctx.aliceLink.MayAddOutgoingHtlc())
+}
+
+// TestChannelLinkUnquiesceNotInitiator tests that a link refuses to resume
+// normal operation of a channel quiesced by the remote party.
+func TestChannelLinkUnquiesceNotInitiator(t *testing.T) {
+	t.Parallel()
+
+	ctx, _, cleanUp := newQuiescenceTestContext(t, time.Hour)
+	defer cleanUp()
+
+	ctx.sendStfuBobToAlice(true)
+	ctx.receiveStfuAliceToBob(false)
+
+	require.Equal(t, ErrNotQuiescenceInitiator, ctx.aliceLink.Unquiesce())
+	require.True(t, ctx.aliceLink.IsQuiescent())
+}
+
+// TestChannelLinkCommitTypeUpgrade tests that a link accepts the remote
+// party's proposal to upgrade the commitment type of the quiescent channel,
+// and resumes normal operation once both parties committed to the new type.
+func TestChannelLinkCommitTypeUpgrade(t *testing.T) {
+	t.Parallel()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin * 5
+	const chanReserve = btcutil.SatoshiPerBitcoin * 1
+	aliceLink, bobChannel, _, start, cleanUp, _, err :=
+		newSingleLinkTestHarness(chanAmt, chanReserve)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	var (
+		coreLink  = aliceLink.(*channelLink)
+		alicePeer = coreLink.cfg.Peer.(*mockPeer)
+	)
+
+	// Without support for dynamic commitments, the commitment type can't
+	// be upgraded.
+	err = aliceLink.UpgradeCommitType(
+		lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx, nil,
+	)
+	require.Equal(t, ErrDynamicCommitmentsNotSupported, err)
+
+	alicePeer.features = lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(
+			lnwire.QuiescenceOptional,
+			lnwire.DynamicCommitmentsOptional,
+			lnwire.StaticRemoteKeyOptional,
+			lnwire.AnchorsZeroFeeHtlcTxOptional,
+		),
+		lnwire.Features,
+	)
+	require.NoError(t, start())
+
+	ctx := linkTestContext{
+		t:          t,
+		aliceLink:  aliceLink,
+		aliceMsgs:  alicePeer.sentMsgs,
+		bobChannel: bobChannel,
+	}
+
+	// Bob quiesces the channel and proposes to upgrade it to anchor
+	// commitments.
+	ctx.sendStfuBobToAlice(true)
+	ctx.receiveStfuAliceToBob(false)
+
+	var commitType lnwallet.CommitmentType = lnwallet.
+		CommitmentTypeAnchorsZeroFeeHtlcTx
+	chanType := commitType.ChannelType()
+	aliceLink.HandleChannelUpdate(&lnwire.DynPropose{
+		ChanID:      aliceLink.ChanID(),
+		ChannelType: &chanType,
+	})
+
+	var msg lnwire.Message
+	select {
+	case msg = <-alicePeer.sentMsgs:
+	case <-time.After(15 * time.Second):
+		t.Fatalf("did not receive message")
+	}
+	require.IsType(t, &lnwire.DynAck{}, msg)
+
+	// Bob applies the upgrade as well and signs the first commitment of
+	// the new type, which Alice reciprocates.
+	newType, err := lnwallet.UpgradedChanType(
+		bobChannel.State().ChanType, commitType,
+	)
+	require.NoError(t, err)
+	require.NoError(t, bobChannel.UpgradeCommitType(newType))
+	require.True(t, bobChannel.CommitTypeUpgradePending())
+
+	ctx.sendCommitSigBobToAlice(0)
+	ctx.receiveRevAndAckAliceToBob()
+	ctx.receiveCommitSigAliceToBob(0)
+	ctx.sendRevAndAckBobToAlice()
+	require.False(t, bobChannel.CommitTypeUpgradePending())
+
+	// Both parties committed to the new type, so Alice's channel is no
+	// longer quiescent.
+	err = wait.Predicate(func() bool {
+		return !aliceLink.IsQuiescent()
+	}, 5*time.Second)
+	require.NoError(t, err, "channel still quiescent")
+	require.Equal(t, newType, coreLink.channel.State().ChanType)
+	require.NoError(t, aliceLink.MayAddOutgoingHtlc())
+}
+
+// newUpgradeTestContext creates a started single link test harness whose
+// peer supports quiescence and anchor commitment upgrades.
+func newUpgradeTestContext(t *testing.T) (*linkTestContext, func()) {
+	t.Helper()
+
+	const chanAmt = btcutil.SatoshiPerBitcoin * 5
+	const chanReserve = btcutil.SatoshiPerBitcoin * 1
+	aliceLink, bobChannel, _, start, cleanUp, _, err :=
+		newSingleLinkTestHarness(chanAmt, chanReserve)
+	require.NoError(t, err)
+
+	alicePeer := aliceLink.(*channelLink).cfg.Peer.(*mockPeer)
+	alicePeer.features = lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(
+			lnwire.QuiescenceOptional,
+			lnwire.DynamicCommitmentsOptional,
+			lnwire.StaticRemoteKeyOptional,
+			lnwire.AnchorsZeroFeeHtlcTxOptional,
+		),
+		lnwire.Features,
+	)
+	if err := start(); err != nil {
+		cleanUp()
+		t.Fatalf("unable to start test harness: %v", err)
+	}
+
+	return &linkTestContext{
+		t:          t,
+		aliceLink:  aliceLink,
+		aliceMsgs:  alicePeer.sentMsgs,
+		bobChannel: bobChannel,
+	}, cleanUp
+}
+
+// TestChannelLinkCommitTypeUpgradeNotInitiator tests that a link resumes
+// normal operation if the commitment type upgrade can't be proposed, because
+// the remote party initiated the quiescence.
+func TestChannelLinkCommitTypeUpgradeNotInitiator(t *testing.T) {
+	t.Parallel()
+
+	ctx, cleanUp := newUpgradeTestContext(t)
+	defer cleanUp()
+
+	// Bob quiesces the channel before Alice requests the upgrade, so
+	// Bob is the initiator of the quiescence.
+	ctx.sendStfuBobToAlice(true)
+	ctx.receiveStfuAliceToBob(false)
+
+	err := ctx.aliceLink.UpgradeCommitType(
+		lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx, nil,
+	)
+	require.Equal(t, ErrNotQuiescenceInitiator, err)
+
+	// Alice must not propose the upgrade and resumes normal operation.
+	ctx.assertNoMsgFromAlice(100 * time.Millisecond)
+	err = wait.Predicate(func() bool {
+		return !ctx.aliceLink.IsQuiescent()
+	}, 5*time.Second)
+	require.NoError(t, err, "channel still quiescent")
+	require.NoError(t, ctx.aliceLink.MayAddOutgoingHtlc())
+}
+
+// TestChannelLinkCommitTypeUpgradeCanceled tests that a link resumes normal
+// operation if the commitment type upgrade is canceled before it was
+// proposed.
+func TestChannelLinkCommitTypeUpgradeCanceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cleanUp := newUpgradeTestContext(t)
+	defer cleanUp()
+
+	cancel := make(chan struct{})
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- ctx.aliceLink.UpgradeCommitType(
+			lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx, cancel,
+		)
+	}()
+
+	// Alice initiates the quiescence. The request is canceled before Bob
+	// replies with his stfu.
+	ctx.receiveStfuAliceToBob(true)
+	close(cancel)
+	ctx.sendStfuBobToAlice(false)
+
+	select {
+	case err := <-errChan:
+		require.EqualError(t, err, "upgrade request canceled")
+	case <-time.After(5 * time.Second):
+		t.Fatalf("upgrade request not canceled")
+	}
+
+	// Alice must not propose the upgrade and resumes normal operation.
+	ctx.assertNoMsgFromAlice(100 * time.Millisecond)
+	err := wait.Predicate(func() bool {
+		return !ctx.aliceLink.IsQuiescent()
+	}, 5*time.Second)
+	require.NoError(t, err, "channel still quiescent")
+	require.NoError(t, ctx.aliceLink.MayAddOutgoingHtlc())
+}
+
 // checkHasPreimages inspects Alice's preimage cache, and asserts whether the
 // preimages for the provided HTLCs are known and unknown, and that all of them
 // match the expected status of expOk.
@@ -5750,7 +6118,7 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	t.Run("satisfied", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 1500, 1000,
-			200, 150, 0)
+			200, 150, lnwire.Fee{}, 0)
 		if result != nil {
 			t.Fatalf("expected policy to be satisfied")
 		}
@@ -5758,7 +6126,7 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	t.Run("below minhtlc", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 100, 50,
-			200, 150, 0)
+			200, 150, lnwire.Fee{}, 0)
 		if _, ok := result.WireMessage().(*lnwire.FailAmountBelowMinimum); !ok {
 			t.Fatalf("expected FailAmountBelowMinimum failure code")
 		}
@@ -5766,7 +6134,7 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	t.Run("above maxhtlc", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 1500, 1200,
-			200, 150, 0)
+			200, 150, lnwire.Fee{}, 0)
 		if _, ok := result.WireMessage().(*lnwire.FailTemporaryChannelFailure); !ok {
 			t.Fatalf("expected FailTemporaryChannelFailure failure code")
 		}
@@ -5774,7 +6142,64 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	t.Run("insufficient fee", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 1005, 1000,
-			200, 150, 0)
+			200, 150, lnwire.Fee{}, 0)
+		if _, ok := result.WireMessage().(*lnwire.FailFeeInsufficient); !ok {
+			t.Fatalf("expected FailFeeInsufficient failure code")
+		}
+	})
+
+	t.Run("inbound fee satisfied", func(t *testing.T) {
+		result := link.CheckHtlcForward(hash, 1000+10+1, 1000,
+			200, 150, lnwire.Fee{BaseFee: 1}, 0)
+		if result != nil {
+			t.Fatalf("expected policy to be satisfied")
+		}
+	})
+
+	t.Run("inbound fee insufficient", func(t *testing.T) {
+		result := link.CheckHtlcForward(hash, 1000+10+1, 1000,
+			200, 150, lnwire.Fee{BaseFee: 2}, 0)
+		if _, ok := result.WireMessage().(*lnwire.FailFeeInsufficient); !ok {
+			t.Fatalf("expected FailFeeInsufficient failure code")
+		}
+	})
+
+	t.Run("inbound fee rate surcharge", func(t *testing.T) {
+		// The proportional surcharge of 1% is calculated over the
+		// amount to forward plus the outbound fee.
+		surcharge := lnwire.Fee{FeeRate: 10000}
+		result := link.CheckHtlcForward(hash, 1000+10+10, 1000,
+			200, 150, surcharge, 0)
+		if result != nil {
+			t.Fatalf("expected policy to be satisfied")
+		}
+
+		result = link.CheckHtlcForward(hash, 1000+10+9, 1000,
+			200, 150, surcharge, 0)
+		if _, ok := result.WireMessage().(*lnwire.FailFeeInsufficient); !ok {
+			t.Fatalf("expected FailFeeInsufficient failure code")
+		}
+	})
+
+	t.Run("inbound fee discount", func(t *testing.T) {
+		result := link.CheckHtlcForward(hash, 1000+10-5, 1000,
+			200, 150, lnwire.Fee{BaseFee: -5}, 0)
+		if result != nil {
+			t.Fatalf("expected policy to be satisfied")
+		}
+	})
+
+	t.Run("inbound fee discount floored", func(t *testing.T) {
+		// A discount that exceeds the outbound fee results in a zero
+		// total fee, never in a negative one.
+		result := link.CheckHtlcForward(hash, 1000, 1000,
+			200, 150, lnwire.Fee{BaseFee: -100}, 0)
+		if result != nil {
+			t.Fatalf("expected policy to be satisfied")
+		}
+
+		result = link.CheckHtlcForward(hash, 999, 1000,
+			200, 150, lnwire.Fee{BaseFee: -100}, 0)
 		if _, ok := result.WireMessage().(*lnwire.FailFeeInsufficient); !ok {
 			t.Fatalf("expected FailFeeInsufficient failure code")
 		}
@@ -5782,7 +6207,7 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	t.Run("expiry too soon", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 1500, 1000,
-			200, 150, 190)
+			200, 150, lnwire.Fee{}, 190)
 		if _, ok := result.WireMessage().(*lnwire.FailExpiryTooSoon); !ok {
 			t.Fatalf("expected FailExpiryTooSoon failure code")
 		}
@@ -5790,7 +6215,7 @@ func TestCheckHtlcForward(t *testing.T) {
 
 	t.Run("incorrect cltv expiry", func(t *testing.T) {
 		result := link.CheckHtlcForward(hash, 1500, 1000,
-			200, 190, 0)
+			200, 190, lnwire.Fee{}, 0)
 		if _, ok := result.WireMessage().(*lnwire.FailIncorrectCltvExpiry); !ok {
 			t.Fatalf("expected FailIncorrectCltvExpiry failure code")
 		}
@@ -5800,7 +6225,7 @@ func TestCheckHtlcForward(t *testing.T) {
 	t.Run("cltv expiry too far in the future", func(t *testing.T) {
 		// Check that expiry isn't too far in the future.
 		result := link.CheckHtlcForward(hash, 1500, 1000,
-			10200, 10100, 0)
+			10200, 10100, lnwire.Fee{}, 0)
 		if _, ok := result.WireMessage().(*lnwire.FailExpiryTooFar); !ok {
 			t.Fatalf("expected FailExpiryTooFar failure code")
 		}
@@ -43,6 +43,10 @@ const (
 	// remote party to force close the channel out on chain now as a
 	// result.
 	ErrRecoveryError
+
+	// ErrQuiescenceTimeout indicates that the channel stayed quiescent
+	// for too long.
+	ErrQuiescenceTimeout
 )
 
 // LinkFailureError encapsulates an error that will make us fail the current
@@ -91,6 +95,8 @@ func (e LinkFailureError) Error() string {
 		return "invalid revocation"
 	case ErrRecoveryError:
 		return "unable to resume channel, recovery required"
+	case ErrQuiescenceTimeout:
+		return "quiescence timeout"
 	default:
 		return "unknown error"
 	}
@@ -120,3 +126,18 @@ func (e LinkFailureError) ShouldSendToPeer() bool {
 		return false
 	}
 }
+
+// ShouldDisconnect indicates whether we should disconnect the peer if the
+// link fails with this LinkFailureError.
+func (e LinkFailureError) ShouldDisconnect() bool {
+	switch e.code {
+
+	// A channel that stayed quiescent for too long only resumes normal
+	// operation once the peers reconnect.
+	case ErrQuiescenceTimeout:
+		return true
+
+	default:
+		return false
+	}
+}
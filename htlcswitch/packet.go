@@ -43,6 +43,11 @@ type htlcPacket struct {
 	// amount is the value of the HTLC that is being created or modified.
 	amount lnwire.MilliSatoshi
 
+	// inboundFee is the inbound fee policy of the incoming link. It is
+	// used to check that a forwarded htlc pays the total fee that is
+	// charged for the pair of incoming and outgoing links.
+	inboundFee lnwire.Fee
+
 	// htlc lnwire message type of which depends on switch request type.
 	htlc lnwire.Message
 
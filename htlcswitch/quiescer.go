@@ -0,0 +1,148 @@
+package htlcswitch
+
+import (
+	"errors"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrQuiescenceNotSupported is returned when quiescence is requested
+	// for a channel with a peer that doesn't support it.
+	ErrQuiescenceNotSupported = errors.New("peer doesn't support " +
+		"quiescence")
+
+	// ErrChannelQuiescent is returned when an update is offered to a link
+	// whose channel is quiescent, or about to become quiescent.
+	ErrChannelQuiescent = errors.New("channel is quiescent")
+
+	// ErrChannelNotQuiescent is returned when the quiescence of a channel
+	// is to be ended, but the channel isn't quiescent.
+	ErrChannelNotQuiescent = errors.New("channel isn't quiescent")
+
+	// errDuplicateStfu is returned when the remote party sends more than
+	// one stfu message.
+	errDuplicateStfu = errors.New("received duplicate stfu")
+
+	// errStfuPendingUpdates is returned when the remote party sends stfu
+	// while some of its updates aren't irrevocably committed yet.
+	errStfuPendingUpdates = errors.New("received stfu with pending " +
+		"updates")
+)
+
+// quiescer tracks the exchange of stfu messages that pauses all updates of a
+// channel. Once either party sent stfu, no new updates are added to the
+// channel. Pending updates are still committed to, until both parties sent
+// stfu and the channel is quiescent. The channel stays quiescent until the
+// operation that required it completes, it is resumed explicitly, or the
+// peers reconnect.
+//
+// NOTE: The quiescer is not safe for concurrent use, it is owned by the
+// htlcManager of the link.
+type quiescer struct {
+	// isFunder is true if we funded the channel. If both parties
+	// requested quiescence, the funder becomes the initiator.
+	isFunder bool
+
+	// requested is true if we requested quiescence ourselves.
+	requested bool
+
+	// sent is true once we sent our stfu message.
+	sent bool
+
+	// received is the stfu message of the remote party, if it sent one.
+	received *lnwire.Stfu
+
+	// waiters are notified once the channel is quiescent, with whether
+	// we're the initiator of the quiescence.
+	waiters []chan bool
+}
+
+// newQuiescer creates a new quiescer for a channel.
+func newQuiescer(isFunder bool) *quiescer {
+	return &quiescer{
+		isFunder: isFunder,
+	}
+}
+
+// active returns true if quiescence was requested by either party. No new
+// updates may be added to the channel from then on.
+func (q *quiescer) active() bool {
+	return q.requested || q.received != nil
+}
+
+// quiescent returns true once both parties sent stfu.
+func (q *quiescer) quiescent() bool {
+	return q.sent && q.received != nil
+}
+
+// needStfu returns true if quiescence was requested and we haven't sent our
+// stfu yet.
+func (q *quiescer) needStfu() bool {
+	return q.active() && !q.sent
+}
+
+// initiator returns true if we're the initiator of the quiescence. If both
+// parties sent stfu as initiator, the funder of the channel wins.
+func (q *quiescer) initiator() bool {
+	switch {
+	case !q.requested:
+		return false
+
+	case q.received != nil && q.received.Initiator:
+		return q.isFunder
+
+	default:
+		return true
+	}
+}
+
+// request registers a local request for quiescence. The returned channel
+// receives whether we're the initiator once the channel is quiescent.
+func (q *quiescer) request() <-chan bool {
+	waiter := make(chan bool, 1)
+	q.waiters = append(q.waiters, waiter)
+
+	// If we didn't send our stfu yet, we'll signal that we're the
+	// initiator.
+	if !q.sent {
+		q.requested = true
+	}
+
+	q.notifyWaiters()
+
+	return waiter
+}
+
+// sendStfu creates the stfu message we send to the remote party.
+func (q *quiescer) sendStfu(chanID lnwire.ChannelID) *lnwire.Stfu {
+	q.sent = true
+	q.notifyWaiters()
+
+	return lnwire.NewStfu(chanID, q.requested)
+}
+
+// receiveStfu processes the stfu message of the remote party.
+func (q *quiescer) receiveStfu(msg *lnwire.Stfu) error {
+	if q.received != nil {
+		return errDuplicateStfu
+	}
+
+	q.received = msg
+	q.notifyWaiters()
+
+	return nil
+}
+
+// notifyWaiters notifies all waiters if the channel is quiescent.
+func (q *quiescer) notifyWaiters() {
+	if !q.quiescent() {
+		return
+	}
+
+	initiator := q.initiator()
+	for _, waiter := range q.waiters {
+		waiter <- initiator
+	}
+	q.waiters = nil
+}
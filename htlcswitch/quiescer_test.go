@@ -0,0 +1,75 @@
+package htlcswitch
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// TestQuiescerInitiator tests that the initiator of the quiescence is
+// determined correctly, including the tie-break if both parties requested
+// it.
+func TestQuiescerInitiator(t *testing.T) {
+	t.Parallel()
+
+	var chanID lnwire.ChannelID
+
+	tests := []struct {
+		name            string
+		isFunder        bool
+		requested       bool
+		remoteInitiator bool
+		initiator       bool
+	}{{
+		name:      "local request",
+		requested: true,
+		initiator: true,
+	}, {
+		name:            "remote request",
+		remoteInitiator: true,
+	}, {
+		name:            "both requested, funder",
+		isFunder:        true,
+		requested:       true,
+		remoteInitiator: true,
+		initiator:       true,
+	}, {
+		name:            "both requested, not funder",
+		requested:       true,
+		remoteInitiator: true,
+	}}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			q := newQuiescer(test.isFunder)
+			require.False(t, q.active())
+
+			var waiter <-chan bool
+			if test.requested {
+				waiter = q.request()
+				require.True(t, q.needStfu())
+			}
+
+			stfu := lnwire.NewStfu(chanID, test.remoteInitiator)
+			require.NoError(t, q.receiveStfu(stfu))
+			require.Equal(t, errDuplicateStfu, q.receiveStfu(stfu))
+			require.True(t, q.active())
+			require.False(t, q.quiescent())
+
+			sent := q.sendStfu(chanID)
+			require.Equal(t, test.requested, sent.Initiator)
+			require.True(t, q.quiescent())
+			require.Equal(t, test.initiator, q.initiator())
+
+			// Requests are answered right away once the channel
+			// is quiescent.
+			if waiter == nil {
+				waiter = q.request()
+			}
+			require.Equal(t, test.initiator, <-waiter)
+		})
+	}
+}
@@ -9,8 +9,8 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -92,6 +92,11 @@ const (
 	// CloseBreach indicates that a channel breach has been detected, and
 	// the link should immediately be marked as unavailable.
 	CloseBreach
+
+	// CloseBumpFee indicates that the replaceable closing transaction of a
+	// channel that is being closed cooperatively should be replaced by
+	// one paying a higher fee.
+	CloseBumpFee
 )
 
 // ChanClose represents a request which close a particular channel specified by
@@ -105,9 +110,10 @@ type ChanClose struct {
 	ChanPoint *wire.OutPoint
 
 	// TargetFeePerKw is the ideal fee that was specified by the caller.
-	// This value is only utilized if the closure type is CloseRegular.
-	// This will be the starting offered fee when the fee negotiation
-	// process for the cooperative closure transaction kicks off.
+	// This value is only utilized if the closure type is CloseRegular or
+	// CloseBumpFee. This will be the starting offered fee when the fee
+	// negotiation process for the cooperative closure transaction kicks
+	// off, or the fee rate of the replacement closing transaction.
 	TargetFeePerKw chainfee.SatPerKWeight
 
 	// DeliveryScript is an optional delivery script to pay funds out to.
@@ -156,6 +162,12 @@ type Config struct {
 	// error messages.
 	FetchLastChannelUpdate func(lnwire.ShortChannelID) (*lnwire.ChannelUpdate, error)
 
+	// GetAliases returns the set of aliases, including a confirmed short
+	// channel ID for zero-conf channels, that map to the given base short
+	// channel ID. This is used to forward HTLCs that use an alias as their
+	// outgoing channel. If nil, no aliases are used.
+	GetAliases func(base lnwire.ShortChannelID) []lnwire.ShortChannelID
+
 	// Notifier is an instance of a chain notifier that we'll use to signal
 	// the switch when a new block has arrived.
 	Notifier chainntnfs.ChainNotifier
@@ -255,6 +267,10 @@ type Switch struct {
 	// ChannelLink
 	forwardingIndex map[lnwire.ShortChannelID]ChannelLink
 
+	// baseIndex maps the aliases of a channel to the base short channel ID
+	// the channel's link is stored under in the forwardingIndex.
+	baseIndex map[lnwire.ShortChannelID]lnwire.ShortChannelID
+
 	// interfaceIndex maps the compressed public key of a peer to all the
 	// channels that the switch maintains with that peer.
 	interfaceIndex map[[33]byte]map[lnwire.ChannelID]ChannelLink
@@ -307,6 +323,7 @@ func New(cfg Config, currentHeight uint32) (*Switch, error) {
 		circuits:          circuitMap,
 		linkIndex:         make(map[lnwire.ChannelID]ChannelLink),
 		forwardingIndex:   make(map[lnwire.ShortChannelID]ChannelLink),
+		baseIndex:         make(map[lnwire.ShortChannelID]lnwire.ShortChannelID),
 		interfaceIndex:    make(map[[33]byte]map[lnwire.ChannelID]ChannelLink),
 		pendingLinkIndex:  make(map[lnwire.ChannelID]ChannelLink),
 		networkResults:    newNetworkResultStore(cfg.DB),
@@ -1020,6 +1037,20 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 		interfaceLinks, _ := s.getLinks(targetPeerKey)
 		s.indexMtx.RUnlock()
 
+		// If the outgoing channel was referred to by one of its
+		// aliases, we'll use the base short channel ID of the target
+		// link from here on.
+		targetChanID := targetLink.ShortChanID()
+		if targetChanID != packet.outgoingChanID {
+			linkErr := checkCircularForward(
+				packet.incomingChanID, targetChanID,
+				s.cfg.AllowCircularRoute, htlc.PaymentHash,
+			)
+			if linkErr != nil {
+				return s.failAddPacket(packet, linkErr)
+			}
+		}
+
 		// We'll keep track of any HTLC failures during the link
 		// selection process. This way we can return the error for
 		// precise link that the sender selected, while optimistically
@@ -1047,7 +1078,8 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 				failure = link.CheckHtlcForward(
 					htlc.PaymentHash, packet.incomingAmount,
 					packet.amount, packet.incomingTimeout,
-					packet.outgoingTimeout, currentHeight,
+					packet.outgoingTimeout,
+					packet.inboundFee, currentHeight,
 				)
 			}
 
@@ -1069,7 +1101,7 @@ func (s *Switch) handlePacketForward(packet *htlcPacket) error {
 			// At this point, some or all of the links rejected the
 			// HTLC so we couldn't forward it. So we'll try to look
 			// up the error that came from the source.
-			linkErr, ok := linkErrs[packet.outgoingChanID]
+			linkErr, ok := linkErrs[targetChanID]
 			if !ok {
 				// If we can't find the error of the source,
 				// then we'll return an unknown next peer,
@@ -2020,6 +2052,14 @@ func (s *Switch) addLiveLink(link ChannelLink) {
 	s.linkIndex[link.ChanID()] = link
 	s.forwardingIndex[link.ShortChanID()] = link
 
+	// If the channel has any aliases, we'll index them as well so HTLCs
+	// can be forwarded using any of them.
+	if s.cfg.GetAliases != nil {
+		for _, alias := range s.cfg.GetAliases(link.ShortChanID()) {
+			s.baseIndex[alias] = link.ShortChanID()
+		}
+	}
+
 	// Next we'll add the link to the interface index so we can
 	// quickly look up all the channels for a particular node.
 	peerPub := link.Peer().PubKey()
@@ -2060,6 +2100,18 @@ func (s *Switch) getLink(chanID lnwire.ChannelID) (ChannelLink, error) {
 // NOTE: This MUST be called with the indexMtx held.
 func (s *Switch) getLinkByShortID(chanID lnwire.ShortChannelID) (ChannelLink, error) {
 	link, ok := s.forwardingIndex[chanID]
+	if ok {
+		return link, nil
+	}
+
+	// The short channel ID may be an alias of the channel, in which case
+	// we'll look up the link using its base short channel ID.
+	base, ok := s.baseIndex[chanID]
+	if !ok {
+		return nil, ErrChannelLinkNotFound
+	}
+
+	link, ok = s.forwardingIndex[base]
 	if !ok {
 		return nil, ErrChannelLinkNotFound
 	}
@@ -2067,6 +2119,15 @@ func (s *Switch) getLinkByShortID(chanID lnwire.ShortChannelID) (ChannelLink, er
 	return link, nil
 }
 
+// AddAliasForLink adds an alias for the channel with the given base short
+// channel ID, allowing HTLCs to be forwarded over the channel using the alias.
+func (s *Switch) AddAliasForLink(base, alias lnwire.ShortChannelID) {
+	s.indexMtx.Lock()
+	defer s.indexMtx.Unlock()
+
+	s.baseIndex[alias] = base
+}
+
 // HasActiveLink returns true if the given channel ID has a link in the link
 // index AND the link is eligible to forward.
 func (s *Switch) HasActiveLink(chanID lnwire.ChannelID) bool {
@@ -2109,6 +2170,13 @@ func (s *Switch) removeLink(chanID lnwire.ChannelID) ChannelLink {
 	delete(s.linkIndex, link.ChanID())
 	delete(s.forwardingIndex, link.ShortChanID())
 
+	// Remove any aliases that map to the link's short channel ID.
+	for alias, base := range s.baseIndex {
+		if base == link.ShortChanID() {
+			delete(s.baseIndex, alias)
+		}
+	}
+
 	// If the link has been added to the peer index, then we'll move to
 	// delete the entry within the index.
 	peerPub := link.Peer().PubKey()
@@ -10,13 +10,16 @@ import (
 	"testing"
 	"time"
 
-	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
+	"github.com/lightningnetwork/lnd/lntest/mock"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/ticker"
+	"github.com/stretchr/testify/require"
 )
 
 var zeroCircuit = channeldb.CircuitKey{}
@@ -397,6 +400,110 @@ func TestSwitchForward(t *testing.T) {
 	}
 }
 
+// TestSwitchForwardAlias checks that the switch forwards HTLCs that use one
+// of the aliases of the outgoing channel.
+func TestSwitchForwardAlias(t *testing.T) {
+	t.Parallel()
+
+	alicePeer, err := newMockServer(
+		t, "alice", testStartingHeight, nil, testDefaultDelta,
+	)
+	if err != nil {
+		t.Fatalf("unable to create alice server: %v", err)
+	}
+	bobPeer, err := newMockServer(
+		t, "bob", testStartingHeight, nil, testDefaultDelta,
+	)
+	if err != nil {
+		t.Fatalf("unable to create bob server: %v", err)
+	}
+
+	s, err := initSwitchWithDB(testStartingHeight, nil)
+	if err != nil {
+		t.Fatalf("unable to init switch: %v", err)
+	}
+
+	chanID1, chanID2, aliceChanID, bobChanID := genIDs()
+	bobAlias := lnwire.NewShortChanIDFromInt(16_000_000 << 40)
+	s.cfg.GetAliases = func(
+		base lnwire.ShortChannelID) []lnwire.ShortChannelID {
+
+		if base != bobChanID {
+			return nil
+		}
+
+		return []lnwire.ShortChannelID{bobAlias}
+	}
+
+	if err := s.Start(); err != nil {
+		t.Fatalf("unable to start switch: %v", err)
+	}
+	defer s.Stop()
+
+	aliceChannelLink := newMockChannelLink(
+		s, chanID1, aliceChanID, alicePeer, true,
+	)
+	bobChannelLink := newMockChannelLink(
+		s, chanID2, bobChanID, bobPeer, true,
+	)
+	if err := s.AddLink(aliceChannelLink); err != nil {
+		t.Fatalf("unable to add alice link: %v", err)
+	}
+	if err := s.AddLink(bobChannelLink); err != nil {
+		t.Fatalf("unable to add bob link: %v", err)
+	}
+
+	// An alias added after the link was created can be used as well.
+	bobAlias2 := lnwire.NewShortChanIDFromInt(16_000_001 << 40)
+	s.AddAliasForLink(bobChanID, bobAlias2)
+
+	for i, alias := range []lnwire.ShortChannelID{bobAlias, bobAlias2} {
+		preimage, err := genPreimage()
+		if err != nil {
+			t.Fatalf("unable to generate preimage: %v", err)
+		}
+		rhash := sha256.Sum256(preimage[:])
+		packet := &htlcPacket{
+			incomingChanID: aliceChannelLink.ShortChanID(),
+			incomingHTLCID: uint64(i),
+			outgoingChanID: alias,
+			obfuscator:     NewMockObfuscator(),
+			htlc: &lnwire.UpdateAddHTLC{
+				PaymentHash: rhash,
+				Amount:      1,
+			},
+		}
+
+		if err := s.ForwardPackets(nil, packet); err != nil {
+			t.Fatal(err)
+		}
+
+		// The packet should be delivered to bob's link using its base
+		// short channel ID.
+		select {
+		case pkt := <-bobChannelLink.packets:
+			if pkt.outgoingChanID != bobChanID {
+				t.Fatalf("expected outgoing chan %v, got %v",
+					bobChanID, pkt.outgoingChanID)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("request was not propagated to destination")
+		}
+	}
+
+	// Once bob's link is removed, its aliases should no longer be known.
+	s.RemoveLink(chanID2)
+
+	s.indexMtx.RLock()
+	defer s.indexMtx.RUnlock()
+
+	for _, alias := range []lnwire.ShortChannelID{bobAlias, bobAlias2} {
+		if _, err := s.getLinkByShortID(alias); err == nil {
+			t.Fatalf("expected alias %v to be removed", alias)
+		}
+	}
+}
+
 func TestSwitchForwardFailAfterFullAdd(t *testing.T) {
 	t.Parallel()
 
@@ -3137,25 +3244,38 @@ func getThreeHopEvents(channels *clusterChannels, htlcID uint64,
 }
 
 type mockForwardInterceptor struct {
-	intercepted InterceptedForward
+	t *testing.T
+
+	interceptedChan chan InterceptedPacket
 }
 
-func (m *mockForwardInterceptor) InterceptForwardHtlc(intercepted InterceptedForward) bool {
+func (m *mockForwardInterceptor) InterceptForwardHtlc(
+	intercepted InterceptedPacket) error {
 
-	m.intercepted = intercepted
-	return true
-}
+	m.interceptedChan <- intercepted
 
-func (m *mockForwardInterceptor) settle(preimage lntypes.Preimage) error {
-	return m.intercepted.Settle(preimage)
+	return nil
 }
 
-func (m *mockForwardInterceptor) fail() error {
-	return m.intercepted.Fail()
+func (m *mockForwardInterceptor) getIntercepted() InterceptedPacket {
+	select {
+	case p := <-m.interceptedChan:
+		return p
+
+	case <-time.After(time.Second):
+		require.Fail(m.t, "timeout")
+
+		return InterceptedPacket{}
+	}
 }
 
-func (m *mockForwardInterceptor) resume() error {
-	return m.intercepted.Resume()
+func (m *mockForwardInterceptor) assertNotIntercepted() {
+	select {
+	case p := <-m.interceptedChan:
+		require.Fail(m.t, "unexpected intercept", p.IncomingCircuit)
+
+	case <-time.After(100 * time.Millisecond):
+	}
 }
 
 func assertNumCircuits(t *testing.T, s *Switch, pending, opened int) {
@@ -3246,32 +3366,60 @@ func TestSwitchHoldForward(t *testing.T) {
 	preimage := [sha256.Size]byte{1}
 	rhash := sha256.Sum256(preimage[:])
 	ogPacket := &htlcPacket{
-		incomingChanID: aliceChannelLink.ShortChanID(),
-		incomingHTLCID: 0,
-		outgoingChanID: bobChannelLink.ShortChanID(),
-		obfuscator:     NewMockObfuscator(),
+		incomingChanID:  aliceChannelLink.ShortChanID(),
+		incomingHTLCID:  0,
+		outgoingChanID:  bobChannelLink.ShortChanID(),
+		obfuscator:      NewMockObfuscator(),
+		incomingTimeout: testStartingHeight + 50,
 		htlc: &lnwire.UpdateAddHTLC{
 			PaymentHash: rhash,
 			Amount:      1,
 		},
 	}
 
-	forwardInterceptor := &mockForwardInterceptor{}
-	switchForwardInterceptor := NewInterceptableSwitch(s)
-	switchForwardInterceptor.SetInterceptor(forwardInterceptor.InterceptForwardHtlc)
+	forwardInterceptor := &mockForwardInterceptor{
+		t:               t,
+		interceptedChan: make(chan InterceptedPacket, 1),
+	}
+	notifier := &mock.ChainNotifier{
+		EpochChan: make(chan *chainntnfs.BlockEpoch, 1),
+	}
+	notifier.EpochChan <- &chainntnfs.BlockEpoch{Height: testStartingHeight}
+
+	switchForwardInterceptor := NewInterceptableSwitch(
+		&InterceptableSwitchConfig{
+			Switch:          s,
+			Notifier:        notifier,
+			CltvRejectDelta: 10,
+		},
+	)
+	require.NoError(t, switchForwardInterceptor.Start())
+	defer func() {
+		require.NoError(t, switchForwardInterceptor.Stop())
+	}()
+
+	switchForwardInterceptor.SetInterceptor(
+		forwardInterceptor.InterceptForwardHtlc,
+	)
 	linkQuit := make(chan struct{})
 
 	// Test resume a hold forward
 	assertNumCircuits(t, s, 0, 0)
-	if err := switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket); err != nil {
-		t.Fatalf("can't forward htlc packet: %v", err)
-	}
+	err = switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket)
+	require.NoError(t, err)
+
+	packet := forwardInterceptor.getIntercepted()
+	require.Equal(t, ogPacket.inKey(), packet.IncomingCircuit)
+	require.EqualValues(
+		t, ogPacket.incomingTimeout-10, packet.AutoFailHeight,
+	)
 	assertNumCircuits(t, s, 0, 0)
 	assertOutgoingLinkReceive(t, bobChannelLink, false)
 
-	if err := forwardInterceptor.resume(); err != nil {
-		t.Fatalf("failed to resume forward")
-	}
+	require.NoError(t, switchForwardInterceptor.Resolve(&FwdResolution{
+		Action: FwdActionResume,
+		Key:    packet.IncomingCircuit,
+	}))
 	assertOutgoingLinkReceive(t, bobChannelLink, true)
 	assertNumCircuits(t, s, 1, 1)
 
@@ -3284,37 +3432,134 @@ func TestSwitchHoldForward(t *testing.T) {
 			PaymentPreimage: preimage,
 		},
 	}
-	if err := switchForwardInterceptor.ForwardPackets(linkQuit, settle); err != nil {
-		t.Fatalf("can't forward htlc packet: %v", err)
-	}
+	err = switchForwardInterceptor.ForwardPackets(linkQuit, settle)
+	require.NoError(t, err)
 	assertOutgoingLinkReceive(t, aliceChannelLink, true)
 	assertNumCircuits(t, s, 0, 0)
 
 	// Test failing a hold forward
-	if err := switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket); err != nil {
-		t.Fatalf("can't forward htlc packet: %v", err)
-	}
+	err = switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket)
+	require.NoError(t, err)
+	packet = forwardInterceptor.getIntercepted()
 	assertNumCircuits(t, s, 0, 0)
 	assertOutgoingLinkReceive(t, bobChannelLink, false)
 
-	if err := forwardInterceptor.fail(); err != nil {
-		t.Fatalf("failed to cancel forward %v", err)
-	}
+	require.NoError(t, switchForwardInterceptor.Resolve(&FwdResolution{
+		Action: FwdActionFail,
+		Key:    packet.IncomingCircuit,
+	}))
 	assertOutgoingLinkReceive(t, bobChannelLink, false)
 	assertOutgoingLinkReceive(t, aliceChannelLink, true)
 	assertNumCircuits(t, s, 0, 0)
 
 	// Test settling a hold forward
-	if err := switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket); err != nil {
-		t.Fatalf("can't forward htlc packet: %v", err)
-	}
+	err = switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket)
+	require.NoError(t, err)
+	packet = forwardInterceptor.getIntercepted()
 	assertNumCircuits(t, s, 0, 0)
 	assertOutgoingLinkReceive(t, bobChannelLink, false)
 
-	if err := forwardInterceptor.settle(preimage); err != nil {
-		t.Fatal("failed to cancel forward")
-	}
+	// A preimage that doesn't match is rejected, and the forward remains
+	// held.
+	err = switchForwardInterceptor.Resolve(&FwdResolution{
+		Action:   FwdActionSettle,
+		Key:      packet.IncomingCircuit,
+		Preimage: lntypes.Preimage{2},
+	})
+	require.Error(t, err)
+
+	require.NoError(t, switchForwardInterceptor.Resolve(&FwdResolution{
+		Action:   FwdActionSettle,
+		Key:      packet.IncomingCircuit,
+		Preimage: preimage,
+	}))
 	assertOutgoingLinkReceive(t, bobChannelLink, false)
 	assertOutgoingLinkReceive(t, aliceChannelLink, true)
 	assertNumCircuits(t, s, 0, 0)
+
+	// Resolving a forward that isn't held fails.
+	err = switchForwardInterceptor.Resolve(&FwdResolution{
+		Action: FwdActionResume,
+		Key:    packet.IncomingCircuit,
+	})
+	require.ErrorIs(t, err, ErrFwdNotExists)
+
+	// A held forward survives a disconnect of the interceptor, and is
+	// offered again once an interceptor reconnects.
+	err = switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket)
+	require.NoError(t, err)
+	forwardInterceptor.getIntercepted()
+
+	switchForwardInterceptor.SetInterceptor(nil)
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+
+	switchForwardInterceptor.SetInterceptor(
+		forwardInterceptor.InterceptForwardHtlc,
+	)
+	packet = forwardInterceptor.getIntercepted()
+	require.Equal(t, ogPacket.inKey(), packet.IncomingCircuit)
+
+	// If the incoming link replays the held forward after a restart, it
+	// remains held and isn't offered to the interceptor again.
+	err = switchForwardInterceptor.ForwardPackets(
+		make(chan struct{}), ogPacket,
+	)
+	require.NoError(t, err)
+	forwardInterceptor.assertNotIntercepted()
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+
+	// Once the auto-fail height is reached, the forward is failed back.
+	notifier.EpochChan <- &chainntnfs.BlockEpoch{
+		Height: packet.AutoFailHeight,
+	}
+	assertOutgoingLinkReceive(t, aliceChannelLink, true)
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+
+	err = switchForwardInterceptor.Resolve(&FwdResolution{
+		Action: FwdActionResume,
+		Key:    packet.IncomingCircuit,
+	})
+	require.ErrorIs(t, err, ErrFwdNotExists)
+
+	// Forwards that are too close to their expiry aren't held at all.
+	err = switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket)
+	require.NoError(t, err)
+	forwardInterceptor.assertNotIntercepted()
+	assertOutgoingLinkReceive(t, aliceChannelLink, true)
+
+	// Without an interceptor, forwards are not held by default.
+	switchForwardInterceptor.SetInterceptor(nil)
+	err = switchForwardInterceptor.ForwardPackets(linkQuit, ogPacket)
+	require.NoError(t, err)
+	assertOutgoingLinkReceive(t, bobChannelLink, true)
+	assertNumCircuits(t, s, 1, 1)
+
+	// If an interceptor is required, forwards are held even if no
+	// interceptor is connected, and are offered once it connects.
+	requiredNotifier := &mock.ChainNotifier{
+		EpochChan: make(chan *chainntnfs.BlockEpoch, 1),
+	}
+	requiredNotifier.EpochChan <- &chainntnfs.BlockEpoch{
+		Height: testStartingHeight,
+	}
+	requiredSwitch := NewInterceptableSwitch(&InterceptableSwitchConfig{
+		Switch:             s,
+		Notifier:           requiredNotifier,
+		CltvRejectDelta:    10,
+		RequireInterceptor: true,
+	})
+	require.NoError(t, requiredSwitch.Start())
+	defer func() {
+		require.NoError(t, requiredSwitch.Stop())
+	}()
+
+	heldPacket := *ogPacket
+	heldPacket.incomingHTLCID = 1
+	err = requiredSwitch.ForwardPackets(linkQuit, &heldPacket)
+	require.NoError(t, err)
+	assertOutgoingLinkReceive(t, bobChannelLink, false)
+
+	requiredSwitch.SetInterceptor(forwardInterceptor.InterceptForwardHtlc)
+	packet = forwardInterceptor.getIntercepted()
+	require.Equal(t, heldPacket.inKey(), packet.IncomingCircuit)
 }
@@ -1,9 +1,9 @@
 package input
 
 import (
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/lntypes"
 )
 
@@ -318,7 +318,7 @@ func MakeHtlcSecondLevelTimeoutAnchorInput(signedTx *wire.MsgTx,
 		txinIdx int) (wire.TxWitness, error) {
 
 		desc := signDetails.SignDesc
-		desc.SigHashes = txscript.NewTxSigHashes(txn)
+		desc.SigHashes = NewTxSigHashesV0Only(txn)
 		desc.InputIndex = txinIdx
 
 		return SenderHtlcSpendTimeout(
@@ -7,11 +7,12 @@ import (
 	"fmt"
 	"testing"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/keychain"
 )
 
@@ -91,13 +92,11 @@ func TestRevocationKeyDerivation(t *testing.T) {
 	// First, we'll generate a commitment point, and a commitment secret.
 	// These will be used to derive the ultimate revocation keys.
 	revocationPreimage := testHdSeed.CloneBytes()
-	commitSecret, commitPoint := btcec.PrivKeyFromBytes(btcec.S256(),
-		revocationPreimage)
+	commitSecret, commitPoint := btcec.PrivKeyFromBytes(revocationPreimage)
 
 	// With the commitment secrets generated, we'll now create the base
 	// keys we'll use to derive the revocation key from.
-	basePriv, basePub := btcec.PrivKeyFromBytes(btcec.S256(),
-		testWalletPrivKey)
+	basePriv, basePub := btcec.PrivKeyFromBytes(testWalletPrivKey)
 
 	// With the point and key obtained, we can now derive the revocation
 	// key itself.
@@ -120,7 +119,7 @@ func TestTweakKeyDerivation(t *testing.T) {
 
 	// First, we'll generate a base public key that we'll be "tweaking".
 	baseSecret := testHdSeed.CloneBytes()
-	basePriv, basePub := btcec.PrivKeyFromBytes(btcec.S256(), baseSecret)
+	basePriv, basePub := btcec.PrivKeyFromBytes(baseSecret)
 
 	// With the base key create, we'll now create a commitment point, and
 	// from that derive the bytes we'll used to tweak the base public key.
@@ -189,8 +188,7 @@ func TestHTLCSenderSpendValidation(t *testing.T) {
 	// Next we'll the commitment secret for our commitment tx and also the
 	// revocation key that we'll use as well.
 	revokePreimage := testHdSeed.CloneBytes()
-	commitSecret, commitPoint := btcec.PrivKeyFromBytes(btcec.S256(),
-		revokePreimage)
+	commitSecret, commitPoint := btcec.PrivKeyFromBytes(revokePreimage)
 
 	// Generate a payment preimage to be used below.
 	paymentPreimage := revokePreimage
@@ -199,10 +197,8 @@ func TestHTLCSenderSpendValidation(t *testing.T) {
 
 	// We'll also need some tests keys for alice and bob, and metadata of
 	// the HTLC output.
-	aliceKeyPriv, aliceKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
-		testWalletPrivKey)
-	bobKeyPriv, bobKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
-		bobsPrivKey)
+	aliceKeyPriv, aliceKeyPub := btcec.PrivKeyFromBytes(testWalletPrivKey)
+	bobKeyPriv, bobKeyPub := btcec.PrivKeyFromBytes(bobsPrivKey)
 	paymentAmt := btcutil.Amount(1 * 10e8)
 
 	aliceLocalKey := TweakPubKey(aliceKeyPub, commitPoint)
@@ -226,7 +222,7 @@ func TestHTLCSenderSpendValidation(t *testing.T) {
 		htlcOutput                      *wire.TxOut
 		sweepTxSigHashes                *txscript.TxSigHashes
 		senderCommitTx, sweepTx         *wire.MsgTx
-		bobRecvrSig                     *btcec.Signature
+		bobRecvrSig                     *ecdsa.Signature
 		bobSigHash                      txscript.SigHashType
 	)
 
@@ -282,7 +278,7 @@ func TestHTLCSenderSpendValidation(t *testing.T) {
 			},
 		)
 
-		sweepTxSigHashes = txscript.NewTxSigHashes(sweepTx)
+		sweepTxSigHashes = NewTxSigHashesV0Only(sweepTx)
 
 		bobSigHash = txscript.SigHashAll
 		if confirmed {
@@ -308,9 +304,7 @@ func TestHTLCSenderSpendValidation(t *testing.T) {
 			t.Fatalf("unable to generate alice signature: %v", err)
 		}
 
-		bobRecvrSig, err = btcec.ParseDERSignature(
-			bobSig.Serialize(), btcec.S256(),
-		)
+		bobRecvrSig, err = ecdsa.ParseDERSignature(bobSig.Serialize())
 		if err != nil {
 			t.Fatalf("unable to parse signature: %v", err)
 		}
@@ -552,7 +546,10 @@ func TestHTLCSenderSpendValidation(t *testing.T) {
 		newEngine := func() (*txscript.Engine, error) {
 			return txscript.NewEngine(htlcPkScript,
 				sweepTx, 0, txscript.StandardVerifyFlags, nil,
-				nil, int64(paymentAmt))
+				nil, int64(paymentAmt),
+				txscript.NewCannedPrevOutputFetcher(
+					htlcPkScript, int64(paymentAmt),
+				))
 		}
 
 		assertEngineExecution(t, i, testCase.valid, newEngine)
@@ -591,8 +588,7 @@ func TestHTLCReceiverSpendValidation(t *testing.T) {
 	// Next we'll the commitment secret for our commitment tx and also the
 	// revocation key that we'll use as well.
 	revokePreimage := testHdSeed.CloneBytes()
-	commitSecret, commitPoint := btcec.PrivKeyFromBytes(btcec.S256(),
-		revokePreimage)
+	commitSecret, commitPoint := btcec.PrivKeyFromBytes(revokePreimage)
 
 	// Generate a payment preimage to be used below.
 	paymentPreimage := revokePreimage
@@ -601,10 +597,8 @@ func TestHTLCReceiverSpendValidation(t *testing.T) {
 
 	// We'll also need some tests keys for alice and bob, and metadata of
 	// the HTLC output.
-	aliceKeyPriv, aliceKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
-		testWalletPrivKey)
-	bobKeyPriv, bobKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
-		bobsPrivKey)
+	aliceKeyPriv, aliceKeyPub := btcec.PrivKeyFromBytes(testWalletPrivKey)
+	bobKeyPriv, bobKeyPub := btcec.PrivKeyFromBytes(bobsPrivKey)
 	paymentAmt := btcutil.Amount(1 * 10e8)
 	cltvTimeout := uint32(8)
 
@@ -629,7 +623,7 @@ func TestHTLCReceiverSpendValidation(t *testing.T) {
 		htlcOutput                      *wire.TxOut
 		receiverCommitTx, sweepTx       *wire.MsgTx
 		sweepTxSigHashes                *txscript.TxSigHashes
-		aliceSenderSig                  *btcec.Signature
+		aliceSenderSig                  *ecdsa.Signature
 		aliceSigHash                    txscript.SigHashType
 	)
 
@@ -681,7 +675,7 @@ func TestHTLCReceiverSpendValidation(t *testing.T) {
 				Value:    1 * 10e8,
 			},
 		)
-		sweepTxSigHashes = txscript.NewTxSigHashes(sweepTx)
+		sweepTxSigHashes = NewTxSigHashesV0Only(sweepTx)
 
 		aliceSigHash = txscript.SigHashAll
 		if confirmed {
@@ -707,9 +701,7 @@ func TestHTLCReceiverSpendValidation(t *testing.T) {
 			t.Fatalf("unable to generate alice signature: %v", err)
 		}
 
-		aliceSenderSig, err = btcec.ParseDERSignature(
-			aliceSig.Serialize(), btcec.S256(),
-		)
+		aliceSenderSig, err = ecdsa.ParseDERSignature(aliceSig.Serialize())
 		if err != nil {
 			t.Fatalf("unable to parse signature: %v", err)
 		}
@@ -971,7 +963,10 @@ func TestHTLCReceiverSpendValidation(t *testing.T) {
 		newEngine := func() (*txscript.Engine, error) {
 			return txscript.NewEngine(htlcPkScript,
 				sweepTx, 0, txscript.StandardVerifyFlags, nil,
-				nil, int64(paymentAmt))
+				nil, int64(paymentAmt),
+				txscript.NewCannedPrevOutputFetcher(
+					htlcPkScript, int64(paymentAmt),
+				))
 		}
 
 		assertEngineExecution(t, i, testCase.valid, newEngine)
@@ -992,14 +987,11 @@ func TestSecondLevelHtlcSpends(t *testing.T) {
 
 	// First we'll set up some initial key state for Alice and Bob that
 	// will be used in the scripts we created below.
-	aliceKeyPriv, aliceKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
-		testWalletPrivKey)
-	bobKeyPriv, bobKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
-		bobsPrivKey)
+	aliceKeyPriv, aliceKeyPub := btcec.PrivKeyFromBytes(testWalletPrivKey)
+	bobKeyPriv, bobKeyPub := btcec.PrivKeyFromBytes(bobsPrivKey)
 
 	revokePreimage := testHdSeed.CloneBytes()
-	commitSecret, commitPoint := btcec.PrivKeyFromBytes(
-		btcec.S256(), revokePreimage)
+	commitSecret, commitPoint := btcec.PrivKeyFromBytes(revokePreimage)
 
 	// As we're modeling this as Bob sweeping the HTLC on-chain from his
 	// commitment transaction after a period of time, we'll be using a
@@ -1024,7 +1016,7 @@ func TestSecondLevelHtlcSpends(t *testing.T) {
 			Value:    1 * 10e8,
 		},
 	)
-	sweepTxSigHashes := txscript.NewTxSigHashes(sweepTx)
+	sweepTxSigHashes := NewTxSigHashesV0Only(sweepTx)
 
 	// The delay key will be crafted using Bob's public key as the output
 	// we created will be spending from Alice's commitment transaction.
@@ -1177,7 +1169,10 @@ func TestSecondLevelHtlcSpends(t *testing.T) {
 		newEngine := func() (*txscript.Engine, error) {
 			return txscript.NewEngine(htlcPkScript,
 				sweepTx, 0, txscript.StandardVerifyFlags, nil,
-				nil, int64(htlcAmt))
+				nil, int64(htlcAmt),
+				txscript.NewCannedPrevOutputFetcher(
+					htlcPkScript, int64(htlcAmt),
+				))
 		}
 
 		assertEngineExecution(t, i, testCase.valid, newEngine)
@@ -1192,8 +1187,7 @@ func TestCommitSpendToRemoteConfirmed(t *testing.T) {
 
 	const outputVal = btcutil.Amount(2 * 10e8)
 
-	aliceKeyPriv, aliceKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
-		testWalletPrivKey)
+	aliceKeyPriv, aliceKeyPub := btcec.PrivKeyFromBytes(testWalletPrivKey)
 
 	txid, err := chainhash.NewHash(testHdSeed.CloneBytes())
 	if err != nil {
@@ -1236,7 +1230,7 @@ func TestCommitSpendToRemoteConfirmed(t *testing.T) {
 			// Alice can spend after the a CSV delay has passed.
 			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
 				sweepTx.TxIn[0].Sequence = LockTimeToSequence(false, 1)
-				sweepTxSigHashes := txscript.NewTxSigHashes(sweepTx)
+				sweepTxSigHashes := NewTxSigHashesV0Only(sweepTx)
 
 				signDesc := &SignDescriptor{
 					KeyDesc: keychain.KeyDescriptor{
@@ -1258,7 +1252,7 @@ func TestCommitSpendToRemoteConfirmed(t *testing.T) {
 			// Alice cannot spend output without sequence set.
 			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
 				sweepTx.TxIn[0].Sequence = wire.MaxTxInSequenceNum
-				sweepTxSigHashes := txscript.NewTxSigHashes(sweepTx)
+				sweepTxSigHashes := NewTxSigHashesV0Only(sweepTx)
 
 				signDesc := &SignDescriptor{
 					KeyDesc: keychain.KeyDescriptor{
@@ -1284,7 +1278,10 @@ func TestCommitSpendToRemoteConfirmed(t *testing.T) {
 		newEngine := func() (*txscript.Engine, error) {
 			return txscript.NewEngine(commitPkScript,
 				sweepTx, 0, txscript.StandardVerifyFlags, nil,
-				nil, int64(outputVal))
+				nil, int64(outputVal),
+				txscript.NewCannedPrevOutputFetcher(
+					commitPkScript, int64(outputVal),
+				))
 		}
 
 		assertEngineExecution(t, i, testCase.valid, newEngine)
@@ -1299,8 +1296,7 @@ func TestSpendAnchor(t *testing.T) {
 	const anchorSize = 294
 
 	// First we'll set up some initial key state for Alice.
-	aliceKeyPriv, aliceKeyPub := btcec.PrivKeyFromBytes(btcec.S256(),
-		testWalletPrivKey)
+	aliceKeyPriv, aliceKeyPub := btcec.PrivKeyFromBytes(testWalletPrivKey)
 
 	// Create a fake anchor outpoint that we'll use to generate the
 	// sweeping transaction.
@@ -1349,7 +1345,7 @@ func TestSpendAnchor(t *testing.T) {
 			// Alice can spend immediately.
 			makeWitnessTestCase(t, func() (wire.TxWitness, error) {
 				sweepTx.TxIn[0].Sequence = wire.MaxTxInSequenceNum
-				sweepTxSigHashes := txscript.NewTxSigHashes(sweepTx)
+				sweepTxSigHashes := NewTxSigHashesV0Only(sweepTx)
 
 				signDesc := &SignDescriptor{
 					KeyDesc: keychain.KeyDescriptor{
@@ -1391,7 +1387,10 @@ func TestSpendAnchor(t *testing.T) {
 		newEngine := func() (*txscript.Engine, error) {
 			return txscript.NewEngine(anchorPkScript,
 				sweepTx, 0, txscript.StandardVerifyFlags, nil,
-				nil, int64(anchorSize))
+				nil, int64(anchorSize),
+				txscript.NewCannedPrevOutputFetcher(
+					anchorPkScript, int64(anchorSize),
+				))
 		}
 
 		assertEngineExecution(t, i, testCase.valid, newEngine)
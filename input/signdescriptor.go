@@ -5,7 +5,7 @@ import (
 	"errors"
 	"io"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/keychain"
@@ -77,6 +77,19 @@ type SignDescriptor struct {
 	// InputIndex is the target input within the transaction that should be
 	// signed.
 	InputIndex int
+
+	// SignMethod is the method that should be used to sign the input. It
+	// defaults to an ECDSA signature for a segwit v0 input.
+	SignMethod SignMethod
+
+	// TapTweak is the script root the internal key of a taproot output
+	// commits to. It is only used with the TaprootKeySpendSignMethod.
+	TapTweak []byte
+
+	// PrevOutputFetcher returns the outputs spent by all inputs of the
+	// transaction, which taproot signatures commit to. It must be set
+	// when signing a taproot input.
+	PrevOutputFetcher txscript.PrevOutputFetcher
 }
 
 // WriteSignDescriptor serializes a SignDescriptor struct into the passed
@@ -84,7 +97,8 @@ type SignDescriptor struct {
 //
 // NOTE: We assume the SigHashes and InputIndex fields haven't been assigned
 // yet, since that is usually done just before broadcast by the witness
-// generator.
+// generator. The taproot fields aren't serialized, as the inputs of our
+// contracts are all segwit v0 inputs.
 func WriteSignDescriptor(w io.Writer, sd *SignDescriptor) error {
 	err := binary.Write(w, binary.BigEndian, sd.KeyDesc.Family)
 	if err != nil {
@@ -159,9 +173,7 @@ func ReadSignDescriptor(r io.Reader, sd *SignDescriptor) error {
 		if err != nil {
 			return err
 		}
-		sd.KeyDesc.PubKey, err = btcec.ParsePubKey(
-			pubKeyBytes, btcec.S256(),
-		)
+		sd.KeyDesc.PubKey, err = btcec.ParsePubKey(pubKeyBytes)
 		if err != nil {
 			return err
 		}
@@ -196,7 +208,7 @@ func ReadSignDescriptor(r io.Reader, sd *SignDescriptor) error {
 	if len(doubleTweakBytes) == 0 {
 		sd.DoubleTweak = nil
 	} else {
-		sd.DoubleTweak, _ = btcec.PrivKeyFromBytes(btcec.S256(), doubleTweakBytes)
+		sd.DoubleTweak, _ = btcec.PrivKeyFromBytes(doubleTweakBytes)
 	}
 
 	// Only one tweak should ever be set, fail if both are present.
@@ -2,6 +2,7 @@ package input
 
 import (
 	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 )
 
@@ -59,6 +60,18 @@ const (
 	//      - pkscript (p2wsh): 34 bytes
 	P2WSHOutputSize = 8 + 1 + P2WSHSize
 
+	// P2TRSize 34 bytes
+	//	- OP_1: 1 byte
+	//	- OP_DATA: 1 byte (x-only public key length)
+	//	- x-only public key: 32 bytes
+	P2TRSize = 1 + 1 + 32
+
+	// P2TROutputSize 43 bytes
+	//      - value: 8 bytes
+	//      - var_int: 1 byte (pkscript_length)
+	//      - pkscript (p2tr): 34 bytes
+	P2TROutputSize = 8 + 1 + P2TRSize
+
 	// P2SHOutputSize 32 bytes
 	//      - value: 8 bytes
 	//      - var_int: 1 byte (pkscript_length)
@@ -80,6 +93,19 @@ const (
 	//      - pubkey
 	P2WKHWitnessSize = 1 + 1 + 73 + 1 + 33
 
+	// TaprootKeyPathWitnessSize 66 bytes
+	//      - number_of_witness_elements: 1 byte
+	//      - signature_length: 1 byte
+	//      - signature: 64 bytes
+	TaprootKeyPathWitnessSize = 1 + 1 + 64
+
+	// TaprootKeyPathCustomSighashWitnessSize 67 bytes
+	//      - number_of_witness_elements: 1 byte
+	//      - signature_length: 1 byte
+	//      - signature: 64 bytes
+	//      - sighash_flag: 1 byte
+	TaprootKeyPathCustomSighashWitnessSize = 1 + 1 + 64 + 1
+
 	// MultiSigSize 71 bytes
 	//	- OP_2: 1 byte
 	//	- OP_DATA: 1 byte (pubKeyAlice length)
@@ -547,6 +573,21 @@ func (twe *TxWeightEstimator) AddP2WKHInput() *TxWeightEstimator {
 	return twe
 }
 
+// AddTaprootKeySpendInput updates the weight estimate to account for an
+// additional input spending a taproot output through the key spend path. Any
+// sighash type other than the default one adds a byte to the signature.
+func (twe *TxWeightEstimator) AddTaprootKeySpendInput(
+	hashType txscript.SigHashType) *TxWeightEstimator {
+
+	if hashType == txscript.SigHashDefault {
+		twe.AddWitnessInput(TaprootKeyPathWitnessSize)
+	} else {
+		twe.AddWitnessInput(TaprootKeyPathCustomSighashWitnessSize)
+	}
+
+	return twe
+}
+
 // AddWitnessInput updates the weight estimate to account for an additional
 // input spending a native pay-to-witness output. This accepts the total size
 // of the witness as a parameter.
@@ -616,6 +657,15 @@ func (twe *TxWeightEstimator) AddP2WSHOutput() *TxWeightEstimator {
 	return twe
 }
 
+// AddP2TROutput updates the weight estimate to account for an additional
+// native P2TR output.
+func (twe *TxWeightEstimator) AddP2TROutput() *TxWeightEstimator {
+	twe.outputSize += P2TROutputSize
+	twe.outputCount++
+
+	return twe
+}
+
 // AddP2SHOutput updates the weight estimate to account for an additional P2SH
 // output.
 func (twe *TxWeightEstimator) AddP2SHOutput() *TxWeightEstimator {
@@ -1,16 +1,15 @@
 package input_test
 
 import (
-	"math/big"
 	"testing"
 
 	"github.com/btcsuite/btcd/blockchain"
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/stretchr/testify/require"
 
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -38,12 +37,9 @@ var (
 	testPreimage = make([]byte, 32)
 
 	// testPubkey is a pubkey used in script size calculation.
-	testPubkey = &btcec.PublicKey{
-		X: &big.Int{},
-		Y: &big.Int{},
-	}
+	testPubkey = btcec.NewPublicKey(&btcec.FieldVal{}, &btcec.FieldVal{})
 
-	testPrivkey, _ = btcec.PrivKeyFromBytes(btcec.S256(), make([]byte, 32))
+	testPrivkey, _ = btcec.PrivKeyFromBytes(make([]byte, 32))
 
 	testTx = wire.NewMsgTx(2)
 
@@ -89,6 +85,15 @@ func TestTxWeightEstimator(t *testing.T) {
 		t.Fatalf("Failed to generate scriptPubKey: %v", err)
 	}
 
+	p2trAddr, err := btcutil.NewAddressTaproot(make([]byte, 32), netParams)
+	if err != nil {
+		t.Fatalf("Failed to generate address: %v", err)
+	}
+	p2trScript, err := txscript.PayToAddrScript(p2trAddr)
+	if err != nil {
+		t.Fatalf("Failed to generate scriptPubKey: %v", err)
+	}
+
 	p2shAddr, err := btcutil.NewAddressScriptHash([]byte{0}, netParams)
 	if err != nil {
 		t.Fatalf("Failed to generate address: %v", err)
@@ -104,9 +109,11 @@ func TestTxWeightEstimator(t *testing.T) {
 		numP2WSHInputs       int
 		numNestedP2WKHInputs int
 		numNestedP2WSHInputs int
+		numP2TRInputs        int
 		numP2PKHOutputs      int
 		numP2WKHOutputs      int
 		numP2WSHOutputs      int
+		numP2TROutputs       int
 		numP2SHOutputs       int
 	}{
 		// Assert base txn size.
@@ -128,6 +135,9 @@ func TestTxWeightEstimator(t *testing.T) {
 		{
 			numNestedP2WSHInputs: 1,
 		},
+		{
+			numP2TRInputs: 1,
+		},
 		{
 			numP2WKHOutputs: 1,
 		},
@@ -137,6 +147,9 @@ func TestTxWeightEstimator(t *testing.T) {
 		{
 			numP2WSHOutputs: 1,
 		},
+		{
+			numP2TROutputs: 1,
+		},
 		{
 			numP2SHOutputs: 1,
 		},
@@ -157,6 +170,9 @@ func TestTxWeightEstimator(t *testing.T) {
 		{
 			numNestedP2WSHInputs: 253,
 		},
+		{
+			numP2TRInputs: 253,
+		},
 		{
 			numP2WKHOutputs: 253,
 		},
@@ -166,6 +182,9 @@ func TestTxWeightEstimator(t *testing.T) {
 		{
 			numP2WSHOutputs: 253,
 		},
+		{
+			numP2TROutputs: 253,
+		},
 		{
 			numP2SHOutputs: 253,
 		},
@@ -207,6 +226,11 @@ func TestTxWeightEstimator(t *testing.T) {
 			numNestedP2WSHInputs: 1,
 			numP2WKHOutputs:      1,
 		},
+		{
+			numP2TRInputs:   1,
+			numP2TROutputs:  1,
+			numP2WKHOutputs: 1,
+		},
 
 		// Assert disparate input/output types increment total
 		// input/output counts.
@@ -297,6 +321,15 @@ func TestTxWeightEstimator(t *testing.T) {
 
 			tx.AddTxIn(&wire.TxIn{SignatureScript: scriptSig, Witness: witness})
 		}
+		for j := 0; j < test.numP2TRInputs; j++ {
+			weightEstimate.AddTaprootKeySpendInput(
+				txscript.SigHashDefault,
+			)
+
+			signature := make([]byte, 64)
+			witness := wire.TxWitness{signature}
+			tx.AddTxIn(&wire.TxIn{Witness: witness})
+		}
 		for j := 0; j < test.numP2PKHOutputs; j++ {
 			weightEstimate.AddP2PKHOutput()
 			tx.AddTxOut(&wire.TxOut{PkScript: p2pkhScript})
@@ -309,6 +342,10 @@ func TestTxWeightEstimator(t *testing.T) {
 			weightEstimate.AddP2WSHOutput()
 			tx.AddTxOut(&wire.TxOut{PkScript: p2wshScript})
 		}
+		for j := 0; j < test.numP2TROutputs; j++ {
+			weightEstimate.AddP2TROutput()
+			tx.AddTxOut(&wire.TxOut{PkScript: p2trScript})
+		}
 		for j := 0; j < test.numP2SHOutputs; j++ {
 			weightEstimate.AddP2SHOutput()
 			tx.AddTxOut(&wire.TxOut{PkScript: p2shScript})
@@ -0,0 +1,120 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+var (
+	// ErrTaprootPrevOutputs is returned when a taproot input is signed
+	// without the outputs spent by the transaction.
+	ErrTaprootPrevOutputs = errors.New("taproot signatures require all " +
+		"previous outputs of the transaction")
+)
+
+// SignMethod defines how an input is signed by SignOutputRaw.
+type SignMethod uint8
+
+const (
+	// WitnessV0SignMethod creates an ECDSA signature for a segwit v0
+	// input according to BIP-143. This is the default.
+	WitnessV0SignMethod SignMethod = 0
+
+	// TaprootKeySpendBIP0086SignMethod creates a schnorr signature for the
+	// key spend path of a BIP-86 taproot output, which commits to no
+	// script.
+	TaprootKeySpendBIP0086SignMethod SignMethod = 1
+
+	// TaprootKeySpendSignMethod creates a schnorr signature for the key
+	// spend path of a taproot output, whose internal key is tweaked with
+	// the TapTweak of the sign descriptor.
+	TaprootKeySpendSignMethod SignMethod = 2
+)
+
+// String returns a human readable representation of the sign method.
+func (s SignMethod) String() string {
+	switch s {
+	case WitnessV0SignMethod:
+		return "witness_v0"
+	case TaprootKeySpendBIP0086SignMethod:
+		return "taproot_key_spend_bip86"
+	case TaprootKeySpendSignMethod:
+		return "taproot_key_spend"
+	default:
+		return fmt.Sprintf("unknown<%d>", s)
+	}
+}
+
+// IsTaproot returns true if the sign method creates a schnorr signature for
+// a taproot input.
+func (s SignMethod) IsTaproot() bool {
+	return s == TaprootKeySpendBIP0086SignMethod ||
+		s == TaprootKeySpendSignMethod
+}
+
+// PayToTaprootScript returns the pkScript of the taproot output with the
+// given output key.
+func PayToTaprootScript(outputKey *btcec.PublicKey) ([]byte, error) {
+	return txscript.NewScriptBuilder().
+		AddOp(txscript.OP_1).
+		AddData(schnorr.SerializePubKey(outputKey)).
+		Script()
+}
+
+// TaprootKeySpendSignature creates a schnorr signature for the key spend path
+// of the taproot output described by the sign descriptor. The passed private
+// key is the internal key of the output, which is tweaked according to the
+// sign method of the descriptor.
+func TaprootKeySpendSignature(tx *wire.MsgTx, signDesc *SignDescriptor,
+	privKey *btcec.PrivateKey) (*schnorr.Signature, error) {
+
+	var scriptRoot []byte
+	switch signDesc.SignMethod {
+	case TaprootKeySpendBIP0086SignMethod:
+		if len(signDesc.TapTweak) > 0 {
+			return nil, fmt.Errorf("tap tweak must be empty for " +
+				"BIP-86 key spends")
+		}
+
+	case TaprootKeySpendSignMethod:
+		scriptRoot = signDesc.TapTweak
+
+	default:
+		return nil, fmt.Errorf("sign method %v is not a taproot key "+
+			"spend", signDesc.SignMethod)
+	}
+
+	// The BIP-341 sighash commits to the amounts and scripts of all spent
+	// outputs, so we can only sign if all of them are known.
+	fetcher := signDesc.PrevOutputFetcher
+	if fetcher == nil {
+		return nil, ErrTaprootPrevOutputs
+	}
+	for _, txIn := range tx.TxIn {
+		if fetcher.FetchPrevOutput(txIn.PreviousOutPoint) == nil {
+			return nil, ErrTaprootPrevOutputs
+		}
+	}
+
+	// The tweak negates the passed private key in place if its public key
+	// has an odd y coordinate, so we sign with a copy to leave the key of
+	// the caller untouched.
+	keyCopy := *privKey
+	rawSig, err := txscript.RawTxInTaprootSignature(
+		tx, txscript.NewTxSigHashes(tx, fetcher), signDesc.InputIndex,
+		signDesc.Output.Value, signDesc.Output.PkScript, scriptRoot,
+		signDesc.HashType, &keyCopy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Any sighash flag other than the default one is appended to the raw
+	// signature, but isn't part of the returned signature.
+	return schnorr.ParseSignature(rawSig[:schnorr.SignatureSize])
+}
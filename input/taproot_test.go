@@ -0,0 +1,162 @@
+package input
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/require"
+)
+
+func mustDecodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+
+	return b
+}
+
+// TestPayToTaprootScriptBIP0086 tests the pkScript of a BIP-86 output against
+// the test vectors of BIP-86.
+func TestPayToTaprootScriptBIP0086(t *testing.T) {
+	t.Parallel()
+
+	internalKey, err := schnorr.ParsePubKey(mustDecodeHex(
+		t, "cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd"+
+			"6fc115",
+	))
+	require.NoError(t, err)
+
+	pkScript, err := PayToTaprootScript(
+		txscript.ComputeTaprootKeyNoScript(internalKey),
+	)
+	require.NoError(t, err)
+	require.Equal(t, mustDecodeHex(
+		t, "5120a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc688"+
+			"0949dc684c",
+	), pkScript)
+}
+
+// TestTaprootKeySpendSignature tests that a key spend signature created with
+// the internal private key is valid under the tweaked output key, and that it
+// commits to all previous outputs of the transaction.
+func TestTaprootKeySpendSignature(t *testing.T) {
+	t.Parallel()
+
+	scriptRoot := chainhash.HashB([]byte("script root"))
+
+	for _, method := range []SignMethod{
+		TaprootKeySpendBIP0086SignMethod, TaprootKeySpendSignMethod,
+	} {
+		privKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		var tweak []byte
+		if method == TaprootKeySpendSignMethod {
+			tweak = scriptRoot
+		}
+		outputKey := txscript.ComputeTaprootOutputKey(
+			privKey.PubKey(), tweak,
+		)
+
+		pkScript, err := PayToTaprootScript(outputKey)
+		require.NoError(t, err)
+
+		tx := wire.NewMsgTx(2)
+		prevOuts := txscript.NewMultiPrevOutFetcher(nil)
+		for i := uint32(0); i < 2; i++ {
+			op := wire.OutPoint{Index: i}
+			tx.AddTxIn(wire.NewTxIn(&op, nil, nil))
+			prevOuts.AddPrevOut(
+				op, wire.NewTxOut(int64(i+1)*1000, pkScript),
+			)
+		}
+		tx.AddTxOut(wire.NewTxOut(1500, pkScript))
+		op := tx.TxIn[1].PreviousOutPoint
+
+		// verify executes the script of the signed input against the
+		// given previous outputs.
+		verify := func(sig *schnorr.Signature,
+			hashType txscript.SigHashType,
+			prevOuts txscript.PrevOutputFetcher) error {
+
+			rawSig := sig.Serialize()
+			if hashType != txscript.SigHashDefault {
+				rawSig = append(rawSig, byte(hashType))
+			}
+			tx.TxIn[1].Witness = wire.TxWitness{rawSig}
+
+			prevOut := prevOuts.FetchPrevOutput(op)
+			vm, err := txscript.NewEngine(
+				prevOut.PkScript, tx, 1,
+				txscript.StandardVerifyFlags, nil,
+				txscript.NewTxSigHashes(tx, prevOuts),
+				prevOut.Value, prevOuts,
+			)
+			if err != nil {
+				return err
+			}
+
+			return vm.Execute()
+		}
+
+		signDesc := &SignDescriptor{
+			Output:            prevOuts.FetchPrevOutput(op),
+			HashType:          txscript.SigHashDefault,
+			InputIndex:        1,
+			SignMethod:        method,
+			TapTweak:          tweak,
+			PrevOutputFetcher: prevOuts,
+		}
+		sig, err := TaprootKeySpendSignature(tx, signDesc, privKey)
+		require.NoError(t, err)
+		require.NoError(
+			t, verify(sig, txscript.SigHashDefault, prevOuts),
+		)
+
+		// Changing the amount of another input invalidates the
+		// signature.
+		changedOuts := txscript.NewMultiPrevOutFetcher(nil)
+		changedOuts.AddPrevOut(
+			wire.OutPoint{Index: 0}, wire.NewTxOut(1001, pkScript),
+		)
+		changedOuts.AddPrevOut(op, signDesc.Output)
+		require.Error(
+			t, verify(sig, txscript.SigHashDefault, changedOuts),
+		)
+
+		// Unless the signature only commits to its own input.
+		anyoneCanPay := txscript.SigHashAll |
+			txscript.SigHashAnyOneCanPay
+		signDesc.HashType = anyoneCanPay
+		sig, err = TaprootKeySpendSignature(tx, signDesc, privKey)
+		require.NoError(t, err)
+		require.NoError(t, verify(sig, anyoneCanPay, changedOuts))
+	}
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{}, nil, nil))
+
+	// Signing fails without the previous outputs.
+	_, err = TaprootKeySpendSignature(tx, &SignDescriptor{
+		Output:     &wire.TxOut{},
+		SignMethod: TaprootKeySpendBIP0086SignMethod,
+	}, privKey)
+	require.ErrorIs(t, err, ErrTaprootPrevOutputs)
+
+	// A BIP-86 output doesn't commit to a script root.
+	_, err = TaprootKeySpendSignature(tx, &SignDescriptor{
+		Output:     &wire.TxOut{},
+		SignMethod: TaprootKeySpendBIP0086SignMethod,
+		TapTweak:   scriptRoot,
+	}, privKey)
+	require.Error(t, err)
+}
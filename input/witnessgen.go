@@ -145,6 +145,11 @@ const (
 	// CommitmentAnchor is a witness that allows us to spend our anchor on
 	// the commitment transaction.
 	CommitmentAnchor StandardWitnessType = 14
+
+	// TaprootPubKeySpend is a witness type that allows us to spend a
+	// regular p2tr output that's sent to an output which is under complete
+	// control of the backing wallet.
+	TaprootPubKeySpend StandardWitnessType = 17
 )
 
 // String returns a human readable version of the target WitnessType.
@@ -203,6 +208,9 @@ func (wt StandardWitnessType) String() string {
 	case NestedWitnessKeyHash:
 		return "NestedWitnessKeyHash"
 
+	case TaprootPubKeySpend:
+		return "TaprootPubKeySpend"
+
 	default:
 		return fmt.Sprintf("Unknown WitnessType: %v", uint32(wt))
 	}
@@ -353,6 +361,8 @@ func (wt StandardWitnessType) WitnessGenerator(signer Signer,
 		case WitnessKeyHash:
 			fallthrough
 		case NestedWitnessKeyHash:
+			fallthrough
+		case TaprootPubKeySpend:
 			return signer.ComputeInputScript(tx, desc)
 
 		default:
@@ -443,6 +453,11 @@ func (wt StandardWitnessType) SizeUpperBound() (int, bool, error) {
 	// The revocation output of a second level output of an HTLC.
 	case HtlcSecondLevelRevoke:
 		return ToLocalPenaltyWitnessSize, false, nil
+
+	// A p2tr output of the wallet spent through the key spend path. We
+	// account for a sighash flag appended to the signature.
+	case TaprootPubKeySpend:
+		return TaprootKeyPathCustomSighashWitnessSize, false, nil
 	}
 
 	return 0, false, fmt.Errorf("unexpected witness type: %v", wt)
@@ -0,0 +1,398 @@
+package invoices
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+)
+
+var (
+	// ErrExitHtlcNotFound is returned when a decision of the htlc acceptor
+	// refers to an htlc that isn't waiting for a decision.
+	ErrExitHtlcNotFound = errors.New("exit hop htlc not found")
+
+	// ErrAcceptorPreimageMismatch is returned when the htlc acceptor
+	// settles an htlc with a preimage that doesn't match its hash.
+	ErrAcceptorPreimageMismatch = errors.New("preimage does not match " +
+		"payment hash")
+
+	// ErrAcceptorSettleInvoice is returned when the htlc acceptor settles
+	// an htlc that pays to one of our invoices. Such htlcs must be resumed
+	// instead, such that the invoice registry settles the invoice.
+	ErrAcceptorSettleInvoice = errors.New("htlc pays to a known " +
+		"invoice, resume it instead")
+)
+
+// ExitHtlc describes an htlc that pays to our node, which is offered to the
+// htlc acceptor before the invoice registry processes it.
+type ExitHtlc struct {
+	// CircuitKey identifies the incoming channel and index of the htlc.
+	CircuitKey channeldb.CircuitKey
+
+	// Hash is the payment hash of the htlc.
+	Hash lntypes.Hash
+
+	// AmtPaid is the amount of the htlc.
+	AmtPaid lnwire.MilliSatoshi
+
+	// Expiry is the absolute block height at which the htlc expires.
+	Expiry uint32
+
+	// CurrentHeight is the block height at which the htlc was received.
+	CurrentHeight int32
+
+	// CustomRecords are the custom records of the onion payload.
+	CustomRecords record.CustomSet
+
+	// MPP is the multi-path record of the onion payload, if present.
+	MPP *record.MPP
+
+	// Invoice is the invoice that the htlc pays to. It is nil if we don't
+	// know an invoice for the payment hash.
+	Invoice *channeldb.Invoice
+
+	// TrampolineOnion is the trampoline onion of the onion payload. It is
+	// only set for htlcs that ask us to forward a payment as trampoline
+	// node, which are handed to the trampoline forwarder instead of the
+	// htlc acceptor.
+	TrampolineOnion []byte
+}
+
+// ExitHtlcAcceptor is a function that is invoked for every htlc that pays to
+// our node while the acceptor is registered. The htlc is held until the
+// acceptor decides on it through ResolveExitHtlc, or until it gets as close to
+// its expiry as hold invoices are allowed to, at which point it is failed.
+type ExitHtlcAcceptor func(*ExitHtlc) error
+
+// ExitHtlcAction defines the decisions that the htlc acceptor can take.
+type ExitHtlcAction uint8
+
+const (
+	// ExitHtlcResume hands the htlc to the invoice registry, which
+	// processes it as if it wasn't intercepted.
+	ExitHtlcResume ExitHtlcAction = iota
+
+	// ExitHtlcSettle settles the htlc with the preimage of the decision,
+	// without involving the invoice registry. It is only allowed for htlcs
+	// that don't pay to any of our invoices.
+	ExitHtlcSettle
+
+	// ExitHtlcFail fails the htlc back to the sender.
+	ExitHtlcFail
+)
+
+// String returns a human readable representation of the action.
+func (a ExitHtlcAction) String() string {
+	switch a {
+	case ExitHtlcResume:
+		return "resume"
+
+	case ExitHtlcSettle:
+		return "settle"
+
+	case ExitHtlcFail:
+		return "fail"
+
+	default:
+		return fmt.Sprintf("unknown action %d", a)
+	}
+}
+
+// ExitHtlcResolution is the decision of the htlc acceptor about an htlc.
+type ExitHtlcResolution struct {
+	// CircuitKey identifies the htlc that the decision refers to.
+	CircuitKey channeldb.CircuitKey
+
+	// Action is the decision about the htlc.
+	Action ExitHtlcAction
+
+	// Preimage is the preimage to settle the htlc with. It is only used
+	// for ExitHtlcSettle.
+	Preimage lntypes.Preimage
+
+	// FailureMessage is the failure to fail the htlc with. It is only used
+	// for ExitHtlcFail. If it is nil, the htlc is failed with
+	// incorrect_or_unknown_payment_details.
+	FailureMessage lnwire.FailureMessage
+}
+
+// pendingExitHtlc is an htlc that waits for a decision of the htlc acceptor.
+type pendingExitHtlc struct {
+	htlc     *ExitHtlc
+	payload  Payload
+	hodlChan chan<- interface{}
+}
+
+// SetExitHtlcAcceptor registers the acceptor that decides on all htlcs that
+// pay to our node. Only one acceptor can be registered at a time. Passing nil
+// unregisters the current acceptor, in which case all htlcs that still wait
+// for a decision are handed to the invoice registry.
+func (i *InvoiceRegistry) SetExitHtlcAcceptor(acceptor ExitHtlcAcceptor) {
+	i.acceptorMtx.Lock()
+	defer i.acceptorMtx.Unlock()
+
+	i.exitHtlcAcceptor = acceptor
+	if acceptor != nil {
+		return
+	}
+
+	log.Infof("Htlc acceptor unregistered, resuming %d pending htlcs",
+		len(i.pendingExitHtlcs))
+
+	for key, pending := range i.pendingExitHtlcs {
+		// Trampoline htlcs are resolved by the trampoline forwarder.
+		if pending.htlc.TrampolineOnion != nil {
+			continue
+		}
+
+		delete(i.pendingExitHtlcs, key)
+
+		err := i.resolveExitHtlc(pending, &ExitHtlcResolution{
+			CircuitKey: key,
+			Action:     ExitHtlcResume,
+		})
+		if err != nil {
+			log.Errorf("Unable to resume htlc %v: %v", key, err)
+		}
+	}
+}
+
+// ResolveExitHtlc applies the decision of the htlc acceptor to the htlc it
+// refers to.
+func (i *InvoiceRegistry) ResolveExitHtlc(res *ExitHtlcResolution) error {
+	i.acceptorMtx.Lock()
+	defer i.acceptorMtx.Unlock()
+
+	pending, ok := i.pendingExitHtlcs[res.CircuitKey]
+	if !ok {
+		return fmt.Errorf("%w: %v", ErrExitHtlcNotFound,
+			res.CircuitKey)
+	}
+
+	// Check the settle before we release the htlc, so that a rejected
+	// settle doesn't leave the htlc unresolved.
+	if res.Action == ExitHtlcSettle {
+		if err := i.checkAcceptorSettle(pending, res); err != nil {
+			return err
+		}
+	}
+
+	delete(i.pendingExitHtlcs, res.CircuitKey)
+
+	return i.resolveExitHtlc(pending, res)
+}
+
+// checkAcceptorSettle checks that the acceptor may settle the pending htlc
+// with the preimage of its decision. Settling an htlc that pays to one of our
+// invoices would bypass the registry and leave the invoice open, so the
+// acceptor must resume those htlcs instead. It must be called with the
+// acceptor mutex held.
+func (i *InvoiceRegistry) checkAcceptorSettle(pending *pendingExitHtlc,
+	res *ExitHtlcResolution) error {
+
+	if !res.Preimage.Matches(pending.htlc.Hash) {
+		return ErrAcceptorPreimageMismatch
+	}
+
+	// The invoice may have been added after the htlc was offered to the
+	// acceptor, so we look it up again.
+	_, err := i.LookupInvoice(pending.htlc.Hash)
+	switch {
+	case err == nil:
+		return ErrAcceptorSettleInvoice
+
+	case errors.Is(err, channeldb.ErrInvoiceNotFound),
+		errors.Is(err, channeldb.ErrNoInvoicesCreated):
+
+		return nil
+
+	default:
+		return err
+	}
+}
+
+// failExpiredExitHtlc fails the htlc if it still waits for a decision of the
+// htlc acceptor. It is called by the expiry watcher once the htlc gets close
+// to its expiry. Trampoline htlcs are left to the trampoline forwarder, as
+// their outgoing payment may still be in flight.
+func (i *InvoiceRegistry) failExpiredExitHtlc(key channeldb.CircuitKey) {
+	// Resuming an htlc adds invoices to the expiry watcher while holding
+	// the acceptor mutex, so we must not block the watcher on it.
+	i.wg.Add(1)
+	go func() {
+		defer i.wg.Done()
+
+		i.failPendingExitHtlc(key)
+	}()
+}
+
+// failPendingExitHtlc fails the htlc with ResultAcceptorTimeout if it still
+// waits for a decision of the htlc acceptor.
+func (i *InvoiceRegistry) failPendingExitHtlc(key channeldb.CircuitKey) {
+	i.acceptorMtx.Lock()
+	defer i.acceptorMtx.Unlock()
+
+	pending, ok := i.pendingExitHtlcs[key]
+	if !ok || pending.htlc.TrampolineOnion != nil {
+		return
+	}
+
+	log.Infof("Htlc acceptor didn't decide on htlc %v before its "+
+		"expiry at height %v, failing it", key, pending.htlc.Expiry)
+
+	delete(i.pendingExitHtlcs, key)
+
+	resolution := NewFailResolution(
+		key, pending.htlc.CurrentHeight, ResultAcceptorTimeout,
+	)
+
+	i.Lock()
+	i.notifyHodlSubscribers(resolution)
+	i.Unlock()
+}
+
+// interceptExitHtlc offers the htlc to the registered htlc acceptor. It
+// returns true if the htlc is held for a decision of the acceptor, in which
+// case the decision is delivered to the hodl channel later on.
+func (i *InvoiceRegistry) interceptExitHtlc(htlc *ExitHtlc, payload Payload,
+	hodlChan chan<- interface{}) (bool, error) {
+
+	i.acceptorMtx.Lock()
+	defer i.acceptorMtx.Unlock()
+
+	// If the htlc already waits for a decision, the incoming link replayed
+	// it after a restart.
+	if i.resubscribePendingHtlc(htlc.CircuitKey, hodlChan) {
+		return true, nil
+	}
+
+	if i.exitHtlcAcceptor == nil {
+		return false, nil
+	}
+
+	// Let the acceptor inspect the invoice that the htlc pays to, if we
+	// know one.
+	invoice, err := i.LookupInvoice(htlc.Hash)
+	switch {
+	case err == nil:
+		htlc.Invoice = &invoice
+
+	case errors.Is(err, channeldb.ErrInvoiceNotFound),
+		errors.Is(err, channeldb.ErrNoInvoicesCreated):
+
+	default:
+		return false, err
+	}
+
+	i.pendingExitHtlcs[htlc.CircuitKey] = &pendingExitHtlc{
+		htlc:     htlc,
+		payload:  payload,
+		hodlChan: hodlChan,
+	}
+
+	i.Lock()
+	i.hodlSubscribe(hodlChan, htlc.CircuitKey)
+	i.Unlock()
+
+	// If the acceptor is unable to take the htlc, it is about to
+	// disconnect. The htlc is then resumed once it is unregistered.
+	if err := i.exitHtlcAcceptor(htlc); err != nil {
+		log.Errorf("Htlc acceptor unable to handle htlc %v: %v",
+			htlc.CircuitKey, err)
+	}
+
+	return true, nil
+}
+
+// resubscribePendingHtlc delivers the decision about a pending htlc to the
+// new hodl channel of the incoming link. It returns false if the htlc isn't
+// pending. It must be called with the acceptor mutex held.
+func (i *InvoiceRegistry) resubscribePendingHtlc(key channeldb.CircuitKey,
+	hodlChan chan<- interface{}) bool {
+
+	pending, ok := i.pendingExitHtlcs[key]
+	if !ok {
+		return false
+	}
+
+	pending.hodlChan = hodlChan
+
+	i.Lock()
+	i.hodlSubscribe(hodlChan, key)
+	i.Unlock()
+
+	return true
+}
+
+// resolveExitHtlc applies the decision to the pending htlc and notifies the
+// incoming link of the resolution. It must be called with the acceptor mutex
+// held.
+func (i *InvoiceRegistry) resolveExitHtlc(pending *pendingExitHtlc,
+	res *ExitHtlcResolution) error {
+
+	htlc := pending.htlc
+	isTrampoline := htlc.TrampolineOnion != nil
+
+	log.Debugf("Htlc acceptor decided to %v htlc %v", res.Action,
+		htlc.CircuitKey)
+
+	settleResult, failResult := ResultSettledByAcceptor,
+		ResultRejectedByAcceptor
+	if isTrampoline {
+		settleResult, failResult = ResultTrampolineForwarded,
+			ResultTrampolineFailed
+	}
+
+	var resolution HtlcResolution
+	switch {
+	// Trampoline htlcs don't pay to any of our invoices, so the registry
+	// is unable to process them.
+	case res.Action == ExitHtlcResume && isTrampoline:
+		return fmt.Errorf("unable to resume trampoline htlc %v",
+			htlc.CircuitKey)
+
+	case res.Action == ExitHtlcResume:
+		var err error
+		resolution, err = i.notifyExitHopHtlc(
+			htlc.Hash, htlc.AmtPaid, htlc.Expiry,
+			htlc.CurrentHeight, htlc.CircuitKey, pending.hodlChan,
+			pending.payload,
+		)
+		if err != nil {
+			return err
+		}
+
+		// If the registry holds the htlc, it notifies the link once
+		// the htlc is resolved.
+		if resolution == nil {
+			return nil
+		}
+
+	case res.Action == ExitHtlcSettle:
+		resolution = NewSettleResolution(
+			res.Preimage, htlc.CircuitKey, htlc.CurrentHeight,
+			settleResult,
+		)
+
+	case res.Action == ExitHtlcFail:
+		failResolution := NewFailResolution(
+			htlc.CircuitKey, htlc.CurrentHeight, failResult,
+		)
+		failResolution.FailureMessage = res.FailureMessage
+		resolution = failResolution
+
+	default:
+		return fmt.Errorf("unknown htlc acceptor action: %v",
+			res.Action)
+	}
+
+	i.Lock()
+	i.notifyHodlSubscribers(resolution)
+	i.Unlock()
+
+	return nil
+}
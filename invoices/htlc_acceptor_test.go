@@ -0,0 +1,236 @@
+package invoices
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// receiveResolution waits for a resolution on the hodl channel.
+func receiveResolution(t *testing.T,
+	hodlChan <-chan interface{}) HtlcResolution {
+
+	t.Helper()
+
+	select {
+	case res := <-hodlChan:
+		return res.(HtlcResolution)
+
+	case <-time.After(testTimeout):
+		t.Fatal("no resolution received")
+	}
+
+	return nil
+}
+
+// TestExitHtlcAcceptor tests that htlcs that pay to our node are held for the
+// decision of the htlc acceptor.
+func TestExitHtlcAcceptor(t *testing.T) {
+	ctx := newTestContext(t)
+	defer ctx.cleanup()
+
+	_, err := ctx.registry.AddInvoice(testInvoice, testInvoicePaymentHash)
+	require.NoError(t, err)
+
+	offered := make(chan *ExitHtlc, 1)
+	ctx.registry.SetExitHtlcAcceptor(func(htlc *ExitHtlc) error {
+		offered <- htlc
+		return nil
+	})
+
+	notify := func(htlcID uint64, hash lntypes.Hash,
+		hodlChan chan interface{}) *ExitHtlc {
+
+		resolution, err := ctx.registry.NotifyExitHopHtlc(
+			hash, testInvoiceAmt, testHtlcExpiry,
+			testCurrentHeight, getCircuitKey(htlcID), hodlChan,
+			testPayload,
+		)
+		require.NoError(t, err)
+		require.Nil(t, resolution)
+
+		select {
+		case htlc := <-offered:
+			return htlc
+
+		case <-time.After(testTimeout):
+			t.Fatal("htlc not offered to acceptor")
+		}
+
+		return nil
+	}
+
+	// The acceptor fails an htlc with a custom failure message.
+	hodlChan := make(chan interface{}, 1)
+	htlc := notify(0, testInvoicePaymentHash, hodlChan)
+	require.NotNil(t, htlc.Invoice)
+	require.Equal(t, testInvoiceAmt, htlc.AmtPaid)
+
+	err = ctx.registry.ResolveExitHtlc(&ExitHtlcResolution{
+		CircuitKey:     htlc.CircuitKey,
+		Action:         ExitHtlcFail,
+		FailureMessage: &lnwire.FailTemporaryNodeFailure{},
+	})
+	require.NoError(t, err)
+
+	failResolution := checkFailResolution(
+		t, receiveResolution(t, hodlChan), ResultRejectedByAcceptor,
+	)
+	require.Equal(
+		t, &lnwire.FailTemporaryNodeFailure{},
+		failResolution.FailureMessage,
+	)
+
+	// A second decision about the same htlc is rejected.
+	err = ctx.registry.ResolveExitHtlc(&ExitHtlcResolution{
+		CircuitKey: htlc.CircuitKey,
+		Action:     ExitHtlcResume,
+	})
+	require.ErrorIs(t, err, ErrExitHtlcNotFound)
+
+	// The acceptor settles an htlc for which we don't have an invoice.
+	// A wrong preimage is rejected and keeps the htlc pending.
+	preimage := lntypes.Preimage{9}
+	htlc = notify(1, preimage.Hash(), hodlChan)
+	require.Nil(t, htlc.Invoice)
+
+	err = ctx.registry.ResolveExitHtlc(&ExitHtlcResolution{
+		CircuitKey: htlc.CircuitKey,
+		Action:     ExitHtlcSettle,
+		Preimage:   testInvoicePreimage,
+	})
+	require.ErrorIs(t, err, ErrAcceptorPreimageMismatch)
+
+	err = ctx.registry.ResolveExitHtlc(&ExitHtlcResolution{
+		CircuitKey: htlc.CircuitKey,
+		Action:     ExitHtlcSettle,
+		Preimage:   preimage,
+	})
+	require.NoError(t, err)
+
+	settleResolution := checkSettleResolution(
+		t, receiveResolution(t, hodlChan), preimage,
+	)
+	require.Equal(t, ResultSettledByAcceptor, settleResolution.Outcome)
+
+	// A replayed htlc isn't offered again, but the decision is delivered
+	// to the new hodl channel.
+	htlc = notify(2, testInvoicePaymentHash, hodlChan)
+
+	replayChan := make(chan interface{}, 1)
+	resolution, err := ctx.registry.NotifyExitHopHtlc(
+		testInvoicePaymentHash, testInvoiceAmt, testHtlcExpiry,
+		testCurrentHeight, htlc.CircuitKey, replayChan, testPayload,
+	)
+	require.NoError(t, err)
+	require.Nil(t, resolution)
+	require.Empty(t, offered)
+
+	// The acceptor can't settle an htlc that pays to one of our invoices,
+	// as that would bypass the registry.
+	err = ctx.registry.ResolveExitHtlc(&ExitHtlcResolution{
+		CircuitKey: htlc.CircuitKey,
+		Action:     ExitHtlcSettle,
+		Preimage:   testInvoicePreimage,
+	})
+	require.ErrorIs(t, err, ErrAcceptorSettleInvoice)
+
+	// Resuming the htlc lets the registry settle the invoice.
+	err = ctx.registry.ResolveExitHtlc(&ExitHtlcResolution{
+		CircuitKey: htlc.CircuitKey,
+		Action:     ExitHtlcResume,
+	})
+	require.NoError(t, err)
+
+	settleResolution = checkSettleResolution(
+		t, receiveResolution(t, replayChan), testInvoicePreimage,
+	)
+	require.Equal(t, ResultSettled, settleResolution.Outcome)
+}
+
+// TestExitHtlcAcceptorUnregister tests that pending htlcs are handed to the
+// invoice registry when the htlc acceptor is unregistered.
+func TestExitHtlcAcceptorUnregister(t *testing.T) {
+	ctx := newTestContext(t)
+	defer ctx.cleanup()
+
+	_, err := ctx.registry.AddInvoice(testInvoice, testInvoicePaymentHash)
+	require.NoError(t, err)
+
+	ctx.registry.SetExitHtlcAcceptor(func(*ExitHtlc) error {
+		return nil
+	})
+
+	hodlChan := make(chan interface{}, 1)
+	resolution, err := ctx.registry.NotifyExitHopHtlc(
+		testInvoicePaymentHash, testInvoiceAmt, testHtlcExpiry,
+		testCurrentHeight, getCircuitKey(0), hodlChan, testPayload,
+	)
+	require.NoError(t, err)
+	require.Nil(t, resolution)
+
+	ctx.registry.SetExitHtlcAcceptor(nil)
+
+	settleResolution := checkSettleResolution(
+		t, receiveResolution(t, hodlChan), testInvoicePreimage,
+	)
+	require.Equal(t, ResultSettled, settleResolution.Outcome)
+
+	// Without an acceptor, htlcs are processed right away.
+	resolution, err = ctx.registry.NotifyExitHopHtlc(
+		testInvoicePaymentHash, testInvoiceAmt, testHtlcExpiry,
+		testCurrentHeight, getCircuitKey(1), hodlChan, testPayload,
+	)
+	require.NoError(t, err)
+	settleResolution = checkSettleResolution(
+		t, resolution, testInvoicePreimage,
+	)
+	require.Equal(t, ResultDuplicateToSettled, settleResolution.Outcome)
+}
+
+// TestExitHtlcAcceptorTimeout tests that htlcs that wait for a decision of the
+// htlc acceptor are failed once they get close to their expiry.
+func TestExitHtlcAcceptorTimeout(t *testing.T) {
+	ctx := newTestContext(t)
+	defer ctx.cleanup()
+
+	ctx.registry.SetExitHtlcAcceptor(func(*ExitHtlc) error {
+		return nil
+	})
+
+	hodlChan := make(chan interface{}, 1)
+	preimage := lntypes.Preimage{9}
+	resolution, err := ctx.registry.NotifyExitHopHtlc(
+		preimage.Hash(), testInvoiceAmt, testHtlcExpiry,
+		testCurrentHeight, getCircuitKey(0), hodlChan, testPayload,
+	)
+	require.NoError(t, err)
+	require.Nil(t, resolution)
+
+	// The test registry doesn't use an expiry delta, so the htlc is failed
+	// once we reach its expiry height.
+	select {
+	case ctx.notifier.blockChan <- &chainntnfs.BlockEpoch{
+		Height: int32(testHtlcExpiry),
+	}:
+
+	case <-time.After(testTimeout):
+		t.Fatal("block not consumed")
+	}
+
+	checkFailResolution(
+		t, receiveResolution(t, hodlChan), ResultAcceptorTimeout,
+	)
+
+	// A late decision of the acceptor is rejected.
+	err = ctx.registry.ResolveExitHtlc(&ExitHtlcResolution{
+		CircuitKey: getCircuitKey(0),
+		Action:     ExitHtlcSettle,
+		Preimage:   preimage,
+	})
+	require.ErrorIs(t, err, ErrExitHtlcNotFound)
+}
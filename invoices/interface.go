@@ -18,4 +18,8 @@ type Payload interface {
 	// CustomRecords returns the custom tlv type records that were parsed
 	// from the payload.
 	CustomRecords() record.CustomSet
+
+	// TrampolineOnion returns the trampoline onion parsed from the onion
+	// payload, which asks us to forward the payment as trampoline node.
+	TrampolineOnion() []byte
 }
@@ -58,6 +58,28 @@ func (b invoiceExpiryHeight) expired(currentHeight, delta uint32) bool {
 	return currentHeight+delta >= b.expiryHeight
 }
 
+// Compile time assertion that exitHtlcExpiry implements invoiceExpiry.
+var _ invoiceExpiry = (*exitHtlcExpiry)(nil)
+
+// exitHtlcExpiry holds information about an htlc that waits for a decision of
+// the htlc acceptor, which can be used to fail it based on its expiry height.
+type exitHtlcExpiry struct {
+	circuitKey   channeldb.CircuitKey
+	expiryHeight uint32
+}
+
+// Less implements PriorityQueueItem.Less such that the top item in the
+// priority queue is the lowest block height.
+func (e exitHtlcExpiry) Less(other queue.PriorityQueueItem) bool {
+	return e.expiryHeight < other.(*exitHtlcExpiry).expiryHeight
+}
+
+// expired returns a boolean that indicates whether this entry has expired,
+// taking our expiry delta into account.
+func (e exitHtlcExpiry) expired(currentHeight, delta uint32) bool {
+	return currentHeight+delta >= e.expiryHeight
+}
+
 // InvoiceExpiryWatcher handles automatic invoice cancellation of expried
 // invoices. Upon start InvoiceExpiryWatcher will retrieve all pending (not yet
 // settled or canceled) invoices invoices to its watcing queue. When a new
@@ -91,6 +113,11 @@ type InvoiceExpiryWatcher struct {
 	// cancelInvoice is a template method that cancels an expired invoice.
 	cancelInvoice func(lntypes.Hash, bool) error
 
+	// failExitHtlc is a template method that fails an htlc that still
+	// waits for a decision of the htlc acceptor when it is about to
+	// expire.
+	failExitHtlc func(channeldb.CircuitKey)
+
 	// timestampExpiryQueue holds invoiceExpiry items and is used to find
 	// the next invoice to expire.
 	timestampExpiryQueue queue.PriorityQueue
@@ -104,6 +131,11 @@ type InvoiceExpiryWatcher struct {
 	// active htlcs.
 	blockExpiryQueue queue.PriorityQueue
 
+	// exitHtlcExpiryQueue holds exitHtlcExpiry items and is used to find
+	// the next htlc held for the htlc acceptor that is about to expire.
+	// The same expiry delta as for hold invoices applies.
+	exitHtlcExpiryQueue queue.PriorityQueue
+
 	// newInvoices channel is used to wake up the main loop when a new
 	// invoices is added.
 	newInvoices chan []invoiceExpiry
@@ -133,9 +165,11 @@ func NewInvoiceExpiryWatcher(clock clock.Clock,
 // Start starts the the subscription handler and the main loop. Start() will
 // return with error if InvoiceExpiryWatcher is already started. Start()
 // expects a cancellation function passed that will be use to cancel expired
-// invoices by their payment hash.
+// invoices by their payment hash, and a function that fails expired htlcs held
+// for the htlc acceptor by their circuit key.
 func (ew *InvoiceExpiryWatcher) Start(
-	cancelInvoice func(lntypes.Hash, bool) error) error {
+	cancelInvoice func(lntypes.Hash, bool) error,
+	failExitHtlc func(channeldb.CircuitKey)) error {
 
 	ew.Lock()
 	defer ew.Unlock()
@@ -146,6 +180,7 @@ func (ew *InvoiceExpiryWatcher) Start(
 
 	ew.started = true
 	ew.cancelInvoice = cancelInvoice
+	ew.failExitHtlc = failExitHtlc
 
 	ntfn, err := ew.notifier.RegisterBlockEpochNtfn(&chainntnfs.BlockEpoch{
 		Height: int32(ew.currentHeight),
@@ -274,6 +309,17 @@ func (ew *InvoiceExpiryWatcher) AddInvoices(invoices ...invoiceExpiry) {
 	}
 }
 
+// AddExitHtlc adds an htlc that waits for a decision of the htlc acceptor to
+// the watcher, such that it is failed before it expires.
+func (ew *InvoiceExpiryWatcher) AddExitHtlc(circuitKey channeldb.CircuitKey,
+	expiryHeight uint32) {
+
+	ew.AddInvoices(&exitHtlcExpiry{
+		circuitKey:   circuitKey,
+		expiryHeight: expiryHeight,
+	})
+}
+
 // nextTimestampExpiry returns a Time chan to wait on until the next invoice
 // expires. If there are no active invoices, then it'll simply wait
 // indefinitely.
@@ -303,6 +349,23 @@ func (ew *InvoiceExpiryWatcher) nextHeightExpiry() <-chan uint32 {
 	return blockChan
 }
 
+// nextExitHtlcExpiry returns a channel that will immediately be read from if
+// the top item on our htlc queue has expired.
+func (ew *InvoiceExpiryWatcher) nextExitHtlcExpiry() <-chan uint32 {
+	if ew.exitHtlcExpiryQueue.Empty() {
+		return nil
+	}
+
+	top := ew.exitHtlcExpiryQueue.Top().(*exitHtlcExpiry)
+	if !top.expired(ew.currentHeight, ew.blockExpiryDelta) {
+		return nil
+	}
+
+	blockChan := make(chan uint32, 1)
+	blockChan <- top.expiryHeight
+	return blockChan
+}
+
 // cancelNextExpiredInvoice will cancel the next expired invoice and removes
 // it from the expiry queue.
 func (ew *InvoiceExpiryWatcher) cancelNextExpiredInvoice() {
@@ -342,6 +405,24 @@ func (ew *InvoiceExpiryWatcher) cancelNextHeightExpiredInvoice() {
 	ew.blockExpiryQueue.Pop()
 }
 
+// failNextExpiredExitHtlc looks at our htlc queue and fails the next htlc if
+// we have reached its expiry block.
+func (ew *InvoiceExpiryWatcher) failNextExpiredExitHtlc() {
+	if ew.exitHtlcExpiryQueue.Empty() {
+		return
+	}
+
+	top := ew.exitHtlcExpiryQueue.Top().(*exitHtlcExpiry)
+	if !top.expired(ew.currentHeight, ew.blockExpiryDelta) {
+		return
+	}
+
+	// The htlc may already have been resolved, in which case failing it
+	// is a no-op.
+	ew.failExitHtlc(top.circuitKey)
+	ew.exitHtlcExpiryQueue.Pop()
+}
+
 // expireInvoice attempts to expire an invoice and logs an error if we get an
 // unexpected error.
 func (ew *InvoiceExpiryWatcher) expireInvoice(hash lntypes.Hash, force bool) {
@@ -375,6 +456,11 @@ func (ew *InvoiceExpiryWatcher) pushInvoices(invoices []invoiceExpiry) {
 				ew.blockExpiryQueue.Push(expiry)
 			}
 
+		case *exitHtlcExpiry:
+			if expiry != nil {
+				ew.exitHtlcExpiryQueue.Push(expiry)
+			}
+
 		default:
 			log.Errorf("unexpected queue item: %T", inv)
 		}
@@ -418,6 +504,10 @@ func (ew *InvoiceExpiryWatcher) mainLoop(blockNtfns *chainntnfs.BlockEpochEvent)
 				cancelNext = ew.cancelNextHeightExpiredInvoice
 				continue
 
+			case <-ew.nextExitHtlcExpiry():
+				cancelNext = ew.failNextExpiredExitHtlc
+				continue
+
 			case newInvoices := <-ew.newInvoices:
 				ew.pushInvoices(newInvoices)
 
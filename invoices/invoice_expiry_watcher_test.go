@@ -70,7 +70,7 @@ func newInvoiceExpiryWatcherTest(t *testing.T, now time.Time,
 		)
 		test.wg.Done()
 		return nil
-	})
+	}, nil)
 
 	if err != nil {
 		t.Fatalf("cannot start InvoiceExpiryWatcher: %v", err)
@@ -122,17 +122,17 @@ func TestInvoiceExpiryWatcherStartStop(t *testing.T) {
 		return nil
 	}
 
-	if err := watcher.Start(cancel); err != nil {
+	if err := watcher.Start(cancel, nil); err != nil {
 		t.Fatalf("unexpected error upon start: %v", err)
 	}
 
-	if err := watcher.Start(cancel); err == nil {
+	if err := watcher.Start(cancel, nil); err == nil {
 		t.Fatalf("expected error upon second start")
 	}
 
 	watcher.Stop()
 
-	if err := watcher.Start(cancel); err != nil {
+	if err := watcher.Start(cancel, nil); err != nil {
 		t.Fatalf("unexpected error upon start: %v", err)
 	}
 }
@@ -132,6 +132,28 @@ type InvoiceRegistry struct {
 
 	expiryWatcher *InvoiceExpiryWatcher
 
+	// acceptorMtx guards the exit hop htlc acceptor and the htlcs that
+	// wait for its decision. It must be acquired before the registry lock.
+	acceptorMtx sync.Mutex
+
+	// exitHtlcAcceptor is the currently registered htlc acceptor, or nil
+	// if there is none.
+	exitHtlcAcceptor ExitHtlcAcceptor
+
+	// pendingExitHtlcs are the htlcs that wait for a decision of the htlc
+	// acceptor or the trampoline forwarder.
+	//
+	// NOTE: This map is deliberately kept in memory only. The incoming
+	// link replays every exit hop htlc that hasn't been resolved yet on
+	// startup, at which point it is offered to the acceptor again, or
+	// processed by the registry if no acceptor is registered by then.
+	pendingExitHtlcs map[channeldb.CircuitKey]*pendingExitHtlc
+
+	// trampolineForwarder is the forwarder that handles htlcs that ask us
+	// to forward a payment as trampoline node, or nil if we don't act as
+	// trampoline node.
+	trampolineForwarder TrampolineForwarder
+
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
@@ -155,6 +177,7 @@ func NewRegistry(cdb *channeldb.DB, expiryWatcher *InvoiceExpiryWatcher,
 		cfg:                       cfg,
 		htlcAutoReleaseChan:       make(chan *htlcReleaseEvent),
 		expiryWatcher:             expiryWatcher,
+		pendingExitHtlcs:          make(map[channeldb.CircuitKey]*pendingExitHtlc),
 		quit:                      make(chan struct{}),
 	}
 }
@@ -235,7 +258,9 @@ func (i *InvoiceRegistry) scanInvoicesOnStart() error {
 func (i *InvoiceRegistry) Start() error {
 	// Start InvoiceExpiryWatcher and prepopulate it with existing active
 	// invoices.
-	err := i.expiryWatcher.Start(i.cancelInvoiceImpl)
+	err := i.expiryWatcher.Start(
+		i.cancelInvoiceImpl, i.failExpiredExitHtlc,
+	)
 
 	if err != nil {
 		return err
@@ -874,6 +899,51 @@ func (i *InvoiceRegistry) NotifyExitHopHtlc(rHash lntypes.Hash,
 	circuitKey channeldb.CircuitKey, hodlChan chan<- interface{},
 	payload Payload) (HtlcResolution, error) {
 
+	htlc := &ExitHtlc{
+		CircuitKey:      circuitKey,
+		Hash:            rHash,
+		AmtPaid:         amtPaid,
+		Expiry:          expiry,
+		CurrentHeight:   currentHeight,
+		CustomRecords:   payload.CustomRecords(),
+		MPP:             payload.MultiPath(),
+		TrampolineOnion: payload.TrampolineOnion(),
+	}
+
+	// An htlc that carries a trampoline onion asks us to forward the
+	// payment to the next trampoline node or its recipient.
+	if htlc.TrampolineOnion != nil {
+		return i.forwardTrampolineHtlc(htlc, payload, hodlChan)
+	}
+
+	// If an htlc acceptor is registered, it decides on the htlc before we
+	// process it. The htlc is then held until the decision arrives.
+	intercepted, err := i.interceptExitHtlc(htlc, payload, hodlChan)
+	if err != nil {
+		return nil, err
+	}
+	if intercepted {
+		// Fail the htlc back if the acceptor doesn't decide on it
+		// before it gets close to its expiry. The watcher must not be
+		// called with the acceptor mutex held.
+		i.expiryWatcher.AddExitHtlc(htlc.CircuitKey, htlc.Expiry)
+
+		return nil, nil
+	}
+
+	return i.notifyExitHopHtlc(
+		rHash, amtPaid, expiry, currentHeight, circuitKey, hodlChan,
+		payload,
+	)
+}
+
+// notifyExitHopHtlc processes an exit hop htlc without consulting the htlc
+// acceptor.
+func (i *InvoiceRegistry) notifyExitHopHtlc(rHash lntypes.Hash,
+	amtPaid lnwire.MilliSatoshi, expiry uint32, currentHeight int32,
+	circuitKey channeldb.CircuitKey, hodlChan chan<- interface{},
+	payload Payload) (HtlcResolution, error) {
+
 	// Create the update context containing the relevant details of the
 	// incoming htlc.
 	ctx := invoiceUpdateCtx{
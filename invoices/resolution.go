@@ -5,6 +5,7 @@ import (
 
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
 )
 
 // HtlcResolution describes how an htlc should be resolved.
@@ -25,6 +26,11 @@ type HtlcFailResolution struct {
 
 	// Outcome indicates the outcome of the invoice registry update.
 	Outcome FailResolutionResult
+
+	// FailureMessage is an optional failure message that the htlc should
+	// be failed with. If it is nil, the failure message is derived from
+	// the outcome.
+	FailureMessage lnwire.FailureMessage
 }
 
 // NewFailResolution returns a htlc failure resolution.
@@ -120,6 +120,22 @@ const (
 	// ResultAmpReconstruction is returned when the derived child
 	// hash/preimage pairs were invalid for at least one HTLC in the set.
 	ResultAmpReconstruction
+
+	// ResultRejectedByAcceptor is returned when the exit hop htlc
+	// acceptor decided to fail the htlc.
+	ResultRejectedByAcceptor
+
+	// ResultTrampolineDisabled is returned when we are asked to forward a
+	// payment as trampoline node, but don't act as one.
+	ResultTrampolineDisabled
+
+	// ResultTrampolineFailed is returned when we were unable to forward a
+	// payment as trampoline node.
+	ResultTrampolineFailed
+
+	// ResultAcceptorTimeout is returned when the exit hop htlc acceptor
+	// didn't decide on the htlc before it got close to its expiry.
+	ResultAcceptorTimeout
 )
 
 // String returns a string representation of the result.
@@ -189,6 +205,18 @@ func (f FailResolutionResult) FailureString() string {
 	case ResultAmpReconstruction:
 		return "amp reconstruction failed"
 
+	case ResultRejectedByAcceptor:
+		return "rejected by htlc acceptor"
+
+	case ResultTrampolineDisabled:
+		return "trampoline forwarding disabled"
+
+	case ResultTrampolineFailed:
+		return "trampoline forward failed"
+
+	case ResultAcceptorTimeout:
+		return "htlc acceptor timeout"
+
 	default:
 		return "unknown failure resolution result"
 	}
@@ -228,6 +256,14 @@ const (
 	// ResultDuplicateToSettled is returned when we settle an invoice which
 	// has already been settled at least once.
 	ResultDuplicateToSettled
+
+	// ResultSettledByAcceptor is returned when the exit hop htlc acceptor
+	// settled the htlc with a preimage.
+	ResultSettledByAcceptor
+
+	// ResultTrampolineForwarded is returned when we forwarded a payment
+	// as trampoline node and learned the preimage from the recipient.
+	ResultTrampolineForwarded
 )
 
 // String returns a string representation of the result.
@@ -245,6 +281,12 @@ func (s SettleResolutionResult) String() string {
 	case ResultDuplicateToSettled:
 		return "accepting duplicate payment to settled invoice"
 
+	case ResultSettledByAcceptor:
+		return "settled by htlc acceptor"
+
+	case ResultTrampolineForwarded:
+		return "forwarded as trampoline node"
+
 	default:
 		return "unknown settle resolution result"
 	}
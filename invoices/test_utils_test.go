@@ -12,7 +12,8 @@ import (
 	"testing"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -25,9 +26,10 @@ import (
 )
 
 type mockPayload struct {
-	mpp           *record.MPP
-	amp           *record.AMP
-	customRecords record.CustomSet
+	mpp             *record.MPP
+	amp             *record.AMP
+	customRecords   record.CustomSet
+	trampolineOnion []byte
 }
 
 func (p *mockPayload) MultiPath() *record.MPP {
@@ -48,6 +50,10 @@ func (p *mockPayload) CustomRecords() record.CustomSet {
 	return p.customRecords
 }
 
+func (p *mockPayload) TrampolineOnion() []byte {
+	return p.trampolineOnion
+}
+
 const (
 	testHtlcExpiry = uint32(5)
 
@@ -70,8 +76,7 @@ var (
 	testPrivKeyBytes, _ = hex.DecodeString(
 		"e126f68f7eafcc8b74f54d269fe206be715000f94dac067d1c04a8ca3b2db734")
 
-	testPrivKey, _ = btcec.PrivKeyFromBytes(
-		btcec.S256(), testPrivKeyBytes)
+	testPrivKey, _ = btcec.PrivKeyFromBytes(testPrivKeyBytes)
 
 	testInvoiceDescription = "coffee"
 
@@ -81,7 +86,7 @@ var (
 
 	testMessageSigner = zpay32.MessageSigner{
 		SignCompact: func(hash []byte) ([]byte, error) {
-			sig, err := btcec.SignCompact(btcec.S256(), testPrivKey, hash, true)
+			sig, err := ecdsa.SignCompact(testPrivKey, hash, true)
 			if err != nil {
 				return nil, fmt.Errorf("can't sign the message: %v", err)
 			}
@@ -452,7 +457,7 @@ func setupHodlExpiry(t *testing.T, creationDate time.Time,
 		return nil
 	}
 
-	require.NoError(t, test.watcher.Start(cancelImpl))
+	require.NoError(t, test.watcher.Start(cancelImpl, nil))
 
 	// We set preimage and hash so that we can use our existing test
 	// helpers. In practice we would only have the hash, but this does not
@@ -0,0 +1,83 @@
+package invoices
+
+import (
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+)
+
+// TrampolineForwarder is a function that is invoked for every htlc that asks
+// us to forward a payment as trampoline node. The htlc is held until the
+// forwarder resolves it through ResolveExitHtlc, by either settling it with
+// the preimage it learned from the recipient or failing it.
+type TrampolineForwarder func(*ExitHtlc) error
+
+// SetTrampolineForwarder registers the forwarder that handles all htlcs that
+// ask us to forward a payment as trampoline node. Without a forwarder, those
+// htlcs are failed as if we didn't understand the trampoline onion.
+func (i *InvoiceRegistry) SetTrampolineForwarder(
+	forwarder TrampolineForwarder) {
+
+	i.acceptorMtx.Lock()
+	defer i.acceptorMtx.Unlock()
+
+	i.trampolineForwarder = forwarder
+}
+
+// forwardTrampolineHtlc hands an htlc that carries a trampoline onion to the
+// trampoline forwarder. The htlc never pays to any of our invoices, so it is
+// neither offered to the htlc acceptor nor processed by the registry.
+func (i *InvoiceRegistry) forwardTrampolineHtlc(htlc *ExitHtlc,
+	payload Payload, hodlChan chan<- interface{}) (HtlcResolution, error) {
+
+	i.acceptorMtx.Lock()
+	defer i.acceptorMtx.Unlock()
+
+	// If the htlc is already being forwarded, the incoming link replayed
+	// it after a restart.
+	if i.resubscribePendingHtlc(htlc.CircuitKey, hodlChan) {
+		return nil, nil
+	}
+
+	if i.trampolineForwarder == nil {
+		log.Debugf("Rejecting trampoline htlc %v, trampoline "+
+			"forwarding is disabled", htlc.CircuitKey)
+
+		resolution := NewFailResolution(
+			htlc.CircuitKey, htlc.CurrentHeight,
+			ResultTrampolineDisabled,
+		)
+		resolution.FailureMessage = lnwire.NewInvalidOnionPayload(
+			uint64(record.TrampolineOnionType), 0,
+		)
+
+		return resolution, nil
+	}
+
+	pending := &pendingExitHtlc{
+		htlc:     htlc,
+		payload:  payload,
+		hodlChan: hodlChan,
+	}
+	i.pendingExitHtlcs[htlc.CircuitKey] = pending
+
+	i.Lock()
+	i.hodlSubscribe(hodlChan, htlc.CircuitKey)
+	i.Unlock()
+
+	// If the forwarder can't take the htlc, we fail it right away rather
+	// than holding it until it expires.
+	if err := i.trampolineForwarder(htlc); err != nil {
+		log.Errorf("Unable to forward trampoline htlc %v: %v",
+			htlc.CircuitKey, err)
+
+		delete(i.pendingExitHtlcs, htlc.CircuitKey)
+
+		return nil, i.resolveExitHtlc(pending, &ExitHtlcResolution{
+			CircuitKey:     htlc.CircuitKey,
+			Action:         ExitHtlcFail,
+			FailureMessage: &lnwire.FailTemporaryNodeFailure{},
+		})
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,105 @@
+package invoices
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTrampolineForwarder tests that htlcs that carry a trampoline onion are
+// handed to the trampoline forwarder instead of the htlc acceptor.
+func TestTrampolineForwarder(t *testing.T) {
+	ctx := newTestContext(t)
+	defer ctx.cleanup()
+
+	payload := &mockPayload{trampolineOnion: []byte{1, 2, 3}}
+	preimage := lntypes.Preimage{7}
+
+	notify := func(htlcID uint64,
+		hodlChan chan interface{}) HtlcResolution {
+
+		resolution, err := ctx.registry.NotifyExitHopHtlc(
+			preimage.Hash(), testInvoiceAmt, testHtlcExpiry,
+			testCurrentHeight, getCircuitKey(htlcID), hodlChan,
+			payload,
+		)
+		require.NoError(t, err)
+
+		return resolution
+	}
+
+	// Without a forwarder, the trampoline onion is rejected.
+	hodlChan := make(chan interface{}, 1)
+	failResolution := checkFailResolution(
+		t, notify(0, hodlChan), ResultTrampolineDisabled,
+	)
+	require.Equal(
+		t, lnwire.NewInvalidOnionPayload(
+			uint64(record.TrampolineOnionType), 0,
+		), failResolution.FailureMessage,
+	)
+
+	// The htlc acceptor isn't consulted about trampoline htlcs, and isn't
+	// able to resume them.
+	ctx.registry.SetExitHtlcAcceptor(func(*ExitHtlc) error {
+		t.Fatal("trampoline htlc offered to acceptor")
+		return nil
+	})
+
+	forwarded := make(chan *ExitHtlc, 1)
+	ctx.registry.SetTrampolineForwarder(func(htlc *ExitHtlc) error {
+		forwarded <- htlc
+		return nil
+	})
+
+	require.Nil(t, notify(1, hodlChan))
+
+	var htlc *ExitHtlc
+	select {
+	case htlc = <-forwarded:
+	case <-time.After(testTimeout):
+		t.Fatal("htlc not forwarded")
+	}
+	require.Equal(t, payload.trampolineOnion, htlc.TrampolineOnion)
+
+	ctx.registry.SetExitHtlcAcceptor(nil)
+	require.Empty(t, hodlChan)
+
+	// A replayed htlc isn't forwarded again.
+	replayChan := make(chan interface{}, 1)
+	require.Nil(t, notify(1, replayChan))
+	require.Empty(t, forwarded)
+
+	err := ctx.registry.ResolveExitHtlc(&ExitHtlcResolution{
+		CircuitKey: htlc.CircuitKey,
+		Action:     ExitHtlcSettle,
+		Preimage:   preimage,
+	})
+	require.NoError(t, err)
+
+	settleResolution := checkSettleResolution(
+		t, receiveResolution(t, replayChan), preimage,
+	)
+	require.Equal(t, ResultTrampolineForwarded, settleResolution.Outcome)
+
+	// If the forwarder is unable to take the htlc, it is failed right
+	// away.
+	ctx.registry.SetTrampolineForwarder(func(*ExitHtlc) error {
+		return errors.New("shutting down")
+	})
+
+	failChan := make(chan interface{}, 1)
+	require.Nil(t, notify(2, failChan))
+	failResolution = checkFailResolution(
+		t, receiveResolution(t, failChan), ResultTrampolineFailed,
+	)
+	require.Equal(
+		t, &lnwire.FailTemporaryNodeFailure{},
+		failResolution.FailureMessage,
+	)
+}
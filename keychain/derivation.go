@@ -3,7 +3,8 @@ package keychain
 import (
 	"fmt"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
 )
 
 const (
@@ -178,7 +179,7 @@ type SecretKeyRing interface {
 
 	ECDHRing
 
-	DigestSignerRing
+	MessageSignerRing
 
 	// DerivePrivKey attempts to derive the private key that corresponds to
 	// the passed key descriptor.  If the public key is set, then this
@@ -188,35 +189,41 @@ type SecretKeyRing interface {
 	DerivePrivKey(keyDesc KeyDescriptor) (*btcec.PrivateKey, error)
 }
 
-// DigestSignerRing is an interface that abstracts away basic low-level ECDSA
+// MessageSignerRing is an interface that abstracts away basic low-level ECDSA
 // signing on keys within a key ring.
-type DigestSignerRing interface {
-	// SignDigest signs the given SHA256 message digest with the private key
-	// described in the key descriptor.
-	SignDigest(keyDesc KeyDescriptor, digest [32]byte) (*btcec.Signature,
-		error)
-
-	// SignDigestCompact signs the given SHA256 message digest with the
-	// private key described in the key descriptor and returns the signature
-	// in the compact, public key recoverable format.
-	SignDigestCompact(keyDesc KeyDescriptor, digest [32]byte) ([]byte, error)
+type MessageSignerRing interface {
+	// SignMessage signs the given message, single or double SHA256 hashing
+	// it first, with the private key described in the key locator.
+	SignMessage(keyLoc KeyLocator, msg []byte,
+		doubleHash bool) (*ecdsa.Signature, error)
+
+	// SignMessageCompact signs the given message, single or double SHA256
+	// hashing it first, with the private key described in the key locator
+	// and returns the signature in the compact, public key recoverable
+	// format.
+	SignMessageCompact(keyLoc KeyLocator, msg []byte,
+		doubleHash bool) ([]byte, error)
 }
 
-// SingleKeyDigestSigner is an abstraction interface that hides the
+// SingleKeyMessageSigner is an abstraction interface that hides the
 // implementation of the low-level ECDSA signing operations by wrapping a
 // single, specific private key.
-type SingleKeyDigestSigner interface {
+type SingleKeyMessageSigner interface {
 	// PubKey returns the public key of the wrapped private key.
 	PubKey() *btcec.PublicKey
 
-	// SignDigest signs the given SHA256 message digest with the wrapped
-	// private key.
-	SignDigest(digest [32]byte) (*btcec.Signature, error)
+	// KeyLocator returns the locator that describes the wrapped private
+	// key.
+	KeyLocator() KeyLocator
+
+	// SignMessage signs the given message, single or double SHA256 hashing
+	// it first, with the wrapped private key.
+	SignMessage(message []byte, doubleHash bool) (*ecdsa.Signature, error)
 
-	// SignDigestCompact signs the given SHA256 message digest with the
-	// wrapped private key and returns the signature in the compact, public
-	// key recoverable format.
-	SignDigestCompact(digest [32]byte) ([]byte, error)
+	// SignMessageCompact signs the given message, single or double SHA256
+	// hashing it first, with the wrapped private key and returns the
+	// signature in the compact, public key recoverable format.
+	SignMessageCompact(message []byte, doubleHash bool) ([]byte, error)
 }
 
 // ECDHRing is an interface that abstracts away basic low-level ECDH shared key
@@ -1,56 +1,94 @@
 package keychain
 
-import "github.com/btcsuite/btcd/btcec"
-
-func NewPubKeyDigestSigner(keyDesc KeyDescriptor,
-	signer DigestSignerRing) *PubKeyDigestSigner {
-
-	return &PubKeyDigestSigner{
-		keyDesc:      keyDesc,
-		digestSigner: signer,
+import (
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+func NewPubKeyMessageSigner(keyDesc KeyDescriptor,
+	signer MessageSignerRing) *PubKeyMessageSigner {
+
+	return &PubKeyMessageSigner{
+		keyDesc:       keyDesc,
+		messageSigner: signer,
 	}
 }
 
-type PubKeyDigestSigner struct {
-	keyDesc      KeyDescriptor
-	digestSigner DigestSignerRing
+type PubKeyMessageSigner struct {
+	keyDesc       KeyDescriptor
+	messageSigner MessageSignerRing
 }
 
-func (p *PubKeyDigestSigner) PubKey() *btcec.PublicKey {
+func (p *PubKeyMessageSigner) PubKey() *btcec.PublicKey {
 	return p.keyDesc.PubKey
 }
 
-func (p *PubKeyDigestSigner) SignDigest(digest [32]byte) (*btcec.Signature,
-	error) {
+func (p *PubKeyMessageSigner) KeyLocator() KeyLocator {
+	return p.keyDesc.KeyLocator
+}
+
+func (p *PubKeyMessageSigner) SignMessage(message []byte,
+	doubleHash bool) (*ecdsa.Signature, error) {
+
+	return p.messageSigner.SignMessage(
+		p.keyDesc.KeyLocator, message, doubleHash,
+	)
+}
+
+func (p *PubKeyMessageSigner) SignMessageCompact(msg []byte,
+	doubleHash bool) ([]byte, error) {
 
-	return p.digestSigner.SignDigest(p.keyDesc, digest)
+	return p.messageSigner.SignMessageCompact(
+		p.keyDesc.KeyLocator, msg, doubleHash,
+	)
 }
 
-func (p *PubKeyDigestSigner) SignDigestCompact(digest [32]byte) ([]byte,
-	error) {
+func NewPrivKeyMessageSigner(privKey *btcec.PrivateKey,
+	keyLoc KeyLocator) *PrivKeyMessageSigner {
+
+	return &PrivKeyMessageSigner{
+		privKey: privKey,
+		keyLoc:  keyLoc,
+	}
+}
 
-	return p.digestSigner.SignDigestCompact(p.keyDesc, digest)
+type PrivKeyMessageSigner struct {
+	keyLoc  KeyLocator
+	privKey *btcec.PrivateKey
 }
 
-type PrivKeyDigestSigner struct {
-	PrivKey *btcec.PrivateKey
+func (p *PrivKeyMessageSigner) PubKey() *btcec.PublicKey {
+	return p.privKey.PubKey()
 }
 
-func (p *PrivKeyDigestSigner) PubKey() *btcec.PublicKey {
-	return p.PrivKey.PubKey()
+func (p *PrivKeyMessageSigner) KeyLocator() KeyLocator {
+	return p.keyLoc
 }
 
-func (p *PrivKeyDigestSigner) SignDigest(digest [32]byte) (*btcec.Signature,
-	error) {
+func (p *PrivKeyMessageSigner) SignMessage(msg []byte,
+	doubleHash bool) (*ecdsa.Signature, error) {
 
-	return p.PrivKey.Sign(digest[:])
+	var digest []byte
+	if doubleHash {
+		digest = chainhash.DoubleHashB(msg)
+	} else {
+		digest = chainhash.HashB(msg)
+	}
+	return ecdsa.Sign(p.privKey, digest), nil
 }
 
-func (p *PrivKeyDigestSigner) SignDigestCompact(digest [32]byte) ([]byte,
-	error) {
+func (p *PrivKeyMessageSigner) SignMessageCompact(msg []byte,
+	doubleHash bool) ([]byte, error) {
 
-	return btcec.SignCompact(btcec.S256(), p.PrivKey, digest[:], true)
+	var digest []byte
+	if doubleHash {
+		digest = chainhash.DoubleHashB(msg)
+	} else {
+		digest = chainhash.HashB(msg)
+	}
+	return ecdsa.SignCompact(p.privKey, digest, true)
 }
 
-var _ SingleKeyDigestSigner = (*PubKeyDigestSigner)(nil)
-var _ SingleKeyDigestSigner = (*PrivKeyDigestSigner)(nil)
+var _ SingleKeyMessageSigner = (*PubKeyMessageSigner)(nil)
+var _ SingleKeyMessageSigner = (*PrivKeyMessageSigner)(nil)
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	_ "github.com/btcsuite/btcwallet/walletdb/bdb" // Import to register backend.
+	"github.com/lightningnetwork/lnd/kvdb/sqlite"
 )
 
 const (
@@ -235,12 +236,12 @@ func updateLastCompactionDate(dbFile string) error {
 	return ioutil.WriteFile(tsFile, tsBytes[:], 0600)
 }
 
-// GetTestBackend opens (or creates if doesn't exist) a bbolt or etcd
+// GetTestBackend opens (or creates if doesn't exist) a bbolt, etcd or sqlite
 // backed database (for testing), and returns a kvdb.Backend and a cleanup
-// func. Whether to create/open bbolt or embedded etcd database is based
-// on the TestBackend constant which is conditionally compiled with build tag.
-// The passed path is used to hold all db files, while the name is only used
-// for bbolt.
+// func. Whether to create/open bbolt, embedded etcd or sqlite database is
+// based on the TestBackend constant which is conditionally compiled with build
+// tag. The passed path is used to hold all db files, while the name is only
+// used for bbolt and sqlite.
 func GetTestBackend(path, name string) (Backend, func(), error) {
 	empty := func() {}
 
@@ -264,6 +265,13 @@ func GetTestBackend(path, name string) (Backend, func(), error) {
 			EtcdBackendName, context.TODO(), etcdConfig,
 		)
 		return backend, cancel, err
+	} else if TestBackend == SqliteBackendName {
+		backend, err := Open(
+			SqliteBackendName, context.TODO(), &sqlite.Config{
+				BusyTimeout: DefaultDBTimeout,
+			}, path, name, "test",
+		)
+		return backend, empty, err
 	}
 
 	return nil, nil, fmt.Errorf("unknown backend")
@@ -0,0 +1,8 @@
+// +build !kvdb_etcd,!kvdb_sqlite
+
+package kvdb
+
+// TestBackend is conditionally set to bdb when neither the kvdb_etcd nor the
+// kvdb_sqlite build tag is defined, allowing testing our database code with
+// bolt backend.
+const TestBackend = BoltBackendName
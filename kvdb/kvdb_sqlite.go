@@ -0,0 +1,7 @@
+// +build kvdb_sqlite
+
+package kvdb
+
+// TestBackend is conditionally set to sqlite when the kvdb_sqlite build tag is
+// defined, allowing testing our database code with the sqlite backend.
+const TestBackend = SqliteBackendName
@@ -0,0 +1,44 @@
+// +build kvdb_postgres
+
+package postgres
+
+import (
+	"context"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	_ "github.com/jackc/pgx/v4/stdlib" // Import to register the driver.
+	"github.com/lightningnetwork/lnd/kvdb/sqlbase"
+)
+
+const (
+	// driverName is the name of the database/sql driver we use to talk to
+	// postgres.
+	driverName = "pgx"
+
+	// schema is the postgres schema all kv tables are created in.
+	schema = "public"
+)
+
+// postgresReplacements are the postgres specific types that replace the
+// generic placeholders of the kv table schema.
+var postgresReplacements = map[string]string{
+	"BLOB":      "BYTEA",
+	"ID_COLUMN": "BIGSERIAL PRIMARY KEY",
+}
+
+// newPostgresBackend returns a db object initialized with the passed backend
+// config. The prefix is used as the table name prefix which allows multiple
+// namespaces to share the same database.
+func newPostgresBackend(ctx context.Context, config *Config, prefix string) (
+	walletdb.DB, error) {
+
+	return sqlbase.NewSqlBackend(ctx, &sqlbase.Config{
+		DriverName:      driverName,
+		Dsn:             config.Dsn,
+		Timeout:         config.Timeout,
+		Schema:          schema,
+		TableNamePrefix: prefix,
+		MaxConnections:  config.MaxConnections,
+		Replacements:    postgresReplacements,
+	})
+}
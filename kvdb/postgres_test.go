@@ -0,0 +1,107 @@
+// +build kvdb_postgres
+
+package kvdb
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/kvdb/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMain starts the embedded postgres instance that all tests of the package
+// create their databases on.
+func TestMain(m *testing.M) {
+	stop, err := postgres.StartEmbeddedPostgres()
+	if err != nil {
+		fmt.Printf("unable to start embedded postgres: %v\n", err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	if err := stop(); err != nil {
+		fmt.Printf("unable to stop embedded postgres: %v\n", err)
+	}
+
+	os.Exit(code)
+}
+
+// TestPostgres runs the kvdb bucket, cursor and transaction test suites
+// against the postgres backend, each on its own database.
+func TestPostgres(t *testing.T) {
+	tests := []struct {
+		name string
+		test func(*testing.T, walletdb.DB)
+	}{
+		{
+			name: "read cursor empty interval",
+			test: testReadCursorEmptyInterval,
+		},
+		{
+			name: "read cursor non empty interval",
+			test: testReadCursorNonEmptyInterval,
+		},
+		{
+			name: "read write cursor",
+			test: testReadWriteCursor,
+		},
+		{
+			name: "read write cursor with bucket and value",
+			test: testReadWriteCursorWithBucketAndValue,
+		},
+		{
+			name: "bucket creation",
+			test: testBucketCreation,
+		},
+		{
+			name: "bucket deletion",
+			test: testBucketDeletion,
+		},
+		{
+			name: "bucket for each",
+			test: testBucketForEach,
+		},
+		{
+			name: "bucket for each with error",
+			test: testBucketForEachWithError,
+		},
+		{
+			name: "bucket sequence",
+			test: testBucketSequence,
+		},
+		{
+			name: "key clash",
+			test: testKeyClash,
+		},
+		{
+			name: "bucket create delete",
+			test: testBucketCreateDelete,
+		},
+		{
+			name: "tx manual commit",
+			test: testTxManualCommit,
+		},
+		{
+			name: "tx rollback",
+			test: testTxRollback,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			f, err := postgres.NewFixture()
+			require.NoError(t, err)
+			defer f.Db.Close()
+
+			test.test(t, f.Db)
+		})
+	}
+}
@@ -0,0 +1,258 @@
+// +build kvdb_postgres kvdb_sqlite
+
+package sqlbase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+const (
+	// kvTableName is the name of the table that will contain all the kv
+	// pairs. It is prefixed with the namespace of the backend.
+	kvTableName = "kv"
+)
+
+// Config holds a set of configuration options of a sql database connection
+// that are common to all sql based backends.
+type Config struct {
+	// DriverName is the name of the database/sql driver to use, for
+	// example "pgx" or "sqlite".
+	DriverName string
+
+	// Dsn is the data source name passed to the driver when opening the
+	// database.
+	Dsn string
+
+	// Timeout is the time after which a single transaction will be
+	// aborted. Setting it to zero disables the timeout.
+	Timeout time.Duration
+
+	// Schema is the optional schema the kv table lives in. If it is set,
+	// the schema is created if it doesn't exist yet.
+	Schema string
+
+	// TableNamePrefix is the prefix used for the kv table name. This allows
+	// multiple logical databases (namespaces) to share the same sql
+	// database.
+	TableNamePrefix string
+
+	// MaxConnections is the maximum number of open connections to the
+	// database. Zero means unlimited.
+	MaxConnections int
+
+	// Replacements maps the sql type placeholders used in the table schema
+	// to the actual sql types of the backend. The placeholders are
+	// ID_COLUMN for the auto incremented primary key and BLOB for binary
+	// columns.
+	Replacements map[string]string
+}
+
+// db holds a reference to the sql db connection.
+type db struct {
+	// cfg is the sql db connection config.
+	cfg *Config
+
+	// ctx is the overall context for the database driver.
+	ctx context.Context
+
+	// db is the underlying database connection instance.
+	db *sql.DB
+
+	// lock is the global lock that ensures a single writer. Readers hold
+	// the read lock so they never observe a partially applied write.
+	lock sync.RWMutex
+
+	// table is the (possibly schema qualified) name of the table that
+	// holds all buckets and key/value pairs of this backend.
+	table string
+}
+
+// Enforce db implements the walletdb.DB interface.
+var _ walletdb.DB = (*db)(nil)
+
+// NewSqlBackend returns a db object initialized with the passed backend
+// config. If the database connection cannot be established or the schema
+// cannot be created, an error is returned.
+func NewSqlBackend(ctx context.Context, cfg *Config) (walletdb.DB, error) {
+	if cfg.TableNamePrefix == "" {
+		return nil, errors.New("empty table name prefix")
+	}
+
+	table := fmt.Sprintf("%s_%s", cfg.TableNamePrefix, kvTableName)
+	if cfg.Schema != "" {
+		table = fmt.Sprintf("%s.%s", cfg.Schema, table)
+	}
+
+	dbConn, err := sql.Open(cfg.DriverName, cfg.Dsn)
+	if err != nil {
+		return nil, err
+	}
+	dbConn.SetMaxOpenConns(cfg.MaxConnections)
+
+	query := schemaQuery(cfg.Schema, table, cfg.Replacements)
+	if _, err := dbConn.ExecContext(ctx, query); err != nil {
+		_ = dbConn.Close()
+
+		return nil, fmt.Errorf("unable to create kv table %v: %v",
+			table, err)
+	}
+
+	return &db{
+		cfg:   cfg,
+		ctx:   ctx,
+		db:    dbConn,
+		table: table,
+	}, nil
+}
+
+// schemaQuery returns the statements needed to create the kv table and its
+// indexes. Buckets are rows with a NULL value, top level buckets are rows
+// with a NULL parent_id.
+func schemaQuery(schema, table string, replacements map[string]string) string {
+	// Index and constraint names must not be schema qualified, so we
+	// derive them from the table name with the dot removed.
+	indexPrefix := strings.Replace(table, ".", "_", -1)
+
+	query := `
+CREATE TABLE IF NOT EXISTS TABLE_NAME
+(
+    key BLOB NOT NULL,
+    value BLOB,
+    parent_id BIGINT,
+    id ID_COLUMN,
+    sequence BIGINT,
+    CONSTRAINT INDEX_PREFIX_parent FOREIGN KEY (parent_id)
+        REFERENCES TABLE_NAME (id)
+        ON UPDATE NO ACTION
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS INDEX_PREFIX_p
+    ON TABLE_NAME (parent_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS INDEX_PREFIX_up
+    ON TABLE_NAME (parent_id, key) WHERE parent_id IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS INDEX_PREFIX_unp
+    ON TABLE_NAME (key) WHERE parent_id IS NULL;
+`
+	if schema != "" {
+		query = fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s;\n%s",
+			schema, query)
+	}
+
+	for from, to := range replacements {
+		query = strings.Replace(query, from, to, -1)
+	}
+	query = strings.Replace(query, "INDEX_PREFIX", indexPrefix, -1)
+
+	return strings.Replace(query, "TABLE_NAME", table, -1)
+}
+
+// getTimeoutCtx gets a timeout context for database requests.
+func (db *db) getTimeoutCtx() (context.Context, func()) {
+	if db.cfg.Timeout == time.Duration(0) {
+		return db.ctx, func() {}
+	}
+
+	return context.WithTimeout(db.ctx, db.cfg.Timeout)
+}
+
+// View opens a database read transaction and executes the function f with the
+// transaction passed as a parameter. After f exits, the transaction is rolled
+// back. If f errors, its error is returned, not a rollback error (if any
+// occur). The passed reset function is called before the start of the
+// transaction and can be used to reset intermediate state. As callers may
+// expect retries of the f closure (depending on the database backend used), the
+// reset function will be called before each retry respectively.
+func (db *db) View(f func(tx walletdb.ReadTx) error, reset func()) error {
+	return db.executeTransaction(
+		func(tx walletdb.ReadWriteTx) error {
+			return f(tx.(walletdb.ReadTx))
+		},
+		reset, true,
+	)
+}
+
+// Update opens a database read/write transaction and executes the function f
+// with the transaction passed as a parameter. After f exits, if f did not
+// error, the transaction is committed. Otherwise, if f did error, the
+// transaction is rolled back. If the rollback fails, the original error
+// returned by f is still returned. If the commit fails, the commit error is
+// returned. As callers may expect retries of the f closure, the reset function
+// will be called before each retry respectively.
+func (db *db) Update(f func(tx walletdb.ReadWriteTx) error, reset func()) error {
+	return db.executeTransaction(f, reset, false)
+}
+
+// executeTransaction creates a new read-only or read-write transaction and
+// executes the given function within it. Read-only transactions are always
+// rolled back, read-write transactions are committed if f succeeds.
+func (db *db) executeTransaction(f func(tx walletdb.ReadWriteTx) error,
+	reset func(), readOnly bool) error {
+
+	reset()
+
+	tx, err := newReadWriteTx(db, readOnly)
+	if err != nil {
+		return err
+	}
+
+	// Make sure the transaction and its lock are released if f panics.
+	defer func() {
+		if r := recover(); r != nil {
+			_ = tx.Rollback()
+			panic(r)
+		}
+	}()
+
+	if err := f(tx); err != nil {
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	if readOnly {
+		return tx.Rollback()
+	}
+
+	return tx.Commit()
+}
+
+// PrintStats returns all collected stats pretty printed into a string.
+func (db *db) PrintStats() string {
+	return "stats not supported by sql driver"
+}
+
+// BeginReadWriteTx opens a database read+write transaction.
+func (db *db) BeginReadWriteTx() (walletdb.ReadWriteTx, error) {
+	return newReadWriteTx(db, false)
+}
+
+// BeginReadTx opens a database read transaction.
+func (db *db) BeginReadTx() (walletdb.ReadTx, error) {
+	return newReadWriteTx(db, true)
+}
+
+// Copy writes a copy of the database to the provided writer. This is not
+// supported by the sql backends, a native database dump should be used
+// instead.
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) Copy(w io.Writer) error {
+	return errors.New("copy is not supported by sql backends")
+}
+
+// Close cleanly shuts down the database and syncs all data.
+// This function is part of the walletdb.Db interface implementation.
+func (db *db) Close() error {
+	return db.db.Close()
+}
@@ -0,0 +1,403 @@
+// +build kvdb_postgres kvdb_sqlite
+
+package sqlbase
+
+import (
+	"database/sql"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+const (
+	// kvColumns is the list of columns we select whenever we read a key
+	// and its value. Buckets are stored with a NULL value, but not all sql
+	// drivers can tell a NULL apart from an empty value, so we explicitly
+	// select whether the row is a bucket.
+	kvColumns = "key, value, value IS NULL"
+)
+
+// readWriteBucket stores the bucket id and the buckets transaction.
+type readWriteBucket struct {
+	// id is used to identify the bucket. It is the primary key of the
+	// bucket's row and the parent id of all its nested keys and buckets.
+	id int64
+
+	// tx holds the parent transaction.
+	tx *readWriteTx
+}
+
+// newReadWriteBucket creates a new rw bucket with the passed transaction and
+// bucket id.
+func newReadWriteBucket(tx *readWriteTx, id int64) *readWriteBucket {
+	return &readWriteBucket{
+		id: id,
+		tx: tx,
+	}
+}
+
+// rowScanner is the common interface of sql.Row and sql.Rows used to scan a
+// single result row.
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanKV scans a single row that was selected using kvColumns. Nested buckets
+// are returned with a nil value, key/value pairs always with a non-nil value.
+func scanKV(row rowScanner) ([]byte, []byte, error) {
+	var (
+		key, value []byte
+		isBucket   bool
+	)
+	if err := row.Scan(&key, &value, &isBucket); err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case isBucket:
+		value = nil
+
+	case value == nil:
+		value = []byte{}
+	}
+
+	return key, value, nil
+}
+
+// NestedReadBucket retrieves a nested read bucket with the given key.
+// Returns nil if the bucket does not exist.
+func (b *readWriteBucket) NestedReadBucket(key []byte) walletdb.ReadBucket {
+	bucket := b.NestedReadWriteBucket(key)
+	if bucket == nil {
+		return nil
+	}
+
+	return bucket.(walletdb.ReadBucket)
+}
+
+// ForEach invokes the passed function with every key/value pair in the
+// bucket. This includes nested buckets, in which case the value is nil, but
+// it does not include the key/value pairs within those nested buckets.
+func (b *readWriteBucket) ForEach(cb func(k, v []byte) error) error {
+	rows, err := b.tx.query(
+		"SELECT "+kvColumns+" FROM "+b.table()+" WHERE "+
+			"parent_id=$1 ORDER BY key", b.id,
+	)
+	if err != nil {
+		return err
+	}
+
+	// The rows must be fully read before we can invoke the callback since
+	// it may issue new queries within the same transaction.
+	var keys, values [][]byte
+	for rows.Next() {
+		key, value, err := scanKV(rows)
+		if err != nil {
+			_ = rows.Close()
+			return err
+		}
+
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	if err := closeRows(rows); err != nil {
+		return err
+	}
+
+	for i, key := range keys {
+		if err := cb(key, values[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get returns the value for the given key. Returns nil if the key does not
+// exist in this bucket.
+func (b *readWriteBucket) Get(key []byte) []byte {
+	// Return nil if the key is empty.
+	if len(key) == 0 {
+		return nil
+	}
+
+	_, value, err := scanKV(b.tx.queryRow(
+		"SELECT "+kvColumns+" FROM "+b.table()+" WHERE "+
+			"parent_id=$1 AND key=$2", b.id, key,
+	))
+	if err != nil {
+		// TODO: we should return the error once the kvdb interface is
+		// extended.
+		return nil
+	}
+
+	return value
+}
+
+// ReadCursor returns a new read-only cursor for this bucket.
+func (b *readWriteBucket) ReadCursor() walletdb.ReadCursor {
+	return newReadWriteCursor(b)
+}
+
+// NestedReadWriteBucket retrieves a nested bucket with the given key.
+// Returns nil if the bucket does not exist.
+func (b *readWriteBucket) NestedReadWriteBucket(
+	key []byte) walletdb.ReadWriteBucket {
+
+	if len(key) == 0 {
+		return nil
+	}
+
+	id, isBucket, err := b.lookup(key)
+	if err != nil || !isBucket {
+		// TODO: we should return the error once the kvdb interface is
+		// extended.
+		return nil
+	}
+
+	return newReadWriteBucket(b.tx, id)
+}
+
+// lookup returns the row id of the given key and whether it is a nested
+// bucket. If the key doesn't exist, sql.ErrNoRows is returned.
+func (b *readWriteBucket) lookup(key []byte) (int64, bool, error) {
+	var (
+		id       int64
+		isBucket bool
+	)
+	err := b.tx.queryRow(
+		"SELECT id, value IS NULL FROM "+b.table()+" WHERE "+
+			"parent_id=$1 AND key=$2", b.id, key,
+	).Scan(&id, &isBucket)
+
+	return id, isBucket, err
+}
+
+// CreateBucket creates and returns a new nested bucket with the given key.
+// Returns ErrBucketExists if the bucket already exists, ErrBucketNameRequired
+// if the key is empty, or ErrIncompatibleValue if the key value is otherwise
+// invalid for the particular database implementation. Other errors are
+// possible depending on the implementation.
+func (b *readWriteBucket) CreateBucket(key []byte) (
+	walletdb.ReadWriteBucket, error) {
+
+	if len(key) == 0 {
+		return nil, walletdb.ErrBucketNameRequired
+	}
+
+	_, isBucket, err := b.lookup(key)
+	switch {
+	case err == sql.ErrNoRows:
+
+	case err != nil:
+		return nil, err
+
+	case isBucket:
+		return nil, walletdb.ErrBucketExists
+
+	default:
+		return nil, walletdb.ErrIncompatibleValue
+	}
+
+	id, err := b.tx.insertBucket(&b.id, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return newReadWriteBucket(b.tx, id), nil
+}
+
+// CreateBucketIfNotExists creates and returns a new nested bucket with the
+// given key if it does not already exist. Returns ErrBucketNameRequired if
+// the key is empty or ErrIncompatibleValue if the key value is otherwise
+// invalid for the particular database backend. Other errors are possible
+// depending on the implementation.
+func (b *readWriteBucket) CreateBucketIfNotExists(key []byte) (
+	walletdb.ReadWriteBucket, error) {
+
+	if len(key) == 0 {
+		return nil, walletdb.ErrBucketNameRequired
+	}
+
+	id, isBucket, err := b.lookup(key)
+	switch {
+	case err == sql.ErrNoRows:
+
+	case err != nil:
+		return nil, err
+
+	case isBucket:
+		return newReadWriteBucket(b.tx, id), nil
+
+	default:
+		return nil, walletdb.ErrIncompatibleValue
+	}
+
+	id, err = b.tx.insertBucket(&b.id, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return newReadWriteBucket(b.tx, id), nil
+}
+
+// DeleteNestedBucket deletes the nested bucket and its sub-buckets pointed to
+// by the passed key. All values in the bucket and sub-buckets will be deleted
+// as well.
+func (b *readWriteBucket) DeleteNestedBucket(key []byte) error {
+	if len(key) == 0 {
+		return walletdb.ErrIncompatibleValue
+	}
+
+	id, isBucket, err := b.lookup(key)
+	switch {
+	case err == sql.ErrNoRows:
+		return walletdb.ErrBucketNotFound
+
+	case err != nil:
+		return err
+
+	case !isBucket:
+		return walletdb.ErrIncompatibleValue
+	}
+
+	return b.tx.deleteBucket(id)
+}
+
+// Put updates the value for the passed key.
+// Returns ErrKeyRequired if the passed key is empty.
+func (b *readWriteBucket) Put(key, value []byte) error {
+	if len(key) == 0 {
+		return walletdb.ErrKeyRequired
+	}
+
+	// A nil value would be stored as NULL which marks a bucket, so we
+	// store an empty value instead.
+	if value == nil {
+		value = []byte{}
+	}
+
+	// Insert the value or update it if the key already exists. The update
+	// is skipped if the key belongs to a nested bucket, which we detect
+	// through the number of affected rows.
+	result, err := b.tx.exec(
+		"INSERT INTO "+b.table()+" AS kv (key, value, parent_id) "+
+			"VALUES($1, $2, $3) ON CONFLICT (parent_id, key) "+
+			"WHERE parent_id IS NOT NULL DO UPDATE SET "+
+			"value=excluded.value WHERE kv.value IS NOT NULL",
+		key, value, b.id,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows != 1 {
+		return walletdb.ErrIncompatibleValue
+	}
+
+	return nil
+}
+
+// Delete deletes the key/value pointed to by the passed key.
+// Returns ErrKeyRequired if the passed key is empty.
+func (b *readWriteBucket) Delete(key []byte) error {
+	if key == nil {
+		return nil
+	}
+	if len(key) == 0 {
+		return walletdb.ErrKeyRequired
+	}
+
+	result, err := b.tx.exec(
+		"DELETE FROM "+b.table()+" WHERE parent_id=$1 AND key=$2 "+
+			"AND value IS NOT NULL", b.id, key,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 1 {
+		return nil
+	}
+
+	// Nothing was deleted, which is fine unless the key belongs to a
+	// nested bucket.
+	_, isBucket, err := b.lookup(key)
+	switch {
+	case err == sql.ErrNoRows:
+		return nil
+
+	case err != nil:
+		return err
+
+	case isBucket:
+		return walletdb.ErrIncompatibleValue
+	}
+
+	return nil
+}
+
+// ReadWriteCursor returns a new read-write cursor for this bucket.
+func (b *readWriteBucket) ReadWriteCursor() walletdb.ReadWriteCursor {
+	return newReadWriteCursor(b)
+}
+
+// Tx returns the buckets transaction.
+func (b *readWriteBucket) Tx() walletdb.ReadWriteTx {
+	return b.tx
+}
+
+// NextSequence returns an autoincrementing sequence number for this bucket.
+// Note that this is not a thread safe function and as such it must not be used
+// for synchronization.
+func (b *readWriteBucket) NextSequence() (uint64, error) {
+	seq := b.Sequence() + 1
+
+	return seq, b.SetSequence(seq)
+}
+
+// SetSequence updates the sequence number for the bucket.
+func (b *readWriteBucket) SetSequence(v uint64) error {
+	_, err := b.tx.exec(
+		"UPDATE "+b.table()+" SET sequence=$1 WHERE id=$2",
+		int64(v), b.id,
+	)
+
+	return err
+}
+
+// Sequence returns the current sequence number for this bucket without
+// incrementing it.
+func (b *readWriteBucket) Sequence() uint64 {
+	var seq sql.NullInt64
+	err := b.tx.queryRow(
+		"SELECT sequence FROM "+b.table()+" WHERE id=$1", b.id,
+	).Scan(&seq)
+	if err != nil {
+		// TODO: This update kvdb interface such that error may be
+		// returned here.
+		return 0
+	}
+
+	// If the sequence number is not yet stored, then take the default
+	// value.
+	if !seq.Valid {
+		return 0
+	}
+
+	return uint64(seq.Int64)
+}
+
+// table returns the name of the table holding the bucket's data.
+func (b *readWriteBucket) table() string {
+	return b.tx.db.table
+}
@@ -0,0 +1,101 @@
+// +build kvdb_postgres kvdb_sqlite
+
+package sqlbase
+
+import (
+	"database/sql"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// readWriteCursor holds a reference to the cursors bucket and the current key
+// used while iterating.
+type readWriteCursor struct {
+	// bucket holds the reference to the parent bucket.
+	bucket *readWriteBucket
+
+	// currKey holds the current key of the cursor.
+	currKey []byte
+}
+
+func newReadWriteCursor(bucket *readWriteBucket) *readWriteCursor {
+	return &readWriteCursor{
+		bucket: bucket,
+	}
+}
+
+// seek fetches the single key/value pair selected by the given where clause
+// and order and moves the cursor to it. If no such pair exists, the cursor
+// isn't moved and nil is returned.
+func (c *readWriteCursor) seek(where, order string,
+	args ...interface{}) ([]byte, []byte) {
+
+	args = append([]interface{}{c.bucket.id}, args...)
+	key, value, err := scanKV(c.bucket.tx.queryRow(
+		"SELECT "+kvColumns+" FROM "+c.bucket.table()+" WHERE "+
+			"parent_id=$1"+where+" ORDER BY key "+order+" LIMIT 1",
+		args...,
+	))
+	if err != nil {
+		// TODO: revise this once kvdb interface supports errors.
+		return nil, nil
+	}
+
+	c.currKey = key
+
+	return key, value
+}
+
+// First positions the cursor at the first key/value pair and returns the
+// pair.
+func (c *readWriteCursor) First() (key, value []byte) {
+	return c.seek("", "ASC")
+}
+
+// Last positions the cursor at the last key/value pair and returns the pair.
+func (c *readWriteCursor) Last() (key, value []byte) {
+	return c.seek("", "DESC")
+}
+
+// Next moves the cursor one key/value pair forward and returns the new pair.
+func (c *readWriteCursor) Next() (key, value []byte) {
+	return c.seek(" AND key>$2", "ASC", c.currKey)
+}
+
+// Prev moves the cursor one key/value pair backward and returns the new pair.
+func (c *readWriteCursor) Prev() (key, value []byte) {
+	return c.seek(" AND key<$2", "DESC", c.currKey)
+}
+
+// Seek positions the cursor at the passed seek key. If the key does not
+// exist, the cursor is moved to the next key after seek. Returns the new
+// pair.
+func (c *readWriteCursor) Seek(seek []byte) (key, value []byte) {
+	// Every key is greater than the empty seek key, so we can avoid
+	// comparing against an empty (or NULL) blob.
+	if len(seek) == 0 {
+		return c.First()
+	}
+
+	return c.seek(" AND key>=$2", "ASC", seek)
+}
+
+// Delete removes the current key/value pair the cursor is at without
+// invalidating the cursor. Returns ErrIncompatibleValue if attempted when the
+// cursor points to a nested bucket.
+func (c *readWriteCursor) Delete() error {
+	_, isBucket, err := c.bucket.lookup(c.currKey)
+	switch {
+	// The key was already deleted, there's nothing left to do.
+	case err == sql.ErrNoRows:
+		return nil
+
+	case err != nil:
+		return err
+
+	case isBucket:
+		return walletdb.ErrIncompatibleValue
+	}
+
+	return c.bucket.Delete(c.currKey)
+}
@@ -0,0 +1,284 @@
+// +build kvdb_postgres kvdb_sqlite
+
+package sqlbase
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+// readWriteTx holds a reference to an open sql transaction.
+type readWriteTx struct {
+	// tx is the underlying sql transaction.
+	tx *sql.Tx
+
+	// db is the backend this transaction is operating on.
+	db *db
+
+	// onCommit gets called upon commit.
+	onCommit func()
+
+	// active is true if the transaction hasn't been committed or rolled
+	// back yet.
+	active bool
+
+	// locker is a reference to the lock that is held by this transaction.
+	locker sync.Locker
+
+	// ctx is the context of the sql transaction and cancel releases it.
+	ctx    context.Context
+	cancel func()
+}
+
+// newReadWriteTx creates a new read-write or read-only transaction. Read-only
+// transactions share the global lock with each other, while read-write
+// transactions hold it exclusively.
+func newReadWriteTx(db *db, readOnly bool) (*readWriteTx, error) {
+	var locker sync.Locker
+	if readOnly {
+		locker = db.lock.RLocker()
+	} else {
+		locker = &db.lock
+	}
+	locker.Lock()
+
+	ctx, cancel := db.getTimeoutCtx()
+	tx, err := db.db.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		cancel()
+		locker.Unlock()
+
+		return nil, err
+	}
+
+	return &readWriteTx{
+		tx:     tx,
+		db:     db,
+		active: true,
+		locker: locker,
+		ctx:    ctx,
+		cancel: cancel,
+	}, nil
+}
+
+// ReadBucket opens the root bucket for read only access. If the bucket
+// described by the key does not exist, nil is returned.
+func (tx *readWriteTx) ReadBucket(key []byte) walletdb.ReadBucket {
+	bucket := tx.ReadWriteBucket(key)
+	if bucket == nil {
+		return nil
+	}
+
+	return bucket.(walletdb.ReadBucket)
+}
+
+// ForEachBucket iterates through all top level buckets.
+func (tx *readWriteTx) ForEachBucket(fn func(key []byte) error) error {
+	rows, err := tx.query(
+		"SELECT key FROM " + tx.db.table +
+			" WHERE parent_id IS NULL ORDER BY key",
+	)
+	if err != nil {
+		return err
+	}
+
+	// The rows must be fully read before we can invoke the callback since
+	// it may issue new queries on the same connection.
+	var keys [][]byte
+	for rows.Next() {
+		var key []byte
+		if err := rows.Scan(&key); err != nil {
+			_ = rows.Close()
+			return err
+		}
+
+		keys = append(keys, key)
+	}
+	if err := closeRows(rows); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := fn(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Rollback closes the transaction, discarding changes (if any) if the
+// database was modified by a write transaction.
+func (tx *readWriteTx) Rollback() error {
+	// If the transaction has been closed rollback will fail.
+	if !tx.active {
+		return walletdb.ErrTxClosed
+	}
+
+	err := tx.tx.Rollback()
+	tx.close()
+
+	return err
+}
+
+// ReadWriteBucket opens the root bucket for read/write access. If the
+// bucket described by the key does not exist, nil is returned.
+func (tx *readWriteTx) ReadWriteBucket(key []byte) walletdb.ReadWriteBucket {
+	if len(key) == 0 {
+		return nil
+	}
+
+	var id int64
+	err := tx.queryRow(
+		"SELECT id FROM "+tx.db.table+" WHERE parent_id IS NULL "+
+			"AND key=$1", key,
+	).Scan(&id)
+	if err != nil {
+		// TODO: we should return the error once the kvdb interface
+		// is extended.
+		return nil
+	}
+
+	return newReadWriteBucket(tx, id)
+}
+
+// CreateTopLevelBucket creates the top level bucket for a key if it does not
+// exist. The newly-created bucket it returned.
+func (tx *readWriteTx) CreateTopLevelBucket(key []byte) (
+	walletdb.ReadWriteBucket, error) {
+
+	if len(key) == 0 {
+		return nil, walletdb.ErrBucketNameRequired
+	}
+
+	if bucket := tx.ReadWriteBucket(key); bucket != nil {
+		return bucket, nil
+	}
+
+	id, err := tx.insertBucket(nil, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return newReadWriteBucket(tx, id), nil
+}
+
+// DeleteTopLevelBucket deletes the top level bucket for a key. This errors if
+// the bucket can not be found or the key keys a single value instead of a
+// bucket.
+func (tx *readWriteTx) DeleteTopLevelBucket(key []byte) error {
+	if len(key) == 0 {
+		return walletdb.ErrBucketNameRequired
+	}
+
+	var id int64
+	err := tx.queryRow(
+		"SELECT id FROM "+tx.db.table+" WHERE parent_id IS NULL "+
+			"AND key=$1", key,
+	).Scan(&id)
+	switch {
+	case err == sql.ErrNoRows:
+		return walletdb.ErrBucketNotFound
+
+	case err != nil:
+		return err
+	}
+
+	return tx.deleteBucket(id)
+}
+
+// Commit commits the transaction if not already committed.
+func (tx *readWriteTx) Commit() error {
+	// Commit will fail if the transaction is already committed.
+	if !tx.active {
+		return walletdb.ErrTxClosed
+	}
+
+	// Try committing the transaction.
+	err := tx.tx.Commit()
+	tx.close()
+	if err != nil {
+		return err
+	}
+
+	if tx.onCommit != nil {
+		tx.onCommit()
+	}
+
+	return nil
+}
+
+// OnCommit sets the commit callback (overriding if already set).
+func (tx *readWriteTx) OnCommit(cb func()) {
+	tx.onCommit = cb
+}
+
+// close marks the transaction as inactive and releases the context and the
+// lock held by the transaction.
+func (tx *readWriteTx) close() {
+	tx.active = false
+	tx.cancel()
+	tx.locker.Unlock()
+}
+
+// insertBucket inserts a new (empty) bucket row below the given parent and
+// returns its id. A nil parent creates a top level bucket.
+func (tx *readWriteTx) insertBucket(parentID *int64, key []byte) (int64,
+	error) {
+
+	var id int64
+	err := tx.queryRow(
+		"INSERT INTO "+tx.db.table+" (parent_id, key) "+
+			"VALUES($1, $2) RETURNING id", parentID, key,
+	).Scan(&id)
+
+	return id, err
+}
+
+// deleteBucket deletes the bucket with the given id together with all its
+// nested buckets and key/value pairs. We don't rely on the foreign key
+// cascade here as not all sql backends enforce foreign keys by default.
+func (tx *readWriteTx) deleteBucket(id int64) error {
+	_, err := tx.exec(
+		"WITH RECURSIVE tree(id) AS (SELECT CAST($1 AS BIGINT) "+
+			"UNION ALL "+
+			"SELECT kv.id FROM "+tx.db.table+" kv JOIN tree "+
+			"ON kv.parent_id=tree.id) DELETE FROM "+tx.db.table+
+			" WHERE id IN (SELECT id FROM tree)", id,
+	)
+
+	return err
+}
+
+// query executes a Query on a transaction.
+func (tx *readWriteTx) query(query string, args ...interface{}) (*sql.Rows,
+	error) {
+
+	return tx.tx.QueryContext(tx.ctx, query, args...)
+}
+
+// queryRow executes a QueryRow on a transaction.
+func (tx *readWriteTx) queryRow(query string, args ...interface{}) *sql.Row {
+	return tx.tx.QueryRowContext(tx.ctx, query, args...)
+}
+
+// exec executes an Exec on a transaction.
+func (tx *readWriteTx) exec(query string, args ...interface{}) (sql.Result,
+	error) {
+
+	return tx.tx.ExecContext(tx.ctx, query, args...)
+}
+
+// closeRows closes the passed rows and returns any error that was encountered
+// while iterating them.
+func closeRows(rows *sql.Rows) error {
+	if err := rows.Err(); err != nil {
+		_ = rows.Close()
+		return err
+	}
+
+	return rows.Close()
+}
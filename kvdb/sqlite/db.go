@@ -0,0 +1,81 @@
+// +build kvdb_sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/kvdb/sqlbase"
+	_ "modernc.org/sqlite" // Import to register the driver.
+)
+
+const (
+	// driverName is the name of the database/sql driver we use to talk to
+	// sqlite.
+	driverName = "sqlite"
+)
+
+// sqliteReplacements are the sqlite specific types that replace the generic
+// placeholders of the kv table schema.
+var sqliteReplacements = map[string]string{
+	"ID_COLUMN": "INTEGER PRIMARY KEY",
+}
+
+// newSqliteBackend returns a db object initialized with the passed backend
+// config. The database file is created in the given directory if it doesn't
+// exist yet. The prefix is used as the table name prefix which allows
+// multiple namespaces to share the same database file.
+func newSqliteBackend(ctx context.Context, cfg *Config, dbPath,
+	fileName, prefix string) (walletdb.DB, error) {
+
+	if err := os.MkdirAll(dbPath, 0700); err != nil {
+		return nil, err
+	}
+
+	dsn, err := buildDsn(cfg, filepath.Join(dbPath, fileName))
+	if err != nil {
+		return nil, err
+	}
+
+	return sqlbase.NewSqlBackend(ctx, &sqlbase.Config{
+		DriverName:      driverName,
+		Dsn:             dsn,
+		Timeout:         cfg.Timeout,
+		TableNamePrefix: prefix,
+		MaxConnections:  cfg.MaxConnections,
+		Replacements:    sqliteReplacements,
+	})
+}
+
+// buildDsn creates the data source name for the sqlite database file at the
+// given path, applying all configured pragma options.
+func buildDsn(cfg *Config, dbFile string) (string, error) {
+	// We use WAL mode so readers don't block the writer and enforce
+	// foreign keys to keep the bucket hierarchy consistent.
+	pragmas := []string{
+		"journal_mode=WAL",
+		"synchronous=full",
+		"foreign_keys=on",
+		fmt.Sprintf("busy_timeout=%d", cfg.BusyTimeout.Milliseconds()),
+	}
+	pragmas = append(pragmas, cfg.PragmaOptions...)
+
+	query := url.Values{}
+	for _, pragma := range pragmas {
+		parts := strings.SplitN(pragma, "=", 2)
+		if len(parts) != 2 {
+			return "", fmt.Errorf("invalid pragma option %q, "+
+				"expected format 'name=value'", pragma)
+		}
+
+		query.Add("_pragma", fmt.Sprintf("%s(%s)", parts[0], parts[1]))
+	}
+
+	return fmt.Sprintf("file:%s?%s", dbFile, query.Encode()), nil
+}
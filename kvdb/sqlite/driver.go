@@ -0,0 +1,99 @@
+// +build kvdb_sqlite
+
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+)
+
+const (
+	dbType = "sqlite"
+)
+
+// parseArgs parses the arguments from the walletdb Open/Create methods.
+func parseArgs(funcName string, args ...interface{}) (context.Context,
+	*Config, string, string, string, error) {
+
+	if len(args) != 5 {
+		return nil, nil, "", "", "", fmt.Errorf("invalid number of "+
+			"arguments to %s.%s -- expected: context.Context, "+
+			"sqlite.Config, string, string, string", dbType,
+			funcName,
+		)
+	}
+
+	ctx, ok := args[0].(context.Context)
+	if !ok {
+		return nil, nil, "", "", "", fmt.Errorf("argument 0 to %s.%s "+
+			"is invalid -- expected: context.Context",
+			dbType, funcName,
+		)
+	}
+
+	config, ok := args[1].(*Config)
+	if !ok {
+		return nil, nil, "", "", "", fmt.Errorf("argument 1 to %s.%s "+
+			"is invalid -- expected: sqlite.Config",
+			dbType, funcName,
+		)
+	}
+
+	// The remaining arguments are the directory of the database file, the
+	// name of the database file and the table name prefix.
+	var strArgs [3]string
+	for i := range strArgs {
+		arg, ok := args[i+2].(string)
+		if !ok {
+			return nil, nil, "", "", "", fmt.Errorf("argument %d "+
+				"to %s.%s is invalid -- expected: string",
+				i+2, dbType, funcName,
+			)
+		}
+
+		strArgs[i] = arg
+	}
+
+	return ctx, config, strArgs[0], strArgs[1], strArgs[2], nil
+}
+
+// createDBDriver is the callback provided during driver registration that
+// creates, initializes, and opens a database for use.
+func createDBDriver(args ...interface{}) (walletdb.DB, error) {
+	ctx, config, dbPath, fileName, prefix, err := parseArgs(
+		"Create", args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSqliteBackend(ctx, config, dbPath, fileName, prefix)
+}
+
+// openDBDriver is the callback provided during driver registration that opens
+// an existing database for use.
+func openDBDriver(args ...interface{}) (walletdb.DB, error) {
+	ctx, config, dbPath, fileName, prefix, err := parseArgs(
+		"Open", args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSqliteBackend(ctx, config, dbPath, fileName, prefix)
+}
+
+func init() {
+	// Register the driver.
+	driver := walletdb.Driver{
+		DbType: dbType,
+		Create: createDBDriver,
+		Open:   openDBDriver,
+	}
+	if err := walletdb.RegisterDriver(driver); err != nil {
+		panic(fmt.Sprintf("Failed to regiser database driver '%s': %v",
+			dbType, err))
+	}
+}
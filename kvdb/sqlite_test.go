@@ -0,0 +1,98 @@
+// +build kvdb_sqlite
+
+package kvdb
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/btcsuite/btcwallet/walletdb"
+	"github.com/lightningnetwork/lnd/kvdb/sqlite"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSqlite runs the kvdb bucket, cursor and transaction test suites against
+// the sqlite backend.
+func TestSqlite(t *testing.T) {
+	tests := []struct {
+		name string
+		test func(*testing.T, walletdb.DB)
+	}{
+		{
+			name: "read cursor empty interval",
+			test: testReadCursorEmptyInterval,
+		},
+		{
+			name: "read cursor non empty interval",
+			test: testReadCursorNonEmptyInterval,
+		},
+		{
+			name: "read write cursor",
+			test: testReadWriteCursor,
+		},
+		{
+			name: "read write cursor with bucket and value",
+			test: testReadWriteCursorWithBucketAndValue,
+		},
+		{
+			name: "bucket creation",
+			test: testBucketCreation,
+		},
+		{
+			name: "bucket deletion",
+			test: testBucketDeletion,
+		},
+		{
+			name: "bucket for each",
+			test: testBucketForEach,
+		},
+		{
+			name: "bucket for each with error",
+			test: testBucketForEachWithError,
+		},
+		{
+			name: "bucket sequence",
+			test: testBucketSequence,
+		},
+		{
+			name: "key clash",
+			test: testKeyClash,
+		},
+		{
+			name: "bucket create delete",
+			test: testBucketCreateDelete,
+		},
+		{
+			name: "tx manual commit",
+			test: testTxManualCommit,
+		},
+		{
+			name: "tx rollback",
+			test: testTxRollback,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			tempDir, err := ioutil.TempDir("", "sqlite")
+			require.NoError(t, err)
+			defer os.RemoveAll(tempDir)
+
+			db, err := Create(
+				SqliteBackendName, context.Background(),
+				&sqlite.Config{BusyTimeout: DefaultDBTimeout},
+				tempDir, "test.sqlite", "test",
+			)
+			require.NoError(t, err)
+			defer db.Close()
+
+			test.test(t, db)
+		})
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"net"
 	"testing"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/stretchr/testify/require"
 )
 
@@ -139,7 +139,7 @@ var (
 
 	pubKeyHex = hex.EncodeToString(pubKeyBytes)
 
-	pubKey, _ = btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	pubKey, _ = btcec.ParsePubKey(pubKeyBytes)
 )
 
 type lnAddressCase struct {
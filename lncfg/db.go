@@ -7,6 +7,8 @@ import (
 
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/kvdb/etcd"
+	"github.com/lightningnetwork/lnd/kvdb/postgres"
+	"github.com/lightningnetwork/lnd/kvdb/sqlite"
 	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
 )
 
@@ -17,10 +19,44 @@ const (
 	towerClientDBName = "wtclient.db"
 	towerServerDBName = "watchtower.db"
 
+	// SqliteChannelDBName is the name of the sqlite database file that
+	// holds the graph and channel state data.
+	SqliteChannelDBName = "channel.sqlite"
+
+	// SqliteMacaroonDBName is the name of the sqlite database file that
+	// holds the macaroon root keys.
+	SqliteMacaroonDBName = "macaroons.sqlite"
+
+	// SqliteDecayedLogDBName is the name of the sqlite database file that
+	// holds the sphinx replay log.
+	SqliteDecayedLogDBName = "sphinxreplay.sqlite"
+
+	// SqliteTowerClientDBName is the name of the sqlite database file that
+	// holds the watchtower client data.
+	SqliteTowerClientDBName = "wtclient.sqlite"
+
+	// SqliteTowerServerDBName is the name of the sqlite database file that
+	// holds the watchtower server data.
+	SqliteTowerServerDBName = "watchtower.sqlite"
+
+	// SqliteWalletDBName is the name of the sqlite database file that
+	// holds the on-chain wallet data.
+	SqliteWalletDBName = "wallet.sqlite"
+
 	BoltBackend                = "bolt"
 	EtcdBackend                = "etcd"
+	PostgresBackend            = "postgres"
+	SqliteBackend              = "sqlite"
 	DefaultBatchCommitInterval = 500 * time.Millisecond
 
+	// defaultPostgresMaxConnections is the default maximum number of open
+	// connections each postgres backed namespace may use.
+	defaultPostgresMaxConnections = 50
+
+	// defaultSqliteBusyTimeout is the default time a sqlite query waits for
+	// a locked database to become available.
+	defaultSqliteBusyTimeout = 5 * time.Second
+
 	// NSChannelDB is the namespace name that we use for the combined graph
 	// and channel state DB.
 	NSChannelDB = "channeldb"
@@ -53,6 +89,10 @@ type DB struct {
 	Etcd *etcd.Config `group:"etcd" namespace:"etcd" description:"Etcd settings."`
 
 	Bolt *kvdb.BoltConfig `group:"bolt" namespace:"bolt" description:"Bolt settings."`
+
+	Postgres *postgres.Config `group:"postgres" namespace:"postgres" description:"Postgres settings."`
+
+	Sqlite *sqlite.Config `group:"sqlite" namespace:"sqlite" description:"Sqlite settings."`
 }
 
 // DefaultDB creates and returns a new default DB config.
@@ -64,6 +104,12 @@ func DefaultDB() *DB {
 			AutoCompactMinAge: kvdb.DefaultBoltAutoCompactMinAge,
 			DBTimeout:         kvdb.DefaultDBTimeout,
 		},
+		Postgres: &postgres.Config{
+			MaxConnections: defaultPostgresMaxConnections,
+		},
+		Sqlite: &sqlite.Config{
+			BusyTimeout: defaultSqliteBusyTimeout,
+		},
 	}
 }
 
@@ -77,9 +123,17 @@ func (db *DB) Validate() error {
 			return fmt.Errorf("etcd host must be set")
 		}
 
+	case PostgresBackend:
+		if db.Postgres.Dsn == "" {
+			return fmt.Errorf("postgres dsn must be set")
+		}
+
+	case SqliteBackend:
+
 	default:
-		return fmt.Errorf("unknown backend, must be either \"%v\" or \"%v\"",
-			BoltBackend, EtcdBackend)
+		return fmt.Errorf("unknown backend, must be one of \"%v\", "+
+			"\"%v\", \"%v\" or \"%v\"", BoltBackend, EtcdBackend,
+			PostgresBackend, SqliteBackend)
 	}
 
 	return nil
@@ -268,6 +322,163 @@ func (db *DB) GetBackends(ctx context.Context, chanDBPath,
 		}, nil
 	}
 
+	if db.Backend == PostgresBackend {
+		// Just like with etcd, all sub DBs live in the same postgres
+		// database but each namespace gets its own table.
+		openPostgres := func(namespace string) (kvdb.Backend, error) {
+			backend, err := kvdb.Open(
+				kvdb.PostgresBackendName, ctx, db.Postgres,
+				namespace,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("error opening postgres "+
+					"%s DB: %v", namespace, err)
+			}
+			closeFuncs[namespace] = backend.Close
+
+			return backend, nil
+		}
+
+		postgresBackend, err := openPostgres(NSChannelDB)
+		if err != nil {
+			return nil, err
+		}
+
+		postgresMacaroonBackend, err := openPostgres(NSMacaroonDB)
+		if err != nil {
+			return nil, err
+		}
+
+		postgresDecayedLogBackend, err := openPostgres(NSDecayedLogDB)
+		if err != nil {
+			return nil, err
+		}
+
+		postgresTowerClientBackend, err := openPostgres(NSTowerClientDB)
+		if err != nil {
+			return nil, err
+		}
+
+		postgresTowerServerBackend, err := openPostgres(NSTowerServerDB)
+		if err != nil {
+			return nil, err
+		}
+
+		postgresWalletBackend, err := openPostgres(NSWalletDB)
+		if err != nil {
+			return nil, err
+		}
+
+		returnEarly = false
+		return &DatabaseBackends{
+			GraphDB:       postgresBackend,
+			ChanStateDB:   postgresBackend,
+			HeightHintDB:  postgresBackend,
+			MacaroonDB:    postgresMacaroonBackend,
+			DecayedLogDB:  postgresDecayedLogBackend,
+			TowerClientDB: postgresTowerClientBackend,
+			TowerServerDB: postgresTowerServerBackend,
+			// The wallet loader will attempt to use/create the
+			// wallet in the replicated remote DB if we're running
+			// in a clustered environment. This will ensure that all
+			// members of the cluster have access to the same wallet
+			// state.
+			WalletDB: btcwallet.LoaderWithExternalWalletDB(
+				postgresWalletBackend,
+			),
+			Remote:     true,
+			CloseFuncs: closeFuncs,
+		}, nil
+	}
+
+	if db.Backend == SqliteBackend {
+		// Sqlite databases are local files that mirror the layout of
+		// the bolt files, each one holding a single namespace.
+		openSqlite := func(dbPath, fileName,
+			namespace string) (kvdb.Backend, error) {
+
+			backend, err := kvdb.Open(
+				kvdb.SqliteBackendName, ctx, db.Sqlite, dbPath,
+				fileName, namespace,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("error opening sqlite "+
+					"%s DB: %v", namespace, err)
+			}
+			closeFuncs[namespace] = backend.Close
+
+			return backend, nil
+		}
+
+		sqliteBackend, err := openSqlite(
+			chanDBPath, SqliteChannelDBName, NSChannelDB,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		sqliteMacaroonBackend, err := openSqlite(
+			walletDBPath, SqliteMacaroonDBName, NSMacaroonDB,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		sqliteDecayedLogBackend, err := openSqlite(
+			chanDBPath, SqliteDecayedLogDBName, NSDecayedLogDB,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// The tower client and server are optional and might not be
+		// enabled by the user. We handle them being nil properly in
+		// the main server.
+		var sqliteTowerClientBackend kvdb.Backend
+		if towerClientEnabled {
+			sqliteTowerClientBackend, err = openSqlite(
+				chanDBPath, SqliteTowerClientDBName,
+				NSTowerClientDB,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		var sqliteTowerServerBackend kvdb.Backend
+		if towerServerEnabled {
+			sqliteTowerServerBackend, err = openSqlite(
+				towerServerDBPath, SqliteTowerServerDBName,
+				NSTowerServerDB,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		sqliteWalletBackend, err := openSqlite(
+			walletDBPath, SqliteWalletDBName, NSWalletDB,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		returnEarly = false
+		return &DatabaseBackends{
+			GraphDB:       sqliteBackend,
+			ChanStateDB:   sqliteBackend,
+			HeightHintDB:  sqliteBackend,
+			MacaroonDB:    sqliteMacaroonBackend,
+			DecayedLogDB:  sqliteDecayedLogBackend,
+			TowerClientDB: sqliteTowerClientBackend,
+			TowerServerDB: sqliteTowerServerBackend,
+			WalletDB: btcwallet.LoaderWithExternalWalletDB(
+				sqliteWalletBackend,
+			),
+			CloseFuncs: closeFuncs,
+		}, nil
+	}
+
 	// We're using all bbolt based databases by default.
 	boltBackend, err := kvdb.GetBoltBackend(&kvdb.BoltBackendConfig{
 		DBPath:            chanDBPath,
@@ -0,0 +1,157 @@
+package lncfg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// FeeEstimator holds the configuration options for combining several fee
+// estimation sources into a single fee estimator.
+type FeeEstimator struct {
+	Active          bool     `long:"active" description:"Combine the fee estimates of the chain backend and all configured fee URLs, rather than using a single one of them"`
+	FeeURLs         []string `long:"feeurl" description:"An additional URL for external fee estimation that is queried along with the other sources. Can be specified multiple times"`
+	Policy          string   `long:"policy" choice:"median" choice:"min" choice:"max" description:"How the estimates of the sources are combined into a single fee rate"`
+	MinFeeRate      uint64   `long:"minfeerate" description:"The minimum fee rate in sat/vbyte ever returned by the fee estimator"`
+	MaxFeeRate      uint64   `long:"maxfeerate" description:"The maximum fee rate in sat/vbyte ever returned by the fee estimator, 0 for no maximum"`
+	TargetBounds    []string `long:"targetbound" description:"A sanity bound for the fee rates of a range of confirmation targets, formatted as conf_target:min:max with the fee rates in sat/vbyte. A bound applies to its confirmation target and all higher ones, up to the next bound. Can be specified multiple times"`
+	FallbackFeeRate uint64   `long:"fallbackfeerate" description:"The fee rate in sat/vbyte used if none of the sources returns an estimate"`
+}
+
+// DefaultFeeEstimator returns a new fee estimator config with the default
+// values set.
+func DefaultFeeEstimator() *FeeEstimator {
+	return &FeeEstimator{
+		Policy:          chainfee.PolicyMedian.String(),
+		FallbackFeeRate: 25,
+	}
+}
+
+// Validate checks the values configured for the fee estimator.
+func (f *FeeEstimator) Validate() error {
+	if !f.Active {
+		return nil
+	}
+
+	if _, err := chainfee.ParseAggregationPolicy(f.Policy); err != nil {
+		return err
+	}
+
+	if f.MaxFeeRate != 0 && f.MinFeeRate > f.MaxFeeRate {
+		return fmt.Errorf("feeestimator minfeerate of %v exceeds "+
+			"maxfeerate of %v", f.MinFeeRate, f.MaxFeeRate)
+	}
+
+	if f.FallbackFeeRate == 0 {
+		return fmt.Errorf("feeestimator fallbackfeerate must be set")
+	}
+
+	_, err := f.Bounds()
+	return err
+}
+
+// AggregationPolicy returns the configured aggregation policy of the fee
+// estimator.
+func (f *FeeEstimator) AggregationPolicy() (chainfee.AggregationPolicy, error) {
+	return chainfee.ParseAggregationPolicy(f.Policy)
+}
+
+// FallbackFeePerKW returns the configured fallback fee rate.
+func (f *FeeEstimator) FallbackFeePerKW() chainfee.SatPerKWeight {
+	return satPerVByteToKW(f.FallbackFeeRate)
+}
+
+// Bounds returns the sanity bounds of the fee estimator. The global minimum
+// and maximum fee rates apply to all confirmation targets, and further narrow
+// down the bounds of each configured target.
+func (f *FeeEstimator) Bounds() ([]chainfee.FeeBound, error) {
+	global := chainfee.FeeBound{
+		ConfTarget:  1,
+		MinFeePerKW: satPerVByteToKW(f.MinFeeRate),
+		MaxFeePerKW: satPerVByteToKW(f.MaxFeeRate),
+	}
+
+	bounds := []chainfee.FeeBound{global}
+	for _, targetBound := range f.TargetBounds {
+		bound, err := parseFeeBound(targetBound)
+		if err != nil {
+			return nil, err
+		}
+
+		if bound.MinFeePerKW < global.MinFeePerKW {
+			bound.MinFeePerKW = global.MinFeePerKW
+		}
+		if global.MaxFeePerKW != 0 && (bound.MaxFeePerKW == 0 ||
+			bound.MaxFeePerKW > global.MaxFeePerKW) {
+
+			bound.MaxFeePerKW = global.MaxFeePerKW
+		}
+		if bound.MaxFeePerKW != 0 &&
+			bound.MinFeePerKW > bound.MaxFeePerKW {
+
+			return nil, fmt.Errorf("feeestimator targetbound %q "+
+				"conflicts with minfeerate and maxfeerate",
+				targetBound)
+		}
+
+		// A bound for the lowest confirmation target replaces the
+		// global one.
+		if bound.ConfTarget == global.ConfTarget {
+			bounds[0] = bound
+			continue
+		}
+
+		bounds = append(bounds, bound)
+	}
+
+	return bounds, nil
+}
+
+// parseFeeBound parses a fee bound formatted as conf_target:min:max, with the
+// fee rates in sat/vbyte.
+func parseFeeBound(targetBound string) (chainfee.FeeBound, error) {
+	parts := strings.Split(targetBound, ":")
+	if len(parts) != 3 {
+		return chainfee.FeeBound{}, fmt.Errorf("invalid feeestimator "+
+			"targetbound %q, expected conf_target:min:max",
+			targetBound)
+	}
+
+	var values [3]uint64
+	for i, part := range parts {
+		value, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return chainfee.FeeBound{}, fmt.Errorf("invalid "+
+				"feeestimator targetbound %q: %v", targetBound,
+				err)
+		}
+
+		values[i] = value
+	}
+
+	bound := chainfee.FeeBound{
+		ConfTarget:  uint32(values[0]),
+		MinFeePerKW: satPerVByteToKW(values[1]),
+		MaxFeePerKW: satPerVByteToKW(values[2]),
+	}
+
+	switch {
+	case bound.ConfTarget == 0:
+		return chainfee.FeeBound{}, fmt.Errorf("invalid feeestimator "+
+			"targetbound %q: conf target must be positive",
+			targetBound)
+
+	case bound.MaxFeePerKW != 0 && bound.MinFeePerKW > bound.MaxFeePerKW:
+		return chainfee.FeeBound{}, fmt.Errorf("invalid feeestimator "+
+			"targetbound %q: min exceeds max", targetBound)
+	}
+
+	return bound, nil
+}
+
+// satPerVByteToKW converts a fee rate in sat/vbyte to sat/kw.
+func satPerVByteToKW(satPerVByte uint64) chainfee.SatPerKWeight {
+	return chainfee.SatPerKVByte(satPerVByte * 1000).FeePerKWeight()
+}
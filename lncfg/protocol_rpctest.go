@@ -20,6 +20,53 @@ type ProtocolOptions struct {
 	// mini.
 	WumboChans bool `long:"wumbo-channels" description:"if set, then lnd will create and accept requests for channels larger chan 0.16 BTC"`
 
+	// OptionScidAlias should be set if we want to signal the
+	// option-scid-alias feature bit. This allows scid aliases and the
+	// option-scid-alias channel-type.
+	OptionScidAlias bool `long:"option-scid-alias" description:"enable support for option_scid_alias channels"`
+
+	// OptionZeroConf should be set if we want to signal the zero-conf
+	// feature bit.
+	OptionZeroConf bool `long:"zero-conf" description:"enable support for zero-conf channels, must have option-scid-alias set also"`
+
+	// OptionSplicing should be set if we want to signal the experimental
+	// splicing feature bit. This allows funds to be added to or removed
+	// from private channels without closing them.
+	OptionSplicing bool `long:"splicing" description:"enable experimental support for splicing funds into and out of channels"`
+
+	// OptionQuiescence should be set if we want to signal the quiescence
+	// feature bit. This allows either party to pause all updates of a
+	// channel, which is required by protocols that change the channel in
+	// place.
+	OptionQuiescence bool `long:"quiescence" description:"enable support for quiescing channels with the stfu message, implied by splicing and dynamic-commitments"`
+
+	// OptionDynamicCommitments should be set if we want to signal the
+	// dynamic commitments feature bit. This allows the commitment type of
+	// existing channels to be upgraded without closing them.
+	OptionDynamicCommitments bool `long:"dynamic-commitments" description:"enable support for upgrading the commitment type of existing channels"`
+
+	// OptionPeerStorage should be set if we want to signal the provide
+	// storage feature bit. We then store an encrypted backup on behalf of
+	// our channel peers, and send our own backup to peers that provide
+	// storage.
+	OptionPeerStorage bool `long:"peer-storage" description:"store encrypted channel backups on behalf of channel peers, and send our own backup to peers that do the same"`
+
+	// OptionRbfCoopClose should be set if we want to signal the rbf coop
+	// close feature bit. This allows either party of a cooperative close
+	// to replace the closing transaction with one paying a higher fee
+	// out of its own balance.
+	OptionRbfCoopClose bool `long:"rbf-coop-close" description:"enable support for cooperative closes whose closing transaction can be fee bumped by either party"`
+
+	// OptionDualFunding should be set if we want to signal the dual
+	// funding feature bit. This allows both parties to contribute funds
+	// to a new channel.
+	OptionDualFunding bool `long:"dual-funding" description:"enable support for dual funded channels"`
+
+	// OptionOnionMessages should be set if we want to signal the onion
+	// messages feature bit. This allows us to send, receive and forward
+	// onion messages.
+	OptionOnionMessages bool `long:"onion-messages" description:"enable support for sending, receiving and forwarding onion messages"`
+
 	// Anchors enables anchor commitments.
 	// TODO(halseth): transition itests to anchors instead!
 	Anchors bool `long:"anchors" description:"enable support for anchor commitments"`
@@ -31,6 +78,59 @@ func (l *ProtocolOptions) Wumbo() bool {
 	return l.WumboChans
 }
 
+// ScidAlias returns true if we have enabled the option-scid-alias feature bit.
+func (l *ProtocolOptions) ScidAlias() bool {
+	return l.OptionScidAlias
+}
+
+// ZeroConf returns true if we have enabled the zero-conf feature bit.
+func (l *ProtocolOptions) ZeroConf() bool {
+	return l.OptionZeroConf
+}
+
+// Splicing returns true if we have enabled the experimental splicing feature
+// bit.
+func (l *ProtocolOptions) Splicing() bool {
+	return l.OptionSplicing
+}
+
+// Quiescence returns true if we have enabled the quiescence feature bit. As
+// splices and commitment upgrades are negotiated on quiescent channels, it is
+// implied by both of them.
+func (l *ProtocolOptions) Quiescence() bool {
+	return l.OptionQuiescence || l.OptionSplicing ||
+		l.OptionDynamicCommitments
+}
+
+// DynamicCommitments returns true if we have enabled the dynamic commitments
+// feature bit.
+func (l *ProtocolOptions) DynamicCommitments() bool {
+	return l.OptionDynamicCommitments
+}
+
+// PeerStorage returns true if we have enabled the provide storage feature
+// bit.
+func (l *ProtocolOptions) PeerStorage() bool {
+	return l.OptionPeerStorage
+}
+
+// RbfCoopClose returns true if we have enabled the rbf coop close feature
+// bit.
+func (l *ProtocolOptions) RbfCoopClose() bool {
+	return l.OptionRbfCoopClose
+}
+
+// DualFunding returns true if we have enabled the dual funding feature bit.
+func (l *ProtocolOptions) DualFunding() bool {
+	return l.OptionDualFunding
+}
+
+// OnionMessages returns true if we have enabled the onion messages feature
+// bit.
+func (l *ProtocolOptions) OnionMessages() bool {
+	return l.OptionOnionMessages
+}
+
 // NoAnchorCommitments returns true if we have disabled support for the anchor
 // commitment type.
 func (l *ProtocolOptions) NoAnchorCommitments() bool {
@@ -0,0 +1,55 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultRemoteSignerRPCTimeout is the default timeout that is used
+	// when forwarding a request to the remote signer through RPC.
+	DefaultRemoteSignerRPCTimeout = 5 * time.Second
+)
+
+// RemoteSigner holds the configuration options for a remote RPC signer.
+type RemoteSigner struct {
+	Enable       bool          `long:"enable" description:"Use a remote signer for deriving the node's keys and for signing any channel related transactions or messages. The private keys of the node identity and all channels are then only held by the remote signer. The local on-chain wallet must be a watch-only wallet that is created with the account xpubs of the remote signer, all of its inputs are signed by the remote signer as well."`
+	RPCHost      string        `long:"rpchost" description:"The remote signer's RPC host:port"`
+	MacaroonPath string        `long:"macaroonpath" description:"The macaroon to use for authenticating with the remote signer"`
+	TLSCertPath  string        `long:"tlscertpath" description:"The TLS certificate to use for establishing the remote signer's identity"`
+	Timeout      time.Duration `long:"timeout" description:"The timeout for connecting to and signing requests with the remote signer. Valid time units are {s, m, h}."`
+}
+
+// DefaultRemoteSigner returns a new remote signer config with the default
+// values set.
+func DefaultRemoteSigner() *RemoteSigner {
+	return &RemoteSigner{
+		Timeout: DefaultRemoteSignerRPCTimeout,
+	}
+}
+
+// Validate checks the values configured for our remote RPC signer.
+func (r *RemoteSigner) Validate() error {
+	if !r.Enable {
+		return nil
+	}
+
+	if r.Timeout < time.Millisecond {
+		return fmt.Errorf("remote signer timeout must be greater "+
+			"than 1 millisecond, got %v", r.Timeout)
+	}
+
+	if r.RPCHost == "" {
+		return fmt.Errorf("remote signer rpchost must be set")
+	}
+
+	if r.MacaroonPath == "" {
+		return fmt.Errorf("remote signer macaroonpath must be set")
+	}
+
+	if r.TLSCertPath == "" {
+		return fmt.Errorf("remote signer tlscertpath must be set")
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package lncfg
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/sweep"
+)
+
+// Sweeper holds the configuration options for the utxo sweeper.
+type Sweeper struct {
+	MaxFeeRate uint64 `long:"maxfeerate" description:"The maximum fee rate in sat/vbyte the sweeper will ever use, also when raising the fee rate of a time-sensitive sweep as its deadline approaches"`
+	FeeCurve   string `long:"feecurve" choice:"linear" choice:"cubic-delay" choice:"cubic-eager" description:"The curve along which the fee rate of time-sensitive sweeps, such as anchor cpfps and second-level htlc transactions, is raised every block as their deadline approaches. 'cubic-delay' raises the fee rate slowly at first and steeply close to the deadline, 'cubic-eager' the other way around."`
+}
+
+// DefaultSweeper returns a new sweeper config with the default values set.
+func DefaultSweeper() *Sweeper {
+	maxFeeRate := sweep.DefaultMaxFeeRate.FeePerKVByte() / 1000
+
+	return &Sweeper{
+		MaxFeeRate: uint64(maxFeeRate),
+		FeeCurve:   sweep.LinearFeeCurve.String(),
+	}
+}
+
+// Validate checks the values configured for the sweeper.
+func (s *Sweeper) Validate() error {
+	maxFeeRate := chainfee.SatPerKVByte(s.MaxFeeRate * 1000)
+	if maxFeeRate.FeePerKWeight() < chainfee.FeePerKwFloor {
+		return fmt.Errorf("sweeper maxfeerate must be at least %v, "+
+			"got %v", chainfee.FeePerKwFloor.FeePerKVByte(),
+			maxFeeRate)
+	}
+
+	if _, err := sweep.ParseFeeCurve(s.FeeCurve); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MaxFeeRatePerKW returns the configured maximum fee rate of the sweeper.
+func (s *Sweeper) MaxFeeRatePerKW() chainfee.SatPerKWeight {
+	return chainfee.SatPerKVByte(s.MaxFeeRate * 1000).FeePerKWeight()
+}
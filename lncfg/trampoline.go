@@ -0,0 +1,75 @@
+package lncfg
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultTrampolineBaseFee is the default base fee in millisatoshi we
+	// charge for forwarding a payment as trampoline node.
+	DefaultTrampolineBaseFee = 1000
+
+	// DefaultTrampolineFeeRate is the default proportional fee in
+	// millionths we charge for forwarding a payment as trampoline node.
+	DefaultTrampolineFeeRate = 1000
+
+	// DefaultTrampolineCltvDelta is the default time-lock delta we
+	// require for forwarding a payment as trampoline node.
+	DefaultTrampolineCltvDelta = 288
+
+	// DefaultTrampolinePaymentTimeout is the default time we spend trying
+	// to forward a payment as trampoline node.
+	DefaultTrampolinePaymentTimeout = time.Minute
+
+	// minTrampolineCltvDelta is the minimum time-lock delta we require
+	// for forwarding a payment as trampoline node. It leaves us enough
+	// time to claim the incoming htlc on chain if the recipient settles
+	// the payment late.
+	minTrampolineCltvDelta = DefaultIncomingBroadcastDelta +
+		DefaultFinalCltvRejectDelta
+)
+
+// Trampoline holds the configuration options for acting as trampoline node.
+type Trampoline struct {
+	Active         bool          `long:"active" description:"Forward payments as trampoline node for nodes that don't sync the channel graph themselves, such as mobile nodes. The fees are charged on top of the fees of the route we find to the recipient. Incoming multi-path payments aren't aggregated, htlcs that only pay a part of a payment are rejected."`
+	BaseFee        uint64        `long:"basefee" description:"The base fee in millisatoshi we charge for forwarding a payment as trampoline node"`
+	FeeRate        uint64        `long:"feerate" description:"The proportional fee in millionths we charge for forwarding a payment as trampoline node"`
+	CltvDelta      uint32        `long:"cltvdelta" description:"The time-lock delta we require between the incoming htlc and the payment we forward as trampoline node"`
+	PaymentTimeout time.Duration `long:"paymenttimeout" description:"The time we spend trying to forward a payment as trampoline node. Valid time units are {s, m, h}."`
+}
+
+// DefaultTrampoline returns a new trampoline config with the default values
+// set.
+func DefaultTrampoline() *Trampoline {
+	return &Trampoline{
+		BaseFee:        DefaultTrampolineBaseFee,
+		FeeRate:        DefaultTrampolineFeeRate,
+		CltvDelta:      DefaultTrampolineCltvDelta,
+		PaymentTimeout: DefaultTrampolinePaymentTimeout,
+	}
+}
+
+// Validate checks the values configured for acting as trampoline node.
+func (t *Trampoline) Validate() error {
+	if !t.Active {
+		return nil
+	}
+
+	if t.CltvDelta < minTrampolineCltvDelta {
+		return fmt.Errorf("trampoline cltvdelta must be at least %v, "+
+			"got %v", minTrampolineCltvDelta, t.CltvDelta)
+	}
+
+	if t.CltvDelta > 0xffff {
+		return fmt.Errorf("trampoline cltvdelta must be at most %v, "+
+			"got %v", 0xffff, t.CltvDelta)
+	}
+
+	if t.PaymentTimeout < time.Second {
+		return fmt.Errorf("trampoline paymenttimeout must be at "+
+			"least 1 second, got %v", t.PaymentTimeout)
+	}
+
+	return nil
+}
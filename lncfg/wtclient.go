@@ -1,6 +1,13 @@
 package lncfg
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultTowerFailoverTimeout is the default duration after which backups
+// pending for an unreachable watchtower are moved to a different watchtower.
+const DefaultTowerFailoverTimeout = 30 * time.Minute
 
 // WtClient holds the configuration options for the daemon's watchtower client.
 type WtClient struct {
@@ -15,6 +22,26 @@ type WtClient struct {
 	// SweepFeeRate specifies the fee rate in sat/byte to be used when
 	// constructing justice transactions sent to the tower.
 	SweepFeeRate uint64 `long:"sweep-fee-rate" description:"Specifies the fee rate in sat/byte to be used when constructing justice transactions sent to the watchtower."`
+
+	// AnchorSweepFeeRate specifies the fee rate in sat/byte to be used
+	// when constructing justice transactions of anchor channels. If it
+	// isn't set, SweepFeeRate is used for anchor channels as well.
+	AnchorSweepFeeRate uint64 `long:"anchor-sweep-fee-rate" description:"Specifies the fee rate in sat/byte to be used when constructing justice transactions of anchor channels sent to the watchtower. Defaults to sweep-fee-rate."`
+
+	// MaxUpdates specifies the maximum number of backups of legacy
+	// channels that each session negotiated with a watchtower should
+	// allow.
+	MaxUpdates uint16 `long:"max-updates" description:"The maximum number of backups of legacy channels each session negotiated with a watchtower should allow."`
+
+	// AnchorMaxUpdates specifies the maximum number of backups of anchor
+	// channels that each session negotiated with a watchtower should
+	// allow.
+	AnchorMaxUpdates uint16 `long:"anchor-max-updates" description:"The maximum number of backups of anchor channels each session negotiated with a watchtower should allow."`
+
+	// TowerFailoverTimeout specifies the duration after which backups
+	// pending for an unreachable watchtower are moved to a different
+	// watchtower.
+	TowerFailoverTimeout time.Duration `long:"tower-failover-timeout" description:"The duration after which backups that are pending to be sent to an unreachable watchtower are moved to a session with a different watchtower. Set to 0 to disable."`
 }
 
 // Validate ensures the user has provided a valid configuration.
@@ -30,6 +57,11 @@ func (c *WtClient) Validate() error {
 			"`lncli wtclient -h` for more information.")
 	}
 
+	if c.TowerFailoverTimeout < 0 {
+		return fmt.Errorf("wtclient.tower-failover-timeout must not " +
+			"be negative")
+	}
+
 	return nil
 }
 
@@ -17,13 +17,15 @@ import (
 	"os"
 	"path/filepath"
 	"runtime/pprof"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
-	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/wallet"
 	"github.com/btcsuite/btcwallet/walletdb"
 	proxy "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
@@ -702,11 +704,13 @@ func Main(cfg *Config, lisCfg ListenerCfg, interceptor signal.Interceptor) error
 		NeutrinoCS:                  neutrinoCS,
 		ActiveNetParams:             cfg.ActiveNetParams,
 		FeeURL:                      cfg.FeeURL,
+		FeeEstimator:                cfg.FeeEstimator,
 		Dialer: func(addr string) (net.Conn, error) {
 			return cfg.net.Dial("tcp", addr, cfg.ConnectionTimeout)
 		},
 		BlockCacheSize: cfg.BlockCacheSize,
 		LoaderOptions:  []btcwallet.LoaderOption{dbs.walletDB},
+		RemoteSigner:   cfg.RemoteSigner,
 	}
 
 	// Parse coin selection strategy.
@@ -1300,10 +1304,13 @@ func createWalletUnlockerService(cfg *Config) *walletunlocker.UnlockerService {
 		cfg.AdminMacPath, cfg.ReadMacPath, cfg.InvoiceMacPath,
 	}
 
-	return walletunlocker.New(
+	pwService := walletunlocker.New(
 		cfg.ActiveNetParams.Params, macaroonFiles,
 		cfg.ResetWalletTransactions, nil,
 	)
+	pwService.SetRemoteSigning(cfg.RemoteSigner.Enable)
+
+	return pwService
 }
 
 // startGrpcListen starts the GRPC server on the passed listeners.
@@ -1540,13 +1547,28 @@ func waitForWalletPassword(cfg *Config,
 				password, password, extendedKey, birthday,
 			)
 
+		// Neither seed nor extended private key was given, so maybe the
+		// third option was chosen, the watch-only initialization. In
+		// this case we need to import each of the xpubs individually.
+		case len(initMsg.WatchOnlyAccounts) > 0:
+			birthday = initMsg.WatchOnlyBirthday
+			newWallet, err = loader.CreateNewWatchingOnlyWallet(
+				password, birthday,
+			)
+			if err != nil {
+				break
+			}
+
+			err = importWatchOnlyAccounts(newWallet, initMsg)
+
 		default:
-			// The unlocker service made sure either the cipher seed
-			// or the extended key is set so, we shouldn't get here.
-			// The default case is just here for readability and
-			// completeness.
+			// The unlocker service made sure either the cipher
+			// seed, the extended key or the watch-only accounts are
+			// set, so we shouldn't get here. The default case is
+			// just here for readability and completeness.
 			err = fmt.Errorf("cannot create wallet, neither seed " +
-				"nor extended key was given")
+				"nor extended key nor watch-only accounts " +
+				"were given")
 		}
 		if err != nil {
 			// Don't leave the file open in case the new wallet
@@ -1605,6 +1627,78 @@ func waitForWalletPassword(cfg *Config,
 	}
 }
 
+// importWatchOnlyAccounts imports all individual account xpubs into our wallet
+// which we created as watch-only.
+func importWatchOnlyAccounts(wallet *wallet.Wallet,
+	initMsg *walletunlocker.WalletInitMsg) error {
+
+	accounts := initMsg.WatchOnlyAccounts
+	scopes := make([]waddrmgr.ScopedIndex, 0, len(accounts))
+	for scope := range accounts {
+		scopes = append(scopes, scope)
+	}
+
+	// We need to import the accounts in the correct order, otherwise the
+	// indices will be incorrect.
+	sort.Slice(scopes, func(i, j int) bool {
+		a, b := scopes[i], scopes[j]
+		switch {
+		case a.Scope.Purpose != b.Scope.Purpose:
+			return a.Scope.Purpose < b.Scope.Purpose
+
+		case a.Scope.Coin != b.Scope.Coin:
+			return a.Scope.Coin < b.Scope.Coin
+
+		default:
+			return a.Index < b.Index
+		}
+	})
+
+	for _, scope := range scopes {
+		// We want witness pubkey hash addresses by default, except for
+		// BIP49 where we want the mixed and BIP86 where we want the
+		// taproot address formats.
+		addrSchemaScope := waddrmgr.KeyScopeBIP0084
+		switch scope.Scope.Purpose {
+		case waddrmgr.KeyScopeBIP0049Plus.Purpose:
+			addrSchemaScope = waddrmgr.KeyScopeBIP0049Plus
+
+		case waddrmgr.KeyScopeBIP0086.Purpose:
+			addrSchemaScope = waddrmgr.KeyScopeBIP0086
+		}
+		addrSchema := waddrmgr.ScopeAddrMap[addrSchemaScope]
+
+		// We want a human-readable account name. But for the default
+		// on-chain wallet we actually need to call it "default" to make
+		// sure everything works correctly.
+		name := fmt.Sprintf("%s/%d'", scope.Scope.String(), scope.Index)
+		if scope.Index == 0 {
+			name = lnwallet.DefaultAccountName
+		}
+
+		props, err := wallet.ImportAccountWithScope(
+			name, accounts[scope],
+			initMsg.WatchOnlyMasterFingerprint, scope.Scope,
+			addrSchema,
+		)
+		if err != nil {
+			return fmt.Errorf("could not import account %v: %v",
+				name, err)
+		}
+
+		// Accounts are numbered in the order they're imported, so a
+		// gap in the indices of a scope would shift all following
+		// accounts.
+		if props.AccountNumber != scope.Index {
+			return fmt.Errorf("account %v was imported with "+
+				"number %d, indices must be contiguous", name,
+				props.AccountNumber)
+		}
+	}
+
+	return nil
+}
+
 // databaseInstances is a struct that holds all instances to the actual
 // databases that are used in lnd.
 type databaseInstances struct {
@@ -9,10 +9,9 @@ import (
 	"math"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
-	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
 
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -68,6 +67,17 @@ type AddInvoiceConfig struct {
 	// GenAmpInvoiceFeatures returns a feature containing feature bits that
 	// should be advertised on freshly generated AMP invoices.
 	GenAmpInvoiceFeatures func() *lnwire.FeatureVector
+
+	// GetAlias returns the alias our peer sent us for the channel with
+	// the given channel ID. Hop hints for channels that use an alias are
+	// created using this alias, as it is the only short channel ID our
+	// peer knows the channel by.
+	GetAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)
+
+	// BestHeight returns the height of the current best block. It is used
+	// to bound the expiry of HTLCs that are sent over the blinded paths
+	// of an invoice.
+	BestHeight func() (uint32, error)
 }
 
 // AddInvoiceData contains the required data to create a new invoice.
@@ -121,6 +131,10 @@ type AddInvoiceData struct {
 	// RouteHints are optional route hints that can each be individually used
 	// to assist in reaching the invoice's destination.
 	RouteHints [][]zpay32.HopHint
+
+	// Blind signals whether the invoice should hide our node behind
+	// blinded paths rather than reveal it to the sender.
+	Blind bool
 }
 
 // paymentHashAndPreimage returns the payment hash and preimage for this invoice
@@ -326,6 +340,7 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 
 	// We'll use our current default CLTV value unless one was specified as
 	// an option on the command line when creating an invoice.
+	finalCltvDelta := uint64(cfg.DefaultCLTVExpiry)
 	switch {
 	case invoice.CltvExpiry > math.MaxUint16:
 		return nil, nil, fmt.Errorf("CLTV delta of %v is too large, max "+
@@ -339,6 +354,7 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 				routing.MinCLTVDelta, invoice.CltvExpiry)
 		}
 
+		finalCltvDelta = invoice.CltvExpiry
 		options = append(options,
 			zpay32.CLTVExpiry(invoice.CltvExpiry))
 	default:
@@ -347,6 +363,15 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 		options = append(options, zpay32.CLTVExpiry(uint64(defaultDelta)))
 	}
 
+	// A blinded invoice must not reveal our node through route hints.
+	if invoice.Blind && (len(invoice.RouteHints) > 0 || invoice.Private) {
+		return nil, nil, errors.New("route hints can't be used with " +
+			"blinded invoices")
+	}
+	if invoice.Blind && invoice.Amp {
+		return nil, nil, errors.New("AMP invoices can't be blinded")
+	}
+
 	// We make sure that the given invoice routing hints number is within the
 	// valid range
 	if len(invoice.RouteHints) > 20 {
@@ -417,6 +442,25 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 	}
 	options = append(options, zpay32.PaymentAddr(paymentAddr))
 
+	// If requested, we hide our node behind blinded paths from our peers
+	// to us. The payment address doubles as path ID that identifies
+	// payments to this invoice.
+	if invoice.Blind {
+		expiry := DefaultInvoiceExpiry
+		if invoice.Expiry > 0 {
+			expiry = time.Duration(invoice.Expiry) * time.Second
+		}
+
+		blindedPaths, err := SelectBlindedPaths(
+			amtMSat, cfg, paymentAddr, finalCltvDelta, expiry,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		options = append(options, blindedPaths...)
+	}
+
 	// Create and encode the payment request as a bech32 (zpay32) string.
 	creationDate := time.Now()
 	payReq, err := zpay32.NewInvoice(
@@ -429,8 +473,9 @@ func AddInvoice(ctx context.Context, cfg *AddInvoiceConfig,
 	payReqString, err := payReq.Encode(
 		zpay32.MessageSigner{
 			SignCompact: func(msg []byte) ([]byte, error) {
-				hash := chainhash.HashB(msg)
-				return cfg.NodeSigner.SignDigestCompact(hash)
+				return cfg.NodeSigner.SignMessageCompact(
+					msg, false,
+				)
 			},
 		},
 	)
@@ -480,6 +525,15 @@ func chanCanBeHopHint(channel *channeldb.OpenChannel, cfg *AddInvoiceConfig) (
 		return nil, false
 	}
 
+	return inboundChanPolicy(channel, cfg)
+}
+
+// inboundChanPolicy returns the policy our peer applies to HTLCs it forwards
+// to us over the target channel, if the channel is active and the peer is
+// publicly advertised. Otherwise false is returned.
+func inboundChanPolicy(channel *channeldb.OpenChannel, cfg *AddInvoiceConfig) (
+	*channeldb.ChannelEdgePolicy, bool) {
+
 	// Make sure the channel is active.
 	chanPoint := lnwire.NewChanIDFromOutPoint(
 		&channel.FundingOutpoint,
@@ -534,14 +588,39 @@ func chanCanBeHopHint(channel *channeldb.OpenChannel, cfg *AddInvoiceConfig) (
 	return remotePolicy, true
 }
 
+// hopHintScid returns the short channel ID that should be used in a hop hint
+// for the passed channel. If our peer sent us an alias for the channel, that
+// alias is used. Channels that use an alias as their short channel ID, but for
+// which we don't know the peer's alias, can't be used as a hop hint.
+func hopHintScid(channel *channeldb.OpenChannel,
+	cfg *AddInvoiceConfig) (lnwire.ShortChannelID, bool) {
+
+	if cfg.GetAlias != nil {
+		chanID := lnwire.NewChanIDFromOutPoint(&channel.FundingOutpoint)
+		peerAlias, err := cfg.GetAlias(chanID)
+		if err == nil {
+			return peerAlias, true
+		}
+	}
+
+	if channel.HasAliasScid() {
+		log.Debugf("Skipping channel %v due to missing peer alias",
+			channel.FundingOutpoint)
+		return lnwire.ShortChannelID{}, false
+	}
+
+	return channel.ShortChanID(), true
+}
+
 // addHopHint creates a hop hint out of the passed channel and channel policy.
 // The new hop hint is appended to the passed slice.
 func addHopHint(hopHints *[]func(*zpay32.Invoice),
-	channel *channeldb.OpenChannel, chanPolicy *channeldb.ChannelEdgePolicy) {
+	channel *channeldb.OpenChannel, chanPolicy *channeldb.ChannelEdgePolicy,
+	hintScid lnwire.ShortChannelID) {
 
 	hopHint := zpay32.HopHint{
 		NodeID:      channel.IdentityPub,
-		ChannelID:   channel.ShortChanID().ToUint64(),
+		ChannelID:   hintScid.ToUint64(),
 		FeeBaseMSat: uint32(chanPolicy.FeeBaseMSat),
 		FeeProportionalMillionths: uint32(
 			chanPolicy.FeeProportionalMillionths,
@@ -581,9 +660,16 @@ func SelectHopHints(amtMSat lnwire.MilliSatoshi, cfg *AddInvoiceConfig,
 			continue
 		}
 
+		// We'll also need a short channel ID our peer knows the
+		// channel by.
+		hintScid, ok := hopHintScid(channel, cfg)
+		if !ok {
+			continue
+		}
+
 		// Now that we now this channel use usable, add it as a hop
 		// hint and the indexes we'll use later.
-		addHopHint(&hopHints, channel, edgePolicy)
+		addHopHint(&hopHints, channel, edgePolicy, hintScid)
 
 		hopHintChans[channel.FundingOutpoint] = struct{}{}
 		totalHintBandwidth += channel.LocalCommitment.RemoteBalance
@@ -625,9 +711,14 @@ func SelectHopHints(amtMSat lnwire.MilliSatoshi, cfg *AddInvoiceConfig,
 			continue
 		}
 
+		hintScid, ok := hopHintScid(channel, cfg)
+		if !ok {
+			continue
+		}
+
 		// Include the route hint in our set of options that will be
 		// used when creating the invoice.
-		addHopHint(&hopHints, channel, remotePolicy)
+		addHopHint(&hopHints, channel, remotePolicy, hintScid)
 
 		// As we've just added a new hop hint, we'll accumulate it's
 		// available balance now to update our tally.
@@ -0,0 +1,187 @@
+package invoicesrpc
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+const (
+	// maxBlindedPaths is the maximum number of blinded paths we include
+	// in an invoice.
+	maxBlindedPaths = 3
+
+	// blindedPathExpiryPadding is the number of blocks we add to the
+	// maximum expiry of HTLCs sent over a blinded path, to allow for
+	// blocks being mined faster than expected during the lifetime of the
+	// invoice.
+	blindedPathExpiryPadding = 144
+)
+
+var (
+	// ErrNoBlindedPaths is returned when a blinded invoice is requested,
+	// but none of our channels qualifies as blinded path.
+	ErrNoBlindedPaths = errors.New("no channels eligible for blinded " +
+		"paths")
+)
+
+// SelectBlindedPaths creates up to maxBlindedPaths blinded paths to our node,
+// each introduced by the peer of one of our active channels. The returned
+// functional options add the paths to an invoice.
+func SelectBlindedPaths(amtMSat lnwire.MilliSatoshi, cfg *AddInvoiceConfig,
+	paymentAddr [32]byte, finalCltvDelta uint64,
+	expiry time.Duration) ([]func(*zpay32.Invoice), error) {
+
+	if cfg.BestHeight == nil {
+		return nil, errors.New("blinded paths require the best height")
+	}
+
+	height, err := cfg.BestHeight()
+	if err != nil {
+		return nil, err
+	}
+
+	sourceNode, err := cfg.Graph.SourceNode()
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch source node: %v", err)
+	}
+	ourPub, err := sourceNode.PubKey()
+	if err != nil {
+		return nil, err
+	}
+
+	openChannels, err := cfg.ChanDB.FetchAllChannels()
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch all channels")
+	}
+
+	// Our own hop of each path only carries the path ID, which lets us
+	// identify the invoice that is paid.
+	ourData, err := record.EncodeBlindedRouteData(
+		&record.BlindedRouteData{
+			PathID: paymentAddr[:],
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The number of blocks we expect to be mined during the lifetime of
+	// the invoice.
+	expiryBlocks := uint32(expiry / (10 * time.Minute))
+
+	var paths []func(*zpay32.Invoice)
+	for _, channel := range openChannels {
+		if len(paths) >= maxBlindedPaths {
+			break
+		}
+
+		// The peer must be able to forward the payment to us.
+		if channel.LocalCommitment.RemoteBalance < amtMSat {
+			continue
+		}
+
+		policy, ok := inboundChanPolicy(channel, cfg)
+		if !ok || policy == nil {
+			continue
+		}
+
+		// Our peer needs to understand blinded routes to introduce
+		// the payment to them.
+		peer, err := cfg.Graph.FetchLightningNode(
+			nil, route.NewVertex(channel.IdentityPub),
+		)
+		if err != nil {
+			log.Debugf("Unable to fetch node %x: %v",
+				channel.IdentityPub.SerializeCompressed(), err)
+			continue
+		}
+		if !peer.Features.HasFeature(lnwire.RouteBlindingOptional) {
+			continue
+		}
+
+		scid, ok := hopHintScid(channel, cfg)
+		if !ok {
+			continue
+		}
+
+		pathCltvDelta := uint64(policy.TimeLockDelta) + finalCltvDelta
+		if pathCltvDelta > math.MaxUint16 {
+			return nil, fmt.Errorf("blinded path cltv delta %v "+
+				"too large", pathCltvDelta)
+		}
+
+		// Our peer forwards the payment to us according to the policy
+		// it applies to the channel, as long as the HTLC doesn't
+		// expire after the invoice could have been paid.
+		var (
+			feeRate = uint32(policy.FeeProportionalMillionths)
+			baseFee = uint32(policy.FeeBaseMSat)
+		)
+		maxCltvExpiry := height + expiryBlocks + uint32(pathCltvDelta) +
+			blindedPathExpiryPadding
+
+		introData, err := record.EncodeBlindedRouteData(
+			&record.BlindedRouteData{
+				ShortChannelID: &scid,
+				RelayInfo: &record.PaymentRelayInfo{
+					CltvExpiryDelta: policy.TimeLockDelta,
+					FeeRate:         feeRate,
+					BaseFee:         baseFee,
+				},
+				Constraints: &record.PaymentConstraints{
+					MaxCltvExpiry:   maxCltvExpiry,
+					HtlcMinimumMsat: policy.MinHTLC,
+				},
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		sessionKey, err := btcec.NewPrivateKey()
+		if err != nil {
+			return nil, err
+		}
+
+		path, err := blindedpath.BuildBlindedPath(
+			sessionKey, []*blindedpath.HopInfo{{
+				NodePub:   channel.IdentityPub,
+				PlainText: introData,
+			}, {
+				NodePub:   ourPub,
+				PlainText: ourData,
+			}},
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		paths = append(paths, zpay32.WithBlindedPaymentPath(
+			&zpay32.BlindedPaymentPath{
+				FeeBaseMsat:     baseFee,
+				FeeRate:         feeRate,
+				CltvExpiryDelta: uint16(pathCltvDelta),
+				HTLCMinMsat:     uint64(policy.MinHTLC),
+				HTLCMaxMsat: uint64(
+					channel.LocalCommitment.RemoteBalance,
+				),
+				Path: path,
+			},
+		))
+	}
+
+	if len(paths) == 0 {
+		return nil, ErrNoBlindedPaths
+	}
+
+	return paths, nil
+}
@@ -59,4 +59,11 @@ type Config struct {
 	// GenAmpInvoiceFeatures returns a feature containing feature bits that
 	// should be advertised on freshly generated AMP invoices.
 	GenAmpInvoiceFeatures func() *lnwire.FeatureVector
+
+	// GetAlias returns the alias our peer sent us for the channel with
+	// the given channel ID.
+	GetAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error)
+
+	// BestHeight returns the height of the current best block.
+	BestHeight func() (uint32, error)
 }
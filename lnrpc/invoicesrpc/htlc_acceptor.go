@@ -0,0 +1,152 @@
+// +build invoicesrpc
+
+package invoicesrpc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrAcceptorAlreadyExists is returned when a client tries to open an
+	// htlc acceptor stream while another one is active.
+	ErrAcceptorAlreadyExists = errors.New("htlc acceptor already exists")
+
+	// ErrMissingPreimage is returned when the client settles an htlc
+	// without providing a preimage.
+	ErrMissingPreimage = errors.New("missing preimage")
+)
+
+// htlcAcceptor is a helper struct that handles the lifecycle of an htlc
+// acceptor streaming session. The htlcs that wait for a decision are held by
+// the invoice registry, which hands them to the registry logic once the
+// stream closes.
+type htlcAcceptor struct {
+	registry    *invoices.InvoiceRegistry
+	chainParams *chaincfg.Params
+	stream      Invoices_HtlcAcceptorServer
+}
+
+// run registers the acceptor with the invoice registry and applies the
+// decisions that the client sends until the stream closes.
+func (h *htlcAcceptor) run() error {
+	h.registry.SetExitHtlcAcceptor(h.onHtlc)
+	defer h.registry.SetExitHtlcAcceptor(nil)
+
+	for {
+		resp, err := h.stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		// A decision that can't be applied doesn't indicate a problem
+		// with the stream, so we only log it.
+		if err := h.resolveFromClient(resp); err != nil {
+			log.Warnf("Unable to apply htlc acceptor decision: %v",
+				err)
+		}
+	}
+}
+
+// onHtlc sends an htlc that pays to our node to the client.
+func (h *htlcAcceptor) onHtlc(htlc *invoices.ExitHtlc) error {
+	req := &HtlcAcceptorRequest{
+		ChanId:        htlc.CircuitKey.ChanID.ToUint64(),
+		HtlcId:        htlc.CircuitKey.HtlcID,
+		PaymentHash:   htlc.Hash[:],
+		AmtPaidMsat:   uint64(htlc.AmtPaid),
+		Expiry:        htlc.Expiry,
+		CurrentHeight: htlc.CurrentHeight,
+		CustomRecords: htlc.CustomRecords,
+	}
+
+	if htlc.MPP != nil {
+		req.MppTotalAmtMsat = uint64(htlc.MPP.TotalMsat())
+	}
+
+	if htlc.Invoice != nil {
+		invoice, err := CreateRPCInvoice(htlc.Invoice, h.chainParams)
+		if err != nil {
+			return err
+		}
+		req.Invoice = invoice
+	}
+
+	return h.stream.Send(req)
+}
+
+// resolveFromClient applies a decision that the client sent.
+func (h *htlcAcceptor) resolveFromClient(in *HtlcAcceptorResponse) error {
+	res := &invoices.ExitHtlcResolution{
+		CircuitKey: channeldb.CircuitKey{
+			ChanID: lnwire.NewShortChanIDFromInt(in.ChanId),
+			HtlcID: in.HtlcId,
+		},
+	}
+
+	switch in.Action {
+	case HtlcAcceptorAction_RESUME:
+		res.Action = invoices.ExitHtlcResume
+
+	case HtlcAcceptorAction_SETTLE:
+		if in.Preimage == nil {
+			return ErrMissingPreimage
+		}
+		preimage, err := lntypes.MakePreimage(in.Preimage)
+		if err != nil {
+			return err
+		}
+
+		res.Action = invoices.ExitHtlcSettle
+		res.Preimage = preimage
+
+	case HtlcAcceptorAction_FAIL:
+		failure, err := unmarshallFailureCode(in.FailureCode)
+		if err != nil {
+			return err
+		}
+
+		res.Action = invoices.ExitHtlcFail
+		res.FailureMessage = failure
+
+	default:
+		return fmt.Errorf("unrecognized htlc acceptor action %v",
+			in.Action)
+	}
+
+	return h.registry.ResolveExitHtlc(res)
+}
+
+// unmarshallFailureCode maps the failure code chosen by the client to a wire
+// failure message. A nil message lets the link fail the htlc with
+// incorrect_or_unknown_payment_details, which requires the amount and height
+// of the htlc.
+func unmarshallFailureCode(
+	code lnrpc.Failure_FailureCode) (lnwire.FailureMessage, error) {
+
+	switch code {
+	case lnrpc.Failure_RESERVED,
+		lnrpc.Failure_INCORRECT_OR_UNKNOWN_PAYMENT_DETAILS:
+
+		return nil, nil
+
+	case lnrpc.Failure_TEMPORARY_NODE_FAILURE:
+		return &lnwire.FailTemporaryNodeFailure{}, nil
+
+	case lnrpc.Failure_PERMANENT_NODE_FAILURE:
+		return &lnwire.FailPermanentNodeFailure{}, nil
+
+	case lnrpc.Failure_MPP_TIMEOUT:
+		return &lnwire.FailMPPTimeout{}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported failure code %v", code)
+	}
+}
@@ -37,6 +37,14 @@ type InvoicesClient interface {
 	//SettleInvoice settles an accepted invoice. If the invoice is already
 	//settled, this call will succeed.
 	SettleInvoice(ctx context.Context, in *SettleInvoiceMsg, opts ...grpc.CallOption) (*SettleInvoiceResp, error)
+	//
+	//HtlcAcceptor dispatches a bi-directional streaming RPC in which htlcs that
+	//pay to this node are offered to the client before the invoice registry
+	//processes them. The client decides for every htlc whether it is settled,
+	//failed or handed to the invoice registry. Only one acceptor can be active
+	//at a time. Htlcs that still wait for a decision when the stream closes are
+	//handed to the invoice registry.
+	HtlcAcceptor(ctx context.Context, opts ...grpc.CallOption) (Invoices_HtlcAcceptorClient, error)
 }
 
 type invoicesClient struct {
@@ -106,6 +114,37 @@ func (c *invoicesClient) SettleInvoice(ctx context.Context, in *SettleInvoiceMsg
 	return out, nil
 }
 
+func (c *invoicesClient) HtlcAcceptor(ctx context.Context, opts ...grpc.CallOption) (Invoices_HtlcAcceptorClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Invoices_ServiceDesc.Streams[1], "/invoicesrpc.Invoices/HtlcAcceptor", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &invoicesHtlcAcceptorClient{stream}
+	return x, nil
+}
+
+type Invoices_HtlcAcceptorClient interface {
+	Send(*HtlcAcceptorResponse) error
+	Recv() (*HtlcAcceptorRequest, error)
+	grpc.ClientStream
+}
+
+type invoicesHtlcAcceptorClient struct {
+	grpc.ClientStream
+}
+
+func (x *invoicesHtlcAcceptorClient) Send(m *HtlcAcceptorResponse) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *invoicesHtlcAcceptorClient) Recv() (*HtlcAcceptorRequest, error) {
+	m := new(HtlcAcceptorRequest)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // InvoicesServer is the server API for Invoices service.
 // All implementations must embed UnimplementedInvoicesServer
 // for forward compatibility
@@ -128,6 +167,14 @@ type InvoicesServer interface {
 	//SettleInvoice settles an accepted invoice. If the invoice is already
 	//settled, this call will succeed.
 	SettleInvoice(context.Context, *SettleInvoiceMsg) (*SettleInvoiceResp, error)
+	//
+	//HtlcAcceptor dispatches a bi-directional streaming RPC in which htlcs that
+	//pay to this node are offered to the client before the invoice registry
+	//processes them. The client decides for every htlc whether it is settled,
+	//failed or handed to the invoice registry. Only one acceptor can be active
+	//at a time. Htlcs that still wait for a decision when the stream closes are
+	//handed to the invoice registry.
+	HtlcAcceptor(Invoices_HtlcAcceptorServer) error
 	mustEmbedUnimplementedInvoicesServer()
 }
 
@@ -147,6 +194,9 @@ func (UnimplementedInvoicesServer) AddHoldInvoice(context.Context, *AddHoldInvoi
 func (UnimplementedInvoicesServer) SettleInvoice(context.Context, *SettleInvoiceMsg) (*SettleInvoiceResp, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SettleInvoice not implemented")
 }
+func (UnimplementedInvoicesServer) HtlcAcceptor(Invoices_HtlcAcceptorServer) error {
+	return status.Errorf(codes.Unimplemented, "method HtlcAcceptor not implemented")
+}
 func (UnimplementedInvoicesServer) mustEmbedUnimplementedInvoicesServer() {}
 
 // UnsafeInvoicesServer may be embedded to opt out of forward compatibility for this service.
@@ -235,6 +285,32 @@ func _Invoices_SettleInvoice_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Invoices_HtlcAcceptor_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(InvoicesServer).HtlcAcceptor(&invoicesHtlcAcceptorServer{stream})
+}
+
+type Invoices_HtlcAcceptorServer interface {
+	Send(*HtlcAcceptorRequest) error
+	Recv() (*HtlcAcceptorResponse, error)
+	grpc.ServerStream
+}
+
+type invoicesHtlcAcceptorServer struct {
+	grpc.ServerStream
+}
+
+func (x *invoicesHtlcAcceptorServer) Send(m *HtlcAcceptorRequest) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *invoicesHtlcAcceptorServer) Recv() (*HtlcAcceptorResponse, error) {
+	m := new(HtlcAcceptorResponse)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Invoices_ServiceDesc is the grpc.ServiceDesc for Invoices service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -261,6 +337,12 @@ var Invoices_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Invoices_SubscribeSingleInvoice_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "HtlcAcceptor",
+			Handler:       _Invoices_HtlcAcceptor_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "invoicesrpc/invoices.proto",
 }
@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 
 	"google.golang.org/grpc"
 	"gopkg.in/macaroon-bakery.v2/bakery"
@@ -58,6 +59,10 @@ var (
 			Entity: "invoices",
 			Action: "write",
 		}},
+		"/invoicesrpc.Invoices/HtlcAcceptor": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
 	}
 
 	// DefaultInvoicesMacFilename is the default name of the invoices
@@ -77,6 +82,8 @@ type ServerShell struct {
 // RPC server allows external callers to access the status of the invoices
 // currently active within lnd, as well as configuring it at runtime.
 type Server struct {
+	acceptorActive int32 // To be used atomically.
+
 	// Required by the grpc-gateway/v2 library for forward compatibility.
 	UnimplementedInvoicesServer
 
@@ -320,6 +327,8 @@ func (s *Server) AddHoldInvoice(ctx context.Context,
 		Graph:                 s.cfg.GraphDB,
 		GenInvoiceFeatures:    s.cfg.GenInvoiceFeatures,
 		GenAmpInvoiceFeatures: s.cfg.GenAmpInvoiceFeatures,
+		GetAlias:              s.cfg.GetAlias,
+		BestHeight:            s.cfg.BestHeight,
 	}
 
 	hash, err := lntypes.MakeHash(invoice.Hash)
@@ -349,6 +358,7 @@ func (s *Server) AddHoldInvoice(ctx context.Context,
 		HodlInvoice:     true,
 		Preimage:        nil,
 		RouteHints:      routeHints,
+		Blind:           invoice.Blind,
 	}
 
 	_, dbInvoice, err := AddInvoice(ctx, addInvoiceCfg, addInvoiceData)
@@ -362,3 +372,22 @@ func (s *Server) AddHoldInvoice(ctx context.Context,
 		PaymentAddr:    dbInvoice.Terms.PaymentAddr[:],
 	}, nil
 }
+
+// HtlcAcceptor is a bidirectional stream that offers htlcs which pay to our
+// node to the client before the invoice registry processes them. Only one
+// acceptor can be active at a time. Htlcs that still wait for a decision when
+// the stream closes are handed to the invoice registry.
+func (s *Server) HtlcAcceptor(stream Invoices_HtlcAcceptorServer) error {
+	if !atomic.CompareAndSwapInt32(&s.acceptorActive, 0, 1) {
+		return ErrAcceptorAlreadyExists
+	}
+	defer atomic.CompareAndSwapInt32(&s.acceptorActive, 1, 0)
+
+	acceptor := &htlcAcceptor{
+		registry:    s.cfg.InvoiceRegistry,
+		chainParams: s.cfg.ChainParams,
+		stream:      stream,
+	}
+
+	return acceptor.run()
+}
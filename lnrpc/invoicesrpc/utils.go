@@ -4,7 +4,7 @@ import (
 	"encoding/hex"
 	"fmt"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnrpc"
@@ -244,7 +244,7 @@ func CreateZpay32HopHints(routeHints []*lnrpc.RouteHint) ([][]zpay32.HopHint, er
 			if err != nil {
 				return nil, err
 			}
-			p, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+			p, err := btcec.ParsePubKey(pubKeyBytes)
 			if err != nil {
 				return nil, err
 			}
@@ -42,14 +42,20 @@ type Config struct {
 // DefaultConfig defines the config defaults.
 func DefaultConfig() *Config {
 	defaultRoutingConfig := RoutingConfig{
-		AprioriHopProbability: routing.DefaultAprioriHopProbability,
-		AprioriWeight:         routing.DefaultAprioriWeight,
-		MinRouteProbability:   routing.DefaultMinRouteProbability,
-		PenaltyHalfLife:       routing.DefaultPenaltyHalfLife,
-		AttemptCost:           routing.DefaultAttemptCost.ToSatoshis(),
-		AttemptCostPPM:        routing.DefaultAttemptCostPPM,
-		MaxMcHistory:          routing.DefaultMaxMcHistory,
-		McFlushInterval:       routing.DefaultMcFlushInterval,
+		ProbabilityEstimatorType: routing.AprioriEstimatorName,
+		AprioriHopProbability:    routing.DefaultAprioriHopProbability,
+		AprioriWeight:            routing.DefaultAprioriWeight,
+		MinRouteProbability:      routing.DefaultMinRouteProbability,
+		PenaltyHalfLife:          routing.DefaultPenaltyHalfLife,
+		AttemptCost:              routing.DefaultAttemptCost.ToSatoshis(),
+		AttemptCostPPM:           routing.DefaultAttemptCostPPM,
+		MaxMcHistory:             routing.DefaultMaxMcHistory,
+		McFlushInterval:          routing.DefaultMcFlushInterval,
+		BimodalConfig: BimodalConfig{
+			NodeWeight: routing.DefaultBimodalNodeWeight,
+			Scale:      uint64(routing.DefaultBimodalScaleMsat),
+			DecayTime:  routing.DefaultBimodalDecayTime,
+		},
 	}
 
 	return &Config{
@@ -60,13 +66,15 @@ func DefaultConfig() *Config {
 // GetRoutingConfig returns the routing config based on this sub server config.
 func GetRoutingConfig(cfg *Config) *RoutingConfig {
 	return &RoutingConfig{
-		AprioriHopProbability: cfg.AprioriHopProbability,
-		AprioriWeight:         cfg.AprioriWeight,
-		MinRouteProbability:   cfg.MinRouteProbability,
-		AttemptCost:           cfg.AttemptCost,
-		AttemptCostPPM:        cfg.AttemptCostPPM,
-		PenaltyHalfLife:       cfg.PenaltyHalfLife,
-		MaxMcHistory:          cfg.MaxMcHistory,
-		McFlushInterval:       cfg.McFlushInterval,
+		ProbabilityEstimatorType: cfg.ProbabilityEstimatorType,
+		AprioriHopProbability:    cfg.AprioriHopProbability,
+		AprioriWeight:            cfg.AprioriWeight,
+		MinRouteProbability:      cfg.MinRouteProbability,
+		AttemptCost:              cfg.AttemptCost,
+		AttemptCostPPM:           cfg.AttemptCostPPM,
+		PenaltyHalfLife:          cfg.PenaltyHalfLife,
+		MaxMcHistory:             cfg.MaxMcHistory,
+		McFlushInterval:          cfg.McFlushInterval,
+		BimodalConfig:            cfg.BimodalConfig,
 	}
 }
@@ -9,10 +9,10 @@ import (
 	math "math"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/feature"
 	"github.com/lightningnetwork/lnd/htlcswitch"
@@ -33,6 +33,11 @@ const (
 	// TODO(roasbeef): make this value dynamic based on expected number of
 	// attempts for given amount
 	DefaultMaxParts = 16
+
+	// DefaultTrampolineCltvDelta is the default time-lock delta the
+	// trampoline node of a payment may spend on its own delta and the
+	// delta of the route to the target.
+	DefaultTrampolineCltvDelta = 288
 )
 
 // RouterBackend contains the backend implementation of the router rpc sub
@@ -86,6 +91,12 @@ type RouterBackend struct {
 	// by letting the router register a ForwardInterceptor.
 	InterceptableForwarder htlcswitch.InterceptableHtlcForwarder
 
+	// FetchOfferInvoice requests a fresh invoice for the encoded offer
+	// from its issuer and returns the encoded payment request. The amount
+	// may be zero for offers that specify an amount.
+	FetchOfferInvoice func(offer string,
+		amt lnwire.MilliSatoshi) (string, error)
+
 	// SetChannelEnabled exposes the ability to manually enable a channel.
 	SetChannelEnabled func(wire.OutPoint) error
 
@@ -100,9 +111,10 @@ type RouterBackend struct {
 // MissionControl defines the mission control dependencies of routerrpc.
 type MissionControl interface {
 	// GetProbability is expected to return the success probability of a
-	// payment from fromNode to toNode.
+	// payment from fromNode to toNode over a channel of the given
+	// capacity, which is zero if it is unknown.
 	GetProbability(fromNode, toNode route.Vertex,
-		amt lnwire.MilliSatoshi) float64
+		amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64
 
 	// ResetHistory resets the history of MissionControl returning it to a
 	// state as if no payment attempts have been made.
@@ -257,7 +269,8 @@ func (r *RouterBackend) QueryRoutes(ctx context.Context,
 	restrictions := &routing.RestrictParams{
 		FeeLimit: feeLimit,
 		ProbabilitySource: func(fromNode, toNode route.Vertex,
-			amt lnwire.MilliSatoshi) float64 {
+			amt lnwire.MilliSatoshi,
+			capacity btcutil.Amount) float64 {
 
 			if _, ok := ignoredNodes[fromNode]; ok {
 				return 0
@@ -276,7 +289,7 @@ func (r *RouterBackend) QueryRoutes(ctx context.Context,
 			}
 
 			return r.MissionControl.GetProbability(
-				fromNode, toNode, amt,
+				fromNode, toNode, amt, capacity,
 			)
 		},
 		DestCustomRecords: record.CustomSet(in.DestCustomRecords),
@@ -360,8 +373,15 @@ func (r *RouterBackend) getSuccessProbability(rt *route.Route) float64 {
 	for _, hop := range rt.Hops {
 		toNode := hop.PubKeyBytes
 
+		// If the capacity of the channel is unknown, the estimator
+		// falls back to its estimate for unknown capacities.
+		capacity, err := r.FetchChannelCapacity(hop.ChannelID)
+		if err != nil {
+			capacity = 0
+		}
+
 		probability := r.MissionControl.GetProbability(
-			fromNode, toNode, amtToFwd,
+			fromNode, toNode, amtToFwd, capacity,
 		)
 
 		successProb *= probability
@@ -650,10 +670,33 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 		return nil, err
 	}
 
+	// If an offer is given, we request a fresh invoice for it from its
+	// issuer and pay that invoice, which carries the amount to pay.
+	paymentRequest := rpcPayReq.PaymentRequest
+	if rpcPayReq.Offer != "" {
+		if paymentRequest != "" {
+			return nil, errors.New("offer and payment_request " +
+				"cannot appear together")
+		}
+
+		if r.FetchOfferInvoice == nil {
+			return nil, errors.New("offers are not supported")
+		}
+
+		paymentRequest, err = r.FetchOfferInvoice(
+			rpcPayReq.Offer, reqAmt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch invoice for "+
+				"offer: %w", err)
+		}
+		reqAmt = 0
+	}
+
 	// If the payment request field isn't blank, then the details of the
 	// invoice are encoded entirely within the encoded payReq.  So we'll
 	// attempt to decode it, populating the payment accordingly.
-	if rpcPayReq.PaymentRequest != "" {
+	if paymentRequest != "" {
 		switch {
 
 		case len(rpcPayReq.Dest) > 0:
@@ -670,7 +713,7 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 		}
 
 		payReq, err := zpay32.Decode(
-			rpcPayReq.PaymentRequest, r.ActiveNetParams,
+			paymentRequest, r.ActiveNetParams,
 		)
 		if err != nil {
 			return nil, err
@@ -758,7 +801,31 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 		)
 		payIntent.DestFeatures = payReq.Features
 		payIntent.PaymentAddr = payAddr
-		payIntent.PaymentRequest = []byte(rpcPayReq.PaymentRequest)
+		payIntent.PaymentRequest = []byte(paymentRequest)
+
+		// If the invoice hides the recipient behind blinded paths, we
+		// route the payment to the introduction point of the first
+		// one. The recipient identifies the payment through the path
+		// ID of the blinded path, so the payment address and the
+		// features of the recipient don't apply.
+		if len(payReq.BlindedPaymentPaths) > 0 {
+			if payReq.Features.HasFeature(lnwire.AMPOptional) {
+				return nil, errors.New("AMP payments to " +
+					"blinded paths are not supported")
+			}
+
+			blindedPayment := payReq.BlindedPaymentPaths[0]
+			payIntent.Target = route.NewVertex(
+				blindedPayment.Path.IntroductionPoint,
+			)
+			payIntent.FinalCLTVDelta =
+				blindedPayment.CltvExpiryDelta
+			payIntent.BlindedPayment = blindedPayment
+			payIntent.RouteHints = nil
+			payIntent.DestFeatures = nil
+			payIntent.PaymentAddr = nil
+			payIntent.MaxParts = 1
+		}
 	} else {
 		// Otherwise, If the payment request field was not specified
 		// (and a custom route wasn't specified), construct the payment
@@ -880,10 +947,45 @@ func (r *RouterBackend) extractIntentFromSendRequest(
 		payIntent.DestFeatures = features
 	}
 
+	// If a trampoline node is given, we only route the payment to the
+	// trampoline node, which finds the remaining route to the target.
+	finalCltvDelta := payIntent.FinalCLTVDelta
+	if len(rpcPayReq.TrampolineNode) > 0 {
+		trampolineNode, err := route.NewVertexFromBytes(
+			rpcPayReq.TrampolineNode,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if rpcPayReq.TrampolineFeeMsat < 0 {
+			return nil, errors.New("trampoline fee must not be " +
+				"negative")
+		}
+
+		cltvDelta := rpcPayReq.TrampolineCltvDelta
+		if cltvDelta == 0 {
+			cltvDelta = DefaultTrampolineCltvDelta
+		}
+		if cltvDelta > math.MaxUint16 {
+			return nil, fmt.Errorf("trampoline cltv delta must "+
+				"be at most %v", math.MaxUint16)
+		}
+
+		payIntent.Trampoline = &routing.TrampolineParams{
+			Node: trampolineNode,
+			Fee: lnwire.MilliSatoshi(
+				rpcPayReq.TrampolineFeeMsat,
+			),
+			CltvDelta: uint16(cltvDelta),
+		}
+		finalCltvDelta += uint16(cltvDelta)
+	}
+
 	// Do bounds checking with the block padding so the router isn't
 	// left with a zombie payment in case the user messes up.
 	err = routing.ValidateCLTVLimit(
-		payIntent.CltvLimit, payIntent.FinalCLTVDelta, true,
+		payIntent.CltvLimit, finalCltvDelta, true,
 	)
 	if err != nil {
 		return nil, err
@@ -927,7 +1029,7 @@ func unmarshallHopHint(rpcHint *lnrpc.HopHint) (zpay32.HopHint, error) {
 		return zpay32.HopHint{}, err
 	}
 
-	pubkey, err := btcec.ParsePubKey(pubBytes, btcec.S256())
+	pubkey, err := btcec.ParsePubKey(pubBytes)
 	if err != nil {
 		return zpay32.HopHint{}, err
 	}
@@ -1280,6 +1382,12 @@ func marshallWireError(msg lnwire.FailureMessage,
 	case *lnwire.InvalidOnionPayload:
 		response.Code = lnrpc.Failure_INVALID_ONION_PAYLOAD
 
+	case *lnwire.FailTrampolineFeeInsufficient:
+		response.Code = lnrpc.Failure_TRAMPOLINE_FEE_INSUFFICIENT
+
+	case *lnwire.FailTrampolineExpiryTooSoon:
+		response.Code = lnrpc.Failure_TRAMPOLINE_EXPIRY_TOO_SOON
+
 	case nil:
 		response.Code = lnrpc.Failure_UNKNOWN_FAILURE
 
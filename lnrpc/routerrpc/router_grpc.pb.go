@@ -96,7 +96,9 @@ type RouterClient interface {
 	//Forwarded HTLC requests are sent to the client and the client responds with
 	//a boolean that tells LND if this htlc should be intercepted.
 	//In case of interception, the htlc can be either settled, cancelled or
-	//resumed later by using the ResolveHoldForward endpoint.
+	//resumed later by using the ResolveHoldForward endpoint. Held htlcs survive
+	//a disconnect of the client and a restart of lnd, and are sent again to the
+	//client once it reconnects.
 	HtlcInterceptor(ctx context.Context, opts ...grpc.CallOption) (Router_HtlcInterceptorClient, error)
 	//
 	//UpdateChanStatus attempts to manually set the state of a channel
@@ -488,7 +490,9 @@ type RouterServer interface {
 	//Forwarded HTLC requests are sent to the client and the client responds with
 	//a boolean that tells LND if this htlc should be intercepted.
 	//In case of interception, the htlc can be either settled, cancelled or
-	//resumed later by using the ResolveHoldForward endpoint.
+	//resumed later by using the ResolveHoldForward endpoint. Held htlcs survive
+	//a disconnect of the client and a restart of lnd, and are sent again to the
+	//client once it reconnects.
 	HtlcInterceptor(Router_HtlcInterceptorServer) error
 	//
 	//UpdateChanStatus attempts to manually set the state of a channel
@@ -10,8 +10,8 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnrpc"
@@ -446,14 +446,39 @@ func (s *Server) GetMissionControlConfig(ctx context.Context,
 	error) {
 
 	cfg := s.cfg.RouterBackend.MissionControl.GetConfig()
+	rpcCfg := &MissionControlConfig{
+		MaximumPaymentResults: uint32(cfg.MaxMcHistory),
+		MinimumFailureRelaxInterval: uint64(
+			cfg.MinFailureRelaxInterval.Seconds(),
+		),
+	}
+
+	switch estimator := cfg.Estimator.(type) {
+	case *routing.AprioriEstimator:
+		rpcCfg.Model = MissionControlConfig_APRIORI
+		rpcCfg.HalfLifeSeconds = uint64(
+			estimator.PenaltyHalfLife.Seconds(),
+		)
+		rpcCfg.HopProbability = float32(estimator.AprioriHopProbability)
+		rpcCfg.Weight = float32(estimator.AprioriWeight)
+
+	case *routing.BimodalEstimator:
+		rpcCfg.Model = MissionControlConfig_BIMODAL
+		rpcCfg.Bimodal = &BimodalParameters{
+			NodeWeight: estimator.BimodalNodeWeight,
+			ScaleMsat:  uint64(estimator.BimodalScaleMsat),
+			DecayTime: uint64(
+				estimator.BimodalDecayTime.Seconds(),
+			),
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown estimator type %T",
+			cfg.Estimator)
+	}
+
 	return &GetMissionControlConfigResponse{
-		Config: &MissionControlConfig{
-			HalfLifeSeconds:             uint64(cfg.PenaltyHalfLife.Seconds()),
-			HopProbability:              float32(cfg.AprioriHopProbability),
-			Weight:                      float32(cfg.AprioriWeight),
-			MaximumPaymentResults:       uint32(cfg.MaxMcHistory),
-			MinimumFailureRelaxInterval: uint64(cfg.MinFailureRelaxInterval.Seconds()),
-		},
+		Config: rpcCfg,
 	}, nil
 }
 
@@ -462,14 +487,57 @@ func (s *Server) SetMissionControlConfig(ctx context.Context,
 	req *SetMissionControlConfigRequest) (*SetMissionControlConfigResponse,
 	error) {
 
+	if req.Config == nil {
+		return nil, errors.New("mission control config required")
+	}
+
+	var (
+		estimator routing.ProbabilityEstimator
+		err       error
+	)
+	switch req.Config.Model {
+	case MissionControlConfig_APRIORI:
+		estimator, err = routing.NewAprioriEstimator(
+			routing.AprioriConfig{
+				PenaltyHalfLife: time.Duration(
+					req.Config.HalfLifeSeconds,
+				) * time.Second,
+				AprioriHopProbability: float64(
+					req.Config.HopProbability,
+				),
+				AprioriWeight: float64(req.Config.Weight),
+			},
+		)
+
+	case MissionControlConfig_BIMODAL:
+		bimodal := req.Config.Bimodal
+		if bimodal == nil {
+			return nil, errors.New("bimodal parameters required " +
+				"for bimodal model")
+		}
+
+		estimator, err = routing.NewBimodalEstimator(
+			routing.BimodalConfig{
+				BimodalNodeWeight: bimodal.NodeWeight,
+				BimodalScaleMsat: lnwire.MilliSatoshi(
+					bimodal.ScaleMsat,
+				),
+				BimodalDecayTime: time.Duration(
+					bimodal.DecayTime,
+				) * time.Second,
+			},
+		)
+
+	default:
+		return nil, fmt.Errorf("unknown probability model %v",
+			req.Config.Model)
+	}
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &routing.MissionControlConfig{
-		ProbabilityEstimatorCfg: routing.ProbabilityEstimatorCfg{
-			PenaltyHalfLife: time.Duration(
-				req.Config.HalfLifeSeconds,
-			) * time.Second,
-			AprioriHopProbability: float64(req.Config.HopProbability),
-			AprioriWeight:         float64(req.Config.Weight),
-		},
+		Estimator:    estimator,
 		MaxMcHistory: int(req.Config.MaximumPaymentResults),
 		MinFailureRelaxInterval: time.Duration(
 			req.Config.MinimumFailureRelaxInterval,
@@ -699,7 +767,9 @@ func (s *Server) QueryProbability(ctx context.Context,
 	amt := lnwire.MilliSatoshi(req.AmtMsat)
 
 	mc := s.cfg.RouterBackend.MissionControl
-	prob := mc.GetProbability(fromNode, toNode, amt)
+	// The request doesn't refer to a specific channel, so the capacity
+	// is unknown.
+	prob := mc.GetProbability(fromNode, toNode, amt, 0)
 	history := mc.GetPairHistorySnapshot(fromNode, toNode)
 
 	return &QueryProbabilityResponse{
@@ -879,8 +949,8 @@ func (s *Server) SubscribeHtlcEvents(req *SubscribeHtlcEventsRequest,
 // 1. Check if there is already a live stream, if yes it rejects the request.
 // 2. Regsitered a ForwardInterceptor
 // 3. Delivers to the caller every √√ and detect his answer.
-// It uses a local implementation of holdForwardsStore to keep all the hold
-// forwards and find them when manual resolution is later needed.
+// The intercepted forwards are held by the switch, which offers them again to
+// the next interceptor if this stream disconnects.
 func (s *Server) HtlcInterceptor(stream Router_HtlcInterceptorServer) error {
 	// We ensure there is only one interceptor at a time.
 	if !atomic.CompareAndSwapInt32(&s.forwardInterceptorActive, 0, 1) {
@@ -889,7 +959,9 @@ func (s *Server) HtlcInterceptor(stream Router_HtlcInterceptorServer) error {
 	defer atomic.CompareAndSwapInt32(&s.forwardInterceptorActive, 1, 0)
 
 	// run the forward interceptor.
-	return newForwardInterceptor(s, stream).run()
+	return newForwardInterceptor(
+		s.cfg.RouterBackend.InterceptableForwarder, stream,
+	).run()
 }
 
 func extractOutPoint(req *UpdateChanStatusRequest) (*wire.OutPoint, error) {
@@ -3,7 +3,7 @@ package routerrpc
 import (
 	"time"
 
-	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcd/btcutil"
 )
 
 // RoutingConfig contains the configurable parameters that control routing.
@@ -12,6 +12,10 @@ type RoutingConfig struct {
 	// to attempt the payment.
 	MinRouteProbability float64 `long:"minrtprob" description:"Minimum required route success probability to attempt the payment"`
 
+	// ProbabilityEstimatorType is the name of the probability estimator
+	// that is used for pathfinding.
+	ProbabilityEstimatorType string `long:"estimator" choice:"apriori" choice:"bimodal" description:"Probability estimator used for pathfinding. The apriori estimator is configured with the apriori* and penaltyhalflife options, the bimodal estimator with the bimodal.* options."`
+
 	// AprioriHopProbability is the assumed success probability of a hop in
 	// a route when no other information is available.
 	AprioriHopProbability float64 `long:"apriorihopprob" description:"Assumed success probability of a hop in a route when no other information is available."`
@@ -47,4 +51,24 @@ type RoutingConfig struct {
 	// McFlushInterval defines the timer interval to use to flush mission
 	// control state to the DB.
 	McFlushInterval time.Duration `long:"mcflushinterval" description:"the timer interval to use to flush mission control state to the DB"`
+
+	// BimodalConfig defines parameters for the bimodal probability
+	// estimator.
+	BimodalConfig BimodalConfig `group:"bimodal" namespace:"bimodal"`
+}
+
+// BimodalConfig defines configuration for the bimodal probability estimator.
+type BimodalConfig struct {
+	// NodeWeight defines how strongly the results of the other channels
+	// of a node are taken into account when estimating the probability
+	// of a channel of that node.
+	NodeWeight float64 `long:"nodeweight" description:"Defines how strongly the results of the other channels of a node are taken into account when estimating the probability of a channel of that node. Valid values are in [0, 1]."`
+
+	// Scale describes the scale over which channels typically have some
+	// liquidity left on their sides.
+	Scale uint64 `long:"scale" description:"Defines the unit in msat of the liquidity that is typically left on the sides of a channel. Channels much larger than the scale are assumed to have their liquidity concentrated on one of their sides."`
+
+	// DecayTime is the time after which the knowledge about a previous
+	// success or failure has decayed by a factor of e.
+	DecayTime time.Duration `long:"decaytime" description:"Describes the decay of the knowledge about previous successes and failures of a channel."`
 }
@@ -42,7 +42,8 @@ type SignerClient interface {
 	ComputeInputScript(ctx context.Context, in *SignReq, opts ...grpc.CallOption) (*InputScriptResp, error)
 	//
 	//SignMessage signs a message with the key specified in the key locator. The
-	//returned signature is fixed-size LN wire format encoded.
+	//returned signature is fixed-size LN wire format encoded, or in the compact
+	//pubkey recoverable format if compact_sig is set.
 	//
 	//The main difference to SignMessage in the main RPC is that a specific key is
 	//used to sign the message instead of the node identity private key.
@@ -147,7 +148,8 @@ type SignerServer interface {
 	ComputeInputScript(context.Context, *SignReq) (*InputScriptResp, error)
 	//
 	//SignMessage signs a message with the key specified in the key locator. The
-	//returned signature is fixed-size LN wire format encoded.
+	//returned signature is fixed-size LN wire format encoded, or in the compact
+	//pubkey recoverable format if compact_sig is set.
 	//
 	//The main difference to SignMessage in the main RPC is that a specific key is
 	//used to sign the message instead of the node identity private key.
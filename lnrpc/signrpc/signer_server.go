@@ -10,7 +10,7 @@ import (
 	"os"
 	"path/filepath"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
@@ -264,7 +264,32 @@ func (s *Server) SignOutputRaw(ctx context.Context, in *SignReq) (*SignResp, err
 		return nil, fmt.Errorf("unable to decode tx: %v", err)
 	}
 
-	sigHashCache := txscript.NewTxSigHashes(&txToSign)
+	// Taproot signatures commit to all outputs spent by the transaction,
+	// so the caller needs to provide them to sign any taproot inputs.
+	var (
+		prevOutFetcher txscript.PrevOutputFetcher
+		sigHashCache   = input.NewTxSigHashesV0Only(&txToSign)
+	)
+	if len(in.PrevOutputs) > 0 {
+		if len(in.PrevOutputs) != len(txToSign.TxIn) {
+			return nil, fmt.Errorf("provided %d previous outputs "+
+				"for %d inputs", len(in.PrevOutputs),
+				len(txToSign.TxIn))
+		}
+
+		fetcher := txscript.NewMultiPrevOutFetcher(nil)
+		for i, prevOut := range in.PrevOutputs {
+			fetcher.AddPrevOut(
+				txToSign.TxIn[i].PreviousOutPoint,
+				&wire.TxOut{
+					Value:    prevOut.Value,
+					PkScript: prevOut.PkScript,
+				},
+			)
+		}
+		prevOutFetcher = fetcher
+		sigHashCache = txscript.NewTxSigHashes(&txToSign, fetcher)
+	}
 
 	log.Debugf("Generating sigs for %v inputs: ", len(in.SignDescs))
 
@@ -306,9 +331,31 @@ func (s *Server) SignOutputRaw(ctx context.Context, in *SignReq) (*SignResp, err
 			}
 		}
 
+		signMethod, err := unmarshalSignMethod(signDesc.SignMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		// Taproot key spends don't need a witness script, but commit
+		// to all previous outputs and possibly to a script root.
+		case signMethod.IsTaproot():
+			if prevOutFetcher == nil {
+				return nil, fmt.Errorf("previous outputs " +
+					"MUST be specified to sign taproot " +
+					"inputs")
+			}
+
+			if signMethod == input.TaprootKeySpendSignMethod &&
+				len(signDesc.TapTweak) != 32 {
+
+				return nil, fmt.Errorf("tap tweak MUST be 32 " +
+					"bytes")
+			}
+
 		// If a witness script isn't passed, then we can't proceed, as
 		// in the p2wsh case, we can't properly generate the sighash.
-		if len(signDesc.WitnessScript) == 0 {
+		case len(signDesc.WitnessScript) == 0:
 			// TODO(roasbeef): if regualr p2wkh, then at times
 			// internally we allow script to go by
 			return nil, fmt.Errorf("witness script MUST be " +
@@ -320,7 +367,7 @@ func (s *Server) SignOutputRaw(ctx context.Context, in *SignReq) (*SignResp, err
 		var tweakPrivKey *btcec.PrivateKey
 		if len(signDesc.DoubleTweak) != 0 {
 			tweakPrivKey, _ = btcec.PrivKeyFromBytes(
-				btcec.S256(), signDesc.DoubleTweak,
+				signDesc.DoubleTweak,
 			)
 		}
 
@@ -339,9 +386,14 @@ func (s *Server) SignOutputRaw(ctx context.Context, in *SignReq) (*SignResp, err
 				Value:    signDesc.Output.Value,
 				PkScript: signDesc.Output.PkScript,
 			},
-			HashType:   txscript.SigHashType(signDesc.Sighash),
-			SigHashes:  sigHashCache,
-			InputIndex: int(signDesc.InputIndex),
+			HashType: txscript.SigHashType(
+				signDesc.Sighash,
+			),
+			SigHashes:         sigHashCache,
+			InputIndex:        int(signDesc.InputIndex),
+			SignMethod:        signMethod,
+			TapTweak:          signDesc.TapTweak,
+			PrevOutputFetcher: prevOutFetcher,
 		})
 	}
 
@@ -444,7 +496,8 @@ func (s *Server) ComputeInputScript(ctx context.Context,
 }
 
 // SignMessage signs a message with the key specified in the key locator. The
-// returned signature is fixed-size LN wire format encoded.
+// returned signature is fixed-size LN wire format encoded, or in the compact
+// pubkey recoverable format if compact_sig is set.
 func (s *Server) SignMessage(ctx context.Context,
 	in *SignMessageReq) (*SignMessageResp, error) {
 
@@ -456,20 +509,32 @@ func (s *Server) SignMessage(ctx context.Context,
 	}
 
 	// Describe the private key we'll be using for signing.
-	keyDescriptor := keychain.KeyDescriptor{
-		KeyLocator: keychain.KeyLocator{
-			Family: keychain.KeyFamily(in.KeyLoc.KeyFamily),
-			Index:  uint32(in.KeyLoc.KeyIndex),
-		},
+	keyLocator := keychain.KeyLocator{
+		Family: keychain.KeyFamily(in.KeyLoc.KeyFamily),
+		Index:  uint32(in.KeyLoc.KeyIndex),
 	}
 
-	// The signature is over the sha256 hash of the message.
-	var digest [32]byte
-	copy(digest[:], chainhash.HashB(in.Msg))
+	// To allow a watch-only wallet to forward the SignMessageCompact
+	// endpoint to this RPC, we provide a way to return the compact
+	// signature format instead of the lnwire format.
+	if in.CompactSig {
+		sigBytes, err := s.cfg.KeyRing.SignMessageCompact(
+			keyLocator, in.Msg, in.DoubleHash,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("can't sign the hash: %v", err)
+		}
+
+		return &SignMessageResp{
+			Signature: sigBytes,
+		}, nil
+	}
 
 	// Create the raw ECDSA signature first and convert it to the final wire
 	// format after.
-	sig, err := s.cfg.KeyRing.SignDigest(keyDescriptor, digest)
+	sig, err := s.cfg.KeyRing.SignMessage(
+		keyLocator, in.Msg, in.DoubleHash,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("can't sign the hash: %v", err)
 	}
@@ -497,7 +562,7 @@ func (s *Server) VerifyMessage(ctx context.Context,
 	if in.Pubkey == nil {
 		return nil, fmt.Errorf("a pubkey to verify MUST be passed in")
 	}
-	pubkey, err := btcec.ParsePubKey(in.Pubkey, btcec.S256())
+	pubkey, err := btcec.ParsePubKey(in.Pubkey)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse pubkey: %v", err)
 	}
@@ -618,9 +683,7 @@ func parseRawKeyBytes(rawKeyBytes []byte) (*btcec.PublicKey, error) {
 	case len(rawKeyBytes) == 33:
 		// If a proper raw key was provided, then we'll attempt
 		// to decode and parse it.
-		return btcec.ParsePubKey(
-			rawKeyBytes, btcec.S256(),
-		)
+		return btcec.ParsePubKey(rawKeyBytes)
 
 	case len(rawKeyBytes) == 0:
 		// No key is provided, return nil.
@@ -634,3 +697,21 @@ func parseRawKeyBytes(rawKeyBytes []byte) (*btcec.PublicKey, error) {
 			"specified")
 	}
 }
+
+// unmarshalSignMethod converts the RPC sign method into its internal
+// counterpart.
+func unmarshalSignMethod(rpcSignMethod SignMethod) (input.SignMethod, error) {
+	switch rpcSignMethod {
+	case SignMethod_SIGN_METHOD_WITNESS_V0:
+		return input.WitnessV0SignMethod, nil
+
+	case SignMethod_SIGN_METHOD_TAPROOT_KEY_SPEND_BIP0086:
+		return input.TaprootKeySpendBIP0086SignMethod, nil
+
+	case SignMethod_SIGN_METHOD_TAPROOT_KEY_SPEND:
+		return input.TaprootKeySpendSignMethod, nil
+
+	default:
+		return 0, fmt.Errorf("unknown sign method %v", rpcSignMethod)
+	}
+}
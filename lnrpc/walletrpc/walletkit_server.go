@@ -13,13 +13,13 @@ import (
 	"path/filepath"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
-	"github.com/btcsuite/btcutil/hdkeychain"
-	"github.com/btcsuite/btcutil/psbt"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/wtxmgr"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
@@ -121,6 +121,10 @@ var (
 			Entity: "onchain",
 			Action: "write",
 		}},
+		"/walletrpc.WalletKit/SignPsbt": {{
+			Entity: "onchain",
+			Action: "write",
+		}},
 		"/walletrpc.WalletKit/FinalizePsbt": {{
 			Entity: "onchain",
 			Action: "write",
@@ -506,9 +510,22 @@ func (w *WalletKit) NextAddr(ctx context.Context,
 		account = req.Account
 	}
 
-	addr, err := w.cfg.Wallet.NewAddress(
-		lnwallet.WitnessPubKey, false, account,
-	)
+	addrType := lnwallet.WitnessPubKey
+	switch req.Type {
+	case AddressType_UNKNOWN, AddressType_WITNESS_PUBKEY_HASH:
+		break
+
+	case AddressType_NESTED_WITNESS_PUBKEY_HASH:
+		addrType = lnwallet.NestedWitnessPubKey
+
+	case AddressType_TAPROOT_PUBKEY:
+		addrType = lnwallet.TaprootPubkey
+
+	default:
+		return nil, fmt.Errorf("unhandled address type %v", req.Type)
+	}
+
+	addr, err := w.cfg.Wallet.NewAddress(addrType, req.Change, account)
 	if err != nil {
 		return nil, err
 	}
@@ -621,16 +638,55 @@ func (w *WalletKit) EstimateFee(ctx context.Context,
 			"than 1")
 	}
 
-	satPerKw, err := w.cfg.FeeEstimator.EstimateFeePerKW(
+	// If the composite fee estimator is active, we'll also report the
+	// result of each of its sources.
+	composite, ok := w.cfg.FeeEstimator.(*chainfee.CompositeEstimator)
+	if !ok {
+		satPerKw, err := w.cfg.FeeEstimator.EstimateFeePerKW(
+			uint32(req.ConfTarget),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return &EstimateFeeResponse{
+			SatPerKw: int64(satPerKw),
+		}, nil
+	}
+
+	satPerKw, estimates, err := composite.EstimateFeeSources(
 		uint32(req.ConfTarget),
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	return &EstimateFeeResponse{
+	resp := &EstimateFeeResponse{
 		SatPerKw: int64(satPerKw),
-	}, nil
+		Sources:  make([]*FeeSourceEstimate, 0, len(estimates)),
+		Fallback: true,
+	}
+	for _, estimate := range estimates {
+		rpcEstimate := &FeeSourceEstimate{
+			Name:                estimate.Health.Name,
+			SatPerKw:            int64(estimate.FeePerKW),
+			Healthy:             estimate.Health.Healthy,
+			ConsecutiveFailures: estimate.Health.ConsecutiveFailures,
+		}
+		if estimate.Err != nil {
+			rpcEstimate.Error = estimate.Err.Error()
+		} else {
+			resp.Fallback = false
+		}
+		if !estimate.Health.LastSuccess.IsZero() {
+			rpcEstimate.LastSuccess =
+				estimate.Health.LastSuccess.Unix()
+		}
+
+		resp.Sources = append(resp.Sources, rpcEstimate)
+	}
+
+	return resp, nil
 }
 
 // PendingSweeps returns lists of on-chain outputs that lnd is currently
@@ -679,6 +735,8 @@ func (w *WalletKit) PendingSweeps(ctx context.Context,
 			witnessType = WitnessType_NESTED_WITNESS_KEY_HASH
 		case input.CommitmentAnchor:
 			witnessType = WitnessType_COMMITMENT_ANCHOR
+		case input.TaprootPubKeySpend:
+			witnessType = WitnessType_TAPROOT_PUB_KEY_SPEND
 		default:
 			log.Warnf("Unhandled witness type %v for input %v",
 				pendingInput.WitnessType, pendingInput.OutPoint)
@@ -822,12 +880,18 @@ func (w *WalletKit) BumpFee(ctx context.Context,
 			"transaction")
 	}
 
-	var witnessType input.WitnessType
+	var (
+		witnessType input.WitnessType
+		hashType    = txscript.SigHashAll
+	)
 	switch utxo.AddressType {
 	case lnwallet.WitnessPubKey:
 		witnessType = input.WitnessKeyHash
 	case lnwallet.NestedWitnessPubKey:
 		witnessType = input.NestedWitnessKeyHash
+	case lnwallet.TaprootPubkey:
+		witnessType = input.TaprootPubKeySpend
+		hashType = txscript.SigHashDefault
 	default:
 		return nil, fmt.Errorf("unknown input witness %v", op)
 	}
@@ -837,7 +901,7 @@ func (w *WalletKit) BumpFee(ctx context.Context,
 			PkScript: utxo.PkScript,
 			Value:    int64(utxo.Value),
 		},
-		HashType: txscript.SigHashAll,
+		HashType: hashType,
 	}
 
 	// We'll use the current height as the height hint since we're dealing
@@ -1171,6 +1235,47 @@ func marshallLeases(locks []*wtxmgr.LockedOutput) []*UtxoLease {
 	return rpcLocks
 }
 
+// SignPsbt expects a partial transaction with all inputs and outputs fully
+// declared and tries to sign all unsigned inputs that have all required fields
+// (UTXO information, BIP32 derivation information, witness or sig scripts)
+// set. The inputs are signed with the keys derived from their BIP32 derivation
+// paths, so they don't need to belong to an address the wallet has seen
+// before. If no error is returned, the PSBT is ready to be given to the next
+// signer or to be finalized if lnd was the last signer.
+//
+// NOTE: This RPC only signs inputs (and only those it can sign), it does not
+// perform any other tasks (such as coin selection, UTXO locking or
+// input/output/fee value validation, PSBT finalization). Any input that is
+// incomplete will be skipped.
+func (w *WalletKit) SignPsbt(_ context.Context, req *SignPsbtRequest) (
+	*SignPsbtResponse, error) {
+
+	packet, err := psbt.NewFromRawBytes(
+		bytes.NewReader(req.FundedPsbt), false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing PSBT: %v", err)
+	}
+
+	// Let the wallet do the heavy lifting. This will sign all inputs that
+	// carry the UTXO and the BIP32 derivation path of their key. Inputs
+	// that are incomplete are skipped.
+	err = w.cfg.Wallet.SignPsbt(packet)
+	if err != nil {
+		return nil, fmt.Errorf("error signing PSBT: %v", err)
+	}
+
+	var signedPsbtBytes bytes.Buffer
+	err = packet.Serialize(&signedPsbtBytes)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing PSBT: %v", err)
+	}
+
+	return &SignPsbtResponse{
+		SignedPsbt: signedPsbtBytes.Bytes(),
+	}, nil
+}
+
 // FinalizePsbt expects a partial transaction with all inputs and outputs fully
 // declared and tries to sign all inputs that belong to the wallet. Lnd must be
 // the last signer of the transaction. That means, if there are any unsigned
@@ -1258,9 +1363,19 @@ func marshalWalletAccount(account *waddrmgr.AccountProperties) (*Account, error)
 	case waddrmgr.KeyScopeBIP0084:
 		addrType = AddressType_WITNESS_PUBKEY_HASH
 
+	case waddrmgr.KeyScopeBIP0086:
+		addrType = AddressType_TAPROOT_PUBKEY
+
 	default:
-		return nil, fmt.Errorf("account %v has unsupported "+
-			"key scope %v", account.AccountName, account.KeyScope)
+		// The accounts of our internal key scope hold the keys of all
+		// key families, which are derived as p2wkh keys.
+		if account.KeyScope.Purpose != keychain.BIP0043Purpose {
+			return nil, fmt.Errorf("account %v has unsupported "+
+				"key scope %v", account.AccountName,
+				account.KeyScope)
+		}
+
+		addrType = AddressType_WITNESS_PUBKEY_HASH
 	}
 
 	rpcAccount := &Account{
@@ -1313,6 +1428,10 @@ func (w *WalletKit) ListAccounts(ctx context.Context,
 		keyScope := waddrmgr.KeyScopeBIP0049Plus
 		keyScopeFilter = &keyScope
 
+	case AddressType_TAPROOT_PUBKEY:
+		keyScope := waddrmgr.KeyScopeBIP0086
+		keyScopeFilter = &keyScope
+
 	default:
 		return nil, fmt.Errorf("unhandled address type %v", req.AddressType)
 	}
@@ -1452,7 +1571,7 @@ func (w *WalletKit) ImportAccount(ctx context.Context,
 func (w *WalletKit) ImportPublicKey(ctx context.Context,
 	req *ImportPublicKeyRequest) (*ImportPublicKeyResponse, error) {
 
-	pubKey, err := btcec.ParsePubKey(req.PublicKey, btcec.S256())
+	pubKey, err := btcec.ParsePubKey(req.PublicKey)
 	if err != nil {
 		return nil, err
 	}
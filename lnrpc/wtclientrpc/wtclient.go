@@ -7,7 +7,7 @@ import (
 	"net"
 	"strconv"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnrpc"
@@ -57,6 +57,14 @@ var (
 			Entity: "offchain",
 			Action: "read",
 		}},
+		"/wtclientrpc.WatchtowerClient/ListTowerBacklogs": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/wtclientrpc.WatchtowerClient/RebalanceTowers": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 	}
 
 	// ErrWtclientNotActive signals that RPC calls cannot be processed
@@ -188,7 +196,7 @@ func (c *WatchtowerClient) AddTower(ctx context.Context,
 		return nil, err
 	}
 
-	pubKey, err := btcec.ParsePubKey(req.Pubkey, btcec.S256())
+	pubKey, err := btcec.ParsePubKey(req.Pubkey)
 	if err != nil {
 		return nil, err
 	}
@@ -227,7 +235,7 @@ func (c *WatchtowerClient) RemoveTower(ctx context.Context,
 		return nil, err
 	}
 
-	pubKey, err := btcec.ParsePubKey(req.Pubkey, btcec.S256())
+	pubKey, err := btcec.ParsePubKey(req.Pubkey)
 	if err != nil {
 		return nil, err
 	}
@@ -301,7 +309,7 @@ func (c *WatchtowerClient) GetTowerInfo(ctx context.Context,
 		return nil, err
 	}
 
-	pubKey, err := btcec.ParsePubKey(req.Pubkey, btcec.S256())
+	pubKey, err := btcec.ParsePubKey(req.Pubkey)
 	if err != nil {
 		return nil, err
 	}
@@ -342,6 +350,8 @@ func (c *WatchtowerClient) Stats(ctx context.Context,
 		stats.NumTasksPending += stat.NumTasksPending
 		stats.NumSessionsAcquired += stat.NumSessionsAcquired
 		stats.NumSessionsExhausted += stat.NumSessionsExhausted
+		stats.NumSessionsReclaimed += stat.NumSessionsReclaimed
+		stats.NumUpdatesReclaimed += stat.NumUpdatesReclaimed
 	}
 
 	return &StatsResponse{
@@ -350,6 +360,8 @@ func (c *WatchtowerClient) Stats(ctx context.Context,
 		NumPendingBackups:    uint32(stats.NumTasksPending),
 		NumSessionsAcquired:  uint32(stats.NumSessionsAcquired),
 		NumSessionsExhausted: uint32(stats.NumSessionsExhausted),
+		NumSessionsReclaimed: uint32(stats.NumSessionsReclaimed),
+		NumBackupsReclaimed:  uint32(stats.NumUpdatesReclaimed),
 	}, nil
 }
 
@@ -385,6 +397,123 @@ func (c *WatchtowerClient) Policy(ctx context.Context,
 	}, nil
 }
 
+// ListTowerBacklogs returns the backups that have yet to be delivered to each
+// registered watchtower, along with the observed health of the watchtower.
+func (c *WatchtowerClient) ListTowerBacklogs(ctx context.Context,
+	req *ListTowerBacklogsRequest) (*ListTowerBacklogsResponse, error) {
+
+	if err := c.isActive(); err != nil {
+		return nil, err
+	}
+
+	clients := []struct {
+		client     wtclient.Client
+		policyType PolicyType
+	}{
+		{c.cfg.Client, PolicyType_LEGACY},
+		{c.cfg.AnchorClient, PolicyType_ANCHOR},
+	}
+
+	var rpcBacklogs []*TowerBacklog
+	for _, client := range clients {
+		backlogs, err := client.client.TowerBacklogs()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, backlog := range backlogs {
+			rpcBacklogs = append(
+				rpcBacklogs,
+				marshallTowerBacklog(backlog, client.policyType),
+			)
+		}
+	}
+
+	return &ListTowerBacklogsResponse{Backlogs: rpcBacklogs}, nil
+}
+
+// RebalanceTowers moves the backups that are pending to be committed to the
+// sessions of a watchtower to a session with a different watchtower. If no
+// watchtower is given, the watchtower of the sessions currently in use is
+// rebalanced.
+func (c *WatchtowerClient) RebalanceTowers(ctx context.Context,
+	req *RebalanceTowersRequest) (*RebalanceTowersResponse, error) {
+
+	if err := c.isActive(); err != nil {
+		return nil, err
+	}
+
+	var pubKey *btcec.PublicKey
+	if len(req.Pubkey) > 0 {
+		var err error
+		pubKey, err = btcec.ParsePubKey(req.Pubkey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Without a given watchtower, either client may not have a session in
+	// use, which isn't an error as long as one of them does.
+	var (
+		numMigrated int
+		rebalanced  bool
+	)
+	for _, client := range []wtclient.Client{
+		c.cfg.Client, c.cfg.AnchorClient,
+	} {
+		n, err := client.RebalanceTowers(pubKey)
+		switch {
+		case err == wtclient.ErrNoActiveSession:
+			continue
+
+		case err != nil:
+			return nil, err
+		}
+
+		numMigrated += n
+		rebalanced = true
+	}
+	if !rebalanced {
+		return nil, wtclient.ErrNoActiveSession
+	}
+
+	return &RebalanceTowersResponse{
+		NumMigratedBackups: uint32(numMigrated),
+	}, nil
+}
+
+// marshallTowerBacklog converts the backlog of a watchtower into its
+// corresponding RPC type.
+func marshallTowerBacklog(backlog *wtclient.TowerBacklog,
+	policyType PolicyType) *TowerBacklog {
+
+	health := backlog.Health
+
+	var lastSuccess, unreachableSince int64
+	if !health.LastSuccess.IsZero() {
+		lastSuccess = health.LastSuccess.Unix()
+	}
+	if !health.UnreachableSince.IsZero() {
+		unreachableSince = health.UnreachableSince.Unix()
+	}
+
+	return &TowerBacklog{
+		Pubkey:              backlog.IdentityKey.SerializeCompressed(),
+		PolicyType:          policyType,
+		NumActiveSessions:   backlog.NumActiveSessions,
+		NumPendingBackups:   backlog.NumPendingBackups,
+		NumCommittedBackups: backlog.NumCommittedBackups,
+		Reachable:           health.Reachable,
+		ConsecutiveFailures: health.ConsecutiveFailures,
+		AvgResponseTimeMs: uint32(
+			health.AvgResponseTime.Milliseconds(),
+		),
+		LastSuccess:      lastSuccess,
+		UnreachableSince: unreachableSince,
+		FailedOver:       health.FailedOver,
+	}
+}
+
 // marshallTower converts a client registered watchtower into its corresponding
 // RPC type.
 func marshallTower(tower *wtclient.RegisteredTower, includeSessions bool) *Tower {
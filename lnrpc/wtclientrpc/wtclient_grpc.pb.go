@@ -38,6 +38,16 @@ type WatchtowerClientClient interface {
 	Stats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*StatsResponse, error)
 	// Policy returns the active watchtower client policy configuration.
 	Policy(ctx context.Context, in *PolicyRequest, opts ...grpc.CallOption) (*PolicyResponse, error)
+	//
+	//ListTowerBacklogs returns the backups that have yet to be delivered to each
+	//registered watchtower, along with the observed health of the watchtower.
+	ListTowerBacklogs(ctx context.Context, in *ListTowerBacklogsRequest, opts ...grpc.CallOption) (*ListTowerBacklogsResponse, error)
+	//
+	//RebalanceTowers moves the backups that are pending to be committed to the
+	//sessions of a watchtower to a session with a different watchtower. If no
+	//watchtower is given, the watchtower of the sessions currently in use is
+	//rebalanced.
+	RebalanceTowers(ctx context.Context, in *RebalanceTowersRequest, opts ...grpc.CallOption) (*RebalanceTowersResponse, error)
 }
 
 type watchtowerClientClient struct {
@@ -102,6 +112,24 @@ func (c *watchtowerClientClient) Policy(ctx context.Context, in *PolicyRequest,
 	return out, nil
 }
 
+func (c *watchtowerClientClient) ListTowerBacklogs(ctx context.Context, in *ListTowerBacklogsRequest, opts ...grpc.CallOption) (*ListTowerBacklogsResponse, error) {
+	out := new(ListTowerBacklogsResponse)
+	err := c.cc.Invoke(ctx, "/wtclientrpc.WatchtowerClient/ListTowerBacklogs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *watchtowerClientClient) RebalanceTowers(ctx context.Context, in *RebalanceTowersRequest, opts ...grpc.CallOption) (*RebalanceTowersResponse, error) {
+	out := new(RebalanceTowersResponse)
+	err := c.cc.Invoke(ctx, "/wtclientrpc.WatchtowerClient/RebalanceTowers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WatchtowerClientServer is the server API for WatchtowerClient service.
 // All implementations must embed UnimplementedWatchtowerClientServer
 // for forward compatibility
@@ -126,6 +154,16 @@ type WatchtowerClientServer interface {
 	Stats(context.Context, *StatsRequest) (*StatsResponse, error)
 	// Policy returns the active watchtower client policy configuration.
 	Policy(context.Context, *PolicyRequest) (*PolicyResponse, error)
+	//
+	//ListTowerBacklogs returns the backups that have yet to be delivered to each
+	//registered watchtower, along with the observed health of the watchtower.
+	ListTowerBacklogs(context.Context, *ListTowerBacklogsRequest) (*ListTowerBacklogsResponse, error)
+	//
+	//RebalanceTowers moves the backups that are pending to be committed to the
+	//sessions of a watchtower to a session with a different watchtower. If no
+	//watchtower is given, the watchtower of the sessions currently in use is
+	//rebalanced.
+	RebalanceTowers(context.Context, *RebalanceTowersRequest) (*RebalanceTowersResponse, error)
 	mustEmbedUnimplementedWatchtowerClientServer()
 }
 
@@ -151,6 +189,12 @@ func (UnimplementedWatchtowerClientServer) Stats(context.Context, *StatsRequest)
 func (UnimplementedWatchtowerClientServer) Policy(context.Context, *PolicyRequest) (*PolicyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Policy not implemented")
 }
+func (UnimplementedWatchtowerClientServer) ListTowerBacklogs(context.Context, *ListTowerBacklogsRequest) (*ListTowerBacklogsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTowerBacklogs not implemented")
+}
+func (UnimplementedWatchtowerClientServer) RebalanceTowers(context.Context, *RebalanceTowersRequest) (*RebalanceTowersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RebalanceTowers not implemented")
+}
 func (UnimplementedWatchtowerClientServer) mustEmbedUnimplementedWatchtowerClientServer() {}
 
 // UnsafeWatchtowerClientServer may be embedded to opt out of forward compatibility for this service.
@@ -272,6 +316,42 @@ func _WatchtowerClient_Policy_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _WatchtowerClient_ListTowerBacklogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTowerBacklogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchtowerClientServer).ListTowerBacklogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wtclientrpc.WatchtowerClient/ListTowerBacklogs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchtowerClientServer).ListTowerBacklogs(ctx, req.(*ListTowerBacklogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WatchtowerClient_RebalanceTowers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RebalanceTowersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WatchtowerClientServer).RebalanceTowers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/wtclientrpc.WatchtowerClient/RebalanceTowers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WatchtowerClientServer).RebalanceTowers(ctx, req.(*RebalanceTowersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // WatchtowerClient_ServiceDesc is the grpc.ServiceDesc for WatchtowerClient service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -303,6 +383,14 @@ var WatchtowerClient_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Policy",
 			Handler:    _WatchtowerClient_Policy_Handler,
 		},
+		{
+			MethodName: "ListTowerBacklogs",
+			Handler:    _WatchtowerClient_ListTowerBacklogs_Handler,
+		},
+		{
+			MethodName: "RebalanceTowers",
+			Handler:    _WatchtowerClient_RebalanceTowers_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "wtclientrpc/wtclient.proto",
@@ -1,14 +1,15 @@
 package itest
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"fmt"
 	"sync"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/chainreg"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
@@ -41,8 +42,9 @@ type interceptorTestCase struct {
 // 1. Intercepted failed htlcs result in no payment (invoice is not settled).
 // 2. Intercepted resumed htlcs result in a payment (invoice is settled).
 // 3. Intercepted held htlcs result in no payment (invoice is not settled).
-// 4. When Interceptor disconnects it resumes all held htlcs, which result in
-//    valid payment (invoice is settled).
+// 4. When Interceptor disconnects the htlcs remain held, and they are offered
+//    again to the next interceptor that connects. When it resumes them, they
+//    result in valid payment (invoice is settled).
 func testForwardInterceptor(net *lntest.NetworkHarness, t *harnessTest) {
 	// Initialize the test context with 3 connected nodes.
 	alice := net.NewNode(t.t, "alice", nil)
@@ -217,10 +219,18 @@ func testForwardInterceptor(net *lntest.NetworkHarness, t *harnessTest) {
 		}
 	}
 
-	// Disconnect interceptor should cause resume held packets.
+	// Disconnecting the interceptor keeps the packets held. They are
+	// offered again once a new interceptor connects, which resumes them.
 	// After that we wait for all go routines to finish, including the one
 	// that tests the payment final status for the held payment.
 	cancelInterceptor()
+	for _, testCase := range testCases {
+		if !testCase.shouldHold {
+			continue
+		}
+
+		testContext.resumeReofferedHtlc(testCase.invoice.RHash)
+	}
 	wg.Wait()
 
 	// Verify that we don't get notified about already completed HTLCs
@@ -305,6 +315,45 @@ func newInterceptorTestContext(t *harnessTest,
 	return &ctx
 }
 
+// resumeReofferedHtlc connects a new interceptor to bob and resumes the held
+// htlc with the given payment hash once it is offered again. As the previous
+// interceptor may still be registered for a short moment, the connection is
+// retried until it succeeds.
+func (c *interceptorTestContext) resumeReofferedHtlc(payHash []byte) {
+	err := wait.NoError(func() error {
+		ctxt, cancel := context.WithTimeout(
+			context.Background(), defaultTimeout,
+		)
+		defer cancel()
+
+		interceptor, err := c.bob.RouterClient.HtlcInterceptor(ctxt)
+		if err != nil {
+			return err
+		}
+
+		request, err := interceptor.Recv()
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Equal(payHash, request.PaymentHash) {
+			return fmt.Errorf("unexpected htlc %x offered",
+				request.PaymentHash)
+		}
+		if request.AutoFailHeight == 0 {
+			return fmt.Errorf("missing auto fail height")
+		}
+
+		resume := routerrpc.ResolveHoldForwardAction_RESUME
+
+		return interceptor.Send(&routerrpc.ForwardHtlcInterceptResponse{
+			IncomingCircuitKey: request.IncomingCircuitKey,
+			Action:             resume,
+		})
+	}, defaultTimeout)
+	require.NoError(c.t.t, err, "held htlc was not offered again")
+}
+
 // prepareTestCases prepares 4 tests:
 // 1. failed htlc.
 // 2. resumed htlc.
@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnrpc/signrpc"
 	"github.com/lightningnetwork/lnd/lntest"
@@ -20,7 +20,7 @@ func testDeriveSharedKey(net *lntest.NetworkHarness, t *harnessTest) {
 
 	// Create an ephemeral key, extracts its public key, and make a
 	// PrivKeyECDH using the ephemeral key.
-	ephemeralPriv, err := btcec.NewPrivateKey(btcec.S256())
+	ephemeralPriv, err := btcec.NewPrivateKey()
 	require.NoError(t.t, err, "failed to create ephemeral key")
 
 	ephemeralPubBytes := ephemeralPriv.PubKey().SerializeCompressed()
@@ -42,7 +42,7 @@ func testDeriveSharedKey(net *lntest.NetworkHarness, t *harnessTest) {
 		)
 	}
 
-	nodePub, err := btcec.ParsePubKey(net.Alice.PubKey[:], btcec.S256())
+	nodePub, err := btcec.ParsePubKey(net.Alice.PubKey[:])
 	require.NoError(t.t, err, "failed to parse node pubkey")
 
 	customizedKeyFamily := int32(keychain.KeyFamilyMultiSig)
@@ -196,7 +196,7 @@ func deriveCustomizedKey(ctx context.Context, node *lntest.HarnessNode,
 	if err != nil {
 		return nil, fmt.Errorf("failed to derive key: %v", err)
 	}
-	pub, err := btcec.ParsePubKey(resp.RawKeyBytes, btcec.S256())
+	pub, err := btcec.ParsePubKey(resp.RawKeyBytes)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse node pubkey: %v", err)
 	}
@@ -44,7 +44,7 @@ var (
 	)
 
 	// dbBackendFlag specifies the backend to use
-	dbBackendFlag = flag.String("dbbackend", "bbolt", "Database backend (bbolt, etcd)")
+	dbBackendFlag = flag.String("dbbackend", "bbolt", "Database backend (bbolt, etcd, sqlite)")
 )
 
 // getTestCaseSplitTranche returns the sub slice of the test cases that should
@@ -153,6 +153,9 @@ func TestLightningNetworkDaemon(t *testing.T) {
 	case "etcd":
 		dbBackend = lntest.BackendEtcd
 
+	case "sqlite":
+		dbBackend = lntest.BackendSqlite
+
 	default:
 		require.Fail(t, "unknown db backend")
 	}
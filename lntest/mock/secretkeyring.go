@@ -1,7 +1,9 @@
 package mock
 
 import (
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 
 	"github.com/lightningnetwork/lnd/keychain"
 )
@@ -41,16 +43,28 @@ func (s *SecretKeyRing) ECDH(_ keychain.KeyDescriptor, pubKey *btcec.PublicKey)
 	return [32]byte{}, nil
 }
 
-// SignDigest signs the passed digest and ignores the KeyDescriptor.
-func (s *SecretKeyRing) SignDigest(_ keychain.KeyDescriptor,
-	digest [32]byte) (*btcec.Signature, error) {
+// SignMessage signs the passed message and ignores the KeyDescriptor.
+func (s *SecretKeyRing) SignMessage(_ keychain.KeyLocator,
+	msg []byte, doubleHash bool) (*ecdsa.Signature, error) {
 
-	return s.RootKey.Sign(digest[:])
+	var digest []byte
+	if doubleHash {
+		digest = chainhash.DoubleHashB(msg)
+	} else {
+		digest = chainhash.HashB(msg)
+	}
+	return ecdsa.Sign(s.RootKey, digest), nil
 }
 
-// SignDigestCompact signs the passed digest.
-func (s *SecretKeyRing) SignDigestCompact(_ keychain.KeyDescriptor,
-	digest [32]byte) ([]byte, error) {
+// SignMessageCompact signs the passed message.
+func (s *SecretKeyRing) SignMessageCompact(_ keychain.KeyLocator,
+	msg []byte, doubleHash bool) ([]byte, error) {
 
-	return btcec.SignCompact(btcec.S256(), s.RootKey, digest[:], true)
+	var digest []byte
+	if doubleHash {
+		digest = chainhash.DoubleHashB(msg)
+	} else {
+		digest = chainhash.HashB(msg)
+	}
+	return ecdsa.SignCompact(s.RootKey, digest, true)
 }
@@ -3,12 +3,14 @@ package mock
 import (
 	"fmt"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 
 	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
 )
 
 // DummySignature is a dummy Signature implementation.
@@ -46,6 +48,7 @@ func (d *DummySigner) ComputeInputScript(tx *wire.MsgTx,
 // everything with a single private key.
 type SingleSigner struct {
 	Privkey *btcec.PrivateKey
+	KeyLoc  keychain.KeyLocator
 }
 
 // SignOutputRaw generates a signature for the passed transaction using the
@@ -77,7 +80,7 @@ func (s *SingleSigner) SignOutputRaw(tx *wire.MsgTx,
 		return nil, err
 	}
 
-	return btcec.ParseDERSignature(sig[:len(sig)-1], btcec.S256())
+	return ecdsa.ParseDERSignature(sig[:len(sig)-1])
 }
 
 // ComputeInputScript computes an input script with the stored private key
@@ -108,20 +111,20 @@ func (s *SingleSigner) ComputeInputScript(tx *wire.MsgTx,
 	}, nil
 }
 
-// SignMessage takes a public key and a message and only signs the message
-// with the stored private key if the public key matches the private key.
-func (s *SingleSigner) SignMessage(pubKey *btcec.PublicKey,
-	msg []byte) (input.Signature, error) {
+// SignMessage takes a key locator and a message and only signs the message
+// with the stored private key if the key locator matches the stored one.
+func (s *SingleSigner) SignMessage(keyLoc keychain.KeyLocator,
+	msg []byte, doubleHash bool) (*ecdsa.Signature, error) {
 
-	if !pubKey.IsEqual(s.Privkey.PubKey()) {
-		return nil, fmt.Errorf("unknown public key")
+	if keyLoc != s.KeyLoc {
+		return nil, fmt.Errorf("unknown key locator")
 	}
 
-	digest := chainhash.DoubleHashB(msg)
-	sign, err := s.Privkey.Sign(digest)
-	if err != nil {
-		return nil, fmt.Errorf("can't sign the message: %v", err)
+	var digest []byte
+	if doubleHash {
+		digest = chainhash.DoubleHashB(msg)
+	} else {
+		digest = chainhash.HashB(msg)
 	}
-
-	return sign, nil
+	return ecdsa.Sign(s.Privkey, digest), nil
 }
@@ -9,14 +9,14 @@ import (
 	"sync"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
-	"github.com/btcsuite/btcutil/hdkeychain"
-	"github.com/btcsuite/btcutil/psbt"
 	"github.com/btcsuite/btcwallet/chain"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/wallet"
@@ -75,6 +75,16 @@ var (
 	// new address being requested is incompatible with the account.
 	errIncompatibleAccountAddr = errors.New("incompatible address type " +
 		"for account")
+
+	// ErrWalletNotWatchOnly is returned when the wallet is configured to
+	// be watch-only but the wallet on disk contains private keys.
+	ErrWalletNotWatchOnly = errors.New("wallet contains private keys " +
+		"but must be watch-only when remote signing is enabled")
+
+	// ErrWalletWatchOnly is returned when the wallet on disk is a
+	// watch-only wallet but the wallet isn't configured to be watch-only.
+	ErrWalletWatchOnly = errors.New("wallet is watch-only and can only " +
+		"be used with remote signing enabled")
 )
 
 // BtcWallet is an implementation of the lnwallet.WalletController interface
@@ -136,7 +146,16 @@ func New(cfg Config, blockCache *blockcache.BlockCache) (*BtcWallet, error) {
 			return nil, err
 		}
 
-		if !walletExists {
+		switch {
+		// A watch-only wallet can only be created from the account
+		// xpubs of the remote signer, which happens through the
+		// WalletUnlocker.
+		case !walletExists && cfg.WatchOnly:
+			return nil, fmt.Errorf("watch-only wallet does not " +
+				"exist, it must be created with the account " +
+				"xpubs of the remote signer")
+
+		case !walletExists:
 			// Wallet has never been created, perform initial
 			// set up.
 			wallet, err = loader.CreateNewWallet(
@@ -146,7 +165,8 @@ func New(cfg Config, blockCache *blockcache.BlockCache) (*BtcWallet, error) {
 			if err != nil {
 				return nil, err
 			}
-		} else {
+
+		default:
 			// Wallet has been created and been initialized at
 			// this point, open it along with all the required DB
 			// namespaces, and the DB itself.
@@ -288,6 +308,54 @@ func (b *BtcWallet) InternalWallet() *base.Wallet {
 //
 // This is a part of the WalletController interface.
 func (b *BtcWallet) Start() error {
+	// A node that uses a remote signer must not hold any private keys, so
+	// we refuse to start with a seed-bearing wallet in that case. A
+	// watch-only wallet on the other hand can't sign anything itself.
+	watchOnly := b.wallet.Manager.WatchOnly()
+	switch {
+	case b.cfg.WatchOnly && !watchOnly:
+		return ErrWalletNotWatchOnly
+
+	case !b.cfg.WatchOnly && watchOnly:
+		return ErrWalletWatchOnly
+
+	// A watch-only wallet can't be unlocked and can't create any new key
+	// scopes or accounts, so all of them must have been imported when the
+	// wallet was created.
+	case watchOnly:
+		_, err := b.wallet.Manager.FetchScopedKeyManager(
+			b.chainKeyScope,
+		)
+		if err != nil {
+			return fmt.Errorf("watch-only wallet is missing key "+
+				"scope %v: %w", b.chainKeyScope, err)
+		}
+
+	default:
+		if err := b.initKeyScopes(); err != nil {
+			return err
+		}
+	}
+
+	// Establish an RPC connection in addition to starting the goroutines
+	// in the underlying wallet.
+	if err := b.chain.Start(); err != nil {
+		return err
+	}
+
+	// Start the underlying btcwallet core.
+	b.wallet.Start()
+
+	// Pass the rpc client into the wallet so it can sync up to the
+	// current main chain.
+	b.wallet.SynchronizeRPC(b.chain)
+
+	return nil
+}
+
+// initKeyScopes unlocks the wallet and makes sure all key scopes and accounts
+// we need exist within the internal waddrmgr.
+func (b *BtcWallet) initKeyScopes() error {
 	// We'll start by unlocking the wallet and ensuring that the KeyScope:
 	// (1017, 1) exists within the internal waddrmgr. We'll need this in
 	// order to properly generate the keys required for signing various
@@ -295,7 +363,7 @@ func (b *BtcWallet) Start() error {
 	if err := b.wallet.Unlock(b.cfg.PrivatePass, nil); err != nil {
 		return err
 	}
-	_, err := b.wallet.Manager.FetchScopedKeyManager(b.chainKeyScope)
+	scope, err := b.wallet.Manager.FetchScopedKeyManager(b.chainKeyScope)
 	if err != nil {
 		// If the scope hasn't yet been created (it wouldn't been
 		// loaded by default if it was), then we'll manually create the
@@ -303,7 +371,8 @@ func (b *BtcWallet) Start() error {
 		err := walletdb.Update(b.db, func(tx walletdb.ReadWriteTx) error {
 			addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
 
-			_, err := b.wallet.Manager.NewScopedKeyManager(
+			var err error
+			scope, err = b.wallet.Manager.NewScopedKeyManager(
 				addrmgrNs, b.chainKeyScope, lightningAddrSchema,
 			)
 			return err
@@ -313,18 +382,54 @@ func (b *BtcWallet) Start() error {
 		}
 	}
 
-	// Establish an RPC connection in addition to starting the goroutines
-	// in the underlying wallet.
-	if err := b.chain.Start(); err != nil {
+	// The accounts of the key families are usually created lazily, but a
+	// watch-only node needs the xpubs of all of them when it's created
+	// from this wallet, so we make sure they all exist.
+	err = walletdb.Update(b.db, func(tx walletdb.ReadWriteTx) error {
+		addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+
+		for keyFam := keychain.KeyFamilyRevocationBase; keyFam <=
+			keychain.KeyFamilyTowerID; keyFam++ {
+
+			_, err := scope.AccountName(addrmgrNs, uint32(keyFam))
+			if err == nil {
+				continue
+			}
+
+			err = scope.NewRawAccount(addrmgrNs, uint32(keyFam))
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
 		return err
 	}
 
-	// Start the underlying btcwallet core.
-	b.wallet.Start()
+	// New default key scopes, such as the BIP-86 scope for taproot
+	// addresses, are only created for new wallets, so we'll add any of
+	// them that are missing to an existing wallet now.
+	for _, scope := range waddrmgr.DefaultKeyScopes {
+		_, err := b.wallet.Manager.FetchScopedKeyManager(scope)
+		if !waddrmgr.IsError(err, waddrmgr.ErrScopeNotFound) {
+			continue
+		}
 
-	// Pass the rpc client into the wallet so it can sync up to the
-	// current main chain.
-	b.wallet.SynchronizeRPC(b.chain)
+		addrSchema := waddrmgr.ScopeAddrMap[scope]
+		err = walletdb.Update(b.db, func(tx walletdb.ReadWriteTx) error {
+			addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+
+			_, err := b.wallet.Manager.NewScopedKeyManager(
+				addrmgrNs, scope, addrSchema,
+			)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -382,6 +487,8 @@ func (b *BtcWallet) keyScopeForAccountAddr(accountName string,
 		addrKeyScope = waddrmgr.KeyScopeBIP0084
 	case lnwallet.NestedWitnessPubKey:
 		addrKeyScope = waddrmgr.KeyScopeBIP0049Plus
+	case lnwallet.TaprootPubkey:
+		addrKeyScope = waddrmgr.KeyScopeBIP0086
 	default:
 		return waddrmgr.KeyScope{}, 0,
 			fmt.Errorf("unknown address type")
@@ -486,7 +593,7 @@ func (b *BtcWallet) ListAccounts(name string,
 	// Only the name filter was provided.
 	case name != "" && keyScope == nil:
 		// If the name corresponds to the default or imported accounts,
-		// we'll return them for both of our supported key scopes.
+		// we'll return them for all of our supported key scopes.
 		if name == lnwallet.DefaultAccountName ||
 			name == waddrmgr.ImportedAddrAccountName {
 
@@ -505,6 +612,14 @@ func (b *BtcWallet) ListAccounts(name string,
 				return nil, err
 			}
 			res = append(res, a2)
+
+			a3, err := b.wallet.AccountPropertiesByName(
+				waddrmgr.KeyScopeBIP0086, name,
+			)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, a3)
 			break
 		}
 
@@ -552,6 +667,27 @@ func (b *BtcWallet) ListAccounts(name string,
 			account := account
 			res = append(res, &account.AccountProperties)
 		}
+
+		accounts, err = b.wallet.Accounts(waddrmgr.KeyScopeBIP0086)
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range accounts.Accounts {
+			account := account
+			res = append(res, &account.AccountProperties)
+		}
+
+		// We also include the accounts of our internal key scope that
+		// hold the keys of all key families, as a watch-only node
+		// needs their xpubs when it's created from this wallet.
+		accounts, err = b.wallet.Accounts(b.chainKeyScope)
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range accounts.Accounts {
+			account := account
+			res = append(res, &account.AccountProperties)
+		}
 	}
 
 	return res, nil
@@ -758,7 +894,17 @@ func (b *BtcWallet) LeaseOutput(id wtxmgr.LockID, op wire.OutPoint,
 
 // ListLeasedOutputs returns a list of all currently locked outputs.
 func (b *BtcWallet) ListLeasedOutputs() ([]*wtxmgr.LockedOutput, error) {
-	return b.wallet.ListLeasedOutputs()
+	leases, err := b.wallet.ListLeasedOutputs()
+	if err != nil {
+		return nil, err
+	}
+
+	lockedOutputs := make([]*wtxmgr.LockedOutput, 0, len(leases))
+	for _, lease := range leases {
+		lockedOutputs = append(lockedOutputs, lease.LockedOutput)
+	}
+
+	return lockedOutputs, nil
 }
 
 // ReleaseOutput unlocks an output, allowing it to be available for coin
@@ -794,7 +940,8 @@ func (b *BtcWallet) ListUnspentWitness(minConfs, maxConfs int32,
 	}
 
 	// Next, we'll run through all the regular outputs, only saving those
-	// which are p2wkh outputs or a p2wsh output nested within a p2sh output.
+	// which are p2wkh outputs, p2wkh outputs nested within a p2sh output or
+	// p2tr outputs.
 	witnessOutputs := make([]*lnwallet.Utxo, 0, len(unspentOutputs))
 	for _, output := range unspentOutputs {
 		pkScript, err := hex.DecodeString(output.ScriptPubKey)
@@ -810,10 +957,13 @@ func (b *BtcWallet) ListUnspentWitness(minConfs, maxConfs int32,
 			// wallet are nested p2pkh. We can't check the redeem script because
 			// the btcwallet service does not include it.
 			addressType = lnwallet.NestedWitnessPubKey
+		} else if txscript.IsPayToTaproot(pkScript) {
+			addressType = lnwallet.TaprootPubkey
 		}
 
 		if addressType == lnwallet.WitnessPubKey ||
-			addressType == lnwallet.NestedWitnessPubKey {
+			addressType == lnwallet.NestedWitnessPubKey ||
+			addressType == lnwallet.TaprootPubkey {
 
 			txid, err := chainhash.NewHashFromStr(output.TxID)
 			if err != nil {
@@ -1109,13 +1259,130 @@ func (b *BtcWallet) FundPsbt(packet *psbt.Packet,
 	}
 
 	// Let the wallet handle coin selection and/or fee estimation based on
-	// the partial TX information in the packet.
+	// the partial TX information in the packet. Only confirmed outputs are
+	// selected.
 	return b.wallet.FundPsbt(
-		packet, keyScope, accountNum, feeSatPerKB,
+		packet, keyScope, 1, accountNum, feeSatPerKB,
 		b.cfg.CoinSelectionStrategy,
 	)
 }
 
+// SignPsbt expects a partial transaction with all inputs and outputs fully
+// declared and tries to sign all unsigned inputs that have all required fields
+// (UTXO information, BIP32 derivation information, witness or sig scripts)
+// set. The inputs are signed with the keys derived from their BIP32 derivation
+// paths, so they don't need to belong to an address the wallet has seen
+// before. If no error is returned, the PSBT is ready to be given to the next
+// signer or to be finalized if lnd was the last signer.
+//
+// NOTE: This method only signs inputs (and only those it can sign), it does
+// not perform any other tasks (such as coin selection, UTXO locking or
+// input/output/fee value validation, PSBT finalization). Any input that is
+// incomplete will be skipped.
+//
+// This is a part of the WalletController interface.
+func (b *BtcWallet) SignPsbt(packet *psbt.Packet) error {
+	// Let's check that this is actually something we can and want to sign.
+	// We need at least one input and one output.
+	err := psbt.VerifyInputOutputLen(packet, true, true)
+	if err != nil {
+		return err
+	}
+
+	// The sighashes of taproot inputs commit to the outputs spent by all
+	// inputs, so we need to know all of them, even those we don't sign.
+	for idx, in := range packet.Inputs {
+		if in.WitnessUtxo == nil && in.NonWitnessUtxo == nil {
+			return fmt.Errorf("input %d is missing UTXO "+
+				"information", idx)
+		}
+	}
+
+	tx := packet.UnsignedTx
+	prevOutFetcher := base.PsbtPrevOutputFetcher(packet)
+	sigHashes := txscript.NewTxSigHashes(tx, prevOutFetcher)
+
+	for idx := range packet.Inputs {
+		in := &packet.Inputs[idx]
+
+		// We can only sign inputs that aren't final yet and that carry
+		// both the UTXO and the derivation path of their key.
+		isFinal := len(in.FinalScriptWitness) > 0 ||
+			len(in.FinalScriptSig) > 0
+		if isFinal || in.WitnessUtxo == nil ||
+			len(in.Bip32Derivation) != 1 {
+
+			continue
+		}
+
+		derivation := in.Bip32Derivation[0]
+		privKey, err := b.deriveKeyByBIP32Path(derivation.Bip32Path)
+		if err != nil {
+			return fmt.Errorf("unable to derive key for input %d: "+
+				"%w", idx, err)
+		}
+
+		// Make sure we don't sign with a key other than the one the
+		// input commits to.
+		pubKey := privKey.PubKey().SerializeCompressed()
+		if !bytes.Equal(pubKey, derivation.PubKey) {
+			return fmt.Errorf("derived key for input %d doesn't "+
+				"match the key of its derivation path", idx)
+		}
+
+		utxo := in.WitnessUtxo
+		isNestedP2WKH := txscript.IsPayToScriptHash(utxo.PkScript) &&
+			txscript.IsPayToWitnessPubKeyHash(in.RedeemScript)
+
+		switch {
+		// Taproot key spends are signed with a schnorr signature over
+		// the BIP-86 tweaked key.
+		case txscript.IsPayToTaproot(utxo.PkScript):
+			sig, err := txscript.RawTxInTaprootSignature(
+				tx, sigHashes, idx, utxo.Value, utxo.PkScript,
+				[]byte{}, in.SighashType, privKey,
+			)
+			if err != nil {
+				return err
+			}
+			in.TaprootKeySpendSig = sig
+
+		// For p2wkh and np2wkh inputs the witness program is the p2wkh
+		// script, which for the nested case is the redeem script.
+		case txscript.IsPayToWitnessPubKeyHash(utxo.PkScript),
+			isNestedP2WKH:
+
+			witnessProgram := utxo.PkScript
+			if isNestedP2WKH {
+				witnessProgram = in.RedeemScript
+			}
+
+			hashType := in.SighashType
+			if hashType == txscript.SigHashDefault {
+				hashType = txscript.SigHashAll
+			}
+
+			sig, err := txscript.RawTxInWitnessSignature(
+				tx, sigHashes, idx, utxo.Value, witnessProgram,
+				hashType, privKey,
+			)
+			if err != nil {
+				return err
+			}
+			partialSig := &psbt.PartialSig{
+				PubKey:    pubKey,
+				Signature: sig,
+			}
+			in.PartialSigs = append(in.PartialSigs, partialSig)
+
+		default:
+			continue
+		}
+	}
+
+	return nil
+}
+
 // FinalizePsbt expects a partial transaction with all inputs and outputs fully
 // declared and tries to sign all inputs that belong to the specified account.
 // Lnd must be the last signer of the transaction. That means, if there are any
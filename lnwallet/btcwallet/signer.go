@@ -1,14 +1,17 @@
 package btcwallet
 
 import (
-	"github.com/btcsuite/btcd/btcec"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/walletdb"
-	"github.com/go-errors/errors"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwallet"
@@ -33,6 +36,8 @@ func (b *BtcWallet) FetchInputInfo(prevOut *wire.OutPoint) (*lnwallet.Utxo, erro
 		addressType = lnwallet.WitnessPubKey
 	case txscript.IsPayToScriptHash(txOut.PkScript):
 		addressType = lnwallet.NestedWitnessPubKey
+	case txscript.IsPayToTaproot(txOut.PkScript):
+		addressType = lnwallet.TaprootPubkey
 	}
 
 	return &lnwallet.Utxo{
@@ -44,6 +49,71 @@ func (b *BtcWallet) FetchInputInfo(prevOut *wire.OutPoint) (*lnwallet.Utxo, erro
 	}, nil
 }
 
+// ScriptForOutput returns the address, witness program and redeem script for a
+// given UTXO. An error is returned if the UTXO does not belong to our wallet or
+// it is not a managed pubKey address.
+//
+// This is a part of the WalletController interface.
+func (b *BtcWallet) ScriptForOutput(output *wire.TxOut) (
+	waddrmgr.ManagedPubKeyAddress, []byte, []byte, error) {
+
+	return b.wallet.ScriptForOutput(output)
+}
+
+// deriveKeyByBIP32Path derives the private key of a full BIP32 derivation path
+// of the form m/purpose'/coin_type'/account'/branch/index.
+func (b *BtcWallet) deriveKeyByBIP32Path(
+	path []uint32) (*btcec.PrivateKey, error) {
+
+	if len(path) != 5 {
+		return nil, fmt.Errorf("invalid BIP32 derivation path length "+
+			"%d, expected 5", len(path))
+	}
+	for i := 0; i < 3; i++ {
+		if path[i] < hdkeychain.HardenedKeyStart {
+			return nil, fmt.Errorf("element %d of BIP32 "+
+				"derivation path must be hardened", i)
+		}
+	}
+
+	scope := waddrmgr.KeyScope{
+		Purpose: path[0] - hdkeychain.HardenedKeyStart,
+		Coin:    path[1] - hdkeychain.HardenedKeyStart,
+	}
+	scopedMgr, err := b.wallet.Manager.FetchScopedKeyManager(scope)
+	if err != nil {
+		return nil, err
+	}
+
+	account := path[2] - hdkeychain.HardenedKeyStart
+	derivationPath := waddrmgr.DerivationPath{
+		InternalAccount: account,
+		Account:         account,
+		Branch:          path[3],
+		Index:           path[4],
+	}
+
+	var key *btcec.PrivateKey
+	err = walletdb.View(b.db, func(tx walletdb.ReadTx) error {
+		addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+
+		addr, err := scopedMgr.DeriveFromKeyPath(
+			addrmgrNs, derivationPath,
+		)
+		if err != nil {
+			return err
+		}
+
+		key, err = addr.(waddrmgr.ManagedPubKeyAddress).PrivKey()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
 // deriveFromKeyLoc attempts to derive a private key using a fully specified
 // KeyLocator.
 func deriveFromKeyLoc(scopedMgr *waddrmgr.ScopedKeyManager,
@@ -208,6 +278,12 @@ func (b *BtcWallet) SignOutputRaw(tx *wire.MsgTx,
 		return nil, err
 	}
 
+	// Taproot inputs are signed with a schnorr signature over the BIP-341
+	// sighash, for which the key is tweaked with the taproot tweak.
+	if signDesc.SignMethod.IsTaproot() {
+		return input.TaprootKeySpendSignature(tx, signDesc, privKey)
+	}
+
 	// TODO(roasbeef): generate sighash midstate if not present?
 
 	amt := signDesc.Output.Value
@@ -220,7 +296,7 @@ func (b *BtcWallet) SignOutputRaw(tx *wire.MsgTx,
 	}
 
 	// Chop off the sighash flag at the end of the signature.
-	return btcec.ParseDERSignature(sig[:len(sig)-1], btcec.S256())
+	return ecdsa.ParseDERSignature(sig[:len(sig)-1])
 }
 
 // ComputeInputScript generates a complete InputScript for the passed
@@ -258,32 +334,30 @@ func (b *BtcWallet) ComputeInputScript(tx *wire.MsgTx,
 // interface.
 var _ input.Signer = (*BtcWallet)(nil)
 
-// SignMessage attempts to sign a target message with the private key that
-// corresponds to the passed public key. If the target private key is unable to
-// be found, then an error will be returned. The actual digest signed is the
-// double SHA-256 of the passed message.
+// SignMessage attempts to sign a target message with the private key described
+// in the key locator. If the target private key is unable to be found, then an
+// error will be returned. The actual digest signed is the single or double
+// SHA-256 of the passed message.
 //
 // NOTE: This is a part of the MessageSigner interface.
-func (b *BtcWallet) SignMessage(pubKey *btcec.PublicKey,
-	msg []byte) (input.Signature, error) {
+func (b *BtcWallet) SignMessage(keyLoc keychain.KeyLocator,
+	msg []byte, doubleHash bool) (*ecdsa.Signature, error) {
 
 	// First attempt to fetch the private key which corresponds to the
-	// specified public key.
-	privKey, err := b.fetchPrivKey(&keychain.KeyDescriptor{
-		PubKey: pubKey,
-	})
+	// specified key locator.
+	privKey, err := b.deriveKeyByLocator(keyLoc)
 	if err != nil {
 		return nil, err
 	}
 
-	// Double hash and sign the data.
-	msgDigest := chainhash.DoubleHashB(msg)
-	sign, err := privKey.Sign(msgDigest)
-	if err != nil {
-		return nil, errors.Errorf("unable sign the message: %v", err)
+	// Hash and sign the data.
+	var msgDigest []byte
+	if doubleHash {
+		msgDigest = chainhash.DoubleHashB(msg)
+	} else {
+		msgDigest = chainhash.HashB(msg)
 	}
-
-	return sign, nil
+	return ecdsa.Sign(privKey, msgDigest), nil
 }
 
 // A compile time check to ensure that BtcWallet implements the MessageSigner
@@ -0,0 +1,473 @@
+package chainfee
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// AggregationPolicy determines how the estimates of the sources of a
+// CompositeEstimator are combined into a single fee rate.
+type AggregationPolicy uint8
+
+const (
+	// PolicyMedian uses the median of the estimates of all sources that
+	// returned one. With an even number of estimates, the mean of the two
+	// middle ones is used.
+	PolicyMedian AggregationPolicy = iota
+
+	// PolicyMin uses the lowest estimate of all sources that returned
+	// one.
+	PolicyMin
+
+	// PolicyMax uses the highest estimate of all sources that returned
+	// one.
+	PolicyMax
+)
+
+// String returns the name of the aggregation policy.
+func (p AggregationPolicy) String() string {
+	switch p {
+	case PolicyMedian:
+		return "median"
+
+	case PolicyMin:
+		return "min"
+
+	case PolicyMax:
+		return "max"
+
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(p))
+	}
+}
+
+// ParseAggregationPolicy returns the aggregation policy with the given name.
+func ParseAggregationPolicy(name string) (AggregationPolicy, error) {
+	switch name {
+	case "median":
+		return PolicyMedian, nil
+
+	case "min":
+		return PolicyMin, nil
+
+	case "max":
+		return PolicyMax, nil
+
+	default:
+		return 0, fmt.Errorf("unknown fee aggregation policy %q", name)
+	}
+}
+
+// aggregate combines the passed estimates, of which there must be at least
+// one, according to the policy.
+func (p AggregationPolicy) aggregate(estimates []SatPerKWeight) SatPerKWeight {
+	sorted := make([]SatPerKWeight, len(estimates))
+	copy(sorted, estimates)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	switch p {
+	case PolicyMin:
+		return sorted[0]
+
+	case PolicyMax:
+		return sorted[len(sorted)-1]
+
+	default:
+		mid := len(sorted) / 2
+		if len(sorted)%2 == 0 {
+			return (sorted[mid-1] + sorted[mid]) / 2
+		}
+
+		return sorted[mid]
+	}
+}
+
+// FeeBound is a sanity bound for the fee rates returned for a range of
+// confirmation targets. A bound applies to its confirmation target and all
+// higher ones, up to the confirmation target of the next bound.
+type FeeBound struct {
+	// ConfTarget is the lowest confirmation target the bound applies to.
+	ConfTarget uint32
+
+	// MinFeePerKW is the lowest fee rate returned for the confirmation
+	// targets of the bound. A value of zero means there's no lower bound.
+	MinFeePerKW SatPerKWeight
+
+	// MaxFeePerKW is the highest fee rate returned for the confirmation
+	// targets of the bound. A value of zero means there's no upper bound.
+	MaxFeePerKW SatPerKWeight
+}
+
+// clamp returns the passed fee rate clamped to the bound.
+func (b FeeBound) clamp(feePerKW SatPerKWeight) SatPerKWeight {
+	if b.MaxFeePerKW != 0 && feePerKW > b.MaxFeePerKW {
+		feePerKW = b.MaxFeePerKW
+	}
+	if feePerKW < b.MinFeePerKW {
+		feePerKW = b.MinFeePerKW
+	}
+
+	return feePerKW
+}
+
+// FeeSource is a named fee estimator queried by a CompositeEstimator.
+type FeeSource struct {
+	// Name identifies the source in logs and health reports.
+	Name string
+
+	// Estimator is the fee estimator backing the source.
+	Estimator Estimator
+}
+
+// SourceHealth reports the outcome of the recent queries of a fee source.
+type SourceHealth struct {
+	// Name identifies the source.
+	Name string
+
+	// Healthy is true if the source was started, and its last query
+	// returned an estimate.
+	Healthy bool
+
+	// LastEstimate is the last fee rate returned by the source.
+	LastEstimate SatPerKWeight
+
+	// LastSuccess is the time at which the source last returned an
+	// estimate, which is the zero time if it never did.
+	LastSuccess time.Time
+
+	// LastErr is the error returned by the last failed query of the
+	// source, if any.
+	LastErr error
+
+	// ConsecutiveFailures is the number of failed queries since the source
+	// last returned an estimate.
+	ConsecutiveFailures uint32
+}
+
+// SourceEstimate is the result of querying a single fee source for a
+// confirmation target.
+type SourceEstimate struct {
+	// FeePerKW is the fee rate returned by the source, which is zero if
+	// the query failed.
+	FeePerKW SatPerKWeight
+
+	// Err is the error returned by the source, if any.
+	Err error
+
+	// Health is the health of the source after the query.
+	Health SourceHealth
+}
+
+// CompositeEstimatorConfig houses the parameters of a CompositeEstimator.
+type CompositeEstimatorConfig struct {
+	// Sources are the fee estimators that are queried for each estimate.
+	Sources []FeeSource
+
+	// Policy determines how the estimates of the sources are combined.
+	Policy AggregationPolicy
+
+	// Bounds are the sanity bounds the combined estimate is clamped to,
+	// depending on its confirmation target.
+	Bounds []FeeBound
+
+	// Fallback is the estimator used if none of the sources returns an
+	// estimate.
+	Fallback Estimator
+}
+
+// sourceState tracks a fee source along with its health. The started and
+// health fields are guarded by the mutex of the CompositeEstimator, which is
+// never held while querying the source itself.
+type sourceState struct {
+	FeeSource
+
+	started bool
+	health  SourceHealth
+}
+
+// CompositeEstimator is an implementation of the Estimator interface that
+// queries several fee sources, and combines their estimates according to an
+// aggregation policy. Sources that fail are skipped, and the fallback
+// estimator is used once none of them returns an estimate. The resulting fee
+// rate is clamped to the sanity bounds of its confirmation target.
+type CompositeEstimator struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg CompositeEstimatorConfig
+
+	// bounds are the configured bounds, sorted by confirmation target.
+	bounds []FeeBound
+
+	mu      sync.Mutex
+	sources []*sourceState
+}
+
+// NewCompositeEstimator creates a new CompositeEstimator from the passed
+// config.
+func NewCompositeEstimator(cfg CompositeEstimatorConfig) (*CompositeEstimator,
+	error) {
+
+	if len(cfg.Sources) == 0 {
+		return nil, errors.New("at least one fee source is required")
+	}
+	if cfg.Fallback == nil {
+		return nil, errors.New("fallback fee estimator is required")
+	}
+
+	sources := make([]*sourceState, 0, len(cfg.Sources))
+	for _, source := range cfg.Sources {
+		sources = append(sources, &sourceState{
+			FeeSource: source,
+			health: SourceHealth{
+				Name: source.Name,
+			},
+		})
+	}
+
+	bounds := make([]FeeBound, len(cfg.Bounds))
+	copy(bounds, cfg.Bounds)
+	sort.Slice(bounds, func(i, j int) bool {
+		return bounds[i].ConfTarget < bounds[j].ConfTarget
+	})
+	for _, bound := range bounds {
+		if bound.MaxFeePerKW != 0 &&
+			bound.MinFeePerKW > bound.MaxFeePerKW {
+
+			return nil, fmt.Errorf("minimum fee rate %v of conf "+
+				"target %v exceeds maximum fee rate %v",
+				bound.MinFeePerKW, bound.ConfTarget,
+				bound.MaxFeePerKW)
+		}
+	}
+
+	return &CompositeEstimator{
+		cfg:     cfg,
+		bounds:  bounds,
+		sources: sources,
+	}, nil
+}
+
+// Start signals the Estimator to start any processes or goroutines it needs
+// to perform its duty. Sources that fail to start are reported as unhealthy
+// and skipped.
+//
+// NOTE: This method is part of the Estimator interface.
+func (c *CompositeEstimator) Start() error {
+	var err error
+	c.started.Do(func() {
+		log.Infof("Starting composite fee estimator with %v sources "+
+			"and %v policy", len(c.sources), c.cfg.Policy)
+
+		if err = c.cfg.Fallback.Start(); err != nil {
+			return
+		}
+
+		for _, source := range c.sources {
+			// Starting a source may require a round trip to a
+			// remote service, so we'll only hold the lock to
+			// record the outcome.
+			startErr := source.Estimator.Start()
+
+			c.mu.Lock()
+			if startErr != nil {
+				log.Errorf("Unable to start fee source %v: %v",
+					source.Name, startErr)
+
+				source.health.LastErr = startErr
+				source.health.ConsecutiveFailures++
+			} else {
+				source.started = true
+			}
+			c.mu.Unlock()
+		}
+	})
+
+	return err
+}
+
+// Stop stops any spawned goroutines and cleans up the resources used by the
+// fee estimator.
+//
+// NOTE: This method is part of the Estimator interface.
+func (c *CompositeEstimator) Stop() error {
+	var err error
+	c.stopped.Do(func() {
+		log.Infof("Stopping composite fee estimator")
+
+		for _, source := range c.startedSources() {
+			if stopErr := source.Estimator.Stop(); stopErr != nil {
+				log.Errorf("Unable to stop fee source %v: %v",
+					source.Name, stopErr)
+			}
+		}
+
+		err = c.cfg.Fallback.Stop()
+	})
+
+	return err
+}
+
+// EstimateFeePerKW takes in a target for the number of blocks until an initial
+// confirmation and returns the estimated fee expressed in sat/kw.
+//
+// NOTE: This method is part of the Estimator interface.
+func (c *CompositeEstimator) EstimateFeePerKW(numBlocks uint32) (SatPerKWeight,
+	error) {
+
+	feePerKW, _, err := c.EstimateFeeSources(numBlocks)
+	return feePerKW, err
+}
+
+// EstimateFeeSources queries all sources for the passed confirmation target,
+// and returns the combined fee rate along with the result of each source.
+func (c *CompositeEstimator) EstimateFeeSources(numBlocks uint32) (
+	SatPerKWeight, []SourceEstimate, error) {
+
+	// Query the sources without holding the lock, as any of them may be a
+	// slow remote service that would otherwise block all other fee
+	// queries.
+	started := make(map[*sourceState]struct{})
+	for _, source := range c.startedSources() {
+		started[source] = struct{}{}
+	}
+
+	results := make([]SourceEstimate, len(c.sources))
+	for i, source := range c.sources {
+		result := &results[i]
+		if _, ok := started[source]; ok {
+			result.FeePerKW, result.Err =
+				source.Estimator.EstimateFeePerKW(numBlocks)
+		} else {
+			result.Err = fmt.Errorf("fee source %v not started",
+				source.Name)
+		}
+	}
+
+	// Now that all sources have responded, record their health.
+	c.mu.Lock()
+	var estimates []SatPerKWeight
+	for i, source := range c.sources {
+		result := &results[i]
+		if result.Err != nil {
+			log.Debugf("Fee source %v failed for conf target of "+
+				"%v: %v", source.Name, numBlocks, result.Err)
+
+			source.health.LastErr = result.Err
+			source.health.ConsecutiveFailures++
+		} else {
+			source.health.LastEstimate = result.FeePerKW
+			source.health.LastSuccess = time.Now()
+			source.health.LastErr = nil
+			source.health.ConsecutiveFailures = 0
+
+			estimates = append(estimates, result.FeePerKW)
+		}
+
+		source.health.Healthy = source.started &&
+			source.health.ConsecutiveFailures == 0
+		result.Health = source.health
+	}
+	c.mu.Unlock()
+
+	// If none of the sources returned an estimate, we'll resort to the
+	// fallback estimator.
+	var feePerKW SatPerKWeight
+	if len(estimates) == 0 {
+		log.Warnf("No fee source returned an estimate for conf "+
+			"target of %v, using fallback estimator", numBlocks)
+
+		var err error
+		feePerKW, err = c.cfg.Fallback.EstimateFeePerKW(numBlocks)
+		if err != nil {
+			return 0, results, err
+		}
+	} else {
+		feePerKW = c.cfg.Policy.aggregate(estimates)
+	}
+
+	// Finally, we'll clamp the estimate to the bound of its confirmation
+	// target, and make sure it doesn't drop below our fee floor.
+	if bound, ok := c.boundFor(numBlocks); ok {
+		clamped := bound.clamp(feePerKW)
+		if clamped != feePerKW {
+			log.Debugf("Clamped fee estimate of %v for conf target "+
+				"of %v to %v", feePerKW, numBlocks, clamped)
+		}
+		feePerKW = clamped
+	}
+	if feePerKW < FeePerKwFloor {
+		feePerKW = FeePerKwFloor
+	}
+
+	log.Debugf("Composite estimator returning %v for conf target of %v "+
+		"from %v of %v sources", feePerKW, numBlocks, len(estimates),
+		len(c.sources))
+
+	return feePerKW, results, nil
+}
+
+// startedSources returns the sources that have been started successfully.
+func (c *CompositeEstimator) startedSources() []*sourceState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sources := make([]*sourceState, 0, len(c.sources))
+	for _, source := range c.sources {
+		if source.started {
+			sources = append(sources, source)
+		}
+	}
+
+	return sources
+}
+
+// boundFor returns the bound that applies to the passed confirmation target,
+// if any.
+func (c *CompositeEstimator) boundFor(numBlocks uint32) (FeeBound, bool) {
+	for i := len(c.bounds) - 1; i >= 0; i-- {
+		if c.bounds[i].ConfTarget <= numBlocks {
+			return c.bounds[i], true
+		}
+	}
+
+	return FeeBound{}, false
+}
+
+// Health returns the health of each of the sources.
+func (c *CompositeEstimator) Health() []SourceHealth {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	health := make([]SourceHealth, 0, len(c.sources))
+	for _, source := range c.sources {
+		health = append(health, source.health)
+	}
+
+	return health
+}
+
+// RelayFeePerKW returns the minimum fee rate required for transactions to be
+// relayed, which is the highest relay fee rate of the started sources and the
+// fallback estimator.
+//
+// NOTE: This method is part of the Estimator interface.
+func (c *CompositeEstimator) RelayFeePerKW() SatPerKWeight {
+	relayFee := c.cfg.Fallback.RelayFeePerKW()
+	for _, source := range c.startedSources() {
+		if fee := source.Estimator.RelayFeePerKW(); fee > relayFee {
+			relayFee = fee
+		}
+	}
+
+	return relayFee
+}
+
+// A compile-time assertion to ensure that CompositeEstimator implements the
+// Estimator interface.
+var _ Estimator = (*CompositeEstimator)(nil)
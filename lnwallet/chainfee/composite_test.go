@@ -0,0 +1,269 @@
+package chainfee
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// mockFeeSource is an estimator that returns a configurable fee rate or
+// error.
+type mockFeeSource struct {
+	StaticEstimator
+
+	feePerKW SatPerKWeight
+	err      error
+	startErr error
+}
+
+func (m *mockFeeSource) Start() error {
+	return m.startErr
+}
+
+func (m *mockFeeSource) EstimateFeePerKW(uint32) (SatPerKWeight, error) {
+	return m.feePerKW, m.err
+}
+
+// blockingFeeSource is an estimator that signals each query on queried, and
+// blocks until release is closed.
+type blockingFeeSource struct {
+	StaticEstimator
+
+	queried chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingFeeSource) EstimateFeePerKW(uint32) (SatPerKWeight, error) {
+	b.queried <- struct{}{}
+	<-b.release
+
+	return 2000, nil
+}
+
+// TestCompositeEstimatorSlowSource checks that a source that is slow to
+// respond doesn't block callers that don't depend on its estimate.
+func TestCompositeEstimatorSlowSource(t *testing.T) {
+	t.Parallel()
+
+	slow := &blockingFeeSource{
+		queried: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	estimator, err := NewCompositeEstimator(CompositeEstimatorConfig{
+		Sources: []FeeSource{
+			{Name: "slow", Estimator: slow},
+			{Name: "fast", Estimator: &mockFeeSource{feePerKW: 1000}},
+		},
+		Policy:   PolicyMax,
+		Fallback: NewStaticEstimator(0, 0),
+	})
+	require.NoError(t, err)
+	require.NoError(t, estimator.Start())
+	defer estimator.Stop()
+
+	type estimate struct {
+		fee SatPerKWeight
+		err error
+	}
+	estimates := make(chan estimate, 1)
+	go func() {
+		fee, err := estimator.EstimateFeePerKW(6)
+		estimates <- estimate{fee, err}
+	}()
+
+	select {
+	case <-slow.queried:
+	case <-time.After(time.Second):
+		t.Fatalf("slow source not queried")
+	}
+
+	// While the slow source is being queried, the health of the sources
+	// and the relay fee should still be available.
+	done := make(chan struct{})
+	go func() {
+		estimator.Health()
+		estimator.RelayFeePerKW()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("estimator blocked by slow source")
+	}
+
+	close(slow.release)
+
+	select {
+	case result := <-estimates:
+		require.NoError(t, result.err)
+		require.Equal(t, SatPerKWeight(2000), result.fee)
+	case <-time.After(time.Second):
+		t.Fatalf("no estimate returned")
+	}
+}
+
+// TestCompositeEstimatorPolicies checks that the estimates of the sources
+// are combined according to the aggregation policy.
+func TestCompositeEstimatorPolicies(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		policy   AggregationPolicy
+		fees     []SatPerKWeight
+		expected SatPerKWeight
+	}{
+		{"median_odd", PolicyMedian, []SatPerKWeight{3000, 1000, 2000},
+			2000},
+		{"median_even", PolicyMedian, []SatPerKWeight{4000, 1000, 2000,
+			3000}, 2500},
+		{"min", PolicyMin, []SatPerKWeight{3000, 1000, 2000}, 1000},
+		{"max", PolicyMax, []SatPerKWeight{3000, 1000, 2000}, 3000},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var sources []FeeSource
+			for _, fee := range tc.fees {
+				sources = append(sources, FeeSource{
+					Name: fee.String(),
+					Estimator: &mockFeeSource{
+						feePerKW: fee,
+					},
+				})
+			}
+
+			estimator, err := NewCompositeEstimator(
+				CompositeEstimatorConfig{
+					Sources:  sources,
+					Policy:   tc.policy,
+					Fallback: NewStaticEstimator(0, 0),
+				},
+			)
+			require.NoError(t, err)
+			require.NoError(t, estimator.Start())
+			defer estimator.Stop()
+
+			fee, err := estimator.EstimateFeePerKW(6)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, fee)
+		})
+	}
+}
+
+// TestCompositeEstimatorFallback checks that failing sources are skipped and
+// reported as unhealthy, and that the fallback estimator is used once none of
+// the sources returns an estimate.
+func TestCompositeEstimatorFallback(t *testing.T) {
+	t.Parallel()
+
+	failing := &mockFeeSource{err: errors.New("api down")}
+	working := &mockFeeSource{feePerKW: 5000}
+	unstarted := &mockFeeSource{startErr: errors.New("no connection")}
+
+	estimator, err := NewCompositeEstimator(CompositeEstimatorConfig{
+		Sources: []FeeSource{
+			{Name: "failing", Estimator: failing},
+			{Name: "working", Estimator: working},
+			{Name: "unstarted", Estimator: unstarted},
+		},
+		Policy:   PolicyMedian,
+		Fallback: NewStaticEstimator(1000, 0),
+	})
+	require.NoError(t, err)
+	require.NoError(t, estimator.Start())
+	defer estimator.Stop()
+
+	// Only the working source returns an estimate, which is used as is.
+	fee, results, err := estimator.EstimateFeeSources(6)
+	require.NoError(t, err)
+	require.Equal(t, SatPerKWeight(5000), fee)
+	require.Len(t, results, 3)
+	require.Error(t, results[0].Err)
+	require.Equal(t, SatPerKWeight(5000), results[1].FeePerKW)
+	require.Error(t, results[2].Err)
+
+	health := estimator.Health()
+	require.False(t, health[0].Healthy)
+	require.EqualValues(t, 1, health[0].ConsecutiveFailures)
+	require.True(t, health[1].Healthy)
+	require.Equal(t, SatPerKWeight(5000), health[1].LastEstimate)
+	require.False(t, health[2].Healthy)
+	require.EqualValues(t, 2, health[2].ConsecutiveFailures)
+
+	// Once the working source fails too, the fallback estimator is used.
+	working.err = errors.New("rpc down")
+	fee, err = estimator.EstimateFeePerKW(6)
+	require.NoError(t, err)
+	require.Equal(t, SatPerKWeight(1000), fee)
+
+	health = estimator.Health()
+	require.False(t, health[1].Healthy)
+	require.Equal(t, SatPerKWeight(5000), health[1].LastEstimate)
+	require.False(t, health[1].LastSuccess.IsZero())
+
+	// A source recovers as soon as it returns an estimate again.
+	failing.err = nil
+	failing.feePerKW = 3000
+	fee, err = estimator.EstimateFeePerKW(6)
+	require.NoError(t, err)
+	require.Equal(t, SatPerKWeight(3000), fee)
+	require.True(t, estimator.Health()[0].Healthy)
+}
+
+// TestCompositeEstimatorBounds checks that estimates are clamped to the
+// bound of their confirmation target.
+func TestCompositeEstimatorBounds(t *testing.T) {
+	t.Parallel()
+
+	source := &mockFeeSource{}
+	estimator, err := NewCompositeEstimator(CompositeEstimatorConfig{
+		Sources: []FeeSource{
+			{Name: "source", Estimator: source},
+		},
+		Bounds: []FeeBound{
+			{ConfTarget: 6, MinFeePerKW: 500, MaxFeePerKW: 10000},
+			{ConfTarget: 1, MinFeePerKW: 1000, MaxFeePerKW: 50000},
+		},
+		Fallback: NewStaticEstimator(0, 0),
+	})
+	require.NoError(t, err)
+	require.NoError(t, estimator.Start())
+	defer estimator.Stop()
+
+	testCases := []struct {
+		target   uint32
+		fee      SatPerKWeight
+		expected SatPerKWeight
+	}{
+		{1, 100000, 50000},
+		{2, 300, 1000},
+		{5, 20000, 20000},
+		{6, 20000, 10000},
+		{144, 300, 500},
+		{144, 5000, 5000},
+	}
+	for _, tc := range testCases {
+		source.feePerKW = tc.fee
+
+		fee, err := estimator.EstimateFeePerKW(tc.target)
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, fee, "target %v", tc.target)
+	}
+
+	// A bound with a minimum above its maximum is rejected.
+	_, err = NewCompositeEstimator(CompositeEstimatorConfig{
+		Sources: []FeeSource{
+			{Name: "source", Estimator: source},
+		},
+		Bounds: []FeeBound{
+			{ConfTarget: 1, MinFeePerKW: 2000, MaxFeePerKW: 1000},
+		},
+		Fallback: NewStaticEstimator(0, 0),
+	})
+	require.Error(t, err)
+}
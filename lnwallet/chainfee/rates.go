@@ -4,7 +4,7 @@ import (
 	"fmt"
 
 	"github.com/btcsuite/btcd/blockchain"
-	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcd/btcutil"
 )
 
 const (
@@ -16,6 +16,12 @@ const (
 	// transaction that we should ever _create_. This is the the equivalent
 	// of 1 sat/byte in sat/kw.
 	AbsoluteFeePerKwFloor SatPerKWeight = 250
+
+	// IncrementalRelayFeePerKw is the default incremental relay fee rate of
+	// bitcoind in sat/kw. A transaction replacing another one must pay at
+	// least this rate for its own weight on top of the fee of the replaced
+	// transaction (BIP-125 rule 4).
+	IncrementalRelayFeePerKw SatPerKWeight = 250
 )
 
 // SatPerKVByte represents a fee rate in sat/kb.
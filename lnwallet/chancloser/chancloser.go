@@ -4,10 +4,10 @@ import (
 	"bytes"
 	"fmt"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/htlcswitch"
 	"github.com/lightningnetwork/lnd/labels"
@@ -94,6 +94,13 @@ type ChanCloseCfg struct {
 	// Disconnect will disconnect from the remote peer in this close.
 	Disconnect func() error
 
+	// RbfCoopClose is true if both parties signal support for cooperative
+	// closes with replaceable closing transactions. Instead of negotiating
+	// a fee, the party proposing a closing transaction then pays its fee
+	// in full, and either party can later replace it with one paying a
+	// higher fee.
+	RbfCoopClose bool
+
 	// Quit is a channel that should be sent upon in the occasion the state
 	// machine should cease all progress and shutdown.
 	Quit chan struct{}
@@ -158,6 +165,18 @@ type ChanCloser struct {
 
 	// locallyInitiated is true if we initiated the channel close.
 	locallyInitiated bool
+
+	// closeFee is the fee paid by the latest replaceable closing
+	// transaction.
+	closeFee btcutil.Amount
+
+	// rbfProposal is the last ClosingComplete message we sent, which still
+	// awaits the signature of the remote party.
+	rbfProposal *lnwire.ClosingComplete
+
+	// bumpReq is the local request to bump the fee of the closing
+	// transaction that rbfProposal was created for, if any.
+	bumpReq *htlcswitch.ChanClose
 }
 
 // NewChanCloser creates a new instance of the channel closure given the passed
@@ -178,8 +197,11 @@ func NewChanCloser(cfg ChanCloseCfg, deliveryScript []byte,
 	// range.
 	//
 	// TODO(roasbeef): clamp fee func?
+	//
+	// With replaceable closing transactions, the fee is paid out of the
+	// balance of the proposer, so it isn't bounded by the commitment fee.
 	channelCommitFee := cfg.Channel.StateSnapshot().CommitFee
-	if idealFeeSat > channelCommitFee {
+	if !cfg.RbfCoopClose && idealFeeSat > channelCommitFee {
 		chancloserLog.Infof("Ideal starting fee of %v is greater than commit "+
 			"fee of %v, clamping", int64(idealFeeSat), int64(channelCommitFee))
 
@@ -344,6 +366,12 @@ func maybeMatchScript(disconnect func() error, upfrontScript,
 func (c *ChanCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message,
 	bool, error) {
 
+	// Once both parties have sent their shutdown messages, replaceable
+	// closes use their own set of messages.
+	if c.cfg.RbfCoopClose && c.state >= closeFeeNegotiation {
+		return c.processRbfCloseMsg(msg)
+	}
+
 	switch c.state {
 
 	// If we're in the close idle state, and we're receiving a channel closure
@@ -379,6 +407,17 @@ func (c *ChanCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message,
 			}
 		}
 
+		// Similarly, if the remote party leased out its contribution
+		// to the channel, we'll deny its close attempt until the lease
+		// expires.
+		leaseExpiry := chanState.LeaseExpiry()
+		if chanInitiator && c.negotiationHeight < leaseExpiry {
+			return nil, false, fmt.Errorf("lessor attempting to "+
+				"co-op close leased ChannelPoint(%v) "+
+				"(current_height=%v, lease_expiry=%v)",
+				c.chanPoint, c.negotiationHeight, leaseExpiry)
+		}
+
 		// If the remote node opened the channel with option upfront shutdown
 		// script, check that the script they provided matches.
 		if err := maybeMatchScript(
@@ -413,8 +452,10 @@ func (c *ChanCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message,
 		c.state = closeFeeNegotiation
 
 		// We'll also craft our initial close proposal in order to keep the
-		// negotiation moving, but only if we're the negotiator.
-		if chanInitiator {
+		// negotiation moving, but only if we're the negotiator. With
+		// replaceable closes, the party that sent the first shutdown
+		// message proposes the first closing transaction instead.
+		if chanInitiator && !c.cfg.RbfCoopClose {
 			closeSigned, err := c.proposeCloseSigned(c.idealFeeSat)
 			if err != nil {
 				return nil, false, err
@@ -458,6 +499,19 @@ func (c *ChanCloser) ProcessCloseMsg(msg lnwire.Message) ([]lnwire.Message,
 		chancloserLog.Infof("ChannelPoint(%v): shutdown response received, "+
 			"entering fee negotiation", c.chanPoint)
 
+		// With replaceable closes, we propose a closing transaction at
+		// our ideal fee, which we pay in full.
+		if c.cfg.RbfCoopClose {
+			closeComplete, err := c.proposeClosingComplete(
+				c.idealFeeSat,
+			)
+			if err != nil {
+				return nil, false, err
+			}
+
+			return []lnwire.Message{closeComplete}, false, nil
+		}
+
 		// Starting with our ideal fee rate, we'll create an initial closing
 		// proposal, but only if we're the initiator, as otherwise, the other
 		// party will send their initial proposal first.
@@ -0,0 +1,318 @@
+package chancloser
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrNotRbfCoopClose is returned when the fee of a closing transaction
+	// is to be bumped, but the channel isn't being closed with replaceable
+	// closing transactions.
+	ErrNotRbfCoopClose = fmt.Errorf("channel is not being closed with " +
+		"replaceable closing transactions")
+
+	// ErrBumpInProgress is returned when the fee of a closing transaction
+	// is to be bumped while a prior bump still awaits the signature of the
+	// remote party.
+	ErrBumpInProgress = fmt.Errorf("closing transaction fee bump already " +
+		"in progress")
+
+	// ErrRbfFeeTooLow is returned when a replacement closing transaction
+	// doesn't pay the fee of the closing transaction it replaces plus the
+	// incremental relay fee for its own weight.
+	ErrRbfFeeTooLow = fmt.Errorf("replacement closing transaction must " +
+		"pay the replaced fee plus the incremental relay fee")
+)
+
+// ResumeRbfChanCloser creates a channel closer for a channel whose replaceable
+// closing transaction has already been broadcast, such that the closing
+// transaction can still be replaced after a restart.
+func ResumeRbfChanCloser(cfg ChanCloseCfg, coopClose *channeldb.RbfCoopClose,
+	locallyInitiated bool) *ChanCloser {
+
+	cfg.RbfCoopClose = true
+
+	cid := lnwire.NewChanIDFromOutPoint(cfg.Channel.ChannelPoint())
+	priorFeeOffers := make(map[btcutil.Amount]*lnwire.ClosingSigned)
+
+	return &ChanCloser{
+		state:                closeFinished,
+		chanPoint:            *cfg.Channel.ChannelPoint(),
+		cid:                  cid,
+		cfg:                  cfg,
+		negotiationHeight:    coopClose.BroadcastHeight,
+		closingTx:            coopClose.CloseTx,
+		priorFeeOffers:       priorFeeOffers,
+		localDeliveryScript:  coopClose.LocalDeliveryScript,
+		remoteDeliveryScript: coopClose.RemoteDeliveryScript,
+		locallyInitiated:     locallyInitiated,
+		closeFee:             coopClose.Fee,
+	}
+}
+
+// RbfCoopClose returns true if the channel is closed with replaceable closing
+// transactions.
+func (c *ChanCloser) RbfCoopClose() bool {
+	return c.cfg.RbfCoopClose
+}
+
+// BumpRequest returns the local request to bump the fee of the closing
+// transaction that still awaits the signature of the remote party, if any.
+func (c *ChanCloser) BumpRequest() *htlcswitch.ChanClose {
+	return c.bumpReq
+}
+
+// BumpFee creates a proposal for a closing transaction that replaces the one
+// that has been broadcast, paying the target fee rate of the passed request
+// out of our balance. The returned message is to be sent to the remote party,
+// which responds with its signature.
+func (c *ChanCloser) BumpFee(req *htlcswitch.ChanClose) (
+	*lnwire.ClosingComplete, error) {
+
+	switch {
+	case !c.cfg.RbfCoopClose:
+		return nil, ErrNotRbfCoopClose
+
+	case c.state != closeFinished:
+		return nil, ErrChanCloseNotFinished
+
+	case c.rbfProposal != nil:
+		return nil, ErrBumpInProgress
+	}
+
+	fee := c.cfg.Channel.CalcFee(req.TargetFeePerKw)
+	if minFee := c.minRbfFee(); fee < minFee {
+		return nil, fmt.Errorf("%w: fee of %v at %v is below the "+
+			"minimum replacement fee of %v", ErrRbfFeeTooLow, fee,
+			req.TargetFeePerKw, minFee)
+	}
+
+	closeComplete, err := c.proposeClosingComplete(fee)
+	if err != nil {
+		return nil, err
+	}
+	c.bumpReq = req
+
+	return closeComplete, nil
+}
+
+// processRbfCloseMsg processes the next message of a cooperative close with
+// replaceable closing transactions, once both parties have sent their
+// shutdown messages. A ClosingComplete message proposes a closing transaction
+// paid for by the remote party, which we sign and broadcast, while a
+// ClosingSig message completes a closing transaction we proposed. The second
+// return value is true if the first closing transaction was broadcast, or if
+// a fee bump that we requested was completed.
+func (c *ChanCloser) processRbfCloseMsg(msg lnwire.Message) ([]lnwire.Message,
+	bool, error) {
+
+	switch msg := msg.(type) {
+	case *lnwire.ClosingComplete:
+		// The party that sent the first shutdown message proposes
+		// the first closing transaction, so we only accept proposals
+		// of the remote party once a closing transaction has been
+		// broadcast if we sent it.
+		if c.state != closeFinished && c.locallyInitiated {
+			return nil, false, fmt.Errorf("unexpected " +
+				"ClosingComplete before first closing " +
+				"transaction")
+		}
+		if c.state == closeFinished {
+			minFee := c.minRbfFee()
+			if msg.FeeSatoshis < minFee {
+				return nil, false, fmt.Errorf("%w: remote fee "+
+					"of %v is below the minimum "+
+					"replacement fee of %v",
+					ErrRbfFeeTooLow, msg.FeeSatoshis,
+					minFee)
+			}
+		}
+
+		rawSig, _, _, err := c.cfg.Channel.CreateRbfCloseProposal(
+			msg.FeeSatoshis, false, c.localDeliveryScript,
+			c.remoteDeliveryScript,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+
+		remoteSig, err := msg.Signature.ToSignature()
+		if err != nil {
+			return nil, false, err
+		}
+
+		wasFinished := c.state == closeFinished
+		err = c.completeRbfClose(
+			rawSig, remoteSig, msg.FeeSatoshis, false,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+
+		localSig, err := lnwire.NewSigFromSignature(rawSig)
+		if err != nil {
+			return nil, false, err
+		}
+		closeSig := lnwire.NewClosingSig(c.cid, localSig)
+
+		return []lnwire.Message{closeSig}, !wasFinished, nil
+
+	case *lnwire.ClosingSig:
+		proposal := c.rbfProposal
+		if proposal == nil {
+			return nil, false, fmt.Errorf("unexpected ClosingSig " +
+				"without outstanding proposal")
+		}
+
+		// Whether or not the proposal can be completed, it is no
+		// longer outstanding.
+		c.rbfProposal = nil
+		c.bumpReq = nil
+
+		// The remote party may have replaced the closing transaction
+		// in the meantime with one paying a higher fee.
+		if c.state == closeFinished {
+			minFee := c.minRbfFee()
+			if proposal.FeeSatoshis < minFee {
+				return nil, false, fmt.Errorf("%w: proposed "+
+					"fee of %v is below the minimum "+
+					"replacement fee of %v",
+					ErrRbfFeeTooLow, proposal.FeeSatoshis,
+					minFee)
+			}
+		}
+
+		localSig, err := proposal.Signature.ToSignature()
+		if err != nil {
+			return nil, false, err
+		}
+
+		remoteSig, err := msg.Signature.ToSignature()
+		if err != nil {
+			return nil, false, err
+		}
+
+		err = c.completeRbfClose(
+			localSig, remoteSig, proposal.FeeSatoshis, true,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return nil, true, nil
+
+	default:
+		return nil, false, fmt.Errorf("expected "+
+			"lnwire.ClosingComplete or lnwire.ClosingSig, instead "+
+			"have %v", spew.Sdump(msg))
+	}
+}
+
+// proposeClosingComplete creates a proposal for a replaceable closing
+// transaction paying the given fee out of our balance.
+func (c *ChanCloser) proposeClosingComplete(fee btcutil.Amount) (
+	*lnwire.ClosingComplete, error) {
+
+	rawSig, _, _, err := c.cfg.Channel.CreateRbfCloseProposal(
+		fee, true, c.localDeliveryScript, c.remoteDeliveryScript,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedSig, err := lnwire.NewSigFromSignature(rawSig)
+	if err != nil {
+		return nil, err
+	}
+
+	chancloserLog.Infof("ChannelPoint(%v): proposing closing tx paying "+
+		"fee of %v sat", c.chanPoint, int64(fee))
+
+	c.rbfProposal = lnwire.NewClosingComplete(c.cid, fee, parsedSig)
+
+	return c.rbfProposal, nil
+}
+
+// minRbfFee returns the lowest fee a closing transaction replacing the current
+// one must pay to be relayed: the fee of the current closing transaction plus
+// the incremental relay fee for the weight of the replacement (BIP-125 rule 4).
+//
+// NOTE: The replacement spends the same funding output to at most the same
+// outputs, so its weight is estimated from the outputs of the current closing
+// transaction and a multisig witness with signatures of maximum size.
+func (c *ChanCloser) minRbfFee() btcutil.Amount {
+	var weightEstimate input.TxWeightEstimator
+	weightEstimate.AddWitnessInput(input.MultiSigWitnessSize)
+	for _, txOut := range c.closingTx.TxOut {
+		weightEstimate.AddTxOutput(txOut)
+	}
+
+	increment := chainfee.IncrementalRelayFeePerKw.FeeForWeight(
+		int64(weightEstimate.Weight()),
+	)
+
+	return c.closeFee + increment
+}
+
+// completeRbfClose completes a replaceable closing transaction with the
+// signatures of both parties, broadcasts it, and persists it as the latest
+// closing transaction of the channel.
+func (c *ChanCloser) completeRbfClose(localSig, remoteSig input.Signature,
+	fee btcutil.Amount, localPays bool) error {
+
+	closeTx, _, err := c.cfg.Channel.CompleteRbfCooperativeClose(
+		localSig, remoteSig, c.localDeliveryScript,
+		c.remoteDeliveryScript, fee, localPays,
+	)
+	if err != nil {
+		return err
+	}
+
+	chancloserLog.Infof("Broadcasting cooperative close tx paying fee of "+
+		"%v sat: %v", int64(fee), newLogClosure(func() string {
+		return spew.Sdump(closeTx)
+	}))
+
+	// We only persist the closing tx once it has been accepted by the
+	// backend. If the broadcast fails, the record of the closing tx it was
+	// to replace is kept, such that we keep republishing a closing tx that
+	// is valid on startup, and don't resume from one that never made it
+	// into the mempool.
+	chanID := c.cfg.Channel.ShortChanID()
+	closeLabel := labels.MakeLabel(labels.LabelTypeChannelClose, &chanID)
+	if err := c.cfg.BroadcastTx(closeTx, closeLabel); err != nil {
+		return err
+	}
+
+	// With the closing tx broadcast, we persist it to the database, such
+	// that it is republished on startup, and can still be replaced after a
+	// restart.
+	err = c.cfg.Channel.MarkRbfCoopBroadcasted(
+		&channeldb.RbfCoopClose{
+			LocalDeliveryScript:  c.localDeliveryScript,
+			RemoteDeliveryScript: c.remoteDeliveryScript,
+			CloseTx:              closeTx,
+			Fee:                  fee,
+			LocalPays:            localPays,
+			BroadcastHeight:      c.negotiationHeight,
+		}, c.locallyInitiated,
+	)
+	if err != nil {
+		return err
+	}
+
+	c.closingTx = closeTx
+	c.closeFee = fee
+	c.state = closeFinished
+
+	return nil
+}
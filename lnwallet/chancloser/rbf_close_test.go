@@ -0,0 +1,192 @@
+package chancloser
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/htlcswitch"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// rbfCloseCfg returns the config of a closer for a replaceable cooperative
+// close of the passed channel. Broadcast transactions are sent on the
+// returned channel.
+func rbfCloseCfg(channel *lnwallet.LightningChannel) (ChanCloseCfg,
+	chan *wire.MsgTx) {
+
+	broadcasts := make(chan *wire.MsgTx, 10)
+
+	return ChanCloseCfg{
+		Channel:           channel,
+		UnregisterChannel: func(lnwire.ChannelID) {},
+		BroadcastTx: func(tx *wire.MsgTx, _ string) error {
+			broadcasts <- tx
+			return nil
+		},
+		DisableChannel: func(wire.OutPoint) error { return nil },
+		Disconnect:     func() error { return nil },
+		RbfCoopClose:   true,
+	}, broadcasts
+}
+
+// TestRbfCoopClose tests that the party that initiates a replaceable
+// cooperative close proposes the first closing transaction, and that either
+// party can replace it with a closing transaction paying a higher fee.
+func TestRbfCoopClose(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := lnwallet.CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	aliceCfg, aliceBroadcasts := rbfCloseCfg(aliceChannel)
+	bobCfg, bobBroadcasts := rbfCloseCfg(bobChannel)
+
+	closeReq := &htlcswitch.ChanClose{
+		CloseType:      htlcswitch.CloseRegular,
+		TargetFeePerKw: chainfee.FeePerKwFloor,
+	}
+	alice := NewChanCloser(
+		aliceCfg, randDeliveryAddress(t), closeReq.TargetFeePerKw, 100,
+		closeReq, true,
+	)
+	bob := NewChanCloser(
+		bobCfg, randDeliveryAddress(t), chainfee.FeePerKwFloor, 100,
+		nil, false,
+	)
+
+	// assertBroadcast asserts that both parties broadcast the same
+	// closing transaction, and returns it.
+	assertBroadcast := func() *wire.MsgTx {
+		aliceTx := <-aliceBroadcasts
+		bobTx := <-bobBroadcasts
+		require.Equal(t, aliceTx.TxHash(), bobTx.TxHash())
+
+		return aliceTx
+	}
+
+	// Alice initiates the close, after which both parties exchange their
+	// shutdown messages.
+	aliceShutdown, err := alice.ShutdownChan()
+	require.NoError(t, err)
+
+	msgs, fin, err := bob.ProcessCloseMsg(aliceShutdown)
+	require.NoError(t, err)
+	require.False(t, fin)
+	require.Len(t, msgs, 1)
+
+	// Alice proposes the first closing transaction once she receives the
+	// shutdown message of Bob.
+	msgs, fin, err = alice.ProcessCloseMsg(msgs[0])
+	require.NoError(t, err)
+	require.False(t, fin)
+	require.Len(t, msgs, 1)
+	require.IsType(t, &lnwire.ClosingComplete{}, msgs[0])
+
+	// Bob can't propose a closing transaction before the first one has
+	// been broadcast.
+	_, err = bob.BumpFee(closeReq)
+	require.ErrorIs(t, err, ErrChanCloseNotFinished)
+
+	msgs, fin, err = bob.ProcessCloseMsg(msgs[0])
+	require.NoError(t, err)
+	require.True(t, fin)
+	require.Len(t, msgs, 1)
+	require.IsType(t, &lnwire.ClosingSig{}, msgs[0])
+
+	msgs, fin, err = alice.ProcessCloseMsg(msgs[0])
+	require.NoError(t, err)
+	require.True(t, fin)
+	require.Empty(t, msgs)
+
+	closeTx := assertBroadcast()
+
+	// Bob now replaces the closing transaction with one paying a higher
+	// fee out of his balance.
+	bumpReq := &htlcswitch.ChanClose{
+		CloseType:      htlcswitch.CloseBumpFee,
+		TargetFeePerKw: chainfee.FeePerKwFloor * 4,
+	}
+	closeComplete, err := bob.BumpFee(bumpReq)
+	require.NoError(t, err)
+	require.Equal(t, bumpReq, bob.BumpRequest())
+
+	_, err = bob.BumpFee(bumpReq)
+	require.ErrorIs(t, err, ErrBumpInProgress)
+
+	msgs, fin, err = alice.ProcessCloseMsg(closeComplete)
+	require.NoError(t, err)
+	require.False(t, fin)
+	require.Len(t, msgs, 1)
+
+	msgs, fin, err = bob.ProcessCloseMsg(msgs[0])
+	require.NoError(t, err)
+	require.True(t, fin)
+	require.Empty(t, msgs)
+	require.Nil(t, bob.BumpRequest())
+
+	replacementTx := assertBroadcast()
+	require.NotEqual(t, closeTx.TxHash(), replacementTx.TxHash())
+
+	// Alice is unable to replace the closing transaction with one that
+	// doesn't pay a higher fee.
+	_, err = alice.BumpFee(bumpReq)
+	require.True(t, errors.Is(err, ErrRbfFeeTooLow))
+
+	// Nor can she replace it with one whose fee rate is only marginally
+	// higher, as it wouldn't cover the incremental relay fee.
+	minFee := alice.minRbfFee()
+	require.Greater(t, int64(minFee), int64(alice.closeFee+1))
+
+	_, err = alice.BumpFee(&htlcswitch.ChanClose{
+		CloseType:      htlcswitch.CloseBumpFee,
+		TargetFeePerKw: bumpReq.TargetFeePerKw + 1,
+	})
+	require.ErrorIs(t, err, ErrRbfFeeTooLow)
+
+	// The same holds for proposals of the remote party.
+	lowProposal := lnwire.NewClosingComplete(
+		closeComplete.ChannelID, minFee-1, closeComplete.Signature,
+	)
+	_, _, err = alice.ProcessCloseMsg(lowProposal)
+	require.ErrorIs(t, err, ErrRbfFeeTooLow)
+
+	// If the broadcast of a replacement fails, the closing transaction it
+	// was to replace remains the persisted one, as asserted below.
+	closeComplete, err = bob.BumpFee(&htlcswitch.ChanClose{
+		CloseType:      htlcswitch.CloseBumpFee,
+		TargetFeePerKw: bumpReq.TargetFeePerKw * 2,
+	})
+	require.NoError(t, err)
+
+	errBroadcast := errors.New("broadcast failed")
+	alice.cfg.BroadcastTx = func(*wire.MsgTx, string) error {
+		return errBroadcast
+	}
+	_, _, err = alice.ProcessCloseMsg(closeComplete)
+	require.ErrorIs(t, err, errBroadcast)
+	require.Empty(t, aliceBroadcasts)
+
+	// A closer resumed from the persisted state continues with the latest
+	// closing transaction.
+	coopClose, err := aliceChannel.State().RbfCoopClose()
+	require.NoError(t, err)
+	require.Equal(t, replacementTx.TxHash(), coopClose.CloseTx.TxHash())
+	require.False(t, coopClose.LocalPays)
+
+	resumedCfg, _ := rbfCloseCfg(aliceChannel)
+	resumed := ResumeRbfChanCloser(resumedCfg, coopClose, true)
+	resumedTx, err := resumed.ClosingTx()
+	require.NoError(t, err)
+	require.Equal(t, replacementTx.TxHash(), resumedTx.TxHash())
+
+	_, err = resumed.BumpFee(bumpReq)
+	require.ErrorIs(t, err, ErrRbfFeeTooLow)
+}
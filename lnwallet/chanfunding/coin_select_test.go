@@ -5,8 +5,8 @@ import (
 	"regexp"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/stretchr/testify/require"
@@ -21,6 +21,10 @@ var (
 		"a914f7bd5b8077b9549653dacf96f824af9d931663e687",
 	)
 
+	p2trScript, _ = hex.DecodeString(
+		"5120a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c",
+	)
+
 	p2khScript, _ = hex.DecodeString(
 		"76a91411034bdcb6ccb7744fdfdeea958a6fb0b415a03288ac",
 	)
@@ -53,7 +57,7 @@ func fundingFee(feeRate chainfee.SatPerKWeight, numInput int, // nolint:unparam
 
 // TestCalculateFees tests that the helper function to calculate the fees
 // both with and without applying a change output is done correctly for
-// (N)P2WKH inputs, and should raise an error otherwise.
+// (N)P2WKH and P2TR inputs, and should raise an error otherwise.
 func TestCalculateFees(t *testing.T) {
 	t.Parallel()
 
@@ -101,6 +105,22 @@ func TestCalculateFees(t *testing.T) {
 			expectedErr:           nil,
 		},
 
+		{
+			name: "one P2TR input",
+			utxos: []Coin{
+				{
+					TxOut: wire.TxOut{
+						PkScript: p2trScript,
+						Value:    1,
+					},
+				},
+			},
+
+			expectedFeeNoChange:   444,
+			expectedFeeWithChange: 568,
+			expectedErr:           nil,
+		},
+
 		{
 			name: "not supported P2KH input",
 			utxos: []Coin{
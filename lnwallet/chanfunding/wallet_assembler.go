@@ -1,14 +1,15 @@
 package chanfunding
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/txsort"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
-	"github.com/btcsuite/btcutil/txsort"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
 )
@@ -105,16 +106,11 @@ func (f *FullIntent) CompileFundingTx(extraInputs []*wire.TxIn,
 		fundingTx, fundingOutput.PkScript,
 	)
 
-	// Next, sign all inputs that are ours, collecting the signatures in
-	// order of the inputs.
-	signDesc := input.SignDescriptor{
-		HashType:  txscript.SigHashAll,
-		SigHashes: txscript.NewTxSigHashes(fundingTx),
-	}
-	for i, txIn := range fundingTx.TxIn {
-		// We can only sign this input if it's ours, so we'll ask the
-		// coin source if it can map this outpoint into a coin we own.
-		// If not, then we'll continue as it isn't our input.
+	// Collect the outputs spent by all inputs we know about. We can only
+	// sign an input if it's ours, so we'll ask the coin source if it can
+	// map the outpoint into a coin we own.
+	ourCoins := make(map[wire.OutPoint]*wire.TxOut)
+	for _, txIn := range fundingTx.TxIn {
 		info, err := f.coinSource.CoinFromOutPoint(
 			txIn.PreviousOutPoint,
 		)
@@ -122,13 +118,36 @@ func (f *FullIntent) CompileFundingTx(extraInputs []*wire.TxIn,
 			continue
 		}
 
-		// Now that we know the input is ours, we'll populate the
-		// signDesc with the per input unique information.
-		signDesc.Output = &wire.TxOut{
+		ourCoins[txIn.PreviousOutPoint] = &wire.TxOut{
 			Value:    info.Value,
 			PkScript: info.PkScript,
 		}
+	}
+
+	// Next, sign all inputs that are ours, collecting the signatures in
+	// order of the inputs.
+	sigHashes, allPrevOuts := newFundingSigHashes(fundingTx, ourCoins)
+	signDesc := input.SignDescriptor{
+		SigHashes: sigHashes,
+	}
+	for i, txIn := range fundingTx.TxIn {
+		// If this isn't one of our coins, then we'll continue as it
+		// isn't our input.
+		prevOut, ok := ourCoins[txIn.PreviousOutPoint]
+		if !ok {
+			continue
+		}
+
+		// Now that we know the input is ours, we'll populate the
+		// signDesc with the per input unique information.
+		signDesc.Output = prevOut
 		signDesc.InputIndex = i
+		signDesc.HashType, err = fundingInputHashType(
+			txIn.PreviousOutPoint, prevOut, allPrevOuts,
+		)
+		if err != nil {
+			return nil, err
+		}
 
 		// Finally, we'll sign the input as is, and populate the input
 		// with the witness and sigScript (if needed).
@@ -153,6 +172,153 @@ func (f *FullIntent) CompileFundingTx(extraInputs []*wire.TxIn,
 	return fundingTx, nil
 }
 
+// AddRemoteFunding adds the given amount to the remote party's contribution
+// to the funding output. This is used once the remote party agreed to
+// contribute funds to a channel we initiated.
+func (f *FullIntent) AddRemoteFunding(amt btcutil.Amount) {
+	f.remoteFundingAmt += amt
+}
+
+// CompileInteractiveTx is the counterpart of CompileFundingTx for funding
+// transactions that have been constructed interactively together with the
+// remote party. As the final transaction is already known, we only ensure it
+// contains our inputs, our change outputs and the funding output, and then
+// sign our inputs. The inputs of the remote party are left untouched, even if
+// they're known to the backing CoinSource.
+func (f *FullIntent) CompileInteractiveTx(
+	fundingTx *wire.MsgTx) (*wire.MsgTx, error) {
+
+	_, fundingOutput, err := f.FundingOutput()
+	if err != nil {
+		return nil, err
+	}
+
+	found, multiSigIndex := input.FindScriptOutputIndex(
+		fundingTx, fundingOutput.PkScript,
+	)
+	if !found ||
+		fundingTx.TxOut[multiSigIndex].Value != fundingOutput.Value {
+
+		return nil, fmt.Errorf("funding output not found in " +
+			"funding tx")
+	}
+
+	for _, changeOutput := range f.ChangeOutputs {
+		if !hasOutput(fundingTx, changeOutput) {
+			return nil, fmt.Errorf("change output missing from " +
+				"funding tx")
+		}
+	}
+
+	// We'll sign a copy of the transaction, so the caller's version
+	// remains unsigned. The outputs spent by the inputs of the remote
+	// party aren't known to us, so we can only sign taproot inputs if the
+	// transaction spends nothing but our coins.
+	fundingTx = fundingTx.Copy()
+	ourCoins := make(map[wire.OutPoint]*wire.TxOut, len(f.InputCoins))
+	for _, coin := range f.InputCoins {
+		prevOut := coin.TxOut
+		ourCoins[coin.OutPoint] = &prevOut
+	}
+	sigHashes, allPrevOuts := newFundingSigHashes(fundingTx, ourCoins)
+	signDesc := input.SignDescriptor{
+		SigHashes: sigHashes,
+	}
+	for _, coin := range f.InputCoins {
+		inputIndex := -1
+		for i, txIn := range fundingTx.TxIn {
+			if txIn.PreviousOutPoint == coin.OutPoint {
+				inputIndex = i
+				break
+			}
+		}
+		if inputIndex == -1 {
+			return nil, fmt.Errorf("input %v missing from "+
+				"funding tx", coin.OutPoint)
+		}
+
+		signDesc.Output = ourCoins[coin.OutPoint]
+		signDesc.InputIndex = inputIndex
+		signDesc.HashType, err = fundingInputHashType(
+			coin.OutPoint, signDesc.Output, allPrevOuts,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		inputScript, err := f.signer.ComputeInputScript(
+			fundingTx, &signDesc,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		txIn := fundingTx.TxIn[inputIndex]
+		txIn.SignatureScript = inputScript.SigScript
+		txIn.Witness = inputScript.Witness
+	}
+
+	f.chanPoint = &wire.OutPoint{
+		Hash:  fundingTx.TxHash(),
+		Index: multiSigIndex,
+	}
+
+	return fundingTx, nil
+}
+
+// newFundingSigHashes returns the sighash cache for the given funding
+// transaction. Taproot inputs commit to the outputs spent by all inputs of
+// the transaction, so the taproot sighashes can only be computed if all of
+// them are among the passed outputs. Otherwise only the segwit v0 sighashes
+// are computed, which is signaled by the returned boolean being false.
+func newFundingSigHashes(tx *wire.MsgTx,
+	prevOuts map[wire.OutPoint]*wire.TxOut) (*txscript.TxSigHashes, bool) {
+
+	fetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for _, txIn := range tx.TxIn {
+		prevOut, ok := prevOuts[txIn.PreviousOutPoint]
+		if !ok {
+			return input.NewTxSigHashesV0Only(tx), false
+		}
+
+		fetcher.AddPrevOut(txIn.PreviousOutPoint, prevOut)
+	}
+
+	return txscript.NewTxSigHashes(tx, fetcher), true
+}
+
+// fundingInputHashType returns the sighash type our funding input spending
+// the given output is signed with. An error is returned for taproot inputs if
+// not all outputs spent by the funding transaction are known.
+func fundingInputHashType(op wire.OutPoint, prevOut *wire.TxOut,
+	allPrevOuts bool) (txscript.SigHashType, error) {
+
+	if !txscript.IsPayToTaproot(prevOut.PkScript) {
+		return txscript.SigHashAll, nil
+	}
+
+	if !allPrevOuts {
+		return 0, fmt.Errorf("unable to sign taproot input %v, the "+
+			"outputs spent by the remote inputs are unknown", op)
+	}
+
+	return txscript.SigHashDefault, nil
+}
+
+// hasOutput returns true if the transaction contains an output that matches
+// the passed output.
+func hasOutput(tx *wire.MsgTx, txOut *wire.TxOut) bool {
+	for _, out := range tx.TxOut {
+		if out.Value == txOut.Value &&
+			bytes.Equal(out.PkScript, txOut.PkScript) {
+
+			return true
+		}
+	}
+
+	return false
+}
+
 // Inputs returns all inputs to the final funding transaction that we
 // know about. Since this funding transaction is created all from our wallet,
 // it will be all inputs.
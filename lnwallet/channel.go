@@ -11,13 +11,15 @@ import (
 	"sync"
 
 	"github.com/btcsuite/btcd/blockchain"
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/txsort"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/mempool"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btclog"
-	"github.com/btcsuite/btcutil"
-	"github.com/btcsuite/btcutil/txsort"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/chainntnfs"
@@ -303,7 +305,7 @@ type PaymentDescriptor struct {
 	// local node. This signature is generated by the remote node and
 	// stored by the local node in the case that local node needs to
 	// broadcast their commitment transaction.
-	sig *btcec.Signature
+	sig *ecdsa.Signature
 
 	// addCommitHeight[Remote|Local] encodes the height of the commitment
 	// which included this HTLC on either the remote or local commitment
@@ -326,6 +328,13 @@ type PaymentDescriptor struct {
 	// NOTE: Populated only on add payment descriptor entry types.
 	OnionBlob []byte
 
+	// ExtraData is the set of TLV records that were carried within the
+	// update_add_htlc message of this HTLC, such as the blinding point of
+	// an HTLC that is forwarded within a blinded route.
+	//
+	// NOTE: Populated only on add payment descriptor entry types.
+	ExtraData lnwire.ExtraOpaqueData
+
 	// ShaOnionBlob is a sha of the onion blob.
 	//
 	// NOTE: Populated only in payment descriptor with MalformedFail type.
@@ -404,6 +413,7 @@ func PayDescsFromRemoteLogUpdates(chanID lnwire.ShortChannelID, height uint64,
 					Height: height,
 					Index:  uint16(i),
 				},
+				ExtraData: wireMsg.ExtraData,
 			}
 			pd.OnionBlob = make([]byte, len(wireMsg.OnionBlob))
 			copy(pd.OnionBlob[:], wireMsg.OnionBlob[:])
@@ -543,6 +553,12 @@ type commitment struct {
 	// view.
 	outgoingHTLCIndex map[int32]*PaymentDescriptor
 	incomingHTLCIndex map[int32]*PaymentDescriptor
+
+	// spliceSig holds our signatures for the variant of this commitment
+	// that spends the funding output of the pending splice. It's only
+	// populated for commitments of the remote party that were signed
+	// while a splice was pending.
+	spliceSig *lnwire.SpliceCommitSig
 }
 
 // locateOutputIndex is a small helper function to locate the output index of a
@@ -1278,6 +1294,16 @@ type LightningChannel struct {
 
 	commitBuilder *CommitmentBuilder
 
+	// pendingSplice is the splice of the channel whose splice transaction
+	// has been signed, but not locked in yet. While it's set, every new
+	// commitment is also signed for the funding output created by the
+	// splice transaction.
+	pendingSplice *channeldb.PendingSplice
+
+	// spliceLocalTip is the variant of the tip of our local commitment
+	// chain that spends the funding output of the pending splice.
+	spliceLocalTip *commitment
+
 	// [local|remote]Log is a (mostly) append-only log storing all the HTLC
 	// updates to this channel. The log is walked backwards as HTLC updates
 	// are applied in order to re-construct a commitment transaction from a
@@ -1354,9 +1380,38 @@ func NewLightningChannel(signer input.Signer,
 		return nil, err
 	}
 
+	// If the channel has a signed splice that hasn't been locked in yet,
+	// new commitments must be signed for its funding output as well.
+	splice, err := state.PendingSplice()
+	switch err {
+	case nil:
+		lc.pendingSplice = splice
+	case channeldb.ErrNoPendingSplice:
+	default:
+		return nil, err
+	}
+
 	return lc, nil
 }
 
+// FundingPkScript returns the p2wsh pkScript of the funding output of the
+// passed channel. As the multi-sig keys of a channel remain the same across
+// splices, this is also the pkScript of any output created by a splice
+// transaction.
+func FundingPkScript(chanState *channeldb.OpenChannel) ([]byte, error) {
+	localKey := chanState.LocalChanCfg.MultiSigKey.PubKey.
+		SerializeCompressed()
+	remoteKey := chanState.RemoteChanCfg.MultiSigKey.PubKey.
+		SerializeCompressed()
+
+	multiSigScript, err := input.GenMultiSigScript(localKey, remoteKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return input.WitnessScriptHash(multiSigScript)
+}
+
 // createSignDesc derives the SignDescriptor for commitment transactions from
 // other fields on the LightningChannel.
 func (lc *LightningChannel) createSignDesc() error {
@@ -1432,6 +1487,7 @@ func (lc *LightningChannel) logUpdateToPayDesc(logUpdate *channeldb.LogUpdate,
 			HtlcIndex:             wireMsg.ID,
 			LogIndex:              logUpdate.LogIndex,
 			addCommitHeightRemote: commitHeight,
+			ExtraData:             wireMsg.ExtraData,
 		}
 		pd.OnionBlob = make([]byte, len(wireMsg.OnionBlob))
 		copy(pd.OnionBlob[:], wireMsg.OnionBlob[:])
@@ -1631,6 +1687,7 @@ func (lc *LightningChannel) remoteLogUpdateToPayDesc(logUpdate *channeldb.LogUpd
 			HtlcIndex:            wireMsg.ID,
 			LogIndex:             logUpdate.LogIndex,
 			addCommitHeightLocal: commitHeight,
+			ExtraData:            wireMsg.ExtraData,
 		}
 		pd.OnionBlob = make([]byte, len(wireMsg.OnionBlob))
 		copy(pd.OnionBlob, wireMsg.OnionBlob[:])
@@ -2291,6 +2348,34 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 		return nil, err
 	}
 
+	return newBreachRetribution(
+		chanState, revokedSnapshot, stateNum, breachHeight,
+	)
+}
+
+// NewSpliceBreachRetribution creates a new fully populated BreachRetribution
+// for the variant of the revoked state with the passed number that spends the
+// funding output of the pending splice of the channel. Until the splice is
+// completed, both variants of a revoked state can be broadcast.
+func NewSpliceBreachRetribution(chanState *channeldb.OpenChannel,
+	stateNum uint64, breachHeight uint32) (*BreachRetribution, error) {
+
+	revokedSnapshot, err := chanState.FindSpliceCommitment(stateNum, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBreachRetribution(
+		chanState, revokedSnapshot, stateNum, breachHeight,
+	)
+}
+
+// newBreachRetribution creates a new fully populated BreachRetribution for the
+// passed revoked commitment of the remote party.
+func newBreachRetribution(chanState *channeldb.OpenChannel,
+	revokedSnapshot *channeldb.ChannelCommitment, stateNum uint64,
+	breachHeight uint32) (*BreachRetribution, error) {
+
 	commitHash := revokedSnapshot.CommitTx.TxHash()
 
 	// With the state number broadcast known, we can now derive/restore the
@@ -2300,14 +2385,18 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 	if err != nil {
 		return nil, err
 	}
-	commitmentSecret, commitmentPoint := btcec.PrivKeyFromBytes(
-		btcec.S256(), revocationPreimage[:],
+	commitmentSecret, commitmentPoint := btcec.PrivKeyFromBytes(revocationPreimage[:],
 	)
 
+	// The commitment may have been signed before the commitment type of
+	// the channel was upgraded, in which case its outputs follow the
+	// previous type.
+	chanType := chanState.CommitChanType(false, stateNum)
+
 	// With the commitment point generated, we can now generate the four
 	// keys we'll need to reconstruct the commitment state,
 	keyRing := DeriveCommitmentKeys(
-		commitmentPoint, false, chanState.ChanType,
+		commitmentPoint, false, chanType,
 		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
 	)
 
@@ -2329,7 +2418,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 	// Since it is the remote breach we are reconstructing, the output going
 	// to us will be a to-remote script with our local params.
 	ourScript, ourDelay, err := CommitScriptToRemote(
-		chanState.ChanType, keyRing.ToRemoteKey,
+		chanType, keyRing.ToRemoteKey,
 	)
 	if err != nil {
 		return nil, err
@@ -2402,7 +2491,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 		// If the HTLC is dust, then we'll skip it as it doesn't have
 		// an output on the commitment transaction.
 		if htlcIsDust(
-			chanState.ChanType, htlc.Incoming, false,
+			chanType, htlc.Incoming, false,
 			chainfee.SatPerKWeight(revokedSnapshot.FeePerKw),
 			htlc.Amt.ToSatoshis(), chanState.RemoteChanCfg.DustLimit,
 		) {
@@ -2426,7 +2515,7 @@ func NewBreachRetribution(chanState *channeldb.OpenChannel, stateNum uint64,
 		// an outgoing HTLC that we sent, then from the PoV of the
 		// remote commitment state, they're the receiver of this HTLC.
 		htlcPkScript, htlcWitnessScript, err := genHtlcScript(
-			chanState.ChanType, htlc.Incoming, false,
+			chanType, htlc.Incoming, false,
 			htlc.RefundTimeout, htlc.RHash, keyRing,
 		)
 		if err != nil {
@@ -2664,8 +2753,12 @@ func (lc *LightningChannel) fetchCommitmentView(remoteChain bool,
 	return c, nil
 }
 
+// fundingTxIn returns the input that spends the output currently funding the
+// channel. Once a channel has been spliced, this differs from the channel
+// point.
 func fundingTxIn(chanState *channeldb.OpenChannel) wire.TxIn {
-	return *wire.NewTxIn(&chanState.FundingOutpoint, nil, nil)
+	fundingPoint := chanState.ActiveFundingOutpoint()
+	return *wire.NewTxIn(&fundingPoint, nil, nil)
 }
 
 // evaluateHTLCView processes all update entries in both HTLC update logs,
@@ -3041,7 +3134,7 @@ func genRemoteHtlcSigJobs(keyRing *CommitmentKeyRing,
 			WitnessScript: htlc.theirWitnessScript,
 			Output:        txOut,
 			HashType:      sigHashType,
-			SigHashes:     txscript.NewTxSigHashes(sigJob.Tx),
+			SigHashes:     input.NewTxSigHashesV0Only(sigJob.Tx),
 			InputIndex:    0,
 		}
 		sigJob.OutputIndex = htlc.remoteOutputIndex
@@ -3093,7 +3186,7 @@ func genRemoteHtlcSigJobs(keyRing *CommitmentKeyRing,
 			WitnessScript: htlc.theirWitnessScript,
 			Output:        txOut,
 			HashType:      sigHashType,
-			SigHashes:     txscript.NewTxSigHashes(sigJob.Tx),
+			SigHashes:     input.NewTxSigHashesV0Only(sigJob.Tx),
 			InputIndex:    0,
 		}
 		sigJob.OutputIndex = htlc.remoteOutputIndex
@@ -3162,6 +3255,7 @@ func (lc *LightningChannel) createCommitDiff(
 				Amount:      pd.Amount,
 				Expiry:      pd.Timeout,
 				PaymentHash: pd.RHash,
+				ExtraData:   pd.ExtraData,
 			}
 			copy(htlc.OnionBlob[:], pd.OnionBlob)
 			logUpdate.UpdateMsg = htlc
@@ -3299,6 +3393,7 @@ func (lc *LightningChannel) getUnsignedAckedUpdates() []channeldb.LogUpdate {
 				Amount:      pd.Amount,
 				Expiry:      pd.Timeout,
 				PaymentHash: pd.RHash,
+				ExtraData:   pd.ExtraData,
 			}
 			copy(htlc.OnionBlob[:], pd.OnionBlob)
 			logUpdate.UpdateMsg = htlc
@@ -3421,6 +3516,46 @@ func (lc *LightningChannel) validateCommitmentSanity(theirLogCounter,
 		return ErrBelowChanReserve
 	}
 
+	// While a splice is pending, the commitment also has a variant that
+	// spends the funding output of the splice. It carries the same HTLCs
+	// and commitment fee, but the balances are adjusted by the splice, so
+	// we'll make sure they're valid as well.
+	if splice := lc.pendingSplice; splice != nil {
+		ourSpliceBalance := int64(ourBalance) + int64(
+			lnwire.NewMSatFromSatoshis(splice.LocalDelta),
+		)
+		theirSpliceBalance := int64(theirBalance) + int64(
+			lnwire.NewMSatFromSatoshis(splice.RemoteDelta),
+		)
+		ourReserve := int64(lnwire.NewMSatFromSatoshis(
+			lc.channelState.LocalChanCfg.ChanReserve,
+		))
+		theirReserve := int64(lnwire.NewMSatFromSatoshis(
+			lc.channelState.RemoteChanCfg.ChanReserve,
+		))
+
+		switch {
+		case ourSpliceBalance < 0 || theirSpliceBalance < 0:
+			return ErrBelowChanReserve
+
+		case ourBalance < ourInitialBalance &&
+			ourSpliceBalance < ourReserve:
+
+			return ErrBelowChanReserve
+
+		case theirBalance < theirInitialBalance &&
+			theirSpliceBalance < theirReserve:
+
+			return ErrBelowChanReserve
+		}
+
+		numHTLCs := len(filteredView.ourUpdates) +
+			len(filteredView.theirUpdates)
+		if numHTLCs > MaxSpliceHTLCs {
+			return ErrMaxHTLCNumber
+		}
+	}
+
 	// validateUpdates take a set of updates, and validates them against
 	// the passed channel constraints.
 	validateUpdates := func(updates []*PaymentDescriptor,
@@ -3598,7 +3733,7 @@ func (lc *LightningChannel) SignNextCommitment() (lnwire.Sig, []lnwire.Sig, []ch
 	// While the jobs are being carried out, we'll Sign their version of
 	// the new commitment transaction while we're waiting for the rest of
 	// the HTLC signatures to be processed.
-	lc.signDesc.SigHashes = txscript.NewTxSigHashes(newCommitView.txn)
+	lc.signDesc.SigHashes = input.NewTxSigHashesV0Only(newCommitView.txn)
 	rawSig, err := lc.Signer.SignOutputRaw(newCommitView.txn, lc.signDesc)
 	if err != nil {
 		close(cancelChan)
@@ -3633,6 +3768,38 @@ func (lc *LightningChannel) SignNextCommitment() (lnwire.Sig, []lnwire.Sig, []ch
 		htlcSigs = append(htlcSigs, jobResp.Sig)
 	}
 
+	// If a splice is pending, we'll also sign the variant of the new
+	// commitment that spends the funding output of the splice. It's
+	// persisted before the commitment itself, as it replaces it once the
+	// splice is completed.
+	if splice := lc.pendingSplice; splice != nil {
+		spliceCommit, err := lc.spliceCommitment(
+			newCommitView, keyRing, splice,
+		)
+		if err != nil {
+			return sig, htlcSigs, nil, err
+		}
+
+		spliceSig, spliceHtlcSigs, err := lc.signSpliceCommitment(
+			spliceCommit, keyRing, splice.Capacity,
+		)
+		if err != nil {
+			return sig, htlcSigs, nil, err
+		}
+
+		err = lc.channelState.AddSpliceCommitment(
+			spliceCommit.toDiskCommit(false), false,
+		)
+		if err != nil {
+			return sig, htlcSigs, nil, err
+		}
+
+		newCommitView.spliceSig = &lnwire.SpliceCommitSig{
+			CommitSig: spliceSig,
+			HtlcSigs:  spliceHtlcSigs,
+		}
+	}
+
 	// As we're about to proposer a new commitment state for the remote
 	// party, we'll write this pending state to disk before we exit, so we
 	// can retransmit it if necessary.
@@ -3640,6 +3807,7 @@ func (lc *LightningChannel) SignNextCommitment() (lnwire.Sig, []lnwire.Sig, []ch
 	if err != nil {
 		return sig, htlcSigs, nil, err
 	}
+	commitDiff.CommitSig.SpliceSig = newCommitView.spliceSig
 	err = lc.channelState.AppendRemoteCommitChain(commitDiff)
 	if err != nil {
 		return sig, htlcSigs, nil, err
@@ -3720,6 +3888,14 @@ func (lc *LightningChannel) ProcessChanSyncMsg(
 		}
 	}
 
+	// If we applied an upgrade of the commitment type that the remote
+	// party didn't, we'll undo it before any commitments are
+	// retransmitted.
+	if err := lc.syncCommitTypeUpgrades(msg); err != nil {
+		lc.log.Errorf("sync failed: %v", err)
+		return nil, nil, nil, err
+	}
+
 	// If we detect that this is is a restored channel, then we can skip a
 	// portion of the verification, as we already know that we're unable to
 	// proceed with any updates.
@@ -4016,6 +4192,18 @@ func (lc *LightningChannel) computeView(view *htlcView, remoteChain bool,
 		theirBalance += lnwire.NewMSatFromSatoshis(
 			commitChain.tip().fee)
 	}
+
+	// If the commitment type was upgraded to anchors, the initiator pays
+	// for the anchor outputs once the first commitment of the new type is
+	// created.
+	anchorCost := lnwire.NewMSatFromSatoshis(
+		lc.upgradedAnchorCost(commitChain, !remoteChain),
+	)
+	if lc.channelState.IsInitiator {
+		ourBalance -= anchorCost
+	} else {
+		theirBalance -= anchorCost
+	}
 	nextHeight := commitChain.tip().height + 1
 
 	// Initiate feePerKw to the last committed fee for this chain as we'll
@@ -4095,7 +4283,7 @@ func genHtlcSigValidationJobs(localCommitmentView *commitment,
 		var (
 			htlcIndex uint64
 			sigHash   func() ([]byte, error)
-			sig       *btcec.Signature
+			sig       *ecdsa.Signature
 			err       error
 		)
 
@@ -4128,7 +4316,7 @@ func genHtlcSigValidationJobs(localCommitmentView *commitment,
 					return nil, err
 				}
 
-				hashCache := txscript.NewTxSigHashes(successTx)
+				hashCache := input.NewTxSigHashesV0Only(successTx)
 				sigHash, err := txscript.CalcWitnessSigHash(
 					htlc.ourWitnessScript, hashCache,
 					sigHashType, successTx, 0,
@@ -4182,7 +4370,7 @@ func genHtlcSigValidationJobs(localCommitmentView *commitment,
 					return nil, err
 				}
 
-				hashCache := txscript.NewTxSigHashes(timeoutTx)
+				hashCache := input.NewTxSigHashesV0Only(timeoutTx)
 				sigHash, err := txscript.CalcWitnessSigHash(
 					htlc.ourWitnessScript, hashCache,
 					sigHashType, timeoutTx, 0,
@@ -4304,6 +4492,57 @@ func (lc *LightningChannel) ReceiveNewCommitment(commitSig lnwire.Sig,
 	lc.Lock()
 	defer lc.Unlock()
 
+	return lc.receiveNewCommitment(commitSig, htlcSigs, nil)
+}
+
+// ReceiveCommitSig processes a commitment_signed message sent by the remote
+// party. In addition to ReceiveNewCommitment, it handles the signatures for
+// the variant of the new commitment that spends the funding output of a
+// pending splice.
+func (lc *LightningChannel) ReceiveCommitSig(msg *lnwire.CommitSig) error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	commitSig, htlcSigs := msg.CommitSig, msg.HtlcSigs
+	switch {
+
+	// Once the remote party has received our splice_locked, it may
+	// complete the splice before we do. Its signature then only covers
+	// the commitment that spends the funding output of the splice, so
+	// we'll complete the splice before validating it.
+	case lc.pendingSplice != nil && lc.pendingSplice.Locked &&
+		msg.SpliceSig == nil:
+
+		if err := lc.completeSplice(); err != nil {
+			return err
+		}
+
+	// If we've completed the splice before the remote party did, the
+	// variant signed for the funding output of the splice is the one
+	// that's valid for us.
+	case lc.pendingSplice == nil && msg.SpliceSig != nil &&
+		lc.channelState.IsSpliced():
+
+		commitSig = msg.SpliceSig.CommitSig
+		htlcSigs = msg.SpliceSig.HtlcSigs
+	}
+
+	var spliceSig *lnwire.SpliceCommitSig
+	if lc.pendingSplice != nil {
+		spliceSig = msg.SpliceSig
+	}
+
+	return lc.receiveNewCommitment(commitSig, htlcSigs, spliceSig)
+}
+
+// receiveNewCommitment is the internal version of ReceiveNewCommitment. If a
+// splice is pending, the passed spliceSig must hold the signatures for the
+// variant of the new commitment that spends its funding output.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) receiveNewCommitment(commitSig lnwire.Sig,
+	htlcSigs []lnwire.Sig, spliceSig *lnwire.SpliceCommitSig) error {
+
 	// Check for empty commit sig. Because of a previously existing bug, it
 	// is possible that we receive an empty commit sig from nodes running an
 	// older version. This is a relaxation of the spec, but it is still
@@ -4375,7 +4614,7 @@ func (lc *LightningChannel) ReceiveNewCommitment(commitSig lnwire.Sig,
 	// this newly proposed state update.
 	localCommitTx := localCommitmentView.txn
 	multiSigScript := lc.signDesc.WitnessScript
-	hashCache := txscript.NewTxSigHashes(localCommitTx)
+	hashCache := input.NewTxSigHashesV0Only(localCommitTx)
 	sigHash, err := txscript.CalcWitnessSigHash(
 		multiSigScript, hashCache, txscript.SigHashAll,
 		localCommitTx, 0, int64(lc.channelState.Capacity),
@@ -4404,16 +4643,12 @@ func (lc *LightningChannel) ReceiveNewCommitment(commitSig lnwire.Sig,
 	// While the HTLC verification jobs are proceeding asynchronously,
 	// we'll ensure that the newly constructed commitment state has a valid
 	// signature.
-	verifyKey := btcec.PublicKey{
-		X:     lc.channelState.RemoteChanCfg.MultiSigKey.PubKey.X,
-		Y:     lc.channelState.RemoteChanCfg.MultiSigKey.PubKey.Y,
-		Curve: btcec.S256(),
-	}
+	verifyKey := lc.channelState.RemoteChanCfg.MultiSigKey.PubKey
 	cSig, err := commitSig.ToSignature()
 	if err != nil {
 		return err
 	}
-	if !cSig.Verify(sigHash, &verifyKey) {
+	if !cSig.Verify(sigHash, verifyKey) {
 		close(cancelChan)
 
 		// If we fail to validate their commitment signature, we'll
@@ -4462,6 +4697,39 @@ func (lc *LightningChannel) ReceiveNewCommitment(commitSig lnwire.Sig,
 		}
 	}
 
+	// If a splice is pending, the variant of the new commitment that
+	// spends the funding output of the splice must be signed as well.
+	// It's persisted right away, as it replaces the new commitment once
+	// the splice is completed.
+	if splice := lc.pendingSplice; splice != nil {
+		if spliceSig == nil {
+			return ErrMissingSpliceSig
+		}
+
+		spliceCommit, err := lc.spliceCommitment(
+			localCommitmentView, keyRing, splice,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = lc.verifySpliceCommitment(
+			spliceCommit, keyRing, splice.Capacity,
+			spliceSig.CommitSig, spliceSig.HtlcSigs,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = lc.channelState.AddSpliceCommitment(
+			spliceCommit.toDiskCommit(true), true,
+		)
+		if err != nil {
+			return err
+		}
+		lc.spliceLocalTip = spliceCommit
+	}
+
 	// The signature checks out, so we can now add the new commitment to
 	// our local commitment chain.
 	localCommitmentView.sig = commitSig.ToSignatureBytes()
@@ -4524,13 +4792,27 @@ func (lc *LightningChannel) oweCommitment(local bool) bool {
 			lastLocalCommit.ourMessageIndex
 	}
 
+	// If the commitment type was upgraded, a commitment of the new type
+	// is owed even without any updates.
+	var upgradePending bool
+	if upgrade := lc.latestCommitTypeUpgrade(); upgrade != nil {
+		if local {
+			upgradePending = lastRemoteCommit.height <
+				upgrade.RemoteHeight
+		} else {
+			upgradePending = lastLocalCommit.height <
+				upgrade.LocalHeight
+		}
+	}
+
 	// If any of the conditions above is true, we owe a commitment
 	// signature.
-	oweCommitment := localUpdatesPending || remoteUpdatesPending
+	oweCommitment := localUpdatesPending || remoteUpdatesPending ||
+		upgradePending
 
 	lc.log.Tracef("%v owes commit: %v (local updates: %v, "+
-		"remote updates %v)", perspective, oweCommitment,
-		localUpdatesPending, remoteUpdatesPending)
+		"remote updates %v, upgrade %v)", perspective, oweCommitment,
+		localUpdatesPending, remoteUpdatesPending, upgradePending)
 
 	return oweCommitment
 }
@@ -4765,6 +5047,7 @@ func (lc *LightningChannel) ReceiveRevocation(revMsg *lnwire.RevokeAndAck) (
 				Amount:      pd.Amount,
 				Expiry:      pd.Timeout,
 				PaymentHash: pd.RHash,
+				ExtraData:   pd.ExtraData,
 			}
 			copy(htlc.OnionBlob[:], pd.OnionBlob)
 			logUpdate.UpdateMsg = htlc
@@ -4981,6 +5264,7 @@ func (lc *LightningChannel) htlcAddDescriptor(htlc *lnwire.UpdateAddHTLC,
 		LogIndex:       lc.localUpdateLog.logIndex,
 		HtlcIndex:      lc.localUpdateLog.htlcCounter,
 		OnionBlob:      htlc.OnionBlob[:],
+		ExtraData:      htlc.ExtraData,
 		OpenCircuitKey: openKey,
 	}
 }
@@ -5037,6 +5321,7 @@ func (lc *LightningChannel) ReceiveHTLC(htlc *lnwire.UpdateAddHTLC) (uint64, err
 		LogIndex:  lc.remoteUpdateLog.logIndex,
 		HtlcIndex: lc.remoteUpdateLog.htlcCounter,
 		OnionBlob: htlc.OnionBlob[:],
+		ExtraData: htlc.ExtraData,
 	}
 
 	localACKedIndex := lc.remoteCommitChain.tail().ourMessageIndex
@@ -5352,16 +5637,14 @@ func (lc *LightningChannel) getSignedCommitTx() (*wire.MsgTx, error) {
 	localCommit := lc.channelState.LocalCommitment
 	commitTx := localCommit.CommitTx.Copy()
 
-	theirSig, err := btcec.ParseDERSignature(
-		localCommit.CommitSig, btcec.S256(),
-	)
+	theirSig, err := ecdsa.ParseDERSignature(localCommit.CommitSig)
 	if err != nil {
 		return nil, err
 	}
 
 	// With this, we then generate the full witness so the caller can
 	// broadcast a fully signed transaction.
-	lc.signDesc.SigHashes = txscript.NewTxSigHashes(commitTx)
+	lc.signDesc.SigHashes = input.NewTxSigHashesV0Only(commitTx)
 	ourSig, err := lc.Signer.SignOutputRaw(commitTx, lc.signDesc)
 	if err != nil {
 		return nil, err
@@ -5457,10 +5740,14 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel, signer input.Si
 	remoteCommit channeldb.ChannelCommitment,
 	commitPoint *btcec.PublicKey) (*UnilateralCloseSummary, error) {
 
+	// The remote commitment may have been signed before the commitment
+	// type of the channel was upgraded.
+	chanType := chanState.CommitChanType(false, remoteCommit.CommitHeight)
+
 	// First, we'll generate the commitment point and the revocation point
 	// so we can re-construct the HTLC state and also our payment key.
 	keyRing := DeriveCommitmentKeys(
-		commitPoint, false, chanState.ChanType,
+		commitPoint, false, chanType,
 		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
 	)
 
@@ -5470,7 +5757,7 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel, signer input.Si
 		chainfee.SatPerKWeight(remoteCommit.FeePerKw), false, signer,
 		remoteCommit.Htlcs, keyRing, &chanState.LocalChanCfg,
 		&chanState.RemoteChanCfg, commitSpend.SpendingTx,
-		chanState.ChanType,
+		chanType,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create htlc "+
@@ -5483,7 +5770,7 @@ func NewUnilateralCloseSummary(chanState *channeldb.OpenChannel, signer input.Si
 	// locate the output index of our non-delayed output on the commitment
 	// transaction.
 	selfScript, maturityDelay, err := CommitScriptToRemote(
-		chanState.ChanType, keyRing.ToRemoteKey,
+		chanType, keyRing.ToRemoteKey,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create self commit "+
@@ -5760,11 +6047,11 @@ func newOutgoingHtlcResolution(signer input.Signer,
 		WitnessScript: htlcScript,
 		Output:        txOut,
 		HashType:      txscript.SigHashAll,
-		SigHashes:     txscript.NewTxSigHashes(timeoutTx),
+		SigHashes:     input.NewTxSigHashesV0Only(timeoutTx),
 		InputIndex:    0,
 	}
 
-	htlcSig, err := btcec.ParseDERSignature(htlc.Signature, btcec.S256())
+	htlcSig, err := ecdsa.ParseDERSignature(htlc.Signature)
 	if err != nil {
 		return nil, err
 	}
@@ -5897,11 +6184,11 @@ func newIncomingHtlcResolution(signer input.Signer,
 		WitnessScript: htlcScript,
 		Output:        txOut,
 		HashType:      txscript.SigHashAll,
-		SigHashes:     txscript.NewTxSigHashes(successTx),
+		SigHashes:     input.NewTxSigHashesV0Only(successTx),
 		InputIndex:    0,
 	}
 
-	htlcSig, err := btcec.ParseDERSignature(htlc.Signature, btcec.S256())
+	htlcSig, err := ecdsa.ParseDERSignature(htlc.Signature)
 	if err != nil {
 		return nil, err
 	}
@@ -6172,9 +6459,13 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 	if err != nil {
 		return nil, err
 	}
+
+	// Our commitment may have been signed before the commitment type of
+	// the channel was upgraded.
+	chanType := chanState.CommitChanType(true, stateNum)
 	commitPoint := input.ComputeCommitmentPoint(revocation[:])
 	keyRing := DeriveCommitmentKeys(
-		commitPoint, true, chanState.ChanType,
+		commitPoint, true, chanType,
 		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
 	)
 
@@ -6243,7 +6534,7 @@ func NewLocalForceCloseSummary(chanState *channeldb.OpenChannel,
 	htlcResolutions, err := extractHtlcResolutions(
 		chainfee.SatPerKWeight(localCommit.FeePerKw), true, signer,
 		localCommit.Htlcs, keyRing, &chanState.LocalChanCfg,
-		&chanState.RemoteChanCfg, commitTx, chanState.ChanType,
+		&chanState.RemoteChanCfg, commitTx, chanType,
 	)
 	if err != nil {
 		return nil, err
@@ -6294,7 +6585,7 @@ func (lc *LightningChannel) CreateCloseProposal(proposedFee btcutil.Amount,
 	// care not to persist the adjusted balance, as the feeRate may change
 	// during the channel closing process.
 	ourBalance, theirBalance, err := CoopCloseBalance(
-		lc.channelState.ChanType, lc.channelState.IsInitiator,
+		lc.localCommitChanType(), lc.channelState.IsInitiator,
 		proposedFee, lc.channelState.LocalCommitment,
 	)
 	if err != nil {
@@ -6319,7 +6610,7 @@ func (lc *LightningChannel) CreateCloseProposal(proposedFee btcutil.Amount,
 	// initiator we'll simply send our signature over to the remote party,
 	// using the generated txid to be notified once the closure transaction
 	// has been confirmed.
-	lc.signDesc.SigHashes = txscript.NewTxSigHashes(closeTx)
+	lc.signDesc.SigHashes = input.NewTxSigHashesV0Only(closeTx)
 	sig, err := lc.Signer.SignOutputRaw(closeTx, lc.signDesc)
 	if err != nil {
 		return nil, nil, 0, err
@@ -6356,7 +6647,7 @@ func (lc *LightningChannel) CompleteCooperativeClose(
 
 	// Get the final balances after subtracting the proposed fee.
 	ourBalance, theirBalance, err := CoopCloseBalance(
-		lc.channelState.ChanType, lc.channelState.IsInitiator,
+		lc.localCommitChanType(), lc.channelState.IsInitiator,
 		proposedFee, lc.channelState.LocalCommitment,
 	)
 	if err != nil {
@@ -6379,10 +6670,138 @@ func (lc *LightningChannel) CompleteCooperativeClose(
 	if err := blockchain.CheckTransactionSanity(tx); err != nil {
 		return nil, 0, err
 	}
-	hashCache := txscript.NewTxSigHashes(closeTx)
 
-	// Finally, construct the witness stack minding the order of the
-	// pubkeys+sigs on the stack.
+	// Finally, add the witness and ensure that the remote peer supplied a
+	// valid signature.
+	if err := lc.finalizeCloseTx(closeTx, localSig, remoteSig); err != nil {
+		return nil, 0, err
+	}
+
+	// As the transaction is sane, and the scripts are valid we'll mark the
+	// channel now as closed as the closure transaction should get into the
+	// chain in a timely manner and possibly be re-broadcast by the wallet.
+	lc.status = channelClosed
+
+	return closeTx, ourBalance, nil
+}
+
+// CreateRbfCloseProposal is the equivalent of CreateCloseProposal for a
+// cooperative close whose closing transaction can be replaced with rbf. The
+// proposed fee is paid entirely by the local party if localPays is true, and
+// by the remote party otherwise. As a closing transaction that has already
+// been broadcast may be replaced by one paying a higher fee, proposals can
+// still be created once the channel is closed.
+func (lc *LightningChannel) CreateRbfCloseProposal(proposedFee btcutil.Amount,
+	localPays bool, localDeliveryScript,
+	remoteDeliveryScript []byte) (input.Signature, *chainhash.Hash,
+	btcutil.Amount, error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	closeTx, ourBalance, err := lc.createRbfCloseTx(
+		proposedFee, localPays, localDeliveryScript,
+		remoteDeliveryScript,
+	)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	lc.signDesc.SigHashes = input.NewTxSigHashesV0Only(closeTx)
+	sig, err := lc.Signer.SignOutputRaw(closeTx, lc.signDesc)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	if lc.status != channelClosed {
+		lc.status = channelClosing
+	}
+
+	closeTXID := closeTx.TxHash()
+	return sig, &closeTXID, ourBalance, nil
+}
+
+// CompleteRbfCooperativeClose is the equivalent of CompleteCooperativeClose
+// for a cooperative close whose closing transaction can be replaced with rbf.
+// The fee is paid entirely by the local party if localPays is true, and by the
+// remote party otherwise.
+//
+// NOTE: The passed local and remote sigs are expected to be fully complete
+// signatures including the proper sighash byte.
+func (lc *LightningChannel) CompleteRbfCooperativeClose(
+	localSig, remoteSig input.Signature,
+	localDeliveryScript, remoteDeliveryScript []byte,
+	proposedFee btcutil.Amount, localPays bool) (*wire.MsgTx,
+	btcutil.Amount, error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	closeTx, ourBalance, err := lc.createRbfCloseTx(
+		proposedFee, localPays, localDeliveryScript,
+		remoteDeliveryScript,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := lc.finalizeCloseTx(closeTx, localSig, remoteSig); err != nil {
+		return nil, 0, err
+	}
+
+	lc.status = channelClosed
+
+	return closeTx, ourBalance, nil
+}
+
+// createRbfCloseTx creates a replaceable cooperative close transaction whose
+// fee is paid by the local party if localPays is true, and by the remote
+// party otherwise. Our balance in the transaction is returned along with it.
+//
+// NOTE: The channel's mutex MUST be held when calling this method.
+func (lc *LightningChannel) createRbfCloseTx(fee btcutil.Amount,
+	localPays bool, localDeliveryScript,
+	remoteDeliveryScript []byte) (*wire.MsgTx, btcutil.Amount, error) {
+
+	ourBalance, theirBalance, err := RbfCoopCloseBalance(
+		lc.localCommitChanType(), lc.channelState.IsInitiator,
+		localPays, fee, lc.channelState.LocalCommitment,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// The input signals replaceability, such that either party can
+	// publish a closing transaction paying a higher fee later on.
+	txIn := fundingTxIn(lc.channelState)
+	txIn.Sequence = mempool.MaxRBFSequence
+
+	closeTx := CreateCooperativeCloseTx(
+		txIn, lc.channelState.LocalChanCfg.DustLimit,
+		lc.channelState.RemoteChanCfg.DustLimit, ourBalance,
+		theirBalance, localDeliveryScript, remoteDeliveryScript,
+	)
+
+	tx := btcutil.NewTx(closeTx)
+	if err := blockchain.CheckTransactionSanity(tx); err != nil {
+		return nil, 0, err
+	}
+
+	return closeTx, ourBalance, nil
+}
+
+// finalizeCloseTx adds the witness spending the funding output to the passed
+// cooperative close transaction, and validates the resulting transaction to
+// ensure that the signatures of both parties are valid.
+//
+// NOTE: The channel's mutex MUST be held when calling this method.
+func (lc *LightningChannel) finalizeCloseTx(closeTx *wire.MsgTx,
+	localSig, remoteSig input.Signature) error {
+
+	hashCache := input.NewTxSigHashesV0Only(closeTx)
+
+	// Construct the witness stack minding the order of the pubkeys+sigs
+	// on the stack.
 	ourKey := lc.channelState.LocalChanCfg.MultiSigKey.PubKey.
 		SerializeCompressed()
 	theirKey := lc.channelState.RemoteChanCfg.MultiSigKey.PubKey.
@@ -6397,20 +6816,15 @@ func (lc *LightningChannel) CompleteCooperativeClose(
 	// properly met, and that the remote peer supplied a valid signature.
 	prevOut := lc.signDesc.Output
 	vm, err := txscript.NewEngine(prevOut.PkScript, closeTx, 0,
-		txscript.StandardVerifyFlags, nil, hashCache, prevOut.Value)
+		txscript.StandardVerifyFlags, nil, hashCache, prevOut.Value,
+		txscript.NewCannedPrevOutputFetcher(
+			prevOut.PkScript, prevOut.Value,
+		))
 	if err != nil {
-		return nil, 0, err
-	}
-	if err := vm.Execute(); err != nil {
-		return nil, 0, err
+		return err
 	}
 
-	// As the transaction is sane, and the scripts are valid we'll mark the
-	// channel now as closed as the closure transaction should get into the
-	// chain in a timely manner and possibly be re-broadcast by the wallet.
-	lc.status = channelClosed
-
-	return closeTx, ourBalance, nil
+	return vm.Execute()
 }
 
 // AnchorResolutions is a set of anchor resolutions that's being used when
@@ -6584,12 +6998,26 @@ func (lc *LightningChannel) availableBalance() (lnwire.MilliSatoshi, int64) {
 		htlcView, true,
 	)
 
-	// Return which ever balance is lowest.
-	if ourRemoteCommitBalance < ourLocalCommitBalance {
-		return ourRemoteCommitBalance, commitWeight
+	// Use which ever balance is lowest.
+	balance := ourLocalCommitBalance
+	if ourRemoteCommitBalance < balance {
+		balance = ourRemoteCommitBalance
+	}
+
+	// If a pending splice removes funds from our balance, they're not
+	// available on the commitments that spend its funding output.
+	if lc.pendingSplice != nil && lc.pendingSplice.LocalDelta < 0 {
+		spliceAmt := lnwire.NewMSatFromSatoshis(
+			-lc.pendingSplice.LocalDelta,
+		)
+		if balance < spliceAmt {
+			balance = 0
+		} else {
+			balance -= spliceAmt
+		}
 	}
 
-	return ourLocalCommitBalance, commitWeight
+	return balance, commitWeight
 }
 
 // availableCommitmentBalance attempts to calculate the balance we have
@@ -7005,6 +7433,19 @@ func (lc *LightningChannel) MarkCoopBroadcasted(tx *wire.MsgTx,
 	return lc.channelState.MarkCoopBroadcasted(tx, localInitiated)
 }
 
+// MarkRbfCoopBroadcasted marks the channel as a replaceable cooperative close
+// transaction has been broadcast, and persists the state of the close such
+// that the closing transaction can be replaced after a restart. It takes a
+// locally initiated bool which is true if we initiated the cooperative close.
+func (lc *LightningChannel) MarkRbfCoopBroadcasted(
+	coopClose *channeldb.RbfCoopClose, localInitiated bool) error {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	return lc.channelState.MarkRbfCoopBroadcasted(coopClose, localInitiated)
+}
+
 // MarkDataLoss marks sets the channel status to LocalDataLoss and stores the
 // passed commitPoint for use to retrieve funds in case the remote force closes
 // the channel.
@@ -10,10 +10,11 @@ import (
 	"testing"
 
 	"github.com/btcsuite/btcd/blockchain"
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/mempool"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -800,6 +801,81 @@ func testCoopClose(t *testing.T, testCase *coopCloseTestCase) {
 	}
 }
 
+// TestRbfCooperativeChannelClosure tests that the party proposing a
+// replaceable cooperative close pays its full fee, and that a closing
+// transaction can be replaced by one paying a higher fee after the channel
+// has been closed.
+func TestRbfCooperativeChannelClosure(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit | channeldb.AnchorOutputsBit,
+	)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	aliceDeliveryScript := bobsPrivKey[:]
+	bobDeliveryScript := testHdSeed[:]
+
+	// The initial balances of both parties, with the commitment fee and
+	// the anchors returned to Alice, the initiator of the channel.
+	aliceCommit := aliceChannel.channelState.LocalCommitment
+	aliceBalance := aliceCommit.LocalBalance.ToSatoshis() +
+		aliceCommit.CommitFee + 2*anchorSize
+	bobBalance := aliceCommit.RemoteBalance.ToSatoshis()
+
+	// closeWithBobPaying has Bob propose a closing transaction with the
+	// given fee, which is then completed by both parties.
+	closeWithBobPaying := func(fee btcutil.Amount) *wire.MsgTx {
+		bobSig, bobTxid, bobTxBalance, err :=
+			bobChannel.CreateRbfCloseProposal(
+				fee, true, bobDeliveryScript,
+				aliceDeliveryScript,
+			)
+		require.NoError(t, err)
+		require.Equal(t, bobBalance-fee, bobTxBalance)
+
+		aliceSig, _, _, err := aliceChannel.CreateRbfCloseProposal(
+			fee, false, aliceDeliveryScript, bobDeliveryScript,
+		)
+		require.NoError(t, err)
+
+		closeTx, aliceTxBalance, err :=
+			aliceChannel.CompleteRbfCooperativeClose(
+				aliceSig, bobSig, aliceDeliveryScript,
+				bobDeliveryScript, fee, false,
+			)
+		require.NoError(t, err)
+		require.Equal(t, aliceBalance, aliceTxBalance)
+		require.Equal(t, *bobTxid, closeTx.TxHash())
+		require.Equal(
+			t, uint32(mempool.MaxRBFSequence),
+			closeTx.TxIn[0].Sequence,
+		)
+
+		_, _, err = bobChannel.CompleteRbfCooperativeClose(
+			bobSig, aliceSig, bobDeliveryScript,
+			aliceDeliveryScript, fee, true,
+		)
+		require.NoError(t, err)
+
+		return closeTx
+	}
+
+	closeTx := closeWithBobPaying(1000)
+
+	// Both channels are now closed, but Bob can still replace the closing
+	// transaction with one paying a higher fee.
+	replacementTx := closeWithBobPaying(2000)
+	require.NotEqual(t, closeTx.TxHash(), replacementTx.TxHash())
+
+	// Bob can't propose a fee that exceeds his balance.
+	_, _, _, err = bobChannel.CreateRbfCloseProposal(
+		bobBalance+1, true, bobDeliveryScript, aliceDeliveryScript,
+	)
+	require.Error(t, err)
+}
+
 // TestForceClose checks that the resulting ForceCloseSummary is correct when a
 // peer is ForceClosing the channel. Will check outputs both above and below
 // the dust limit. Additionally, we'll ensure that the node which executed the
@@ -990,7 +1066,10 @@ func testForceClose(t *testing.T, testCase *forceCloseTestCase) {
 	timeoutTx := htlcResolution.SignedTimeoutTx
 	vm, err := txscript.NewEngine(senderHtlcPkScript,
 		timeoutTx, 0, txscript.StandardVerifyFlags, nil,
-		nil, int64(htlcAmount.ToSatoshis()))
+		nil, int64(htlcAmount.ToSatoshis()),
+		txscript.NewCannedPrevOutputFetcher(
+			senderHtlcPkScript, int64(htlcAmount.ToSatoshis()),
+		))
 	if err != nil {
 		t.Fatalf("unable to create engine: %v", err)
 	}
@@ -1026,6 +1105,9 @@ func testForceClose(t *testing.T, testCase *forceCloseTestCase) {
 		htlcResolution.SweepSignDesc.Output.PkScript,
 		sweepTx, 0, txscript.StandardVerifyFlags, nil,
 		nil, htlcResolution.SweepSignDesc.Output.Value,
+		txscript.NewCannedPrevOutputFetcher(
+			htlcResolution.SweepSignDesc.Output.PkScript, htlcResolution.SweepSignDesc.Output.Value,
+		),
 	)
 	if err != nil {
 		t.Fatalf("unable to create engine: %v", err)
@@ -1057,7 +1139,10 @@ func testForceClose(t *testing.T, testCase *forceCloseTestCase) {
 	successTx.TxIn[0].Witness[3] = preimageBob[:]
 	vm, err = txscript.NewEngine(receiverHtlcScript,
 		successTx, 0, txscript.StandardVerifyFlags, nil,
-		nil, int64(htlcAmount.ToSatoshis()))
+		nil, int64(htlcAmount.ToSatoshis()),
+		txscript.NewCannedPrevOutputFetcher(
+			receiverHtlcScript, int64(htlcAmount.ToSatoshis()),
+		))
 	if err != nil {
 		t.Fatalf("unable to create engine: %v", err)
 	}
@@ -1089,6 +1174,9 @@ func testForceClose(t *testing.T, testCase *forceCloseTestCase) {
 		inHtlcResolution.SweepSignDesc.Output.PkScript,
 		sweepTx, 0, txscript.StandardVerifyFlags, nil,
 		nil, inHtlcResolution.SweepSignDesc.Output.Value,
+		txscript.NewCannedPrevOutputFetcher(
+			inHtlcResolution.SweepSignDesc.Output.PkScript, inHtlcResolution.SweepSignDesc.Output.Value,
+		),
 	)
 	if err != nil {
 		t.Fatalf("unable to create engine: %v", err)
@@ -4401,7 +4489,7 @@ func TestChanSyncFailure(t *testing.T) {
 	}
 	p := bobSyncMsg.LocalUnrevokedCommitPoint.SerializeCompressed()
 	p[4] ^= 0x01
-	modCommitPoint, err := btcec.ParsePubKey(p, btcec.S256())
+	modCommitPoint, err := btcec.ParsePubKey(p)
 	if err != nil {
 		t.Fatalf("unable to parse pubkey: %v", err)
 	}
@@ -5958,7 +6046,7 @@ func TestChannelUnilateralCloseHtlcResolution(t *testing.T) {
 		Value:    outHtlcResolution.SweepSignDesc.Output.Value,
 	})
 	outHtlcResolution.SweepSignDesc.InputIndex = 0
-	outHtlcResolution.SweepSignDesc.SigHashes = txscript.NewTxSigHashes(
+	outHtlcResolution.SweepSignDesc.SigHashes = input.NewTxSigHashesV0Only(
 		sweepTx,
 	)
 	sweepTx.LockTime = outHtlcResolution.Expiry
@@ -5976,6 +6064,9 @@ func TestChannelUnilateralCloseHtlcResolution(t *testing.T) {
 		outHtlcResolution.SweepSignDesc.Output.PkScript,
 		sweepTx, 0, txscript.StandardVerifyFlags, nil,
 		nil, outHtlcResolution.SweepSignDesc.Output.Value,
+		txscript.NewCannedPrevOutputFetcher(
+			outHtlcResolution.SweepSignDesc.Output.PkScript, outHtlcResolution.SweepSignDesc.Output.Value,
+		),
 	)
 	if err != nil {
 		t.Fatalf("unable to create engine: %v", err)
@@ -5996,7 +6087,7 @@ func TestChannelUnilateralCloseHtlcResolution(t *testing.T) {
 		Value:    inHtlcResolution.SweepSignDesc.Output.Value,
 	})
 	inHtlcResolution.SweepSignDesc.InputIndex = 0
-	inHtlcResolution.SweepSignDesc.SigHashes = txscript.NewTxSigHashes(
+	inHtlcResolution.SweepSignDesc.SigHashes = input.NewTxSigHashesV0Only(
 		sweepTx,
 	)
 	sweepTx.TxIn[0].Witness, err = input.SenderHtlcSpendRedeem(
@@ -6014,6 +6105,9 @@ func TestChannelUnilateralCloseHtlcResolution(t *testing.T) {
 		inHtlcResolution.SweepSignDesc.Output.PkScript,
 		sweepTx, 0, txscript.StandardVerifyFlags, nil,
 		nil, inHtlcResolution.SweepSignDesc.Output.Value,
+		txscript.NewCannedPrevOutputFetcher(
+			inHtlcResolution.SweepSignDesc.Output.PkScript, inHtlcResolution.SweepSignDesc.Output.Value,
+		),
 	)
 	if err != nil {
 		t.Fatalf("unable to create engine: %v", err)
@@ -6131,7 +6225,7 @@ func TestChannelUnilateralClosePendingCommit(t *testing.T) {
 		PkScript: testHdSeed[:],
 		Value:    aliceSignDesc.Output.Value,
 	})
-	aliceSignDesc.SigHashes = txscript.NewTxSigHashes(sweepTx)
+	aliceSignDesc.SigHashes = input.NewTxSigHashesV0Only(sweepTx)
 	sweepTx.TxIn[0].Witness, err = input.CommitSpendNoDelay(
 		aliceChannel.Signer, &aliceSignDesc, sweepTx, false,
 	)
@@ -6145,6 +6239,9 @@ func TestChannelUnilateralClosePendingCommit(t *testing.T) {
 		aliceSignDesc.Output.PkScript, sweepTx, 0,
 		txscript.StandardVerifyFlags, nil, nil,
 		aliceSignDesc.Output.Value,
+		txscript.NewCannedPrevOutputFetcher(
+			aliceSignDesc.Output.PkScript, aliceSignDesc.Output.Value,
+		),
 	)
 	if err != nil {
 		t.Fatalf("unable to create engine: %v", err)
@@ -9669,3 +9766,219 @@ func TestMayAddOutgoingHtlcZeroValue(t *testing.T) {
 	require.NoError(t, aliceChannel.MayAddOutgoingHtlc())
 	require.NoError(t, bobChannel.MayAddOutgoingHtlc())
 }
+
+// TestHasPendingUpdates tests that updates are reported as pending until they
+// are irrevocably committed to by both parties.
+func TestHasPendingUpdates(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	assertPending := func(alice, bob bool) {
+		t.Helper()
+
+		require.Equal(t, alice, aliceChannel.HasPendingUpdates(true))
+		require.Equal(t, alice, bobChannel.HasPendingUpdates(false))
+		require.Equal(t, bob, bobChannel.HasPendingUpdates(true))
+		require.Equal(t, bob, aliceChannel.HasPendingUpdates(false))
+	}
+	assertPending(false, false)
+
+	// Alice adds an HTLC, which is pending until both parties revoked
+	// their prior commitments.
+	htlc, _ := createHTLC(0, lnwire.MilliSatoshi(5000000))
+	_, err = aliceChannel.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	_, err = bobChannel.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+	assertPending(true, false)
+
+	// -----sig---->
+	aliceSig, aliceHtlcSigs, _, err := aliceChannel.SignNextCommitment()
+	require.NoError(t, err)
+	err = bobChannel.ReceiveNewCommitment(aliceSig, aliceHtlcSigs)
+	require.NoError(t, err)
+
+	// <----rev-----
+	bobRevocation, _, err := bobChannel.RevokeCurrentCommitment()
+	require.NoError(t, err)
+	_, _, _, _, err = aliceChannel.ReceiveRevocation(bobRevocation)
+	require.NoError(t, err)
+
+	// The HTLC is only committed to in Bob's commitment so far.
+	assertPending(true, false)
+
+	// <----sig-----
+	bobSig, bobHtlcSigs, _, err := bobChannel.SignNextCommitment()
+	require.NoError(t, err)
+	err = aliceChannel.ReceiveNewCommitment(bobSig, bobHtlcSigs)
+	require.NoError(t, err)
+	assertPending(true, false)
+
+	// -----rev---->
+	aliceRevocation, _, err := aliceChannel.RevokeCurrentCommitment()
+	require.NoError(t, err)
+	_, _, _, _, err = bobChannel.ReceiveRevocation(aliceRevocation)
+	require.NoError(t, err)
+	assertPending(false, false)
+}
+
+// TestUpgradeCommitType tests that the commitment type of a legacy channel can
+// be upgraded to anchors in place, and that revoked states of both the old
+// and the new commitment type can still be punished.
+func TestUpgradeCommitType(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := CreateTestChannels(
+		channeldb.SingleFunderBit,
+	)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	// sendHtlc sends an HTLC from Alice to Bob and settles it again,
+	// returning the height of Bob's commitment that carried it, which is
+	// revoked afterwards.
+	sendHtlc := func(id uint64) uint64 {
+		t.Helper()
+
+		amt := lnwire.NewMSatFromSatoshis(10000)
+		htlc, preimage := createHTLC(int(id), amt)
+		_, err := aliceChannel.AddHTLC(htlc, nil)
+		require.NoError(t, err)
+		_, err = bobChannel.ReceiveHTLC(htlc)
+		require.NoError(t, err)
+		err = ForceStateTransition(aliceChannel, bobChannel)
+		require.NoError(t, err)
+
+		height := bobChannel.channelState.LocalCommitment.CommitHeight
+
+		err = bobChannel.SettleHTLC(preimage, id, nil, nil, nil)
+		require.NoError(t, err)
+		err = aliceChannel.ReceiveHTLCSettle(preimage, id)
+		require.NoError(t, err)
+		err = ForceStateTransition(bobChannel, aliceChannel)
+		require.NoError(t, err)
+
+		return height
+	}
+
+	// assertBreach asserts that Alice is able to sweep her output from
+	// Bob's revoked commitment at the given height.
+	assertBreach := func(height uint64, anchors bool) {
+		t.Helper()
+
+		retribution, err := NewBreachRetribution(
+			aliceChannel.channelState, height, 0,
+		)
+		require.NoError(t, err)
+
+		breachTx := retribution.BreachTransaction
+		var numAnchors int
+		for _, txOut := range breachTx.TxOut {
+			if txOut.Value == int64(anchorSize) {
+				numAnchors++
+			}
+		}
+		require.Equal(t, anchors, numAnchors == 2)
+
+		signDesc := retribution.LocalOutputSignDesc
+		require.NotNil(t, signDesc)
+
+		output := breachTx.TxOut[retribution.LocalOutpoint.Index]
+		require.Equal(t, signDesc.Output.PkScript, output.PkScript)
+		require.Equal(t, signDesc.Output.Value, output.Value)
+	}
+
+	legacyHeight := sendHtlc(0)
+
+	// The channel can only be upgraded to valid commitment types.
+	err = aliceChannel.UpgradeCommitType(channeldb.AnchorOutputsBit)
+	require.ErrorIs(t, err, ErrInvalidCommitTypeUpgrade)
+
+	// Both parties upgrade the channel to anchors, after which both owe
+	// each other a commitment of the new type.
+	var commitType CommitmentType = CommitmentTypeAnchorsZeroFeeHtlcTx
+	commitType, err = CommitmentTypeFromChannelType(
+		commitType.ChannelType(),
+	)
+	require.NoError(t, err)
+	require.EqualValues(t, CommitmentTypeAnchorsZeroFeeHtlcTx, commitType)
+
+	anchorType, err := UpgradedChanType(
+		aliceChannel.channelState.ChanType, commitType,
+	)
+	require.NoError(t, err)
+	require.Equal(t, channeldb.SingleFunderTweaklessBit|
+		channeldb.AnchorOutputsBit|channeldb.ZeroHtlcTxFeeBit,
+		anchorType)
+	require.NoError(t, aliceChannel.UpgradeCommitType(anchorType))
+	require.NoError(t, bobChannel.UpgradeCommitType(anchorType))
+	require.True(t, aliceChannel.CommitTypeUpgradePending())
+	require.True(t, aliceChannel.OweCommitment(true))
+	require.True(t, bobChannel.OweCommitment(true))
+
+	prevCommit := aliceChannel.channelState.LocalCommitment
+	require.NoError(t, ForceStateTransition(aliceChannel, bobChannel))
+	require.False(t, aliceChannel.CommitTypeUpgradePending())
+	require.False(t, bobChannel.CommitTypeUpgradePending())
+	require.False(t, aliceChannel.OweCommitment(true))
+	require.False(t, bobChannel.OweCommitment(true))
+
+	// Alice, as the initiator, pays for the anchor outputs of the new
+	// commitments.
+	commit := aliceChannel.channelState.LocalCommitment
+	require.Len(t, commit.CommitTx.TxOut, 4)
+	require.Equal(t, prevCommit.RemoteBalance, commit.RemoteBalance)
+	require.Equal(t,
+		prevCommit.LocalBalance+lnwire.NewMSatFromSatoshis(
+			prevCommit.CommitFee-commit.CommitFee-2*anchorSize,
+		),
+		commit.LocalBalance,
+	)
+
+	// Revoked states of both the legacy and the anchor commitment type
+	// must be punishable.
+	anchorHeight := sendHtlc(1)
+	assertBreach(legacyHeight, false)
+	assertBreach(anchorHeight, true)
+}
+
+// TestRevertCommitTypeUpgrade tests that an upgrade of the commitment type
+// that the remote party didn't apply is undone when the channel is
+// reestablished.
+func TestRevertCommitTypeUpgrade(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := CreateTestChannels(
+		channeldb.SingleFunderBit,
+	)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	tweakless := channeldb.SingleFunderTweaklessBit
+	require.NoError(t, aliceChannel.UpgradeCommitType(tweakless))
+
+	// Bob never applied the upgrade, so Alice reverts hers once she
+	// learns about it.
+	bobSyncMsg, err := bobChannel.channelState.ChanSyncMsg()
+	require.NoError(t, err)
+	_, _, _, err = aliceChannel.ProcessChanSyncMsg(bobSyncMsg)
+	require.NoError(t, err)
+	require.Equal(t, channeldb.SingleFunderBit,
+		aliceChannel.channelState.ChanType)
+	require.False(t, aliceChannel.CommitTypeUpgradePending())
+	require.False(t, aliceChannel.OweCommitment(true))
+
+	// Bob doesn't need to act on Alice's upgrade either way.
+	require.NoError(t, aliceChannel.UpgradeCommitType(tweakless))
+	aliceSyncMsg, err := aliceChannel.channelState.ChanSyncMsg()
+	require.NoError(t, err)
+	_, _, _, err = bobChannel.ProcessChanSyncMsg(aliceSyncMsg)
+	require.NoError(t, err)
+	require.Equal(t, channeldb.SingleFunderBit,
+		bobChannel.channelState.ChanType)
+}
@@ -0,0 +1,830 @@
+package chansplicer
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/txsort"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/labels"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrSpliceInProgress is returned when a splice is attempted while
+	// another splice of the channel hasn't finished yet.
+	ErrSpliceInProgress = fmt.Errorf("channel splice already in progress")
+
+	// ErrInvalidState is returned when the splice state machine receives a
+	// message while it is in a state that doesn't expect it.
+	ErrInvalidState = fmt.Errorf("invalid state")
+
+	// ErrPublicChannel is returned when a splice is attempted on an
+	// announced channel. Splicing announced channels requires the new
+	// funding output to be announced, which isn't supported yet.
+	ErrPublicChannel = fmt.Errorf("only private channels can be spliced")
+)
+
+// spliceState represents all the possible states the channel splicer state
+// machine can be in. Each message will either advance to the next state, or
+// result in an error which aborts the splice.
+type spliceState uint8
+
+const (
+	// spliceIdle is the initial starting state. The channel has been
+	// quiesced by its link before the state machine is used. If it
+	// receives a message while in this state, then it is the acceptor of
+	// a splice initiated by the remote party.
+	spliceIdle spliceState = iota
+
+	// spliceInitSent is the state of the initiator once it has proposed
+	// the splice, and waits for it to be accepted.
+	spliceInitSent
+
+	// spliceAwaitingCreated is the state of the acceptor once it has
+	// accepted the splice, and waits for the splice transaction.
+	spliceAwaitingCreated
+
+	// spliceCreatedSent is the state of the initiator once it has sent
+	// the splice transaction along with its commitment signature, and
+	// waits for the signatures of the remote party.
+	spliceCreatedSent
+
+	// spliceAwaitingLock is the state both parties enter once the splice
+	// transaction has been signed. In this state, the parties wait for the
+	// splice transaction to confirm and exchange splice_locked messages.
+	spliceAwaitingLock
+
+	// spliceFinished is the final state of the state machine. The channel
+	// is now funded by the output created by the splice transaction.
+	spliceFinished
+)
+
+// SpliceRequest is a request to splice funds into or out of a channel.
+type SpliceRequest struct {
+	// ChanPoint is the channel point of the channel to splice.
+	ChanPoint *wire.OutPoint
+
+	// Amount is the amount to splice into the channel if positive, or the
+	// amount to splice out of the channel if negative. Funds spliced in
+	// are taken from the wallet, while the fees of a splice out are paid
+	// from the channel balance.
+	Amount btcutil.Amount
+
+	// DeliveryScript is the script that funds spliced out of the channel
+	// are sent to. It's only used if the Amount is negative.
+	DeliveryScript lnwire.DeliveryAddress
+
+	// FeePerKw is the fee rate of the splice transaction.
+	FeePerKw chainfee.SatPerKWeight
+
+	// Updates receives the splice transaction once it has been broadcast.
+	Updates chan *wire.MsgTx
+
+	// Err is used to receive an error if the splice fails.
+	Err chan error
+}
+
+// Config holds all the items that a ChanSplicer requires to carry out its
+// duties.
+type Config struct {
+	// Channel is the channel that should be spliced.
+	Channel *lnwallet.LightningChannel
+
+	// Signer is used to sign the wallet inputs of the splice transaction.
+	Signer input.Signer
+
+	// CoinSource is used to select the wallet inputs that fund a splice
+	// in.
+	CoinSource chanfunding.CoinSource
+
+	// CoinSelectLocker ensures that coin selection is synchronized with
+	// other users of the wallet.
+	CoinSelectLocker chanfunding.CoinSelectionLocker
+
+	// CoinLocker is used to lock the wallet inputs of the splice
+	// transaction.
+	CoinLocker chanfunding.OutpointLocker
+
+	// ChangeAddr returns a fresh address to send the change of a splice in
+	// to.
+	ChangeAddr func() (btcutil.Address, error)
+
+	// MarkChannelSpliced updates the channel graph to track the funding
+	// output created by the splice instead of the current one.
+	MarkChannelSpliced func(chanPoint, fundingPoint *wire.OutPoint,
+		capacity btcutil.Amount) error
+
+	// BroadcastTx broadcasts the passed transaction to the network.
+	BroadcastTx func(*wire.MsgTx, string) error
+}
+
+// ChanSplicer is a state machine that handles the splicing of funds into or
+// out of a channel that has been quiesced by its link. This includes
+// negotiating and signing the splice transaction along with the commitments
+// that spend its funding output, and finally switching the channel over to
+// the new funding output once the splice transaction has confirmed. Once the
+// splice transaction has been signed, the link can resume forwarding, as
+// every new commitment is signed for both funding outputs until then.
+//
+// Only the initiator of a splice contributes to it, and the funding keys of
+// the channel are reused for the new funding output.
+type ChanSplicer struct {
+	// state is the current state of the state machine.
+	state spliceState
+
+	// cfg holds the configuration for this ChanSplicer instance.
+	cfg Config
+
+	// chanPoint is the full channel point of the target channel.
+	chanPoint wire.OutPoint
+
+	// cid is the full channel ID of the target channel.
+	cid lnwire.ChannelID
+
+	// height is the height at which the splice was started.
+	height uint32
+
+	// req is the splice request. This will only be populated if we're the
+	// initiator of the splice.
+	req *SpliceRequest
+
+	// spliceTx is the splice transaction.
+	spliceTx *wire.MsgTx
+
+	// walletInputs are the wallet inputs the initiator added to the splice
+	// transaction.
+	walletInputs []chanfunding.Coin
+
+	// lockTime is the lock time of the splice transaction proposed by the
+	// initiator.
+	lockTime uint32
+
+	// localDelta and remoteDelta are the amounts added to or removed from
+	// the balances of the local and remote party respectively.
+	localDelta  btcutil.Amount
+	remoteDelta btcutil.Amount
+
+	// splice is the pending splice that has been created from the splice
+	// transaction.
+	splice *channeldb.PendingSplice
+
+	// localLocked is true once the splice transaction has confirmed and we
+	// have sent our splice_locked message.
+	localLocked bool
+
+	// remoteLocked is true once we've received the splice_locked message
+	// of the remote party.
+	remoteLocked bool
+}
+
+// NewChanSplicer creates a new instance of the channel splicer given the
+// passed configuration. The request should only be populated iff we're the
+// initiator of the splice.
+func NewChanSplicer(cfg Config, req *SpliceRequest,
+	height uint32) *ChanSplicer {
+
+	chanPoint := *cfg.Channel.ChannelPoint()
+	return &ChanSplicer{
+		state:     spliceIdle,
+		cfg:       cfg,
+		chanPoint: chanPoint,
+		cid:       lnwire.NewChanIDFromOutPoint(&chanPoint),
+		height:    height,
+		req:       req,
+	}
+}
+
+// ResumeChanSplicer creates a new instance of the channel splicer for a
+// splice that has already been signed, but whose splice transaction hasn't
+// been locked in yet.
+func ResumeChanSplicer(cfg Config,
+	splice *channeldb.PendingSplice) *ChanSplicer {
+
+	chanPoint := *cfg.Channel.ChannelPoint()
+	return &ChanSplicer{
+		state:     spliceAwaitingLock,
+		cfg:       cfg,
+		chanPoint: chanPoint,
+		cid:       lnwire.NewChanIDFromOutPoint(&chanPoint),
+		height:    splice.BroadcastHeight,
+		spliceTx:  splice.SpliceTx,
+		splice:    splice,
+	}
+}
+
+// InitSplice is the first method to be called by the initiator of the
+// splice, once the channel is quiescent and we're the initiator of the
+// quiescence. It creates the splice transaction and returns the splice_init
+// message to send to the remote party.
+func (s *ChanSplicer) InitSplice() ([]lnwire.Message, error) {
+	if s.state != spliceIdle {
+		return nil, ErrSpliceInProgress
+	}
+
+	if err := s.checkChannel(); err != nil {
+		return nil, err
+	}
+
+	chansplicerLog.Infof("ChannelPoint(%v): initiating splice of %v",
+		s.chanPoint, s.req.Amount)
+
+	if err := s.createSpliceTx(); err != nil {
+		return nil, err
+	}
+
+	s.state = spliceInitSent
+
+	localKey := s.cfg.Channel.State().LocalChanCfg.MultiSigKey
+	return []lnwire.Message{&lnwire.SpliceInit{
+		ChanID:           s.cid,
+		RelativeSatoshis: int64(s.localDelta),
+		FundingFeePerKw:  uint32(s.req.FeePerKw),
+		LockTime:         s.lockTime,
+		FundingPubKey:    localKey.PubKey,
+	}}, nil
+}
+
+// checkChannel ensures that the channel can be spliced. Only private channels
+// are supported, as the graph of other nodes would still track the funding
+// output of an announced channel once it has been spent by the splice.
+func (s *ChanSplicer) checkChannel() error {
+	if s.cfg.Channel.State().ChannelFlags&lnwire.FFAnnounceChannel != 0 {
+		return ErrPublicChannel
+	}
+
+	return nil
+}
+
+// ProcessSpliceMsg attempts to process the next message in the splice
+// series. This method will update the state accordingly and return two
+// primary values: the next set of messages to be sent, and a bool indicating
+// if the splice has completed. If the second value is true, then the channel
+// is funded by the output created by the splice transaction, and the
+// ChanSplicer can be garbage collected.
+func (s *ChanSplicer) ProcessSpliceMsg(msg lnwire.Message) ([]lnwire.Message,
+	bool, error) {
+
+	switch s.state {
+
+	// The remote party proposed a splice of the quiescent channel. As we
+	// don't contribute to splices, we'll only ensure that the funding keys
+	// are reused.
+	case spliceIdle:
+		spliceInit, ok := msg.(*lnwire.SpliceInit)
+		if !ok {
+			return nil, false, fmt.Errorf("expected "+
+				"lnwire.SpliceInit, instead have %v",
+				spew.Sdump(msg))
+		}
+
+		if err := s.checkChannel(); err != nil {
+			return nil, false, err
+		}
+
+		chansplicerLog.Infof("ChannelPoint(%v): responding to splice",
+			s.chanPoint)
+
+		remoteKey := s.cfg.Channel.RemoteFundingKey
+		if !spliceInit.FundingPubKey.IsEqual(remoteKey) {
+			return nil, false, fmt.Errorf("funding key rotation " +
+				"not supported")
+		}
+
+		s.remoteDelta = btcutil.Amount(spliceInit.RelativeSatoshis)
+		s.lockTime = spliceInit.LockTime
+		s.state = spliceAwaitingCreated
+
+		localKey := s.cfg.Channel.State().LocalChanCfg.MultiSigKey
+		return []lnwire.Message{&lnwire.SpliceAck{
+			ChanID:        s.cid,
+			FundingPubKey: localKey.PubKey,
+		}}, false, nil
+
+	// The remote party accepted our splice, so we'll send over the splice
+	// transaction along with our signature for their new commitment.
+	case spliceInitSent:
+		spliceAck, ok := msg.(*lnwire.SpliceAck)
+		if !ok {
+			return nil, false, fmt.Errorf("expected "+
+				"lnwire.SpliceAck, instead have %v",
+				spew.Sdump(msg))
+		}
+
+		remoteKey := s.cfg.Channel.RemoteFundingKey
+		if !spliceAck.FundingPubKey.IsEqual(remoteKey) {
+			return nil, false, fmt.Errorf("funding key rotation " +
+				"not supported")
+		}
+		if spliceAck.RelativeSatoshis != 0 {
+			return nil, false, fmt.Errorf("remote contributions " +
+				"to splices not supported")
+		}
+
+		splice, err := s.cfg.Channel.NewPendingSplice(
+			s.spliceTx, s.localDelta, 0,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+		commitSig, htlcSigs, err := s.cfg.Channel.SignSpliceCommitment(
+			splice,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+
+		s.splice = splice
+		s.state = spliceCreatedSent
+
+		return []lnwire.Message{&lnwire.SpliceCreated{
+			ChanID:    s.cid,
+			SpliceTx:  s.spliceTx,
+			CommitSig: commitSig,
+			HtlcSigs:  htlcSigs,
+		}}, false, nil
+
+	// We've received the splice transaction. If it's valid, we'll
+	// persist the splice before handing out our signatures.
+	case spliceAwaitingCreated:
+		spliceCreated, ok := msg.(*lnwire.SpliceCreated)
+		if !ok {
+			return nil, false, fmt.Errorf("expected "+
+				"lnwire.SpliceCreated, instead have %v",
+				spew.Sdump(msg))
+		}
+
+		spliceTx := spliceCreated.SpliceTx
+		if spliceTx.LockTime != s.lockTime {
+			return nil, false, fmt.Errorf("splice transaction "+
+				"has lock time %v, expected %v",
+				spliceTx.LockTime, s.lockTime)
+		}
+
+		splice, err := s.cfg.Channel.NewPendingSplice(
+			spliceTx, 0, s.remoteDelta,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+		err = s.cfg.Channel.ReceiveSpliceCommitSig(
+			splice, spliceCreated.CommitSig, spliceCreated.HtlcSigs,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+
+		commitSig, htlcSigs, err := s.cfg.Channel.SignSpliceCommitment(
+			splice,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+		rawSig, err := s.cfg.Channel.SignSpliceInput(spliceTx)
+		if err != nil {
+			return nil, false, err
+		}
+		fundingSig, err := lnwire.NewSigFromSignature(rawSig)
+		if err != nil {
+			return nil, false, err
+		}
+
+		s.spliceTx = spliceTx
+		s.splice = splice
+		if err := s.markSplicePending(); err != nil {
+			return nil, false, err
+		}
+
+		return []lnwire.Message{&lnwire.SpliceSigned{
+			ChanID:     s.cid,
+			CommitSig:  commitSig,
+			HtlcSigs:   htlcSigs,
+			FundingSig: fundingSig,
+		}}, false, nil
+
+	// The remote party signed the splice, so we'll complete the splice
+	// transaction and broadcast it.
+	case spliceCreatedSent:
+		spliceSigned, ok := msg.(*lnwire.SpliceSigned)
+		if !ok {
+			return nil, false, fmt.Errorf("expected "+
+				"lnwire.SpliceSigned, instead have %v",
+				spew.Sdump(msg))
+		}
+
+		err := s.cfg.Channel.ReceiveSpliceCommitSig(
+			s.splice, spliceSigned.CommitSig, spliceSigned.HtlcSigs,
+		)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if err := s.signSpliceTx(spliceSigned.FundingSig); err != nil {
+			return nil, false, err
+		}
+		if err := s.markSplicePending(); err != nil {
+			return nil, false, err
+		}
+
+		chansplicerLog.Infof("ChannelPoint(%v): broadcasting splice "+
+			"tx %v", s.chanPoint, newLogClosure(func() string {
+			return spew.Sdump(s.spliceTx)
+		}))
+
+		err = s.cfg.BroadcastTx(s.spliceTx, s.label())
+		if err != nil {
+			chansplicerLog.Errorf("ChannelPoint(%v): unable to "+
+				"broadcast splice tx: %v", s.chanPoint, err)
+		}
+
+		if s.req != nil {
+			s.req.Updates <- s.spliceTx
+		}
+
+		return nil, false, nil
+
+	// The remote party considers the splice transaction confirmed.
+	case spliceAwaitingLock:
+		spliceLocked, ok := msg.(*lnwire.SpliceLocked)
+		if !ok {
+			return nil, false, fmt.Errorf("expected "+
+				"lnwire.SpliceLocked, instead have %v",
+				spew.Sdump(msg))
+		}
+
+		spliceTxid := s.spliceTx.TxHash()
+		if spliceLocked.SpliceTxid != spliceTxid {
+			return nil, false, fmt.Errorf("splice_locked for "+
+				"unknown splice tx %v", spliceLocked.SpliceTxid)
+		}
+
+		s.remoteLocked = true
+		if !s.localLocked {
+			return nil, false, nil
+		}
+
+		if err := s.completeSplice(); err != nil {
+			return nil, false, err
+		}
+
+		return nil, true, nil
+
+	default:
+		return nil, false, ErrInvalidState
+	}
+}
+
+// SpliceConfirmed is to be called once the splice transaction has reached
+// the required number of confirmations. It returns the splice_locked message
+// to send to the remote party, and a bool indicating if the splice has
+// completed.
+func (s *ChanSplicer) SpliceConfirmed() (*lnwire.SpliceLocked, bool, error) {
+	if s.state != spliceAwaitingLock {
+		return nil, false, ErrInvalidState
+	}
+
+	// The remote party may complete the splice as soon as it receives our
+	// splice_locked, so we'll need to be ready to accept commitments that
+	// only spend the funding output of the splice from then on.
+	err := s.cfg.Channel.MarkSpliceLocked()
+
+	// If the funding output created by the splice has already been spent,
+	// the chain watcher will have completed the splice.
+	if err != nil && err != channeldb.ErrNoPendingSplice {
+		return nil, false, err
+	}
+
+	s.localLocked = true
+	spliceLocked := lnwire.NewSpliceLocked(s.cid, s.spliceTx.TxHash())
+
+	if !s.remoteLocked {
+		return spliceLocked, false, nil
+	}
+
+	if err := s.completeSplice(); err != nil {
+		return nil, false, err
+	}
+
+	return spliceLocked, true, nil
+}
+
+// AwaitingQuiescence returns true if we're the initiator of the splice, and
+// InitSplice hasn't been called yet as the channel isn't quiescent.
+func (s *ChanSplicer) AwaitingQuiescence() bool {
+	return s.state == spliceIdle && s.req != nil
+}
+
+// AwaitingConfirmation returns true if the splice transaction has been signed
+// by both parties, and the state machine waits for it to confirm.
+func (s *ChanSplicer) AwaitingConfirmation() bool {
+	return s.state == spliceAwaitingLock && !s.localLocked
+}
+
+// PendingSplice returns the pending splice once the splice transaction has
+// been signed, and nil otherwise.
+func (s *ChanSplicer) PendingSplice() *channeldb.PendingSplice {
+	if s.state != spliceAwaitingLock {
+		return nil
+	}
+
+	return s.splice
+}
+
+// SpliceRequest returns the original splice request that prompted the
+// creation of the state machine.
+//
+// NOTE: This will only return a non-nil pointer if we were the initiator of
+// the splice.
+func (s *ChanSplicer) SpliceRequest() *SpliceRequest {
+	return s.req
+}
+
+// Channel returns the channel stored in the config.
+func (s *ChanSplicer) Channel() *lnwallet.LightningChannel {
+	return s.cfg.Channel
+}
+
+// ReleaseInputs unlocks the wallet inputs of the splice transaction. This
+// should be called if the splice is aborted before it has been signed.
+func (s *ChanSplicer) ReleaseInputs() {
+	if s.state == spliceAwaitingLock || s.state == spliceFinished {
+		return
+	}
+
+	for _, coin := range s.walletInputs {
+		s.cfg.CoinLocker.UnlockOutpoint(coin.OutPoint)
+	}
+	s.walletInputs = nil
+}
+
+// createSpliceTx creates the unsigned splice transaction for the splice
+// request. A splice in is funded by inputs from our wallet, while a splice
+// out pays its fees from our channel balance.
+func (s *ChanSplicer) createSpliceTx() error {
+	chanState := s.cfg.Channel.State()
+	fundingPoint := chanState.ActiveFundingOutpoint()
+	capacity := chanState.Capacity
+	feeRate := s.req.FeePerKw
+
+	spliceTx := wire.NewMsgTx(2)
+	spliceTx.LockTime = s.height
+	spliceTx.AddTxIn(wire.NewTxIn(&fundingPoint, nil, nil))
+
+	switch {
+	case s.req.Amount > 0:
+		// The coin selection accounts for all inputs and outputs of
+		// the splice transaction, except for the input spending the
+		// current funding output, so we'll add its fee to the amount
+		// to select.
+		fundingInputFee := feeRate.FeeForWeight(
+			input.InputSize*blockchain.WitnessScaleFactor +
+				input.MultiSigWitnessSize,
+		)
+
+		var changeAmt btcutil.Amount
+		err := s.cfg.CoinSelectLocker.WithCoinSelectLock(func() error {
+			allCoins, err := s.cfg.CoinSource.ListCoins(
+				1, math.MaxInt32,
+			)
+			if err != nil {
+				return err
+			}
+
+			// Only native segwit inputs can be used, as the txid
+			// of the splice transaction must not change once it
+			// has been signed.
+			var coins []chanfunding.Coin
+			for _, coin := range allCoins {
+				if txscript.IsWitnessProgram(coin.PkScript) {
+					coins = append(coins, coin)
+				}
+			}
+
+			selected, change, err := chanfunding.CoinSelect(
+				feeRate, s.req.Amount+fundingInputFee,
+				chanState.LocalChanCfg.DustLimit, coins,
+			)
+			if err != nil {
+				return err
+			}
+
+			for _, coin := range selected {
+				s.cfg.CoinLocker.LockOutpoint(coin.OutPoint)
+			}
+			s.walletInputs = selected
+			changeAmt = change
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, coin := range s.walletInputs {
+			spliceTx.AddTxIn(wire.NewTxIn(&coin.OutPoint, nil, nil))
+		}
+		if changeAmt != 0 {
+			changeAddr, err := s.cfg.ChangeAddr()
+			if err != nil {
+				return err
+			}
+			changeScript, err := txscript.PayToAddrScript(
+				changeAddr,
+			)
+			if err != nil {
+				return err
+			}
+			spliceTx.AddTxOut(&wire.TxOut{
+				PkScript: changeScript,
+				Value:    int64(changeAmt),
+			})
+		}
+
+		s.localDelta = s.req.Amount
+
+	case s.req.Amount < 0:
+		if len(s.req.DeliveryScript) == 0 {
+			return fmt.Errorf("no delivery script for splice out")
+		}
+
+		deliveryOut := &wire.TxOut{
+			PkScript: s.req.DeliveryScript,
+			Value:    int64(-s.req.Amount),
+		}
+		spliceTx.AddTxOut(deliveryOut)
+
+		var weightEstimate input.TxWeightEstimator
+		weightEstimate.AddWitnessInput(input.MultiSigWitnessSize)
+		weightEstimate.AddP2WSHOutput()
+		weightEstimate.AddTxOutput(deliveryOut)
+		fee := feeRate.FeeForWeight(int64(weightEstimate.Weight()))
+
+		s.localDelta = s.req.Amount - fee
+
+	default:
+		return fmt.Errorf("splice amount must not be zero")
+	}
+
+	spliceTx.AddTxOut(s.cfg.Channel.FundingTxOut(capacity + s.localDelta))
+	txsort.InPlaceSort(spliceTx)
+
+	s.spliceTx = spliceTx
+	s.lockTime = spliceTx.LockTime
+
+	return nil
+}
+
+// signSpliceTx completes the witnesses of all inputs of the splice
+// transaction, using the passed signature of the remote party for the input
+// spending the current funding output.
+func (s *ChanSplicer) signSpliceTx(remoteFundingSig lnwire.Sig) error {
+	remoteSig, err := remoteFundingSig.ToSignature()
+	if err != nil {
+		return err
+	}
+	localSig, err := s.cfg.Channel.SignSpliceInput(s.spliceTx)
+	if err != nil {
+		return err
+	}
+	err = s.cfg.Channel.AddSpliceInputWitness(
+		s.spliceTx, localSig, remoteSig,
+	)
+	if err != nil {
+		return err
+	}
+
+	// Taproot inputs commit to the outputs spent by all inputs of the
+	// splice transaction, which are the current funding output and our
+	// wallet inputs.
+	chanState := s.cfg.Channel.State()
+	prevOutFetcher := txscript.NewMultiPrevOutFetcher(nil)
+	prevOutFetcher.AddPrevOut(
+		chanState.ActiveFundingOutpoint(),
+		s.cfg.Channel.FundingTxOut(chanState.Capacity),
+	)
+	for _, coin := range s.walletInputs {
+		coin := coin
+		prevOutFetcher.AddPrevOut(coin.OutPoint, &coin.TxOut)
+	}
+
+	sigHashes := txscript.NewTxSigHashes(s.spliceTx, prevOutFetcher)
+	for _, coin := range s.walletInputs {
+		coin := coin
+
+		inputIndex := -1
+		for i, txIn := range s.spliceTx.TxIn {
+			if txIn.PreviousOutPoint == coin.OutPoint {
+				inputIndex = i
+				break
+			}
+		}
+		if inputIndex == -1 {
+			return fmt.Errorf("wallet input %v not found",
+				coin.OutPoint)
+		}
+
+		hashType := txscript.SigHashAll
+		if txscript.IsPayToTaproot(coin.PkScript) {
+			hashType = txscript.SigHashDefault
+		}
+
+		signDesc := &input.SignDescriptor{
+			Output:            &coin.TxOut,
+			HashType:          hashType,
+			SigHashes:         sigHashes,
+			PrevOutputFetcher: prevOutFetcher,
+			InputIndex:        inputIndex,
+		}
+		inputScript, err := s.cfg.Signer.ComputeInputScript(
+			s.spliceTx, signDesc,
+		)
+		if err != nil {
+			return err
+		}
+
+		txIn := s.spliceTx.TxIn[inputIndex]
+		txIn.SignatureScript = inputScript.SigScript
+		txIn.Witness = inputScript.Witness
+	}
+
+	s.splice.SpliceTx = s.spliceTx
+
+	return nil
+}
+
+// markSplicePending updates the channel graph to track the new funding
+// output, and persists the signed splice. From then on, the splice
+// transaction can confirm at any time.
+func (s *ChanSplicer) markSplicePending() error {
+	chanState := s.cfg.Channel.State()
+	prevFundingPoint := chanState.ActiveFundingOutpoint()
+	prevCapacity := chanState.Capacity
+
+	// Once the splice transaction confirms, the current funding output
+	// is spent, so we'll need to make sure the channel isn't pruned from
+	// the graph. We do this before the splice is persisted, so that a
+	// failure still allows the splice to be aborted.
+	err := s.cfg.MarkChannelSpliced(
+		&s.chanPoint, &s.splice.FundingOutpoint, s.splice.Capacity,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to mark channel spliced in "+
+			"graph: %w", err)
+	}
+
+	s.splice.BroadcastHeight = s.height
+
+	err = s.cfg.Channel.MarkSplicePending(s.splice)
+	if err != nil {
+		// The splice is aborted, so the graph needs to track the
+		// current funding output again.
+		revertErr := s.cfg.MarkChannelSpliced(
+			&s.chanPoint, &prevFundingPoint, prevCapacity,
+		)
+		if revertErr != nil {
+			chansplicerLog.Errorf("ChannelPoint(%v): unable to "+
+				"revert spliced channel in graph: %v",
+				s.chanPoint, revertErr)
+		}
+
+		return err
+	}
+
+	s.state = spliceAwaitingLock
+
+	return nil
+}
+
+// completeSplice switches the channel over to the funding output created by
+// the splice transaction.
+func (s *ChanSplicer) completeSplice() error {
+	err := s.cfg.Channel.CompleteSplice()
+
+	// If the funding output created by the splice has already been spent,
+	// the chain watcher will have completed the splice.
+	if err != nil && err != channeldb.ErrNoPendingSplice {
+		return err
+	}
+
+	chansplicerLog.Infof("ChannelPoint(%v): splice completed, now "+
+		"funded by %v", s.chanPoint, s.splice.FundingOutpoint)
+
+	s.state = spliceFinished
+
+	return nil
+}
+
+// label returns the label of the splice transaction.
+func (s *ChanSplicer) label() string {
+	shortChanID := s.cfg.Channel.ShortChanID()
+	return labels.MakeLabel(labels.LabelTypeChannelSplice, &shortChanID)
+}
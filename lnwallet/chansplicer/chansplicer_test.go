@@ -0,0 +1,335 @@
+package chansplicer
+
+import (
+	"crypto/sha256"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestConfig creates a splicer config for the passed channel that records
+// any broadcast transactions.
+func newTestConfig(channel *lnwallet.LightningChannel,
+	broadcastTxs *[]*wire.MsgTx) Config {
+
+	return Config{
+		Channel: channel,
+		Signer:  channel.Signer,
+		MarkChannelSpliced: func(_, _ *wire.OutPoint,
+			_ btcutil.Amount) error {
+
+			return nil
+		},
+		BroadcastTx: func(tx *wire.MsgTx, _ string) error {
+			*broadcastTxs = append(*broadcastTxs, tx)
+			return nil
+		},
+	}
+}
+
+// sendHtlc adds an HTLC paying the passed amount from the sender to the
+// receiver, and locks it in on both commitments.
+func sendHtlc(t *testing.T, sender, receiver *lnwallet.LightningChannel,
+	preimage [32]byte, amt btcutil.Amount) uint64 {
+
+	t.Helper()
+
+	htlc := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256(preimage[:]),
+		Amount:      lnwire.NewMSatFromSatoshis(amt),
+		Expiry:      500,
+	}
+	htlcIndex, err := sender.AddHTLC(htlc, nil)
+	require.NoError(t, err)
+	htlc.ID = htlcIndex
+	_, err = receiver.ReceiveHTLC(htlc)
+	require.NoError(t, err)
+	require.NoError(t, lnwallet.ForceStateTransition(sender, receiver))
+
+	return htlcIndex
+}
+
+// settleHtlc settles the HTLC with the passed index that has been sent to
+// the settler, and locks the settle in on both commitments.
+func settleHtlc(t *testing.T, settler, sender *lnwallet.LightningChannel,
+	preimage [32]byte, htlcIndex uint64) {
+
+	t.Helper()
+
+	err := settler.SettleHTLC(preimage, htlcIndex, nil, nil, nil)
+	require.NoError(t, err)
+	err = sender.ReceiveHTLCSettle(preimage, htlcIndex)
+	require.NoError(t, err)
+	require.NoError(t, lnwallet.ForceStateTransition(settler, sender))
+}
+
+// exchangeSpliceMsgs passes the messages sent by the sender back and forth
+// between the two splicers until neither has a reply left, returning the first
+// error encountered.
+func exchangeSpliceMsgs(t *testing.T, msgs []lnwire.Message, sender,
+	receiver *ChanSplicer) error {
+
+	t.Helper()
+
+	for len(msgs) != 0 {
+		var nextMsgs []lnwire.Message
+		for _, msg := range msgs {
+			replies, done, err := receiver.ProcessSpliceMsg(msg)
+			if err != nil {
+				return err
+			}
+			require.False(t, done)
+
+			nextMsgs = append(nextMsgs, replies...)
+		}
+
+		msgs = nextMsgs
+		sender, receiver = receiver, sender
+	}
+
+	return nil
+}
+
+// newTestSpliceRequest creates a request to splice out of the channel with the
+// passed channel point.
+func newTestSpliceRequest(chanPoint *wire.OutPoint) *SpliceRequest {
+	return &SpliceRequest{
+		ChanPoint:      chanPoint,
+		Amount:         -btcutil.SatoshiPerBitcoin,
+		DeliveryScript: append([]byte{0x00, 0x14}, make([]byte, 20)...),
+		FeePerKw:       2500,
+		Updates:        make(chan *wire.MsgTx, 1),
+		Err:            make(chan error, 1),
+	}
+}
+
+// TestChanSplicerSpliceOut tests that a splice out moves both parties over to
+// the funding output created by the splice transaction, and that HTLCs can be
+// forwarded through the channel before, during and after the splice.
+func TestChanSplicerSpliceOut(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := lnwallet.CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+	defer cleanUp()
+
+	chanPoint := *aliceChannel.ChannelPoint()
+	aliceState := aliceChannel.State()
+	bobState := bobChannel.State()
+	oldCapacity := aliceState.Capacity
+	oldAliceBalance := aliceState.LocalCommitment.LocalBalance
+	oldBobBalance := bobState.LocalCommitment.LocalBalance
+
+	// Alice sends an HTLC before the splice, which remains active on the
+	// commitments that spend the funding output of the splice.
+	const htlcAmt = 100000
+	preimage1 := [32]byte{1}
+	htlcIndex1 := sendHtlc(
+		t, aliceChannel, bobChannel, preimage1, htlcAmt,
+	)
+
+	var broadcastTxs []*wire.MsgTx
+	req := newTestSpliceRequest(&chanPoint)
+	aliceSplicer := NewChanSplicer(
+		newTestConfig(aliceChannel, &broadcastTxs), req, 100,
+	)
+	bobSplicer := NewChanSplicer(
+		newTestConfig(bobChannel, &broadcastTxs), nil, 100,
+	)
+
+	// Alice initiates the splice, after which the messages are passed
+	// back and forth until the splice transaction has been broadcast.
+	msgs, err := aliceSplicer.InitSplice()
+	require.NoError(t, err)
+
+	err = exchangeSpliceMsgs(t, msgs, aliceSplicer, bobSplicer)
+	require.NoError(t, err)
+
+	require.Len(t, broadcastTxs, 1)
+	spliceTx := broadcastTxs[0]
+	require.Equal(t, spliceTx, <-req.Updates)
+	require.True(t, aliceSplicer.AwaitingConfirmation())
+	require.True(t, bobSplicer.AwaitingConfirmation())
+
+	// Both parties must have persisted the same pending splice.
+	aliceSplice, err := aliceState.PendingSplice()
+	require.NoError(t, err)
+	bobSplice, err := bobState.PendingSplice()
+	require.NoError(t, err)
+	require.Equal(t, spliceTx.TxHash(), aliceSplice.SpliceTx.TxHash())
+	require.Equal(t, aliceSplice.FundingOutpoint, bobSplice.FundingOutpoint)
+	require.Equal(t, aliceSplice.Capacity, bobSplice.Capacity)
+
+	// While the splice is pending, the channel keeps forwarding, signing
+	// every commitment for both funding outputs.
+	preimage2 := [32]byte{2}
+	htlcIndex2 := sendHtlc(
+		t, aliceChannel, bobChannel, preimage2, htlcAmt,
+	)
+
+	// Alice must be able to punish Bob for broadcasting the revoked
+	// commitment that spends the funding output of the splice.
+	revokedHeight := aliceState.RemoteCommitment.CommitHeight - 1
+	retribution, err := lnwallet.NewSpliceBreachRetribution(
+		aliceState, revokedHeight, 0,
+	)
+	require.NoError(t, err)
+	require.Equal(t, aliceSplice.FundingOutpoint,
+		retribution.BreachTransaction.TxIn[0].PreviousOutPoint)
+
+	// Once the splice transaction confirms, both parties exchange their
+	// splice_locked messages. Alice completes the splice first.
+	aliceLocked, done, err := aliceSplicer.SpliceConfirmed()
+	require.NoError(t, err)
+	require.False(t, done)
+	bobLocked, done, err := bobSplicer.SpliceConfirmed()
+	require.NoError(t, err)
+	require.False(t, done)
+
+	_, done, err = aliceSplicer.ProcessSpliceMsg(bobLocked)
+	require.NoError(t, err)
+	require.True(t, done)
+	require.Equal(t, aliceSplice.FundingOutpoint,
+		aliceState.ActiveFundingOutpoint())
+
+	// Bob settles the first HTLC before he has processed Alice's
+	// splice_locked. Alice only accepts the commitment signed for the new
+	// funding output, while Bob completes the splice once he receives a
+	// commitment that only spends it.
+	settleHtlc(t, bobChannel, aliceChannel, preimage1, htlcIndex1)
+	require.Equal(t, aliceSplice.FundingOutpoint,
+		bobState.ActiveFundingOutpoint())
+
+	_, done, err = bobSplicer.ProcessSpliceMsg(aliceLocked)
+	require.NoError(t, err)
+	require.True(t, done)
+
+	// The channel point remains the same, while the channel is now funded
+	// by the new funding output.
+	require.Equal(t, chanPoint, aliceState.FundingOutpoint)
+	require.Equal(t, aliceSplice.Capacity, aliceState.Capacity)
+	require.Equal(t, aliceSplice.Capacity, bobState.Capacity)
+
+	// Finally, we'll ensure the channel can still be updated once it has
+	// been restored from disk.
+	alicePool := lnwallet.NewSigPool(1, aliceChannel.Signer)
+	require.NoError(t, alicePool.Start())
+	defer alicePool.Stop()
+	bobPool := lnwallet.NewSigPool(1, bobChannel.Signer)
+	require.NoError(t, bobPool.Start())
+	defer bobPool.Stop()
+
+	newAlice, err := lnwallet.NewLightningChannel(
+		aliceChannel.Signer, aliceState, alicePool,
+	)
+	require.NoError(t, err)
+	newBob, err := lnwallet.NewLightningChannel(
+		bobChannel.Signer, bobState, bobPool,
+	)
+	require.NoError(t, err)
+
+	settleHtlc(t, newBob, newAlice, preimage2, htlcIndex2)
+
+	// With all HTLCs settled, Alice paid for both of them, the spliced
+	// out funds and the fees of the splice transaction.
+	spliceFee := oldCapacity + req.Amount - aliceState.Capacity
+	require.Positive(t, int64(spliceFee))
+
+	htlcsMSat := lnwire.NewMSatFromSatoshis(2 * htlcAmt)
+	require.Equal(t, oldBobBalance+htlcsMSat,
+		bobState.LocalCommitment.LocalBalance)
+	aliceDelta := lnwire.NewMSatFromSatoshis(-req.Amount + spliceFee)
+	require.Equal(t, oldAliceBalance-aliceDelta-htlcsMSat,
+		aliceState.LocalCommitment.LocalBalance)
+}
+
+// TestChanSplicerPublicChannel tests that neither party splices an announced
+// channel.
+func TestChanSplicerPublicChannel(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := lnwallet.CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+	defer cleanUp()
+
+	chanPoint := *aliceChannel.ChannelPoint()
+	var broadcastTxs []*wire.MsgTx
+	aliceSplicer := NewChanSplicer(
+		newTestConfig(aliceChannel, &broadcastTxs),
+		newTestSpliceRequest(&chanPoint), 100,
+	)
+	bobSplicer := NewChanSplicer(
+		newTestConfig(bobChannel, &broadcastTxs), nil, 100,
+	)
+
+	// Alice can initiate a splice as long as the channel is private.
+	msgs, err := aliceSplicer.InitSplice()
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+
+	// Once the channel is announced, Bob refuses to respond to it.
+	bobChannel.State().ChannelFlags |= lnwire.FFAnnounceChannel
+	_, _, err = bobSplicer.ProcessSpliceMsg(msgs[0])
+	require.ErrorIs(t, err, ErrPublicChannel)
+
+	// Alice refuses to initiate a splice of an announced channel as well.
+	aliceChannel.State().ChannelFlags |= lnwire.FFAnnounceChannel
+	aliceSplicer = NewChanSplicer(
+		newTestConfig(aliceChannel, &broadcastTxs),
+		newTestSpliceRequest(&chanPoint), 100,
+	)
+	_, err = aliceSplicer.InitSplice()
+	require.ErrorIs(t, err, ErrPublicChannel)
+}
+
+// TestChanSplicerMarkSplicedFailure tests that a splice isn't persisted if the
+// channel graph can't be updated to track the new funding output.
+func TestChanSplicerMarkSplicedFailure(t *testing.T) {
+	t.Parallel()
+
+	aliceChannel, bobChannel, cleanUp, err := lnwallet.CreateTestChannels(
+		channeldb.SingleFunderTweaklessBit,
+	)
+	require.NoError(t, err, "unable to create test channels")
+	defer cleanUp()
+
+	chanPoint := *aliceChannel.ChannelPoint()
+	var broadcastTxs []*wire.MsgTx
+	aliceSplicer := NewChanSplicer(
+		newTestConfig(aliceChannel, &broadcastTxs),
+		newTestSpliceRequest(&chanPoint), 100,
+	)
+
+	// Bob is unable to update his channel graph.
+	errGraph := errors.New("graph failure")
+	bobCfg := newTestConfig(bobChannel, &broadcastTxs)
+	bobCfg.MarkChannelSpliced = func(_, _ *wire.OutPoint,
+		_ btcutil.Amount) error {
+
+		return errGraph
+	}
+	bobSplicer := NewChanSplicer(bobCfg, nil, 100)
+
+	msgs, err := aliceSplicer.InitSplice()
+	require.NoError(t, err)
+
+	err = exchangeSpliceMsgs(t, msgs, aliceSplicer, bobSplicer)
+	require.ErrorIs(t, err, errGraph)
+
+	// The error must be surfaced before anything is persisted or
+	// broadcast, so the splice can still be aborted.
+	require.Empty(t, broadcastTxs)
+	require.False(t, bobSplicer.AwaitingConfirmation())
+	_, err = bobChannel.State().PendingSplice()
+	require.ErrorIs(t, err, channeldb.ErrNoPendingSplice)
+}
@@ -0,0 +1,303 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrInvalidCommitTypeUpgrade is returned when the commitment type of
+	// a channel can't be upgraded to the requested type.
+	ErrInvalidCommitTypeUpgrade = fmt.Errorf("invalid commitment type " +
+		"upgrade")
+
+	// ErrCommitTypeUpgradeFunds is returned when the initiator of a
+	// channel can't pay for the commitment of the upgraded type.
+	ErrCommitTypeUpgradeFunds = fmt.Errorf("initiator can't pay for " +
+		"upgraded commitment")
+
+	// ErrChannelNotClean is returned when a commitment type upgrade is
+	// attempted on a channel that still has HTLCs or updates that haven't
+	// been locked in by both parties.
+	ErrChannelNotClean = fmt.Errorf("channel has active htlcs or " +
+		"pending updates")
+)
+
+// upgradeBits are the channel type bits that can be added to a channel by
+// upgrading its commitment type in place.
+const upgradeBits = channeldb.SingleFunderTweaklessBit |
+	channeldb.AnchorOutputsBit | channeldb.ZeroHtlcTxFeeBit
+
+// ValidateCommitTypeUpgrade returns an error if the commitment type of a
+// channel of the current type can't be upgraded to the new type. Channels can
+// be upgraded from legacy to static remote key commitments, and from either of
+// those to anchor commitments.
+func ValidateCommitTypeUpgrade(current,
+	newType channeldb.ChannelType) error {
+
+	switch {
+	case newType == current:
+		return fmt.Errorf("%w: channel already has type %v",
+			ErrInvalidCommitTypeUpgrade, current)
+
+	// Upgrades may only add features to the commitment type.
+	case newType&current != current, (newType^current)&^upgradeBits != 0:
+		return fmt.Errorf("%w: can't change channel type %v to %v",
+			ErrInvalidCommitTypeUpgrade, current, newType)
+
+	case current.HasAnchors():
+		return fmt.Errorf("%w: anchor channels can't be upgraded",
+			ErrInvalidCommitTypeUpgrade)
+
+	case newType.HasAnchors() && !newType.IsTweakless():
+		return fmt.Errorf("%w: anchor channels must be tweakless",
+			ErrInvalidCommitTypeUpgrade)
+
+	case newType.ZeroHtlcTxFee() && !newType.HasAnchors():
+		return fmt.Errorf("%w: zero fee htlc transactions require "+
+			"anchors", ErrInvalidCommitTypeUpgrade)
+	}
+
+	return nil
+}
+
+// UpgradedChanType returns the channel type that a channel of the current
+// type has after its commitment type is upgraded to the given commitment type.
+// An error is returned if the upgrade isn't possible.
+func UpgradedChanType(current channeldb.ChannelType,
+	commitType CommitmentType) (channeldb.ChannelType, error) {
+
+	newType := current &^ upgradeBits
+	switch commitType {
+	case CommitmentTypeLegacy:
+
+	case CommitmentTypeTweakless:
+		newType |= channeldb.SingleFunderTweaklessBit
+
+	case CommitmentTypeAnchorsZeroFeeHtlcTx:
+		newType |= channeldb.SingleFunderTweaklessBit |
+			channeldb.AnchorOutputsBit | channeldb.ZeroHtlcTxFeeBit
+
+	default:
+		return 0, fmt.Errorf("%w: unknown commitment type %v",
+			ErrInvalidCommitTypeUpgrade, commitType)
+	}
+
+	if err := ValidateCommitTypeUpgrade(current, newType); err != nil {
+		return 0, err
+	}
+
+	return newType, nil
+}
+
+// ChannelType returns the explicit channel type that denotes the commitment
+// type on the wire.
+func (c CommitmentType) ChannelType() lnwire.ChannelType {
+	var features *lnwire.RawFeatureVector
+	switch c {
+	case CommitmentTypeTweakless:
+		features = lnwire.NewRawFeatureVector(
+			lnwire.StaticRemoteKeyRequired,
+		)
+
+	case CommitmentTypeAnchorsZeroFeeHtlcTx:
+		features = lnwire.NewRawFeatureVector(
+			lnwire.StaticRemoteKeyRequired,
+			lnwire.AnchorsZeroFeeHtlcTxRequired,
+		)
+
+	default:
+		features = lnwire.NewRawFeatureVector()
+	}
+
+	return lnwire.ChannelType(*features)
+}
+
+// CommitmentTypeFromChannelType returns the commitment type denoted by the
+// passed explicit channel type.
+func CommitmentTypeFromChannelType(
+	chanType lnwire.ChannelType) (CommitmentType, error) {
+
+	features := lnwire.RawFeatureVector(chanType)
+	switch {
+	case features.OnlyContains(
+		lnwire.StaticRemoteKeyRequired,
+		lnwire.AnchorsZeroFeeHtlcTxRequired,
+	):
+		return CommitmentTypeAnchorsZeroFeeHtlcTx, nil
+
+	case features.OnlyContains(lnwire.StaticRemoteKeyRequired):
+		return CommitmentTypeTweakless, nil
+
+	case features.IsEmpty():
+		return CommitmentTypeLegacy, nil
+
+	default:
+		return 0, fmt.Errorf("%w: unknown channel type",
+			ErrInvalidCommitTypeUpgrade)
+	}
+}
+
+// UpgradeCommitType upgrades the commitment type of the channel to the new
+// type. All commitments signed from now on use the new type, so the upgrade
+// MUST be agreed on by both parties before either of them signs the next
+// commitment. The channel must be clean, which means that it's quiescent and
+// has no HTLCs.
+func (lc *LightningChannel) UpgradeCommitType(
+	newType channeldb.ChannelType) error {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	if !lc.isChannelClean() {
+		return ErrChannelNotClean
+	}
+
+	err := ValidateCommitTypeUpgrade(lc.channelState.ChanType, newType)
+	if err != nil {
+		return err
+	}
+
+	// The initiator pays for the fee of the upgraded commitment and, if
+	// the channel gains anchors, for both anchor outputs, so we'll make
+	// sure it is still above its reserve afterwards.
+	commit := lc.localCommitChain.tip()
+	initiatorBalance := commit.ourBalance
+	reserve := lc.channelState.LocalChanCfg.ChanReserve
+	if !lc.channelState.IsInitiator {
+		initiatorBalance = commit.theirBalance
+		reserve = lc.channelState.RemoteChanCfg.ChanReserve
+	}
+	initiatorBalance += lnwire.NewMSatFromSatoshis(commit.fee)
+
+	required := commit.feePerKw.FeeForWeight(CommitWeight(newType)) +
+		reserve
+	if newType.HasAnchors() {
+		required += 2 * anchorSize
+	}
+	if initiatorBalance < lnwire.NewMSatFromSatoshis(required) {
+		return fmt.Errorf("%w: balance %v, required %v",
+			ErrCommitTypeUpgradeFunds,
+			initiatorBalance.ToSatoshis(), required)
+	}
+
+	return lc.channelState.UpgradeCommitType(newType)
+}
+
+// CommitTypeUpgradePending returns true if the commitment type of the
+// channel has been upgraded, but either party doesn't have an irrevocably
+// committed commitment of the new type yet.
+func (lc *LightningChannel) CommitTypeUpgradePending() bool {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	upgrade := lc.latestCommitTypeUpgrade()
+	if upgrade == nil {
+		return false
+	}
+
+	return lc.localCommitChain.tail().height < upgrade.LocalHeight ||
+		lc.remoteCommitChain.tail().height < upgrade.RemoteHeight
+}
+
+// latestCommitTypeUpgrade returns the latest upgrade of the commitment type of
+// the channel, or nil if it was never upgraded.
+func (lc *LightningChannel) latestCommitTypeUpgrade() *channeldb.
+	CommitTypeUpgrade {
+
+	upgrades := lc.channelState.CommitTypeUpgrades()
+	if len(upgrades) == 0 {
+		return nil
+	}
+
+	return &upgrades[len(upgrades)-1]
+}
+
+// syncCommitTypeUpgrades undoes our latest upgrade of the commitment type if
+// the remote party didn't apply it. This happens if the connection was lost
+// while the upgrade was negotiated. As neither party signs a commitment of
+// the new type before both applied the upgrade, no such commitment can exist
+// in this case.
+func (lc *LightningChannel) syncCommitTypeUpgrades(
+	msg *lnwire.ChannelReestablish) error {
+
+	remoteUpgrades, err := msg.CommitTypeUpgrades()
+	if err != nil {
+		return err
+	}
+
+	localUpgrades := uint64(len(lc.channelState.CommitTypeUpgrades()))
+	switch {
+	// If the remote party applied more upgrades, it is up to them to
+	// undo theirs.
+	case localUpgrades <= remoteUpgrades:
+		return nil
+
+	case localUpgrades > remoteUpgrades+1:
+		return fmt.Errorf("remote party is missing %v commitment "+
+			"type upgrades", localUpgrades-remoteUpgrades)
+	}
+
+	upgrade := lc.latestCommitTypeUpgrade()
+	if lc.localCommitChain.tip().height >= upgrade.LocalHeight ||
+		lc.remoteCommitChain.tip().height >= upgrade.RemoteHeight {
+
+		return fmt.Errorf("remote party is missing commitment type " +
+			"upgrade that has already been signed")
+	}
+
+	lc.log.Infof("Reverting commitment type upgrade from %v to %v not "+
+		"applied by remote party", upgrade.PrevType,
+		lc.channelState.ChanType)
+
+	return lc.channelState.RevertCommitTypeUpgrade()
+}
+
+// upgradedAnchorCost returns the amount that is deducted from the balance of
+// the initiator when the next commitment of the given chain gains anchor
+// outputs because the commitment type was upgraded.
+func (lc *LightningChannel) upgradedAnchorCost(
+	commitChain *commitmentChain, local bool) btcutil.Amount {
+
+	if !lc.channelState.ChanType.HasAnchors() {
+		return 0
+	}
+
+	tip := commitChain.tip()
+	if lc.channelState.CommitChanType(local, tip.height).HasAnchors() {
+		return 0
+	}
+
+	return 2 * anchorSize
+}
+
+// localCommitChanType returns the channel type of our current commitment,
+// which differs from the channel type while an upgrade of the commitment type
+// is pending.
+func (lc *LightningChannel) localCommitChanType() channeldb.ChannelType {
+	return lc.channelState.CommitChanType(
+		true, lc.channelState.LocalCommitment.CommitHeight,
+	)
+}
+
+// isChannelClean returns true if neither party has any HTLCs on their
+// commitment transaction, and all updates have been irrevocably committed to
+// by both parties.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) isChannelClean() bool {
+	if lc.hasPendingUpdates() {
+		return false
+	}
+
+	localCommit := lc.localCommitChain.tip()
+	remoteCommit := lc.remoteCommitChain.tip()
+
+	return len(localCommit.outgoingHTLCs) == 0 &&
+		len(localCommit.incomingHTLCs) == 0 &&
+		len(remoteCommit.outgoingHTLCs) == 0 &&
+		len(remoteCommit.incomingHTLCs) == 0
+}
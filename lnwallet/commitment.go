@@ -4,10 +4,10 @@ import (
 	"fmt"
 
 	"github.com/btcsuite/btcd/blockchain"
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
@@ -739,6 +739,38 @@ func CoopCloseBalance(chanType channeldb.ChannelType, isInitiator bool,
 	return ourBalance, theirBalance, nil
 }
 
+// RbfCoopCloseBalance returns the final balances that should be used to
+// create a replaceable cooperative close tx. Unlike with CoopCloseBalance, the
+// fee is paid by the party proposing the closing transaction, as indicated by
+// localPays, while the balance of the other party is left untouched.
+func RbfCoopCloseBalance(chanType channeldb.ChannelType, isInitiator,
+	localPays bool, coopCloseFee btcutil.Amount,
+	localCommit channeldb.ChannelCommitment) (btcutil.Amount,
+	btcutil.Amount, error) {
+
+	// We start out with the balances of a close without any fee, such
+	// that the commitment fee and anchors are returned to the initiator.
+	ourBalance, theirBalance, err := CoopCloseBalance(
+		chanType, isInitiator, 0, localCommit,
+	)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if localPays {
+		ourBalance -= coopCloseFee
+	} else {
+		theirBalance -= coopCloseFee
+	}
+
+	if ourBalance < 0 || theirBalance < 0 {
+		return 0, 0, fmt.Errorf("proposer cannot afford coop close " +
+			"fee")
+	}
+
+	return ourBalance, theirBalance, nil
+}
+
 // genHtlcScript generates the proper P2WSH public key scripts for the HTLC
 // output modified by two-bits denoting if this is an incoming HTLC, and if the
 // HTLC is being applied to their commitment transaction or ours.
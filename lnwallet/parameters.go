@@ -1,7 +1,8 @@
 package lnwallet
 
 import (
-	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/wallet/txrules"
 	"github.com/lightningnetwork/lnd/input"
 )
@@ -9,5 +10,25 @@ import (
 // DefaultDustLimit is used to calculate the dust HTLC amount which will be
 // send to other node during funding process.
 func DefaultDustLimit() btcutil.Amount {
-	return txrules.GetDustThreshold(input.P2WSHSize, txrules.DefaultRelayFeePerKb)
+	return GetDustThreshold(input.P2WSHSize, txrules.DefaultRelayFeePerKb)
+}
+
+// GetDustThreshold is used to define the amount below which an output with a
+// script of the given size will be determined as dust. Threshold is
+// determined as 3 times the relay fee of the output and an input spending it.
+func GetDustThreshold(scriptSize int,
+	relayFeePerKb btcutil.Amount) btcutil.Amount {
+
+	// Calculate the total (estimated) cost to the network. This is
+	// calculated using the serialize size of the output plus the serial
+	// size of a transaction input which redeems it. The output is assumed
+	// to be compressed P2PKH as this is the most common script type. Use
+	// the average size of a compressed P2PKH redeem input (148) rather
+	// than the largest possible.
+	totalSize := 8 + wire.VarIntSerializeSize(uint64(scriptSize)) +
+		scriptSize + 148
+
+	byteFee := relayFeePerKb / 1000
+	relayFee := btcutil.Amount(totalSize) * byteFee
+	return 3 * relayFee
 }
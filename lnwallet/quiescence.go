@@ -0,0 +1,27 @@
+package lnwallet
+
+// HasPendingUpdates returns true if the local or the remote party, depending
+// on the local flag, has updates that aren't irrevocably committed to by both
+// parties yet. A party may only signal that the channel is quiescent once all
+// of its updates are irrevocably committed.
+func (lc *LightningChannel) HasPendingUpdates(local bool) bool {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	var (
+		localTail  = lc.localCommitChain.tail()
+		remoteTail = lc.remoteCommitChain.tail()
+	)
+
+	if local {
+		logIndex := lc.localUpdateLog.logIndex
+
+		return logIndex != localTail.ourMessageIndex ||
+			logIndex != remoteTail.ourMessageIndex
+	}
+
+	logIndex := lc.remoteUpdateLog.logIndex
+
+	return logIndex != localTail.theirMessageIndex ||
+		logIndex != remoteTail.theirMessageIndex
+}
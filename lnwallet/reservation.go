@@ -1,13 +1,14 @@
 package lnwallet
 
 import (
+	"fmt"
 	"net"
 	"sync"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
@@ -163,6 +164,10 @@ type ChannelReservation struct {
 
 	fundingIntent chanfunding.Intent
 
+	// interactive is true if the funding transaction of this reservation
+	// is constructed interactively together with the remote party.
+	interactive bool
+
 	// nextRevocationKeyLoc stores the key locator information for this
 	// channel.
 	nextRevocationKeyLoc keychain.KeyLocator
@@ -318,6 +323,14 @@ func NewChannelReservation(capacity, localFundingAmt btcutil.Amount,
 		// technically the "initiator"
 		initiator = false
 		chanType |= channeldb.DualFunderBit
+
+		// Dual funded channels use the same commitment format as
+		// single funded ones, so we also set the tweakless bit if
+		// needed.
+		if commitType == CommitmentTypeTweakless ||
+			commitType == CommitmentTypeAnchorsZeroFeeHtlcTx {
+			chanType |= channeldb.SingleFunderTweaklessBit
+		}
 	}
 
 	// We are adding anchor outputs to our commitment. We only support this
@@ -491,6 +504,176 @@ func (r *ChannelReservation) ProcessContribution(theirContribution *ChannelContr
 	return <-errChan
 }
 
+// ProcessInteractiveContribution records the counterparty's contribution to
+// a pending dual funded channel whose funding transaction is constructed
+// interactively. Unlike ProcessContribution, the funding transaction isn't
+// assembled yet, it will be passed in through ProcessInteractiveTx once both
+// parties agreed on it.
+func (r *ChannelReservation) ProcessInteractiveContribution(
+	theirContribution *ChannelContribution) error {
+
+	errChan := make(chan error, 1)
+
+	r.wallet.msgChan <- &addInteractiveContributionMsg{
+		pendingFundingID: r.reservationID,
+		contribution:     theirContribution,
+		err:              errChan,
+	}
+
+	return <-errChan
+}
+
+// ProcessInteractiveTx continues the funding flow of a dual funded channel
+// once the funding transaction has been constructed interactively. Our inputs
+// to the transaction are signed and both commitment transactions are created,
+// so the signature for the counterparty's version of the commitment
+// transaction is available via .OurSignatures() afterwards.
+func (r *ChannelReservation) ProcessInteractiveTx(fundingTx *wire.MsgTx) error {
+	errChan := make(chan error, 1)
+
+	r.wallet.msgChan <- &addInteractiveTxMsg{
+		pendingFundingID: r.reservationID,
+		fundingTx:        fundingTx,
+		err:              errChan,
+	}
+
+	return <-errChan
+}
+
+// AddRemoteFunding adds the given amount contributed by the remote party to
+// a channel we initiated, turning the reservation into a dual funded one. The
+// funding transaction will then need to be constructed interactively. This is
+// only possible if the channel is funded by the wallet itself. If the remote
+// party's contribution is leased, we pay the lease fee out of our balance.
+func (r *ChannelReservation) AddRemoteFunding(amt btcutil.Amount,
+	lease *channeldb.LeaseTerms) error {
+
+	r.Lock()
+	defer r.Unlock()
+
+	fullIntent, ok := r.fundingIntent.(*chanfunding.FullIntent)
+	if !ok {
+		return fmt.Errorf("remote funding is only supported for " +
+			"wallet funded channels")
+	}
+	if r.pushMSat != 0 {
+		return fmt.Errorf("remote funding is not supported for " +
+			"channels with a push amount")
+	}
+
+	err := r.setDualFundedBalances(
+		fullIntent.LocalFundingAmt(), amt, true, lease,
+	)
+	if err != nil {
+		return err
+	}
+
+	fullIntent.AddRemoteFunding(amt)
+	r.interactive = true
+
+	return nil
+}
+
+// IsInteractive returns true if the funding transaction of this reservation
+// is constructed interactively together with the remote party.
+func (r *ChannelReservation) IsInteractive() bool {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.interactive
+}
+
+// FundingOutput returns the funding output of the channel. It is only
+// available once the multi-sig keys of both parties are known.
+func (r *ChannelReservation) FundingOutput() (*wire.TxOut, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	_, fundingOutput, err := r.fundingIntent.FundingOutput()
+	return fundingOutput, err
+}
+
+// FundingInputs returns the wallet inputs and change outputs we contribute to
+// the funding transaction.
+func (r *ChannelReservation) FundingInputs() ([]chanfunding.Coin,
+	[]*wire.TxOut) {
+
+	r.RLock()
+	defer r.RUnlock()
+
+	fullIntent, ok := r.fundingIntent.(*chanfunding.FullIntent)
+	if !ok {
+		return nil, nil
+	}
+
+	return fullIntent.InputCoins, fullIntent.ChangeOutputs
+}
+
+// setDualFundedBalances sets the starting balances of a dual funded channel
+// where we contribute localAmt and the remote party contributes remoteAmt.
+// The initiator of the channel pays for the commitment fee and the anchors.
+// If the responder's contribution is leased, the initiator also pays the
+// lease fee to the responder.
+//
+// NOTE: The reservation's mutex MUST be held when calling this method.
+func (r *ChannelReservation) setDualFundedBalances(localAmt,
+	remoteAmt btcutil.Amount, initiator bool,
+	lease *channeldb.LeaseTerms) error {
+
+	chanState := r.partialState
+	commitFee := chanState.LocalCommitment.CommitFee
+	fees := commitFee
+	if chanState.ChanType.HasAnchors() {
+		fees += 2 * anchorSize
+	}
+
+	var leaseFee btcutil.Amount
+	if lease != nil {
+		leaseFee = lease.Fee
+	}
+
+	ourBalance, theirBalance := localAmt, remoteAmt
+	if initiator {
+		ourBalance -= fees + leaseFee
+		theirBalance += leaseFee
+	} else {
+		theirBalance -= fees + leaseFee
+		ourBalance += leaseFee
+	}
+
+	// The initiator must be able to pay for the fees, and still have a
+	// balance above the dust limit afterwards.
+	funderBalance := theirBalance
+	if initiator {
+		funderBalance = ourBalance
+	}
+	if funderBalance <= 2*DefaultDustLimit() {
+		return ErrFunderBalanceDust(
+			int64(commitFee), int64(funderBalance),
+			int64(2*DefaultDustLimit()),
+		)
+	}
+
+	// A dual funded channel uses the same commitment format as a single
+	// funded one, so we only need to replace the funder bit.
+	chanState.ChanType |= channeldb.DualFunderBit
+	chanState.IsInitiator = initiator
+	chanState.Capacity = localAmt + remoteAmt
+	chanState.Lease = lease
+
+	ourBalanceMSat := lnwire.NewMSatFromSatoshis(ourBalance)
+	theirBalanceMSat := lnwire.NewMSatFromSatoshis(theirBalance)
+	chanState.LocalCommitment.LocalBalance = ourBalanceMSat
+	chanState.LocalCommitment.RemoteBalance = theirBalanceMSat
+	chanState.RemoteCommitment.LocalBalance = ourBalanceMSat
+	chanState.RemoteCommitment.RemoteBalance = theirBalanceMSat
+
+	r.ourContribution.FundingAmount = ourBalance
+	r.theirContribution.FundingAmount = theirBalance
+
+	return nil
+}
+
 // IsPsbt returns true if there is a PSBT funding intent mapped to this
 // reservation.
 func (r *ChannelReservation) IsPsbt() bool {
@@ -0,0 +1,734 @@
+package rpcwallet
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnrpc/signrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/macaroons"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"gopkg.in/macaroon.v2"
+)
+
+var (
+	// ErrRemoteSigningPrivateKeyNotAvailable is the error that is returned
+	// if an operation is requested from the RPC wallet that is not
+	// supported in remote signing mode.
+	ErrRemoteSigningPrivateKeyNotAvailable = errors.New("deriving " +
+		"private key is not supported by RPC based key ring")
+)
+
+// RPCKeyRing is an implementation of the SecretKeyRing interface that uses a
+// local watch-only wallet for keeping track of addresses and transactions but
+// delegates any signing or ECDH operations to a remote signer node through
+// RPC. The remote signer is another lnd instance that holds the private keys
+// of both the on-chain wallet and all key families and exposes them through
+// its signrpc and walletrpc sub-servers.
+type RPCKeyRing struct {
+	// WalletController is the embedded wallet controller of the watch-only
+	// base wallet. We shadow all of its methods that need to sign anything
+	// so the signing is done by the remote signer.
+	lnwallet.WalletController
+
+	// watchOnlyKeyRing is the key ring of the watch-only wallet. It can
+	// derive all public keys from the imported account xpubs.
+	watchOnlyKeyRing keychain.SecretKeyRing
+
+	rpcTimeout time.Duration
+
+	conn         *grpc.ClientConn
+	signerClient signrpc.SignerClient
+	walletClient walletrpc.WalletKitClient
+}
+
+// A compile time check to ensure that RPCKeyRing implements the desired
+// interfaces.
+var _ keychain.SecretKeyRing = (*RPCKeyRing)(nil)
+var _ input.Signer = (*RPCKeyRing)(nil)
+var _ lnwallet.MessageSigner = (*RPCKeyRing)(nil)
+var _ lnwallet.WalletController = (*RPCKeyRing)(nil)
+
+// NewRPCKeyRing creates a new remote signing secret key ring that uses the
+// given watch-only wallet and key ring for everything that doesn't need a
+// private key and connects to the remote signer described by the passed
+// remote signer config for everything else.
+func NewRPCKeyRing(watchOnlyKeyRing keychain.SecretKeyRing,
+	watchOnlyWallet lnwallet.WalletController,
+	remoteSigner *lncfg.RemoteSigner) (*RPCKeyRing, error) {
+
+	rpcConn, err := connectRPC(
+		remoteSigner.RPCHost, remoteSigner.TLSCertPath,
+		remoteSigner.MacaroonPath, remoteSigner.Timeout,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to the remote "+
+			"signing node through RPC: %v", err)
+	}
+
+	return &RPCKeyRing{
+		WalletController: watchOnlyWallet,
+		watchOnlyKeyRing: watchOnlyKeyRing,
+		rpcTimeout:       remoteSigner.Timeout,
+		conn:             rpcConn,
+		signerClient:     signrpc.NewSignerClient(rpcConn),
+		walletClient:     walletrpc.NewWalletKitClient(rpcConn),
+	}, nil
+}
+
+// Close closes the RPC connection to the remote signer. The watch-only wallet
+// is stopped through the embedded WalletController's Stop method.
+func (r *RPCKeyRing) Close() error {
+	return r.conn.Close()
+}
+
+// DeriveNextKey attempts to derive the *next* key within the key family
+// (account in BIP43) specified. This method should return the next external
+// child within this branch. The public key is derived from the account xpub
+// of the watch-only wallet, so the remote signer isn't involved.
+//
+// NOTE: This method is part of the keychain.KeyRing interface.
+func (r *RPCKeyRing) DeriveNextKey(
+	keyFam keychain.KeyFamily) (keychain.KeyDescriptor, error) {
+
+	return r.watchOnlyKeyRing.DeriveNextKey(keyFam)
+}
+
+// DeriveKey attempts to derive an arbitrary key specified by the passed
+// KeyLocator. This may be used in several recovery scenarios, or when manually
+// rotating something like our current default node key. The public key is
+// derived from the account xpub of the watch-only wallet, so the remote signer
+// isn't involved.
+//
+// NOTE: This method is part of the keychain.KeyRing interface.
+func (r *RPCKeyRing) DeriveKey(
+	keyLoc keychain.KeyLocator) (keychain.KeyDescriptor, error) {
+
+	return r.watchOnlyKeyRing.DeriveKey(keyLoc)
+}
+
+// DerivePrivKey attempts to derive the private key that corresponds to the
+// passed key descriptor. As the private keys never leave the remote signer,
+// this always returns an error.
+//
+// NOTE: This method is part of the keychain.SecretKeyRing interface.
+func (r *RPCKeyRing) DerivePrivKey(_ keychain.KeyDescriptor) (
+	*btcec.PrivateKey, error) {
+
+	return nil, ErrRemoteSigningPrivateKeyNotAvailable
+}
+
+// ECDH performs a scalar multiplication (ECDH-like operation) between the
+// target key descriptor and remote public key. The output returned will be the
+// sha256 of the resulting shared point serialized in compressed format. If k
+// is our private key, and P is the public key, we perform the following
+// operation:
+//
+//  sx := k*P
+//  s := sha256(sx.SerializeCompressed())
+//
+// NOTE: This method is part of the keychain.ECDHRing interface.
+func (r *RPCKeyRing) ECDH(keyDesc keychain.KeyDescriptor,
+	pubKey *btcec.PublicKey) ([32]byte, error) {
+
+	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
+	defer cancel()
+
+	key := [32]byte{}
+	req := &signrpc.SharedKeyRequest{
+		EphemeralPubkey: pubKey.SerializeCompressed(),
+		KeyDesc: &signrpc.KeyDescriptor{
+			KeyLoc: &signrpc.KeyLocator{
+				KeyFamily: int32(keyDesc.Family),
+				KeyIndex:  int32(keyDesc.Index),
+			},
+		},
+	}
+
+	// The remote signer only accepts a raw public key if no key index is
+	// set, as it'll then scan the key family for the matching key itself.
+	if keyDesc.Index == 0 && keyDesc.PubKey != nil {
+		req.KeyDesc.RawKeyBytes = keyDesc.PubKey.SerializeCompressed()
+	}
+
+	resp, err := r.signerClient.DeriveSharedKey(ctxt, req)
+	if err != nil {
+		return key, fmt.Errorf("error deriving shared key with "+
+			"remote signer: %v", err)
+	}
+
+	copy(key[:], resp.SharedKey)
+	return key, nil
+}
+
+// SignMessage signs the given message, single or double SHA256 hashing it
+// first, with the private key described in the key locator.
+//
+// NOTE: This method is part of the keychain.MessageSignerRing and
+// lnwallet.MessageSigner interfaces.
+func (r *RPCKeyRing) SignMessage(keyLoc keychain.KeyLocator,
+	msg []byte, doubleHash bool) (*ecdsa.Signature, error) {
+
+	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
+	defer cancel()
+
+	resp, err := r.signerClient.SignMessage(ctxt, &signrpc.SignMessageReq{
+		Msg: msg,
+		KeyLoc: &signrpc.KeyLocator{
+			KeyFamily: int32(keyLoc.Family),
+			KeyIndex:  int32(keyLoc.Index),
+		},
+		DoubleHash: doubleHash,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error signing message with remote "+
+			"signer: %v", err)
+	}
+
+	wireSig, err := lnwire.NewSigFromRawSignature(resp.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create sig: %v", err)
+	}
+
+	return wireSig.ToSignature()
+}
+
+// SignMessageCompact signs the given message, single or double SHA256 hashing
+// it first, with the private key described in the key locator and returns the
+// signature in the compact, public key recoverable format.
+//
+// NOTE: This method is part of the keychain.MessageSignerRing interface.
+func (r *RPCKeyRing) SignMessageCompact(keyLoc keychain.KeyLocator,
+	msg []byte, doubleHash bool) ([]byte, error) {
+
+	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
+	defer cancel()
+
+	resp, err := r.signerClient.SignMessage(ctxt, &signrpc.SignMessageReq{
+		Msg: msg,
+		KeyLoc: &signrpc.KeyLocator{
+			KeyFamily: int32(keyLoc.Family),
+			KeyIndex:  int32(keyLoc.Index),
+		},
+		DoubleHash: doubleHash,
+		CompactSig: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error signing message with remote "+
+			"signer: %v", err)
+	}
+
+	return resp.Signature, nil
+}
+
+// SignOutputRaw generates a signature for the passed transaction according to
+// the data within the passed SignDescriptor.
+//
+// NOTE: This method is part of the input.Signer interface.
+func (r *RPCKeyRing) SignOutputRaw(tx *wire.MsgTx,
+	signDesc *input.SignDescriptor) (input.Signature, error) {
+
+	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
+	defer cancel()
+
+	var buf bytes.Buffer
+	if err := tx.Serialize(&buf); err != nil {
+		return nil, err
+	}
+
+	rpcSignDesc, err := toRPCSignDesc(signDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Taproot signatures commit to all outputs spent by the transaction,
+	// so we need to pass them along to the remote signer.
+	var prevOutputs []*signrpc.TxOut
+	if signDesc.SignMethod.IsTaproot() {
+		if signDesc.PrevOutputFetcher == nil {
+			return nil, input.ErrTaprootPrevOutputs
+		}
+
+		for _, txIn := range tx.TxIn {
+			prevOut := signDesc.PrevOutputFetcher.FetchPrevOutput(
+				txIn.PreviousOutPoint,
+			)
+			if prevOut == nil {
+				return nil, input.ErrTaprootPrevOutputs
+			}
+
+			prevOutputs = append(prevOutputs, &signrpc.TxOut{
+				Value:    prevOut.Value,
+				PkScript: prevOut.PkScript,
+			})
+		}
+	}
+
+	resp, err := r.signerClient.SignOutputRaw(ctxt, &signrpc.SignReq{
+		RawTxBytes:  buf.Bytes(),
+		SignDescs:   []*signrpc.SignDescriptor{rpcSignDesc},
+		PrevOutputs: prevOutputs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error signing output with remote "+
+			"signer: %v", err)
+	}
+
+	if len(resp.RawSigs) != 1 {
+		return nil, fmt.Errorf("expected one signature from remote "+
+			"signer, got %d", len(resp.RawSigs))
+	}
+
+	if signDesc.SignMethod.IsTaproot() {
+		return schnorr.ParseSignature(resp.RawSigs[0])
+	}
+
+	return ecdsa.ParseDERSignature(resp.RawSigs[0])
+}
+
+// ComputeInputScript generates a complete InputIndex for the passed
+// transaction with the signature as defined within the passed SignDescriptor.
+// This method is only used for inputs that belong to the on-chain wallet. The
+// watch-only wallet knows the derivation path of their keys, so we hand the
+// input to the remote signer as a PSBT to get it signed.
+//
+// NOTE: This method is part of the input.Signer interface.
+func (r *RPCKeyRing) ComputeInputScript(tx *wire.MsgTx,
+	signDesc *input.SignDescriptor) (*input.Script, error) {
+
+	// The remote signer derives the key from its BIP32 path only, so it
+	// can't apply any tweaks. Wallet inputs never use them anyway.
+	if signDesc.SingleTweak != nil || signDesc.DoubleTweak != nil {
+		return nil, fmt.Errorf("cannot compute input script for " +
+			"wallet input with tweaked key")
+	}
+
+	packet, err := r.newSignPacket(tx, signDesc.PrevOutputFetcher)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := signDesc.InputIndex
+	if idx < 0 || idx >= len(packet.Inputs) {
+		return nil, fmt.Errorf("invalid input index %d", idx)
+	}
+	packet.Inputs[idx].WitnessUtxo = signDesc.Output
+
+	sigScript, err := r.addDerivationInfo(
+		&packet.Inputs[idx], signDesc.HashType,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	signedPacket, err := r.remoteSignPsbt(packet)
+	if err != nil {
+		return nil, err
+	}
+
+	// Depending on the type of the output, the remote signer either added
+	// a schnorr signature for a taproot key spend or an ECDSA signature
+	// for a (nested) p2wkh input.
+	signedIn := signedPacket.Inputs[idx]
+	switch {
+	case len(signedIn.TaprootKeySpendSig) > 0:
+		return &input.Script{
+			Witness: wire.TxWitness{signedIn.TaprootKeySpendSig},
+		}, nil
+
+	case len(signedIn.PartialSigs) == 1:
+		partialSig := signedIn.PartialSigs[0]
+		return &input.Script{
+			Witness: wire.TxWitness{
+				partialSig.Signature, partialSig.PubKey,
+			},
+			SigScript: sigScript,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("remote signer didn't sign input %d",
+			idx)
+	}
+}
+
+// SendOutputs funds, signs, and broadcasts a Bitcoin transaction paying out to
+// the specified outputs. The watch-only wallet creates the transaction and the
+// remote signer signs all of its inputs.
+//
+// NOTE: This method is part of the lnwallet.WalletController interface.
+func (r *RPCKeyRing) SendOutputs(outputs []*wire.TxOut,
+	feeRate chainfee.SatPerKWeight, minConfs int32,
+	label string) (*wire.MsgTx, error) {
+
+	// The watch-only wallet doesn't sign the inputs of the transaction it
+	// creates, it only selects them and adds the change output.
+	authoredTx, err := r.WalletController.CreateSimpleTx(
+		outputs, feeRate, minConfs, false,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := authoredTx.Tx
+	packet, err := psbt.NewFromUnsignedTx(tx)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range packet.Inputs {
+		packet.Inputs[idx].WitnessUtxo = &wire.TxOut{
+			Value:    int64(authoredTx.PrevInputValues[idx]),
+			PkScript: authoredTx.PrevScripts[idx],
+		}
+	}
+
+	if err := r.signAndFinalize(packet, nil); err != nil {
+		return nil, err
+	}
+
+	finalTx, err := psbt.Extract(packet)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.WalletController.PublishTransaction(finalTx, label)
+	if err != nil {
+		return nil, err
+	}
+
+	return finalTx, nil
+}
+
+// SignPsbt expects a partial transaction with all inputs and outputs fully
+// declared and lets the remote signer sign all unsigned inputs that have all
+// required fields set.
+//
+// NOTE: This method is part of the lnwallet.WalletController interface.
+func (r *RPCKeyRing) SignPsbt(packet *psbt.Packet) error {
+	signedPacket, err := r.remoteSignPsbt(packet)
+	if err != nil {
+		return err
+	}
+
+	*packet = *signedPacket
+
+	return nil
+}
+
+// FinalizePsbt expects a partial transaction with all inputs and outputs fully
+// declared and lets the remote signer sign all inputs that belong to the
+// specified account. Lnd must be the last signer of the transaction. That
+// means, if there are any unsigned non-witness inputs or inputs without UTXO
+// information attached or inputs without witness data that do not belong to
+// lnd's wallet, this method will fail.
+//
+// NOTE: This method is part of the lnwallet.WalletController interface.
+func (r *RPCKeyRing) FinalizePsbt(packet *psbt.Packet, account string) error {
+	accounts, err := r.WalletController.ListAccounts(account, nil)
+	if err != nil {
+		return err
+	}
+
+	return r.signAndFinalize(packet, accounts)
+}
+
+// signAndFinalize adds the derivation info to all inputs of the packet that
+// belong to the watch-only wallet, lets the remote signer sign them and then
+// finalizes the packet. If a list of accounts is given, only the inputs of
+// those accounts are signed.
+func (r *RPCKeyRing) signAndFinalize(packet *psbt.Packet,
+	accounts []*waddrmgr.AccountProperties) error {
+
+	for idx := range packet.Inputs {
+		in := &packet.Inputs[idx]
+		if len(in.FinalScriptWitness) > 0 || in.WitnessUtxo == nil {
+			continue
+		}
+
+		addr, _, _, err := r.WalletController.ScriptForOutput(
+			in.WitnessUtxo,
+		)
+		if err != nil {
+			continue
+		}
+		if accounts != nil && !isAccountAddress(addr, accounts) {
+			continue
+		}
+
+		_, err = r.addDerivationInfo(in, in.SighashType)
+		if err != nil {
+			return err
+		}
+	}
+
+	signedPacket, err := r.remoteSignPsbt(packet)
+	if err != nil {
+		return err
+	}
+	*packet = *signedPacket
+
+	// Now that all our inputs are signed, we can finalize them. This fails
+	// if any input is still missing its signature.
+	return psbt.MaybeFinalizeAll(packet)
+}
+
+// newSignPacket creates a PSBT packet for the given transaction that can be
+// handed to the remote signer. As the signatures don't commit to the input
+// scripts, those are removed. The outputs spent by all inputs are looked up,
+// either through the given fetcher or in the watch-only wallet, as taproot
+// signatures commit to all of them.
+func (r *RPCKeyRing) newSignPacket(tx *wire.MsgTx,
+	prevOutFetcher txscript.PrevOutputFetcher) (*psbt.Packet, error) {
+
+	unsignedTx := tx.Copy()
+	for _, txIn := range unsignedTx.TxIn {
+		txIn.SignatureScript = nil
+		txIn.Witness = nil
+	}
+
+	packet, err := psbt.NewFromUnsignedTx(unsignedTx)
+	if err != nil {
+		return nil, err
+	}
+
+	for idx, txIn := range unsignedTx.TxIn {
+		var prevOut *wire.TxOut
+		if prevOutFetcher != nil {
+			prevOut = prevOutFetcher.FetchPrevOutput(
+				txIn.PreviousOutPoint,
+			)
+		}
+
+		if prevOut == nil {
+			utxo, err := r.WalletController.FetchInputInfo(
+				&txIn.PreviousOutPoint,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("unable to find "+
+					"output spent by input %d: %v", idx,
+					err)
+			}
+
+			prevOut = &wire.TxOut{
+				Value:    int64(utxo.Value),
+				PkScript: utxo.PkScript,
+			}
+		}
+
+		packet.Inputs[idx].WitnessUtxo = prevOut
+	}
+
+	return packet, nil
+}
+
+// addDerivationInfo adds the BIP32 derivation path of the key that the output
+// spent by the given input is locked to, so the remote signer can derive the
+// key to sign it with. The sig script required to spend the output is
+// returned, which is only set for nested p2wkh outputs.
+func (r *RPCKeyRing) addDerivationInfo(in *psbt.PInput,
+	hashType txscript.SigHashType) ([]byte, error) {
+
+	addr, witnessProgram, sigScript, err :=
+		r.WalletController.ScriptForOutput(in.WitnessUtxo)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, path, ok := addr.DerivationInfo()
+	if !ok {
+		return nil, fmt.Errorf("address %v has no derivation info "+
+			"and can't be signed by the remote signer",
+			addr.Address())
+	}
+
+	in.SighashType = hashType
+	in.Bip32Derivation = []*psbt.Bip32Derivation{{
+		PubKey:               addr.PubKey().SerializeCompressed(),
+		MasterKeyFingerprint: path.MasterKeyFingerprint,
+		Bip32Path: []uint32{
+			scope.Purpose + hdkeychain.HardenedKeyStart,
+			scope.Coin + hdkeychain.HardenedKeyStart,
+			path.Account + hdkeychain.HardenedKeyStart,
+			path.Branch, path.Index,
+		},
+	}}
+
+	// The remote signer needs the p2wkh witness program as redeem script
+	// to sign a nested p2wkh input.
+	if len(sigScript) > 0 {
+		in.RedeemScript = witnessProgram
+	}
+
+	return sigScript, nil
+}
+
+// remoteSignPsbt lets the remote signer sign all inputs of the given packet
+// that carry the required derivation info and returns the signed packet.
+func (r *RPCKeyRing) remoteSignPsbt(packet *psbt.Packet) (*psbt.Packet,
+	error) {
+
+	var buf bytes.Buffer
+	if err := packet.Serialize(&buf); err != nil {
+		return nil, err
+	}
+
+	ctxt, cancel := context.WithTimeout(context.Background(), r.rpcTimeout)
+	defer cancel()
+
+	resp, err := r.walletClient.SignPsbt(ctxt, &walletrpc.SignPsbtRequest{
+		FundedPsbt: buf.Bytes(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error signing PSBT with remote "+
+			"signer: %v", err)
+	}
+
+	return psbt.NewFromRawBytes(bytes.NewReader(resp.SignedPsbt), false)
+}
+
+// isAccountAddress returns true if the given address belongs to any of the
+// given accounts.
+func isAccountAddress(addr waddrmgr.ManagedPubKeyAddress,
+	accounts []*waddrmgr.AccountProperties) bool {
+
+	scope, _, ok := addr.DerivationInfo()
+	if !ok {
+		return false
+	}
+
+	for _, account := range accounts {
+		if account.KeyScope == scope &&
+			account.AccountNumber == addr.InternalAccount() {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// toRPCSignDesc converts the given sign descriptor to its RPC counterpart.
+func toRPCSignDesc(signDesc *input.SignDescriptor) (*signrpc.SignDescriptor,
+	error) {
+
+	if signDesc.Output == nil {
+		return nil, fmt.Errorf("sign descriptor is missing the " +
+			"output to be spent")
+	}
+
+	keyDesc := &signrpc.KeyDescriptor{
+		KeyLoc: &signrpc.KeyLocator{
+			KeyFamily: int32(signDesc.KeyDesc.Family),
+			KeyIndex:  int32(signDesc.KeyDesc.Index),
+		},
+	}
+	if signDesc.KeyDesc.PubKey != nil {
+		pubKey := signDesc.KeyDesc.PubKey
+		keyDesc.RawKeyBytes = pubKey.SerializeCompressed()
+	}
+
+	var doubleTweak []byte
+	if signDesc.DoubleTweak != nil {
+		doubleTweak = signDesc.DoubleTweak.Serialize()
+	}
+
+	var signMethod signrpc.SignMethod
+	switch signDesc.SignMethod {
+	case input.WitnessV0SignMethod:
+		signMethod = signrpc.SignMethod_SIGN_METHOD_WITNESS_V0
+
+	case input.TaprootKeySpendBIP0086SignMethod:
+		signMethod = signrpc.
+			SignMethod_SIGN_METHOD_TAPROOT_KEY_SPEND_BIP0086
+
+	case input.TaprootKeySpendSignMethod:
+		signMethod = signrpc.SignMethod_SIGN_METHOD_TAPROOT_KEY_SPEND
+
+	default:
+		return nil, fmt.Errorf("unknown sign method %v",
+			signDesc.SignMethod)
+	}
+
+	return &signrpc.SignDescriptor{
+		KeyDesc:       keyDesc,
+		SingleTweak:   signDesc.SingleTweak,
+		DoubleTweak:   doubleTweak,
+		WitnessScript: signDesc.WitnessScript,
+		Output: &signrpc.TxOut{
+			Value:    signDesc.Output.Value,
+			PkScript: signDesc.Output.PkScript,
+		},
+		Sighash:    uint32(signDesc.HashType),
+		InputIndex: int32(signDesc.InputIndex),
+		SignMethod: signMethod,
+		TapTweak:   signDesc.TapTweak,
+	}, nil
+}
+
+// connectRPC tries to connect to the remote signer through RPC.
+func connectRPC(hostPort, tlsCertPath, macaroonPath string,
+	timeout time.Duration) (*grpc.ClientConn, error) {
+
+	certBytes, err := ioutil.ReadFile(tlsCertPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading TLS cert file %v: %v",
+			tlsCertPath, err)
+	}
+
+	cp := x509.NewCertPool()
+	if !cp.AppendCertsFromPEM(certBytes) {
+		return nil, fmt.Errorf("credentials: failed to append " +
+			"certificate")
+	}
+
+	macBytes, err := ioutil.ReadFile(macaroonPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading macaroon file %v: %v",
+			macaroonPath, err)
+	}
+	mac := &macaroon.Macaroon{}
+	if err := mac.UnmarshalBinary(macBytes); err != nil {
+		return nil, fmt.Errorf("error decoding macaroon: %v", err)
+	}
+
+	macCred := macaroons.NewMacaroonCredential(mac)
+	opts := []grpc.DialOption{
+		grpc.WithBlock(),
+		grpc.WithTransportCredentials(
+			credentials.NewClientTLSFromCert(cp, ""),
+		),
+		grpc.WithPerRPCCredentials(macCred),
+	}
+
+	ctxt, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	log.Infof("Connecting to remote signer at %v", hostPort)
+
+	conn, err := grpc.DialContext(ctxt, hostPort, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to RPC server: %v",
+			err)
+	}
+
+	return conn, nil
+}
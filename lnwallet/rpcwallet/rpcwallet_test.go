@@ -0,0 +1,259 @@
+package rpcwallet
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/waddrmgr"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnrpc/walletrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+// mockAddress is a p2wkh wallet address with a known derivation path.
+type mockAddress struct {
+	waddrmgr.ManagedPubKeyAddress
+
+	pubKey  *btcec.PublicKey
+	address btcutil.Address
+	scope   waddrmgr.KeyScope
+	path    waddrmgr.DerivationPath
+}
+
+func (m *mockAddress) PubKey() *btcec.PublicKey {
+	return m.pubKey
+}
+
+func (m *mockAddress) Address() btcutil.Address {
+	return m.address
+}
+
+func (m *mockAddress) DerivationInfo() (waddrmgr.KeyScope,
+	waddrmgr.DerivationPath, bool) {
+
+	return m.scope, m.path, true
+}
+
+// mockWatchOnlyWallet is a watch-only wallet that owns a single address.
+type mockWatchOnlyWallet struct {
+	lnwallet.WalletController
+
+	addr *mockAddress
+}
+
+func (m *mockWatchOnlyWallet) ScriptForOutput(
+	*wire.TxOut) (waddrmgr.ManagedPubKeyAddress, []byte, []byte, error) {
+
+	pkScript, err := txscript.PayToAddrScript(m.addr.address)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return m.addr, pkScript, nil, nil
+}
+
+// mockRemoteSigner is a remote signer that holds the private key of the
+// single address of the watch-only wallet and records the PSBT it signed.
+type mockRemoteSigner struct {
+	walletrpc.WalletKitClient
+
+	privKey  *btcec.PrivateKey
+	lastPsbt *psbt.Packet
+}
+
+func (m *mockRemoteSigner) SignPsbt(_ context.Context,
+	req *walletrpc.SignPsbtRequest,
+	_ ...grpc.CallOption) (*walletrpc.SignPsbtResponse, error) {
+
+	packet, err := psbt.NewFromRawBytes(
+		bytes.NewReader(req.FundedPsbt), false,
+	)
+	if err != nil {
+		return nil, err
+	}
+	m.lastPsbt = packet
+
+	prevOutFetcher := txscript.NewMultiPrevOutFetcher(nil)
+	for idx, txIn := range packet.UnsignedTx.TxIn {
+		prevOutFetcher.AddPrevOut(
+			txIn.PreviousOutPoint, packet.Inputs[idx].WitnessUtxo,
+		)
+	}
+	sigHashes := txscript.NewTxSigHashes(
+		packet.UnsignedTx, prevOutFetcher,
+	)
+
+	for idx := range packet.Inputs {
+		in := &packet.Inputs[idx]
+		if len(in.Bip32Derivation) != 1 {
+			continue
+		}
+
+		sig, err := txscript.RawTxInWitnessSignature(
+			packet.UnsignedTx, sigHashes, idx,
+			in.WitnessUtxo.Value, in.WitnessUtxo.PkScript,
+			in.SighashType, m.privKey,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		in.PartialSigs = append(in.PartialSigs, &psbt.PartialSig{
+			PubKey:    in.Bip32Derivation[0].PubKey,
+			Signature: sig,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := packet.Serialize(&buf); err != nil {
+		return nil, err
+	}
+
+	return &walletrpc.SignPsbtResponse{SignedPsbt: buf.Bytes()}, nil
+}
+
+// TestToRPCSignDesc makes sure a sign descriptor is converted to its RPC
+// representation without losing any information the remote signer needs.
+func TestToRPCSignDesc(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	tweakKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	signDesc := &input.SignDescriptor{
+		KeyDesc: keychain.KeyDescriptor{
+			KeyLocator: keychain.KeyLocator{
+				Family: keychain.KeyFamilyRevocationBase,
+				Index:  7,
+			},
+			PubKey: privKey.PubKey(),
+		},
+		DoubleTweak:   tweakKey,
+		WitnessScript: []byte{0x01, 0x02},
+		Output: &wire.TxOut{
+			Value:    1234,
+			PkScript: []byte{0x03, 0x04},
+		},
+		HashType:   txscript.SigHashAll,
+		InputIndex: 2,
+	}
+
+	rpcDesc, err := toRPCSignDesc(signDesc)
+	require.NoError(t, err)
+
+	require.Equal(
+		t, int32(keychain.KeyFamilyRevocationBase),
+		rpcDesc.KeyDesc.KeyLoc.KeyFamily,
+	)
+	require.Equal(t, int32(7), rpcDesc.KeyDesc.KeyLoc.KeyIndex)
+	require.Equal(
+		t, privKey.PubKey().SerializeCompressed(),
+		rpcDesc.KeyDesc.RawKeyBytes,
+	)
+	require.Equal(t, tweakKey.Serialize(), rpcDesc.DoubleTweak)
+	require.Nil(t, rpcDesc.SingleTweak)
+	require.Equal(t, signDesc.WitnessScript, rpcDesc.WitnessScript)
+	require.Equal(t, int64(1234), rpcDesc.Output.Value)
+	require.Equal(t, signDesc.Output.PkScript, rpcDesc.Output.PkScript)
+	require.Equal(t, uint32(txscript.SigHashAll), rpcDesc.Sighash)
+	require.Equal(t, int32(2), rpcDesc.InputIndex)
+
+	// A sign descriptor without an output can't be signed remotely.
+	signDesc.Output = nil
+	_, err = toRPCSignDesc(signDesc)
+	require.Error(t, err)
+}
+
+// TestComputeInputScript makes sure inputs of the watch-only wallet are signed
+// by the remote signer and result in a valid witness.
+func TestComputeInputScript(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	address, err := btcutil.NewAddressWitnessPubKeyHash(
+		btcutil.Hash160(privKey.PubKey().SerializeCompressed()),
+		&chaincfg.RegressionNetParams,
+	)
+	require.NoError(t, err)
+	pkScript, err := txscript.PayToAddrScript(address)
+	require.NoError(t, err)
+
+	addr := &mockAddress{
+		pubKey:  privKey.PubKey(),
+		address: address,
+		scope:   waddrmgr.KeyScopeBIP0084,
+		path: waddrmgr.DerivationPath{
+			Account: 2,
+			Branch:  1,
+			Index:   5,
+		},
+	}
+	signer := &mockRemoteSigner{privKey: privKey}
+	keyRing := &RPCKeyRing{
+		WalletController: &mockWatchOnlyWallet{addr: addr},
+		rpcTimeout:       time.Second,
+		walletClient:     signer,
+	}
+
+	prevOut := &wire.TxOut{Value: 100_000, PkScript: pkScript}
+	tx := wire.NewMsgTx(2)
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: 1},
+	})
+	tx.AddTxOut(&wire.TxOut{Value: 90_000, PkScript: pkScript})
+
+	prevOutFetcher := txscript.NewCannedPrevOutputFetcher(
+		prevOut.PkScript, prevOut.Value,
+	)
+	signDesc := &input.SignDescriptor{
+		Output:            prevOut,
+		HashType:          txscript.SigHashAll,
+		InputIndex:        0,
+		PrevOutputFetcher: prevOutFetcher,
+	}
+
+	script, err := keyRing.ComputeInputScript(tx, signDesc)
+	require.NoError(t, err)
+
+	// The remote signer must have been told which key to use.
+	derivations := signer.lastPsbt.Inputs[0].Bip32Derivation
+	require.Len(t, derivations, 1)
+	require.Equal(t, []uint32{
+		84 + hdkeychain.HardenedKeyStart,
+		0 + hdkeychain.HardenedKeyStart,
+		2 + hdkeychain.HardenedKeyStart, 1, 5,
+	}, derivations[0].Bip32Path)
+
+	// The resulting witness must be valid for the spent output.
+	tx.TxIn[0].Witness = script.Witness
+	tx.TxIn[0].SignatureScript = script.SigScript
+	vm, err := txscript.NewEngine(
+		pkScript, tx, 0, txscript.StandardVerifyFlags, nil,
+		txscript.NewTxSigHashes(tx, prevOutFetcher), prevOut.Value,
+		prevOutFetcher,
+	)
+	require.NoError(t, err)
+	require.NoError(t, vm.Execute())
+
+	// Tweaked keys can't be derived by the remote signer from the path
+	// alone.
+	signDesc.SingleTweak = []byte{0x01}
+	_, err = keyRing.ComputeInputScript(tx, signDesc)
+	require.Error(t, err)
+}
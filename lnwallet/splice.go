@@ -0,0 +1,705 @@
+package lnwallet
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+var (
+	// ErrPendingUpdates is returned when a splice is attempted while
+	// either party has updates that haven't been irrevocably committed to
+	// by both parties.
+	ErrPendingUpdates = fmt.Errorf("channel has pending updates")
+
+	// ErrMissingSpliceSig is returned when the remote party doesn't sign
+	// the variant of a new commitment that spends the funding output of
+	// the pending splice.
+	ErrMissingSpliceSig = fmt.Errorf("commitment of pending splice not " +
+		"signed")
+)
+
+// MaxSpliceHTLCs is the maximum number of HTLCs that can be active on a
+// commitment while a splice is pending. The signatures for both variants of
+// a commitment are sent within a single commitment_signed message, which
+// limits their total number.
+const MaxSpliceHTLCs = input.MaxHTLCNumber / 2
+
+// hasPendingUpdates returns true if either party has updates or commitments
+// that haven't been irrevocably committed to by both parties.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) hasPendingUpdates() bool {
+	return lc.localCommitChain.hasUnackedCommitment() ||
+		lc.remoteCommitChain.hasUnackedCommitment() ||
+		lc.oweCommitment(true) || lc.oweCommitment(false)
+}
+
+// FundingTxOut returns the funding output of the channel for the given
+// capacity. As the funding keys of the channel are reused when the channel is
+// spliced, this can be used to create the funding output of a splice
+// transaction.
+func (lc *LightningChannel) FundingTxOut(capacity btcutil.Amount) *wire.TxOut {
+	return &wire.TxOut{
+		PkScript: lc.signDesc.Output.PkScript,
+		Value:    int64(capacity),
+	}
+}
+
+// NewPendingSplice validates the passed splice transaction and creates the
+// commitments of both parties that spend the new funding output created by
+// it. The localDelta and remoteDelta are the amounts added to or removed from
+// the balance of the local and remote party respectively. The commitments are
+// variants of the current commitments that carry the same HTLCs, and are
+// unsigned.
+func (lc *LightningChannel) NewPendingSplice(spliceTx *wire.MsgTx,
+	localDelta, remoteDelta btcutil.Amount) (*channeldb.PendingSplice,
+	error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	if lc.pendingSplice != nil {
+		return nil, fmt.Errorf("channel already has a pending splice")
+	}
+
+	// The commitments of both parties must be settled, so there's exactly
+	// one commitment per party that needs a variant to start with.
+	if lc.hasPendingUpdates() {
+		return nil, ErrPendingUpdates
+	}
+
+	// The splice transaction must spend the current funding output of the
+	// channel.
+	if _, err := lc.spliceInputIndex(spliceTx); err != nil {
+		return nil, err
+	}
+
+	// It must also create exactly one new funding output, carrying the
+	// current capacity adjusted by the contributions of both parties.
+	chanState := lc.channelState
+	capacity := chanState.Capacity + localDelta + remoteDelta
+	fundingPkScript := lc.signDesc.Output.PkScript
+
+	outputIndex := -1
+	for i, txOut := range spliceTx.TxOut {
+		if !bytes.Equal(txOut.PkScript, fundingPkScript) {
+			continue
+		}
+
+		if outputIndex != -1 {
+			return nil, fmt.Errorf("splice transaction has " +
+				"multiple funding outputs")
+		}
+		outputIndex = i
+	}
+	if outputIndex == -1 {
+		return nil, fmt.Errorf("splice transaction has no funding " +
+			"output")
+	}
+
+	fundingValue := btcutil.Amount(spliceTx.TxOut[outputIndex].Value)
+	if fundingValue != capacity {
+		return nil, fmt.Errorf("splice funding output has value %v, "+
+			"expected %v", fundingValue, capacity)
+	}
+
+	// Only inputs that commit to their witness can be present, otherwise
+	// the txid of the splice transaction could be malleated, which would
+	// invalidate the commitments we're about to sign.
+	for _, txIn := range spliceTx.TxIn {
+		if len(txIn.SignatureScript) != 0 {
+			return nil, fmt.Errorf("splice transaction has " +
+				"non-segwit input")
+		}
+	}
+
+	splice := &channeldb.PendingSplice{
+		SpliceTx: spliceTx,
+		FundingOutpoint: wire.OutPoint{
+			Hash:  spliceTx.TxHash(),
+			Index: uint32(outputIndex),
+		},
+		Capacity:    capacity,
+		LocalDelta:  localDelta,
+		RemoteDelta: remoteDelta,
+	}
+
+	localCommit, _, err := lc.tailSpliceCommitment(splice, true)
+	if err != nil {
+		return nil, err
+	}
+	remoteCommit, _, err := lc.tailSpliceCommitment(splice, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// A party that removes funds from the channel must remain above its
+	// channel reserve on both commitments.
+	ourReserve := lnwire.NewMSatFromSatoshis(
+		chanState.LocalChanCfg.ChanReserve,
+	)
+	theirReserve := lnwire.NewMSatFromSatoshis(
+		chanState.RemoteChanCfg.ChanReserve,
+	)
+	for _, c := range []*commitment{localCommit, remoteCommit} {
+		if localDelta < 0 && c.ourBalance < ourReserve {
+			return nil, ErrBelowChanReserve
+		}
+		if remoteDelta < 0 && c.theirBalance < theirReserve {
+			return nil, ErrBelowChanReserve
+		}
+	}
+
+	splice.LocalCommitment = *localCommit.toDiskCommit(true)
+	splice.RemoteCommitment = *remoteCommit.toDiskCommit(false)
+
+	return splice, nil
+}
+
+// tailSpliceCommitment creates the variant of the current commitment of the
+// local or remote party that spends the funding output of the passed splice.
+// The key ring of the commitment is returned as well.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) tailSpliceCommitment(
+	splice *channeldb.PendingSplice, local bool) (*commitment,
+	*CommitmentKeyRing, error) {
+
+	chanState := lc.channelState
+
+	var keyRing *CommitmentKeyRing
+	commit := lc.localCommitChain.tail()
+	if local {
+		commitSecret, err := chanState.RevocationProducer.AtIndex(
+			commit.height,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyRing = DeriveCommitmentKeys(
+			input.ComputeCommitmentPoint(commitSecret[:]), true,
+			chanState.ChanType, &chanState.LocalChanCfg,
+			&chanState.RemoteChanCfg,
+		)
+	} else {
+		commit = lc.remoteCommitChain.tail()
+		keyRing = DeriveCommitmentKeys(
+			chanState.RemoteCurrentRevocation, false,
+			chanState.ChanType, &chanState.LocalChanCfg,
+			&chanState.RemoteChanCfg,
+		)
+	}
+
+	spliceCommit, err := lc.spliceCommitment(commit, keyRing, splice)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return spliceCommit, keyRing, nil
+}
+
+// spliceCommitment creates the variant of the passed commitment that spends
+// the funding output of the passed splice. It carries the same HTLCs and fee
+// rate as the commitment, while the balances of both parties are adjusted by
+// the splice.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) spliceCommitment(c *commitment,
+	keyRing *CommitmentKeyRing,
+	splice *channeldb.PendingSplice) (*commitment, error) {
+
+	chanState := lc.channelState
+
+	// The balances on the commitment have the commitment fee subtracted
+	// from the initiator's balance, so we'll add it back before applying
+	// the deltas.
+	ourBalance := int64(c.ourBalance)
+	theirBalance := int64(c.theirBalance)
+	commitFee := int64(lnwire.NewMSatFromSatoshis(c.fee))
+	if chanState.IsInitiator {
+		ourBalance += commitFee
+	} else {
+		theirBalance += commitFee
+	}
+	ourBalance += int64(lnwire.NewMSatFromSatoshis(splice.LocalDelta))
+	theirBalance += int64(lnwire.NewMSatFromSatoshis(splice.RemoteDelta))
+
+	if ourBalance < 0 || theirBalance < 0 {
+		return nil, fmt.Errorf("splice removes more funds than " +
+			"available")
+	}
+
+	spliceCommit := &commitment{
+		height:            c.height,
+		isOurs:            c.isOurs,
+		ourMessageIndex:   c.ourMessageIndex,
+		theirMessageIndex: c.theirMessageIndex,
+		ourHtlcIndex:      c.ourHtlcIndex,
+		theirHtlcIndex:    c.theirHtlcIndex,
+		feePerKw:          c.feePerKw,
+		dustLimit:         c.dustLimit,
+	}
+
+	// The HTLCs are copied, so locating them on the variant doesn't
+	// affect the output indexes recorded for the commitment itself.
+	spliceCommit.outgoingHTLCs = make([]PaymentDescriptor, len(c.outgoingHTLCs))
+	copy(spliceCommit.outgoingHTLCs, c.outgoingHTLCs)
+	spliceCommit.incomingHTLCs = make([]PaymentDescriptor, len(c.incomingHTLCs))
+	copy(spliceCommit.incomingHTLCs, c.incomingHTLCs)
+
+	view := &htlcView{feePerKw: c.feePerKw}
+	for i := range spliceCommit.outgoingHTLCs {
+		view.ourUpdates = append(
+			view.ourUpdates, &spliceCommit.outgoingHTLCs[i],
+		)
+	}
+	for i := range spliceCommit.incomingHTLCs {
+		view.theirUpdates = append(
+			view.theirUpdates, &spliceCommit.incomingHTLCs[i],
+		)
+	}
+
+	// We'll use a copy of the channel state that's funded by the new
+	// funding output to build the variant.
+	spliceState := &channeldb.OpenChannel{
+		ChanType:        chanState.ChanType,
+		IsInitiator:     chanState.IsInitiator,
+		FundingOutpoint: splice.FundingOutpoint,
+		Capacity:        splice.Capacity,
+		LocalChanCfg:    chanState.LocalChanCfg,
+		RemoteChanCfg:   chanState.RemoteChanCfg,
+	}
+	builder := NewCommitmentBuilder(spliceState)
+
+	unsignedCommit, err := builder.createUnsignedCommitmentTx(
+		lnwire.MilliSatoshi(ourBalance),
+		lnwire.MilliSatoshi(theirBalance), c.isOurs, c.feePerKw,
+		c.height, view, keyRing,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	spliceCommit.txn = unsignedCommit.txn
+	spliceCommit.ourBalance = unsignedCommit.ourBalance
+	spliceCommit.theirBalance = unsignedCommit.theirBalance
+	spliceCommit.fee = unsignedCommit.fee
+
+	err = spliceCommit.populateHtlcIndexes(
+		chanState.ChanType, unsignedCommit.cltvs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return spliceCommit, nil
+}
+
+// signSpliceCommitment signs the passed variant of a commitment of the remote
+// party, along with all of its HTLCs.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) signSpliceCommitment(spliceCommit *commitment,
+	keyRing *CommitmentKeyRing, capacity btcutil.Amount) (lnwire.Sig,
+	[]lnwire.Sig, error) {
+
+	chanState := lc.channelState
+	sigBatch, cancelChan, err := genRemoteHtlcSigJobs(
+		keyRing, chanState.ChanType, &chanState.LocalChanCfg,
+		&chanState.RemoteChanCfg, spliceCommit,
+	)
+	if err != nil {
+		return lnwire.Sig{}, nil, err
+	}
+	lc.sigPool.SubmitSignBatch(sigBatch)
+
+	signDesc := *lc.signDesc
+	signDesc.Output = lc.FundingTxOut(capacity)
+	signDesc.SigHashes = input.NewTxSigHashesV0Only(spliceCommit.txn)
+
+	rawSig, err := lc.Signer.SignOutputRaw(spliceCommit.txn, &signDesc)
+	if err != nil {
+		close(cancelChan)
+		return lnwire.Sig{}, nil, err
+	}
+	sig, err := lnwire.NewSigFromSignature(rawSig)
+	if err != nil {
+		close(cancelChan)
+		return lnwire.Sig{}, nil, err
+	}
+
+	sort.Slice(sigBatch, func(i, j int) bool {
+		return sigBatch[i].OutputIndex < sigBatch[j].OutputIndex
+	})
+
+	var htlcSigs []lnwire.Sig
+	for _, htlcSigJob := range sigBatch {
+		jobResp := <-htlcSigJob.Resp
+		if jobResp.Err != nil {
+			close(cancelChan)
+			return lnwire.Sig{}, nil, jobResp.Err
+		}
+
+		htlcSigs = append(htlcSigs, jobResp.Sig)
+	}
+
+	return sig, htlcSigs, nil
+}
+
+// verifySpliceCommitment verifies the signatures of the remote party for the
+// passed variant of one of our commitments. If they're valid, they're stored
+// within the variant.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) verifySpliceCommitment(spliceCommit *commitment,
+	keyRing *CommitmentKeyRing, capacity btcutil.Amount,
+	commitSig lnwire.Sig, htlcSigs []lnwire.Sig) error {
+
+	chanState := lc.channelState
+	verifyJobs, err := genHtlcSigValidationJobs(
+		spliceCommit, keyRing, htlcSigs, chanState.ChanType,
+		&chanState.LocalChanCfg, &chanState.RemoteChanCfg,
+	)
+	if err != nil {
+		return err
+	}
+
+	cancelChan := make(chan struct{})
+	verifyResps := lc.sigPool.SubmitVerifyBatch(verifyJobs, cancelChan)
+
+	commitTx := spliceCommit.txn
+	sigHash, err := txscript.CalcWitnessSigHash(
+		lc.signDesc.WitnessScript, input.NewTxSigHashesV0Only(commitTx),
+		txscript.SigHashAll, commitTx, 0, int64(capacity),
+	)
+	if err != nil {
+		close(cancelChan)
+		return err
+	}
+
+	cSig, err := commitSig.ToSignature()
+	if err != nil {
+		close(cancelChan)
+		return err
+	}
+	verifyKey := chanState.RemoteChanCfg.MultiSigKey.PubKey
+	if !cSig.Verify(sigHash, verifyKey) {
+		close(cancelChan)
+		return fmt.Errorf("invalid splice commitment signature at "+
+			"height %v for ChannelPoint(%v)", spliceCommit.height,
+			chanState.FundingOutpoint)
+	}
+
+	for i := 0; i < len(verifyJobs); i++ {
+		if htlcErr := <-verifyResps; htlcErr != nil {
+			close(cancelChan)
+			return fmt.Errorf("invalid splice htlc signature at "+
+				"height %v for ChannelPoint(%v): %v",
+				spliceCommit.height, chanState.FundingOutpoint,
+				htlcErr)
+		}
+	}
+
+	spliceCommit.sig = commitSig.ToSignatureBytes()
+
+	return nil
+}
+
+// SignSpliceCommitment signs the remote party's commitment of the passed
+// splice, returning the signature for the commitment and its HTLCs. The
+// signed commitment is also stored within the pending splice.
+func (lc *LightningChannel) SignSpliceCommitment(
+	splice *channeldb.PendingSplice) (lnwire.Sig, []lnwire.Sig, error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	remoteCommit, keyRing, err := lc.tailSpliceCommitment(splice, false)
+	if err != nil {
+		return lnwire.Sig{}, nil, err
+	}
+
+	sig, htlcSigs, err := lc.signSpliceCommitment(
+		remoteCommit, keyRing, splice.Capacity,
+	)
+	if err != nil {
+		return lnwire.Sig{}, nil, err
+	}
+
+	splice.RemoteCommitment = *remoteCommit.toDiskCommit(false)
+
+	return sig, htlcSigs, nil
+}
+
+// ReceiveSpliceCommitSig verifies the remote party's signatures for our
+// commitment of the passed splice. If the signatures are valid, the signed
+// commitment is stored within the pending splice.
+func (lc *LightningChannel) ReceiveSpliceCommitSig(
+	splice *channeldb.PendingSplice, commitSig lnwire.Sig,
+	htlcSigs []lnwire.Sig) error {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	localCommit, keyRing, err := lc.tailSpliceCommitment(splice, true)
+	if err != nil {
+		return err
+	}
+
+	err = lc.verifySpliceCommitment(
+		localCommit, keyRing, splice.Capacity, commitSig, htlcSigs,
+	)
+	if err != nil {
+		return err
+	}
+
+	splice.LocalCommitment = *localCommit.toDiskCommit(true)
+
+	return nil
+}
+
+// MarkSplicePending persists the passed splice once both of its commitments
+// have been signed. From then on, every new commitment is signed for both
+// the current funding output and the one created by the splice transaction,
+// until the splice is completed.
+func (lc *LightningChannel) MarkSplicePending(
+	splice *channeldb.PendingSplice) error {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	if err := lc.channelState.MarkSplicePending(splice); err != nil {
+		return err
+	}
+	lc.pendingSplice = splice
+
+	return nil
+}
+
+// MarkSpliceLocked records that the splice transaction of the pending splice
+// has confirmed. This must be called before our splice_locked message is
+// sent, as the remote party may complete the splice as soon as it receives
+// it.
+func (lc *LightningChannel) MarkSpliceLocked() error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	if lc.pendingSplice == nil {
+		return channeldb.ErrNoPendingSplice
+	}
+
+	if err := lc.channelState.MarkSpliceLocked(); err != nil {
+		return err
+	}
+	lc.pendingSplice.Locked = true
+
+	return nil
+}
+
+// PendingSplice returns the pending splice of the channel, or nil if the
+// channel has none.
+func (lc *LightningChannel) PendingSplice() *channeldb.PendingSplice {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.pendingSplice
+}
+
+// RemoteSpliceCommitSig returns the signatures for the variant of the latest
+// commitment of the remote party that spends the funding output of the
+// pending splice. If the commitment was signed without a pending splice, nil
+// is returned.
+func (lc *LightningChannel) RemoteSpliceCommitSig() *lnwire.SpliceCommitSig {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.remoteCommitChain.tip().spliceSig
+}
+
+// CompleteSplice switches the channel over to the funding output created by
+// the pending splice. The variants of the commitments that spend it replace
+// the commitments of both parties. If the channel has no pending splice,
+// channeldb.ErrNoPendingSplice is returned.
+func (lc *LightningChannel) CompleteSplice() error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	return lc.completeSplice()
+}
+
+// completeSplice is the internal version of CompleteSplice.
+//
+// NOTE: This method requires the channel lock to be held.
+func (lc *LightningChannel) completeSplice() error {
+	if lc.pendingSplice == nil {
+		return channeldb.ErrNoPendingSplice
+	}
+
+	// If we've received a new commitment that we haven't revoked the
+	// current one for yet, its variant replaces it.
+	var localTip *commitment
+	if lc.localCommitChain.hasUnackedCommitment() {
+		localTip = lc.spliceLocalTip
+		if localTip == nil ||
+			localTip.height != lc.localCommitChain.tip().height {
+
+			return fmt.Errorf("pending local commitment has no " +
+				"splice variant")
+		}
+	}
+
+	chanState := lc.channelState
+	if err := chanState.CompleteSplice(); err != nil {
+		return err
+	}
+
+	// The commitments of both parties have been replaced on disk, so
+	// we'll rebuild both commitment chains from there.
+
+	commitSecret, err := chanState.RevocationProducer.AtIndex(
+		lc.currentHeight,
+	)
+	if err != nil {
+		return err
+	}
+	localCommitPoint := input.ComputeCommitmentPoint(commitSecret[:])
+	remoteCommitPoint := chanState.RemoteCurrentRevocation
+
+	localCommit, err := lc.diskCommitToMemCommit(
+		true, &chanState.LocalCommitment, localCommitPoint,
+		remoteCommitPoint,
+	)
+	if err != nil {
+		return err
+	}
+	remoteCommit, err := lc.diskCommitToMemCommit(
+		false, &chanState.RemoteCommitment, localCommitPoint,
+		remoteCommitPoint,
+	)
+	if err != nil {
+		return err
+	}
+
+	localCommitChain := newCommitmentChain()
+	localCommitChain.addCommitment(localCommit)
+	if localTip != nil {
+		localCommitChain.addCommitment(localTip)
+	}
+
+	remoteCommitChain := newCommitmentChain()
+	remoteCommitChain.addCommitment(remoteCommit)
+	if lc.remoteCommitChain.hasUnackedCommitment() {
+		commitDiff, err := chanState.RemoteCommitChainTip()
+		if err != nil {
+			return err
+		}
+
+		remoteTip, err := lc.diskCommitToMemCommit(
+			false, &commitDiff.Commitment, nil,
+			chanState.RemoteNextRevocation,
+		)
+		if err != nil {
+			return err
+		}
+		remoteCommitChain.addCommitment(remoteTip)
+	}
+
+	lc.localCommitChain = localCommitChain
+	lc.remoteCommitChain = remoteCommitChain
+	lc.signDesc.Output = lc.FundingTxOut(chanState.Capacity)
+	lc.Capacity = chanState.Capacity
+	lc.pendingSplice = nil
+	lc.spliceLocalTip = nil
+
+	lc.log.Infof("splice completed, now funded by %v",
+		chanState.ActiveFundingOutpoint())
+
+	return nil
+}
+
+// SignSpliceInput signs the input of the passed splice transaction that
+// spends the current funding output of the channel.
+func (lc *LightningChannel) SignSpliceInput(
+	spliceTx *wire.MsgTx) (input.Signature, error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	inputIndex, err := lc.spliceInputIndex(spliceTx)
+	if err != nil {
+		return nil, err
+	}
+
+	signDesc := *lc.signDesc
+	signDesc.InputIndex = inputIndex
+	signDesc.SigHashes = input.NewTxSigHashesV0Only(spliceTx)
+
+	return lc.Signer.SignOutputRaw(spliceTx, &signDesc)
+}
+
+// AddSpliceInputWitness populates the witness of the input of the passed
+// splice transaction that spends the current funding output of the channel.
+// The resulting witness is validated to ensure the remote party supplied a
+// valid signature.
+//
+// NOTE: The passed local and remote sigs are expected to be fully complete
+// signatures including the proper sighash byte.
+func (lc *LightningChannel) AddSpliceInputWitness(spliceTx *wire.MsgTx,
+	localSig, remoteSig input.Signature) error {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	inputIndex, err := lc.spliceInputIndex(spliceTx)
+	if err != nil {
+		return err
+	}
+
+	ourKey := lc.channelState.LocalChanCfg.MultiSigKey.PubKey.
+		SerializeCompressed()
+	theirKey := lc.channelState.RemoteChanCfg.MultiSigKey.PubKey.
+		SerializeCompressed()
+	spliceTx.TxIn[inputIndex].Witness = input.SpendMultiSig(
+		lc.signDesc.WitnessScript, ourKey, localSig, theirKey,
+		remoteSig,
+	)
+
+	prevOut := lc.signDesc.Output
+	vm, err := txscript.NewEngine(
+		prevOut.PkScript, spliceTx, inputIndex,
+		txscript.StandardVerifyFlags, nil,
+		input.NewTxSigHashesV0Only(spliceTx), prevOut.Value,
+		txscript.NewCannedPrevOutputFetcher(
+			prevOut.PkScript, prevOut.Value,
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	return vm.Execute()
+}
+
+// spliceInputIndex returns the index of the input of the splice transaction
+// that spends the current funding output of the channel.
+func (lc *LightningChannel) spliceInputIndex(spliceTx *wire.MsgTx) (int,
+	error) {
+
+	fundingPoint := lc.channelState.ActiveFundingOutpoint()
+	for i, txIn := range spliceTx.TxIn {
+		if txIn.PreviousOutPoint == fundingPoint {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("splice transaction doesn't spend funding "+
+		"output %v", fundingPoint)
+}
@@ -17,8 +17,9 @@ import (
 	"testing"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/integration/rpctest"
@@ -26,7 +27,6 @@ import (
 	"github.com/btcsuite/btcd/rpcclient"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/btcsuite/btcwallet/chain"
 	"github.com/btcsuite/btcwallet/walletdb"
 	_ "github.com/btcsuite/btcwallet/walletdb/bdb"
@@ -81,8 +81,8 @@ var (
 	netParams = &chaincfg.RegressionNetParams
 	chainHash = netParams.GenesisHash
 
-	_, alicePub = btcec.PrivKeyFromBytes(btcec.S256(), testHdSeed[:])
-	_, bobPub   = btcec.PrivKeyFromBytes(btcec.S256(), bobsPrivKey)
+	_, alicePub = btcec.PrivKeyFromBytes(testHdSeed[:])
+	_, bobPub   = btcec.PrivKeyFromBytes(bobsPrivKey)
 
 	// The number of confirmations required to consider any created channel
 	// open.
@@ -1699,7 +1699,7 @@ func txFromOutput(tx *wire.MsgTx, signer input.Signer, fromPubKey,
 		WitnessScript: keyScript,
 		Output:        tx.TxOut[outputIndex],
 		HashType:      txscript.SigHashAll,
-		SigHashes:     txscript.NewTxSigHashes(tx1),
+		SigHashes:     input.NewTxSigHashesV0Only(tx1),
 		InputIndex:    0, // Has only one input.
 	}
 
@@ -1720,6 +1720,9 @@ func txFromOutput(tx *wire.MsgTx, signer input.Signer, fromPubKey,
 	vm, err := txscript.NewEngine(
 		keyScript, tx1, 0, txscript.StandardVerifyFlags, nil,
 		nil, outputValue,
+		txscript.NewCannedPrevOutputFetcher(
+			keyScript, outputValue,
+		),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create engine: %v", err)
@@ -2000,8 +2003,7 @@ func testSignOutputUsingTweaks(r *rpctest.Harness,
 	// we'll generate a commitment pre-image, then derive a revocation key
 	// and single tweak from that.
 	commitPreimage := bytes.Repeat([]byte{2}, 32)
-	commitSecret, commitPoint := btcec.PrivKeyFromBytes(btcec.S256(),
-		commitPreimage)
+	commitSecret, commitPoint := btcec.PrivKeyFromBytes(commitPreimage)
 
 	revocationKey := input.DeriveRevocationPubkey(pubKey.PubKey, commitPoint)
 	commitTweak := input.SingleTweakBytes(commitPoint, pubKey.PubKey)
@@ -2085,7 +2087,7 @@ func testSignOutputUsingTweaks(r *rpctest.Harness,
 			WitnessScript: keyScript,
 			Output:        newOutput,
 			HashType:      txscript.SigHashAll,
-			SigHashes:     txscript.NewTxSigHashes(sweepTx),
+			SigHashes:     input.NewTxSigHashesV0Only(sweepTx),
 			InputIndex:    0,
 		}
 
@@ -2114,7 +2116,10 @@ func testSignOutputUsingTweaks(r *rpctest.Harness,
 		// the proper private key.
 		vm, err := txscript.NewEngine(keyScript,
 			sweepTx, 0, txscript.StandardVerifyFlags, nil,
-			nil, int64(btcutil.SatoshiPerBitcoin))
+			nil, int64(btcutil.SatoshiPerBitcoin),
+			txscript.NewCannedPrevOutputFetcher(
+				keyScript, int64(btcutil.SatoshiPerBitcoin),
+			))
 		if err != nil {
 			t.Fatalf("unable to create engine: %v", err)
 		}
@@ -2822,7 +2827,7 @@ func testSignOutputCreateAccount(r *rpctest.Harness, w *lnwallet.LightningWallet
 			Value: 1000,
 		},
 		HashType:   txscript.SigHashAll,
-		SigHashes:  txscript.NewTxSigHashes(fakeTx),
+		SigHashes:  input.NewTxSigHashesV0Only(fakeTx),
 		InputIndex: 0,
 	}
 
@@ -3387,13 +3392,15 @@ func runTests(t *testing.T, walletDriver *lnwallet.WalletDriver,
 			var chainConn *chain.BitcoindConn
 			err = wait.NoError(func() error {
 				chainConn, err = chain.NewBitcoindConn(&chain.BitcoindConfig{
-					ChainParams:     netParams,
-					Host:            host,
-					User:            "weks",
-					Pass:            "weks",
-					ZMQBlockHost:    zmqBlockHost,
-					ZMQTxHost:       zmqTxHost,
-					ZMQReadDeadline: 5 * time.Second,
+					ChainParams: netParams,
+					Host:        host,
+					User:        "weks",
+					Pass:        "weks",
+					ZMQConfig: &chain.ZMQConfig{
+						ZMQBlockHost:    zmqBlockHost,
+						ZMQTxHost:       zmqTxHost,
+						ZMQReadDeadline: 5 * time.Second,
+					},
 					// Fields only required for pruned nodes, not
 					// needed for these tests.
 					Dialer:             nil,
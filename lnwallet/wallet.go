@@ -11,14 +11,14 @@ import (
 	"sync/atomic"
 
 	"github.com/btcsuite/btcd/blockchain"
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
+	"github.com/btcsuite/btcd/btcutil/txsort"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
-	"github.com/btcsuite/btcutil/psbt"
-	"github.com/btcsuite/btcutil/txsort"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/input"
@@ -153,6 +153,23 @@ type InitFundingReserveMsg struct {
 	// used.
 	ChanFunder chanfunding.Assembler
 
+	// ZeroConf is a boolean that is true if a zero-conf channel was
+	// negotiated.
+	ZeroConf bool
+
+	// OptionScidAlias is a boolean that is true if an option-scid-alias
+	// channel type was explicitly negotiated.
+	OptionScidAlias bool
+
+	// DualFundResponder is true if we're the responder to a dual funded
+	// channel and contribute LocalFundingAmt to it. The funding
+	// transaction will then be constructed interactively.
+	DualFundResponder bool
+
+	// Lease is set if we're the responder to a dual funded channel and
+	// lease out our contribution to the initiator.
+	Lease *channeldb.LeaseTerms
+
 	// err is a channel in which all errors will be sent across. Will be
 	// nil if this initial set is successful.
 	//
@@ -205,6 +222,31 @@ type continueContributionMsg struct {
 	err chan error
 }
 
+// addInteractiveContributionMsg carries the counterparty's contribution to a
+// dual funded channel whose funding transaction is constructed interactively.
+// Processing it only records the contribution and binds the multi-sig keys,
+// the funding transaction is passed in later via an addInteractiveTxMsg.
+type addInteractiveContributionMsg struct {
+	pendingFundingID uint64
+
+	contribution *ChannelContribution
+
+	// NOTE: In order to avoid deadlocks, this channel MUST be buffered.
+	err chan error
+}
+
+// addInteractiveTxMsg carries the funding transaction of a dual funded
+// channel once both parties agreed on it. Processing it signs our inputs to
+// the transaction and creates both commitment transactions.
+type addInteractiveTxMsg struct {
+	pendingFundingID uint64
+
+	fundingTx *wire.MsgTx
+
+	// NOTE: In order to avoid deadlocks, this channel MUST be buffered.
+	err chan error
+}
+
 // addSingleContributionMsg represents a message executing the second phase of
 // a single funder channel reservation workflow. This messages carries the
 // counterparty's "contribution" to the payment channel. As this message is
@@ -490,6 +532,10 @@ out:
 				l.handleContributionMsg(msg)
 			case *continueContributionMsg:
 				l.handleChanPointReady(msg)
+			case *addInteractiveContributionMsg:
+				l.handleInteractiveContribution(msg)
+			case *addInteractiveTxMsg:
+				l.handleInteractiveTx(msg)
 			case *addSingleFunderSigsMsg:
 				l.handleSingleFunderSigs(msg)
 			case *addCounterPartySigsMsg:
@@ -824,6 +870,32 @@ func (l *LightningWallet) handleFundingReserveRequest(req *InitFundingReserveMsg
 		return
 	}
 
+	// Record the zero-conf and option-scid-alias properties of the channel
+	// in its channel type so they are persisted along with the channel.
+	if req.ZeroConf {
+		reservation.partialState.ChanType |= channeldb.ZeroConfBit
+	}
+	if req.OptionScidAlias {
+		reservation.partialState.ChanType |= channeldb.ScidAliasChanBit
+	}
+
+	// If we're the responder to a dual funded channel, the balances are
+	// split according to each party's contribution, with the initiator
+	// paying for the commitment fees.
+	if req.DualFundResponder {
+		reservation.interactive = true
+		err := reservation.setDualFundedBalances(
+			localFundingAmt, remoteFundingAmt, false, req.Lease,
+		)
+		if err != nil {
+			fundingIntent.Cancel()
+
+			req.err <- err
+			req.resp <- nil
+			return
+		}
+	}
+
 	err = l.initOurContribution(
 		reservation, fundingIntent, req.NodeAddr, req.NodeID, keyRing,
 	)
@@ -1470,12 +1542,23 @@ func (l *LightningWallet) handleChanPointReady(req *continueContributionMsg) {
 	// obfuscator then use it to encode the current state number within
 	// both commitment transactions.
 	var stateObfuscator [StateHintSize]byte
-	if chanState.ChanType.IsSingleFunder() {
+	switch {
+	case chanState.ChanType.IsSingleFunder() || chanState.IsInitiator:
 		stateObfuscator = DeriveStateHintObfuscator(
 			ourContribution.PaymentBasePoint.PubKey,
 			theirContribution.PaymentBasePoint.PubKey,
 		)
-	} else {
+
+	// If we're the responder of an interactively funded channel, the
+	// obfuscator is derived with the initiator's key first, like it is
+	// done for single funded channels.
+	case pendingReservation.interactive:
+		stateObfuscator = DeriveStateHintObfuscator(
+			theirContribution.PaymentBasePoint.PubKey,
+			ourContribution.PaymentBasePoint.PubKey,
+		)
+
+	default:
 		ourSer := ourContribution.PaymentBasePoint.PubKey.SerializeCompressed()
 		theirSer := theirContribution.PaymentBasePoint.PubKey.SerializeCompressed()
 		switch bytes.Compare(ourSer, theirSer) {
@@ -1531,7 +1614,7 @@ func (l *LightningWallet) handleChanPointReady(req *continueContributionMsg) {
 		KeyDesc:       ourKey,
 		Output:        fundingOutput,
 		HashType:      txscript.SigHashAll,
-		SigHashes:     txscript.NewTxSigHashes(theirCommitTx),
+		SigHashes:     input.NewTxSigHashesV0Only(theirCommitTx),
 		InputIndex:    0,
 	}
 	sigTheirCommit, err := l.Cfg.Signer.SignOutputRaw(theirCommitTx, &signDesc)
@@ -1544,6 +1627,119 @@ func (l *LightningWallet) handleChanPointReady(req *continueContributionMsg) {
 	req.err <- nil
 }
 
+// handleInteractiveContribution records the remote party's contribution to a
+// dual funded channel whose funding transaction is constructed interactively.
+// Their multi-sig key is bound to our funding intent, so the funding output
+// is known once this method returns.
+func (l *LightningWallet) handleInteractiveContribution(
+	req *addInteractiveContributionMsg) {
+
+	l.limboMtx.Lock()
+	pendingReservation, ok := l.fundingLimbo[req.pendingFundingID]
+	l.limboMtx.Unlock()
+	if !ok {
+		req.err <- fmt.Errorf("attempted to update non-existent " +
+			"funding state")
+		return
+	}
+
+	// Grab the mutex on the ChannelReservation to ensure thread-safety
+	pendingReservation.Lock()
+	defer pendingReservation.Unlock()
+
+	// If UpfrontShutdownScript is set, validate that it is a valid script.
+	shutdown := req.contribution.UpfrontShutdown
+	if len(shutdown) > 0 {
+		if !validateUpfrontShutdown(shutdown, &l.Cfg.NetParams) {
+			req.err <- fmt.Errorf("invalid shutdown script")
+			return
+		}
+	}
+
+	intent := pendingReservation.fundingIntent
+	fundingIntent, ok := intent.(*chanfunding.FullIntent)
+	if !ok || !pendingReservation.interactive {
+		req.err <- fmt.Errorf("reservation isn't funded interactively")
+		return
+	}
+
+	// Keep the funding amount that was determined when the balances were
+	// set, it isn't part of the contribution sent by the remote party.
+	req.contribution.FundingAmount =
+		pendingReservation.theirContribution.FundingAmount
+	pendingReservation.theirContribution = req.contribution
+
+	fundingIntent.BindKeys(
+		&pendingReservation.ourContribution.MultiSigKey,
+		req.contribution.MultiSigKey.PubKey,
+	)
+
+	req.err <- nil
+}
+
+// handleInteractiveTx continues the funding flow of a dual funded channel
+// once the funding transaction has been constructed interactively. We sign
+// our own inputs to it and then create both commitment transactions.
+func (l *LightningWallet) handleInteractiveTx(req *addInteractiveTxMsg) {
+	l.limboMtx.Lock()
+	pendingReservation, ok := l.fundingLimbo[req.pendingFundingID]
+	l.limboMtx.Unlock()
+	if !ok {
+		req.err <- fmt.Errorf("attempted to update non-existent " +
+			"funding state")
+		return
+	}
+
+	// Grab the mutex on the ChannelReservation to ensure thread-safety
+	pendingReservation.Lock()
+	defer pendingReservation.Unlock()
+
+	intent := pendingReservation.fundingIntent
+	fundingIntent, ok := intent.(*chanfunding.FullIntent)
+	if !ok || !pendingReservation.interactive {
+		req.err <- fmt.Errorf("reservation isn't funded interactively")
+		return
+	}
+
+	fundingTx, err := fundingIntent.CompileInteractiveTx(req.fundingTx)
+	if err != nil {
+		req.err <- fmt.Errorf("unable to sign funding tx: %v", err)
+		return
+	}
+	chanPoint, err := fundingIntent.ChanPoint()
+	if err != nil {
+		req.err <- fmt.Errorf("unable to obtain chan point: %v", err)
+		return
+	}
+
+	pendingReservation.fundingTx = fundingTx
+	pendingReservation.partialState.FundingOutpoint = *chanPoint
+	pendingReservation.ourFundingInputScripts = make(
+		[]*input.Script, 0, len(fundingIntent.InputCoins),
+	)
+	for _, txIn := range fundingTx.TxIn {
+		if len(txIn.Witness) == 0 {
+			continue
+		}
+
+		pendingReservation.ourFundingInputScripts = append(
+			pendingReservation.ourFundingInputScripts,
+			&input.Script{
+				Witness:   txIn.Witness,
+				SigScript: txIn.SignatureScript,
+			},
+		)
+	}
+
+	walletLog.Debugf("Interactive funding tx for ChannelPoint(%v) "+
+		"signed: %v", chanPoint, spew.Sdump(fundingTx))
+
+	l.handleChanPointReady(&continueContributionMsg{
+		pendingFundingID: req.pendingFundingID,
+		err:              req.err,
+	})
+}
+
 // handleSingleContribution is called as the second step to a single funder
 // workflow to which we are the responder. It simply saves the remote peer's
 // contribution to the channel, as solely the remote peer will contribute any
@@ -1601,7 +1797,7 @@ func (l *LightningWallet) verifyFundingInputs(fundingTx *wire.MsgTx,
 	remoteInputScripts []*input.Script) error {
 
 	sigIndex := 0
-	fundingHashCache := txscript.NewTxSigHashes(fundingTx)
+	fundingHashCache := input.NewTxSigHashesV0Only(fundingTx)
 	inputScripts := remoteInputScripts
 	for i, txin := range fundingTx.TxIn {
 		if len(inputScripts) != 0 && len(txin.Witness) == 0 {
@@ -1638,6 +1834,9 @@ func (l *LightningWallet) verifyFundingInputs(fundingTx *wire.MsgTx,
 				output.PkScript, fundingTx, i,
 				txscript.StandardVerifyFlags, nil,
 				fundingHashCache, output.Value,
+				txscript.NewCannedPrevOutputFetcher(
+					output.PkScript, output.Value,
+				),
 			)
 			if err != nil {
 				return fmt.Errorf("cannot create script "+
@@ -1717,7 +1916,7 @@ func (l *LightningWallet) handleFundingCounterPartySigs(msg *addCounterPartySigs
 	// Next, create the spending scriptSig, and then verify that the script
 	// is complete, allowing us to spend from the funding transaction.
 	channelValue := int64(res.partialState.Capacity)
-	hashCache := txscript.NewTxSigHashes(commitTx)
+	hashCache := input.NewTxSigHashesV0Only(commitTx)
 	sigHash, err := txscript.CalcWitnessSigHash(
 		witnessScript, hashCache, txscript.SigHashAll, commitTx,
 		0, channelValue,
@@ -1863,7 +2062,7 @@ func (l *LightningWallet) handleSingleFunderSigs(req *addSingleFunderSigsMsg) {
 		req.fundingOutpoint, spew.Sdump(theirCommitTx))
 
 	channelValue := int64(pendingReservation.partialState.Capacity)
-	hashCache := txscript.NewTxSigHashes(ourCommitTx)
+	hashCache := input.NewTxSigHashesV0Only(ourCommitTx)
 	theirKey := pendingReservation.theirContribution.MultiSigKey
 	ourKey := pendingReservation.ourContribution.MultiSigKey
 	witnessScript, _, err := input.GenFundingPkScript(
@@ -1914,7 +2113,7 @@ func (l *LightningWallet) handleSingleFunderSigs(req *addSingleFunderSigsMsg) {
 			Value:    channelValue,
 		},
 		HashType:   txscript.SigHashAll,
-		SigHashes:  txscript.NewTxSigHashes(theirCommitTx),
+		SigHashes:  input.NewTxSigHashesV0Only(theirCommitTx),
 		InputIndex: 0,
 	}
 	sigTheirCommit, err := l.Cfg.Signer.SignOutputRaw(theirCommitTx, &signDesc)
@@ -1976,6 +2175,32 @@ func (l *LightningWallet) WithCoinSelectLock(f func() error) error {
 	return f()
 }
 
+// FetchTx returns the wallet transaction with the given hash. This is used to
+// obtain the full previous transactions of the inputs we contribute to an
+// interactively constructed funding transaction.
+func (l *LightningWallet) FetchTx(txid chainhash.Hash) (*wire.MsgTx, error) {
+	txDetails, err := l.ListTransactionDetails(0, -1, "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, txDetail := range txDetails {
+		if txDetail.Hash != txid {
+			continue
+		}
+
+		tx := &wire.MsgTx{}
+		err := tx.Deserialize(bytes.NewReader(txDetail.RawTx))
+		if err != nil {
+			return nil, err
+		}
+
+		return tx, nil
+	}
+
+	return nil, fmt.Errorf("transaction %v not found in wallet", txid)
+}
+
 // DeriveStateHintObfuscator derives the bytes to be used for obfuscating the
 // state hints from the root to be used for a new channel. The obfuscator is
 // generated via the following computation:
@@ -4,8 +4,8 @@ import (
 	"bytes"
 	"io"
 
-	"github.com/btcsuite/btcd/btcec"
-	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/lightningnetwork/lnd/tlv"
 )
 
@@ -99,6 +99,11 @@ type AcceptChannel struct {
 	// open.
 	ChannelType *ChannelType
 
+	// DualFundingAmount is the amount the responder contributes to the
+	// channel. It's only set if the initiator requested that the
+	// responder contributes funds to the channel.
+	DualFundingAmount *DualFundingAmount
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -125,6 +130,9 @@ func (a *AcceptChannel) Encode(w *bytes.Buffer, pver uint32) error {
 	if a.ChannelType != nil {
 		recordProducers = append(recordProducers, a.ChannelType)
 	}
+	if a.DualFundingAmount != nil {
+		recordProducers = append(recordProducers, a.DualFundingAmount)
+	}
 	err := EncodeMessageExtraData(&a.ExtraData, recordProducers...)
 	if err != nil {
 		return err
@@ -226,9 +234,12 @@ func (a *AcceptChannel) Decode(r io.Reader, pver uint32) error {
 
 	// Next we'll parse out the set of known records, keeping the raw tlv
 	// bytes untouched to ensure we don't drop any bytes erroneously.
-	var chanType ChannelType
+	var (
+		chanType       ChannelType
+		dualFundingAmt DualFundingAmount
+	)
 	typeMap, err := tlvRecords.ExtractRecords(
-		&a.UpfrontShutdownScript, &chanType,
+		&a.UpfrontShutdownScript, &chanType, &dualFundingAmt,
 	)
 	if err != nil {
 		return err
@@ -238,6 +249,9 @@ func (a *AcceptChannel) Decode(r io.Reader, pver uint32) error {
 	if val, ok := typeMap[ChannelTypeRecordType]; ok && val == nil {
 		a.ChannelType = &chanType
 	}
+	if val, ok := typeMap[DualFundingRecordType]; ok && val == nil {
+		a.DualFundingAmount = &dualFundingAmt
+	}
 
 	a.ExtraData = tlvRecords
 
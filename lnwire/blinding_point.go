@@ -0,0 +1,90 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// BlindingPointRecordType is the type of the record within the extra data of
+// an update_add_htlc message that carries the blinding point of an HTLC that
+// is forwarded within a blinded route.
+const BlindingPointRecordType tlv.Type = 0
+
+// BlindingPoint is the ephemeral public key a node within a blinded route
+// needs to decrypt the data the recipient of the route encrypted for it, and
+// to process the onion of the HTLC.
+type BlindingPoint btcec.PublicKey
+
+// Record returns a TLV record that can be used to encode/decode the blinding
+// point from a given TLV stream.
+func (b *BlindingPoint) Record() tlv.Record {
+	return tlv.MakeStaticRecord(
+		BlindingPointRecordType, b, 33, blindingPointEncoder,
+		blindingPointDecoder,
+	)
+}
+
+// blindingPointEncoder is a custom TLV encoder for the BlindingPoint record.
+func blindingPointEncoder(w io.Writer, val interface{}, buf *[8]byte) error {
+	if v, ok := val.(*BlindingPoint); ok {
+		key := (*btcec.PublicKey)(v)
+		return tlv.EPubKey(w, &key, buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.BlindingPoint")
+}
+
+// blindingPointDecoder is a custom TLV decoder for the BlindingPoint record.
+func blindingPointDecoder(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*BlindingPoint); ok && l == 33 {
+		var key *btcec.PublicKey
+		if err := tlv.DPubKey(r, &key, buf, l); err != nil {
+			return err
+		}
+		*v = BlindingPoint(*key)
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "lnwire.BlindingPoint", l, 33)
+}
+
+// ExtractBlindingPoint parses the blinding point from the extra data of an
+// update_add_htlc message. If the extra data doesn't contain a blinding
+// point, nil is returned.
+func ExtractBlindingPoint(extraData ExtraOpaqueData) (*btcec.PublicKey,
+	error) {
+
+	if len(extraData) == 0 {
+		return nil, nil
+	}
+
+	var blindingPoint BlindingPoint
+	typeMap, err := extraData.ExtractRecords(&blindingPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if val, ok := typeMap[BlindingPointRecordType]; !ok || val != nil {
+		return nil, nil
+	}
+
+	return (*btcec.PublicKey)(&blindingPoint), nil
+}
+
+// BlindingPoint returns the blinding point carried within the extra data of
+// the HTLC, or nil if the HTLC isn't forwarded within a blinded route.
+func (c *UpdateAddHTLC) BlindingPoint() (*btcec.PublicKey, error) {
+	return ExtractBlindingPoint(c.ExtraData)
+}
+
+// SetBlindingPoint stores the passed blinding point within the extra data of
+// the HTLC.
+func (c *UpdateAddHTLC) SetBlindingPoint(key *btcec.PublicKey) error {
+	blindingPoint := BlindingPoint(*key)
+	return c.ExtraData.PackRecords(&blindingPoint)
+}
@@ -0,0 +1,35 @@
+package lnwire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpdateAddHTLCBlindingPoint tests that the blinding point of an HTLC
+// survives the encoding and decoding of the update_add_htlc message.
+func TestUpdateAddHTLCBlindingPoint(t *testing.T) {
+	t.Parallel()
+
+	// An HTLC without extra data doesn't carry a blinding point.
+	htlc := &UpdateAddHTLC{}
+	blindingPoint, err := htlc.BlindingPoint()
+	require.NoError(t, err)
+	require.Nil(t, blindingPoint)
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+	require.NoError(t, htlc.SetBlindingPoint(privKey.PubKey()))
+
+	var b bytes.Buffer
+	require.NoError(t, htlc.Encode(&b, 0))
+
+	var htlc2 UpdateAddHTLC
+	require.NoError(t, htlc2.Decode(&b, 0))
+
+	blindingPoint, err = htlc2.BlindingPoint()
+	require.NoError(t, err)
+	require.True(t, blindingPoint.IsEqual(privKey.PubKey()))
+}
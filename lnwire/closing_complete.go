@@ -0,0 +1,86 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// ClosingComplete is sent by either party to a channel that is being closed
+// cooperatively with replaceable closing transactions. It proposes a closing
+// transaction whose fee is paid entirely from the balance of the sender,
+// while the receiver's output carries its full balance. The receiver signs
+// the proposal with a ClosingSig message. Once a closing transaction has been
+// broadcast, either party may send a new ClosingComplete with a higher fee to
+// replace it.
+type ClosingComplete struct {
+	// ChannelID identifies the channel that is being closed.
+	ChannelID ChannelID
+
+	// FeeSatoshis is the total fee of the proposed closing transaction,
+	// paid by the sender.
+	FeeSatoshis btcutil.Amount
+
+	// Signature is the signature of the sender for the proposed closing
+	// transaction.
+	Signature Sig
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// NewClosingComplete creates a new ClosingComplete message.
+func NewClosingComplete(cid ChannelID, fee btcutil.Amount,
+	sig Sig) *ClosingComplete {
+
+	return &ClosingComplete{
+		ChannelID:   cid,
+		FeeSatoshis: fee,
+		Signature:   sig,
+	}
+}
+
+// A compile-time check to ensure ClosingComplete implements the
+// lnwire.Message interface.
+var _ Message = (*ClosingComplete)(nil)
+
+// Decode deserializes a serialized ClosingComplete message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingComplete) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(
+		r, &c.ChannelID, &c.FeeSatoshis, &c.Signature, &c.ExtraData,
+	)
+}
+
+// Encode serializes the target ClosingComplete message into the passed
+// io.Writer observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingComplete) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, c.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, c.FeeSatoshis); err != nil {
+		return err
+	}
+
+	if err := WriteSig(w, c.Signature); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, c.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingComplete) MsgType() MessageType {
+	return MsgClosingComplete
+}
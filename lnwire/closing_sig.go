@@ -0,0 +1,68 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// ClosingSig is sent in response to a ClosingComplete message. It carries the
+// signature of the sender for the closing transaction proposed by the
+// receiver. After sending this message, the sender must be prepared for the
+// proposed closing transaction to confirm.
+type ClosingSig struct {
+	// ChannelID identifies the channel that is being closed.
+	ChannelID ChannelID
+
+	// Signature is the signature of the sender for the proposed closing
+	// transaction.
+	Signature Sig
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// NewClosingSig creates a new ClosingSig message.
+func NewClosingSig(cid ChannelID, sig Sig) *ClosingSig {
+	return &ClosingSig{
+		ChannelID: cid,
+		Signature: sig,
+	}
+}
+
+// A compile-time check to ensure ClosingSig implements the lnwire.Message
+// interface.
+var _ Message = (*ClosingSig)(nil)
+
+// Decode deserializes a serialized ClosingSig message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingSig) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r, &c.ChannelID, &c.Signature, &c.ExtraData)
+}
+
+// Encode serializes the target ClosingSig message into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingSig) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, c.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteSig(w, c.Signature); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, c.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *ClosingSig) MsgType() MessageType {
+	return MsgClosingSig
+}
@@ -36,6 +36,10 @@ type CommitSig struct {
 	// transaction should be signed.
 	HtlcSigs []Sig
 
+	// SpliceSig, if set, holds the signatures for the variant of the new
+	// commitment that spends the funding output of a pending splice.
+	SpliceSig *SpliceCommitSig
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -58,12 +62,30 @@ var _ Message = (*CommitSig)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (c *CommitSig) Decode(r io.Reader, pver uint32) error {
-	return ReadElements(r,
+	var tlvRecords ExtraOpaqueData
+	err := ReadElements(r,
 		&c.ChanID,
 		&c.CommitSig,
 		&c.HtlcSigs,
-		&c.ExtraData,
+		&tlvRecords,
 	)
+	if err != nil {
+		return err
+	}
+
+	var spliceSig SpliceCommitSig
+	typeMap, err := tlvRecords.ExtractRecords(&spliceSig)
+	if err != nil {
+		return err
+	}
+
+	if val, ok := typeMap[SpliceCommitSigRecordType]; ok && val == nil {
+		c.SpliceSig = &spliceSig
+	}
+
+	c.ExtraData = tlvRecords
+
+	return nil
 }
 
 // Encode serializes the target CommitSig into the passed io.Writer
@@ -71,6 +93,15 @@ func (c *CommitSig) Decode(r io.Reader, pver uint32) error {
 //
 // This is part of the lnwire.Message interface.
 func (c *CommitSig) Encode(w *bytes.Buffer, pver uint32) error {
+	// Only pack the known records if there are any, so that the extra
+	// data of a regular commitment_signed message is sent untouched.
+	if c.SpliceSig != nil {
+		err := EncodeMessageExtraData(&c.ExtraData, c.SpliceSig)
+		if err != nil {
+			return err
+		}
+	}
+
 	if err := WriteChannelID(w, c.ChanID); err != nil {
 		return err
 	}
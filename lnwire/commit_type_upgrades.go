@@ -0,0 +1,45 @@
+package lnwire
+
+import (
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+// CommitTypeUpgradesRecordType is the type of the record within the extra
+// data of a channel_reestablish message that carries the number of in-place
+// commitment type upgrades the sender applied to the channel.
+const CommitTypeUpgradesRecordType tlv.Type = 1
+
+// commitTypeUpgrades is the number of in-place commitment type upgrades a
+// party applied to a channel.
+type commitTypeUpgrades uint64
+
+// Record returns a TLV record that can be used to encode/decode the number of
+// commitment type upgrades from a given TLV stream.
+func (c *commitTypeUpgrades) Record() tlv.Record {
+	return tlv.MakePrimitiveRecord(
+		CommitTypeUpgradesRecordType, (*uint64)(c),
+	)
+}
+
+// CommitTypeUpgrades returns the number of commitment type upgrades the sender
+// applied to the channel. If the message doesn't carry the number, the channel
+// was never upgraded by the sender and zero is returned.
+func (a *ChannelReestablish) CommitTypeUpgrades() (uint64, error) {
+	if len(a.ExtraData) == 0 {
+		return 0, nil
+	}
+
+	var upgrades commitTypeUpgrades
+	if _, err := a.ExtraData.ExtractRecords(&upgrades); err != nil {
+		return 0, err
+	}
+
+	return uint64(upgrades), nil
+}
+
+// SetCommitTypeUpgrades stores the number of commitment type upgrades the
+// sender applied to the channel within the extra data of the message.
+func (a *ChannelReestablish) SetCommitTypeUpgrades(n uint64) error {
+	upgrades := commitTypeUpgrades(n)
+	return a.ExtraData.PackRecords(&upgrades)
+}
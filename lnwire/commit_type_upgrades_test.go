@@ -0,0 +1,42 @@
+package lnwire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommitTypeUpgrades tests that the number of commitment type upgrades
+// survives a round trip through a channel_reestablish message.
+func TestCommitTypeUpgrades(t *testing.T) {
+	t.Parallel()
+
+	commitPoint, err := randPubKey()
+	require.NoError(t, err)
+
+	msg := &ChannelReestablish{
+		NextLocalCommitHeight:     10,
+		RemoteCommitTailHeight:    9,
+		LocalUnrevokedCommitPoint: commitPoint,
+	}
+
+	// A message without the record signals that the channel was never
+	// upgraded.
+	upgrades, err := msg.CommitTypeUpgrades()
+	require.NoError(t, err)
+	require.Zero(t, upgrades)
+
+	require.NoError(t, msg.SetCommitTypeUpgrades(2))
+
+	var b bytes.Buffer
+	_, err = WriteMessage(&b, msg, 0)
+	require.NoError(t, err)
+
+	msg2, err := ReadMessage(&b, 0)
+	require.NoError(t, err)
+
+	upgrades, err = msg2.(*ChannelReestablish).CommitTypeUpgrades()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, upgrades)
+}
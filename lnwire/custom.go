@@ -0,0 +1,71 @@
+package lnwire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// CustomTypeStart is the start of the custom type range for peer messages as
+// defined in BOLT 01.
+const CustomTypeStart MessageType = 32768
+
+// Custom represents an application-defined wire message.
+type Custom struct {
+	// Type is the message type of the custom message. It must be within
+	// the custom type range.
+	Type MessageType
+
+	// Data is the raw payload of the custom message.
+	Data []byte
+}
+
+// A compile time check to ensure Custom implements the lnwire.Message
+// interface.
+var _ Message = (*Custom)(nil)
+
+// NewCustom instantiates a new custom message, returning an error if the
+// message type isn't within the custom type range.
+func NewCustom(msgType MessageType, data []byte) (*Custom, error) {
+	if msgType < CustomTypeStart {
+		return nil, fmt.Errorf("msg type: %d not in custom range: %v+",
+			msgType, CustomTypeStart)
+	}
+
+	return &Custom{
+		Type: msgType,
+		Data: data,
+	}, nil
+}
+
+// Encode serializes the target Custom message into the passed io.Writer
+// implementation.
+//
+// This is part of the lnwire.Message interface.
+func (c *Custom) Encode(b *bytes.Buffer, pver uint32) error {
+	_, err := b.Write(c.Data)
+	return err
+}
+
+// Decode deserializes the serialized Custom message stored in the passed
+// io.Reader into the target Custom message.
+//
+// This is part of the lnwire.Message interface.
+func (c *Custom) Decode(r io.Reader, pver uint32) error {
+	var b bytes.Buffer
+	if _, err := io.Copy(&b, r); err != nil {
+		return err
+	}
+
+	c.Data = b.Bytes()
+
+	return nil
+}
+
+// MsgType returns the uint32 code which uniquely identifies this message as a
+// Custom message on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *Custom) MsgType() MessageType {
+	return c.Type
+}
@@ -0,0 +1,142 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// DualFundingRecordType is the type of the record within the
+	// open_channel and accept_channel messages that is used to negotiate
+	// a dual funded channel. Within the open_channel message it carries
+	// the request of the initiator, within the accept_channel message it
+	// carries the amount the responder contributes.
+	DualFundingRecordType tlv.Type = 3
+
+	// dualFundingRequestLen is the length of an encoded
+	// DualFundingRequest.
+	dualFundingRequestLen = 16
+)
+
+// DualFundingRequest is sent by the initiator of a channel within the
+// open_channel message to request that the responder contributes funds to
+// the channel. If the responder agrees, the funding transaction is
+// constructed interactively by both parties.
+type DualFundingRequest struct {
+	// RequestedAmount is the amount the initiator would like the
+	// responder to contribute to the channel. The responder may decide to
+	// contribute less, or nothing at all.
+	RequestedAmount btcutil.Amount
+
+	// FundingFeePerKw is the fee rate of the funding transaction. Both
+	// parties pay for the inputs and outputs they add to the funding
+	// transaction at this fee rate.
+	FundingFeePerKw uint32
+
+	// LockTime is the lock time of the funding transaction.
+	LockTime uint32
+}
+
+// Record returns a TLV record that can be used to encode/decode the dual
+// funding request from a given TLV stream.
+func (d *DualFundingRequest) Record() tlv.Record {
+	return tlv.MakeStaticRecord(
+		DualFundingRecordType, d, dualFundingRequestLen,
+		dualFundingRequestEncoder, dualFundingRequestDecoder,
+	)
+}
+
+// dualFundingRequestEncoder is a custom TLV encoder for the
+// DualFundingRequest record.
+func dualFundingRequestEncoder(w io.Writer, val interface{},
+	buf *[8]byte) error {
+
+	if v, ok := val.(*DualFundingRequest); ok {
+		amt := uint64(v.RequestedAmount)
+		if err := tlv.EUint64(w, &amt, buf); err != nil {
+			return err
+		}
+		if err := tlv.EUint32(w, &v.FundingFeePerKw, buf); err != nil {
+			return err
+		}
+
+		return tlv.EUint32(w, &v.LockTime, buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.DualFundingRequest")
+}
+
+// dualFundingRequestDecoder is a custom TLV decoder for the
+// DualFundingRequest record.
+func dualFundingRequestDecoder(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*DualFundingRequest); ok &&
+		l == dualFundingRequestLen {
+
+		var amt uint64
+		if err := tlv.DUint64(r, &amt, buf, 8); err != nil {
+			return err
+		}
+		v.RequestedAmount = btcutil.Amount(amt)
+
+		err := tlv.DUint32(r, &v.FundingFeePerKw, buf, 4)
+		if err != nil {
+			return err
+		}
+
+		return tlv.DUint32(r, &v.LockTime, buf, 4)
+	}
+
+	return tlv.NewTypeForDecodingErr(
+		val, "lnwire.DualFundingRequest", l, dualFundingRequestLen,
+	)
+}
+
+// DualFundingAmount is sent by the responder of a channel within the
+// accept_channel message if the initiator requested that it contributes
+// funds to the channel. It denotes the amount the responder adds to the
+// channel.
+type DualFundingAmount btcutil.Amount
+
+// Record returns a TLV record that can be used to encode/decode the dual
+// funding amount from a given TLV stream.
+func (d *DualFundingAmount) Record() tlv.Record {
+	return tlv.MakeStaticRecord(
+		DualFundingRecordType, d, 8, dualFundingAmountEncoder,
+		dualFundingAmountDecoder,
+	)
+}
+
+// dualFundingAmountEncoder is a custom TLV encoder for the DualFundingAmount
+// record.
+func dualFundingAmountEncoder(w io.Writer, val interface{},
+	buf *[8]byte) error {
+
+	if v, ok := val.(*DualFundingAmount); ok {
+		amt := uint64(*v)
+		return tlv.EUint64(w, &amt, buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.DualFundingAmount")
+}
+
+// dualFundingAmountDecoder is a custom TLV decoder for the DualFundingAmount
+// record.
+func dualFundingAmountDecoder(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*DualFundingAmount); ok && l == 8 {
+		var amt uint64
+		if err := tlv.DUint64(r, &amt, buf, 8); err != nil {
+			return err
+		}
+		*v = DualFundingAmount(amt)
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "lnwire.DualFundingAmount", l, 8)
+}
@@ -0,0 +1,89 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// DynPropose is sent by the initiator of a quiescent channel to propose an
+// in-place change of the channel's parameters. Currently the only parameter
+// that can be changed is the commitment type of the channel.
+type DynPropose struct {
+	// ChanID identifies the channel whose parameters should be changed.
+	ChanID ChannelID
+
+	// ChannelType is the commitment type the channel should be upgraded
+	// to, if set.
+	ChannelType *ChannelType
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile-time check to ensure DynPropose implements the lnwire.Message
+// interface.
+var _ Message = (*DynPropose)(nil)
+
+// Decode deserializes a serialized DynPropose message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (d *DynPropose) Decode(r io.Reader, pver uint32) error {
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(r, &d.ChanID, &tlvRecords); err != nil {
+		return err
+	}
+
+	var chanType ChannelType
+	typeMap, err := tlvRecords.ExtractRecords(&chanType)
+	if err != nil {
+		return err
+	}
+
+	if val, ok := typeMap[ChannelTypeRecordType]; ok && val == nil {
+		d.ChannelType = &chanType
+	}
+
+	d.ExtraData = tlvRecords
+
+	return nil
+}
+
+// Encode serializes the target DynPropose message into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (d *DynPropose) Encode(w *bytes.Buffer, pver uint32) error {
+	// Only pack the known records if there are any, so that the extra
+	// data of a message without any proposed changes is sent untouched.
+	if d.ChannelType != nil {
+		err := EncodeMessageExtraData(&d.ExtraData, d.ChannelType)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := WriteChannelID(w, d.ChanID); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, d.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (d *DynPropose) MsgType() MessageType {
+	return MsgDynPropose
+}
+
+// TargetChanID returns the channel id of the link for which this message is
+// intended.
+//
+// NOTE: Part of peer.LinkUpdater interface.
+func (d *DynPropose) TargetChanID() ChannelID {
+	return d.ChanID
+}
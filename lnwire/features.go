@@ -129,6 +129,26 @@ const (
 	// transactions, which also imply anchor commitments.
 	AnchorsZeroFeeHtlcTxOptional FeatureBit = 23
 
+	// RouteBlindingRequired is a required feature bit that signals that
+	// the node is able to forward and receive payments within blinded
+	// routes.
+	RouteBlindingRequired FeatureBit = 24
+
+	// RouteBlindingOptional is an optional feature bit that signals that
+	// the node is able to forward and receive payments within blinded
+	// routes.
+	RouteBlindingOptional FeatureBit = 25
+
+	// DualFundRequired is a required feature bit that signals that the
+	// node is able to open channels that both parties contribute funds to,
+	// using the interactive transaction construction protocol.
+	DualFundRequired FeatureBit = 28
+
+	// DualFundOptional is an optional feature bit that signals that the
+	// node is able to open channels that both parties contribute funds to,
+	// using the interactive transaction construction protocol.
+	DualFundOptional FeatureBit = 29
+
 	// AMPRequired is a required feature bit that signals that the receiver
 	// of a payment supports accepts spontaneous payments, i.e.
 	// sender-generated preimages according to BOLT XX.
@@ -139,6 +159,86 @@ const (
 	// sender-generated preimages according to BOLT XX.
 	AMPOptional FeatureBit = 31
 
+	// QuiescenceRequired is a required feature bit that signals that the
+	// node understands the stfu message and is able to pause all updates
+	// of a channel on request.
+	QuiescenceRequired FeatureBit = 34
+
+	// QuiescenceOptional is an optional feature bit that signals that the
+	// node understands the stfu message and is able to pause all updates
+	// of a channel on request.
+	QuiescenceOptional FeatureBit = 35
+
+	// OnionMessagesRequired is a required feature bit that signals that
+	// the node is able to forward onion messages.
+	OnionMessagesRequired FeatureBit = 38
+
+	// OnionMessagesOptional is an optional feature bit that signals that
+	// the node is able to forward onion messages.
+	OnionMessagesOptional FeatureBit = 39
+
+	// ProvideStorageRequired is a required feature bit that signals that
+	// the node stores an encrypted backup blob on behalf of its channel
+	// peers, and returns it to them when they reconnect.
+	ProvideStorageRequired FeatureBit = 42
+
+	// ProvideStorageOptional is an optional feature bit that signals that
+	// the node stores an encrypted backup blob on behalf of its channel
+	// peers, and returns it to them when they reconnect.
+	ProvideStorageOptional FeatureBit = 43
+
+	// ScidAliasRequired is a required feature bit that signals that the
+	// node understands the option_scid_alias channel type and the alias
+	// short channel ID sent within the funding_locked message.
+	ScidAliasRequired FeatureBit = 46
+
+	// ScidAliasOptional is an optional feature bit that signals that the
+	// node understands the option_scid_alias channel type and the alias
+	// short channel ID sent within the funding_locked message.
+	ScidAliasOptional FeatureBit = 47
+
+	// ZeroConfRequired is a required feature bit that signals that the node
+	// is willing to open and use channels before their funding
+	// transaction confirms.
+	ZeroConfRequired FeatureBit = 50
+
+	// ZeroConfOptional is an optional feature bit that signals that the
+	// node is willing to open and use channels before their funding
+	// transaction confirms.
+	ZeroConfOptional FeatureBit = 51
+
+	// TrampolineRoutingRequired is a required feature bit that signals
+	// that the node is willing to find routes for payments on behalf of
+	// senders that only route to it, in exchange for a fee.
+	TrampolineRoutingRequired FeatureBit = 56
+
+	// TrampolineRoutingOptional is an optional feature bit that signals
+	// that the node is willing to find routes for payments on behalf of
+	// senders that only route to it, in exchange for a fee.
+	TrampolineRoutingOptional FeatureBit = 57
+
+	// RbfCoopCloseRequired is a required feature bit that signals that the
+	// node closes channels cooperatively with closing transactions that
+	// either party can replace with a higher fee paid from its own
+	// balance.
+	RbfCoopCloseRequired FeatureBit = 60
+
+	// RbfCoopCloseOptional is an optional feature bit that signals that
+	// the node closes channels cooperatively with closing transactions
+	// that either party can replace with a higher fee paid from its own
+	// balance.
+	RbfCoopCloseOptional FeatureBit = 61
+
+	// SplicingRequired is a required feature bit that signals that the
+	// node is able to replace the funding output of a live channel with a
+	// new one that adds or removes funds.
+	SplicingRequired FeatureBit = 62
+
+	// SplicingOptional is an optional feature bit that signals that the
+	// node is able to replace the funding output of a live channel with a
+	// new one that adds or removes funds.
+	SplicingOptional FeatureBit = 63
+
 	// ExplicitChannelTypeRequired is a required bit that denotes that a
 	// connection established with this node is to use explicit channel
 	// commitment types for negotiation instead of the existing implicit
@@ -159,6 +259,20 @@ const (
 	// TODO: Decide on actual feature bit value.
 	ExplicitChannelTypeOptional = 2021
 
+	// DynamicCommitmentsRequired is a required feature bit that signals
+	// that the node is able to change the commitment type of an existing
+	// channel in place, while the channel is quiescent. The proposal has
+	// not been assigned a feature bit yet, so the experimental bit next to
+	// the explicit channel type bits is used.
+	DynamicCommitmentsRequired FeatureBit = 2022
+
+	// DynamicCommitmentsOptional is an optional feature bit that signals
+	// that the node is able to change the commitment type of an existing
+	// channel in place, while the channel is quiescent. The proposal has
+	// not been assigned a feature bit yet, so the experimental bit next to
+	// the explicit channel type bits is used.
+	DynamicCommitmentsOptional FeatureBit = 2023
+
 	// maxAllowedSize is a maximum allowed size of feature vector.
 	//
 	// NOTE: Within the protocol, the maximum allowed message size is 65535
@@ -206,6 +320,28 @@ var Features = map[FeatureBit]string{
 	AMPOptional:                   "amp",
 	ExplicitChannelTypeOptional:   "explicit-commitment-type",
 	ExplicitChannelTypeRequired:   "explicit-commitment-type",
+	ProvideStorageRequired:        "provide-storage",
+	ProvideStorageOptional:        "provide-storage",
+	ScidAliasRequired:             "scid-alias",
+	ScidAliasOptional:             "scid-alias",
+	ZeroConfRequired:              "zero-conf",
+	ZeroConfOptional:              "zero-conf",
+	QuiescenceRequired:            "quiescence",
+	QuiescenceOptional:            "quiescence",
+	RbfCoopCloseRequired:          "rbf-coop-close",
+	RbfCoopCloseOptional:          "rbf-coop-close",
+	SplicingRequired:              "splicing",
+	SplicingOptional:              "splicing",
+	DualFundRequired:              "dual-funding",
+	DualFundOptional:              "dual-funding",
+	RouteBlindingRequired:         "route-blinding",
+	RouteBlindingOptional:         "route-blinding",
+	OnionMessagesRequired:         "onion-messages",
+	OnionMessagesOptional:         "onion-messages",
+	TrampolineRoutingRequired:     "trampoline-routing",
+	TrampolineRoutingOptional:     "trampoline-routing",
+	DynamicCommitmentsRequired:    "dynamic-commitments",
+	DynamicCommitmentsOptional:    "dynamic-commitments",
 }
 
 // RawFeatureVector represents a set of feature bits as defined in BOLT-09.  A
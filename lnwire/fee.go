@@ -0,0 +1,105 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// FeeRecordType is the type of the experimental record used to
+	// advertise an inbound fee within the extra opaque data of a channel
+	// update.
+	FeeRecordType tlv.Type = 55555
+
+	// feeRecordLen is the length of the encoded fee record.
+	feeRecordLen = 8
+
+	// feeRateParts is the total number of parts used to express fee rates.
+	feeRateParts = 1e6
+)
+
+// Fee represents a fee schedule consisting of a base fee and a proportional
+// fee rate. Both values are signed, which allows a node to advertise a
+// discount for htlcs that arrive over a particular channel.
+type Fee struct {
+	// BaseFee is the base fee in milli-satoshis.
+	BaseFee int32
+
+	// FeeRate is the proportional fee rate in parts per million.
+	FeeRate int32
+}
+
+// CalcFee computes the fee for the given amount. The result may be negative
+// in case of a discount. To keep the integer math simple, positive fees are
+// rounded down while negative fees are rounded up.
+func (f Fee) CalcFee(amt MilliSatoshi) int64 {
+	return int64(f.BaseFee) + int64(f.FeeRate)*int64(amt)/feeRateParts
+}
+
+// String returns a human readable representation of the fee.
+func (f Fee) String() string {
+	return fmt.Sprintf("base=%v msat, rate=%v ppm", f.BaseFee, f.FeeRate)
+}
+
+// Record returns a TLV record that can be used to encode/decode the fee from
+// a given TLV stream.
+func (f *Fee) Record() tlv.Record {
+	return tlv.MakeStaticRecord(
+		FeeRecordType, f, feeRecordLen, feeEncoder, feeDecoder,
+	)
+}
+
+// feeEncoder is a custom TLV encoder for the Fee record.
+func feeEncoder(w io.Writer, val interface{}, buf *[8]byte) error {
+	if v, ok := val.(*Fee); ok {
+		baseFee := uint32(v.BaseFee)
+		if err := tlv.EUint32T(w, baseFee, buf); err != nil {
+			return err
+		}
+
+		return tlv.EUint32T(w, uint32(v.FeeRate), buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.Fee")
+}
+
+// feeDecoder is a custom TLV decoder for the Fee record.
+func feeDecoder(r io.Reader, val interface{}, buf *[8]byte, l uint64) error {
+	if v, ok := val.(*Fee); ok && l == feeRecordLen {
+		var baseFee, feeRate uint32
+		if err := tlv.DUint32(r, &baseFee, buf, 4); err != nil {
+			return err
+		}
+		if err := tlv.DUint32(r, &feeRate, buf, 4); err != nil {
+			return err
+		}
+
+		v.BaseFee = int32(baseFee)
+		v.FeeRate = int32(feeRate)
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "lnwire.Fee", l, feeRecordLen)
+}
+
+// ExtractInboundFee attempts to parse an inbound fee record from the passed
+// extra opaque data. The second return value indicates whether the record was
+// present.
+func ExtractInboundFee(extraData ExtraOpaqueData) (Fee, bool, error) {
+	var fee Fee
+	if len(extraData) == 0 {
+		return fee, false, nil
+	}
+
+	typeMap, err := extraData.ExtractRecords(&fee)
+	if err != nil {
+		return fee, false, err
+	}
+
+	_, ok := typeMap[FeeRecordType]
+
+	return fee, ok, nil
+}
@@ -0,0 +1,42 @@
+package lnwire
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestFeeEncodeDecode tests that we're able to properly encode and decode
+// (negative) fees within TLV streams.
+func TestFeeEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	fee := Fee{
+		BaseFee: -1000,
+		FeeRate: 250,
+	}
+
+	var extraData ExtraOpaqueData
+	require.NoError(t, extraData.PackRecords(&fee))
+
+	fee2, ok, err := ExtractInboundFee(extraData)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, fee, fee2)
+
+	// Extra data without the record should be reported as such.
+	_, ok, err = ExtractInboundFee(nil)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+// TestFeeCalcFee tests the rounding behaviour of fee calculation.
+func TestFeeCalcFee(t *testing.T) {
+	t.Parallel()
+
+	fee := Fee{BaseFee: 10, FeeRate: 1000}
+	require.Equal(t, int64(11), fee.CalcFee(1999))
+
+	fee = Fee{BaseFee: -10, FeeRate: -1000}
+	require.Equal(t, int64(-11), fee.CalcFee(1999))
+}
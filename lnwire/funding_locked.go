@@ -4,7 +4,8 @@ import (
 	"bytes"
 	"io"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/tlv"
 )
 
 // FundingLocked is the message that both parties to a new channel creation
@@ -21,6 +22,13 @@ type FundingLocked struct {
 	// next commitment transaction for the channel.
 	NextPerCommitmentPoint *btcec.PublicKey
 
+	// AliasScid is an alias short channel ID that the sender of this
+	// message will recognize for this channel. The recipient can use it in
+	// place of the confirmed short channel ID, e.g. in route hints of
+	// invoices, without leaking the funding outpoint of the channel. This
+	// is an optional TLV field.
+	AliasScid *ShortChannelID
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -47,11 +55,36 @@ var _ Message = (*FundingLocked)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (c *FundingLocked) Decode(r io.Reader, pver uint32) error {
-	return ReadElements(r,
+	// Read all the mandatory fields in the message.
+	err := ReadElements(r,
 		&c.ChanID,
 		&c.NextPerCommitmentPoint,
-		&c.ExtraData,
 	)
+	if err != nil {
+		return err
+	}
+
+	var tlvRecords ExtraOpaqueData
+	if err := ReadElements(r, &tlvRecords); err != nil {
+		return err
+	}
+
+	// Next we'll parse out the set of known records, keeping the raw tlv
+	// bytes untouched to ensure we don't drop any bytes erroneously.
+	var aliasScid ShortChannelID
+	typeMap, err := tlvRecords.ExtractRecords(&aliasScid)
+	if err != nil {
+		return err
+	}
+
+	// Set the alias short channel ID if it was included in the stream.
+	if val, ok := typeMap[AliasScidRecordType]; ok && val == nil {
+		c.AliasScid = &aliasScid
+	}
+
+	c.ExtraData = tlvRecords
+
+	return nil
 }
 
 // Encode serializes the target FundingLocked message into the passed io.Writer
@@ -60,6 +93,17 @@ func (c *FundingLocked) Decode(r io.Reader, pver uint32) error {
 //
 // This is part of the lnwire.Message interface.
 func (c *FundingLocked) Encode(w *bytes.Buffer, pver uint32) error {
+	// We'll only encode the alias short channel ID if it is set. Otherwise
+	// we leave the extra data untouched.
+	if c.AliasScid != nil {
+		err := EncodeMessageExtraData(
+			&c.ExtraData, []tlv.RecordProducer{c.AliasScid}...,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
 	if err := WriteChannelID(w, c.ChanID); err != nil {
 		return err
 	}
@@ -0,0 +1,218 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// LeaseRatesRecordType is the type of the record within the extra data
+	// of a node_announcement message that carries the rates the node
+	// charges for leasing out inbound liquidity.
+	LeaseRatesRecordType tlv.Type = 1
+
+	// LeaseRequestRecordType is the type of the record within the extra
+	// data of an open_channel message that carries the initiator's request
+	// to lease the funds the responder contributes to the channel.
+	LeaseRequestRecordType tlv.Type = 5
+
+	// leaseRatesLen is the length of encoded LeaseRates.
+	leaseRatesLen = 10
+
+	// leaseRequestLen is the length of an encoded LeaseRequest.
+	leaseRequestLen = 10
+
+	// leaseFeeBasisDenominator is the denominator of the proportional
+	// lease fee, which is expressed in basis points of the leased amount.
+	leaseFeeBasisDenominator = 10000
+)
+
+// leaseFee returns the fee for leasing amt with the given base fee and
+// proportional fee in basis points.
+func leaseFee(base btcutil.Amount, basis uint16,
+	amt btcutil.Amount) btcutil.Amount {
+
+	return base + amt*btcutil.Amount(basis)/leaseFeeBasisDenominator
+}
+
+// LeaseRates are the rates a node charges for leasing out inbound liquidity,
+// which it advertises within its node announcement. The leased funds are
+// contributed to a dual funded channel opened by the lessee, and the lessor's
+// own balance within the channel is encumbered for the lease duration.
+type LeaseRates struct {
+	// FeeBase is the fixed fee charged for every lease.
+	FeeBase btcutil.Amount
+
+	// FeeBasis is the proportional fee charged for every lease, in basis
+	// points of the leased amount.
+	FeeBasis uint16
+
+	// MaxDuration is the maximum lease duration in blocks.
+	MaxDuration uint32
+}
+
+// Fee returns the fee for leasing the given amount at these rates.
+func (l *LeaseRates) Fee(amt btcutil.Amount) btcutil.Amount {
+	return leaseFee(l.FeeBase, l.FeeBasis, amt)
+}
+
+// Record returns a TLV record that can be used to encode/decode the lease
+// rates from a given TLV stream.
+func (l *LeaseRates) Record() tlv.Record {
+	return tlv.MakeStaticRecord(
+		LeaseRatesRecordType, l, leaseRatesLen, leaseRatesEncoder,
+		leaseRatesDecoder,
+	)
+}
+
+// leaseRatesEncoder is a custom TLV encoder for the LeaseRates record.
+func leaseRatesEncoder(w io.Writer, val interface{}, buf *[8]byte) error {
+	if v, ok := val.(*LeaseRates); ok {
+		base := uint32(v.FeeBase)
+		if err := tlv.EUint32(w, &base, buf); err != nil {
+			return err
+		}
+		if err := tlv.EUint16(w, &v.FeeBasis, buf); err != nil {
+			return err
+		}
+
+		return tlv.EUint32(w, &v.MaxDuration, buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.LeaseRates")
+}
+
+// leaseRatesDecoder is a custom TLV decoder for the LeaseRates record.
+func leaseRatesDecoder(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*LeaseRates); ok && l == leaseRatesLen {
+		var base uint32
+		if err := tlv.DUint32(r, &base, buf, 4); err != nil {
+			return err
+		}
+		v.FeeBase = btcutil.Amount(base)
+
+		if err := tlv.DUint16(r, &v.FeeBasis, buf, 2); err != nil {
+			return err
+		}
+
+		return tlv.DUint32(r, &v.MaxDuration, buf, 4)
+	}
+
+	return tlv.NewTypeForDecodingErr(
+		val, "lnwire.LeaseRates", l, leaseRatesLen,
+	)
+}
+
+// LeaseRatesFromExtraData returns the lease rates carried within the extra
+// data of a node announcement, or nil if the node doesn't lease out inbound
+// liquidity.
+func LeaseRatesFromExtraData(extra ExtraOpaqueData) (*LeaseRates, error) {
+	if len(extra) == 0 {
+		return nil, nil
+	}
+
+	var rates LeaseRates
+	typeMap, err := extra.ExtractRecords(&rates)
+	if err != nil {
+		return nil, err
+	}
+
+	if val, ok := typeMap[LeaseRatesRecordType]; !ok || val != nil {
+		return nil, nil
+	}
+
+	return &rates, nil
+}
+
+// LeaseRates returns the rates the node charges for leasing out inbound
+// liquidity, or nil if the announcement doesn't carry any.
+func (a *NodeAnnouncement) LeaseRates() (*LeaseRates, error) {
+	return LeaseRatesFromExtraData(a.ExtraOpaqueData)
+}
+
+// SetLeaseRates stores the rates the node charges for leasing out inbound
+// liquidity within the extra data of the announcement. If rates is nil, the
+// extra data is cleared.
+func (a *NodeAnnouncement) SetLeaseRates(rates *LeaseRates) error {
+	if rates == nil {
+		a.ExtraOpaqueData = nil
+		return nil
+	}
+
+	return a.ExtraOpaqueData.PackRecords(rates)
+}
+
+// LeaseRequest is sent by the initiator of a dual funded channel within the
+// open_channel message to lease the funds the responder contributes at the
+// rates the responder advertised. The responder's balance within the channel
+// is then encumbered for the lease duration.
+type LeaseRequest struct {
+	// Duration is the requested lease duration in blocks.
+	Duration uint32
+
+	// FeeBase is the fixed lease fee advertised by the responder.
+	FeeBase btcutil.Amount
+
+	// FeeBasis is the proportional lease fee advertised by the responder,
+	// in basis points of the leased amount.
+	FeeBasis uint16
+}
+
+// Fee returns the fee for leasing the given amount.
+func (l *LeaseRequest) Fee(amt btcutil.Amount) btcutil.Amount {
+	return leaseFee(l.FeeBase, l.FeeBasis, amt)
+}
+
+// Record returns a TLV record that can be used to encode/decode the lease
+// request from a given TLV stream.
+func (l *LeaseRequest) Record() tlv.Record {
+	return tlv.MakeStaticRecord(
+		LeaseRequestRecordType, l, leaseRequestLen,
+		leaseRequestEncoder, leaseRequestDecoder,
+	)
+}
+
+// leaseRequestEncoder is a custom TLV encoder for the LeaseRequest record.
+func leaseRequestEncoder(w io.Writer, val interface{}, buf *[8]byte) error {
+	if v, ok := val.(*LeaseRequest); ok {
+		if err := tlv.EUint32(w, &v.Duration, buf); err != nil {
+			return err
+		}
+
+		base := uint32(v.FeeBase)
+		if err := tlv.EUint32(w, &base, buf); err != nil {
+			return err
+		}
+
+		return tlv.EUint16(w, &v.FeeBasis, buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.LeaseRequest")
+}
+
+// leaseRequestDecoder is a custom TLV decoder for the LeaseRequest record.
+func leaseRequestDecoder(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*LeaseRequest); ok && l == leaseRequestLen {
+		if err := tlv.DUint32(r, &v.Duration, buf, 4); err != nil {
+			return err
+		}
+
+		var base uint32
+		if err := tlv.DUint32(r, &base, buf, 4); err != nil {
+			return err
+		}
+		v.FeeBase = btcutil.Amount(base)
+
+		return tlv.DUint16(r, &v.FeeBasis, buf, 2)
+	}
+
+	return tlv.NewTypeForDecodingErr(
+		val, "lnwire.LeaseRequest", l, leaseRequestLen,
+	)
+}
@@ -0,0 +1,66 @@
+package lnwire
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNodeAnnouncementLeaseRates tests that the lease rates stored within the
+// extra data of a node announcement survive a round trip over the wire.
+func TestNodeAnnouncementLeaseRates(t *testing.T) {
+	t.Parallel()
+
+	msg := &NodeAnnouncement{
+		Features:        NewRawFeatureVector(),
+		Addresses:       []net.Addr{},
+		ExtraOpaqueData: make([]byte, 0),
+	}
+	copy(msg.NodeID[:], bytes.Repeat([]byte{0x02}, 33))
+
+	// Without extra data, the node doesn't lease out any liquidity.
+	rates, err := msg.LeaseRates()
+	require.NoError(t, err)
+	require.Nil(t, rates)
+
+	expRates := &LeaseRates{
+		FeeBase:     1000,
+		FeeBasis:    50,
+		MaxDuration: 4032,
+	}
+	require.NoError(t, msg.SetLeaseRates(expRates))
+
+	var b bytes.Buffer
+	_, err = WriteMessage(&b, msg, 0)
+	require.NoError(t, err)
+
+	decoded, err := ReadMessage(&b, 0)
+	require.NoError(t, err)
+	nodeAnn, ok := decoded.(*NodeAnnouncement)
+	require.True(t, ok)
+
+	rates, err = nodeAnn.LeaseRates()
+	require.NoError(t, err)
+	require.Equal(t, expRates, rates)
+
+	// Clearing the rates removes them from the announcement.
+	require.NoError(t, nodeAnn.SetLeaseRates(nil))
+	rates, err = nodeAnn.LeaseRates()
+	require.NoError(t, err)
+	require.Nil(t, rates)
+}
+
+// TestLeaseFee tests that the lease fee consists of the base fee and the
+// proportional fee in basis points of the leased amount.
+func TestLeaseFee(t *testing.T) {
+	t.Parallel()
+
+	rates := &LeaseRates{FeeBase: 1000, FeeBasis: 50}
+	require.Equal(t, btcutil.Amount(6000), rates.Fee(1000000))
+
+	req := &LeaseRequest{FeeBase: 1000, FeeBasis: 50}
+	require.Equal(t, rates.Fee(1000000), req.Fee(1000000))
+}
@@ -6,7 +6,6 @@ import (
 	"encoding/hex"
 	"image/color"
 	"math"
-	"math/big"
 	"math/rand"
 	"net"
 	"reflect"
@@ -14,24 +13,27 @@ import (
 	"testing/quick"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/tor"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
 	shaHash1Bytes, _ = hex.DecodeString("e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
 	shaHash1, _      = chainhash.NewHash(shaHash1Bytes)
 	outpoint1        = wire.NewOutPoint(shaHash1, 0)
-	testSig          = &btcec.Signature{
-		R: new(big.Int),
-		S: new(big.Int),
-	}
-	_, _ = testSig.R.SetString("63724406601629180062774974542967536251589935445068131219452686511677818569431", 10)
-	_, _ = testSig.S.SetString("18801056069249825825291287104931333862866033135609736119018462340006816851118", 10)
+	testRBytes, _    = hex.DecodeString("8ce2bc69281ce27da07e6683571319d18e949ddfa2965fb6caa1bf0314f882d7")
+	testSBytes, _    = hex.DecodeString("299105481d63e0f4bc2a88121167221b6700d72a0ead154c03be696a292d24ae")
+	testRScalar      = new(btcec.ModNScalar)
+	testSScalar      = new(btcec.ModNScalar)
+	_                = testRScalar.SetByteSlice(testRBytes)
+	_                = testSScalar.SetByteSlice(testSBytes)
+	testSig          = ecdsa.NewSignature(testRScalar, testSScalar)
 )
 
 const letterBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
@@ -46,7 +48,7 @@ func randAlias(r *rand.Rand) NodeAlias {
 }
 
 func randPubKey() (*btcec.PublicKey, error) {
-	priv, err := btcec.NewPrivateKey(btcec.S256())
+	priv, err := btcec.NewPrivateKey()
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +59,7 @@ func randPubKey() (*btcec.PublicKey, error) {
 func randRawKey() ([33]byte, error) {
 	var n [33]byte
 
-	priv, err := btcec.NewPrivateKey(btcec.S256())
+	priv, err := btcec.NewPrivateKey()
 	if err != nil {
 		return n, err
 	}
@@ -86,6 +88,27 @@ func randRawFeatureVector(r *rand.Rand) *RawFeatureVector {
 	return featureVec
 }
 
+// randHtlcSigs returns a slice of less than maxSigs htlc signatures. The
+// slice is only created if there will be any signatures in it to prevent
+// false positive test failures due to an empty slice versus a nil slice.
+func randHtlcSigs(t *testing.T, r *rand.Rand, maxSigs int32) []Sig {
+	numSigs := r.Int31n(maxSigs)
+	if numSigs == 0 {
+		return nil
+	}
+
+	sigs := make([]Sig, numSigs)
+	for i := range sigs {
+		var err error
+		sigs[i], err = NewSigFromSignature(testSig)
+		if err != nil {
+			t.Fatalf("unable to parse sig: %v", err)
+		}
+	}
+
+	return sigs
+}
+
 func randTCP4Addr(r *rand.Rand) (*net.TCPAddr, error) {
 	var ip [4]byte
 	if _, err := r.Read(ip[:]); err != nil {
@@ -240,13 +263,38 @@ func TestMaxOutPointIndex(t *testing.T) {
 func TestEmptyMessageUnknownType(t *testing.T) {
 	t.Parallel()
 
-	fakeType := MessageType(math.MaxUint16)
+	fakeType := CustomTypeStart - 1
 	if _, err := makeEmptyMessage(fakeType); err == nil {
 		t.Fatalf("should not be able to make an empty message of an " +
 			"unknown type")
 	}
 }
 
+// TestCustomMessage tests that messages within the custom type range are
+// read as raw custom messages.
+func TestCustomMessage(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewCustom(CustomTypeStart-1, nil)
+	require.Error(t, err)
+
+	for _, msgType := range []MessageType{
+		CustomTypeStart, MessageType(math.MaxUint16),
+	} {
+		msg, err := NewCustom(msgType, []byte{1, 2, 3})
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		_, err = WriteMessage(&b, msg, 0)
+		require.NoError(t, err)
+
+		newMsg, err := ReadMessage(&b, 0)
+		require.NoError(t, err)
+		require.Equal(t, msg, newMsg)
+		require.Equal(t, "Custom", newMsg.MsgType().String())
+	}
+}
+
 // TestLightningWireProtocol uses the testing/quick package to create a series
 // of fuzz tests to attempt to break a primary scenario which is implemented as
 // property based testing scenario.
@@ -370,6 +418,18 @@ func TestLightningWireProtocol(t *testing.T) {
 
 				req.ChannelType = new(ChannelType)
 				*req.ChannelType = ChannelType(*randRawFeatureVector(r))
+
+				req.DualFundingRequest = &DualFundingRequest{
+					RequestedAmount: btcutil.Amount(r.Int63()),
+					FundingFeePerKw: uint32(r.Int31()),
+					LockTime:        uint32(r.Int31()),
+				}
+
+				req.LeaseRequest = &LeaseRequest{
+					Duration: r.Uint32(),
+					FeeBase:  btcutil.Amount(r.Uint32()),
+					FeeBasis: uint16(r.Int31()),
+				}
 			} else {
 				req.UpfrontShutdownScript = []byte{}
 			}
@@ -439,6 +499,9 @@ func TestLightningWireProtocol(t *testing.T) {
 
 				req.ChannelType = new(ChannelType)
 				*req.ChannelType = ChannelType(*randRawFeatureVector(r))
+
+				amt := DualFundingAmount(r.Int63())
+				req.DualFundingAmount = &amt
 			} else {
 				req.UpfrontShutdownScript = []byte{}
 			}
@@ -511,6 +574,13 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			req := NewFundingLocked(ChannelID(c), pubKey)
 
+			// Half the time we'll include an alias short channel
+			// ID.
+			if r.Int31()%2 == 0 {
+				scid := NewShortChanIDFromInt(uint64(r.Int63()))
+				req.AliasScid = &scid
+			}
+
 			v[0] = reflect.ValueOf(*req)
 		},
 		MsgClosingSigned: func(v []reflect.Value, r *rand.Rand) {
@@ -532,6 +602,248 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(req)
 		},
+		MsgClosingComplete: func(v []reflect.Value, r *rand.Rand) {
+			req := ClosingComplete{
+				FeeSatoshis: btcutil.Amount(r.Int63()),
+				ExtraData:   make([]byte, 0),
+			}
+			var err error
+			req.Signature, err = NewSigFromSignature(testSig)
+			if err != nil {
+				t.Fatalf("unable to parse sig: %v", err)
+				return
+			}
+
+			if _, err := r.Read(req.ChannelID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgClosingSig: func(v []reflect.Value, r *rand.Rand) {
+			req := ClosingSig{
+				ExtraData: make([]byte, 0),
+			}
+			var err error
+			req.Signature, err = NewSigFromSignature(testSig)
+			if err != nil {
+				t.Fatalf("unable to parse sig: %v", err)
+				return
+			}
+
+			if _, err := r.Read(req.ChannelID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgStfu: func(v []reflect.Value, r *rand.Rand) {
+			req := NewStfu(ChannelID{}, r.Int31()%2 == 0)
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(*req)
+		},
+		MsgDynPropose: func(v []reflect.Value, r *rand.Rand) {
+			req := DynPropose{
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			// 1/2 chance of proposing a new channel type.
+			if r.Intn(2) == 0 {
+				req.ChannelType = new(ChannelType)
+				*req.ChannelType = ChannelType(
+					*randRawFeatureVector(r),
+				)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceInit: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceInit{
+				RelativeSatoshis: r.Int63() - r.Int63(),
+				FundingFeePerKw:  uint32(r.Int31()),
+				LockTime:         uint32(r.Int31()),
+				ExtraData:        make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			var err error
+			req.FundingPubKey, err = randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceAck: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceAck{
+				RelativeSatoshis: r.Int63() - r.Int63(),
+				ExtraData:        make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			var err error
+			req.FundingPubKey, err = randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceCreated: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceCreated{
+				SpliceTx:  wire.NewMsgTx(2),
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			var prevOut wire.OutPoint
+			if _, err := r.Read(prevOut.Hash[:]); err != nil {
+				t.Fatalf("unable to generate txid: %v", err)
+				return
+			}
+			txIn := wire.NewTxIn(&prevOut, []byte{}, nil)
+			req.SpliceTx.AddTxIn(txIn)
+			txOut := wire.NewTxOut(r.Int63(), []byte{})
+			req.SpliceTx.AddTxOut(txOut)
+
+			var err error
+			req.CommitSig, err = NewSigFromSignature(testSig)
+			if err != nil {
+				t.Fatalf("unable to parse sig: %v", err)
+				return
+			}
+			req.HtlcSigs = randHtlcSigs(t, r, 20)
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceSigned: func(v []reflect.Value, r *rand.Rand) {
+			req := SpliceSigned{
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			var err error
+			req.CommitSig, err = NewSigFromSignature(testSig)
+			if err != nil {
+				t.Fatalf("unable to parse sig: %v", err)
+				return
+			}
+			req.FundingSig = req.CommitSig
+			req.HtlcSigs = randHtlcSigs(t, r, 20)
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgSpliceLocked: func(v []reflect.Value, r *rand.Rand) {
+			req := NewSpliceLocked(ChannelID{}, chainhash.Hash{})
+			if _, err := r.Read(req.ChanID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+			if _, err := r.Read(req.SpliceTxid[:]); err != nil {
+				t.Fatalf("unable to generate txid: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(*req)
+		},
+		MsgTxAddInput: func(v []reflect.Value, r *rand.Rand) {
+			req := TxAddInput{
+				SerialID:  uint64(r.Int63()),
+				PrevTx:    wire.NewMsgTx(2),
+				PrevTxOut: uint32(r.Int31()),
+				Sequence:  uint32(r.Int31()),
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChannelID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+
+			var prevOut wire.OutPoint
+			if _, err := r.Read(prevOut.Hash[:]); err != nil {
+				t.Fatalf("unable to generate txid: %v", err)
+				return
+			}
+			txIn := wire.NewTxIn(&prevOut, []byte{}, nil)
+			req.PrevTx.AddTxIn(txIn)
+			txOut := wire.NewTxOut(r.Int63(), []byte{})
+			req.PrevTx.AddTxOut(txOut)
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxAddOutput: func(v []reflect.Value, r *rand.Rand) {
+			req := TxAddOutput{
+				SerialID:  uint64(r.Int63()),
+				Amount:    btcutil.Amount(r.Int63()),
+				PkScript:  make([]byte, 1+r.Intn(34)),
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChannelID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+			if _, err := r.Read(req.PkScript); err != nil {
+				t.Fatalf("unable to generate pkscript: %v", err)
+				return
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
+		MsgTxSignatures: func(v []reflect.Value, r *rand.Rand) {
+			req := TxSignatures{
+				ExtraData: make([]byte, 0),
+			}
+			if _, err := r.Read(req.ChannelID[:]); err != nil {
+				t.Fatalf("unable to generate chan id: %v", err)
+				return
+			}
+			if _, err := r.Read(req.TxHash[:]); err != nil {
+				t.Fatalf("unable to generate txid: %v", err)
+				return
+			}
+
+			numWitnesses := 1 + r.Intn(3)
+			for i := 0; i < numWitnesses; i++ {
+				witness := wire.TxWitness{
+					make([]byte, 1+r.Intn(72)),
+					make([]byte, 33),
+				}
+				for _, item := range witness {
+					if _, err := r.Read(item); err != nil {
+						t.Fatalf("unable to generate "+
+							"witness: %v", err)
+						return
+					}
+				}
+				req.Witnesses = append(req.Witnesses, witness)
+			}
+
+			v[0] = reflect.ValueOf(req)
+		},
 		MsgCommitSig: func(v []reflect.Value, r *rand.Rand) {
 			req := NewCommitSig()
 			if _, err := r.Read(req.ChanID[:]); err != nil {
@@ -546,20 +858,18 @@ func TestLightningWireProtocol(t *testing.T) {
 				return
 			}
 
-			// Only create the slice if there will be any signatures
-			// in it to prevent false positive test failures due to
-			// an empty slice versus a nil slice.
-			numSigs := uint16(r.Int31n(1020))
-			if numSigs > 0 {
-				req.HtlcSigs = make([]Sig, numSigs)
-			}
-			for i := 0; i < int(numSigs); i++ {
-				req.HtlcSigs[i], err = NewSigFromSignature(testSig)
-				if err != nil {
-					t.Fatalf("unable to parse sig: %v", err)
-					return
+			// A commitment that is signed for a pending splice as
+			// well carries two sets of htlc signatures, so we'll
+			// make sure the message still fits.
+			maxSigs := int32(1020)
+			if r.Intn(2) == 0 {
+				maxSigs = 500
+				req.SpliceSig = &SpliceCommitSig{
+					CommitSig: req.CommitSig,
+					HtlcSigs:  randHtlcSigs(t, r, maxSigs),
 				}
 			}
+			req.HtlcSigs = randHtlcSigs(t, r, maxSigs)
 
 			v[0] = reflect.ValueOf(*req)
 		},
@@ -860,6 +1170,45 @@ func TestLightningWireProtocol(t *testing.T) {
 
 			v[0] = reflect.ValueOf(req)
 		},
+		MsgOnionMessage: func(v []reflect.Value, r *rand.Rand) {
+			blindingPoint, err := randPubKey()
+			if err != nil {
+				t.Fatalf("unable to generate key: %v", err)
+				return
+			}
+
+			onionBlob := make([]byte, r.Intn(1500))
+			if _, err := r.Read(onionBlob); err != nil {
+				t.Fatalf("unable to generate onion: %v", err)
+				return
+			}
+
+			req := NewOnionMessage(blindingPoint, onionBlob)
+
+			v[0] = reflect.ValueOf(*req)
+		},
+		MsgPeerStorage: func(v []reflect.Value, r *rand.Rand) {
+			blob := make([]byte, r.Intn(1500))
+			if _, err := r.Read(blob); err != nil {
+				t.Fatalf("unable to generate blob: %v", err)
+				return
+			}
+
+			req := NewPeerStorage(blob)
+
+			v[0] = reflect.ValueOf(*req)
+		},
+		MsgPeerStorageRetrieval: func(v []reflect.Value, r *rand.Rand) {
+			blob := make([]byte, r.Intn(1500))
+			if _, err := r.Read(blob); err != nil {
+				t.Fatalf("unable to generate blob: %v", err)
+				return
+			}
+
+			req := NewPeerStorageRetrieval(blob)
+
+			v[0] = reflect.ValueOf(*req)
+		},
 	}
 
 	// With the above types defined, we'll now generate a slice of
@@ -937,6 +1286,126 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgClosingComplete,
+			scenario: func(m ClosingComplete) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgClosingSig,
+			scenario: func(m ClosingSig) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgStfu,
+			scenario: func(m Stfu) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgPeerStorage,
+			scenario: func(m PeerStorage) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgPeerStorageRetrieval,
+			scenario: func(m PeerStorageRetrieval) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgDynPropose,
+			scenario: func(m DynPropose) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgDynAck,
+			scenario: func(m DynAck) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgDynReject,
+			scenario: func(m DynReject) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAddInput,
+			scenario: func(m TxAddInput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAddOutput,
+			scenario: func(m TxAddOutput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxRemoveInput,
+			scenario: func(m TxRemoveInput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxRemoveOutput,
+			scenario: func(m TxRemoveOutput) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxComplete,
+			scenario: func(m TxComplete) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxSignatures,
+			scenario: func(m TxSignatures) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgTxAbort,
+			scenario: func(m TxAbort) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceInit,
+			scenario: func(m SpliceInit) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceAck,
+			scenario: func(m SpliceAck) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceCreated,
+			scenario: func(m SpliceCreated) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceSigned,
+			scenario: func(m SpliceSigned) bool {
+				return mainScenario(&m)
+			},
+		},
+		{
+			msgType: MsgSpliceLocked,
+			scenario: func(m SpliceLocked) bool {
+				return mainScenario(&m)
+			},
+		},
 		{
 			msgType: MsgUpdateAddHTLC,
 			scenario: func(m UpdateAddHTLC) bool {
@@ -1016,6 +1485,12 @@ func TestLightningWireProtocol(t *testing.T) {
 				return mainScenario(&m)
 			},
 		},
+		{
+			msgType: MsgOnionMessage,
+			scenario: func(m OnionMessage) bool {
+				return mainScenario(&m)
+			},
+		},
 		{
 			msgType: MsgQueryShortChanIDs,
 			scenario: func(m QueryShortChanIDs) bool {
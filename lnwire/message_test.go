@@ -7,14 +7,15 @@ import (
 	"image/color"
 	"io"
 	"math"
-	"math/big"
 	"math/rand"
 	"net"
 	"sync"
 	"testing"
 
-	"github.com/btcsuite/btcd/btcec"
-	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/tor"
 	"github.com/stretchr/testify/mock"
@@ -25,10 +26,9 @@ const deliveryAddressMaxSize = 34
 const letterBytes = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
 
 var (
-	testSig = &btcec.Signature{
-		R: new(big.Int),
-		S: new(big.Int),
-	}
+	testSig = ecdsa.NewSignature(
+		new(btcec.ModNScalar), new(btcec.ModNScalar),
+	)
 	testNodeSig, _ = lnwire.NewSigFromSignature(testSig)
 
 	testNumExtraBytes = 1000
@@ -274,6 +274,24 @@ func makeAllMessages(t testing.TB, r *rand.Rand) []lnwire.Message {
 	msgAll = append(msgAll, newMsgFundingLocked(t, r))
 	msgAll = append(msgAll, newMsgShutdown(t, r))
 	msgAll = append(msgAll, newMsgClosingSigned(t, r))
+	msgAll = append(msgAll, newMsgClosingComplete(t, r))
+	msgAll = append(msgAll, newMsgClosingSig(t, r))
+	msgAll = append(msgAll, newMsgTxAddInput(t, r))
+	msgAll = append(msgAll, newMsgTxAddOutput(t, r))
+	msgAll = append(msgAll, newMsgTxRemoveInput(t, r))
+	msgAll = append(msgAll, newMsgTxRemoveOutput(t, r))
+	msgAll = append(msgAll, newMsgTxComplete(t, r))
+	msgAll = append(msgAll, newMsgTxSignatures(t, r))
+	msgAll = append(msgAll, newMsgTxAbort(t, r))
+	msgAll = append(msgAll, newMsgStfu(t, r))
+	msgAll = append(msgAll, newMsgDynPropose(t, r))
+	msgAll = append(msgAll, newMsgDynAck(t, r))
+	msgAll = append(msgAll, newMsgDynReject(t, r))
+	msgAll = append(msgAll, newMsgSpliceInit(t, r))
+	msgAll = append(msgAll, newMsgSpliceAck(t, r))
+	msgAll = append(msgAll, newMsgSpliceCreated(t, r))
+	msgAll = append(msgAll, newMsgSpliceSigned(t, r))
+	msgAll = append(msgAll, newMsgSpliceLocked(t, r))
 	msgAll = append(msgAll, newMsgUpdateAddHTLC(t, r))
 	msgAll = append(msgAll, newMsgUpdateFulfillHTLC(t, r))
 	msgAll = append(msgAll, newMsgUpdateFailHTLC(t, r))
@@ -291,6 +309,9 @@ func makeAllMessages(t testing.TB, r *rand.Rand) []lnwire.Message {
 	msgAll = append(msgAll, newMsgQueryChannelRange(t, r))
 	msgAll = append(msgAll, newMsgReplyChannelRange(t, r))
 	msgAll = append(msgAll, newMsgGossipTimestampRange(t, r))
+	msgAll = append(msgAll, newMsgOnionMessage(t, r))
+	msgAll = append(msgAll, newMsgPeerStorage(t, r))
+	msgAll = append(msgAll, newMsgPeerStorageRetrieval(t, r))
 	msgAll = append(msgAll, newMsgQueryShortChanIDsZlib(t, r))
 	msgAll = append(msgAll, newMsgReplyChannelRangeZlib(t, r))
 
@@ -477,6 +498,299 @@ func newMsgClosingSigned(t testing.TB, r *rand.Rand) *lnwire.ClosingSigned {
 	return msg
 }
 
+func newMsgClosingComplete(t testing.TB,
+	r *rand.Rand) *lnwire.ClosingComplete {
+
+	t.Helper()
+
+	msg := &lnwire.ClosingComplete{
+		FeeSatoshis: btcutil.Amount(r.Int63()),
+		Signature:   testNodeSig,
+		ExtraData:   createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChannelID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgClosingSig(t testing.TB, r *rand.Rand) *lnwire.ClosingSig {
+	t.Helper()
+
+	msg := &lnwire.ClosingSig{
+		Signature: testNodeSig,
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChannelID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgTxAddInput(t testing.TB, r *rand.Rand) *lnwire.TxAddInput {
+	t.Helper()
+
+	msg := &lnwire.TxAddInput{
+		SerialID:  r.Uint64(),
+		PrevTx:    wire.NewMsgTx(2),
+		PrevTxOut: r.Uint32(),
+		Sequence:  r.Uint32(),
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChannelID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	var prevOut wire.OutPoint
+	_, err = r.Read(prevOut.Hash[:])
+	require.NoError(t, err, "unable to generate txid")
+
+	msg.PrevTx.AddTxIn(wire.NewTxIn(&prevOut, []byte{}, nil))
+	msg.PrevTx.AddTxOut(wire.NewTxOut(r.Int63(), []byte{0x00}))
+
+	return msg
+}
+
+func newMsgTxAddOutput(t testing.TB, r *rand.Rand) *lnwire.TxAddOutput {
+	t.Helper()
+
+	msg := &lnwire.TxAddOutput{
+		SerialID:  r.Uint64(),
+		Amount:    btcutil.Amount(r.Int63()),
+		PkScript:  make([]byte, 22),
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChannelID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	_, err = r.Read(msg.PkScript)
+	require.NoError(t, err, "unable to generate pkscript")
+
+	return msg
+}
+
+func newMsgTxRemoveInput(t testing.TB, r *rand.Rand) *lnwire.TxRemoveInput {
+	t.Helper()
+
+	msg := &lnwire.TxRemoveInput{
+		SerialID:  r.Uint64(),
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChannelID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgTxRemoveOutput(t testing.TB, r *rand.Rand) *lnwire.TxRemoveOutput {
+	t.Helper()
+
+	msg := &lnwire.TxRemoveOutput{
+		SerialID:  r.Uint64(),
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChannelID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgTxComplete(t testing.TB, r *rand.Rand) *lnwire.TxComplete {
+	t.Helper()
+
+	msg := &lnwire.TxComplete{
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChannelID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgTxSignatures(t testing.TB, r *rand.Rand) *lnwire.TxSignatures {
+	t.Helper()
+
+	msg := &lnwire.TxSignatures{
+		Witnesses: []wire.TxWitness{
+			{testNodeSig.ToSignatureBytes(), make([]byte, 33)},
+		},
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChannelID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	_, err = r.Read(msg.TxHash[:])
+	require.NoError(t, err, "unable to generate txid")
+
+	return msg
+}
+
+func newMsgTxAbort(t testing.TB, r *rand.Rand) *lnwire.TxAbort {
+	t.Helper()
+
+	msg := &lnwire.TxAbort{
+		Data:      createExtraData(t, r),
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChannelID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgStfu(t testing.TB, r *rand.Rand) *lnwire.Stfu {
+	t.Helper()
+
+	msg := &lnwire.Stfu{
+		Initiator: r.Int31()%2 == 0,
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgDynPropose(t testing.TB, r *rand.Rand) *lnwire.DynPropose {
+	t.Helper()
+
+	chanType := lnwire.ChannelType(*lnwire.NewRawFeatureVector(
+		lnwire.StaticRemoteKeyRequired,
+		lnwire.AnchorsZeroFeeHtlcTxRequired,
+	))
+	msg := &lnwire.DynPropose{
+		ChannelType: &chanType,
+		ExtraData:   createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgDynAck(t testing.TB, r *rand.Rand) *lnwire.DynAck {
+	t.Helper()
+
+	msg := &lnwire.DynAck{
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgDynReject(t testing.TB, r *rand.Rand) *lnwire.DynReject {
+	t.Helper()
+
+	msg := &lnwire.DynReject{
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgSpliceInit(t testing.TB, r *rand.Rand) *lnwire.SpliceInit {
+	t.Helper()
+
+	msg := &lnwire.SpliceInit{
+		RelativeSatoshis: r.Int63() - r.Int63(),
+		FundingFeePerKw:  uint32(r.Int31()),
+		LockTime:         uint32(r.Int31()),
+		FundingPubKey:    randPubKey(t),
+		ExtraData:        createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgSpliceAck(t testing.TB, r *rand.Rand) *lnwire.SpliceAck {
+	t.Helper()
+
+	msg := &lnwire.SpliceAck{
+		RelativeSatoshis: r.Int63() - r.Int63(),
+		FundingPubKey:    randPubKey(t),
+		ExtraData:        createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgSpliceCreated(t testing.TB, r *rand.Rand) *lnwire.SpliceCreated {
+	t.Helper()
+
+	msg := &lnwire.SpliceCreated{
+		SpliceTx:  wire.NewMsgTx(2),
+		CommitSig: testNodeSig,
+		HtlcSigs:  []lnwire.Sig{testNodeSig},
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	var prevOut wire.OutPoint
+	_, err = r.Read(prevOut.Hash[:])
+	require.NoError(t, err, "unable to generate txid")
+
+	msg.SpliceTx.AddTxIn(wire.NewTxIn(&prevOut, []byte{}, nil))
+	msg.SpliceTx.AddTxOut(wire.NewTxOut(r.Int63(), []byte{0x00}))
+
+	return msg
+}
+
+func newMsgSpliceSigned(t testing.TB, r *rand.Rand) *lnwire.SpliceSigned {
+	t.Helper()
+
+	msg := &lnwire.SpliceSigned{
+		CommitSig:  testNodeSig,
+		HtlcSigs:   []lnwire.Sig{testNodeSig},
+		FundingSig: testNodeSig,
+		ExtraData:  createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	return msg
+}
+
+func newMsgSpliceLocked(t testing.TB, r *rand.Rand) *lnwire.SpliceLocked {
+	t.Helper()
+
+	msg := &lnwire.SpliceLocked{
+		ExtraData: createExtraData(t, r),
+	}
+
+	_, err := r.Read(msg.ChanID[:])
+	require.NoError(t, err, "unable to generate chan id")
+
+	_, err = r.Read(msg.SpliceTxid[:])
+	require.NoError(t, err, "unable to generate txid")
+
+	return msg
+}
+
 func newMsgUpdateAddHTLC(t testing.TB, r *rand.Rand) *lnwire.UpdateAddHTLC {
 	t.Helper()
 
@@ -541,13 +855,19 @@ func newMsgCommitSig(t testing.TB, r io.Reader) *lnwire.CommitSig {
 	require.NoError(t, err, "unable to generate chan id")
 
 	msg.CommitSig = testNodeSig
-	msg.ExtraData = createExtraData(t, r)
 
 	msg.HtlcSigs = make([]lnwire.Sig, testNumSigs)
 	for i := 0; i < testNumSigs; i++ {
 		msg.HtlcSigs[i] = testNodeSig
 	}
 
+	// The extra data is parsed as a TLV stream, so we'll sign the variant
+	// of a pending splice instead of appending random bytes.
+	msg.SpliceSig = &lnwire.SpliceCommitSig{
+		CommitSig: testNodeSig,
+		HtlcSigs:  msg.HtlcSigs,
+	}
+
 	return msg
 }
 
@@ -861,12 +1181,50 @@ func newMsgGossipTimestampRange(t testing.TB,
 	return msg
 }
 
+func newMsgOnionMessage(t testing.TB, r *rand.Rand) *lnwire.OnionMessage {
+	t.Helper()
+
+	msg := lnwire.NewOnionMessage(randPubKey(t), make([]byte, 1366))
+	msg.ExtraData = createExtraData(t, r)
+
+	_, err := r.Read(msg.OnionBlob)
+	require.NoError(t, err, "unable to generate onion blob")
+
+	return msg
+}
+
+func newMsgPeerStorage(t testing.TB, r *rand.Rand) *lnwire.PeerStorage {
+	t.Helper()
+
+	msg := lnwire.NewPeerStorage(make([]byte, 1000))
+	msg.ExtraData = createExtraData(t, r)
+
+	_, err := r.Read(msg.Blob)
+	require.NoError(t, err, "unable to generate blob")
+
+	return msg
+}
+
+func newMsgPeerStorageRetrieval(t testing.TB,
+	r *rand.Rand) *lnwire.PeerStorageRetrieval {
+
+	t.Helper()
+
+	msg := lnwire.NewPeerStorageRetrieval(make([]byte, 1000))
+	msg.ExtraData = createExtraData(t, r)
+
+	_, err := r.Read(msg.Blob)
+	require.NoError(t, err, "unable to generate blob")
+
+	return msg
+}
+
 func randRawKey(t testing.TB) [33]byte {
 	t.Helper()
 
 	var n [33]byte
 
-	priv, err := btcec.NewPrivateKey(btcec.S256())
+	priv, err := btcec.NewPrivateKey()
 	require.NoError(t, err, "failed to create privKey")
 
 	copy(n[:], priv.PubKey().SerializeCompressed())
@@ -877,7 +1235,7 @@ func randRawKey(t testing.TB) [33]byte {
 func randPubKey(t testing.TB) *btcec.PublicKey {
 	t.Helper()
 
-	priv, err := btcec.NewPrivateKey(btcec.S256())
+	priv, err := btcec.NewPrivateKey()
 	require.NoError(t, err, "failed to create pubkey")
 
 	return priv.PubKey()
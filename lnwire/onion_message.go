@@ -0,0 +1,93 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// OnionMessage is a message that is routed through the network along a
+// blinded path, without being tied to a channel or payment. Each node along
+// the path peels a layer off the onion to learn the next node, while the
+// blinding point allows it to decrypt the data the sender left for it.
+type OnionMessage struct {
+	// BlindingPoint is the ephemeral public key the receiving node uses
+	// to derive its blinded node key and decrypt its data.
+	BlindingPoint *btcec.PublicKey
+
+	// OnionBlob is the serialized onion packet destined for the receiving
+	// node.
+	OnionBlob []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// NewOnionMessage creates a new OnionMessage message.
+func NewOnionMessage(blindingPoint *btcec.PublicKey,
+	onionBlob []byte) *OnionMessage {
+
+	return &OnionMessage{
+		BlindingPoint: blindingPoint,
+		OnionBlob:     onionBlob,
+		ExtraData:     make([]byte, 0),
+	}
+}
+
+// A compile-time check to ensure OnionMessage implements the lnwire.Message
+// interface.
+var _ Message = (*OnionMessage)(nil)
+
+// Decode deserializes a serialized OnionMessage message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) Decode(r io.Reader, pver uint32) error {
+	if err := ReadElement(r, &o.BlindingPoint); err != nil {
+		return err
+	}
+
+	var l [2]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return err
+	}
+
+	o.OnionBlob = make([]byte, binary.BigEndian.Uint16(l[:]))
+	if _, err := io.ReadFull(r, o.OnionBlob); err != nil {
+		return err
+	}
+
+	return ReadElement(r, &o.ExtraData)
+}
+
+// Encode serializes the target OnionMessage into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WritePublicKey(w, o.BlindingPoint); err != nil {
+		return err
+	}
+
+	if err := WriteUint16(w, uint16(len(o.OnionBlob))); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, o.OnionBlob); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, o.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (o *OnionMessage) MsgType() MessageType {
+	return MsgOnionMessage
+}
@@ -4,9 +4,9 @@ import (
 	"bytes"
 	"io"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/tlv"
 )
 
@@ -135,6 +135,14 @@ type OpenChannel struct {
 	// open.
 	ChannelType *ChannelType
 
+	// DualFundingRequest is an optional request of the initiator that the
+	// responder contributes funds to the channel.
+	DualFundingRequest *DualFundingRequest
+
+	// LeaseRequest is an optional request of the initiator to lease the
+	// funds the responder contributes to a dual funded channel.
+	LeaseRequest *LeaseRequest
+
 	// ExtraData is the set of data that was appended to this message to
 	// fill out the full maximum transport message size. These fields can
 	// be used to specify optional data such as custom TLV fields.
@@ -160,6 +168,12 @@ func (o *OpenChannel) Encode(w *bytes.Buffer, pver uint32) error {
 	if o.ChannelType != nil {
 		recordProducers = append(recordProducers, o.ChannelType)
 	}
+	if o.DualFundingRequest != nil {
+		recordProducers = append(recordProducers, o.DualFundingRequest)
+	}
+	if o.LeaseRequest != nil {
+		recordProducers = append(recordProducers, o.LeaseRequest)
+	}
 	err := EncodeMessageExtraData(&o.ExtraData, recordProducers...)
 	if err != nil {
 		return err
@@ -282,9 +296,14 @@ func (o *OpenChannel) Decode(r io.Reader, pver uint32) error {
 
 	// Next we'll parse out the set of known records, keeping the raw tlv
 	// bytes untouched to ensure we don't drop any bytes erroneously.
-	var chanType ChannelType
+	var (
+		chanType           ChannelType
+		dualFundingRequest DualFundingRequest
+		leaseRequest       LeaseRequest
+	)
 	typeMap, err := tlvRecords.ExtractRecords(
-		&o.UpfrontShutdownScript, &chanType,
+		&o.UpfrontShutdownScript, &chanType, &dualFundingRequest,
+		&leaseRequest,
 	)
 	if err != nil {
 		return err
@@ -294,6 +313,12 @@ func (o *OpenChannel) Decode(r io.Reader, pver uint32) error {
 	if val, ok := typeMap[ChannelTypeRecordType]; ok && val == nil {
 		o.ChannelType = &chanType
 	}
+	if val, ok := typeMap[DualFundingRecordType]; ok && val == nil {
+		o.DualFundingRequest = &dualFundingRequest
+	}
+	if val, ok := typeMap[LeaseRequestRecordType]; ok && val == nil {
+		o.LeaseRequest = &leaseRequest
+	}
 
 	o.ExtraData = tlvRecords
 
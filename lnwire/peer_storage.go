@@ -0,0 +1,96 @@
+package lnwire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// MaxPeerStorageBytes is the maximum size of the blob a node may ask its peer
+// to store. It's the largest blob that fits into a message along with its
+// type and length prefix.
+const MaxPeerStorageBytes = 65531
+
+// PeerStorage is sent by a node to ask its peer to hold on to an opaque blob
+// of data, typically an encrypted backup of the node's channels. The peer
+// replaces any blob it previously stored for the node, and returns the blob
+// in a PeerStorageRetrieval message whenever the node reconnects.
+type PeerStorage struct {
+	// Blob is the opaque data the peer is asked to store.
+	Blob []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// NewPeerStorage creates a new PeerStorage message.
+func NewPeerStorage(blob []byte) *PeerStorage {
+	return &PeerStorage{
+		Blob:      blob,
+		ExtraData: make([]byte, 0),
+	}
+}
+
+// A compile-time check to ensure PeerStorage implements the lnwire.Message
+// interface.
+var _ Message = (*PeerStorage)(nil)
+
+// Decode deserializes a serialized PeerStorage message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) Decode(r io.Reader, pver uint32) error {
+	blob, err := readPeerStorageBlob(r)
+	if err != nil {
+		return err
+	}
+	p.Blob = blob
+
+	return ReadElement(r, &p.ExtraData)
+}
+
+// Encode serializes the target PeerStorage into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := writePeerStorageBlob(w, p.Blob); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, p.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) MsgType() MessageType {
+	return MsgPeerStorage
+}
+
+// readPeerStorageBlob reads a length prefixed peer storage blob.
+func readPeerStorageBlob(r io.Reader) ([]byte, error) {
+	var l [2]byte
+	if _, err := io.ReadFull(r, l[:]); err != nil {
+		return nil, err
+	}
+
+	blob := make([]byte, binary.BigEndian.Uint16(l[:]))
+	if _, err := io.ReadFull(r, blob); err != nil {
+		return nil, err
+	}
+
+	return blob, nil
+}
+
+// writePeerStorageBlob writes a length prefixed peer storage blob.
+func writePeerStorageBlob(w *bytes.Buffer, blob []byte) error {
+	if err := WriteUint16(w, uint16(len(blob))); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, blob)
+}
@@ -0,0 +1,66 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// PeerStorageRetrieval is sent by a node that stores a blob on behalf of its
+// peer whenever the peer reconnects. It returns the latest blob the peer sent
+// in a PeerStorage message, which allows the peer to recover its channels if
+// it lost its data.
+type PeerStorageRetrieval struct {
+	// Blob is the opaque data the peer asked us to store.
+	Blob []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// NewPeerStorageRetrieval creates a new PeerStorageRetrieval message.
+func NewPeerStorageRetrieval(blob []byte) *PeerStorageRetrieval {
+	return &PeerStorageRetrieval{
+		Blob:      blob,
+		ExtraData: make([]byte, 0),
+	}
+}
+
+// A compile-time check to ensure PeerStorageRetrieval implements the
+// lnwire.Message interface.
+var _ Message = (*PeerStorageRetrieval)(nil)
+
+// Decode deserializes a serialized PeerStorageRetrieval message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) Decode(r io.Reader, pver uint32) error {
+	blob, err := readPeerStorageBlob(r)
+	if err != nil {
+		return err
+	}
+	p.Blob = blob
+
+	return ReadElement(r, &p.ExtraData)
+}
+
+// Encode serializes the target PeerStorageRetrieval into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := writePeerStorageBlob(w, p.Blob); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, p.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) MsgType() MessageType {
+	return MsgPeerStorageRetrieval
+}
@@ -0,0 +1,81 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// SpliceAck is sent by the non-initiator of a splice to accept the proposal
+// of a SpliceInit message.
+type SpliceAck struct {
+	// ChanID identifies the channel that is spliced.
+	ChanID ChannelID
+
+	// RelativeSatoshis is the amount the sender adds to the channel. A
+	// negative amount means that funds are removed from the channel.
+	RelativeSatoshis int64
+
+	// FundingPubKey is the key the sender uses within the multi-sig script
+	// of the new funding output.
+	FundingPubKey *btcec.PublicKey
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile-time check to ensure SpliceAck implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceAck)(nil)
+
+// Decode deserializes a serialized SpliceAck message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) Decode(r io.Reader, pver uint32) error {
+	var relativeSatoshis uint64
+	err := ReadElements(r,
+		&s.ChanID,
+		&relativeSatoshis,
+		&s.FundingPubKey,
+		&s.ExtraData,
+	)
+	if err != nil {
+		return err
+	}
+
+	s.RelativeSatoshis = int64(relativeSatoshis)
+
+	return nil
+}
+
+// Encode serializes the target SpliceAck message into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteUint64(w, uint64(s.RelativeSatoshis)); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, s.FundingPubKey); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceAck) MsgType() MessageType {
+	return MsgSpliceAck
+}
@@ -0,0 +1,101 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// SpliceCommitSigRecordType is the type of the record within the extra
+	// data of a commitment_signed message that carries the signatures for
+	// the variant of the commitment that spends the funding output of a
+	// pending splice.
+	SpliceCommitSigRecordType tlv.Type = 1
+
+	// sigLen is the length of an encoded signature.
+	sigLen = 64
+)
+
+// SpliceCommitSig holds the signatures for the variant of a new commitment
+// that spends the funding output created by a pending splice. While a splice
+// transaction hasn't been locked in, every commitment is signed for both the
+// current and the new funding output, so the channel can be used as normal
+// while the splice transaction confirms.
+type SpliceCommitSig struct {
+	// CommitSig is the signature for the commitment transaction that
+	// spends the new funding output.
+	CommitSig Sig
+
+	// HtlcSigs are the signatures for the HTLC transactions of the
+	// commitment that spends the new funding output, in the same order as
+	// for a regular commitment_signed message.
+	HtlcSigs []Sig
+}
+
+// Record returns a TLV record that can be used to encode/decode the splice
+// signatures from a given TLV stream.
+func (s *SpliceCommitSig) Record() tlv.Record {
+	sizeFunc := func() uint64 {
+		return uint64(sigLen * (1 + len(s.HtlcSigs)))
+	}
+
+	return tlv.MakeDynamicRecord(
+		SpliceCommitSigRecordType, s, sizeFunc, spliceCommitSigEncoder,
+		spliceCommitSigDecoder,
+	)
+}
+
+// spliceCommitSigEncoder is a custom TLV encoder for the SpliceCommitSig
+// record.
+func spliceCommitSigEncoder(w io.Writer, val interface{}, _ *[8]byte) error {
+	if v, ok := val.(*SpliceCommitSig); ok {
+		if _, err := w.Write(v.CommitSig[:]); err != nil {
+			return err
+		}
+
+		for _, sig := range v.HtlcSigs {
+			if _, err := w.Write(sig[:]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "lnwire.SpliceCommitSig")
+}
+
+// spliceCommitSigDecoder is a custom TLV decoder for the SpliceCommitSig
+// record.
+func spliceCommitSigDecoder(r io.Reader, val interface{}, _ *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*SpliceCommitSig); ok && l >= sigLen &&
+		l%sigLen == 0 {
+
+		if _, err := io.ReadFull(r, v.CommitSig[:]); err != nil {
+			return err
+		}
+
+		numHtlcSigs := l/sigLen - 1
+		if numHtlcSigs == 0 {
+			v.HtlcSigs = nil
+			return nil
+		}
+
+		v.HtlcSigs = make([]Sig, numHtlcSigs)
+		for i := range v.HtlcSigs {
+			_, err := io.ReadFull(r, v.HtlcSigs[i][:])
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(
+		val, "lnwire.SpliceCommitSig", l, sigLen,
+	)
+}
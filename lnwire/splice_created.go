@@ -0,0 +1,107 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// SpliceCreated is sent by the initiator of a splice once the splice has been
+// accepted. It carries the unsigned splice transaction, which spends the
+// current funding output of the channel, along with the signature of the
+// initiator for the remote party's commitment transaction that spends the new
+// funding output.
+//
+// NOTE: This message, together with SpliceSigned, takes the place of the
+// interactive transaction construction of the splicing proposal. It is only
+// sent to peers that signal the experimental splicing feature bit.
+type SpliceCreated struct {
+	// ChanID identifies the channel that is spliced.
+	ChanID ChannelID
+
+	// SpliceTx is the unsigned splice transaction.
+	SpliceTx *wire.MsgTx
+
+	// CommitSig is the signature of the initiator for the commitment
+	// transaction of the receiver that spends the new funding output.
+	CommitSig Sig
+
+	// HtlcSigs are the signatures of the initiator for the HTLC
+	// transactions of the commitment of the receiver that spends the new
+	// funding output.
+	HtlcSigs []Sig
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile-time check to ensure SpliceCreated implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceCreated)(nil)
+
+// Decode deserializes a serialized SpliceCreated message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceCreated) Decode(r io.Reader, pver uint32) error {
+	if err := ReadElement(r, &s.ChanID); err != nil {
+		return err
+	}
+
+	var txLen uint16
+	if err := ReadElement(r, &txLen); err != nil {
+		return err
+	}
+
+	txBytes := make([]byte, txLen)
+	if _, err := io.ReadFull(r, txBytes); err != nil {
+		return err
+	}
+
+	s.SpliceTx = &wire.MsgTx{}
+	if err := s.SpliceTx.Deserialize(bytes.NewReader(txBytes)); err != nil {
+		return err
+	}
+
+	return ReadElements(r, &s.CommitSig, &s.HtlcSigs, &s.ExtraData)
+}
+
+// Encode serializes the target SpliceCreated message into the passed
+// io.Writer observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceCreated) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	var txBytes bytes.Buffer
+	if err := s.SpliceTx.Serialize(&txBytes); err != nil {
+		return err
+	}
+
+	if err := writeDataWithLength(w, txBytes.Bytes()); err != nil {
+		return err
+	}
+
+	if err := WriteSig(w, s.CommitSig); err != nil {
+		return err
+	}
+
+	if err := WriteSigs(w, s.HtlcSigs); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceCreated) MsgType() MessageType {
+	return MsgSpliceCreated
+}
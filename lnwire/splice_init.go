@@ -0,0 +1,100 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// SpliceInit is sent by the initiator of a splice once the channel is
+// quiescent. It proposes to change the funding output of the channel by the
+// given relative amount, which is added to or removed from the balance of the
+// initiator.
+type SpliceInit struct {
+	// ChanID identifies the channel that should be spliced.
+	ChanID ChannelID
+
+	// RelativeSatoshis is the amount the initiator adds to the channel. A
+	// negative amount means that funds are removed from the channel.
+	RelativeSatoshis int64
+
+	// FundingFeePerKw is the fee rate the initiator uses for the splice
+	// transaction.
+	FundingFeePerKw uint32
+
+	// LockTime is the lock time of the splice transaction.
+	LockTime uint32
+
+	// FundingPubKey is the key the initiator uses within the multi-sig
+	// script of the new funding output.
+	FundingPubKey *btcec.PublicKey
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile-time check to ensure SpliceInit implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceInit)(nil)
+
+// Decode deserializes a serialized SpliceInit message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) Decode(r io.Reader, pver uint32) error {
+	var relativeSatoshis uint64
+	err := ReadElements(r,
+		&s.ChanID,
+		&relativeSatoshis,
+		&s.FundingFeePerKw,
+		&s.LockTime,
+		&s.FundingPubKey,
+		&s.ExtraData,
+	)
+	if err != nil {
+		return err
+	}
+
+	s.RelativeSatoshis = int64(relativeSatoshis)
+
+	return nil
+}
+
+// Encode serializes the target SpliceInit message into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteUint64(w, uint64(s.RelativeSatoshis)); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, s.FundingFeePerKw); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, s.LockTime); err != nil {
+		return err
+	}
+
+	if err := WritePublicKey(w, s.FundingPubKey); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceInit) MsgType() MessageType {
+	return MsgSpliceInit
+}
@@ -0,0 +1,78 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// SpliceLocked is sent by both parties of a splice once the splice
+// transaction has reached a sufficient number of confirmations. Once both
+// parties have sent and received the message, the channel uses the new
+// funding output and may leave the quiescent state.
+type SpliceLocked struct {
+	// ChanID identifies the channel that is spliced.
+	ChanID ChannelID
+
+	// SpliceTxid is the txid of the splice transaction that confirmed.
+	SpliceTxid chainhash.Hash
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// NewSpliceLocked creates a new SpliceLocked message.
+func NewSpliceLocked(cid ChannelID, txid chainhash.Hash) *SpliceLocked {
+	return &SpliceLocked{
+		ChanID:     cid,
+		SpliceTxid: txid,
+		ExtraData:  make([]byte, 0),
+	}
+}
+
+// A compile-time check to ensure SpliceLocked implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceLocked)(nil)
+
+// Decode deserializes a serialized SpliceLocked message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceLocked) Decode(r io.Reader, pver uint32) error {
+	if err := ReadElement(r, &s.ChanID); err != nil {
+		return err
+	}
+
+	if _, err := io.ReadFull(r, s.SpliceTxid[:]); err != nil {
+		return err
+	}
+
+	return ReadElement(r, &s.ExtraData)
+}
+
+// Encode serializes the target SpliceLocked message into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceLocked) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, s.SpliceTxid[:]); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceLocked) MsgType() MessageType {
+	return MsgSpliceLocked
+}
@@ -0,0 +1,85 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// SpliceSigned is sent by the non-initiator of a splice in response to a
+// SpliceCreated message. It carries the signature for the initiator's
+// commitment transaction that spends the new funding output, and the
+// signature for the input of the splice transaction that spends the current
+// funding output. After sending this message, the sender must be prepared for
+// the splice transaction to confirm.
+type SpliceSigned struct {
+	// ChanID identifies the channel that is spliced.
+	ChanID ChannelID
+
+	// CommitSig is the signature of the sender for the commitment
+	// transaction of the receiver that spends the new funding output.
+	CommitSig Sig
+
+	// HtlcSigs are the signatures of the sender for the HTLC transactions
+	// of the commitment of the receiver that spends the new funding
+	// output.
+	HtlcSigs []Sig
+
+	// FundingSig is the signature of the sender for the input of the
+	// splice transaction that spends the current funding output.
+	FundingSig Sig
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile-time check to ensure SpliceSigned implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceSigned)(nil)
+
+// Decode deserializes a serialized SpliceSigned message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceSigned) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r,
+		&s.ChanID,
+		&s.CommitSig,
+		&s.HtlcSigs,
+		&s.FundingSig,
+		&s.ExtraData,
+	)
+}
+
+// Encode serializes the target SpliceSigned message into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceSigned) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteSig(w, s.CommitSig); err != nil {
+		return err
+	}
+
+	if err := WriteSigs(w, s.HtlcSigs); err != nil {
+		return err
+	}
+
+	if err := WriteSig(w, s.FundingSig); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *SpliceSigned) MsgType() MessageType {
+	return MsgSpliceSigned
+}
@@ -0,0 +1,69 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// Stfu is sent by either side of a channel to request that the channel enters
+// a quiescent state. Once both sides have sent the message, and all pending
+// updates have been irrevocably committed, no further channel updates may be
+// proposed until the operation that required quiescence has completed.
+type Stfu struct {
+	// ChanID identifies the channel that should become quiescent.
+	ChanID ChannelID
+
+	// Initiator is true if the sender of this message is the party that
+	// requested quiescence, and false if the message is sent in response
+	// to a request of the remote party.
+	Initiator bool
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// NewStfu creates a new Stfu message.
+func NewStfu(cid ChannelID, initiator bool) *Stfu {
+	return &Stfu{
+		ChanID:    cid,
+		Initiator: initiator,
+		ExtraData: make([]byte, 0),
+	}
+}
+
+// A compile-time check to ensure Stfu implements the lnwire.Message interface.
+var _ Message = (*Stfu)(nil)
+
+// Decode deserializes a serialized Stfu message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *Stfu) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r, &s.ChanID, &s.Initiator, &s.ExtraData)
+}
+
+// Encode serializes the target Stfu message into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *Stfu) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, s.ChanID); err != nil {
+		return err
+	}
+
+	if err := WriteBool(w, s.Initiator); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, s.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *Stfu) MsgType() MessageType {
+	return MsgStfu
+}
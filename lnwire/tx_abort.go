@@ -0,0 +1,63 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+)
+
+// TxAbort is sent by either party to abort the negotiation of a transaction
+// that hasn't been signed yet. Both parties forget about the aborted
+// transaction, but the connection and the channel remain intact.
+type TxAbort struct {
+	// ChannelID identifies the channel the transaction was negotiated
+	// for.
+	ChannelID ChannelID
+
+	// Data is an optional human readable reason for the abort.
+	Data ErrorData
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile-time check to ensure TxAbort implements the lnwire.Message
+// interface.
+var _ Message = (*TxAbort)(nil)
+
+// Decode deserializes a serialized TxAbort message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAbort) Decode(r io.Reader, pver uint32) error {
+	return ReadElements(r,
+		&t.ChannelID,
+		&t.Data,
+		&t.ExtraData,
+	)
+}
+
+// Encode serializes the target TxAbort message into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAbort) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, t.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteErrorData(w, t.Data); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAbort) MsgType() MessageType {
+	return MsgTxAbort
+}
@@ -0,0 +1,106 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/wire"
+)
+
+// TxAddInput is sent by either party during the interactive construction of
+// a transaction to add one of its inputs to the transaction. The full
+// previous transaction is included, which allows the receiver to verify the
+// amount and script of the spent output.
+type TxAddInput struct {
+	// ChannelID identifies the channel the transaction is constructed
+	// for. During a channel open, this is the pending channel ID.
+	ChannelID ChannelID
+
+	// SerialID is a unique ID for this input within the transaction. The
+	// initiator of the construction only uses even serial IDs, the
+	// responder only odd ones. The final inputs are sorted by their serial
+	// IDs.
+	SerialID uint64
+
+	// PrevTx is the transaction that contains the output spent by the
+	// input.
+	PrevTx *wire.MsgTx
+
+	// PrevTxOut is the index of the spent output within PrevTx.
+	PrevTxOut uint32
+
+	// Sequence is the sequence number of the input.
+	Sequence uint32
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile-time check to ensure TxAddInput implements the lnwire.Message
+// interface.
+var _ Message = (*TxAddInput)(nil)
+
+// Decode deserializes a serialized TxAddInput message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddInput) Decode(r io.Reader, pver uint32) error {
+	if err := ReadElements(r, &t.ChannelID, &t.SerialID); err != nil {
+		return err
+	}
+
+	txBytes, err := readDataWithLength(r)
+	if err != nil {
+		return err
+	}
+
+	t.PrevTx = &wire.MsgTx{}
+	if err := t.PrevTx.Deserialize(bytes.NewReader(txBytes)); err != nil {
+		return err
+	}
+
+	return ReadElements(r, &t.PrevTxOut, &t.Sequence, &t.ExtraData)
+}
+
+// Encode serializes the target TxAddInput message into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddInput) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, t.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteUint64(w, t.SerialID); err != nil {
+		return err
+	}
+
+	var txBytes bytes.Buffer
+	if err := t.PrevTx.Serialize(&txBytes); err != nil {
+		return err
+	}
+
+	if err := writeDataWithLength(w, txBytes.Bytes()); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, t.PrevTxOut); err != nil {
+		return err
+	}
+
+	if err := WriteUint32(w, t.Sequence); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddInput) MsgType() MessageType {
+	return MsgTxAddInput
+}
@@ -0,0 +1,87 @@
+package lnwire
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+)
+
+// TxAddOutput is sent by either party during the interactive construction of
+// a transaction to add one of its outputs to the transaction.
+type TxAddOutput struct {
+	// ChannelID identifies the channel the transaction is constructed
+	// for. During a channel open, this is the pending channel ID.
+	ChannelID ChannelID
+
+	// SerialID is a unique ID for this output within the transaction. The
+	// initiator of the construction only uses even serial IDs, the
+	// responder only odd ones. The final outputs are sorted by their
+	// serial IDs.
+	SerialID uint64
+
+	// Amount is the value of the output.
+	Amount btcutil.Amount
+
+	// PkScript is the script of the output.
+	PkScript []byte
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile-time check to ensure TxAddOutput implements the lnwire.Message
+// interface.
+var _ Message = (*TxAddOutput)(nil)
+
+// Decode deserializes a serialized TxAddOutput message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddOutput) Decode(r io.Reader, pver uint32) error {
+	err := ReadElements(r, &t.ChannelID, &t.SerialID, &t.Amount)
+	if err != nil {
+		return err
+	}
+
+	t.PkScript, err = readDataWithLength(r)
+	if err != nil {
+		return err
+	}
+
+	return ReadElement(r, &t.ExtraData)
+}
+
+// Encode serializes the target TxAddOutput message into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddOutput) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, t.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteUint64(w, t.SerialID); err != nil {
+		return err
+	}
+
+	if err := WriteSatoshi(w, t.Amount); err != nil {
+		return err
+	}
+
+	if err := writeDataWithLength(w, t.PkScript); err != nil {
+		return err
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxAddOutput) MsgType() MessageType {
+	return MsgTxAddOutput
+}
@@ -0,0 +1,156 @@
+package lnwire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// TxSignatures is sent by both parties once the commitment transactions that
+// spend the funding output of an interactively constructed transaction have
+// been signed. It carries the witnesses for all inputs the sender added to
+// the transaction.
+type TxSignatures struct {
+	// ChannelID identifies the channel the transaction was constructed
+	// for.
+	ChannelID ChannelID
+
+	// TxHash is the txid of the transaction that is signed.
+	TxHash chainhash.Hash
+
+	// Witnesses are the witnesses for the inputs of the sender, in the
+	// order of their serial IDs.
+	Witnesses []wire.TxWitness
+
+	// ExtraData is the set of data that was appended to this message to
+	// fill out the full maximum transport message size. These fields can
+	// be used to specify optional data such as custom TLV fields.
+	ExtraData ExtraOpaqueData
+}
+
+// A compile-time check to ensure TxSignatures implements the lnwire.Message
+// interface.
+var _ Message = (*TxSignatures)(nil)
+
+// Decode deserializes a serialized TxSignatures message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxSignatures) Decode(r io.Reader, pver uint32) error {
+	if err := ReadElement(r, &t.ChannelID); err != nil {
+		return err
+	}
+
+	if _, err := io.ReadFull(r, t.TxHash[:]); err != nil {
+		return err
+	}
+
+	var numWitnesses uint16
+	if err := ReadElement(r, &numWitnesses); err != nil {
+		return err
+	}
+
+	t.Witnesses = make([]wire.TxWitness, 0, numWitnesses)
+	for i := 0; i < int(numWitnesses); i++ {
+		witnessBytes, err := readDataWithLength(r)
+		if err != nil {
+			return err
+		}
+
+		witness, err := decodeWitness(bytes.NewReader(witnessBytes))
+		if err != nil {
+			return err
+		}
+		t.Witnesses = append(t.Witnesses, witness)
+	}
+
+	return ReadElement(r, &t.ExtraData)
+}
+
+// Encode serializes the target TxSignatures message into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxSignatures) Encode(w *bytes.Buffer, pver uint32) error {
+	if err := WriteChannelID(w, t.ChannelID); err != nil {
+		return err
+	}
+
+	if err := WriteBytes(w, t.TxHash[:]); err != nil {
+		return err
+	}
+
+	if err := WriteUint16(w, uint16(len(t.Witnesses))); err != nil {
+		return err
+	}
+
+	for _, witness := range t.Witnesses {
+		var witnessBytes bytes.Buffer
+		if err := encodeWitness(&witnessBytes, witness); err != nil {
+			return err
+		}
+
+		err := writeDataWithLength(w, witnessBytes.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+
+	return WriteBytes(w, t.ExtraData)
+}
+
+// MsgType returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (t *TxSignatures) MsgType() MessageType {
+	return MsgTxSignatures
+}
+
+// encodeWitness serializes a witness the same way it's serialized within a
+// transaction.
+func encodeWitness(w io.Writer, witness wire.TxWitness) error {
+	err := wire.WriteVarInt(w, 0, uint64(len(witness)))
+	if err != nil {
+		return err
+	}
+
+	for _, item := range witness {
+		if err := wire.WriteVarBytes(w, 0, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeWitness deserializes a witness that was serialized with
+// encodeWitness.
+func decodeWitness(r io.Reader) (wire.TxWitness, error) {
+	numItems, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each witness item is prefixed by at least one byte for its length,
+	// which lets us bound the number of items.
+	if numItems > MaxMsgBody {
+		return nil, fmt.Errorf("too many witness items: %v", numItems)
+	}
+
+	witness := make(wire.TxWitness, 0, numItems)
+	for i := uint64(0); i < numItems; i++ {
+		item, err := wire.ReadVarBytes(
+			r, 0, MaxMsgBody, "witness item",
+		)
+		if err != nil {
+			return nil, err
+		}
+		witness = append(witness, item)
+	}
+
+	return witness, nil
+}
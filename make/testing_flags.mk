@@ -58,6 +58,14 @@ ifeq ($(dbbackend),etcd)
 DEV_TAGS += kvdb_etcd
 endif
 
+ifeq ($(dbbackend),postgres)
+DEV_TAGS += kvdb_postgres
+endif
+
+ifeq ($(dbbackend),sqlite)
+DEV_TAGS += kvdb_sqlite
+endif
+
 ifneq ($(tags),)
 DEV_TAGS += ${tags}
 endif
@@ -0,0 +1,162 @@
+package offers
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// InvoiceRequestType is the onion message record type that carries an
+	// invoice request.
+	InvoiceRequestType = 64
+
+	// InvoiceType is the onion message record type that carries the
+	// invoice sent in reply to an invoice request.
+	InvoiceType = 66
+
+	// InvoiceErrorType is the onion message record type that carries the
+	// reason an invoice request was rejected.
+	InvoiceErrorType = 68
+
+	// invReqOfferType is the record type of the offer an invoice is
+	// requested for.
+	invReqOfferType tlv.Type = 0
+
+	// invReqAmountType is the record type of the amount the payer wants
+	// to pay.
+	invReqAmountType tlv.Type = 2
+
+	// invReqPayerNoteType is the record type of the note the payer leaves
+	// for the issuer of the offer.
+	invReqPayerNoteType tlv.Type = 4
+
+	// invReqReplyPathType is the record type of the blinded path the
+	// invoice is sent back over.
+	invReqReplyPathType tlv.Type = 6
+)
+
+// InvoiceRequest is sent by a payer to the issuer of an offer to request an
+// invoice for it.
+type InvoiceRequest struct {
+	// Offer is the offer the invoice is requested for.
+	Offer *Offer
+
+	// Amount is the amount the payer wants to pay. It is zero if the payer
+	// pays the amount of the offer.
+	Amount lnwire.MilliSatoshi
+
+	// PayerNote is an optional note the payer leaves for the issuer.
+	PayerNote string
+
+	// ReplyPath is the blinded path to the payer the invoice is sent back
+	// over.
+	ReplyPath *blindedpath.BlindedPath
+}
+
+// Encode serializes the invoice request as a TLV stream.
+func (r *InvoiceRequest) Encode() ([]byte, error) {
+	if r.ReplyPath == nil {
+		return nil, errors.New("invoice request is missing reply path")
+	}
+
+	offer, err := r.Offer.Serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	var replyPath bytes.Buffer
+	if err := r.ReplyPath.Encode(&replyPath); err != nil {
+		return nil, err
+	}
+
+	var (
+		amount     = uint64(r.Amount)
+		payerNote  = []byte(r.PayerNote)
+		replyBytes = replyPath.Bytes()
+	)
+
+	records := []tlv.Record{
+		tlv.MakePrimitiveRecord(invReqOfferType, &offer),
+	}
+
+	if amount != 0 {
+		records = append(records, tlv.MakeDynamicRecord(
+			invReqAmountType, &amount, func() uint64 {
+				return tlv.SizeTUint64(amount)
+			}, tlv.ETUint64, tlv.DTUint64,
+		))
+	}
+
+	if len(payerNote) > 0 {
+		records = append(records, tlv.MakePrimitiveRecord(
+			invReqPayerNoteType, &payerNote,
+		))
+	}
+
+	records = append(records, tlv.MakePrimitiveRecord(
+		invReqReplyPathType, &replyBytes,
+	))
+
+	return encodeRecords(records...)
+}
+
+// DecodeInvoiceRequest parses an invoice request and verifies the signature
+// of the offer it refers to.
+func DecodeInvoiceRequest(b []byte) (*InvoiceRequest, error) {
+	var (
+		offer     []byte
+		amount    uint64
+		payerNote []byte
+		replyPath []byte
+	)
+
+	tlvStream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(invReqOfferType, &offer),
+		tlv.MakeDynamicRecord(
+			invReqAmountType, &amount, nil, tlv.ETUint64,
+			tlv.DTUint64,
+		),
+		tlv.MakePrimitiveRecord(invReqPayerNoteType, &payerNote),
+		tlv.MakePrimitiveRecord(invReqReplyPathType, &replyPath),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := tlvStream.DecodeWithParsedTypes(
+		bytes.NewReader(b),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := parsedTypes[invReqOfferType]; !ok {
+		return nil, errors.New("invoice request is missing offer")
+	}
+	if _, ok := parsedTypes[invReqReplyPathType]; !ok {
+		return nil, errors.New("invoice request is missing reply path")
+	}
+
+	r := &InvoiceRequest{
+		Amount:    lnwire.MilliSatoshi(amount),
+		PayerNote: string(payerNote),
+	}
+
+	r.Offer, err = Deserialize(offer)
+	if err != nil {
+		return nil, err
+	}
+
+	r.ReplyPath, err = blindedpath.DecodeBlindedPath(
+		bytes.NewReader(replyPath),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
@@ -0,0 +1,456 @@
+package offers
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+const (
+	// DefaultRequestTimeout is the default time we wait for the issuer of
+	// an offer to reply to our invoice request.
+	DefaultRequestTimeout = 30 * time.Second
+)
+
+var (
+	// ErrRequestTimeout is returned when the issuer of an offer doesn't
+	// reply to our invoice request in time.
+	ErrRequestTimeout = errors.New("timeout waiting for invoice")
+
+	// ErrManagerShuttingDown is returned when an invoice is requested
+	// while the manager is shutting down.
+	ErrManagerShuttingDown = errors.New("offer manager shutting down")
+)
+
+// Config holds the dependencies of the offer manager.
+type Config struct {
+	// NodeID is the public key of our node, which issues our offers.
+	NodeID *btcec.PublicKey
+
+	// Chain is the genesis hash of the chain we operate on.
+	Chain chainhash.Hash
+
+	// SignMessage signs the sha256 digest of the passed message with the
+	// key of our node.
+	SignMessage func(msg []byte) (*ecdsa.Signature, error)
+
+	// CreateInvoice creates a fresh invoice for one of our offers and
+	// returns its encoded payment request.
+	CreateInvoice func(offer *Offer, amt lnwire.MilliSatoshi,
+		payerNote string) (string, error)
+
+	// DecodeInvoice decodes an invoice received in reply to one of our
+	// invoice requests.
+	DecodeInvoice func(payReq string) (*zpay32.Invoice, error)
+
+	// FindPath returns a path of nodes over which we can send onion
+	// messages to the target node. The first node of the path must be a
+	// peer of ours and the last one the target.
+	FindPath func(target *btcec.PublicKey) ([]*btcec.PublicKey, error)
+
+	// SendOnionMessage sends an onion message along a path of nodes.
+	SendOnionMessage func(path []*btcec.PublicKey,
+		records record.CustomSet) error
+
+	// SendBlindedOnionMessage sends an onion message along a blinded
+	// path.
+	SendBlindedOnionMessage func(path *blindedpath.BlindedPath,
+		records record.CustomSet) error
+
+	// RequestTimeout is the time we wait for the reply to an invoice
+	// request.
+	RequestTimeout time.Duration
+
+	// Now returns the current time.
+	Now func() time.Time
+}
+
+// invoiceReply is the reply to one of our invoice requests.
+type invoiceReply struct {
+	payReq string
+	err    error
+}
+
+// Manager issues offers, answers invoice requests for our own offers and
+// requests invoices for the offers of other nodes. All messages are
+// exchanged over onion messages.
+type Manager struct {
+	started sync.Once
+	stopped sync.Once
+
+	cfg *Config
+
+	// pendingRequests are the reply channels of our invoice requests,
+	// keyed by the path ID of the reply path of each request.
+	pendingRequests map[[32]byte]chan *invoiceReply
+	mtx             sync.Mutex
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// NewManager creates a new offer manager.
+func NewManager(cfg *Config) *Manager {
+	return &Manager{
+		cfg:             cfg,
+		pendingRequests: make(map[[32]byte]chan *invoiceReply),
+		quit:            make(chan struct{}),
+	}
+}
+
+// Start starts the offer manager.
+func (m *Manager) Start() error {
+	m.started.Do(func() {
+		log.Info("Offer manager starting")
+	})
+
+	return nil
+}
+
+// Stop stops the offer manager and waits for all invoice requests it is
+// answering to complete.
+func (m *Manager) Stop() error {
+	m.stopped.Do(func() {
+		log.Info("Offer manager shutting down")
+
+		close(m.quit)
+		m.wg.Wait()
+	})
+
+	return nil
+}
+
+// CreateOffer creates and signs a new offer of our node. An amount of zero
+// lets the payer choose the amount and an expiry of zero creates an offer
+// that doesn't expire.
+func (m *Manager) CreateOffer(description string, amt lnwire.MilliSatoshi,
+	issuer string, expiry time.Duration) (*Offer, error) {
+
+	if description == "" {
+		return nil, errors.New("offer requires a description")
+	}
+
+	offer := &Offer{
+		Chain:       m.cfg.Chain,
+		Amount:      amt,
+		Description: description,
+		Issuer:      issuer,
+		NodeID:      m.cfg.NodeID,
+	}
+	if expiry > 0 {
+		offer.AbsoluteExpiry = m.cfg.Now().Add(expiry)
+	}
+
+	if err := offer.Sign(m.cfg.SignMessage); err != nil {
+		return nil, err
+	}
+
+	return offer, nil
+}
+
+// invoiceAmount returns the amount of the invoice for the offer, given the
+// amount the payer wants to pay.
+func invoiceAmount(offer *Offer, amt lnwire.MilliSatoshi) (lnwire.MilliSatoshi,
+	error) {
+
+	switch {
+	case amt == 0 && offer.Amount == 0:
+		return 0, errors.New("amount must be specified for offers " +
+			"without amount")
+
+	case amt == 0:
+		return offer.Amount, nil
+
+	case amt < offer.Amount:
+		return 0, fmt.Errorf("amount %v below offer amount %v", amt,
+			offer.Amount)
+
+	default:
+		return amt, nil
+	}
+}
+
+// validateOffer checks that the offer can be used on our chain.
+func (m *Manager) validateOffer(offer *Offer) error {
+	if offer.Chain != m.cfg.Chain {
+		return fmt.Errorf("offer for chain %v, expected %v",
+			offer.Chain, m.cfg.Chain)
+	}
+
+	if offer.Expired(m.cfg.Now()) {
+		return ErrOfferExpired
+	}
+
+	return nil
+}
+
+// RequestInvoice requests a fresh invoice for the offer from its issuer and
+// returns the encoded payment request. The amount may be zero if the offer
+// specifies an amount.
+func (m *Manager) RequestInvoice(offer *Offer, amt lnwire.MilliSatoshi,
+	payerNote string) (string, error) {
+
+	if err := m.validateOffer(offer); err != nil {
+		return "", err
+	}
+
+	invoiceAmt, err := invoiceAmount(offer, amt)
+	if err != nil {
+		return "", err
+	}
+
+	path, err := m.cfg.FindPath(offer.NodeID)
+	if err != nil {
+		return "", fmt.Errorf("unable to find onion message path to "+
+			"%x: %v", offer.NodeID.SerializeCompressed(), err)
+	}
+
+	// The issuer replies over the reverse of our path, which identifies
+	// the request through its path ID.
+	var pathID [32]byte
+	if _, err := rand.Read(pathID[:]); err != nil {
+		return "", err
+	}
+
+	replyPath, err := m.buildReplyPath(path, pathID)
+	if err != nil {
+		return "", err
+	}
+
+	invReq, err := (&InvoiceRequest{
+		Offer:     offer,
+		Amount:    amt,
+		PayerNote: payerNote,
+		ReplyPath: replyPath,
+	}).Encode()
+	if err != nil {
+		return "", err
+	}
+
+	replyChan := make(chan *invoiceReply, 1)
+	m.mtx.Lock()
+	m.pendingRequests[pathID] = replyChan
+	m.mtx.Unlock()
+
+	defer func() {
+		m.mtx.Lock()
+		delete(m.pendingRequests, pathID)
+		m.mtx.Unlock()
+	}()
+
+	err = m.cfg.SendOnionMessage(path, record.CustomSet{
+		InvoiceRequestType: invReq,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	select {
+	case reply := <-replyChan:
+		if reply.err != nil {
+			return "", reply.err
+		}
+
+		err := m.validateInvoice(offer, invoiceAmt, reply.payReq)
+		if err != nil {
+			return "", err
+		}
+
+		return reply.payReq, nil
+
+	case <-time.After(m.cfg.RequestTimeout):
+		return "", ErrRequestTimeout
+
+	case <-m.quit:
+		return "", ErrManagerShuttingDown
+	}
+}
+
+// buildReplyPath creates a blinded path to our node over the reverse of the
+// passed path, excluding its target.
+func (m *Manager) buildReplyPath(path []*btcec.PublicKey,
+	pathID [32]byte) (*blindedpath.BlindedPath, error) {
+
+	nodes := make([]*btcec.PublicKey, 0, len(path))
+	for i := len(path) - 2; i >= 0; i-- {
+		nodes = append(nodes, path[i])
+	}
+	nodes = append(nodes, m.cfg.NodeID)
+
+	hops := make([]*blindedpath.HopInfo, len(nodes))
+	for i, nodePub := range nodes {
+		var routeData record.BlindedRouteData
+		if i < len(nodes)-1 {
+			routeData.NextNodeID = nodes[i+1]
+		} else {
+			routeData.PathID = pathID[:]
+		}
+
+		plainText, err := record.EncodeBlindedRouteData(&routeData)
+		if err != nil {
+			return nil, err
+		}
+
+		hops[i] = &blindedpath.HopInfo{
+			NodePub:   nodePub,
+			PlainText: plainText,
+		}
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return blindedpath.BuildBlindedPath(sessionKey, hops)
+}
+
+// validateInvoice checks that the invoice we received matches the offer we
+// requested it for.
+func (m *Manager) validateInvoice(offer *Offer, amt lnwire.MilliSatoshi,
+	payReq string) error {
+
+	invoice, err := m.cfg.DecodeInvoice(payReq)
+	if err != nil {
+		return fmt.Errorf("invalid invoice: %v", err)
+	}
+
+	switch {
+	case !invoice.Destination.IsEqual(offer.NodeID):
+		return errors.New("invoice not issued by offer node")
+
+	case invoice.MilliSat == nil || *invoice.MilliSat != amt:
+		return fmt.Errorf("invoice amount doesn't match requested "+
+			"amount %v", amt)
+
+	case invoice.Description == nil ||
+		*invoice.Description != offer.Description:
+
+		return errors.New("invoice description doesn't match offer")
+	}
+
+	return nil
+}
+
+// HandleOnionMessage processes an onion message destined for us. It returns
+// false if the message isn't related to offers.
+func (m *Manager) HandleOnionMessage(pathID []byte,
+	records record.CustomSet) bool {
+
+	if invReq, ok := records[InvoiceRequestType]; ok {
+		// Creating the invoice requires database access, so we don't
+		// block the caller.
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+
+			m.handleInvoiceRequest(invReq)
+		}()
+
+		return true
+	}
+
+	payReq, isInvoice := records[InvoiceType]
+	invoiceErr, isError := records[InvoiceErrorType]
+	if !isInvoice && !isError {
+		return false
+	}
+
+	var key [32]byte
+	if len(pathID) != len(key) {
+		log.Debugf("Ignoring invoice reply with invalid path id %x",
+			pathID)
+		return true
+	}
+	copy(key[:], pathID)
+
+	reply := &invoiceReply{payReq: string(payReq)}
+	if isError {
+		reply.err = fmt.Errorf("invoice request rejected: %s",
+			invoiceErr)
+	}
+
+	m.mtx.Lock()
+	replyChan, ok := m.pendingRequests[key]
+	m.mtx.Unlock()
+
+	if !ok {
+		log.Debugf("Ignoring invoice reply for unknown request")
+		return true
+	}
+
+	select {
+	case replyChan <- reply:
+	default:
+	}
+
+	return true
+}
+
+// handleInvoiceRequest answers an invoice request for one of our offers.
+//
+// NOTE: This method must be run as a goroutine.
+func (m *Manager) handleInvoiceRequest(b []byte) {
+	invReq, err := DecodeInvoiceRequest(b)
+	if err != nil {
+		log.Debugf("Unable to decode invoice request: %v", err)
+		return
+	}
+
+	payReq, err := m.createInvoice(invReq)
+
+	records := record.CustomSet{InvoiceType: []byte(payReq)}
+	if err != nil {
+		log.Debugf("Rejecting invoice request: %v", err)
+
+		records = record.CustomSet{
+			InvoiceErrorType: []byte(err.Error()),
+		}
+	}
+
+	err = m.cfg.SendBlindedOnionMessage(invReq.ReplyPath, records)
+	if err != nil {
+		log.Errorf("Unable to reply to invoice request: %v", err)
+	}
+}
+
+// createInvoice creates the invoice requested by the passed invoice request.
+func (m *Manager) createInvoice(invReq *InvoiceRequest) (string, error) {
+	offer := invReq.Offer
+
+	// The signature of the offer has already been verified, so the offer
+	// is ours if it carries our node ID.
+	if !offer.NodeID.IsEqual(m.cfg.NodeID) {
+		return "", errors.New("unknown offer")
+	}
+
+	if err := m.validateOffer(offer); err != nil {
+		return "", err
+	}
+
+	amt, err := invoiceAmount(offer, invReq.Amount)
+	if err != nil {
+		return "", err
+	}
+
+	payReq, err := m.cfg.CreateInvoice(offer, amt, invReq.PayerNote)
+	if err != nil {
+		log.Errorf("Unable to create invoice for offer: %v", err)
+		return "", errors.New("unable to create invoice")
+	}
+
+	log.Debugf("Created invoice of %v for offer %q", amt,
+		offer.Description)
+
+	return payReq, nil
+}
@@ -0,0 +1,195 @@
+package offers
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/stretchr/testify/require"
+)
+
+var testChainParams = &chaincfg.RegressionNetParams
+
+// testNode is a node that issues or pays offers in the tests.
+type testNode struct {
+	privKey *btcec.PrivateKey
+	manager *Manager
+
+	// invoices are the invoices created by the node.
+	invoices []string
+}
+
+// newTestNode creates a test node. The onion messages it sends are delivered
+// to the peer node.
+func newTestNode(t *testing.T, peer **testNode) *testNode {
+	t.Helper()
+
+	privKey, signer := newTestSigner(t)
+	node := &testNode{privKey: privKey}
+
+	createInvoice := func(offer *Offer, amt lnwire.MilliSatoshi,
+		_ string) (string, error) {
+
+		invoice, err := zpay32.NewInvoice(
+			testChainParams, lntypes.Hash{1}, time.Now(),
+			zpay32.Amount(amt),
+			zpay32.Description(offer.Description),
+		)
+		if err != nil {
+			return "", err
+		}
+
+		payReq, err := invoice.Encode(zpay32.MessageSigner{
+			SignCompact: func(msg []byte) ([]byte, error) {
+				return ecdsa.SignCompact(privKey,
+					chainhash.HashB(msg), true,
+				)
+			},
+		})
+		if err != nil {
+			return "", err
+		}
+		node.invoices = append(node.invoices, payReq)
+
+		return payReq, nil
+	}
+
+	node.manager = NewManager(&Config{
+		NodeID:        privKey.PubKey(),
+		Chain:         *testChainParams.GenesisHash,
+		SignMessage:   signer,
+		CreateInvoice: createInvoice,
+		DecodeInvoice: func(payReq string) (*zpay32.Invoice, error) {
+			return zpay32.Decode(payReq, testChainParams)
+		},
+		FindPath: func(target *btcec.PublicKey) ([]*btcec.PublicKey,
+			error) {
+
+			if !target.IsEqual((*peer).privKey.PubKey()) {
+				return nil, errors.New("no path")
+			}
+
+			return []*btcec.PublicKey{target}, nil
+		},
+		SendOnionMessage: func(path []*btcec.PublicKey,
+			records record.CustomSet) error {
+
+			(*peer).manager.HandleOnionMessage(nil, records)
+			return nil
+		},
+		SendBlindedOnionMessage: func(path *blindedpath.BlindedPath,
+			records record.CustomSet) error {
+
+			// The reply path consists of the peer only, which
+			// learns the path ID from the data encrypted for it.
+			plainText, err := blindedpath.DecryptBlindedData(
+				&keychain.PrivKeyECDH{
+					PrivKey: (*peer).privKey,
+				}, path.BlindingPoint,
+				path.BlindedHops[0].CipherText,
+			)
+			if err != nil {
+				return err
+			}
+
+			routeData, err := record.DecodeBlindedRouteData(
+				bytes.NewReader(plainText),
+			)
+			if err != nil {
+				return err
+			}
+
+			(*peer).manager.HandleOnionMessage(
+				routeData.PathID, records,
+			)
+			return nil
+		},
+		RequestTimeout: time.Second,
+		Now:            time.Now,
+	})
+	require.NoError(t, node.manager.Start())
+	t.Cleanup(func() {
+		require.NoError(t, node.manager.Stop())
+	})
+
+	return node
+}
+
+// TestRequestInvoice tests that a payer is able to request invoices for the
+// offer of an issuer.
+func TestRequestInvoice(t *testing.T) {
+	t.Parallel()
+
+	var issuer, payer *testNode
+	issuer = newTestNode(t, &payer)
+	payer = newTestNode(t, &issuer)
+
+	// The issuer creates an offer with a fixed amount.
+	offer, err := issuer.manager.CreateOffer(
+		"coffee", 21000, "the shop", time.Hour,
+	)
+	require.NoError(t, err)
+
+	encoded, err := offer.Encode()
+	require.NoError(t, err)
+
+	offer, err = Decode(encoded)
+	require.NoError(t, err)
+
+	// Each request for the offer yields a fresh invoice.
+	for i := 0; i < 2; i++ {
+		payReq, err := payer.manager.RequestInvoice(offer, 0, "")
+		require.NoError(t, err)
+		require.Equal(t, issuer.invoices[i], payReq)
+	}
+
+	// Paying more than the offer amount is allowed, paying less isn't.
+	_, err = payer.manager.RequestInvoice(offer, 25000, "tip")
+	require.NoError(t, err)
+
+	_, err = payer.manager.RequestInvoice(offer, 20000, "")
+	require.Error(t, err)
+
+	// Offers without amount require the payer to choose one.
+	donation, err := issuer.manager.CreateOffer("donation", 0, "", 0)
+	require.NoError(t, err)
+
+	_, err = payer.manager.RequestInvoice(donation, 0, "")
+	require.Error(t, err)
+
+	_, err = payer.manager.RequestInvoice(donation, 5000, "")
+	require.NoError(t, err)
+
+	// The issuer only creates invoices for its own offers.
+	foreign, err := payer.manager.CreateOffer("foreign", 1000, "", 0)
+	require.NoError(t, err)
+
+	_, err = issuer.manager.createInvoice(&InvoiceRequest{Offer: foreign})
+	require.Error(t, err)
+
+	// Expired offers are rejected by the payer.
+	expired, err := issuer.manager.CreateOffer(
+		"expired", 1000, "", time.Nanosecond,
+	)
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+
+	_, err = payer.manager.RequestInvoice(expired, 0, "")
+	require.ErrorIs(t, err, ErrOfferExpired)
+
+	// Messages that don't belong to offers aren't handled.
+	require.False(t, payer.manager.HandleOnionMessage(
+		nil, record.CustomSet{100: []byte{1}},
+	))
+}
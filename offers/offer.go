@@ -0,0 +1,358 @@
+package offers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// offerHRP is the human readable part of an encoded offer.
+	offerHRP = "lno"
+
+	// offerChainType is the record type of the chain an offer is valid
+	// on.
+	offerChainType tlv.Type = 2
+
+	// offerAmountType is the record type of the amount of an offer.
+	offerAmountType tlv.Type = 8
+
+	// offerDescriptionType is the record type of the description of an
+	// offer.
+	offerDescriptionType tlv.Type = 10
+
+	// offerAbsoluteExpiryType is the record type of the unix timestamp at
+	// which an offer expires.
+	offerAbsoluteExpiryType tlv.Type = 14
+
+	// offerIssuerType is the record type of the issuer of an offer.
+	offerIssuerType tlv.Type = 18
+
+	// offerNodeIDType is the record type of the node that issued an offer.
+	offerNodeIDType tlv.Type = 22
+
+	// offerSignatureType is the record type of the signature of the
+	// issuing node over all other records of an offer.
+	offerSignatureType tlv.Type = 240
+
+	// bech32Charset is the character set of the bech32 encoding.
+	bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+var (
+	// ErrInvalidOfferSignature is returned when the signature of an offer
+	// doesn't match its node ID.
+	ErrInvalidOfferSignature = errors.New("invalid offer signature")
+
+	// ErrOfferExpired is returned when an offer is used after its
+	// expiry.
+	ErrOfferExpired = errors.New("offer expired")
+)
+
+// Offer is a static, reusable request for payments to a node. Payers use the
+// offer to request a fresh invoice from the issuing node over onion messages
+// for every payment. Offers are signed by the issuing node, so that it is
+// able to recognize its own offers without storing them.
+type Offer struct {
+	// Chain is the genesis hash of the chain the offer is valid on.
+	Chain chainhash.Hash
+
+	// Amount is the amount to pay for the offer. If it is zero, the payer
+	// chooses the amount.
+	Amount lnwire.MilliSatoshi
+
+	// Description describes what the payment is for.
+	Description string
+
+	// AbsoluteExpiry is the time after which the offer can't be used
+	// anymore. If it is the zero time, the offer doesn't expire.
+	AbsoluteExpiry time.Time
+
+	// Issuer optionally identifies the issuer of the offer to the payer.
+	Issuer string
+
+	// NodeID is the node that issued the offer and that invoices for the
+	// offer are requested from.
+	NodeID *btcec.PublicKey
+
+	// Signature is the signature of NodeID over the other records of the
+	// offer.
+	Signature [64]byte
+}
+
+// records returns the records of the offer, excluding its signature.
+func (o *Offer) records() []tlv.Record {
+	var (
+		chain       = [32]byte(o.Chain)
+		amount      = uint64(o.Amount)
+		description = []byte(o.Description)
+	)
+
+	records := []tlv.Record{
+		tlv.MakePrimitiveRecord(offerChainType, &chain),
+	}
+
+	if amount != 0 {
+		records = append(records, tlv.MakeDynamicRecord(
+			offerAmountType, &amount, func() uint64 {
+				return tlv.SizeTUint64(amount)
+			}, tlv.ETUint64, tlv.DTUint64,
+		))
+	}
+
+	records = append(records, tlv.MakePrimitiveRecord(
+		offerDescriptionType, &description,
+	))
+
+	if !o.AbsoluteExpiry.IsZero() {
+		expiry := uint64(o.AbsoluteExpiry.Unix())
+		records = append(records, tlv.MakeDynamicRecord(
+			offerAbsoluteExpiryType, &expiry, func() uint64 {
+				return tlv.SizeTUint64(expiry)
+			}, tlv.ETUint64, tlv.DTUint64,
+		))
+	}
+
+	if o.Issuer != "" {
+		issuer := []byte(o.Issuer)
+		records = append(records, tlv.MakePrimitiveRecord(
+			offerIssuerType, &issuer,
+		))
+	}
+
+	return append(records, tlv.MakePrimitiveRecord(
+		offerNodeIDType, &o.NodeID,
+	))
+}
+
+// encodeRecords serializes the passed records as a TLV stream.
+func encodeRecords(records ...tlv.Record) ([]byte, error) {
+	tlvStream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := tlvStream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// SigMessage returns the message the issuing node signs to create the
+// signature of the offer.
+func (o *Offer) SigMessage() ([]byte, error) {
+	if o.NodeID == nil {
+		return nil, errors.New("offer is missing node id")
+	}
+
+	return encodeRecords(o.records()...)
+}
+
+// Sign signs the offer with the passed signer, which must sign the sha256
+// digest of the message with the key of the offer's node ID.
+func (o *Offer) Sign(signMsg func([]byte) (*ecdsa.Signature, error)) error {
+	msg, err := o.SigMessage()
+	if err != nil {
+		return err
+	}
+
+	sig, err := signMsg(msg)
+	if err != nil {
+		return err
+	}
+
+	wireSig, err := lnwire.NewSigFromSignature(sig)
+	if err != nil {
+		return err
+	}
+	o.Signature = wireSig
+
+	return o.VerifySignature()
+}
+
+// VerifySignature checks that the signature of the offer was created by its
+// node ID.
+func (o *Offer) VerifySignature() error {
+	msg, err := o.SigMessage()
+	if err != nil {
+		return err
+	}
+
+	wireSig := lnwire.Sig(o.Signature)
+	sig, err := wireSig.ToSignature()
+	if err != nil {
+		return err
+	}
+
+	digest := sha256.Sum256(msg)
+	if !sig.Verify(digest[:], o.NodeID) {
+		return ErrInvalidOfferSignature
+	}
+
+	return nil
+}
+
+// Expired returns true if the offer expired at the given time.
+func (o *Offer) Expired(now time.Time) bool {
+	return !o.AbsoluteExpiry.IsZero() && now.After(o.AbsoluteExpiry)
+}
+
+// Serialize returns the binary TLV encoding of the signed offer.
+func (o *Offer) Serialize() ([]byte, error) {
+	if o.NodeID == nil {
+		return nil, errors.New("offer is missing node id")
+	}
+
+	records := append(o.records(), tlv.MakePrimitiveRecord(
+		offerSignatureType, &o.Signature,
+	))
+
+	return encodeRecords(records...)
+}
+
+// ID returns the identifier of the offer, which is the sha256 digest of its
+// serialization.
+func (o *Offer) ID() ([32]byte, error) {
+	b, err := o.Serialize()
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	return sha256.Sum256(b), nil
+}
+
+// Encode returns the bech32 encoding of the offer. Unlike invoices, offers are
+// encoded without a checksum, as they are typically shared as QR codes, which
+// carry their own error correction.
+func (o *Offer) Encode() (string, error) {
+	b, err := o.Serialize()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := bech32.ConvertBits(b, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(offerHRP)
+	sb.WriteByte('1')
+	for _, d := range data {
+		sb.WriteByte(bech32Charset[d])
+	}
+
+	return sb.String(), nil
+}
+
+// Deserialize parses the binary TLV encoding of an offer and verifies its
+// signature.
+func Deserialize(b []byte) (*Offer, error) {
+	var (
+		o           Offer
+		chain       [32]byte
+		amount      uint64
+		description []byte
+		expiry      uint64
+		issuer      []byte
+	)
+
+	tlvStream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(offerChainType, &chain),
+		tlv.MakeDynamicRecord(
+			offerAmountType, &amount, nil, tlv.ETUint64,
+			tlv.DTUint64,
+		),
+		tlv.MakePrimitiveRecord(offerDescriptionType, &description),
+		tlv.MakeDynamicRecord(
+			offerAbsoluteExpiryType, &expiry, nil, tlv.ETUint64,
+			tlv.DTUint64,
+		),
+		tlv.MakePrimitiveRecord(offerIssuerType, &issuer),
+		tlv.MakePrimitiveRecord(offerNodeIDType, &o.NodeID),
+		tlv.MakePrimitiveRecord(offerSignatureType, &o.Signature),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := tlvStream.DecodeWithParsedTypes(
+		bytes.NewReader(b),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, required := range []tlv.Type{
+		offerChainType, offerDescriptionType, offerNodeIDType,
+		offerSignatureType,
+	} {
+		if _, ok := parsedTypes[required]; !ok {
+			return nil, fmt.Errorf("offer is missing required "+
+				"record %d", required)
+		}
+	}
+
+	o.Chain = chainhash.Hash(chain)
+	o.Amount = lnwire.MilliSatoshi(amount)
+	o.Description = string(description)
+	o.Issuer = string(issuer)
+	if _, ok := parsedTypes[offerAbsoluteExpiryType]; ok {
+		o.AbsoluteExpiry = time.Unix(int64(expiry), 0)
+	}
+
+	if err := o.VerifySignature(); err != nil {
+		return nil, err
+	}
+
+	return &o, nil
+}
+
+// Decode parses a bech32 encoded offer and verifies its signature. Following
+// BOLT 12, the encoding may be split into several parts joined with '+',
+// each optionally followed by whitespace.
+func Decode(offer string) (*Offer, error) {
+	offer = strings.Join(strings.FieldsFunc(offer, func(r rune) bool {
+		return r == '+' || r == ' ' || r == '\t' || r == '\r' ||
+			r == '\n'
+	}), "")
+
+	lower := strings.ToLower(offer)
+	if offer != lower && offer != strings.ToUpper(offer) {
+		return nil, errors.New("offer must not be mixed case")
+	}
+
+	if !strings.HasPrefix(lower, offerHRP+"1") {
+		return nil, fmt.Errorf("offer must start with %v1", offerHRP)
+	}
+
+	encoded := lower[len(offerHRP)+1:]
+	data := make([]byte, len(encoded))
+	for i := 0; i < len(encoded); i++ {
+		d := strings.IndexByte(bech32Charset, encoded[i])
+		if d < 0 {
+			return nil, fmt.Errorf("invalid character in offer: "+
+				"'%c'", encoded[i])
+		}
+		data[i] = byte(d)
+	}
+
+	b, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return Deserialize(b)
+}
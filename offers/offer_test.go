@@ -0,0 +1,104 @@
+package offers
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSigner returns a private key and a signer that signs the sha256
+// digest of a message with it.
+func newTestSigner(t *testing.T) (*btcec.PrivateKey,
+	func([]byte) (*ecdsa.Signature, error)) {
+
+	t.Helper()
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	return privKey, func(msg []byte) (*ecdsa.Signature, error) {
+		digest := sha256.Sum256(msg)
+		return ecdsa.Sign(privKey, digest[:]), nil
+	}
+}
+
+// TestOfferEncodeDecode tests that offers survive an encoding round trip and
+// that tampered offers are rejected.
+func TestOfferEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	privKey, signer := newTestSigner(t)
+
+	offer := &Offer{
+		Chain:          *chaincfg.RegressionNetParams.GenesisHash,
+		Amount:         21000,
+		Description:    "coffee",
+		AbsoluteExpiry: time.Unix(1700000000, 0),
+		Issuer:         "the shop",
+		NodeID:         privKey.PubKey(),
+	}
+	require.NoError(t, offer.Sign(signer))
+
+	encoded, err := offer.Encode()
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encoded, "lno1"))
+
+	decoded, err := Decode(encoded)
+	require.NoError(t, err)
+	require.Equal(t, offer, decoded)
+
+	// An offer split into several parts decodes to the same offer, as
+	// does its upper case form.
+	split := encoded[:20] + "+\n  " + encoded[20:]
+	decoded, err = Decode(split)
+	require.NoError(t, err)
+	require.Equal(t, offer, decoded)
+
+	decoded, err = Decode(strings.ToUpper(encoded))
+	require.NoError(t, err)
+	require.Equal(t, offer, decoded)
+
+	// Offers without amount, issuer and expiry are valid as well.
+	minimal := &Offer{
+		Chain:       *chaincfg.RegressionNetParams.GenesisHash,
+		Description: "donation",
+		NodeID:      privKey.PubKey(),
+	}
+	require.NoError(t, minimal.Sign(signer))
+
+	encoded, err = minimal.Encode()
+	require.NoError(t, err)
+
+	decoded, err = Decode(encoded)
+	require.NoError(t, err)
+	require.Equal(t, minimal, decoded)
+
+	// An offer with a changed amount no longer matches its signature.
+	tampered := *offer
+	tampered.Amount = 1
+	encoded, err = tampered.Encode()
+	require.NoError(t, err)
+
+	_, err = Decode(encoded)
+	require.ErrorIs(t, err, ErrInvalidOfferSignature)
+}
+
+// TestOfferExpired tests the expiry of offers.
+func TestOfferExpired(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(1700000000, 0)
+
+	offer := &Offer{}
+	require.False(t, offer.Expired(now))
+
+	offer.AbsoluteExpiry = now
+	require.False(t, offer.Expired(now))
+	require.True(t, offer.Expired(now.Add(time.Second)))
+}
@@ -10,7 +10,8 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/connmgr"
 	"github.com/btcsuite/btcd/txscript"
@@ -30,16 +31,20 @@ import (
 	"github.com/lightningnetwork/lnd/htlcswitch/hop"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/labels"
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwallet/chancloser"
+	"github.com/lightningnetwork/lnd/lnwallet/chansplicer"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/netann"
 	"github.com/lightningnetwork/lnd/pool"
 	"github.com/lightningnetwork/lnd/queue"
+	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/ticker"
 	"github.com/lightningnetwork/lnd/watchtower/wtclient"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -68,6 +73,24 @@ const (
 
 	// ErrorBufferSize is the number of historic peer errors that we store.
 	ErrorBufferSize = 10
+
+	// onionMessageRate is the number of onion messages per second we
+	// accept from a peer on average. Messages beyond this rate are
+	// dropped.
+	onionMessageRate = 10
+
+	// onionMessageBurst is the number of onion messages we accept from a
+	// peer in a short burst, before the onionMessageRate applies.
+	onionMessageBurst = 50
+
+	// peerStorageRate is the number of peer storage messages per second we
+	// accept from a peer on average. Messages beyond this rate are
+	// dropped, as each of them results in a database write.
+	peerStorageRate = 1
+
+	// peerStorageBurst is the number of peer storage messages we accept
+	// from a peer in a short burst, before the peerStorageRate applies.
+	peerStorageBurst = 5
 )
 
 var (
@@ -75,6 +98,11 @@ var (
 	// either the Brontide doesn't know of it, or the channel in question
 	// is pending.
 	ErrChannelNotFound = fmt.Errorf("channel not found")
+
+	// ErrPeerBackupTooLarge is returned when our channel backup is too
+	// large to be stored by a peer.
+	ErrPeerBackupTooLarge = fmt.Errorf("backup exceeds maximum of %v "+
+		"bytes", lnwire.MaxPeerStorageBytes)
 )
 
 // outgoingMsg packages an lnwire.Message to be sent out on the wire, along with
@@ -102,6 +130,25 @@ type closeMsg struct {
 	msg lnwire.Message
 }
 
+// spliceMsg is a wrapper struct around any wire messages that deal with
+// splicing a channel. This struct includes the raw channel ID targeted along
+// with the original message, and a channel that's closed once the message has
+// been processed.
+type spliceMsg struct {
+	cid  lnwire.ChannelID
+	msg  lnwire.Message
+	done chan struct{}
+}
+
+// spliceQuiescence is the outcome of quiescing a channel for a splice we
+// initiate.
+type spliceQuiescence struct {
+	cid       lnwire.ChannelID
+	splicer   *chansplicer.ChanSplicer
+	initiator bool
+	err       error
+}
+
 // PendingUpdate describes the pending state of a closing channel.
 type PendingUpdate struct {
 	Txid        []byte
@@ -259,6 +306,11 @@ type Config struct {
 	// process fails.
 	DisconnectPeer func(*btcec.PublicKey) error
 
+	// MarkChannelSpliced updates the channel graph to track the funding
+	// output created by the splice of a channel.
+	MarkChannelSpliced func(chanPoint, fundingPoint *wire.OutPoint,
+		capacity btcutil.Amount) error
+
 	// GenNodeAnnouncement is used to send our node announcement to the remote
 	// on startup.
 	GenNodeAnnouncement func(bool,
@@ -318,6 +370,30 @@ type Config struct {
 	// that is accumulated before signing a new commitment.
 	ChannelCommitBatchSize uint32
 
+	// HandleCustomMessage is called whenever a custom message is received
+	// from the peer.
+	HandleCustomMessage func(peer [33]byte, msg *lnwire.Custom) error
+
+	// ForwardOnionMessage is called whenever an onion message received
+	// from the peer needs to be forwarded to the next node of its path.
+	// If it is nil, onion messages received from the peer are ignored.
+	ForwardOnionMessage func(nextNode *btcec.PublicKey,
+		msg *lnwire.OnionMessage) error
+
+	// HandleOnionMessage is called whenever an onion message destined for
+	// us is received from the peer.
+	HandleOnionMessage func(peer [33]byte,
+		payload *hop.OnionMessagePayload) error
+
+	// PeerBackup returns the latest encrypted backup of our channels that
+	// is handed to the peer for safekeeping. If it is nil, we don't send
+	// our backup to the peer.
+	PeerBackup func() []byte
+
+	// HandlePeerStorageRetrieval is called whenever the peer returns the
+	// backup of our channels it stored for us.
+	HandlePeerStorageRetrieval func(peer [33]byte, blob []byte) error
+
 	// Quit is the server's quit channel. If this is closed, we halt operation.
 	Quit chan struct{}
 }
@@ -421,6 +497,28 @@ type Brontide struct {
 	// well as lnwire.ClosingSigned messages.
 	chanCloseMsgs chan *closeMsg
 
+	// activeSplices is a map that keeps track of all the active channel
+	// splices. Any splicing messages are directed to one of these active
+	// state machines. Once the splice has completed, the state machine
+	// will be deleted from the map.
+	activeSplices map[lnwire.ChannelID]*chansplicer.ChanSplicer
+
+	// localSpliceReqs is a channel in which any local requests to splice
+	// a particular channel are sent over.
+	localSpliceReqs chan *chansplicer.SpliceRequest
+
+	// chanSpliceMsgs is a channel that any message related to channel
+	// splices are sent over.
+	chanSpliceMsgs chan *spliceMsg
+
+	// spliceQuiesced is a channel over which the outcome of quiescing a
+	// channel for a splice we initiate is sent.
+	spliceQuiesced chan *spliceQuiescence
+
+	// spliceConfs is a channel over which the IDs of channels are sent
+	// once their splice transaction has confirmed.
+	spliceConfs chan lnwire.ChannelID
+
 	// remoteFeatures is the feature vector received from the peer during
 	// the connection handshake.
 	remoteFeatures *lnwire.FeatureVector
@@ -431,6 +529,15 @@ type Brontide struct {
 	// peer's chansync message with its own over and over again.
 	resentChanSyncMsg map[lnwire.ChannelID]struct{}
 
+	// onionMsgLimiter limits the rate at which we process onion messages
+	// received from the peer, as each of them may cause us to send a
+	// message to another peer.
+	onionMsgLimiter *rate.Limiter
+
+	// peerStorageLimiter limits the rate at which we store the backups the
+	// peer sends us.
+	peerStorageLimiter *rate.Limiter
+
 	queueQuit chan struct{}
 	quit      chan struct{}
 	wg        sync.WaitGroup
@@ -455,9 +562,22 @@ func NewBrontide(cfg Config) *Brontide {
 		localCloseChanReqs: make(chan *htlcswitch.ChanClose),
 		linkFailures:       make(chan linkFailureReport),
 		chanCloseMsgs:      make(chan *closeMsg),
-		resentChanSyncMsg:  make(map[lnwire.ChannelID]struct{}),
-		queueQuit:          make(chan struct{}),
-		quit:               make(chan struct{}),
+		activeSplices: make(
+			map[lnwire.ChannelID]*chansplicer.ChanSplicer,
+		),
+		localSpliceReqs:   make(chan *chansplicer.SpliceRequest),
+		chanSpliceMsgs:    make(chan *spliceMsg),
+		spliceQuiesced:    make(chan *spliceQuiescence),
+		spliceConfs:       make(chan lnwire.ChannelID),
+		resentChanSyncMsg: make(map[lnwire.ChannelID]struct{}),
+		onionMsgLimiter: rate.NewLimiter(
+			onionMessageRate, onionMessageBurst,
+		),
+		peerStorageLimiter: rate.NewLimiter(
+			peerStorageRate, peerStorageBurst,
+		),
+		queueQuit: make(chan struct{}),
+		quit:      make(chan struct{}),
 	}
 
 	return p
@@ -596,6 +716,10 @@ func (p *Brontide) Start() error {
 	// announcements through their timestamps.
 	p.maybeSendNodeAnn(activeChans)
 
+	// If we have channels with the peer, we'll return the backup it
+	// stored with us, and hand it the latest backup of our own channels.
+	p.sendPeerStorageMsgs(activeChans)
+
 	return nil
 }
 
@@ -666,6 +790,14 @@ func (p *Brontide) loadActiveChannels(chans []*channeldb.OpenChannel) (
 			peerLog.Warnf("ChannelPoint(%v) has status %v, won't "+
 				"start.", chanPoint, dbChan.ChanStatus())
 
+			// If the channel is being closed with replaceable
+			// closing transactions, we'll resume the close such
+			// that the closing transaction can still be replaced.
+			err := p.maybeResumeRbfCoopClose(lnChan)
+			if err != nil {
+				return nil, err
+			}
+
 			// To help our peer recover from a potential data loss,
 			// we resend our channel reestablish message if the
 			// channel is in a borked state. We won't process any
@@ -687,50 +819,11 @@ func (p *Brontide) loadActiveChannels(chans []*channeldb.OpenChannel) (
 		// Before we register this new link with the HTLC Switch, we'll
 		// need to fetch its current link-layer forwarding policy from
 		// the database.
-		graph := p.cfg.ChannelGraph
-		info, p1, p2, err := graph.FetchChannelEdgesByOutpoint(chanPoint)
-		if err != nil && err != channeldb.ErrEdgeNotFound {
+		forwardingPolicy, err := p.fetchForwardingPolicy(chanPoint)
+		if err != nil {
 			return nil, err
 		}
 
-		// We'll filter out our policy from the directional channel
-		// edges based whom the edge connects to. If it doesn't connect
-		// to us, then we know that we were the one that advertised the
-		// policy.
-		//
-		// TODO(roasbeef): can add helper method to get policy for
-		// particular channel.
-		var selfPolicy *channeldb.ChannelEdgePolicy
-		if info != nil && bytes.Equal(info.NodeKey1Bytes[:],
-			p.cfg.ServerPubKey[:]) {
-
-			selfPolicy = p1
-		} else {
-			selfPolicy = p2
-		}
-
-		// If we don't yet have an advertised routing policy, then
-		// we'll use the current default, otherwise we'll translate the
-		// routing policy into a forwarding policy.
-		var forwardingPolicy *htlcswitch.ForwardingPolicy
-		if selfPolicy != nil {
-			forwardingPolicy = &htlcswitch.ForwardingPolicy{
-				MinHTLCOut:    selfPolicy.MinHTLC,
-				MaxHTLC:       selfPolicy.MaxHTLC,
-				BaseFee:       selfPolicy.FeeBaseMSat,
-				FeeRate:       selfPolicy.FeeProportionalMillionths,
-				TimeLockDelta: uint32(selfPolicy.TimeLockDelta),
-			}
-		} else {
-			peerLog.Warnf("Unable to find our forwarding policy "+
-				"for channel %v, using default values",
-				chanPoint)
-			forwardingPolicy = &p.cfg.RoutingPolicy
-		}
-
-		peerLog.Tracef("Using link policy of: %v",
-			spew.Sdump(forwardingPolicy))
-
 		// If the channel is pending, set the value to nil in the
 		// activeChannels map. This is done to signify that the channel is
 		// pending. We don't add the link to the switch here - it's the funding
@@ -745,6 +838,21 @@ func (p *Brontide) loadActiveChannels(chans []*channeldb.OpenChannel) (
 			continue
 		}
 
+		// If the channel has a signed splice that hasn't been locked
+		// in yet, we'll resume it. Its link is added as usual, as
+		// every commitment is signed for both funding outputs until
+		// then.
+		splice, err := dbChan.PendingSplice()
+		switch {
+		case err == nil:
+			if err := p.resumeSplice(lnChan, splice); err != nil {
+				return nil, err
+			}
+
+		case err != channeldb.ErrNoPendingSplice:
+			return nil, err
+		}
+
 		// Subscribe to the set of on-chain events for this channel.
 		chainEvents, err := p.cfg.ChainArb.SubscribeChannelEvents(
 			*chanPoint,
@@ -770,6 +878,104 @@ func (p *Brontide) loadActiveChannels(chans []*channeldb.OpenChannel) (
 	return msgs, nil
 }
 
+// maybeResumeRbfCoopClose creates a closer for the passed channel if its
+// replaceable cooperative closing transaction has been broadcast.
+func (p *Brontide) maybeResumeRbfCoopClose(
+	channel *lnwallet.LightningChannel) error {
+
+	chanState := channel.State()
+	if !chanState.HasChanStatus(channeldb.ChanStatusCoopBroadcasted) {
+		return nil
+	}
+
+	coopClose, err := chanState.RbfCoopClose()
+	switch {
+	case err == channeldb.ErrNoRbfCoopClose:
+		return nil
+
+	case err != nil:
+		return err
+	}
+
+	peerLog.Infof("Resuming rbf coop close of ChannelPoint(%v) with "+
+		"closing txid %v", channel.ChannelPoint(),
+		coopClose.CloseTx.TxHash())
+
+	locallyInitiated := chanState.HasChanStatus(
+		channeldb.ChanStatusLocalCloseInitiator,
+	)
+	chanID := lnwire.NewChanIDFromOutPoint(channel.ChannelPoint())
+	p.activeChanCloses[chanID] = chancloser.ResumeRbfChanCloser(
+		p.chanCloseCfg(channel), coopClose, locallyInitiated,
+	)
+
+	return nil
+}
+
+// fetchForwardingPolicy fetches the current link-layer forwarding policy of
+// the channel from the database. If we don't yet have an advertised routing
+// policy, then the current default is returned.
+func (p *Brontide) fetchForwardingPolicy(chanPoint *wire.OutPoint) (
+	*htlcswitch.ForwardingPolicy, error) {
+
+	graph := p.cfg.ChannelGraph
+	info, p1, p2, err := graph.FetchChannelEdgesByOutpoint(chanPoint)
+	if err != nil && err != channeldb.ErrEdgeNotFound {
+		return nil, err
+	}
+
+	// We'll filter out our policy from the directional channel
+	// edges based whom the edge connects to. If it doesn't connect
+	// to us, then we know that we were the one that advertised the
+	// policy.
+	//
+	// TODO(roasbeef): can add helper method to get policy for
+	// particular channel.
+	var selfPolicy *channeldb.ChannelEdgePolicy
+	if info != nil && bytes.Equal(info.NodeKey1Bytes[:],
+		p.cfg.ServerPubKey[:]) {
+
+		selfPolicy = p1
+	} else {
+		selfPolicy = p2
+	}
+
+	// If we don't yet have an advertised routing policy, then
+	// we'll use the current default, otherwise we'll translate the
+	// routing policy into a forwarding policy.
+	var forwardingPolicy *htlcswitch.ForwardingPolicy
+	if selfPolicy != nil {
+		forwardingPolicy = &htlcswitch.ForwardingPolicy{
+			MinHTLCOut:    selfPolicy.MinHTLC,
+			MaxHTLC:       selfPolicy.MaxHTLC,
+			BaseFee:       selfPolicy.FeeBaseMSat,
+			FeeRate:       selfPolicy.FeeProportionalMillionths,
+			TimeLockDelta: uint32(selfPolicy.TimeLockDelta),
+		}
+
+		// Our inbound fee, if any, is stored in the extra
+		// opaque data of our own channel update.
+		inboundFee, _, err := lnwire.ExtractInboundFee(
+			selfPolicy.ExtraOpaqueData,
+		)
+		if err != nil {
+			peerLog.Warnf("Unable to parse inbound fee "+
+				"for channel %v: %v", chanPoint, err)
+		}
+		forwardingPolicy.InboundFee = inboundFee
+	} else {
+		peerLog.Warnf("Unable to find our forwarding policy "+
+			"for channel %v, using default values",
+			chanPoint)
+		forwardingPolicy = &p.cfg.RoutingPolicy
+	}
+
+	peerLog.Tracef("Using link policy of: %v",
+		spew.Sdump(forwardingPolicy))
+
+	return forwardingPolicy, nil
+}
+
 // addLink creates and adds a new ChannelLink from the specified channel.
 func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 	lnChan *lnwallet.LightningChannel,
@@ -813,6 +1019,8 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		towerClient = p.cfg.TowerClient
 	}
 
+	quiescenceTimeout := htlcswitch.DefaultQuiescenceTimeout
+
 	linkCfg := htlcswitch.ChannelLinkConfig{
 		Peer:                    p,
 		DecodeHopIterators:      p.cfg.Sphinx.DecodeHopIterators,
@@ -833,6 +1041,7 @@ func (p *Brontide) addLink(chanPoint *wire.OutPoint,
 		BatchTicker:             ticker.New(p.cfg.ChannelCommitInterval),
 		FwdPkgGCTicker:          ticker.New(time.Hour),
 		PendingCommitTicker:     ticker.New(time.Minute),
+		QuiescenceTicker:        ticker.New(quiescenceTimeout),
 		BatchSize:               p.cfg.ChannelCommitBatchSize,
 		UnsafeReplay:            p.cfg.UnsafeReplay,
 		MinFeeUpdateTimeout:     htlcswitch.DefaultMinLinkFeeUpdateTimeout,
@@ -1404,7 +1613,13 @@ out:
 			*lnwire.AcceptChannel,
 			*lnwire.FundingCreated,
 			*lnwire.FundingSigned,
-			*lnwire.FundingLocked:
+			*lnwire.FundingLocked,
+			*lnwire.TxAddInput,
+			*lnwire.TxAddOutput,
+			*lnwire.TxRemoveInput,
+			*lnwire.TxRemoveOutput,
+			*lnwire.TxComplete,
+			*lnwire.TxSignatures:
 
 			p.cfg.FundingManager.ProcessFundingMsg(msg, p)
 
@@ -1420,6 +1635,46 @@ out:
 			case <-p.quit:
 				break out
 			}
+		case *lnwire.ClosingComplete:
+			select {
+			case p.chanCloseMsgs <- &closeMsg{msg.ChannelID, msg}:
+			case <-p.quit:
+				break out
+			}
+		case *lnwire.ClosingSig:
+			select {
+			case p.chanCloseMsgs <- &closeMsg{msg.ChannelID, msg}:
+			case <-p.quit:
+				break out
+			}
+
+		// A stfu message is handled by the link of the channel, which
+		// quiesces the channel for splices as well.
+		case *lnwire.Stfu:
+			targetChan = msg.ChanID
+			isLinkUpdate = p.isActiveChannel(targetChan)
+
+		case *lnwire.SpliceInit, *lnwire.SpliceAck,
+			*lnwire.SpliceCreated, *lnwire.SpliceSigned,
+			*lnwire.SpliceLocked, *lnwire.TxAbort:
+
+			spliceMsg := newSpliceMsg(msg)
+			select {
+			case p.chanSpliceMsgs <- spliceMsg:
+			case <-p.quit:
+				break out
+			}
+
+			// The link resumes forwarding once the splice has been
+			// signed, and the next message may be a commitment
+			// that spends the funding output of the splice. So
+			// we'll wait for the message to be processed before
+			// reading the next one.
+			select {
+			case <-spliceMsg.done:
+			case <-p.quit:
+				break out
+			}
 
 		case *lnwire.Error:
 			targetChan = msg.ChanID
@@ -1460,6 +1715,37 @@ out:
 
 			discStream.AddMsg(msg)
 
+		case *lnwire.Custom:
+			err := p.handleCustomMessage(msg)
+			if err != nil {
+				p.storeError(err)
+				peerLog.Errorf("peer: %v, %v", p, err)
+			}
+
+		case *lnwire.PeerStorage:
+			err := p.handlePeerStorage(msg)
+			if err != nil {
+				p.storeError(err)
+				peerLog.Errorf("peer: %v, %v", p, err)
+			}
+
+		case *lnwire.PeerStorageRetrieval:
+			err := p.handlePeerStorageRetrieval(msg)
+			if err != nil {
+				p.storeError(err)
+				peerLog.Errorf("peer: %v, %v", p, err)
+			}
+
+		case *lnwire.OnionMessage:
+			// Invalid onion messages are dropped without failing
+			// the connection, as they may have been corrupted by
+			// any node along their path.
+			err := p.handleOnionMessage(msg)
+			if err != nil {
+				peerLog.Debugf("peer: %v, unable to handle "+
+					"onion message: %v", p, err)
+			}
+
 		default:
 			// If the message we received is unknown to us, store
 			// the type to track the failure.
@@ -1537,6 +1823,170 @@ func (p *Brontide) storeError(err error) {
 	)
 }
 
+// handleCustomMessage handles the given custom message if a handler is
+// registered.
+func (p *Brontide) handleCustomMessage(msg *lnwire.Custom) error {
+	if p.cfg.HandleCustomMessage == nil {
+		return fmt.Errorf("no custom message handler for "+
+			"message type %v", uint16(msg.MsgType()))
+	}
+
+	return p.cfg.HandleCustomMessage(p.PubKey(), msg)
+}
+
+// handleOnionMessage processes an onion message received from the peer. The
+// message is either forwarded to the next node of its path or handed to the
+// onion message handler if we are its recipient. Messages that exceed the
+// rate we accept from the peer are dropped.
+//
+// NOTE: This method should only be called from within the readHandler.
+func (p *Brontide) handleOnionMessage(msg *lnwire.OnionMessage) error {
+	if p.cfg.ForwardOnionMessage == nil || p.cfg.HandleOnionMessage == nil {
+		peerLog.Debugf("Ignoring onion message from %v, onion "+
+			"messages are disabled", p)
+		return nil
+	}
+
+	if !p.onionMsgLimiter.Allow() {
+		peerLog.Debugf("Dropping onion message from %v, rate limit "+
+			"exceeded", p)
+		return nil
+	}
+
+	payload, err := p.cfg.Sphinx.ProcessOnionMessage(msg)
+	if err != nil {
+		return err
+	}
+
+	if payload.NextNodeID == nil {
+		return p.cfg.HandleOnionMessage(p.PubKey(), payload)
+	}
+
+	return p.cfg.ForwardOnionMessage(
+		payload.NextNodeID, payload.NextMessage,
+	)
+}
+
+// providesStorage returns true if we store the backups of peers we have
+// channels with.
+func (p *Brontide) providesStorage() bool {
+	return p.cfg.Features.HasFeature(lnwire.ProvideStorageOptional)
+}
+
+// hasChannels returns true if we have any pending or open channels with the
+// peer.
+func (p *Brontide) hasChannels() bool {
+	p.activeChanMtx.RLock()
+	defer p.activeChanMtx.RUnlock()
+
+	return len(p.activeChannels) > 0
+}
+
+// handlePeerStorage stores the backup the peer sent us, so that we can return
+// it once the peer reconnects. We only store the backups of peers we have
+// channels with, and drop them if the peer exceeds the rate we accept.
+//
+// NOTE: This method should only be called from within the readHandler.
+func (p *Brontide) handlePeerStorage(msg *lnwire.PeerStorage) error {
+	if !p.providesStorage() || !p.hasChannels() {
+		peerLog.Debugf("Ignoring peer storage from %v", p)
+		return nil
+	}
+
+	if !p.peerStorageLimiter.Allow() {
+		peerLog.Debugf("Dropping peer storage from %v, rate limit "+
+			"exceeded", p)
+		return nil
+	}
+
+	peerLog.Debugf("Storing %v byte backup of peer %v", len(msg.Blob), p)
+
+	return p.cfg.ChannelDB.PutPeerStorage(
+		route.Vertex(p.cfg.PubKeyBytes), msg.Blob,
+	)
+}
+
+// handlePeerStorageRetrieval hands the backup the peer stored for us to the
+// registered handler.
+//
+// NOTE: This method should only be called from within the readHandler.
+func (p *Brontide) handlePeerStorageRetrieval(
+	msg *lnwire.PeerStorageRetrieval) error {
+
+	if p.cfg.HandlePeerStorageRetrieval == nil {
+		peerLog.Debugf("Ignoring peer storage retrieval from %v", p)
+		return nil
+	}
+
+	return p.cfg.HandlePeerStorageRetrieval(p.PubKey(), msg.Blob)
+}
+
+// sendPeerStorageMsgs returns the backup the peer stored with us, and sends
+// the peer the latest backup of our own channels. Both are only sent if we
+// have channels with the peer.
+func (p *Brontide) sendPeerStorageMsgs(channels []*channeldb.OpenChannel) {
+	if len(channels) == 0 {
+		return
+	}
+
+	if p.providesStorage() {
+		blob, err := p.cfg.ChannelDB.FetchPeerStorage(
+			route.Vertex(p.cfg.PubKeyBytes),
+		)
+		switch {
+		case err != nil:
+			peerLog.Errorf("Unable to fetch peer storage of %v: %v",
+				p, err)
+
+		case blob != nil:
+			msg := lnwire.NewPeerStorageRetrieval(blob)
+			if err := p.SendMessageLazy(false, msg); err != nil {
+				peerLog.Debugf("Unable to send peer storage "+
+					"retrieval to %v: %v", p, err)
+			}
+		}
+	}
+
+	if p.cfg.PeerBackup == nil {
+		return
+	}
+
+	blob := p.cfg.PeerBackup()
+	if blob == nil {
+		return
+	}
+	err := p.SendPeerBackup(blob)
+	switch {
+	// An oversized backup means that none of our channels are backed up
+	// with the peer, so we'll make sure that it doesn't go unnoticed.
+	case err == ErrPeerBackupTooLarge:
+		peerLog.Warnf("Unable to send %v byte backup to %v: %v",
+			len(blob), p, err)
+
+	case err != nil:
+		peerLog.Debugf("Unable to send backup to %v: %v", p, err)
+	}
+}
+
+// SendPeerBackup hands the encrypted backup of our channels to the peer for
+// safekeeping. The backup is only sent if the peer provides storage and we
+// have channels with it.
+func (p *Brontide) SendPeerBackup(blob []byte) error {
+	if !p.remoteFeatures.HasFeature(lnwire.ProvideStorageOptional) {
+		return errors.New("peer doesn't provide storage")
+	}
+
+	if !p.hasChannels() {
+		return errors.New("no channels with peer")
+	}
+
+	if len(blob) > lnwire.MaxPeerStorageBytes {
+		return ErrPeerBackupTooLarge
+	}
+
+	return p.SendMessageLazy(false, lnwire.NewPeerStorage(blob))
+}
+
 // handleError processes an error message read from the remote peer. The boolean
 // returns indicates whether the message should be delivered to a targeted peer.
 // It stores the error we received from the peer in memory if we have a channel
@@ -1604,6 +2054,30 @@ func messageSummary(msg lnwire.Message) string {
 		return fmt.Sprintf("chan_id=%v, next_point=%x",
 			msg.ChanID, msg.NextPerCommitmentPoint.SerializeCompressed())
 
+	case *lnwire.TxAddInput:
+		return fmt.Sprintf("chan_id=%v, serial_id=%v", msg.ChannelID,
+			msg.SerialID)
+
+	case *lnwire.TxAddOutput:
+		return fmt.Sprintf("chan_id=%v, serial_id=%v, amt=%v",
+			msg.ChannelID, msg.SerialID, msg.Amount)
+
+	case *lnwire.TxRemoveInput:
+		return fmt.Sprintf("chan_id=%v, serial_id=%v", msg.ChannelID,
+			msg.SerialID)
+
+	case *lnwire.TxRemoveOutput:
+		return fmt.Sprintf("chan_id=%v, serial_id=%v", msg.ChannelID,
+			msg.SerialID)
+
+	case *lnwire.TxSignatures:
+		return fmt.Sprintf("chan_id=%v, txid=%v", msg.ChannelID,
+			msg.TxHash)
+
+	case *lnwire.TxAbort:
+		return fmt.Sprintf("chan_id=%v, data=%q", msg.ChannelID,
+			msg.Data)
+
 	case *lnwire.Shutdown:
 		return fmt.Sprintf("chan_id=%v, script=%x", msg.ChannelID,
 			msg.Address[:])
@@ -1612,6 +2086,13 @@ func messageSummary(msg lnwire.Message) string {
 		return fmt.Sprintf("chan_id=%v, fee_sat=%v", msg.ChannelID,
 			msg.FeeSatoshis)
 
+	case *lnwire.ClosingComplete:
+		return fmt.Sprintf("chan_id=%v, fee_sat=%v", msg.ChannelID,
+			msg.FeeSatoshis)
+
+	case *lnwire.ClosingSig:
+		return fmt.Sprintf("chan_id=%v", msg.ChannelID)
+
 	case *lnwire.UpdateAddHTLC:
 		return fmt.Sprintf("chan_id=%v, id=%v, amt=%v, expiry=%v, hash=%x",
 			msg.ChanID, msg.ID, msg.Amount, msg.Expiry, msg.PaymentHash[:])
@@ -1697,16 +2178,23 @@ func messageSummary(msg lnwire.Message) string {
 			time.Unix(int64(msg.FirstTimestamp), 0),
 			msg.TimestampRange)
 
+	case *lnwire.OnionMessage:
+		return fmt.Sprintf("blinding_point=%x, onion_len=%v",
+			msg.BlindingPoint.SerializeCompressed(),
+			len(msg.OnionBlob))
+
+	case *lnwire.PeerStorage:
+		return fmt.Sprintf("blob_len=%v", len(msg.Blob))
+
+	case *lnwire.PeerStorageRetrieval:
+		return fmt.Sprintf("blob_len=%v", len(msg.Blob))
+
 	}
 
 	return ""
 }
 
-// logWireMessage logs the receipt or sending of particular wire message. This
-// function is used rather than just logging the message in order to produce
-// less spammy log messages in trace mode by setting the 'Curve" parameter to
-// nil. Doing this avoids printing out each of the field elements in the curve
-// parameters for secp256k1.
+// logWireMessage logs the receipt or sending of particular wire message.
 func (p *Brontide) logWireMessage(msg lnwire.Message, read bool) {
 	summaryPrefix := "Received"
 	if !read {
@@ -1729,31 +2217,6 @@ func (p *Brontide) logWireMessage(msg lnwire.Message, read bool) {
 			msg.MsgType(), summary, preposition, p)
 	}))
 
-	switch m := msg.(type) {
-	case *lnwire.ChannelReestablish:
-		if m.LocalUnrevokedCommitPoint != nil {
-			m.LocalUnrevokedCommitPoint.Curve = nil
-		}
-	case *lnwire.RevokeAndAck:
-		m.NextRevocationKey.Curve = nil
-	case *lnwire.AcceptChannel:
-		m.FundingKey.Curve = nil
-		m.RevocationPoint.Curve = nil
-		m.PaymentPoint.Curve = nil
-		m.DelayedPaymentPoint.Curve = nil
-		m.HtlcPoint.Curve = nil
-		m.FirstCommitmentPoint.Curve = nil
-	case *lnwire.OpenChannel:
-		m.FundingKey.Curve = nil
-		m.RevocationPoint.Curve = nil
-		m.PaymentPoint.Curve = nil
-		m.DelayedPaymentPoint.Curve = nil
-		m.HtlcPoint.Curve = nil
-		m.FirstCommitmentPoint.Curve = nil
-	case *lnwire.FundingLocked:
-		m.NextPerCommitmentPoint.Curve = nil
-	}
-
 	prefix := "readMessage from"
 	if !read {
 		prefix = "writeMessage to"
@@ -2297,6 +2760,26 @@ out:
 		case closeMsg := <-p.chanCloseMsgs:
 			p.handleCloseMsg(closeMsg)
 
+		// We've just received a local request to splice an active
+		// channel.
+		case req := <-p.localSpliceReqs:
+			p.handleLocalSpliceReq(req)
+
+		// We've received a new splicing related message from the
+		// remote peer, we'll use this message to advance the splicer
+		// state machine.
+		case spliceMsg := <-p.chanSpliceMsgs:
+			p.handleSpliceMsg(spliceMsg)
+
+		// A channel we want to splice has been quiesced by its link.
+		case res := <-p.spliceQuiesced:
+			p.handleSpliceQuiescence(res)
+
+		// The splice transaction of a channel has confirmed, so we'll
+		// lock in the splice.
+		case chanID := <-p.spliceConfs:
+			p.handleSpliceConf(chanID)
+
 		// The channel reannounce delay has elapsed, broadcast the
 		// reenabled channel updates to the network. This should only
 		// fire once, so we set the reenableTimeout channel to nil to
@@ -2316,6 +2799,12 @@ out:
 			reenableTimeout = nil
 
 		case <-p.quit:
+			// Any splices that haven't been signed yet are
+			// aborted, so we'll release their wallet inputs.
+			for _, splicer := range p.activeSplices {
+				splicer.ReleaseInputs()
+			}
+
 			// As, we've been signalled to exit, we'll reset all
 			// our active channel back to their default state.
 			p.activeChanMtx.Lock()
@@ -2392,6 +2881,14 @@ func (p *Brontide) reenableActiveChannels() {
 func (p *Brontide) fetchActiveChanCloser(chanID lnwire.ChannelID) (
 	*chancloser.ChanCloser, error) {
 
+	// A replaceable cooperative close is kept around once its closing
+	// transaction has been broadcast and the channel is no longer active,
+	// as either party can still replace the closing transaction.
+	rbfCloser, ok := p.activeChanCloses[chanID]
+	if ok && rbfCloser.RbfCoopClose() {
+		return rbfCloser, nil
+	}
+
 	// First, we'll ensure that we actually know of the target channel. If
 	// not, we'll ignore this message.
 	p.activeChanMtx.RLock()
@@ -2453,18 +2950,7 @@ func (p *Brontide) fetchActiveChanCloser(chanID lnwire.ChannelID) (
 		}
 
 		chanCloser = chancloser.NewChanCloser(
-			chancloser.ChanCloseCfg{
-				Channel:           channel,
-				UnregisterChannel: p.cfg.Switch.RemoveLink,
-				BroadcastTx:       p.cfg.Wallet.PublishTransaction,
-				DisableChannel: func(chanPoint wire.OutPoint) error {
-					return p.cfg.ChanStatusMgr.RequestDisable(chanPoint, false)
-				},
-				Disconnect: func() error {
-					return p.cfg.DisconnectPeer(p.IdentityKey())
-				},
-				Quit: p.quit,
-			},
+			p.chanCloseCfg(channel),
 			deliveryScript,
 			feePerKw,
 			uint32(startingHeight),
@@ -2477,6 +2963,32 @@ func (p *Brontide) fetchActiveChanCloser(chanID lnwire.ChannelID) (
 	return chanCloser, nil
 }
 
+// chanCloseCfg returns the config of a channel closer for the passed channel.
+func (p *Brontide) chanCloseCfg(
+	channel *lnwallet.LightningChannel) chancloser.ChanCloseCfg {
+
+	return chancloser.ChanCloseCfg{
+		Channel:           channel,
+		UnregisterChannel: p.cfg.Switch.RemoveLink,
+		BroadcastTx:       p.cfg.Wallet.PublishTransaction,
+		DisableChannel: func(chanPoint wire.OutPoint) error {
+			return p.cfg.ChanStatusMgr.RequestDisable(chanPoint, false)
+		},
+		Disconnect: func() error {
+			return p.cfg.DisconnectPeer(p.IdentityKey())
+		},
+		RbfCoopClose: p.rbfCoopClose(),
+		Quit:         p.quit,
+	}
+}
+
+// rbfCoopClose returns true if both we and the remote peer signal support for
+// cooperative closes with replaceable closing transactions.
+func (p *Brontide) rbfCoopClose() bool {
+	return p.cfg.Features.HasFeature(lnwire.RbfCoopCloseOptional) &&
+		p.remoteFeatures.HasFeature(lnwire.RbfCoopCloseOptional)
+}
+
 // chooseDeliveryScript takes two optionally set shutdown scripts and returns
 // a suitable script to close out to. This may be nil if neither script is
 // set. If both scripts are set, this function will error if they do not match.
@@ -2513,6 +3025,13 @@ func chooseDeliveryScript(upfront,
 func (p *Brontide) handleLocalCloseReq(req *htlcswitch.ChanClose) {
 	chanID := lnwire.NewChanIDFromOutPoint(req.ChanPoint)
 
+	// A fee bump targets a channel whose closing transaction has already
+	// been broadcast, which is no longer among the active channels.
+	if req.CloseType == htlcswitch.CloseBumpFee {
+		p.handleCloseBumpReq(chanID, req)
+		return
+	}
+
 	p.activeChanMtx.RLock()
 	channel, ok := p.activeChannels[chanID]
 	p.activeChanMtx.RUnlock()
@@ -2571,18 +3090,7 @@ func (p *Brontide) handleLocalCloseReq(req *htlcswitch.ChanClose) {
 		}
 
 		chanCloser := chancloser.NewChanCloser(
-			chancloser.ChanCloseCfg{
-				Channel:           channel,
-				UnregisterChannel: p.cfg.Switch.RemoveLink,
-				BroadcastTx:       p.cfg.Wallet.PublishTransaction,
-				DisableChannel: func(chanPoint wire.OutPoint) error {
-					return p.cfg.ChanStatusMgr.RequestDisable(chanPoint, false)
-				},
-				Disconnect: func() error {
-					return p.cfg.DisconnectPeer(p.IdentityKey())
-				},
-				Quit: p.quit,
-			},
+			p.chanCloseCfg(channel),
 			deliveryScript,
 			req.TargetFeePerKw,
 			uint32(startingHeight),
@@ -2618,6 +3126,33 @@ func (p *Brontide) handleLocalCloseReq(req *htlcswitch.ChanClose) {
 	}
 }
 
+// handleCloseBumpReq handles a local request to replace the closing
+// transaction of a channel that is being closed cooperatively with one paying
+// a higher fee. The replacement is proposed to the remote party, and
+// broadcast once it has been signed by them.
+func (p *Brontide) handleCloseBumpReq(chanID lnwire.ChannelID,
+	req *htlcswitch.ChanClose) {
+
+	chanCloser, ok := p.activeChanCloses[chanID]
+	if !ok {
+		err := fmt.Errorf("unable to bump close fee, ChannelID(%v) "+
+			"is not being closed cooperatively", chanID)
+		peerLog.Errorf(err.Error())
+		req.Err <- err
+		return
+	}
+
+	closeComplete, err := chanCloser.BumpFee(req)
+	if err != nil {
+		peerLog.Errorf("Unable to bump close fee of ChannelPoint(%v): "+
+			"%v", req.ChanPoint, err)
+		req.Err <- err
+		return
+	}
+
+	p.queueMsg(closeComplete, nil)
+}
+
 // linkFailureReport is sent to the channelManager whenever a link reports a
 // link failure, and is forced to exit. The report houses the necessary
 // information to clean up the channel state, send back the error message, and
@@ -2699,6 +3234,12 @@ func (p *Brontide) handleLinkFailure(failure linkFailureReport) {
 				"remote peer: %v", err)
 		}
 	}
+
+	// Some failures can only be recovered from by reconnecting, so we'll
+	// disconnect the peer.
+	if failure.linkErr.ShouldDisconnect() {
+		p.Disconnect(failure.linkErr)
+	}
 }
 
 // finalizeChanClosure performs the final clean up steps once the cooperative
@@ -2713,6 +3254,15 @@ func (p *Brontide) finalizeChanClosure(chanCloser *chancloser.ChanCloser) {
 	chanPoint := chanCloser.Channel().ChannelPoint()
 	p.WipeChannel(chanPoint)
 
+	// A replaceable closing transaction may still be replaced, so the
+	// request is notified once any of them closes the channel.
+	if chanCloser.RbfCoopClose() {
+		if closeReq != nil {
+			p.waitForRbfCoopClose(chanCloser, closeReq)
+		}
+		return
+	}
+
 	// Next, we'll launch a goroutine which will request to be notified by
 	// the ChainNotifier once the closure transaction obtains a single
 	// confirmation.
@@ -2929,7 +3479,7 @@ func (p *Brontide) resendChanSyncMsg(cid lnwire.ChannelID) error {
 
 	if !c.RemotePub.IsEqual(p.IdentityKey()) {
 		return fmt.Errorf("ignoring channel reestablish from "+
-			"peer=%x", p.IdentityKey())
+			"peer=%x", p.IdentityKey().SerializeCompressed())
 	}
 
 	peerLog.Debugf("Re-sending channel sync message for channel %v to "+
@@ -3095,6 +3645,15 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 		return
 	}
 
+	// Once a replaceable closing transaction has been broadcast, any
+	// further messages replace it with one paying a higher fee.
+	if _, err := chanCloser.ClosingTx(); err == nil &&
+		chanCloser.RbfCoopClose() {
+
+		p.handleCloseBumpMsg(chanCloser, msg)
+		return
+	}
+
 	// Next, we'll process the next message using the target state machine.
 	// We'll either continue negotiation, or halt.
 	msgs, closeFin, err := chanCloser.ProcessCloseMsg(
@@ -3133,9 +3692,102 @@ func (p *Brontide) handleCloseMsg(msg *closeMsg) {
 	p.finalizeChanClosure(chanCloser)
 }
 
-// HandleLocalCloseChanReqs accepts a *htlcswitch.ChanClose and passes it onto
-// the channelManager goroutine, which will shut down the link and possibly
-// close the channel.
+// handleCloseBumpMsg processes a message of the remote party that replaces
+// the broadcast closing transaction of the passed closer, either proposing a
+// replacement paid for by the remote party, or signing one we proposed. As the
+// latest closing transaction remains valid, failing to process the message
+// leaves the close untouched.
+func (p *Brontide) handleCloseBumpMsg(chanCloser *chancloser.ChanCloser,
+	msg *closeMsg) {
+
+	bumpReq := chanCloser.BumpRequest()
+
+	msgs, bumped, err := chanCloser.ProcessCloseMsg(msg.msg)
+	if err != nil {
+		err := fmt.Errorf("unable to process close msg: %v", err)
+		peerLog.Error(err)
+
+		// Only a signature of the remote party concludes our
+		// outstanding fee bump.
+		_, isSig := msg.msg.(*lnwire.ClosingSig)
+		if isSig && bumpReq != nil {
+			bumpReq.Err <- err
+		}
+		return
+	}
+
+	for _, msg := range msgs {
+		p.queueMsg(msg, nil)
+	}
+
+	// If the message completed a fee bump we requested, we'll notify the
+	// requester of the replacement closing transaction.
+	if bumped && bumpReq != nil {
+		p.waitForRbfCoopClose(chanCloser, bumpReq)
+	}
+}
+
+// waitForRbfCoopClose sends a pending update for the latest replaceable
+// closing transaction of the passed closer to the passed close request, and
+// notifies the request once the channel is closed. As any of the broadcast
+// closing transactions may confirm, we wait for the funding output to be
+// spent rather than for a particular transaction to confirm.
+func (p *Brontide) waitForRbfCoopClose(chanCloser *chancloser.ChanCloser,
+	closeReq *htlcswitch.ChanClose) {
+
+	closingTx, err := chanCloser.ClosingTx()
+	if err != nil {
+		peerLog.Error(err)
+		closeReq.Err <- err
+		return
+	}
+
+	closingTxid := closingTx.TxHash()
+	closeReq.Updates <- &PendingUpdate{
+		Txid: closingTxid[:],
+	}
+
+	chanState := chanCloser.Channel().State()
+	fundingOutpoint := chanState.ActiveFundingOutpoint()
+	fundingScript, err := lnwallet.FundingPkScript(chanState)
+	if err != nil {
+		peerLog.Error(err)
+		closeReq.Err <- err
+		return
+	}
+
+	spendNtfn, err := p.cfg.ChainNotifier.RegisterSpendNtfn(
+		&fundingOutpoint, fundingScript, chanCloser.NegotiationHeight(),
+	)
+	if err != nil {
+		closeReq.Err <- err
+		return
+	}
+
+	go func() {
+		defer spendNtfn.Cancel()
+
+		// In the case that the ChainNotifier is shutting down, the
+		// spend channel will be closed, generating a nil receive.
+		spend, ok := <-spendNtfn.Spend
+		if !ok {
+			return
+		}
+
+		peerLog.Infof("ChannelPoint(%v) is now closed by txid %v at "+
+			"height %v", chanCloser.Channel().ChannelPoint(),
+			spend.SpenderTxHash, spend.SpendingHeight)
+
+		closeReq.Updates <- &ChannelCloseUpdate{
+			ClosingTxid: spend.SpenderTxHash[:],
+			Success:     true,
+		}
+	}()
+}
+
+// HandleLocalCloseChanReqs accepts a *htlcswitch.ChanClose and passes it onto
+// the channelManager goroutine, which will shut down the link and possibly
+// close the channel.
 func (p *Brontide) HandleLocalCloseChanReqs(req *htlcswitch.ChanClose) {
 	select {
 	case p.localCloseChanReqs <- req:
@@ -3147,6 +3799,608 @@ func (p *Brontide) HandleLocalCloseChanReqs(req *htlcswitch.ChanClose) {
 	}
 }
 
+// HandleLocalSpliceReq accepts a *chansplicer.SpliceRequest and passes it
+// onto the channelManager goroutine, which will have the link quiesce the
+// channel and then negotiate the splice with the remote peer.
+func (p *Brontide) HandleLocalSpliceReq(req *chansplicer.SpliceRequest) {
+	select {
+	case p.localSpliceReqs <- req:
+		peerLog.Infof("Local splice request delivered to peer: %x",
+			p.PubKey())
+	case <-p.quit:
+		req.Err <- lnpeer.ErrPeerExiting
+	}
+}
+
+// fetchLink returns the link of the channel with the passed ID, or nil if the
+// channel has no link in the switch.
+func (p *Brontide) fetchLink(
+	chanID lnwire.ChannelID) htlcswitch.ChannelUpdateHandler {
+
+	links, _ := p.cfg.Switch.GetLinksByInterface(p.cfg.PubKeyBytes)
+	for _, link := range links {
+		if link.ChanID() == chanID {
+			return link
+		}
+	}
+
+	return nil
+}
+
+// newSpliceMsg wraps the passed splicing related message along with the ID of
+// the channel it targets.
+func newSpliceMsg(msg lnwire.Message) *spliceMsg {
+	var cid lnwire.ChannelID
+	switch msg := msg.(type) {
+	case *lnwire.SpliceInit:
+		cid = msg.ChanID
+	case *lnwire.SpliceAck:
+		cid = msg.ChanID
+	case *lnwire.SpliceCreated:
+		cid = msg.ChanID
+	case *lnwire.SpliceSigned:
+		cid = msg.ChanID
+	case *lnwire.SpliceLocked:
+		cid = msg.ChanID
+	case *lnwire.TxAbort:
+		cid = msg.ChannelID
+	}
+
+	return &spliceMsg{cid: cid, msg: msg, done: make(chan struct{})}
+}
+
+// spliceConfig returns the config of a splice state machine for the passed
+// channel.
+func (p *Brontide) spliceConfig(
+	channel *lnwallet.LightningChannel) chansplicer.Config {
+
+	return chansplicer.Config{
+		Channel:          channel,
+		Signer:           p.cfg.Signer,
+		CoinSource:       lnwallet.NewCoinSource(p.cfg.Wallet),
+		CoinSelectLocker: p.cfg.Wallet,
+		CoinLocker:       p.cfg.Wallet,
+		ChangeAddr: func() (btcutil.Address, error) {
+			return p.cfg.Wallet.NewAddress(
+				lnwallet.WitnessPubKey, true,
+				lnwallet.DefaultAccountName,
+			)
+		},
+		MarkChannelSpliced: p.cfg.MarkChannelSpliced,
+		BroadcastTx:        p.cfg.Wallet.PublishTransaction,
+	}
+}
+
+// handleLocalSpliceReq kicks off the splice of an active channel. The link of
+// the channel is asked to quiesce it first, the splice is then negotiated by
+// handleSpliceQuiescence.
+func (p *Brontide) handleLocalSpliceReq(req *chansplicer.SpliceRequest) {
+	chanID := lnwire.NewChanIDFromOutPoint(req.ChanPoint)
+
+	p.activeChanMtx.RLock()
+	channel, ok := p.activeChannels[chanID]
+	p.activeChanMtx.RUnlock()
+
+	link := p.fetchLink(chanID)
+
+	var err error
+	switch {
+	case !ok || channel == nil:
+		err = fmt.Errorf("unable to splice channel, ChannelID(%v) is "+
+			"unknown", chanID)
+
+	case !p.cfg.Features.HasFeature(lnwire.SplicingOptional):
+		err = fmt.Errorf("splicing not enabled, set " +
+			"protocol.splicing to enable it")
+
+	case !p.remoteFeatures.HasFeature(lnwire.SplicingOptional):
+		err = fmt.Errorf("peer %x doesn't support splicing",
+			p.PubKey())
+
+	case channel.State().ChannelFlags&lnwire.FFAnnounceChannel != 0:
+		err = chansplicer.ErrPublicChannel
+
+	case link == nil:
+		err = fmt.Errorf("unable to splice channel, ChannelID(%v) "+
+			"has no active link", chanID)
+	}
+	if err == nil {
+		if _, ok := p.activeSplices[chanID]; ok {
+			err = chansplicer.ErrSpliceInProgress
+		}
+		if _, ok := p.activeChanCloses[chanID]; ok {
+			err = lnwallet.ErrChanClosing
+		}
+	}
+	if err != nil {
+		peerLog.Errorf(err.Error())
+		req.Err <- err
+		return
+	}
+
+	_, startingHeight, err := p.cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		peerLog.Errorf(err.Error())
+		req.Err <- err
+		return
+	}
+
+	splicer := chansplicer.NewChanSplicer(
+		p.spliceConfig(channel), req, uint32(startingHeight),
+	)
+	p.activeSplices[chanID] = splicer
+
+	// Quiescing the channel requires the link to commit to all pending
+	// updates, so we'll wait for it in a goroutine.
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		initiator, err := link.Quiesce(p.quit)
+
+		select {
+		case p.spliceQuiesced <- &spliceQuiescence{
+			cid:       chanID,
+			splicer:   splicer,
+			initiator: initiator,
+			err:       err,
+		}:
+		case <-p.quit:
+		}
+	}()
+}
+
+// handleSpliceQuiescence is called once the link quiesced a channel we want
+// to splice. If we're the initiator of the quiescence, we'll propose the
+// splice to the remote peer.
+func (p *Brontide) handleSpliceQuiescence(res *spliceQuiescence) {
+	// If the remote peer initiated a splice in the meantime, our request
+	// has already been failed.
+	if p.activeSplices[res.cid] != res.splicer {
+		return
+	}
+
+	// If we couldn't quiesce the channel or the remote peer won the race
+	// to initiate the quiescence, the splice is aborted. The channel is
+	// either not quiescent, or quiescent for the remote peer's operation,
+	// so there's nothing to undo.
+	err := res.err
+	if err == nil && !res.initiator {
+		err = htlcswitch.ErrNotQuiescenceInitiator
+	}
+	if err != nil {
+		p.abortSplice(res.cid, res.splicer, err)
+		return
+	}
+
+	msgs, err := res.splicer.InitSplice()
+	if err != nil {
+		p.failSplice(res.cid, res.splicer, err)
+		return
+	}
+
+	for _, msg := range msgs {
+		p.queueMsg(msg, nil)
+	}
+}
+
+// fetchActiveChanSplicer attempts to fetch the active splice state machine
+// that corresponds to the passed channel ID. If no state machine is found,
+// then the remote party is initiating a splice, and a new one is created.
+func (p *Brontide) fetchActiveChanSplicer(chanID lnwire.ChannelID) (
+	*chansplicer.ChanSplicer, error) {
+
+	p.activeChanMtx.RLock()
+	channel, ok := p.activeChannels[chanID]
+	p.activeChanMtx.RUnlock()
+
+	// If the channel isn't in the map or the channel is nil, return
+	// ErrChannelNotFound as the channel is pending.
+	if !ok || channel == nil {
+		return nil, ErrChannelNotFound
+	}
+
+	splicer, ok := p.activeSplices[chanID]
+	switch {
+	// If we're still waiting for our link to quiesce the channel for our
+	// own splice, the remote peer won the race to initiate the quiescence,
+	// so we'll abort our splice in favor of theirs.
+	case ok && splicer.AwaitingQuiescence():
+		p.abortSplice(
+			chanID, splicer, htlcswitch.ErrNotQuiescenceInitiator,
+		)
+
+	case ok:
+		return splicer, nil
+	}
+
+	if !p.cfg.Features.HasFeature(lnwire.SplicingOptional) {
+		return nil, fmt.Errorf("splicing not enabled")
+	}
+	if _, ok := p.activeChanCloses[chanID]; ok {
+		return nil, lnwallet.ErrChanClosing
+	}
+
+	// The remote peer may only propose a splice once it quiesced the
+	// channel as the initiator.
+	link := p.fetchLink(chanID)
+	if link == nil || !link.IsQuiescent() || link.IsQuiescenceInitiator() {
+		return nil, fmt.Errorf("ChannelID(%v) isn't quiesced by "+
+			"the remote peer", chanID)
+	}
+
+	_, startingHeight, err := p.cfg.ChainIO.GetBestBlock()
+	if err != nil {
+		peerLog.Errorf("unable to obtain best block: %v", err)
+		return nil, fmt.Errorf("cannot obtain best block")
+	}
+
+	splicer = chansplicer.NewChanSplicer(
+		p.spliceConfig(channel), nil, uint32(startingHeight),
+	)
+	p.activeSplices[chanID] = splicer
+
+	return splicer, nil
+}
+
+// handleSpliceMsg is called when a splicing related message is received from
+// the remote peer.
+func (p *Brontide) handleSpliceMsg(msg *spliceMsg) {
+	defer close(msg.done)
+
+	switch m := msg.msg.(type) {
+
+	// If the remote peer locked in a splice we've already completed, then
+	// it didn't receive our splice_locked message, so we'll resend it.
+	case *lnwire.SpliceLocked:
+		if _, ok := p.activeSplices[msg.cid]; !ok {
+			p.maybeResendSpliceLocked(m)
+			return
+		}
+
+	case *lnwire.TxAbort:
+		p.handleTxAbort(m)
+		return
+	}
+
+	splicer, err := p.fetchActiveChanSplicer(msg.cid)
+	if err != nil {
+		// If the channel is not known to us, we'll simply ignore this
+		// message.
+		if err == ErrChannelNotFound {
+			return
+		}
+
+		peerLog.Errorf("Unable to respond to remote splice msg: %v",
+			err)
+
+		p.queueMsg(&lnwire.TxAbort{
+			ChannelID: msg.cid,
+			Data:      lnwire.ErrorData(err.Error()),
+		}, nil)
+		p.resumeRemoteQuiescence(msg.cid)
+		return
+	}
+
+	wasSigned := splicer.PendingSplice() != nil
+	msgs, spliceFin, err := splicer.ProcessSpliceMsg(msg.msg)
+	switch {
+
+	// Once the splice has been signed, it can't be aborted anymore, as
+	// the splice transaction may confirm at any time.
+	case err != nil && wasSigned:
+		peerLog.Errorf("Unable to process splice msg for "+
+			"ChannelID(%v): %v", msg.cid, err)
+		return
+
+	case err != nil:
+		err := fmt.Errorf("unable to process splice msg: %v", err)
+		p.failSplice(msg.cid, splicer, err)
+		return
+	}
+
+	for _, msg := range msgs {
+		p.queueMsg(msg, nil)
+	}
+
+	// Once the splice has been signed, the channel resumes forwarding,
+	// and we'll wait for the splice transaction to confirm.
+	if !wasSigned && splicer.PendingSplice() != nil {
+		p.resumeSpliceQuiescence(msg.cid)
+
+		err := p.waitForSpliceConf(msg.cid, splicer)
+		if err != nil {
+			peerLog.Errorf("Unable to wait for splice of "+
+				"ChannelID(%v) to confirm: %v", msg.cid, err)
+		}
+	}
+
+	if spliceFin {
+		p.finalizeChanSplice(msg.cid, splicer)
+	}
+}
+
+// handleTxAbort is called when the remote peer aborts the splice of a
+// channel. If the splice hasn't been signed yet, we'll abort our side of the
+// splice, and echo the tx_abort message before the channel resumes from
+// quiescence.
+func (p *Brontide) handleTxAbort(msg *lnwire.TxAbort) {
+	splicer, ok := p.activeSplices[msg.ChannelID]
+
+	switch {
+	// If we don't take part in a splice of the channel, the remote peer
+	// either failed to propose one after quiescing the channel, or this is
+	// its reply to our own tx_abort.
+	case !ok || splicer.AwaitingQuiescence():
+		p.resumeRemoteQuiescence(msg.ChannelID)
+		return
+
+	// Once the splice has been signed, it can't be aborted anymore.
+	case splicer.PendingSplice() != nil:
+		p.handleSignedSpliceAbort(msg, splicer)
+		return
+	}
+
+	err := fmt.Errorf("splice aborted by peer: %q", msg.Data)
+	p.failSplice(msg.ChannelID, splicer, err)
+}
+
+// handleSignedSpliceAbort is called when the remote peer aborts a splice that
+// has already been signed. The splice transaction may confirm at any time, so
+// we can't forget the splice. If we're the initiator, we hold the fully signed
+// splice transaction and rebroadcast it, so the splice completes regardless.
+// Otherwise we'd depend on the remote peer to broadcast a splice it abandoned,
+// so we fail the channel instead.
+func (p *Brontide) handleSignedSpliceAbort(msg *lnwire.TxAbort,
+	splicer *chansplicer.ChanSplicer) {
+
+	peerLog.Warnf("Peer %x aborted signed splice of ChannelID(%v): %q",
+		p.PubKey(), msg.ChannelID, msg.Data)
+
+	// Once the splice transaction confirmed, it completes with the
+	// exchange of splice_locked.
+	if !splicer.AwaitingConfirmation() {
+		return
+	}
+
+	channel := splicer.Channel()
+	if p.maybeRebroadcastSpliceTx(channel, splicer.PendingSplice()) {
+		return
+	}
+
+	chanPoint := *channel.ChannelPoint()
+	peerLog.Warnf("Force closing ChannelPoint(%v) with aborted splice",
+		chanPoint)
+
+	delete(p.activeSplices, msg.ChannelID)
+	p.WipeChannel(&chanPoint)
+
+	closeTx, err := p.cfg.ChainArb.ForceCloseContract(chanPoint)
+	if err != nil {
+		peerLog.Errorf("Unable to force close ChannelPoint(%v): %v",
+			chanPoint, err)
+	} else {
+		peerLog.Infof("ChannelPoint(%v) force closed with txid %v",
+			chanPoint, closeTx.TxHash())
+	}
+
+	err = p.SendMessage(true, &lnwire.Error{
+		ChanID: msg.ChannelID,
+		Data:   lnwire.ErrorData("signed splice aborted"),
+	})
+	if err != nil {
+		peerLog.Errorf("Unable to send error to peer %x: %v",
+			p.PubKey(), err)
+	}
+}
+
+// maybeResendSpliceLocked resends our splice_locked message if the channel
+// has already been spliced by the transaction the remote peer locked in.
+func (p *Brontide) maybeResendSpliceLocked(msg *lnwire.SpliceLocked) {
+	p.activeChanMtx.RLock()
+	channel, ok := p.activeChannels[msg.ChanID]
+	p.activeChanMtx.RUnlock()
+
+	if !ok || channel == nil {
+		return
+	}
+
+	fundingPoint := channel.State().ActiveFundingOutpoint()
+	if fundingPoint.Hash != msg.SpliceTxid {
+		peerLog.Warnf("Received splice_locked for unknown splice tx "+
+			"%v of ChannelID(%v)", msg.SpliceTxid, msg.ChanID)
+		return
+	}
+
+	p.queueMsg(lnwire.NewSpliceLocked(msg.ChanID, msg.SpliceTxid), nil)
+}
+
+// handleSpliceConf is called once the splice transaction of the channel with
+// the passed ID has confirmed.
+func (p *Brontide) handleSpliceConf(chanID lnwire.ChannelID) {
+	splicer, ok := p.activeSplices[chanID]
+	if !ok {
+		return
+	}
+
+	// The splice can't be aborted anymore, so if we're unable to lock it
+	// in, it's retried once the channel is loaded from disk again.
+	spliceLocked, spliceFin, err := splicer.SpliceConfirmed()
+	if err != nil {
+		peerLog.Errorf("Unable to lock in splice of ChannelID(%v): %v",
+			chanID, err)
+		return
+	}
+
+	p.queueMsg(spliceLocked, nil)
+
+	if spliceFin {
+		p.finalizeChanSplice(chanID, splicer)
+	}
+}
+
+// resumeSplice resumes a splice that has been signed, but not yet locked in
+// before the connection to the peer was lost.
+func (p *Brontide) resumeSplice(channel *lnwallet.LightningChannel,
+	splice *channeldb.PendingSplice) error {
+
+	chanID := lnwire.NewChanIDFromOutPoint(channel.ChannelPoint())
+	splicer := chansplicer.ResumeChanSplicer(
+		p.spliceConfig(channel), splice,
+	)
+	p.activeSplices[chanID] = splicer
+
+	// The splice transaction may never have made it to the network.
+	p.maybeRebroadcastSpliceTx(channel, splice)
+
+	return p.waitForSpliceConf(chanID, splicer)
+}
+
+// maybeRebroadcastSpliceTx rebroadcasts the splice transaction of the passed
+// pending splice if we're the initiator of the splice, as only the initiator
+// holds the fully signed splice transaction. It returns true if we're the
+// initiator.
+func (p *Brontide) maybeRebroadcastSpliceTx(
+	channel *lnwallet.LightningChannel,
+	splice *channeldb.PendingSplice) bool {
+
+	spliceTx := splice.SpliceTx
+	if len(spliceTx.TxIn[0].Witness) == 0 {
+		return false
+	}
+
+	shortChanID := channel.ShortChanID()
+	label := labels.MakeLabel(labels.LabelTypeChannelSplice, &shortChanID)
+	err := p.cfg.Wallet.PublishTransaction(spliceTx, label)
+	if err != nil {
+		peerLog.Errorf("Unable to rebroadcast splice tx %v: %v",
+			spliceTx.TxHash(), err)
+	}
+
+	return true
+}
+
+// waitForSpliceConf launches a goroutine that notifies the channelManager
+// once the splice transaction of the passed state machine has confirmed.
+func (p *Brontide) waitForSpliceConf(chanID lnwire.ChannelID,
+	splicer *chansplicer.ChanSplicer) error {
+
+	splice := splicer.PendingSplice()
+	spliceTxid := splice.SpliceTx.TxHash()
+	pkScript := splice.SpliceTx.TxOut[splice.FundingOutpoint.Index].PkScript
+
+	// We'll wait for the same number of confirmations as for the funding
+	// transaction of the channel, but for at least one.
+	numConfs := uint32(splicer.Channel().State().NumConfsRequired)
+	if numConfs == 0 {
+		numConfs = 1
+	}
+
+	confNtfn, err := p.cfg.ChainNotifier.RegisterConfirmationsNtfn(
+		&spliceTxid, pkScript, numConfs, splice.BroadcastHeight,
+	)
+	if err != nil {
+		return err
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer confNtfn.Cancel()
+
+		select {
+		case _, ok := <-confNtfn.Confirmed:
+			if !ok {
+				return
+			}
+
+		case <-p.quit:
+			return
+		}
+
+		select {
+		case p.spliceConfs <- chanID:
+		case <-p.quit:
+		}
+	}()
+
+	return nil
+}
+
+// finalizeChanSplice is called once a splice has completed. The link keeps
+// operating on the same channel state machine, which is now funded by the
+// output created by the splice transaction.
+func (p *Brontide) finalizeChanSplice(chanID lnwire.ChannelID,
+	splicer *chansplicer.ChanSplicer) {
+
+	delete(p.activeSplices, chanID)
+
+	chanState := splicer.Channel().State()
+	peerLog.Infof("Splice of ChannelPoint(%v) completed, channel now "+
+		"funded by %v with capacity %v", chanState.FundingOutpoint,
+		chanState.ActiveFundingOutpoint(), chanState.Capacity)
+}
+
+// abortSplice aborts the splice handled by the passed state machine before
+// it has been signed.
+func (p *Brontide) abortSplice(chanID lnwire.ChannelID,
+	splicer *chansplicer.ChanSplicer, err error) {
+
+	peerLog.Errorf("Splice of ChannelID(%v) failed: %v", chanID, err)
+
+	splicer.ReleaseInputs()
+	if req := splicer.SpliceRequest(); req != nil {
+		req.Err <- err
+	}
+	delete(p.activeSplices, chanID)
+}
+
+// failSplice aborts the splice handled by the passed state machine once the
+// channel has been quiesced for it, but before it has been signed. We'll
+// send tx_abort to the remote peer, after which the channel resumes from
+// quiescence.
+func (p *Brontide) failSplice(chanID lnwire.ChannelID,
+	splicer *chansplicer.ChanSplicer, err error) {
+
+	p.abortSplice(chanID, splicer, err)
+
+	p.queueMsg(&lnwire.TxAbort{
+		ChannelID: chanID,
+		Data:      lnwire.ErrorData(err.Error()),
+	}, nil)
+
+	p.resumeSpliceQuiescence(chanID)
+}
+
+// resumeRemoteQuiescence has the link of the channel with the passed ID
+// resume from quiescence if it was initiated by the remote peer for a splice
+// that we never accepted.
+func (p *Brontide) resumeRemoteQuiescence(chanID lnwire.ChannelID) {
+	link := p.fetchLink(chanID)
+	if link == nil || !link.IsQuiescent() || link.IsQuiescenceInitiator() {
+		return
+	}
+
+	p.resumeSpliceQuiescence(chanID)
+}
+
+// resumeSpliceQuiescence has the link of the channel with the passed ID
+// resume from the quiescence of a splice. This is done in a goroutine, as the
+// link may be waiting on the channelManager itself.
+func (p *Brontide) resumeSpliceQuiescence(chanID lnwire.ChannelID) {
+	link := p.fetchLink(chanID)
+	if link == nil {
+		return
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		link.ResumeQuiescence()
+	}()
+}
+
 // NetAddress returns the network of the remote peer as an lnwire.NetAddress.
 func (p *Brontide) NetAddress() *lnwire.NetAddress {
 	return p.cfg.Addr
@@ -5,10 +5,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/htlcswitch"
@@ -16,6 +16,7 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet/chancloser"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/pool"
+	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/stretchr/testify/require"
 )
 
@@ -652,6 +653,161 @@ func TestPeerChannelClosureFeeNegotiationsInitiator(t *testing.T) {
 	notifier.ConfChan <- &chainntnfs.TxConfirmation{}
 }
 
+// TestPeerRbfChannelClosure tests that the initiator of a replaceable
+// cooperative close proposes the first closing transaction, and that the
+// closing transaction can be replaced by either party afterwards.
+func TestPeerRbfChannelClosure(t *testing.T) {
+	t.Parallel()
+
+	notifier := &mock.ChainNotifier{
+		SpendChan: make(chan *chainntnfs.SpendDetail),
+		EpochChan: make(chan *chainntnfs.BlockEpoch),
+		ConfChan:  make(chan *chainntnfs.TxConfirmation),
+	}
+	broadcastTxChan := make(chan *wire.MsgTx)
+
+	alicePeer, bobChan, cleanUp, err := createTestPeer(
+		notifier, broadcastTxChan, noUpdate,
+	)
+	require.NoError(t, err, "unable to create test channels")
+	defer cleanUp()
+
+	// Both parties signal support for replaceable cooperative closes.
+	rbfFeatures := lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(lnwire.RbfCoopCloseOptional),
+		lnwire.Features,
+	)
+	alicePeer.cfg.Features = rbfFeatures
+	alicePeer.remoteFeatures = rbfFeatures
+
+	receiveMsg := func() lnwire.Message {
+		select {
+		case outMsg := <-alicePeer.outgoingQueue:
+			return outMsg.msg
+		case <-time.After(timeout):
+			t.Fatalf("did not receive message")
+			return nil
+		}
+	}
+	receiveBroadcast := func() *wire.MsgTx {
+		select {
+		case tx := <-broadcastTxChan:
+			return tx
+		case <-time.After(timeout):
+			t.Fatalf("closing tx not broadcast")
+			return nil
+		}
+	}
+	receivePendingUpdate := func(updates chan interface{}) {
+		select {
+		case update := <-updates:
+			require.IsType(t, &PendingUpdate{}, update)
+		case <-time.After(timeout):
+			t.Fatalf("did not receive pending update")
+		}
+	}
+
+	// We make Alice send a shutdown request.
+	updateChan := make(chan interface{}, 2)
+	errChan := make(chan error, 1)
+	alicePeer.localCloseChanReqs <- &htlcswitch.ChanClose{
+		CloseType:      htlcswitch.CloseRegular,
+		ChanPoint:      bobChan.ChannelPoint(),
+		Updates:        updateChan,
+		TargetFeePerKw: 12500,
+		Err:            errChan,
+	}
+
+	shutdownMsg, ok := receiveMsg().(*lnwire.Shutdown)
+	require.True(t, ok, "expected Shutdown message")
+	aliceDeliveryScript := shutdownMsg.Address
+
+	// Bob will respond with his own Shutdown message, after which Alice
+	// proposes the first closing transaction.
+	chanID := shutdownMsg.ChannelID
+	alicePeer.chanCloseMsgs <- &closeMsg{
+		cid: chanID,
+		msg: lnwire.NewShutdown(chanID, dummyDeliveryScript),
+	}
+
+	closeComplete, ok := receiveMsg().(*lnwire.ClosingComplete)
+	require.True(t, ok, "expected ClosingComplete message")
+
+	// Bob signs the closing transaction paid for by Alice.
+	bobSig, _, _, err := bobChan.CreateRbfCloseProposal(
+		closeComplete.FeeSatoshis, false, dummyDeliveryScript,
+		aliceDeliveryScript,
+	)
+	require.NoError(t, err)
+	parsedSig, err := lnwire.NewSigFromSignature(bobSig)
+	require.NoError(t, err)
+
+	alicePeer.chanCloseMsgs <- &closeMsg{
+		cid: chanID,
+		msg: lnwire.NewClosingSig(chanID, parsedSig),
+	}
+
+	closeTx := receiveBroadcast()
+	receivePendingUpdate(updateChan)
+
+	// Bob now replaces the closing transaction with one paying a higher
+	// fee out of his balance, which Alice signs and broadcasts.
+	bobFee := closeComplete.FeeSatoshis * 2
+	bobSig, _, _, err = bobChan.CreateRbfCloseProposal(
+		bobFee, true, dummyDeliveryScript, aliceDeliveryScript,
+	)
+	require.NoError(t, err)
+	parsedSig, err = lnwire.NewSigFromSignature(bobSig)
+	require.NoError(t, err)
+
+	alicePeer.chanCloseMsgs <- &closeMsg{
+		cid: chanID,
+		msg: lnwire.NewClosingComplete(chanID, bobFee, parsedSig),
+	}
+
+	replacementTx := receiveBroadcast()
+	require.NotEqual(t, closeTx.TxHash(), replacementTx.TxHash())
+
+	_, ok = receiveMsg().(*lnwire.ClosingSig)
+	require.True(t, ok, "expected ClosingSig message")
+
+	// Alice can't replace it with a closing transaction paying a lower
+	// fee.
+	bumpUpdates := make(chan interface{}, 2)
+	bumpErrs := make(chan error, 1)
+	alicePeer.localCloseChanReqs <- &htlcswitch.ChanClose{
+		CloseType:      htlcswitch.CloseBumpFee,
+		ChanPoint:      bobChan.ChannelPoint(),
+		Updates:        bumpUpdates,
+		TargetFeePerKw: 12500,
+		Err:            bumpErrs,
+	}
+
+	select {
+	case err := <-bumpErrs:
+		require.ErrorIs(t, err, chancloser.ErrRbfFeeTooLow)
+	case <-time.After(timeout):
+		t.Fatalf("fee bump not rejected")
+	}
+
+	// Once the funding output is spent, Alice's close request completes
+	// with the txid of the closing transaction that confirmed.
+	replacementTxid := replacementTx.TxHash()
+	notifier.SpendChan <- &chainntnfs.SpendDetail{
+		SpenderTxHash: &replacementTxid,
+		SpendingTx:    replacementTx,
+	}
+
+	select {
+	case update := <-updateChan:
+		closeUpdate, ok := update.(*ChannelCloseUpdate)
+		require.True(t, ok, "expected ChannelCloseUpdate")
+		require.Equal(t, replacementTxid[:], closeUpdate.ClosingTxid)
+	case <-time.After(timeout):
+		t.Fatalf("did not receive close update")
+	}
+}
+
 // TestChooseDeliveryScript tests that chooseDeliveryScript correctly errors
 // when upfront and user set scripts that do not match are provided, allows
 // matching values and returns appropriate values in the case where one or none
@@ -984,6 +1140,152 @@ func TestStaticRemoteDowngrade(t *testing.T) {
 	}
 }
 
+// TestHandleCustomMessage tests that custom messages received from the peer
+// are handed to the registered handler, and that they are refused if no
+// handler is registered.
+func TestHandleCustomMessage(t *testing.T) {
+	t.Parallel()
+
+	var pubKey [33]byte
+	pubKey[0] = 0x02
+
+	msg, err := lnwire.NewCustom(lnwire.CustomTypeStart, []byte{1, 2, 3})
+	require.NoError(t, err)
+
+	// Without a handler, the message can't be delivered.
+	p := NewBrontide(Config{PubKeyBytes: pubKey})
+	require.Error(t, p.handleCustomMessage(msg))
+
+	type customMsg struct {
+		peer [33]byte
+		msg  *lnwire.Custom
+	}
+	received := make(chan customMsg, 1)
+	p = NewBrontide(Config{
+		PubKeyBytes: pubKey,
+		HandleCustomMessage: func(peer [33]byte,
+			msg *lnwire.Custom) error {
+
+			received <- customMsg{peer: peer, msg: msg}
+			return nil
+		},
+	})
+	require.NoError(t, p.handleCustomMessage(msg))
+
+	select {
+	case r := <-received:
+		require.Equal(t, pubKey, r.peer)
+		require.Equal(t, msg, r.msg)
+
+	case <-time.After(timeout):
+		t.Fatalf("custom message not handled")
+	}
+}
+
+// TestPeerStorage tests the exchange of peer_storage and
+// peer_storage_retrieval messages with a peer we have a channel with.
+func TestPeerStorage(t *testing.T) {
+	t.Parallel()
+
+	notifier := &mock.ChainNotifier{
+		SpendChan: make(chan *chainntnfs.SpendDetail),
+		EpochChan: make(chan *chainntnfs.BlockEpoch),
+		ConfChan:  make(chan *chainntnfs.TxConfirmation),
+	}
+	broadcastTxChan := make(chan *wire.MsgTx)
+
+	alicePeer, _, cleanUp, err := createTestPeer(
+		notifier, broadcastTxChan, noUpdate,
+	)
+	require.NoError(t, err)
+	defer cleanUp()
+
+	var channels []*channeldb.OpenChannel
+	for _, channel := range alicePeer.activeChannels {
+		channels = append(channels, channel.State())
+	}
+
+	peerKey := route.Vertex(alicePeer.PubKey())
+	peerBlob := []byte("backup of the peer")
+	ourBlob := []byte("backup of our channels")
+
+	receiveMsg := func() lnwire.Message {
+		t.Helper()
+
+		select {
+		case outMsg := <-alicePeer.outgoingQueue:
+			return outMsg.msg
+
+		case <-time.After(timeout):
+			t.Fatalf("did not receive message")
+			return nil
+		}
+	}
+
+	// As long as we don't provide storage, the backup of the peer is
+	// ignored.
+	err = alicePeer.handlePeerStorage(lnwire.NewPeerStorage(peerBlob))
+	require.NoError(t, err)
+	stored, err := alicePeer.cfg.ChannelDB.FetchPeerStorage(peerKey)
+	require.NoError(t, err)
+	require.Nil(t, stored)
+
+	// Once we provide storage, the backup is stored.
+	alicePeer.cfg.Features = lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(lnwire.ProvideStorageOptional),
+		lnwire.Features,
+	)
+	err = alicePeer.handlePeerStorage(lnwire.NewPeerStorage(peerBlob))
+	require.NoError(t, err)
+	stored, err = alicePeer.cfg.ChannelDB.FetchPeerStorage(peerKey)
+	require.NoError(t, err)
+	require.Equal(t, peerBlob, stored)
+
+	// Our own backup is only sent if the peer provides storage.
+	alicePeer.cfg.PeerBackup = func() []byte {
+		return ourBlob
+	}
+	require.Error(t, alicePeer.SendPeerBackup(ourBlob))
+
+	alicePeer.remoteFeatures = lnwire.NewFeatureVector(
+		lnwire.NewRawFeatureVector(lnwire.ProvideStorageOptional),
+		lnwire.Features,
+	)
+	require.Equal(
+		t, ErrPeerBackupTooLarge, alicePeer.SendPeerBackup(
+			make([]byte, lnwire.MaxPeerStorageBytes+1),
+		),
+	)
+
+	// When the peer reconnects, we return its backup and send it the
+	// latest backup of our channels.
+	go alicePeer.sendPeerStorageMsgs(channels)
+
+	msg := receiveMsg()
+	require.IsType(t, &lnwire.PeerStorageRetrieval{}, msg)
+	require.Equal(t, peerBlob, msg.(*lnwire.PeerStorageRetrieval).Blob)
+
+	msg = receiveMsg()
+	require.IsType(t, &lnwire.PeerStorage{}, msg)
+	require.Equal(t, ourBlob, msg.(*lnwire.PeerStorage).Blob)
+
+	// The backup the peer returns to us is handed to the handler.
+	var retrieved []byte
+	alicePeer.cfg.HandlePeerStorageRetrieval = func(peer [33]byte,
+		blob []byte) error {
+
+		require.Equal(t, alicePeer.PubKey(), peer)
+		retrieved = blob
+
+		return nil
+	}
+	err = alicePeer.handlePeerStorageRetrieval(
+		lnwire.NewPeerStorageRetrieval(ourBlob),
+	)
+	require.NoError(t, err)
+	require.Equal(t, ourBlob, retrieved)
+}
+
 // genScript creates a script paying out to the address provided, which must
 // be a valid address.
 func genScript(t *testing.T, address string) lnwire.DeliveryAddress {
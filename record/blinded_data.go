@@ -0,0 +1,334 @@
+package record
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// EncryptedDataOnionType is the type used in the onion to reference
+	// the encrypted_recipient_data of a hop that is part of a blinded
+	// route.
+	EncryptedDataOnionType tlv.Type = 10
+
+	// BlindingPointOnionType is the type used in the onion to reference
+	// the blinding point that the introduction node of a blinded route
+	// needs to decrypt its encrypted_recipient_data.
+	BlindingPointOnionType tlv.Type = 12
+
+	// TotalAmtMsatOnionType is the type used in the onion to reference
+	// the total amount of a payment to a blinded route. It is only
+	// included in the payload of the final hop.
+	TotalAmtMsatOnionType tlv.Type = 18
+)
+
+const (
+	// blindedShortChannelIDType is the type of the short channel ID of
+	// the outgoing channel within the encrypted data of a blinded hop.
+	blindedShortChannelIDType tlv.Type = 2
+
+	// blindedNextNodeIDType is the type of the public key of the next
+	// node within the encrypted data of a blinded hop.
+	blindedNextNodeIDType tlv.Type = 4
+
+	// blindedPathIDType is the type of the path ID the recipient uses to
+	// identify a payment to one of its blinded routes.
+	blindedPathIDType tlv.Type = 6
+
+	// blindedPaymentRelayType is the type of the forwarding parameters
+	// within the encrypted data of a blinded hop.
+	blindedPaymentRelayType tlv.Type = 10
+
+	// blindedPaymentConstraintsType is the type of the constraints an
+	// HTLC forwarded over a blinded hop must satisfy.
+	blindedPaymentConstraintsType tlv.Type = 12
+)
+
+// NewEncryptedDataRecord creates a tlv.Record that encodes the
+// encrypted_recipient_data (type 10) for an onion payload.
+func NewEncryptedDataRecord(data *[]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(EncryptedDataOnionType, data)
+}
+
+// NewBlindingPointRecord creates a tlv.Record that encodes the blinding
+// point (type 12) for an onion payload.
+func NewBlindingPointRecord(point **btcec.PublicKey) tlv.Record {
+	return tlv.MakePrimitiveRecord(BlindingPointOnionType, point)
+}
+
+// NewTotalAmtMsatRecord creates a tlv.Record that encodes the
+// total_amount_msat (type 18) for an onion payload.
+func NewTotalAmtMsatRecord(amt *uint64) tlv.Record {
+	return tlv.MakeDynamicRecord(
+		TotalAmtMsatOnionType, amt, func() uint64 {
+			return tlv.SizeTUint64(*amt)
+		},
+		tlv.ETUint64, tlv.DTUint64,
+	)
+}
+
+// PaymentRelayInfo holds the forwarding parameters the recipient of a
+// blinded route chose for a hop within the route. They replace the
+// amount_to_forward and outgoing_cltv_value fields of a regular onion
+// payload.
+type PaymentRelayInfo struct {
+	// CltvExpiryDelta is the expiry delta the hop deducts from the
+	// expiry of the incoming HTLC.
+	CltvExpiryDelta uint16
+
+	// FeeRate is the proportional fee in millionths the hop charges.
+	FeeRate uint32
+
+	// BaseFee is the base fee the hop charges.
+	BaseFee uint32
+}
+
+// Record returns a tlv.Record that can be used to encode or decode the
+// payment relay info within the encrypted data of a blinded hop.
+func (p *PaymentRelayInfo) Record() tlv.Record {
+	size := func() uint64 {
+		return 2 + 4 + tlv.SizeTUint32(p.BaseFee)
+	}
+
+	return tlv.MakeDynamicRecord(
+		blindedPaymentRelayType, p, size, encodePaymentRelay,
+		decodePaymentRelay,
+	)
+}
+
+// encodePaymentRelay writes the PaymentRelayInfo record to the provided
+// io.Writer.
+func encodePaymentRelay(w io.Writer, val interface{}, buf *[8]byte) error {
+	if v, ok := val.(*PaymentRelayInfo); ok {
+		err := tlv.EUint16(w, &v.CltvExpiryDelta, buf)
+		if err != nil {
+			return err
+		}
+
+		if err := tlv.EUint32(w, &v.FeeRate, buf); err != nil {
+			return err
+		}
+
+		return tlv.ETUint32(w, &v.BaseFee, buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "PaymentRelayInfo")
+}
+
+// decodePaymentRelay reads the PaymentRelayInfo record from the provided
+// io.Reader.
+func decodePaymentRelay(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*PaymentRelayInfo); ok && 6 <= l && l <= 10 {
+		err := tlv.DUint16(r, &v.CltvExpiryDelta, buf, 2)
+		if err != nil {
+			return err
+		}
+
+		if err := tlv.DUint32(r, &v.FeeRate, buf, 4); err != nil {
+			return err
+		}
+
+		return tlv.DTUint32(r, &v.BaseFee, buf, l-6)
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "PaymentRelayInfo", l, 10)
+}
+
+// PaymentConstraints holds the constraints an HTLC forwarded over a hop of
+// a blinded route must satisfy. They allow the recipient to restrict how the
+// blinded route can be used.
+type PaymentConstraints struct {
+	// MaxCltvExpiry is the maximum expiry an HTLC may have when it is
+	// forwarded over the hop.
+	MaxCltvExpiry uint32
+
+	// HtlcMinimumMsat is the minimum amount of an HTLC forwarded over the
+	// hop.
+	HtlcMinimumMsat lnwire.MilliSatoshi
+}
+
+// Record returns a tlv.Record that can be used to encode or decode the
+// payment constraints within the encrypted data of a blinded hop.
+func (p *PaymentConstraints) Record() tlv.Record {
+	size := func() uint64 {
+		return 4 + tlv.SizeTUint64(uint64(p.HtlcMinimumMsat))
+	}
+
+	return tlv.MakeDynamicRecord(
+		blindedPaymentConstraintsType, p, size,
+		encodePaymentConstraints, decodePaymentConstraints,
+	)
+}
+
+// encodePaymentConstraints writes the PaymentConstraints record to the
+// provided io.Writer.
+func encodePaymentConstraints(w io.Writer, val interface{},
+	buf *[8]byte) error {
+
+	if v, ok := val.(*PaymentConstraints); ok {
+		if err := tlv.EUint32(w, &v.MaxCltvExpiry, buf); err != nil {
+			return err
+		}
+
+		return tlv.ETUint64T(w, uint64(v.HtlcMinimumMsat), buf)
+	}
+
+	return tlv.NewTypeForEncodingErr(val, "PaymentConstraints")
+}
+
+// decodePaymentConstraints reads the PaymentConstraints record from the
+// provided io.Reader.
+func decodePaymentConstraints(r io.Reader, val interface{}, buf *[8]byte,
+	l uint64) error {
+
+	if v, ok := val.(*PaymentConstraints); ok && 4 <= l && l <= 12 {
+		if err := tlv.DUint32(r, &v.MaxCltvExpiry, buf, 4); err != nil {
+			return err
+		}
+
+		var htlcMin uint64
+		if err := tlv.DTUint64(r, &htlcMin, buf, l-4); err != nil {
+			return err
+		}
+		v.HtlcMinimumMsat = lnwire.MilliSatoshi(htlcMin)
+
+		return nil
+	}
+
+	return tlv.NewTypeForDecodingErr(val, "PaymentConstraints", l, 12)
+}
+
+// BlindedRouteData is the data the recipient of a blinded route encrypts for
+// each hop within the route. It is carried as encrypted_recipient_data in the
+// onion payload of the hop.
+type BlindedRouteData struct {
+	// ShortChannelID is the channel the hop forwards the HTLC over. It
+	// is only set for intermediate hops of a blinded payment route.
+	ShortChannelID *lnwire.ShortChannelID
+
+	// NextNodeID is the public key of the next node in the route. It is
+	// used instead of ShortChannelID for routes that don't forward over
+	// channels.
+	NextNodeID *btcec.PublicKey
+
+	// PathID is set by the recipient for the final hop to identify the
+	// route a payment arrived over.
+	PathID []byte
+
+	// RelayInfo holds the forwarding parameters of an intermediate hop.
+	RelayInfo *PaymentRelayInfo
+
+	// Constraints holds the constraints an HTLC forwarded over the hop
+	// must satisfy.
+	Constraints *PaymentConstraints
+}
+
+// EncodeBlindedRouteData serializes the given BlindedRouteData as a TLV
+// stream that can be encrypted for a hop of a blinded route.
+func EncodeBlindedRouteData(data *BlindedRouteData) ([]byte, error) {
+	var records []tlv.Record
+
+	if data.ShortChannelID != nil {
+		scid := data.ShortChannelID.ToUint64()
+		records = append(records, tlv.MakePrimitiveRecord(
+			blindedShortChannelIDType, &scid,
+		))
+	}
+
+	if data.NextNodeID != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			blindedNextNodeIDType, &data.NextNodeID,
+		))
+	}
+
+	if data.PathID != nil {
+		records = append(records, tlv.MakePrimitiveRecord(
+			blindedPathIDType, &data.PathID,
+		))
+	}
+
+	if data.RelayInfo != nil {
+		records = append(records, data.RelayInfo.Record())
+	}
+
+	if data.Constraints != nil {
+		records = append(records, data.Constraints.Record())
+	}
+
+	tlvStream, err := tlv.NewStream(records...)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err := tlvStream.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// DecodeBlindedRouteData parses the decrypted encrypted_recipient_data of a
+// hop of a blinded route from the passed io.Reader.
+func DecodeBlindedRouteData(r io.Reader) (*BlindedRouteData, error) {
+	var (
+		data        BlindedRouteData
+		scid        uint64
+		nextNodeID  *btcec.PublicKey
+		pathID      []byte
+		relayInfo   PaymentRelayInfo
+		constraints PaymentConstraints
+	)
+
+	tlvStream, err := tlv.NewStream(
+		tlv.MakePrimitiveRecord(blindedShortChannelIDType, &scid),
+		tlv.MakePrimitiveRecord(blindedNextNodeIDType, &nextNodeID),
+		tlv.MakePrimitiveRecord(blindedPathIDType, &pathID),
+		relayInfo.Record(),
+		constraints.Record(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	parsedTypes, err := tlvStream.DecodeWithParsedTypes(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// The recipient is the only one able to create the encrypted data, so
+	// unknown even types indicate that it wants us to understand
+	// something we don't.
+	for t, parseResult := range parsedTypes {
+		if parseResult != nil && t%2 == 0 {
+			return nil, fmt.Errorf("unknown required type %d in "+
+				"blinded route data", t)
+		}
+	}
+
+	if _, ok := parsedTypes[blindedShortChannelIDType]; ok {
+		shortChanID := lnwire.NewShortChanIDFromInt(scid)
+		data.ShortChannelID = &shortChanID
+	}
+	if _, ok := parsedTypes[blindedNextNodeIDType]; ok {
+		data.NextNodeID = nextNodeID
+	}
+	if _, ok := parsedTypes[blindedPathIDType]; ok {
+		data.PathID = pathID
+	}
+	if _, ok := parsedTypes[blindedPaymentRelayType]; ok {
+		data.RelayInfo = &relayInfo
+	}
+	if _, ok := parsedTypes[blindedPaymentConstraintsType]; ok {
+		data.Constraints = &constraints
+	}
+
+	return &data, nil
+}
@@ -0,0 +1,37 @@
+package record
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// OnionMessageEncryptedDataType is the type used in the payload of an
+	// onion message to reference the encrypted_recipient_data of the hop.
+	OnionMessageEncryptedDataType tlv.Type = 4
+
+	// OnionMessageAppTypeStart is the start of the range of payload types
+	// that carry the content of an onion message for its final recipient.
+	OnionMessageAppTypeStart = 64
+)
+
+// NewOnionMessageEncryptedDataRecord creates a tlv.Record that encodes the
+// encrypted_recipient_data (type 4) for the payload of an onion message.
+func NewOnionMessageEncryptedDataRecord(data *[]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(OnionMessageEncryptedDataType, data)
+}
+
+// ValidateOnionMessageRecords checks that all records of an onion message
+// are within the range reserved for the final recipient.
+func ValidateOnionMessageRecords(records CustomSet) error {
+	for key := range records {
+		if key < OnionMessageAppTypeStart {
+			return fmt.Errorf("no onion message records with "+
+				"types below %v allowed",
+				OnionMessageAppTypeStart)
+		}
+	}
+
+	return nil
+}
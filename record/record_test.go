@@ -2,6 +2,7 @@ package record_test
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -22,6 +23,8 @@ var (
 	testShare      = [32]byte{0x03, 0x04}
 	testSetID      = [32]byte{0x05, 0x06}
 	testChildIndex = uint32(17)
+	testBaseFee    = uint32(1000)
+	testMaxCltv    = uint32(800000)
 )
 
 var recordEncDecTests = []recordEncDecTest{
@@ -66,6 +69,52 @@ var recordEncDecTests = []recordEncDecTest{
 			}
 		},
 	},
+	{
+		name: "payment relay",
+		encRecord: func() tlv.RecordProducer {
+			return &record.PaymentRelayInfo{
+				CltvExpiryDelta: 40,
+				FeeRate:         1000,
+				BaseFee:         testBaseFee,
+			}
+		},
+		decRecord: func() tlv.RecordProducer {
+			return new(record.PaymentRelayInfo)
+		},
+		assert: func(t *testing.T, r interface{}) {
+			relay := r.(*record.PaymentRelayInfo)
+			if relay.CltvExpiryDelta != 40 {
+				t.Fatal("incorrect cltv expiry delta")
+			}
+			if relay.FeeRate != 1000 {
+				t.Fatal("incorrect fee rate")
+			}
+			if relay.BaseFee != testBaseFee {
+				t.Fatal("incorrect base fee")
+			}
+		},
+	},
+	{
+		name: "payment constraints",
+		encRecord: func() tlv.RecordProducer {
+			return &record.PaymentConstraints{
+				MaxCltvExpiry:   testMaxCltv,
+				HtlcMinimumMsat: testTotal,
+			}
+		},
+		decRecord: func() tlv.RecordProducer {
+			return new(record.PaymentConstraints)
+		},
+		assert: func(t *testing.T, r interface{}) {
+			constraints := r.(*record.PaymentConstraints)
+			if constraints.MaxCltvExpiry != testMaxCltv {
+				t.Fatal("incorrect max cltv expiry")
+			}
+			if constraints.HtlcMinimumMsat != testTotal {
+				t.Fatal("incorrect htlc minimum")
+			}
+		},
+	},
 }
 
 // TestRecordEncodeDecode is a generic test framework for custom TLV records. It
@@ -97,3 +146,41 @@ func TestRecordEncodeDecode(t *testing.T) {
 		})
 	}
 }
+
+// TestBlindedRouteData asserts that the data of a blinded hop survives an
+// encoding round trip, and that unknown even types are rejected.
+func TestBlindedRouteData(t *testing.T) {
+	scid := lnwire.NewShortChanIDFromInt(1234)
+	data := &record.BlindedRouteData{
+		ShortChannelID: &scid,
+		RelayInfo: &record.PaymentRelayInfo{
+			CltvExpiryDelta: 40,
+			FeeRate:         1000,
+			BaseFee:         testBaseFee,
+		},
+		Constraints: &record.PaymentConstraints{
+			MaxCltvExpiry:   testMaxCltv,
+			HtlcMinimumMsat: testTotal,
+		},
+	}
+
+	b, err := record.EncodeBlindedRouteData(data)
+	if err != nil {
+		t.Fatalf("unable to encode route data: %v", err)
+	}
+
+	data2, err := record.DecodeBlindedRouteData(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("unable to decode route data: %v", err)
+	}
+	if !reflect.DeepEqual(data, data2) {
+		t.Fatalf("route data mismatch: want %v, got %v", data, data2)
+	}
+
+	// Append an unknown even type, which the decoder must reject.
+	b = append(b, 0x20, 0x00)
+	_, err = record.DecodeBlindedRouteData(bytes.NewReader(b))
+	if err == nil {
+		t.Fatal("expected unknown even type to be rejected")
+	}
+}
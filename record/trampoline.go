@@ -0,0 +1,53 @@
+package record
+
+import (
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/tlv"
+)
+
+const (
+	// TrampolineOnionType is the type used in the onion to reference the
+	// trampoline onion of a payment. It is included in the payload of the
+	// final hop of a route to a trampoline node, and in the trampoline
+	// payload of a trampoline node that forwards the payment to another
+	// trampoline node.
+	TrampolineOnionType tlv.Type = 20
+
+	// OutgoingNodeIDOnionType is the type used in a trampoline payload to
+	// reference the node the trampoline node forwards the payment to.
+	OutgoingNodeIDOnionType tlv.Type = 22
+
+	// InvoiceFeaturesOnionType is the type used in a trampoline payload
+	// to reference the features of the invoice that is paid, so that the
+	// trampoline node is able to pay recipients that don't understand
+	// trampoline payments.
+	InvoiceFeaturesOnionType tlv.Type = 24
+
+	// InvoiceRoutingInfoOnionType is the type used in a trampoline payload
+	// to reference the route hints of the invoice that is paid.
+	InvoiceRoutingInfoOnionType tlv.Type = 26
+)
+
+// NewTrampolineOnionRecord creates a tlv.Record that encodes the
+// trampoline_onion_packet (type 20) for an onion payload.
+func NewTrampolineOnionRecord(onion *[]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(TrampolineOnionType, onion)
+}
+
+// NewOutgoingNodeIDRecord creates a tlv.Record that encodes the
+// outgoing_node_id (type 22) for a trampoline payload.
+func NewOutgoingNodeIDRecord(nodeID **btcec.PublicKey) tlv.Record {
+	return tlv.MakePrimitiveRecord(OutgoingNodeIDOnionType, nodeID)
+}
+
+// NewInvoiceFeaturesRecord creates a tlv.Record that encodes the
+// invoice_features (type 24) for a trampoline payload.
+func NewInvoiceFeaturesRecord(features *[]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(InvoiceFeaturesOnionType, features)
+}
+
+// NewInvoiceRoutingInfoRecord creates a tlv.Record that encodes the
+// invoice_routing_info (type 26) for a trampoline payload.
+func NewInvoiceRoutingInfoRecord(routingInfo *[]byte) tlv.Record {
+	return tlv.MakePrimitiveRecord(InvoiceRoutingInfoOnionType, routingInfo)
+}
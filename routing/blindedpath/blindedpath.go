@@ -0,0 +1,267 @@
+package blindedpath
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/keychain"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+var (
+	// blindedNodeIDKey is the HMAC key used to derive the blinding factor
+	// of the node IDs within a blinded route.
+	blindedNodeIDKey = []byte("blinded_node_id")
+
+	// rhoKey is the HMAC key used to derive the key that encrypts the
+	// data of a hop within a blinded route.
+	rhoKey = []byte("rho")
+
+	// ErrNoHops is returned when a blinded path is requested for an empty
+	// set of hops.
+	ErrNoHops = errors.New("blinded path must have at least one hop")
+)
+
+// HopInfo contains the plain text data the creator of a blinded path wants to
+// deliver to a node within the path.
+type HopInfo struct {
+	// NodePub is the real public key of the node.
+	NodePub *btcec.PublicKey
+
+	// PlainText is the data that is encrypted for the node.
+	PlainText []byte
+}
+
+// BlindedHop is a hop within a blinded path as seen by the sender of a
+// payment or message.
+type BlindedHop struct {
+	// BlindedNodePub is the blinded public key of the node. The sender
+	// uses it in place of the real key of the node when constructing the
+	// onion.
+	BlindedNodePub *btcec.PublicKey
+
+	// CipherText is the encrypted data for the node, which it needs to
+	// learn where to forward the onion to.
+	CipherText []byte
+}
+
+// BlindedPath is a path to the creator of the path, of which all nodes but
+// the introduction point are hidden from the sender.
+type BlindedPath struct {
+	// IntroductionPoint is the real public key of the first node of the
+	// path, which the sender needs to find a route to.
+	IntroductionPoint *btcec.PublicKey
+
+	// BlindingPoint is the ephemeral public key that is handed to the
+	// introduction point to decrypt its data.
+	BlindingPoint *btcec.PublicKey
+
+	// BlindedHops are the hops of the path, starting with the
+	// introduction point.
+	BlindedHops []*BlindedHop
+}
+
+// BuildBlindedPath creates a blinded path over the given hops using the
+// passed session key as the ephemeral key of the first hop. The data of each
+// hop is encrypted so that only the node of that hop is able to read it.
+func BuildBlindedPath(sessionKey *btcec.PrivateKey,
+	hops []*HopInfo) (*BlindedPath, error) {
+
+	if len(hops) == 0 {
+		return nil, ErrNoHops
+	}
+
+	path := &BlindedPath{
+		IntroductionPoint: hops[0].NodePub,
+		BlindingPoint:     sessionKey.PubKey(),
+		BlindedHops:       make([]*BlindedHop, len(hops)),
+	}
+
+	ephemeralKey := sessionKey
+	for i, hop := range hops {
+		ecdh := &keychain.PrivKeyECDH{PrivKey: ephemeralKey}
+		sharedSecret, err := ecdh.ECDH(hop.NodePub)
+		if err != nil {
+			return nil, err
+		}
+
+		blindedNodePub := blindPubKey(
+			hop.NodePub, hmac256(blindedNodeIDKey, sharedSecret),
+		)
+
+		cipherText, err := encryptHopData(sharedSecret, hop.PlainText)
+		if err != nil {
+			return nil, err
+		}
+
+		path.BlindedHops[i] = &BlindedHop{
+			BlindedNodePub: blindedNodePub,
+			CipherText:     cipherText,
+		}
+
+		// The ephemeral key of the next hop is derived from the current
+		// one and the shared secret, so that each node is only able to
+		// derive the blinding point of its successor.
+		blindingFactor := nextBlindingFactor(
+			ephemeralKey.PubKey(), sharedSecret,
+		)
+		ephemeralKey = blindPrivKey(ephemeralKey, blindingFactor)
+	}
+
+	return path, nil
+}
+
+// DecryptBlindedData decrypts the data the creator of a blinded path
+// encrypted for us, using the blinding point that was handed to us along
+// with the data.
+func DecryptBlindedData(nodeKey keychain.SingleKeyECDH,
+	blindingPoint *btcec.PublicKey, cipherText []byte) ([]byte, error) {
+
+	sharedSecret, err := nodeKey.ECDH(blindingPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := chacha20poly1305.New(hmac256(rhoKey, sharedSecret))
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [chacha20poly1305.NonceSize]byte
+	plainText, err := aead.Open(nil, nonce[:], cipherText, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decrypt blinded data: %v",
+			err)
+	}
+
+	return plainText, nil
+}
+
+// NextBlindingPoint derives the blinding point that needs to be handed to
+// the next node within a blinded path from the blinding point that was
+// handed to us.
+func NextBlindingPoint(nodeKey keychain.SingleKeyECDH,
+	blindingPoint *btcec.PublicKey) (*btcec.PublicKey, error) {
+
+	sharedSecret, err := nodeKey.ECDH(blindingPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return blindPubKey(
+		blindingPoint, nextBlindingFactor(blindingPoint, sharedSecret),
+	), nil
+}
+
+// blindedNodeKey is an implementation of the SingleKeyECDH interface for the
+// blinded key of a node within a blinded path. It allows a node to process
+// an onion that was constructed for its blinded public key without exposing
+// its private key.
+type blindedNodeKey struct {
+	nodeKey        keychain.SingleKeyECDH
+	blindingFactor []byte
+	pubKey         *btcec.PublicKey
+}
+
+// A compile time check to ensure blindedNodeKey implements the
+// keychain.SingleKeyECDH interface.
+var _ keychain.SingleKeyECDH = (*blindedNodeKey)(nil)
+
+// NewBlindedNodeKey returns the blinded version of our node key for the
+// blinded path that handed us the passed blinding point.
+func NewBlindedNodeKey(nodeKey keychain.SingleKeyECDH,
+	blindingPoint *btcec.PublicKey) (keychain.SingleKeyECDH, error) {
+
+	sharedSecret, err := nodeKey.ECDH(blindingPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	blindingFactor := hmac256(blindedNodeIDKey, sharedSecret)
+
+	return &blindedNodeKey{
+		nodeKey:        nodeKey,
+		blindingFactor: blindingFactor,
+		pubKey:         blindPubKey(nodeKey.PubKey(), blindingFactor),
+	}, nil
+}
+
+// PubKey returns the blinded public key of the node.
+//
+// NOTE: This is part of the keychain.SingleKeyECDH interface.
+func (b *blindedNodeKey) PubKey() *btcec.PublicKey {
+	return b.pubKey
+}
+
+// ECDH performs an ECDH operation between the blinded private key of the node
+// and the passed public key. As the blinded private key is the product of the
+// node key and the blinding factor, we can instead apply the blinding factor
+// to the remote public key.
+//
+// NOTE: This is part of the keychain.SingleKeyECDH interface.
+func (b *blindedNodeKey) ECDH(pubKey *btcec.PublicKey) ([32]byte, error) {
+	return b.nodeKey.ECDH(blindPubKey(pubKey, b.blindingFactor))
+}
+
+// encryptHopData encrypts the data for a hop of a blinded path using the key
+// derived from the shared secret with the hop.
+func encryptHopData(sharedSecret [32]byte, plainText []byte) ([]byte,
+	error) {
+
+	aead, err := chacha20poly1305.New(hmac256(rhoKey, sharedSecret))
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [chacha20poly1305.NonceSize]byte
+	return aead.Seal(nil, nonce[:], plainText, nil), nil
+}
+
+// nextBlindingFactor returns the factor the ephemeral key of a hop is
+// multiplied with to arrive at the ephemeral key of the next hop.
+func nextBlindingFactor(ephemeralPub *btcec.PublicKey,
+	sharedSecret [32]byte) []byte {
+
+	h := sha256.New()
+	h.Write(ephemeralPub.SerializeCompressed())
+	h.Write(sharedSecret[:])
+
+	return h.Sum(nil)
+}
+
+// hmac256 computes the HMAC-SHA256 of the shared secret using the given key.
+func hmac256(key []byte, sharedSecret [32]byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(sharedSecret[:])
+
+	return mac.Sum(nil)
+}
+
+// blindPubKey multiplies the passed public key with the blinding factor.
+func blindPubKey(pubKey *btcec.PublicKey,
+	blindingFactor []byte) *btcec.PublicKey {
+
+	var factor btcec.ModNScalar
+	factor.SetByteSlice(blindingFactor)
+
+	var point, blinded btcec.JacobianPoint
+	pubKey.AsJacobian(&point)
+	btcec.ScalarMultNonConst(&factor, &point, &blinded)
+
+	blinded.ToAffine()
+	return btcec.NewPublicKey(&blinded.X, &blinded.Y)
+}
+
+// blindPrivKey multiplies the passed private key with the blinding factor.
+func blindPrivKey(privKey *btcec.PrivateKey,
+	blindingFactor []byte) *btcec.PrivateKey {
+
+	var d btcec.ModNScalar
+	d.SetByteSlice(blindingFactor)
+	d.Mul(&privKey.Key)
+
+	return &btcec.PrivateKey{Key: d}
+}
@@ -0,0 +1,120 @@
+package blindedpath
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBlindedPath tests that each node of a blinded path is able to decrypt
+// the data that was encrypted for it, derive the blinded key the sender used
+// for it and pass on the correct blinding point to the next node.
+func TestBlindedPath(t *testing.T) {
+	t.Parallel()
+
+	const numHops = 3
+
+	var (
+		nodeKeys = make([]*keychain.PrivKeyECDH, numHops)
+		hops     = make([]*HopInfo, numHops)
+	)
+	for i := 0; i < numHops; i++ {
+		privKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		nodeKeys[i] = &keychain.PrivKeyECDH{PrivKey: privKey}
+		hops[i] = &HopInfo{
+			NodePub:   privKey.PubKey(),
+			PlainText: bytes.Repeat([]byte{byte(i)}, 10+i),
+		}
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	path, err := BuildBlindedPath(sessionKey, hops)
+	require.NoError(t, err)
+	require.Len(t, path.BlindedHops, numHops)
+	require.True(t, path.IntroductionPoint.IsEqual(hops[0].NodePub))
+
+	blindingPoint := path.BlindingPoint
+	for i, blindedHop := range path.BlindedHops {
+		// The blinded node key must not reveal the real node key.
+		require.False(t, blindedHop.BlindedNodePub.IsEqual(
+			hops[i].NodePub,
+		))
+
+		plainText, err := DecryptBlindedData(
+			nodeKeys[i], blindingPoint, blindedHop.CipherText,
+		)
+		require.NoError(t, err)
+		require.Equal(t, hops[i].PlainText, plainText)
+
+		blindedKey, err := NewBlindedNodeKey(nodeKeys[i], blindingPoint)
+		require.NoError(t, err)
+		require.True(t, blindedKey.PubKey().IsEqual(
+			blindedHop.BlindedNodePub,
+		))
+
+		// An ECDH operation with the blinded key must match the one of
+		// a sender that only knows the blinded public key.
+		ephemeralKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		senderECDH := &keychain.PrivKeyECDH{PrivKey: ephemeralKey}
+		expected, err := senderECDH.ECDH(blindedHop.BlindedNodePub)
+		require.NoError(t, err)
+		shared, err := blindedKey.ECDH(ephemeralKey.PubKey())
+		require.NoError(t, err)
+		require.Equal(t, expected, shared)
+
+		blindingPoint, err = NextBlindingPoint(
+			nodeKeys[i], blindingPoint,
+		)
+		require.NoError(t, err)
+	}
+
+	// A node can't decrypt the data of another node.
+	_, err = DecryptBlindedData(
+		nodeKeys[1], path.BlindingPoint,
+		path.BlindedHops[0].CipherText,
+	)
+	require.Error(t, err)
+}
+
+// TestBlindedPathEncoding tests that blinded paths survive an encoding round
+// trip.
+func TestBlindedPathEncoding(t *testing.T) {
+	t.Parallel()
+
+	hops := make([]*HopInfo, 2)
+	for i := range hops {
+		privKey, err := btcec.NewPrivateKey()
+		require.NoError(t, err)
+
+		hops[i] = &HopInfo{
+			NodePub:   privKey.PubKey(),
+			PlainText: bytes.Repeat([]byte{byte(i)}, 5+i),
+		}
+	}
+
+	sessionKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	path, err := BuildBlindedPath(sessionKey, hops)
+	require.NoError(t, err)
+
+	var b bytes.Buffer
+	require.NoError(t, path.Encode(&b))
+
+	decoded, err := DecodeBlindedPath(&b)
+	require.NoError(t, err)
+	require.Equal(t, path, decoded)
+
+	// Paths without hops can't be encoded.
+	path.BlindedHops = nil
+	require.Error(t, path.Encode(&b))
+}
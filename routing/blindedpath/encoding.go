@@ -0,0 +1,120 @@
+package blindedpath
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// Encode serializes the blinded path to the passed io.Writer. The encoding
+// holds the introduction point, the blinding point and the blinded hops, each
+// of which is prefixed with the length of its cipher text.
+func (p *BlindedPath) Encode(w io.Writer) error {
+	if len(p.BlindedHops) == 0 || len(p.BlindedHops) > math.MaxUint8 {
+		return fmt.Errorf("invalid number of blinded hops: %d",
+			len(p.BlindedHops))
+	}
+
+	_, err := w.Write(p.IntroductionPoint.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(p.BlindingPoint.SerializeCompressed())
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{byte(len(p.BlindedHops))}); err != nil {
+		return err
+	}
+
+	for _, hop := range p.BlindedHops {
+		if len(hop.CipherText) > math.MaxUint16 {
+			return fmt.Errorf("cipher text of %d bytes too long",
+				len(hop.CipherText))
+		}
+
+		_, err := w.Write(hop.BlindedNodePub.SerializeCompressed())
+		if err != nil {
+			return err
+		}
+
+		var cipherTextLen [2]byte
+		binary.BigEndian.PutUint16(
+			cipherTextLen[:], uint16(len(hop.CipherText)),
+		)
+		if _, err := w.Write(cipherTextLen[:]); err != nil {
+			return err
+		}
+		if _, err := w.Write(hop.CipherText); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeBlindedPath parses a blinded path that was serialized with Encode
+// from the passed io.Reader.
+func DecodeBlindedPath(r io.Reader) (*BlindedPath, error) {
+	var (
+		p   BlindedPath
+		err error
+	)
+
+	p.IntroductionPoint, err = readPubKey(r)
+	if err != nil {
+		return nil, err
+	}
+	p.BlindingPoint, err = readPubKey(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var numHops [1]byte
+	if _, err := io.ReadFull(r, numHops[:]); err != nil {
+		return nil, err
+	}
+	if numHops[0] == 0 {
+		return nil, ErrNoHops
+	}
+
+	p.BlindedHops = make([]*BlindedHop, numHops[0])
+	for i := range p.BlindedHops {
+		blindedNodePub, err := readPubKey(r)
+		if err != nil {
+			return nil, err
+		}
+
+		var cipherTextLen [2]byte
+		if _, err := io.ReadFull(r, cipherTextLen[:]); err != nil {
+			return nil, err
+		}
+
+		cipherText := make(
+			[]byte, binary.BigEndian.Uint16(cipherTextLen[:]),
+		)
+		if _, err := io.ReadFull(r, cipherText); err != nil {
+			return nil, err
+		}
+
+		p.BlindedHops[i] = &BlindedHop{
+			BlindedNodePub: blindedNodePub,
+			CipherText:     cipherText,
+		}
+	}
+
+	return &p, nil
+}
+
+// readPubKey reads a compressed public key from the passed reader.
+func readPubKey(r io.Reader) (*btcec.PublicKey, error) {
+	var pubKey [33]byte
+	if _, err := io.ReadFull(r, pubKey[:]); err != nil {
+		return nil, err
+	}
+
+	return btcec.ParsePubKey(pubKey[:])
+}
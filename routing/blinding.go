@@ -0,0 +1,79 @@
+package routing
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/lightningnetwork/lnd/zpay32"
+)
+
+// blindedPathFee returns the fee the nodes of a blinded payment path charge
+// for forwarding the passed amount to the recipient of the path.
+func blindedPathFee(path *zpay32.BlindedPaymentPath,
+	amt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	return lnwire.MilliSatoshi(path.FeeBaseMsat) +
+		amt*lnwire.MilliSatoshi(path.FeeRate)/1_000_000
+}
+
+// checkBlindedPathAmount returns an error if the passed amount can't be sent
+// over the blinded payment path.
+func checkBlindedPathAmount(path *zpay32.BlindedPaymentPath,
+	amt lnwire.MilliSatoshi) error {
+
+	if uint64(amt) < path.HTLCMinMsat {
+		return fmt.Errorf("amount %v below blinded path minimum %v",
+			amt, lnwire.MilliSatoshi(path.HTLCMinMsat))
+	}
+
+	if path.HTLCMaxMsat != 0 && uint64(amt) > path.HTLCMaxMsat {
+		return fmt.Errorf("amount %v above blinded path maximum %v",
+			amt, lnwire.MilliSatoshi(path.HTLCMaxMsat))
+	}
+
+	return nil
+}
+
+// addBlindedHops extends a route that ends at the introduction point of a
+// blinded payment path with the blinded hops of the path. The recipient of
+// the path receives amt, while finalExpiry is the lowest expiry it must
+// accept for the HTLC.
+func addBlindedHops(rt *route.Route, path *zpay32.BlindedPaymentPath,
+	amt lnwire.MilliSatoshi, finalExpiry uint32) error {
+
+	blindedHops := path.Path.BlindedHops
+	if len(rt.Hops) == 0 || len(blindedHops) == 0 {
+		return fmt.Errorf("cannot add blinded path to empty route")
+	}
+
+	// The last hop of the route is the introduction point, which receives
+	// its encrypted data along with the blinding point in its payload.
+	// Since the nodes of the path calculate their forwarding parameters
+	// from the encrypted data, all hops are credited with the amount the
+	// recipient receives, which attributes the fees of the path to the
+	// introduction point.
+	introHop := rt.Hops[len(rt.Hops)-1]
+	introHop.EncryptedData = blindedHops[0].CipherText
+	introHop.BlindingPoint = path.Path.BlindingPoint
+	introHop.AmtToForward = amt
+	introHop.OutgoingTimeLock = finalExpiry
+	introHop.LegacyPayload = false
+	introHop.MPP = nil
+
+	for _, blindedHop := range blindedHops[1:] {
+		blindedVertex := route.NewVertex(blindedHop.BlindedNodePub)
+		rt.Hops = append(rt.Hops, &route.Hop{
+			PubKeyBytes:      blindedVertex,
+			AmtToForward:     amt,
+			OutgoingTimeLock: finalExpiry,
+			EncryptedData:    blindedHop.CipherText,
+		})
+	}
+
+	// The final hop identifies itself as such to the recipient through
+	// the total amount of the payment.
+	rt.Hops[len(rt.Hops)-1].TotalAmtMsat = amt
+
+	return nil
+}
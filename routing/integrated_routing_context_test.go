@@ -32,6 +32,7 @@ type integratedRoutingContext struct {
 	finalExpiry int32
 
 	mcCfg          MissionControlConfig
+	aprioriCfg     AprioriConfig
 	pathFindingCfg PathFindingConfig
 }
 
@@ -58,12 +59,10 @@ func newIntegratedRoutingContext(t *testing.T) *integratedRoutingContext {
 		amt:         100000,
 		finalExpiry: 40,
 
-		mcCfg: MissionControlConfig{
-			ProbabilityEstimatorCfg: ProbabilityEstimatorCfg{
-				PenaltyHalfLife:       30 * time.Minute,
-				AprioriHopProbability: 0.6,
-				AprioriWeight:         0.5,
-			},
+		aprioriCfg: AprioriConfig{
+			PenaltyHalfLife:       30 * time.Minute,
+			AprioriHopProbability: 0.6,
+			AprioriWeight:         0.5,
 		},
 
 		pathFindingCfg: PathFindingConfig{
@@ -125,6 +124,12 @@ func (c *integratedRoutingContext) testPayment(maxParts uint32,
 
 	// Instantiate a new mission control with the current configuration
 	// values.
+	estimator, err := NewAprioriEstimator(c.aprioriCfg)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.mcCfg.Estimator = estimator
+
 	mc, err := NewMissionControl(db, c.source.pubkey, &c.mcCfg)
 	if err != nil {
 		c.t.Fatal(err)
@@ -95,6 +95,15 @@ func (r *Manager) UpdatePolicy(newSchema routing.ChannelPolicy,
 			Edge: edge,
 		})
 
+		// The inbound fee may have been left unchanged, so we read it
+		// back from the updated edge.
+		inboundFee, _, err := lnwire.ExtractInboundFee(
+			edge.ExtraOpaqueData,
+		)
+		if err != nil {
+			return err
+		}
+
 		// Add updated policy to list of policies to send to switch.
 		policiesToUpdate[info.ChannelPoint] = htlcswitch.ForwardingPolicy{
 			BaseFee:       edge.FeeBaseMSat,
@@ -102,6 +111,7 @@ func (r *Manager) UpdatePolicy(newSchema routing.ChannelPolicy,
 			TimeLockDelta: uint32(edge.TimeLockDelta),
 			MinHTLCOut:    edge.MinHTLC,
 			MaxHTLC:       edge.MaxHTLC,
+			InboundFee:    inboundFee,
 		}
 
 		return nil
@@ -169,6 +179,20 @@ func (r *Manager) updateEdge(tx kvdb.RTx, chanPoint wire.OutPoint,
 		edge.MinHTLC = *newSchema.MinHTLC
 	}
 
+	// If a new inbound fee is specified, store it in the extra opaque
+	// data of the edge so that it is included in our channel update. A
+	// zero inbound fee is not advertised at all.
+	if newSchema.InboundFee != nil {
+		var extraData lnwire.ExtraOpaqueData
+		if *newSchema.InboundFee != (lnwire.Fee{}) {
+			err := extraData.PackRecords(newSchema.InboundFee)
+			if err != nil {
+				return err
+			}
+		}
+		edge.ExtraOpaqueData = extraData
+	}
+
 	// If the MaxHtlc flag wasn't already set, we can set it now.
 	edge.MessageFlags |= lnwire.ChanUpdateOptionMaxHtlc
 
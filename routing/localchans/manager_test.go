@@ -6,8 +6,8 @@ import (
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
-	"github.com/btcsuite/btcutil"
 
 	"github.com/btcsuite/btcd/wire"
 	"github.com/lightningnetwork/lnd/channeldb"
@@ -33,6 +33,10 @@ func TestManager(t *testing.T) {
 		},
 		TimeLockDelta: 80,
 		MaxHTLC:       5000,
+		InboundFee: &lnwire.Fee{
+			BaseFee: -10,
+			FeeRate: -20,
+		},
 	}
 
 	currentPolicy := channeldb.ChannelEdgePolicy{
@@ -60,6 +64,9 @@ func TestManager(t *testing.T) {
 		if policy.MaxHTLC != newPolicy.MaxHTLC {
 			t.Fatal("unexpected max htlc")
 		}
+		if policy.InboundFee != *newPolicy.InboundFee {
+			t.Fatal("unexpected inbound fee")
+		}
 	}
 
 	propagateChanPolicyUpdate := func(
@@ -86,6 +93,16 @@ func TestManager(t *testing.T) {
 			t.Fatal("unexpected max htlc")
 		}
 
+		inboundFee, ok, err := lnwire.ExtractInboundFee(
+			policy.ExtraOpaqueData,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok || inboundFee != *newPolicy.InboundFee {
+			t.Fatal("unexpected inbound fee")
+		}
+
 		return nil
 	}
 
@@ -138,11 +155,12 @@ func TestManager(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	// If no max htlc is specified, the max htlc value should be kept
-	// unchanged.
+	// If no max htlc or inbound fee is specified, the max htlc value and
+	// the inbound fee should be kept unchanged.
 	currentPolicy.MaxHTLC = newPolicy.MaxHTLC
 	noMaxHtlcPolicy := newPolicy
 	noMaxHtlcPolicy.MaxHTLC = 0
+	noMaxHtlcPolicy.InboundFee = nil
 
 	err = manager.UpdatePolicy(noMaxHtlcPolicy)
 	if err != nil {
@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -72,6 +73,10 @@ var (
 	// ErrInvalidFailureInterval is returned if we get an invalid failure
 	// interval.
 	ErrInvalidFailureInterval = errors.New("failure interval must be >= 0")
+
+	// ErrNoEstimator is returned if mission control is configured without
+	// a probability estimator.
+	ErrNoEstimator = errors.New("no probability estimator configured")
 )
 
 // NodeResults contains previous results from a node to its peers.
@@ -102,7 +107,7 @@ type MissionControl struct {
 
 	// estimator is the probability estimator that is used with the payment
 	// results that mission control collects.
-	estimator *probabilityEstimator
+	estimator ProbabilityEstimator
 
 	sync.Mutex
 
@@ -115,9 +120,8 @@ type MissionControl struct {
 // MissionControlConfig defines parameters that control mission control
 // behaviour.
 type MissionControlConfig struct {
-	// ProbabilityEstimatorConfig is the config we will use for probability
-	// calculations.
-	ProbabilityEstimatorCfg
+	// Estimator gives probability estimates for node pairs.
+	Estimator ProbabilityEstimator
 
 	// MaxMcHistory defines the maximum number of payment results that are
 	// held on disk.
@@ -134,8 +138,8 @@ type MissionControlConfig struct {
 }
 
 func (c *MissionControlConfig) validate() error {
-	if err := c.ProbabilityEstimatorCfg.validate(); err != nil {
-		return err
+	if c.Estimator == nil {
+		return ErrNoEstimator
 	}
 
 	if c.MaxMcHistory < 0 {
@@ -151,11 +155,9 @@ func (c *MissionControlConfig) validate() error {
 
 // String returns a string representation of a mission control config.
 func (c *MissionControlConfig) String() string {
-	return fmt.Sprintf("Penalty Half Life: %v, Apriori Hop "+
-		"Probablity: %v, Maximum History: %v, Apriori Weight: %v, "+
-		"Minimum Failure Relax Interval: %v", c.PenaltyHalfLife,
-		c.AprioriHopProbability, c.MaxMcHistory, c.AprioriWeight,
-		c.MinFailureRelaxInterval)
+	return fmt.Sprintf("Estimator: (%v), Maximum History: %v, "+
+		"Minimum Failure Relax Interval: %v", c.Estimator,
+		c.MaxMcHistory, c.MinFailureRelaxInterval)
 }
 
 // TimedPairResult describes a timestamped pair result.
@@ -224,17 +226,12 @@ func NewMissionControl(db kvdb.Backend, self route.Vertex,
 		return nil, err
 	}
 
-	estimator := &probabilityEstimator{
-		ProbabilityEstimatorCfg: cfg.ProbabilityEstimatorCfg,
-		prevSuccessProbability:  prevSuccessProbability,
-	}
-
 	mc := &MissionControl{
 		state:     newMissionControlState(cfg.MinFailureRelaxInterval),
 		now:       time.Now,
 		selfNode:  self,
 		store:     store,
-		estimator: estimator,
+		estimator: cfg.Estimator,
 	}
 
 	if err := mc.init(); err != nil {
@@ -283,7 +280,7 @@ func (m *MissionControl) GetConfig() *MissionControlConfig {
 	defer m.Unlock()
 
 	return &MissionControlConfig{
-		ProbabilityEstimatorCfg: m.estimator.ProbabilityEstimatorCfg,
+		Estimator:               m.estimator,
 		MaxMcHistory:            m.store.maxRecords,
 		McFlushInterval:         m.store.flushInterval,
 		MinFailureRelaxInterval: m.state.minFailureRelaxInterval,
@@ -308,7 +305,7 @@ func (m *MissionControl) SetConfig(cfg *MissionControlConfig) error {
 
 	m.store.maxRecords = cfg.MaxMcHistory
 	m.state.minFailureRelaxInterval = cfg.MinFailureRelaxInterval
-	m.estimator.ProbabilityEstimatorCfg = cfg.ProbabilityEstimatorCfg
+	m.estimator = cfg.Estimator
 
 	return nil
 }
@@ -331,9 +328,10 @@ func (m *MissionControl) ResetHistory() error {
 }
 
 // GetProbability is expected to return the success probability of a payment
-// from fromNode along edge.
+// from fromNode along edge. The capacity is the capacity of the channel that
+// is used, which is zero if it is unknown.
 func (m *MissionControl) GetProbability(fromNode, toNode route.Vertex,
-	amt lnwire.MilliSatoshi) float64 {
+	amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64 {
 
 	m.Lock()
 	defer m.Unlock()
@@ -343,10 +341,12 @@ func (m *MissionControl) GetProbability(fromNode, toNode route.Vertex,
 
 	// Use a distinct probability estimation function for local channels.
 	if fromNode == m.selfNode {
-		return m.estimator.getLocalPairProbability(now, results, toNode)
+		return m.estimator.LocalPairProbability(now, results, toNode)
 	}
 
-	return m.estimator.getPairProbability(now, results, toNode, amt)
+	return m.estimator.PairProbability(
+		now, results, toNode, amt, capacity,
+	)
 }
 
 // GetHistorySnapshot takes a snapshot from the current mission control state
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing/route"
@@ -38,6 +39,7 @@ var (
 	testPenaltyHalfLife       = 30 * time.Minute
 	testAprioriHopProbability = 0.9
 	testAprioriWeight         = 0.5
+	testCapacity              = btcutil.Amount(100_000)
 )
 
 type mcTestContext struct {
@@ -84,14 +86,19 @@ func (ctx *mcTestContext) restartMc() {
 		require.NoError(ctx.t, ctx.mc.store.storeResults())
 	}
 
+	estimator, err := NewAprioriEstimator(AprioriConfig{
+		PenaltyHalfLife:       testPenaltyHalfLife,
+		AprioriHopProbability: testAprioriHopProbability,
+		AprioriWeight:         testAprioriWeight,
+	})
+	if err != nil {
+		ctx.t.Fatal(err)
+	}
+
 	mc, err := NewMissionControl(
 		ctx.db, mcTestSelf,
 		&MissionControlConfig{
-			ProbabilityEstimatorCfg: ProbabilityEstimatorCfg{
-				PenaltyHalfLife:       testPenaltyHalfLife,
-				AprioriHopProbability: testAprioriHopProbability,
-				AprioriWeight:         testAprioriWeight,
-			},
+			Estimator: estimator,
 		},
 	)
 	if err != nil {
@@ -112,7 +119,9 @@ func (ctx *mcTestContext) cleanup() {
 func (ctx *mcTestContext) expectP(amt lnwire.MilliSatoshi, expected float64) {
 	ctx.t.Helper()
 
-	p := ctx.mc.GetProbability(mcTestNode1, mcTestNode2, amt)
+	p := ctx.mc.GetProbability(
+		mcTestNode1, mcTestNode2, amt, testCapacity,
+	)
 	if p != expected {
 		ctx.t.Fatalf("expected probability %v but got %v", expected, p)
 	}
@@ -151,7 +160,9 @@ func TestMissionControl(t *testing.T) {
 
 	// For local channels, we expect a higher probability than our a prior
 	// test probability.
-	selfP := ctx.mc.GetProbability(mcTestSelf, mcTestNode1, 100)
+	selfP := ctx.mc.GetProbability(
+		mcTestSelf, mcTestNode1, 100, testCapacity,
+	)
 	if selfP != prevSuccessProbability {
 		t.Fatalf("expected prev success prob for untried local chans")
 	}
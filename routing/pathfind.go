@@ -7,6 +7,7 @@ import (
 	"math"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	sphinx "github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/feature"
@@ -42,7 +43,7 @@ const (
 type pathFinder = func(g *graphParams, r *RestrictParams,
 	cfg *PathFindingConfig, source, target route.Vertex,
 	amt lnwire.MilliSatoshi, finalHtlcExpiry int32) (
-	[]*channeldb.ChannelEdgePolicy, error)
+	[]*unifiedPolicyEdge, error)
 
 var (
 	// DefaultAttemptCost is the default fixed virtual cost in path finding
@@ -102,7 +103,7 @@ type finalHopParams struct {
 // any feature vectors on all hops have been validated for transitive
 // dependencies.
 func newRoute(sourceVertex route.Vertex,
-	pathEdges []*channeldb.ChannelEdgePolicy, currentHeight uint32,
+	pathEdges []*unifiedPolicyEdge, currentHeight uint32,
 	finalHop finalHopParams) (*route.Route, error) {
 
 	var (
@@ -124,7 +125,7 @@ func newRoute(sourceVertex route.Vertex,
 	for i := pathLength - 1; i >= 0; i-- {
 		// Now we'll start to calculate the items within the per-hop
 		// payload for the hop this edge is leading to.
-		edge := pathEdges[i]
+		edge := pathEdges[i].policy
 
 		// We'll calculate the amounts, timelocks, and fees for each hop
 		// in the route. The base case is the final hop which includes
@@ -211,14 +212,20 @@ func newRoute(sourceVertex route.Vertex,
 			// based on the amount that this hop needs to forward
 			// and its policy for the outgoing channel. This policy
 			// is stored as part of the incoming channel of
-			// the next hop.
-			fee = pathEdges[i+1].ComputeFee(amtToForward)
+			// the next hop. On top of that, the current hop may
+			// charge an inbound fee for the incoming channel.
+			fee = computeForwardingFee(
+				pathEdges[i+1].policy, pathEdges[i].inboundFee,
+				amtToForward,
+			)
 
 			// We'll take the total timelock of the preceding hop as
 			// the outgoing timelock or this hop. Then we'll
 			// increment the total timelock incurred by this hop.
 			outgoingTimeLock = totalTimeLock
-			totalTimeLock += uint32(pathEdges[i+1].TimeLockDelta)
+			totalTimeLock += uint32(
+				pathEdges[i+1].policy.TimeLockDelta,
+			)
 		}
 
 		// Since we're traversing the path backwards atm, we prepend
@@ -254,6 +261,46 @@ func newRoute(sourceVertex route.Vertex,
 	return newRoute, nil
 }
 
+// computeForwardingFee returns the total fee that a node charges for
+// forwarding amt over the outgoing channel with the given policy, if the htlc
+// arrives over a channel with the given inbound fee. The inbound fee is
+// calculated over the outgoing amount plus the outgoing fee. It may be
+// negative, but the total fee never drops below zero.
+func computeForwardingFee(outgoing *channeldb.ChannelEdgePolicy,
+	inboundFee lnwire.Fee, amt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	outboundFee := outgoing.ComputeFee(amt)
+	fee := int64(outboundFee) + inboundFee.CalcFee(amt+outboundFee)
+	if fee < 0 {
+		return 0
+	}
+
+	return lnwire.MilliSatoshi(fee)
+}
+
+// computeAmtToForward returns the largest amount that a node can forward over
+// the outgoing channel with the given policy, if it receives incomingAmt over
+// a channel with the given inbound fee. This is the inverse of
+// computeForwardingFee. Because the total fee is never negative, the result
+// is never larger than the incoming amount, which allows for a binary search.
+func computeAmtToForward(outgoing *channeldb.ChannelEdgePolicy,
+	inboundFee lnwire.Fee,
+	incomingAmt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	low, high := lnwire.MilliSatoshi(0), incomingAmt
+	for low < high {
+		mid := low + (high-low+1)/2
+		fee := computeForwardingFee(outgoing, inboundFee, mid)
+		if mid+fee <= incomingAmt {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+
+	return low
+}
+
 // edgeWeight computes the weight of an edge. This value is used when searching
 // for the shortest path within the channel graph between two nodes. Weight is
 // is the fee itself plus a time lock penalty added to it. This benefits
@@ -298,7 +345,7 @@ type RestrictParams struct {
 	// ProbabilitySource is a callback that is expected to return the
 	// success probability of traversing the channel from the node.
 	ProbabilitySource func(route.Vertex, route.Vertex,
-		lnwire.MilliSatoshi) float64
+		lnwire.MilliSatoshi, btcutil.Amount) float64
 
 	// FeeLimit is a maximum fee amount allowed to be used on the path from
 	// the source to the target.
@@ -416,7 +463,7 @@ func getOutgoingBalance(node route.Vertex, outgoingChans map[uint64]struct{},
 // available bandwidth.
 func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 	source, target route.Vertex, amt lnwire.MilliSatoshi,
-	finalHtlcExpiry int32) ([]*channeldb.ChannelEdgePolicy, error) {
+	finalHtlcExpiry int32) ([]*unifiedPolicyEdge, error) {
 
 	// Pathfinding can be a significant portion of the total payment
 	// latency, especially on low-powered devices. Log several metrics to
@@ -587,17 +634,48 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 	// satisfy our specific requirements.
 	processEdge := func(fromVertex route.Vertex,
 		fromFeatures *lnwire.FeatureVector,
-		edge *channeldb.ChannelEdgePolicy, toNodeDist *nodeWithDist) {
+		unifiedEdge *unifiedPolicyEdge, toNodeDist *nodeWithDist) {
 
 		edgesExpanded++
 
+		edge := unifiedEdge.policy
+
+		// Calculate the inbound fee that the to node charges for htlcs
+		// that arrive over this channel. It is based on the amount
+		// that the to node needs to receive, which already includes
+		// its outbound fee. The target node doesn't forward and
+		// therefore doesn't charge an inbound fee. The inbound fee may
+		// be a discount, but the total fee of the to node can never be
+		// negative.
+		var inboundFee int64
+		if toNodeDist.node != target {
+			inboundFee = unifiedEdge.inboundFee.CalcFee(
+				toNodeDist.amountToReceive,
+			)
+
+			minInboundFee := -int64(toNodeDist.outboundFee)
+			if inboundFee < minInboundFee {
+				inboundFee = minInboundFee
+			}
+		}
+
 		// Calculate amount that the candidate node would have to send
 		// out.
-		amountToSend := toNodeDist.amountToReceive
+		amountToSend := lnwire.MilliSatoshi(
+			int64(toNodeDist.amountToReceive) + inboundFee,
+		)
+
+		// The inbound fee changes the amount that flows through this
+		// channel, so we need to make sure that it is still within the
+		// channel's limits.
+		if inboundFee != 0 && !unifiedEdge.amtInRange(amountToSend) {
+			return
+		}
 
 		// Request the success probability for this edge.
 		edgeProbability := r.ProbabilitySource(
 			fromVertex, toNodeDist.node, amountToSend,
+			unifiedEdge.capacity,
 		)
 
 		log.Trace(newLogClosure(func() string {
@@ -663,10 +741,18 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		}
 
 		// By adding fromVertex in the route, there will be an extra
-		// weight composed of the fee that this node will charge and
-		// the amount that will be locked for timeLockDelta blocks in
-		// the HTLC that is handed out to fromVertex.
-		weight := edgeWeight(amountToReceive, fee, timeLockDelta)
+		// weight composed of the fee that this node will charge, the
+		// inbound fee of the to node and the amount that will be
+		// locked for timeLockDelta blocks in the HTLC that is handed
+		// out to fromVertex.
+		weightFee := int64(fee) + inboundFee
+		if weightFee < 0 {
+			weightFee = 0
+		}
+		weight := edgeWeight(
+			amountToReceive, lnwire.MilliSatoshi(weightFee),
+			timeLockDelta,
+		)
 
 		// Compute the tentative weight to this new channel/edge
 		// which is the weight from our toNode to the target node
@@ -745,9 +831,10 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 			weight:          tempWeight,
 			node:            fromVertex,
 			amountToReceive: amountToReceive,
+			outboundFee:     fee,
 			incomingCltv:    incomingCltv,
 			probability:     probability,
-			nextHop:         edge,
+			nextHop:         unifiedEdge,
 			routingInfoSize: routingInfoSize,
 		}
 		distance[fromVertex] = withDist
@@ -817,7 +904,10 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		}
 
 		for _, reverseEdge := range additionalEdgesWithSrc[pivot] {
-			u.addPolicy(reverseEdge.sourceNode, reverseEdge.edge, 0)
+			u.addPolicy(
+				reverseEdge.sourceNode, reverseEdge.edge,
+				lnwire.Fee{}, 0,
+			)
 		}
 
 		amtToSend := partialPath.amountToReceive
@@ -883,7 +973,7 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 
 	// Use the distance map to unravel the forward path from source to
 	// target.
-	var pathEdges []*channeldb.ChannelEdgePolicy
+	var pathEdges []*unifiedPolicyEdge
 	currentNode := source
 	for {
 		// Determine the next hop forward using the next map.
@@ -897,7 +987,7 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		pathEdges = append(pathEdges, currentNodeWithDist.nextHop)
 
 		// Advance current node.
-		currentNode = currentNodeWithDist.nextHop.Node.PubKeyBytes
+		currentNode = currentNodeWithDist.nextHop.policy.Node.PubKeyBytes
 
 		// Check stop condition at the end of this loop. This prevents
 		// breaking out too soon for self-payments that have target set
@@ -918,7 +1008,7 @@ func findPath(g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 	// route construction does not care where the features are actually
 	// taken from. In the future we may wish to do route construction within
 	// findPath, and avoid using ChannelEdgePolicy altogether.
-	pathEdges[len(pathEdges)-1].Node.Features = features
+	pathEdges[len(pathEdges)-1].policy.Node.Features = features
 
 	log.Debugf("Found route: probability=%v, hops=%v, fee=%v",
 		distance[source].probability, len(pathEdges),
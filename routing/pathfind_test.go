@@ -10,7 +10,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math"
-	"math/big"
 	"net"
 	"os"
 	"reflect"
@@ -18,10 +17,11 @@ import (
 	"testing"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/record"
@@ -85,12 +85,13 @@ var (
 )
 
 var (
-	testSig = &btcec.Signature{
-		R: new(big.Int),
-		S: new(big.Int),
-	}
-	_, _ = testSig.R.SetString("63724406601629180062774974542967536251589935445068131219452686511677818569431", 10)
-	_, _ = testSig.S.SetString("18801056069249825825291287104931333862866033135609736119018462340006816851118", 10)
+	testRBytes, _ = hex.DecodeString("8ce2bc69281ce27da07e6683571319d18e949ddfa2965fb6caa1bf0314f882d7")
+	testSBytes, _ = hex.DecodeString("299105481d63e0f4bc2a88121167221b6700d72a0ead154c03be696a292d24ae")
+	testRScalar   = new(btcec.ModNScalar)
+	testSScalar   = new(btcec.ModNScalar)
+	_             = testRScalar.SetByteSlice(testRBytes)
+	_             = testSScalar.SetByteSlice(testSBytes)
+	testSig       = ecdsa.NewSignature(testRScalar, testSScalar)
 
 	testAuthProof = channeldb.ChannelAuthProof{
 		NodeSig1Bytes:    testSig.Serialize(),
@@ -102,7 +103,9 @@ var (
 
 // noProbabilitySource is used in testing to return the same probability 1 for
 // all edges.
-func noProbabilitySource(route.Vertex, route.Vertex, lnwire.MilliSatoshi) float64 {
+func noProbabilitySource(route.Vertex, route.Vertex, lnwire.MilliSatoshi,
+	btcutil.Amount) float64 {
+
 	return 1
 }
 
@@ -242,9 +245,7 @@ func parseTestGraph(path string) (*testGraphInstance, error) {
 			return nil, err
 		}
 		if len(privBytes) > 0 {
-			key, derivedPub := btcec.PrivKeyFromBytes(
-				btcec.S256(), privBytes,
-			)
+			key, derivedPub := btcec.PrivKeyFromBytes(privBytes)
 
 			if !bytes.Equal(
 				pubBytes, derivedPub.SerializeCompressed(),
@@ -398,6 +399,7 @@ type testChannelPolicy struct {
 	LastUpdate  time.Time
 	Disabled    bool
 	Features    *lnwire.FeatureVector
+	InboundFee  *lnwire.Fee
 }
 
 type testChannelEnd struct {
@@ -501,8 +503,7 @@ func createTestGraphFromChannels(testChannels []*testChannel, source string) (
 			0, 0, 0, 0, 0, 0, 0, nodeIndex + 1,
 		}
 
-		privKey, pubKey := btcec.PrivKeyFromBytes(btcec.S256(),
-			keyBytes)
+		privKey, pubKey := btcec.PrivKeyFromBytes(keyBytes)
 
 		if features == nil {
 			features = lnwire.EmptyFeatureVector()
@@ -634,6 +635,14 @@ func createTestGraphFromChannels(testChannels []*testChannel, source string) (
 				FeeBaseMSat:               node1.FeeBaseMsat,
 				FeeProportionalMillionths: node1.FeeRate,
 			}
+			if node1.InboundFee != nil {
+				var extraData lnwire.ExtraOpaqueData
+				err := extraData.PackRecords(node1.InboundFee)
+				if err != nil {
+					return nil, err
+				}
+				edgePolicy.ExtraOpaqueData = extraData
+			}
 			if err := graph.UpdateEdgePolicy(edgePolicy); err != nil {
 				return nil, err
 			}
@@ -662,6 +671,14 @@ func createTestGraphFromChannels(testChannels []*testChannel, source string) (
 				FeeBaseMSat:               node2.FeeBaseMsat,
 				FeeProportionalMillionths: node2.FeeRate,
 			}
+			if node2.InboundFee != nil {
+				var extraData lnwire.ExtraOpaqueData
+				err := extraData.PackRecords(node2.InboundFee)
+				if err != nil {
+					return nil, err
+				}
+				edgePolicy.ExtraOpaqueData = extraData
+			}
 			if err := graph.UpdateEdgePolicy(edgePolicy); err != nil {
 				return nil, err
 			}
@@ -1031,7 +1048,7 @@ func TestPathFindingWithAdditionalEdges(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unable to decode public key: %v", err)
 	}
-	dogePubKey, err := btcec.ParsePubKey(dogePubKeyBytes, btcec.S256())
+	dogePubKey, err := btcec.ParsePubKey(dogePubKeyBytes)
 	if err != nil {
 		t.Fatalf("unable to parse public key from bytes: %v", err)
 	}
@@ -1057,7 +1074,7 @@ func TestPathFindingWithAdditionalEdges(t *testing.T) {
 	}
 
 	find := func(r *RestrictParams) (
-		[]*channeldb.ChannelEdgePolicy, error) {
+		[]*unifiedPolicyEdge, error) {
 
 		return dbFindPath(
 			graph.graph, additionalEdges, nil,
@@ -1124,27 +1141,38 @@ func TestNewRoute(t *testing.T) {
 	createHop := func(baseFee lnwire.MilliSatoshi,
 		feeRate lnwire.MilliSatoshi,
 		bandwidth lnwire.MilliSatoshi,
-		timeLockDelta uint16) *channeldb.ChannelEdgePolicy {
-
-		return &channeldb.ChannelEdgePolicy{
-			Node: &channeldb.LightningNode{
-				Features: lnwire.NewFeatureVector(
-					nil, nil,
-				),
+		timeLockDelta uint16) *unifiedPolicyEdge {
+
+		return &unifiedPolicyEdge{
+			policy: &channeldb.ChannelEdgePolicy{
+				Node: &channeldb.LightningNode{
+					Features: lnwire.NewFeatureVector(
+						nil, nil,
+					),
+				},
+				FeeProportionalMillionths: feeRate,
+				FeeBaseMSat:               baseFee,
+				TimeLockDelta:             timeLockDelta,
 			},
-			FeeProportionalMillionths: feeRate,
-			FeeBaseMSat:               baseFee,
-			TimeLockDelta:             timeLockDelta,
 		}
 	}
 
+	// withInboundFee sets the inbound fee that the node the hop points to
+	// charges for htlcs that arrive over the hop.
+	withInboundFee := func(hop *unifiedPolicyEdge,
+		inboundFee lnwire.Fee) *unifiedPolicyEdge {
+
+		hop.inboundFee = inboundFee
+		return hop
+	}
+
 	testCases := []struct {
 		// name identifies the test case in the test output.
 		name string
 
 		// hops is the list of hops (the route) that gets passed into
 		// the call to newRoute.
-		hops []*channeldb.ChannelEdgePolicy
+		hops []*unifiedPolicyEdge
 
 		// paymentAmount is the amount that is send into the route
 		// indicated by hops.
@@ -1193,7 +1221,7 @@ func TestNewRoute(t *testing.T) {
 			// For a single hop payment, no fees are expected to be paid.
 			name:          "single hop",
 			paymentAmount: 100000,
-			hops: []*channeldb.ChannelEdgePolicy{
+			hops: []*unifiedPolicyEdge{
 				createHop(100, 1000, 1000000, 10),
 			},
 			expectedFees:          []lnwire.MilliSatoshi{0},
@@ -1206,7 +1234,7 @@ func TestNewRoute(t *testing.T) {
 			// a fee to receive the payment.
 			name:          "two hop",
 			paymentAmount: 100000,
-			hops: []*channeldb.ChannelEdgePolicy{
+			hops: []*unifiedPolicyEdge{
 				createHop(0, 1000, 1000000, 10),
 				createHop(30, 1000, 1000000, 5),
 			},
@@ -1221,7 +1249,7 @@ func TestNewRoute(t *testing.T) {
 			name:          "two hop tlv onion feature",
 			destFeatures:  tlvFeatures,
 			paymentAmount: 100000,
-			hops: []*channeldb.ChannelEdgePolicy{
+			hops: []*unifiedPolicyEdge{
 				createHop(0, 1000, 1000000, 10),
 				createHop(30, 1000, 1000000, 5),
 			},
@@ -1238,7 +1266,7 @@ func TestNewRoute(t *testing.T) {
 			destFeatures:  tlvPayAddrFeatures,
 			paymentAddr:   &testPaymentAddr,
 			paymentAmount: 100000,
-			hops: []*channeldb.ChannelEdgePolicy{
+			hops: []*unifiedPolicyEdge{
 				createHop(0, 1000, 1000000, 10),
 				createHop(30, 1000, 1000000, 5),
 			},
@@ -1258,7 +1286,7 @@ func TestNewRoute(t *testing.T) {
 			// gets rounded down to 1.
 			name:          "three hop",
 			paymentAmount: 100000,
-			hops: []*channeldb.ChannelEdgePolicy{
+			hops: []*unifiedPolicyEdge{
 				createHop(0, 10, 1000000, 10),
 				createHop(0, 10, 1000000, 5),
 				createHop(0, 10, 1000000, 3),
@@ -1273,7 +1301,7 @@ func TestNewRoute(t *testing.T) {
 			// because of the increase amount to forward.
 			name:          "three hop with fee carry over",
 			paymentAmount: 100000,
-			hops: []*channeldb.ChannelEdgePolicy{
+			hops: []*unifiedPolicyEdge{
 				createHop(0, 10000, 1000000, 10),
 				createHop(0, 10000, 1000000, 5),
 				createHop(0, 10000, 1000000, 3),
@@ -1288,7 +1316,7 @@ func TestNewRoute(t *testing.T) {
 			// effect.
 			name:          "three hop with minimal fees for carry over",
 			paymentAmount: 100000,
-			hops: []*channeldb.ChannelEdgePolicy{
+			hops: []*unifiedPolicyEdge{
 				createHop(0, 10000, 1000000, 10),
 
 				// First hop charges 0.1% so the second hop fee
@@ -1303,6 +1331,78 @@ func TestNewRoute(t *testing.T) {
 			expectedTotalAmount:   101101,
 			expectedTimeLocks:     []uint32{4, 1, 1},
 			expectedTotalTimeLock: 9,
+		}, {
+			// A three hop payment where both forwarding nodes
+			// charge an inbound fee. The first hop gives a discount
+			// that is calculated over the amount to forward plus
+			// its outbound fee: 1000 - 300 - 101200*1000/1e6.
+			name:          "three hop with inbound fees",
+			paymentAmount: 100000,
+			hops: []*unifiedPolicyEdge{
+				withInboundFee(
+					createHop(0, 0, 1000000, 10),
+					lnwire.Fee{BaseFee: -300, FeeRate: -1000},
+				),
+				withInboundFee(
+					createHop(1000, 0, 1000000, 5),
+					lnwire.Fee{BaseFee: 200},
+				),
+
+				// The final hop doesn't forward, so its inbound
+				// fee must be ignored.
+				withInboundFee(
+					createHop(0, 0, 1000000, 3),
+					lnwire.Fee{BaseFee: 5000},
+				),
+			},
+			expectedFees:          []lnwire.MilliSatoshi{599, 200, 0},
+			expectedTotalAmount:   100799,
+			expectedTimeLocks:     []uint32{4, 1, 1},
+			expectedTotalTimeLock: 9,
+		}, {
+			// A three hop payment where the inbound discount of the
+			// first hop exceeds its outbound fee. The total fee of a
+			// node can never be negative.
+			name:          "three hop with floored inbound discount",
+			paymentAmount: 100000,
+			hops: []*unifiedPolicyEdge{
+				withInboundFee(
+					createHop(0, 0, 1000000, 10),
+					lnwire.Fee{BaseFee: -5000},
+				),
+				withInboundFee(
+					createHop(1000, 0, 1000000, 5),
+					lnwire.Fee{BaseFee: 200},
+				),
+				createHop(0, 0, 1000000, 3),
+			},
+			expectedFees:          []lnwire.MilliSatoshi{0, 200, 0},
+			expectedTotalAmount:   100200,
+			expectedTimeLocks:     []uint32{4, 1, 1},
+			expectedTotalTimeLock: 9,
+		}, {
+			// A three hop payment where both forwarding nodes
+			// charge an inbound surcharge. The proportional
+			// surcharge of the first hop is calculated over the
+			// amount to forward plus its outbound fee:
+			// 1000 + 101100*1000/1e6.
+			name:          "three hop with inbound surcharge",
+			paymentAmount: 100000,
+			hops: []*unifiedPolicyEdge{
+				withInboundFee(
+					createHop(0, 0, 1000000, 10),
+					lnwire.Fee{FeeRate: 1000},
+				),
+				withInboundFee(
+					createHop(1000, 0, 1000000, 5),
+					lnwire.Fee{BaseFee: 100},
+				),
+				createHop(0, 0, 1000000, 3),
+			},
+			expectedFees:          []lnwire.MilliSatoshi{1101, 100, 0},
+			expectedTotalAmount:   101201,
+			expectedTimeLocks:     []uint32{4, 1, 1},
+			expectedTotalTimeLock: 9,
 		}}
 
 	for _, testCase := range testCases {
@@ -1312,7 +1412,7 @@ func TestNewRoute(t *testing.T) {
 		// custom feature vector.
 		if testCase.destFeatures != nil {
 			finalHop := testCase.hops[len(testCase.hops)-1]
-			finalHop.Node.Features = testCase.destFeatures
+			finalHop.policy.Node.Features = testCase.destFeatures
 		}
 
 		assertRoute := func(t *testing.T, route *route.Route) {
@@ -1539,7 +1639,7 @@ func TestDestTLVGraphFallback(t *testing.T) {
 	}
 
 	find := func(r *RestrictParams,
-		target route.Vertex) ([]*channeldb.ChannelEdgePolicy, error) {
+		target route.Vertex) ([]*unifiedPolicyEdge, error) {
 
 		return dbFindPath(
 			ctx.graph, nil, nil,
@@ -2270,16 +2370,17 @@ func TestPathFindSpecExample(t *testing.T) {
 }
 
 func assertExpectedPath(t *testing.T, aliasMap map[string]route.Vertex,
-	path []*channeldb.ChannelEdgePolicy, nodeAliases ...string) {
+	path []*unifiedPolicyEdge, nodeAliases ...string) {
 
 	if len(path) != len(nodeAliases) {
 		t.Fatal("number of hops and number of aliases do not match")
 	}
 
 	for i, hop := range path {
-		if hop.Node.PubKeyBytes != aliasMap[nodeAliases[i]] {
+		node := hop.policy.Node
+		if node.PubKeyBytes != aliasMap[nodeAliases[i]] {
 			t.Fatalf("expected %v to be pos #%v in hop, instead "+
-				"%v was", nodeAliases[i], i, hop.Node.Alias)
+				"%v was", nodeAliases[i], i, node.Alias)
 		}
 	}
 }
@@ -2358,10 +2459,10 @@ func TestRestrictOutgoingChannel(t *testing.T) {
 
 	// Assert that the route starts with channel chanSourceB1, in line with
 	// the specified restriction.
-	if path[0].ChannelID != chanSourceB1 {
+	if path[0].policy.ChannelID != chanSourceB1 {
 		t.Fatalf("expected route to pass through channel %v, "+
 			"but channel %v was selected instead", chanSourceB1,
-			path[0].ChannelID)
+			path[0].policy.ChannelID)
 	}
 
 	// If a direct channel to target is allowed as well, that channel is
@@ -2373,7 +2474,7 @@ func TestRestrictOutgoingChannel(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unable to find path: %v", err)
 	}
-	if path[0].ChannelID != chanSourceTarget {
+	if path[0].policy.ChannelID != chanSourceTarget {
 		t.Fatalf("expected route to pass through channel %v",
 			chanSourceTarget)
 	}
@@ -2417,10 +2518,10 @@ func TestRestrictLastHop(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unable to find path: %v", err)
 	}
-	if path[0].ChannelID != 3 {
+	if path[0].policy.ChannelID != 3 {
 		t.Fatalf("expected route to pass through channel 3, "+
 			"but channel %v was selected instead",
-			path[0].ChannelID)
+			path[0].policy.ChannelID)
 	}
 }
 
@@ -2643,7 +2744,7 @@ func testProbabilityRouting(t *testing.T, paymentAmt btcutil.Amount,
 
 	// Configure a probability source with the test parameters.
 	ctx.restrictParams.ProbabilitySource = func(fromNode, toNode route.Vertex,
-		amt lnwire.MilliSatoshi) float64 {
+		amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64 {
 
 		if amt == 0 {
 			t.Fatal("expected non-zero amount")
@@ -2681,10 +2782,10 @@ func testProbabilityRouting(t *testing.T, paymentAmt btcutil.Amount,
 	}
 
 	// Assert that the route passes through the expected channel.
-	if path[1].ChannelID != expectedChan {
+	if path[1].policy.ChannelID != expectedChan {
 		t.Fatalf("expected route to pass through channel %v, "+
 			"but channel %v was selected instead", expectedChan,
-			path[1].ChannelID)
+			path[1].policy.ChannelID)
 	}
 }
 
@@ -2726,7 +2827,7 @@ func TestEqualCostRouteSelection(t *testing.T) {
 	target := ctx.testGraphInstance.aliasMap["target"]
 
 	ctx.restrictParams.ProbabilitySource = func(fromNode, toNode route.Vertex,
-		amt lnwire.MilliSatoshi) float64 {
+		amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64 {
 
 		switch {
 		case fromNode == alias["source"] && toNode == alias["a"]:
@@ -2747,10 +2848,10 @@ func TestEqualCostRouteSelection(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if path[1].ChannelID != 2 {
+	if path[1].policy.ChannelID != 2 {
 		t.Fatalf("expected route to pass through channel %v, "+
 			"but channel %v was selected instead", 2,
-			path[1].ChannelID)
+			path[1].policy.ChannelID)
 	}
 }
 
@@ -2875,6 +2976,87 @@ func TestRouteToSelf(t *testing.T) {
 	ctx.assertPath(path, []uint64{1, 3, 2})
 }
 
+// TestInboundFees tests that path finding takes into account the inbound fees
+// that forwarding nodes charge for their incoming channels.
+func TestInboundFees(t *testing.T) {
+	t.Parallel()
+
+	noFeePolicy := &testChannelPolicy{
+		Expiry: 144,
+	}
+
+	testChannels := []*testChannel{
+		// Node a charges an inbound fee for htlcs that arrive over the
+		// channel with the source.
+		asymmetricTestChannel("source", "a", 100000, noFeePolicy,
+			&testChannelPolicy{
+				Expiry:     144,
+				InboundFee: &lnwire.Fee{BaseFee: 1000},
+			}, 1,
+		),
+		symmetricTestChannel("source", "b", 100000, noFeePolicy, 2),
+		symmetricTestChannel("a", "target", 100000, &testChannelPolicy{
+			Expiry:      144,
+			FeeBaseMsat: 500,
+		}, 3),
+
+		// The target doesn't forward, so its inbound fee must not be
+		// taken into account.
+		asymmetricTestChannel("b", "target", 100000,
+			&testChannelPolicy{
+				Expiry:      144,
+				FeeBaseMsat: 1000,
+			},
+			&testChannelPolicy{
+				Expiry:     144,
+				InboundFee: &lnwire.Fee{BaseFee: 10000},
+			}, 4,
+		),
+	}
+
+	ctx := newPathFindingTestContext(t, testChannels, "source")
+	defer ctx.cleanup()
+
+	paymentAmt := lnwire.NewMSatFromSatoshis(100)
+	target := ctx.keyFromAlias("target")
+
+	// Node a has the lowest outbound fee, but including its inbound fee
+	// it is more expensive than node b.
+	path, err := ctx.findPath(target, paymentAmt)
+	if err != nil {
+		t.Fatalf("unable to find path: %v", err)
+	}
+	ctx.assertPath(path, []uint64{2, 4})
+	if path[0].inboundFee != (lnwire.Fee{}) {
+		t.Fatalf("unexpected inbound fee %v", path[0].inboundFee)
+	}
+
+	// Restricting the route to the channel with node a must yield a route
+	// that pays both the outbound and the inbound fee of node a.
+	ctx.restrictParams.OutgoingChannelIDs = []uint64{1}
+	path, err = ctx.findPath(target, paymentAmt)
+	if err != nil {
+		t.Fatalf("unable to find path: %v", err)
+	}
+	ctx.assertPath(path, []uint64{1, 3})
+
+	route, err := newRoute(
+		ctx.source, path, 0,
+		finalHopParams{
+			amt:       paymentAmt,
+			totalAmt:  paymentAmt,
+			cltvDelta: 1,
+		},
+	)
+	if err != nil {
+		t.Fatalf("unable to create route: %v", err)
+	}
+	if route.TotalFees() != 1500 {
+		t.Fatalf("expected total fee of 1500, got %v",
+			route.TotalFees())
+	}
+}
+
 type pathFindingTestContext struct {
 	t                 *testing.T
 	graph             *channeldb.ChannelGraph
@@ -2930,7 +3112,7 @@ func (c *pathFindingTestContext) cleanup() {
 }
 
 func (c *pathFindingTestContext) findPath(target route.Vertex,
-	amt lnwire.MilliSatoshi) ([]*channeldb.ChannelEdgePolicy,
+	amt lnwire.MilliSatoshi) ([]*unifiedPolicyEdge,
 	error) {
 
 	return dbFindPath(
@@ -2939,16 +3121,17 @@ func (c *pathFindingTestContext) findPath(target route.Vertex,
 	)
 }
 
-func (c *pathFindingTestContext) assertPath(path []*channeldb.ChannelEdgePolicy, expected []uint64) {
+func (c *pathFindingTestContext) assertPath(path []*unifiedPolicyEdge, expected []uint64) {
 	if len(path) != len(expected) {
 		c.t.Fatalf("expected path of length %v, but got %v",
 			len(expected), len(path))
 	}
 
 	for i, edge := range path {
-		if edge.ChannelID != expected[i] {
+		if edge.policy.ChannelID != expected[i] {
 			c.t.Fatalf("expected hop %v to be channel %v, "+
-				"but got %v", i, expected[i], edge.ChannelID)
+				"but got %v", i, expected[i],
+				edge.policy.ChannelID)
 		}
 	}
 }
@@ -2960,7 +3143,7 @@ func dbFindPath(graph *channeldb.ChannelGraph,
 	bandwidthHints map[uint64]lnwire.MilliSatoshi,
 	r *RestrictParams, cfg *PathFindingConfig,
 	source, target route.Vertex, amt lnwire.MilliSatoshi,
-	finalHtlcExpiry int32) ([]*channeldb.ChannelEdgePolicy, error) {
+	finalHtlcExpiry int32) ([]*unifiedPolicyEdge, error) {
 
 	routingTx, err := newDbRoutingTx(graph)
 	if err != nil {
@@ -2982,3 +3165,34 @@ func dbFindPath(graph *channeldb.ChannelGraph,
 		r, cfg, source, target, amt, finalHtlcExpiry,
 	)
 }
+
+// TestComputeAmtToForward tests that computeAmtToForward is the inverse of
+// computeForwardingFee.
+func TestComputeAmtToForward(t *testing.T) {
+	t.Parallel()
+
+	policy := &channeldb.ChannelEdgePolicy{
+		FeeBaseMSat:               1000,
+		FeeProportionalMillionths: 2000,
+	}
+
+	inboundFees := []lnwire.Fee{
+		{},
+		{BaseFee: 500, FeeRate: 100},
+		{BaseFee: -300, FeeRate: -1000},
+		{BaseFee: -5000},
+	}
+
+	for _, inboundFee := range inboundFees {
+		for _, amt := range []lnwire.MilliSatoshi{0, 1, 999, 123456} {
+			fee := computeForwardingFee(policy, inboundFee, amt)
+			fwdAmt := computeAmtToForward(
+				policy, inboundFee, amt+fee,
+			)
+			if fwdAmt != amt {
+				t.Fatalf("inbound fee %v: expected %v, got %v",
+					inboundFee, amt, fwdAmt)
+			}
+		}
+	}
+}
@@ -1,9 +1,10 @@
 package routing
 
 import (
+	"errors"
 	"fmt"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btclog"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/build"
@@ -197,6 +198,24 @@ func newPaymentSession(p *LightningPayment,
 	missionControl MissionController, pathFindingConfig PathFindingConfig) (
 	*paymentSession, error) {
 
+	// The trampoline node of a payment needs to be the recipient of the
+	// route we find, so it can't be combined with a blinded path. The
+	// trampoline onion also doesn't carry custom records or AMP records
+	// to the recipient.
+	switch {
+	case p.Trampoline != nil && p.BlindedPayment != nil:
+		return nil, errors.New("trampoline payments to blinded paths " +
+			"are not supported")
+
+	case p.Trampoline != nil && len(p.DestCustomRecords) > 0:
+		return nil, errors.New("trampoline payments with custom " +
+			"records are not supported")
+
+	case p.Trampoline != nil && p.amp != nil:
+		return nil, errors.New("trampoline AMP payments are not " +
+			"supported")
+	}
+
 	edges, err := RouteHintsToEdges(p.RouteHints, p.Target)
 	if err != nil {
 		return nil, err
@@ -259,7 +278,67 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 		PaymentAddr:        p.payment.PaymentAddr,
 	}
 
+	// A trampoline payment is routed to the trampoline node, which needs
+	// to receive its fee and time-lock delta on top of the payment. The
+	// details of the recipient are only revealed to the trampoline node,
+	// through the trampoline onion.
+	target := p.payment.Target
+	additionalEdges := p.additionalEdges
+	trampoline := p.payment.Trampoline
+	if trampoline != nil {
+		if trampoline.Fee > feeLimit {
+			p.log.Debugf("Trampoline fee %v exceeds fee limit %v",
+				trampoline.Fee, feeLimit)
+			return nil, errNoPathFound
+		}
+		restrictions.FeeLimit -= trampoline.Fee
+
+		if uint32(trampoline.CltvDelta) > restrictions.CltvLimit {
+			p.log.Debugf("Trampoline cltv delta %v exceeds cltv "+
+				"limit %v", trampoline.CltvDelta,
+				restrictions.CltvLimit)
+			return nil, errNoPathFound
+		}
+		restrictions.CltvLimit -= uint32(trampoline.CltvDelta)
+
+		restrictions.LastHop = nil
+		restrictions.DestCustomRecords = nil
+		restrictions.DestFeatures = nil
+		restrictions.PaymentAddr = nil
+
+		target = trampoline.Node
+		additionalEdges = nil
+	}
+
 	finalHtlcExpiry := int32(height) + int32(finalCltvDelta)
+	if trampoline != nil {
+		finalHtlcExpiry += int32(trampoline.CltvDelta)
+	}
+
+	// A payment to a blinded path is routed to its introduction point,
+	// which needs to receive enough to cover the fees of the path. Those
+	// fees are deducted from the fee limit of the route to it.
+	blindedPayment := p.payment.BlindedPayment
+	if blindedPayment != nil {
+		err := checkBlindedPathAmount(blindedPayment, maxAmt)
+		if err != nil {
+			p.log.Debugf("Unable to pay blinded path: %v", err)
+			return nil, errNoPathFound
+		}
+
+		blindedFee := blindedPathFee(blindedPayment, maxAmt)
+		if blindedFee > feeLimit {
+			p.log.Debugf("Blinded path fee %v exceeds fee limit "+
+				"%v", blindedFee, feeLimit)
+			return nil, errNoPathFound
+		}
+		restrictions.FeeLimit -= blindedFee
+
+		// The recipient identifies the payment through the path ID
+		// within its encrypted data, so the introduction point doesn't
+		// need to understand payment addresses.
+		restrictions.PaymentAddr = nil
+	}
 
 	// Before we enter the loop below, we'll make sure to respect the max
 	// payment shard size (if it's set), which is effectively our
@@ -295,16 +374,26 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 
 		sourceVertex := routingGraph.sourceNode()
 
+		// The amount we route to the target includes the fees of the
+		// blinded path, if there is any.
+		routeAmt := maxAmt
+		if blindedPayment != nil {
+			routeAmt += blindedPathFee(blindedPayment, maxAmt)
+		}
+		if trampoline != nil {
+			routeAmt += trampoline.Fee
+		}
+
 		// Find a route for the current amount.
 		path, err := p.pathFinder(
 			&graphParams{
-				additionalEdges: p.additionalEdges,
+				additionalEdges: additionalEdges,
 				bandwidthHints:  bandwidthHints,
 				graph:           routingGraph,
 			},
 			restrictions, &p.pathFindingConfig,
-			sourceVertex, p.payment.Target,
-			maxAmt, finalHtlcExpiry,
+			sourceVertex, target,
+			routeAmt, finalHtlcExpiry,
 		)
 
 		// Close routing graph.
@@ -313,8 +402,11 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 		switch {
 		case err == errNoPathFound:
 			// Don't split if this is a legacy payment without mpp
-			// record.
-			if p.payment.PaymentAddr == nil {
+			// record, a payment to a blinded path or a trampoline
+			// payment.
+			if p.payment.PaymentAddr == nil ||
+				blindedPayment != nil || trampoline != nil {
+
 				p.log.Debugf("not splitting because payment " +
 					"address is unspecified")
 
@@ -380,6 +472,20 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 		// With the next candidate path found, we'll attempt to turn
 		// this into a route by applying the time-lock and fee
 		// requirements.
+		if trampoline != nil {
+			return p.newTrampolineRoute(
+				sourceVertex, path, height, maxAmt,
+				finalCltvDelta,
+			)
+		}
+
+		if blindedPayment != nil {
+			return p.newBlindedRoute(
+				sourceVertex, path, height, maxAmt,
+				finalCltvDelta,
+			)
+		}
+
 		route, err := newRoute(
 			sourceVertex, path, height,
 			finalHopParams{
@@ -398,6 +504,43 @@ func (p *paymentSession) RequestRoute(maxAmt, feeLimit lnwire.MilliSatoshi,
 	}
 }
 
+// newBlindedRoute turns a path to the introduction point of the blinded
+// payment path of the payment into a route to its recipient.
+func (p *paymentSession) newBlindedRoute(sourceVertex route.Vertex,
+	path []*unifiedPolicyEdge, height uint32, amt lnwire.MilliSatoshi,
+	finalCltvDelta uint16) (*route.Route, error) {
+
+	blindedPayment := p.payment.BlindedPayment
+	routeAmt := amt + blindedPathFee(blindedPayment, amt)
+
+	rt, err := newRoute(
+		sourceVertex, path, height,
+		finalHopParams{
+			amt:       routeAmt,
+			totalAmt:  routeAmt,
+			cltvDelta: finalCltvDelta,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// The recipient only needs to accept HTLCs that expire after the
+	// padded current height, the remaining time-lock delta of the path
+	// is consumed by its nodes.
+	finalExpiry := height + uint32(BlockPadding)
+	err = addBlindedHops(rt, blindedPayment, amt, finalExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	// Custom records are destined for the recipient rather than the
+	// introduction point.
+	rt.Hops[len(rt.Hops)-1].CustomRecords = p.payment.DestCustomRecords
+
+	return rt, nil
+}
+
 // UpdateAdditionalEdge updates the channel edge policy for a private edge. It
 // validates the message signature and checks it's up to date, then applies the
 // updates to the supplied policy. It returns a boolean to indicate whether
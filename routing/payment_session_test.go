@@ -4,9 +4,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lntypes"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
 	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/zpay32"
 	"github.com/stretchr/testify/require"
@@ -217,7 +219,7 @@ func TestRequestRoute(t *testing.T) {
 	session.pathFinder = func(
 		g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
 		source, target route.Vertex, amt lnwire.MilliSatoshi,
-		finalHtlcExpiry int32) ([]*channeldb.ChannelEdgePolicy, error) {
+		finalHtlcExpiry int32) ([]*unifiedPolicyEdge, error) {
 
 		// We expect find path to receive a cltv limit excluding the
 		// final cltv delta (including the block padding).
@@ -225,12 +227,14 @@ func TestRequestRoute(t *testing.T) {
 			t.Fatal("wrong cltv limit")
 		}
 
-		path := []*channeldb.ChannelEdgePolicy{
+		path := []*unifiedPolicyEdge{
 			{
-				Node: &channeldb.LightningNode{
-					Features: lnwire.NewFeatureVector(
-						nil, nil,
-					),
+				policy: &channeldb.ChannelEdgePolicy{
+					Node: &channeldb.LightningNode{
+						Features: lnwire.NewFeatureVector(
+							nil, nil,
+						),
+					},
 				},
 			},
 		}
@@ -253,6 +257,113 @@ func TestRequestRoute(t *testing.T) {
 	}
 }
 
+// TestRequestBlindedRoute tests that a payment to a blinded path is routed to
+// the introduction point of the path, and that the blinded hops are appended
+// to the route.
+func TestRequestBlindedRoute(t *testing.T) {
+	const (
+		height = 10
+		amt    = lnwire.MilliSatoshi(100_000)
+	)
+
+	blindedKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	blindedPayment := &zpay32.BlindedPaymentPath{
+		FeeBaseMsat:     1000,
+		FeeRate:         1000,
+		CltvExpiryDelta: 100,
+		HTLCMaxMsat:     uint64(amt),
+		Path: &blindedpath.BlindedPath{
+			IntroductionPoint: blindedKey.PubKey(),
+			BlindingPoint:     blindedKey.PubKey(),
+			BlindedHops: []*blindedpath.BlindedHop{{
+				BlindedNodePub: blindedKey.PubKey(),
+				CipherText:     []byte{1},
+			}, {
+				BlindedNodePub: blindedKey.PubKey(),
+				CipherText:     []byte{2},
+			}},
+		},
+	}
+
+	payment := &LightningPayment{
+		CltvLimit:      1000,
+		FinalCLTVDelta: blindedPayment.CltvExpiryDelta,
+		Amount:         amt,
+		FeeLimit:       2000,
+		BlindedPayment: blindedPayment,
+	}
+
+	var paymentHash [32]byte
+	require.NoError(t, payment.SetPaymentHash(paymentHash))
+
+	session, err := newPaymentSession(
+		payment,
+		func() (map[uint64]lnwire.MilliSatoshi,
+			error) {
+
+			return nil, nil
+		},
+		func() (routingGraph, func(), error) {
+			return &sessionGraph{}, func() {}, nil
+		},
+		&MissionControl{},
+		PathFindingConfig{},
+	)
+	require.NoError(t, err)
+
+	// The path to the introduction point must carry the fee of the
+	// blinded path, which is deducted from the fee limit.
+	session.pathFinder = func(
+		g *graphParams, r *RestrictParams, cfg *PathFindingConfig,
+		source, target route.Vertex, pathAmt lnwire.MilliSatoshi,
+		finalHtlcExpiry int32) ([]*unifiedPolicyEdge, error) {
+
+		require.Equal(t, amt+1100, pathAmt)
+		require.Equal(t, lnwire.MilliSatoshi(900), r.FeeLimit)
+		require.Nil(t, r.PaymentAddr)
+
+		path := []*unifiedPolicyEdge{
+			{
+				policy: &channeldb.ChannelEdgePolicy{
+					Node: &channeldb.LightningNode{
+						Features: lnwire.NewFeatureVector(
+							nil, nil,
+						),
+					},
+				},
+			},
+		}
+
+		return path, nil
+	}
+
+	rt, err := session.RequestRoute(
+		payment.Amount, payment.FeeLimit, 0, height,
+	)
+	require.NoError(t, err)
+
+	require.Len(t, rt.Hops, 2)
+	require.Equal(t, amt+1100, rt.TotalAmount)
+	require.Equal(t, amt, rt.ReceiverAmt())
+	require.EqualValues(t, height+100+BlockPadding, rt.TotalTimeLock)
+
+	introHop, finalHop := rt.Hops[0], rt.Hops[1]
+	require.Equal(t, []byte{1}, introHop.EncryptedData)
+	require.NotNil(t, introHop.BlindingPoint)
+	require.Zero(t, introHop.TotalAmtMsat)
+	require.Equal(t, []byte{2}, finalHop.EncryptedData)
+	require.Equal(t, amt, finalHop.TotalAmtMsat)
+	require.EqualValues(t, height+BlockPadding, finalHop.OutgoingTimeLock)
+
+	// An amount that exceeds the maximum of the path can't be sent.
+	_, err = session.RequestRoute(
+		payment.Amount+1, payment.FeeLimit, 0, height,
+	)
+	require.Equal(t, errNoPathFound, err)
+}
+
 type sessionGraph struct {
 	routingGraph
 }
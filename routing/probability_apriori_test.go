@@ -27,7 +27,7 @@ const (
 
 type estimatorTestContext struct {
 	t         *testing.T
-	estimator *probabilityEstimator
+	estimator *AprioriEstimator
 
 	// results contains a list of last results. Every element in the list
 	// corresponds to the last result towards a node. The list index equals
@@ -39,8 +39,8 @@ type estimatorTestContext struct {
 func newEstimatorTestContext(t *testing.T) *estimatorTestContext {
 	return &estimatorTestContext{
 		t: t,
-		estimator: &probabilityEstimator{
-			ProbabilityEstimatorCfg: ProbabilityEstimatorCfg{
+		estimator: &AprioriEstimator{
+			AprioriConfig: AprioriConfig{
 				AprioriHopProbability: aprioriHopProb,
 				AprioriWeight:         aprioriWeight,
 				PenaltyHalfLife:       time.Hour,
@@ -64,7 +64,9 @@ func (c *estimatorTestContext) assertPairProbability(now time.Time,
 
 	const tolerance = 0.01
 
-	p := c.estimator.getPairProbability(now, results, route.Vertex{toNode}, amt)
+	p := c.estimator.PairProbability(
+		now, results, route.Vertex{toNode}, amt, 0,
+	)
 	diff := p - expectedProb
 	if diff > tolerance || diff < -tolerance {
 		c.t.Fatalf("expected probability %v for node %v, but got %v",
@@ -0,0 +1,362 @@
+package routing
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+const (
+	// DefaultBimodalScaleMsat is the default value for BimodalScaleMsat.
+	// It describes the typical amount of liquidity that is left on either
+	// side of a channel.
+	DefaultBimodalScaleMsat = lnwire.MilliSatoshi(300_000_000)
+
+	// DefaultBimodalNodeWeight is the default value for
+	// BimodalNodeWeight.
+	DefaultBimodalNodeWeight = 0.2
+
+	// DefaultBimodalDecayTime is the default value for BimodalDecayTime.
+	DefaultBimodalDecayTime = 7 * 24 * time.Hour
+)
+
+var (
+	// ErrInvalidScale is returned when we get a scale below or equal
+	// zero.
+	ErrInvalidScale = errors.New("scale must be > 0")
+
+	// ErrInvalidNodeWeight is returned when we get a node weight that is
+	// out of range.
+	ErrInvalidNodeWeight = errors.New("node weight must be in [0, 1]")
+
+	// ErrInvalidDecayTime is returned when we get a decay time below or
+	// equal zero.
+	ErrInvalidDecayTime = errors.New("decay time must be > 0")
+)
+
+// BimodalConfig contains configuration for the bimodal probability estimator.
+type BimodalConfig struct {
+	// BimodalNodeWeight defines how strongly the results of the other
+	// channels of a node are taken into account when estimating the
+	// probability of a channel of that node. The allowed values are in
+	// the range [0, 1], where a value of zero only takes the results of
+	// the channel itself into account.
+	BimodalNodeWeight float64
+
+	// BimodalScaleMsat describes the scale over which channels typically
+	// have some liquidity left on their sides. It determines how quickly
+	// the distribution of the liquidity drops off from the edges of a
+	// channel. A value that is large compared to the capacity of a channel
+	// results in a uniform distribution of its liquidity.
+	BimodalScaleMsat lnwire.MilliSatoshi
+
+	// BimodalDecayTime is the time after which the knowledge about a
+	// previous success or failure has decayed by a factor of e.
+	BimodalDecayTime time.Duration
+}
+
+// validate checks the configuration of the estimator for allowed values.
+func (p BimodalConfig) validate() error {
+	if p.BimodalDecayTime <= 0 {
+		return ErrInvalidDecayTime
+	}
+
+	if p.BimodalNodeWeight < 0 || p.BimodalNodeWeight > 1 {
+		return ErrInvalidNodeWeight
+	}
+
+	if p.BimodalScaleMsat == 0 {
+		return ErrInvalidScale
+	}
+
+	return nil
+}
+
+// DefaultBimodalConfig returns the default configuration for the bimodal
+// estimator.
+func DefaultBimodalConfig() BimodalConfig {
+	return BimodalConfig{
+		BimodalNodeWeight: DefaultBimodalNodeWeight,
+		BimodalScaleMsat:  DefaultBimodalScaleMsat,
+		BimodalDecayTime:  DefaultBimodalDecayTime,
+	}
+}
+
+// BimodalEstimator returns node and pair probabilities based on historical
+// payment results. It models the liquidity of a channel as bimodally
+// distributed, meaning that most of the liquidity of a channel is usually on
+// one of its sides. Successes and failures of previous payment attempts
+// restrict the range of the liquidity, so that a failure of a small amount
+// doesn't rule out a large channel for smaller amounts.
+type BimodalEstimator struct {
+	// BimodalConfig contains configuration options for our estimator.
+	BimodalConfig
+}
+
+// A compile time check to ensure BimodalEstimator implements the
+// ProbabilityEstimator interface.
+var _ ProbabilityEstimator = (*BimodalEstimator)(nil)
+
+// NewBimodalEstimator creates a new BimodalEstimator.
+func NewBimodalEstimator(cfg BimodalConfig) (*BimodalEstimator, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	return &BimodalEstimator{
+		BimodalConfig: cfg,
+	}, nil
+}
+
+// Name returns the name of the estimator.
+//
+// NOTE: This is part of the ProbabilityEstimator interface.
+func (p *BimodalEstimator) Name() string {
+	return BimodalEstimatorName
+}
+
+// String returns the estimator's configuration as a string representation.
+//
+// NOTE: This is part of the ProbabilityEstimator interface.
+func (p *BimodalEstimator) String() string {
+	return fmt.Sprintf("estimator type: %v, decay time: %v, liquidity "+
+		"scale: %v, node weight: %v", BimodalEstimatorName,
+		p.BimodalDecayTime, p.BimodalScaleMsat, p.BimodalNodeWeight)
+}
+
+// PairProbability estimates the probability of successfully traversing to
+// toNode based on historical payment outcomes for the from node. Those outcomes
+// are passed in via the results parameter.
+//
+// NOTE: This is part of the ProbabilityEstimator interface.
+func (p *BimodalEstimator) PairProbability(now time.Time,
+	results NodeResults, toNode route.Vertex, amt lnwire.MilliSatoshi,
+	capacity btcutil.Amount) float64 {
+
+	directProbability := p.directProbability(
+		now, results, toNode, amt, lnwire.NewMSatFromSatoshis(capacity),
+	)
+
+	return p.calculateProbability(directProbability, now, results, toNode)
+}
+
+// LocalPairProbability estimates the probability of successfully traversing
+// our own local channels to toNode.
+//
+// NOTE: This is part of the ProbabilityEstimator interface.
+func (p *BimodalEstimator) LocalPairProbability(now time.Time,
+	results NodeResults, toNode route.Vertex) float64 {
+
+	// We know the balances of our own channels, so we assume that the
+	// channels we select are able to forward the amount.
+	directProbability := 1.0
+
+	// If a local channel failed nevertheless, we reduce its probability
+	// for some time to avoid retrying it over and over again.
+	result, ok := results[toNode]
+	if ok && !result.FailTime.IsZero() {
+		directProbability -= p.decayFactor(now, result.FailTime)
+	}
+
+	return directProbability
+}
+
+// calculateProbability mixes the probability of the channel to toNode with the
+// results of the other channels of the node. A node that recently forwarded
+// payments over its other channels is likely to be online and well-managed,
+// while failures of its other channels hint at the opposite.
+func (p *BimodalEstimator) calculateProbability(directProbability float64,
+	now time.Time, results NodeResults, toNode route.Vertex) float64 {
+
+	// If we don't take other channels into account, we can return early.
+	if p.BimodalNodeWeight == 0 {
+		return directProbability
+	}
+
+	// If we have fresh information about the channel itself, we only use
+	// that. Otherwise the results of the other channels of the node would
+	// pin the probability, even though we know better.
+	if result, ok := results[toNode]; ok {
+		lastResult := result.FailTime
+		if result.SuccessTime.After(lastResult) {
+			lastResult = result.SuccessTime
+		}
+
+		if now.Sub(lastResult) < p.BimodalDecayTime {
+			return directProbability
+		}
+	}
+
+	// The direct probability is weighted fully, while the results of the
+	// other channels are weighted by the node weight and their age.
+	// Successes count as a probability of one, failures as zero.
+	totalProbability := directProbability
+	totalWeight := 1.0
+	for peer, result := range results {
+		if peer == toNode {
+			continue
+		}
+
+		if !result.SuccessTime.IsZero() {
+			weight := p.BimodalNodeWeight * p.decayFactor(
+				now, result.SuccessTime,
+			)
+			totalProbability += weight
+			totalWeight += weight
+		}
+
+		if !result.FailTime.IsZero() {
+			totalWeight += p.BimodalNodeWeight * p.decayFactor(
+				now, result.FailTime,
+			)
+		}
+	}
+
+	return totalProbability / totalWeight
+}
+
+// directProbability estimates the probability of forwarding the amount over
+// the channel to toNode, only based on the results of that channel.
+func (p *BimodalEstimator) directProbability(now time.Time,
+	results NodeResults, toNode route.Vertex, amt,
+	capacity lnwire.MilliSatoshi) float64 {
+
+	result, ok := results[toNode]
+
+	// Without the capacity of the channel, we can't model its liquidity.
+	// We then fall back to the results of the channel itself, and the a
+	// priori probability for amounts we don't know anything about.
+	if capacity == 0 {
+		switch {
+		case ok && amt <= p.successAmount(result, now):
+			return 1
+
+		case ok && !result.FailTime.IsZero() &&
+			amt >= result.FailAmt:
+
+			return DefaultAprioriHopProbability *
+				(1 - p.decayFactor(now, result.FailTime))
+
+		default:
+			return DefaultAprioriHopProbability
+		}
+	}
+
+	// Without any results, the liquidity may lie anywhere within the
+	// capacity of the channel.
+	successAmt := lnwire.MilliSatoshi(0)
+	failAmt := capacity
+	if ok {
+		successAmt = p.successAmount(result, now)
+		failAmt = p.failAmount(result, capacity, now)
+	}
+
+	return p.probabilityFormula(capacity, successAmt, failAmt, amt)
+}
+
+// successAmount returns the amount we know the channel is able to forward.
+// As the liquidity of the channel may have shifted since the success, the
+// amount decays towards zero over time.
+func (p *BimodalEstimator) successAmount(result TimedPairResult,
+	now time.Time) lnwire.MilliSatoshi {
+
+	if result.SuccessTime.IsZero() {
+		return 0
+	}
+
+	decay := p.decayFactor(now, result.SuccessTime)
+
+	return lnwire.MilliSatoshi(float64(result.SuccessAmt) * decay)
+}
+
+// failAmount returns the amount we know the channel isn't able to forward. As
+// the liquidity of the channel may have shifted since the failure, the amount
+// relaxes towards the capacity of the channel over time.
+func (p *BimodalEstimator) failAmount(result TimedPairResult,
+	capacity lnwire.MilliSatoshi, now time.Time) lnwire.MilliSatoshi {
+
+	if result.FailTime.IsZero() || result.FailAmt >= capacity {
+		return capacity
+	}
+
+	decay := p.decayFactor(now, result.FailTime)
+	unknown := float64(capacity-result.FailAmt) * (1 - decay)
+
+	return result.FailAmt + lnwire.MilliSatoshi(unknown)
+}
+
+// decayFactor returns a factor in the range [0, 1] that describes how much of
+// the knowledge gained at the given time is still relevant. It starts at one
+// and decays exponentially with the configured decay time.
+func (p *BimodalEstimator) decayFactor(now, t time.Time) float64 {
+	age := now.Sub(t)
+	if age < 0 {
+		age = 0
+	}
+
+	return math.Exp(-float64(age) / float64(p.BimodalDecayTime))
+}
+
+// probabilityFormula computes the probability of forwarding the amount over a
+// channel of the given capacity, knowing that the channel was able to forward
+// the success amount, but not the fail amount.
+//
+// The liquidity x of the channel is modeled with the unnormalized density
+//
+//	P(x) = exp(-x/s) + exp((x-c)/s) + 1/c,
+//
+// with the scale s and the capacity c. The exponential terms describe that
+// most of the liquidity is usually on one side of a channel, while the small
+// uniform term avoids vanishing probabilities in the middle of large
+// channels. The success probability is the probability mass of the liquidity
+// above the amount, restricted to the range between the success and fail
+// amounts.
+func (p *BimodalEstimator) probabilityFormula(capacity, successAmt, failAmt,
+	amt lnwire.MilliSatoshi) float64 {
+
+	// Results of a multi-channel node pair may exceed the capacity of the
+	// selected channel, so we restrict them to the capacity.
+	if failAmt > capacity {
+		failAmt = capacity
+	}
+	if successAmt > capacity {
+		successAmt = capacity
+	}
+
+	// If the results contradict each other, we don't know anything about
+	// the liquidity of the channel.
+	if successAmt >= failAmt {
+		successAmt = 0
+		failAmt = capacity
+	}
+
+	switch {
+	case amt <= successAmt:
+		return 1
+
+	case amt >= failAmt:
+		return 0
+	}
+
+	c := float64(capacity)
+	s := float64(p.BimodalScaleMsat)
+
+	// primitive is the antiderivative of the liquidity density.
+	primitive := func(x float64) float64 {
+		return -s*math.Exp(-x/s) + s*math.Exp((x-c)/s) + x/c
+	}
+
+	total := primitive(float64(failAmt)) - primitive(float64(successAmt))
+	if total <= 0 {
+		return 0
+	}
+
+	prob := (primitive(float64(failAmt)) - primitive(float64(amt))) / total
+
+	return math.Max(0, math.Min(1, prob))
+}
@@ -0,0 +1,294 @@
+package routing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// bimodalTestCapacity is the capacity of the channels used in the
+	// bimodal estimator tests.
+	bimodalTestCapacity = btcutil.Amount(10_000_000)
+
+	// bimodalTestCapMsat is the capacity of the test channels in msat.
+	bimodalTestCapMsat = lnwire.MilliSatoshi(10_000_000_000)
+
+	// bimodalTestTolerance is the tolerance of the probability
+	// comparisons.
+	bimodalTestTolerance = 0.001
+)
+
+func newTestBimodalEstimator(t *testing.T,
+	nodeWeight float64) *BimodalEstimator {
+
+	estimator, err := NewBimodalEstimator(BimodalConfig{
+		BimodalNodeWeight: nodeWeight,
+		BimodalScaleMsat:  DefaultBimodalScaleMsat,
+		BimodalDecayTime:  DefaultBimodalDecayTime,
+	})
+	require.NoError(t, err)
+
+	return estimator
+}
+
+// TestBimodalConfigValidation tests that invalid configurations of the
+// bimodal estimator are rejected.
+func TestBimodalConfigValidation(t *testing.T) {
+	t.Parallel()
+
+	cfg := DefaultBimodalConfig()
+	cfg.BimodalScaleMsat = 0
+	_, err := NewBimodalEstimator(cfg)
+	require.ErrorIs(t, err, ErrInvalidScale)
+
+	cfg = DefaultBimodalConfig()
+	cfg.BimodalNodeWeight = 1.5
+	_, err = NewBimodalEstimator(cfg)
+	require.ErrorIs(t, err, ErrInvalidNodeWeight)
+
+	cfg = DefaultBimodalConfig()
+	cfg.BimodalDecayTime = 0
+	_, err = NewBimodalEstimator(cfg)
+	require.ErrorIs(t, err, ErrInvalidDecayTime)
+}
+
+// TestBimodalProbabilityFormula tests the probability of forwarding amounts
+// over a channel with the bimodal liquidity distribution.
+func TestBimodalProbabilityFormula(t *testing.T) {
+	t.Parallel()
+
+	estimator := newTestBimodalEstimator(t, 0)
+	capacity := bimodalTestCapMsat
+
+	tests := []struct {
+		name       string
+		successAmt lnwire.MilliSatoshi
+		failAmt    lnwire.MilliSatoshi
+		amt        lnwire.MilliSatoshi
+		expected   float64
+	}{
+		{
+			name:     "no info, zero amount",
+			failAmt:  capacity,
+			amt:      0,
+			expected: 1,
+		},
+		{
+			name:     "no info, half of the capacity",
+			failAmt:  capacity,
+			amt:      capacity / 2,
+			expected: 0.5,
+		},
+		{
+			name:     "no info, full capacity",
+			failAmt:  capacity,
+			amt:      capacity,
+			expected: 0,
+		},
+		{
+			// Most of the liquidity is near the edges, so a small
+			// amount is very likely to go through.
+			name:     "no info, small amount",
+			failAmt:  capacity,
+			amt:      capacity / 100,
+			expected: 0.858,
+		},
+		{
+			name:       "below success amount",
+			successAmt: capacity / 4,
+			failAmt:    capacity,
+			amt:        capacity / 4,
+			expected:   1,
+		},
+		{
+			name:     "above fail amount",
+			failAmt:  capacity / 4,
+			amt:      capacity / 4,
+			expected: 0,
+		},
+		{
+			// The liquidity is known to be in the middle of the
+			// channel, where it is distributed uniformly.
+			name:       "between success and fail amount",
+			successAmt: capacity / 4,
+			failAmt:    capacity * 3 / 4,
+			amt:        capacity / 2,
+			expected:   0.5,
+		},
+		{
+			// A failure of a small amount leaves the liquidity on
+			// the near edge, so a smaller amount is still likely
+			// to succeed.
+			name:     "below small failure",
+			failAmt:  capacity / 10,
+			amt:      capacity / 100,
+			expected: 0.706,
+		},
+		{
+			name:       "contradicting results",
+			successAmt: capacity / 2,
+			failAmt:    capacity / 4,
+			amt:        capacity / 2,
+			expected:   0.5,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.name, func(t *testing.T) {
+			p := estimator.probabilityFormula(
+				capacity, test.successAmt, test.failAmt,
+				test.amt,
+			)
+			require.InDelta(
+				t, test.expected, p, bimodalTestTolerance,
+			)
+		})
+	}
+}
+
+// TestBimodalPairProbability tests the estimation of pair probabilities based
+// on previous payment results.
+func TestBimodalPairProbability(t *testing.T) {
+	t.Parallel()
+
+	var (
+		now        = testTime
+		toNode     = route.Vertex{node1}
+		otherNode  = route.Vertex{node2}
+		smallAmt   = bimodalTestCapMsat / 100
+		failAmt    = bimodalTestCapMsat / 10
+		decayTime  = DefaultBimodalDecayTime
+		estimator  = newTestBimodalEstimator(t, 0)
+		noResults  = NodeResults{}
+		untriedAmt = bimodalTestCapMsat / 2
+	)
+
+	// Without any results, the probability only depends on the amount.
+	p := estimator.PairProbability(
+		now, noResults, toNode, untriedAmt, bimodalTestCapacity,
+	)
+	require.InDelta(t, 0.5, p, bimodalTestTolerance)
+
+	// A fresh failure of an amount rules out that amount, but smaller
+	// amounts are still likely to succeed.
+	results := NodeResults{
+		toNode: {
+			FailTime: now,
+			FailAmt:  failAmt,
+		},
+	}
+	p = estimator.PairProbability(
+		now, results, toNode, failAmt, bimodalTestCapacity,
+	)
+	require.Zero(t, p)
+
+	p = estimator.PairProbability(
+		now, results, toNode, smallAmt, bimodalTestCapacity,
+	)
+	require.Greater(t, p, 0.5)
+
+	// Over time, the failure amount relaxes towards the capacity, so the
+	// failed amount gets a chance again.
+	relaxedP := estimator.PairProbability(
+		now.Add(decayTime), results, toNode, failAmt,
+		bimodalTestCapacity,
+	)
+	require.Greater(t, relaxedP, 0.0)
+
+	p = estimator.PairProbability(
+		now.Add(3*decayTime), results, toNode, failAmt,
+		bimodalTestCapacity,
+	)
+	require.Greater(t, p, relaxedP)
+
+	// A success guarantees that the amount can be sent again, until the
+	// success decays.
+	results = NodeResults{
+		toNode: {
+			SuccessTime: now,
+			SuccessAmt:  untriedAmt,
+		},
+	}
+	p = estimator.PairProbability(
+		now, results, toNode, untriedAmt, bimodalTestCapacity,
+	)
+	require.Equal(t, 1.0, p)
+
+	p = estimator.PairProbability(
+		now.Add(decayTime), results, toNode, untriedAmt,
+		bimodalTestCapacity,
+	)
+	require.Less(t, p, 1.0)
+
+	// Without the capacity, we fall back to the a priori probability
+	// for amounts we don't know anything about.
+	p = estimator.PairProbability(now, noResults, toNode, untriedAmt, 0)
+	require.Equal(t, DefaultAprioriHopProbability, p)
+
+	// With a node weight, the results of the other channels of the node
+	// are taken into account for channels without fresh results.
+	estimator = newTestBimodalEstimator(t, 1)
+	results = NodeResults{
+		otherNode: {
+			SuccessTime: now,
+			SuccessAmt:  smallAmt,
+		},
+	}
+	p = estimator.PairProbability(
+		now, results, toNode, untriedAmt, bimodalTestCapacity,
+	)
+	require.InDelta(t, 0.75, p, bimodalTestTolerance)
+
+	results = NodeResults{
+		otherNode: {
+			FailTime: now,
+			FailAmt:  smallAmt,
+		},
+	}
+	p = estimator.PairProbability(
+		now, results, toNode, untriedAmt, bimodalTestCapacity,
+	)
+	require.InDelta(t, 0.25, p, bimodalTestTolerance)
+
+	// Fresh results of the channel itself take precedence.
+	results[toNode] = TimedPairResult{
+		SuccessTime: now,
+		SuccessAmt:  untriedAmt,
+	}
+	p = estimator.PairProbability(
+		now, results, toNode, untriedAmt, bimodalTestCapacity,
+	)
+	require.Equal(t, 1.0, p)
+}
+
+// TestBimodalLocalPairProbability tests that local channels are assumed to
+// work, unless they recently failed.
+func TestBimodalLocalPairProbability(t *testing.T) {
+	t.Parallel()
+
+	estimator := newTestBimodalEstimator(t, DefaultBimodalNodeWeight)
+	toNode := route.Vertex{node1}
+
+	p := estimator.LocalPairProbability(testTime, NodeResults{}, toNode)
+	require.Equal(t, 1.0, p)
+
+	results := NodeResults{
+		toNode: {
+			FailTime: testTime,
+		},
+	}
+	p = estimator.LocalPairProbability(testTime, results, toNode)
+	require.Zero(t, p)
+
+	p = estimator.LocalPairProbability(
+		testTime.Add(time.Hour), results, toNode,
+	)
+	require.Greater(t, p, 0.0)
+}
@@ -12,6 +12,7 @@ import (
 var (
 	reasonError            = channeldb.FailureReasonError
 	reasonIncorrectDetails = channeldb.FailureReasonPaymentDetails
+	reasonNoRoute          = channeldb.FailureReasonNoRoute
 )
 
 // pairResult contains the result of the interpretation of a payment attempt for
@@ -219,6 +220,17 @@ func (i *interpretedResult) processPaymentOutcomeFinal(
 		// destination correctly. Continue the payment process.
 		i.successPairRange(route, 0, n-1)
 
+	// The trampoline node at the end of the route was unable to forward
+	// the payment with the fee or time-lock delta we left for it. The
+	// route to it worked, but retrying with the same trampoline
+	// parameters won't succeed, so we fail the payment.
+	case *lnwire.FailTrampolineFeeInsufficient,
+		*lnwire.FailTrampolineExpiryTooSoon:
+
+		i.successPairRange(route, 0, n-1)
+
+		i.finalFailureReason = &reasonNoRoute
+
 	default:
 		// All other errors are considered terminal if coming from the
 		// final hop. They indicate that something is wrong at the
@@ -10,7 +10,7 @@ import (
 	"strconv"
 	"strings"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	sphinx "github.com/lightningnetwork/lightning-onion"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/record"
@@ -38,6 +38,12 @@ var (
 	// ErrAMPMissingMPP is returned when the caller tries to attach an AMP
 	// record but no MPP record is presented for the final hop.
 	ErrAMPMissingMPP = errors.New("cannot send AMP without MPP record")
+
+	// ErrIntermediateTrampolineHop is returned when a hop tries to deliver
+	// a trampoline onion to an intermediate hop, only final hops can
+	// receive trampoline onions.
+	ErrIntermediateTrampolineHop = errors.New("cannot send trampoline " +
+		"onion to intermediate")
 )
 
 // Vertex is a simple alias for the serialization of a compressed Bitcoin
@@ -127,6 +133,37 @@ type Hop struct {
 	// understand the new TLV payload, so we must instead use the legacy
 	// payload.
 	LegacyPayload bool
+
+	// EncryptedData is the data the recipient of a blinded route
+	// encrypted for this hop. It is only set for the hops of a blinded
+	// route, which derive their forwarding instructions from it.
+	EncryptedData []byte
+
+	// BlindingPoint is the ephemeral key the introduction node of a
+	// blinded route needs to decrypt its data. It is only set for the
+	// introduction node.
+	BlindingPoint *btcec.PublicKey
+
+	// TotalAmtMsat is the total amount of a payment to a blinded route.
+	// It is only set for the final hop of a blinded route.
+	TotalAmtMsat lnwire.MilliSatoshi
+
+	// TrampolineOnion is the trampoline onion that is handed to the final
+	// hop of a route to a trampoline node, which finds the remaining route
+	// to the recipient of the payment.
+	TrampolineOnion []byte
+}
+
+// isBlinded returns true if the hop is part of a blinded route.
+func (h *Hop) isBlinded() bool {
+	return h.EncryptedData != nil
+}
+
+// isBlindedIntermediate returns true if the hop is part of a blinded route,
+// but not its final hop. The forwarding instructions of such a hop are
+// entirely contained within its encrypted data.
+func (h *Hop) isBlindedIntermediate() bool {
+	return h.isBlinded() && h.TotalAmtMsat == 0
 }
 
 // Copy returns a deep copy of the Hop.
@@ -165,18 +202,23 @@ func (h *Hop) PackHopPayload(w io.Writer, nextChanID uint64) error {
 	// required routing fields, as well as these optional values.
 	var records []tlv.Record
 
-	// Every hop must have an amount to forward and CLTV expiry.
+	// Every hop must have an amount to forward and CLTV expiry, except
+	// for the intermediate hops of a blinded route, which learn them from
+	// their encrypted data.
 	amt := uint64(h.AmtToForward)
-	records = append(records,
-		record.NewAmtToFwdRecord(&amt),
-		record.NewLockTimeRecord(&h.OutgoingTimeLock),
-	)
+	if !h.isBlindedIntermediate() {
+		records = append(records,
+			record.NewAmtToFwdRecord(&amt),
+			record.NewLockTimeRecord(&h.OutgoingTimeLock),
+		)
+	}
 
 	// BOLT 04 says the next_hop_id should be omitted for the final hop,
-	// but present for all others.
+	// but present for all others. Within a blinded route, the next hop is
+	// part of the encrypted data.
 	//
 	// TODO(conner): test using hop.Exit once available
-	if nextChanID != 0 {
+	if nextChanID != 0 && !h.isBlinded() {
 		records = append(records,
 			record.NewNextHopIDRecord(&nextChanID),
 		)
@@ -205,6 +247,35 @@ func (h *Hop) PackHopPayload(w io.Writer, nextChanID uint64) error {
 		}
 	}
 
+	// Add the records of a blinded route destined for this hop.
+	if h.isBlinded() {
+		records = append(records,
+			record.NewEncryptedDataRecord(&h.EncryptedData),
+		)
+	}
+	if h.BlindingPoint != nil {
+		records = append(records,
+			record.NewBlindingPointRecord(&h.BlindingPoint),
+		)
+	}
+	totalAmt := uint64(h.TotalAmtMsat)
+	if totalAmt != 0 {
+		records = append(records,
+			record.NewTotalAmtMsatRecord(&totalAmt),
+		)
+	}
+
+	// A trampoline onion may only be handed to the final hop.
+	if h.TrampolineOnion != nil {
+		if nextChanID != 0 {
+			return ErrIntermediateTrampolineHop
+		}
+
+		records = append(records,
+			record.NewTrampolineOnionRecord(&h.TrampolineOnion),
+		)
+	}
+
 	// Append any custom types destined for this hop.
 	tlvRecords := tlv.MapToRecords(h.CustomRecords)
 	records = append(records, tlvRecords...)
@@ -235,20 +306,50 @@ func (h *Hop) PayloadSize(nextChanID uint64) uint64 {
 			tlv.VarIntSize(length) + length
 	}
 
-	// Add amount size.
-	addRecord(record.AmtOnionType, tlv.SizeTUint64(uint64(h.AmtToForward)))
+	if !h.isBlindedIntermediate() {
+		// Add amount size.
+		addRecord(
+			record.AmtOnionType,
+			tlv.SizeTUint64(uint64(h.AmtToForward)),
+		)
 
-	// Add lock time size.
-	addRecord(
-		record.LockTimeOnionType,
-		tlv.SizeTUint64(uint64(h.OutgoingTimeLock)),
-	)
+		// Add lock time size.
+		addRecord(
+			record.LockTimeOnionType,
+			tlv.SizeTUint64(uint64(h.OutgoingTimeLock)),
+		)
+	}
 
 	// Add next hop if present.
-	if nextChanID != 0 {
+	if nextChanID != 0 && !h.isBlinded() {
 		addRecord(record.NextHopOnionType, 8)
 	}
 
+	// Add the records of a blinded route if present.
+	if h.isBlinded() {
+		addRecord(
+			record.EncryptedDataOnionType,
+			uint64(len(h.EncryptedData)),
+		)
+	}
+	if h.BlindingPoint != nil {
+		addRecord(record.BlindingPointOnionType, 33)
+	}
+	if h.TotalAmtMsat != 0 {
+		addRecord(
+			record.TotalAmtMsatOnionType,
+			tlv.SizeTUint64(uint64(h.TotalAmtMsat)),
+		)
+	}
+
+	// Add the trampoline onion if present.
+	if h.TrampolineOnion != nil {
+		addRecord(
+			record.TrampolineOnionType,
+			uint64(len(h.TrampolineOnion)),
+		)
+	}
+
 	// Add mpp if present.
 	if h.MPP != nil {
 		addRecord(record.MPPOnionType, h.MPP.PayloadSize())
@@ -404,9 +505,7 @@ func (r *Route) ToSphinxPath() (*sphinx.PaymentPath, error) {
 	// to an OnionHop with matching per-hop payload within the path as used
 	// by the sphinx package.
 	for i, hop := range r.Hops {
-		pub, err := btcec.ParsePubKey(
-			hop.PubKeyBytes[:], btcec.S256(),
-		)
+		pub, err := btcec.ParsePubKey(hop.PubKeyBytes[:])
 		if err != nil {
 			return nil, err
 		}
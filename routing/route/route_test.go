@@ -5,14 +5,14 @@ import (
 	"encoding/hex"
 	"testing"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/record"
 )
 
 var (
 	testPrivKeyBytes, _ = hex.DecodeString("e126f68f7eafcc8b74f54d269fe206be715000f94dac067d1c04a8ca3b2db734")
-	_, testPubKey       = btcec.PrivKeyFromBytes(btcec.S256(), testPrivKeyBytes)
+	_, testPubKey       = btcec.PrivKeyFromBytes(testPrivKeyBytes)
 	testPubKeyBytes, _  = NewVertexFromBytes(testPubKey.SerializeCompressed())
 )
 
@@ -189,6 +189,81 @@ func TestPayloadSize(t *testing.T) {
 		},
 	}
 
+	assertPayloadSizes(t, hops)
+}
+
+// TestBlindedPayloadSize tests the payload size calculation for the hops of a
+// blinded route.
+func TestBlindedPayloadSize(t *testing.T) {
+	hops := []*Hop{
+		{
+			PubKeyBytes:      testPubKeyBytes,
+			AmtToForward:     1200,
+			OutgoingTimeLock: 700000,
+			ChannelID:        63584534844,
+		},
+		{
+			PubKeyBytes:      testPubKeyBytes,
+			AmtToForward:     1000,
+			OutgoingTimeLock: 600000,
+			ChannelID:        3432483437438,
+			EncryptedData:    bytes.Repeat([]byte{1}, 50),
+			BlindingPoint:    testPubKey,
+		},
+		{
+			PubKeyBytes:   testPubKeyBytes,
+			AmtToForward:  1000,
+			EncryptedData: bytes.Repeat([]byte{2}, 60),
+		},
+		{
+			PubKeyBytes:      testPubKeyBytes,
+			AmtToForward:     1000,
+			OutgoingTimeLock: 600000,
+			EncryptedData:    bytes.Repeat([]byte{3}, 40),
+			TotalAmtMsat:     1000,
+		},
+	}
+
+	assertPayloadSizes(t, hops)
+}
+
+// TestTrampolineHop asserts that a Hop will only encode a trampoline onion for
+// the final hop of a route, and that its payload size accounts for the onion.
+func TestTrampolineHop(t *testing.T) {
+	t.Parallel()
+
+	hops := []*Hop{
+		{
+			PubKeyBytes:      testPubKeyBytes,
+			AmtToForward:     1200,
+			OutgoingTimeLock: 700000,
+			ChannelID:        63584534844,
+		},
+		{
+			PubKeyBytes:      testPubKeyBytes,
+			AmtToForward:     1000,
+			OutgoingTimeLock: 600000,
+			TrampolineOnion:  bytes.Repeat([]byte{1}, 300),
+		},
+	}
+
+	// Encoding a trampoline onion to an intermediate hop should result in
+	// a failure.
+	var b bytes.Buffer
+	err := hops[1].PackHopPayload(&b, 2)
+	if err != ErrIntermediateTrampolineHop {
+		t.Fatalf("expected err: %v, got: %v",
+			ErrIntermediateTrampolineHop, err)
+	}
+
+	assertPayloadSizes(t, hops)
+}
+
+// assertPayloadSizes asserts that the payload size calculated for each of the
+// hops matches the size of its encoded payload.
+func assertPayloadSizes(t *testing.T, hops []*Hop) {
+	t.Helper()
+
 	rt := Route{
 		Hops: hops,
 	}
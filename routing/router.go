@@ -10,9 +10,9 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
 
@@ -233,7 +233,7 @@ type MissionController interface {
 	// GetProbability is expected to return the success probability of a
 	// payment from fromNode along edge.
 	GetProbability(fromNode, toNode route.Vertex,
-		amt lnwire.MilliSatoshi) float64
+		amt lnwire.MilliSatoshi, capacity btcutil.Amount) float64
 }
 
 // FeeSchema is the set fee configuration for a Lightning Node on the network.
@@ -270,6 +270,10 @@ type ChannelPolicy struct {
 	// MinHTLC is the minimum HTLC size including fees we are allowed to
 	// forward over this channel.
 	MinHTLC *lnwire.MilliSatoshi
+
+	// InboundFee is the fee that is charged for htlcs that arrive over
+	// this channel. If nil, the current inbound fee is left unchanged.
+	InboundFee *lnwire.Fee
 }
 
 // Config defines the configuration for the ChannelRouter. ALL elements within
@@ -352,6 +356,12 @@ type Config struct {
 	// from blocking initial usage of the daemon.
 	AssumeChannelValid bool
 
+	// IsAlias returns true if the given short channel ID is an alias
+	// rather than a reference to an on-chain funding output. Edges using
+	// an alias can't be validated against the chain. If nil, no short
+	// channel ID is considered an alias.
+	IsAlias func(scid lnwire.ShortChannelID) bool
+
 	// PathFindingConfig defines global path finding parameters.
 	PathFindingConfig PathFindingConfig
 
@@ -1445,6 +1455,15 @@ func (r *ChannelRouter) processUpdate(msg interface{},
 				"chan_id=%v", msg.ChannelID)
 		}
 
+		// Edges using an alias are only ever added for our own
+		// channels, which we've already validated ourselves. As the
+		// alias can't be used to locate the funding output, we'll add
+		// the edge directly using the channel point it carries.
+		channelID := lnwire.NewShortChanIDFromInt(msg.ChannelID)
+		if r.cfg.IsAlias != nil && r.cfg.IsAlias(channelID) {
+			return r.addAliasEdge(msg, op...)
+		}
+
 		// If AssumeChannelValid is present, then we are unable to
 		// perform any of the expensive checks below, so we'll
 		// short-circuit our path straight to adding the edge to our
@@ -1465,7 +1484,6 @@ func (r *ChannelRouter) processUpdate(msg interface{},
 		// Before we can add the channel to the channel graph, we need
 		// to obtain the full funding outpoint that's encoded within
 		// the channel ID.
-		channelID := lnwire.NewShortChanIDFromInt(msg.ChannelID)
 		fundingTx, err := r.fetchFundingTx(&channelID)
 		if err != nil {
 			// In order to ensure we don't erroneously mark a
@@ -1680,6 +1698,49 @@ func (r *ChannelRouter) processUpdate(msg interface{},
 	return nil
 }
 
+// addAliasEdge adds an edge that uses an alias as its short channel ID to the
+// graph, and updates the chain view so we'll be notified once the channel's
+// funding output is spent.
+func (r *ChannelRouter) addAliasEdge(edge *channeldb.ChannelEdgeInfo,
+	op ...batch.SchedulerOption) error {
+
+	if err := r.cfg.Graph.AddChannelEdge(edge, op...); err != nil {
+		return errors.Errorf("unable to add edge: %v", err)
+	}
+
+	log.Tracef("New alias channel added! Link connects %x and %x with "+
+		"ChannelPoint(%v): chan_id=%v, capacity=%v",
+		edge.NodeKey1Bytes, edge.NodeKey2Bytes, edge.ChannelPoint,
+		edge.ChannelID, edge.Capacity)
+	r.stats.incNumEdgesDiscovered()
+
+	witnessScript, err := input.GenMultiSigScript(
+		edge.BitcoinKey1Bytes[:], edge.BitcoinKey2Bytes[:],
+	)
+	if err != nil {
+		return err
+	}
+	fundingPkScript, err := input.WitnessScriptHash(witnessScript)
+	if err != nil {
+		return err
+	}
+
+	filterUpdate := []channeldb.EdgePoint{
+		{
+			FundingPkScript: fundingPkScript,
+			OutPoint:        edge.ChannelPoint,
+		},
+	}
+	err = r.cfg.ChainView.UpdateFilter(
+		filterUpdate, atomic.LoadUint32(&r.bestHeight),
+	)
+	if err != nil {
+		return errors.Errorf("unable to update chain view: %v", err)
+	}
+
+	return nil
+}
+
 // fetchFundingTx returns the funding transaction identified by the passed
 // short channel ID.
 //
@@ -1807,7 +1868,7 @@ func generateNewSessionKey() (*btcec.PrivateKey, error) {
 	// any replay.
 	//
 	// TODO(roasbeef): add more sources of randomness?
-	return btcec.NewPrivateKey(btcec.S256())
+	return btcec.NewPrivateKey()
 }
 
 // generateSphinxPacket generates then encodes a sphinx packet which encodes
@@ -1828,12 +1889,7 @@ func generateSphinxPacket(rt *route.Route, paymentHash []byte,
 
 	log.Tracef("Constructed per-hop payloads for payment_hash=%x: %v",
 		paymentHash[:], newLogClosure(func() string {
-			path := make([]sphinx.OnionHop, sphinxPath.TrueRouteLength())
-			for i := range path {
-				hopCopy := sphinxPath[i]
-				hopCopy.NodePub.Curve = nil
-				path[i] = hopCopy
-			}
+			path := sphinxPath[:sphinxPath.TrueRouteLength()]
 			return spew.Sdump(path)
 		}),
 	)
@@ -1857,14 +1913,7 @@ func generateSphinxPacket(rt *route.Route, paymentHash []byte,
 
 	log.Tracef("Generated sphinx packet: %v",
 		newLogClosure(func() string {
-			// We make a copy of the ephemeral key and unset the
-			// internal curve here in order to keep the logs from
-			// getting noisy.
-			key := *sphinxPacket.EphemeralKey
-			key.Curve = nil
-			packetCopy := *sphinxPacket
-			packetCopy.EphemeralKey = &key
-			return spew.Sdump(packetCopy)
+			return spew.Sdump(sphinxPacket)
 		}),
 	)
 
@@ -1966,6 +2015,18 @@ type LightningPayment struct {
 	//
 	// NOTE: This field is _optional_.
 	MaxShardAmt *lnwire.MilliSatoshi
+
+	// BlindedPayment is an optional blinded path to the recipient of the
+	// payment. If set, the Target must be the introduction point of the
+	// path and the FinalCLTVDelta must cover the time-lock delta of the
+	// path. Payments to a blinded path are never split.
+	BlindedPayment *zpay32.BlindedPaymentPath
+
+	// Trampoline is an optional trampoline node the payment is routed
+	// over. If set, we only find routes to the trampoline node, which
+	// forwards the payment to the Target. Trampoline payments are never
+	// split.
+	Trampoline *TrampolineParams
 }
 
 // AMPOptions houses information that must be known in order to send an AMP
@@ -2068,21 +2129,7 @@ func (r *ChannelRouter) SendPaymentAsync(payment *LightningPayment) error {
 // representation of the passed payment.
 func spewPayment(payment *LightningPayment) logClosure {
 	return newLogClosure(func() string {
-		// Make a copy of the payment with a nilled Curve
-		// before spewing.
-		var routeHints [][]zpay32.HopHint
-		for _, routeHint := range payment.RouteHints {
-			var hopHints []zpay32.HopHint
-			for _, hopHint := range routeHint {
-				h := hopHint.Copy()
-				h.NodeID.Curve = nil
-				hopHints = append(hopHints, h)
-			}
-			routeHints = append(routeHints, hopHints)
-		}
-		p := *payment
-		p.RouteHints = routeHints
-		return spew.Sdump(p)
+		return spew.Sdump(payment)
 	})
 }
 
@@ -2654,6 +2701,47 @@ func (r *ChannelRouter) MarkEdgeLive(chanID lnwire.ShortChannelID) error {
 	return r.cfg.Graph.MarkEdgeLive(chanID.ToUint64())
 }
 
+// MarkChannelSpliced records that the funding output of one of our channels
+// has been replaced by a splice. The channel remains within the graph, and
+// will be pruned once the new funding output is spent.
+func (r *ChannelRouter) MarkChannelSpliced(chanPoint,
+	fundingPoint *wire.OutPoint, capacity btcutil.Amount) error {
+
+	info, _, _, err := r.cfg.Graph.FetchChannelEdgesByOutpoint(chanPoint)
+	if err != nil {
+		return err
+	}
+
+	err = r.cfg.Graph.MarkChannelSpliced(chanPoint, fundingPoint, capacity)
+	if err != nil {
+		return err
+	}
+
+	// Update the filter of our chain view, so we're notified once the
+	// new funding output is spent.
+	witnessScript, err := input.GenMultiSigScript(
+		info.BitcoinKey1Bytes[:], info.BitcoinKey2Bytes[:],
+	)
+	if err != nil {
+		return err
+	}
+	fundingPkScript, err := input.WitnessScriptHash(witnessScript)
+	if err != nil {
+		return err
+	}
+
+	filterUpdate := []channeldb.EdgePoint{
+		{
+			FundingPkScript: fundingPkScript,
+			OutPoint:        *fundingPoint,
+		},
+	}
+
+	return r.cfg.ChainView.UpdateFilter(
+		filterUpdate, atomic.LoadUint32(&r.bestHeight),
+	)
+}
+
 // generateBandwidthHints is a helper function that's utilized the main
 // findPath function in order to obtain hints from the lower layer w.r.t to the
 // available bandwidth of edges on the network. Currently, we'll only obtain
@@ -2769,6 +2857,9 @@ func (r *ChannelRouter) BuildRoute(amt *lnwire.MilliSatoshi,
 	}()
 
 	// Traverse hops backwards to accumulate fees in the running amounts.
+	// We keep track of the outbound fee of the previously processed hop,
+	// because it bounds the inbound fee that the hop may charge.
+	var nextOutboundFee lnwire.MilliSatoshi
 	source := r.selfNode.PubKeyBytes
 	for i := len(hops) - 1; i >= 0; i-- {
 		toNode := hops[i]
@@ -2818,12 +2909,30 @@ func (r *ChannelRouter) BuildRoute(amt *lnwire.MilliSatoshi,
 			}
 		}
 
+		// Add the inbound fee that the to node charges for this
+		// channel. The final node doesn't forward and therefore
+		// doesn't charge an inbound fee. The total fee of the to node
+		// can never be negative.
+		if i < len(hops)-1 {
+			inboundFee := policy.inboundFee.CalcFee(runningAmt)
+			if inboundFee < -int64(nextOutboundFee) {
+				inboundFee = -int64(nextOutboundFee)
+			}
+
+			runningAmt = lnwire.MilliSatoshi(
+				int64(runningAmt) + inboundFee,
+			)
+		}
+
 		// Add fee for this hop.
+		nextOutboundFee = 0
 		if !localChan {
-			runningAmt += policy.ComputeFee(runningAmt)
+			nextOutboundFee = policy.policy.ComputeFee(runningAmt)
+			runningAmt += nextOutboundFee
 		}
 
-		log.Tracef("Select channel %v at position %v", policy.ChannelID, i)
+		log.Tracef("Select channel %v at position %v",
+			policy.policy.ChannelID, i)
 
 		edges[i] = unifiedPolicy
 	}
@@ -2832,7 +2941,7 @@ func (r *ChannelRouter) BuildRoute(amt *lnwire.MilliSatoshi,
 	// total amount, we make a forward pass. Because the amount may have
 	// been increased in the backward pass, fees need to be recalculated and
 	// amount ranges re-checked.
-	var pathEdges []*channeldb.ChannelEdgePolicy
+	var pathEdges []*unifiedPolicyEdge
 	receiverAmt := runningAmt
 	for i, edge := range edges {
 		policy := edge.getPolicy(receiverAmt, bandwidthHints)
@@ -2844,8 +2953,12 @@ func (r *ChannelRouter) BuildRoute(amt *lnwire.MilliSatoshi,
 		}
 
 		if i > 0 {
-			// Decrease the amount to send while going forward.
-			receiverAmt -= policy.ComputeFeeFromIncoming(
+			// Decrease the amount to send while going forward. The
+			// node forwarding over this channel charges its
+			// outbound fee and the inbound fee of the channel that
+			// it received the htlc on.
+			receiverAmt = computeAmtToForward(
+				policy.policy, pathEdges[i-1].inboundFee,
 				receiverAmt,
 			)
 		}
@@ -10,10 +10,11 @@ import (
 	"testing"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -115,12 +116,15 @@ func createTestCtxFromGraphInstanceAssumeValid(t *testing.T,
 		AttemptCost:    100,
 	}
 
+	estimator, err := NewAprioriEstimator(AprioriConfig{
+		PenaltyHalfLife:       time.Hour,
+		AprioriHopProbability: 0.9,
+		AprioriWeight:         0.5,
+	})
+	require.NoError(t, err, "failed to create estimator")
+
 	mcConfig := &MissionControlConfig{
-		ProbabilityEstimatorCfg: ProbabilityEstimatorCfg{
-			PenaltyHalfLife:       time.Hour,
-			AprioriHopProbability: 0.9,
-			AprioriWeight:         0.5,
-		},
+		Estimator: estimator,
 	}
 
 	mc, err := NewMissionControl(
@@ -245,8 +249,7 @@ func signErrChanUpdate(t *testing.T, key *btcec.PrivateKey,
 	require.NoError(t, err, "failed to retrieve data to sign")
 
 	digest := chainhash.DoubleHashB(chanUpdateMsg)
-	sig, err := key.Sign(digest)
-	require.NoError(t, err, "failed to sign msg")
+	sig := ecdsa.Sign(key, digest)
 
 	errChanUpdate.Signature, err = lnwire.NewSigFromSignature(sig)
 	require.NoError(t, err, "failed to create new signature")
@@ -642,7 +645,7 @@ func TestSendPaymentErrorFeeInsufficientPrivateEdge(t *testing.T) {
 		sgNode           = ctx.aliases["songoku"]
 	)
 
-	sgNodeID, err := btcec.ParsePubKey(sgNode[:], btcec.S256())
+	sgNodeID, err := btcec.ParsePubKey(sgNode[:])
 	require.NoError(t, err)
 
 	// Craft a LightningPayment struct that'll send a payment from roasbeef
@@ -776,7 +779,7 @@ func TestSendPaymentPrivateEdgeUpdateFeeExceedsLimit(t *testing.T) {
 		feeLimit         = lnwire.MilliSatoshi(500000)
 	)
 
-	sgNodeID, err := btcec.ParsePubKey(sgNode[:], btcec.S256())
+	sgNodeID, err := btcec.ParsePubKey(sgNode[:])
 	require.NoError(t, err)
 
 	// Craft a LightningPayment struct that'll send a payment from roasbeef
@@ -1446,7 +1449,7 @@ func TestAddEdgeUnknownVertexes(t *testing.T) {
 
 	// We will connect node 1 to "sophon"
 	connectNode := ctx.aliases["sophon"]
-	connectNodeKey, err := btcec.ParsePubKey(connectNode[:], btcec.S256())
+	connectNodeKey, err := btcec.ParsePubKey(connectNode[:])
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -2473,8 +2476,8 @@ func TestFindPathFeeWeighting(t *testing.T) {
 	if len(path) != 1 {
 		t.Fatalf("expected path length of 1, instead was: %v", len(path))
 	}
-	if path[0].Node.Alias != "luoji" {
-		t.Fatalf("wrong node: %v", path[0].Node.Alias)
+	if path[0].policy.Node.Alias != "luoji" {
+		t.Fatalf("wrong node: %v", path[0].policy.Node.Alias)
 	}
 }
 
@@ -2715,7 +2718,7 @@ func TestIsStaleEdgePolicy(t *testing.T) {
 func TestEmptyRoutesGenerateSphinxPacket(t *testing.T) {
 	t.Parallel()
 
-	sessionKey, _ := btcec.NewPrivateKey(btcec.S256())
+	sessionKey, _ := btcec.NewPrivateKey()
 	emptyRoute := &route.Route{}
 	_, _, err := generateSphinxPacket(emptyRoute, testHash[:], sessionKey)
 	if err != route.ErrNoRouteHopsProvided {
@@ -3477,6 +3480,34 @@ func TestChannelOnChainRejectionZombie(t *testing.T) {
 	assertChanChainRejection(t, ctx, edge, ErrInvalidFundingOutput)
 }
 
+// TestAddAliasEdge tests that edges using an alias as their short channel ID
+// are added to the graph without being validated against the chain.
+func TestAddAliasEdge(t *testing.T) {
+	t.Parallel()
+
+	ctx, cleanup := createTestCtxSingleNode(t, 0)
+	defer cleanup()
+
+	// We'll create an edge without adding its funding transaction to the
+	// chain, which would normally cause the validation to fail.
+	edge, err := newChannelEdgeInfo(ctx, 1, edgeCreationNoFundingTx)
+	require.NoError(t, err)
+
+	aliasScid := lnwire.NewShortChanIDFromInt(edge.ChannelID)
+	ctx.router.cfg.IsAlias = func(scid lnwire.ShortChannelID) bool {
+		return scid == aliasScid
+	}
+
+	require.NoError(t, ctx.router.AddEdge(edge))
+
+	_, _, exists, isZombie, err := ctx.graph.HasChannelEdge(
+		edge.ChannelID,
+	)
+	require.NoError(t, err)
+	require.True(t, exists, "alias edge should be added")
+	require.False(t, isZombie, "alias edge shouldn't be a zombie")
+}
+
 func createDummyTestGraph(t *testing.T) *testGraphInstance {
 	// Setup two simple channels such that we can mock sending along this
 	// route.
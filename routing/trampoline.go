@@ -0,0 +1,108 @@
+package routing
+
+import (
+	"github.com/btcsuite/btcd/btcec/v2"
+	htlchop "github.com/lightningnetwork/lnd/htlcswitch/hop"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// TrampolineParams describes the trampoline node a payment is routed over.
+// We only find a route to the trampoline node, which finds the remaining
+// route to the target of the payment on our behalf. This allows nodes that
+// don't sync the channel graph to pay arbitrary destinations.
+type TrampolineParams struct {
+	// Node is the trampoline node the payment is routed to.
+	Node route.Vertex
+
+	// Fee is the fee the trampoline node is allowed to spend on its own
+	// fee and the fees of the route to the target. It is paid on top of
+	// the amount of the payment and counts towards its fee limit.
+	Fee lnwire.MilliSatoshi
+
+	// CltvDelta is the time-lock delta the trampoline node is allowed to
+	// spend on its own delta and the delta of the route to the target.
+	CltvDelta uint16
+
+	// Onion is the trampoline onion for the trampoline node. If it is
+	// nil, an onion instructing the trampoline node to pay the target of
+	// the payment is created for every route.
+	Onion []byte
+}
+
+// newTrampolineRoute turns a path to the trampoline node of the payment into
+// a route that hands the trampoline node the trampoline onion, along with
+// the fee and time-lock delta it may spend on forwarding amt to the target of
+// the payment.
+func (p *paymentSession) newTrampolineRoute(sourceVertex route.Vertex,
+	path []*unifiedPolicyEdge, height uint32, amt lnwire.MilliSatoshi,
+	finalCltvDelta uint16) (*route.Route, error) {
+
+	trampoline := p.payment.Trampoline
+	routeAmt := amt + trampoline.Fee
+
+	rt, err := newRoute(
+		sourceVertex, path, height,
+		finalHopParams{
+			amt:       routeAmt,
+			totalAmt:  routeAmt,
+			cltvDelta: finalCltvDelta + trampoline.CltvDelta,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	onion := trampoline.Onion
+	if onion == nil {
+		finalExpiry := height + uint32(finalCltvDelta)
+		onion, err = p.buildTrampolineOnion(amt, finalExpiry)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The trampoline node must understand the TLV payload, as it
+	// advertises trampoline support.
+	trampolineHop := rt.Hops[len(rt.Hops)-1]
+	trampolineHop.TrampolineOnion = onion
+	trampolineHop.LegacyPayload = false
+
+	return rt, nil
+}
+
+// buildTrampolineOnion creates the trampoline onion that instructs the
+// trampoline node of the payment to pay amt to its target, with the HTLCs
+// expiring at finalExpiry. The details of the invoice are passed on to the
+// trampoline node, so that the target doesn't need to support trampoline
+// payments.
+func (p *paymentSession) buildTrampolineOnion(amt lnwire.MilliSatoshi,
+	finalExpiry uint32) ([]byte, error) {
+
+	trampolineKey, err := btcec.ParsePubKey(p.payment.Trampoline.Node[:])
+	if err != nil {
+		return nil, err
+	}
+
+	targetKey, err := btcec.ParsePubKey(p.payment.Target[:])
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &htlchop.TrampolinePayload{
+		OutgoingNodeID:  targetKey,
+		AmtToForward:    amt,
+		OutgoingCltv:    finalExpiry,
+		InvoiceFeatures: p.payment.DestFeatures,
+		RouteHints:      p.payment.RouteHints,
+	}
+	if p.payment.PaymentAddr != nil {
+		payload.MPP = record.NewMPP(amt, *p.payment.PaymentAddr)
+	}
+
+	return htlchop.BuildTrampolineOnion([]*htlchop.TrampolineHop{{
+		NodeID:  trampolineKey,
+		Payload: payload,
+	}})
+}
@@ -0,0 +1,337 @@
+package routing
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	htlchop "github.com/lightningnetwork/lnd/htlcswitch/hop"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
+)
+
+// trampolineMaxParts is the maximum number of parts the payment to the next
+// trampoline node or the recipient is split into, if it supports multi-path
+// payments.
+const trampolineMaxParts = 16
+
+// TrampolineForwarderConfig contains the dependencies and the policy of the
+// trampoline forwarder.
+type TrampolineForwarderConfig struct {
+	// DecodeOnion decrypts our layer of a trampoline onion.
+	DecodeOnion func([]byte) (*htlchop.TrampolinePayload, error)
+
+	// SendPayment dispatches the payment to the next trampoline node or
+	// the recipient without waiting for its outcome.
+	SendPayment func(*LightningPayment) error
+
+	// SubscribePayment subscribes to the updates of the payment with the
+	// given hash.
+	SubscribePayment func(lntypes.Hash) (*ControlTowerSubscriber, error)
+
+	// ResolveHtlc settles or fails the incoming htlc once the outcome of
+	// the payment is known.
+	ResolveHtlc func(*invoices.ExitHtlcResolution) error
+
+	// FeeBase is the base fee we charge for forwarding a payment as
+	// trampoline node, on top of the fees of the route we pay.
+	FeeBase lnwire.MilliSatoshi
+
+	// FeeRate is the proportional fee in millionths we charge for
+	// forwarding a payment as trampoline node.
+	FeeRate lnwire.MilliSatoshi
+
+	// CltvDelta is the time-lock delta we require between the incoming
+	// htlc and the payment we forward.
+	CltvDelta uint16
+
+	// PaymentTimeout is the time we spend trying to forward a payment.
+	PaymentTimeout time.Duration
+}
+
+// TrampolineForwarder forwards the payments of htlcs that ask us to act as
+// trampoline node. It finds a route to the next trampoline node or the
+// recipient itself, and settles the incoming htlc once the payment succeeds.
+//
+// NOTE: Every incoming htlc is forwarded as a payment on its own. Incoming
+// multi-path payments aren't aggregated, so htlcs that only pay a part of the
+// total amount are rejected.
+type TrampolineForwarder struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg *TrampolineForwarderConfig
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// NewTrampolineForwarder creates a new trampoline forwarder.
+func NewTrampolineForwarder(
+	cfg *TrampolineForwarderConfig) *TrampolineForwarder {
+
+	return &TrampolineForwarder{
+		cfg:  cfg,
+		quit: make(chan struct{}),
+	}
+}
+
+// Start starts the trampoline forwarder.
+func (t *TrampolineForwarder) Start() error {
+	if !atomic.CompareAndSwapUint32(&t.started, 0, 1) {
+		return nil
+	}
+
+	log.Infof("Trampoline forwarder starting")
+
+	return nil
+}
+
+// Stop stops the trampoline forwarder. Payments that are in flight are
+// resumed by the router on restart, after which the incoming link replays
+// their htlcs to us.
+func (t *TrampolineForwarder) Stop() error {
+	if !atomic.CompareAndSwapUint32(&t.stopped, 0, 1) {
+		return nil
+	}
+
+	log.Infof("Trampoline forwarder shutting down")
+
+	close(t.quit)
+	t.wg.Wait()
+
+	return nil
+}
+
+// Fee returns the fee we charge for forwarding amt as trampoline node.
+func (t *TrampolineForwarder) Fee(
+	amt lnwire.MilliSatoshi) lnwire.MilliSatoshi {
+
+	return t.cfg.FeeBase + amt*t.cfg.FeeRate/1_000_000
+}
+
+// ForwardHtlc forwards the payment of the given htlc. It returns immediately,
+// the htlc is resolved once the outcome of the payment is known.
+//
+// NOTE: This method is part of the invoices.TrampolineForwarder signature.
+func (t *TrampolineForwarder) ForwardHtlc(htlc *invoices.ExitHtlc) error {
+	select {
+	case <-t.quit:
+		return errors.New("trampoline forwarder shutting down")
+	default:
+	}
+
+	t.wg.Add(1)
+	go t.forwardHtlc(htlc)
+
+	return nil
+}
+
+// forwardHtlc forwards the payment of the htlc and resolves the htlc with its
+// outcome.
+//
+// NOTE: This method MUST be run as a goroutine.
+func (t *TrampolineForwarder) forwardHtlc(htlc *invoices.ExitHtlc) {
+	defer t.wg.Done()
+
+	failure := t.pay(htlc)
+	if failure == nil {
+		return
+	}
+
+	log.Debugf("Failing trampoline htlc %v: %v", htlc.CircuitKey,
+		failure)
+
+	err := t.cfg.ResolveHtlc(&invoices.ExitHtlcResolution{
+		CircuitKey:     htlc.CircuitKey,
+		Action:         invoices.ExitHtlcFail,
+		FailureMessage: failure,
+	})
+	if err != nil {
+		log.Errorf("Unable to fail trampoline htlc %v: %v",
+			htlc.CircuitKey, err)
+	}
+}
+
+// pay forwards the payment of the htlc and settles the htlc once it
+// succeeds. It returns the failure to fail the htlc with otherwise. If we
+// shut down before the outcome is known, the htlc is left unresolved.
+func (t *TrampolineForwarder) pay(
+	htlc *invoices.ExitHtlc) lnwire.FailureMessage {
+
+	payment, failure := t.newPayment(htlc)
+	if failure != nil {
+		return failure
+	}
+
+	// If the htlc is replayed after a restart, the payment is already in
+	// flight or even completed. We then only wait for its outcome.
+	err := t.cfg.SendPayment(payment)
+	switch {
+	case err == nil:
+		log.Infof("Forwarding trampoline htlc %v as payment %v to %v",
+			htlc.CircuitKey, htlc.Hash, payment.Target)
+
+	case errors.Is(err, channeldb.ErrPaymentInFlight),
+		errors.Is(err, channeldb.ErrAlreadyPaid):
+
+		log.Debugf("Trampoline payment %v already initiated",
+			htlc.Hash)
+
+	default:
+		log.Errorf("Unable to forward trampoline htlc %v: %v",
+			htlc.CircuitKey, err)
+
+		return &lnwire.FailTemporaryNodeFailure{}
+	}
+
+	subscription, err := t.cfg.SubscribePayment(htlc.Hash)
+	if err != nil {
+		log.Errorf("Unable to subscribe to trampoline payment %v: %v",
+			htlc.Hash, err)
+
+		return &lnwire.FailTemporaryNodeFailure{}
+	}
+	defer subscription.Close()
+
+	// The subscription is closed once the payment completed, the last
+	// update then holds its outcome.
+	var latest *channeldb.MPPayment
+loop:
+	for {
+		select {
+		case item, ok := <-subscription.Updates:
+			if !ok {
+				break loop
+			}
+			latest = item.(*channeldb.MPPayment)
+
+		case <-t.quit:
+			return nil
+		}
+	}
+
+	if latest == nil {
+		return &lnwire.FailTemporaryNodeFailure{}
+	}
+
+	settleInfo, reason := latest.TerminalInfo()
+	if settleInfo != nil {
+		err := t.cfg.ResolveHtlc(&invoices.ExitHtlcResolution{
+			CircuitKey: htlc.CircuitKey,
+			Action:     invoices.ExitHtlcSettle,
+			Preimage:   settleInfo.Preimage,
+		})
+		if err != nil {
+			log.Errorf("Unable to settle trampoline htlc %v: %v",
+				htlc.CircuitKey, err)
+		}
+
+		return nil
+	}
+
+	if reason == nil {
+		return &lnwire.FailTemporaryNodeFailure{}
+	}
+
+	log.Debugf("Trampoline payment %v failed: %v", htlc.Hash, *reason)
+
+	switch *reason {
+	// The recipient rejected the payment, which we pass on to the
+	// sender.
+	case channeldb.FailureReasonPaymentDetails:
+		return lnwire.NewFailIncorrectDetails(
+			htlc.AmtPaid, uint32(htlc.CurrentHeight),
+		)
+
+	// We couldn't find a route within the fee the sender allowed us to
+	// spend. The sender may retry with a higher fee.
+	case channeldb.FailureReasonNoRoute,
+		channeldb.FailureReasonInsufficientBalance:
+
+		return &lnwire.FailTrampolineFeeInsufficient{}
+
+	default:
+		return &lnwire.FailTemporaryNodeFailure{}
+	}
+}
+
+// newPayment creates the payment that forwards the htlc to the next
+// trampoline node or the recipient. It returns the failure to fail the htlc
+// with if the htlc doesn't pay enough or expires too soon.
+func (t *TrampolineForwarder) newPayment(
+	htlc *invoices.ExitHtlc) (*LightningPayment, lnwire.FailureMessage) {
+
+	// We don't aggregate incoming multi-path payments, so the htlc must pay
+	// the full amount. We point the sender at the mpp record rather than
+	// failing on the fee, which would make it retry with a higher fee.
+	if htlc.MPP != nil && htlc.MPP.TotalMsat() != htlc.AmtPaid {
+		log.Debugf("Rejecting trampoline htlc %v, it pays %v of a "+
+			"multi-path payment of %v", htlc.CircuitKey,
+			htlc.AmtPaid, htlc.MPP.TotalMsat())
+
+		return nil, lnwire.NewInvalidOnionPayload(
+			uint64(record.MPPOnionType), 0,
+		)
+	}
+
+	payload, err := t.cfg.DecodeOnion(htlc.TrampolineOnion)
+	if err != nil {
+		log.Debugf("Invalid trampoline onion in htlc %v: %v",
+			htlc.CircuitKey, err)
+
+		return nil, lnwire.NewInvalidOnionPayload(
+			uint64(record.TrampolineOnionType), 0,
+		)
+	}
+
+	fee := t.Fee(payload.AmtToForward)
+	if htlc.AmtPaid < payload.AmtToForward+fee {
+		return nil, &lnwire.FailTrampolineFeeInsufficient{}
+	}
+
+	height := uint32(htlc.CurrentHeight)
+	if payload.OutgoingCltv <= height ||
+		htlc.Expiry < payload.OutgoingCltv+uint32(t.cfg.CltvDelta) {
+
+		return nil, &lnwire.FailTrampolineExpiryTooSoon{}
+	}
+
+	cltvLimit := htlc.Expiry - uint32(t.cfg.CltvDelta) - height
+	payment := &LightningPayment{
+		Target:            route.NewVertex(payload.OutgoingNodeID),
+		Amount:            payload.AmtToForward,
+		FeeLimit:          htlc.AmtPaid - payload.AmtToForward - fee,
+		FinalCLTVDelta:    uint16(payload.OutgoingCltv - height),
+		CltvLimit:         cltvLimit,
+		PayAttemptTimeout: t.cfg.PaymentTimeout,
+		RouteHints:        payload.RouteHints,
+		DestFeatures:      payload.InvoiceFeatures,
+		MaxParts:          1,
+	}
+	if payload.MPP != nil {
+		paymentAddr := payload.MPP.PaymentAddr()
+		payment.PaymentAddr = &paymentAddr
+		payment.MaxParts = trampolineMaxParts
+	}
+	if err := payment.SetPaymentHash(htlc.Hash); err != nil {
+		return nil, &lnwire.FailTemporaryNodeFailure{}
+	}
+
+	// If the onion holds the trampoline onion of the next trampoline
+	// node, we only route the payment to it. The fee and time-lock delta
+	// that remain are at the disposal of the next trampoline node.
+	if payload.NextOnion != nil {
+		payment.Trampoline = &TrampolineParams{
+			Node:  payment.Target,
+			Onion: payload.NextOnion,
+		}
+	}
+
+	return payment, nil
+}
@@ -0,0 +1,222 @@
+package routing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/channeldb"
+	htlchop "github.com/lightningnetwork/lnd/htlcswitch/hop"
+	"github.com/lightningnetwork/lnd/invoices"
+	"github.com/lightningnetwork/lnd/lntypes"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/record"
+	"github.com/lightningnetwork/lnd/routing/route"
+	"github.com/stretchr/testify/require"
+)
+
+// testTrampolinePreimage is the preimage of the htlcs forwarded in the tests.
+var testTrampolinePreimage = lntypes.Preimage{1}
+
+// trampolineForwarderHarness drives a trampoline forwarder with mocked
+// dependencies.
+type trampolineForwarderHarness struct {
+	t *testing.T
+
+	forwarder *TrampolineForwarder
+
+	payload     *htlchop.TrampolinePayload
+	mpp         *record.MPP
+	sendErr     error
+	payments    chan *LightningPayment
+	outcome     *channeldb.MPPayment
+	resolutions chan *invoices.ExitHtlcResolution
+}
+
+func newTrampolineForwarderHarness(t *testing.T) *trampolineForwarderHarness {
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(t, err)
+
+	h := &trampolineForwarderHarness{
+		t: t,
+		payload: &htlchop.TrampolinePayload{
+			OutgoingNodeID: privKey.PubKey(),
+			AmtToForward:   100_000,
+			OutgoingCltv:   200,
+			MPP:            record.NewMPP(100_000, [32]byte{1}),
+		},
+		payments:    make(chan *LightningPayment, 1),
+		resolutions: make(chan *invoices.ExitHtlcResolution, 1),
+	}
+
+	h.forwarder = NewTrampolineForwarder(&TrampolineForwarderConfig{
+		DecodeOnion: func([]byte) (*htlchop.TrampolinePayload, error) {
+			if h.payload == nil {
+				return nil, htlchop.ErrInvalidTrampolineOnion
+			}
+
+			return h.payload, nil
+		},
+		SendPayment: func(payment *LightningPayment) error {
+			h.payments <- payment
+			return h.sendErr
+		},
+		SubscribePayment: func(lntypes.Hash) (*ControlTowerSubscriber,
+			error) {
+
+			subscriber := newControlTowerSubscriber()
+			subscriber.queue.ChanIn() <- h.outcome
+			close(subscriber.queue.ChanIn())
+
+			return subscriber, nil
+		},
+		ResolveHtlc: func(res *invoices.ExitHtlcResolution) error {
+			h.resolutions <- res
+			return nil
+		},
+		FeeBase:   1_000,
+		FeeRate:   1_000,
+		CltvDelta: 40,
+	})
+	require.NoError(t, h.forwarder.Start())
+
+	return h
+}
+
+// forward hands an htlc to the forwarder and returns its resolution.
+func (h *trampolineForwarderHarness) forward(amt lnwire.MilliSatoshi,
+	expiry uint32) *invoices.ExitHtlcResolution {
+
+	h.t.Helper()
+
+	err := h.forwarder.ForwardHtlc(&invoices.ExitHtlc{
+		CircuitKey:      channeldb.CircuitKey{HtlcID: 1},
+		Hash:            testTrampolinePreimage.Hash(),
+		AmtPaid:         amt,
+		Expiry:          expiry,
+		CurrentHeight:   100,
+		MPP:             h.mpp,
+		TrampolineOnion: []byte{1},
+	})
+	require.NoError(h.t, err)
+
+	select {
+	case res := <-h.resolutions:
+		return res
+
+	case <-time.After(time.Second * 5):
+		h.t.Fatal("htlc not resolved")
+	}
+
+	return nil
+}
+
+// TestTrampolineForwarder tests that the trampoline forwarder charges its
+// fee and time-lock delta, and resolves the incoming htlc with the outcome of
+// the payment it forwards.
+func TestTrampolineForwarder(t *testing.T) {
+	t.Parallel()
+
+	h := newTrampolineForwarderHarness(t)
+	defer func() {
+		require.NoError(t, h.forwarder.Stop())
+	}()
+
+	// We charge 1000 msat base fee plus 100 msat proportional fee.
+	const fullAmt = 101_100 + 5_000
+	require.Equal(t, lnwire.MilliSatoshi(1_100), h.forwarder.Fee(100_000))
+
+	// An htlc that doesn't pay our fee is rejected.
+	res := h.forward(101_099, 240)
+	require.Equal(t, invoices.ExitHtlcFail, res.Action)
+	require.Equal(
+		t, &lnwire.FailTrampolineFeeInsufficient{}, res.FailureMessage,
+	)
+
+	// An htlc that doesn't leave us our time-lock delta is rejected.
+	res = h.forward(fullAmt, 239)
+	require.Equal(t, invoices.ExitHtlcFail, res.Action)
+	require.Equal(
+		t, &lnwire.FailTrampolineExpiryTooSoon{}, res.FailureMessage,
+	)
+	require.Empty(t, h.payments)
+
+	// A successful payment settles the incoming htlc. The remainder of
+	// the incoming amount is available to pay the fees of the route.
+	h.outcome = &channeldb.MPPayment{
+		Status: channeldb.StatusSucceeded,
+		HTLCs: []channeldb.HTLCAttempt{{
+			Settle: &channeldb.HTLCSettleInfo{
+				Preimage: testTrampolinePreimage,
+			},
+		}},
+	}
+	res = h.forward(fullAmt, 250)
+	require.Equal(t, invoices.ExitHtlcSettle, res.Action)
+	require.Equal(t, testTrampolinePreimage, res.Preimage)
+
+	payment := <-h.payments
+	require.Equal(t, route.NewVertex(h.payload.OutgoingNodeID),
+		payment.Target)
+	require.Equal(t, h.payload.AmtToForward, payment.Amount)
+	require.Equal(t, lnwire.MilliSatoshi(5_000), payment.FeeLimit)
+	require.Equal(t, uint16(100), payment.FinalCLTVDelta)
+	require.Equal(t, uint32(110), payment.CltvLimit)
+	require.Equal(t, uint32(trampolineMaxParts), payment.MaxParts)
+	require.Nil(t, payment.Trampoline)
+
+	// A replayed htlc waits for the outcome of the payment in flight. If
+	// no route is found, the sender learns that our fee budget was
+	// insufficient.
+	reason := channeldb.FailureReasonNoRoute
+	h.sendErr = channeldb.ErrPaymentInFlight
+	h.outcome = &channeldb.MPPayment{
+		Status:        channeldb.StatusFailed,
+		FailureReason: &reason,
+	}
+	res = h.forward(fullAmt, 250)
+	require.Equal(t, invoices.ExitHtlcFail, res.Action)
+	require.Equal(
+		t, &lnwire.FailTrampolineFeeInsufficient{}, res.FailureMessage,
+	)
+	<-h.payments
+
+	// If the payment can't be dispatched, we fail the htlc.
+	h.sendErr = errors.New("no graph")
+	res = h.forward(fullAmt, 250)
+	require.Equal(
+		t, &lnwire.FailTemporaryNodeFailure{}, res.FailureMessage,
+	)
+	<-h.payments
+
+	// An htlc that only pays a part of a multi-path payment is rejected,
+	// as we don't aggregate incoming multi-path payments.
+	h.sendErr = nil
+	h.mpp = record.NewMPP(2*fullAmt, [32]byte{2})
+	res = h.forward(fullAmt, 250)
+	require.Equal(
+		t, lnwire.NewInvalidOnionPayload(
+			uint64(record.MPPOnionType), 0,
+		), res.FailureMessage,
+	)
+	require.Empty(t, h.payments)
+
+	// An htlc that pays the full amount of its mpp record is forwarded,
+	// and resolved with the outcome of the payment.
+	h.mpp = record.NewMPP(fullAmt, [32]byte{2})
+	res = h.forward(fullAmt, 250)
+	require.Equal(
+		t, &lnwire.FailTrampolineFeeInsufficient{}, res.FailureMessage,
+	)
+	<-h.payments
+
+	// An onion we can't decrypt is rejected.
+	h.payload = nil
+	res = h.forward(fullAmt, 250)
+	require.Equal(
+		t, lnwire.NewInvalidOnionPayload(
+			uint64(record.TrampolineOnionType), 0,
+		), res.FailureMessage,
+	)
+}
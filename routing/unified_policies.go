@@ -1,7 +1,7 @@
 package routing
 
 import (
-	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing/route"
@@ -38,9 +38,11 @@ func newUnifiedPolicies(sourceNode, toNode route.Vertex,
 }
 
 // addPolicy adds a single channel policy. Capacity may be zero if unknown
-// (light clients).
+// (light clients). The inbound fee is the fee that the to node charges for
+// htlcs that arrive over the channel.
 func (u *unifiedPolicies) addPolicy(fromNode route.Vertex,
-	edge *channeldb.ChannelEdgePolicy, capacity btcutil.Amount) {
+	edge *channeldb.ChannelEdgePolicy, inboundFee lnwire.Fee,
+	capacity btcutil.Amount) {
 
 	localChan := fromNode == u.sourceNode
 
@@ -61,15 +63,16 @@ func (u *unifiedPolicies) addPolicy(fromNode route.Vertex,
 	}
 
 	policy.edges = append(policy.edges, &unifiedPolicyEdge{
-		policy:   edge,
-		capacity: capacity,
+		policy:     edge,
+		capacity:   capacity,
+		inboundFee: inboundFee,
 	})
 }
 
 // addGraphPolicies adds all policies that are known for the toNode in the
 // graph.
 func (u *unifiedPolicies) addGraphPolicies(g routingGraph) error {
-	cb := func(edgeInfo *channeldb.ChannelEdgeInfo, _,
+	cb := func(edgeInfo *channeldb.ChannelEdgeInfo, outEdge,
 		inEdge *channeldb.ChannelEdgePolicy) error {
 
 		// If there is no edge policy for this candidate node, skip.
@@ -85,8 +88,21 @@ func (u *unifiedPolicies) addGraphPolicies(g routingGraph) error {
 			return err
 		}
 
+		// The inbound fee for this channel is advertised by the to node
+		// as part of its own policy for the channel. A malformed fee
+		// record is treated as if there were no inbound fee.
+		var inboundFee lnwire.Fee
+		if outEdge != nil {
+			fee, _, err := lnwire.ExtractInboundFee(
+				outEdge.ExtraOpaqueData,
+			)
+			if err == nil {
+				inboundFee = fee
+			}
+		}
+
 		// Add this policy to the unified policies map.
-		u.addPolicy(fromNode, inEdge, edgeInfo.Capacity)
+		u.addPolicy(fromNode, inEdge, inboundFee, edgeInfo.Capacity)
 
 		return nil
 	}
@@ -100,6 +116,10 @@ func (u *unifiedPolicies) addGraphPolicies(g routingGraph) error {
 type unifiedPolicyEdge struct {
 	policy   *channeldb.ChannelEdgePolicy
 	capacity btcutil.Amount
+
+	// inboundFee is the fee that the node this edge points to charges for
+	// htlcs that arrive over the channel.
+	inboundFee lnwire.Fee
 }
 
 // amtInRange checks whether an amount falls within the valid range for a
@@ -139,7 +159,7 @@ type unifiedPolicy struct {
 // specific amount to send. It differentiates between local and network
 // channels.
 func (u *unifiedPolicy) getPolicy(amt lnwire.MilliSatoshi,
-	bandwidthHints map[uint64]lnwire.MilliSatoshi) *channeldb.ChannelEdgePolicy {
+	bandwidthHints map[uint64]lnwire.MilliSatoshi) *unifiedPolicyEdge {
 
 	if u.localChan {
 		return u.getPolicyLocal(amt, bandwidthHints)
@@ -151,10 +171,10 @@ func (u *unifiedPolicy) getPolicy(amt lnwire.MilliSatoshi,
 // getPolicyLocal returns the optimal policy to use for this local connection
 // given a specific amount to send.
 func (u *unifiedPolicy) getPolicyLocal(amt lnwire.MilliSatoshi,
-	bandwidthHints map[uint64]lnwire.MilliSatoshi) *channeldb.ChannelEdgePolicy {
+	bandwidthHints map[uint64]lnwire.MilliSatoshi) *unifiedPolicyEdge {
 
 	var (
-		bestPolicy   *channeldb.ChannelEdgePolicy
+		bestPolicy   *unifiedPolicyEdge
 		maxBandwidth lnwire.MilliSatoshi
 	)
 
@@ -196,7 +216,7 @@ func (u *unifiedPolicy) getPolicyLocal(amt lnwire.MilliSatoshi,
 		maxBandwidth = bandwidth
 
 		// Update best policy.
-		bestPolicy = edge.policy
+		bestPolicy = edge
 	}
 
 	return bestPolicy
@@ -206,11 +226,11 @@ func (u *unifiedPolicy) getPolicyLocal(amt lnwire.MilliSatoshi,
 // a specific amount to send. The goal is to return a policy that maximizes the
 // probability of a successful forward in a non-strict forwarding context.
 func (u *unifiedPolicy) getPolicyNetwork(
-	amt lnwire.MilliSatoshi) *channeldb.ChannelEdgePolicy {
+	amt lnwire.MilliSatoshi) *unifiedPolicyEdge {
 
 	var (
-		bestPolicy  *channeldb.ChannelEdgePolicy
-		maxFee      lnwire.MilliSatoshi
+		bestPolicy  *unifiedPolicyEdge
+		maxFee      int64
 		maxTimelock uint16
 	)
 
@@ -234,14 +254,16 @@ func (u *unifiedPolicy) getPolicyNetwork(
 		}
 
 		// Use the policy that results in the highest fee for this
-		// specific amount.
-		fee := edge.policy.ComputeFee(amt)
-		if fee < maxFee {
+		// specific amount. The inbound fee of the to node differs per
+		// channel, so it is taken into account as well.
+		fee := int64(edge.policy.ComputeFee(amt)) +
+			edge.inboundFee.CalcFee(amt)
+		if bestPolicy != nil && fee < maxFee {
 			continue
 		}
 		maxFee = fee
 
-		bestPolicy = edge.policy
+		bestPolicy = edge
 	}
 
 	// Return early if no channel matches.
@@ -259,10 +281,14 @@ func (u *unifiedPolicy) getPolicyNetwork(
 	// get forwarded. Because we penalize pair-wise, there won't be a second
 	// chance for this node pair. But this is all only needed for nodes that
 	// have distinct policies for channels to the same peer.
-	modifiedPolicy := *bestPolicy
+	modifiedPolicy := *bestPolicy.policy
 	modifiedPolicy.TimeLockDelta = maxTimelock
 
-	return &modifiedPolicy
+	return &unifiedPolicyEdge{
+		policy:     &modifiedPolicy,
+		capacity:   bestPolicy.capacity,
+		inboundFee: bestPolicy.inboundFee,
+	}
 }
 
 // minAmt returns the minimum amount that can be forwarded on this connection.
@@ -36,15 +36,17 @@ func TestUnifiedPolicies(t *testing.T) {
 		MaxHTLC:                   400,
 		MinHTLC:                   100,
 	}
-	u.addPolicy(fromNode, &p1, 7)
-	u.addPolicy(fromNode, &p2, 7)
+	u.addPolicy(fromNode, &p1, lnwire.Fee{}, 7)
+	u.addPolicy(fromNode, &p2, lnwire.Fee{}, 7)
 
-	checkPolicy := func(policy *channeldb.ChannelEdgePolicy,
+	checkPolicy := func(edge *unifiedPolicyEdge,
 		feeBase lnwire.MilliSatoshi, feeRate lnwire.MilliSatoshi,
 		timeLockDelta uint16) {
 
 		t.Helper()
 
+		policy := edge.policy
+
 		if policy.FeeBaseMSat != feeBase {
 			t.Fatalf("expected fee base %v, got %v",
 				feeBase, policy.FeeBaseMSat)
@@ -89,3 +91,39 @@ func TestUnifiedPolicies(t *testing.T) {
 		p1.TimeLockDelta,
 	)
 }
+
+// TestUnifiedPoliciesInboundFee tests that the inbound fee of the to node is
+// taken into account when picking the policy for a set of network channels.
+func TestUnifiedPoliciesInboundFee(t *testing.T) {
+	source := route.Vertex{1}
+	toNode := route.Vertex{2}
+	fromNode := route.Vertex{3}
+
+	u := newUnifiedPolicies(source, toNode, nil)
+
+	// Add two channels with the same outbound policy, but a different
+	// inbound fee charged by the to node.
+	p1 := channeldb.ChannelEdgePolicy{
+		ChannelID:     1,
+		FeeBaseMSat:   30,
+		TimeLockDelta: 40,
+	}
+	p2 := p1
+	p2.ChannelID = 2
+
+	u.addPolicy(fromNode, &p1, lnwire.Fee{BaseFee: -20}, 7)
+	u.addPolicy(fromNode, &p2, lnwire.Fee{BaseFee: 10}, 7)
+
+	// The channel with the higher total fee must be selected, together
+	// with its inbound fee.
+	edge := u.policies[fromNode].getPolicy(100, nil)
+	if edge == nil {
+		t.Fatal("expected policy")
+	}
+	if edge.policy.ChannelID != 2 {
+		t.Fatalf("expected channel 2, got %v", edge.policy.ChannelID)
+	}
+	if edge.inboundFee != (lnwire.Fee{BaseFee: 10}) {
+		t.Fatalf("unexpected inbound fee %v", edge.inboundFee)
+	}
+}
@@ -18,13 +18,14 @@ import (
 	"time"
 
 	"github.com/btcsuite/btcd/blockchain"
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/psbt"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
-	"github.com/btcsuite/btcutil/psbt"
 	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/wallet/txauthor"
 	"github.com/davecgh/go-spew/spew"
@@ -59,8 +60,10 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwallet/chancloser"
 	"github.com/lightningnetwork/lnd/lnwallet/chanfunding"
+	"github.com/lightningnetwork/lnd/lnwallet/chansplicer"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/macaroons"
+	"github.com/lightningnetwork/lnd/offers"
 	"github.com/lightningnetwork/lnd/peer"
 	"github.com/lightningnetwork/lnd/peernotifier"
 	"github.com/lightningnetwork/lnd/record"
@@ -326,6 +329,25 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "offchain",
 			Action: "write",
 		}},
+		"/lnrpc.Lightning/SpliceChannel": {{
+			Entity: "onchain",
+			Action: "write",
+		}, {
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/QuiesceChannel": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/UnquiesceChannel": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/UpgradeChannel": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
 		"/lnrpc.Lightning/GetInfo": {{
 			Entity: "info",
 			Action: "read",
@@ -521,6 +543,30 @@ func MainRPCServerPermissions() map[string][]bakery.Op {
 			Entity: "peers",
 			Action: "read",
 		}},
+		"/lnrpc.Lightning/SendCustomMessage": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SubscribeCustomMessages": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/SendOnionMessage": {{
+			Entity: "offchain",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/SubscribeOnionMessages": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
+		"/lnrpc.Lightning/AddOffer": {{
+			Entity: "invoices",
+			Action: "write",
+		}},
+		"/lnrpc.Lightning/DecodeOffer": {{
+			Entity: "offchain",
+			Action: "read",
+		}},
 		"/lnrpc.Lightning/FundingStateStep": {{
 			Entity: "onchain",
 			Action: "write",
@@ -669,6 +715,7 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 		DefaultFinalCltvDelta:  uint16(r.cfg.Bitcoin.TimeLockDelta),
 		SubscribeHtlcEvents:    s.htlcNotifier.SubscribeHtlcEvents,
 		InterceptableForwarder: s.interceptableSwitch,
+		FetchOfferInvoice:      s.FetchOfferInvoice,
 		SetChannelEnabled: func(outpoint wire.OutPoint) error {
 			return s.chanStatusMgr.RequestEnable(outpoint, true)
 		},
@@ -701,7 +748,7 @@ func (r *rpcServer) addDeps(s *server, macService *macaroons.Service,
 		routerBackend, s.nodeSigner, s.graphDB, s.chanStateDB,
 		s.sweeper, tower, s.towerClient, s.anchorTowerClient,
 		r.cfg.net.ResolveTCPAddr, genInvoiceFeatures,
-		genAmpInvoiceFeatures, rpcsLog,
+		genAmpInvoiceFeatures, s.aliasMgr.GetPeerAlias, rpcsLog,
 	)
 	if err != nil {
 		return err
@@ -1185,7 +1232,7 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 	// accidentally tried to send funds to a bare pubkey address. This check is
 	// here to prevent unintended transfers.
 	decodedAddr, _ := hex.DecodeString(in.Addr)
-	_, err = btcec.ParsePubKey(decodedAddr, btcec.S256())
+	_, err = btcec.ParsePubKey(decodedAddr)
 	if err == nil {
 		return nil, fmt.Errorf("cannot send coins to pubkeys")
 	}
@@ -1453,6 +1500,22 @@ func (r *rpcServer) NewAddress(ctx context.Context,
 		if err != nil {
 			return nil, err
 		}
+
+	case lnrpc.AddressType_TAPROOT_PUBKEY:
+		addr, err = r.server.cc.Wallet.NewAddress(
+			lnwallet.TaprootPubkey, false, account,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+	case lnrpc.AddressType_UNUSED_TAPROOT_PUBKEY:
+		addr, err = r.server.cc.Wallet.LastUnusedAddress(
+			lnwallet.TaprootPubkey, account,
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	rpcsLog.Debugf("[newaddress] account=%v type=%v addr=%v", account,
@@ -1480,7 +1543,7 @@ func (r *rpcServer) SignMessage(ctx context.Context,
 	}
 
 	in.Msg = append(signedMsgPrefix, in.Msg...)
-	sigBytes, err := r.server.nodeSigner.SignCompact(in.Msg)
+	sigBytes, err := r.server.nodeSigner.SignMessageCompact(in.Msg, true)
 	if err != nil {
 		return nil, err
 	}
@@ -1511,7 +1574,7 @@ func (r *rpcServer) VerifyMessage(ctx context.Context,
 	digest := chainhash.DoubleHashB(in.Msg)
 
 	// RecoverCompact both recovers the pubkey and validates the signature.
-	pubKey, _, err := btcec.RecoverCompact(btcec.S256(), sig, digest)
+	pubKey, _, err := ecdsa.RecoverCompact(sig, digest)
 	if err != nil {
 		return &lnrpc.VerifyMessageResponse{Valid: false}, nil
 	}
@@ -1554,7 +1617,7 @@ func (r *rpcServer) ConnectPeer(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
-	pubKey, err := btcec.ParsePubKey(pubkeyHex, btcec.S256())
+	pubKey, err := btcec.ParsePubKey(pubkeyHex)
 	if err != nil {
 		return nil, err
 	}
@@ -1623,7 +1686,7 @@ func (r *rpcServer) DisconnectPeer(ctx context.Context,
 	if err != nil {
 		return nil, fmt.Errorf("unable to decode pubkey bytes: %v", err)
 	}
-	peerPubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	peerPubKey, err := btcec.ParsePubKey(pubKeyBytes)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse pubkey: %v", err)
 	}
@@ -1690,17 +1753,13 @@ func newFundingShimAssembler(chanPointShim *lnrpc.ChanPointShim, initiator bool,
 
 	// Next we'll parse out the remote party's funding key, as well as our
 	// full key descriptor.
-	remoteKey, err := btcec.ParsePubKey(
-		chanPointShim.RemoteKey, btcec.S256(),
-	)
+	remoteKey, err := btcec.ParsePubKey(chanPointShim.RemoteKey)
 	if err != nil {
 		return nil, err
 	}
 
 	shimKeyDesc := chanPointShim.LocalKey
-	localKey, err := btcec.ParsePubKey(
-		shimKeyDesc.RawKeyBytes, btcec.S256(),
-	)
+	localKey, err := btcec.ParsePubKey(shimKeyDesc.RawKeyBytes)
 	if err != nil {
 		return nil, err
 	}
@@ -1833,6 +1892,19 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 			"initial state must be below the local funding amount")
 	}
 
+	// A dual funded channel can't be combined with pushing funds to the
+	// remote party, as it contributes its own funds instead.
+	remoteFundingAmt := btcutil.Amount(in.RemoteFundingAmt)
+	switch {
+	case remoteFundingAmt < 0:
+		return nil, fmt.Errorf("remote funding amount must not be " +
+			"negative")
+
+	case remoteFundingAmt > 0 && remoteInitialBalance > 0:
+		return nil, fmt.Errorf("remote funding amount can't be " +
+			"combined with a push amount")
+	}
+
 	// Ensure that the user doesn't exceed the current soft-limit for
 	// channel size. If the funding amount is above the soft-limit, then
 	// we'll reject the request.
@@ -1879,7 +1951,7 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 	// Parse the raw bytes of the node key into a pubkey object so we can
 	// easily manipulate it.
 	case len(in.NodePubkey) > 0:
-		nodePubKey, err = btcec.ParsePubKey(in.NodePubkey, btcec.S256())
+		nodePubKey, err = btcec.ParsePubKey(in.NodePubkey)
 		if err != nil {
 			return nil, err
 		}
@@ -1893,7 +1965,7 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 			return nil, err
 		}
 
-		nodePubKey, err = btcec.ParsePubKey(keyBytes, btcec.S256())
+		nodePubKey, err = btcec.ParsePubKey(keyBytes)
 		if err != nil {
 			return nil, err
 		}
@@ -1955,6 +2027,52 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 			in.CommitmentType)
 	}
 
+	// Zero-conf channels are only supported with the anchors commitment
+	// type, as the opener needs to be able to bump the fee of the funding
+	// transaction if it doesn't confirm.
+	if in.ZeroConf {
+		if in.CommitmentType != lnrpc.CommitmentType_ANCHORS {
+			return nil, fmt.Errorf("zero-conf channels require " +
+				"the anchors commitment type")
+		}
+
+		(*lnwire.RawFeatureVector)(channelType).Set(
+			lnwire.ZeroConfRequired,
+		)
+	}
+
+	// The option-scid-alias channel type can only be used for private
+	// channels, with an explicitly chosen commitment type.
+	if in.ScidAlias {
+		if channelType == nil {
+			return nil, fmt.Errorf("scid-alias channels require " +
+				"an explicit commitment type")
+		}
+		if !in.Private {
+			return nil, fmt.Errorf("scid-alias channels must be " +
+				"private")
+		}
+
+		(*lnwire.RawFeatureVector)(channelType).Set(
+			lnwire.ScidAliasRequired,
+		)
+	}
+
+	// If we lease the remote party's contribution, we do so at the rates
+	// it advertises.
+	var leaseReq *lnwire.LeaseRequest
+	if in.LeaseDuration != 0 {
+		if remoteFundingAmt == 0 {
+			return nil, fmt.Errorf("lease duration requires a " +
+				"remote funding amount")
+		}
+
+		leaseReq, err = r.newLeaseRequest(nodePubKey, in.LeaseDuration)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Instruct the server to trigger the necessary events to attempt to
 	// open a new channel. A stream is returned in place, this stream will
 	// be used to consume updates of the state of the pending channel.
@@ -1962,6 +2080,8 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 		TargetPubkey:     nodePubKey,
 		ChainHash:        *r.cfg.ActiveNetParams.GenesisHash,
 		LocalFundingAmt:  localFundingAmt,
+		RemoteFundingAmt: remoteFundingAmt,
+		LeaseRequest:     leaseReq,
 		PushAmt:          lnwire.NewMSatFromSatoshis(remoteInitialBalance),
 		MinHtlcIn:        minHtlcIn,
 		FundingFeePerKw:  feeRate,
@@ -1976,6 +2096,56 @@ func (r *rpcServer) parseOpenChannelReq(in *lnrpc.OpenChannelRequest,
 	}, nil
 }
 
+// newLeaseRequest creates the request to lease the funds the given node
+// contributes to a channel for the given duration, at the lease rates the node
+// advertises in its node announcement.
+func (r *rpcServer) newLeaseRequest(nodePubKey *btcec.PublicKey,
+	duration uint32) (*lnwire.LeaseRequest, error) {
+
+	node, err := r.server.graphDB.FetchLightningNode(
+		nil, route.NewVertex(nodePubKey),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch node announcement: %v",
+			err)
+	}
+
+	rates, err := lnwire.LeaseRatesFromExtraData(node.ExtraOpaqueData)
+	switch {
+	case err != nil:
+		return nil, fmt.Errorf("unable to parse lease rates: %v", err)
+
+	case rates == nil:
+		return nil, fmt.Errorf("node doesn't advertise lease rates")
+
+	case duration > rates.MaxDuration:
+		return nil, fmt.Errorf("lease duration %v exceeds the "+
+			"maximum of %v", duration, rates.MaxDuration)
+	}
+
+	return &lnwire.LeaseRequest{
+		Duration: duration,
+		FeeBase:  rates.FeeBase,
+		FeeBasis: rates.FeeBasis,
+	}, nil
+}
+
+// marshalLeaseRates converts the lease rates carried within the extra data of
+// a node announcement into their RPC representation. Nil is returned if the
+// node doesn't advertise any, or they can't be parsed.
+func marshalLeaseRates(extra []byte) *lnrpc.LeaseRates {
+	rates, err := lnwire.LeaseRatesFromExtraData(extra)
+	if err != nil || rates == nil {
+		return nil
+	}
+
+	return &lnrpc.LeaseRates{
+		FeeBaseSat:  uint32(rates.FeeBase),
+		FeeBasis:    uint32(rates.FeeBasis),
+		MaxDuration: rates.MaxDuration,
+	}
+}
+
 // OpenChannel attempts to open a singly funded channel specified in the
 // request to a remote peer.
 func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
@@ -2225,6 +2395,14 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 		return fmt.Errorf("force closing a channel uses a pre-defined fee")
 	}
 
+	// A fee bump replaces the closing transaction of a cooperative close
+	// that is already in progress, which pays out to the delivery
+	// addresses that were agreed on before.
+	if in.Bump && (in.Force || len(in.DeliveryAddress) > 0) {
+		return fmt.Errorf("bumping the fee of a cooperative close can't " +
+			"be combined with force or a delivery address")
+	}
+
 	force := in.Force
 	index := in.ChannelPoint.OutputIndex
 	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
@@ -2271,11 +2449,12 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 		return err
 	}
 
+	switch {
 	// If a force closure was requested, then we'll handle all the details
 	// around the creation and broadcast of the unilateral closure
 	// transaction here rather than going to the switch as we don't require
 	// interaction from the peer.
-	if force {
+	case force:
 
 		// As we're force closing this channel, as a precaution, we'll
 		// ensure that the switch doesn't continue to see this channel
@@ -2323,7 +2502,45 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 					Success:     true,
 				}
 			})
-	} else {
+
+	// If a fee bump was requested, the replaceable closing transaction of
+	// the channel is replaced by one paying a higher fee. As the channel
+	// is no longer active, the request is handed to the peer directly
+	// rather than going through the switch.
+	case in.Bump:
+		if !channel.HasChanStatus(channeldb.ChanStatusCoopBroadcasted) {
+			return fmt.Errorf("channel is not being closed " +
+				"cooperatively")
+		}
+
+		feeRate, err := calculateFeeRate(
+			uint64(in.SatPerByte), in.SatPerVbyte, // nolint:staticcheck
+			uint32(in.TargetConf), r.server.cc.FeeEstimator,
+		)
+		if err != nil {
+			return err
+		}
+
+		remotePeer, err := r.server.FindPeer(channel.IdentityPub)
+		if err != nil {
+			return fmt.Errorf("unable to bump close fee while peer "+
+				"is offline: %v", err)
+		}
+
+		rpcsLog.Debugf("Target sat/kw for replacement closing "+
+			"transaction: %v", int64(feeRate))
+
+		updateChan = make(chan interface{}, 2)
+		errChan = make(chan error, 1)
+		remotePeer.HandleLocalCloseChanReqs(&htlcswitch.ChanClose{
+			CloseType:      htlcswitch.CloseBumpFee,
+			ChanPoint:      chanPoint,
+			TargetFeePerKw: feeRate,
+			Updates:        updateChan,
+			Err:            errChan,
+		})
+
+	default:
 		// If this is a frozen channel, then we only allow the co-op
 		// close to proceed if we were the responder to this channel if
 		// the absolute thaw height has not been met.
@@ -2340,6 +2557,15 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 			}
 		}
 
+		// If we leased out our contribution to this channel, we only
+		// allow the co-op close to proceed once the lease expired.
+		leaseExpiry := channel.LeaseExpiry()
+		if !channel.IsInitiator && uint32(bestHeight) < leaseExpiry {
+			return fmt.Errorf("cannot co-op close leased channel "+
+				"as lessor until height=%v, "+
+				"(current_height=%v)", leaseExpiry, bestHeight)
+		}
+
 		// If the link is not known by the switch, we cannot gracefully close
 		// the channel.
 		channelID := lnwire.NewChanIDFromOutPoint(chanPoint)
@@ -2526,6 +2752,303 @@ func (r *rpcServer) abandonChan(chanPoint *wire.OutPoint,
 	return nil
 }
 
+// SpliceChannel adds funds from the wallet to an active channel, or moves
+// funds out of it, without closing the channel. The call returns once
+// the splice transaction has been broadcast.
+func (r *rpcServer) SpliceChannel(ctx context.Context,
+	in *lnrpc.SpliceChannelRequest) (*lnrpc.SpliceChannelResponse, error) {
+
+	if !r.server.Started() {
+		return nil, ErrServerNotActive
+	}
+
+	if in.GetChannelPoint() == nil {
+		return nil, fmt.Errorf("must specify channel point in splice " +
+			"channel")
+	}
+	if in.Amount == 0 {
+		return nil, fmt.Errorf("must specify amount to splice")
+	}
+
+	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	rpcsLog.Tracef("[splicechannel] request for ChannelPoint(%v), "+
+		"amount=%v", chanPoint, in.Amount)
+
+	channel, err := r.server.chanStateDB.FetchChannel(nil, *chanPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	peer, err := r.server.FindPeer(channel.IdentityPub)
+	if err != nil {
+		return nil, fmt.Errorf("unable to splice channel while peer "+
+			"is offline: %v", err)
+	}
+
+	feeRate, err := calculateFeeRate(
+		0, in.SatPerVbyte, uint32(in.TargetConf),
+		r.server.cc.FeeEstimator,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Funds spliced out of the channel are sent to the passed delivery
+	// address, or a new wallet address if none was specified.
+	var deliveryScript lnwire.DeliveryAddress
+	switch {
+	case in.Amount > 0 && len(in.DeliveryAddress) > 0:
+		return nil, fmt.Errorf("delivery address can only be set " +
+			"when splicing out")
+
+	case in.Amount < 0:
+		var addr btcutil.Address
+		if len(in.DeliveryAddress) > 0 {
+			addr, err = btcutil.DecodeAddress(
+				in.DeliveryAddress, r.cfg.ActiveNetParams.Params,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("invalid delivery "+
+					"address: %v", err)
+			}
+		} else {
+			addr, err = r.server.cc.Wallet.NewAddress(
+				lnwallet.WitnessPubKey, false,
+				lnwallet.DefaultAccountName,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		deliveryScript, err = txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	req := &chansplicer.SpliceRequest{
+		ChanPoint:      chanPoint,
+		Amount:         btcutil.Amount(in.Amount),
+		DeliveryScript: deliveryScript,
+		FeePerKw:       feeRate,
+		Updates:        make(chan *wire.MsgTx, 1),
+		Err:            make(chan error, 1),
+	}
+	peer.HandleLocalSpliceReq(req)
+
+	select {
+	case spliceTx := <-req.Updates:
+		spliceTxid := spliceTx.TxHash()
+
+		rpcsLog.Infof("[splicechannel] splice of ChannelPoint(%v) "+
+			"broadcast: txid(%v)", chanPoint, spliceTxid)
+
+		// The splice has been persisted before the splice transaction
+		// was broadcast, so we can look up the new funding outpoint.
+		splice, err := channel.PendingSplice()
+		if err != nil {
+			return nil, err
+		}
+
+		resp := &lnrpc.SpliceChannelResponse{
+			SpliceTxid: spliceTxid[:],
+			FundingPoint: &lnrpc.ChannelPoint{
+				FundingTxid: &lnrpc.ChannelPoint_FundingTxidBytes{
+					FundingTxidBytes: spliceTxid[:],
+				},
+				OutputIndex: splice.FundingOutpoint.Index,
+			},
+		}
+
+		return resp, nil
+
+	case err := <-req.Err:
+		rpcsLog.Errorf("[splicechannel] unable to splice "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+		return nil, err
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	case <-r.quit:
+		return nil, fmt.Errorf("server shutting down")
+	}
+}
+
+// QuiesceChannel pauses all updates of an active channel until it is resumed
+// with UnquiesceChannel or the peers reconnect. The call returns once the
+// channel is quiescent.
+func (r *rpcServer) QuiesceChannel(ctx context.Context,
+	in *lnrpc.QuiesceChannelRequest) (*lnrpc.QuiesceChannelResponse,
+	error) {
+
+	if !r.server.Started() {
+		return nil, ErrServerNotActive
+	}
+
+	if in.GetChannelPoint() == nil {
+		return nil, fmt.Errorf("must specify channel point to " +
+			"quiesce")
+	}
+
+	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	rpcsLog.Tracef("[quiescechannel] request for ChannelPoint(%v)",
+		chanPoint)
+
+	chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+	link, err := r.server.htlcSwitch.GetLink(chanID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to quiesce ChannelPoint(%v), "+
+			"channel is not active: %v", chanPoint, err)
+	}
+
+	// The request is canceled if the caller goes away or we shut down.
+	cancel := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-r.quit:
+		}
+		close(cancel)
+	}()
+
+	initiator, err := link.Quiesce(cancel)
+	if err != nil {
+		rpcsLog.Errorf("[quiescechannel] unable to quiesce "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+		return nil, err
+	}
+
+	rpcsLog.Infof("[quiescechannel] ChannelPoint(%v) is quiescent, "+
+		"initiator=%v", chanPoint, initiator)
+
+	return &lnrpc.QuiesceChannelResponse{
+		Initiator: initiator,
+	}, nil
+}
+
+// UnquiesceChannel resumes normal operation of a channel that we quiesced with
+// QuiesceChannel.
+func (r *rpcServer) UnquiesceChannel(_ context.Context,
+	in *lnrpc.UnquiesceChannelRequest) (*lnrpc.UnquiesceChannelResponse,
+	error) {
+
+	if !r.server.Started() {
+		return nil, ErrServerNotActive
+	}
+
+	if in.GetChannelPoint() == nil {
+		return nil, fmt.Errorf("must specify channel point to " +
+			"unquiesce")
+	}
+
+	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	rpcsLog.Tracef("[unquiescechannel] request for ChannelPoint(%v)",
+		chanPoint)
+
+	chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+	link, err := r.server.htlcSwitch.GetLink(chanID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unquiesce ChannelPoint(%v), "+
+			"channel is not active: %v", chanPoint, err)
+	}
+
+	if err := link.Unquiesce(); err != nil {
+		rpcsLog.Errorf("[unquiescechannel] unable to unquiesce "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+		return nil, err
+	}
+
+	rpcsLog.Infof("[unquiescechannel] ChannelPoint(%v) resumed", chanPoint)
+
+	return &lnrpc.UnquiesceChannelResponse{}, nil
+}
+
+// UpgradeChannel upgrades the commitment type of an active channel in place.
+// The call returns once both peers committed to the new commitment type.
+func (r *rpcServer) UpgradeChannel(ctx context.Context,
+	in *lnrpc.UpgradeChannelRequest) (*lnrpc.UpgradeChannelResponse,
+	error) {
+
+	if !r.server.Started() {
+		return nil, ErrServerNotActive
+	}
+
+	if in.GetChannelPoint() == nil {
+		return nil, fmt.Errorf("must specify channel point to " +
+			"upgrade")
+	}
+
+	var commitType lnwallet.CommitmentType
+	switch in.CommitmentType {
+	case lnrpc.CommitmentType_LEGACY:
+		commitType = lnwallet.CommitmentTypeLegacy
+
+	case lnrpc.CommitmentType_STATIC_REMOTE_KEY:
+		commitType = lnwallet.CommitmentTypeTweakless
+
+	case lnrpc.CommitmentType_ANCHORS:
+		commitType = lnwallet.CommitmentTypeAnchorsZeroFeeHtlcTx
+
+	default:
+		return nil, fmt.Errorf("unhandled request channel type %v",
+			in.CommitmentType)
+	}
+
+	txid, err := lnrpc.GetChanPointFundingTxid(in.GetChannelPoint())
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	rpcsLog.Tracef("[upgradechannel] request for ChannelPoint(%v) to "+
+		"commitment type %v", chanPoint, commitType)
+
+	chanID := lnwire.NewChanIDFromOutPoint(chanPoint)
+	link, err := r.server.htlcSwitch.GetLink(chanID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to upgrade ChannelPoint(%v), "+
+			"channel is not active: %v", chanPoint, err)
+	}
+
+	// The request is canceled if the caller goes away or we shut down.
+	cancel := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-r.quit:
+		}
+		close(cancel)
+	}()
+
+	if err := link.UpgradeCommitType(commitType, cancel); err != nil {
+		rpcsLog.Errorf("[upgradechannel] unable to upgrade "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+		return nil, err
+	}
+
+	rpcsLog.Infof("[upgradechannel] ChannelPoint(%v) upgraded to "+
+		"commitment type %v", chanPoint, commitType)
+
+	return &lnrpc.UpgradeChannelResponse{}, nil
+}
+
 // AbandonChannel removes all channel state from the database except for a
 // close summary. This method can be used to get rid of permanently unusable
 // channels due to bugs fixed in newer versions of lnd.
@@ -2963,6 +3486,12 @@ func (r *rpcServer) WalletBalance(ctx context.Context,
 		map[string]*lnrpc.WalletAccountBalance, len(accounts),
 	)
 	for _, account := range accounts {
+		// The accounts of our internal key scope only hold the keys of
+		// the key families and never any on-chain funds.
+		if account.KeyScope.Purpose == keychain.BIP0043Purpose {
+			continue
+		}
+
 		// There are two default accounts, one for NP2WKH outputs and
 		// another for P2WKH outputs. The balance will be computed for
 		// both given one call to ConfirmedBalance with the default
@@ -3628,12 +4157,13 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 		}
 
 		channelID := lnwire.NewChanIDFromOutPoint(&chanPoint)
-		var linkActive bool
+		var linkActive, linkQuiescent bool
 		if link, err := r.server.htlcSwitch.GetLink(channelID); err == nil {
 			// A channel is only considered active if it is known
 			// by the switch *and* able to forward
 			// incoming/outgoing payments.
 			linkActive = link.EligibleToForward()
+			linkQuiescent = link.IsQuiescent()
 		}
 
 		// Next, we'll determine whether we should add this channel to
@@ -3643,6 +4173,7 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 		if err != nil {
 			return nil, err
 		}
+		channel.Quiescent = linkQuiescent
 
 		// We'll only skip returning this channel if we were requested
 		// for a specific kind and this channel doesn't satisfy it.
@@ -3762,12 +4293,41 @@ func createRPCOpenChannel(r *rpcServer, graph *channeldb.ChannelGraph,
 		RemoteConstraints: createChannelConstraint(
 			&dbChannel.RemoteChanCfg,
 		),
+		ZeroConf: dbChannel.IsZeroConf(),
 		// TODO: remove the following deprecated fields
 		CsvDelay:             uint32(dbChannel.LocalChanCfg.CsvDelay),
 		LocalChanReserveSat:  int64(dbChannel.LocalChanCfg.ChanReserve),
 		RemoteChanReserveSat: int64(dbChannel.RemoteChanCfg.ChanReserve),
 	}
 
+	// Populate the set of aliases of the channel, as well as the alias our
+	// peer sent us for it.
+	for _, alias := range r.server.aliasMgr.GetAliases(
+		dbChannel.ShortChannelID,
+	) {
+
+		channel.AliasScids = append(channel.AliasScids, alias.ToUint64())
+	}
+
+	chanID := lnwire.NewChanIDFromOutPoint(&chanPoint)
+	if peerAlias, err := r.server.aliasMgr.GetPeerAlias(chanID); err == nil {
+		channel.PeerScidAlias = peerAlias.ToUint64()
+	}
+
+	if dbChannel.IsZeroConf() {
+		channel.ZeroConfConfirmedScid = dbChannel.ConfirmedScid().
+			ToUint64()
+	}
+
+	if lease := dbChannel.Lease; lease != nil {
+		channel.Lease = &lnrpc.ChannelLease{
+			Duration:     lease.Duration,
+			AmountSat:    int64(lease.Amount),
+			FeeSat:       int64(lease.Fee),
+			ExpiryHeight: dbChannel.LeaseExpiry(),
+		}
+	}
+
 	for i, htlc := range localCommit.Htlcs {
 		var rHash [32]byte
 		copy(rHash[:], htlc.RHash[:])
@@ -4977,26 +5537,7 @@ func (r *rpcServer) sendPaymentSync(ctx context.Context,
 func (r *rpcServer) AddInvoice(ctx context.Context,
 	invoice *lnrpc.Invoice) (*lnrpc.AddInvoiceResponse, error) {
 
-	defaultDelta := r.cfg.Bitcoin.TimeLockDelta
-	if r.cfg.registeredChains.PrimaryChain() == chainreg.LitecoinChain {
-		defaultDelta = r.cfg.Litecoin.TimeLockDelta
-	}
-
-	addInvoiceCfg := &invoicesrpc.AddInvoiceConfig{
-		AddInvoice:        r.server.invoices.AddInvoice,
-		IsChannelActive:   r.server.htlcSwitch.HasActiveLink,
-		ChainParams:       r.cfg.ActiveNetParams.Params,
-		NodeSigner:        r.server.nodeSigner,
-		DefaultCLTVExpiry: defaultDelta,
-		ChanDB:            r.server.chanStateDB,
-		Graph:             r.server.graphDB,
-		GenInvoiceFeatures: func() *lnwire.FeatureVector {
-			return r.server.featureMgr.Get(feature.SetInvoice)
-		},
-		GenAmpInvoiceFeatures: func() *lnwire.FeatureVector {
-			return r.server.featureMgr.Get(feature.SetInvoiceAmp)
-		},
-	}
+	addInvoiceCfg := r.server.newAddInvoiceConfig()
 
 	value, err := lnrpc.UnmarshallAmt(invoice.Value, invoice.ValueMsat)
 	if err != nil {
@@ -5018,6 +5559,7 @@ func (r *rpcServer) AddInvoice(ctx context.Context,
 		Private:         invoice.Private,
 		RouteHints:      routeHints,
 		Amp:             invoice.IsAmp,
+		Blind:           invoice.Blind,
 	}
 
 	if invoice.RPreimage != nil {
@@ -5323,6 +5865,7 @@ func (r *rpcServer) DescribeGraph(ctx context.Context,
 			Alias:      node.Alias,
 			Color:      routing.EncodeHexColor(node.Color),
 			Features:   invoicesrpc.CreateRPCFeatures(node.Features),
+			LeaseRates: marshalLeaseRates(node.ExtraOpaqueData),
 		}
 
 		resp.Nodes = append(resp.Nodes, lnNode)
@@ -5389,32 +5932,42 @@ func marshalDbEdge(edgeInfo *channeldb.ChannelEdgeInfo,
 	}
 
 	if c1 != nil {
-		edge.Node1Policy = &lnrpc.RoutingPolicy{
-			TimeLockDelta:    uint32(c1.TimeLockDelta),
-			MinHtlc:          int64(c1.MinHTLC),
-			MaxHtlcMsat:      uint64(c1.MaxHTLC),
-			FeeBaseMsat:      int64(c1.FeeBaseMSat),
-			FeeRateMilliMsat: int64(c1.FeeProportionalMillionths),
-			Disabled:         c1.ChannelFlags&lnwire.ChanUpdateDisabled != 0,
-			LastUpdate:       uint32(c1.LastUpdate.Unix()),
-		}
+		edge.Node1Policy = marshalDBRoutingPolicy(c1)
 	}
 
 	if c2 != nil {
-		edge.Node2Policy = &lnrpc.RoutingPolicy{
-			TimeLockDelta:    uint32(c2.TimeLockDelta),
-			MinHtlc:          int64(c2.MinHTLC),
-			MaxHtlcMsat:      uint64(c2.MaxHTLC),
-			FeeBaseMsat:      int64(c2.FeeBaseMSat),
-			FeeRateMilliMsat: int64(c2.FeeProportionalMillionths),
-			Disabled:         c2.ChannelFlags&lnwire.ChanUpdateDisabled != 0,
-			LastUpdate:       uint32(c2.LastUpdate.Unix()),
-		}
+		edge.Node2Policy = marshalDBRoutingPolicy(c2)
 	}
 
 	return edge
 }
 
+// marshalDBRoutingPolicy converts a channel edge policy into its RPC
+// representation.
+func marshalDBRoutingPolicy(
+	policy *channeldb.ChannelEdgePolicy) *lnrpc.RoutingPolicy {
+
+	rpcPolicy := &lnrpc.RoutingPolicy{
+		TimeLockDelta:    uint32(policy.TimeLockDelta),
+		MinHtlc:          int64(policy.MinHTLC),
+		MaxHtlcMsat:      uint64(policy.MaxHTLC),
+		FeeBaseMsat:      int64(policy.FeeBaseMSat),
+		FeeRateMilliMsat: int64(policy.FeeProportionalMillionths),
+		Disabled:         policy.ChannelFlags&lnwire.ChanUpdateDisabled != 0,
+		LastUpdate:       uint32(policy.LastUpdate.Unix()),
+	}
+
+	// The inbound fee is optional and carried in the extra opaque data of
+	// the policy. A malformed record is not reported.
+	inboundFee, _, err := lnwire.ExtractInboundFee(policy.ExtraOpaqueData)
+	if err == nil {
+		rpcPolicy.InboundFeeBaseMsat = inboundFee.BaseFee
+		rpcPolicy.InboundFeeRateMilliMsat = inboundFee.FeeRate
+	}
+
+	return rpcPolicy
+}
+
 // GetNodeMetrics returns all available node metrics calculated from the
 // current channel graph.
 func (r *rpcServer) GetNodeMetrics(ctx context.Context,
@@ -5573,6 +6126,7 @@ func (r *rpcServer) GetNodeInfo(ctx context.Context,
 			Alias:      node.Alias,
 			Color:      routing.EncodeHexColor(node.Color),
 			Features:   features,
+			LeaseRates: marshalLeaseRates(node.ExtraOpaqueData),
 		},
 		NumChannels:   numChannels,
 		TotalCapacity: int64(totalCapacity),
@@ -6296,18 +6850,29 @@ func (r *rpcServer) UpdateChannelPolicy(ctx context.Context,
 		minHtlc = &min
 	}
 
+	// The inbound fee may be negative to give a discount or positive to
+	// charge a surcharge for htlcs that arrive over the channel.
+	var inboundFee *lnwire.Fee
+	if req.InboundFee != nil {
+		inboundFee = &lnwire.Fee{
+			BaseFee: req.InboundFee.BaseFeeMsat,
+			FeeRate: req.InboundFee.FeeRatePpm,
+		}
+	}
+
 	chanPolicy := routing.ChannelPolicy{
 		FeeSchema:     feeSchema,
 		TimeLockDelta: req.TimeLockDelta,
 		MaxHTLC:       maxHtlc,
 		MinHTLC:       minHtlc,
+		InboundFee:    inboundFee,
 	}
 
 	rpcsLog.Debugf("[updatechanpolicy] updating channel policy base_fee=%v, "+
 		"rate_float=%v, rate_fixed=%v, time_lock_delta: %v, "+
-		"min_htlc=%v, max_htlc=%v, targets=%v",
+		"min_htlc=%v, max_htlc=%v, inbound_fee=%v, targets=%v",
 		req.BaseFeeMsat, req.FeeRate, feeRateFixed, req.TimeLockDelta,
-		minHtlc, maxHtlc,
+		minHtlc, maxHtlc, inboundFee,
 		spew.Sdump(targetChans))
 
 	// With the scope resolved, we'll now send this to the local channel
@@ -7004,6 +7569,193 @@ func (r *rpcServer) CheckMacaroonPermissions(ctx context.Context,
 	}, nil
 }
 
+// SendCustomMessage sends a custom peer message.
+func (r *rpcServer) SendCustomMessage(ctx context.Context,
+	req *lnrpc.SendCustomMessageRequest) (*lnrpc.SendCustomMessageResponse,
+	error) {
+
+	peer, err := route.NewVertexFromBytes(req.Peer)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.server.SendCustomMessage(
+		peer, lnwire.MessageType(req.Type), req.Data,
+	)
+	switch {
+	case err == ErrPeerNotConnected:
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	return &lnrpc.SendCustomMessageResponse{}, nil
+}
+
+// SubscribeCustomMessages subscribes to a stream of incoming custom peer
+// messages.
+func (r *rpcServer) SubscribeCustomMessages(
+	req *lnrpc.SubscribeCustomMessagesRequest,
+	server lnrpc.Lightning_SubscribeCustomMessagesServer) error {
+
+	client, err := r.server.SubscribeCustomMessages()
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	for {
+		select {
+		case <-client.Quit():
+			return errors.New("shutdown")
+
+		case <-server.Context().Done():
+			return server.Context().Err()
+
+		case update := <-client.Updates():
+			customMsg := update.(*CustomMessage)
+
+			err := server.Send(&lnrpc.CustomMessage{
+				Peer: customMsg.Peer[:],
+				Data: customMsg.Msg.Data,
+				Type: uint32(customMsg.Msg.Type),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// SendOnionMessage sends an onion message along a path of nodes.
+func (r *rpcServer) SendOnionMessage(ctx context.Context,
+	req *lnrpc.SendOnionMessageRequest) (*lnrpc.SendOnionMessageResponse,
+	error) {
+
+	path := make([]*btcec.PublicKey, len(req.Path))
+	for i, nodeBytes := range req.Path {
+		nodePub, err := btcec.ParsePubKey(nodeBytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node key in path: %v",
+				err)
+		}
+		path[i] = nodePub
+	}
+
+	err := r.server.SendOnionMessage(path, req.Records)
+	switch {
+	case err == ErrPeerNotConnected:
+		return nil, status.Error(codes.NotFound, err.Error())
+	case err == ErrOnionMessagesDisabled:
+		return nil, status.Error(codes.Unimplemented, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	return &lnrpc.SendOnionMessageResponse{}, nil
+}
+
+// SubscribeOnionMessages subscribes to a stream of incoming onion messages
+// that are destined for us.
+func (r *rpcServer) SubscribeOnionMessages(
+	req *lnrpc.SubscribeOnionMessagesRequest,
+	server lnrpc.Lightning_SubscribeOnionMessagesServer) error {
+
+	client, err := r.server.SubscribeOnionMessages()
+	if err != nil {
+		return err
+	}
+	defer client.Cancel()
+
+	for {
+		select {
+		case <-client.Quit():
+			return errors.New("shutdown")
+
+		case <-server.Context().Done():
+			return server.Context().Err()
+
+		case update := <-client.Updates():
+			onionMsg := update.(*OnionMessage)
+
+			err := server.Send(&lnrpc.OnionMessage{
+				Peer:    onionMsg.Peer[:],
+				PathId:  onionMsg.PathID,
+				Records: onionMsg.CustomRecords,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// AddOffer creates a static offer that payers can pay repeatedly by
+// requesting a fresh invoice for each payment over onion messages.
+func (r *rpcServer) AddOffer(ctx context.Context,
+	req *lnrpc.AddOfferRequest) (*lnrpc.AddOfferResponse, error) {
+
+	if r.server.offerManager == nil {
+		return nil, status.Error(
+			codes.Unimplemented, ErrOnionMessagesDisabled.Error(),
+		)
+	}
+
+	offer, err := r.server.offerManager.CreateOffer(
+		req.Description, lnwire.MilliSatoshi(req.AmountMsat),
+		req.Issuer, time.Duration(req.Expiry)*time.Second,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, err := offer.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	offerID, err := offer.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("[addoffer] created offer %x", offerID)
+
+	return &lnrpc.AddOfferResponse{
+		Offer:   encoded,
+		OfferId: offerID[:],
+	}, nil
+}
+
+// DecodeOffer decodes an offer and verifies its signature.
+func (r *rpcServer) DecodeOffer(ctx context.Context,
+	req *lnrpc.DecodeOfferRequest) (*lnrpc.Offer, error) {
+
+	offer, err := offers.Decode(req.Offer)
+	if err != nil {
+		return nil, err
+	}
+
+	offerID, err := offer.ID()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcOffer := &lnrpc.Offer{
+		Description: offer.Description,
+		AmountMsat:  uint64(offer.Amount),
+		Issuer:      offer.Issuer,
+		NodeId:      offer.NodeID.SerializeCompressed(),
+		ChainHash:   offer.Chain[:],
+		OfferId:     offerID[:],
+	}
+	if !offer.AbsoluteExpiry.IsZero() {
+		rpcOffer.AbsoluteExpiry = offer.AbsoluteExpiry.Unix()
+	}
+
+	return rpcOffer, nil
+}
+
 // FundingStateStep is an advanced funding related call that allows the caller
 // to either execute some preparatory steps for a funding workflow, or manually
 // progress a funding workflow. The primary way a funding flow is identified is
@@ -17,14 +17,16 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/connmgr"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/go-errors/errors"
 	sphinx "github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/aliasmgr"
 	"github.com/lightningnetwork/lnd/autopilot"
 	"github.com/lightningnetwork/lnd/brontide"
 	"github.com/lightningnetwork/lnd/cert"
@@ -49,17 +51,21 @@ import (
 	"github.com/lightningnetwork/lnd/lncfg"
 	"github.com/lightningnetwork/lnd/lnpeer"
 	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnrpc/invoicesrpc"
 	"github.com/lightningnetwork/lnd/lnrpc/routerrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/nat"
 	"github.com/lightningnetwork/lnd/netann"
+	"github.com/lightningnetwork/lnd/offers"
 	"github.com/lightningnetwork/lnd/peer"
 	"github.com/lightningnetwork/lnd/peernotifier"
 	"github.com/lightningnetwork/lnd/pool"
 	"github.com/lightningnetwork/lnd/queue"
+	"github.com/lightningnetwork/lnd/record"
 	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
 	"github.com/lightningnetwork/lnd/routing/localchans"
 	"github.com/lightningnetwork/lnd/routing/route"
 	"github.com/lightningnetwork/lnd/subscribe"
@@ -71,6 +77,7 @@ import (
 	"github.com/lightningnetwork/lnd/watchtower/wtclient"
 	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
 	"github.com/lightningnetwork/lnd/watchtower/wtserver"
+	"github.com/lightningnetwork/lnd/zpay32"
 )
 
 const (
@@ -95,6 +102,10 @@ const (
 	// value used or a particular peer will be chosen between 0s and this
 	// value.
 	maxInitReconnectDelay = 30
+
+	// maxOnionMessageHops is the maximum number of hops of the paths over
+	// which we send our own onion messages.
+	maxOnionMessageHops = 10
 )
 
 var (
@@ -111,6 +122,11 @@ var (
 	// gracefully exiting.
 	ErrServerShuttingDown = errors.New("server is shutting down")
 
+	// ErrOnionMessagesDisabled is returned when an onion message is sent
+	// while we don't signal support for onion messages.
+	ErrOnionMessagesDisabled = errors.New("onion messages are disabled, " +
+		"set protocol.onion-messages to enable them")
+
 	// validColorRegexp is a regexp that lets you check if a particular
 	// color string matches the standard hex color format #RRGGBB.
 	validColorRegexp = regexp.MustCompile("^#[A-Fa-f0-9]{6}$")
@@ -157,6 +173,9 @@ type server struct {
 	// to authenticate any incoming connections.
 	identityECDH keychain.SingleKeyECDH
 
+	// identityKeyLoc is the key locator for the above wrapped identity key.
+	identityKeyLoc keychain.KeyLocator
+
 	// nodeSigner is an implementation of the MessageSigner implementation
 	// that's backed by the identity private key of the running lnd node.
 	nodeSigner *netann.NodeSigner
@@ -226,6 +245,10 @@ type server struct {
 
 	htlcSwitch *htlcswitch.Switch
 
+	// aliasMgr keeps track of the alias short channel IDs of our channels
+	// and of the aliases our peers sent us.
+	aliasMgr *aliasmgr.Manager
+
 	interceptableSwitch *htlcswitch.InterceptableSwitch
 
 	invoices *invoices.InvoiceRegistry
@@ -234,6 +257,24 @@ type server struct {
 
 	peerNotifier *peernotifier.PeerNotifier
 
+	// customMessageServer is a subscription server that distributes the
+	// custom messages received from our peers to their subscribers.
+	customMessageServer *subscribe.Server
+
+	// onionMessageServer is a subscription server that distributes the
+	// onion messages destined for us to their subscribers.
+	onionMessageServer *subscribe.Server
+
+	// offerManager issues offers and exchanges invoice requests and
+	// invoices over onion messages. It is nil if onion messages are
+	// disabled.
+	offerManager *offers.Manager
+
+	// trampolineForwarder forwards the payments of htlcs that ask us to
+	// act as trampoline node. It is nil if we don't act as trampoline
+	// node.
+	trampolineForwarder *routing.TrampolineForwarder
+
 	htlcNotifier *htlcswitch.HtlcNotifier
 
 	witnessBeacon contractcourt.WitnessBeacon
@@ -288,6 +329,17 @@ type server struct {
 	// channelNotifier to be notified of newly opened and closed channels.
 	chanSubSwapper *chanbackup.SubSwapper
 
+	// peerBackupSwapper hands every new multi-channel backup to the peers
+	// that store it on our behalf. It is nil if peer storage is disabled.
+	peerBackupSwapper *chanbackup.PeerSwapper
+
+	// peerRestoreMtx guards peerRestored.
+	peerRestoreMtx sync.Mutex
+
+	// peerRestored is true once our channels were restored from a backup
+	// returned by one of our peers.
+	peerRestored bool
+
 	// chanEventStore tracks the behaviour of channels and their remote peers to
 	// provide insights into their health and performance.
 	chanEventStore *chanfitness.ChannelEventStore
@@ -361,7 +413,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	var (
 		err           error
 		nodeKeyECDH   = keychain.NewPubKeyECDH(*nodeKeyDesc, cc.KeyRing)
-		nodeKeySigner = keychain.NewPubKeyDigestSigner(
+		nodeKeySigner = keychain.NewPubKeyMessageSigner(
 			*nodeKeyDesc, cc.KeyRing,
 		)
 	)
@@ -409,10 +461,20 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	)
 
 	featureMgr, err := feature.NewManager(feature.Config{
-		NoTLVOnion:        cfg.ProtocolOptions.LegacyOnion(),
-		NoStaticRemoteKey: cfg.ProtocolOptions.NoStaticRemoteKey(),
-		NoAnchors:         cfg.ProtocolOptions.NoAnchorCommitments(),
-		NoWumbo:           !cfg.ProtocolOptions.Wumbo(),
+		NoTLVOnion:           cfg.ProtocolOptions.LegacyOnion(),
+		NoStaticRemoteKey:    cfg.ProtocolOptions.NoStaticRemoteKey(),
+		NoAnchors:            cfg.ProtocolOptions.NoAnchorCommitments(),
+		NoWumbo:              !cfg.ProtocolOptions.Wumbo(),
+		NoScidAlias:          !cfg.ProtocolOptions.ScidAlias(),
+		NoZeroConf:           !cfg.ProtocolOptions.ZeroConf(),
+		NoSplicing:           !cfg.ProtocolOptions.Splicing(),
+		NoQuiescence:         !cfg.ProtocolOptions.Quiescence(),
+		NoDynamicCommitments: !cfg.ProtocolOptions.DynamicCommitments(),
+		NoPeerStorage:        !cfg.ProtocolOptions.PeerStorage(),
+		NoRbfCoopClose:       !cfg.ProtocolOptions.RbfCoopClose(),
+		NoDualFunding:        !cfg.ProtocolOptions.DualFunding(),
+		NoOnionMessages:      !cfg.ProtocolOptions.OnionMessages(),
+		NoTrampolineRouting:  !cfg.Trampoline.Active,
 	})
 	if err != nil {
 		return nil, err
@@ -441,14 +503,18 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 
 		channelNotifier: channelnotifier.New(dbs.chanStateDB),
 
-		identityECDH: nodeKeyECDH,
-		nodeSigner:   netann.NewNodeSigner(nodeKeySigner),
+		identityECDH:   nodeKeyECDH,
+		identityKeyLoc: nodeKeyDesc.KeyLocator,
+		nodeSigner:     netann.NewNodeSigner(nodeKeySigner),
 
 		listenAddrs: listenAddrs,
 
 		// TODO(roasbeef): derive proper onion key based on rotation
 		// schedule
-		sphinx: hop.NewOnionProcessor(sphinxRouter),
+		sphinx: hop.NewOnionProcessor(
+			sphinxRouter, nodeKeyECDH, cfg.ActiveNetParams.Params,
+			replayLog,
+		),
 
 		torController: torController,
 
@@ -466,6 +532,9 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		peerConnectedListeners:    make(map[string][]chan<- lnpeer.Peer),
 		peerDisconnectedListeners: make(map[string][]chan<- struct{}),
 
+		customMessageServer: subscribe.NewServer(),
+		onionMessageServer:  subscribe.NewServer(),
+
 		featureMgr: featureMgr,
 		quit:       make(chan struct{}),
 	}
@@ -488,6 +557,39 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		dbs.chanStateDB, expiryWatcher, &registryConfig,
 	)
 
+	// Offers are exchanged over onion messages, so we only handle them if
+	// we support onion messages.
+	if cfg.ProtocolOptions.OnionMessages() {
+		chainParams := cfg.ActiveNetParams.Params
+		s.offerManager = offers.NewManager(&offers.Config{
+			NodeID: nodeKeyDesc.PubKey,
+			Chain:  *chainParams.GenesisHash,
+			SignMessage: func(msg []byte) (*ecdsa.Signature,
+				error) {
+
+				return s.nodeSigner.SignMessage(
+					s.identityKeyLoc, msg, false,
+				)
+			},
+			CreateInvoice: s.createOfferInvoice,
+			DecodeInvoice: func(payReq string) (*zpay32.Invoice,
+				error) {
+
+				return zpay32.Decode(payReq, chainParams)
+			},
+			FindPath:                s.findOnionMessagePath,
+			SendOnionMessage:        s.SendOnionMessage,
+			SendBlindedOnionMessage: s.SendBlindedOnionMessage,
+			RequestTimeout:          offers.DefaultRequestTimeout,
+			Now:                     time.Now,
+		})
+	}
+
+	s.aliasMgr, err = aliasmgr.NewManager(dbs.chanStateDB)
+	if err != nil {
+		return nil, err
+	}
+
 	s.htlcNotifier = htlcswitch.NewHtlcNotifier(time.Now)
 
 	s.htlcSwitch, err = htlcswitch.New(htlcswitch.Config{
@@ -510,6 +612,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		SwitchPackager:         channeldb.NewSwitchPackager(),
 		ExtractErrorEncrypter:  s.sphinx.ExtractErrorEncrypter,
 		FetchLastChannelUpdate: s.fetchLastChanUpdate(),
+		GetAliases:             s.aliasMgr.GetAliases,
 		Notifier:               s.cc.ChainNotifier,
 		HtlcNotifier:           s.htlcNotifier,
 		FwdEventTicker:         ticker.New(htlcswitch.DefaultFwdEventInterval),
@@ -523,13 +626,21 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	if err != nil {
 		return nil, err
 	}
-	s.interceptableSwitch = htlcswitch.NewInterceptableSwitch(s.htlcSwitch)
+	s.interceptableSwitch = htlcswitch.NewInterceptableSwitch(
+		&htlcswitch.InterceptableSwitchConfig{
+			Switch:             s.htlcSwitch,
+			Notifier:           s.cc.ChainNotifier,
+			CltvRejectDelta:    cfg.InterceptorCltvRejectDelta,
+			RequireInterceptor: cfg.RequireInterceptor,
+		},
+	)
 
 	chanStatusMgrCfg := &netann.ChanStatusConfig{
 		ChanStatusSampleInterval: cfg.ChanStatusSampleInterval,
 		ChanEnableTimeout:        cfg.ChanEnableTimeout,
 		ChanDisableTimeout:       cfg.ChanDisableTimeout,
 		OurPubKey:                nodeKeyECDH.PubKey(),
+		OurKeyLoc:                nodeKeyDesc.KeyLocator,
 		MessageSigner:            s.nodeSigner,
 		IsChannelActive:          s.htlcSwitch.HasActiveLink,
 		ApplyChannelUpdate:       s.applyChannelUpdate,
@@ -649,6 +760,24 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	if err != nil {
 		return nil, err
 	}
+
+	// If we lease out our contribution to dual funded channels, we'll
+	// advertise our lease rates within our node announcement.
+	var (
+		leaseRates *lnwire.LeaseRates
+		nodeExtra  lnwire.ExtraOpaqueData
+	)
+	if cfg.MaxLeaseDuration > 0 {
+		leaseRates = &lnwire.LeaseRates{
+			FeeBase:     btcutil.Amount(cfg.LeaseFeeBase),
+			FeeBasis:    cfg.LeaseFeeBasis,
+			MaxDuration: cfg.MaxLeaseDuration,
+		}
+		if err := nodeExtra.PackRecords(leaseRates); err != nil {
+			return nil, err
+		}
+	}
+
 	selfNode := &channeldb.LightningNode{
 		HaveNodeAnnouncement: true,
 		LastUpdate:           time.Now(),
@@ -656,6 +785,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		Alias:                nodeAlias.String(),
 		Features:             s.featureMgr.Get(feature.SetNodeAnn),
 		Color:                color,
+		ExtraOpaqueData:      nodeExtra,
 	}
 	copy(selfNode.PubKeyBytes[:], nodeKeyECDH.PubKey().SerializeCompressed())
 
@@ -670,7 +800,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	// With the announcement generated, we'll sign it to properly
 	// authenticate the message on the network.
 	authSig, err := netann.SignAnnouncement(
-		s.nodeSigner, nodeKeyECDH.PubKey(), nodeAnn,
+		s.nodeSigner, nodeKeyDesc.KeyLocator, nodeAnn,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to generate signature for "+
@@ -731,16 +861,43 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	// servers, the mission control instance itself can be moved there too.
 	routingConfig := routerrpc.GetRoutingConfig(cfg.SubRPCServers.RouterRPC)
 
-	estimatorCfg := routing.ProbabilityEstimatorCfg{
-		AprioriHopProbability: routingConfig.AprioriHopProbability,
-		PenaltyHalfLife:       routingConfig.PenaltyHalfLife,
-		AprioriWeight:         routingConfig.AprioriWeight,
+	var estimator routing.ProbabilityEstimator
+	switch routingConfig.ProbabilityEstimatorType {
+	case routing.AprioriEstimatorName:
+		estimator, err = routing.NewAprioriEstimator(
+			routing.AprioriConfig{
+				AprioriHopProbability: routingConfig.
+					AprioriHopProbability,
+				PenaltyHalfLife: routingConfig.PenaltyHalfLife,
+				AprioriWeight:   routingConfig.AprioriWeight,
+			},
+		)
+
+	case routing.BimodalEstimatorName:
+		bimodalCfg := routingConfig.BimodalConfig
+		estimator, err = routing.NewBimodalEstimator(
+			routing.BimodalConfig{
+				BimodalNodeWeight: bimodalCfg.NodeWeight,
+				BimodalScaleMsat: lnwire.MilliSatoshi(
+					bimodalCfg.Scale,
+				),
+				BimodalDecayTime: bimodalCfg.DecayTime,
+			},
+		)
+
+	default:
+		return nil, fmt.Errorf("unknown probability estimator: %v",
+			routingConfig.ProbabilityEstimatorType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't create probability estimator: "+
+			"%v", err)
 	}
 
 	s.missionControl, err = routing.NewMissionControl(
 		dbs.chanStateDB, selfNode.PubKeyBytes,
 		&routing.MissionControlConfig{
-			ProbabilityEstimatorCfg: estimatorCfg,
+			Estimator:               estimator,
 			MaxMcHistory:            routingConfig.MaxMcHistory,
 			McFlushInterval:         routingConfig.McFlushInterval,
 			MinFailureRelaxInterval: routing.DefaultMinFailureRelaxInterval,
@@ -795,11 +952,34 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		PathFindingConfig:   pathFindingConfig,
 		Clock:               clock.NewDefaultClock(),
 		StrictZombiePruning: strictPruning,
+		IsAlias:             aliasmgr.IsAlias,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("can't create router: %v", err)
 	}
 
+	// Htlcs that ask us to forward a payment as trampoline node are
+	// rejected by the invoice registry, unless we act as trampoline node.
+	if cfg.Trampoline.Active {
+		tCfg := cfg.Trampoline
+		forwarderCfg := &routing.TrampolineForwarderConfig{
+			DecodeOnion:      s.sphinx.DecodeTrampolineOnion,
+			SendPayment:      s.chanRouter.SendPaymentAsync,
+			SubscribePayment: s.controlTower.SubscribePayment,
+			ResolveHtlc:      s.invoices.ResolveExitHtlc,
+			FeeBase:          lnwire.MilliSatoshi(tCfg.BaseFee),
+			FeeRate:          lnwire.MilliSatoshi(tCfg.FeeRate),
+			CltvDelta:        uint16(tCfg.CltvDelta),
+			PaymentTimeout:   tCfg.PaymentTimeout,
+		}
+		s.trampolineForwarder = routing.NewTrampolineForwarder(
+			forwarderCfg,
+		)
+		s.invoices.SetTrampolineForwarder(
+			s.trampolineForwarder.ForwardHtlc,
+		)
+	}
+
 	chanSeries := discovery.NewChanSeries(s.graphDB)
 	gossipMessageStore, err := discovery.NewMessageStore(s.chanStateDB)
 	if err != nil {
@@ -837,9 +1017,9 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		PinnedSyncers:           cfg.Gossip.PinnedSyncers,
 		MaxChannelUpdateBurst:   cfg.Gossip.MaxChannelUpdateBurst,
 		ChannelUpdateInterval:   cfg.Gossip.ChannelUpdateInterval,
-	},
-		nodeKeyECDH.PubKey(),
-	)
+		FindBaseByAlias:         s.findBaseByAlias,
+		GetAlias:                s.aliasMgr.GetPeerAlias,
+	}, nodeKeyDesc)
 
 	s.localChanMgr = &localchans.Manager{
 		ForAllOutgoingChannels:    s.chanRouter.ForAllOutgoingChannels,
@@ -867,6 +1047,12 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		return nil, err
 	}
 
+	// The fee curve was already validated when parsing the config.
+	feeCurve, err := sweep.ParseFeeCurve(cfg.Sweeper.FeeCurve)
+	if err != nil {
+		return nil, err
+	}
+
 	s.sweeper = sweep.New(&sweep.UtxoSweeperConfig{
 		FeeEstimator:   cc.FeeEstimator,
 		GenSweepScript: newSweepPkScriptGen(cc.Wallet),
@@ -880,8 +1066,9 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		MaxInputsPerTx:       sweep.DefaultMaxInputsPerTx,
 		MaxSweepAttempts:     sweep.DefaultMaxSweepAttempts,
 		NextAttemptDeltaFunc: sweep.DefaultNextAttemptDeltaFunc,
-		MaxFeeRate:           sweep.DefaultMaxFeeRate,
+		MaxFeeRate:           cfg.Sweeper.MaxFeeRatePerKW(),
 		FeeRateBucketSize:    sweep.DefaultFeeRateBucketSize,
+		FeeCurve:             feeCurve,
 	})
 
 	s.utxoNursery = newUtxoNursery(&NurseryConfig{
@@ -1044,6 +1231,7 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 	s.fundingMgr, err = funding.NewFundingManager(funding.Config{
 		NoWumboChans:       !cfg.ProtocolOptions.Wumbo(),
 		IDKey:              nodeKeyECDH.PubKey(),
+		IDKeyLoc:           nodeKeyDesc.KeyLocator,
 		Wallet:             cc.Wallet,
 		PublishTransaction: cc.Wallet.PublishTransaction,
 		UpdateLabel: func(hash chainhash.Hash, label string) error {
@@ -1051,14 +1239,16 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		},
 		Notifier:     cc.ChainNotifier,
 		FeeEstimator: cc.FeeEstimator,
-		SignMessage: func(pubKey *btcec.PublicKey,
-			msg []byte) (input.Signature, error) {
+		SignMessage: func(keyLoc keychain.KeyLocator,
+			msg []byte) (*ecdsa.Signature, error) {
 
-			if pubKey.IsEqual(nodeKeyECDH.PubKey()) {
-				return s.nodeSigner.SignMessage(pubKey, msg)
+			if keyLoc == nodeKeyDesc.KeyLocator {
+				return s.nodeSigner.SignMessage(
+					keyLoc, msg, true,
+				)
 			}
 
-			return cc.MsgSigner.SignMessage(pubKey, msg)
+			return cc.MsgSigner.SignMessage(keyLoc, msg, true)
 		},
 		CurrentNodeAnnouncement: func() (lnwire.NodeAnnouncement, error) {
 			return s.genNodeAnnouncement(true)
@@ -1222,6 +1412,8 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		ReservationTimeout:            10 * time.Minute,
 		MinChanSize:                   btcutil.Amount(cfg.MinChanSize),
 		MaxChanSize:                   btcutil.Amount(cfg.MaxChanSize),
+		MaxDualFundContribution:       btcutil.Amount(cfg.MaxDualFundContribution),
+		LeaseRates:                    leaseRates,
 		MaxPendingChannels:            cfg.MaxPendingChannels,
 		RejectPush:                    cfg.RejectPush,
 		MaxLocalCSVDelay:              chainCfg.MaxLocalDelay,
@@ -1232,6 +1424,15 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		RegisteredChains:              cfg.registeredChains,
 		MaxAnchorsCommitFeeRate: chainfee.SatPerKVByte(
 			s.cfg.MaxCommitFeeRateAnchors * 1000).FeePerKWeight(),
+		AliasManager: &switchAliasManager{
+			Manager:    s.aliasMgr,
+			htlcSwitch: s.htlcSwitch,
+		},
+		DeleteAliasEdge: func(scid lnwire.ShortChannelID) error {
+			return s.graphDB.DeleteChannelEdges(
+				false, scid.ToUint64(),
+			)
+		},
 	})
 	if err != nil {
 		return nil, err
@@ -1243,13 +1444,25 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 		chanNotifier: s.channelNotifier,
 		addrs:        s.chanStateDB,
 	}
-	backupFile := chanbackup.NewMultiFile(cfg.BackupFilePath)
+	var backupSwapper chanbackup.Swapper = chanbackup.NewMultiFile(
+		cfg.BackupFilePath,
+	)
+
+	// If we store our backup with our peers, every new backup is also
+	// handed to them.
+	if cfg.ProtocolOptions.PeerStorage() {
+		s.peerBackupSwapper = chanbackup.NewPeerSwapper(
+			backupSwapper, s.distributePeerBackup,
+		)
+		backupSwapper = s.peerBackupSwapper
+	}
+
 	startingChans, err := chanbackup.FetchStaticChanBackups(s.chanStateDB)
 	if err != nil {
 		return nil, err
 	}
 	s.chanSubSwapper, err = chanbackup.NewSubSwapper(
-		startingChans, chanNotifier, s.cc.KeyRing, backupFile,
+		startingChans, chanNotifier, s.cc.KeyRing, backupSwapper,
 	)
 	if err != nil {
 		return nil, err
@@ -1286,10 +1499,38 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			policy.SweepFeeRate = sweepRateSatPerVByte.FeePerKWeight()
 		}
 
+		if cfg.WtClient.MaxUpdates != 0 {
+			policy.MaxUpdates = cfg.WtClient.MaxUpdates
+		}
+
 		if err := policy.Validate(); err != nil {
 			return nil, err
 		}
 
+		// Copy the policy for legacy channels and set the blob flag
+		// signalling support for anchor channels. Anchor channels can
+		// be configured with their own sweep fee rate and session
+		// size.
+		anchorPolicy := policy
+		anchorPolicy.TxPolicy.BlobType |=
+			blob.Type(blob.FlagAnchorChannel)
+
+		if cfg.WtClient.AnchorSweepFeeRate != 0 {
+			sweepRateSatPerVByte := chainfee.SatPerKVByte(
+				1000 * cfg.WtClient.AnchorSweepFeeRate,
+			)
+			anchorPolicy.SweepFeeRate =
+				sweepRateSatPerVByte.FeePerKWeight()
+		}
+
+		if cfg.WtClient.AnchorMaxUpdates != 0 {
+			anchorPolicy.MaxUpdates = cfg.WtClient.AnchorMaxUpdates
+		}
+
+		if err := anchorPolicy.Validate(); err != nil {
+			return nil, err
+		}
+
 		// authDial is the wrapper around the btrontide.Dial for the
 		// watchtower.
 		authDial := func(localKey keychain.SingleKeyECDH,
@@ -1301,6 +1542,13 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			)
 		}
 
+		// subscribeChannelEvents lets the clients learn about closed
+		// channels, such that they can delete the sessions that only
+		// hold updates for closed channels.
+		subscribeChannelEvents := func() (subscribe.Subscription, error) {
+			return s.channelNotifier.SubscribeChannelEvents()
+		}
+
 		s.towerClient, err = wtclient.New(&wtclient.Config{
 			Signer:         cc.Wallet.Cfg.Signer,
 			NewAddress:     newSweepPkScriptGen(cc.Wallet),
@@ -1313,17 +1561,15 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			MinBackoff:     10 * time.Second,
 			MaxBackoff:     5 * time.Minute,
 			ForceQuitDelay: wtclient.DefaultForceQuitDelay,
+
+			TowerFailoverTimeout:   cfg.WtClient.TowerFailoverTimeout,
+			SubscribeChannelEvents: subscribeChannelEvents,
+			FetchClosedChannel:     dbs.chanStateDB.FetchClosedChannelForID,
 		})
 		if err != nil {
 			return nil, err
 		}
 
-		// Copy the policy for legacy channels and set the blob flag
-		// signalling support for anchor channels.
-		anchorPolicy := policy
-		anchorPolicy.TxPolicy.BlobType |=
-			blob.Type(blob.FlagAnchorChannel)
-
 		s.anchorTowerClient, err = wtclient.New(&wtclient.Config{
 			Signer:         cc.Wallet.Cfg.Signer,
 			NewAddress:     newSweepPkScriptGen(cc.Wallet),
@@ -1336,6 +1582,10 @@ func newServer(cfg *Config, listenAddrs []net.Addr,
 			MinBackoff:     10 * time.Second,
 			MaxBackoff:     5 * time.Minute,
 			ForceQuitDelay: wtclient.DefaultForceQuitDelay,
+
+			TowerFailoverTimeout:   cfg.WtClient.TowerFailoverTimeout,
+			SubscribeChannelEvents: subscribeChannelEvents,
+			FetchClosedChannel:     dbs.chanStateDB.FetchClosedChannelForID,
 		})
 		if err != nil {
 			return nil, err
@@ -1574,6 +1824,26 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.htlcNotifier.Stop)
 
+		if err := s.customMessageServer.Start(); err != nil {
+			startErr = err
+			return
+		}
+		cleanup = cleanup.add(s.customMessageServer.Stop)
+
+		if err := s.onionMessageServer.Start(); err != nil {
+			startErr = err
+			return
+		}
+		cleanup = cleanup.add(s.onionMessageServer.Stop)
+
+		if s.offerManager != nil {
+			if err := s.offerManager.Start(); err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.offerManager.Stop)
+		}
+
 		if err := s.sphinx.Start(); err != nil {
 			startErr = err
 			return
@@ -1601,6 +1871,12 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.htlcSwitch.Stop)
 
+		if err := s.interceptableSwitch.Start(); err != nil {
+			startErr = err
+			return
+		}
+		cleanup = cleanup.add(s.interceptableSwitch.Stop)
+
 		if err := s.sweeper.Start(); err != nil {
 			startErr = err
 			return
@@ -1637,6 +1913,15 @@ func (s *server) Start() error {
 		}
 		cleanup = cleanup.add(s.chanRouter.Stop)
 
+		if s.trampolineForwarder != nil {
+			err := s.trampolineForwarder.Start()
+			if err != nil {
+				startErr = err
+				return
+			}
+			cleanup = cleanup.add(s.trampolineForwarder.Stop)
+		}
+
 		if err := s.fundingMgr.Start(); err != nil {
 			startErr = err
 			return
@@ -1832,6 +2117,10 @@ func (s *server) Stop() error {
 		if err := s.chanRouter.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop chanRouter: %v", err)
 		}
+		if err := s.interceptableSwitch.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop interceptable "+
+				"switch: %v", err)
+		}
 		if err := s.htlcSwitch.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop htlcSwitch: %v", err)
 		}
@@ -1862,6 +2151,27 @@ func (s *server) Stop() error {
 		if err := s.htlcNotifier.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop htlcNotifier: %v", err)
 		}
+		if err := s.customMessageServer.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop customMessageServer: %v",
+				err)
+		}
+		if err := s.onionMessageServer.Stop(); err != nil {
+			srvrLog.Warnf("failed to stop onionMessageServer: %v",
+				err)
+		}
+		if s.offerManager != nil {
+			if err := s.offerManager.Stop(); err != nil {
+				srvrLog.Warnf("failed to stop offerManager: %v",
+					err)
+			}
+		}
+		if s.trampolineForwarder != nil {
+			err := s.trampolineForwarder.Stop()
+			if err != nil {
+				srvrLog.Warnf("failed to stop trampoline "+
+					"forwarder: %v", err)
+			}
+		}
 		s.connMgr.Stop()
 		if err := s.invoices.Stop(); err != nil {
 			srvrLog.Warnf("failed to stop invoices: %v", err)
@@ -2460,8 +2770,9 @@ func (s *server) createNewHiddenService() error {
 		Features: lnwire.NewFeatureVector(
 			newNodeAnn.Features, lnwire.Features,
 		),
-		Color:        newNodeAnn.RGBColor,
-		AuthSigBytes: newNodeAnn.Signature.ToSignatureBytes(),
+		Color:           newNodeAnn.RGBColor,
+		AuthSigBytes:    newNodeAnn.Signature.ToSignatureBytes(),
+		ExtraOpaqueData: newNodeAnn.ExtraOpaqueData,
 	}
 	copy(selfNode.PubKeyBytes[:], s.identityECDH.PubKey().SerializeCompressed())
 	if err := s.graphDB.SetSourceNode(selfNode); err != nil {
@@ -2493,7 +2804,7 @@ func (s *server) genNodeAnnouncement(refresh bool,
 	// Otherwise, we'll sign a new update after applying all of the passed
 	// modifiers.
 	err := netann.SignNodeAnnouncement(
-		s.nodeSigner, s.identityECDH.PubKey(), s.currentNodeAnn,
+		s.nodeSigner, s.identityKeyLoc, s.currentNodeAnn,
 		modifiers...,
 	)
 	if err != nil {
@@ -3263,6 +3574,7 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 		TowerClient:             s.towerClient,
 		AnchorTowerClient:       s.anchorTowerClient,
 		DisconnectPeer:          s.DisconnectPeer,
+		MarkChannelSpliced:      s.chanRouter.MarkChannelSpliced,
 		GenNodeAnnouncement:     s.genNodeAnnouncement,
 
 		PrunePersistentPeerConnection: s.prunePersistentPeerConnection,
@@ -3280,12 +3592,29 @@ func (s *server) peerConnected(conn net.Conn, connReq *connmgr.ConnReq,
 			s.cfg.MaxCommitFeeRateAnchors * 1000).FeePerKWeight(),
 		ChannelCommitInterval:  s.cfg.ChannelCommitInterval,
 		ChannelCommitBatchSize: s.cfg.ChannelCommitBatchSize,
+		HandleCustomMessage:    s.handleCustomMessage,
 		Quit:                   s.quit,
 	}
 
 	copy(pCfg.PubKeyBytes[:], peerAddr.IdentityKey.SerializeCompressed())
 	copy(pCfg.ServerPubKey[:], s.identityECDH.PubKey().SerializeCompressed())
 
+	// Only forward and receive onion messages if we signal support for
+	// them.
+	if s.cfg.ProtocolOptions.OnionMessages() {
+		pCfg.ForwardOnionMessage = s.forwardOnionMessage
+		pCfg.HandleOnionMessage = s.handleOnionMessage
+	}
+
+	// Only exchange backups with our peers if we signal support for peer
+	// storage.
+	if s.peerBackupSwapper != nil {
+		pCfg.PeerBackup = func() []byte {
+			return s.peerBackupSwapper.LatestBackup()
+		}
+		pCfg.HandlePeerStorageRetrieval = s.handlePeerStorageRetrieval
+	}
+
 	p := peer.NewBrontide(pCfg)
 
 	// TODO(roasbeef): update IP address for link-node
@@ -3922,6 +4251,23 @@ func (s *server) fetchLastChanUpdate() func(lnwire.ShortChannelID) (
 	ourPubKey := s.identityECDH.PubKey().SerializeCompressed()
 	return func(cid lnwire.ShortChannelID) (*lnwire.ChannelUpdate, error) {
 		info, edge1, edge2, err := s.chanRouter.GetChannelByID(cid)
+
+		// The edge of a zero-conf channel is moved to its confirmed
+		// short channel ID once the channel is announced, so we'll
+		// also try the other short channel IDs of the channel.
+		if err != nil {
+			for _, alias := range s.aliasMgr.GetAliases(cid) {
+				if alias == cid {
+					continue
+				}
+
+				info, edge1, edge2, err = s.chanRouter.
+					GetChannelByID(alias)
+				if err == nil {
+					break
+				}
+			}
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -3932,6 +4278,41 @@ func (s *server) fetchLastChanUpdate() func(lnwire.ShortChannelID) (
 	}
 }
 
+// findBaseByAlias returns the base short channel ID of the channel the given
+// alias belongs to. Only actual aliases are resolved, as the confirmed short
+// channel ID of a zero-conf channel has an edge in the graph of its own.
+func (s *server) findBaseByAlias(
+	alias lnwire.ShortChannelID) (lnwire.ShortChannelID, error) {
+
+	if !aliasmgr.IsAlias(alias) {
+		return lnwire.ShortChannelID{}, aliasmgr.ErrAliasNotFound
+	}
+
+	return s.aliasMgr.FindBaseSCID(alias)
+}
+
+// switchAliasManager wraps the alias manager to make any new alias of a
+// channel immediately usable for forwarding within the switch.
+type switchAliasManager struct {
+	*aliasmgr.Manager
+
+	htlcSwitch *htlcswitch.Switch
+}
+
+// AddLocalAlias persists the alias for the channel identified by the passed
+// base short channel ID, and adds it to the switch.
+func (m *switchAliasManager) AddLocalAlias(alias,
+	base lnwire.ShortChannelID) error {
+
+	if err := m.Manager.AddLocalAlias(alias, base); err != nil {
+		return err
+	}
+
+	m.htlcSwitch.AddAliasForLink(base, alias)
+
+	return nil
+}
+
 // applyChannelUpdate applies the channel update to the different sub-systems of
 // the server.
 func (s *server) applyChannelUpdate(update *lnwire.ChannelUpdate) error {
@@ -3974,3 +4355,383 @@ func shouldPeerBootstrap(cfg *Config) bool {
 
 	return !cfg.NoNetBootstrap && !isDevNetwork
 }
+
+// SendCustomMessage sends a custom message to the peer with the specified
+// pubkey.
+func (s *server) SendCustomMessage(peerPub [33]byte, msgType lnwire.MessageType,
+	data []byte) error {
+
+	// Reject message types outside of the custom range before looking up
+	// the peer, so that the caller learns about it right away.
+	msg, err := lnwire.NewCustom(msgType, data)
+	if err != nil {
+		return err
+	}
+
+	peer, err := s.FindPeerByPubStr(string(peerPub[:]))
+	if err != nil {
+		return err
+	}
+
+	// We'll wait until the peer is active.
+	select {
+	case <-peer.ActiveSignal():
+	case <-peer.QuitSignal():
+		return fmt.Errorf("peer %x disconnected", peerPub)
+	case <-s.quit:
+		return ErrServerShuttingDown
+	}
+
+	// Send the message as low-priority. For now we assume that all
+	// application-defined message are low priority.
+	return peer.SendMessageLazy(true, msg)
+}
+
+// CustomMessage is a custom message that was received from a peer.
+type CustomMessage struct {
+	// Peer is the public key of the peer that sent the message.
+	Peer [33]byte
+
+	// Msg is the custom message that was received.
+	Msg *lnwire.Custom
+}
+
+// SubscribeCustomMessages subscribes to a stream of incoming custom peer
+// messages.
+func (s *server) SubscribeCustomMessages() (*subscribe.Client, error) {
+	return s.customMessageServer.Subscribe()
+}
+
+// handleCustomMessage dispatches an incoming custom peer message to
+// subscribers.
+func (s *server) handleCustomMessage(peer [33]byte, msg *lnwire.Custom) error {
+	srvrLog.Debugf("Custom message received: peer=%x, type=%d",
+		peer, msg.Type)
+
+	return s.customMessageServer.SendUpdate(&CustomMessage{
+		Peer: peer,
+		Msg:  msg,
+	})
+}
+
+// SendOnionMessage sends an onion message along the given path of nodes,
+// delivering the passed records to the last node of the path. The first node
+// of the path must be a peer of ours.
+func (s *server) SendOnionMessage(path []*btcec.PublicKey,
+	records record.CustomSet) error {
+
+	if !s.cfg.ProtocolOptions.OnionMessages() {
+		return ErrOnionMessagesDisabled
+	}
+
+	if len(path) == 0 {
+		return fmt.Errorf("onion message path must not be empty")
+	}
+
+	msg, err := hop.BuildOnionMessage(path, records)
+	if err != nil {
+		return err
+	}
+
+	return s.sendOnionMessage(path[0], msg)
+}
+
+// SendBlindedOnionMessage sends an onion message along the given blinded
+// path, delivering the passed records to its recipient. The introduction
+// point of the path must be a peer of ours.
+func (s *server) SendBlindedOnionMessage(path *blindedpath.BlindedPath,
+	records record.CustomSet) error {
+
+	if !s.cfg.ProtocolOptions.OnionMessages() {
+		return ErrOnionMessagesDisabled
+	}
+
+	msg, err := hop.BuildBlindedOnionMessage(path, records)
+	if err != nil {
+		return err
+	}
+
+	return s.sendOnionMessage(path.IntroductionPoint, msg)
+}
+
+// sendOnionMessage sends an onion message we created to the first node of
+// its path, waiting for the peer to become active.
+func (s *server) sendOnionMessage(firstNode *btcec.PublicKey,
+	msg *lnwire.OnionMessage) error {
+
+	peer, err := s.FindPeer(firstNode)
+	if err != nil {
+		return err
+	}
+
+	// We'll wait until the peer is active.
+	select {
+	case <-peer.ActiveSignal():
+	case <-peer.QuitSignal():
+		return fmt.Errorf("peer %x disconnected",
+			firstNode.SerializeCompressed())
+	case <-s.quit:
+		return ErrServerShuttingDown
+	}
+
+	if !peer.RemoteFeatures().HasFeature(lnwire.OnionMessagesOptional) {
+		return fmt.Errorf("peer %x doesn't support onion messages",
+			firstNode.SerializeCompressed())
+	}
+
+	return peer.SendMessageLazy(false, msg)
+}
+
+// forwardOnionMessage forwards an onion message to the next node of its
+// path. Onion messages are only forwarded to nodes we are connected to.
+func (s *server) forwardOnionMessage(nextNode *btcec.PublicKey,
+	msg *lnwire.OnionMessage) error {
+
+	peer, err := s.FindPeer(nextNode)
+	if err != nil {
+		return fmt.Errorf("unable to forward onion message: %v", err)
+	}
+
+	// We don't wait for the next peer to become active, as that would
+	// stall the read handler of the peer that sent us the message.
+	select {
+	case <-peer.ActiveSignal():
+	default:
+		return fmt.Errorf("unable to forward onion message: peer %x "+
+			"not active", nextNode.SerializeCompressed())
+	}
+
+	if !peer.RemoteFeatures().HasFeature(lnwire.OnionMessagesOptional) {
+		return fmt.Errorf("unable to forward onion message: peer %x "+
+			"doesn't support onion messages",
+			nextNode.SerializeCompressed())
+	}
+
+	return peer.SendMessageLazy(false, msg)
+}
+
+// OnionMessage is an onion message that was destined for us.
+type OnionMessage struct {
+	// Peer is the public key of the peer that sent the message to us.
+	Peer [33]byte
+
+	// PathID is the path ID the creator of the path left for us, if any.
+	PathID []byte
+
+	// CustomRecords are the records the sender included for us.
+	CustomRecords record.CustomSet
+}
+
+// SubscribeOnionMessages subscribes to a stream of onion messages destined
+// for us.
+func (s *server) SubscribeOnionMessages() (*subscribe.Client, error) {
+	return s.onionMessageServer.Subscribe()
+}
+
+// handleOnionMessage dispatches an onion message destined for us to
+// subscribers.
+func (s *server) handleOnionMessage(peer [33]byte,
+	payload *hop.OnionMessagePayload) error {
+
+	srvrLog.Debugf("Onion message received: peer=%x, num_records=%d",
+		peer, len(payload.CustomRecords))
+
+	// Messages that belong to offers are consumed by the offer manager.
+	if s.offerManager != nil && s.offerManager.HandleOnionMessage(
+		payload.PathID, payload.CustomRecords,
+	) {
+
+		return nil
+	}
+
+	return s.onionMessageServer.SendUpdate(&OnionMessage{
+		Peer:          peer,
+		PathID:        payload.PathID,
+		CustomRecords: payload.CustomRecords,
+	})
+}
+
+// findOnionMessagePath returns the shortest path of nodes over which we can
+// send an onion message to the target node. The first node of the path is an
+// active peer of ours and all other nodes advertise support for onion
+// messages in the graph.
+func (s *server) findOnionMessagePath(
+	target *btcec.PublicKey) ([]*btcec.PublicKey, error) {
+
+	supportsOnionMessages := func(peer *btcec.PublicKey) bool {
+		p, err := s.FindPeer(peer)
+		if err != nil {
+			return false
+		}
+
+		select {
+		case <-p.ActiveSignal():
+		default:
+			return false
+		}
+
+		return p.RemoteFeatures().HasFeature(
+			lnwire.OnionMessagesOptional,
+		)
+	}
+
+	if supportsOnionMessages(target) {
+		return []*btcec.PublicKey{target}, nil
+	}
+
+	targetVertex := route.NewVertex(target)
+	self := route.NewVertex(s.identityECDH.PubKey())
+
+	// We search the graph breadth first, remembering the predecessor of
+	// each node we reach.
+	prev := map[route.Vertex]route.Vertex{self: self}
+	queue := []route.Vertex{self}
+	for depth := 0; depth < maxOnionMessageHops && len(queue) > 0; depth++ {
+		var next []route.Vertex
+		for _, node := range queue {
+			err := s.graphDB.ForEachNodeChannel(nil, node[:], func(
+				_ kvdb.RTx, info *channeldb.ChannelEdgeInfo,
+				_, _ *channeldb.ChannelEdgePolicy) error {
+
+				other := info.NodeKey1Bytes
+				if other == node {
+					other = info.NodeKey2Bytes
+				}
+				if _, ok := prev[other]; ok {
+					return nil
+				}
+
+				if !s.canRelayOnionMessage(self, node, other) {
+					return nil
+				}
+
+				prev[other] = node
+				next = append(next, other)
+
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if _, ok := prev[targetVertex]; ok {
+			break
+		}
+		queue = next
+	}
+
+	if _, ok := prev[targetVertex]; !ok {
+		return nil, errors.New("no onion message path found")
+	}
+
+	var path []*btcec.PublicKey
+	for node := targetVertex; node != self; node = prev[node] {
+		pubKey, err := btcec.ParsePubKey(node[:])
+		if err != nil {
+			return nil, err
+		}
+		path = append([]*btcec.PublicKey{pubKey}, path...)
+	}
+
+	return path, nil
+}
+
+// canRelayOnionMessage returns true if we can send an onion message from the
+// given node to the next node. Our own onion messages are only sent to active
+// peers that support them, other nodes need to advertise support in the
+// graph.
+func (s *server) canRelayOnionMessage(self, from, to route.Vertex) bool {
+	if from == self {
+		pubKey, err := btcec.ParsePubKey(to[:])
+		if err != nil {
+			return false
+		}
+
+		peer, err := s.FindPeer(pubKey)
+		if err != nil {
+			return false
+		}
+
+		return peer.RemoteFeatures().HasFeature(
+			lnwire.OnionMessagesOptional,
+		)
+	}
+
+	node, err := s.graphDB.FetchLightningNode(nil, to)
+	if err != nil {
+		return false
+	}
+
+	return node.Features.HasFeature(lnwire.OnionMessagesOptional)
+}
+
+// createOfferInvoice creates a fresh invoice for one of our offers.
+func (s *server) createOfferInvoice(offer *offers.Offer,
+	amt lnwire.MilliSatoshi, payerNote string) (string, error) {
+
+	if payerNote != "" {
+		srvrLog.Infof("Creating invoice for offer %q with payer "+
+			"note: %v", offer.Description, payerNote)
+	}
+
+	_, invoice, err := invoicesrpc.AddInvoice(
+		context.Background(), s.newAddInvoiceConfig(),
+		&invoicesrpc.AddInvoiceData{
+			Memo:  offer.Description,
+			Value: amt,
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return string(invoice.PaymentRequest), nil
+}
+
+// FetchOfferInvoice requests a fresh invoice for the encoded offer from its
+// issuer and returns the encoded payment request.
+func (s *server) FetchOfferInvoice(encodedOffer string,
+	amt lnwire.MilliSatoshi) (string, error) {
+
+	if s.offerManager == nil {
+		return "", ErrOnionMessagesDisabled
+	}
+
+	offer, err := offers.Decode(encodedOffer)
+	if err != nil {
+		return "", err
+	}
+
+	return s.offerManager.RequestInvoice(offer, amt, "")
+}
+
+// newAddInvoiceConfig returns the configuration used to create invoices of
+// our node.
+func (s *server) newAddInvoiceConfig() *invoicesrpc.AddInvoiceConfig {
+	defaultDelta := s.cfg.Bitcoin.TimeLockDelta
+	if s.cfg.registeredChains.PrimaryChain() == chainreg.LitecoinChain {
+		defaultDelta = s.cfg.Litecoin.TimeLockDelta
+	}
+
+	return &invoicesrpc.AddInvoiceConfig{
+		AddInvoice:        s.invoices.AddInvoice,
+		IsChannelActive:   s.htlcSwitch.HasActiveLink,
+		ChainParams:       s.cfg.ActiveNetParams.Params,
+		NodeSigner:        s.nodeSigner,
+		DefaultCLTVExpiry: defaultDelta,
+		ChanDB:            s.chanStateDB,
+		Graph:             s.graphDB,
+		GenInvoiceFeatures: func() *lnwire.FeatureVector {
+			return s.featureMgr.Get(feature.SetInvoice)
+		},
+		GenAmpInvoiceFeatures: func() *lnwire.FeatureVector {
+			return s.featureMgr.Get(feature.SetInvoiceAmp)
+		},
+		GetAlias: s.aliasMgr.GetPeerAlias,
+		BestHeight: func() (uint32, error) {
+			_, height, err := s.cc.ChainIO.GetBestBlock()
+			return uint32(height), err
+		},
+	}
+}
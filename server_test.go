@@ -19,6 +19,9 @@ import (
 	"time"
 
 	"github.com/lightningnetwork/lnd/lncfg"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseHexColor(t *testing.T) {
@@ -286,3 +289,72 @@ func TestShouldPeerBootstrap(t *testing.T) {
 		}
 	}
 }
+
+// TestCustomMessageFanOut tests that custom messages received from peers are
+// delivered to all subscribers.
+func TestCustomMessageFanOut(t *testing.T) {
+	t.Parallel()
+
+	s := &server{
+		customMessageServer: subscribe.NewServer(),
+	}
+	require.NoError(t, s.customMessageServer.Start())
+	defer func() {
+		require.NoError(t, s.customMessageServer.Stop())
+	}()
+
+	const numClients = 3
+	clients := make([]*subscribe.Client, 0, numClients)
+	for i := 0; i < numClients; i++ {
+		client, err := s.SubscribeCustomMessages()
+		require.NoError(t, err)
+		defer client.Cancel()
+
+		clients = append(clients, client)
+	}
+
+	var peerPub [33]byte
+	peerPub[0] = 0x02
+	msg, err := lnwire.NewCustom(lnwire.CustomTypeStart+1, []byte{1, 2})
+	require.NoError(t, err)
+	require.NoError(t, s.handleCustomMessage(peerPub, msg))
+
+	for _, client := range clients {
+		select {
+		case update := <-client.Updates():
+			customMsg, ok := update.(*CustomMessage)
+			require.True(t, ok)
+			require.Equal(t, peerPub, customMsg.Peer)
+			require.Equal(t, msg, customMsg.Msg)
+
+		case <-time.After(5 * time.Second):
+			t.Fatalf("custom message not delivered")
+		}
+	}
+}
+
+// TestSendCustomMessageType tests that only message types within the custom
+// range can be sent to a peer.
+func TestSendCustomMessageType(t *testing.T) {
+	t.Parallel()
+
+	s := &server{}
+
+	var peerPub [33]byte
+	peerPub[0] = 0x02
+
+	// Message types below the custom range are rejected before the peer
+	// is looked up.
+	for _, msgType := range []lnwire.MessageType{
+		0, lnwire.MsgUpdateAddHTLC, lnwire.CustomTypeStart - 1,
+	} {
+		err := s.SendCustomMessage(peerPub, msgType, nil)
+		require.Error(t, err)
+		require.NotEqual(t, ErrPeerNotConnected, err)
+	}
+
+	// A message type within the custom range passes the check, but the
+	// peer isn't connected.
+	err := s.SendCustomMessage(peerPub, lnwire.CustomTypeStart, nil)
+	require.Equal(t, ErrPeerNotConnected, err)
+}
@@ -101,6 +101,7 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 	tcpResolver lncfg.TCPResolver,
 	genInvoiceFeatures func() *lnwire.FeatureVector,
 	genAmpInvoiceFeatures func() *lnwire.FeatureVector,
+	getAlias func(lnwire.ChannelID) (lnwire.ShortChannelID, error),
 	rpcLogger btclog.Logger) error {
 
 	// First, we'll use reflect to obtain a version of the config struct
@@ -234,6 +235,16 @@ func (s *subRPCServerConfigs) PopulateDependencies(cfg *Config,
 			subCfgValue.FieldByName("GenAmpInvoiceFeatures").Set(
 				reflect.ValueOf(genAmpInvoiceFeatures),
 			)
+			subCfgValue.FieldByName("GetAlias").Set(
+				reflect.ValueOf(getAlias),
+			)
+			bestHeight := func() (uint32, error) {
+				_, height, err := cc.ChainIO.GetBestBlock()
+				return uint32(height), err
+			}
+			subCfgValue.FieldByName("BestHeight").Set(
+				reflect.ValueOf(bestHeight),
+			)
 
 		// RouterRPC isn't conditionally compiled and doesn't need to be
 		// populated using reflection.
@@ -0,0 +1,120 @@
+package sweep
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/lightningnetwork/lnd/input"
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+)
+
+// FeeCurve determines how the fee rate of an input with a deadline is raised
+// from the fee rate of its fee preference to its maximum fee rate as the
+// deadline approaches.
+type FeeCurve uint8
+
+const (
+	// LinearFeeCurve raises the fee rate by the same amount every block.
+	LinearFeeCurve FeeCurve = iota
+
+	// CubicDelayFeeCurve raises the fee rate slowly at first and steeply
+	// close to the deadline. It saves fees if blocks clear quickly, at the
+	// cost of leaving less room to recover from a fee spike late in the
+	// game.
+	CubicDelayFeeCurve
+
+	// CubicEagerFeeCurve raises the fee rate steeply at first and slowly
+	// close to the deadline. It pays more in fees, but makes an early
+	// confirmation more likely.
+	CubicEagerFeeCurve
+)
+
+// String returns the name of the fee curve.
+func (c FeeCurve) String() string {
+	switch c {
+	case LinearFeeCurve:
+		return "linear"
+
+	case CubicDelayFeeCurve:
+		return "cubic-delay"
+
+	case CubicEagerFeeCurve:
+		return "cubic-eager"
+
+	default:
+		return fmt.Sprintf("unknown<%d>", uint8(c))
+	}
+}
+
+// ParseFeeCurve returns the fee curve with the given name.
+func ParseFeeCurve(name string) (FeeCurve, error) {
+	for _, c := range []FeeCurve{
+		LinearFeeCurve, CubicDelayFeeCurve, CubicEagerFeeCurve,
+	} {
+		if c.String() == name {
+			return c, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown fee curve: %v", name)
+}
+
+// FeeRate returns the fee rate at the given position on the curve, which
+// starts at startRate and ends at endRate after the given number of blocks.
+// Once the end of the curve is reached, endRate is returned.
+func (c FeeCurve) FeeRate(startRate, endRate chainfee.SatPerKWeight,
+	elapsed, total int32) chainfee.SatPerKWeight {
+
+	if elapsed >= total || endRate <= startRate {
+		return endRate
+	}
+	if elapsed <= 0 {
+		return startRate
+	}
+
+	x := float64(elapsed) / float64(total)
+
+	var y float64
+	switch c {
+	case CubicDelayFeeCurve:
+		y = math.Pow(x, 3)
+
+	case CubicEagerFeeCurve:
+		y = 1 - math.Pow(1-x, 3)
+
+	default:
+		y = x
+	}
+
+	delta := float64(endRate - startRate)
+
+	return startRate + chainfee.SatPerKWeight(delta*y)
+}
+
+// budgetFeeRate returns the highest fee rate at which the given input can be
+// swept by itself without paying more than the budget in fees. If the input
+// anchors down an unconfirmed parent, the budget also covers the fee that is
+// required to bump the parent to the same fee rate.
+func budgetFeeRate(inp input.Input,
+	budget btcutil.Amount) (chainfee.SatPerKWeight, error) {
+
+	var estimator input.TxWeightEstimator
+	err := inp.WitnessType().AddWeightEstimation(&estimator)
+	if err != nil {
+		return 0, err
+	}
+	if inp.RequiredTxOut() != nil {
+		estimator.AddTxOutput(inp.RequiredTxOut())
+	}
+	estimator.AddP2WKHOutput()
+
+	weight := int64(estimator.Weight())
+	if parent := inp.UnconfParent(); parent != nil {
+		budget += parent.Fee
+		weight += parent.Weight
+	}
+
+	return chainfee.SatPerKWeight(budget*1000) /
+		chainfee.SatPerKWeight(weight), nil
+}
@@ -0,0 +1,74 @@
+package sweep
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFeeCurve tests that the fee curves start at the start rate, end at the
+// end rate and raise the fee rate at the expected pace in between.
+func TestFeeCurve(t *testing.T) {
+	t.Parallel()
+
+	const (
+		start chainfee.SatPerKWeight = 1000
+		end   chainfee.SatPerKWeight = 9000
+		total                        = 4
+	)
+
+	tests := []struct {
+		curve    FeeCurve
+		expected []chainfee.SatPerKWeight
+	}{
+		{
+			curve: LinearFeeCurve,
+			expected: []chainfee.SatPerKWeight{
+				1000, 3000, 5000, 7000, 9000,
+			},
+		},
+		{
+			curve: CubicDelayFeeCurve,
+			expected: []chainfee.SatPerKWeight{
+				1000, 1125, 2000, 4375, 9000,
+			},
+		},
+		{
+			curve: CubicEagerFeeCurve,
+			expected: []chainfee.SatPerKWeight{
+				1000, 5625, 8000, 8875, 9000,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+
+		t.Run(test.curve.String(), func(t *testing.T) {
+			t.Parallel()
+
+			for elapsed, expected := range test.expected {
+				rate := test.curve.FeeRate(
+					start, end, int32(elapsed), total,
+				)
+				require.Equal(t, expected, rate)
+			}
+
+			// Past the end of the curve, the end rate is kept.
+			rate := test.curve.FeeRate(start, end, total+1, total)
+			require.Equal(t, end, rate)
+
+			curve, err := ParseFeeCurve(test.curve.String())
+			require.NoError(t, err)
+			require.Equal(t, test.curve, curve)
+		})
+	}
+
+	// A start rate above the end rate is capped at the end rate.
+	rate := LinearFeeCurve.FeeRate(end, start, 0, total)
+	require.Equal(t, start, rate)
+
+	_, err := ParseFeeCurve("quadratic")
+	require.Error(t, err)
+}
@@ -9,9 +9,9 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/input"
@@ -81,6 +81,20 @@ type Params struct {
 	// ExclusiveGroup is an identifier that, if set, prevents other inputs
 	// with the same identifier from being batched together.
 	ExclusiveGroup *uint64
+
+	// DeadlineHeight is the height by which the input must be swept. If
+	// set, the fee rate of the input is raised every block along the fee
+	// curve of the UtxoSweeper, starting at the fee rate of the fee
+	// preference and reaching the maximum fee rate of the input right
+	// before the deadline. The sweep is republished every block until it
+	// confirms.
+	DeadlineHeight int32
+
+	// Budget is the maximum fee we are willing to pay to sweep the input.
+	// It caps the fee rate an input with a deadline is raised to. If not
+	// set, the fee rate is only capped by the maximum fee rate of the
+	// UtxoSweeper.
+	Budget btcutil.Amount
 }
 
 // ParamsUpdate contains a new set of parameters to update a pending sweep with.
@@ -97,8 +111,9 @@ type ParamsUpdate struct {
 
 // String returns a human readable interpretation of the sweep parameters.
 func (p Params) String() string {
-	return fmt.Sprintf("fee=%v, force=%v, exclusive_group=%v",
-		p.Fee, p.Force, p.ExclusiveGroup)
+	return fmt.Sprintf("fee=%v, force=%v, exclusive_group=%v, "+
+		"deadline_height=%v, budget=%v", p.Fee, p.Force,
+		p.ExclusiveGroup, p.DeadlineHeight, p.Budget)
 }
 
 // pendingInput is created when an input reaches the main loop for the first
@@ -119,6 +134,11 @@ type pendingInput struct {
 	// input may be (re)published.
 	minPublishHeight int32
 
+	// startHeight is the height at which the input was first offered to
+	// the UtxoSweeper. It marks the start of the fee curve of inputs with
+	// a deadline.
+	startHeight int32
+
 	// publishAttempts records the number of attempts that have already been
 	// made to sweep this tx.
 	publishAttempts int
@@ -149,6 +169,18 @@ type inputCluster struct {
 	inputs       pendingInputs
 }
 
+// hasDeadline returns true if the cluster contains inputs with a deadline.
+// Those inputs must be swept at their own fee rate.
+func (c inputCluster) hasDeadline() bool {
+	for _, input := range c.inputs {
+		if input.params.DeadlineHeight != 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
 // pendingSweepsReq is an internal message we'll use to represent an external
 // caller's intent to retrieve all of the pending inputs the UtxoSweeper is
 // attempting to sweep.
@@ -295,6 +327,10 @@ type UtxoSweeperConfig struct {
 	//   #1: min = 1 sat/vbyte, max (exclusive) = 11 sat/vbyte
 	//   #2: min = 11 sat/vbyte, max (exclusive) = 21 sat/vbyte...
 	FeeRateBucketSize int
+
+	// FeeCurve determines how the fee rate of inputs with a deadline is
+	// raised as their deadline approaches.
+	FeeCurve FeeCurve
 }
 
 // Result is the struct that is pushed through the result channel. Callers can
@@ -507,6 +543,100 @@ func (s *UtxoSweeper) feeRateForPreference(
 	return feeRate, nil
 }
 
+// feeRateForInput returns the fee rate the given input should be swept with at
+// the given height. For inputs without a deadline, this is the fee rate of
+// their fee preference. The fee rate of inputs with a deadline follows the fee
+// curve from the fee rate of their fee preference up to their maximum fee
+// rate, which is reached one block before the deadline.
+func (s *UtxoSweeper) feeRateForInput(pi *pendingInput,
+	currentHeight int32) (chainfee.SatPerKWeight, error) {
+
+	feeRate, err := s.feeRateForPreference(pi.params.Fee)
+	if err != nil {
+		return 0, err
+	}
+
+	if pi.params.DeadlineHeight == 0 {
+		return feeRate, nil
+	}
+
+	// The fee rate is capped by the budget of the input, but never drops
+	// below the relay fee rate so the sweep still propagates.
+	maxFeeRate := s.cfg.MaxFeeRate
+	if pi.params.Budget != 0 {
+		budgetRate, err := budgetFeeRate(pi, pi.params.Budget)
+		if err != nil {
+			return 0, err
+		}
+		if budgetRate < maxFeeRate {
+			maxFeeRate = budgetRate
+		}
+	}
+	if maxFeeRate < s.relayFeeRate {
+		maxFeeRate = s.relayFeeRate
+	}
+	if feeRate >= maxFeeRate {
+		return maxFeeRate, nil
+	}
+
+	feeRate = s.cfg.FeeCurve.FeeRate(
+		feeRate, maxFeeRate, currentHeight-pi.startHeight,
+		pi.params.DeadlineHeight-1-pi.startHeight,
+	)
+
+	// Never lower the fee rate of an input with a deadline, so that every
+	// sweep we publish replaces the previous one, even if the fee
+	// estimate dropped in the meantime.
+	if pi.lastFeeRate > feeRate && pi.lastFeeRate <= maxFeeRate {
+		feeRate = pi.lastFeeRate
+	}
+
+	return feeRate, nil
+}
+
+// budgetCappedFeeRate lowers the fee rate of a sweep tx spending the given
+// inputs, such that its fee doesn't exceed the combined budget of the inputs
+// that have one. The budget fee rate of an input assumes it is swept by
+// itself, so the fee is checked against the weight of the actual tx, including
+// any wallet inputs that were added to it and the unconfirmed parents it pays
+// for. The fee rate never drops below the relay fee rate.
+func (s *UtxoSweeper) budgetCappedFeeRate(inputs inputSet,
+	feeRate chainfee.SatPerKWeight) chainfee.SatPerKWeight {
+
+	var budget btcutil.Amount
+	for _, inp := range inputs {
+		pi, ok := s.pendingInputs[*inp.OutPoint()]
+		if !ok {
+			continue
+		}
+		budget += pi.params.Budget
+	}
+	if budget == 0 {
+		return feeRate
+	}
+
+	_, estimator := getWeightEstimate(inputs, nil, feeRate)
+	if estimator.fee() <= budget {
+		return feeRate
+	}
+
+	// The fee already paid by the parents counts towards the package, just
+	// like it does for the budget fee rate of a single input.
+	weight := int64(estimator.weight()) + estimator.parentsWeight
+	cappedRate := chainfee.SatPerKWeight(
+		(budget+estimator.parentsFee)*1000,
+	) / chainfee.SatPerKWeight(weight)
+	if cappedRate < s.relayFeeRate {
+		cappedRate = s.relayFeeRate
+	}
+
+	log.Debugf("Capping sweep fee rate of %v inputs from %v to %v to "+
+		"stay within their budget of %v", len(inputs), feeRate,
+		cappedRate, budget)
+
+	return cappedRate
+}
+
 // collector is the sweeper main loop. It processes new inputs, spend
 // notifications and counts down to publication of the sweep tx.
 func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch) {
@@ -556,6 +686,7 @@ func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch) {
 				listeners:        []chan Result{input.resultChan},
 				Input:            input.input,
 				minPublishHeight: bestHeight,
+				startHeight:      bestHeight,
 				params:           input.params,
 			}
 			s.pendingInputs[outpoint] = pendInput
@@ -676,7 +807,7 @@ func (s *UtxoSweeper) collector(blockEpochs <-chan *chainntnfs.BlockEpoch) {
 			// this to ensure any inputs which have had their fee
 			// rate bumped are broadcast first in order enforce the
 			// RBF policy.
-			inputClusters := s.createInputClusters()
+			inputClusters := s.createInputClusters(bestHeight)
 			sort.Slice(inputClusters, func(i, j int) bool {
 				return inputClusters[i].sweepFeeRate >
 					inputClusters[j].sweepFeeRate
@@ -783,16 +914,22 @@ func (s *UtxoSweeper) bucketForFeeRate(
 // inputs known by the UtxoSweeper. It clusters inputs by
 // 1) Required tx locktime
 // 2) Similar fee rates
-func (s *UtxoSweeper) createInputClusters() []inputCluster {
+func (s *UtxoSweeper) createInputClusters(
+	currentHeight int32) []inputCluster {
+
 	inputs := s.pendingInputs
 
 	// We start by getting the inputs clusters by locktime. Since the
 	// inputs commit to the locktime, they can only be clustered together
 	// if the locktime is equal.
-	lockTimeClusters, nonLockTimeInputs := s.clusterByLockTime(inputs)
+	lockTimeClusters, nonLockTimeInputs := s.clusterByLockTime(
+		inputs, currentHeight,
+	)
 
 	// Cluster the the remaining inputs by sweep fee rate.
-	feeClusters := s.clusterBySweepFeeRate(nonLockTimeInputs)
+	feeClusters := s.clusterBySweepFeeRate(
+		nonLockTimeInputs, currentHeight,
+	)
 
 	// Since the inputs that we clustered by fee rate don't commit to a
 	// specific locktime, we can try to merge a locktime cluster with a fee
@@ -805,10 +942,18 @@ func (s *UtxoSweeper) createInputClusters() []inputCluster {
 // is determined by calculating the average fee rate of all inputs within that
 // cluster. In addition to the created clusters, inputs that did not specify a
 // required lock time are returned.
-func (s *UtxoSweeper) clusterByLockTime(inputs pendingInputs) ([]inputCluster,
-	pendingInputs) {
+func (s *UtxoSweeper) clusterByLockTime(inputs pendingInputs,
+	currentHeight int32) ([]inputCluster, pendingInputs) {
+
+	// Inputs with a deadline are swept at their own fee rate, so they are
+	// only clustered with inputs of the same locktime and fee rate. For
+	// all other inputs, the fee rate of the key is zero.
+	type lockTimeKey struct {
+		lockTime     uint32
+		deadlineRate chainfee.SatPerKWeight
+	}
 
-	locktimes := make(map[uint32]pendingInputs)
+	locktimes := make(map[lockTimeKey]pendingInputs)
 	inputFeeRates := make(map[wire.OutPoint]chainfee.SatPerKWeight)
 	rem := make(pendingInputs)
 
@@ -820,21 +965,26 @@ func (s *UtxoSweeper) clusterByLockTime(inputs pendingInputs) ([]inputCluster,
 			continue
 		}
 
+		// We also get the preferred fee rate for this input.
+		feeRate, err := s.feeRateForInput(input, currentHeight)
+		if err != nil {
+			log.Warnf("Skipping input %v: %v", op, err)
+			continue
+		}
+
+		key := lockTimeKey{lockTime: lt}
+		if input.params.DeadlineHeight != 0 {
+			key.deadlineRate = feeRate
+		}
+
 		// Check if we already have inputs with this locktime.
-		p, ok := locktimes[lt]
+		p, ok := locktimes[key]
 		if !ok {
 			p = make(pendingInputs)
 		}
 
 		p[op] = input
-		locktimes[lt] = p
-
-		// We also get the preferred fee rate for this input.
-		feeRate, err := s.feeRateForPreference(input.params.Fee)
-		if err != nil {
-			log.Warnf("Skipping input %v: %v", op, err)
-			continue
-		}
+		locktimes[key] = p
 
 		input.lastFeeRate = feeRate
 		inputFeeRates[op] = feeRate
@@ -843,8 +993,8 @@ func (s *UtxoSweeper) clusterByLockTime(inputs pendingInputs) ([]inputCluster,
 	// We'll then determine the sweep fee rate for each set of inputs by
 	// calculating the average fee rate of the inputs within each set.
 	inputClusters := make([]inputCluster, 0, len(locktimes))
-	for lt, inputs := range locktimes {
-		lt := lt
+	for key, inputs := range locktimes {
+		lt := key.lockTime
 
 		var sweepFeeRate chainfee.SatPerKWeight
 		for op := range inputs {
@@ -866,14 +1016,17 @@ func (s *UtxoSweeper) clusterByLockTime(inputs pendingInputs) ([]inputCluster,
 // and clusters those together with similar fee rates. Each cluster contains a
 // sweep fee rate, which is determined by calculating the average fee rate of
 // all inputs within that cluster.
-func (s *UtxoSweeper) clusterBySweepFeeRate(inputs pendingInputs) []inputCluster {
+func (s *UtxoSweeper) clusterBySweepFeeRate(inputs pendingInputs,
+	currentHeight int32) []inputCluster {
+
 	bucketInputs := make(map[int]*bucketList)
+	deadlineInputs := make(map[chainfee.SatPerKWeight]*bucketList)
 	inputFeeRates := make(map[wire.OutPoint]chainfee.SatPerKWeight)
 
 	// First, we'll group together all inputs with similar fee rates. This
 	// is done by determining the fee rate bucket they should belong in.
 	for op, input := range inputs {
-		feeRate, err := s.feeRateForPreference(input.params.Fee)
+		feeRate, err := s.feeRateForInput(input, currentHeight)
 		if err != nil {
 			log.Warnf("Skipping input %v: %v", op, err)
 			continue
@@ -898,14 +1051,30 @@ func (s *UtxoSweeper) clusterBySweepFeeRate(inputs pendingInputs) []inputCluster
 			}
 		}
 
-		feeGroup := s.bucketForFeeRate(feeRate)
+		// Inputs with a deadline are swept at their own fee rate,
+		// which follows their fee curve and is capped by their budget.
+		// Rather than averaging it with the fee rates of all inputs in
+		// the same fee rate bucket, we only cluster them with inputs
+		// of the exact same fee rate.
+		var buckets *bucketList
+		if input.params.DeadlineHeight != 0 {
+			var ok bool
+			buckets, ok = deadlineInputs[feeRate]
+			if !ok {
+				buckets = &bucketList{}
+				deadlineInputs[feeRate] = buckets
+			}
+		} else {
+			feeGroup := s.bucketForFeeRate(feeRate)
 
-		// Create a bucket list for this fee rate if there isn't one
-		// yet.
-		buckets, ok := bucketInputs[feeGroup]
-		if !ok {
-			buckets = &bucketList{}
-			bucketInputs[feeGroup] = buckets
+			// Create a bucket list for this fee rate if there
+			// isn't one yet.
+			var ok bool
+			buckets, ok = bucketInputs[feeGroup]
+			if !ok {
+				buckets = &bucketList{}
+				bucketInputs[feeGroup] = buckets
+			}
 		}
 
 		// Request the bucket list to add this input. The bucket list
@@ -918,8 +1087,17 @@ func (s *UtxoSweeper) clusterBySweepFeeRate(inputs pendingInputs) []inputCluster
 
 	// We'll then determine the sweep fee rate for each set of inputs by
 	// calculating the average fee rate of the inputs within each set.
-	inputClusters := make([]inputCluster, 0, len(bucketInputs))
+	bucketLists := make([]*bucketList, 0, len(bucketInputs)+
+		len(deadlineInputs))
 	for _, buckets := range bucketInputs {
+		bucketLists = append(bucketLists, buckets)
+	}
+	for _, buckets := range deadlineInputs {
+		bucketLists = append(bucketLists, buckets)
+	}
+
+	inputClusters := make([]inputCluster, 0, len(bucketLists))
+	for _, buckets := range bucketLists {
 		for _, inputs := range buckets.buckets {
 			var sweepFeeRate chainfee.SatPerKWeight
 			for op := range inputs {
@@ -1003,6 +1181,13 @@ func mergeClusters(a, b inputCluster) []inputCluster {
 	case a.lockTime != nil && b.lockTime != nil && *a.lockTime != *b.lockTime:
 		return []inputCluster{a, b}
 
+	// Inputs with a deadline are swept at their own fee rate, so they
+	// can't be merged with inputs of a different fee rate.
+	case a.sweepFeeRate != b.sweepFeeRate &&
+		(a.hasDeadline() || b.hasDeadline()):
+
+		return []inputCluster{a, b}
+
 	case a.lockTime != nil:
 		newCluster.lockTime = a.lockTime
 
@@ -1041,7 +1226,7 @@ func (s *UtxoSweeper) scheduleSweep(currentHeight int32) error {
 
 	// We'll only start our timer once we have inputs we're able to sweep.
 	startTimer := false
-	for _, cluster := range s.createInputClusters() {
+	for _, cluster := range s.createInputClusters(currentHeight) {
 		// Examine pending inputs and try to construct lists of inputs.
 		// We don't need to obtain the coin selection lock, because we
 		// just need an indication as to whether we can sweep. More
@@ -1190,6 +1375,10 @@ func (s *UtxoSweeper) sweep(inputs inputSet, feeRate chainfee.SatPerKWeight,
 		s.currentOutputScript = pkScript
 	}
 
+	// Make sure the tx doesn't pay more than the inputs with a deadline
+	// are willing to pay.
+	feeRate = s.budgetCappedFeeRate(inputs, feeRate)
+
 	// Create sweep tx.
 	tx, err := createSweepTx(
 		inputs, nil, s.currentOutputScript, uint32(currentHeight),
@@ -1247,6 +1436,21 @@ func (s *UtxoSweeper) sweep(inputs inputSet, feeRate chainfee.SatPerKWeight,
 		// Record another publish attempt.
 		pi.publishAttempts++
 
+		// Inputs with a deadline are republished every block with a
+		// higher fee rate until they confirm, so they don't back off
+		// and never run out of attempts.
+		if pi.params.DeadlineHeight != 0 {
+			pi.minPublishHeight = currentHeight + 1
+
+			log.Debugf("Rescheduling input %v with deadline %v "+
+				"after %v attempts at height %v",
+				input.PreviousOutPoint,
+				pi.params.DeadlineHeight, pi.publishAttempts,
+				pi.minPublishHeight)
+
+			continue
+		}
+
 		// We don't care what the result of the publish call was. Even
 		// if it is published successfully, it can still be that it
 		// needs to be retried. Call NextAttemptDeltaFunc to calculate
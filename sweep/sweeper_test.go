@@ -8,11 +8,11 @@ import (
 	"testing"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/build"
 	"github.com/lightningnetwork/lnd/input"
@@ -59,7 +59,7 @@ var (
 		0xf8, 0x2e, 0x16, 0x0b, 0xfa, 0x9b, 0x8b, 0x64, 0xf9,
 		0xd4, 0xc0, 0x3f, 0x99, 0x9b, 0x86, 0x43, 0xf6, 0x56,
 		0xb4, 0x12, 0xa3,
-	}, btcec.S256())
+	})
 )
 
 func createTestInput(value int64, witnessType input.WitnessType) input.BaseInput {
@@ -1253,6 +1253,192 @@ func TestBumpFeeRBF(t *testing.T) {
 	ctx.finish(1)
 }
 
+// TestDeadline asserts that the fee rate of an input with a deadline is raised
+// every block along the fee curve until it reaches the budget of the input
+// right before the deadline, and that the sweep is republished every block
+// until it confirms.
+func TestDeadline(t *testing.T) {
+	ctx := createSweeperTestContext(t)
+
+	feePref := FeePreference{ConfTarget: 144}
+	startFeeRate := chainfee.FeePerKwFloor
+	ctx.estimator.blocksToFee[feePref.ConfTarget] = startFeeRate
+
+	input := createTestInput(
+		btcutil.SatoshiPerBitcoin, input.CommitmentTimeLock,
+	)
+
+	// The sweep starts at height 100 (mockChainIOHeight) and must confirm
+	// in block 105.
+	const (
+		startHeight    = 100
+		deadlineHeight = 105
+		budget         = 10000
+	)
+	maxFeeRate, err := budgetFeeRate(&input, budget)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resultChan, err := ctx.sweeper.SweepInput(
+		&input, Params{
+			Fee:            feePref,
+			DeadlineHeight: deadlineHeight,
+			Budget:         budget,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// We expect a sweep at the start rate of the fee curve, followed by a
+	// replacement every block even though we exceed the maximum number of
+	// sweep attempts. The maximum fee rate is reached one block before
+	// the deadline and kept afterwards.
+	for height := int32(startHeight); height <= deadlineHeight; height++ {
+		if height > startHeight {
+			ctx.notifier.NotifyEpoch(height)
+		}
+
+		ctx.tick()
+		tx := ctx.receiveTx()
+
+		expectedFeeRate := LinearFeeCurve.FeeRate(
+			startFeeRate, maxFeeRate, height-startHeight,
+			deadlineHeight-1-startHeight,
+		)
+		assertTxFeeRate(t, &tx, expectedFeeRate, &input)
+	}
+
+	ctx.backend.mine()
+	ctx.expectResult(resultChan, nil)
+
+	ctx.finish(1)
+}
+
+// TestDeadlineOwnFeeRate asserts that inputs with a deadline are only swept
+// together while they share the same fee rate, and are otherwise swept at
+// their own fee rate.
+func TestDeadlineOwnFeeRate(t *testing.T) {
+	ctx := createSweeperTestContext(t)
+
+	feePref := FeePreference{ConfTarget: 144}
+	startFeeRate := chainfee.FeePerKwFloor
+	ctx.estimator.blocksToFee[feePref.ConfTarget] = startFeeRate
+
+	const (
+		startHeight    = 100
+		deadlineHeight = 105
+	)
+
+	// Both inputs share the same deadline, but the second one has twice
+	// the budget of the first one.
+	inputs := make([]input.BaseInput, 2)
+	maxFeeRates := make(map[wire.OutPoint]chainfee.SatPerKWeight)
+	var results []chan Result
+	for i := range inputs {
+		budget := btcutil.Amount(10000 * (i + 1))
+		inputs[i] = createTestInput(
+			btcutil.SatoshiPerBitcoin, input.CommitmentTimeLock,
+		)
+
+		maxFeeRate, err := budgetFeeRate(&inputs[i], budget)
+		require.NoError(t, err)
+		maxFeeRates[*inputs[i].OutPoint()] = maxFeeRate
+
+		result, err := ctx.sweeper.SweepInput(
+			&inputs[i], Params{
+				Fee:            feePref,
+				DeadlineHeight: deadlineHeight,
+				Budget:         budget,
+			},
+		)
+		require.NoError(t, err)
+		results = append(results, result)
+	}
+
+	// At the start of the fee curve, both inputs share the same fee rate,
+	// so they're swept together.
+	ctx.tick()
+	tx := ctx.receiveTx()
+	assertTxFeeRate(t, &tx, startFeeRate, &inputs[0], &inputs[1])
+
+	// One block later, the fee rates of the inputs diverge, so each input
+	// is swept by itself at its own fee rate.
+	ctx.notifier.NotifyEpoch(startHeight + 1)
+	ctx.tick()
+
+	for range inputs {
+		tx := ctx.receiveTx()
+		require.Len(t, tx.TxIn, 1)
+
+		op := tx.TxIn[0].PreviousOutPoint
+		expectedFeeRate := LinearFeeCurve.FeeRate(
+			startFeeRate, maxFeeRates[op], 1,
+			deadlineHeight-1-startHeight,
+		)
+
+		inp := &inputs[0]
+		if op == *inputs[1].OutPoint() {
+			inp = &inputs[1]
+		}
+		assertTxFeeRate(t, &tx, expectedFeeRate, inp)
+	}
+
+	ctx.backend.mine()
+	for _, result := range results {
+		ctx.expectResult(result, nil)
+	}
+
+	ctx.finish(1)
+}
+
+// TestBudgetCappedFeeRate asserts that the fee rate of a sweep tx is capped
+// such that the fee of the actual tx stays within the budget of its inputs.
+func TestBudgetCappedFeeRate(t *testing.T) {
+	t.Parallel()
+
+	const budget = 1000
+
+	deadlineInput := createTestInput(100000, input.CommitmentTimeLock)
+	walletInput := createTestInput(100000, input.WitnessKeyHash)
+
+	s := &UtxoSweeper{
+		pendingInputs: pendingInputs{
+			*deadlineInput.OutPoint(): &pendingInput{
+				Input: &deadlineInput,
+				params: Params{
+					DeadlineHeight: 110,
+					Budget:         budget,
+				},
+			},
+		},
+		relayFeeRate: chainfee.FeePerKwFloor,
+	}
+	inputs := inputSet{&deadlineInput, &walletInput}
+
+	// A fee rate that fits the budget is kept.
+	feeRate := s.budgetCappedFeeRate(inputs, chainfee.FeePerKwFloor)
+	require.Equal(t, chainfee.FeePerKwFloor, feeRate)
+
+	// The budget fee rate of the input assumes it is swept by itself, so
+	// it exceeds the budget once a wallet input is added to the tx. The
+	// fee rate is then lowered to the budget.
+	maxFeeRate, err := budgetFeeRate(&deadlineInput, budget)
+	require.NoError(t, err)
+
+	feeRate = s.budgetCappedFeeRate(inputs, maxFeeRate)
+	require.Less(t, int64(feeRate), int64(maxFeeRate))
+
+	_, estimator := getWeightEstimate(inputs, nil, feeRate)
+	require.LessOrEqual(t, int64(estimator.fee()), int64(budget))
+
+	// The fee rate never drops below the relay fee rate.
+	s.pendingInputs[*deadlineInput.OutPoint()].params.Budget = 1
+	feeRate = s.budgetCappedFeeRate(inputs, maxFeeRate)
+	require.Equal(t, chainfee.FeePerKwFloor, feeRate)
+}
+
 // TestExclusiveGroup tests the sweeper exclusive group functionality.
 func TestExclusiveGroup(t *testing.T) {
 	ctx := createSweeperTestContext(t)
@@ -4,10 +4,9 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
-	"github.com/btcsuite/btcwallet/wallet/txrules"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
@@ -122,7 +121,7 @@ type txInputSet struct {
 }
 
 func dustLimit(relayFee chainfee.SatPerKWeight) btcutil.Amount {
-	return txrules.GetDustThreshold(
+	return lnwallet.GetDustThreshold(
 		input.P2WPKHSize,
 		btcutil.Amount(relayFee.FeePerKVByte()),
 	)
@@ -366,12 +365,18 @@ func (t *txInputSet) tryAddWalletInputsIfNeeded() error {
 // createWalletTxInput converts a wallet utxo into an object that can be added
 // to the other inputs to sweep.
 func createWalletTxInput(utxo *lnwallet.Utxo) (input.Input, error) {
-	var witnessType input.WitnessType
+	var (
+		witnessType input.WitnessType
+		hashType    = txscript.SigHashAll
+	)
 	switch utxo.AddressType {
 	case lnwallet.WitnessPubKey:
 		witnessType = input.WitnessKeyHash
 	case lnwallet.NestedWitnessPubKey:
 		witnessType = input.NestedWitnessKeyHash
+	case lnwallet.TaprootPubkey:
+		witnessType = input.TaprootPubKeySpend
+		hashType = txscript.SigHashDefault
 	default:
 		return nil, fmt.Errorf("unknown address type %v",
 			utxo.AddressType)
@@ -382,7 +387,7 @@ func createWalletTxInput(utxo *lnwallet.Utxo) (input.Input, error) {
 			PkScript: utxo.PkScript,
 			Value:    int64(utxo.Value),
 		},
-		HashType: txscript.SigHashAll,
+		HashType: hashType,
 	}
 
 	// A height hint doesn't need to be set, because we don't monitor these
@@ -6,9 +6,9 @@ import (
 	"strings"
 
 	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
 )
@@ -157,6 +157,10 @@ func createSweepTx(inputs []input.Input, outputs []*wire.TxOut,
 		// We'll add the inputs as we go so we know the final ordering
 		// of inputs to sign.
 		idxs []input.Input
+
+		// We'll also keep track of all the outputs spent by the sweep
+		// transaction, as taproot inputs sign all of them.
+		prevOutFetcher = txscript.NewMultiPrevOutFetcher(nil)
 	)
 
 	// We start by adding all inputs that commit to an output. We do this
@@ -172,6 +176,7 @@ func createSweepTx(inputs []input.Input, outputs []*wire.TxOut,
 			PreviousOutPoint: *o.OutPoint(),
 			Sequence:         o.BlocksToMaturity(),
 		})
+		prevOutFetcher.AddPrevOut(*o.OutPoint(), o.SignDesc().Output)
 		sweepTx.AddTxOut(o.RequiredTxOut())
 
 		if lt, ok := o.RequiredLockTime(); ok {
@@ -200,6 +205,7 @@ func createSweepTx(inputs []input.Input, outputs []*wire.TxOut,
 			PreviousOutPoint: *o.OutPoint(),
 			Sequence:         o.BlocksToMaturity(),
 		})
+		prevOutFetcher.AddPrevOut(*o.OutPoint(), o.SignDesc().Output)
 
 		if lt, ok := o.RequiredLockTime(); ok {
 			if locktime != -1 && locktime != int32(lt) {
@@ -257,7 +263,7 @@ func createSweepTx(inputs []input.Input, outputs []*wire.TxOut,
 		return nil, err
 	}
 
-	hashCache := txscript.NewTxSigHashes(sweepTx)
+	hashCache := txscript.NewTxSigHashes(sweepTx, prevOutFetcher)
 
 	// With all the inputs in place, use each output's unique input script
 	// function to generate the final witness required for spending.
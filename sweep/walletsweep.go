@@ -4,9 +4,9 @@ import (
 	"fmt"
 	"math"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/chainfee"
@@ -255,6 +255,12 @@ func CraftSweepAllTx(feeRate chainfee.SatPerKWeight, dustLimit btcutil.Amount,
 		case lnwallet.NestedWitnessPubKey:
 			witnessType = input.NestedWitnessKeyHash
 
+		// If this is a p2tr output, then we'll spend it through the
+		// BIP-86 key spend path using the default sighash type.
+		case lnwallet.TaprootPubkey:
+			witnessType = input.TaprootPubKeySpend
+			signDesc.HashType = txscript.SigHashDefault
+
 		// All other output types we count as unknown and will fail to
 		// sweep.
 		default:
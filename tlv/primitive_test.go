@@ -5,7 +5,7 @@ import (
 	"reflect"
 	"testing"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/tlv"
 )
 
@@ -14,7 +14,7 @@ var testPK, _ = btcec.ParsePubKey([]byte{0x02,
 	0xc0, 0x37, 0x17, 0x31, 0x80, 0xe9, 0xa4, 0x65,
 	0x47, 0x60, 0x26, 0xee, 0x50, 0xf9, 0x66, 0x95,
 	0x96, 0x3e, 0x8e, 0xfe, 0x43, 0x6f, 0x54, 0xeb,
-}, btcec.S256())
+})
 
 type primitive struct {
 	u8    byte
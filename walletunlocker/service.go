@@ -3,13 +3,15 @@ package walletunlocker
 import (
 	"context"
 	"crypto/rand"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"os"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/chaincfg"
-	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/btcsuite/btcwallet/waddrmgr"
 	"github.com/btcsuite/btcwallet/wallet"
 	"github.com/lightningnetwork/lnd/aezeed"
 	"github.com/lightningnetwork/lnd/chanbackup"
@@ -38,6 +40,11 @@ type ChannelsToRecover struct {
 	// PackedSingleChanBackups is a series of encrypted and serialized
 	// single-channel backup for one or more channels.
 	PackedSingleChanBackups chanbackup.PackedSingles
+
+	// RestoreFromPeers indicates that the channels should be restored
+	// from the multi-channel backup returned by any of our peers that
+	// store it on our behalf.
+	RestoreFromPeers bool
 }
 
 // WalletInitMsg is a message sent by the UnlockerService when a user wishes to
@@ -57,13 +64,26 @@ type WalletInitMsg struct {
 
 	// WalletExtendedKey is the wallet's extended master root key that
 	// should be used instead of the seed, if non-nil. The extended key is
-	// mutually exclusive to the wallet seed, but one of both is always set.
+	// mutually exclusive to the wallet seed and the watch-only accounts,
+	// but one of them is always set.
 	WalletExtendedKey *hdkeychain.ExtendedKey
 
 	// ExtendedKeyBirthday is the birthday of a wallet that's being restored
 	// through an extended key instead of an aezeed.
 	ExtendedKeyBirthday time.Time
 
+	// WatchOnlyAccounts is a map of scoped account extended public keys
+	// that should be imported to create a watch-only wallet.
+	WatchOnlyAccounts map[waddrmgr.ScopedIndex]*hdkeychain.ExtendedKey
+
+	// WatchOnlyBirthday is the birthday of the master root key the above
+	// watch-only account xpubs were derived from.
+	WatchOnlyBirthday time.Time
+
+	// WatchOnlyMasterFingerprint is the fingerprint of the master root key
+	// the above watch-only account xpubs were derived from.
+	WatchOnlyMasterFingerprint uint32
+
 	// RecoveryWindow is the address look-ahead used when restoring a seed
 	// with existing funds. A recovery window zero indicates that no
 	// recovery should be attempted, such as after the wallet's initial
@@ -154,6 +174,10 @@ type UnlockerService struct {
 	// macaroon root keys. This will be nil on initialization and must be
 	// set using the SetMacaroonDB method as soon as it's available.
 	macaroonDB kvdb.Backend
+
+	// remoteSigning indicates that lnd uses a remote signer, in which case
+	// only a watch-only wallet can be created.
+	remoteSigning bool
 }
 
 // New creates and returns a new UnlockerService.
@@ -187,6 +211,12 @@ func (u *UnlockerService) SetMacaroonDB(macaroonDB kvdb.Backend) {
 	u.macaroonDB = macaroonDB
 }
 
+// SetRemoteSigning can be used to signal that lnd uses a remote signer, which
+// means only a watch-only wallet may be created.
+func (u *UnlockerService) SetRemoteSigning(remoteSigning bool) {
+	u.remoteSigning = remoteSigning
+}
+
 func (u *UnlockerService) newLoader(recoveryWindow uint32) (*wallet.Loader,
 	error) {
 
@@ -372,14 +402,61 @@ func (u *UnlockerService) InitWallet(ctx context.Context,
 		StatelessInit:  in.StatelessInit,
 	}
 
-	// There are two supported ways to initialize the wallet. Either from
-	// the aezeed or the final extended master key directly.
+	// A node that uses a remote signer must not hold any private keys, so
+	// its wallet can only be created from the account xpubs of the signer.
+	// A watch-only wallet on the other hand can't sign anything without a
+	// remote signer.
+	switch {
+	case u.remoteSigning && in.WatchOnly == nil:
+		return nil, fmt.Errorf("remote signing is enabled, the " +
+			"wallet must be created from the watch-only accounts " +
+			"of the remote signer")
+
+	case !u.remoteSigning && in.WatchOnly != nil:
+		return nil, fmt.Errorf("a watch-only wallet can only be " +
+			"created with remote signing enabled")
+	}
+
+	// There are three supported ways to initialize the wallet. Either from
+	// the aezeed, the final extended master key directly or from the
+	// account xpubs of a remote signer.
 	switch {
 	// Don't allow the user to specify both as that would be ambiguous.
 	case len(in.CipherSeedMnemonic) > 0 && len(in.ExtendedMasterKey) > 0:
 		return nil, fmt.Errorf("cannot specify both the cipher " +
 			"seed mnemonic and the extended master key")
 
+	// A watch-only wallet must not contain any private key material.
+	case in.WatchOnly != nil && (len(in.CipherSeedMnemonic) > 0 ||
+		len(in.ExtendedMasterKey) > 0):
+
+		return nil, fmt.Errorf("cannot specify watch-only accounts " +
+			"together with private key material")
+
+	// A watch-only wallet is created from the account xpubs of all the
+	// key scopes lnd uses.
+	case in.WatchOnly != nil:
+		accounts, err := parseWatchOnlyAccounts(in.WatchOnly.Accounts)
+		if err != nil {
+			return nil, err
+		}
+		initMsg.WatchOnlyAccounts = accounts
+
+		fingerprint := in.WatchOnly.MasterKeyFingerprint
+		switch len(fingerprint) {
+		case 0:
+		case 4:
+			initMsg.WatchOnlyMasterFingerprint =
+				binary.BigEndian.Uint32(fingerprint)
+		default:
+			return nil, fmt.Errorf("invalid master key " +
+				"fingerprint, must be 4 bytes")
+		}
+
+		initMsg.WatchOnlyBirthday = walletBirthday(
+			in.WatchOnly.MasterKeyBirthdayTimestamp,
+		)
+
 	// The aezeed is the preferred and default way of initializing a wallet.
 	case len(in.CipherSeedMnemonic) > 0:
 		// We'll map the user provided aezeed and passphrase into a
@@ -440,19 +517,10 @@ func (u *UnlockerService) InitWallet(ctx context.Context,
 
 		// When importing a wallet from its extended private key we
 		// don't know the birthday as that information is not encoded in
-		// that format. We therefore must set an arbitrary date to start
-		// rescanning at if the user doesn't provide an explicit value
-		// for it. Since lnd only uses SegWit addresses, we pick the
-		// date of the first block that contained SegWit transactions
-		// (481824).
-		initMsg.ExtendedKeyBirthday = time.Date(
-			2017, time.August, 24, 1, 57, 37, 0, time.UTC,
+		// that format.
+		initMsg.ExtendedKeyBirthday = walletBirthday(
+			in.ExtendedMasterKeyBirthdayTimestamp,
 		)
-		if in.ExtendedMasterKeyBirthdayTimestamp != 0 {
-			initMsg.ExtendedKeyBirthday = time.Unix(
-				int64(in.ExtendedMasterKeyBirthdayTimestamp), 0,
-			)
-		}
 
 		initMsg.WalletExtendedKey = extendedKey
 
@@ -468,6 +536,7 @@ func (u *UnlockerService) InitWallet(ctx context.Context,
 	if chansToRestore != nil {
 		initMsg.ChanBackups = *chansToRestore
 	}
+	initMsg.ChanBackups.RestoreFromPeers = in.RestoreFromPeers
 
 	// Deliver the initialization message back to the main daemon.
 	select {
@@ -490,6 +559,104 @@ func (u *UnlockerService) InitWallet(ctx context.Context,
 	}
 }
 
+// walletBirthday returns the birthday of a wallet that's created from key
+// material that doesn't encode it, like an extended key. If the user doesn't
+// provide an explicit timestamp, we must set an arbitrary date to start
+// rescanning at. Since lnd only uses SegWit addresses, we pick the date of the
+// first block that contained SegWit transactions (481824).
+func walletBirthday(timestamp uint64) time.Time {
+	if timestamp != 0 {
+		return time.Unix(int64(timestamp), 0)
+	}
+
+	return time.Date(2017, time.August, 24, 1, 57, 37, 0, time.UTC)
+}
+
+// parseWatchOnlyAccounts parses the account xpubs of a watch-only wallet and
+// makes sure the accounts of all key scopes lnd needs are present.
+func parseWatchOnlyAccounts(rpcAccounts []*lnrpc.WatchOnlyAccount) (
+	map[waddrmgr.ScopedIndex]*hdkeychain.ExtendedKey, error) {
+
+	accounts := make(map[waddrmgr.ScopedIndex]*hdkeychain.ExtendedKey)
+	lnCoinTypes := make(map[uint32]struct{})
+	for _, rpcAccount := range rpcAccounts {
+		scopedIndex := waddrmgr.ScopedIndex{
+			Scope: waddrmgr.KeyScope{
+				Purpose: rpcAccount.Purpose,
+				Coin:    rpcAccount.CoinType,
+			},
+			Index: rpcAccount.Account,
+		}
+		if _, ok := accounts[scopedIndex]; ok {
+			return nil, fmt.Errorf("duplicate watch-only account "+
+				"%v/%d'", scopedIndex.Scope, scopedIndex.Index)
+		}
+
+		xpub, err := hdkeychain.NewKeyFromString(rpcAccount.Xpub)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing xpub of "+
+				"account %v/%d': %v", scopedIndex.Scope,
+				scopedIndex.Index, err)
+		}
+
+		// The accounts are imported as-is, so they must be public
+		// account level keys (m/purpose'/coin_type'/account').
+		if xpub.IsPrivate() {
+			return nil, fmt.Errorf("watch-only account %v/%d' "+
+				"must not contain private keys",
+				scopedIndex.Scope, scopedIndex.Index)
+		}
+		if xpub.Depth() != 3 {
+			return nil, fmt.Errorf("watch-only account %v/%d' "+
+				"must be at depth 3, not %d", scopedIndex.Scope,
+				scopedIndex.Index, xpub.Depth())
+		}
+
+		if rpcAccount.Purpose == keychain.BIP0043Purpose {
+			lnCoinTypes[rpcAccount.CoinType] = struct{}{}
+		}
+
+		accounts[scopedIndex] = xpub
+	}
+
+	// The default accounts of the on-chain wallet must exist.
+	for _, scope := range waddrmgr.DefaultKeyScopes {
+		scopedIndex := waddrmgr.ScopedIndex{Scope: scope}
+		if _, ok := accounts[scopedIndex]; !ok {
+			return nil, fmt.Errorf("missing watch-only account "+
+				"%v/0'", scope)
+		}
+	}
+
+	// So must the accounts of all key families within our internal key
+	// scope, as none of them can be created later on.
+	if len(lnCoinTypes) != 1 {
+		return nil, fmt.Errorf("watch-only accounts must contain "+
+			"exactly one coin type for purpose %d",
+			keychain.BIP0043Purpose)
+	}
+	for coinType := range lnCoinTypes {
+		for keyFam := keychain.KeyFamilyMultiSig; keyFam <=
+			keychain.KeyFamilyTowerID; keyFam++ {
+
+			scopedIndex := waddrmgr.ScopedIndex{
+				Scope: waddrmgr.KeyScope{
+					Purpose: keychain.BIP0043Purpose,
+					Coin:    coinType,
+				},
+				Index: uint32(keyFam),
+			}
+			if _, ok := accounts[scopedIndex]; !ok {
+				return nil, fmt.Errorf("missing watch-only "+
+					"account %v/%d'", scopedIndex.Scope,
+					scopedIndex.Index)
+			}
+		}
+	}
+
+	return accounts, nil
+}
+
 // LoadAndUnlock creates a loader for the wallet and tries to unlock the wallet
 // with the given password and recovery window. If the drop wallet transactions
 // flag is set, the history state drop is performed before unlocking the wallet
@@ -682,10 +849,15 @@ func (u *UnlockerService) ChangePassword(ctx context.Context,
 
 	// Attempt to change both the public and private passphrases for the
 	// wallet. This will be done atomically in order to prevent one
-	// passphrase change from being successful and not the other.
-	err = w.ChangePassphrases(
-		publicPw, in.NewPassword, privatePw, in.NewPassword,
-	)
+	// passphrase change from being successful and not the other. A
+	// watch-only wallet only has a public passphrase.
+	if w.Manager.WatchOnly() {
+		err = w.ChangePublicPassphrase(publicPw, in.NewPassword)
+	} else {
+		err = w.ChangePassphrases(
+			publicPw, in.NewPassword, privatePw, in.NewPassword,
+		)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("unable to change wallet passphrase: "+
 			"%v", err)
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcwallet/snacl"
 	"github.com/btcsuite/btcwallet/waddrmgr"
@@ -334,6 +335,126 @@ func TestInitWallet(t *testing.T) {
 	require.Error(t, err)
 }
 
+// watchOnlyAccounts derives the xpubs of all accounts a watch-only wallet of
+// a remote signing node needs from a random master key.
+func watchOnlyAccounts(t *testing.T) []*lnrpc.WatchOnlyAccount {
+	seed := bytes.Repeat([]byte{0x42}, hdkeychain.RecommendedSeedLen)
+	rootKey, err := hdkeychain.NewMaster(seed, testNetParams)
+	require.NoError(t, err)
+
+	deriveXpub := func(purpose, coin,
+		account uint32) *lnrpc.WatchOnlyAccount {
+
+		key := rootKey
+		for _, idx := range []uint32{purpose, coin, account} {
+			key, err = key.Derive(idx + hdkeychain.HardenedKeyStart)
+			require.NoError(t, err)
+		}
+		xpub, err := key.Neuter()
+		require.NoError(t, err)
+
+		return &lnrpc.WatchOnlyAccount{
+			Purpose:  purpose,
+			CoinType: coin,
+			Account:  account,
+			Xpub:     xpub.String(),
+		}
+	}
+
+	var accounts []*lnrpc.WatchOnlyAccount
+	for _, scope := range waddrmgr.DefaultKeyScopes {
+		accounts = append(
+			accounts, deriveXpub(scope.Purpose, scope.Coin, 0),
+		)
+	}
+	for keyFam := keychain.KeyFamilyMultiSig; keyFam <=
+		keychain.KeyFamilyTowerID; keyFam++ {
+
+		accounts = append(accounts, deriveXpub(
+			keychain.BIP0043Purpose, testNetParams.HDCoinType,
+			uint32(keyFam),
+		))
+	}
+
+	return accounts
+}
+
+// TestInitWatchOnlyWallet tests that a watch-only wallet can only be created
+// with a complete set of account xpubs and only on a remote signing node,
+// which in turn can't be initialized with a seed.
+func TestInitWatchOnlyWallet(t *testing.T) {
+	t.Parallel()
+
+	testDir, err := ioutil.TempDir("", "testcreate")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(testDir)
+	}()
+
+	service := walletunlocker.New(
+		testNetParams, nil, false, testLoaderOpts(testDir),
+	)
+
+	ctx := context.Background()
+	accounts := watchOnlyAccounts(t)
+	req := &lnrpc.InitWalletRequest{
+		WalletPassword: testPassword,
+		WatchOnly: &lnrpc.WatchOnly{
+			MasterKeyBirthdayTimestamp: 1_600_000_000,
+			MasterKeyFingerprint:       []byte{1, 2, 3, 4},
+			Accounts:                   accounts,
+		},
+	}
+
+	// Without remote signing, a watch-only wallet can't be created.
+	_, err = service.InitWallet(ctx, req)
+	require.Error(t, err)
+
+	// With remote signing, a wallet with a seed can't be created.
+	service.SetRemoteSigning(true)
+	_, mnemonic := createSeedAndMnemonic(t, nil)
+	_, err = service.InitWallet(ctx, &lnrpc.InitWalletRequest{
+		WalletPassword:     testPassword,
+		CipherSeedMnemonic: mnemonic[:],
+	})
+	require.Error(t, err)
+
+	// An incomplete set of accounts is rejected as well.
+	req.WatchOnly.Accounts = accounts[:len(accounts)-1]
+	_, err = service.InitWallet(ctx, req)
+	require.Error(t, err)
+
+	req.WatchOnly.Accounts = accounts
+	errChan := make(chan error, 1)
+	go func() {
+		_, err := service.InitWallet(ctx, req)
+		errChan <- err
+	}()
+
+	select {
+	case err := <-errChan:
+		t.Fatalf("InitWallet call failed: %v", err)
+
+	case msg := <-service.InitMsgs:
+		require.Equal(t, testPassword, msg.Passphrase)
+		require.Nil(t, msg.WalletSeed)
+		require.Len(t, msg.WatchOnlyAccounts, len(accounts))
+		require.Equal(
+			t, uint32(0x01020304), msg.WatchOnlyMasterFingerprint,
+		)
+		require.Equal(
+			t, int64(1_600_000_000), msg.WatchOnlyBirthday.Unix(),
+		)
+
+		service.MacResponseChan <- testMac
+
+	case <-time.After(defaultTestTimeout):
+		t.Fatalf("password not received")
+	}
+
+	require.NoError(t, <-errChan)
+}
+
 // TestInitWalletInvalidCipherSeed tests that if we attempt to create a wallet
 // with an invalid cipher seed, then we'll receive an error.
 func TestCreateWalletInvalidEntropy(t *testing.T) {
@@ -10,7 +10,7 @@ import (
 
 	"golang.org/x/crypto/chacha20poly1305"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -153,16 +153,12 @@ type JusticeKit struct {
 // CommitToLocalWitnessScript returns the serialized witness script for the
 // commitment to-local output.
 func (b *JusticeKit) CommitToLocalWitnessScript() ([]byte, error) {
-	revocationPubKey, err := btcec.ParsePubKey(
-		b.RevocationPubKey[:], btcec.S256(),
-	)
+	revocationPubKey, err := btcec.ParsePubKey(b.RevocationPubKey[:])
 	if err != nil {
 		return nil, err
 	}
 
-	localDelayedPubKey, err := btcec.ParsePubKey(
-		b.LocalDelayPubKey[:], btcec.S256(),
-	)
+	localDelayedPubKey, err := btcec.ParsePubKey(b.LocalDelayPubKey[:])
 	if err != nil {
 		return nil, err
 	}
@@ -207,9 +203,7 @@ func (b *JusticeKit) CommitToRemoteWitnessScript() ([]byte, error) {
 	// If this is a blob for an anchor channel, we'll return the p2wsh
 	// output containing a CSV delay of 1.
 	if b.BlobType.IsAnchorChannel() {
-		pk, err := btcec.ParsePubKey(
-			b.CommitToRemotePubKey[:], btcec.S256(),
-		)
+		pk, err := btcec.ParsePubKey(b.CommitToRemotePubKey[:])
 		if err != nil {
 			return nil, err
 		}
@@ -8,7 +8,8 @@ import (
 	"reflect"
 	"testing"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/lnwire"
@@ -255,7 +256,7 @@ func testJusticeKitRemoteWitnessConstruction(
 	t *testing.T, test remoteWitnessTest) {
 
 	// Generate the to-remote pubkey.
-	toRemotePrivKey, err := btcec.NewPrivateKey(btcec.S256())
+	toRemotePrivKey, err := btcec.NewPrivateKey()
 	require.Nil(t, err)
 
 	// Copy the to-remote pubkey into the format expected by our justice
@@ -266,8 +267,7 @@ func testJusticeKitRemoteWitnessConstruction(
 	// Sign a message using the to-remote private key. The exact message
 	// doesn't matter as we won't be validating the signature's validity.
 	digest := bytes.Repeat([]byte("a"), 32)
-	rawToRemoteSig, err := toRemotePrivKey.Sign(digest)
-	require.Nil(t, err)
+	rawToRemoteSig := ecdsa.Sign(toRemotePrivKey, digest)
 
 	// Convert the DER-encoded signature into a fixed-size sig.
 	commitToRemoteSig, err := lnwire.NewSigFromSignature(rawToRemoteSig)
@@ -323,10 +323,10 @@ func TestJusticeKitToLocalWitnessConstruction(t *testing.T) {
 	csvDelay := uint32(144)
 
 	// Generate the revocation and delay private keys.
-	revPrivKey, err := btcec.NewPrivateKey(btcec.S256())
+	revPrivKey, err := btcec.NewPrivateKey()
 	require.Nil(t, err)
 
-	delayPrivKey, err := btcec.NewPrivateKey(btcec.S256())
+	delayPrivKey, err := btcec.NewPrivateKey()
 	require.Nil(t, err)
 
 	// Copy the revocation and delay pubkeys into the format expected by our
@@ -340,8 +340,7 @@ func TestJusticeKitToLocalWitnessConstruction(t *testing.T) {
 	// Sign a message using the revocation private key. The exact message
 	// doesn't matter as we won't be validating the signature's validity.
 	digest := bytes.Repeat([]byte("a"), 32)
-	rawRevSig, err := revPrivKey.Sign(digest)
-	require.Nil(t, err)
+	rawRevSig := ecdsa.Sign(revPrivKey, digest)
 
 	// Convert the DER-encoded signature into a fixed-size sig.
 	commitToLocalSig, err := lnwire.NewSigFromSignature(rawRevSig)
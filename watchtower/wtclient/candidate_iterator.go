@@ -3,6 +3,7 @@ package wtclient
 import (
 	"container/list"
 	"net"
+	"sort"
 	"sync"
 
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
@@ -41,6 +42,11 @@ type towerListIterator struct {
 	queue         *list.List
 	nextCandidate *list.Element
 	candidates    map[wtdb.TowerID]*wtdb.Tower
+
+	// health is an optional tracker of the towers' health. If set, Reset
+	// orders the candidates by their health and Next skips towers that
+	// have been failed over, unless no other tower is available.
+	health *towerHealthTracker
 }
 
 // Compile-time constraint to ensure *towerListIterator implements the
@@ -65,20 +71,50 @@ func newTowerListIterator(candidates ...*wtdb.Tower) *towerListIterator {
 }
 
 // Reset clears the iterators state, and makes the address at the front of the
-// list the next item to be returned..
+// list the next item to be returned. If a health tracker is set, the healthiest
+// towers are moved to the front of the list first.
 func (t *towerListIterator) Reset() error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	if t.health != nil {
+		t.sortByHealth()
+	}
+
 	// Reset the next candidate to the front of the linked-list.
 	t.nextCandidate = t.queue.Front()
 
 	return nil
 }
 
-// Next returns the next candidate tower. This iterator will always return
-// candidates in the order given when the iterator was instantiated.  If no more
-// candidates are available, ErrTowerCandidatesExhausted is returned.
+// sortByHealth reorders the queue of candidates so that the healthiest towers
+// come first. Candidates with equal health keep their relative order.
+//
+// NOTE: This method MUST be called with the iterator's lock held.
+func (t *towerListIterator) sortByHealth() {
+	towers := make([]*wtdb.Tower, 0, len(t.candidates))
+	for e := t.queue.Front(); e != nil; e = e.Next() {
+		tower, ok := t.candidates[e.Value.(wtdb.TowerID)]
+		if !ok {
+			continue
+		}
+		towers = append(towers, tower)
+	}
+
+	sort.SliceStable(towers, func(i, j int) bool {
+		return t.health.less(towers[i], towers[j])
+	})
+
+	t.queue.Init()
+	for _, tower := range towers {
+		t.queue.PushBack(tower.ID)
+	}
+}
+
+// Next returns the next candidate tower. Without a health tracker, this
+// iterator will always return candidates in the order given when the iterator
+// was instantiated. If no more candidates are available,
+// ErrTowerCandidatesExhausted is returned.
 func (t *towerListIterator) Next() (*wtdb.Tower, error) {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -99,12 +135,34 @@ func (t *towerListIterator) Next() (*wtdb.Tower, error) {
 
 		// Set the next candidate to the subsequent element.
 		t.nextCandidate = t.nextCandidate.Next()
+
+		// Skip towers that have been failed over, as long as there
+		// are other towers we can use instead.
+		if t.health != nil && t.health.isFailedOver(tower.IdentityKey) &&
+			!t.allFailedOver() {
+
+			continue
+		}
+
 		return tower, nil
 	}
 
 	return nil, ErrTowerCandidatesExhausted
 }
 
+// allFailedOver returns whether all candidate towers have been failed over.
+//
+// NOTE: This method MUST be called with the iterator's lock held.
+func (t *towerListIterator) allFailedOver() bool {
+	for _, tower := range t.candidates {
+		if !t.health.isFailedOver(tower.IdentityKey) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // AddCandidate adds a new candidate tower to the iterator. If the candidate
 // already exists, then any new addresses are added to it.
 func (t *towerListIterator) AddCandidate(candidate *wtdb.Tower) {
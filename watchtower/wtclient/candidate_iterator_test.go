@@ -8,7 +8,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
 )
@@ -34,12 +34,11 @@ func randAddr(t *testing.T) net.Addr {
 }
 
 func randTower(t *testing.T) *wtdb.Tower {
-	priv, err := btcec.NewPrivateKey(btcec.S256())
+	priv, err := btcec.NewPrivateKey()
 	if err != nil {
 		t.Fatalf("unable to create private key: %v", err)
 	}
 	pubKey := priv.PubKey()
-	pubKey.Curve = nil
 	return &wtdb.Tower{
 		ID:          wtdb.TowerID(rand.Uint64()),
 		IdentityKey: pubKey,
@@ -155,3 +154,56 @@ func TestTowerCandidateIterator(t *testing.T) {
 	assertActiveCandidate(t, towerIterator, secondTower, true)
 	assertNextCandidate(t, towerIterator, secondTower)
 }
+
+// TestTowerCandidateIteratorHealth asserts that an iterator with a health
+// tracker orders its candidates by their health, and skips failed over towers
+// unless no other tower is available.
+func TestTowerCandidateIteratorHealth(t *testing.T) {
+	t.Parallel()
+
+	towers := []*wtdb.Tower{randTower(t), randTower(t), randTower(t)}
+
+	health := newTowerHealthTracker()
+	towerIterator := newTowerListIterator(towers...)
+	towerIterator.health = health
+
+	// The first tower becomes unreachable, and the third tower responds
+	// faster than the second one. After a reset, the third tower should be
+	// tried first and the unreachable tower last.
+	health.recordFailure(towers[0].IdentityKey)
+	health.recordSuccess(towers[1].IdentityKey, 2*time.Second)
+	health.recordSuccess(towers[2].IdentityKey, time.Second)
+	towerIterator.Reset()
+
+	assertNextCandidate(t, towerIterator, towers[2])
+	assertNextCandidate(t, towerIterator, towers[1])
+	assertNextCandidate(t, towerIterator, towers[0])
+
+	// Once the third tower is failed over, it should be skipped.
+	health.markFailedOver(towers[2].IdentityKey)
+	towerIterator.Reset()
+
+	assertNextCandidate(t, towerIterator, towers[1])
+	assertNextCandidate(t, towerIterator, towers[0])
+	if _, err := towerIterator.Next(); err != ErrTowerCandidatesExhausted {
+		t.Fatalf("expected ErrTowerCandidatesExhausted, got %v", err)
+	}
+
+	// If all towers are failed over, none of them should be skipped.
+	health.markFailedOver(towers[0].IdentityKey)
+	health.markFailedOver(towers[1].IdentityKey)
+	towerIterator.Reset()
+
+	assertNextCandidate(t, towerIterator, towers[2])
+	assertNextCandidate(t, towerIterator, towers[1])
+	assertNextCandidate(t, towerIterator, towers[0])
+
+	// Clearing the failover of a tower makes it the only candidate again.
+	health.clearFailedOver(towers[1].IdentityKey)
+	towerIterator.Reset()
+
+	assertNextCandidate(t, towerIterator, towers[1])
+	if _, err := towerIterator.Next(); err != ErrTowerCandidatesExhausted {
+		t.Fatalf("expected ErrTowerCandidatesExhausted, got %v", err)
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"sync"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btclog"
 	"github.com/lightningnetwork/lnd/build"
@@ -17,6 +17,7 @@ import (
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
 	"github.com/lightningnetwork/lnd/tor"
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
 	"github.com/lightningnetwork/lnd/watchtower/wtpolicy"
@@ -65,6 +66,30 @@ type RegisteredTower struct {
 	ActiveSessionCandidate bool
 }
 
+// TowerBacklog describes the backups the client has yet to deliver to a
+// watchtower, along with the watchtower's observed health.
+type TowerBacklog struct {
+	// IdentityKey is the identifying public key of the watchtower.
+	IdentityKey *btcec.PublicKey
+
+	// NumActiveSessions is the number of sessions with the watchtower that
+	// are currently used to deliver backups.
+	NumActiveSessions uint32
+
+	// NumPendingBackups is the number of backups that have been assigned
+	// to one of the watchtower's sessions, but have not been committed to
+	// it yet. These can still be moved to a different watchtower.
+	NumPendingBackups uint32
+
+	// NumCommittedBackups is the number of backups that have been
+	// committed to one of the watchtower's sessions, but have not been
+	// acknowledged by the watchtower yet.
+	NumCommittedBackups uint32
+
+	// Health is the observed health of the watchtower.
+	Health TowerHealth
+}
+
 // Client is the primary interface used by the daemon to control a client's
 // lifecycle and backup revoked states.
 type Client interface {
@@ -94,6 +119,17 @@ type Client interface {
 	// Policy returns the active client policy configuration.
 	Policy() wtpolicy.Policy
 
+	// TowerBacklogs returns the backlog and health of each registered
+	// watchtower.
+	TowerBacklogs() ([]*TowerBacklog, error)
+
+	// RebalanceTowers moves the backups that are pending to be committed
+	// to the sessions of the given watchtower to a session with a
+	// different watchtower. If no watchtower is given, the watchtower of
+	// the session currently in use is rebalanced. The number of moved
+	// backups is returned.
+	RebalanceTowers(*btcec.PublicKey) (int, error)
+
 	// RegisterChannel persistently initializes any channel-dependent
 	// parameters within the client. This should be called during link
 	// startup to ensure that the client is able to support the link during
@@ -110,6 +146,13 @@ type Client interface {
 	BackupState(*lnwire.ChannelID, *lnwallet.BreachRetribution,
 		channeldb.ChannelType) error
 
+	// BackupSpliceState initiates a request to back up the variant of a
+	// revoked state that spends the funding output of a pending splice.
+	// It must be called after the state itself has been backed up with
+	// BackupState.
+	BackupSpliceState(*lnwire.ChannelID, *lnwallet.BreachRetribution,
+		channeldb.ChannelType) error
+
 	// Start initializes the watchtower client, allowing it process requests
 	// to backup revoked channel states.
 	Start() error
@@ -188,6 +231,26 @@ type Config struct {
 	// watchtowers. If the exponential backoff produces a timeout greater
 	// than this value, the backoff will be clamped to MaxBackoff.
 	MaxBackoff time.Duration
+
+	// TowerFailoverTimeout is the duration after which the backups that
+	// are pending to be sent to an unreachable watchtower are moved to a
+	// session with a different watchtower. If the value is less than or
+	// equal to zero, backups are never moved automatically.
+	TowerFailoverTimeout time.Duration
+
+	// SubscribeChannelEvents subscribes to channel events, allowing the
+	// client to learn about closed channels and delete the sessions that
+	// only hold updates for closed channels. If nil, sessions are never
+	// deleted.
+	SubscribeChannelEvents func() (subscribe.Subscription, error)
+
+	// FetchClosedChannel fetches the close summary of a channel, returning
+	// channeldb.ErrClosedChannelNotFound if the channel isn't closed. It's
+	// used on startup to find the channels that were closed while the
+	// client was offline. If nil, only the channel closes received through
+	// SubscribeChannelEvents are considered.
+	FetchClosedChannel func(lnwire.ChannelID) (*channeldb.ChannelCloseSummary,
+		error)
 }
 
 // newTowerMsg is an internal message we'll use within the TowerClient to signal
@@ -223,6 +286,38 @@ type staleTowerMsg struct {
 	errChan chan error
 }
 
+// towerBacklogMsg is an internal message we'll use within the TowerClient to
+// request the backlog of each registered tower.
+type towerBacklogMsg struct {
+	// backlogs is populated with the backlog of each tower before a nil
+	// error is sent on errChan.
+	backlogs []*TowerBacklog
+
+	// errChan is the channel through which we'll send a response back to
+	// the caller when handling their request.
+	//
+	// NOTE: This channel must be buffered.
+	errChan chan error
+}
+
+// rebalanceMsg is an internal message we'll use within the TowerClient to
+// signal that the backlog of a tower should be moved to a different tower.
+type rebalanceMsg struct {
+	// pubKey is the identifying public key of the watchtower to rebalance.
+	// If it's not set, the tower of the active session queue is used.
+	pubKey *btcec.PublicKey
+
+	// numMigrated is populated with the number of moved backups before a
+	// nil error is sent on errChan.
+	numMigrated int
+
+	// errChan is the channel through which we'll send a response back to
+	// the caller when handling their request.
+	//
+	// NOTE: This channel must be buffered.
+	errChan chan error
+}
+
 // TowerClient is a concrete implementation of the Client interface, offering a
 // non-blocking, reliable subsystem for backing up revoked states to a specified
 // private tower.
@@ -245,17 +340,32 @@ type TowerClient struct {
 	sessionQueue *sessionQueue
 	prevTask     *backupTask
 
+	// backlog holds the tasks that were moved off a tower, and are
+	// pending to be assigned to a new session queue.
+	backlog []*backupTask
+
+	health *towerHealthTracker
+
 	backupMu          sync.Mutex
 	summaries         wtdb.ChannelSummaries
 	chanCommitHeights map[lnwire.ChannelID]uint64
 
+	// spliceCommitHeights holds the highest commit height of each channel
+	// for which the variant spending the funding output of a pending
+	// splice has been backed up.
+	spliceCommitHeights map[lnwire.ChannelID]uint64
+
 	statTicker *time.Ticker
 	stats      *ClientStats
 
-	newTowers   chan *newTowerMsg
-	staleTowers chan *staleTowerMsg
+	newTowers        chan *newTowerMsg
+	staleTowers      chan *staleTowerMsg
+	backlogRequests  chan *towerBacklogMsg
+	rebalances       chan *rebalanceMsg
+	closableSessions chan *closableSessionsMsg
 
 	wg        sync.WaitGroup
+	quit      chan struct{}
 	forceQuit chan struct{}
 }
 
@@ -314,18 +424,29 @@ func New(config *Config) (*TowerClient, error) {
 		return nil, err
 	}
 
+	// Order the candidate towers by their health, so that new sessions
+	// are preferably negotiated with responsive towers.
+	health := newTowerHealthTracker()
+	towerIterator := newTowerListIterator(candidateTowers...)
+	towerIterator.health = health
+
 	c := &TowerClient{
 		cfg:               cfg,
 		log:               plog,
 		pipeline:          newTaskPipeline(plog),
-		candidateTowers:   newTowerListIterator(candidateTowers...),
+		candidateTowers:   towerIterator,
 		candidateSessions: candidateSessions,
 		activeSessions:    make(sessionQueueSet),
+		health:            health,
 		summaries:         chanSummaries,
 		statTicker:        time.NewTicker(DefaultStatInterval),
 		stats:             new(ClientStats),
 		newTowers:         make(chan *newTowerMsg),
 		staleTowers:       make(chan *staleTowerMsg),
+		backlogRequests:   make(chan *towerBacklogMsg),
+		rebalances:        make(chan *rebalanceMsg),
+		closableSessions:  make(chan *closableSessionsMsg),
+		quit:              make(chan struct{}),
 		forceQuit:         make(chan struct{}),
 	}
 	c.negotiator = newSessionNegotiator(&NegotiatorConfig{
@@ -432,6 +553,11 @@ func (c *TowerClient) buildHighestCommitHeights() {
 	}
 
 	c.chanCommitHeights = chanCommitHeights
+
+	// The variants of revoked states that spend the funding output of a
+	// pending splice aren't tracked on disk, so they may be backed up
+	// again after a restart.
+	c.spliceCommitHeights = make(map[lnwire.ChannelID]uint64)
 }
 
 // Start initializes the watchtower client by loading or negotiating an active
@@ -462,6 +588,16 @@ func (c *TowerClient) Start() error {
 			return
 		}
 
+		// Subscribe to channel events, such that we can delete the
+		// sessions that only hold updates for closed channels.
+		var chanEvents subscribe.Subscription
+		if c.cfg.SubscribeChannelEvents != nil {
+			chanEvents, err = c.cfg.SubscribeChannelEvents()
+			if err != nil {
+				return
+			}
+		}
+
 		// Start the task pipeline to which new backup tasks will be
 		// submitted from active links.
 		c.pipeline.Start()
@@ -469,6 +605,11 @@ func (c *TowerClient) Start() error {
 		c.wg.Add(1)
 		go c.backupDispatcher()
 
+		if chanEvents != nil {
+			c.wg.Add(1)
+			go c.sessionCollector(chanEvents)
+		}
+
 		c.log.Infof("Watchtower client started successfully")
 	})
 	return err
@@ -501,9 +642,11 @@ func (c *TowerClient) Stop() error {
 		c.pipeline.Stop()
 
 		// 3. Once the backup queue has shutdown, wait for the main
-		// dispatcher to exit. The backup queue will signal it's
-		// completion to the dispatcher, which releases the wait group
-		// after all tasks have been assigned to session queues.
+		// dispatcher and the session collector to exit. The backup
+		// queue will signal it's completion to the dispatcher, which
+		// releases the wait group after all tasks have been assigned to
+		// session queues.
+		close(c.quit)
 		c.wg.Wait()
 
 		// 4. Since all valid tasks have been assigned to session
@@ -642,6 +785,41 @@ func (c *TowerClient) BackupState(chanID *lnwire.ChannelID,
 	return c.pipeline.QueueBackupTask(task)
 }
 
+// BackupSpliceState initiates a request to back up the variant of a revoked
+// state that spends the funding output of a pending splice. Both variants of a
+// revoked state share its commit height, but are identified by the tower
+// through the txid of the breach transaction, so each gets its own backup.
+func (c *TowerClient) BackupSpliceState(chanID *lnwire.ChannelID,
+	breachInfo *lnwallet.BreachRetribution,
+	chanType channeldb.ChannelType) error {
+
+	c.backupMu.Lock()
+	summary, ok := c.summaries[*chanID]
+	if !ok {
+		c.backupMu.Unlock()
+		return ErrUnregisteredChannel
+	}
+
+	// Ignore backups that have already been presented to the client.
+	height, ok := c.spliceCommitHeights[*chanID]
+	if ok && breachInfo.RevokedStateNum <= height {
+		c.backupMu.Unlock()
+		c.log.Debugf("Ignoring duplicate splice backup for "+
+			"chanid=%v at height=%d", chanID,
+			breachInfo.RevokedStateNum)
+		return nil
+	}
+
+	c.spliceCommitHeights[*chanID] = breachInfo.RevokedStateNum
+	c.backupMu.Unlock()
+
+	task := newBackupTask(
+		chanID, breachInfo, summary.SweepPkScript, chanType,
+	)
+
+	return c.pipeline.QueueBackupTask(task)
+}
+
 // nextSessionQueue attempts to fetch an active session from our set of
 // candidate sessions. Candidate sessions with a differing policy from the
 // active client's advertised policy will be ignored, but may be resumed if the
@@ -690,6 +868,16 @@ func (c *TowerClient) backupDispatcher() {
 	c.log.Tracef("Starting backup dispatcher")
 	defer c.log.Tracef("Stopping backup dispatcher")
 
+	// If enabled, periodically check whether any of the towers we have
+	// pending backups for has been unreachable for too long.
+	var failoverTicks <-chan time.Time
+	if c.cfg.TowerFailoverTimeout > 0 {
+		failoverTicker := time.NewTicker(c.cfg.TowerFailoverTimeout / 4)
+		defer failoverTicker.Stop()
+
+		failoverTicks = failoverTicker.C
+	}
+
 	for {
 		switch {
 
@@ -734,6 +922,18 @@ func (c *TowerClient) backupDispatcher() {
 					"is disallowed while a new session " +
 					"negotiation is in progress")
 
+			case <-failoverTicks:
+				c.checkTowerFailover()
+
+			case msg := <-c.backlogRequests:
+				msg.errChan <- c.handleBacklogRequest(msg)
+
+			case msg := <-c.rebalances:
+				msg.errChan <- c.handleRebalance(msg)
+
+			case msg := <-c.closableSessions:
+				msg.errChan <- c.handleClosableSessions(msg)
+
 			case <-c.forceQuit:
 				return
 			}
@@ -765,6 +965,19 @@ func (c *TowerClient) backupDispatcher() {
 				continue
 			}
 
+			// Tasks that were moved off another tower take
+			// precedence over new tasks from the pipeline.
+			if len(c.backlog) > 0 {
+				task := c.backlog[0]
+				c.backlog[0] = nil
+				c.backlog = c.backlog[1:]
+
+				c.log.Debugf("Reprocessing %v", task.id)
+
+				c.processTask(task)
+				continue
+			}
+
 			// Normal operation where new tasks are read from the
 			// pipeline.
 			select {
@@ -812,6 +1025,20 @@ func (c *TowerClient) backupDispatcher() {
 			// of its corresponding candidate sessions as inactive.
 			case msg := <-c.staleTowers:
 				msg.errChan <- c.handleStaleTower(msg)
+
+			// Check whether any towers have become unreachable
+			// for long enough to move their backlog elsewhere.
+			case <-failoverTicks:
+				c.checkTowerFailover()
+
+			case msg := <-c.backlogRequests:
+				msg.errChan <- c.handleBacklogRequest(msg)
+
+			case msg := <-c.rebalances:
+				msg.errChan <- c.handleRebalance(msg)
+
+			case msg := <-c.closableSessions:
+				msg.errChan <- c.handleClosableSessions(msg)
 			}
 		}
 	}
@@ -919,18 +1146,37 @@ func (c *TowerClient) taskRejected(task *backupTask, curStatus reserveStatus) {
 
 // dial connects the peer at addr using privKey as our secret key for the
 // connection. The connection will use the configured Net's resolver to resolve
-// the address for either Tor or clear net connections.
+// the address for either Tor or clear net connections. The outcome of the
+// connection attempt is recorded in the tower's health.
 func (c *TowerClient) dial(localKey keychain.SingleKeyECDH,
 	addr *lnwire.NetAddress) (wtserver.Peer, error) {
 
-	return c.cfg.AuthDial(localKey, addr, c.cfg.Dial)
+	start := time.Now()
+	peer, err := c.cfg.AuthDial(localKey, addr, c.cfg.Dial)
+	if err != nil {
+		c.health.recordFailure(addr.IdentityKey)
+		return nil, err
+	}
+	c.health.recordSuccess(addr.IdentityKey, time.Since(start))
+
+	return peer, nil
 }
 
-// readMessage receives and parses the next message from the given Peer. An
-// error is returned if a message is not received before the server's read
-// timeout, the read off the wire failed, or the message could not be
-// deserialized.
+// readMessage receives and parses the next message from the given Peer, and
+// records the outcome in the tower's health. An error is returned if a message
+// is not received before the server's read timeout, the read off the wire
+// failed, or the message could not be deserialized.
 func (c *TowerClient) readMessage(peer wtserver.Peer) (wtwire.Message, error) {
+	msg, err := c.readNextMessage(peer)
+	c.health.recordReply(peer, err)
+
+	return msg, err
+}
+
+// readNextMessage receives and parses the next message from the given Peer.
+func (c *TowerClient) readNextMessage(peer wtserver.Peer) (wtwire.Message,
+	error) {
+
 	// Set a read timeout to ensure we drop the connection if nothing is
 	// received in a timely manner.
 	err := peer.SetReadDeadline(time.Now().Add(c.cfg.ReadTimeout))
@@ -990,8 +1236,15 @@ func (c *TowerClient) sendMessage(peer wtserver.Peer, msg wtwire.Message) error
 	_, err = peer.Write(b.Bytes())
 	if err != nil {
 		c.log.Errorf("Unable to send msg: %v", err)
+		c.health.recordFailure(peer.RemotePub())
+		return err
 	}
-	return err
+
+	// Remember when the message was sent, so that we can measure how long
+	// it takes the tower to reply.
+	c.health.recordSend(peer)
+
+	return nil
 }
 
 // newSessionQueue creates a sessionQueue from a ClientSession loaded from the
@@ -1083,6 +1336,10 @@ func (c *TowerClient) handleNewTower(msg *newTowerMsg) error {
 	}
 	c.candidateTowers.AddCandidate(tower)
 
+	// If the tower was failed over before, re-adding it makes it a regular
+	// candidate for new sessions again.
+	c.health.clearFailedOver(tower.IdentityKey)
+
 	// Include all of its corresponding sessions to our set of candidates.
 	isAnchorClient := c.cfg.Policy.IsAnchorChannel()
 	activeSessionFilter := genActiveSessionFilter(isAnchorClient)
@@ -1240,6 +1497,182 @@ func (c *TowerClient) LookupTower(pubKey *btcec.PublicKey) (*RegisteredTower, er
 	}, nil
 }
 
+// TowerBacklogs returns the backlog and health of each registered watchtower.
+func (c *TowerClient) TowerBacklogs() ([]*TowerBacklog, error) {
+	msg := &towerBacklogMsg{
+		errChan: make(chan error, 1),
+	}
+
+	select {
+	case c.backlogRequests <- msg:
+	case <-c.pipeline.quit:
+		return nil, ErrClientExiting
+	case <-c.pipeline.forceQuit:
+		return nil, ErrClientExiting
+	}
+
+	select {
+	case err := <-msg.errChan:
+		return msg.backlogs, err
+	case <-c.pipeline.quit:
+		return nil, ErrClientExiting
+	case <-c.pipeline.forceQuit:
+		return nil, ErrClientExiting
+	}
+}
+
+// handleBacklogRequest populates the request with the backlog of each
+// registered tower, which is computed from the active session queues.
+func (c *TowerClient) handleBacklogRequest(msg *towerBacklogMsg) error {
+	towers, err := c.cfg.DB.ListTowers()
+	if err != nil {
+		return err
+	}
+
+	backlogs := make(map[towerKey]*TowerBacklog, len(towers))
+	for _, tower := range towers {
+		backlog := &TowerBacklog{
+			IdentityKey: tower.IdentityKey,
+			Health:      c.health.Health(tower.IdentityKey),
+		}
+		backlogs[newTowerKey(tower.IdentityKey)] = backlog
+		msg.backlogs = append(msg.backlogs, backlog)
+	}
+
+	for _, sq := range c.activeSessions {
+		backlog, ok := backlogs[newTowerKey(sq.towerAddr.IdentityKey)]
+		if !ok {
+			continue
+		}
+
+		numPending, numCommitted := sq.Backlog()
+		backlog.NumActiveSessions++
+		backlog.NumPendingBackups += numPending
+		backlog.NumCommittedBackups += numCommitted
+	}
+
+	return nil
+}
+
+// RebalanceTowers moves the backups that are pending to be committed to the
+// sessions of the given watchtower to a session with a different watchtower.
+// If no watchtower is given, the watchtower of the session currently in use is
+// rebalanced. The number of moved backups is returned.
+func (c *TowerClient) RebalanceTowers(pubKey *btcec.PublicKey) (int, error) {
+	msg := &rebalanceMsg{
+		pubKey:  pubKey,
+		errChan: make(chan error, 1),
+	}
+
+	select {
+	case c.rebalances <- msg:
+	case <-c.pipeline.quit:
+		return 0, ErrClientExiting
+	case <-c.pipeline.forceQuit:
+		return 0, ErrClientExiting
+	}
+
+	select {
+	case err := <-msg.errChan:
+		return msg.numMigrated, err
+	case <-c.pipeline.quit:
+		return 0, ErrClientExiting
+	case <-c.pipeline.forceQuit:
+		return 0, ErrClientExiting
+	}
+}
+
+// handleRebalance handles a request to move the backlog of a tower to a
+// different tower.
+func (c *TowerClient) handleRebalance(msg *rebalanceMsg) error {
+	pubKey := msg.pubKey
+	switch {
+	case pubKey != nil:
+		// Make sure the tower is known before failing it over.
+		if _, err := c.cfg.DB.LoadTower(pubKey); err != nil {
+			return err
+		}
+
+	case c.sessionQueue != nil:
+		pubKey = c.sessionQueue.towerAddr.IdentityKey
+
+	default:
+		return ErrNoActiveSession
+	}
+
+	msg.numMigrated = c.failoverTower(pubKey)
+
+	c.log.Infof("Rebalanced %d backups away from tower=%x",
+		msg.numMigrated, pubKey.SerializeCompressed())
+
+	return nil
+}
+
+// checkTowerFailover fails over every tower with active session queues that
+// has been unreachable for longer than the configured TowerFailoverTimeout.
+func (c *TowerClient) checkTowerFailover() {
+	for _, sq := range c.activeSessions {
+		pubKey := sq.towerAddr.IdentityKey
+		if c.health.isFailedOver(pubKey) {
+			continue
+		}
+
+		unreachableFor := c.health.unreachableFor(pubKey)
+		if unreachableFor == 0 ||
+			unreachableFor < c.cfg.TowerFailoverTimeout {
+
+			continue
+		}
+
+		numMigrated := c.failoverTower(pubKey)
+
+		c.log.Warnf("Tower=%x unreachable for %v, moved %d backups "+
+			"to other towers", pubKey.SerializeCompressed(),
+			unreachableFor, numMigrated)
+	}
+}
+
+// failoverTower stops using the sessions of the given tower for new backups,
+// and moves the tasks that are pending to be committed to its session queues
+// to the client's backlog, from which they'll be assigned to a session with a
+// different tower. Updates that have already been committed remain with their
+// session queue, which will continue trying to deliver them. The number of
+// moved tasks is returned.
+func (c *TowerClient) failoverTower(pubKey *btcec.PublicKey) int {
+	c.health.markFailedOver(pubKey)
+
+	key := newTowerKey(pubKey)
+	for id, s := range c.candidateSessions {
+		if newTowerKey(s.Tower.IdentityKey) == key {
+			delete(c.candidateSessions, id)
+		}
+	}
+
+	if c.sessionQueue != nil &&
+		newTowerKey(c.sessionQueue.towerAddr.IdentityKey) == key {
+
+		c.sessionQueue = nil
+	}
+
+	var numMigrated int
+	for _, sq := range c.activeSessions {
+		if newTowerKey(sq.towerAddr.IdentityKey) != key {
+			continue
+		}
+
+		tasks := sq.TakePending()
+		c.backlog = append(c.backlog, tasks...)
+		numMigrated += len(tasks)
+	}
+	c.stats.tasksMigrated(numMigrated)
+
+	// Reorder the candidate towers so that the next session is negotiated
+	// with the healthiest tower.
+	c.candidateTowers.Reset()
+
+	return numMigrated
+}
+
 // Stats returns the in-memory statistics of the client since startup.
 func (c *TowerClient) Stats() ClientStats {
 	return c.stats.Copy()
@@ -2,21 +2,25 @@ package wtclient_test
 
 import (
 	"encoding/binary"
+	"errors"
 	"net"
 	"sync"
 	"testing"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
 	"github.com/btcsuite/btcd/txscript"
 	"github.com/btcsuite/btcd/wire"
-	"github.com/btcsuite/btcutil"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channelnotifier"
 	"github.com/lightningnetwork/lnd/input"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
 	"github.com/lightningnetwork/lnd/tor"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
 	"github.com/lightningnetwork/lnd/watchtower/wtclient"
@@ -31,6 +35,8 @@ const (
 	csvDelay uint32 = 144
 
 	towerAddrStr = "18.28.243.2:9911"
+
+	secondTowerAddrStr = "18.28.243.3:9911"
 )
 
 var (
@@ -67,7 +73,7 @@ var (
 func randPrivKey(t *testing.T) *btcec.PrivateKey {
 	t.Helper()
 
-	sk, err := btcec.NewPrivateKey(btcec.S256())
+	sk, err := btcec.NewPrivateKey()
 	if err != nil {
 		t.Fatalf("unable to generate pubkey: %v", err)
 	}
@@ -78,11 +84,16 @@ func randPrivKey(t *testing.T) *btcec.PrivateKey {
 type mockNet struct {
 	mu           sync.RWMutex
 	connCallback func(wtserver.Peer)
+
+	// towerCallbacks overrides the connCallback for specific towers. A nil
+	// callback makes the tower unreachable.
+	towerCallbacks map[string]func(wtserver.Peer)
 }
 
 func newMockNet(cb func(wtserver.Peer)) *mockNet {
 	return &mockNet{
-		connCallback: cb,
+		connCallback:   cb,
+		towerCallbacks: make(map[string]func(wtserver.Peer)),
 	}
 }
 
@@ -114,13 +125,23 @@ func (m *mockNet) AuthDial(local keychain.SingleKeyECDH,
 		Port: 36723,
 	}
 
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	connCallback := m.connCallback
+	towerKey := string(netAddr.IdentityKey.SerializeCompressed())
+	if cb, ok := m.towerCallbacks[towerKey]; ok {
+		connCallback = cb
+	}
+	if connCallback == nil {
+		return nil, errors.New("tower unreachable")
+	}
+
 	localPeer, remotePeer := wtmock.NewMockConn(
 		localPk, netAddr.IdentityKey, localAddr, netAddr.Address, 0,
 	)
 
-	m.mu.RLock()
-	m.connCallback(remotePeer)
-	m.mu.RUnlock()
+	connCallback(remotePeer)
 
 	return localPeer, nil
 }
@@ -131,6 +152,14 @@ func (m *mockNet) setConnCallback(cb func(wtserver.Peer)) {
 	m.connCallback = cb
 }
 
+func (m *mockNet) setTowerCallback(pubKey *btcec.PublicKey,
+	cb func(wtserver.Peer)) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.towerCallbacks[string(pubKey.SerializeCompressed())] = cb
+}
+
 type mockChannel struct {
 	mu            sync.Mutex
 	commitHeight  uint64
@@ -382,6 +411,7 @@ type testHarness struct {
 	serverCfg  *wtserver.Config
 	server     *wtserver.Server
 	net        *mockNet
+	chanEvents *subscribe.Server
 
 	mu       sync.Mutex
 	channels map[lnwire.ChannelID]*mockChannel
@@ -393,6 +423,8 @@ type harnessCfg struct {
 	policy             wtpolicy.Policy
 	noRegisterChan0    bool
 	noAckCreateSession bool
+
+	towerFailoverTimeout time.Duration
 }
 
 func newHarness(t *testing.T, cfg harnessCfg) *testHarness {
@@ -401,7 +433,7 @@ func newHarness(t *testing.T, cfg harnessCfg) *testHarness {
 		t.Fatalf("Unable to resolve tower TCP addr: %v", err)
 	}
 
-	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	privKey, err := btcec.NewPrivateKey()
 	if err != nil {
 		t.Fatalf("Unable to generate tower private key: %v", err)
 	}
@@ -437,6 +469,11 @@ func newHarness(t *testing.T, cfg harnessCfg) *testHarness {
 	mockNet := newMockNet(server.InboundPeerConnected)
 	clientDB := wtmock.NewClientDB()
 
+	chanEvents := subscribe.NewServer()
+	if err := chanEvents.Start(); err != nil {
+		t.Fatalf("Unable to start channel events server: %v", err)
+	}
+
 	clientCfg := &wtclient.Config{
 		Signer:        signer,
 		Dial:          mockNet.Dial,
@@ -452,6 +489,11 @@ func newHarness(t *testing.T, cfg harnessCfg) *testHarness {
 		MinBackoff:     time.Millisecond,
 		MaxBackoff:     time.Second,
 		ForceQuitDelay: 10 * time.Second,
+
+		TowerFailoverTimeout: cfg.towerFailoverTimeout,
+		SubscribeChannelEvents: func() (subscribe.Subscription, error) {
+			return chanEvents.Subscribe()
+		},
 	}
 	client, err := wtclient.New(clientCfg)
 	if err != nil {
@@ -484,6 +526,7 @@ func newHarness(t *testing.T, cfg harnessCfg) *testHarness {
 		serverCfg:  serverCfg,
 		server:     server,
 		net:        mockNet,
+		chanEvents: chanEvents,
 		channels:   make(map[lnwire.ChannelID]*mockChannel),
 	}
 
@@ -539,6 +582,73 @@ func (h *testHarness) startClient() {
 	}
 }
 
+// startSecondTower creates and starts a second tower that the client can be
+// pointed to. The harness's serverDB is replaced by the database of the second
+// tower, so that subsequent calls to waitServerUpdates assert the updates it
+// received.
+func (h *testHarness) startSecondTower() (*lnwire.NetAddress,
+	*wtserver.Server) {
+
+	h.t.Helper()
+
+	towerTCPAddr, err := net.ResolveTCPAddr("tcp", secondTowerAddrStr)
+	require.NoError(h.t, err)
+
+	privKey, err := btcec.NewPrivateKey()
+	require.NoError(h.t, err)
+
+	serverCfg := *h.serverCfg
+	serverCfg.DB = wtmock.NewTowerDB()
+	serverCfg.NodeKeyECDH = &keychain.PrivKeyECDH{PrivKey: privKey}
+
+	server, err := wtserver.New(&serverCfg)
+	require.NoError(h.t, err)
+	require.NoError(h.t, server.Start())
+
+	towerAddr := &lnwire.NetAddress{
+		IdentityKey: privKey.PubKey(),
+		Address:     towerTCPAddr,
+	}
+	h.net.setTowerCallback(towerAddr.IdentityKey, server.InboundPeerConnected)
+	h.serverDB = serverCfg.DB.(*wtmock.TowerDB)
+
+	return towerAddr, server
+}
+
+// towerBacklog returns the client's backlog of the given tower.
+func (h *testHarness) towerBacklog(
+	pubKey *btcec.PublicKey) *wtclient.TowerBacklog {
+
+	h.t.Helper()
+
+	backlogs, err := h.client.TowerBacklogs()
+	require.NoError(h.t, err)
+
+	for _, backlog := range backlogs {
+		if backlog.IdentityKey.IsEqual(pubKey) {
+			return backlog
+		}
+	}
+
+	h.t.Fatalf("no backlog for tower %x", pubKey.SerializeCompressed())
+	return nil
+}
+
+// closeChannel notifies the client that the channel identified by id has been
+// closed.
+func (h *testHarness) closeChannel(id uint64) {
+	h.t.Helper()
+
+	err := h.chanEvents.SendUpdate(channelnotifier.ClosedChannelEvent{
+		CloseSummary: &channeldb.ChannelCloseSummary{
+			ChanPoint: wire.OutPoint{
+				Hash: chainhash.Hash(chanIDFromInt(id)),
+			},
+		},
+	})
+	require.NoError(h.t, err)
+}
+
 // chanIDFromInt creates a unique channel id given a unique integral id.
 func chanIDFromInt(id uint64) lnwire.ChannelID {
 	var chanID lnwire.ChannelID
@@ -641,6 +751,39 @@ func (h *testHarness) backupState(id, i uint64, expErr error) {
 	}
 }
 
+// backupSpliceState instructs the channel identified by id to send a backup
+// for the variant of state i that spends the funding output of a splice, and
+// returns the breach hint of that variant.
+func (h *testHarness) backupSpliceState(id, i uint64,
+	expErr error) blob.BreachHint {
+
+	h.t.Helper()
+
+	_, retribution := h.channel(id).getState(i)
+
+	// The mock commitments have no inputs, so derive the variant by adding
+	// one that stands in for the splice's funding output. This changes the
+	// txid of the breach transaction and its outputs.
+	spliceRetribution := *retribution
+	breachTx := retribution.BreachTransaction.Copy()
+	breachTx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Index: 1},
+	})
+	breachTxID := breachTx.TxHash()
+
+	spliceRetribution.BreachTransaction = breachTx
+	spliceRetribution.LocalOutpoint.Hash = breachTxID
+	spliceRetribution.RemoteOutpoint.Hash = breachTxID
+
+	chanID := chanIDFromInt(id)
+	err := h.client.BackupSpliceState(
+		&chanID, &spliceRetribution, channeldb.SingleFunderBit,
+	)
+	require.Equal(h.t, expErr, err)
+
+	return blob.NewBreachHintFromHash(&breachTxID)
+}
+
 // sendPayments instructs the channel identified by id to send amt to the remote
 // party for each state in from-to times and returns the breach hints for states
 // [from, to).
@@ -1421,6 +1564,48 @@ var clientTests = []clientTest{
 			h.waitServerUpdates(hints, 5*time.Second)
 		},
 	},
+	{
+		// Asserts that the variant of a revoked state spending the
+		// funding output of a pending splice is backed up alongside the
+		// state itself, even though both share a commit height.
+		name: "splice backups",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				TxPolicy: wtpolicy.TxPolicy{
+					BlobType:     blob.TypeAltruistCommit,
+					SweepFeeRate: wtpolicy.DefaultSweepFeeRate,
+				},
+				MaxUpdates: 5,
+			},
+		},
+		fn: func(h *testHarness) {
+			const (
+				numUpdates = 3
+				chanID     = 0
+			)
+
+			// Generate the retributions that will be backed up.
+			hints := h.advanceChannelN(chanID, numUpdates)
+
+			// Back up each state and the variant of it that spends
+			// the splice's funding output. Queueing the variants
+			// twice asserts that they are deduped as well.
+			for i := uint64(0); i < numUpdates; i++ {
+				h.backupState(chanID, i, nil)
+
+				hint := h.backupSpliceState(chanID, i, nil)
+				h.backupSpliceState(chanID, i, nil)
+
+				hints = append(hints, hint)
+			}
+
+			// Wait for both variants of every state to be
+			// populated in the server's database.
+			h.waitServerUpdates(hints, 5*time.Second)
+		},
+	},
 	{
 		// Asserts that the client can continue making backups to a
 		// tower that's been re-added after it's been removed.
@@ -1532,6 +1717,197 @@ var clientTests = []clientTest{
 			require.Nil(h.t, err)
 		},
 	},
+	{
+		// Asserts that backups pending for a tower that has become
+		// unreachable are moved to a session with another tower once
+		// the failover timeout expires.
+		name: "tower failover",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				TxPolicy: wtpolicy.TxPolicy{
+					BlobType:     blob.TypeAltruistCommit,
+					SweepFeeRate: wtpolicy.DefaultSweepFeeRate,
+				},
+				MaxUpdates: 20,
+			},
+			towerFailoverTimeout: 500 * time.Millisecond,
+		},
+		fn: func(h *testHarness) {
+			const (
+				chanID     = 0
+				numUpdates = 6
+			)
+
+			// Back up the first half of the states to the first
+			// tower.
+			hints := h.advanceChannelN(chanID, numUpdates)
+			h.backupStates(chanID, 0, numUpdates/2, nil)
+			h.waitServerUpdates(hints[:numUpdates/2], 5*time.Second)
+
+			// Register a second tower, then make the first tower
+			// unreachable.
+			firstTower := h.serverAddr.IdentityKey
+			secondAddr, secondServer := h.startSecondTower()
+			defer secondServer.Stop()
+
+			h.addTower(secondAddr)
+			h.net.setTowerCallback(firstTower, nil)
+
+			// The remaining states are accepted by the session with
+			// the first tower, but can't be delivered. Once the
+			// failover timeout expires, they should be moved to a
+			// session with the second tower.
+			h.backupStates(chanID, numUpdates/2, numUpdates, nil)
+			h.waitServerUpdates(hints[numUpdates/2:], 10*time.Second)
+
+			stats := h.client.Stats()
+			require.Equal(h.t, numUpdates/2, stats.NumTasksMigrated)
+
+			backlog := h.towerBacklog(firstTower)
+			require.True(h.t, backlog.Health.FailedOver)
+			require.False(h.t, backlog.Health.Reachable)
+			require.Zero(h.t, backlog.NumPendingBackups)
+
+			backlog = h.towerBacklog(secondAddr.IdentityKey)
+			require.False(h.t, backlog.Health.FailedOver)
+			require.True(h.t, backlog.Health.Reachable)
+			require.EqualValues(h.t, 1, backlog.NumActiveSessions)
+		},
+	},
+	{
+		// Asserts that rebalancing moves new backups from the tower in
+		// use to a different tower.
+		name: "rebalance towers",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				TxPolicy: wtpolicy.TxPolicy{
+					BlobType:     blob.TypeAltruistCommit,
+					SweepFeeRate: wtpolicy.DefaultSweepFeeRate,
+				},
+				MaxUpdates: 20,
+			},
+		},
+		fn: func(h *testHarness) {
+			const (
+				chanID     = 0
+				numUpdates = 6
+			)
+
+			// Rebalancing requires a session to be in use, or a
+			// registered tower.
+			_, err := h.client.RebalanceTowers(nil)
+			require.Equal(h.t, wtclient.ErrNoActiveSession, err)
+
+			_, err = h.client.RebalanceTowers(randPrivKey(h.t).PubKey())
+			require.Error(h.t, err)
+
+			// Back up the first half of the states to the first
+			// tower.
+			hints := h.advanceChannelN(chanID, numUpdates)
+			h.backupStates(chanID, 0, numUpdates/2, nil)
+			h.waitServerUpdates(hints[:numUpdates/2], 5*time.Second)
+
+			// Register a second tower and rebalance away from the
+			// first one. All backups have been delivered, so none
+			// of them need to be moved.
+			firstTower := h.serverAddr.IdentityKey
+			secondAddr, secondServer := h.startSecondTower()
+			defer secondServer.Stop()
+
+			h.addTower(secondAddr)
+			numMigrated, err := h.client.RebalanceTowers(nil)
+			require.NoError(h.t, err)
+			require.Zero(h.t, numMigrated)
+
+			// The remaining states should be backed up to the
+			// second tower.
+			h.backupStates(chanID, numUpdates/2, numUpdates, nil)
+			h.waitServerUpdates(hints[numUpdates/2:], 5*time.Second)
+
+			backlog := h.towerBacklog(firstTower)
+			require.True(h.t, backlog.Health.FailedOver)
+			require.True(h.t, backlog.Health.Reachable)
+		},
+	},
+	{
+		// Asserts that sessions are deleted from the tower and the
+		// client's database once they're exhausted and all channels
+		// they hold updates for are closed.
+		name: "session garbage collection",
+		cfg: harnessCfg{
+			localBalance:  localBalance,
+			remoteBalance: remoteBalance,
+			policy: wtpolicy.Policy{
+				TxPolicy: wtpolicy.TxPolicy{
+					BlobType:     blob.TypeAltruistCommit,
+					SweepFeeRate: wtpolicy.DefaultSweepFeeRate,
+				},
+				MaxUpdates: 5,
+			},
+		},
+		fn: func(h *testHarness) {
+			h.makeChannel(1, h.cfg.localBalance, h.cfg.remoteBalance)
+			h.registerChannel(1)
+
+			// Exhaust the first session with updates for both
+			// channels, and back up two more states of channel 0
+			// to a second session.
+			hints0 := h.advanceChannelN(0, 5)
+			hints1 := h.advanceChannelN(1, 2)
+
+			h.backupStates(0, 0, 3, nil)
+			h.backupStates(1, 0, 2, nil)
+			h.waitServerUpdates(
+				append(hints0[:3:3], hints1...), 5*time.Second,
+			)
+
+			h.backupStates(0, 3, 5, nil)
+			h.waitServerUpdates(
+				append(hints0, hints1...), 5*time.Second,
+			)
+
+			sessions, err := h.clientDB.ListClientSessions(nil)
+			require.NoError(h.t, err)
+			require.Len(h.t, sessions, 2)
+
+			var exhausted, active wtdb.SessionID
+			for id, s := range sessions {
+				if s.SeqNum == s.Policy.MaxUpdates {
+					exhausted = id
+				} else {
+					active = id
+				}
+			}
+
+			// Closing both channels should only delete the
+			// exhausted session, as the other one can still
+			// accept updates.
+			h.closeChannel(0)
+			h.closeChannel(1)
+
+			require.Eventually(h.t, func() bool {
+				stats := h.client.Stats()
+				return stats.NumSessionsReclaimed == 1
+			}, 5*time.Second, 10*time.Millisecond)
+			require.Equal(
+				h.t, 5, h.client.Stats().NumUpdatesReclaimed,
+			)
+
+			_, err = h.serverDB.GetSessionInfo(&exhausted)
+			require.Equal(h.t, wtdb.ErrSessionNotFound, err)
+			_, err = h.serverDB.GetSessionInfo(&active)
+			require.NoError(h.t, err)
+
+			sessions, err = h.clientDB.ListClientSessions(nil)
+			require.NoError(h.t, err)
+			require.Len(h.t, sessions, 1)
+			require.Contains(h.t, sessions, active)
+		},
+	},
 }
 
 // TestClient executes the client test suite, asserting the ability to backup
@@ -1544,6 +1920,7 @@ func TestClient(t *testing.T) {
 
 			h := newHarness(t, tc.cfg)
 			defer h.server.Stop()
+			defer h.chanEvents.Stop()
 			defer h.client.ForceQuit()
 
 			tc.fn(h)
@@ -32,4 +32,8 @@ var (
 	// revoked state because the channel had not been previously registered
 	// with the client.
 	ErrUnregisteredChannel = errors.New("channel is not registered")
+
+	// ErrNoActiveSession signals that the client has no session in use
+	// whose backlog could be moved to a different tower.
+	ErrNoActiveSession = errors.New("no active session")
 )
@@ -3,7 +3,7 @@ package wtclient
 import (
 	"net"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/keychain"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/tor"
@@ -94,6 +94,21 @@ type DB interface {
 	// update identified by seqNum was received and saved. The returned
 	// lastApplied will be recorded.
 	AckUpdate(id *wtdb.SessionID, seqNum, lastApplied uint16) error
+
+	// MarkChannelClosed records that the channel has been closed on-chain,
+	// such that no more revoked states can be broadcast for it.
+	MarkChannelClosed(lnwire.ChannelID) error
+
+	// ListClosableSessions returns the set of client sessions that no
+	// longer accept new updates, have all of their updates acked, and only
+	// hold updates for closed channels.
+	ListClosableSessions() (map[wtdb.SessionID]*wtdb.ClientSession, error)
+
+	// DeleteSession removes a closable client session and all of its
+	// updates, along with the summaries of closed channels that are no
+	// longer referenced by any remaining session. The summaries of the
+	// given channels are kept, as backups for them are still pending.
+	DeleteSession(wtdb.SessionID, map[lnwire.ChannelID]struct{}) error
 }
 
 // AuthDialer connects to a remote node using an authenticated transport, such as
@@ -0,0 +1,345 @@
+package wtclient
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/channelnotifier"
+	"github.com/lightningnetwork/lnd/keychain"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/subscribe"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtwire"
+)
+
+// closableSessionsMsg is an internal message we'll use within the TowerClient
+// to signal that the given sessions are about to be deleted, and must no longer
+// be used for backups.
+type closableSessionsMsg struct {
+	// sessions is the set of sessions that will be deleted.
+	sessions map[wtdb.SessionID]*wtdb.ClientSession
+
+	// pendingChans is populated with the channels that have backups which
+	// haven't been committed to a session yet, before a nil error is sent
+	// on errChan. Their summaries must be kept, as the backups may still
+	// need them.
+	pendingChans map[lnwire.ChannelID]struct{}
+
+	// errChan is the channel through which we'll send a response back to
+	// the caller when handling their request.
+	//
+	// NOTE: This channel must be buffered.
+	errChan chan error
+}
+
+// sessionCollector deletes the sessions that only hold updates for closed
+// channels, both from the towers and from the client's database. It runs once
+// on startup, and then each time one of our channels is closed.
+//
+// NOTE: This method MUST be run as a goroutine.
+func (c *TowerClient) sessionCollector(sub subscribe.Subscription) {
+	defer c.wg.Done()
+	defer sub.Cancel()
+
+	c.log.Tracef("Starting session collector")
+	defer c.log.Tracef("Stopping session collector")
+
+	// Channels may have been closed while we were offline, so we'll check
+	// all registered channels before collecting any sessions.
+	c.markClosedChannels()
+	c.collectSessions()
+
+	for {
+		select {
+		case update, ok := <-sub.Updates():
+			if !ok {
+				return
+			}
+
+			event, ok := update.(channelnotifier.ClosedChannelEvent)
+			if !ok {
+				continue
+			}
+
+			chanID := lnwire.NewChanIDFromOutPoint(
+				&event.CloseSummary.ChanPoint,
+			)
+			if c.markChannelClosed(chanID) {
+				c.collectSessions()
+			}
+
+		case <-sub.Quit():
+			return
+
+		case <-c.quit:
+			return
+
+		case <-c.forceQuit:
+			return
+		}
+	}
+}
+
+// markClosedChannels marks every registered channel that has been closed as
+// such in the database.
+func (c *TowerClient) markClosedChannels() {
+	if c.cfg.FetchClosedChannel == nil {
+		return
+	}
+
+	c.backupMu.Lock()
+	chanIDs := make([]lnwire.ChannelID, 0, len(c.summaries))
+	for chanID := range c.summaries {
+		chanIDs = append(chanIDs, chanID)
+	}
+	c.backupMu.Unlock()
+
+	for _, chanID := range chanIDs {
+		_, err := c.cfg.FetchClosedChannel(chanID)
+		switch {
+		case err == channeldb.ErrClosedChannelNotFound:
+			continue
+
+		case err != nil:
+			c.log.Errorf("Unable to fetch close summary of "+
+				"chan_id=%v: %v", chanID, err)
+			continue
+		}
+
+		c.markChannelClosed(chanID)
+	}
+}
+
+// markChannelClosed marks the channel as closed in the database, returning
+// whether it was registered with the client.
+func (c *TowerClient) markChannelClosed(chanID lnwire.ChannelID) bool {
+	err := c.cfg.DB.MarkChannelClosed(chanID)
+	switch {
+	case err == wtdb.ErrChannelNotRegistered:
+		return false
+
+	case err != nil:
+		c.log.Errorf("Unable to mark chan_id=%v as closed: %v",
+			chanID, err)
+		return false
+	}
+
+	c.log.Debugf("Marked chan_id=%v as closed", chanID)
+
+	return true
+}
+
+// collectSessions deletes each closable session of the client's channel type.
+// Sessions that can't be deleted from their tower are retried the next time
+// sessions are collected.
+func (c *TowerClient) collectSessions() {
+	sessions, err := c.cfg.DB.ListClosableSessions()
+	if err != nil {
+		c.log.Errorf("Unable to list closable sessions: %v", err)
+		return
+	}
+
+	// The legacy and anchor clients share the same database, so we'll only
+	// collect the sessions of our own channel type.
+	isAnchorClient := c.cfg.Policy.IsAnchorChannel()
+	for id, s := range sessions {
+		if s.Policy.IsAnchorChannel() != isAnchorClient {
+			delete(sessions, id)
+		}
+	}
+
+	if len(sessions) == 0 {
+		return
+	}
+
+	// Make sure the dispatcher no longer uses any of the sessions before
+	// deleting them, and learn which channels still have pending backups.
+	msg := &closableSessionsMsg{
+		sessions: sessions,
+		errChan:  make(chan error, 1),
+	}
+	select {
+	case c.closableSessions <- msg:
+	case <-c.quit:
+		return
+	case <-c.forceQuit:
+		return
+	}
+
+	select {
+	case err := <-msg.errChan:
+		if err != nil {
+			c.log.Errorf("Unable to release closable sessions: %v",
+				err)
+			return
+		}
+	case <-c.forceQuit:
+		return
+	}
+
+	for _, s := range sessions {
+		err := c.deleteSession(s, msg.pendingChans)
+		if err != nil {
+			c.log.Warnf("Unable to delete session=%s: %v", s.ID,
+				err)
+			continue
+		}
+
+		c.stats.sessionReclaimed(len(s.AckedUpdates))
+
+		c.log.Infof("Deleted session=%s, reclaimed %d updates", s.ID,
+			len(s.AckedUpdates))
+	}
+}
+
+// handleClosableSessions removes the given sessions from the client's candidate
+// and active sessions, such that they're no longer used for backups. It also
+// collects the channels of all backups that haven't been committed to a session
+// yet.
+func (c *TowerClient) handleClosableSessions(msg *closableSessionsMsg) error {
+	for id := range msg.sessions {
+		delete(c.candidateSessions, id)
+
+		if c.sessionQueue != nil && *c.sessionQueue.ID() == id {
+			c.sessionQueue = nil
+		}
+
+		if sq, ok := c.activeSessions[id]; ok {
+			sq.Stop()
+			delete(c.activeSessions, id)
+		}
+	}
+
+	pendingChans := make(map[lnwire.ChannelID]struct{})
+	addPending := func(chanIDs ...lnwire.ChannelID) {
+		for _, chanID := range chanIDs {
+			pendingChans[chanID] = struct{}{}
+		}
+	}
+
+	if c.prevTask != nil {
+		addPending(c.prevTask.id.ChanID)
+	}
+	for _, task := range c.backlog {
+		addPending(task.id.ChanID)
+	}
+	for _, sq := range c.activeSessions {
+		addPending(sq.PendingChanIDs()...)
+	}
+	addPending(c.pipeline.PendingChanIDs()...)
+
+	msg.pendingChans = pendingChans
+
+	return nil
+}
+
+// deleteSession asks the session's tower to delete all of its state, after
+// which the session is removed from the client's database. The summaries of
+// the pending channels are kept.
+func (c *TowerClient) deleteSession(s *wtdb.ClientSession,
+	pendingChans map[lnwire.ChannelID]struct{}) error {
+
+	tower, err := c.cfg.DB.LoadTowerByID(s.TowerID)
+	if err != nil {
+		return err
+	}
+
+	// The tower authenticates the session through the key used to dial
+	// it, so we'll rederive the session key.
+	keyDesc, err := c.cfg.SecretKeyRing.DeriveKey(keychain.KeyLocator{
+		Family: keychain.KeyFamilyTowerSession,
+		Index:  s.KeyIndex,
+	})
+	if err != nil {
+		return err
+	}
+	sessionKey := keychain.NewPubKeyECDH(keyDesc, c.cfg.SecretKeyRing)
+
+	// Try each of the tower's addresses until one of them succeeds.
+	for _, addr := range tower.Addresses {
+		err = c.sendDeleteSession(sessionKey, &lnwire.NetAddress{
+			IdentityKey: tower.IdentityKey,
+			Address:     addr,
+		})
+		if err == nil {
+			break
+		}
+
+		c.log.Debugf("Unable to delete session=%s with tower at "+
+			"%v: %v", s.ID, addr, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.cfg.DB.DeleteSession(s.ID, pendingChans)
+}
+
+// sendDeleteSession sends a DeleteSession message for the session identified
+// by the given key to the tower at addr, and processes the tower's reply. A
+// tower that no longer knows about the session is treated as a success.
+func (c *TowerClient) sendDeleteSession(sessionKey keychain.SingleKeyECDH,
+	addr *lnwire.NetAddress) error {
+
+	conn, err := c.dial(sessionKey, addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	localInit := wtwire.NewInitMessage(
+		lnwire.NewRawFeatureVector(wtwire.AltruistSessionsRequired),
+		c.cfg.ChainHash,
+	)
+
+	// Send Init to tower.
+	err = c.sendMessage(conn, localInit)
+	if err != nil {
+		return err
+	}
+
+	// Receive Init from tower.
+	remoteMsg, err := c.readMessage(conn)
+	if err != nil {
+		return err
+	}
+
+	remoteInit, ok := remoteMsg.(*wtwire.Init)
+	if !ok {
+		return fmt.Errorf("watchtower %s responded with %T to Init",
+			addr, remoteMsg)
+	}
+
+	// Validate Init.
+	err = localInit.CheckRemoteInit(remoteInit, wtwire.FeatureNames)
+	if err != nil {
+		return err
+	}
+
+	// Send DeleteSession to tower.
+	err = c.sendMessage(conn, &wtwire.DeleteSession{})
+	if err != nil {
+		return err
+	}
+
+	// Receive DeleteSessionReply from tower.
+	remoteMsg, err = c.readMessage(conn)
+	if err != nil {
+		return err
+	}
+
+	reply, ok := remoteMsg.(*wtwire.DeleteSessionReply)
+	if !ok {
+		return fmt.Errorf("watchtower %s responded with %T to "+
+			"DeleteSession", addr, remoteMsg)
+	}
+
+	switch reply.Code {
+	case wtwire.CodeOK, wtwire.DeleteSessionCodeNotFound:
+		return nil
+
+	default:
+		return fmt.Errorf("received error code %v in "+
+			"DeleteSessionReply from %s", reply.Code, addr)
+	}
+}
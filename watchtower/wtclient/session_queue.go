@@ -101,6 +101,11 @@ type sessionQueue struct {
 
 	seqNum uint16
 
+	// inFlight is true while the queue is draining backups to the tower,
+	// during which the task at the front of the pending queue may already
+	// be committed to the session.
+	inFlight bool
+
 	retryBackoff time.Duration
 
 	quit      chan struct{}
@@ -306,6 +311,16 @@ func (q *sessionQueue) drainBackups() {
 	}
 	defer conn.Close()
 
+	q.queueCond.L.Lock()
+	q.inFlight = true
+	q.queueCond.L.Unlock()
+
+	defer func() {
+		q.queueCond.L.Lock()
+		q.inFlight = false
+		q.queueCond.L.Unlock()
+	}()
+
 	// Begin draining the queue of pending state updates. Before the first
 	// update is sent, we will precede it with an Init message. If the first
 	// is successful, subsequent updates can be streamed without sending an
@@ -596,6 +611,55 @@ func (q *sessionQueue) sendStateUpdate(conn wtserver.Peer,
 	return nil
 }
 
+// TakePending removes all tasks that have been accepted by the queue but not
+// yet committed to the session, so that they can be backed up to a different
+// session instead. If the queue is currently draining backups to the tower, the
+// task at the front of the pending queue is left in place, as it may already
+// be committed.
+func (q *sessionQueue) TakePending() []*backupTask {
+	q.queueCond.L.Lock()
+	defer q.queueCond.L.Unlock()
+
+	next := q.pendingQueue.Front()
+	if q.inFlight && next != nil {
+		next = next.Next()
+	}
+
+	var tasks []*backupTask
+	for next != nil {
+		e := next
+		next = e.Next()
+
+		tasks = append(tasks, q.pendingQueue.Remove(e).(*backupTask))
+	}
+
+	return tasks
+}
+
+// PendingChanIDs returns the channels of the tasks that have been assigned to
+// the session, but have not been committed to it yet.
+func (q *sessionQueue) PendingChanIDs() []lnwire.ChannelID {
+	q.queueCond.L.Lock()
+	defer q.queueCond.L.Unlock()
+
+	chanIDs := make([]lnwire.ChannelID, 0, q.pendingQueue.Len())
+	for e := q.pendingQueue.Front(); e != nil; e = e.Next() {
+		chanIDs = append(chanIDs, e.Value.(*backupTask).id.ChanID)
+	}
+
+	return chanIDs
+}
+
+// Backlog returns the number of tasks that are pending to be committed to the
+// session, and the number of committed updates that are still awaiting an ack
+// from the tower.
+func (q *sessionQueue) Backlog() (uint32, uint32) {
+	q.queueCond.L.Lock()
+	defer q.queueCond.L.Unlock()
+
+	return uint32(q.pendingQueue.Len()), uint32(q.commitQueue.Len())
+}
+
 // reserveStatus returns a reserveStatus indicating whether or not the
 // sessionQueue can accept another task. reserveAvailable is returned when a
 // task can be accepted, and reserveExhausted is returned if the all slots in
@@ -29,6 +29,20 @@ type ClientStats struct {
 	// NumSessionsExhausted is the total number of watchtower sessions that
 	// have been exhausted.
 	NumSessionsExhausted int
+
+	// NumTasksMigrated is the total number of backups that were moved off
+	// a tower that became unreachable or was rebalanced, before they were
+	// committed to one of its sessions.
+	NumTasksMigrated int
+
+	// NumSessionsReclaimed is the total number of sessions that only held
+	// updates for closed channels, and have been deleted from their
+	// watchtowers and the client's database.
+	NumSessionsReclaimed int
+
+	// NumUpdatesReclaimed is the total number of state updates that have
+	// been deleted along with the reclaimed sessions.
+	NumUpdatesReclaimed int
 }
 
 // taskReceived increments the number to backup requests the client has received
@@ -58,6 +72,17 @@ func (s *ClientStats) taskIneligible() {
 	s.NumTasksIneligible++
 }
 
+// tasksMigrated records that the given number of accepted tasks were taken
+// back from their session queue, and are pending to be assigned to a different
+// session.
+func (s *ClientStats) tasksMigrated(numTasks int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.NumTasksMigrated += numTasks
+	s.NumTasksAccepted -= numTasks
+	s.NumTasksPending += numTasks
+}
+
 // sessionAcquired increments the number of sessions that have been successfully
 // negotiated by the client during this execution.
 func (s *ClientStats) sessionAcquired() {
@@ -74,14 +99,27 @@ func (s *ClientStats) sessionExhausted() {
 	s.NumSessionsExhausted++
 }
 
+// sessionReclaimed increments the number of sessions that have been deleted
+// after all of their channels were closed, along with the number of deleted
+// updates.
+func (s *ClientStats) sessionReclaimed(numUpdates int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.NumSessionsReclaimed++
+	s.NumUpdatesReclaimed += numUpdates
+}
+
 // String returns a human readable summary of the client's metrics.
 func (s *ClientStats) String() string {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	return fmt.Sprintf("tasks(received=%d accepted=%d ineligible=%d) "+
-		"sessions(acquired=%d exhausted=%d)", s.NumTasksPending,
-		s.NumTasksAccepted, s.NumTasksIneligible, s.NumSessionsAcquired,
-		s.NumSessionsExhausted)
+	return fmt.Sprintf("tasks(received=%d accepted=%d ineligible=%d "+
+		"migrated=%d) sessions(acquired=%d exhausted=%d "+
+		"reclaimed=%d) updates(reclaimed=%d)",
+		s.NumTasksPending, s.NumTasksAccepted, s.NumTasksIneligible,
+		s.NumTasksMigrated, s.NumSessionsAcquired,
+		s.NumSessionsExhausted, s.NumSessionsReclaimed,
+		s.NumUpdatesReclaimed)
 }
 
 // Copy returns a copy of the current stats.
@@ -94,5 +132,8 @@ func (s *ClientStats) Copy() ClientStats {
 		NumTasksIneligible:   s.NumTasksIneligible,
 		NumSessionsAcquired:  s.NumSessionsAcquired,
 		NumSessionsExhausted: s.NumSessionsExhausted,
+		NumTasksMigrated:     s.NumTasksMigrated,
+		NumSessionsReclaimed: s.NumSessionsReclaimed,
+		NumUpdatesReclaimed:  s.NumUpdatesReclaimed,
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/btcsuite/btclog"
+	"github.com/lightningnetwork/lnd/lnwire"
 )
 
 // taskPipeline implements a reliable, in-order queue that ensures its queue
@@ -25,6 +26,10 @@ type taskPipeline struct {
 	queueCond *sync.Cond
 	queue     *list.List
 
+	// handoff is the task that has been removed from the queue, and is
+	// being delivered to the dispatcher.
+	handoff *backupTask
+
 	newBackupTasks chan *backupTask
 
 	quit      chan struct{}
@@ -126,6 +131,23 @@ func (q *taskPipeline) QueueBackupTask(task *backupTask) error {
 	return nil
 }
 
+// PendingChanIDs returns the channels of the tasks that have not been delivered
+// to the dispatcher yet.
+func (q *taskPipeline) PendingChanIDs() []lnwire.ChannelID {
+	q.queueCond.L.Lock()
+	defer q.queueCond.L.Unlock()
+
+	var chanIDs []lnwire.ChannelID
+	if q.handoff != nil {
+		chanIDs = append(chanIDs, q.handoff.id.ChanID)
+	}
+	for e := q.queue.Front(); e != nil; e = e.Next() {
+		chanIDs = append(chanIDs, e.Value.(*backupTask).id.ChanID)
+	}
+
+	return chanIDs
+}
+
 // queueManager processes all incoming backup requests that get added via
 // QueueBackupTask. The manager will exit
 //
@@ -160,6 +182,7 @@ func (q *taskPipeline) queueManager() {
 		// Pop the first element from the queue.
 		e := q.queue.Front()
 		task := q.queue.Remove(e).(*backupTask)
+		q.handoff = task
 		q.queueCond.L.Unlock()
 
 		select {
@@ -167,6 +190,9 @@ func (q *taskPipeline) queueManager() {
 		// Backup task submitted to dispatcher. We don't select on quit to
 		// ensure that we still drain tasks while shutting down.
 		case q.newBackupTasks <- task:
+			q.queueCond.L.Lock()
+			q.handoff = nil
+			q.queueCond.L.Unlock()
 
 		// Force quit, return immediately to allow the client to exit.
 		case <-q.forceQuit:
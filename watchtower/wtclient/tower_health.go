@@ -0,0 +1,227 @@
+package wtclient
+
+import (
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/lightningnetwork/lnd/watchtower/wtdb"
+	"github.com/lightningnetwork/lnd/watchtower/wtserver"
+)
+
+// responseTimeWeight is the weight given to the most recent response time of
+// a tower when updating its moving average.
+const responseTimeWeight = 0.2
+
+// towerKey is the serialized identity key of a tower, used to index the health
+// records of the towers.
+type towerKey [33]byte
+
+// newTowerKey returns the towerKey of the given identity key.
+func newTowerKey(pubKey *btcec.PublicKey) towerKey {
+	var key towerKey
+	copy(key[:], pubKey.SerializeCompressed())
+	return key
+}
+
+// TowerHealth is a snapshot of the health of a tower as observed by the
+// client.
+type TowerHealth struct {
+	// Reachable is false if the last attempt to communicate with the tower
+	// failed.
+	Reachable bool
+
+	// ConsecutiveFailures is the number of failed attempts to communicate
+	// with the tower since the last successful one.
+	ConsecutiveFailures uint32
+
+	// AvgResponseTime is the moving average of the time it took the tower
+	// to respond to our connection attempts and messages.
+	AvgResponseTime time.Duration
+
+	// LastSuccess is the last time the tower responded to us. It is zero if
+	// the tower never did since startup.
+	LastSuccess time.Time
+
+	// UnreachableSince is the time of the first failure since the last
+	// successful response of the tower. It is zero if the tower is
+	// reachable.
+	UnreachableSince time.Time
+
+	// FailedOver is true if the backlog of the tower has been moved to
+	// other towers. A failed over tower is only considered for new
+	// sessions again once it's re-added, or no other tower is available.
+	FailedOver bool
+}
+
+// towerHealthTracker records the response times and failures of the towers the
+// client communicates with, and orders tower candidates by their health.
+type towerHealthTracker struct {
+	mu sync.Mutex
+
+	towers map[towerKey]*TowerHealth
+
+	// pendingReplies maps each connection with an outstanding message to
+	// the time the message was sent, allowing us to measure the time it
+	// took the tower to reply.
+	pendingReplies map[wtserver.Peer]time.Time
+
+	now func() time.Time
+}
+
+// newTowerHealthTracker initializes an empty towerHealthTracker.
+func newTowerHealthTracker() *towerHealthTracker {
+	return &towerHealthTracker{
+		towers:         make(map[towerKey]*TowerHealth),
+		pendingReplies: make(map[wtserver.Peer]time.Time),
+		now:            time.Now,
+	}
+}
+
+// health returns the health record of the given tower, creating it if it
+// doesn't exist yet.
+//
+// NOTE: This method MUST be called with the tracker's lock held.
+func (t *towerHealthTracker) health(key towerKey) *TowerHealth {
+	health, ok := t.towers[key]
+	if !ok {
+		health = &TowerHealth{Reachable: true}
+		t.towers[key] = health
+	}
+
+	return health
+}
+
+// recordSuccess records a successful response of the tower that took the given
+// amount of time.
+func (t *towerHealthTracker) recordSuccess(pubKey *btcec.PublicKey,
+	responseTime time.Duration) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health := t.health(newTowerKey(pubKey))
+	health.Reachable = true
+	health.ConsecutiveFailures = 0
+	health.LastSuccess = t.now()
+	health.UnreachableSince = time.Time{}
+
+	if health.AvgResponseTime == 0 {
+		health.AvgResponseTime = responseTime
+		return
+	}
+	health.AvgResponseTime = time.Duration(
+		responseTimeWeight*float64(responseTime) +
+			(1-responseTimeWeight)*float64(health.AvgResponseTime),
+	)
+}
+
+// recordFailure records a failed attempt to communicate with the tower.
+func (t *towerHealthTracker) recordFailure(pubKey *btcec.PublicKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health := t.health(newTowerKey(pubKey))
+	if health.Reachable {
+		health.UnreachableSince = t.now()
+	}
+	health.Reachable = false
+	health.ConsecutiveFailures++
+}
+
+// recordSend marks the time a message was sent over the given connection.
+func (t *towerHealthTracker) recordSend(peer wtserver.Peer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.pendingReplies[peer] = t.now()
+}
+
+// recordReply records the outcome of reading a reply from the given
+// connection. If the read succeeded and a message was sent before, the elapsed
+// time is recorded as the response time of the tower.
+func (t *towerHealthTracker) recordReply(peer wtserver.Peer, err error) {
+	t.mu.Lock()
+	sentAt, ok := t.pendingReplies[peer]
+	delete(t.pendingReplies, peer)
+	t.mu.Unlock()
+
+	switch {
+	case err != nil:
+		t.recordFailure(peer.RemotePub())
+
+	case ok:
+		t.recordSuccess(peer.RemotePub(), t.now().Sub(sentAt))
+	}
+}
+
+// unreachableFor returns for how long the tower has been unreachable, or zero
+// if it is reachable.
+func (t *towerHealthTracker) unreachableFor(pubKey *btcec.PublicKey) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health, ok := t.towers[newTowerKey(pubKey)]
+	if !ok || health.Reachable {
+		return 0
+	}
+
+	return t.now().Sub(health.UnreachableSince)
+}
+
+// markFailedOver marks that the backlog of the tower has been moved to other
+// towers.
+func (t *towerHealthTracker) markFailedOver(pubKey *btcec.PublicKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.health(newTowerKey(pubKey)).FailedOver = true
+}
+
+// clearFailedOver makes the tower a regular candidate for new sessions again.
+func (t *towerHealthTracker) clearFailedOver(pubKey *btcec.PublicKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if health, ok := t.towers[newTowerKey(pubKey)]; ok {
+		health.FailedOver = false
+	}
+}
+
+// isFailedOver returns whether the backlog of the tower has been moved to
+// other towers.
+func (t *towerHealthTracker) isFailedOver(pubKey *btcec.PublicKey) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health, ok := t.towers[newTowerKey(pubKey)]
+	return ok && health.FailedOver
+}
+
+// Health returns a snapshot of the health of the tower.
+func (t *towerHealthTracker) Health(pubKey *btcec.PublicKey) TowerHealth {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	health, ok := t.towers[newTowerKey(pubKey)]
+	if !ok {
+		return TowerHealth{Reachable: true}
+	}
+
+	return *health
+}
+
+// less returns whether tower a should be preferred over tower b for new
+// sessions. Reachable towers are preferred over unreachable ones, after which
+// the tower with the lower average response time is preferred. Towers we have
+// no response times for are tried first.
+func (t *towerHealthTracker) less(a, b *wtdb.Tower) bool {
+	healthA := t.Health(a.IdentityKey)
+	healthB := t.Health(b.IdentityKey)
+
+	if healthA.Reachable != healthB.Reachable {
+		return healthA.Reachable
+	}
+
+	return healthA.AvgResponseTime < healthB.AvgResponseTime
+}
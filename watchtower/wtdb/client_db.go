@@ -7,7 +7,7 @@ import (
 	"math"
 	"net"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
@@ -48,6 +48,10 @@ var (
 	//    tower-pubkey -> tower-id.
 	cTowerIndexBkt = []byte("client-tower-index-bucket")
 
+	// cClosedChanBkt is a top-level bucket storing:
+	//    channel-id -> empty.
+	cClosedChanBkt = []byte("client-closed-channel-bucket")
+
 	// ErrTowerNotFound signals that the target tower was not found in the
 	// database.
 	ErrTowerNotFound = errors.New("tower not found")
@@ -108,6 +112,11 @@ var (
 	// ErrLastTowerAddr is an error returned when the last address of a
 	// watchtower is attempted to be removed.
 	ErrLastTowerAddr = errors.New("cannot remove last tower address")
+
+	// ErrSessionNotClosable signals that a client session cannot be
+	// deleted because it may still accept updates, has unacked updates, or
+	// holds updates for channels that are not closed.
+	ErrSessionNotClosable = errors.New("client session not closable")
 )
 
 // NewBoltBackendCreator returns a function that creates a new bbolt backend for
@@ -195,6 +204,7 @@ func initClientDBBuckets(tx kvdb.RwTx) error {
 		cSessionBkt,
 		cTowerBkt,
 		cTowerIndexBkt,
+		cClosedChanBkt,
 	}
 
 	for _, bucket := range buckets {
@@ -1002,6 +1012,184 @@ func (c *ClientDB) AckUpdate(id *SessionID, seqNum uint16,
 	}, func() {})
 }
 
+// MarkChannelClosed records that the channel has been closed on-chain, such
+// that no more revoked states can be broadcast for it. Sessions that only hold
+// updates for closed channels can be deleted once they're no longer used.
+func (c *ClientDB) MarkChannelClosed(chanID lnwire.ChannelID) error {
+	return kvdb.Update(c.db, func(tx kvdb.RwTx) error {
+		chanSummaries := tx.ReadBucket(cChanSummaryBkt)
+		if chanSummaries == nil {
+			return ErrUninitializedDB
+		}
+
+		closedChans := tx.ReadWriteBucket(cClosedChanBkt)
+		if closedChans == nil {
+			return ErrUninitializedDB
+		}
+
+		// Only channels that were registered with the client can have
+		// updates in any of its sessions.
+		if chanSummaries.Get(chanID[:]) == nil {
+			return ErrChannelNotRegistered
+		}
+
+		return closedChans.Put(chanID[:], []byte{})
+	}, func() {})
+}
+
+// ListClosableSessions returns the set of client sessions that can be deleted,
+// along with their acked updates. A session is closable once it no longer
+// accepts new updates, all of its updates have been acked, and all channels it
+// holds updates for have been closed.
+func (c *ClientDB) ListClosableSessions() (map[SessionID]*ClientSession,
+	error) {
+
+	var closableSessions map[SessionID]*ClientSession
+	err := kvdb.View(c.db, func(tx kvdb.RTx) error {
+		sessions := tx.ReadBucket(cSessionBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		closedChans := tx.ReadBucket(cClosedChanBkt)
+		if closedChans == nil {
+			return ErrUninitializedDB
+		}
+
+		return sessions.ForEach(func(k, _ []byte) error {
+			session, err := getClientSession(sessions, k)
+			if err != nil {
+				return err
+			}
+
+			if isSessionClosable(closedChans, session) {
+				closableSessions[session.ID] = session
+			}
+
+			return nil
+		})
+	}, func() {
+		closableSessions = make(map[SessionID]*ClientSession)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return closableSessions, nil
+}
+
+// DeleteSession removes a closable client session and all of its updates from
+// the database. Channel summaries of closed channels that are no longer
+// referenced by any remaining session are removed as well, unless they're
+// part of keepChans because backups for them are still pending.
+// ErrSessionNotClosable is returned if the session can still be used.
+func (c *ClientDB) DeleteSession(id SessionID,
+	keepChans map[lnwire.ChannelID]struct{}) error {
+
+	return kvdb.Update(c.db, func(tx kvdb.RwTx) error {
+		sessions := tx.ReadWriteBucket(cSessionBkt)
+		if sessions == nil {
+			return ErrUninitializedDB
+		}
+
+		chanSummaries := tx.ReadWriteBucket(cChanSummaryBkt)
+		if chanSummaries == nil {
+			return ErrUninitializedDB
+		}
+
+		closedChans := tx.ReadWriteBucket(cClosedChanBkt)
+		if closedChans == nil {
+			return ErrUninitializedDB
+		}
+
+		session, err := getClientSession(sessions, id[:])
+		if err != nil {
+			return err
+		}
+
+		if !isSessionClosable(closedChans, session) {
+			return ErrSessionNotClosable
+		}
+
+		err = sessions.DeleteNestedBucket(id[:])
+		if err != nil {
+			return err
+		}
+
+		// Collect the channels the session held updates for, and
+		// discard those that still have pending backups or are still
+		// referenced by another session.
+		staleChans := make(map[lnwire.ChannelID]struct{})
+		for _, backupID := range session.AckedUpdates {
+			if _, ok := keepChans[backupID.ChanID]; ok {
+				continue
+			}
+			staleChans[backupID.ChanID] = struct{}{}
+		}
+
+		err = sessions.ForEach(func(k, _ []byte) error {
+			if len(staleChans) == 0 {
+				return nil
+			}
+
+			other, err := getClientSession(sessions, k)
+			if err != nil {
+				return err
+			}
+
+			for _, update := range other.CommittedUpdates {
+				delete(staleChans, update.BackupID.ChanID)
+			}
+			for _, backupID := range other.AckedUpdates {
+				delete(staleChans, backupID.ChanID)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// The remaining channels are closed and have no updates left,
+		// so their summaries are no longer needed.
+		for chanID := range staleChans {
+			err := chanSummaries.Delete(chanID[:])
+			if err != nil {
+				return err
+			}
+
+			err = closedChans.Delete(chanID[:])
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, func() {})
+}
+
+// isSessionClosable returns whether the session no longer accepts new updates,
+// has all of its updates acked, and only holds updates for closed channels.
+func isSessionClosable(closedChans kvdb.RBucket, session *ClientSession) bool {
+	if len(session.CommittedUpdates) > 0 {
+		return false
+	}
+
+	if session.Status == CSessionActive &&
+		session.SeqNum < session.Policy.MaxUpdates {
+
+		return false
+	}
+
+	for _, backupID := range session.AckedUpdates {
+		if closedChans.Get(backupID.ChanID[:]) == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
 // getClientSessionBody loads the body of a ClientSession from the sessions
 // bucket corresponding to the serialized session id. This does not deserialize
 // the CommittedUpdates or AckUpdates associated with the session. If the caller
@@ -10,7 +10,7 @@ import (
 	"reflect"
 	"testing"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/kvdb"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
@@ -223,6 +223,41 @@ func (h *clientDBHarness) ackUpdate(id *wtdb.SessionID, seqNum uint16,
 	}
 }
 
+func (h *clientDBHarness) markChannelClosed(chanID lnwire.ChannelID,
+	expErr error) {
+
+	h.t.Helper()
+
+	err := h.db.MarkChannelClosed(chanID)
+	if err != expErr {
+		h.t.Fatalf("expected mark channel closed error: %v, got: %v",
+			expErr, err)
+	}
+}
+
+func (h *clientDBHarness) listClosableSessions() map[wtdb.SessionID]*wtdb.ClientSession {
+	h.t.Helper()
+
+	sessions, err := h.db.ListClosableSessions()
+	if err != nil {
+		h.t.Fatalf("unable to list closable sessions: %v", err)
+	}
+
+	return sessions
+}
+
+func (h *clientDBHarness) deleteSession(id wtdb.SessionID,
+	keepChans map[lnwire.ChannelID]struct{}, expErr error) {
+
+	h.t.Helper()
+
+	err := h.db.DeleteSession(id, keepChans)
+	if err != expErr {
+		h.t.Fatalf("expected delete session error: %v, got: %v",
+			expErr, err)
+	}
+}
+
 // testCreateClientSession asserts various conditions regarding the creation of
 // a new ClientSession. The test asserts:
 //   - client sessions can only be created if a session key index is reserved.
@@ -729,6 +764,99 @@ func testAckUpdate(h *clientDBHarness) {
 	h.ackUpdate(&session.ID, 4, 3, wtdb.ErrUnallocatedLastApplied)
 }
 
+// testClosableSessions asserts that a session only becomes closable once it is
+// exhausted, all of its updates are acked and all of its channels are closed,
+// and that deleting it prunes the summaries of its closed channels that have no
+// pending backups.
+func testClosableSessions(h *clientDBHarness) {
+	const blobType = blob.TypeAltruistCommit
+
+	session := &wtdb.ClientSession{
+		ClientSessionBody: wtdb.ClientSessionBody{
+			TowerID: wtdb.TowerID(3),
+			Policy: wtpolicy.Policy{
+				TxPolicy: wtpolicy.TxPolicy{
+					BlobType: blobType,
+				},
+				MaxUpdates: 2,
+			},
+			RewardPkScript: []byte{0x01, 0x02, 0x03},
+		},
+		ID: wtdb.SessionID([33]byte{0x03}),
+	}
+	session.KeyIndex = h.nextKeyIndex(session.TowerID, blobType)
+	h.insertSession(session, nil)
+
+	// Commit one update for each of two registered channels, and only ack
+	// the first one.
+	update1 := randCommittedUpdate(h.t, 1)
+	update2 := randCommittedUpdate(h.t, 2)
+	chanID1 := update1.BackupID.ChanID
+	chanID2 := update2.BackupID.ChanID
+	h.registerChan(chanID1, []byte{0x01}, nil)
+	h.registerChan(chanID2, []byte{0x02}, nil)
+
+	h.commitUpdate(&session.ID, update1, nil)
+	h.ackUpdate(&session.ID, 1, 1, nil)
+
+	// The session still accepts updates, so it isn't closable even though
+	// its only channel is closed.
+	h.markChannelClosed(chanID1, nil)
+	if len(h.listClosableSessions()) != 0 {
+		h.t.Fatalf("session should not be closable")
+	}
+	h.deleteSession(session.ID, nil, wtdb.ErrSessionNotClosable)
+
+	// Exhaust the session. It can't be closed while its last update is
+	// unacked, nor while the second channel remains open.
+	h.commitUpdate(&session.ID, update2, nil)
+	if len(h.listClosableSessions()) != 0 {
+		h.t.Fatalf("session should not be closable")
+	}
+	h.ackUpdate(&session.ID, 2, 2, nil)
+	if len(h.listClosableSessions()) != 0 {
+		h.t.Fatalf("session should not be closable")
+	}
+
+	// Marking an unknown channel as closed should fail.
+	var unknownChanID lnwire.ChannelID
+	h.markChannelClosed(unknownChanID, wtdb.ErrChannelNotRegistered)
+
+	// Once the second channel is closed, the session should be closable.
+	h.markChannelClosed(chanID2, nil)
+	closable := h.listClosableSessions()
+	if len(closable) != 1 {
+		h.t.Fatalf("expected 1 closable session, got %d",
+			len(closable))
+	}
+	checkAckedUpdates(h.t, closable[session.ID], map[uint16]wtdb.BackupID{
+		1: update1.BackupID,
+		2: update2.BackupID,
+	})
+
+	// Deleting the session should remove it along with the summary of the
+	// first channel. The second channel still has pending backups, so its
+	// summary must be kept.
+	keepChans := map[lnwire.ChannelID]struct{}{
+		chanID2: {},
+	}
+	h.deleteSession(session.ID, keepChans, nil)
+	h.deleteSession(session.ID, nil, wtdb.ErrClientSessionNotFound)
+
+	if _, ok := h.listSessions(nil)[session.ID]; ok {
+		h.t.Fatalf("session should have been deleted")
+	}
+	summaries := h.fetchChanSummaries()
+	if _, ok := summaries[chanID1]; ok {
+		h.t.Fatalf("summary of chan_id=%v should have been deleted",
+			chanID1)
+	}
+	if _, ok := summaries[chanID2]; !ok {
+		h.t.Fatalf("summary of chan_id=%v should have been kept",
+			chanID2)
+	}
+}
+
 // checkCommittedUpdates asserts that the CommittedUpdates on session match the
 // expUpdates provided.
 func checkCommittedUpdates(t *testing.T, session *wtdb.ClientSession,
@@ -893,6 +1021,10 @@ func TestClientDB(t *testing.T) {
 			name: "ack update",
 			run:  testAckUpdate,
 		},
+		{
+			name: "closable sessions",
+			run:  testClosableSessions,
+		},
 	}
 
 	for _, database := range dbs {
@@ -5,7 +5,7 @@ import (
 	"sync"
 	"sync/atomic"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/watchtower/blob"
 	"github.com/lightningnetwork/lnd/watchtower/wtdb"
@@ -25,6 +25,7 @@ type ClientDB struct {
 
 	mu             sync.Mutex
 	summaries      map[lnwire.ChannelID]wtdb.ClientChanSummary
+	closedChans    map[lnwire.ChannelID]struct{}
 	activeSessions map[wtdb.SessionID]wtdb.ClientSession
 	towerIndex     map[towerPK]wtdb.TowerID
 	towers         map[wtdb.TowerID]*wtdb.Tower
@@ -38,6 +39,7 @@ type ClientDB struct {
 func NewClientDB() *ClientDB {
 	return &ClientDB{
 		summaries:      make(map[lnwire.ChannelID]wtdb.ClientChanSummary),
+		closedChans:    make(map[lnwire.ChannelID]struct{}),
 		activeSessions: make(map[wtdb.SessionID]wtdb.ClientSession),
 		towerIndex:     make(map[towerPK]wtdb.TowerID),
 		towers:         make(map[wtdb.TowerID]*wtdb.Tower),
@@ -447,6 +449,113 @@ func (m *ClientDB) RegisterChannel(chanID lnwire.ChannelID,
 	return nil
 }
 
+// MarkChannelClosed records that the channel has been closed on-chain, such
+// that no more revoked states can be broadcast for it.
+func (m *ClientDB) MarkChannelClosed(chanID lnwire.ChannelID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.summaries[chanID]; !ok {
+		return wtdb.ErrChannelNotRegistered
+	}
+
+	m.closedChans[chanID] = struct{}{}
+
+	return nil
+}
+
+// ListClosableSessions returns the set of client sessions that no longer accept
+// new updates, have all of their updates acked, and only hold updates for
+// closed channels.
+func (m *ClientDB) ListClosableSessions() (
+	map[wtdb.SessionID]*wtdb.ClientSession, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sessions, err := m.listClientSessions(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, session := range sessions {
+		if !m.isSessionClosable(session) {
+			delete(sessions, id)
+		}
+	}
+
+	return sessions, nil
+}
+
+// DeleteSession removes a closable client session, along with the summaries of
+// closed channels that are no longer referenced by any remaining session and
+// aren't part of keepChans.
+func (m *ClientDB) DeleteSession(id wtdb.SessionID,
+	keepChans map[lnwire.ChannelID]struct{}) error {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	session, ok := m.activeSessions[id]
+	if !ok {
+		return wtdb.ErrClientSessionNotFound
+	}
+
+	if !m.isSessionClosable(&session) {
+		return wtdb.ErrSessionNotClosable
+	}
+
+	delete(m.activeSessions, id)
+
+	staleChans := make(map[lnwire.ChannelID]struct{})
+	for _, backupID := range session.AckedUpdates {
+		if _, ok := keepChans[backupID.ChanID]; ok {
+			continue
+		}
+		staleChans[backupID.ChanID] = struct{}{}
+	}
+
+	for _, other := range m.activeSessions {
+		for _, update := range other.CommittedUpdates {
+			delete(staleChans, update.BackupID.ChanID)
+		}
+		for _, backupID := range other.AckedUpdates {
+			delete(staleChans, backupID.ChanID)
+		}
+	}
+
+	for chanID := range staleChans {
+		delete(m.summaries, chanID)
+		delete(m.closedChans, chanID)
+	}
+
+	return nil
+}
+
+// isSessionClosable returns whether the session no longer accepts new updates,
+// has all of its updates acked, and only holds updates for closed channels.
+//
+// NOTE: This method requires the database's lock to be acquired.
+func (m *ClientDB) isSessionClosable(session *wtdb.ClientSession) bool {
+	if len(session.CommittedUpdates) > 0 {
+		return false
+	}
+
+	if session.Status == wtdb.CSessionActive &&
+		session.SeqNum < session.Policy.MaxUpdates {
+
+		return false
+	}
+
+	for _, backupID := range session.AckedUpdates {
+		if _, ok := m.closedChans[backupID.ChanID]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
 func cloneBytes(b []byte) []byte {
 	if b == nil {
 		return nil
@@ -1,4 +1,4 @@
-package tlv_test
+package wtwire_test
 
 import (
 	"bytes"
@@ -0,0 +1,104 @@
+package zpay32
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcutil/bech32"
+	"github.com/lightningnetwork/lnd/routing/blindedpath"
+)
+
+const (
+	// maxNumBlindedHops is the maximum number of hops a blinded payment
+	// path within an invoice can have.
+	maxNumBlindedHops = 255
+)
+
+// BlindedPaymentPath is a blinded path to the recipient of an invoice along
+// with the aggregated forwarding parameters of all hops of the path. The
+// sender treats the path as a single hop from the introduction point to the
+// recipient.
+type BlindedPaymentPath struct {
+	// FeeBaseMsat is the total base fee of the path in millisatoshis.
+	FeeBaseMsat uint32
+
+	// FeeRate is the total proportional fee of the path in millionths.
+	FeeRate uint32
+
+	// CltvExpiryDelta is the total time-lock delta of the path, including
+	// the final CLTV delta of the recipient.
+	CltvExpiryDelta uint16
+
+	// HTLCMinMsat is the minimum amount that can be sent over the path.
+	HTLCMinMsat uint64
+
+	// HTLCMaxMsat is the maximum amount that can be sent over the path.
+	HTLCMaxMsat uint64
+
+	// Path is the blinded path itself.
+	Path *blindedpath.BlindedPath
+}
+
+// WithBlindedPaymentPath is a functional option that allows callers of
+// NewInvoice to add a blinded payment path to the invoice.
+func WithBlindedPaymentPath(p *BlindedPaymentPath) func(*Invoice) {
+	return func(i *Invoice) {
+		i.BlindedPaymentPaths = append(i.BlindedPaymentPaths, p)
+	}
+}
+
+// encodeBlindedPaymentPath serializes a blinded payment path to its base32
+// representation within an invoice.
+func encodeBlindedPaymentPath(p *BlindedPaymentPath) ([]byte, error) {
+	if len(p.Path.BlindedHops) == 0 ||
+		len(p.Path.BlindedHops) > maxNumBlindedHops {
+
+		return nil, fmt.Errorf("invalid number of blinded hops: %d",
+			len(p.Path.BlindedHops))
+	}
+
+	var b bytes.Buffer
+	for _, v := range []interface{}{
+		p.FeeBaseMsat, p.FeeRate, p.CltvExpiryDelta, p.HTLCMinMsat,
+		p.HTLCMaxMsat,
+	} {
+		if err := binary.Write(&b, binary.BigEndian, v); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := p.Path.Encode(&b); err != nil {
+		return nil, err
+	}
+
+	return bech32.ConvertBits(b.Bytes(), 8, 5, true)
+}
+
+// parseBlindedPaymentPath converts the data (encoded in base32) into a
+// blinded payment path.
+func parseBlindedPaymentPath(data []byte) (*BlindedPaymentPath, error) {
+	base256Data, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+
+	r := bytes.NewReader(base256Data)
+
+	var p BlindedPaymentPath
+	for _, v := range []interface{}{
+		&p.FeeBaseMsat, &p.FeeRate, &p.CltvExpiryDelta,
+		&p.HTLCMinMsat, &p.HTLCMaxMsat,
+	} {
+		if err := binary.Read(r, binary.BigEndian, v); err != nil {
+			return nil, err
+		}
+	}
+
+	p.Path, err = blindedpath.DecodeBlindedPath(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
@@ -7,10 +7,10 @@ import (
 	"testing"
 	"time"
 
-	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/bech32"
 	"github.com/btcsuite/btcd/chaincfg"
-	"github.com/btcsuite/btcutil"
-	"github.com/btcsuite/btcutil/bech32"
 	"github.com/lightningnetwork/lnd/lnwire"
 )
 
@@ -452,7 +452,8 @@ func TestParseDestination(t *testing.T) {
 		if test.valid && !comparePubkeys(destination, test.result) {
 			t.Fatalf("test %d failed decoding destination: "+
 				"expected %x, got %x",
-				i, *test.result, *destination)
+				i, test.result.SerializeCompressed(),
+				destination.SerializeCompressed())
 			return
 		}
 	}